@@ -18,11 +18,13 @@ import (
 	"context"
 	"fmt"
 	"iter"
+	"slices"
 
 	"google.golang.org/genai"
 
 	"google.golang.org/adk/artifact"
 	agentinternal "google.golang.org/adk/internal/agent"
+	"google.golang.org/adk/internal/telemetry/tracing"
 	"google.golang.org/adk/memory"
 	"google.golang.org/adk/model"
 	"google.golang.org/adk/session"
@@ -102,12 +104,21 @@ type Config struct {
 }
 
 // Artifacts interface provides methods to work with artifacts of the current
-// session.
+// session. The artifacts whose name starts with "user:" are shared by all the
+// sessions of the user, see package artifact.
 type Artifacts interface {
+	// Save stores a new version of the artifact and returns it.
 	Save(ctx context.Context, name string, data *genai.Part) (*artifact.SaveResponse, error)
+	// List returns the names of the artifacts of the session, and of the
+	// user scoped artifacts.
 	List(context.Context) (*artifact.ListResponse, error)
+	// Load returns the latest version of the artifact.
 	Load(ctx context.Context, name string) (*artifact.LoadResponse, error)
+	// LoadVersion returns a version of the artifact, or its latest version
+	// if version is 0.
 	LoadVersion(ctx context.Context, name string, version int) (*artifact.LoadResponse, error)
+	// Versions returns the versions of the artifact.
+	Versions(ctx context.Context, name string) (*artifact.VersionsResponse, error)
 }
 
 // Memory interface provides methods to access agent memory across the
@@ -131,6 +142,30 @@ type BeforeAgentCallback func(CallbackContext) (*genai.Content, error)
 // BeforeAgentCallbacks returned non-nil results.
 type AfterAgentCallback func(CallbackContext) (*genai.Content, error)
 
+// WithAgentCallbacks returns a copy of ctx in which the callbacks run around
+// the runs of all the agents, before the callbacks of each agent, e.g. the
+// callbacks of the plugins of the runner, see runner.Config.Plugins.
+func WithAgentCallbacks(ctx context.Context, before []BeforeAgentCallback, after []AfterAgentCallback) context.Context {
+	return context.WithValue(ctx, agentCallbacksCtxKey, &agentCallbacks{before: before, after: after})
+}
+
+type agentCallbacks struct {
+	before []BeforeAgentCallback
+	after  []AfterAgentCallback
+}
+
+type agentCallbacksKey struct{}
+
+var agentCallbacksCtxKey = agentCallbacksKey{}
+
+// contextAgentCallbacks returns the callbacks set with WithAgentCallbacks.
+func contextAgentCallbacks(ctx context.Context) *agentCallbacks {
+	if c, ok := ctx.Value(agentCallbacksCtxKey).(*agentCallbacks); ok {
+		return c
+	}
+	return &agentCallbacks{}
+}
+
 type agent struct {
 	agentinternal.State
 
@@ -156,9 +191,20 @@ func (a *agent) SubAgents() []Agent {
 
 func (a *agent) Run(ctx InvocationContext) iter.Seq2[*session.Event, error] {
 	return func(yield func(*session.Event, error) bool) {
+		spanCtx, spans := tracing.StartAgent(ctx, a.name, a.description, ctx.InvocationID(), CorrelationIDFromContext(ctx))
+		var runErr error
+		defer func() { tracing.End(spans, runErr) }()
+		yieldEvent := yield
+		yield = func(event *session.Event, err error) bool {
+			if err != nil {
+				runErr = err
+			}
+			return yieldEvent(event, err)
+		}
+
 		// TODO: verify&update the setup here. Should we branch etc.
 		ctx := &invocationContext{
-			Context:   ctx,
+			Context:   spanCtx,
 			agent:     a,
 			artifacts: ctx.Artifacts(),
 			memory:    ctx.Memory(),
@@ -225,7 +271,8 @@ func runBeforeAgentCallbacks(ctx InvocationContext) (*session.Event, error) {
 		actions:           &session.EventActions{StateDelta: make(map[string]any)},
 	}
 
-	for _, callback := range ctx.Agent().internal().beforeAgentCallbacks {
+	callbacks := append(slices.Clone(contextAgentCallbacks(ctx).before), agent.internal().beforeAgentCallbacks...)
+	for _, callback := range callbacks {
 		content, err := callback(callbackCtx)
 		if err != nil {
 			return nil, fmt.Errorf("failed to run before agent callback: %w", err)
@@ -268,7 +315,8 @@ func runAfterAgentCallbacks(ctx InvocationContext) (*session.Event, error) {
 		actions:           &session.EventActions{StateDelta: make(map[string]any)},
 	}
 
-	for _, callback := range agent.internal().afterAgentCallbacks {
+	callbacks := append(slices.Clone(contextAgentCallbacks(ctx).after), agent.internal().afterAgentCallbacks...)
+	for _, callback := range callbacks {
 		newContent, err := callback(callbackCtx)
 		if err != nil {
 			return nil, fmt.Errorf("failed to run after agent callback: %w", err)
@@ -0,0 +1,297 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package agentconfig builds agent trees from declarative configs, e.g. to
+// tweak the instructions or the models of the agents without changing code,
+// as the agent configs of adk-python do.
+//
+// A config is a JSON document describing an agent:
+//
+//	{
+//	  "agent_class": "LlmAgent",
+//	  "name": "assistant",
+//	  "model": "gemini-2.0-flash",
+//	  "instruction": "Answer the questions of {user_name}.",
+//	  "tools": [{"name": "google_search"}, {"name": "get_weather"}],
+//	  "generate_content_config": {"temperature": 0.2},
+//	  "sub_agents": [{"config_path": "researcher.json"}]
+//	}
+//
+// The models are created, and the tools and the agents referenced by name are
+// resolved, by a [Registry]. A YAML config must be converted to JSON first,
+// e.g. with sigs.k8s.io/yaml.
+package agentconfig
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/agent"
+	"google.golang.org/adk/agent/llmagent"
+	"google.golang.org/adk/agent/workflowagents/loopagent"
+	"google.golang.org/adk/agent/workflowagents/parallelagent"
+	"google.golang.org/adk/agent/workflowagents/sequentialagent"
+	"google.golang.org/adk/model"
+	"google.golang.org/adk/model/registry"
+	"google.golang.org/adk/tool"
+	"google.golang.org/adk/tool/geminitool"
+)
+
+// The agent classes of the configs.
+const (
+	LLMAgent        = "LlmAgent"
+	SequentialAgent = "SequentialAgent"
+	ParallelAgent   = "ParallelAgent"
+	LoopAgent       = "LoopAgent"
+)
+
+// Config is the config of an agent.
+type Config struct {
+	// AgentClass is the kind of agent: LlmAgent, SequentialAgent,
+	// ParallelAgent or LoopAgent. Defaults to LlmAgent.
+	AgentClass  string `json:"agent_class,omitempty"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	// SubAgents are the sub-agents of the agent, in order.
+	SubAgents []AgentRef `json:"sub_agents,omitempty"`
+
+	// The fields of the LLM agents.
+
+	// Model is the name of the model, created by Registry.Model. Defaults
+	// to the model of the ancestors.
+	Model             string `json:"model,omitempty"`
+	Instruction       string `json:"instruction,omitempty"`
+	GlobalInstruction string `json:"global_instruction,omitempty"`
+	// Tools are the tools of the agent, resolved by name by the registry.
+	Tools []ToolRef `json:"tools,omitempty"`
+	// GenerateContentConfig is the config of the model requests, in the JSON
+	// encoding of genai.GenerateContentConfig, e.g. {"temperature": 0.2}.
+	GenerateContentConfig    *genai.GenerateContentConfig `json:"generate_content_config,omitempty"`
+	OutputKey                string                       `json:"output_key,omitempty"`
+	IncludeContents          string                       `json:"include_contents,omitempty"`
+	DisallowTransferToParent bool                         `json:"disallow_transfer_to_parent,omitempty"`
+	DisallowTransferToPeers  bool                         `json:"disallow_transfer_to_peers,omitempty"`
+
+	// The fields of the loop agents.
+
+	// MaxIterations is the maximum number of iterations of a LoopAgent, 0
+	// for no maximum.
+	MaxIterations uint `json:"max_iterations,omitempty"`
+}
+
+// AgentRef references a sub-agent: either the config file at ConfigPath,
+// relative to the file of the parent, or the agent registered as Agent.
+type AgentRef struct {
+	ConfigPath string `json:"config_path,omitempty"`
+	Agent      string `json:"agent,omitempty"`
+}
+
+// ToolRef references a tool or a toolset registered as Name, or a Gemini
+// built-in tool: google_search or code_execution.
+type ToolRef struct {
+	Name string `json:"name"`
+}
+
+// Registry resolves the names of the configs.
+type Registry struct {
+	// Model returns the model of a name. Defaults to registry.NewModel, which
+	// resolves the provider-prefixed names, e.g. "openai/gpt-4o", and the
+	// Gemini models.
+	Model func(ctx context.Context, name string) (model.LLM, error)
+	// Tools are the tools available to the configs, by name.
+	Tools map[string]tool.Tool
+	// Toolsets are the toolsets available to the configs, by name.
+	Toolsets map[string]tool.Toolset
+	// Agents are the agents built in code which the configs can use as
+	// sub-agents, by name.
+	Agents map[string]agent.Agent
+}
+
+// Load builds the agent tree of the config file at path.
+func Load(ctx context.Context, path string, registry *Registry) (agent.Agent, error) {
+	return newBuilder(registry).load(ctx, path, nil)
+}
+
+// New builds the agent tree of the config. The config paths of the
+// sub-agents are relative to the working directory.
+func New(ctx context.Context, cfg *Config, registry *Registry) (agent.Agent, error) {
+	return newBuilder(registry).build(ctx, cfg, ".", nil)
+}
+
+type builder struct {
+	registry *Registry
+	// models are the models created, by name.
+	models map[string]model.LLM
+	// loading are the config files being loaded, to detect cycles.
+	loading map[string]bool
+}
+
+func newBuilder(registry *Registry) *builder {
+	if registry == nil {
+		registry = &Registry{}
+	}
+	return &builder{registry: registry, models: make(map[string]model.LLM), loading: make(map[string]bool)}
+}
+
+func (b *builder) load(ctx context.Context, path string, parentModel model.LLM) (agent.Agent, error) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+	if b.loading[abs] {
+		return nil, fmt.Errorf("config %s includes itself", path)
+	}
+	b.loading[abs] = true
+	defer delete(b.loading, abs)
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read agent config: %w", err)
+	}
+	var cfg Config
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("invalid agent config %s: %w", path, err)
+	}
+	a, err := b.build(ctx, &cfg, filepath.Dir(path), parentModel)
+	if err != nil {
+		return nil, fmt.Errorf("invalid agent config %s: %w", path, err)
+	}
+	return a, nil
+}
+
+func (b *builder) build(ctx context.Context, cfg *Config, dir string, parentModel model.LLM) (agent.Agent, error) {
+	if cfg.Name == "" {
+		return nil, errors.New("the agent has no name")
+	}
+
+	agentModel := parentModel
+	if cfg.Model != "" {
+		var err error
+		if agentModel, err = b.model(ctx, cfg.Model); err != nil {
+			return nil, err
+		}
+	}
+
+	subAgents := make([]agent.Agent, 0, len(cfg.SubAgents))
+	for i, ref := range cfg.SubAgents {
+		var sub agent.Agent
+		var err error
+		switch {
+		case ref.ConfigPath != "" && ref.Agent != "":
+			return nil, fmt.Errorf("sub-agent %d of agent %q has both a config path and an agent", i, cfg.Name)
+		case ref.ConfigPath != "":
+			path := ref.ConfigPath
+			if !filepath.IsAbs(path) {
+				path = filepath.Join(dir, path)
+			}
+			sub, err = b.load(ctx, path, agentModel)
+		case ref.Agent != "":
+			var ok bool
+			if sub, ok = b.registry.Agents[ref.Agent]; !ok {
+				err = fmt.Errorf("unknown agent %q", ref.Agent)
+			}
+		default:
+			err = fmt.Errorf("sub-agent %d of agent %q has no config path nor agent", i, cfg.Name)
+		}
+		if err != nil {
+			return nil, err
+		}
+		subAgents = append(subAgents, sub)
+	}
+
+	agentCfg := agent.Config{
+		Name:        cfg.Name,
+		Description: cfg.Description,
+		SubAgents:   subAgents,
+	}
+	switch cfg.AgentClass {
+	case "", LLMAgent:
+		if agentModel == nil {
+			return nil, fmt.Errorf("agent %q has no model", cfg.Name)
+		}
+		tools, toolsets, err := b.tools(cfg.Tools)
+		if err != nil {
+			return nil, err
+		}
+		return llmagent.New(llmagent.Config{
+			Name:                     cfg.Name,
+			Description:              cfg.Description,
+			SubAgents:                subAgents,
+			Model:                    agentModel,
+			Instruction:              cfg.Instruction,
+			GlobalInstruction:        cfg.GlobalInstruction,
+			Tools:                    tools,
+			Toolsets:                 toolsets,
+			GenerateContentConfig:    cfg.GenerateContentConfig,
+			OutputKey:                cfg.OutputKey,
+			IncludeContents:          llmagent.IncludeContents(cfg.IncludeContents),
+			DisallowTransferToParent: cfg.DisallowTransferToParent,
+			DisallowTransferToPeers:  cfg.DisallowTransferToPeers,
+		})
+	case SequentialAgent:
+		return sequentialagent.New(sequentialagent.Config{AgentConfig: agentCfg})
+	case ParallelAgent:
+		return parallelagent.New(parallelagent.Config{AgentConfig: agentCfg})
+	case LoopAgent:
+		return loopagent.New(loopagent.Config{AgentConfig: agentCfg, MaxIterations: cfg.MaxIterations})
+	}
+	return nil, fmt.Errorf("agent %q has an unknown agent class %q", cfg.Name, cfg.AgentClass)
+}
+
+// model returns the model of the name, created once per build.
+func (b *builder) model(ctx context.Context, name string) (model.LLM, error) {
+	if m, ok := b.models[name]; ok {
+		return m, nil
+	}
+	newModel := b.registry.Model
+	if newModel == nil {
+		newModel = registry.NewModel
+	}
+	m, err := newModel(ctx, name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create model %q: %w", name, err)
+	}
+	b.models[name] = m
+	return m, nil
+}
+
+func (b *builder) tools(refs []ToolRef) ([]tool.Tool, []tool.Toolset, error) {
+	var tools []tool.Tool
+	var toolsets []tool.Toolset
+	for _, ref := range refs {
+		if t, ok := b.registry.Tools[ref.Name]; ok {
+			tools = append(tools, t)
+			continue
+		}
+		if ts, ok := b.registry.Toolsets[ref.Name]; ok {
+			toolsets = append(toolsets, ts)
+			continue
+		}
+		switch ref.Name {
+		case "google_search":
+			tools = append(tools, geminitool.GoogleSearch{})
+		case "code_execution":
+			tools = append(tools, geminitool.CodeExecution{})
+		default:
+			return nil, nil, fmt.Errorf("unknown tool %q", ref.Name)
+		}
+	}
+	return tools, toolsets, nil
+}
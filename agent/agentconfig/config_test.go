@@ -0,0 +1,181 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package agentconfig_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/agent"
+	"google.golang.org/adk/agent/agentconfig"
+	"google.golang.org/adk/internal/testutil"
+	"google.golang.org/adk/model"
+	"google.golang.org/adk/tool"
+	"google.golang.org/adk/tool/functiontool"
+)
+
+func writeConfigs(t *testing.T, configs map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, config := range configs {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(config), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func TestLoad(t *testing.T) {
+	dir := writeConfigs(t, map[string]string{
+		"root.json": `{
+			"agent_class": "SequentialAgent",
+			"name": "pipeline",
+			"sub_agents": [
+				{"config_path": "agents/writer.json"},
+				{"agent": "reviewer"}
+			]
+		}`,
+		"agents/writer.json": `{
+			"name": "writer",
+			"description": "Writes the answer.",
+			"model": "mock-model",
+			"instruction": "Write for {user_name?}.",
+			"tools": [{"name": "get_weather"}, {"name": "google_search"}],
+			"generate_content_config": {"temperature": 0.5},
+			"output_key": "draft",
+			"sub_agents": [{"config_path": "helper.json"}]
+		}`,
+		"agents/helper.json": `{"name": "helper"}`,
+	})
+
+	weather, err := functiontool.New(functiontool.Config{Name: "get_weather", Description: "Returns the weather."},
+		func(tool.Context, struct{}) (string, error) { return "sunny", nil })
+	if err != nil {
+		t.Fatal(err)
+	}
+	reviewer, err := agent.New(agent.Config{Name: "reviewer"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	mockModel := &testutil.MockModel{Responses: []*genai.Content{genai.NewContentFromText("done", genai.RoleModel)}}
+	var modelNames []string
+	registry := &agentconfig.Registry{
+		Model: func(_ context.Context, name string) (model.LLM, error) {
+			modelNames = append(modelNames, name)
+			return mockModel, nil
+		},
+		Tools:  map[string]tool.Tool{"get_weather": weather},
+		Agents: map[string]agent.Agent{"reviewer": reviewer},
+	}
+
+	root, err := agentconfig.Load(t.Context(), filepath.Join(dir, "root.json"), registry)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if diff := cmp.Diff([]string{"mock-model"}, modelNames); diff != "" {
+		t.Errorf("created models mismatch (-want +got):\n%s", diff)
+	}
+	var names []string
+	for _, sub := range root.SubAgents() {
+		names = append(names, sub.Name())
+	}
+	if diff := cmp.Diff([]string{"writer", "reviewer"}, names); diff != "" {
+		t.Fatalf("sub-agents mismatch (-want +got):\n%s", diff)
+	}
+	writer := root.SubAgents()[0]
+	if writer.Description() != "Writes the answer." || len(writer.SubAgents()) != 1 || writer.SubAgents()[0].Name() != "helper" {
+		t.Errorf("writer = %q with sub-agents %v, want the config", writer.Description(), writer.SubAgents())
+	}
+	if root.SubAgents()[1] != reviewer {
+		t.Error("second sub-agent is not the registered agent")
+	}
+
+	// The writer runs with the config.
+	if _, err := testutil.CollectTextParts(testutil.NewTestAgentRunner(t, writer).Run(t, "session", "hi")); err != nil {
+		t.Fatal(err)
+	}
+	req := mockModel.Requests[0]
+	if got := req.Config.SystemInstruction.Parts[0].Text; got != "Write for ." {
+		t.Errorf("instruction = %q, want the one of the config", got)
+	}
+	if req.Config.Temperature == nil || *req.Config.Temperature != 0.5 {
+		t.Errorf("Temperature = %v, want 0.5", req.Config.Temperature)
+	}
+	if _, ok := req.Tools["get_weather"]; !ok {
+		t.Errorf("request tools = %v, want get_weather", req.Tools)
+	}
+}
+
+func TestNew(t *testing.T) {
+	mockModel := &testutil.MockModel{}
+	loop, err := agentconfig.New(t.Context(), &agentconfig.Config{
+		AgentClass:    agentconfig.LoopAgent,
+		Name:          "loop",
+		MaxIterations: 2,
+	}, &agentconfig.Registry{
+		Model: func(context.Context, string) (model.LLM, error) { return mockModel, nil },
+	})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	if loop.Name() != "loop" {
+		t.Errorf("Name() = %q, want loop", loop.Name())
+	}
+
+	a, err := agentconfig.New(t.Context(), &agentconfig.Config{Name: "assistant", Model: "any", IncludeContents: "none"}, &agentconfig.Registry{
+		Model: func(context.Context, string) (model.LLM, error) { return mockModel, nil },
+	})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	if a.Name() != "assistant" {
+		t.Errorf("Name() = %q, want assistant", a.Name())
+	}
+}
+
+func TestLoad_Errors(t *testing.T) {
+	registry := &agentconfig.Registry{
+		Model: func(context.Context, string) (model.LLM, error) { return &testutil.MockModel{}, nil },
+	}
+	for _, tc := range []struct {
+		name    string
+		configs map[string]string
+	}{
+		{name: "invalid JSON", configs: map[string]string{"root.json": `{`}},
+		{name: "no name", configs: map[string]string{"root.json": `{"model": "m"}`}},
+		{name: "no model", configs: map[string]string{"root.json": `{"name": "a"}`}},
+		{name: "unknown class", configs: map[string]string{"root.json": `{"name": "a", "agent_class": "Other"}`}},
+		{name: "unknown tool", configs: map[string]string{"root.json": `{"name": "a", "model": "m", "tools": [{"name": "missing"}]}`}},
+		{name: "unknown agent", configs: map[string]string{"root.json": `{"name": "a", "model": "m", "sub_agents": [{"agent": "missing"}]}`}},
+		{name: "missing file", configs: map[string]string{"root.json": `{"name": "a", "model": "m", "sub_agents": [{"config_path": "missing.json"}]}`}},
+		{name: "cycle", configs: map[string]string{"root.json": `{"name": "a", "model": "m", "sub_agents": [{"config_path": "root.json"}]}`}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			dir := writeConfigs(t, tc.configs)
+			if _, err := agentconfig.Load(t.Context(), filepath.Join(dir, "root.json"), registry); err == nil {
+				t.Error("Load() succeeded, want error")
+			}
+		})
+	}
+}
@@ -0,0 +1,217 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package codeexecutor lets LLM agents run the code their model writes. It is
+// configured with llmagent.Config.CodeExecutor.
+//
+// When a response of the model contains a code block, e.g.
+//
+//	```python
+//	print(sum(range(10)))
+//	```
+//
+// the code is cut from the response into a genai.Part with ExecutableCode,
+// and run by the [CodeExecutor]. Its output is appended to the session as an
+// event with a genai.Part with CodeExecutionResult, and the model is called
+// again with it, as the next turn of the conversation, until it answers
+// without code. In the requests, the code and the results are sent back as
+// code blocks, so any model can use them.
+//
+// Unlike geminitool.CodeExecution, which runs the code on the servers of
+// Gemini, the code runs where the executor runs it:
+//   - in a sandboxed container, see package containerexecutor;
+//   - in the code interpreter extension of Vertex AI, see package
+//     vertexexecutor;
+//   - on the local machine, for prototyping only, see [NewUnsafeLocal].
+//
+// They are interchangeable. The instruction of the agent should tell the
+// model to write code blocks when it needs to compute something.
+//
+// This mirrors adk-python src/google/adk/code_executors.
+package codeexecutor
+
+import (
+	"context"
+	"strings"
+
+	"google.golang.org/genai"
+)
+
+// CodeExecutor runs code.
+type CodeExecutor interface {
+	// Execute runs the code. The error is for the failures of the executor;
+	// the failures of the code are reported by the result.
+	Execute(ctx context.Context, in *Input) (*Result, error)
+}
+
+// Input is the code to run.
+type Input struct {
+	Code string
+	// ExecutionID identifies the executions sharing their state, e.g. the
+	// files they write, for the stateful executors. It is the session ID if
+	// Config.Stateful is set, and empty otherwise.
+	ExecutionID string
+}
+
+// Result is the outcome of a run.
+type Result struct {
+	Stdout string
+	Stderr string
+	// ExitCode of the code, non-zero if it failed.
+	ExitCode int
+}
+
+// Delimiters enclose a code block, e.g. "```python\n" and "\n```".
+type Delimiters struct {
+	Start, End string
+}
+
+var (
+	// DefaultCodeBlockDelimiters are the delimiters of the code blocks of
+	// the configs without ones.
+	DefaultCodeBlockDelimiters = []Delimiters{
+		{Start: "```tool_code\n", End: "\n```"},
+		{Start: "```python\n", End: "\n```"},
+	}
+	// DefaultResultDelimiters are the delimiters of the results of the
+	// configs without ones.
+	DefaultResultDelimiters = Delimiters{Start: "```tool_output\n", End: "\n```"}
+)
+
+// DefaultErrorRetryAttempts is the default of Config.ErrorRetryAttempts.
+const DefaultErrorRetryAttempts = 2
+
+// Config is the code execution of an agent.
+type Config struct {
+	// Executor runs the code. Required.
+	Executor CodeExecutor
+	// Stateful makes the executions of a session share their state, see
+	// Input.ExecutionID.
+	Stateful bool
+	// CodeBlockDelimiters are the delimiters of the code blocks to run, in
+	// order of preference. The first ones enclose the code sent back to the
+	// model. Defaults to DefaultCodeBlockDelimiters.
+	CodeBlockDelimiters []Delimiters
+	// ResultDelimiters enclose the results sent back to the model. Defaults
+	// to DefaultResultDelimiters.
+	ResultDelimiters *Delimiters
+	// ErrorRetryAttempts is the number of times the model may fix its code
+	// after failed runs in a row in an invocation. After that, its code is
+	// no longer run, so it must answer. Defaults to DefaultErrorRetryAttempts;
+	// negative means no retry.
+	ErrorRetryAttempts int
+}
+
+func (c *Config) codeBlockDelimiters() []Delimiters {
+	if len(c.CodeBlockDelimiters) == 0 {
+		return DefaultCodeBlockDelimiters
+	}
+	return c.CodeBlockDelimiters
+}
+
+func (c *Config) resultDelimiters() Delimiters {
+	if c.ResultDelimiters == nil {
+		return DefaultResultDelimiters
+	}
+	return *c.ResultDelimiters
+}
+
+// RetryAttempts returns the number of retries after failed runs, see
+// ErrorRetryAttempts.
+func (c *Config) RetryAttempts() int {
+	switch {
+	case c.ErrorRetryAttempts == 0:
+		return DefaultErrorRetryAttempts
+	case c.ErrorRetryAttempts < 0:
+		return 0
+	}
+	return c.ErrorRetryAttempts
+}
+
+// ExtractCode returns the parts of a model response with its first code
+// block replaced by a part with ExecutableCode, and the text after it, which
+// is the model guessing the output, dropped. It returns nil if the parts have
+// no code block.
+func (c *Config) ExtractCode(parts []*genai.Part) []*genai.Part {
+	for i, part := range parts {
+		if part == nil || part.Text == "" || part.Thought {
+			continue
+		}
+		for _, d := range c.codeBlockDelimiters() {
+			start := strings.Index(part.Text, d.Start)
+			if start < 0 {
+				continue
+			}
+			end := strings.Index(part.Text[start+len(d.Start):], d.End)
+			if end < 0 {
+				continue
+			}
+			code := part.Text[start+len(d.Start) : start+len(d.Start)+end]
+			extracted := append([]*genai.Part(nil), parts[:i]...)
+			if before := part.Text[:start]; strings.TrimSpace(before) != "" {
+				extracted = append(extracted, genai.NewPartFromText(before))
+			}
+			return append(extracted, genai.NewPartFromExecutableCode(code, genai.LanguagePython))
+		}
+	}
+	return nil
+}
+
+// Run runs the code, and returns the part with its result. The failures of
+// the executor, other than the cancellation of the context, are reported as
+// failed runs, so that the model can react.
+func (c *Config) Run(ctx context.Context, code, executionID string) (*genai.Part, error) {
+	if !c.Stateful {
+		executionID = ""
+	}
+	res, err := c.Executor.Execute(ctx, &Input{Code: code, ExecutionID: executionID})
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, err
+		}
+		return genai.NewPartFromCodeExecutionResult(genai.OutcomeFailed, err.Error()), nil
+	}
+	output := res.Stdout
+	if res.Stderr != "" {
+		if output != "" && !strings.HasSuffix(output, "\n") {
+			output += "\n"
+		}
+		output += res.Stderr
+	}
+	outcome := genai.OutcomeOK
+	if res.ExitCode != 0 {
+		outcome = genai.OutcomeFailed
+	}
+	return genai.NewPartFromCodeExecutionResult(outcome, output), nil
+}
+
+// ToText returns the part with its executable code or code execution result
+// as a code block, or the part itself for the other parts.
+func (c *Config) ToText(part *genai.Part) *genai.Part {
+	switch {
+	case part == nil:
+		return part
+	case part.ExecutableCode != nil:
+		d := c.codeBlockDelimiters()[0]
+		return genai.NewPartFromText(d.Start + part.ExecutableCode.Code + d.End)
+	case part.CodeExecutionResult != nil:
+		d := c.resultDelimiters()
+		output := part.CodeExecutionResult.Output
+		if part.CodeExecutionResult.Outcome != genai.OutcomeOK {
+			output = "Error: " + output
+		}
+		return genai.NewPartFromText(d.Start + output + d.End)
+	}
+	return part
+}
@@ -0,0 +1,131 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package codeexecutor_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/agent/codeexecutor"
+)
+
+func TestConfig_ExtractCode(t *testing.T) {
+	cfg := &codeexecutor.Config{}
+	tests := []struct {
+		name  string
+		parts []*genai.Part
+		want  []*genai.Part
+	}{
+		{
+			name:  "no code",
+			parts: []*genai.Part{genai.NewPartFromText("The answer is 42.")},
+		},
+		{
+			name:  "unterminated block",
+			parts: []*genai.Part{genai.NewPartFromText("```python\nprint(1)")},
+		},
+		{
+			name:  "code block",
+			parts: []*genai.Part{genai.NewPartFromText("Let me compute.\n```python\nprint(1)\nprint(2)\n```\nIt prints 1 and 2.")},
+			want: []*genai.Part{
+				genai.NewPartFromText("Let me compute.\n"),
+				genai.NewPartFromExecutableCode("print(1)\nprint(2)", genai.LanguagePython),
+			},
+		},
+		{
+			name: "first block of the second part",
+			parts: []*genai.Part{
+				genai.NewPartFromText("thinking"),
+				genai.NewPartFromText("```tool_code\na()\n```\n```python\nb()\n```"),
+			},
+			want: []*genai.Part{
+				genai.NewPartFromText("thinking"),
+				genai.NewPartFromExecutableCode("a()", genai.LanguagePython),
+			},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if diff := cmp.Diff(tc.want, cfg.ExtractCode(tc.parts)); diff != "" {
+				t.Errorf("ExtractCode() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestConfig_ToText(t *testing.T) {
+	cfg := &codeexecutor.Config{}
+	tests := []struct {
+		part *genai.Part
+		want string
+	}{
+		{genai.NewPartFromExecutableCode("print(1)", genai.LanguagePython), "```tool_code\nprint(1)\n```"},
+		{genai.NewPartFromCodeExecutionResult(genai.OutcomeOK, "1\n"), "```tool_output\n1\n\n```"},
+		{genai.NewPartFromCodeExecutionResult(genai.OutcomeFailed, "NameError"), "```tool_output\nError: NameError\n```"},
+		{genai.NewPartFromText("text"), "text"},
+	}
+	for _, tc := range tests {
+		if got := cfg.ToText(tc.part).Text; got != tc.want {
+			t.Errorf("ToText(%v) = %q, want %q", tc.part, got, tc.want)
+		}
+	}
+}
+
+type executorFunc func(ctx context.Context, in *codeexecutor.Input) (*codeexecutor.Result, error)
+
+func (f executorFunc) Execute(ctx context.Context, in *codeexecutor.Input) (*codeexecutor.Result, error) {
+	return f(ctx, in)
+}
+
+func TestConfig_Run(t *testing.T) {
+	var executionIDs []string
+	executor := executorFunc(func(ctx context.Context, in *codeexecutor.Input) (*codeexecutor.Result, error) {
+		executionIDs = append(executionIDs, in.ExecutionID)
+		switch in.Code {
+		case "fail":
+			return &codeexecutor.Result{Stdout: "partial", Stderr: "Traceback", ExitCode: 1}, nil
+		case "broken":
+			return nil, errors.New("executor unavailable")
+		}
+		return &codeexecutor.Result{Stdout: "ok\n", Stderr: "warning\n"}, nil
+	})
+
+	tests := []struct {
+		code     string
+		stateful bool
+		want     *genai.Part
+	}{
+		{"print", false, genai.NewPartFromCodeExecutionResult(genai.OutcomeOK, "ok\nwarning\n")},
+		{"fail", true, genai.NewPartFromCodeExecutionResult(genai.OutcomeFailed, "partial\nTraceback")},
+		{"broken", false, genai.NewPartFromCodeExecutionResult(genai.OutcomeFailed, "executor unavailable")},
+	}
+	for _, tc := range tests {
+		cfg := &codeexecutor.Config{Executor: executor, Stateful: tc.stateful}
+		got, err := cfg.Run(t.Context(), tc.code, "session")
+		if err != nil {
+			t.Fatalf("Run(%q) error = %v", tc.code, err)
+		}
+		if diff := cmp.Diff(tc.want, got); diff != "" {
+			t.Errorf("Run(%q) mismatch (-want +got):\n%s", tc.code, diff)
+		}
+	}
+	if diff := cmp.Diff([]string{"", "session", ""}, executionIDs); diff != "" {
+		t.Errorf("execution IDs mismatch (-want +got):\n%s", diff)
+	}
+}
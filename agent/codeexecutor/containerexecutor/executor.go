@@ -0,0 +1,245 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package containerexecutor provides a [codeexecutor.CodeExecutor] running
+// the code in containers, with the docker command line, or a compatible one
+// such as podman.
+//
+// Each run has limited CPU, memory, processes and time, and no network
+// unless Config.Network is set. With Config.Runtime set to "runsc", the
+// containers run in the gVisor sandbox, which isolates them further from the
+// host kernel.
+//
+// The stateless runs, whose Input.ExecutionID is empty, each run in a new
+// container, removed afterwards. The stateful runs of an execution ID run in
+// the same container, kept until Close, so they share the files they write,
+// e.g. a dataset downloaded by a previous run. The variables of the code do
+// not persist across runs.
+package containerexecutor
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/adk/agent/codeexecutor"
+)
+
+// Defaults of the Config.
+const (
+	DefaultImage   = "python:3.12-slim"
+	DefaultCommand = "docker"
+	DefaultCPUs    = 1
+	DefaultMemory  = "512m"
+	DefaultPIDs    = 128
+	DefaultTimeout = 30 * time.Second
+)
+
+// Config is the configuration of an Executor.
+type Config struct {
+	// Image of the containers. Defaults to DefaultImage.
+	Image string
+	// Command is the container command line. Defaults to DefaultCommand.
+	Command string
+	// Runtime of the containers, e.g. "runsc" for gVisor. Defaults to the
+	// runtime of the container engine.
+	Runtime string
+	// Interpreter runs the code, read from its standard input. Defaults to
+	// "python3 -".
+	Interpreter []string
+	// CPUs is the number of CPUs of a container. Defaults to DefaultCPUs.
+	CPUs float64
+	// Memory is the memory limit of a container. Defaults to DefaultMemory.
+	Memory string
+	// PIDs is the limit of the processes of a container. Defaults to
+	// DefaultPIDs.
+	PIDs int
+	// Timeout of a run. Defaults to DefaultTimeout.
+	Timeout time.Duration
+	// Network gives the containers access to the network.
+	Network bool
+}
+
+// Executor runs code in containers. It must be closed to remove the
+// containers of the stateful runs.
+type Executor struct {
+	cfg Config
+	// id distinguishes the containers of the executor from the ones of the
+	// other executors.
+	id string
+
+	mu sync.Mutex
+	// containers are the names of the containers of the execution IDs.
+	containers map[string]string
+}
+
+// New returns an executor.
+func New(cfg Config) *Executor {
+	if cfg.Image == "" {
+		cfg.Image = DefaultImage
+	}
+	if cfg.Command == "" {
+		cfg.Command = DefaultCommand
+	}
+	if len(cfg.Interpreter) == 0 {
+		cfg.Interpreter = []string{"python3", "-"}
+	}
+	if cfg.CPUs == 0 {
+		cfg.CPUs = DefaultCPUs
+	}
+	if cfg.Memory == "" {
+		cfg.Memory = DefaultMemory
+	}
+	if cfg.PIDs == 0 {
+		cfg.PIDs = DefaultPIDs
+	}
+	if cfg.Timeout == 0 {
+		cfg.Timeout = DefaultTimeout
+	}
+	return &Executor{cfg: cfg, id: strings.ToLower(rand.Text()[:8]), containers: make(map[string]string)}
+}
+
+// Execute implements [codeexecutor.CodeExecutor].
+func (e *Executor) Execute(ctx context.Context, in *codeexecutor.Input) (*codeexecutor.Result, error) {
+	if in.ExecutionID == "" {
+		name := containerName(rand.Text())
+		args := append([]string{"run", "--rm", "-i", "--name", name}, e.limits()...)
+		args = append(append(args, e.cfg.Image), e.cfg.Interpreter...)
+		return e.run(ctx, name, in.Code, args)
+	}
+
+	name, err := e.container(ctx, in.ExecutionID)
+	if err != nil {
+		return nil, err
+	}
+	args := append([]string{"exec", "-i", name}, e.cfg.Interpreter...)
+	res, err := e.run(ctx, name, in.Code, args)
+	if err != nil {
+		// The container is started again by the next run. It may have been
+		// removed already, see run.
+		e.mu.Lock()
+		delete(e.containers, in.ExecutionID)
+		e.mu.Unlock()
+		_ = e.remove(name)
+	}
+	return res, err
+}
+
+// Close removes the containers of the stateful runs.
+func (e *Executor) Close() error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	var errs []error
+	for id, name := range e.containers {
+		if err := e.remove(name); err != nil {
+			errs = append(errs, err)
+		}
+		delete(e.containers, id)
+	}
+	return errors.Join(errs...)
+}
+
+// limits returns the options of the containers limiting their resources.
+func (e *Executor) limits() []string {
+	args := []string{
+		"--cpus", strconv.FormatFloat(e.cfg.CPUs, 'f', -1, 64),
+		"--memory", e.cfg.Memory,
+		"--pids-limit", strconv.Itoa(e.cfg.PIDs),
+	}
+	if !e.cfg.Network {
+		args = append(args, "--network", "none")
+	}
+	if e.cfg.Runtime != "" {
+		args = append(args, "--runtime", e.cfg.Runtime)
+	}
+	return args
+}
+
+// container returns the container of the execution ID, started if needed.
+func (e *Executor) container(ctx context.Context, executionID string) (string, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if name, ok := e.containers[executionID]; ok {
+		return name, nil
+	}
+	sum := sha256.Sum256([]byte(executionID))
+	name := containerName(e.id + "-" + hex.EncodeToString(sum[:8]))
+	args := append([]string{"run", "-d", "--name", name}, e.limits()...)
+	args = append(args, e.cfg.Image, "sleep", "infinity")
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, e.cfg.Command, args...)
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("failed to start container: %w: %s", err, stderr.String())
+	}
+	e.containers[executionID] = name
+	return name, nil
+}
+
+// run runs the command with the code as its input. On timeout, or if the
+// context is canceled, the container is removed, since stopping the command
+// line does not stop it.
+func (e *Executor) run(ctx context.Context, container, code string, args []string) (*codeexecutor.Result, error) {
+	runCtx, cancel := context.WithTimeout(ctx, e.cfg.Timeout)
+	defer cancel()
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(runCtx, e.cfg.Command, args...)
+	cmd.Stdin = bytes.NewBufferString(code)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	err := cmd.Run()
+	if runCtx.Err() != nil {
+		if rmErr := e.remove(container); rmErr != nil {
+			return nil, errors.Join(rmErr, runCtx.Err())
+		}
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		return nil, fmt.Errorf("code execution timed out after %v", e.cfg.Timeout)
+	}
+	var exitErr *exec.ExitError
+	switch {
+	case errors.As(err, &exitErr) && exitErr.ExitCode() < 125:
+		// Exit codes from 125 are failures of the container command line.
+	case err != nil:
+		return nil, fmt.Errorf("failed to run container: %w: %s", err, stderr.String())
+	}
+	return &codeexecutor.Result{Stdout: stdout.String(), Stderr: stderr.String(), ExitCode: cmd.ProcessState.ExitCode()}, nil
+}
+
+func containerName(id string) string {
+	return "adk-exec-" + strings.ToLower(id)
+}
+
+func (e *Executor) remove(container string) error {
+	var stderr bytes.Buffer
+	cmd := exec.Command(e.cfg.Command, "rm", "-f", container)
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("failed to remove container %s: %w: %s", container, err, stderr.String())
+	}
+	return nil
+}
+
+var _ codeexecutor.CodeExecutor = (*Executor)(nil)
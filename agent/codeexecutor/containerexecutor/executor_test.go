@@ -0,0 +1,136 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package containerexecutor
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"google.golang.org/adk/agent/codeexecutor"
+)
+
+// fakeDocker writes a container command line logging its arguments, and
+// echoing the code it reads for run -i and exec -i. The code "sleep" sleeps,
+// and the code "fail" fails.
+func fakeDocker(t *testing.T) (command, log string) {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("the fake command line is a shell script")
+	}
+	dir := t.TempDir()
+	log = filepath.Join(dir, "log")
+	script := `#!/bin/sh
+echo "$@" >> ` + log + `
+case " $* " in
+*" -i "*)
+	code=$(cat)
+	case "$code" in
+	sleep) exec sleep 5 ;;
+	fail) echo "Traceback" >&2; exit 1 ;;
+	esac
+	echo "ran $code"
+	;;
+esac
+`
+	command = filepath.Join(dir, "docker")
+	if err := os.WriteFile(command, []byte(script), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	return command, log
+}
+
+func calls(t *testing.T, log string) []string {
+	t.Helper()
+	b, err := os.ReadFile(log)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The container names are random.
+	s := regexp.MustCompile(`adk-exec-[a-z0-9-]+`).ReplaceAllString(strings.TrimSpace(string(b)), "NAME")
+	return strings.Split(s, "\n")
+}
+
+func TestExecutor_Stateless(t *testing.T) {
+	command, log := fakeDocker(t)
+	e := New(Config{Command: command, Runtime: "runsc"})
+
+	got, err := e.Execute(t.Context(), &codeexecutor.Input{Code: "print(1)"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(&codeexecutor.Result{Stdout: "ran print(1)\n"}, got); diff != "" {
+		t.Errorf("Execute() mismatch (-want +got):\n%s", diff)
+	}
+	got, err = e.Execute(t.Context(), &codeexecutor.Input{Code: "fail"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(&codeexecutor.Result{Stderr: "Traceback\n", ExitCode: 1}, got); diff != "" {
+		t.Errorf("Execute() mismatch (-want +got):\n%s", diff)
+	}
+
+	run := "run --rm -i --name NAME --cpus 1 --memory 512m --pids-limit 128 --network none --runtime runsc python:3.12-slim python3 -"
+	if diff := cmp.Diff([]string{run, run}, calls(t, log)); diff != "" {
+		t.Errorf("calls mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestExecutor_Stateful(t *testing.T) {
+	command, log := fakeDocker(t)
+	e := New(Config{Command: command, Network: true, CPUs: 0.5, Memory: "1g"})
+
+	for _, code := range []string{"a", "b"} {
+		got, err := e.Execute(t.Context(), &codeexecutor.Input{Code: code, ExecutionID: "session"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.Stdout != "ran "+code+"\n" {
+			t.Errorf("Execute(%q) stdout = %q", code, got.Stdout)
+		}
+	}
+	if err := e.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{
+		"run -d --name NAME --cpus 0.5 --memory 1g --pids-limit 128 python:3.12-slim sleep infinity",
+		"exec -i NAME python3 -",
+		"exec -i NAME python3 -",
+		"rm -f NAME",
+	}
+	if diff := cmp.Diff(want, calls(t, log)); diff != "" {
+		t.Errorf("calls mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestExecutor_Timeout(t *testing.T) {
+	command, log := fakeDocker(t)
+	e := New(Config{Command: command, Timeout: 100 * time.Millisecond})
+
+	if _, err := e.Execute(t.Context(), &codeexecutor.Input{Code: "sleep"}); err == nil || !strings.Contains(err.Error(), "timed out") {
+		t.Fatalf("Execute() error = %v, want a timeout", err)
+	}
+	got := calls(t, log)
+	if len(got) != 2 || got[1] != "rm -f NAME" {
+		t.Errorf("calls = %q, want the container removed", got)
+	}
+}
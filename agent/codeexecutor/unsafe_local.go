@@ -0,0 +1,136 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package codeexecutor
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"sync"
+	"time"
+)
+
+// DefaultUnsafeLocalTimeout is the default of UnsafeLocalConfig.Timeout.
+const DefaultUnsafeLocalTimeout = 30 * time.Second
+
+// UnsafeLocalConfig is the configuration of an UnsafeLocalExecutor.
+type UnsafeLocalConfig struct {
+	// Interpreter runs the code, read from its standard input. Defaults to
+	// "python3 -".
+	Interpreter []string
+	// Timeout of a run. Defaults to DefaultUnsafeLocalTimeout.
+	Timeout time.Duration
+	// Dir is the directory of the working directories of the runs. Defaults
+	// to the default directory for temporary files.
+	Dir string
+}
+
+// UnsafeLocalExecutor runs the code in a process on the local machine, with
+// the permissions, the environment and the network access of the program.
+// It is meant for prototyping only: the code written by a model must not be
+// trusted, use a sandbox such as package containerexecutor in production.
+//
+// Each run has its own working directory, removed afterwards, except the
+// stateful runs of an execution ID, which share theirs until Close.
+type UnsafeLocalExecutor struct {
+	cfg UnsafeLocalConfig
+
+	mu sync.Mutex
+	// dirs are the working directories of the execution IDs.
+	dirs map[string]string
+}
+
+// NewUnsafeLocal returns an executor running the code on the local machine.
+func NewUnsafeLocal(cfg UnsafeLocalConfig) *UnsafeLocalExecutor {
+	if len(cfg.Interpreter) == 0 {
+		cfg.Interpreter = []string{"python3", "-"}
+	}
+	if cfg.Timeout == 0 {
+		cfg.Timeout = DefaultUnsafeLocalTimeout
+	}
+	return &UnsafeLocalExecutor{cfg: cfg, dirs: make(map[string]string)}
+}
+
+// Execute implements [CodeExecutor].
+func (e *UnsafeLocalExecutor) Execute(ctx context.Context, in *Input) (*Result, error) {
+	dir, err := e.workDir(in.ExecutionID)
+	if err != nil {
+		return nil, err
+	}
+	if in.ExecutionID == "" {
+		defer os.RemoveAll(dir)
+	}
+
+	runCtx, cancel := context.WithTimeout(ctx, e.cfg.Timeout)
+	defer cancel()
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(runCtx, e.cfg.Interpreter[0], e.cfg.Interpreter[1:]...)
+	cmd.Dir = dir
+	cmd.Stdin = bytes.NewBufferString(in.Code)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	// The children of the interpreter may keep its output open once it is
+	// killed.
+	cmd.WaitDelay = time.Second
+	err = cmd.Run()
+	if runCtx.Err() != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		return nil, fmt.Errorf("code execution timed out after %v", e.cfg.Timeout)
+	}
+	var exitErr *exec.ExitError
+	if err != nil && !errors.As(err, &exitErr) {
+		return nil, fmt.Errorf("failed to run the interpreter: %w", err)
+	}
+	return &Result{Stdout: stdout.String(), Stderr: stderr.String(), ExitCode: cmd.ProcessState.ExitCode()}, nil
+}
+
+// Close removes the working directories of the stateful runs.
+func (e *UnsafeLocalExecutor) Close() error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	var errs []error
+	for id, dir := range e.dirs {
+		if err := os.RemoveAll(dir); err != nil {
+			errs = append(errs, err)
+		}
+		delete(e.dirs, id)
+	}
+	return errors.Join(errs...)
+}
+
+// workDir returns the working directory of the execution ID, a new one if
+// it is empty.
+func (e *UnsafeLocalExecutor) workDir(executionID string) (string, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if dir, ok := e.dirs[executionID]; ok {
+		return dir, nil
+	}
+	dir, err := os.MkdirTemp(e.cfg.Dir, "adk-exec-")
+	if err != nil {
+		return "", fmt.Errorf("failed to create the working directory: %w", err)
+	}
+	if executionID != "" {
+		e.dirs[executionID] = dir
+	}
+	return dir, nil
+}
+
+var _ CodeExecutor = (*UnsafeLocalExecutor)(nil)
@@ -0,0 +1,85 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package codeexecutor_test
+
+import (
+	"os/exec"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"google.golang.org/adk/agent/codeexecutor"
+)
+
+func TestUnsafeLocalExecutor(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("no shell")
+	}
+	e := codeexecutor.NewUnsafeLocal(codeexecutor.UnsafeLocalConfig{Interpreter: []string{"sh"}, Timeout: time.Second})
+	defer e.Close()
+
+	tests := []struct {
+		name string
+		in   *codeexecutor.Input
+		want *codeexecutor.Result
+	}{
+		{
+			name: "output",
+			in:   &codeexecutor.Input{Code: "echo out; echo err >&2"},
+			want: &codeexecutor.Result{Stdout: "out\n", Stderr: "err\n"},
+		},
+		{
+			name: "exit code",
+			in:   &codeexecutor.Input{Code: "exit 3"},
+			want: &codeexecutor.Result{ExitCode: 3},
+		},
+		{
+			name: "stateless runs do not share files",
+			in:   &codeexecutor.Input{Code: "echo data > f; ls"},
+			want: &codeexecutor.Result{Stdout: "f\n"},
+		},
+		{
+			name: "stateful run writes a file",
+			in:   &codeexecutor.Input{Code: "echo data > f; ls", ExecutionID: "s1"},
+			want: &codeexecutor.Result{Stdout: "f\n"},
+		},
+		{
+			name: "stateful run reads the file",
+			in:   &codeexecutor.Input{Code: "cat f", ExecutionID: "s1"},
+			want: &codeexecutor.Result{Stdout: "data\n"},
+		},
+		{
+			name: "other execution ID",
+			in:   &codeexecutor.Input{Code: "ls", ExecutionID: "s2"},
+			want: &codeexecutor.Result{},
+		},
+	}
+	for _, tc := range tests {
+		got, err := e.Execute(t.Context(), tc.in)
+		if err != nil {
+			t.Fatalf("%s: Execute() error = %v", tc.name, err)
+		}
+		if diff := cmp.Diff(tc.want, got); diff != "" {
+			t.Errorf("%s: Execute() mismatch (-want +got):\n%s", tc.name, diff)
+		}
+	}
+
+	e = codeexecutor.NewUnsafeLocal(codeexecutor.UnsafeLocalConfig{Interpreter: []string{"sh"}, Timeout: 50 * time.Millisecond})
+	if _, err := e.Execute(t.Context(), &codeexecutor.Input{Code: "sleep 5"}); err == nil || !strings.Contains(err.Error(), "timed out") {
+		t.Errorf("Execute() error = %v, want a timeout", err)
+	}
+}
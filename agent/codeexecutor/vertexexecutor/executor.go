@@ -0,0 +1,156 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package vertexexecutor provides a [codeexecutor.CodeExecutor] running the
+// code with the code interpreter extension of Vertex AI, a managed Python
+// sandbox.
+//
+// The extension is created once per project and location, e.g. with the
+// Vertex AI SDK for Python:
+//
+//	from vertexai.preview import extensions
+//	extensions.Extension.from_hub("code_interpreter")
+//
+// The runs are stateless: Input.ExecutionID is ignored. The files written by
+// the code are not returned.
+//
+// This mirrors adk-python
+// src/google/adk/code_executors/vertex_ai_code_executor.py.
+package vertexexecutor
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"golang.org/x/oauth2/google"
+
+	"google.golang.org/adk/agent/codeexecutor"
+	"google.golang.org/adk/internal/utils"
+)
+
+const cloudPlatformScope = "https://www.googleapis.com/auth/cloud-platform"
+
+var extensionNameRegexp = regexp.MustCompile(`^projects/[^/]+/locations/[^/]+/extensions/[^/]+$`)
+
+// Config is the configuration of an Executor.
+type Config struct {
+	// Project and Location of the extension, required unless Extension is
+	// a resource name.
+	Project, Location string
+	// Extension is the ID, or the resource name, of the code interpreter
+	// extension. Required.
+	Extension string
+	// HTTPClient sends the requests. Defaults to a client authorized with
+	// the application default credentials.
+	HTTPClient *http.Client
+	// Endpoint of the Vertex AI API. Defaults to the regional endpoint of
+	// the location.
+	Endpoint string
+}
+
+// Executor runs code with the code interpreter extension of Vertex AI.
+type Executor struct {
+	httpClient *http.Client
+	url        string
+}
+
+// New returns an executor.
+func New(ctx context.Context, cfg Config) (*Executor, error) {
+	name := cfg.Extension
+	switch {
+	case name == "":
+		return nil, fmt.Errorf("extension is required")
+	case !extensionNameRegexp.MatchString(name):
+		if cfg.Project == "" || cfg.Location == "" {
+			return nil, fmt.Errorf("project and location are required for the extension ID %q", name)
+		}
+		name = fmt.Sprintf("projects/%s/locations/%s/extensions/%s", cfg.Project, cfg.Location, name)
+	}
+	location := strings.Split(name, "/")[3]
+
+	httpClient := cfg.HTTPClient
+	if httpClient == nil {
+		var err error
+		httpClient, err = google.DefaultClient(ctx, cloudPlatformScope)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find the default credentials: %w", err)
+		}
+	}
+	endpoint := cfg.Endpoint
+	if endpoint == "" {
+		endpoint = fmt.Sprintf("https://%s-aiplatform.googleapis.com", location)
+	}
+	return &Executor{
+		httpClient: httpClient,
+		url:        strings.TrimSuffix(endpoint, "/") + "/v1beta1/" + name + ":execute",
+	}, nil
+}
+
+type executeRequest struct {
+	OperationID     string         `json:"operationId"`
+	OperationParams map[string]any `json:"operationParams"`
+}
+
+type executeResponse struct {
+	// Content is the JSON encoded output of the operation.
+	Content string `json:"content"`
+}
+
+type executionOutput struct {
+	ExecutionResult string `json:"execution_result"`
+	ExecutionError  string `json:"execution_error"`
+}
+
+// Execute implements [codeexecutor.CodeExecutor]. The runs which report an
+// error have the exit code 1, since the extension does not report it.
+func (e *Executor) Execute(ctx context.Context, in *codeexecutor.Input) (*codeexecutor.Result, error) {
+	body, err := json.Marshal(executeRequest{OperationID: "execute", OperationParams: map[string]any{"code": in.Code}})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode the request: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.url, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := e.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call the code interpreter: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, fmt.Errorf("code interpreter failed: %w", utils.NewAPIError(resp))
+	}
+
+	var out executeResponse
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return nil, fmt.Errorf("failed to decode the response: %w", err)
+	}
+	var output executionOutput
+	if err := json.Unmarshal([]byte(out.Content), &output); err != nil {
+		return nil, fmt.Errorf("failed to decode the output %q: %w", out.Content, err)
+	}
+	res := &codeexecutor.Result{Stdout: output.ExecutionResult, Stderr: output.ExecutionError}
+	if res.Stderr != "" {
+		res.ExitCode = 1
+	}
+	return res, nil
+}
+
+var _ codeexecutor.CodeExecutor = (*Executor)(nil)
@@ -0,0 +1,92 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vertexexecutor_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"google.golang.org/adk/agent/codeexecutor"
+	"google.golang.org/adk/agent/codeexecutor/vertexexecutor"
+)
+
+func TestExecutor(t *testing.T) {
+	var gotPath string
+	var gotBody map[string]any
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		if err := json.NewDecoder(r.Body).Decode(&gotBody); err != nil {
+			t.Error(err)
+		}
+		code := gotBody["operationParams"].(map[string]any)["code"]
+		content := map[string]any{"execution_result": "", "execution_error": "NameError"}
+		if code == "print(1)" {
+			content = map[string]any{"execution_result": "1\n", "execution_error": ""}
+		}
+		b, _ := json.Marshal(content)
+		json.NewEncoder(w).Encode(map[string]any{"content": string(b)})
+	}))
+	defer srv.Close()
+
+	e, err := vertexexecutor.New(t.Context(), vertexexecutor.Config{
+		Project:    "p",
+		Location:   "us-central1",
+		Extension:  "123",
+		HTTPClient: srv.Client(),
+		Endpoint:   srv.URL,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := e.Execute(t.Context(), &codeexecutor.Input{Code: "print(1)", ExecutionID: "ignored"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(&codeexecutor.Result{Stdout: "1\n"}, got); diff != "" {
+		t.Errorf("Execute() mismatch (-want +got):\n%s", diff)
+	}
+	if want := "/v1beta1/projects/p/locations/us-central1/extensions/123:execute"; gotPath != want {
+		t.Errorf("path = %q, want %q", gotPath, want)
+	}
+	wantBody := map[string]any{"operationId": "execute", "operationParams": map[string]any{"code": "print(1)"}}
+	if diff := cmp.Diff(wantBody, gotBody); diff != "" {
+		t.Errorf("request body mismatch (-want +got):\n%s", diff)
+	}
+
+	got, err = e.Execute(t.Context(), &codeexecutor.Input{Code: "x"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(&codeexecutor.Result{Stderr: "NameError", ExitCode: 1}, got); diff != "" {
+		t.Errorf("Execute() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestNew_Errors(t *testing.T) {
+	for _, cfg := range []vertexexecutor.Config{
+		{},
+		{Extension: "123"},
+		{Extension: "123", Project: "p"},
+	} {
+		if _, err := vertexexecutor.New(t.Context(), cfg); err == nil {
+			t.Errorf("New(%+v) succeeded, want error", cfg)
+		}
+	}
+}
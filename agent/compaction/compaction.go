@@ -0,0 +1,266 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package compaction bounds the conversation history that an LLM agent sends
+// to its model in long sessions. It is configured with
+// llmagent.Config.ContextCompaction.
+//
+// Two strategies are provided, which can be combined:
+//   - a sliding window, [Config.MaxTokens], leaving the oldest turns of the
+//     conversation out of the requests once the history exceeds an estimated
+//     number of tokens;
+//   - a [Summarizer], asking a model to collapse the old events into a
+//     summary event stored in the session, which then stands for them in the
+//     requests.
+//
+// The events of the session are left untouched: the strategies only apply
+// when the requests are built.
+package compaction
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/agent"
+	"google.golang.org/adk/model"
+	"google.golang.org/adk/session"
+	"google.golang.org/adk/usage"
+)
+
+// MetadataKey is the key in session.Event.CustomMetadata of the summary
+// events. The value is a map with the "last_event_id" key, the ID of the
+// last event the summary stands for.
+const MetadataKey = "adk_compaction"
+
+// DefaultInstruction is the instruction of the summarizer model.
+const DefaultInstruction = "You summarize conversations between a user and an AI agent." +
+	" Write a concise summary of the conversation below, keeping the facts, decisions," +
+	" results of the tool calls and open questions that are needed to carry on the conversation." +
+	" Answer with the summary only."
+
+// DefaultTriggerTokens is the default of Summarizer.TriggerTokens.
+const DefaultTriggerTokens = 10000
+
+// summaryPrefix starts the text of the summary events.
+const summaryPrefix = "Summary of the earlier conversation:\n"
+
+// Config is the context-management strategy of an agent.
+type Config struct {
+	// MaxTokens is the estimated number of tokens of the history above
+	// which its oldest turns are left out of the requests, see
+	// usage.EstimateTokens. The latest turn is always sent. Zero means no
+	// limit.
+	MaxTokens int64
+	// Summarizer collapses the old events into a summary event, or nil for
+	// none.
+	Summarizer *Summarizer
+}
+
+// Summarizer asks a model to summarize the events of the previous turns of
+// the conversation, once they exceed TriggerTokens. The summary is appended
+// to the session before the agent calls its model, and replaces the events
+// it summarizes, and the previous summary, in the requests.
+type Summarizer struct {
+	// Model writing the summaries. Required.
+	Model model.LLM
+	// Instruction of the model. Defaults to DefaultInstruction.
+	Instruction string
+	// TriggerTokens is the estimated number of tokens of the events not
+	// summarized yet, the current turn excluded, above which they are
+	// summarized. Defaults to DefaultTriggerTokens.
+	TriggerTokens int64
+}
+
+// IsSummary reports whether the event is a summary event.
+func IsSummary(ev *session.Event) bool {
+	_, ok := ev.CustomMetadata[MetadataKey]
+	return ok
+}
+
+// History returns the events the requests are built from: the latest
+// summary in place of the events it summarizes, followed by the events of
+// the sliding window.
+func (c *Config) History(events []*session.Event) []*session.Event {
+	summary, rest := summarized(events)
+	if c.MaxTokens <= 0 {
+		return prepend(summary, rest)
+	}
+
+	budget := c.MaxTokens
+	if summary != nil {
+		budget -= usage.EstimateTokens(summary.Content)
+	}
+	total := int64(0)
+	for _, ev := range rest {
+		total += usage.EstimateTokens(ev.Content)
+	}
+	// The window starts at the first turn whose events fit, or else at the
+	// latest turn.
+	start, kept := 0, total
+	for i := 1; i < len(rest) && kept > budget; i++ {
+		total -= usage.EstimateTokens(rest[i-1].Content)
+		if isTurnStart(rest[i]) {
+			start, kept = i, total
+		}
+	}
+	return prepend(summary, rest[start:])
+}
+
+// Summarize returns a summary event of the events of the session preceding
+// the current turn, or nil if there are not enough of them to summarize.
+// The event is to be appended to the session.
+func (c *Config) Summarize(ctx agent.InvocationContext) (*session.Event, error) {
+	s := c.Summarizer
+	if s == nil || ctx.Session() == nil {
+		return nil, nil
+	}
+	if s.Model == nil {
+		return nil, errors.New("summarizer model is required")
+	}
+	var events []*session.Event
+	for ev := range ctx.Session().Events().All() {
+		events = append(events, ev)
+	}
+
+	// The current turn is not summarized.
+	current := len(events)
+	for current > 0 && !isTurnStart(events[current-1]) {
+		current--
+	}
+	if current == 0 {
+		return nil, nil
+	}
+	summary, rest := summarized(events[:current-1])
+	if len(rest) == 0 {
+		return nil, nil
+	}
+	trigger := s.TriggerTokens
+	if trigger <= 0 {
+		trigger = DefaultTriggerTokens
+	}
+	total := int64(0)
+	for _, ev := range rest {
+		total += usage.EstimateTokens(ev.Content)
+	}
+	if total <= trigger {
+		return nil, nil
+	}
+
+	instruction := s.Instruction
+	if instruction == "" {
+		instruction = DefaultInstruction
+	}
+	req := &model.LLMRequest{
+		Model:    s.Model.Name(),
+		Contents: []*genai.Content{genai.NewContentFromText(transcript(prepend(summary, rest)), genai.RoleUser)},
+		Config: &genai.GenerateContentConfig{
+			SystemInstruction: genai.NewContentFromText(instruction, genai.RoleUser),
+		},
+	}
+	var text strings.Builder
+	for resp, err := range s.Model.GenerateContent(ctx, req, false) {
+		if err != nil {
+			return nil, fmt.Errorf("failed to summarize the conversation: %w", err)
+		}
+		if resp.Partial || resp.Content == nil {
+			continue
+		}
+		for _, part := range resp.Content.Parts {
+			if !part.Thought {
+				text.WriteString(part.Text)
+			}
+		}
+	}
+	if strings.TrimSpace(text.String()) == "" {
+		return nil, errors.New("failed to summarize the conversation: the model returned no summary")
+	}
+
+	ev := session.NewEvent(ctx.InvocationID())
+	ev.Author = ctx.Agent().Name()
+	ev.Branch = ctx.Branch()
+	ev.Content = genai.NewContentFromText(summaryPrefix+strings.TrimSpace(text.String()), genai.RoleModel)
+	ev.CustomMetadata = map[string]any{
+		MetadataKey: map[string]any{"last_event_id": rest[len(rest)-1].ID},
+	}
+	return ev, nil
+}
+
+// summarized returns the latest summary of the events, if any, and the
+// events it does not summarize, without the summary events.
+func summarized(events []*session.Event) (*session.Event, []*session.Event) {
+	var summary *session.Event
+	start := 0
+	for i := len(events) - 1; i >= 0 && summary == nil; i-- {
+		if !IsSummary(events[i]) {
+			continue
+		}
+		m, _ := events[i].CustomMetadata[MetadataKey].(map[string]any)
+		lastID, _ := m["last_event_id"].(string)
+		if j := slices.IndexFunc(events[:i], func(ev *session.Event) bool { return ev.ID == lastID }); j >= 0 {
+			summary, start = events[i], j+1
+		}
+	}
+	var rest []*session.Event
+	for _, ev := range events[start:] {
+		if !IsSummary(ev) {
+			rest = append(rest, ev)
+		}
+	}
+	return summary, rest
+}
+
+// isTurnStart reports whether the event is a message of the user, as
+// opposed to the function responses sent by the client.
+func isTurnStart(ev *session.Event) bool {
+	if ev.Author != "user" || ev.Content == nil {
+		return false
+	}
+	return !slices.ContainsFunc(ev.Content.Parts, func(p *genai.Part) bool { return p.FunctionResponse != nil })
+}
+
+func prepend(summary *session.Event, events []*session.Event) []*session.Event {
+	if summary == nil {
+		return events
+	}
+	return append([]*session.Event{summary}, events...)
+}
+
+// transcript returns the text of the events for the summarizer model.
+func transcript(events []*session.Event) string {
+	var lines []string
+	for _, ev := range events {
+		if ev.Content == nil {
+			continue
+		}
+		for _, part := range ev.Content.Parts {
+			switch {
+			case part.Thought:
+			case part.Text != "":
+				lines = append(lines, ev.Author+": "+part.Text)
+			case part.FunctionCall != nil:
+				args, _ := json.Marshal(part.FunctionCall.Args)
+				lines = append(lines, fmt.Sprintf("%s called tool %s with %s", ev.Author, part.FunctionCall.Name, args))
+			case part.FunctionResponse != nil:
+				resp, _ := json.Marshal(part.FunctionResponse.Response)
+				lines = append(lines, fmt.Sprintf("tool %s returned %s", part.FunctionResponse.Name, resp))
+			}
+		}
+	}
+	return strings.Join(lines, "\n")
+}
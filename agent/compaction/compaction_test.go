@@ -0,0 +1,120 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package compaction_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/agent/compaction"
+	"google.golang.org/adk/model"
+	"google.golang.org/adk/session"
+	"google.golang.org/adk/usage"
+)
+
+func event(id, author string, parts ...*genai.Part) *session.Event {
+	role := genai.RoleModel
+	if author == "user" {
+		role = genai.RoleUser
+	}
+	ev := session.NewEvent("inv")
+	ev.ID = id
+	ev.Author = author
+	ev.LLMResponse = model.LLMResponse{Content: genai.NewContentFromParts(parts, genai.Role(role))}
+	return ev
+}
+
+func text(id, author, s string) *session.Event {
+	return event(id, author, genai.NewPartFromText(s))
+}
+
+func summary(id, lastEventID string) *session.Event {
+	ev := text(id, "agent", "summary")
+	ev.CustomMetadata = map[string]any{compaction.MetadataKey: map[string]any{"last_event_id": lastEventID}}
+	return ev
+}
+
+func ids(events []*session.Event) []string {
+	var got []string
+	for _, ev := range events {
+		got = append(got, ev.ID)
+	}
+	return got
+}
+
+func TestHistory(t *testing.T) {
+	long := strings.Repeat("x", 400)
+	tokens := usage.EstimateTokens(text("", "user", long).Content)
+
+	events := []*session.Event{
+		text("u1", "user", long),
+		text("m1", "agent", long),
+		text("u2", "user", long),
+		event("c2", "agent", genai.NewPartFromFunctionCall("tool", nil)),
+		event("r2", "user", genai.NewPartFromFunctionResponse("tool", nil)),
+		text("m2", "agent", long),
+		text("u3", "user", long),
+	}
+
+	tests := []struct {
+		name   string
+		cfg    compaction.Config
+		events []*session.Event
+		want   []string
+	}{
+		{
+			name:   "no limit",
+			events: events,
+			want:   []string{"u1", "m1", "u2", "c2", "r2", "m2", "u3"},
+		},
+		{
+			name:   "window drops whole turns",
+			cfg:    compaction.Config{MaxTokens: 4 * tokens},
+			events: events,
+			want:   []string{"u2", "c2", "r2", "m2", "u3"},
+		},
+		{
+			name:   "latest turn always kept",
+			cfg:    compaction.Config{MaxTokens: 1},
+			events: events,
+			want:   []string{"u3"},
+		},
+		{
+			name:   "summary replaces the summarized events",
+			events: append(append(events[:3:3], summary("s1", "m1")), events[3:]...),
+			want:   []string{"s1", "u2", "c2", "r2", "m2", "u3"},
+		},
+		{
+			name:   "latest summary wins",
+			events: append(append(events[:6:6], summary("s1", "m1"), summary("s2", "m2")), events[6:]...),
+			want:   []string{"s2", "u3"},
+		},
+		{
+			name:   "summary of unknown events ignored",
+			events: append(events[:2:2], summary("s1", "gone"), events[6]),
+			want:   []string{"u1", "m1", "u3"},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if diff := cmp.Diff(tc.want, ids(tc.cfg.History(tc.events))); diff != "" {
+				t.Errorf("History() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
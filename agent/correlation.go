@@ -0,0 +1,47 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package agent
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// WithCorrelationID returns a copy of ctx carrying the given correlation ID.
+//
+// The correlation ID joins agent activity with upstream and downstream
+// systems. When the returned context is passed to runner.Run, the ID is
+// propagated to every event, tool call, model request and trace span of the
+// invocation. If no correlation ID is provided, the runner generates one.
+func WithCorrelationID(ctx context.Context, correlationID string) context.Context {
+	return context.WithValue(ctx, correlationIDCtxKey, correlationID)
+}
+
+// CorrelationIDFromContext returns the correlation ID carried by ctx, or an
+// empty string if there is none.
+func CorrelationIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(correlationIDCtxKey).(string)
+	return id
+}
+
+// NewCorrelationID generates a new correlation ID.
+func NewCorrelationID() string {
+	return "c-" + uuid.NewString()
+}
+
+type correlationIDKey struct{}
+
+var correlationIDCtxKey = correlationIDKey{}
@@ -0,0 +1,226 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package guardrail provides output guardrails: application-defined policy
+// checks run on the final answer of an agent before it is returned.
+//
+// Guardrails complement the safety settings of the model: they enforce the
+// policy of the application, such as forbidden topics or leaked secrets, and
+// can allow the answer, rewrite it, or block it and return a fallback
+// message instead. They are configured with llmagent.Config.OutputGuardrails.
+//
+// The package provides three guardrails: [NewBlocklist] matches regular
+// expressions, [NewClassifier] scores the answer with a pluggable classifier
+// such as a toxicity model, and [NewModelCheck] asks an LLM to judge the
+// answer against a written policy.
+package guardrail
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+
+	"google.golang.org/adk/agent"
+)
+
+// MetadataKey is the key in session.Event.CustomMetadata of the events
+// reporting that a guardrail rewrote or blocked an answer. The value is a
+// map with the "guardrail", "action" and "reason" keys.
+const MetadataKey = "adk_guardrail"
+
+// DefaultFallback is the message returned instead of a blocked answer.
+const DefaultFallback = "I'm sorry, I can't provide that response."
+
+// Action is the decision of a guardrail.
+type Action string
+
+const (
+	// ActionAllow returns the answer unchanged.
+	ActionAllow Action = "allow"
+	// ActionRewrite replaces the answer with Verdict.Output.
+	ActionRewrite Action = "rewrite"
+	// ActionBlock replaces the answer with the fallback message.
+	ActionBlock Action = "block"
+)
+
+// Verdict is the result of a guardrail check.
+type Verdict struct {
+	Action Action
+	// Output is the rewritten answer, for ActionRewrite.
+	Output string
+	// Reason explains the decision. It is recorded in the guardrail event
+	// but not shown to the user.
+	Reason string
+}
+
+// Allow is the verdict allowing the answer.
+var Allow = &Verdict{Action: ActionAllow}
+
+// Guardrail checks the final answer of an agent.
+type Guardrail interface {
+	// Name identifies the guardrail in the events it emits.
+	Name() string
+	// Check returns the verdict on the answer. A nil verdict allows it.
+	Check(ctx agent.ReadonlyContext, output string) (*Verdict, error)
+}
+
+// Outcome records a guardrail that rewrote or blocked an answer.
+type Outcome struct {
+	Guardrail string
+	Verdict
+}
+
+// Metadata returns the value stored under MetadataKey in the guardrail
+// event.
+func (o *Outcome) Metadata() map[string]any {
+	return map[string]any{
+		"guardrail": o.Guardrail,
+		"action":    string(o.Action),
+		"reason":    o.Reason,
+	}
+}
+
+// Apply runs the guardrails in order on the answer. Each guardrail sees the
+// answer rewritten by the previous ones, and the first block stops the
+// checks. It returns the final answer, whether it was blocked, and the
+// outcomes of the guardrails that rewrote or blocked it.
+func Apply(ctx agent.ReadonlyContext, guardrails []Guardrail, output string) (string, bool, []Outcome, error) {
+	var outcomes []Outcome
+	for _, g := range guardrails {
+		v, err := g.Check(ctx, output)
+		if err != nil {
+			return "", false, nil, fmt.Errorf("guardrail %q failed: %w", g.Name(), err)
+		}
+		if v == nil {
+			continue
+		}
+		switch v.Action {
+		case ActionAllow:
+		case ActionRewrite:
+			outcomes = append(outcomes, Outcome{Guardrail: g.Name(), Verdict: *v})
+			output = v.Output
+		case ActionBlock:
+			outcomes = append(outcomes, Outcome{Guardrail: g.Name(), Verdict: *v})
+			return "", true, outcomes, nil
+		default:
+			return "", false, nil, fmt.Errorf("guardrail %q returned unknown action %q", g.Name(), v.Action)
+		}
+	}
+	return output, false, outcomes, nil
+}
+
+// BlocklistConfig is the configuration of a blocklist guardrail.
+type BlocklistConfig struct {
+	// Name of the guardrail. Defaults to "blocklist".
+	Name string
+	// Patterns the answer must not match. Required.
+	Patterns []*regexp.Regexp
+	// Redact rewrites the answer by replacing the matches with Replacement
+	// instead of blocking it.
+	Redact bool
+	// Replacement of the matches when Redact is set. Defaults to
+	// "[redacted]".
+	Replacement string
+}
+
+// NewBlocklist returns a guardrail blocking, or redacting, the answers
+// matching any of the patterns.
+func NewBlocklist(cfg BlocklistConfig) (Guardrail, error) {
+	if len(cfg.Patterns) == 0 {
+		return nil, errors.New("error creating blocklist guardrail: no patterns")
+	}
+	if cfg.Name == "" {
+		cfg.Name = "blocklist"
+	}
+	if cfg.Replacement == "" {
+		cfg.Replacement = "[redacted]"
+	}
+	return &blocklist{cfg: cfg}, nil
+}
+
+type blocklist struct {
+	cfg BlocklistConfig
+}
+
+func (b *blocklist) Name() string {
+	return b.cfg.Name
+}
+
+func (b *blocklist) Check(_ agent.ReadonlyContext, output string) (*Verdict, error) {
+	var matched []string
+	for _, p := range b.cfg.Patterns {
+		if p.MatchString(output) {
+			matched = append(matched, p.String())
+			if b.cfg.Redact {
+				output = p.ReplaceAllLiteralString(output, b.cfg.Replacement)
+			}
+		}
+	}
+	if len(matched) == 0 {
+		return Allow, nil
+	}
+	reason := fmt.Sprintf("matched %q", matched)
+	if b.cfg.Redact {
+		return &Verdict{Action: ActionRewrite, Output: output, Reason: reason}, nil
+	}
+	return &Verdict{Action: ActionBlock, Reason: reason}, nil
+}
+
+// ClassifyFunc scores the answer, e.g. its toxicity, between 0 and 1.
+type ClassifyFunc func(ctx context.Context, output string) (float64, error)
+
+// ClassifierConfig is the configuration of a classifier guardrail.
+type ClassifierConfig struct {
+	// Name of the guardrail. Defaults to "classifier".
+	Name string
+	// Classify scores the answer. Required.
+	Classify ClassifyFunc
+	// Threshold at or above which the answer is blocked. Defaults to 0.5.
+	Threshold float64
+}
+
+// NewClassifier returns a guardrail blocking the answers scored at or above
+// the threshold by the classifier.
+func NewClassifier(cfg ClassifierConfig) (Guardrail, error) {
+	if cfg.Classify == nil {
+		return nil, errors.New("error creating classifier guardrail: no classify function")
+	}
+	if cfg.Name == "" {
+		cfg.Name = "classifier"
+	}
+	if cfg.Threshold <= 0 {
+		cfg.Threshold = 0.5
+	}
+	return &classifier{cfg: cfg}, nil
+}
+
+type classifier struct {
+	cfg ClassifierConfig
+}
+
+func (c *classifier) Name() string {
+	return c.cfg.Name
+}
+
+func (c *classifier) Check(ctx agent.ReadonlyContext, output string) (*Verdict, error) {
+	score, err := c.cfg.Classify(ctx, output)
+	if err != nil {
+		return nil, err
+	}
+	if score < c.cfg.Threshold {
+		return Allow, nil
+	}
+	return &Verdict{Action: ActionBlock, Reason: fmt.Sprintf("score %.2f reaches the threshold %.2f", score, c.cfg.Threshold)}, nil
+}
@@ -0,0 +1,191 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package guardrail_test
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/agent"
+	"google.golang.org/adk/agent/guardrail"
+	icontext "google.golang.org/adk/internal/context"
+	"google.golang.org/adk/internal/testutil"
+)
+
+func newContext(t *testing.T) agent.ReadonlyContext {
+	return icontext.NewReadonlyContext(icontext.NewInvocationContext(t.Context(), icontext.InvocationContextParams{}))
+}
+
+func mustBlocklist(t *testing.T, cfg guardrail.BlocklistConfig) guardrail.Guardrail {
+	t.Helper()
+	g, err := guardrail.NewBlocklist(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return g
+}
+
+func TestApply(t *testing.T) {
+	secret := regexp.MustCompile(`sk-[a-z0-9]+`)
+	forbidden := regexp.MustCompile(`(?i)competitor`)
+	redact := mustBlocklist(t, guardrail.BlocklistConfig{Name: "secrets", Patterns: []*regexp.Regexp{secret}, Redact: true})
+	block := mustBlocklist(t, guardrail.BlocklistConfig{Name: "topics", Patterns: []*regexp.Regexp{forbidden}})
+
+	tests := []struct {
+		name         string
+		output       string
+		wantOutput   string
+		wantBlocked  bool
+		wantOutcomes []guardrail.Outcome
+	}{
+		{
+			name:       "allowed",
+			output:     "Hello.",
+			wantOutput: "Hello.",
+		},
+		{
+			name:       "rewritten",
+			output:     "Your key is sk-abc123.",
+			wantOutput: "Your key is [redacted].",
+			wantOutcomes: []guardrail.Outcome{{
+				Guardrail: "secrets",
+				Verdict:   guardrail.Verdict{Action: guardrail.ActionRewrite, Output: "Your key is [redacted].", Reason: `matched ["sk-[a-z0-9]+"]`},
+			}},
+		},
+		{
+			name:        "rewritten and blocked",
+			output:      "Use sk-abc123 with our Competitor.",
+			wantBlocked: true,
+			wantOutcomes: []guardrail.Outcome{
+				{
+					Guardrail: "secrets",
+					Verdict:   guardrail.Verdict{Action: guardrail.ActionRewrite, Output: "Use [redacted] with our Competitor.", Reason: `matched ["sk-[a-z0-9]+"]`},
+				},
+				{
+					Guardrail: "topics",
+					Verdict:   guardrail.Verdict{Action: guardrail.ActionBlock, Reason: `matched ["(?i)competitor"]`},
+				},
+			},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			output, blocked, outcomes, err := guardrail.Apply(newContext(t), []guardrail.Guardrail{redact, block}, tc.output)
+			if err != nil {
+				t.Fatalf("Apply() error = %v", err)
+			}
+			if output != tc.wantOutput || blocked != tc.wantBlocked {
+				t.Errorf("Apply() = (%q, %v), want (%q, %v)", output, blocked, tc.wantOutput, tc.wantBlocked)
+			}
+			if diff := cmp.Diff(tc.wantOutcomes, outcomes); diff != "" {
+				t.Errorf("Apply() outcomes mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestClassifier(t *testing.T) {
+	errClassifier := errors.New("classifier unavailable")
+	g, err := guardrail.NewClassifier(guardrail.ClassifierConfig{
+		Threshold: 0.8,
+		Classify: func(_ context.Context, output string) (float64, error) {
+			switch output {
+			case "rude":
+				return 0.9, nil
+			case "error":
+				return 0, errClassifier
+			}
+			return 0.1, nil
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, blocked, _, err := guardrail.Apply(newContext(t), []guardrail.Guardrail{g}, "polite"); err != nil || blocked {
+		t.Errorf("Apply(polite) = (blocked %v, %v), want (false, nil)", blocked, err)
+	}
+	if _, blocked, _, err := guardrail.Apply(newContext(t), []guardrail.Guardrail{g}, "rude"); err != nil || !blocked {
+		t.Errorf("Apply(rude) = (blocked %v, %v), want (true, nil)", blocked, err)
+	}
+	if _, _, _, err := guardrail.Apply(newContext(t), []guardrail.Guardrail{g}, "error"); !errors.Is(err, errClassifier) {
+		t.Errorf("Apply(error) error = %v, want %v", err, errClassifier)
+	}
+}
+
+func TestModelCheck(t *testing.T) {
+	tests := []struct {
+		name            string
+		response        string
+		disallowRewrite bool
+		want            *guardrail.Verdict
+		wantErr         bool
+	}{
+		{
+			name:     "allow",
+			response: `{"action": "allow"}`,
+			want:     &guardrail.Verdict{Action: guardrail.ActionAllow},
+		},
+		{
+			name:     "rewrite",
+			response: `{"action": "rewrite", "output": "Fixed.", "reason": "tone"}`,
+			want:     &guardrail.Verdict{Action: guardrail.ActionRewrite, Output: "Fixed.", Reason: "tone"},
+		},
+		{
+			name:            "rewrite disallowed",
+			response:        `{"action": "rewrite", "output": "Fixed.", "reason": "tone"}`,
+			disallowRewrite: true,
+			want:            &guardrail.Verdict{Action: guardrail.ActionBlock, Reason: "tone"},
+		},
+		{
+			name:     "block",
+			response: `{"action": "block", "reason": "medical advice"}`,
+			want:     &guardrail.Verdict{Action: guardrail.ActionBlock, Reason: "medical advice"},
+		},
+		{
+			name:     "invalid",
+			response: `allow`,
+			wantErr:  true,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			m := &testutil.MockModel{Responses: []*genai.Content{genai.NewContentFromText(tc.response, genai.RoleModel)}}
+			g, err := guardrail.NewModelCheck(guardrail.ModelCheckConfig{
+				Model:           m,
+				Policy:          "Never give medical advice.",
+				DisallowRewrite: tc.disallowRewrite,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := g.Check(newContext(t), "Take two aspirins.")
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("Check() error = %v, wantErr %v", err, tc.wantErr)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("Check() mismatch (-want +got):\n%s", diff)
+			}
+			if len(m.Requests) != 1 || m.Requests[0].Config.ResponseSchema == nil {
+				t.Errorf("model requests = %v, want one request with a response schema", m.Requests)
+			}
+		})
+	}
+}
@@ -0,0 +1,131 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package guardrail
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/agent"
+	"google.golang.org/adk/model"
+)
+
+const modelCheckInstruction = `You review the answers of an assistant before they are shown to the user.
+Check the answer against this policy:
+
+%s
+
+Reply with a JSON object:
+- "action": "allow" if the answer complies with the policy, "rewrite" if it can be fixed by editing it, "block" otherwise;
+- "output": the complete fixed answer, only for "rewrite";
+- "reason": a short explanation of the decision.`
+
+// ModelCheckConfig is the configuration of a model-based guardrail.
+type ModelCheckConfig struct {
+	// Name of the guardrail. Defaults to "model_check".
+	Name string
+	// Model judging the answers. Required.
+	Model model.LLM
+	// Policy the answers must comply with, in natural language. Required.
+	Policy string
+	// DisallowRewrite makes the guardrail block the answers the model would
+	// rewrite.
+	DisallowRewrite bool
+}
+
+// NewModelCheck returns a guardrail asking the model whether the answer
+// complies with the policy.
+func NewModelCheck(cfg ModelCheckConfig) (Guardrail, error) {
+	if cfg.Model == nil {
+		return nil, errors.New("error creating model check guardrail: no model")
+	}
+	if strings.TrimSpace(cfg.Policy) == "" {
+		return nil, errors.New("error creating model check guardrail: no policy")
+	}
+	if cfg.Name == "" {
+		cfg.Name = "model_check"
+	}
+	return &modelCheck{cfg: cfg}, nil
+}
+
+type modelCheck struct {
+	cfg ModelCheckConfig
+}
+
+func (m *modelCheck) Name() string {
+	return m.cfg.Name
+}
+
+var modelVerdictSchema = &genai.Schema{
+	Type: genai.TypeObject,
+	Properties: map[string]*genai.Schema{
+		"action": {Type: genai.TypeString, Enum: []string{string(ActionAllow), string(ActionRewrite), string(ActionBlock)}},
+		"output": {Type: genai.TypeString},
+		"reason": {Type: genai.TypeString},
+	},
+	Required:         []string{"action"},
+	PropertyOrdering: []string{"action", "output", "reason"},
+}
+
+func (m *modelCheck) Check(ctx agent.ReadonlyContext, output string) (*Verdict, error) {
+	req := &model.LLMRequest{
+		Model:    m.cfg.Model.Name(),
+		Contents: []*genai.Content{genai.NewContentFromText("Answer to review:\n\n"+output, genai.RoleUser)},
+		Config: &genai.GenerateContentConfig{
+			SystemInstruction: genai.NewContentFromText(fmt.Sprintf(modelCheckInstruction, m.cfg.Policy), genai.RoleUser),
+			ResponseMIMEType:  "application/json",
+			ResponseSchema:    modelVerdictSchema,
+		},
+	}
+	var b strings.Builder
+	for resp, err := range m.cfg.Model.GenerateContent(ctx, req, false) {
+		if err != nil {
+			return nil, err
+		}
+		if resp.Content == nil {
+			continue
+		}
+		for _, p := range resp.Content.Parts {
+			if !p.Thought {
+				b.WriteString(p.Text)
+			}
+		}
+	}
+
+	var resp struct {
+		Action string `json:"action"`
+		Output string `json:"output"`
+		Reason string `json:"reason"`
+	}
+	if err := json.Unmarshal([]byte(b.String()), &resp); err != nil {
+		return nil, fmt.Errorf("invalid verdict %q: %w", b.String(), err)
+	}
+	v := &Verdict{Action: Action(resp.Action), Output: resp.Output, Reason: resp.Reason}
+	switch v.Action {
+	case ActionAllow, ActionBlock:
+		v.Output = ""
+	case ActionRewrite:
+		if m.cfg.DisallowRewrite || strings.TrimSpace(v.Output) == "" {
+			v.Action, v.Output = ActionBlock, ""
+		}
+	default:
+		return nil, fmt.Errorf("invalid verdict action %q", resp.Action)
+	}
+	return v, nil
+}
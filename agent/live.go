@@ -0,0 +1,107 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package agent
+
+import (
+	"errors"
+	"sync"
+
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/model"
+)
+
+// ErrLiveRequestQueueClosed is returned when sending on a closed
+// LiveRequestQueue.
+var ErrLiveRequestQueueClosed = errors.New("live request queue is closed")
+
+// LiveRequestQueue is the input of a live run, see runner.Runner.RunLive:
+// the client sends on it the messages, the audio or video chunks of the
+// user, and closes it to end the run.
+//
+// It is safe for concurrent use.
+type LiveRequestQueue struct {
+	requests  chan *model.LiveRequest
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+// liveRequestQueueSize is the number of requests buffered by a
+// LiveRequestQueue, after which sending blocks until the model connection
+// catches up.
+const liveRequestQueueSize = 64
+
+// NewLiveRequestQueue returns an open LiveRequestQueue.
+func NewLiveRequestQueue() *LiveRequestQueue {
+	return &LiveRequestQueue{
+		requests: make(chan *model.LiveRequest, liveRequestQueueSize),
+		done:     make(chan struct{}),
+	}
+}
+
+// SendContent sends a turn of the conversation, e.g. a text message.
+func (q *LiveRequestQueue) SendContent(content *genai.Content) error {
+	return q.Send(&model.LiveRequest{Content: content})
+}
+
+// SendRealtime sends a chunk of realtime input, e.g. audio or video.
+func (q *LiveRequestQueue) SendRealtime(blob *genai.Blob) error {
+	return q.Send(&model.LiveRequest{Blob: blob})
+}
+
+// SendActivityStart marks the start of the user activity, when the
+// automatic activity detection is disabled.
+func (q *LiveRequestQueue) SendActivityStart() error {
+	return q.Send(&model.LiveRequest{ActivityStart: true})
+}
+
+// SendActivityEnd marks the end of the user activity, when the automatic
+// activity detection is disabled.
+func (q *LiveRequestQueue) SendActivityEnd() error {
+	return q.Send(&model.LiveRequest{ActivityEnd: true})
+}
+
+// Send sends a request. It blocks while the buffer of the queue is full, and
+// fails with ErrLiveRequestQueueClosed once the queue is closed.
+func (q *LiveRequestQueue) Send(req *model.LiveRequest) error {
+	select {
+	case <-q.done:
+		return ErrLiveRequestQueueClosed
+	default:
+	}
+	select {
+	case <-q.done:
+		return ErrLiveRequestQueueClosed
+	case q.requests <- req:
+		return nil
+	}
+}
+
+// Close closes the queue, ending the live run once the requests buffered are
+// sent to the model. Closing a closed queue does nothing.
+func (q *LiveRequestQueue) Close() {
+	q.closeOnce.Do(func() { close(q.done) })
+}
+
+// Requests returns the channel of the requests sent on the queue.
+func (q *LiveRequestQueue) Requests() <-chan *model.LiveRequest {
+	return q.requests
+}
+
+// Done returns a channel closed when the queue is closed. The requests
+// buffered in Requests are still to be read.
+func (q *LiveRequestQueue) Done() <-chan struct{} {
+	return q.done
+}
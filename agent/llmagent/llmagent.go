@@ -15,6 +15,7 @@
 package llmagent
 
 import (
+	"errors"
 	"fmt"
 	"iter"
 	"strings"
@@ -22,9 +23,14 @@ import (
 	"google.golang.org/genai"
 
 	"google.golang.org/adk/agent"
+	"google.golang.org/adk/agent/codeexecutor"
+	"google.golang.org/adk/agent/compaction"
+	"google.golang.org/adk/agent/guardrail"
+	"google.golang.org/adk/agent/planner"
 	agentinternal "google.golang.org/adk/internal/agent"
 	icontext "google.golang.org/adk/internal/context"
 	"google.golang.org/adk/internal/llminternal"
+	"google.golang.org/adk/internal/utils"
 	"google.golang.org/adk/model"
 	"google.golang.org/adk/session"
 	"google.golang.org/adk/tool"
@@ -52,21 +58,46 @@ func New(cfg Config) (agent.Agent, error) {
 		afterToolCallbacks = append(afterToolCallbacks, llminternal.AfterToolCallback(c))
 	}
 
+	generateContentConfig := cfg.GenerateContentConfig
+	if cfg.ThinkingConfig != nil {
+		if generateContentConfig != nil && generateContentConfig.ThinkingConfig != nil {
+			return nil, errors.New("ThinkingConfig and GenerateContentConfig.ThinkingConfig are both set")
+		}
+		var copied genai.GenerateContentConfig
+		if generateContentConfig != nil {
+			copied = *generateContentConfig
+		}
+		copied.ThinkingConfig = cfg.ThinkingConfig
+		generateContentConfig = &copied
+	}
+
+	if cfg.GuardrailFallback == "" {
+		cfg.GuardrailFallback = guardrail.DefaultFallback
+	}
+
 	a := &llmAgent{
 		beforeModelCallbacks: beforeModelCallbacks,
 		model:                cfg.Model,
 		afterModelCallbacks:  afterModelCallbacks,
 		beforeToolCallbacks:  beforeToolCallbacks,
 		afterToolCallbacks:   afterToolCallbacks,
+		parallelToolCalls:    cfg.ParallelToolCalls,
+		maxParallelToolCalls: cfg.MaxParallelToolCalls,
+		toolInterceptors:     cfg.ToolInterceptors,
+		invalidArgsRetries:   cfg.InvalidArgumentsRetries,
 		instruction:          cfg.Instruction,
 		inputSchema:          cfg.InputSchema,
 		outputSchema:         cfg.OutputSchema,
+		outputRetries:        cfg.OutputRetries,
+		outputGuardrails:     cfg.OutputGuardrails,
+		guardrailFallback:    cfg.GuardrailFallback,
 
 		State: llminternal.State{
 			Model:                    cfg.Model,
-			GenerateContentConfig:    cfg.GenerateContentConfig,
+			GenerateContentConfig:    generateContentConfig,
 			Tools:                    cfg.Tools,
 			Toolsets:                 cfg.Toolsets,
+			ToolConflicts:            cfg.ToolConflicts,
 			DisallowTransferToParent: cfg.DisallowTransferToParent,
 			DisallowTransferToPeers:  cfg.DisallowTransferToPeers,
 			InputSchema:              cfg.InputSchema,
@@ -78,6 +109,9 @@ func New(cfg Config) (agent.Agent, error) {
 			GlobalInstruction:         cfg.GlobalInstruction,
 			GlobalInstructionProvider: llminternal.InstructionProvider(cfg.GlobalInstructionProvider),
 			OutputKey:                 cfg.OutputKey,
+			Planner:                   cfg.Planner,
+			ContextCompaction:         cfg.ContextCompaction,
+			CodeExecutor:              cfg.CodeExecutor,
 		},
 	}
 
@@ -138,7 +172,16 @@ type Config struct {
 	//
 	// For example: use this config to adjust model temperature, configure
 	// safety settings, etc.
+	//
+	// These are the defaults of the agent: agent.RunConfig.GenerateContentConfig
+	// overrides them for a run.
 	GenerateContentConfig *genai.GenerateContentConfig
+	// ThinkingConfig configures the thinking of the models supporting it,
+	// such as Gemini 2.5: the budget of thinking tokens and whether the
+	// thoughts are returned, as parts marked as thoughts, see
+	// session.Event.Thoughts. It is a shorthand for
+	// GenerateContentConfig.ThinkingConfig, which must not be set as well.
+	ThinkingConfig *genai.ThinkingConfig
 
 	// BeforeModelCallbacks will be called in the order they are provided until
 	// there's a callback that returns a non-nil LLMResponse or error. Then
@@ -217,11 +260,17 @@ type Config struct {
 	// TODO(ngeorgy): consider to switch to jsonschema for input and output schema.
 	// The input schema when agent is used as a tool.
 	InputSchema *genai.Schema
-	// The output schema when agent replies.
+	// The output schema when agent replies. Use SchemaFor to infer it from a
+	// Go type, including the field descriptions.
 	//
 	// NOTE: when this is set, agent can only reply and cannot use any tools,
 	// such as function tools, RAGs, agent transfer, etc.
 	OutputSchema *genai.Schema
+	// OutputRetries is the number of times the model is asked to answer again
+	// when its final answer does not conform to OutputSchema, with a message
+	// reporting the validation error. Once the retries are exhausted, the
+	// last answer is returned as is.
+	OutputRetries int
 
 	// Callbacks are executed in the order they are provided.
 	// If a callback returns result/error, then the execution of the callback
@@ -236,13 +285,72 @@ type Config struct {
 	// Toolsets will be used by llmagent to extract tools and pass to the
 	// underlying LLM.
 	Toolsets []tool.Toolset
+	// ParallelToolCalls runs the function calls of a model response
+	// concurrently rather than in order, e.g. to fetch several independent
+	// results at once. The tools and the tool callbacks must then be safe for
+	// concurrent use, except the sequential tools, see
+	// functiontool.Config.Sequential, whose calls run one at a time after the
+	// others. The function responses are in the order of the calls either way.
+	ParallelToolCalls bool
+	// MaxParallelToolCalls is the maximum number of function calls run at
+	// once with ParallelToolCalls, e.g. to bound the load on a backend the
+	// tools share. Zero means no limit.
+	MaxParallelToolCalls int
+	// ToolInterceptors wrap the calls of the tools of the agent, e.g. to
+	// redact their arguments or check a quota, inside the interceptors of the
+	// runner. See tool.Interceptor.
+	ToolInterceptors []tool.Interceptor
+	// InvalidArgumentsRetries limits the number of consecutive calls of a
+	// tool, within an invocation, whose arguments are invalid, e.g. failing
+	// with a *functiontool.ValidationError, that are reported to the model so
+	// that it corrects them: the next one ends the invocation with its error.
+	// See tool.CodeInvalidArguments. Zero means no limit.
+	InvalidArgumentsRetries int
+	// ToolConflicts is how the tools sharing a name are handled, in the
+	// order of Tools then of the tools of Toolsets, e.g. to combine
+	// toolsets which both have a "search" tool. Defaults to
+	// tool.ConflictError. See also toolset.Prefix, which namespaces the
+	// tools of a toolset.
+	ToolConflicts tool.ConflictPolicy
 
 	// OutputKey is an optional parameter to specify the key in session state for the agent output.
+	// With an OutputSchema, the output conforming to it is saved parsed, as
+	// a map[string]any.
 	//
 	// Typical uses cases are:
 	// - Extracts agent reply for later use, such as in tools, callbacks, etc.
 	// - Connects agents to coordinate with each other.
 	OutputKey string
+
+	// Planner makes the model plan and reason before answering, and marks
+	// the planning and the reasoning as thoughts, see package planner. E.g.
+	// planner.NewBuiltIn for the models with built-in thinking, or
+	// planner.NewPlanReAct for the others.
+	Planner planner.Planner
+
+	// ContextCompaction bounds the conversation history sent to the model in
+	// long sessions, with a sliding window and/or summaries of the old events
+	// stored in the session, see package compaction. Nil sends the whole
+	// history.
+	ContextCompaction *compaction.Config
+
+	// CodeExecutor runs the code blocks written by the model, and sends
+	// their output back to it, see package codeexecutor. Nil runs no code.
+	CodeExecutor *codeexecutor.Config
+
+	// OutputGuardrails check the final answer of the agent, in order, before
+	// it is returned. A guardrail can rewrite the answer, or block it, in
+	// which case GuardrailFallback is returned instead. Each rewrite or block
+	// is reported by an event preceding the answer, with the outcome under
+	// guardrail.MetadataKey in its CustomMetadata.
+	//
+	// NOTE: when guardrails are set, the partial events of streamed answers
+	// are not returned, since they would reveal the answer before it is
+	// checked.
+	OutputGuardrails []guardrail.Guardrail
+	// GuardrailFallback is the answer returned when a guardrail blocks the
+	// answer of the agent. Defaults to guardrail.DefaultFallback.
+	GuardrailFallback string
 }
 
 // BeforeModelCallback that is called before sending a request to the model.
@@ -296,11 +404,19 @@ type llmAgent struct {
 	afterModelCallbacks  []llminternal.AfterModelCallback
 	instruction          string
 
-	beforeToolCallbacks []llminternal.BeforeToolCallback
-	afterToolCallbacks  []llminternal.AfterToolCallback
+	beforeToolCallbacks  []llminternal.BeforeToolCallback
+	afterToolCallbacks   []llminternal.AfterToolCallback
+	parallelToolCalls    bool
+	maxParallelToolCalls int
+	toolInterceptors     []tool.Interceptor
+	invalidArgsRetries   int
 
-	inputSchema  *genai.Schema
-	outputSchema *genai.Schema
+	inputSchema   *genai.Schema
+	outputSchema  *genai.Schema
+	outputRetries int
+
+	outputGuardrails  []guardrail.Guardrail
+	guardrailFallback string
 }
 
 type agentState = agentinternal.State
@@ -308,13 +424,14 @@ type agentState = agentinternal.State
 func (a *llmAgent) run(ctx agent.InvocationContext) iter.Seq2[*session.Event, error] {
 	// TODO: branch context?
 	ctx = icontext.NewInvocationContext(ctx, icontext.InvocationContextParams{
-		Artifacts:   ctx.Artifacts(),
-		Memory:      ctx.Memory(),
-		Session:     ctx.Session(),
-		Branch:      ctx.Branch(),
-		Agent:       a,
-		UserContent: ctx.UserContent(),
-		RunConfig:   ctx.RunConfig(),
+		Artifacts:    ctx.Artifacts(),
+		Memory:       ctx.Memory(),
+		Session:      ctx.Session(),
+		Branch:       ctx.Branch(),
+		Agent:        a,
+		UserContent:  ctx.UserContent(),
+		RunConfig:    ctx.RunConfig(),
+		InvocationID: ctx.InvocationID(),
 	})
 
 	f := &llminternal.Flow{
@@ -325,18 +442,145 @@ func (a *llmAgent) run(ctx agent.InvocationContext) iter.Seq2[*session.Event, er
 		AfterModelCallbacks:  a.afterModelCallbacks,
 		BeforeToolCallbacks:  a.beforeToolCallbacks,
 		AfterToolCallbacks:   a.afterToolCallbacks,
+		ParallelToolCalls:    a.parallelToolCalls,
+		MaxParallelToolCalls: a.maxParallelToolCalls,
+		ToolInterceptors:     a.toolInterceptors,
+		InvalidArgsRetries:   a.invalidArgsRetries,
 	}
 
 	return func(yield func(*session.Event, error) bool) {
-		for ev, err := range f.Run(ctx) {
-			a.maybeSaveOutputToState(ev)
-			if !yield(ev, err) {
+		if a.ContextCompaction != nil {
+			summary, err := a.ContextCompaction.Summarize(ctx)
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+			if summary != nil && !yield(summary, nil) {
+				return
+			}
+		}
+		for attempt := 0; ; attempt++ {
+			var repair *session.Event
+			for ev, err := range f.Run(ctx) {
+				if ev != nil && len(a.outputGuardrails) > 0 && ev.Author == a.Name() {
+					if ev.Partial {
+						continue
+					}
+					guardrailEvents, err := a.applyGuardrails(ctx, ev)
+					if err != nil {
+						yield(nil, err)
+						return
+					}
+					for _, gev := range guardrailEvents {
+						if !yield(gev, nil) {
+							return
+						}
+					}
+				}
+				if repair == nil && attempt < a.outputRetries {
+					repair = a.outputRepair(ctx, ev)
+				}
+				if repair == nil {
+					a.maybeSaveOutputToState(ev)
+				}
+				if !yield(ev, err) {
+					return
+				}
+			}
+			// The model answers again, seeing its invalid answer and the
+			// validation error in the history.
+			if repair == nil || !yield(repair, nil) {
 				return
 			}
 		}
 	}
 }
 
+// outputRepair returns the user message asking the model to answer again if
+// the event is a final answer of the agent not conforming to its output
+// schema, or nil.
+func (a *llmAgent) outputRepair(ctx agent.InvocationContext, event *session.Event) *session.Event {
+	if a.outputSchema == nil || event == nil || event.Author != a.Name() || !event.IsFinalResponse() {
+		return nil
+	}
+	output := outputText(event)
+	if strings.TrimSpace(output) == "" {
+		return nil
+	}
+	_, err := utils.ValidateOutputSchema(output, a.outputSchema)
+	if err == nil {
+		return nil
+	}
+	ev := session.NewEvent(ctx.InvocationID())
+	ev.Author = "user"
+	ev.Branch = event.Branch
+	ev.Content = genai.NewContentFromText(fmt.Sprintf("Your answer does not conform to the output schema: %v. Answer again with JSON conforming to the schema.", err), genai.RoleUser)
+	return ev
+}
+
+// outputText returns the text of the event, without the thoughts.
+func outputText(event *session.Event) string {
+	if event.Content == nil {
+		return ""
+	}
+	var sb strings.Builder
+	for _, part := range event.Content.Parts {
+		if part.Text != "" && !part.Thought {
+			sb.WriteString(part.Text)
+		}
+	}
+	return sb.String()
+}
+
+// applyGuardrails checks the event if it is the final answer of the agent,
+// rewriting or replacing its content as the guardrails decide. It returns
+// the events reporting the guardrails that rewrote or blocked the answer.
+func (a *llmAgent) applyGuardrails(ctx agent.InvocationContext, event *session.Event) ([]*session.Event, error) {
+	if !event.IsFinalResponse() || event.Content == nil {
+		return nil, nil
+	}
+	text := outputText(event)
+	if text == "" {
+		return nil, nil
+	}
+
+	output, blocked, outcomes, err := guardrail.Apply(icontext.NewReadonlyContext(ctx), a.outputGuardrails, text)
+	if err != nil {
+		return nil, err
+	}
+	if len(outcomes) == 0 {
+		return nil, nil
+	}
+
+	events := make([]*session.Event, 0, len(outcomes))
+	for _, o := range outcomes {
+		ev := session.NewEvent(ctx.InvocationID())
+		ev.Author = a.Name()
+		ev.Branch = event.Branch
+		ev.CustomMetadata = map[string]any{guardrail.MetadataKey: o.Metadata()}
+		events = append(events, ev)
+	}
+
+	if blocked {
+		event.Content = genai.NewContentFromText(a.guardrailFallback, genai.Role(event.Content.Role))
+		return events, nil
+	}
+	// The text parts, including thoughts, are replaced by the rewritten
+	// answer; the other parts are kept.
+	parts := make([]*genai.Part, 0, len(event.Content.Parts))
+	rewritten := false
+	for _, part := range event.Content.Parts {
+		if part.Text == "" {
+			parts = append(parts, part)
+		} else if !rewritten {
+			parts = append(parts, genai.NewPartFromText(output))
+			rewritten = true
+		}
+	}
+	event.Content = &genai.Content{Role: event.Content.Role, Parts: parts}
+	return events, nil
+}
+
 // maybeSaveOutputToState saves the model output to state if needed. skip if the event
 // was authored by some other agent (e.g. current agent transferred to another agent)
 func (a *llmAgent) maybeSaveOutputToState(event *session.Event) {
@@ -348,22 +592,20 @@ func (a *llmAgent) maybeSaveOutputToState(event *session.Event) {
 		return
 	}
 	if a.OutputKey != "" && !event.Partial && event.Content != nil && len(event.Content.Parts) > 0 {
-		var sb strings.Builder
-		for _, part := range event.Content.Parts {
-			if part.Text != "" && !part.Thought {
-				sb.WriteString(part.Text)
-			}
-		}
-		result := sb.String()
+		text := outputText(event)
+		var result any = text
 
-		// TODO: add output schema validation and unmarshalling
 		if a.OutputSchema != nil {
 			// If the result from the final chunk is just whitespace or empty,
 			// it means this is an empty final chunk of a stream.
 			// Do not attempt to parse it as JSON.
-			if strings.TrimSpace(result) == "" {
+			if strings.TrimSpace(text) == "" {
 				return
 			}
+			// The output not conforming to the schema is saved as text.
+			if parsed, err := utils.ValidateOutputSchema(text, a.OutputSchema); err == nil {
+				result = parsed
+			}
 		}
 
 		if event.Actions.StateDelta == nil {
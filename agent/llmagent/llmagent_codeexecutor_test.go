@@ -0,0 +1,142 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package llmagent_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/agent/codeexecutor"
+	"google.golang.org/adk/agent/llmagent"
+	"google.golang.org/adk/internal/testutil"
+)
+
+type fakeExecutor struct {
+	inputs []*codeexecutor.Input
+}
+
+func (e *fakeExecutor) Execute(ctx context.Context, in *codeexecutor.Input) (*codeexecutor.Result, error) {
+	e.inputs = append(e.inputs, in)
+	if in.Code == "boom()" {
+		return &codeexecutor.Result{Stderr: "NameError: boom", ExitCode: 1}, nil
+	}
+	return &codeexecutor.Result{Stdout: "45\n"}, nil
+}
+
+func TestCodeExecutor(t *testing.T) {
+	tests := []struct {
+		name         string
+		retries      int
+		responses    []string
+		wantCode     []string
+		wantEvents   []string
+		wantRequests [][]string
+	}{
+		{
+			name: "code output sent back",
+			responses: []string{
+				"Let me compute.\n```python\nprint(sum(range(10)))\n```\nIt prints 45.",
+				"The sum is 45.",
+			},
+			wantCode:   []string{"print(sum(range(10)))"},
+			wantEvents: []string{"text: Let me compute.\n", "code: print(sum(range(10)))", "result OUTCOME_OK: 45\n", "text: The sum is 45."},
+			wantRequests: [][]string{
+				{"sum"},
+				{"sum", "Let me compute.\n", "```tool_code\nprint(sum(range(10)))\n```", "```tool_output\n45\n\n```"},
+			},
+		},
+		{
+			name:    "failed code not retried forever",
+			retries: 1,
+			responses: []string{
+				"```python\nboom()\n```",
+				"```python\nboom()\n```",
+				"```python\nboom()\n```",
+			},
+			wantCode: []string{"boom()", "boom()"},
+			wantEvents: []string{
+				"code: boom()", "result OUTCOME_FAILED: NameError: boom",
+				"code: boom()", "result OUTCOME_FAILED: NameError: boom",
+				"text: ```python\nboom()\n```",
+			},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			m := &testutil.MockModel{}
+			for _, r := range tc.responses {
+				m.Responses = append(m.Responses, genai.NewContentFromText(r, genai.RoleModel))
+			}
+			executor := &fakeExecutor{}
+			a, err := llmagent.New(llmagent.Config{
+				Name:         "coder",
+				Model:        m,
+				CodeExecutor: &codeexecutor.Config{Executor: executor, Stateful: true, ErrorRetryAttempts: tc.retries},
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			events, err := testutil.CollectEvents(testutil.NewTestAgentRunner(t, a).Run(t, "session", "sum"))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var got []string
+			for _, ev := range events {
+				for _, part := range ev.Content.Parts {
+					switch {
+					case part.ExecutableCode != nil:
+						got = append(got, "code: "+part.ExecutableCode.Code)
+					case part.CodeExecutionResult != nil:
+						got = append(got, "result "+string(part.CodeExecutionResult.Outcome)+": "+part.CodeExecutionResult.Output)
+					default:
+						got = append(got, "text: "+part.Text)
+					}
+				}
+			}
+			if diff := cmp.Diff(tc.wantEvents, got); diff != "" {
+				t.Errorf("events mismatch (-want +got):\n%s", diff)
+			}
+			var code []string
+			for _, in := range executor.inputs {
+				code = append(code, in.Code)
+				if in.ExecutionID != "session" {
+					t.Errorf("execution ID = %q, want the session ID", in.ExecutionID)
+				}
+			}
+			if diff := cmp.Diff(tc.wantCode, code); diff != "" {
+				t.Errorf("executed code mismatch (-want +got):\n%s", diff)
+			}
+			if tc.wantRequests != nil {
+				var texts [][]string
+				for _, req := range m.Requests {
+					var contents []string
+					for _, c := range req.Contents {
+						for _, p := range c.Parts {
+							contents = append(contents, p.Text)
+						}
+					}
+					texts = append(texts, contents)
+				}
+				if diff := cmp.Diff(tc.wantRequests, texts); diff != "" {
+					t.Errorf("model requests mismatch (-want +got):\n%s", diff)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,101 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package llmagent_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/agent/compaction"
+	"google.golang.org/adk/agent/llmagent"
+	"google.golang.org/adk/internal/testutil"
+	"google.golang.org/adk/model"
+)
+
+func TestContextCompaction(t *testing.T) {
+	m := &testutil.MockModel{Responses: []*genai.Content{
+		genai.NewContentFromText("a1", genai.RoleModel),
+		genai.NewContentFromText("a2", genai.RoleModel),
+		genai.NewContentFromText("a3", genai.RoleModel),
+	}}
+	summarizer := &testutil.MockModel{Responses: []*genai.Content{
+		genai.NewContentFromText("S1", genai.RoleModel),
+		genai.NewContentFromText("S2", genai.RoleModel),
+	}}
+	a, err := llmagent.New(llmagent.Config{
+		Name:  "compacting_agent",
+		Model: m,
+		ContextCompaction: &compaction.Config{
+			Summarizer: &compaction.Summarizer{Model: summarizer, TriggerTokens: 1},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	runner := testutil.NewTestAgentRunner(t, a)
+
+	var summaries []string
+	for _, q := range []string{"q1", "q2", "q3"} {
+		events, err := testutil.CollectEvents(runner.Run(t, "session", q))
+		if err != nil {
+			t.Fatalf("Run(%q) error = %v", q, err)
+		}
+		for _, ev := range events {
+			if compaction.IsSummary(ev) {
+				summaries = append(summaries, ev.Content.Parts[0].Text)
+			}
+		}
+	}
+
+	wantSummaries := []string{"Summary of the earlier conversation:\nS1", "Summary of the earlier conversation:\nS2"}
+	if diff := cmp.Diff(wantSummaries, summaries); diff != "" {
+		t.Errorf("summaries mismatch (-want +got):\n%s", diff)
+	}
+	wantRequests := [][]string{
+		{"q1"},
+		{"Summary of the earlier conversation:\nS1", "q2"},
+		{"Summary of the earlier conversation:\nS2", "q3"},
+	}
+	if diff := cmp.Diff(wantRequests, requestTexts(m.Requests)); diff != "" {
+		t.Errorf("model requests mismatch (-want +got):\n%s", diff)
+	}
+	if len(summarizer.Requests) != 2 {
+		t.Fatalf("got %d summarizer requests, want 2", len(summarizer.Requests))
+	}
+	transcript := requestTexts(summarizer.Requests[1:])[0][0]
+	for _, want := range []string{"S1", "user: q2", "compacting_agent: a2"} {
+		if !strings.Contains(transcript, want) {
+			t.Errorf("summarizer transcript %q does not contain %q", transcript, want)
+		}
+	}
+	if strings.Contains(transcript, "q1") {
+		t.Errorf("summarizer transcript %q contains the summarized events", transcript)
+	}
+}
+
+func requestTexts(reqs []*model.LLMRequest) [][]string {
+	var texts [][]string
+	for _, req := range reqs {
+		var contents []string
+		for _, c := range req.Contents {
+			contents = append(contents, c.Parts[0].Text)
+		}
+		texts = append(texts, contents)
+	}
+	return texts
+}
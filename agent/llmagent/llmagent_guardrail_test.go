@@ -0,0 +1,134 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package llmagent_test
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/agent"
+	"google.golang.org/adk/agent/guardrail"
+	"google.golang.org/adk/agent/llmagent"
+	"google.golang.org/adk/internal/testutil"
+)
+
+func TestOutputGuardrails(t *testing.T) {
+	secrets, err := guardrail.NewBlocklist(guardrail.BlocklistConfig{
+		Name:     "secrets",
+		Patterns: []*regexp.Regexp{regexp.MustCompile(`sk-[a-z0-9]+`)},
+		Redact:   true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	topics, err := guardrail.NewBlocklist(guardrail.BlocklistConfig{
+		Name:     "topics",
+		Patterns: []*regexp.Regexp{regexp.MustCompile(`(?i)competitor`)},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name          string
+		response      string
+		stream        bool
+		wantTexts     []string
+		wantGuardrail []string
+		wantOutput    string
+	}{
+		{
+			name:       "allowed",
+			response:   "Hello!",
+			wantTexts:  []string{"Hello!"},
+			wantOutput: "Hello!",
+		},
+		{
+			name:          "rewritten",
+			response:      "Your key is sk-abc123.",
+			wantTexts:     []string{"Your key is [redacted]."},
+			wantGuardrail: []string{"secrets:rewrite"},
+			wantOutput:    "Your key is [redacted].",
+		},
+		{
+			name:          "blocked",
+			response:      "Ask our competitor.",
+			wantTexts:     []string{"Sorry."},
+			wantGuardrail: []string{"topics:block"},
+			wantOutput:    "Sorry.",
+		},
+		{
+			name:          "blocked when streaming",
+			response:      "Ask our competitor.",
+			stream:        true,
+			wantTexts:     []string{"Sorry."},
+			wantGuardrail: []string{"topics:block"},
+			wantOutput:    "Sorry.",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			m := &testutil.MockModel{Responses: []*genai.Content{genai.NewContentFromText(tc.response, genai.RoleModel)}}
+			a, err := llmagent.New(llmagent.Config{
+				Name:              "guarded_agent",
+				Model:             m,
+				OutputKey:         "answer",
+				OutputGuardrails:  []guardrail.Guardrail{secrets, topics},
+				GuardrailFallback: "Sorry.",
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var cfg agent.RunConfig
+			if tc.stream {
+				cfg.StreamingMode = agent.StreamingModeSSE
+			}
+			runner := testutil.NewTestAgentRunner(t, a)
+			var texts, guardrails []string
+			var output any
+			// The guardrail events have no content, so they are collected
+			// without testutil.CollectEvents.
+			for ev, err := range runner.RunContentWithConfig(t, "session", genai.NewContentFromText("hi", genai.RoleUser), cfg) {
+				if err != nil {
+					t.Fatal(err)
+				}
+				if md, ok := ev.CustomMetadata[guardrail.MetadataKey].(map[string]any); ok {
+					guardrails = append(guardrails, md["guardrail"].(string)+":"+md["action"].(string))
+				}
+				if ev.Content != nil {
+					for _, p := range ev.Content.Parts {
+						texts = append(texts, p.Text)
+					}
+				}
+				if v, ok := ev.Actions.StateDelta["answer"]; ok {
+					output = v
+				}
+			}
+			if diff := cmp.Diff(tc.wantTexts, texts); diff != "" {
+				t.Errorf("texts mismatch (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.wantGuardrail, guardrails); diff != "" {
+				t.Errorf("guardrail events mismatch (-want +got):\n%s", diff)
+			}
+			if output != tc.wantOutput {
+				t.Errorf("output state = %v, want %q", output, tc.wantOutput)
+			}
+		})
+	}
+}
@@ -29,6 +29,16 @@ type MockOutputSchema struct {
 	Confidence float64 `json:"confidence"`
 }
 
+// mockOutputSchema is the output schema of MockOutputSchema.
+var mockOutputSchema = &genai.Schema{
+	Type: genai.TypeObject,
+	Properties: map[string]*genai.Schema{
+		"message":    {Type: genai.TypeString},
+		"confidence": {Type: genai.TypeNumber},
+	},
+	Required: []string{"message"},
+}
+
 // createTestEvent is a helper to build events for tests.
 func createTestEvent(author, contentText string, isFinal bool) *session.Event {
 	var parts []*genai.Part
@@ -105,7 +115,24 @@ func TestLlmAgent_MaybeSaveOutputToState(t *testing.T) {
 			event:          createTestEvent("testagent", "Test response", true),
 			wantStateDelta: map[string]any{},
 		},
-		// TODO tests with OutputSchema
+		{
+			name:           "parses output conforming to output schema",
+			agentConfig:    Config{Name: "test_agent", OutputKey: "result", OutputSchema: mockOutputSchema},
+			event:          createTestEvent("test_agent", `{"message": "hi", "confidence": 0.5}`, true),
+			wantStateDelta: map[string]any{"result": map[string]any{"message": "hi", "confidence": 0.5}},
+		},
+		{
+			name:           "saves output not conforming to output schema as text",
+			agentConfig:    Config{Name: "test_agent", OutputKey: "result", OutputSchema: mockOutputSchema},
+			event:          createTestEvent("test_agent", `{"msg": "hi"}`, true),
+			wantStateDelta: map[string]any{"result": `{"msg": "hi"}`},
+		},
+		{
+			name:           "skips empty output with output schema",
+			agentConfig:    Config{Name: "test_agent", OutputKey: "result", OutputSchema: mockOutputSchema},
+			event:          createTestEvent("test_agent", " ", true),
+			wantStateDelta: map[string]any{},
+		},
 	}
 
 	// Iterate over the test cases
@@ -15,26 +15,37 @@
 package llmagent_test
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"iter"
+	"maps"
 	"net/http"
 	"path/filepath"
+	"slices"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 	"google.golang.org/genai"
 
 	"google.golang.org/adk/agent"
 	"google.golang.org/adk/agent/llmagent"
+	"google.golang.org/adk/agent/planner"
+	"google.golang.org/adk/auth"
 	"google.golang.org/adk/internal/httprr"
 	"google.golang.org/adk/internal/testutil"
 	"google.golang.org/adk/model"
 	"google.golang.org/adk/model/gemini"
+	"google.golang.org/adk/runner"
 	"google.golang.org/adk/session"
 	"google.golang.org/adk/tool"
 	"google.golang.org/adk/tool/functiontool"
+	"google.golang.org/adk/tool/toolconfirmation"
+	"google.golang.org/adk/tool/toolset"
 )
 
 const modelName = "gemini-2.0-flash"
@@ -738,6 +749,623 @@ func TestFunctionTool(t *testing.T) {
 	}
 }
 
+func TestFunctionTool_FunctionCall(t *testing.T) {
+	type Args struct {
+		City string `json:"city"`
+	}
+	lookup, err := functiontool.New(functiontool.Config{
+		Name:        "lookup",
+		Description: "looks up a city",
+	}, func(ctx tool.Context, args Args) (map[string]any, error) {
+		fc := ctx.FunctionCall()
+		if fc == nil || fc.ID != ctx.FunctionCallID() || fc.Name != "lookup" {
+			return nil, fmt.Errorf("unexpected function call %+v", fc)
+		}
+		return map[string]any{"call_id": fc.ID, "raw_city": fc.Args["city"]}, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	model := &testutil.MockModel{Responses: []*genai.Content{
+		genai.NewContentFromParts([]*genai.Part{
+			genai.NewPartFromFunctionCall("lookup", map[string]any{"city": "Paris"}),
+			genai.NewPartFromFunctionCall("lookup", map[string]any{"city": "Rome"}),
+		}, "model"),
+		genai.NewContentFromText("done", "model"),
+	}}
+	a, err := llmagent.New(llmagent.Config{
+		Name:                     "agent",
+		Model:                    model,
+		DisallowTransferToParent: true,
+		DisallowTransferToPeers:  true,
+		Tools:                    []tool.Tool{lookup},
+	})
+	if err != nil {
+		t.Fatalf("failed to create LLM Agent: %v", err)
+	}
+	runner := testutil.NewTestAgentRunner(t, a)
+
+	cities := make(map[string]string) // by call ID
+	var responses []*genai.FunctionResponse
+	for ev, err := range runner.Run(t, "session1", "look up Paris and Rome") {
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, p := range ev.LLMResponse.Content.Parts {
+			if p.FunctionCall != nil {
+				cities[p.FunctionCall.ID] = p.FunctionCall.Args["city"].(string)
+			}
+			if p.FunctionResponse != nil {
+				responses = append(responses, p.FunctionResponse)
+			}
+		}
+	}
+	if len(responses) != 2 {
+		t.Fatalf("got %d function responses, want 2", len(responses))
+	}
+	for _, resp := range responses {
+		if resp.Response["call_id"] != resp.ID {
+			t.Errorf("response %q has the result of the call %v", resp.ID, resp.Response["call_id"])
+		}
+		if resp.Response["raw_city"] != cities[resp.ID] {
+			t.Errorf("response %q has the city %v, want %q", resp.ID, resp.Response["raw_city"], cities[resp.ID])
+		}
+	}
+}
+
+func TestFunctionTool_Content(t *testing.T) {
+	type Args struct{}
+	png := []byte("\x89PNG")
+	chart, err := functiontool.New(functiontool.Config{
+		Name:        "chart",
+		Description: "draws the sales chart",
+	}, func(tool.Context, Args) (tool.Content, error) {
+		return tool.Content{Parts: []*genai.Part{
+			genai.NewPartFromText("Sales per month"),
+			genai.NewPartFromBytes(png, "image/png"),
+		}}, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	model := &testutil.MockModel{Responses: []*genai.Content{
+		genai.NewContentFromFunctionCall("chart", map[string]any{}, "model"),
+		genai.NewContentFromText("done", "model"),
+	}}
+	a, err := llmagent.New(llmagent.Config{
+		Name:                     "agent",
+		Model:                    model,
+		DisallowTransferToParent: true,
+		DisallowTransferToPeers:  true,
+		Tools:                    []tool.Tool{chart},
+	})
+	if err != nil {
+		t.Fatalf("failed to create LLM Agent: %v", err)
+	}
+
+	runner := testutil.NewTestAgentRunner(t, a)
+	if _, err := testutil.CollectTextParts(runner.Run(t, "session1", "draw the chart")); err != nil {
+		t.Fatal(err)
+	}
+	if len(model.Requests) != 2 {
+		t.Fatalf("model got %d requests, want 2", len(model.Requests))
+	}
+	contents := model.Requests[1].Contents
+	got := contents[len(contents)-1].Parts
+	for _, p := range got {
+		if p.FunctionResponse != nil {
+			p.FunctionResponse.ID = ""
+		}
+	}
+	want := []*genai.Part{
+		{FunctionResponse: &genai.FunctionResponse{Name: "chart", Response: map[string]any{"output": "Sales per month"}}},
+		genai.NewPartFromBytes(png, "image/png"),
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("function response parts mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestFunctionTool_State(t *testing.T) {
+	type Args struct {
+		Fail bool `json:"fail,omitempty"`
+	}
+	counter, err := functiontool.New(functiontool.Config{
+		Name:        "count",
+		Description: "increments the counter",
+	}, func(ctx tool.Context, args Args) (int, error) {
+		count := 0
+		if val, err := ctx.State().Get("count"); err == nil {
+			count = val.(int)
+		}
+		if err := ctx.State().Set("count", count+1); err != nil {
+			return 0, err
+		}
+		if args.Fail {
+			return 0, errors.New("failed")
+		}
+		return count + 1, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	call := func(fail bool) *genai.Part {
+		return genai.NewPartFromFunctionCall("count", map[string]any{"fail": fail})
+	}
+	model := &testutil.MockModel{Responses: []*genai.Content{
+		genai.NewContentFromParts([]*genai.Part{call(false)}, "model"),
+		genai.NewContentFromText("turn 1", "model"),
+		genai.NewContentFromParts([]*genai.Part{call(true)}, "model"),
+		genai.NewContentFromText("turn 2", "model"),
+		// The second call sees the change of the first one.
+		genai.NewContentFromParts([]*genai.Part{call(false), call(false)}, "model"),
+		genai.NewContentFromText("turn 3", "model"),
+	}}
+	a, err := llmagent.New(llmagent.Config{
+		Name:                     "agent",
+		Model:                    model,
+		DisallowTransferToParent: true,
+		DisallowTransferToPeers:  true,
+		Tools:                    []tool.Tool{counter},
+	})
+	if err != nil {
+		t.Fatalf("failed to create LLM Agent: %v", err)
+	}
+
+	runner := testutil.NewTestAgentRunner(t, a)
+	// The failed call of the second turn does not change the counter.
+	wants := [][]any{{1}, {nil}, {2, 3}}
+	for i, want := range wants {
+		var got []any
+		for ev, err := range runner.Run(t, "session1", fmt.Sprintf("turn %d", i+1)) {
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, p := range ev.LLMResponse.Content.Parts {
+				if p.FunctionResponse == nil {
+					continue
+				}
+				got = append(got, p.FunctionResponse.Response["result"])
+				if _, ok := p.FunctionResponse.Response["error"]; ok && len(ev.Actions.StateDelta) > 0 {
+					t.Errorf("turn %d: state delta of the failed call = %v, want none", i+1, ev.Actions.StateDelta)
+				}
+			}
+		}
+		if diff := cmp.Diff(want, got); diff != "" {
+			t.Errorf("turn %d: counts mismatch (-want +got):\n%s", i+1, diff)
+		}
+	}
+}
+
+func TestParallelToolCalls(t *testing.T) {
+	type Args struct {
+		Name string `json:"name"`
+	}
+	// The calls of wait return once both are running, which they only do
+	// if they run concurrently.
+	var arrived sync.WaitGroup
+	arrived.Add(2)
+	var running atomic.Int32
+	wait, err := functiontool.New(functiontool.Config{
+		Name:        "wait",
+		Description: "waits for the other call",
+	}, func(ctx tool.Context, args Args) (string, error) {
+		running.Add(1)
+		defer running.Add(-1)
+		arrived.Done()
+		done := make(chan struct{})
+		go func() {
+			arrived.Wait()
+			close(done)
+		}()
+		select {
+		case <-done:
+			return args.Name, nil
+		case <-time.After(5 * time.Second):
+			return "", errors.New("the calls do not run concurrently")
+		}
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	count, err := functiontool.New(functiontool.Config{
+		Name:        "count",
+		Description: "counts the running calls",
+		Sequential:  true,
+	}, func(ctx tool.Context, args struct{}) (int32, error) {
+		return running.Load(), nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	model := &testutil.MockModel{Responses: []*genai.Content{
+		genai.NewContentFromParts([]*genai.Part{
+			genai.NewPartFromFunctionCall("wait", map[string]any{"name": "a"}),
+			genai.NewPartFromFunctionCall("count", map[string]any{}),
+			genai.NewPartFromFunctionCall("wait", map[string]any{"name": "b"}),
+		}, "model"),
+		genai.NewContentFromText("done", "model"),
+	}}
+	a, err := llmagent.New(llmagent.Config{
+		Name:              "agent",
+		Model:             model,
+		Tools:             []tool.Tool{wait, count},
+		ParallelToolCalls: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := testutil.CollectTextParts(testutil.NewTestAgentRunner(t, a).Run(t, "session", "wait")); err != nil {
+		t.Fatal(err)
+	}
+	if len(model.Requests) != 2 {
+		t.Fatalf("got %d model requests, want 2", len(model.Requests))
+	}
+	contents := model.Requests[1].Contents
+	var got []map[string]any
+	for _, p := range contents[len(contents)-1].Parts {
+		got = append(got, p.FunctionResponse.Response)
+	}
+	// The responses are in the order of the calls, and the sequential call
+	// runs after the others.
+	want := []map[string]any{{"result": "a"}, {"result": int32(0)}, {"result": "b"}}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("function responses mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestMaxParallelToolCalls(t *testing.T) {
+	var running, peak atomic.Int32
+	slow, err := functiontool.New(functiontool.Config{
+		Name:        "slow",
+		Description: "takes a while",
+	}, func(ctx tool.Context, args struct{}) (string, error) {
+		n := running.Add(1)
+		defer running.Add(-1)
+		for p := peak.Load(); n > p && !peak.CompareAndSwap(p, n); p = peak.Load() {
+		}
+		time.Sleep(20 * time.Millisecond)
+		return "ok", nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var calls []*genai.Part
+	for range 5 {
+		calls = append(calls, genai.NewPartFromFunctionCall("slow", map[string]any{}))
+	}
+	model := &testutil.MockModel{Responses: []*genai.Content{
+		genai.NewContentFromParts(calls, "model"),
+		genai.NewContentFromText("done", "model"),
+	}}
+	a, err := llmagent.New(llmagent.Config{
+		Name:                 "agent",
+		Model:                model,
+		Tools:                []tool.Tool{slow},
+		ParallelToolCalls:    true,
+		MaxParallelToolCalls: 2,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := testutil.CollectTextParts(testutil.NewTestAgentRunner(t, a).Run(t, "session", "go")); err != nil {
+		t.Fatal(err)
+	}
+	if got := peak.Load(); got != 2 {
+		t.Errorf("got %d calls running at once, want 2", got)
+	}
+	contents := model.Requests[1].Contents
+	if got := len(contents[len(contents)-1].Parts); got != 5 {
+		t.Errorf("got %d function responses, want 5", got)
+	}
+}
+
+func TestFunctionTool_Auth(t *testing.T) {
+	type Args struct{}
+	cfg := &auth.AuthConfig{
+		AuthScheme: &auth.AuthScheme{
+			Type:             auth.OAuth2,
+			AuthorizationURL: "https://example.com/authorize",
+			TokenURL:         "https://example.com/token",
+			Scopes:           []string{"calendar"},
+		},
+		RawAuthCredential: &auth.AuthCredential{AuthType: auth.OAuth2, OAuth2: &auth.OAuth2Auth{ClientID: "client"}},
+	}
+	calendar, err := functiontool.New(functiontool.Config{
+		Name:        "calendar",
+		Description: "lists the events of the calendar",
+	}, func(ctx tool.Context, _ Args) (map[string]any, error) {
+		cred := ctx.GetAuthResponse(cfg)
+		if cred == nil {
+			ctx.RequestCredential(cfg)
+			return map[string]any{"status": "waiting for the user to authenticate"}, nil
+		}
+		return map[string]any{"token": cred.OAuth2.AccessToken}, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	model := &testutil.MockModel{Responses: []*genai.Content{
+		genai.NewContentFromFunctionCall("calendar", map[string]any{}, "model"),
+		genai.NewContentFromText("authenticated", "model"),
+		genai.NewContentFromFunctionCall("calendar", map[string]any{}, "model"),
+		genai.NewContentFromText("cached", "model"),
+	}}
+	a, err := llmagent.New(llmagent.Config{
+		Name:                     "agent",
+		Model:                    model,
+		DisallowTransferToParent: true,
+		DisallowTransferToPeers:  true,
+		Tools:                    []tool.Tool{calendar},
+	})
+	if err != nil {
+		t.Fatalf("failed to create LLM Agent: %v", err)
+	}
+	runner := testutil.NewTestAgentRunner(t, a)
+	collect := func(stream iter.Seq2[*session.Event, error]) []*session.Event {
+		t.Helper()
+		var events []*session.Event
+		for ev, err := range stream {
+			if err != nil {
+				t.Fatal(err)
+			}
+			events = append(events, ev)
+		}
+		return events
+	}
+	responses := func(events []*session.Event) []map[string]any {
+		var got []map[string]any
+		for _, ev := range events {
+			for _, p := range ev.LLMResponse.Content.Parts {
+				if p.FunctionResponse != nil {
+					got = append(got, p.FunctionResponse.Response)
+				}
+			}
+		}
+		return got
+	}
+
+	// The tool requests the credential, and the invocation ends with the
+	// auth event.
+	events := collect(runner.Run(t, "session1", "list my events"))
+	if len(events) != 3 {
+		t.Fatalf("got %d events, want the function call, its response and the auth event", len(events))
+	}
+	toolCallID := events[0].LLMResponse.Content.Parts[0].FunctionCall.ID
+	authEvent := events[2]
+	authCall := authEvent.LLMResponse.Content.Parts[0].FunctionCall
+	if authCall.Name != auth.RequestCredentialFunctionName || !slices.Equal(authEvent.LongRunningToolIDs, []string{authCall.ID}) {
+		t.Fatalf("auth event = %+v, want a long-running credential request", authEvent.LLMResponse.Content)
+	}
+	if authCall.Args["function_call_id"] != toolCallID {
+		t.Errorf("credential request for the call %v, want %q", authCall.Args["function_call_id"], toolCallID)
+	}
+
+	// The client sends the credential: the call is run again.
+	authConfig := authCall.Args["auth_config"].(map[string]any)
+	authConfig["exchanged_auth_credential"] = map[string]any{"auth_type": "oauth2", "oauth2": map[string]any{"access_token": "token1"}}
+	authResponse := genai.NewContentFromFunctionResponse(auth.RequestCredentialFunctionName, authConfig, genai.RoleUser)
+	authResponse.Parts[0].FunctionResponse.ID = authCall.ID
+	events = collect(runner.RunContent(t, "session1", authResponse))
+	if diff := cmp.Diff([]map[string]any{{"token": "token1"}}, responses(events)); diff != "" {
+		t.Errorf("responses after the authentication mismatch (-want +got):\n%s", diff)
+	}
+	if got := events[len(events)-1].LLMResponse.Content.Parts[0].Text; got != "authenticated" {
+		t.Errorf("final response = %q, want %q", got, "authenticated")
+	}
+	// The model gets the response of the call run again, without the
+	// credential request.
+	for _, c := range model.Requests[1].Contents {
+		for _, p := range c.Parts {
+			if p.FunctionCall != nil && p.FunctionCall.Name == auth.RequestCredentialFunctionName {
+				t.Errorf("request to the model has the credential request %v", p.FunctionCall)
+			}
+		}
+	}
+	contents := model.Requests[1].Contents
+	if got := contents[len(contents)-1].Parts[0].FunctionResponse; got == nil || got.Response["token"] != "token1" {
+		t.Errorf("last content of the request to the model = %+v, want the response with the token", contents[len(contents)-1])
+	}
+
+	// The credential is cached in the session state.
+	events = collect(runner.Run(t, "session1", "list my events again"))
+	if diff := cmp.Diff([]map[string]any{{"token": "token1"}}, responses(events)); diff != "" {
+		t.Errorf("responses with the cached credential mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestFunctionTool_Confirmation(t *testing.T) {
+	type Args struct {
+		Path string `json:"path"`
+	}
+	var deleted []string
+	deleteFile, err := functiontool.New(functiontool.Config{
+		Name:                "delete_file",
+		Description:         "deletes a file",
+		RequireConfirmation: true,
+		ConfirmationPrompt: func(args map[string]any) string {
+			return fmt.Sprintf("Delete %v?", args["path"])
+		},
+	}, func(ctx tool.Context, args Args) (map[string]any, error) {
+		deleted = append(deleted, args.Path)
+		return map[string]any{"deleted": args.Path}, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	model := &testutil.MockModel{Responses: []*genai.Content{
+		genai.NewContentFromFunctionCall("delete_file", map[string]any{"path": "a.txt"}, "model"),
+		genai.NewContentFromText("deleted", "model"),
+		genai.NewContentFromFunctionCall("delete_file", map[string]any{"path": "b.txt"}, "model"),
+		genai.NewContentFromText("not deleted", "model"),
+	}}
+	a, err := llmagent.New(llmagent.Config{
+		Name:                     "agent",
+		Model:                    model,
+		DisallowTransferToParent: true,
+		DisallowTransferToPeers:  true,
+		Tools:                    []tool.Tool{deleteFile},
+	})
+	if err != nil {
+		t.Fatalf("failed to create LLM Agent: %v", err)
+	}
+	runner := testutil.NewTestAgentRunner(t, a)
+	collect := func(stream iter.Seq2[*session.Event, error]) []*session.Event {
+		t.Helper()
+		var events []*session.Event
+		for ev, err := range stream {
+			if err != nil {
+				t.Fatal(err)
+			}
+			events = append(events, ev)
+		}
+		return events
+	}
+	// request runs the agent with the message, and returns the
+	// confirmation request the invocation ends with.
+	request := func(msg string) *genai.FunctionCall {
+		t.Helper()
+		events := collect(runner.Run(t, "session1", msg))
+		if len(events) != 3 {
+			t.Fatalf("got %d events, want the function call, its response and the confirmation event", len(events))
+		}
+		confirmationEvent := events[2]
+		call := confirmationEvent.LLMResponse.Content.Parts[0].FunctionCall
+		if call.Name != toolconfirmation.FunctionCallName || !slices.Equal(confirmationEvent.LongRunningToolIDs, []string{call.ID}) {
+			t.Fatalf("confirmation event = %+v, want a long-running confirmation request", confirmationEvent.LLMResponse.Content)
+		}
+		return call
+	}
+	// answer sends the answer to the confirmation request, and returns the
+	// responses of the call run again.
+	answer := func(call *genai.FunctionCall, confirmed bool) []map[string]any {
+		t.Helper()
+		resp := genai.NewContentFromFunctionResponse(toolconfirmation.FunctionCallName, map[string]any{"confirmed": confirmed}, genai.RoleUser)
+		resp.Parts[0].FunctionResponse.ID = call.ID
+		var got []map[string]any
+		for _, ev := range collect(runner.RunContent(t, "session1", resp)) {
+			for _, p := range ev.LLMResponse.Content.Parts {
+				if p.FunctionResponse != nil {
+					got = append(got, p.FunctionResponse.Response)
+				}
+			}
+		}
+		return got
+	}
+
+	// The call waits for the confirmation, which carries the call.
+	call := request("delete a.txt")
+	original := call.Args["original_function_call"].(map[string]any)
+	if original["name"] != "delete_file" || original["args"].(map[string]any)["path"] != "a.txt" {
+		t.Errorf("original function call = %v, want the call of delete_file", original)
+	}
+	if got := call.Args["tool_confirmation"].(map[string]any)["hint"]; got != "Delete a.txt?" {
+		t.Errorf("hint = %v, want %q", got, "Delete a.txt?")
+	}
+	if len(deleted) != 0 {
+		t.Fatalf("the handler ran before the confirmation: %v", deleted)
+	}
+
+	// The confirmed call runs.
+	if diff := cmp.Diff([]map[string]any{{"deleted": "a.txt"}}, answer(call, true)); diff != "" {
+		t.Errorf("responses after the confirmation mismatch (-want +got):\n%s", diff)
+	}
+	for _, c := range model.Requests[1].Contents {
+		for _, p := range c.Parts {
+			if p.FunctionCall != nil && p.FunctionCall.Name == toolconfirmation.FunctionCallName {
+				t.Errorf("request to the model has the confirmation request %v", p.FunctionCall)
+			}
+		}
+	}
+
+	// The declined call fails.
+	got := answer(request("delete b.txt"), false)
+	if len(got) != 1 || !strings.Contains(fmt.Sprint(got[0]["error"]), toolconfirmation.ErrDeclined.Error()) {
+		t.Errorf("responses after the denial = %v, want the declined error", got)
+	}
+	if diff := cmp.Diff([]string{"a.txt"}, deleted); diff != "" {
+		t.Errorf("deleted files mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestFunctionTool_Streaming(t *testing.T) {
+	type Args struct {
+		Lines int `json:"lines"`
+	}
+	type Result struct {
+		Line string `json:"line"`
+	}
+	canceled := make(chan error, 1)
+	tail, err := functiontool.NewStreaming(functiontool.Config{
+		Name:        "tail",
+		Description: "tails the logs",
+	}, func(ctx context.Context, args Args, emit func(Result) error) (Result, error) {
+		for i := range args.Lines {
+			if err := emit(Result{Line: fmt.Sprintf("line %d", i+1)}); err != nil {
+				<-ctx.Done()
+				canceled <- context.Cause(ctx)
+				return Result{}, err
+			}
+		}
+		return Result{Line: "eof"}, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	newRunner := func() *testutil.TestAgentRunner {
+		a, err := llmagent.New(llmagent.Config{
+			Name: "agent",
+			Model: &testutil.MockModel{Responses: []*genai.Content{
+				genai.NewContentFromFunctionCall("tail", map[string]any{"lines": 2}, "model"),
+				genai.NewContentFromText("done", "model"),
+			}},
+			DisallowTransferToParent: true,
+			DisallowTransferToPeers:  true,
+			Tools:                    []tool.Tool{tail},
+		})
+		if err != nil {
+			t.Fatalf("failed to create LLM Agent: %v", err)
+		}
+		return testutil.NewTestAgentRunner(t, a)
+	}
+
+	type response struct {
+		Partial      bool
+		WillContinue bool
+		Line         any
+	}
+	var got []response
+	for ev, err := range newRunner().Run(t, "session1", "tail the logs") {
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, p := range ev.LLMResponse.Content.Parts {
+			if fr := p.FunctionResponse; fr != nil {
+				got = append(got, response{ev.LLMResponse.Partial, fr.WillContinue != nil && *fr.WillContinue, fr.Response["line"]})
+			}
+		}
+	}
+	want := []response{{true, true, "line 1"}, {true, true, "line 2"}, {false, false, "eof"}}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("function responses mismatch (-want +got):\n%s", diff)
+	}
+
+	// The handler's context is canceled once the consumer is gone.
+	for ev := range newRunner().Run(t, "session2", "tail the logs") {
+		if ev.LLMResponse.Partial {
+			break
+		}
+	}
+	select {
+	case cause := <-canceled:
+		if cause == nil {
+			t.Error("handler context canceled without cause")
+		}
+	case <-time.After(5 * time.Second):
+		t.Error("handler context not canceled after the consumer stopped")
+	}
+}
+
 func TestAgentTransfer(t *testing.T) {
 	// Helpers to create genai.Content conveniently.
 	transferCall := func(agentName string) *genai.Content {
@@ -972,3 +1600,664 @@ type roundTripperFunc func(*http.Request) (*http.Response, error)
 func (fn roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
 	return fn(req)
 }
+
+func TestFunctionTool_Errors(t *testing.T) {
+	type Args struct {
+		City string `json:"city"`
+	}
+	ctx, cancel := context.WithCancel(t.Context())
+	defer cancel()
+	lookup, err := functiontool.New(functiontool.Config{
+		Name:        "lookup",
+		Description: "looks up a city",
+	}, func(tctx tool.Context, args Args) (map[string]any, error) {
+		switch args.City {
+		case "Atlantis":
+			return nil, &tool.ToolError{Code: "not_found", Message: "no such city"}
+		case "cancel":
+			cancel()
+			return nil, tctx.Err()
+		}
+		return nil, errors.New("service unavailable")
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("recoverable", func(t *testing.T) {
+		model := &testutil.MockModel{Responses: []*genai.Content{
+			genai.NewContentFromParts([]*genai.Part{
+				genai.NewPartFromFunctionCall("lookup", map[string]any{"city": "Atlantis"}),
+				genai.NewPartFromFunctionCall("lookup", map[string]any{"city": "Paris"}),
+			}, "model"),
+			genai.NewContentFromText("sorry", "model"),
+		}}
+		a, err := llmagent.New(llmagent.Config{Name: "agent", Model: model, Tools: []tool.Tool{lookup}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		texts, err := testutil.CollectTextParts(testutil.NewTestAgentRunner(t, a).Run(t, "session", "look up Atlantis and Paris"))
+		if err != nil {
+			t.Fatalf("run error = %v, want the errors reported to the model", err)
+		}
+		if diff := cmp.Diff([]string{"sorry"}, texts); diff != "" {
+			t.Errorf("texts mismatch (-want +got):\n%s", diff)
+		}
+		if len(model.Requests) != 2 {
+			t.Fatalf("got %d model requests, want 2", len(model.Requests))
+		}
+		contents := model.Requests[1].Contents
+		var got []map[string]any
+		for _, p := range contents[len(contents)-1].Parts {
+			got = append(got, p.FunctionResponse.Response)
+		}
+		want := []map[string]any{
+			{"error": map[string]any{"code": "not_found", "message": "no such city"}},
+			{"error": "service unavailable"},
+		}
+		if diff := cmp.Diff(want, got); diff != "" {
+			t.Errorf("function responses mismatch (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("fatal", func(t *testing.T) {
+		model := &testutil.MockModel{Responses: []*genai.Content{
+			genai.NewContentFromFunctionCall("lookup", map[string]any{"city": "cancel"}, "model"),
+			genai.NewContentFromText("unreachable", "model"),
+		}}
+		a, err := llmagent.New(llmagent.Config{Name: "agent", Model: model, Tools: []tool.Tool{lookup}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		sessionService := session.InMemoryService()
+		r, err := runner.New(runner.Config{AppName: "app", Agent: a, SessionService: sessionService})
+		if err != nil {
+			t.Fatal(err)
+		}
+		created, err := sessionService.Create(ctx, &session.CreateRequest{AppName: "app", UserID: "user"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		var runErr error
+		for _, err := range r.Run(ctx, "user", created.Session.ID(), genai.NewContentFromText("cancel", genai.RoleUser), agent.RunConfig{}) {
+			if err != nil {
+				runErr = err
+				break
+			}
+		}
+		if !errors.Is(runErr, context.Canceled) {
+			t.Errorf("run error = %v, want context.Canceled", runErr)
+		}
+		if len(model.Requests) != 1 {
+			t.Errorf("got %d model requests, want 1: the canceled invocation must end", len(model.Requests))
+		}
+	})
+}
+
+func TestFunctionTool_InvalidArguments(t *testing.T) {
+	type Args struct {
+		City string `json:"city"`
+		Days int    `json:"days"`
+	}
+	forecast, err := functiontool.New(functiontool.Config{
+		Name:        "forecast",
+		Description: "returns the forecast of a city",
+	}, func(_ tool.Context, args Args) (string, error) {
+		return "sunny", nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	missingCity := genai.NewContentFromFunctionCall("forecast", map[string]any{"days": 2}, "model")
+	validCall := genai.NewContentFromFunctionCall("forecast", map[string]any{"city": "Paris", "days": 2}, "model")
+
+	t.Run("corrected", func(t *testing.T) {
+		model := &testutil.MockModel{Responses: []*genai.Content{
+			missingCity,
+			validCall,
+			missingCity,
+			genai.NewContentFromText("sunny", "model"),
+		}}
+		a, err := llmagent.New(llmagent.Config{Name: "agent", Model: model, Tools: []tool.Tool{forecast}, InvalidArgumentsRetries: 1})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := testutil.CollectEvents(testutil.NewTestAgentRunner(t, a).Run(t, "session", "forecast")); err != nil {
+			t.Fatalf("run error = %v, want the invalid arguments reported to the model", err)
+		}
+		if len(model.Requests) != 4 {
+			t.Fatalf("got %d model requests, want 4", len(model.Requests))
+		}
+		contents := model.Requests[1].Contents
+		got := contents[len(contents)-1].Parts[0].FunctionResponse.Response
+		want := map[string]any{"error": map[string]any{
+			"code":    tool.CodeInvalidArguments,
+			"message": `invalid arguments for tool "forecast": field "city": the field is required; fix the arguments and call the tool again`,
+			"details": map[string]any{"fields": []any{map[string]any{"field": "city", "message": "the field is required"}}},
+		}}
+		if diff := cmp.Diff(want, got); diff != "" {
+			t.Errorf("function response mismatch (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("exhausted", func(t *testing.T) {
+		model := &testutil.MockModel{Responses: []*genai.Content{
+			missingCity,
+			genai.NewContentFromFunctionCall("forecast", map[string]any{"city": "Paris", "days": "two"}, "model"),
+			genai.NewContentFromText("unreachable", "model"),
+		}}
+		a, err := llmagent.New(llmagent.Config{Name: "agent", Model: model, Tools: []tool.Tool{forecast}, InvalidArgumentsRetries: 1})
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, err = testutil.CollectEvents(testutil.NewTestAgentRunner(t, a).Run(t, "session", "forecast"))
+		var verr *functiontool.ValidationError
+		if !errors.As(err, &verr) {
+			t.Fatalf("run error = %v, want a ValidationError", err)
+		}
+		if diff := cmp.Diff([]functiontool.FieldError{{Field: "days", Message: `type: two has type "string", want "integer"`}}, verr.Fields); diff != "" {
+			t.Errorf("ValidationError.Fields mismatch (-want +got):\n%s", diff)
+		}
+		if len(model.Requests) != 2 {
+			t.Errorf("got %d model requests, want 2: the invocation must end once the retries are exhausted", len(model.Requests))
+		}
+	})
+}
+
+func TestToolConflicts(t *testing.T) {
+	newSearch := func(source string) tool.Tool {
+		t.Helper()
+		search, err := functiontool.New(functiontool.Config{
+			Name:        "search",
+			Description: "searches the " + source,
+		}, func(tool.Context, struct{}) (string, error) {
+			return source, nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return search
+	}
+
+	for _, tc := range []struct {
+		name         string
+		policy       tool.ConflictPolicy
+		call         string
+		wantDeclared []string
+		wantResult   string
+		wantErr      string
+	}{
+		{
+			name:    "error",
+			policy:  tool.ConflictError,
+			wantErr: `duplicate tool: "search"`,
+		},
+		{
+			name:         "rename",
+			policy:       tool.ConflictRename,
+			call:         "docs_search",
+			wantDeclared: []string{"search", "docs_search", "search_2"},
+			wantResult:   "docs",
+		},
+		{
+			name:         "prefer first",
+			policy:       tool.ConflictPreferFirst,
+			call:         "search",
+			wantDeclared: []string{"search"},
+			wantResult:   "web",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			model := &testutil.MockModel{Responses: []*genai.Content{
+				genai.NewContentFromFunctionCall(tc.call, map[string]any{}, "model"),
+				genai.NewContentFromText("done", "model"),
+			}}
+			a, err := llmagent.New(llmagent.Config{
+				Name:  "agent",
+				Model: model,
+				Tools: []tool.Tool{newSearch("web")},
+				Toolsets: []tool.Toolset{
+					toolset.New("docs", newSearch("docs")),
+					// The name of the toolset is taken by the previous tool.
+					toolset.New("docs", newSearch("wiki")),
+				},
+				ToolConflicts: tc.policy,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			events, err := testutil.CollectEvents(testutil.NewTestAgentRunner(t, a).Run(t, "session", "search"))
+			if tc.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+					t.Fatalf("run error = %v, want %q", err, tc.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("run error = %v", err)
+			}
+			var declared []string
+			for _, decl := range model.Requests[0].Config.Tools[0].FunctionDeclarations {
+				declared = append(declared, decl.Name)
+			}
+			if diff := cmp.Diff(tc.wantDeclared, declared); diff != "" {
+				t.Errorf("declarations mismatch (-want +got):\n%s", diff)
+			}
+			var results []any
+			for _, ev := range events {
+				for _, fr := range ev.Content.Parts {
+					if fr.FunctionResponse != nil {
+						results = append(results, fr.FunctionResponse.Response["result"])
+					}
+				}
+			}
+			if diff := cmp.Diff([]any{tc.wantResult}, results); diff != "" {
+				t.Errorf("function results mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestFunctionTool_Panic(t *testing.T) {
+	explode, err := functiontool.New(functiontool.Config{
+		Name:        "explode",
+		Description: "panics",
+	}, func(tool.Context, struct{}) (string, error) {
+		panic("boom")
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	echo, err := functiontool.New(functiontool.Config{
+		Name:        "echo",
+		Description: "returns ok",
+	}, func(tool.Context, struct{}) (string, error) {
+		return "ok", nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	model := &testutil.MockModel{Responses: []*genai.Content{
+		genai.NewContentFromParts([]*genai.Part{
+			{FunctionCall: &genai.FunctionCall{ID: "c1", Name: "explode"}},
+			{FunctionCall: &genai.FunctionCall{ID: "c2", Name: "echo"}},
+		}, "model"),
+		genai.NewContentFromText("sorry", "model"),
+	}}
+	a, err := llmagent.New(llmagent.Config{
+		Name:  "agent",
+		Model: model,
+		Tools: []tool.Tool{explode, echo},
+		// The panics of the callbacks are recovered too.
+		AfterToolCallbacks: []llmagent.AfterToolCallback{
+			func(ctx tool.Context, t tool.Tool, args, result map[string]any, err error) (map[string]any, error) {
+				if t.Name() == "echo" {
+					panic(errors.New("callback failed"))
+				}
+				return nil, nil
+			},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var panics map[string]any
+	for ev, err := range testutil.NewTestAgentRunner(t, a).Run(t, "session", "explode") {
+		if err != nil {
+			t.Fatalf("run error = %v, want the panics reported to the model", err)
+		}
+		if p, ok := ev.CustomMetadata[tool.PanicMetadataKey].(map[string]any); ok {
+			panics = p
+		}
+	}
+
+	if len(model.Requests) != 2 {
+		t.Fatalf("got %d model requests, want 2", len(model.Requests))
+	}
+	contents := model.Requests[1].Contents
+	var got []map[string]any
+	for _, p := range contents[len(contents)-1].Parts {
+		got = append(got, p.FunctionResponse.Response)
+	}
+	// The model is not told the stacks.
+	want := []map[string]any{
+		{"error": map[string]any{"code": "panic", "message": `panic in tool "explode": boom`}},
+		{"error": map[string]any{"code": "panic", "message": `panic in tool "echo": callback failed`}},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("function responses mismatch (-want +got):\n%s", diff)
+	}
+	// The event records them.
+	for id, value := range map[string]string{"c1": "boom", "c2": "callback failed"} {
+		p, _ := panics[id].(map[string]any)
+		stack, _ := p["stack"].(string)
+		if p["value"] != value || !strings.Contains(stack, "goroutine") {
+			t.Errorf("panic metadata of %s = %v, want the value %q and the stack", id, p, value)
+		}
+	}
+}
+
+func TestToolInterceptors(t *testing.T) {
+	type Args struct {
+		User     string `json:"user"`
+		Password string `json:"password"`
+	}
+	login, err := functiontool.New(functiontool.Config{
+		Name:        "login",
+		Description: "logs in",
+	}, func(_ tool.Context, args Args) (map[string]any, error) {
+		return map[string]any{"user": args.User, "password": args.Password}, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var calls []string
+	// The runner interceptor records the calls, the agent one redacts the
+	// password before the tool runs.
+	record := func(next tool.Handler) tool.Handler {
+		return func(ctx tool.Context, tl tool.Tool, args map[string]any) (map[string]any, error) {
+			result, err := next(ctx, tl, args)
+			calls = append(calls, fmt.Sprintf("%s(%v) = %v", tl.Name(), args["password"], result["password"]))
+			return result, err
+		}
+	}
+	redact := func(next tool.Handler) tool.Handler {
+		return func(ctx tool.Context, tl tool.Tool, args map[string]any) (map[string]any, error) {
+			args = maps.Clone(args)
+			args["password"] = "***"
+			return next(ctx, tl, args)
+		}
+	}
+	model := &testutil.MockModel{Responses: []*genai.Content{
+		genai.NewContentFromFunctionCall("login", map[string]any{"user": "ann", "password": "secret"}, "model"),
+		genai.NewContentFromText("done", "model"),
+	}}
+	a, err := llmagent.New(llmagent.Config{
+		Name:             "agent",
+		Model:            model,
+		Tools:            []tool.Tool{login},
+		ToolInterceptors: []tool.Interceptor{redact},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sessionService := session.InMemoryService()
+	r, err := runner.New(runner.Config{
+		AppName:          "app",
+		Agent:            a,
+		SessionService:   sessionService,
+		ToolInterceptors: []tool.Interceptor{record},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	created, err := sessionService.Create(t.Context(), &session.CreateRequest{AppName: "app", UserID: "user"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, err := range r.Run(t.Context(), "user", created.Session.ID(), genai.NewContentFromText("log in", genai.RoleUser), agent.RunConfig{}) {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if diff := cmp.Diff([]string{"login(secret) = ***"}, calls); diff != "" {
+		t.Errorf("calls mismatch (-want +got):\n%s", diff)
+	}
+	contents := model.Requests[1].Contents
+	got := contents[len(contents)-1].Parts[0].FunctionResponse.Response
+	if diff := cmp.Diff(map[string]any{"user": "ann", "password": "***"}, got); diff != "" {
+		t.Errorf("function response mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestOutputRetries(t *testing.T) {
+	schema := &genai.Schema{
+		Type:       genai.TypeObject,
+		Properties: map[string]*genai.Schema{"message": {Type: genai.TypeString}},
+		Required:   []string{"message"},
+	}
+	for _, tc := range []struct {
+		name       string
+		retries    int
+		responses  []*genai.Content
+		wantTexts  []string
+		wantOutput any
+	}{
+		{
+			name:    "repaired answer",
+			retries: 2,
+			responses: []*genai.Content{
+				genai.NewContentFromText(`{"msg": "hi"}`, genai.RoleModel),
+				genai.NewContentFromText(`{"message": "hi"}`, genai.RoleModel),
+			},
+			wantTexts:  []string{`{"msg": "hi"}`, `{"message": "hi"}`},
+			wantOutput: map[string]any{"message": "hi"},
+		},
+		{
+			name:    "retries exhausted",
+			retries: 1,
+			responses: []*genai.Content{
+				genai.NewContentFromText("hi", genai.RoleModel),
+				genai.NewContentFromText("hi again", genai.RoleModel),
+			},
+			wantTexts:  []string{"hi", "hi again"},
+			wantOutput: "hi again",
+		},
+		{
+			name:    "no retries",
+			retries: 0,
+			responses: []*genai.Content{
+				genai.NewContentFromText("hi", genai.RoleModel),
+			},
+			wantTexts:  []string{"hi"},
+			wantOutput: "hi",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			testLLM := &testutil.MockModel{Responses: tc.responses}
+			a, err := llmagent.New(llmagent.Config{
+				Name:          "json_agent",
+				Model:         testLLM,
+				OutputSchema:  schema,
+				OutputRetries: tc.retries,
+				OutputKey:     "result",
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var texts []string
+			var output any
+			for ev, err := range testutil.NewTestAgentRunner(t, a).Run(t, "session", "say hi") {
+				if err != nil {
+					t.Fatal(err)
+				}
+				if ev.Author != a.Name() {
+					continue
+				}
+				texts = append(texts, ev.Content.Parts[0].Text)
+				if v, ok := ev.Actions.StateDelta["result"]; ok {
+					output = v
+				}
+			}
+			if diff := cmp.Diff(tc.wantTexts, texts); diff != "" {
+				t.Errorf("answers mismatch (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.wantOutput, output); diff != "" {
+				t.Errorf("output mismatch (-want +got):\n%s", diff)
+			}
+			if len(testLLM.Requests) < 2 {
+				return
+			}
+			// The model is asked again with the validation error.
+			contents := testLLM.Requests[1].Contents
+			last := contents[len(contents)-1]
+			if last.Role != genai.RoleUser || !strings.Contains(last.Parts[0].Text, "does not conform to the output schema") {
+				t.Errorf("last content of the retry = %v, want the validation error", last.Parts[0].Text)
+			}
+		})
+	}
+}
+
+func TestPlanner(t *testing.T) {
+	answer := planner.PlanningTag + " 1. answer " + planner.FinalAnswerTag + " 42"
+	testLLM := &testutil.MockModel{Responses: []*genai.Content{
+		genai.NewContentFromText(answer, genai.RoleModel),
+		genai.NewContentFromText(planner.FinalAnswerTag+" 43", genai.RoleModel),
+	}}
+	a, err := llmagent.New(llmagent.Config{
+		Name:    "planning_agent",
+		Model:   testLLM,
+		Planner: planner.NewPlanReAct(),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	runner := testutil.NewTestAgentRunner(t, a)
+
+	var parts []*genai.Part
+	for ev, err := range runner.Run(t, "session", "question") {
+		if err != nil {
+			t.Fatal(err)
+		}
+		parts = append(parts, ev.Content.Parts...)
+	}
+	want := []*genai.Part{
+		{Text: planner.PlanningTag + " 1. answer " + planner.FinalAnswerTag, Thought: true},
+		{Text: " 42"},
+	}
+	if diff := cmp.Diff(want, parts); diff != "" {
+		t.Errorf("answer parts mismatch (-want +got):\n%s", diff)
+	}
+	if _, err := testutil.CollectTextParts(runner.Run(t, "session", "next question")); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(testLLM.Requests) != 2 {
+		t.Fatalf("got %d requests, want 2", len(testLLM.Requests))
+	}
+	req := testLLM.Requests[1]
+	if !strings.Contains(req.Config.SystemInstruction.Parts[0].Text, planner.FinalAnswerTag) {
+		t.Errorf("SystemInstruction = %v, want the planning instruction", req.Config.SystemInstruction.Parts[0].Text)
+	}
+	// The planning marked as thought is sent back to the model as text.
+	found := false
+	for _, content := range req.Contents {
+		for _, part := range content.Parts {
+			if part.Thought {
+				t.Errorf("request part %q is a thought, want it unmarked", part.Text)
+			}
+			found = found || part.Text == want[0].Text
+		}
+	}
+	if !found {
+		t.Errorf("request contents %v miss the planning", req.Contents)
+	}
+}
+
+func TestThinkingConfig(t *testing.T) {
+	budget := int32(1024)
+	thinking := &genai.ThinkingConfig{ThinkingBudget: &budget, IncludeThoughts: true}
+	temperature := float32(0.5)
+	testLLM := &testutil.MockModel{Responses: []*genai.Content{
+		genai.NewContentFromParts([]*genai.Part{{Text: "Easy.", Thought: true}, {Text: "42"}}, genai.RoleModel),
+	}}
+	a, err := llmagent.New(llmagent.Config{
+		Name:                  "thinking_agent",
+		Model:                 testLLM,
+		GenerateContentConfig: &genai.GenerateContentConfig{Temperature: &temperature},
+		ThinkingConfig:        thinking,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	events, err := testutil.CollectEvents(testutil.NewTestAgentRunner(t, a).Run(t, "session", "question"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 1 || events[0].Thoughts() != "Easy." || events[0].Text() != "42" {
+		t.Errorf("events = %v, want one answer with its thoughts", events)
+	}
+	cfg := testLLM.Requests[0].Config
+	if diff := cmp.Diff(thinking, cfg.ThinkingConfig); diff != "" {
+		t.Errorf("ThinkingConfig mismatch (-want +got):\n%s", diff)
+	}
+	if cfg.Temperature == nil || *cfg.Temperature != temperature {
+		t.Errorf("Temperature = %v, want %v", cfg.Temperature, temperature)
+	}
+
+	_, err = llmagent.New(llmagent.Config{
+		Name:                  "thinking_agent",
+		Model:                 testLLM,
+		GenerateContentConfig: &genai.GenerateContentConfig{ThinkingConfig: thinking},
+		ThinkingConfig:        thinking,
+	})
+	if err == nil {
+		t.Error("New() with two thinking configs succeeded, want an error")
+	}
+}
+
+// partialsModel streams its chunks as partial responses, without a final
+// aggregated response.
+type partialsModel struct {
+	chunks   []*genai.Part
+	requests []*model.LLMRequest
+}
+
+func (m *partialsModel) Name() string { return "partials" }
+
+func (m *partialsModel) GenerateContent(ctx context.Context, req *model.LLMRequest, stream bool) iter.Seq2[*model.LLMResponse, error] {
+	m.requests = append(m.requests, req)
+	return func(yield func(*model.LLMResponse, error) bool) {
+		for _, chunk := range m.chunks {
+			if !yield(&model.LLMResponse{Content: &genai.Content{Role: genai.RoleModel, Parts: []*genai.Part{chunk}}, Partial: true}, nil) {
+				return
+			}
+		}
+	}
+}
+
+func TestStreamingPartials(t *testing.T) {
+	m := &partialsModel{chunks: []*genai.Part{{Text: "Let me ", Thought: true}, {Text: "think."}, {Text: "Hel"}, {Text: "lo"}}}
+	a, err := llmagent.New(llmagent.Config{Name: "streamer", Model: m})
+	if err != nil {
+		t.Fatal(err)
+	}
+	runner := testutil.NewTestAgentRunner(t, a)
+	cfg := agent.RunConfig{StreamingMode: agent.StreamingModeSSE}
+
+	events, err := testutil.CollectEvents(runner.RunContentWithConfig(t, "session", genai.NewContentFromText("hi", genai.RoleUser), cfg))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var partials []string
+	for _, ev := range events[:len(events)-1] {
+		if !ev.Partial {
+			t.Errorf("event %v is not partial, want the chunks of the stream", ev.Content)
+		}
+		partials = append(partials, ev.Content.Parts[0].Text)
+	}
+	if diff := cmp.Diff([]string{"Let me ", "think.", "Hel", "lo"}, partials); diff != "" {
+		t.Errorf("partial events mismatch (-want +got):\n%s", diff)
+	}
+	final := events[len(events)-1]
+	wantFinal := &genai.Content{Role: genai.RoleModel, Parts: []*genai.Part{{Text: "Let me ", Thought: true}, {Text: "think.Hello"}}}
+	if diff := cmp.Diff(wantFinal, final.Content); diff != "" || final.Partial || !final.IsFinalResponse() {
+		t.Errorf("final event = %v (partial %v), want the aggregated content (-want +got):\n%s", final.Content, final.Partial, diff)
+	}
+
+	// Only the final event is in the history of the next request.
+	if _, err := testutil.CollectEvents(runner.RunContentWithConfig(t, "session", genai.NewContentFromText("again", genai.RoleUser), cfg)); err != nil {
+		t.Fatal(err)
+	}
+	var history []string
+	for _, content := range m.requests[1].Contents {
+		for _, part := range content.Parts {
+			history = append(history, part.Text)
+		}
+	}
+	if diff := cmp.Diff([]string{"hi", "Let me ", "think.Hello", "again"}, history); diff != "" {
+		t.Errorf("history mismatch (-want +got):\n%s", diff)
+	}
+}
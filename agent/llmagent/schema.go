@@ -0,0 +1,41 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package llmagent
+
+import (
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/internal/typeutil"
+)
+
+// SchemaFor returns the schema of the JSON encoding of T, for use as
+// Config.OutputSchema or Config.InputSchema.
+//
+// Field descriptions are taken from the `jsonschema` struct tags, as for the
+// arguments of function tools, which helps the model extract structured
+// output:
+//
+//	type Invoice struct {
+//		Number string  `json:"number" jsonschema:"the invoice number, e.g. INV-0042"`
+//		Total  float64 `json:"total" jsonschema:"the total amount including taxes"`
+//	}
+//
+//	schema, err := llmagent.SchemaFor[Invoice]()
+//
+// JSON schema keywords that Gemini does not accept in a response schema are
+// dropped.
+func SchemaFor[T any]() (*genai.Schema, error) {
+	return typeutil.GenaiSchemaFor[T]()
+}
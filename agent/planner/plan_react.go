@@ -0,0 +1,131 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package planner
+
+import (
+	"strings"
+
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/agent"
+	"google.golang.org/adk/model"
+)
+
+// The tags of the sections of the responses of a PlanReAct planner.
+const (
+	PlanningTag    = "/*PLANNING*/"
+	ReplanningTag  = "/*REPLANNING*/"
+	ReasoningTag   = "/*REASONING*/"
+	ActionTag      = "/*ACTION*/"
+	FinalAnswerTag = "/*FINAL_ANSWER*/"
+)
+
+// NewPlanReAct returns a planner asking the model to plan, then to call the
+// tools with reasoning in between, and to give its final answer, each under
+// its tag. The text before the final answer tag, and the parts starting with
+// the planning, replanning, reasoning and action tags, are marked as
+// thoughts. The function calls following the first one are dropped unless
+// they are contiguous with it, so that the model acts one step at a time.
+func NewPlanReAct() Planner {
+	return planReAct{}
+}
+
+type planReAct struct{}
+
+func (planReAct) BuildPlanningInstruction(ctx agent.ReadonlyContext, req *model.LLMRequest) (string, error) {
+	return planReActInstruction, nil
+}
+
+func (planReAct) ProcessPlanningResponse(ctx agent.ReadonlyContext, parts []*genai.Part) ([]*genai.Part, error) {
+	if len(parts) == 0 {
+		return nil, nil
+	}
+	var preserved []*genai.Part
+	for i, part := range parts {
+		if part.FunctionCall == nil {
+			preserved = appendNonFunctionCallPart(preserved, part)
+			continue
+		}
+		// Stop at the first group of function calls, ignoring the function
+		// calls without name.
+		if part.FunctionCall.Name == "" {
+			continue
+		}
+		preserved = append(preserved, part)
+		for _, next := range parts[i+1:] {
+			if next.FunctionCall == nil {
+				break
+			}
+			preserved = append(preserved, next)
+		}
+		break
+	}
+	return preserved, nil
+}
+
+// appendNonFunctionCallPart appends the part, split into the reasoning and
+// the final answer if it has the final answer tag.
+func appendNonFunctionCallPart(preserved []*genai.Part, part *genai.Part) []*genai.Part {
+	if i := strings.LastIndex(part.Text, FinalAnswerTag); i >= 0 {
+		reasoning, answer := part.Text[:i+len(FinalAnswerTag)], part.Text[i+len(FinalAnswerTag):]
+		if reasoning != "" {
+			preserved = append(preserved, &genai.Part{Text: reasoning, Thought: true})
+		}
+		if answer != "" {
+			preserved = append(preserved, &genai.Part{Text: answer})
+		}
+		return preserved
+	}
+	for _, tag := range []string{PlanningTag, ReasoningTag, ActionTag, ReplanningTag} {
+		if strings.HasPrefix(part.Text, tag) {
+			thought := *part
+			thought.Thought = true
+			return append(preserved, &thought)
+		}
+	}
+	return append(preserved, part)
+}
+
+// Source: adk-python src/google/adk/planners/plan_re_act_planner.py.
+const planReActInstruction = `When answering the question, try to leverage the available tools to gather the information instead of your memorized knowledge.
+
+Follow this process when answering the question: (1) first come up with a plan in natural language text format; (2) Then use tools to execute the plan and provide reasoning between tool calls to make a summary of current state and next step. Tool calls and reasoning should be interleaved with each other. (3) In the end, return one final answer.
+
+Follow this format when answering the question: (1) The planning part should be under ` + PlanningTag + `. (2) The tool calls should be under ` + ActionTag + `, and the reasoning parts should be under ` + ReasoningTag + `. (3) The final answer part should be under ` + FinalAnswerTag + `.
+
+
+Below are the requirements for the planning:
+The plan is made to answer the user query if following the plan. The plan is coherent and covers all aspects of information from user query, and only involves the tools that are accessible by the agent. The plan contains the decomposed steps as a numbered list where each step should use one or multiple available tools. By reading the plan, you can intuitively know which tools to trigger or what actions to take.
+If the initial plan cannot be successfully executed, you should learn from previous execution results and revise your plan. The revised plan should be under ` + ReplanningTag + `. Then use tools to follow the new plan.
+
+
+Below are the requirements for the reasoning:
+The reasoning makes a summary of the current trajectory based on the user query and tool outputs. Based on the tool outputs and plan, the reasoning also comes up with instructions to the next steps, making the trajectory closer to the final answer.
+
+
+Below are the requirements for the final answer:
+The final answer should be precise and follow query formatting requirements. Some queries may not be answerable with the available tools and information. In those cases, inform the user why you cannot process their query and ask for more information.
+
+
+Below are the requirements for the tool calls:
+The available tools are described in the context and can be directly used.
+- You cannot use any parameters or fields that are not explicitly defined in the declarations of the tools.
+- The tool calls should be directly relevant to the user query and reasoning steps.
+
+
+VERY IMPORTANT instruction that you MUST follow in addition to the above instructions:
+
+You should ask for clarification if you need more information to answer the question.
+You should prefer using the information available in the context instead of repeated tool use.`
@@ -0,0 +1,69 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package planner provides planners: they make the model of an agent plan
+// and reason before acting, and separate the planning and the reasoning from
+// the final answer. They are configured with llmagent.Config.Planner.
+//
+// The package provides two planners, mirroring adk-python
+// src/google/adk/planners: [NewBuiltIn] uses the built-in thinking of the
+// models supporting it, such as Gemini 2.5, and [NewPlanReAct] asks the
+// models without thinking to plan, act and reason under tags, marking the
+// planning and the reasoning as thoughts.
+package planner
+
+import (
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/agent"
+	"google.golang.org/adk/model"
+)
+
+// Planner plans the steps of an agent.
+type Planner interface {
+	// BuildPlanningInstruction returns the instruction appended to the
+	// request of the model, or "" for none. It may also change the request,
+	// e.g. its thinking config.
+	BuildPlanningInstruction(ctx agent.ReadonlyContext, req *model.LLMRequest) (string, error)
+	// ProcessPlanningResponse returns the parts replacing the parts of a
+	// response of the model, or nil to keep them, e.g. with the planning
+	// marked as thoughts.
+	ProcessPlanningResponse(ctx agent.ReadonlyContext, parts []*genai.Part) ([]*genai.Part, error)
+}
+
+// NewBuiltIn returns a planner using the built-in thinking of the model: it
+// sets the thinking config of the requests, and leaves the responses, whose
+// thoughts are already marked by the model, as is.
+func NewBuiltIn(thinkingConfig *genai.ThinkingConfig) Planner {
+	return &builtIn{thinkingConfig: thinkingConfig}
+}
+
+type builtIn struct {
+	thinkingConfig *genai.ThinkingConfig
+}
+
+func (p *builtIn) BuildPlanningInstruction(ctx agent.ReadonlyContext, req *model.LLMRequest) (string, error) {
+	if p.thinkingConfig == nil {
+		return "", nil
+	}
+	if req.Config == nil {
+		req.Config = &genai.GenerateContentConfig{}
+	}
+	req.Config.ThinkingConfig = p.thinkingConfig
+	return "", nil
+}
+
+func (p *builtIn) ProcessPlanningResponse(ctx agent.ReadonlyContext, parts []*genai.Part) ([]*genai.Part, error) {
+	return nil, nil
+}
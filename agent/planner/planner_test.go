@@ -0,0 +1,105 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package planner_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/agent/planner"
+	"google.golang.org/adk/model"
+)
+
+func TestBuiltIn(t *testing.T) {
+	thinking := &genai.ThinkingConfig{IncludeThoughts: true}
+	p := planner.NewBuiltIn(thinking)
+
+	req := &model.LLMRequest{}
+	instruction, err := p.BuildPlanningInstruction(nil, req)
+	if err != nil || instruction != "" {
+		t.Errorf("BuildPlanningInstruction() = %q, %v, want no instruction", instruction, err)
+	}
+	if req.Config == nil || req.Config.ThinkingConfig != thinking {
+		t.Errorf("ThinkingConfig = %v, want %v", req.Config, thinking)
+	}
+
+	parts, err := p.ProcessPlanningResponse(nil, []*genai.Part{{Text: "answer"}})
+	if err != nil || parts != nil {
+		t.Errorf("ProcessPlanningResponse() = %v, %v, want the parts kept", parts, err)
+	}
+}
+
+func TestPlanReAct(t *testing.T) {
+	p := planner.NewPlanReAct()
+
+	instruction, err := p.BuildPlanningInstruction(nil, &model.LLMRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tag := range []string{planner.PlanningTag, planner.ReplanningTag, planner.ReasoningTag, planner.ActionTag, planner.FinalAnswerTag} {
+		if !strings.Contains(instruction, tag) {
+			t.Errorf("instruction does not mention %s", tag)
+		}
+	}
+
+	call := func(name string) *genai.Part {
+		return &genai.Part{FunctionCall: &genai.FunctionCall{Name: name}}
+	}
+	for _, tc := range []struct {
+		name  string
+		parts []*genai.Part
+		want  []*genai.Part
+	}{
+		{
+			name:  "final answer",
+			parts: []*genai.Part{{Text: planner.ReasoningTag + " done. " + planner.FinalAnswerTag + " 42"}},
+			want: []*genai.Part{
+				{Text: planner.ReasoningTag + " done. " + planner.FinalAnswerTag, Thought: true},
+				{Text: " 42"},
+			},
+		},
+		{
+			name: "planning and first group of calls",
+			parts: []*genai.Part{
+				{Text: planner.PlanningTag + " 1. search"},
+				{Text: "unmarked"},
+				call(""),
+				call("search"),
+				call("fetch"),
+				{Text: planner.ReasoningTag + " after"},
+				call("other"),
+			},
+			want: []*genai.Part{
+				{Text: planner.PlanningTag + " 1. search", Thought: true},
+				{Text: "unmarked"},
+				call("search"),
+				call("fetch"),
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := p.ProcessPlanningResponse(nil, tc.parts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("ProcessPlanningResponse() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
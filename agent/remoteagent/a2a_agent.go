@@ -20,6 +20,7 @@ import (
 	"iter"
 	"os"
 	"strings"
+	"sync"
 
 	"github.com/a2aproject/a2a-go/a2a"
 	"github.com/a2aproject/a2a-go/a2aclient"
@@ -101,10 +102,21 @@ type A2AConfig struct {
 
 // NewA2A creates a remote A2A agent. A2A (Agent-To-Agent) protocol is used for communication with an
 // agent which can run in a different process or on a different host.
+//
+// The name and the description of the agent default to the ones of the AgentCard, if provided. A card
+// resolved from the AgentCardSource is reused by the later invocations once resolved.
 func NewA2A(cfg A2AConfig) (agent.Agent, error) {
 	if cfg.AgentCard == nil && cfg.AgentCardSource == "" {
 		return nil, fmt.Errorf("either AgentCard or AgentCardSource must be provided")
 	}
+	if cfg.AgentCard != nil {
+		if cfg.Name == "" {
+			cfg.Name = cfg.AgentCard.Name
+		}
+		if cfg.Description == "" {
+			cfg.Description = cfg.AgentCard.Description
+		}
+	}
 
 	remoteAgent := &a2aAgent{resolvedCard: cfg.AgentCard}
 	return agent.New(agent.Config{
@@ -119,17 +131,34 @@ func NewA2A(cfg A2AConfig) (agent.Agent, error) {
 }
 
 type a2aAgent struct {
+	// mu guards resolvedCard, resolved by the first invocation if not
+	// provided.
+	mu           sync.Mutex
 	resolvedCard *a2a.AgentCard
 }
 
+// card returns the resolved agent card, resolving it if needed.
+func (a *a2aAgent) card(ctx agent.InvocationContext, cfg A2AConfig) (*a2a.AgentCard, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.resolvedCard != nil {
+		return a.resolvedCard, nil
+	}
+	card, err := resolveAgentCard(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+	a.resolvedCard = card
+	return card, nil
+}
+
 func (a *a2aAgent) run(ctx agent.InvocationContext, cfg A2AConfig) iter.Seq2[*session.Event, error] {
 	return func(yield func(*session.Event, error) bool) {
-		card, err := resolveAgentCard(ctx, cfg)
+		card, err := a.card(ctx, cfg)
 		if err != nil {
 			yield(toErrorEvent(ctx, fmt.Errorf("agent card resolution failed: %w", err)), nil)
 			return
 		}
-		a.resolvedCard = card
 
 		var client *a2aclient.Client
 		if cfg.ClientFactory != nil {
@@ -244,12 +273,12 @@ func resolveAgentCard(ctx agent.InvocationContext, cfg A2AConfig) (*a2a.AgentCar
 		return nil, fmt.Errorf("failed to read agent card from %q: %w", cfg.AgentCardSource, err)
 	}
 
-	var card *a2a.AgentCard
-	if err := json.Unmarshal(fileBytes, card); err != nil {
+	var card a2a.AgentCard
+	if err := json.Unmarshal(fileBytes, &card); err != nil {
 		return nil, fmt.Errorf("failed to unmarshal an agent card: %w", err)
 	}
 
-	return card, nil
+	return &card, nil
 }
 
 func newMessage(ctx agent.InvocationContext) (*a2a.Message, error) {
@@ -292,7 +321,7 @@ func updateCustomMetadata(event *session.Event, request *a2a.MessageSendParams,
 		if v == nil {
 			continue
 		}
-		payload, err := converters.ToMapStructure(request)
+		payload, err := converters.ToMapStructure(v)
 		if err == nil {
 			event.CustomMetadata[adka2a.ToADKMetaKey(k)] = payload
 		} else {
@@ -17,10 +17,14 @@ package remoteagent
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io/fs"
 	"iter"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
@@ -33,11 +37,15 @@ import (
 	"google.golang.org/genai"
 
 	"google.golang.org/adk/agent"
+	"google.golang.org/adk/agent/llmagent"
 	icontext "google.golang.org/adk/internal/context"
 	"google.golang.org/adk/model"
+	"google.golang.org/adk/model/mockmodel"
 	"google.golang.org/adk/runner"
 	"google.golang.org/adk/server/adka2a"
 	"google.golang.org/adk/session"
+	"google.golang.org/adk/tool"
+	"google.golang.org/adk/tool/agenttool"
 )
 
 type mockA2AExecutor struct {
@@ -855,3 +863,119 @@ func TestRemoteAgent_ErrorEventOnServerError(t *testing.T) {
 		t.Fatal("event.ErrorMessage empty, want non-empty")
 	}
 }
+
+func TestRemoteAgent_ResolvesAgentCardFromFile(t *testing.T) {
+	remoteEvents := []a2a.Event{a2a.NewMessage(a2a.MessageRoleAgent, a2a.TextPart{Text: "Hello!"})}
+	server := startA2AServer(newA2AEventReplay(t, remoteEvents))
+	defer server.Close()
+
+	card := &a2a.AgentCard{
+		Name:               "greeter",
+		Description:        "Greets the users.",
+		PreferredTransport: a2a.TransportProtocolJSONRPC,
+		URL:                server.URL,
+		Capabilities:       a2a.AgentCapabilities{Streaming: true},
+	}
+	cardBytes, err := json.Marshal(card)
+	if err != nil {
+		t.Fatalf("json.Marshal(agentCard) error = %v", err)
+	}
+	path := filepath.Join(t.TempDir(), "agent-card.json")
+	if err := os.WriteFile(path, cardBytes, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	remoteAgent, err := NewA2A(A2AConfig{Name: "a2a", AgentCardSource: path})
+	if err != nil {
+		t.Fatalf("remoteagent.NewA2A() error = %v", err)
+	}
+
+	// The card resolved by the first run is reused by the second one.
+	for range 2 {
+		ictx := newInvocationContext(t, []*session.Event{newUserHello()})
+		gotEvents, err := runAndCollect(ictx, remoteAgent)
+		if err != nil {
+			t.Fatalf("agent.Run() error = %v", err)
+		}
+		wantResponses := []model.LLMResponse{{Content: genai.NewContentFromText("Hello!", genai.RoleModel)}}
+		gotResponses := toLLMResponses(gotEvents)
+		if diff := cmp.Diff(wantResponses, gotResponses, cmpopts.IgnoreFields(model.LLMResponse{}, "CustomMetadata")); diff != "" {
+			t.Fatalf("agent.Run() wrong result (+got,-want):\ngot = %+v\nwant = %+v\ndiff = %s", gotResponses, wantResponses, diff)
+		}
+		if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestRemoteAgent_DefaultsFromAgentCard(t *testing.T) {
+	card := &a2a.AgentCard{Name: "greeter", Description: "Greets the users.", URL: "http://localhost"}
+	remoteAgent, err := NewA2A(A2AConfig{AgentCard: card})
+	if err != nil {
+		t.Fatalf("remoteagent.NewA2A() error = %v", err)
+	}
+	if remoteAgent.Name() != "greeter" || remoteAgent.Description() != "Greets the users." {
+		t.Errorf("got agent %q (%q), want the name and description of the card", remoteAgent.Name(), remoteAgent.Description())
+	}
+}
+
+func TestRemoteAgent_AsTool(t *testing.T) {
+	weatherAgent, err := llmagent.New(llmagent.Config{
+		Name:        "weather_agent",
+		Description: "Answers questions about the weather.",
+		Model:       mockmodel.New(mockmodel.Text("Sunny!")),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := httptest.NewServer(nil)
+	defer server.Close()
+	handler, err := adka2a.NewServer(adka2a.ServerConfig{
+		URL: server.URL,
+		ExecutorConfig: adka2a.ExecutorConfig{
+			RunnerConfig: runner.Config{Agent: weatherAgent, SessionService: session.InMemoryService()},
+		},
+	})
+	if err != nil {
+		t.Fatalf("adka2a.NewServer() error = %v", err)
+	}
+	server.Config.Handler = handler
+
+	remoteAgent, err := NewA2A(A2AConfig{Name: "weather_agent", AgentCardSource: server.URL})
+	if err != nil {
+		t.Fatalf("remoteagent.NewA2A() error = %v", err)
+	}
+	rootModel := mockmodel.New(
+		mockmodel.FunctionCall("weather_agent", map[string]any{"request": "Weather in Paris?"}),
+		mockmodel.Text("It is sunny in Paris."),
+	)
+	root, err := llmagent.New(llmagent.Config{
+		Name:  "root",
+		Model: rootModel,
+		Tools: []tool.Tool{agenttool.New(remoteAgent, nil)},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r, err := runner.New(runner.Config{AppName: t.Name(), Agent: root, SessionService: session.InMemoryService(), AutoCreateSession: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, err := range r.Run(t.Context(), "user", "session", genai.NewContentFromText("Weather in Paris?", genai.RoleUser), agent.RunConfig{}) {
+		if err != nil {
+			t.Fatalf("runner.Run() error = %v", err)
+		}
+	}
+
+	requests := rootModel.Requests()
+	if len(requests) != 2 {
+		t.Fatalf("got %d model requests, want 2", len(requests))
+	}
+	contents := requests[1].Contents
+	got := contents[len(contents)-1].Parts[0].FunctionResponse
+	want := &genai.FunctionResponse{Name: "weather_agent", Response: map[string]any{"result": "Sunny!"}}
+	if diff := cmp.Diff(want, got, cmpopts.IgnoreFields(genai.FunctionResponse{}, "ID")); diff != "" {
+		t.Errorf("function response mismatch (-want +got):\n%s", diff)
+	}
+}
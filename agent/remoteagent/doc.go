@@ -13,4 +13,21 @@
 // limitations under the License.
 
 // Package remoteagent allows to use a remote ADK agents.
+//
+// [NewA2A] returns an agent delegating its runs to an agent served over the
+// A2A protocol, e.g. by an ADK server of any language, see adka2a.NewServer.
+// The events of the remote agent are streamed back as the events of the
+// local agent. Like any agent, it can be a sub-agent, or be called as a tool
+// by the LLM agents, see agenttool.New:
+//
+//	remote, err := remoteagent.NewA2A(remoteagent.A2AConfig{
+//		Name:            "weather_agent",
+//		Description:     "Answers questions about the weather.",
+//		AgentCardSource: "https://weather.example.com",
+//	})
+//	...
+//	root, err := llmagent.New(llmagent.Config{
+//		...
+//		Tools: []tool.Tool{agenttool.New(remote, nil)},
+//	})
 package remoteagent
@@ -14,6 +14,14 @@
 
 package agent
 
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"google.golang.org/genai"
+)
+
 // StreamingMode defines the streaming mode for agent execution.
 type StreamingMode string
 
@@ -23,6 +31,28 @@ const (
 	// StreamingModeSSE enables server-sent events streaming, one-way, where
 	// LLM response parts are streamed immediately as they are generated.
 	StreamingModeSSE StreamingMode = "sse"
+	// StreamingModeBidi is the mode of the live runs, see
+	// runner.Runner.RunLive: the input is streamed to the model, e.g. audio,
+	// over a bidirectional connection. It is set by RunLive.
+	StreamingModeBidi StreamingMode = "bidi"
+)
+
+// EventOverflow defines what the runner does with an event when the buffer
+// of events ahead of the consumer is full. See RunConfig.EventBufferSize.
+type EventOverflow string
+
+const (
+	// EventOverflowBlock blocks the agent until the consumer catches up.
+	// This is the default.
+	EventOverflowBlock EventOverflow = "block"
+	// EventOverflowDropPartial drops partial events, such as the chunks of
+	// a streamed model response, while the buffer is full. Non-partial
+	// events and errors are never dropped: the agent blocks on them as with
+	// EventOverflowBlock. Since the final event of a streamed model response
+	// holds the whole response, the consumer only loses intermediate
+	// progress. Dropped events leave gaps in the sequence numbers of the
+	// events received by the consumer.
+	EventOverflowDropPartial EventOverflow = "drop_partial"
 )
 
 // RunConfig controls runtime behavior of an agent.
@@ -32,4 +62,136 @@ type RunConfig struct {
 	// If true, ADK runner will save each part of the user input that is a blob
 	// (e.g., images, files) as an artifact.
 	SaveInputBlobsAsArtifacts bool
+	// MaxInlinePartSize is the size in bytes above which the inline data of a
+	// part is offloaded to the artifact service before the event is stored.
+	// The part is replaced with an artifact reference (see
+	// artifact.NewReference) that is resolved into the artifact content when
+	// LLM requests are built. Zero disables offloading. Offloading requires
+	// the runner to have an artifact service.
+	MaxInlinePartSize int
+	// EventBufferSize is the number of events buffered between the agent and
+	// the consumer of the events of a run. With the default of zero, the
+	// agent runs in lock step with the consumer: it is blocked while the
+	// consumer processes an event. A buffer lets the agent run ahead of a slow
+	// consumer, by at most EventBufferSize events, after which EventOverflow
+	// applies.
+	//
+	// While the agent is blocked in streaming mode, the runner stops reading
+	// the model response stream, which the model service may eventually time
+	// out. EventOverflowDropPartial avoids this at the cost of intermediate
+	// progress events.
+	EventBufferSize int
+	// EventOverflow is the behavior when the event buffer is full. Defaults to
+	// EventOverflowBlock.
+	EventOverflow EventOverflow
+	// EmitTurnBoundaries makes the runner mark the boundaries of the turn in
+	// the event stream of a run: the first event, a copy of the stored user
+	// message event if any, has TurnBoundary set to session.TurnStart, and
+	// the last event, which has no content and is not stored in the
+	// session, has TurnBoundary set to session.TurnComplete. See
+	// session.Turn for the definition of a turn.
+	EmitTurnBoundaries bool
+	// StripThoughtsFromHistory removes the thoughts of the model, see
+	// session.Event.Thoughts, from the events stored in the session, so that
+	// they are neither kept in the history nor sent back to the model; their
+	// thought signatures are kept, see session.StripThoughts. The events
+	// yielded by the run keep their thoughts.
+	StripThoughtsFromHistory bool
+	// StripThoughtsFromOutput removes the thoughts of the model from the
+	// events yielded by the run, e.g. not to show them to the end users. The
+	// partial events with thoughts only are not yielded. The events stored in
+	// the session keep their thoughts, unless StripThoughtsFromHistory.
+	StripThoughtsFromOutput bool
+	// GenerateContentConfig overrides, for the run, the generation config of
+	// the LLM agents set in llmagent.Config.GenerateContentConfig. The two are
+	// merged field by field:
+	//   - a field set here, i.e. not the zero value, replaces the agent's one
+	//     as a whole; this applies to the scalars, such as Temperature, to
+	//     the slices, such as StopSequences and SafetySettings, and to the
+	//     nested configs, such as ThinkingConfig;
+	//   - Tools and the parts of SystemInstruction are appended to the
+	//     agent's ones, and the tools of the agent are added after them;
+	//   - Labels are merged, the keys set here winning.
+	//
+	// Since the zero value means unset, a boolean field can't be turned off
+	// and a slice can't be cleared.
+	GenerateContentConfig *genai.GenerateContentConfig
+
+	// MaxLLMCalls limits the number of model calls of the run, across all
+	// its agents, e.g. to stop a model looping on tool calls. Zero means no
+	// limit.
+	MaxLLMCalls int
+	// MaxToolCalls limits the number of tool calls of the run, across all
+	// its agents. Zero means no limit.
+	MaxToolCalls int
+	// MaxTokens limits the tokens of the model calls of the run, across all
+	// its agents: the sum of the total token counts reported by the model,
+	// see usage.Aggregator. The run ends once a model response exceeds it,
+	// after the response event and before its function calls are run. Zero
+	// means no limit.
+	MaxTokens int
+	// Timeout limits the duration of the run. Zero means no limit.
+	//
+	// A run over one of these limits ends with a *LimitExceededError,
+	// preceded by an event reporting it, see runner.Runner.Run.
+	Timeout time.Duration
+
+	// The configs of the live connections, see runner.Runner.RunLive.
+
+	// ResponseModalities are the modalities of the responses of the model,
+	// e.g. genai.ModalityAudio. Defaults to the one of the model.
+	ResponseModalities []genai.Modality
+	// SpeechConfig configures the speech of the model, e.g. its voice.
+	SpeechConfig *genai.SpeechConfig
+	// InputAudioTranscription and OutputAudioTranscription enable the
+	// transcription of the audio of the user and of the model.
+	InputAudioTranscription  *genai.AudioTranscriptionConfig
+	OutputAudioTranscription *genai.AudioTranscriptionConfig
+	// RealtimeInputConfig configures the realtime input, e.g. the automatic
+	// activity detection.
+	RealtimeInputConfig *genai.RealtimeInputConfig
+}
+
+// The limits of a run, see LimitExceededError.
+const (
+	LimitLLMCalls  = "llm_calls"
+	LimitToolCalls = "tool_calls"
+	LimitTokens    = "tokens"
+	LimitTimeout   = "timeout"
+)
+
+// ErrMaxCallsExceeded matches, with errors.Is, the LimitExceededError of the
+// runs exceeding their MaxLLMCalls or MaxToolCalls.
+var ErrMaxCallsExceeded = errors.New("maximum number of calls exceeded")
+
+// LimitExceededError is the error of a run exceeding a limit of its
+// RunConfig.
+type LimitExceededError struct {
+	// Limit is the limit exceeded: LimitLLMCalls, LimitToolCalls,
+	// LimitTokens or LimitTimeout.
+	Limit string
+	// Max is the maximum number of calls, for LimitLLMCalls and
+	// LimitToolCalls, or of tokens, for LimitTokens.
+	Max int
+	// Timeout is the timeout of the run, for LimitTimeout.
+	Timeout time.Duration
+}
+
+func (e *LimitExceededError) Error() string {
+	switch e.Limit {
+	case LimitLLMCalls:
+		return fmt.Sprintf("run exceeded the limit of %d LLM calls", e.Max)
+	case LimitToolCalls:
+		return fmt.Sprintf("run exceeded the limit of %d tool calls", e.Max)
+	case LimitTokens:
+		return fmt.Sprintf("run exceeded the limit of %d tokens", e.Max)
+	case LimitTimeout:
+		return fmt.Sprintf("run exceeded its timeout of %v", e.Timeout)
+	}
+	return fmt.Sprintf("run exceeded the limit %q", e.Limit)
+}
+
+// Is reports whether the limit is one of the calls, see ErrMaxCallsExceeded.
+func (e *LimitExceededError) Is(target error) bool {
+	return target == ErrMaxCallsExceeded && (e.Limit == LimitLLMCalls || e.Limit == LimitToolCalls)
 }
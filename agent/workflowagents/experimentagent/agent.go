@@ -0,0 +1,174 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package experimentagent provides an agent that routes each user to one of
+// several weighted variants, e.g. for A/B testing prompts or models.
+package experimentagent
+
+import (
+	"fmt"
+	"hash/fnv"
+	"iter"
+	"maps"
+
+	"google.golang.org/adk/agent"
+	agentinternal "google.golang.org/adk/internal/agent"
+	"google.golang.org/adk/session"
+)
+
+// MetadataKeyVariant is the key in session.Event.CustomMetadata holding the
+// name of the variant which produced the event.
+const MetadataKeyVariant = "experiment_variant"
+
+// Variant is one arm of an experiment.
+type Variant struct {
+	// Agent that handles the sessions routed to this variant.
+	Agent agent.Agent
+	// Weight is the relative share of users routed to this variant.
+	// Variants with zero weight never run.
+	Weight uint
+}
+
+// Config defines the configuration for an ExperimentAgent.
+type Config struct {
+	// Basic agent setup. SubAgents must be empty, they are populated from
+	// Variants.
+	AgentConfig agent.Config
+
+	// Variants of the experiment. At least one variant must have a non-zero
+	// weight.
+	Variants []Variant
+
+	// StateKey is the session state key where the name of the selected variant
+	// is recorded. Defaults to "<agent name>_variant".
+	StateKey string
+}
+
+// New creates an ExperimentAgent.
+//
+// ExperimentAgent forwards each invocation to one of its variants. The variant
+// is selected by hashing the agent name together with the user ID, so a user
+// is consistently assigned to the same variant across sessions while the
+// overall traffic is split according to the variant weights.
+//
+// The selected variant name is recorded in the session state under
+// Config.StateKey and in the CustomMetadata of every forwarded event under
+// MetadataKeyVariant.
+func New(cfg Config) (agent.Agent, error) {
+	if cfg.AgentConfig.Run != nil {
+		return nil, fmt.Errorf("ExperimentAgent doesn't allow custom Run implementations")
+	}
+	if len(cfg.AgentConfig.SubAgents) > 0 {
+		return nil, fmt.Errorf("ExperimentAgent sub-agents are defined by Variants")
+	}
+
+	var totalWeight uint64
+	for _, v := range cfg.Variants {
+		if v.Agent == nil {
+			return nil, fmt.Errorf("variant agent must not be nil")
+		}
+		totalWeight += uint64(v.Weight)
+		cfg.AgentConfig.SubAgents = append(cfg.AgentConfig.SubAgents, v.Agent)
+	}
+	if totalWeight == 0 {
+		return nil, fmt.Errorf("ExperimentAgent requires at least one variant with a non-zero weight")
+	}
+
+	stateKey := cfg.StateKey
+	if stateKey == "" {
+		stateKey = cfg.AgentConfig.Name + "_variant"
+	}
+
+	impl := &experimentAgent{
+		name:        cfg.AgentConfig.Name,
+		variants:    cfg.Variants,
+		totalWeight: totalWeight,
+		stateKey:    stateKey,
+	}
+	cfg.AgentConfig.Run = impl.run
+
+	experimentAgent, err := agent.New(cfg.AgentConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create base agent: %w", err)
+	}
+
+	internalAgent, ok := experimentAgent.(agentinternal.Agent)
+	if !ok {
+		return nil, fmt.Errorf("internal error: failed to convert to internal agent")
+	}
+	state := agentinternal.Reveal(internalAgent)
+	state.AgentType = agentinternal.TypeExperimentAgent
+	state.Config = cfg
+
+	return experimentAgent, nil
+}
+
+type experimentAgent struct {
+	name        string
+	variants    []Variant
+	totalWeight uint64
+	stateKey    string
+}
+
+// selectVariant returns the variant assigned to the given user.
+func (a *experimentAgent) selectVariant(userID string) agent.Agent {
+	h := fnv.New64a()
+	// Salt with the experiment name so that different experiments assign
+	// users independently.
+	h.Write([]byte(a.name))
+	h.Write([]byte{0})
+	h.Write([]byte(userID))
+	bucket := h.Sum64() % a.totalWeight
+
+	for _, v := range a.variants {
+		if bucket < uint64(v.Weight) {
+			return v.Agent
+		}
+		bucket -= uint64(v.Weight)
+	}
+	// Unreachable: bucket is always lower than the total weight.
+	return a.variants[len(a.variants)-1].Agent
+}
+
+func (a *experimentAgent) run(ctx agent.InvocationContext) iter.Seq2[*session.Event, error] {
+	return func(yield func(*session.Event, error) bool) {
+		variant := a.selectVariant(ctx.Session().UserID())
+
+		recorded := false
+		if v, err := ctx.Session().State().Get(a.stateKey); err == nil && v == variant.Name() {
+			recorded = true
+		}
+
+		for event, err := range variant.Run(ctx) {
+			if event != nil {
+				event.CustomMetadata = maps.Clone(event.CustomMetadata)
+				if event.CustomMetadata == nil {
+					event.CustomMetadata = make(map[string]any)
+				}
+				event.CustomMetadata[MetadataKeyVariant] = variant.Name()
+
+				if !recorded && !event.Partial {
+					if event.Actions.StateDelta == nil {
+						event.Actions.StateDelta = make(map[string]any)
+					}
+					event.Actions.StateDelta[a.stateKey] = variant.Name()
+					recorded = true
+				}
+			}
+			if !yield(event, err) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,158 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package experimentagent_test
+
+import (
+	"fmt"
+	"iter"
+	"testing"
+
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/agent"
+	"google.golang.org/adk/agent/workflowagents/experimentagent"
+	"google.golang.org/adk/runner"
+	"google.golang.org/adk/session"
+)
+
+func newVariant(t *testing.T, name string) agent.Agent {
+	t.Helper()
+	a, err := agent.New(agent.Config{
+		Name: name,
+		Run: func(ctx agent.InvocationContext) iter.Seq2[*session.Event, error] {
+			return func(yield func(*session.Event, error) bool) {
+				ev := session.NewEvent(ctx.InvocationID())
+				ev.Content = genai.NewContentFromText("hello from "+name, genai.RoleModel)
+				yield(ev, nil)
+			}
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return a
+}
+
+func TestExperimentAgent(t *testing.T) {
+	ctx := t.Context()
+	const appName = "app"
+
+	experiment, err := experimentagent.New(experimentagent.Config{
+		AgentConfig: agent.Config{Name: "prompt_experiment"},
+		Variants: []experimentagent.Variant{
+			{Agent: newVariant(t, "control"), Weight: 1},
+			{Agent: newVariant(t, "treatment"), Weight: 1},
+			{Agent: newVariant(t, "disabled"), Weight: 0},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sessionService := session.InMemoryService()
+	r, err := runner.New(runner.Config{
+		AppName:        appName,
+		Agent:          experiment,
+		SessionService: sessionService,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	run := func(userID string) string {
+		t.Helper()
+		resp, err := sessionService.Create(ctx, &session.CreateRequest{AppName: appName, UserID: userID})
+		if err != nil {
+			t.Fatal(err)
+		}
+		var variant string
+		for ev, err := range r.Run(ctx, userID, resp.Session.ID(), genai.NewContentFromText("hi", genai.RoleUser), agent.RunConfig{}) {
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, ok := ev.CustomMetadata[experimentagent.MetadataKeyVariant].(string)
+			if !ok {
+				t.Fatalf("event CustomMetadata = %v, want the variant recorded", ev.CustomMetadata)
+			}
+			if ev.Author != got {
+				t.Errorf("event author = %q, want the variant %q", ev.Author, got)
+			}
+			variant = got
+		}
+
+		getResp, err := sessionService.Get(ctx, &session.GetRequest{AppName: appName, UserID: userID, SessionID: resp.Session.ID()})
+		if err != nil {
+			t.Fatal(err)
+		}
+		stored, err := getResp.Session.State().Get("prompt_experiment_variant")
+		if err != nil {
+			t.Fatalf("variant is not recorded in the session state: %v", err)
+		}
+		if stored != variant {
+			t.Errorf("state variant = %v, want %q", stored, variant)
+		}
+		return variant
+	}
+
+	counts := make(map[string]int)
+	for i := range 100 {
+		userID := fmt.Sprintf("user-%d", i)
+		variant := run(userID)
+		if again := run(userID); again != variant {
+			t.Errorf("user %q got variant %q, then %q: assignment must be stable", userID, variant, again)
+		}
+		counts[variant]++
+	}
+
+	if counts["disabled"] != 0 {
+		t.Errorf("zero-weight variant ran %d times", counts["disabled"])
+	}
+	if counts["control"] == 0 || counts["treatment"] == 0 {
+		t.Errorf("variant counts = %v, want traffic routed to both weighted variants", counts)
+	}
+}
+
+func TestNew_InvalidConfig(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  experimentagent.Config
+	}{
+		{
+			name: "no variants",
+			cfg:  experimentagent.Config{AgentConfig: agent.Config{Name: "exp"}},
+		},
+		{
+			name: "zero total weight",
+			cfg: experimentagent.Config{
+				AgentConfig: agent.Config{Name: "exp"},
+				Variants:    []experimentagent.Variant{{Agent: newVariant(t, "a")}},
+			},
+		},
+		{
+			name: "sub-agents set",
+			cfg: experimentagent.Config{
+				AgentConfig: agent.Config{Name: "exp", SubAgents: []agent.Agent{newVariant(t, "a")}},
+				Variants:    []experimentagent.Variant{{Agent: newVariant(t, "b"), Weight: 1}},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := experimentagent.New(tt.cfg); err == nil {
+				t.Error("experimentagent.New() succeeded, want error")
+			}
+		})
+	}
+}
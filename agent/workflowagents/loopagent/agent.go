@@ -33,6 +33,12 @@ type Config struct {
 	// If MaxIterations == 0, then LoopAgent runs indefinitely or until any
 	// sub-agent escalates.
 	MaxIterations uint
+
+	// RecordIterations records, in the CustomMetadata of the events of the
+	// sub-agents, the iteration which produced them, see Iteration, e.g. to
+	// tell which revision of a generate-critique-refine loop was the final
+	// one.
+	RecordIterations bool
 }
 
 // New creates a LoopAgent.
@@ -41,14 +47,18 @@ type Config struct {
 // of iterations or until a termination condition is met.
 //
 // Use the LoopAgent when your workflow involves repetition or iterative
-// refinement, such as like revising code.
+// refinement, such as like revising code. A sub-agent ends the loop by
+// escalating, e.g. an LLM agent calling the tool of package exitlooptool once
+// the result is good enough; the loop also ends on the first error of a
+// sub-agent.
 func New(cfg Config) (agent.Agent, error) {
 	if cfg.AgentConfig.Run != nil {
 		return nil, fmt.Errorf("LoopAgent doesn't allow custom Run implementations")
 	}
 
 	loopAgentImpl := &loopAgent{
-		maxIterations: cfg.MaxIterations,
+		maxIterations:    cfg.MaxIterations,
+		recordIterations: cfg.RecordIterations,
 	}
 	cfg.AgentConfig.Run = loopAgentImpl.Run
 
@@ -69,19 +79,28 @@ func New(cfg Config) (agent.Agent, error) {
 }
 
 type loopAgent struct {
-	maxIterations uint
+	maxIterations    uint
+	recordIterations bool
 }
 
 func (a *loopAgent) Run(ctx agent.InvocationContext) iter.Seq2[*session.Event, error] {
 	count := a.maxIterations
 
 	return func(yield func(*session.Event, error) bool) {
-		for {
+		for iteration := 1; ; iteration++ {
 			shouldExit := false
 			for _, subAgent := range ctx.Agent().SubAgents() {
 				for event, err := range subAgent.Run(ctx) {
-					// TODO: ensure consistency -- if there's an error, return and close iterator, verify everywhere in ADK.
-					if !yield(event, err) {
+					if err != nil {
+						// The loop ends with the error rather than retrying
+						// the failing sub-agent, possibly indefinitely.
+						yield(nil, err)
+						return
+					}
+					if event != nil && a.recordIterations {
+						setIteration(event, ctx.Agent().Name(), iteration)
+					}
+					if !yield(event, nil) {
 						return
 					}
 
@@ -103,3 +122,40 @@ func (a *loopAgent) Run(ctx agent.InvocationContext) iter.Seq2[*session.Event, e
 		}
 	}
 }
+
+// IterationMetadataKey is the key of the iterations in the CustomMetadata of
+// the events of the sub-agents of the loop agents: a map from the names of
+// the loop agents, several for nested loops, to the iteration, starting at 1,
+// of the loop agent which produced the event. Only the loop agents with
+// Config.RecordIterations record their iterations.
+const IterationMetadataKey = "loop_iteration"
+
+// Iteration returns the iteration, starting at 1, of the loop agent named
+// loopName which produced the event, and whether the event has one.
+func Iteration(event *session.Event, loopName string) (int, bool) {
+	if event == nil {
+		return 0, false
+	}
+	iterations, _ := event.CustomMetadata[IterationMetadataKey].(map[string]any)
+	// The iterations are numbers once decoded from JSON, e.g. from a
+	// database session service.
+	switch n := iterations[loopName].(type) {
+	case int:
+		return n, true
+	case float64:
+		return int(n), true
+	}
+	return 0, false
+}
+
+func setIteration(event *session.Event, loopName string, iteration int) {
+	if event.CustomMetadata == nil {
+		event.CustomMetadata = make(map[string]any)
+	}
+	iterations, _ := event.CustomMetadata[IterationMetadataKey].(map[string]any)
+	if iterations == nil {
+		iterations = make(map[string]any)
+		event.CustomMetadata[IterationMetadataKey] = iterations
+	}
+	iterations[loopName] = iteration
+}
@@ -16,6 +16,7 @@ package loopagent_test
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"iter"
 	"testing"
@@ -233,7 +234,7 @@ func TestNewLoopAgent(t *testing.T) {
 			}
 
 			ignoreFields := []cmp.Option{
-				cmpopts.IgnoreFields(session.Event{}, "ID", "InvocationID", "Timestamp"),
+				cmpopts.IgnoreFields(session.Event{}, "ID", "InvocationID", "Timestamp", "CorrelationID", "Sequence", "TurnID"),
 				cmpopts.IgnoreFields(session.EventActions{}, "StateDelta"),
 				cmpopts.IgnoreFields(genai.FunctionCall{}, "ID"),
 				cmpopts.IgnoreFields(genai.FunctionResponse{}, "ID"),
@@ -357,3 +358,114 @@ func (f *FakeLLM) GenerateContent(ctx context.Context, req *model.LLMRequest, st
 		}
 	}
 }
+
+func TestLoopAgent_EndsOnError(t *testing.T) {
+	wantErr := errors.New("sub-agent failed")
+	failing, err := agent.New(agent.Config{
+		Name: "failing_agent",
+		Run: func(agent.InvocationContext) iter.Seq2[*session.Event, error] {
+			return func(yield func(*session.Event, error) bool) {
+				yield(nil, wantErr)
+			}
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	loopAgent, err := loopagent.New(loopagent.Config{
+		AgentConfig: agent.Config{
+			Name:      "test_agent",
+			SubAgents: []agent.Agent{failing, newCustomAgent(t, 0)},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sessionService := session.InMemoryService()
+	agentRunner, err := runner.New(runner.Config{
+		AppName:        "test_app",
+		Agent:          loopAgent,
+		SessionService: sessionService,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := sessionService.Create(t.Context(), &session.CreateRequest{
+		AppName:   "test_app",
+		UserID:    "user_id",
+		SessionID: "session_id",
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	var gotErrs []error
+	for event, err := range agentRunner.Run(t.Context(), "user_id", "session_id", genai.NewContentFromText("user input", genai.RoleUser), agent.RunConfig{}) {
+		if err == nil {
+			t.Errorf("got event %v, want the error only", event)
+			continue
+		}
+		gotErrs = append(gotErrs, err)
+	}
+	if len(gotErrs) != 1 || !errors.Is(gotErrs[0], wantErr) {
+		t.Errorf("got errors %v, want %v once", gotErrs, wantErr)
+	}
+}
+
+func TestLoopAgent_RecordIterations(t *testing.T) {
+	loopAgent, err := loopagent.New(loopagent.Config{
+		AgentConfig: agent.Config{
+			Name:      "test_agent",
+			SubAgents: []agent.Agent{newCustomAgent(t, 0)},
+		},
+		MaxIterations:    3,
+		RecordIterations: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sessionService := session.InMemoryService()
+	agentRunner, err := runner.New(runner.Config{
+		AppName:        "test_app",
+		Agent:          loopAgent,
+		SessionService: sessionService,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := sessionService.Create(t.Context(), &session.CreateRequest{
+		AppName:   "test_app",
+		UserID:    "user_id",
+		SessionID: "session_id",
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	var gotIterations []int
+	for event, err := range agentRunner.Run(t.Context(), "user_id", "session_id", genai.NewContentFromText("user input", genai.RoleUser), agent.RunConfig{}) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		iteration, ok := loopagent.Iteration(event, "test_agent")
+		if !ok {
+			t.Errorf("event %v has no iteration", event)
+		}
+		gotIterations = append(gotIterations, iteration)
+	}
+	if diff := cmp.Diff([]int{1, 2, 3}, gotIterations); diff != "" {
+		t.Errorf("iterations mismatch (-want +got):\n%s", diff)
+	}
+
+	resp, err := sessionService.Get(t.Context(), &session.GetRequest{AppName: "test_app", UserID: "user_id", SessionID: "session_id"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	last := resp.Session.Events().At(resp.Session.Events().Len() - 1)
+	if iteration, ok := loopagent.Iteration(last, "test_agent"); !ok || iteration != 3 {
+		t.Errorf("Iteration() of the stored final event = %d, %v, want 3", iteration, ok)
+	}
+	if _, ok := loopagent.Iteration(last, "other_agent"); ok {
+		t.Error("Iteration() of another loop found an iteration")
+	}
+}
@@ -18,6 +18,7 @@ package parallelagent
 import (
 	"fmt"
 	"iter"
+	"strings"
 
 	"golang.org/x/sync/errgroup"
 
@@ -31,11 +32,23 @@ import (
 type Config struct {
 	// Basic agent setup.
 	AgentConfig agent.Config
+
+	// OutputKeyPrefix optionally saves the answer of each sub-agent in the
+	// session state, under OutputKeyPrefix followed by the name of the
+	// sub-agent, e.g. "research:web" for the "web" sub-agent with the
+	// "research:" prefix, so that a following agent, e.g. the aggregator of
+	// a sequential agent, reads all the answers. The answer of a sub-agent is
+	// the text of the last final response of its branch.
+	OutputKeyPrefix string
 }
 
 // New creates a ParallelAgent.
 //
-// Parallel agent runs its sub-agents in parallel in isolated manner.
+// Parallel agent runs its sub-agents in parallel in isolated manner: each
+// one runs in its own branch, "parent.sub_agent", so that it does not see the
+// conversation of the others, and their events are yielded as they come,
+// interleaved. The first error of a sub-agent cancels the others. Set
+// Config.OutputKeyPrefix to keep the answer of each sub-agent in the state.
 //
 // This approach is beneficial for scenarios requiring multiple perspectives or
 // attempts on a single task, such as:
@@ -46,7 +59,9 @@ func New(cfg Config) (agent.Agent, error) {
 		return nil, fmt.Errorf("ParallelAgent doesn't allow custom Run implementations")
 	}
 
-	cfg.AgentConfig.Run = run
+	cfg.AgentConfig.Run = func(ctx agent.InvocationContext) iter.Seq2[*session.Event, error] {
+		return run(ctx, cfg.OutputKeyPrefix)
+	}
 
 	parallelAgent, err := agent.New(cfg.AgentConfig)
 	if err != nil {
@@ -64,7 +79,7 @@ func New(cfg Config) (agent.Agent, error) {
 	return parallelAgent, nil
 }
 
-func run(ctx agent.InvocationContext) iter.Seq2[*session.Event, error] {
+func run(ctx agent.InvocationContext, outputKeyPrefix string) iter.Seq2[*session.Event, error] {
 	curAgent := ctx.Agent()
 
 	var (
@@ -81,16 +96,21 @@ func run(ctx agent.InvocationContext) iter.Seq2[*session.Event, error] {
 		subAgent := sa
 		errGroup.Go(func() error {
 			subCtx := icontext.NewInvocationContext(errGroupCtx, icontext.InvocationContextParams{
-				Artifacts:   ctx.Artifacts(),
-				Memory:      ctx.Memory(),
-				Session:     ctx.Session(),
-				Branch:      branch,
-				Agent:       subAgent,
-				UserContent: ctx.UserContent(),
-				RunConfig:   ctx.RunConfig(),
+				Artifacts:    ctx.Artifacts(),
+				Memory:       ctx.Memory(),
+				Session:      ctx.Session(),
+				Branch:       branch,
+				Agent:        subAgent,
+				UserContent:  ctx.UserContent(),
+				RunConfig:    ctx.RunConfig(),
+				InvocationID: ctx.InvocationID(),
 			})
 
-			if err := runSubAgent(subCtx, subAgent, resultsChan, doneChan); err != nil {
+			outputKey := ""
+			if outputKeyPrefix != "" {
+				outputKey = outputKeyPrefix + subAgent.Name()
+			}
+			if err := runSubAgent(subCtx, subAgent, outputKey, resultsChan, doneChan); err != nil {
 				return fmt.Errorf("failed to run sub-agent %q: %w", subAgent.Name(), err)
 			}
 
@@ -114,8 +134,13 @@ func run(ctx agent.InvocationContext) iter.Seq2[*session.Event, error] {
 	}
 }
 
-func runSubAgent(ctx agent.InvocationContext, agent agent.Agent, results chan<- result, done <-chan bool) error {
+// runSubAgent sends the events of the sub-agent to results, saving its
+// answers under outputKey, if not empty.
+func runSubAgent(ctx agent.InvocationContext, agent agent.Agent, outputKey string, results chan<- result, done <-chan bool) error {
 	for event, err := range agent.Run(ctx) {
+		if outputKey != "" && event != nil {
+			saveAnswer(event, outputKey)
+		}
 		select {
 		case <-done:
 			return nil
@@ -143,3 +168,24 @@ type result struct {
 	event *session.Event
 	err   error
 }
+
+// saveAnswer saves the text of the event in the state under key, if it is a
+// final response with text.
+func saveAnswer(event *session.Event, key string) {
+	if !event.IsFinalResponse() || event.Content == nil {
+		return
+	}
+	var sb strings.Builder
+	for _, part := range event.Content.Parts {
+		if part.Text != "" && !part.Thought {
+			sb.WriteString(part.Text)
+		}
+	}
+	if sb.Len() == 0 {
+		return
+	}
+	if event.Actions.StateDelta == nil {
+		event.Actions.StateDelta = make(map[string]any)
+	}
+	event.Actions.StateDelta[key] = sb.String()
+}
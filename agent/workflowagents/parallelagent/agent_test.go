@@ -24,6 +24,7 @@ import (
 	"time"
 
 	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
 	"google.golang.org/genai"
 
 	"google.golang.org/adk/agent"
@@ -153,7 +154,7 @@ func TestNewParallelAgent(t *testing.T) {
 				slices.SortFunc(tt.wantEvents, eventCompareFunc)
 				slices.SortFunc(gotEvents, eventCompareFunc)
 
-				if diff := cmp.Diff(tt.wantEvents, gotEvents); diff != "" {
+				if diff := cmp.Diff(tt.wantEvents, gotEvents, cmpopts.IgnoreFields(session.Event{}, "CorrelationID", "Sequence", "TurnID")); diff != "" {
 					t.Errorf("events mismatch (-want +got):\n%s", diff)
 				}
 			}
@@ -161,6 +162,46 @@ func TestNewParallelAgent(t *testing.T) {
 	}
 }
 
+func TestParallelAgent_OutputKeyPrefix(t *testing.T) {
+	a, err := parallelagent.New(parallelagent.Config{
+		AgentConfig: agent.Config{
+			Name: "research",
+			SubAgents: []agent.Agent{
+				must(agent.New(agent.Config{Name: "sub1", Run: customRun(1, nil)})),
+				must(agent.New(agent.Config{Name: "sub2", Run: customRun(2, nil)})),
+			},
+		},
+		OutputKeyPrefix: "answers:",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sessionService := session.InMemoryService()
+	r, err := runner.New(runner.Config{AppName: "app", Agent: a, SessionService: sessionService})
+	if err != nil {
+		t.Fatal(err)
+	}
+	created, err := sessionService.Create(t.Context(), &session.CreateRequest{AppName: "app", UserID: "user"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, err := range r.Run(t.Context(), "user", created.Session.ID(), genai.NewContentFromText("go", genai.RoleUser), agent.RunConfig{}) {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	resp, err := sessionService.Get(t.Context(), &session.GetRequest{AppName: "app", UserID: "user", SessionID: created.Session.ID()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Each answer has its own key.
+	for key, want := range map[string]string{"answers:sub1": "hello 1", "answers:sub2": "hello 2"} {
+		if got, err := resp.Session.State().Get(key); err != nil || got != want {
+			t.Errorf("State().Get(%q) = %v, %v, want %q", key, got, err, want)
+		}
+	}
+}
+
 // newParallelAgent creates parallel agent with 2 subagents emitting maxIterations events or infinitely if maxIterations==0.
 func newParallelAgent(t *testing.T, maxIterations uint, numSubAgents int, agentErr error) agent.Agent {
 	var subAgents []agent.Agent
@@ -25,7 +25,11 @@ import (
 
 // New creates a SequentialAgent.
 //
-// SequentialAgent executes its sub-agents once, in the order they are listed.
+// SequentialAgent executes its sub-agents once, in the order they are listed,
+// within the same invocation: each sub-agent sees the session state, and the
+// events, of the previous ones, e.g. a value saved with the OutputKey of an
+// LLM agent. The sequence ends early when a sub-agent escalates, see
+// session.EventActions.Escalate, or fails.
 //
 // Use the SequentialAgent when you want the execution to occur in a fixed,
 // strict order.
@@ -253,7 +253,7 @@ func TestNewSequentialAgent(t *testing.T) {
 
 				for i, gotEvent := range gotEvents {
 					tt.wantEvents[i].Timestamp = gotEvent.Timestamp
-					if diff := cmp.Diff(tt.wantEvents[i], gotEvent, cmpopts.IgnoreFields(session.Event{}, "ID", "Timestamp", "InvocationID"),
+					if diff := cmp.Diff(tt.wantEvents[i], gotEvent, cmpopts.IgnoreFields(session.Event{}, "ID", "Timestamp", "InvocationID", "CorrelationID", "Sequence", "TurnID"),
 						cmpopts.IgnoreFields(session.EventActions{}, "StateDelta")); diff != "" {
 						t.Errorf("event[i] mismatch (-want +got):\n%s", diff)
 					}
@@ -263,6 +263,61 @@ func TestNewSequentialAgent(t *testing.T) {
 	}
 }
 
+func TestSequentialAgent_StateAndEscalation(t *testing.T) {
+	var ran []string
+	step := func(name string, run func(ctx agent.InvocationContext, ev *session.Event)) agent.Agent {
+		a, err := agent.New(agent.Config{
+			Name: name,
+			Run: func(ctx agent.InvocationContext) iter.Seq2[*session.Event, error] {
+				return func(yield func(*session.Event, error) bool) {
+					ran = append(ran, name)
+					ev := session.NewEvent(ctx.InvocationID())
+					ev.Author = name
+					run(ctx, ev)
+					yield(ev, nil)
+				}
+			},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return a
+	}
+	var seen any
+	sequentialAgent := newSequentialAgent(t, []agent.Agent{
+		step("draft", func(_ agent.InvocationContext, ev *session.Event) {
+			ev.Actions.StateDelta = map[string]any{"draft": "v1"}
+		}),
+		// The state changes of the previous sub-agents are visible.
+		step("review", func(ctx agent.InvocationContext, ev *session.Event) {
+			seen, _ = ctx.Session().State().Get("draft")
+			ev.Actions.Escalate = true
+		}),
+		step("publish", func(agent.InvocationContext, *session.Event) {}),
+	}, "pipeline")
+
+	sessionService := session.InMemoryService()
+	agentRunner, err := runner.New(runner.Config{AppName: "test_app", Agent: sequentialAgent, SessionService: sessionService})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := sessionService.Create(t.Context(), &session.CreateRequest{AppName: "test_app", UserID: "user_id", SessionID: "session_id"}); err != nil {
+		t.Fatal(err)
+	}
+	for _, err := range agentRunner.Run(t.Context(), "user_id", "session_id", genai.NewContentFromText("go", genai.RoleUser), agent.RunConfig{}) {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	if seen != "v1" {
+		t.Errorf("review saw draft %v, want the state set by draft", seen)
+	}
+	// The escalation ends the sequence.
+	if diff := cmp.Diff([]string{"draft", "review"}, ran); diff != "" {
+		t.Errorf("sub-agents run mismatch (-want +got):\n%s", diff)
+	}
+}
+
 func newCustomAgent(t *testing.T, id int) agent.Agent {
 	t.Helper()
 
@@ -0,0 +1,279 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package fileartifact provides an [artifact.Service] storing the artifacts
+// in a local directory, e.g. for development or for a single server.
+//
+// The artifacts are organized as the ones of package gcsartifact: each
+// version is a file named
+//
+//	<dir>/<appName>/<userID>/<sessionID>/<fileName>/<version>
+//
+// with the user scoped artifacts, whose file name starts with "user:", under
+// "user" in place of the session ID. The path segments are escaped. The MIME
+// type of each version is stored next to it, in a file with the ".mime"
+// extension.
+package fileartifact
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/artifact"
+)
+
+const mimeExt = ".mime"
+
+// fileService is a local directory implementation of the Service.
+type fileService struct {
+	dir string
+	// mu serializes the allocation of the versions.
+	mu sync.Mutex
+}
+
+// NewService creates an artifact service storing the artifacts in the
+// directory, which is created if needed.
+func NewService(dir string) (artifact.Service, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create artifact directory: %w", err)
+	}
+	return &fileService{dir: dir}, nil
+}
+
+// fileHasUserNamespace checks if a filename indicates a user scoped artifact.
+func fileHasUserNamespace(filename string) bool {
+	return strings.HasPrefix(filename, "user:")
+}
+
+// escape returns the path segment of a name.
+func escape(name string) string {
+	s := url.PathEscape(name)
+	if s == "." || s == ".." {
+		s = strings.ReplaceAll(s, ".", "%2E")
+	}
+	return s
+}
+
+func (s *fileService) sessionDir(appName, userID, sessionID string) string {
+	return filepath.Join(s.dir, escape(appName), escape(userID), escape(sessionID))
+}
+
+func (s *fileService) artifactDir(appName, userID, sessionID, fileName string) string {
+	if fileHasUserNamespace(fileName) {
+		sessionID = "user"
+	}
+	return filepath.Join(s.sessionDir(appName, userID, sessionID), escape(fileName))
+}
+
+// Save implements [artifact.Service]
+func (s *fileService) Save(ctx context.Context, req *artifact.SaveRequest) (*artifact.SaveResponse, error) {
+	err := req.Validate()
+	if err != nil {
+		return nil, fmt.Errorf("request validation failed: %w", err)
+	}
+	dir := s.artifactDir(req.AppName, req.UserID, req.SessionID, req.FileName)
+
+	data, mimeType := []byte(req.Part.Text), "text/plain"
+	if req.Part.InlineData != nil {
+		data, mimeType = req.Part.InlineData.Data, req.Part.InlineData.MIMEType
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create artifact directory: %w", err)
+	}
+	versions, err := versions(dir)
+	if err != nil {
+		return nil, err
+	}
+	nextVersion := int64(1)
+	if len(versions) > 0 {
+		nextVersion = slices.Max(versions) + 1
+	}
+	// The version file is created exclusively, so that another process
+	// saving the same artifact cannot overwrite it.
+	for {
+		f, err := os.OpenFile(filepath.Join(dir, strconv.FormatInt(nextVersion, 10)), os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+		if errors.Is(err, fs.ErrExist) {
+			nextVersion++
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to create artifact file: %w", err)
+		}
+		_, err = f.Write(data)
+		if closeErr := f.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to write artifact file: %w", err)
+		}
+		break
+	}
+	if err := os.WriteFile(filepath.Join(dir, strconv.FormatInt(nextVersion, 10)+mimeExt), []byte(mimeType), 0o644); err != nil {
+		return nil, fmt.Errorf("failed to write artifact MIME type: %w", err)
+	}
+	return &artifact.SaveResponse{Version: nextVersion}, nil
+}
+
+// Delete implements [artifact.Service]
+func (s *fileService) Delete(ctx context.Context, req *artifact.DeleteRequest) error {
+	err := req.Validate()
+	if err != nil {
+		return fmt.Errorf("request validation failed: %w", err)
+	}
+	dir := s.artifactDir(req.AppName, req.UserID, req.SessionID, req.FileName)
+
+	if req.Version == 0 {
+		if err := os.RemoveAll(dir); err != nil {
+			return fmt.Errorf("failed to delete artifact: %w", err)
+		}
+		return nil
+	}
+	name := filepath.Join(dir, strconv.FormatInt(req.Version, 10))
+	for _, file := range []string{name, name + mimeExt} {
+		if err := os.Remove(file); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return fmt.Errorf("failed to delete artifact: %w", err)
+		}
+	}
+	return nil
+}
+
+// Load implements [artifact.Service]
+func (s *fileService) Load(ctx context.Context, req *artifact.LoadRequest) (*artifact.LoadResponse, error) {
+	err := req.Validate()
+	if err != nil {
+		return nil, fmt.Errorf("request validation failed: %w", err)
+	}
+	dir := s.artifactDir(req.AppName, req.UserID, req.SessionID, req.FileName)
+
+	version := req.Version
+	if version == 0 {
+		versions, err := versions(dir)
+		if err != nil {
+			return nil, err
+		}
+		if len(versions) == 0 {
+			return nil, fmt.Errorf("artifact not found: %w", fs.ErrNotExist)
+		}
+		version = slices.Max(versions)
+	}
+
+	name := filepath.Join(dir, strconv.FormatInt(version, 10))
+	data, err := os.ReadFile(name)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("artifact not found: %w", fs.ErrNotExist)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read artifact file: %w", err)
+	}
+	mimeType := "application/octet-stream"
+	if b, err := os.ReadFile(name + mimeExt); err == nil {
+		mimeType = string(b)
+	}
+	return &artifact.LoadResponse{Part: genai.NewPartFromBytes(data, mimeType)}, nil
+}
+
+// List implements [artifact.Service]
+func (s *fileService) List(ctx context.Context, req *artifact.ListRequest) (*artifact.ListResponse, error) {
+	err := req.Validate()
+	if err != nil {
+		return nil, fmt.Errorf("request validation failed: %w", err)
+	}
+
+	filenamesSet := map[string]bool{}
+	for _, sessionID := range []string{req.SessionID, "user"} {
+		entries, err := os.ReadDir(s.sessionDir(req.AppName, req.UserID, sessionID))
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to list artifacts: %w", err)
+		}
+		for _, entry := range entries {
+			name, err := url.PathUnescape(entry.Name())
+			if err != nil || !entry.IsDir() {
+				continue
+			}
+			// The session named "user" holds no user scoped artifacts.
+			if (sessionID == "user") != fileHasUserNamespace(name) {
+				continue
+			}
+			filenamesSet[name] = true
+		}
+	}
+
+	filenames := make([]string, 0, len(filenamesSet))
+	for name := range filenamesSet {
+		filenames = append(filenames, name)
+	}
+	sort.Strings(filenames)
+	return &artifact.ListResponse{FileNames: filenames}, nil
+}
+
+// Versions implements [artifact.Service] and returns an error if no versions are found.
+func (s *fileService) Versions(ctx context.Context, req *artifact.VersionsRequest) (*artifact.VersionsResponse, error) {
+	err := req.Validate()
+	if err != nil {
+		return nil, fmt.Errorf("request validation failed: %w", err)
+	}
+	versions, err := versions(s.artifactDir(req.AppName, req.UserID, req.SessionID, req.FileName))
+	if err != nil {
+		return nil, err
+	}
+	if len(versions) == 0 {
+		return nil, fmt.Errorf("artifact not found: %w", fs.ErrNotExist)
+	}
+	return &artifact.VersionsResponse{Versions: versions}, nil
+}
+
+// versions returns the versions stored in the artifact directory, the
+// latest first.
+func versions(dir string) ([]int64, error) {
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to list artifact versions: %w", err)
+	}
+	var versions []int64
+	for _, entry := range entries {
+		version, err := strconv.ParseInt(entry.Name(), 10, 64)
+		// ignore the files whose name is not a version
+		if err != nil || version <= 0 {
+			continue
+		}
+		versions = append(versions, version)
+	}
+	slices.Sort(versions)
+	slices.Reverse(versions)
+	return versions, nil
+}
+
+var _ artifact.Service = (*fileService)(nil)
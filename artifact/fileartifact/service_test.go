@@ -0,0 +1,67 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fileartifact_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/artifact"
+	"google.golang.org/adk/artifact/fileartifact"
+	"google.golang.org/adk/internal/artifact/tests"
+)
+
+func TestFileArtifactService(t *testing.T) {
+	factory := func(t *testing.T) (artifact.Service, error) {
+		return fileartifact.NewService(t.TempDir())
+	}
+	tests.TestArtifactService(t, "File", factory)
+}
+
+func TestFileArtifactService_EscapesNames(t *testing.T) {
+	ctx := t.Context()
+	dir := t.TempDir()
+	srv, err := fileartifact.NewService(filepath.Join(dir, "artifacts"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	part := genai.NewPartFromBytes([]byte("secret"), "text/plain")
+	if _, err := srv.Save(ctx, &artifact.SaveRequest{AppName: "app", UserID: "..", SessionID: "s", FileName: "../../../escaped", Part: part}); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "escaped")); err == nil {
+		t.Errorf("Save() wrote outside of the artifact directory")
+	}
+
+	list, err := srv.List(ctx, &artifact.ListRequest{AppName: "app", UserID: "..", SessionID: "s"})
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if diff := cmp.Diff([]string{"../../../escaped"}, list.FileNames); diff != "" {
+		t.Errorf("List() mismatch (-want +got):\n%s", diff)
+	}
+	got, err := srv.Load(ctx, &artifact.LoadRequest{AppName: "app", UserID: "..", SessionID: "s", FileName: "../../../escaped"})
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if diff := cmp.Diff(part, got.Part); diff != "" {
+		t.Errorf("Load() mismatch (-want +got):\n%s", diff)
+	}
+}
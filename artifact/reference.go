@@ -0,0 +1,71 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package artifact
+
+import (
+	"net/url"
+	"strconv"
+
+	"google.golang.org/genai"
+)
+
+// ReferenceScheme is the URI scheme of artifact references.
+const ReferenceScheme = "artifact"
+
+// NewReference returns a part referencing a version of an artifact of the
+// current session. The reference is a genai.FileData part with a URI of the
+// form
+//
+//	artifact:<escaped file name>?version=<version>
+//
+// and the MIME type of the artifact. It is used in place of large inline
+// data, see agent.RunConfig.MaxInlinePartSize, and is resolved back into the
+// artifact content when the LLM request is built.
+func NewReference(fileName string, version int64, mimeType string) *genai.Part {
+	u := url.URL{
+		Scheme:   ReferenceScheme,
+		Opaque:   url.PathEscape(fileName),
+		RawQuery: url.Values{"version": {strconv.FormatInt(version, 10)}}.Encode(),
+	}
+	return &genai.Part{
+		FileData: &genai.FileData{
+			FileURI:     u.String(),
+			MIMEType:    mimeType,
+			DisplayName: fileName,
+		},
+	}
+}
+
+// ParseReference returns the artifact file name and version referenced by a
+// part created with [NewReference]. It reports false if the part is not an
+// artifact reference.
+func ParseReference(part *genai.Part) (fileName string, version int64, ok bool) {
+	if part == nil || part.FileData == nil {
+		return "", 0, false
+	}
+	u, err := url.Parse(part.FileData.FileURI)
+	if err != nil || u.Scheme != ReferenceScheme || u.Opaque == "" {
+		return "", 0, false
+	}
+	fileName, err = url.PathUnescape(u.Opaque)
+	if err != nil {
+		return "", 0, false
+	}
+	version, err = strconv.ParseInt(u.Query().Get("version"), 10, 64)
+	if err != nil {
+		return "", 0, false
+	}
+	return fileName, version, true
+}
@@ -0,0 +1,34 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package artifact_test
+
+import (
+	"testing"
+
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/artifact"
+)
+
+func TestArtifactReference(t *testing.T) {
+	part := artifact.NewReference("my file/1.png", 3, "image/png")
+	fileName, version, ok := artifact.ParseReference(part)
+	if !ok || fileName != "my file/1.png" || version != 3 {
+		t.Errorf("ParseReference(%q) = %q, %d, %v, want %q, 3, true", part.FileData.FileURI, fileName, version, ok, "my file/1.png")
+	}
+	if _, _, ok := artifact.ParseReference(&genai.Part{FileData: &genai.FileData{FileURI: "gs://bucket/file.png"}}); ok {
+		t.Error("ParseReference() of a gs:// URI reported an artifact reference")
+	}
+}
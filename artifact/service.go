@@ -17,6 +17,19 @@
 // An artifact is a file identified by an application name, a user ID, a session ID,
 // and a filename. The service provides basic storage operations for artifacts,
 // such as Save, Load, Delete, and List. It also supports versioning of artifacts.
+//
+// # Versions
+//
+// Each Save stores a new version of the artifact, numbered from 1, and
+// returns it. Load returns the latest version unless a version is requested,
+// and Versions lists the versions stored.
+//
+// # User scoped artifacts
+//
+// The artifacts whose filename starts with "user:", e.g. "user:report.pdf",
+// belong to the user rather than to the session: they are shared by all the
+// sessions of the user in the app, whatever the session ID of the requests,
+// and are listed with the artifacts of each session.
 package artifact
 
 import (
@@ -0,0 +1,162 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package auth defines the credentials that tools request from the user,
+// e.g. the OAuth2 tokens of the external APIs they call.
+//
+// A tool handler requests a credential with tool.Context.RequestCredential.
+// Once the call returned, the agent emits an event with a long-running
+// function call named RequestCredentialFunctionName, whose arguments are a
+// CredentialRequest, and the invocation ends. The client authenticates the
+// user, e.g. with the OAuth2 authorization flow, and sends a new message
+// with the function response of that call: the AuthConfig of the request
+// with ExchangedAuthCredential set. The agent then stores the credential in
+// the session state and runs the original function call again, in which the
+// handler gets the credential with tool.Context.GetAuthResponse.
+//
+// For the OAuth2 schemes, the request carries the authorization URI the user
+// visits, see GenerateAuthURI, and the client may send the authorization
+// code, or the URI the user was redirected to, rather than the tokens: the
+// agent exchanges it, see ExchangeCredential. The expired access tokens are
+// refreshed by GetAuthResponse, see RefreshCredential.
+package auth
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+)
+
+// RequestCredentialFunctionName is the name of the function call of the
+// events requesting a credential.
+const RequestCredentialFunctionName = "adk_request_credential"
+
+// CredentialType is the type of an authentication scheme or credential.
+type CredentialType string
+
+// Types of the authentication schemes and credentials.
+const (
+	APIKey        CredentialType = "apiKey"
+	HTTP          CredentialType = "http"
+	OAuth2        CredentialType = "oauth2"
+	OpenIDConnect CredentialType = "openIdConnect"
+)
+
+// AuthScheme describes how a tool authenticates to an API, as the security
+// schemes of OpenAPI.
+type AuthScheme struct {
+	Type CredentialType `json:"type"`
+	// Description of the scheme, e.g. shown to the user.
+	Description string `json:"description,omitempty"`
+
+	// For APIKey, the name of the header, query parameter or cookie, and
+	// where it goes: "header", "query" or "cookie".
+	Name string `json:"name,omitempty"`
+	In   string `json:"in,omitempty"`
+
+	// For HTTP, the scheme of the Authorization header, e.g. "bearer".
+	Scheme string `json:"scheme,omitempty"`
+
+	// For OAuth2 and OpenIDConnect, the endpoints of the authorization code
+	// flow and the requested scopes.
+	AuthorizationURL string   `json:"authorization_url,omitempty"`
+	TokenURL         string   `json:"token_url,omitempty"`
+	Scopes           []string `json:"scopes,omitempty"`
+}
+
+// AuthCredential is a credential of an AuthScheme. Only the field of its
+// type is set.
+type AuthCredential struct {
+	AuthType CredentialType `json:"auth_type"`
+	APIKey   string         `json:"api_key,omitempty"`
+	HTTP     *HTTPAuth      `json:"http,omitempty"`
+	OAuth2   *OAuth2Auth    `json:"oauth2,omitempty"`
+}
+
+// HTTPAuth is the credential of the HTTP schemes.
+type HTTPAuth struct {
+	// Token is the token of the bearer scheme.
+	Token    string `json:"token,omitempty"`
+	Username string `json:"username,omitempty"`
+	Password string `json:"password,omitempty"`
+}
+
+// OAuth2Auth is the credential of the OAuth2 and OpenIDConnect schemes: the
+// client credentials, set by the tool, and the state of the authorization
+// flow and the tokens, set by the client.
+type OAuth2Auth struct {
+	ClientID     string `json:"client_id,omitempty"`
+	ClientSecret string `json:"client_secret,omitempty"`
+	RedirectURI  string `json:"redirect_uri,omitempty"`
+
+	// AuthURI is the URI the user visits to authorize the access, and State
+	// the state parameter of the flow.
+	AuthURI string `json:"auth_uri,omitempty"`
+	State   string `json:"state,omitempty"`
+	// AuthResponseURI is the URI the user was redirected to, with AuthCode.
+	AuthResponseURI string `json:"auth_response_uri,omitempty"`
+	AuthCode        string `json:"auth_code,omitempty"`
+
+	AccessToken  string `json:"access_token,omitempty"`
+	RefreshToken string `json:"refresh_token,omitempty"`
+	// ExpiresAt is the expiry time of the access token, in seconds since the
+	// Unix epoch, or 0 if unknown.
+	ExpiresAt int64 `json:"expires_at,omitempty"`
+}
+
+// AuthConfig is the credential requested by a tool.
+type AuthConfig struct {
+	AuthScheme *AuthScheme `json:"auth_scheme"`
+	// RawAuthCredential is the credential the client starts from, e.g. with
+	// the OAuth2 client ID and secret. Optional.
+	RawAuthCredential *AuthCredential `json:"raw_auth_credential,omitempty"`
+	// ExchangedAuthCredential is the credential obtained by the client, e.g.
+	// with the OAuth2 access token.
+	ExchangedAuthCredential *AuthCredential `json:"exchanged_auth_credential,omitempty"`
+	// CredentialKey is the key of the credential in the session state,
+	// derived from the scheme and the raw credential by default. It may have
+	// a prefix of the session state keys, e.g. "user:" to share the
+	// credential across the sessions of the user, or "temp:" not to keep it
+	// after the invocation.
+	CredentialKey string `json:"credential_key,omitempty"`
+}
+
+// Key returns the key of the credential in the session state.
+func (c *AuthConfig) Key() (string, error) {
+	if c.CredentialKey != "" {
+		return c.CredentialKey, nil
+	}
+	if c.AuthScheme == nil {
+		return "", fmt.Errorf("auth config has no auth scheme")
+	}
+	b, err := json.Marshal(struct {
+		Scheme *AuthScheme     `json:"scheme"`
+		Raw    *AuthCredential `json:"raw,omitempty"`
+	}{c.AuthScheme, c.RawAuthCredential})
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(b)
+	return fmt.Sprintf("adk_credential_%s_%s", c.AuthScheme.Type, hex.EncodeToString(sum[:8])), nil
+}
+
+// CredentialRequest is the arguments of the function calls requesting a
+// credential.
+type CredentialRequest struct {
+	// FunctionCallID is the ID of the function call whose handler requested
+	// the credential.
+	FunctionCallID string      `json:"function_call_id"`
+	AuthConfig     *AuthConfig `json:"auth_config"`
+}
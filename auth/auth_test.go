@@ -0,0 +1,196 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auth_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"google.golang.org/adk/auth"
+)
+
+func TestAuthConfig_Key(t *testing.T) {
+	newConfig := func(clientID string) *auth.AuthConfig {
+		return &auth.AuthConfig{
+			AuthScheme:        &auth.AuthScheme{Type: auth.OAuth2, TokenURL: "https://example.com/token"},
+			RawAuthCredential: &auth.AuthCredential{AuthType: auth.OAuth2, OAuth2: &auth.OAuth2Auth{ClientID: clientID}},
+		}
+	}
+	key := func(cfg *auth.AuthConfig) string {
+		t.Helper()
+		k, err := cfg.Key()
+		if err != nil {
+			t.Fatalf("Key() error = %v", err)
+		}
+		return k
+	}
+
+	k1 := key(newConfig("client1"))
+	if !strings.HasPrefix(k1, "adk_credential_oauth2_") {
+		t.Errorf("Key() = %q, want a key derived from the oauth2 scheme", k1)
+	}
+	if k := key(newConfig("client1")); k != k1 {
+		t.Errorf("Key() of the same config = %q, want %q", k, k1)
+	}
+	if k := key(newConfig("client2")); k == k1 {
+		t.Errorf("Key() of another client = %q, want another key", k)
+	}
+	// The exchanged credential does not change the key.
+	cfg := newConfig("client1")
+	cfg.ExchangedAuthCredential = &auth.AuthCredential{AuthType: auth.OAuth2, OAuth2: &auth.OAuth2Auth{AccessToken: "token"}}
+	if k := key(cfg); k != k1 {
+		t.Errorf("Key() with an exchanged credential = %q, want %q", k, k1)
+	}
+	cfg.CredentialKey = "user:calendar"
+	if k := key(cfg); k != "user:calendar" {
+		t.Errorf("Key() = %q, want the credential key", k)
+	}
+	if _, err := (&auth.AuthConfig{}).Key(); err == nil {
+		t.Error("Key() without scheme succeeded, want error")
+	}
+}
+
+// newTokenServer returns a token endpoint exchanging the code "code1" and
+// the refresh token "refresh1" of the client "client" for tokens.
+func newTokenServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if id, secret, _ := r.BasicAuth(); id != "client" || secret != "secret" {
+			http.Error(w, `{"error":"invalid_client"}`, http.StatusUnauthorized)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.FormValue("grant_type") == "authorization_code" && r.FormValue("code") == "code1":
+			fmt.Fprint(w, `{"access_token":"token1","refresh_token":"refresh1","token_type":"bearer","expires_in":3600}`)
+		case r.FormValue("grant_type") == "refresh_token" && r.FormValue("refresh_token") == "refresh1":
+			fmt.Fprint(w, `{"access_token":"token2","token_type":"bearer","expires_in":3600}`)
+		default:
+			http.Error(w, `{"error":"invalid_grant"}`, http.StatusBadRequest)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestGenerateAuthURI(t *testing.T) {
+	cfg := &auth.AuthConfig{
+		AuthScheme: &auth.AuthScheme{
+			Type:             auth.OAuth2,
+			AuthorizationURL: "https://example.com/authorize",
+			TokenURL:         "https://example.com/token",
+			Scopes:           []string{"calendar"},
+		},
+		RawAuthCredential: &auth.AuthCredential{AuthType: auth.OAuth2, OAuth2: &auth.OAuth2Auth{
+			ClientID:     "client",
+			ClientSecret: "secret",
+			RedirectURI:  "https://app.example.com/callback",
+		}},
+	}
+	got, err := auth.GenerateAuthURI(cfg)
+	if err != nil {
+		t.Fatalf("GenerateAuthURI() error = %v", err)
+	}
+	ex := got.ExchangedAuthCredential.OAuth2
+	if ex.State == "" || ex.ClientSecret != "" {
+		t.Errorf("exchanged credential = %+v, want a state and no client secret", ex)
+	}
+	u, err := url.Parse(ex.AuthURI)
+	if err != nil {
+		t.Fatal(err)
+	}
+	q := u.Query()
+	if u.Host != "example.com" || q.Get("client_id") != "client" || q.Get("state") != ex.State ||
+		q.Get("redirect_uri") != "https://app.example.com/callback" || q.Get("scope") != "calendar" || q.Get("access_type") != "offline" {
+		t.Errorf("AuthURI = %s, want the authorization URL with the parameters of the flow", ex.AuthURI)
+	}
+	if cfg.ExchangedAuthCredential != nil {
+		t.Error("GenerateAuthURI() modified the config")
+	}
+
+	// The configs without client are returned as is.
+	apiKey := &auth.AuthConfig{AuthScheme: &auth.AuthScheme{Type: auth.APIKey}}
+	if got, err := auth.GenerateAuthURI(apiKey); err != nil || got != apiKey {
+		t.Errorf("GenerateAuthURI() of an API key = %v, %v, want the config", got, err)
+	}
+}
+
+func TestExchangeCredential(t *testing.T) {
+	server := newTokenServer(t)
+	newConfig := func(ex *auth.OAuth2Auth) *auth.AuthConfig {
+		return &auth.AuthConfig{
+			AuthScheme:              &auth.AuthScheme{Type: auth.OAuth2, AuthorizationURL: server.URL + "/authorize", TokenURL: server.URL + "/token"},
+			RawAuthCredential:       &auth.AuthCredential{AuthType: auth.OAuth2, OAuth2: &auth.OAuth2Auth{ClientID: "client", ClientSecret: "secret"}},
+			ExchangedAuthCredential: &auth.AuthCredential{AuthType: auth.OAuth2, OAuth2: ex},
+		}
+	}
+	for _, tc := range []struct {
+		name      string
+		ex        *auth.OAuth2Auth
+		wantToken string
+		wantErr   bool
+	}{
+		{name: "access token", ex: &auth.OAuth2Auth{AccessToken: "token0"}, wantToken: "token0"},
+		{name: "code", ex: &auth.OAuth2Auth{AuthCode: "code1"}, wantToken: "token1"},
+		{name: "response URI", ex: &auth.OAuth2Auth{State: "s1", AuthResponseURI: "https://app.example.com/callback?code=code1&state=s1"}, wantToken: "token1"},
+		{name: "state mismatch", ex: &auth.OAuth2Auth{State: "s1", AuthResponseURI: "https://app.example.com/callback?code=code1&state=s2"}, wantErr: true},
+		{name: "denied", ex: &auth.OAuth2Auth{AuthResponseURI: "https://app.example.com/callback?error=access_denied"}, wantErr: true},
+		{name: "invalid code", ex: &auth.OAuth2Auth{AuthCode: "code2"}, wantErr: true},
+		{name: "no code", ex: &auth.OAuth2Auth{}, wantErr: true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			cred, err := auth.ExchangeCredential(t.Context(), newConfig(tc.ex))
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("ExchangeCredential() error = %v, wantErr %v", err, tc.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if cred.OAuth2.AccessToken != tc.wantToken {
+				t.Errorf("access token = %q, want %q", cred.OAuth2.AccessToken, tc.wantToken)
+			}
+			if tc.wantToken == "token1" && (cred.OAuth2.RefreshToken != "refresh1" || cred.OAuth2.ExpiresAt == 0 || cred.OAuth2.ClientSecret != "") {
+				t.Errorf("exchanged credential = %+v, want the refresh token and expiry, without client secret", cred.OAuth2)
+			}
+		})
+	}
+}
+
+func TestRefreshCredential(t *testing.T) {
+	server := newTokenServer(t)
+	scheme := &auth.AuthScheme{Type: auth.OAuth2, TokenURL: server.URL + "/token"}
+	raw := &auth.AuthCredential{AuthType: auth.OAuth2, OAuth2: &auth.OAuth2Auth{ClientID: "client", ClientSecret: "secret"}}
+	newCred := func(expiresAt time.Time) *auth.AuthCredential {
+		return &auth.AuthCredential{AuthType: auth.OAuth2, OAuth2: &auth.OAuth2Auth{AccessToken: "token1", RefreshToken: "refresh1", ExpiresAt: expiresAt.Unix()}}
+	}
+
+	valid := newCred(time.Now().Add(time.Hour))
+	if got, refreshed, err := auth.RefreshCredential(t.Context(), scheme, raw, valid); err != nil || refreshed || got != valid {
+		t.Errorf("RefreshCredential() of a valid token = %v, %v, %v, want the credential", got, refreshed, err)
+	}
+	got, refreshed, err := auth.RefreshCredential(t.Context(), scheme, raw, newCred(time.Now().Add(-time.Minute)))
+	if err != nil || !refreshed {
+		t.Fatalf("RefreshCredential() of an expired token = %v, %v, want refreshed", refreshed, err)
+	}
+	// The refresh token is kept.
+	if got.OAuth2.AccessToken != "token2" || got.OAuth2.RefreshToken != "refresh1" || got.OAuth2.ExpiresAt < time.Now().Unix() {
+		t.Errorf("refreshed credential = %+v, want the new access token", got.OAuth2)
+	}
+}
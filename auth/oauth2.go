@@ -0,0 +1,174 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+
+	"golang.org/x/oauth2"
+)
+
+// GenerateAuthURI returns the config with the authorization URI the user
+// visits to authorize the access, and the state of the flow, set in its
+// ExchangedAuthCredential, for the OAuth2 and OpenIDConnect schemes with an
+// authorization URL and a raw credential with a client ID. The agents call it
+// before requesting the credential from the client, so that the client only
+// has to send the user to AuthURI. The other configs, and those already
+// having an authorization URI, are returned as is.
+func GenerateAuthURI(cfg *AuthConfig) (*AuthConfig, error) {
+	if !isOAuth2(cfg.AuthScheme) || cfg.AuthScheme.AuthorizationURL == "" ||
+		cfg.RawAuthCredential == nil || cfg.RawAuthCredential.OAuth2 == nil || cfg.RawAuthCredential.OAuth2.ClientID == "" {
+		return cfg, nil
+	}
+	if ex := cfg.ExchangedAuthCredential; ex != nil && ex.OAuth2 != nil && ex.OAuth2.AuthURI != "" {
+		return cfg, nil
+	}
+	state := make([]byte, 16)
+	if _, err := rand.Read(state); err != nil {
+		return nil, fmt.Errorf("failed to generate the state of the OAuth2 flow: %w", err)
+	}
+	raw := cfg.RawAuthCredential.OAuth2
+	exchanged := *raw
+	exchanged.ClientSecret = ""
+	exchanged.State = hex.EncodeToString(state)
+	// The refresh token is requested, so that the credential outlives the
+	// access token, see RefreshCredential.
+	exchanged.AuthURI = oauth2Config(cfg.AuthScheme, raw).AuthCodeURL(exchanged.State, oauth2.AccessTypeOffline)
+
+	withURI := *cfg
+	withURI.ExchangedAuthCredential = &AuthCredential{AuthType: cfg.RawAuthCredential.AuthType, OAuth2: &exchanged}
+	return &withURI, nil
+}
+
+// ExchangeCredential returns the credential of the config with its tokens:
+// for the OAuth2 and OpenIDConnect schemes, the authorization code of
+// ExchangedAuthCredential, or the code in the URI the user was redirected to,
+// is exchanged for tokens at the token URL of the scheme, with the client
+// credentials of RawAuthCredential. The other credentials, and those already
+// having an access token, are returned as is.
+//
+// The agents call it with the credentials sent by the clients, so that a
+// client may send the redirect URI of the user without handling the token
+// exchange itself. The state of the redirect URI, if any, must be the state
+// of the flow.
+func ExchangeCredential(ctx context.Context, cfg *AuthConfig) (*AuthCredential, error) {
+	cred := cfg.ExchangedAuthCredential
+	if cred == nil {
+		return nil, errors.New("auth config has no exchanged credential")
+	}
+	if !isOAuth2(cfg.AuthScheme) || cred.OAuth2 == nil || cred.OAuth2.AccessToken != "" {
+		return cred, nil
+	}
+	ex := *cred.OAuth2
+	code := ex.AuthCode
+	if code == "" && ex.AuthResponseURI != "" {
+		u, err := url.Parse(ex.AuthResponseURI)
+		if err != nil {
+			return nil, fmt.Errorf("invalid auth response URI: %w", err)
+		}
+		q := u.Query()
+		if e := q.Get("error"); e != "" {
+			return nil, fmt.Errorf("the authorization failed: %s", e)
+		}
+		if state := q.Get("state"); state != "" && ex.State != "" && state != ex.State {
+			return nil, errors.New("the state of the auth response URI is not the state of the flow")
+		}
+		code = q.Get("code")
+	}
+	if code == "" {
+		return nil, errors.New("the OAuth2 credential has no access token nor authorization code")
+	}
+	if cfg.AuthScheme.TokenURL == "" {
+		return nil, errors.New("the OAuth2 scheme has no token URL to exchange the authorization code")
+	}
+
+	client := ex
+	if raw := cfg.RawAuthCredential; raw != nil && raw.OAuth2 != nil {
+		// The client credentials are kept by the agent, not the client.
+		client.ClientID, client.ClientSecret = raw.OAuth2.ClientID, raw.OAuth2.ClientSecret
+		if client.RedirectURI == "" {
+			client.RedirectURI = raw.OAuth2.RedirectURI
+		}
+	}
+	token, err := oauth2Config(cfg.AuthScheme, &client).Exchange(ctx, code)
+	if err != nil {
+		return nil, fmt.Errorf("failed to exchange the authorization code: %w", err)
+	}
+	setToken(&ex, token)
+	ex.AuthCode, ex.ClientSecret = "", ""
+	return &AuthCredential{AuthType: cred.AuthType, OAuth2: &ex}, nil
+}
+
+// RefreshCredential returns the credential with a new access token if its
+// access token expired, or expires within a minute, and it has a refresh
+// token. It reports whether the credential was refreshed; the other
+// credentials are returned as is. The client credentials are those of raw,
+// if any, or of the credential.
+func RefreshCredential(ctx context.Context, scheme *AuthScheme, raw, cred *AuthCredential) (*AuthCredential, bool, error) {
+	if !isOAuth2(scheme) || cred == nil || cred.OAuth2 == nil || cred.OAuth2.RefreshToken == "" || scheme.TokenURL == "" {
+		return cred, false, nil
+	}
+	ex := *cred.OAuth2
+	if ex.ExpiresAt == 0 || time.Until(time.Unix(ex.ExpiresAt, 0)) > time.Minute {
+		return cred, false, nil
+	}
+	client := ex
+	if raw != nil && raw.OAuth2 != nil {
+		client.ClientID, client.ClientSecret = raw.OAuth2.ClientID, raw.OAuth2.ClientSecret
+	}
+	expired := &oauth2.Token{RefreshToken: ex.RefreshToken, Expiry: time.Unix(ex.ExpiresAt, 0)}
+	token, err := oauth2Config(scheme, &client).TokenSource(ctx, expired).Token()
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to refresh the access token: %w", err)
+	}
+	setToken(&ex, token)
+	return &AuthCredential{AuthType: cred.AuthType, OAuth2: &ex}, true, nil
+}
+
+func isOAuth2(scheme *AuthScheme) bool {
+	return scheme != nil && (scheme.Type == OAuth2 || scheme.Type == OpenIDConnect)
+}
+
+func oauth2Config(scheme *AuthScheme, cred *OAuth2Auth) *oauth2.Config {
+	return &oauth2.Config{
+		ClientID:     cred.ClientID,
+		ClientSecret: cred.ClientSecret,
+		RedirectURL:  cred.RedirectURI,
+		Scopes:       scheme.Scopes,
+		Endpoint: oauth2.Endpoint{
+			AuthURL:  scheme.AuthorizationURL,
+			TokenURL: scheme.TokenURL,
+		},
+	}
+}
+
+// setToken sets the tokens of the credential. A refresh token is kept if the
+// token has none.
+func setToken(cred *OAuth2Auth, token *oauth2.Token) {
+	cred.AccessToken = token.AccessToken
+	if token.RefreshToken != "" {
+		cred.RefreshToken = token.RefreshToken
+	}
+	cred.ExpiresAt = 0
+	if !token.Expiry.IsZero() {
+		cred.ExpiresAt = token.Expiry.Unix()
+	}
+}
@@ -12,12 +12,18 @@
 // See the License for the specific language governing permissions and
 // limitations under the License.
 
-// adkgo is a CLI tool to help deploy and test an ADK application.
+// adkgo is a CLI tool to help deploy and test an ADK application: it chats
+// with the agents of agent configs in the terminal (run), scores them against
+// eval sets (eval), serves them over the ADK REST API with the ADK Web UI
+// (serve), and deploys applications (deploy).
 package main
 
 import (
 	_ "google.golang.org/adk/cmd/adkgo/internal/deploy/cloudrun"
+	_ "google.golang.org/adk/cmd/adkgo/internal/eval"
 	"google.golang.org/adk/cmd/adkgo/internal/root"
+	_ "google.golang.org/adk/cmd/adkgo/internal/run"
+	_ "google.golang.org/adk/cmd/adkgo/internal/serve"
 )
 
 func main() {
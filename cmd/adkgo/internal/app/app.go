@@ -0,0 +1,131 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package app loads the agents of the run, eval and serve commands from their
+// agent configs, see package agentconfig.
+package app
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/glebarez/sqlite"
+	"github.com/spf13/cobra"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	"google.golang.org/adk/agent"
+	"google.golang.org/adk/agent/agentconfig"
+	"google.golang.org/adk/artifact"
+	"google.golang.org/adk/cmd/launcher"
+	"google.golang.org/adk/model"
+	"google.golang.org/adk/model/registry"
+	"google.golang.org/adk/session"
+	"google.golang.org/adk/session/database"
+)
+
+// RootConfigFile is the config file of the root agent of an agent directory.
+const RootConfigFile = "root_agent.json"
+
+// Flags are the flags shared by the commands running agents.
+type Flags struct {
+	// Model, if set, replaces the models of the agent configs, e.g.
+	// "openai/gpt-4o", see package registry.
+	Model string
+	// SessionDB, if set, is the SQLite database file storing the sessions.
+	// By default, the sessions are kept in memory.
+	SessionDB string
+}
+
+// AddModelFlag adds the flag of the Model to the command.
+func (f *Flags) AddModelFlag(cmd *cobra.Command) {
+	cmd.Flags().StringVarP(&f.Model, "model", "m", "", `Model of all the agents, overriding the models of the configs, e.g. "gemini-2.5-flash" or "openai/gpt-4o"`)
+}
+
+// AddFlags adds all the flags to the command.
+func (f *Flags) AddFlags(cmd *cobra.Command) {
+	f.AddModelFlag(cmd)
+	cmd.Flags().StringVar(&f.SessionDB, "session_db", "", "SQLite database file storing the sessions, kept in memory if not specified")
+}
+
+// LoadAgent loads the agent tree of a path: an agent config file, or a
+// directory with the config of the root agent in RootConfigFile.
+func (f *Flags) LoadAgent(ctx context.Context, path string) (agent.Agent, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("cannot load agent: %w", err)
+	}
+	if info.IsDir() {
+		path = filepath.Join(path, RootConfigFile)
+	}
+	var reg agentconfig.Registry
+	if f.Model != "" {
+		reg.Model = func(ctx context.Context, name string) (model.LLM, error) {
+			return registry.NewModel(ctx, f.Model)
+		}
+	}
+	return agentconfig.Load(ctx, path, &reg)
+}
+
+// SessionService returns the session service of the flags.
+func (f *Flags) SessionService() (session.Service, error) {
+	if f.SessionDB == "" {
+		return session.InMemoryService(), nil
+	}
+	service, err := database.NewSessionService(sqlite.Open(f.SessionDB), &gorm.Config{
+		// The lookups of the sessions and states not stored yet are expected.
+		Logger: logger.New(log.Default(), logger.Config{
+			SlowThreshold:             200 * time.Millisecond,
+			LogLevel:                  logger.Warn,
+			IgnoreRecordNotFoundError: true,
+		}),
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := database.AutoMigrate(service); err != nil {
+		return nil, err
+	}
+	return service, nil
+}
+
+// Config returns the launcher config of the agents of the paths, the first
+// one being the root agent.
+func (f *Flags) Config(ctx context.Context, paths ...string) (*launcher.Config, error) {
+	var agents []agent.Agent
+	for _, path := range paths {
+		a, err := f.LoadAgent(ctx, path)
+		if err != nil {
+			return nil, err
+		}
+		agents = append(agents, a)
+	}
+	loader, err := agent.NewMultiLoader(agents[0], agents[1:]...)
+	if err != nil {
+		return nil, err
+	}
+	sessionService, err := f.SessionService()
+	if err != nil {
+		return nil, err
+	}
+	return &launcher.Config{
+		AgentLoader:     loader,
+		SessionService:  sessionService,
+		ArtifactService: artifact.InMemoryService(),
+	}, nil
+}
@@ -0,0 +1,72 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package eval provides the eval command, scoring an agent against eval sets.
+package eval
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"google.golang.org/adk/cmd/adkgo/internal/app"
+	"google.golang.org/adk/cmd/adkgo/internal/root"
+	adkeval "google.golang.org/adk/eval"
+)
+
+var flags app.Flags
+
+// evalCmd represents the eval command
+var evalCmd = &cobra.Command{
+	Use:   "eval AGENT EVAL_SET...",
+	Short: "Scores an agent against eval sets.",
+	Long: `Runs the eval cases of the eval set files against an agent, and reports their scores, see package eval.
+The command fails if a case fails the default criteria: the exact tool trajectory, and a response match score of at least 0.8.
+
+AGENT is an agent config file, or a directory with the config of the root agent in ` + app.RootConfigFile + `.`,
+	Args: cobra.MinimumNArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+		a, err := flags.LoadAgent(ctx, args[0])
+		if err != nil {
+			return err
+		}
+		failed := 0
+		for _, path := range args[1:] {
+			set, err := adkeval.LoadEvalSet(path)
+			if err != nil {
+				return err
+			}
+			report, err := adkeval.Run(ctx, adkeval.Config{Agent: a}, set)
+			if err != nil {
+				return fmt.Errorf("eval set %s: %w", path, err)
+			}
+			fmt.Fprint(cmd.OutOrStdout(), report)
+			if !report.Passed {
+				failed++
+			}
+		}
+		if failed > 0 {
+			return fmt.Errorf("%d of %d eval sets failed", failed, len(args)-1)
+		}
+		return nil
+	},
+}
+
+// init creates flags and adds subcommand to parent
+func init() {
+	root.RootCmd.AddCommand(evalCmd)
+
+	flags.AddModelFlag(evalCmd)
+}
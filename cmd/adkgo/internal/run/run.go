@@ -0,0 +1,60 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package run provides the run command, chatting with an agent in the
+// terminal.
+package run
+
+import (
+	"github.com/spf13/cobra"
+
+	"google.golang.org/adk/agent"
+	"google.golang.org/adk/cmd/adkgo/internal/app"
+	"google.golang.org/adk/cmd/adkgo/internal/root"
+	"google.golang.org/adk/cmd/launcher/console"
+)
+
+var (
+	flags         app.Flags
+	streamingMode string
+)
+
+// runCmd represents the run command
+var runCmd = &cobra.Command{
+	Use:   "run AGENT",
+	Short: "Chats with an agent in the terminal.",
+	Long: `Runs an interactive chat with an agent in the terminal.
+
+AGENT is an agent config file, or a directory with the config of the root agent in ` + app.RootConfigFile + `.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		config, err := flags.Config(cmd.Context(), args[0])
+		if err != nil {
+			return err
+		}
+		l := console.NewLauncher()
+		if _, err := l.Parse([]string{"-streaming_mode", streamingMode}); err != nil {
+			return err
+		}
+		return l.Run(cmd.Context(), config)
+	},
+}
+
+// init creates flags and adds subcommand to parent
+func init() {
+	root.RootCmd.AddCommand(runCmd)
+
+	flags.AddFlags(runCmd)
+	runCmd.Flags().StringVar(&streamingMode, "streaming_mode", string(agent.StreamingModeSSE), "Streaming mode (none|sse)")
+}
@@ -0,0 +1,70 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package serve provides the serve command, serving agents over the ADK REST
+// API, with the ADK Web UI.
+package serve
+
+import (
+	"strconv"
+
+	"github.com/spf13/cobra"
+
+	"google.golang.org/adk/cmd/adkgo/internal/app"
+	"google.golang.org/adk/cmd/adkgo/internal/root"
+	"google.golang.org/adk/cmd/launcher/web"
+	"google.golang.org/adk/cmd/launcher/web/api"
+	"google.golang.org/adk/cmd/launcher/web/webui"
+)
+
+var (
+	flags app.Flags
+	port  int
+	noUI  bool
+)
+
+// serveCmd represents the serve command
+var serveCmd = &cobra.Command{
+	Use:   "serve AGENT...",
+	Short: "Serves agents over the ADK REST API, with the ADK Web UI.",
+	Long: `Serves agents over the ADK REST API at /api/, and the ADK Web UI at /ui/.
+
+Each AGENT is an agent config file, or a directory with the config of the root agent in ` + app.RootConfigFile + `. The apps of the API are the names of the agents.`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		config, err := flags.Config(cmd.Context(), args...)
+		if err != nil {
+			return err
+		}
+		address := "localhost:" + strconv.Itoa(port)
+		webArgs := []string{"-port", strconv.Itoa(port), "api", "-webui_address", address}
+		if !noUI {
+			webArgs = append(webArgs, "webui", "-api_server_address", "http://"+address+"/api")
+		}
+		l := web.NewLauncher(api.NewLauncher(), webui.NewLauncher())
+		if _, err := l.Parse(webArgs); err != nil {
+			return err
+		}
+		return l.Run(cmd.Context(), config)
+	},
+}
+
+// init creates flags and adds subcommand to parent
+func init() {
+	root.RootCmd.AddCommand(serveCmd)
+
+	flags.AddFlags(serveCmd)
+	serveCmd.Flags().IntVarP(&port, "port", "p", 8080, "Localhost port of the server")
+	serveCmd.Flags().BoolVar(&noUI, "no_ui", false, "Serve the ADK REST API only, without the ADK Web UI")
+}
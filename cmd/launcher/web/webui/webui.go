@@ -22,6 +22,7 @@ import (
 	"io/fs"
 	"log"
 	"net/http"
+	"strings"
 
 	"github.com/gorilla/mux"
 
@@ -88,29 +89,41 @@ var content embed.FS
 
 // AddSubrouter adds a subrouter to serve the ADK Web UI.
 func (w *webUILauncher) AddSubrouter(router *mux.Router, pathPrefix, backendAddress string) {
-	// Setup serving of ADK Web UI
-	rUI := router.Methods("GET").PathPrefix(pathPrefix).Subrouter()
-
-	//   generate /assets/config/runtime-config.json in the runtime.
-	//   It removes the need to prepare this file during deployment and update the distribution files.
-	runtimeConfigResponse := struct {
-		BackendUrl string `json:"backendUrl"`
-	}{BackendUrl: backendAddress}
-	rUI.Methods("GET").Path("/assets/config/runtime-config.json").HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		controllers.EncodeJSONResponse(runtimeConfigResponse, http.StatusOK, w)
-	})
-
 	//   redirect the user from / to pathPrefix (/ui/)
 	router.Methods("GET").Path("/").HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		http.Redirect(w, r, pathPrefix, http.StatusFound)
 	})
 
+	router.Methods("GET").PathPrefix(pathPrefix).Handler(http.StripPrefix(strings.TrimSuffix(pathPrefix, "/"), NewHandler(backendAddress)))
+}
+
+// NewHandler returns an http.Handler serving the ADK Web UI, the development
+// UI to chat with the agents, and inspect their events, traces, tool calls
+// and sessions. It can be embedded in any server, next to the ADK REST API
+// (see adkrest.New) it calls at the backend URL, as seen from the browser,
+// e.g. "http://localhost:8080/api". The paths of the UI are relative: mount
+// it under a path ending with "/", with the prefix stripped:
+//
+//	mux.Handle("/ui/", http.StripPrefix("/ui", webui.NewHandler("http://localhost:8080/api")))
+func NewHandler(backendURL string) http.Handler {
 	// serve web ui from the embedded resources
 	ui, err := fs.Sub(content, "distr")
 	if err != nil {
 		log.Fatalf("cannot prepare ADK Web UI files as embedded content: %v", err)
 	}
-	rUI.Methods("GET").Handler(http.StripPrefix(pathPrefix, http.FileServer(http.FS(ui))))
+
+	//   generate /assets/config/runtime-config.json in the runtime.
+	//   It removes the need to prepare this file during deployment and update the distribution files.
+	runtimeConfigResponse := struct {
+		BackendUrl string `json:"backendUrl"`
+	}{BackendUrl: backendURL}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /assets/config/runtime-config.json", func(w http.ResponseWriter, r *http.Request) {
+		controllers.EncodeJSONResponse(runtimeConfigResponse, http.StatusOK, w)
+	})
+	mux.Handle("GET /", http.FileServer(http.FS(ui)))
+	return mux
 }
 
 // NewLauncher creates a new Sublauncher for the ADK Web UI.
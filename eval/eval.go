@@ -0,0 +1,211 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eval
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"google.golang.org/adk/agent"
+	"google.golang.org/adk/runner"
+	"google.golang.org/adk/session"
+)
+
+// Criterion is a metric and the average score an eval case needs to pass.
+type Criterion struct {
+	Metric    Metric
+	Threshold float64
+}
+
+// DefaultCriteria are the criteria of adk-python: the exact tool trajectory,
+// and a response match score of at least 0.8.
+func DefaultCriteria() []Criterion {
+	return []Criterion{
+		{Metric: ToolTrajectory(), Threshold: 1},
+		{Metric: ResponseMatch(), Threshold: 0.8},
+	}
+}
+
+// Config is the configuration of Run.
+type Config struct {
+	// Agent evaluated.
+	Agent agent.Agent
+	// AppName of the sessions, unless set by the eval cases. Defaults to
+	// "eval".
+	AppName string
+	// Criteria scoring the invocations. Defaults to DefaultCriteria.
+	Criteria []Criterion
+}
+
+// Report is the result of an eval set.
+type Report struct {
+	EvalSetID string
+	Cases     []*CaseResult
+	// Scores are the averages of the scores of the cases, by metric name.
+	Scores map[string]float64
+	// Passed reports whether all the cases passed.
+	Passed bool
+}
+
+// CaseResult is the result of an eval case.
+type CaseResult struct {
+	EvalID string
+	// Scores are the averages of the scores of the invocations, by metric
+	// name. The metrics without any reference to compare to are missing.
+	Scores map[string]float64
+	// Passed reports whether all the scores reach the thresholds.
+	Passed      bool
+	Invocations []*InvocationResult
+}
+
+// InvocationResult is the result of an invocation of an eval case.
+type InvocationResult struct {
+	Expected *Invocation
+	Actual   *Invocation
+	Scores   map[string]float64
+}
+
+// Run runs the eval cases of the set through a runner of the agent, each in
+// a new in-memory session, and scores them.
+//
+// The errors of the runs and of the metrics fail Run, the scores below the
+// thresholds do not: see Report.Passed.
+func Run(ctx context.Context, cfg Config, set *EvalSet) (*Report, error) {
+	if cfg.Agent == nil {
+		return nil, errors.New("agent is required")
+	}
+	if cfg.AppName == "" {
+		cfg.AppName = "eval"
+	}
+	if cfg.Criteria == nil {
+		cfg.Criteria = DefaultCriteria()
+	}
+
+	report := &Report{EvalSetID: set.ID, Scores: make(map[string]float64), Passed: true}
+	sums := make(map[string]float64)
+	counts := make(map[string]int)
+	for _, c := range set.Cases {
+		res, err := runCase(ctx, cfg, c)
+		if err != nil {
+			return nil, fmt.Errorf("eval set %q: eval case %q: %w", set.ID, c.ID, err)
+		}
+		report.Cases = append(report.Cases, res)
+		report.Passed = report.Passed && res.Passed
+		for name, score := range res.Scores {
+			sums[name] += score
+			counts[name]++
+		}
+	}
+	for name, sum := range sums {
+		report.Scores[name] = sum / float64(counts[name])
+	}
+	return report, nil
+}
+
+func runCase(ctx context.Context, cfg Config, c *EvalCase) (*CaseResult, error) {
+	appName, userID, state := cfg.AppName, "eval_user", map[string]any(nil)
+	if in := c.SessionInput; in != nil {
+		if in.AppName != "" {
+			appName = in.AppName
+		}
+		if in.UserID != "" {
+			userID = in.UserID
+		}
+		state = in.State
+	}
+	sessionService := session.InMemoryService()
+	created, err := sessionService.Create(ctx, &session.CreateRequest{AppName: appName, UserID: userID, State: state})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create session: %w", err)
+	}
+	r, err := runner.New(runner.Config{AppName: appName, Agent: cfg.Agent, SessionService: sessionService})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create runner: %w", err)
+	}
+
+	res := &CaseResult{EvalID: c.ID, Scores: make(map[string]float64), Passed: true}
+	sums := make(map[string]float64)
+	counts := make(map[string]int)
+	for _, expected := range c.Conversation {
+		actual := &Invocation{UserContent: expected.UserContent, IntermediateData: &IntermediateData{}}
+		for ev, err := range r.Run(ctx, userID, created.Session.ID(), expected.UserContent, agent.RunConfig{}) {
+			if err != nil {
+				return nil, fmt.Errorf("failed to run the agent: %w", err)
+			}
+			actual.InvocationID = ev.InvocationID
+			actual.add(ev)
+		}
+
+		inv := &InvocationResult{Expected: expected, Actual: actual, Scores: make(map[string]float64)}
+		for _, crit := range cfg.Criteria {
+			name := crit.Metric.Name()
+			score, err := crit.Metric.Score(ctx, expected, actual)
+			if errors.Is(err, ErrNoReference) {
+				continue
+			}
+			if err != nil {
+				return nil, fmt.Errorf("metric %q: %w", name, err)
+			}
+			inv.Scores[name] = score
+			sums[name] += score
+			counts[name]++
+		}
+		res.Invocations = append(res.Invocations, inv)
+	}
+	for _, crit := range cfg.Criteria {
+		name := crit.Metric.Name()
+		if counts[name] == 0 {
+			continue
+		}
+		res.Scores[name] = sums[name] / float64(counts[name])
+		if res.Scores[name] < crit.Threshold {
+			res.Passed = false
+		}
+	}
+	return res, nil
+}
+
+// String returns a summary of the report: the scores of each case and their
+// averages.
+func (r *Report) String() string {
+	var sb strings.Builder
+	status := func(passed bool) string {
+		if passed {
+			return "PASS"
+		}
+		return "FAIL"
+	}
+	fmt.Fprintf(&sb, "eval set %q: %s %s\n", r.EvalSetID, status(r.Passed), formatScores(r.Scores))
+	for _, c := range r.Cases {
+		fmt.Fprintf(&sb, "  %s %q %s\n", status(c.Passed), c.EvalID, formatScores(c.Scores))
+	}
+	return sb.String()
+}
+
+func formatScores(scores map[string]float64) string {
+	names := make([]string, 0, len(scores))
+	for name := range scores {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var parts []string
+	for _, name := range names {
+		parts = append(parts, fmt.Sprintf("%s=%.2f", name, scores[name]))
+	}
+	return strings.Join(parts, " ")
+}
@@ -0,0 +1,234 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eval_test
+
+import (
+	"context"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/agent"
+	"google.golang.org/adk/agent/llmagent"
+	"google.golang.org/adk/eval"
+	"google.golang.org/adk/eval/evaltest"
+	"google.golang.org/adk/internal/testutil"
+	"google.golang.org/adk/session"
+	"google.golang.org/adk/tool"
+	"google.golang.org/adk/tool/functiontool"
+)
+
+type rollArgs struct {
+	Sides int `json:"sides"`
+}
+
+func newAgent(t *testing.T, responses ...*genai.Content) agent.Agent {
+	t.Helper()
+	roll, err := functiontool.New(functiontool.Config{Name: "roll_die", Description: "Rolls a die."},
+		func(ctx tool.Context, args rollArgs) (int, error) { return 4, nil })
+	if err != nil {
+		t.Fatal(err)
+	}
+	a, err := llmagent.New(llmagent.Config{
+		Name:  "dice_agent",
+		Model: &testutil.MockModel{Responses: responses},
+		Tools: []tool.Tool{roll},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return a
+}
+
+func TestRun(t *testing.T) {
+	set, err := eval.LoadEvalSet("testdata/dice.evalset.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	a := newAgent(t,
+		genai.NewContentFromFunctionCall("roll_die", map[string]any{"sides": 6}, genai.RoleModel),
+		genai.NewContentFromText("You rolled a 4.", genai.RoleModel),
+		genai.NewContentFromText("Hello!", genai.RoleModel),
+	)
+
+	report, err := eval.Run(context.Background(), eval.Config{Agent: a}, set)
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	approx := cmpopts.EquateApprox(0, 0.01)
+	wantCases := map[string]map[string]float64{
+		"roll":  {"tool_trajectory_avg_score": 1, "response_match_score": 1},
+		"greet": {"tool_trajectory_avg_score": 1, "response_match_score": 2.0 / 7},
+	}
+	gotCases := make(map[string]map[string]float64)
+	for _, c := range report.Cases {
+		gotCases[c.EvalID] = c.Scores
+	}
+	if diff := cmp.Diff(wantCases, gotCases, approx); diff != "" {
+		t.Errorf("case scores mismatch (-want +got):\n%s", diff)
+	}
+	if !report.Cases[0].Passed || report.Cases[1].Passed || report.Passed {
+		t.Errorf("got passed %v, %v, %v; want true, false, false", report.Cases[0].Passed, report.Cases[1].Passed, report.Passed)
+	}
+	wantScores := map[string]float64{"tool_trajectory_avg_score": 1, "response_match_score": (1 + 2.0/7) / 2}
+	if diff := cmp.Diff(wantScores, report.Scores, approx); diff != "" {
+		t.Errorf("report scores mismatch (-want +got):\n%s", diff)
+	}
+	actual := report.Cases[0].Invocations[0].Actual
+	if got := actual.IntermediateData.ToolUses; len(got) != 1 || got[0].Name != "roll_die" {
+		t.Errorf("got tool uses %v, want roll_die", got)
+	}
+	for _, want := range []string{`eval set "dice": FAIL`, `PASS "roll"`, `FAIL "greet"`, "response_match_score=0.29"} {
+		if !strings.Contains(report.String(), want) {
+			t.Errorf("report %q does not contain %q", report, want)
+		}
+	}
+}
+
+func TestCaseFromEvents(t *testing.T) {
+	event := func(invocationID, author string, content *genai.Content) *session.Event {
+		ev := session.NewEvent(invocationID)
+		ev.Author = author
+		ev.Content = content
+		return ev
+	}
+	roll := &genai.FunctionCall{Name: "roll_die", Args: map[string]any{"sides": 6.0}}
+	events := []*session.Event{
+		event("inv1", "user", genai.NewContentFromText("Roll a die.", genai.RoleUser)),
+		event("inv1", "dice_agent", genai.NewContentFromParts([]*genai.Part{{FunctionCall: roll}}, genai.RoleModel)),
+		event("inv1", "dice_agent", genai.NewContentFromFunctionResponse("roll_die", map[string]any{"result": 4}, genai.RoleUser)),
+		event("inv1", "dice_agent", genai.NewContentFromText("You rolled a 4.", genai.RoleModel)),
+		event("inv2", "user", genai.NewContentFromText("Thanks!", genai.RoleUser)),
+		event("inv2", "dice_agent", genai.NewContentFromText("You are welcome.", genai.RoleModel)),
+	}
+
+	want := &eval.EvalCase{
+		ID: "prod",
+		Conversation: []*eval.Invocation{
+			{
+				InvocationID:     "inv1",
+				UserContent:      events[0].Content,
+				FinalResponse:    events[3].Content,
+				IntermediateData: &eval.IntermediateData{ToolUses: []*genai.FunctionCall{roll}},
+			},
+			{
+				InvocationID:     "inv2",
+				UserContent:      events[4].Content,
+				FinalResponse:    events[5].Content,
+				IntermediateData: &eval.IntermediateData{},
+			},
+		},
+	}
+	if diff := cmp.Diff(want, eval.CaseFromEvents("prod", events)); diff != "" {
+		t.Errorf("CaseFromEvents() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestToolTrajectory(t *testing.T) {
+	inv := func(calls ...*genai.FunctionCall) *eval.Invocation {
+		return &eval.Invocation{IntermediateData: &eval.IntermediateData{ToolUses: calls}}
+	}
+	call := func(name string, args map[string]any) *genai.FunctionCall {
+		return &genai.FunctionCall{Name: name, Args: args}
+	}
+	tests := []struct {
+		name             string
+		expected, actual *eval.Invocation
+		want             float64
+	}{
+		{"no tools", &eval.Invocation{}, inv(), 1},
+		{"same calls", inv(call("a", map[string]any{"n": 6.0}), call("b", nil)), inv(call("a", map[string]any{"n": 6}), call("b", map[string]any{})), 1},
+		{"other args", inv(call("a", map[string]any{"n": 6})), inv(call("a", map[string]any{"n": 20})), 0},
+		{"other order", inv(call("a", nil), call("b", nil)), inv(call("b", nil), call("a", nil)), 0},
+		{"missing call", inv(call("a", nil), call("b", nil)), inv(call("a", nil)), 0},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := eval.ToolTrajectory().Score(context.Background(), tc.expected, tc.actual)
+			if err != nil || got != tc.want {
+				t.Errorf("Score() = %v, %v, want %v", got, err, tc.want)
+			}
+		})
+	}
+}
+
+func TestResponseMatch(t *testing.T) {
+	inv := func(s string) *eval.Invocation {
+		return &eval.Invocation{FinalResponse: genai.NewContentFromText(s, genai.RoleModel)}
+	}
+	tests := []struct {
+		expected, actual string
+		want             float64
+	}{
+		{"You rolled a 4.", "you rolled a 4", 1},
+		{"The sky is blue", "The sky is red", 0.75},
+		{"yes", "no", 0},
+	}
+	for _, tc := range tests {
+		got, err := eval.ResponseMatch().Score(context.Background(), inv(tc.expected), inv(tc.actual))
+		if err != nil || math.Abs(got-tc.want) > 0.01 {
+			t.Errorf("Score(%q, %q) = %v, %v, want %v", tc.expected, tc.actual, got, err, tc.want)
+		}
+	}
+	if _, err := eval.ResponseMatch().Score(context.Background(), &eval.Invocation{}, inv("x")); err != eval.ErrNoReference {
+		t.Errorf("Score() without reference error = %v, want ErrNoReference", err)
+	}
+}
+
+func TestResponseJudge(t *testing.T) {
+	judge := &testutil.MockModel{Responses: []*genai.Content{
+		genai.NewContentFromText(`{"reasoning": "same roll", "verdict": "valid"}`, genai.RoleModel),
+		genai.NewContentFromText(`{"reasoning": "other roll", "verdict": "invalid"}`, genai.RoleModel),
+	}}
+	expected := &eval.Invocation{
+		UserContent:   genai.NewContentFromText("Roll a die.", genai.RoleUser),
+		FinalResponse: genai.NewContentFromText("You rolled a 4.", genai.RoleModel),
+	}
+	m := eval.ResponseJudge(judge)
+	for _, want := range []float64{1, 0} {
+		got, err := m.Score(context.Background(), expected, &eval.Invocation{FinalResponse: genai.NewContentFromText("It's a four!", genai.RoleModel)})
+		if err != nil || got != want {
+			t.Errorf("Score() = %v, %v, want %v", got, err, want)
+		}
+	}
+	prompt := judge.Requests[0].Contents[0].Parts[0].Text
+	for _, want := range []string{"Roll a die.", "You rolled a 4.", "It's a four!"} {
+		if !strings.Contains(prompt, want) {
+			t.Errorf("judge prompt %q does not contain %q", prompt, want)
+		}
+	}
+}
+
+func TestParseEvalSet_Invalid(t *testing.T) {
+	for _, data := range []string{`{`, `{"eval_cases": [null]}`, `{"eval_cases": [{"conversation": [{}]}]}`} {
+		if _, err := eval.ParseEvalSet([]byte(data)); err == nil {
+			t.Errorf("ParseEvalSet(%s) succeeded, want error", data)
+		}
+	}
+}
+
+func TestEvaltestRun(t *testing.T) {
+	a := newAgent(t,
+		genai.NewContentFromFunctionCall("roll_die", map[string]any{"sides": 6}, genai.RoleModel),
+		genai.NewContentFromText("You rolled a 4.", genai.RoleModel),
+		genai.NewContentFromText("Hello Alice, how can I help?", genai.RoleModel),
+	)
+	evaltest.Run(t, eval.Config{Agent: a}, "testdata/dice.evalset.json")
+}
@@ -0,0 +1,164 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package eval evaluates agents against eval sets: conversations with the
+// tool calls the agent is expected to make and the reference responses it is
+// expected to give.
+//
+// [Run] replays the conversations of an eval set through a runner of the
+// agent, whose model may be a mock or a real one, and scores each invocation
+// with the metrics of the criteria, e.g. [ToolTrajectory] and
+// [ResponseMatch], or [ResponseJudge] asking a model to judge the
+// responses. The report has the scores of each case and their averages. See
+// package evaltest to run the eval sets in go test.
+//
+// [CaseFromEvents] builds the eval cases of recorded conversations, e.g.
+// production traffic exported with package eventlog.
+//
+// The eval sets are JSON files in the format of adk-python, see
+// src/google/adk/evaluation/eval_set.py, e.g.
+//
+//	{
+//	  "eval_set_id": "dice",
+//	  "eval_cases": [{
+//	    "eval_id": "roll",
+//	    "conversation": [{
+//	      "user_content": {"role": "user", "parts": [{"text": "Roll a die."}]},
+//	      "final_response": {"role": "model", "parts": [{"text": "You rolled a 4."}]},
+//	      "intermediate_data": {"tool_uses": [{"name": "roll_die", "args": {"sides": 6}}]}
+//	    }]
+//	  }]
+//	}
+package eval
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/session"
+)
+
+// EvalSet is a set of eval cases.
+type EvalSet struct {
+	ID          string      `json:"eval_set_id"`
+	Name        string      `json:"name,omitempty"`
+	Description string      `json:"description,omitempty"`
+	Cases       []*EvalCase `json:"eval_cases"`
+}
+
+// EvalCase is a conversation with the agent.
+type EvalCase struct {
+	ID string `json:"eval_id"`
+	// Conversation are the invocations of the conversation, in order.
+	Conversation []*Invocation `json:"conversation"`
+	// SessionInput is the initial session of the conversation, if any.
+	SessionInput *SessionInput `json:"session_input,omitempty"`
+}
+
+// Invocation is a message of the user and the expected behavior of the
+// agent, or its actual behavior in the reports.
+type Invocation struct {
+	InvocationID string         `json:"invocation_id,omitempty"`
+	UserContent  *genai.Content `json:"user_content"`
+	// FinalResponse is the final response of the agent, nil if it is not
+	// evaluated.
+	FinalResponse    *genai.Content    `json:"final_response,omitempty"`
+	IntermediateData *IntermediateData `json:"intermediate_data,omitempty"`
+}
+
+// IntermediateData is what the agent does before its final response.
+type IntermediateData struct {
+	// ToolUses are the tool calls of the agent, in order.
+	ToolUses []*genai.FunctionCall `json:"tool_uses,omitempty"`
+}
+
+// SessionInput is the initial session of an eval case.
+type SessionInput struct {
+	AppName string         `json:"app_name,omitempty"`
+	UserID  string         `json:"user_id,omitempty"`
+	State   map[string]any `json:"state,omitempty"`
+}
+
+// ParseEvalSet parses a JSON eval set.
+func ParseEvalSet(data []byte) (*EvalSet, error) {
+	var set EvalSet
+	if err := json.Unmarshal(data, &set); err != nil {
+		return nil, fmt.Errorf("failed to parse eval set: %w", err)
+	}
+	for _, c := range set.Cases {
+		if c == nil {
+			return nil, fmt.Errorf("eval set %q: nil eval case", set.ID)
+		}
+		for i, inv := range c.Conversation {
+			if inv == nil || inv.UserContent == nil {
+				return nil, fmt.Errorf("eval set %q: eval case %q: invocation %d has no user content", set.ID, c.ID, i)
+			}
+		}
+	}
+	return &set, nil
+}
+
+// LoadEvalSet loads a JSON eval set file.
+func LoadEvalSet(path string) (*EvalSet, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read eval set: %w", err)
+	}
+	set, err := ParseEvalSet(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return set, nil
+}
+
+// CaseFromEvents returns an eval case of the conversation of the events,
+// e.g. of a session or of an export of production traffic read with
+// eventlog.Read: one invocation per user message, expecting the tool calls
+// and the final response of the agent.
+func CaseFromEvents(id string, events []*session.Event) *EvalCase {
+	c := &EvalCase{ID: id}
+	var inv *Invocation
+	for _, ev := range events {
+		if ev.Author == "user" {
+			if ev.Content == nil || (inv != nil && inv.InvocationID == ev.InvocationID) {
+				continue
+			}
+			inv = &Invocation{InvocationID: ev.InvocationID, UserContent: ev.Content, IntermediateData: &IntermediateData{}}
+			c.Conversation = append(c.Conversation, inv)
+			continue
+		}
+		if inv != nil && ev.InvocationID == inv.InvocationID {
+			inv.add(ev)
+		}
+	}
+	return c
+}
+
+// add records the tool calls and the final response of the agent event.
+func (inv *Invocation) add(ev *session.Event) {
+	if ev.Author == "user" || ev.Content == nil || ev.Partial {
+		return
+	}
+	for _, part := range ev.Content.Parts {
+		if part != nil && part.FunctionCall != nil {
+			inv.IntermediateData.ToolUses = append(inv.IntermediateData.ToolUses, part.FunctionCall)
+		}
+	}
+	if ev.IsFinalResponse() {
+		inv.FinalResponse = ev.Content
+	}
+}
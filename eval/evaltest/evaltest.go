@@ -0,0 +1,49 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package evaltest runs eval sets in go test, e.g.
+//
+//	func TestAgent(t *testing.T) {
+//		evaltest.Run(t, eval.Config{Agent: newAgent(t)}, "testdata/agent.evalset.json")
+//	}
+package evaltest
+
+import (
+	"context"
+	"testing"
+
+	"google.golang.org/adk/eval"
+)
+
+// Run runs the eval set files, see eval.Run, and fails the test for each eval
+// case which does not pass. The reports are logged.
+func Run(t testing.TB, cfg eval.Config, paths ...string) {
+	t.Helper()
+	for _, path := range paths {
+		set, err := eval.LoadEvalSet(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		report, err := eval.Run(context.Background(), cfg, set)
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Logf("%s: %s", path, report)
+		for _, c := range report.Cases {
+			if !c.Passed {
+				t.Errorf("%s: eval case %q failed with scores %v", path, c.EvalID, c.Scores)
+			}
+		}
+	}
+}
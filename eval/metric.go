@@ -0,0 +1,239 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eval
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"unicode"
+
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/model"
+)
+
+// ErrNoReference is returned by the metrics for the invocations without the
+// expected data they compare to, which are left out of the scores.
+var ErrNoReference = errors.New("no reference")
+
+// Metric scores the actual invocations against the expected ones.
+type Metric interface {
+	// Name identifies the metric in the reports.
+	Name() string
+	// Score returns the score of the actual invocation, between 0 and 1.
+	Score(ctx context.Context, expected, actual *Invocation) (float64, error)
+}
+
+// ToolTrajectory returns the metric "tool_trajectory_avg_score": 1 if the
+// agent called the expected tools with the expected arguments in the
+// expected order, and 0 otherwise.
+func ToolTrajectory() Metric {
+	return toolTrajectory{}
+}
+
+type toolTrajectory struct{}
+
+func (toolTrajectory) Name() string {
+	return "tool_trajectory_avg_score"
+}
+
+func (toolTrajectory) Score(ctx context.Context, expected, actual *Invocation) (float64, error) {
+	want, got := toolUses(expected), toolUses(actual)
+	if len(want) != len(got) {
+		return 0, nil
+	}
+	for i := range want {
+		if want[i].Name != got[i].Name {
+			return 0, nil
+		}
+		wantArgs, err := normalize(want[i].Args)
+		if err != nil {
+			return 0, err
+		}
+		gotArgs, err := normalize(got[i].Args)
+		if err != nil {
+			return 0, err
+		}
+		if !reflect.DeepEqual(wantArgs, gotArgs) {
+			return 0, nil
+		}
+	}
+	return 1, nil
+}
+
+func toolUses(inv *Invocation) []*genai.FunctionCall {
+	if inv.IntermediateData == nil {
+		return nil
+	}
+	return inv.IntermediateData.ToolUses
+}
+
+// normalize returns the arguments as decoded from JSON, so that e.g. the
+// integers of the agent equal the numbers of the eval set.
+func normalize(args map[string]any) (any, error) {
+	if len(args) == 0 {
+		return nil, nil
+	}
+	b, err := json.Marshal(args)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode the tool arguments: %w", err)
+	}
+	var v any
+	if err := json.Unmarshal(b, &v); err != nil {
+		return nil, fmt.Errorf("failed to decode the tool arguments: %w", err)
+	}
+	return v, nil
+}
+
+// ResponseMatch returns the metric "response_match_score": the ROUGE-1 F1
+// score of the final response against the reference response, i.e. the
+// overlap of their words, ignoring case and punctuation.
+func ResponseMatch() Metric {
+	return responseMatch{}
+}
+
+type responseMatch struct{}
+
+func (responseMatch) Name() string {
+	return "response_match_score"
+}
+
+func (responseMatch) Score(ctx context.Context, expected, actual *Invocation) (float64, error) {
+	if expected.FinalResponse == nil {
+		return 0, ErrNoReference
+	}
+	return rouge1(words(text(expected.FinalResponse)), words(text(actual.FinalResponse))), nil
+}
+
+func words(s string) []string {
+	return strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// rouge1 returns the F1 score of the unigrams of the candidate against the
+// reference.
+func rouge1(reference, candidate []string) float64 {
+	if len(reference) == 0 || len(candidate) == 0 {
+		if len(reference) == len(candidate) {
+			return 1
+		}
+		return 0
+	}
+	counts := make(map[string]int)
+	for _, w := range reference {
+		counts[w]++
+	}
+	overlap := 0
+	for _, w := range candidate {
+		if counts[w] > 0 {
+			counts[w]--
+			overlap++
+		}
+	}
+	if overlap == 0 {
+		return 0
+	}
+	precision := float64(overlap) / float64(len(candidate))
+	recall := float64(overlap) / float64(len(reference))
+	return 2 * precision * recall / (precision + recall)
+}
+
+// judgeInstruction is the instruction of the judge model of ResponseJudge.
+const judgeInstruction = `You evaluate the responses of an AI agent. Given the request of the user, a reference response and the response of the agent, decide whether the response of the agent is valid: whether it conveys the same information and answers the request as well as the reference does. The wording and the level of detail may differ.
+
+Answer with a JSON object with the keys:
+- "verdict": "valid" or "invalid";
+- "reasoning": a short explanation.`
+
+var judgeSchema = &genai.Schema{
+	Type: genai.TypeObject,
+	Properties: map[string]*genai.Schema{
+		"verdict":   {Type: genai.TypeString, Enum: []string{"valid", "invalid"}},
+		"reasoning": {Type: genai.TypeString},
+	},
+	Required:         []string{"verdict"},
+	PropertyOrdering: []string{"reasoning", "verdict"},
+}
+
+// ResponseJudge returns the metric "final_response_match_v2", asking the
+// model whether the final response is as valid as the reference response:
+// 1 if it is, and 0 otherwise.
+func ResponseJudge(m model.LLM) Metric {
+	return &responseJudge{model: m}
+}
+
+type responseJudge struct {
+	model model.LLM
+}
+
+func (j *responseJudge) Name() string {
+	return "final_response_match_v2"
+}
+
+func (j *responseJudge) Score(ctx context.Context, expected, actual *Invocation) (float64, error) {
+	if expected.FinalResponse == nil {
+		return 0, ErrNoReference
+	}
+	prompt := fmt.Sprintf("User request:\n%s\n\nReference response:\n%s\n\nAgent response:\n%s",
+		text(expected.UserContent), text(expected.FinalResponse), text(actual.FinalResponse))
+	req := &model.LLMRequest{
+		Model:    j.model.Name(),
+		Contents: []*genai.Content{genai.NewContentFromText(prompt, genai.RoleUser)},
+		Config: &genai.GenerateContentConfig{
+			SystemInstruction: genai.NewContentFromText(judgeInstruction, genai.RoleUser),
+			ResponseMIMEType:  "application/json",
+			ResponseSchema:    judgeSchema,
+		},
+	}
+	var b strings.Builder
+	for resp, err := range j.model.GenerateContent(ctx, req, false) {
+		if err != nil {
+			return 0, fmt.Errorf("failed to call the judge model: %w", err)
+		}
+		b.WriteString(text(resp.Content))
+	}
+	var verdict struct {
+		Verdict string `json:"verdict"`
+	}
+	if err := json.Unmarshal([]byte(b.String()), &verdict); err != nil {
+		return 0, fmt.Errorf("invalid verdict %q: %w", b.String(), err)
+	}
+	switch verdict.Verdict {
+	case "valid":
+		return 1, nil
+	case "invalid":
+		return 0, nil
+	}
+	return 0, fmt.Errorf("invalid verdict %q", verdict.Verdict)
+}
+
+// text returns the text of the content, thoughts excluded.
+func text(content *genai.Content) string {
+	if content == nil {
+		return ""
+	}
+	var sb strings.Builder
+	for _, part := range content.Parts {
+		if part != nil && !part.Thought {
+			sb.WriteString(part.Text)
+		}
+	}
+	return sb.String()
+}
@@ -23,8 +23,6 @@ import (
 	"net/url"
 	"os"
 
-	"github.com/a2aproject/a2a-go/a2a"
-	"github.com/a2aproject/a2a-go/a2asrv"
 	"google.golang.org/genai"
 
 	"google.golang.org/adk/agent"
@@ -77,29 +75,21 @@ func startWeatherAgentServer() string {
 		ctx := context.Background()
 		agent := newWeatherAgent(ctx)
 
-		agentPath := "/invoke"
-		agentCard := &a2a.AgentCard{
-			Name:               agent.Name(),
-			Skills:             adka2a.BuildAgentSkills(agent),
-			PreferredTransport: a2a.TransportProtocolJSONRPC,
-			URL:                baseURL.JoinPath(agentPath).String(),
-			Capabilities:       a2a.AgentCapabilities{Streaming: true},
-		}
-
-		mux := http.NewServeMux()
-		mux.Handle(a2asrv.WellKnownAgentCardPath, a2asrv.NewStaticAgentCardHandler(agentCard))
-
-		executor := adka2a.NewExecutor(adka2a.ExecutorConfig{
-			RunnerConfig: runner.Config{
-				AppName:        agent.Name(),
-				Agent:          agent,
-				SessionService: session.InMemoryService(),
+		handler, err := adka2a.NewServer(adka2a.ServerConfig{
+			URL: baseURL.String(),
+			ExecutorConfig: adka2a.ExecutorConfig{
+				RunnerConfig: runner.Config{
+					AppName:        agent.Name(),
+					Agent:          agent,
+					SessionService: session.InMemoryService(),
+				},
 			},
 		})
-		requestHandler := a2asrv.NewHandler(executor)
-		mux.Handle(agentPath, a2asrv.NewJSONRPCHandler(requestHandler))
+		if err != nil {
+			log.Fatalf("Failed to create the A2A server: %v", err)
+		}
 
-		err := http.Serve(listener, mux)
+		err = http.Serve(listener, handler)
 
 		log.Printf("A2A server stopped: %v", err)
 	}()
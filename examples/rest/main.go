@@ -20,13 +20,12 @@ import (
 	"log"
 	"net/http"
 	"os"
-	"time"
 
 	"google.golang.org/genai"
 
 	"google.golang.org/adk/agent"
 	"google.golang.org/adk/agent/llmagent"
-	"google.golang.org/adk/cmd/launcher"
+	"google.golang.org/adk/cmd/launcher/web/webui"
 	"google.golang.org/adk/model/gemini"
 	"google.golang.org/adk/server/adkrest"
 	"google.golang.org/adk/session"
@@ -59,15 +58,15 @@ func main() {
 		log.Fatalf("Failed to create agent: %v", err)
 	}
 
-	// Configure the ADK REST API
-	config := &launcher.Config{
+	// Create the REST API handler - this returns a standard http.Handler
+	apiHandler, err := adkrest.New(adkrest.Config{
 		AgentLoader:    agent.NewSingleLoader(a),
 		SessionService: session.InMemoryService(),
+	})
+	if err != nil {
+		log.Fatalf("Failed to create the REST API handler: %v", err)
 	}
 
-	// Create the REST API handler - this returns a standard http.Handler
-	apiHandler := adkrest.NewHandler(config, 120*time.Second)
-
 	// Create a standard net/http ServeMux
 	mux := http.NewServeMux()
 
@@ -75,6 +74,9 @@ func main() {
 	// You can use any HTTP server or router here - not tied to gorilla/mux
 	mux.Handle("/api/", http.StripPrefix("/api", apiHandler))
 
+	// Serve the ADK Web UI, calling the API, at the /ui/ path
+	mux.Handle("/ui/", http.StripPrefix("/ui", webui.NewHandler("http://localhost:8080/api")))
+
 	// Add a simple health check endpoint
 	mux.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusOK)
@@ -86,6 +88,7 @@ func main() {
 	// Start the server
 	log.Println("Starting server on :8080")
 	log.Println("API available at http://localhost:8080/api/")
+	log.Println("Web UI available at http://localhost:8080/ui/")
 	log.Println("Health check at http://localhost:8080/health")
 
 	if err := http.ListenAndServe(":8080", mux); err != nil {
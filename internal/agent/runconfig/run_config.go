@@ -14,7 +14,14 @@
 
 package runconfig
 
-import "context"
+import (
+	"context"
+	"sync/atomic"
+
+	"google.golang.org/adk/agent"
+	"google.golang.org/adk/model"
+	"google.golang.org/adk/tool"
+)
 
 type StreamingMode string
 
@@ -26,6 +33,55 @@ const (
 
 type RunConfig struct {
 	StreamingMode StreamingMode
+	// ToolInterceptors wrap the tool calls of all the agents of the run.
+	ToolInterceptors []tool.Interceptor
+	// MaxLLMCalls and MaxToolCalls limit the calls of the run, and MaxTokens
+	// the tokens of its model calls, if positive.
+	MaxLLMCalls  int
+	MaxToolCalls int
+	MaxTokens    int
+	// LiveRequestQueue is the input of the live runs, in StreamingModeBidi.
+	LiveRequestQueue *agent.LiveRequestQueue
+	// The model and tool callbacks of the plugins of the run, which run
+	// before the callbacks of each agent.
+	BeforeModelCallbacks []func(ctx agent.CallbackContext, llmRequest *model.LLMRequest) (*model.LLMResponse, error)
+	AfterModelCallbacks  []func(ctx agent.CallbackContext, llmResponse *model.LLMResponse, llmResponseError error) (*model.LLMResponse, error)
+	BeforeToolCallbacks  []func(ctx tool.Context, tool tool.Tool, args map[string]any) (map[string]any, error)
+	AfterToolCallbacks   []func(ctx tool.Context, tool tool.Tool, args, result map[string]any, err error) (map[string]any, error)
+
+	llmCalls  atomic.Int64
+	toolCalls atomic.Int64
+	tokens    atomic.Int64
+}
+
+// CountLLMCall counts a model call of the run, and reports whether it is
+// within MaxLLMCalls.
+func (c *RunConfig) CountLLMCall() bool {
+	if c == nil {
+		return true
+	}
+	n := c.llmCalls.Add(1)
+	return c.MaxLLMCalls <= 0 || n <= int64(c.MaxLLMCalls)
+}
+
+// CountToolCalls counts n tool calls of the run, and reports whether they
+// are within MaxToolCalls.
+func (c *RunConfig) CountToolCalls(n int) bool {
+	if c == nil {
+		return true
+	}
+	total := c.toolCalls.Add(int64(n))
+	return c.MaxToolCalls <= 0 || total <= int64(c.MaxToolCalls)
+}
+
+// CountTokens counts n tokens of the model calls of the run, and reports
+// whether they are within MaxTokens.
+func (c *RunConfig) CountTokens(n int) bool {
+	if c == nil {
+		return true
+	}
+	total := c.tokens.Add(int64(n))
+	return c.MaxTokens <= 0 || total <= int64(c.MaxTokens)
 }
 
 func ToContext(ctx context.Context, cfg *RunConfig) context.Context {
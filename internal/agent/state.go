@@ -31,6 +31,7 @@ const (
 	TypeLoopAgent       Type = "LoopAgent"
 	TypeSequentialAgent Type = "SequentialAgent"
 	TypeParallelAgent   Type = "ParallelAgent"
+	TypeExperimentAgent Type = "ExperimentAgent"
 	TypeCustomAgent     Type = "CustomAgent"
 )
 
@@ -0,0 +1,178 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package agentengine is a client of the REST API of the Vertex AI Agent
+// Engine, shared by its session and memory services.
+package agentengine
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"golang.org/x/oauth2/google"
+
+	"google.golang.org/adk/internal/utils"
+)
+
+const cloudPlatformScope = "https://www.googleapis.com/auth/cloud-platform"
+
+// minPollInterval and maxPollInterval bound the interval between two polls
+// of a long-running operation, doubled after each poll.
+const (
+	minPollInterval = 100 * time.Millisecond
+	maxPollInterval = 2 * time.Second
+)
+
+var (
+	engineIDRegexp   = regexp.MustCompile(`^[0-9]+$`)
+	engineNameRegexp = regexp.MustCompile(`^projects/[^/]+/locations/[^/]+/reasoningEngines/[^/]+$`)
+)
+
+// Client calls the API of the Agent Engines of a project and location.
+type Client struct {
+	httpClient *http.Client
+	baseURL    string
+	project    string
+	location   string
+	engineID   string
+}
+
+// NewClient returns a client of the Agent Engines of the project in the
+// location. The engineID is the ID or the resource name of the Agent Engine;
+// if it is empty, the app name of each call is used. If httpClient is nil, the
+// requests are authorized with the application default credentials. The
+// endpoint defaults to the regional endpoint of the location.
+func NewClient(ctx context.Context, project, location, engineID string, httpClient *http.Client, endpoint string) (*Client, error) {
+	if project == "" || location == "" {
+		return nil, fmt.Errorf("project and location are required, got project: %q, location: %q", project, location)
+	}
+	if httpClient == nil {
+		var err error
+		httpClient, err = google.DefaultClient(ctx, cloudPlatformScope)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find the default credentials: %w", err)
+		}
+	}
+	if endpoint == "" {
+		endpoint = fmt.Sprintf("https://%s-aiplatform.googleapis.com", location)
+	}
+	return &Client{
+		httpClient: httpClient,
+		baseURL:    strings.TrimSuffix(endpoint, "/") + "/v1beta1/",
+		project:    project,
+		location:   location,
+		engineID:   engineID,
+	}, nil
+}
+
+// EngineName returns the resource name of the Agent Engine of the app.
+func (c *Client) EngineName(appName string) (string, error) {
+	id := c.engineID
+	if id == "" {
+		id = appName
+	}
+	switch {
+	case engineNameRegexp.MatchString(id):
+		return id, nil
+	case engineIDRegexp.MatchString(id):
+		return fmt.Sprintf("projects/%s/locations/%s/reasoningEngines/%s", c.project, c.location, id), nil
+	}
+	return "", fmt.Errorf("app name %q is not the ID or the resource name of an Agent Engine", id)
+}
+
+// Do sends a request with the JSON body, if not nil, to the resource path
+// and decodes the JSON response into out, if not nil.
+func (c *Client) Do(ctx context.Context, method, path string, query url.Values, body, out any) error {
+	u := c.baseURL + path
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+	var reqBody bytes.Buffer
+	if body != nil {
+		if err := json.NewEncoder(&reqBody).Encode(body); err != nil {
+			return fmt.Errorf("failed to encode the request: %w", err)
+		}
+	}
+	req, err := http.NewRequestWithContext(ctx, method, u, &reqBody)
+	if err != nil {
+		return err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to call the Agent Engine API: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("%s %s failed: %w", method, path, utils.NewAPIError(resp))
+	}
+	if out == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode the response of %s %s: %w", method, path, err)
+	}
+	return nil
+}
+
+// Operation is a long-running operation.
+type Operation struct {
+	Name     string          `json:"name"`
+	Done     bool            `json:"done"`
+	Error    *OperationError `json:"error,omitempty"`
+	Response json.RawMessage `json:"response,omitempty"`
+}
+
+// OperationError is the status of a failed operation.
+type OperationError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// Wait polls the operation until it is done, and returns it, or its error.
+func (c *Client) Wait(ctx context.Context, op *Operation) (*Operation, error) {
+	for interval := minPollInterval; !op.Done; interval = min(2*interval, maxPollInterval) {
+		timer := time.NewTimer(interval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+		var polled Operation
+		if err := c.Do(ctx, http.MethodGet, op.Name, nil, nil, &polled); err != nil {
+			return nil, err
+		}
+		op = &polled
+	}
+	if op.Error != nil {
+		return nil, fmt.Errorf("operation %s failed with code %d: %s", op.Name, op.Error.Code, op.Error.Message)
+	}
+	return op, nil
+}
+
+// LastSegment returns the last segment of a resource name, the ID of the
+// resource.
+func LastSegment(name string) string {
+	return name[strings.LastIndex(name, "/")+1:]
+}
@@ -68,4 +68,13 @@ func (a *Artifacts) List(ctx context.Context) (*artifact.ListResponse, error) {
 	})
 }
 
+func (a *Artifacts) Versions(ctx context.Context, name string) (*artifact.VersionsResponse, error) {
+	return a.Service.Versions(ctx, &artifact.VersionsRequest{
+		AppName:   a.AppName,
+		UserID:    a.UserID,
+		SessionID: a.SessionID,
+		FileName:  name,
+	})
+}
+
 var _ agent.Artifacts = (*Artifacts)(nil)
@@ -122,3 +122,44 @@ func TestArtifacts_Errors(t *testing.T) {
 		t.Errorf("LoadVersion(\"existsArtifact\", 99) succeeded, want error")
 	}
 }
+
+func TestArtifacts_VersionsAndUserScope(t *testing.T) {
+	service := artifact.InMemoryService()
+	session1 := artifactinternal.Artifacts{Service: service, AppName: "testApp", UserID: "testUser", SessionID: "session1"}
+	session2 := artifactinternal.Artifacts{Service: service, AppName: "testApp", UserID: "testUser", SessionID: "session2"}
+
+	for _, text := range []string{"v1", "v2"} {
+		if _, err := session1.Save(t.Context(), "user:report", genai.NewPartFromText(text)); err != nil {
+			t.Fatalf("Save failed: %v", err)
+		}
+	}
+	if _, err := session1.Save(t.Context(), "draft", genai.NewPartFromText("draft")); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	versions, err := session2.Versions(t.Context(), "user:report")
+	if err != nil {
+		t.Fatalf("Versions failed: %v", err)
+	}
+	if diff := cmp.Diff([]int64{2, 1}, versions.Versions); diff != "" {
+		t.Errorf("Versions mismatch (-want +got):\n%s", diff)
+	}
+	loadResp, err := session2.LoadVersion(t.Context(), "user:report", 1)
+	if err != nil {
+		t.Fatalf("LoadVersion failed: %v", err)
+	}
+	if diff := cmp.Diff(genai.NewPartFromText("v1"), loadResp.Part); diff != "" {
+		t.Errorf("LoadVersion mismatch (-want +got):\n%s", diff)
+	}
+
+	listResp, err := session2.List(t.Context())
+	if err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	if diff := cmp.Diff([]string{"user:report"}, listResp.FileNames); diff != "" {
+		t.Errorf("List mismatch (-want +got):\n%s", diff)
+	}
+	if _, err := session2.Versions(t.Context(), "draft"); err == nil {
+		t.Errorf("Versions(\"draft\") in another session succeeded, want error")
+	}
+}
@@ -16,6 +16,7 @@ package context
 
 import (
 	"context"
+	"time"
 
 	"github.com/google/uuid"
 	"google.golang.org/genai"
@@ -35,13 +36,20 @@ type InvocationContextParams struct {
 	UserContent   *genai.Content
 	RunConfig     *agent.RunConfig
 	EndInvocation bool
+
+	// InvocationID of a resumed invocation. A new ID is generated if empty.
+	InvocationID string
 }
 
 func NewInvocationContext(ctx context.Context, params InvocationContextParams) agent.InvocationContext {
+	invocationID := params.InvocationID
+	if invocationID == "" {
+		invocationID = "e-" + uuid.NewString()
+	}
 	return &InvocationContext{
 		Context:      ctx,
 		params:       params,
-		invocationID: "e-" + uuid.NewString(),
+		invocationID: invocationID,
 	}
 }
 
@@ -91,3 +99,30 @@ func (c *InvocationContext) EndInvocation() {
 func (c *InvocationContext) Ended() bool {
 	return c.params.EndInvocation
 }
+
+// WithContext returns the invocation context with the deadline, cancellation
+// and values of ctx, which is usually derived from it, e.g. with a span.
+func WithContext(invCtx agent.InvocationContext, ctx context.Context) agent.InvocationContext {
+	return &derivedInvocationContext{InvocationContext: invCtx, ctx: ctx}
+}
+
+type derivedInvocationContext struct {
+	agent.InvocationContext
+	ctx context.Context
+}
+
+func (c *derivedInvocationContext) Deadline() (time.Time, bool) {
+	return c.ctx.Deadline()
+}
+
+func (c *derivedInvocationContext) Done() <-chan struct{} {
+	return c.ctx.Done()
+}
+
+func (c *derivedInvocationContext) Err() error {
+	return c.ctx.Err()
+}
+
+func (c *derivedInvocationContext) Value(key any) any {
+	return c.ctx.Value(key)
+}
@@ -18,6 +18,9 @@ import (
 	"google.golang.org/genai"
 
 	"google.golang.org/adk/agent"
+	"google.golang.org/adk/agent/codeexecutor"
+	"google.golang.org/adk/agent/compaction"
+	"google.golang.org/adk/agent/planner"
 	"google.golang.org/adk/model"
 	"google.golang.org/adk/tool"
 )
@@ -30,8 +33,9 @@ type Agent interface {
 type State struct {
 	Model model.LLM
 
-	Tools    []tool.Tool
-	Toolsets []tool.Toolset
+	Tools         []tool.Tool
+	Toolsets      []tool.Toolset
+	ToolConflicts tool.ConflictPolicy
 
 	IncludeContents string
 
@@ -49,6 +53,12 @@ type State struct {
 	OutputSchema *genai.Schema
 
 	OutputKey string
+
+	Planner planner.Planner
+
+	ContextCompaction *compaction.Config
+
+	CodeExecutor *codeexecutor.Config
 }
 
 type InstructionProvider func(ctx agent.ReadonlyContext) (string, error)
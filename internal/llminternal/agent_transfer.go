@@ -18,6 +18,7 @@ import (
 	"bytes"
 	"fmt"
 	"slices"
+	"strings"
 
 	"github.com/google/safehtml/template"
 	"google.golang.org/genai"
@@ -56,11 +57,9 @@ import (
 //  - This agent has DisallowTransferToPeers set to false (default).
 //
 // Depending on the target agent type, the transfer may be automatically
-// reversed. See python's Runner._find_agent_to_run method for which
-// agent will remain active to handle the next user message.
-// (src/google/adk/runners.py)
-//
-// TODO: implement it in the runners package and update this doc.
+// reversed: the runner's findAgentToRun picks which agent remains active to
+// handle the next user message, the agent answering the function responses or
+// else the latest agent whose chain of parents allows the transfers.
 
 func AgentTransferRequestProcessor(ctx agent.InvocationContext, req *model.LLMRequest) error {
 	// TODO: support agent types other than LLMAgent, that have parent/subagents?
@@ -78,7 +77,11 @@ func AgentTransferRequestProcessor(ctx agent.InvocationContext, req *model.LLMRe
 
 	// TODO(hyangah): why do we set this up in request processor
 	// instead of registering this as a normal function tool of the Agent?
-	transferToAgentTool := &TransferToAgentTool{}
+	names := make([]string, len(targets))
+	for i, target := range targets {
+		names[i] = target.Name()
+	}
+	transferToAgentTool := &TransferToAgentTool{Targets: names}
 	si, err := instructionsForTransferToAgent(agent, parents[agent.Name()], targets, transferToAgentTool)
 	if err != nil {
 		return err
@@ -87,7 +90,13 @@ func AgentTransferRequestProcessor(ctx agent.InvocationContext, req *model.LLMRe
 	return appendTools(req, transferToAgentTool)
 }
 
-type TransferToAgentTool struct{}
+// TransferToAgentTool lets the model hand control to another agent.
+type TransferToAgentTool struct {
+	// Targets are the names of the agents the model may transfer to. The
+	// declaration restricts the agent name to them, and the transfers to other
+	// agents fail. Without targets, any agent name is accepted.
+	Targets []string
+}
 
 // Description implements tool.Tool.
 func (t *TransferToAgentTool) Description() string {
@@ -115,6 +124,7 @@ func (t *TransferToAgentTool) Declaration() *genai.FunctionDeclaration {
 				"agent_name": {
 					Type:        "string",
 					Description: "the agent name to transfer to",
+					Enum:        t.Targets,
 				},
 			},
 			Required: []string{"agent_name"},
@@ -140,6 +150,9 @@ func (t *TransferToAgentTool) Run(ctx tool.Context, args any) (map[string]any, e
 	if !ok || agent == "" {
 		return nil, fmt.Errorf("empty agent_name: %v", args)
 	}
+	if len(t.Targets) > 0 && !slices.Contains(t.Targets, agent) {
+		return nil, fmt.Errorf("unknown agent %q, the agent can transfer to: %s", agent, strings.Join(t.Targets, ", "))
+	}
 	ctx.Actions().TransferToAgent = agent
 	return map[string]any{}, nil
 }
@@ -85,6 +85,18 @@ func TestAgentTransferRequestProcessor(t *testing.T) {
 		if gotTool.Name() != wantToolName {
 			t.Errorf("unexpected name for tool, got: %v, want: %v", gotTool.Name(), wantToolName)
 		}
+		// The declaration restricts the transfers to the targets.
+		if transferTool, ok := gotTool.(*llminternal.TransferToAgentTool); ok {
+			enum := transferTool.Declaration().Parameters.Properties["agent_name"].Enum
+			for _, want := range append(slices.Clone(wantAgents), wantParent) {
+				if want != "" && !slices.Contains(enum, want) {
+					t.Errorf("agent_name enum = %v, want it to include %q", enum, want)
+				}
+			}
+			if slices.Contains(enum, curAgent.Name()) {
+				t.Errorf("agent_name enum = %v, want it to exclude the current agent", enum)
+			}
+		}
 
 		// check instructions.
 		instructions := utils.TextParts(req.Config.SystemInstruction)
@@ -460,6 +472,20 @@ func TestTransferToAgentToolRun(t *testing.T) {
 		}
 	})
 
+	t.Run("Targets", func(t *testing.T) {
+		curTool := &llminternal.TransferToAgentTool{Targets: []string{"Sub1", "Sub2"}}
+		if got := curTool.Declaration().Parameters.Properties["agent_name"].Enum; !slices.Equal(got, curTool.Targets) {
+			t.Errorf("agent_name enum = %v, want %v", got, curTool.Targets)
+		}
+		ctx := toolinternal.NewToolContext(icontext.NewInvocationContext(t.Context(), icontext.InvocationContextParams{}), "", &session.EventActions{})
+		if _, err := curTool.Run(ctx, map[string]any{"agent_name": "Sub2"}); err != nil {
+			t.Fatalf("Run() failed: %v", err)
+		}
+		if got := ctx.Actions().TransferToAgent; got != "Sub2" {
+			t.Errorf("TransferToAgent = %q, want Sub2", got)
+		}
+	})
+
 	t.Run("InvalidArguments", func(t *testing.T) {
 		testCases := []struct {
 			name string
@@ -469,12 +495,13 @@ func TestTransferToAgentToolRun(t *testing.T) {
 			{name: "NilArg", args: nil},
 			{name: "InvalidType", args: map[string]any{"agent_name": 123}},
 			{name: "InvalidValue", args: map[string]any{"agent_name": ""}},
+			{name: "UnknownAgent", args: map[string]any{"agent_name": "Other"}},
 		}
 
 		for _, tc := range testCases {
 			t.Run(tc.name, func(t *testing.T) {
-				curTool := &llminternal.TransferToAgentTool{}
-				ctx := toolinternal.NewToolContext(icontext.NewInvocationContext(t.Context(), icontext.InvocationContextParams{}), "", nil)
+				curTool := &llminternal.TransferToAgentTool{Targets: []string{"TestAgent"}}
+				ctx := toolinternal.NewToolContext(icontext.NewInvocationContext(t.Context(), icontext.InvocationContextParams{}), "", &session.EventActions{})
 				if got, err := curTool.Run(ctx, tc.args); err == nil {
 					t.Fatalf("Run(%v) = (%v, %v), want error", tc.args, got, err)
 				}
@@ -0,0 +1,195 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package llminternal
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/agent"
+	"google.golang.org/adk/auth"
+	"google.golang.org/adk/internal/toolinternal"
+	"google.golang.org/adk/internal/typeutil"
+	"google.golang.org/adk/internal/utils"
+	"google.golang.org/adk/model"
+	"google.golang.org/adk/session"
+	"google.golang.org/adk/tool"
+)
+
+// generateAuthEvent returns the event asking the client for the credentials
+// requested by the tools in the function response event, or nil if none
+// was requested. Its function calls are long-running: the client answers
+// them with the obtained credentials.
+//
+// See adk-python src/google/adk/flows/llm_flows/functions.py generate_auth_event.
+func generateAuthEvent(ctx agent.InvocationContext, fnResponseEvent *session.Event) (*session.Event, error) {
+	configs := fnResponseEvent.Actions.RequestedAuthConfigs
+	if len(configs) == 0 {
+		return nil, nil
+	}
+	var parts []*genai.Part
+	for _, callID := range slices.Sorted(maps.Keys(configs)) {
+		cfg := *configs[callID]
+		key, err := cfg.Key()
+		if err != nil {
+			return nil, fmt.Errorf("invalid auth config requested by function call %q: %w", callID, err)
+		}
+		// The key is sent with the request, so that the client keeps it.
+		cfg.CredentialKey = key
+		withURI, err := auth.GenerateAuthURI(&cfg)
+		if err != nil {
+			return nil, err
+		}
+		args, err := typeutil.ConvertToWithJSONSchema[auth.CredentialRequest, map[string]any](auth.CredentialRequest{FunctionCallID: callID, AuthConfig: withURI}, nil)
+		if err != nil {
+			return nil, err
+		}
+		parts = append(parts, genai.NewPartFromFunctionCall(auth.RequestCredentialFunctionName, args))
+	}
+	content := genai.NewContentFromParts(parts, genai.RoleModel)
+	utils.PopulateClientFunctionCallID(content)
+
+	ev := session.NewEvent(ctx.InvocationID())
+	ev.Author = ctx.Agent().Name()
+	ev.Branch = ctx.Branch()
+	ev.LLMResponse = model.LLMResponse{Content: content}
+	for _, fc := range utils.FunctionCalls(content) {
+		ev.LongRunningToolIDs = append(ev.LongRunningToolIDs, fc.ID)
+	}
+	return ev, nil
+}
+
+// resumeAuthorizedCalls handles the credentials sent by the client in the
+// last event of the session, the answers to the function calls of an auth
+// event. The credentials are stored in the session state, and the function
+// calls that requested them are run again. It returns their function
+// response event, or nil if the last event has no credentials.
+//
+// See adk-python src/google/adk/auth/auth_preprocessor.py.
+func (f *Flow) resumeAuthorizedCalls(ctx agent.InvocationContext, emitter *partialEmitter) (*session.Event, error) {
+	events := ctx.Session().Events()
+	if events.Len() == 0 {
+		return nil, nil
+	}
+	var responses []*genai.FunctionResponse
+	for _, resp := range utils.FunctionResponses(events.At(events.Len() - 1).Content) {
+		if resp.Name == auth.RequestCredentialFunctionName {
+			responses = append(responses, resp)
+		}
+	}
+	if len(responses) == 0 {
+		return nil, nil
+	}
+
+	calls := sessionFunctionCalls(ctx)
+	stateDelta := make(map[string]any)
+	var resumed []*genai.Part
+	for _, resp := range responses {
+		requestCall, ok := calls[resp.ID]
+		if !ok {
+			return nil, fmt.Errorf("no credential request for the function response %q", resp.ID)
+		}
+		request, err := typeutil.ConvertToWithJSONSchema[map[string]any, auth.CredentialRequest](requestCall.Args, nil)
+		if err != nil || request.AuthConfig == nil {
+			return nil, fmt.Errorf("invalid credential request %q: %v", resp.ID, err)
+		}
+		response, err := typeutil.ConvertToWithJSONSchema[map[string]any, auth.AuthConfig](resp.Response, nil)
+		if err != nil {
+			return nil, fmt.Errorf("invalid credential response %q: %w", resp.ID, err)
+		}
+		if response.ExchangedAuthCredential == nil {
+			return nil, fmt.Errorf("credential response %q has no exchanged credential", resp.ID)
+		}
+		key, err := request.AuthConfig.Key()
+		if err != nil {
+			return nil, fmt.Errorf("invalid credential request %q: %w", resp.ID, err)
+		}
+		cred, err := auth.ExchangeCredential(ctx, exchangeConfig(request.AuthConfig, response.ExchangedAuthCredential))
+		if err != nil {
+			return nil, fmt.Errorf("invalid credential response %q: %w", resp.ID, err)
+		}
+		val, err := toolinternal.CredentialStateValue(cred)
+		if err != nil {
+			return nil, err
+		}
+		// The credential is visible to the function calls run again, and
+		// stored with their function response event.
+		if err := ctx.Session().State().Set(key, val); err != nil {
+			return nil, fmt.Errorf("failed to store credential: %w", err)
+		}
+		stateDelta[key] = val
+
+		if fc, ok := calls[request.FunctionCallID]; ok && !slices.ContainsFunc(resumed, func(p *genai.Part) bool { return p.FunctionCall.ID == fc.ID }) {
+			resumed = append(resumed, &genai.Part{FunctionCall: fc})
+		}
+	}
+	if len(resumed) == 0 {
+		return nil, nil
+	}
+
+	tools, err := f.tools(ctx)
+	if err != nil {
+		return nil, err
+	}
+	ev, err := f.handleFunctionCalls(ctx, tools, &model.LLMResponse{Content: genai.NewContentFromParts(resumed, genai.RoleModel)}, emitter, nil)
+	if err != nil || ev == nil {
+		return nil, err
+	}
+	if ev.Actions.StateDelta == nil {
+		ev.Actions.StateDelta = make(map[string]any)
+	}
+	for key, val := range stateDelta {
+		// The tools may have replaced the credentials.
+		if _, ok := ev.Actions.StateDelta[key]; !ok {
+			ev.Actions.StateDelta[key] = val
+		}
+	}
+	return ev, nil
+}
+
+// exchangeConfig returns the config of the credential request with the
+// credential sent by the client, whose OAuth2 authorization code is exchanged
+// with the client credentials and the state of the request.
+func exchangeConfig(request *auth.AuthConfig, exchanged *auth.AuthCredential) *auth.AuthConfig {
+	cfg := *request
+	cfg.ExchangedAuthCredential = exchanged
+	if prev := request.ExchangedAuthCredential; prev != nil && prev.OAuth2 != nil && exchanged.OAuth2 != nil {
+		oauth := *exchanged.OAuth2
+		oauth.State = prev.OAuth2.State
+		cfg.ExchangedAuthCredential = &auth.AuthCredential{AuthType: exchanged.AuthType, OAuth2: &oauth}
+	}
+	return &cfg
+}
+
+// tools returns the tools of the agent, keyed by name, as registered in the
+// requests to the model.
+func (f *Flow) tools(ctx agent.InvocationContext) (map[string]tool.Tool, error) {
+	req := &model.LLMRequest{}
+	if err := f.preprocess(ctx, req); err != nil {
+		return nil, err
+	}
+	tools := make(map[string]tool.Tool, len(req.Tools))
+	for name, v := range req.Tools {
+		t, ok := v.(tool.Tool)
+		if !ok {
+			return nil, fmt.Errorf("unexpected tool type %T for tool %v", v, name)
+		}
+		tools[name] = t
+	}
+	return tools, nil
+}
@@ -15,15 +15,19 @@
 package llminternal
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"iter"
 	"maps"
+	"runtime/debug"
 	"slices"
+	"sync"
 
 	"google.golang.org/genai"
 
 	"google.golang.org/adk/agent"
+	"google.golang.org/adk/auth"
 	"google.golang.org/adk/internal/agent/parentmap"
 	"google.golang.org/adk/internal/agent/runconfig"
 	icontext "google.golang.org/adk/internal/context"
@@ -33,6 +37,8 @@ import (
 	"google.golang.org/adk/model"
 	"google.golang.org/adk/session"
 	"google.golang.org/adk/tool"
+	"google.golang.org/adk/tool/toolconfirmation"
+	"google.golang.org/adk/usage"
 )
 
 var ErrModelNotConfigured = errors.New("model not configured; ensure Model is set in llmagent.Config")
@@ -54,13 +60,27 @@ type Flow struct {
 	AfterModelCallbacks  []AfterModelCallback
 	BeforeToolCallbacks  []BeforeToolCallback
 	AfterToolCallbacks   []AfterToolCallback
+
+	// ParallelToolCalls runs the function calls of a model response
+	// concurrently, see handleFunctionCalls.
+	ParallelToolCalls bool
+	// MaxParallelToolCalls is the maximum number of function calls run at
+	// once with ParallelToolCalls. Zero means no limit.
+	MaxParallelToolCalls int
+	// ToolInterceptors wrap the Run of the tools, inside the interceptors of
+	// the run, see runTool.
+	ToolInterceptors []tool.Interceptor
+	// InvalidArgsRetries limits the consecutive calls of a tool with invalid
+	// arguments reported to the model, see handleFunctionCall. Zero means no
+	// limit.
+	InvalidArgsRetries int
 }
 
 var (
 	DefaultRequestProcessors = []func(ctx agent.InvocationContext, req *model.LLMRequest) error{
 		basicRequestProcessor,
-		authPreprocessor,
 		instructionsRequestProcessor,
+		localContextRequestProcessor,
 		identityRequestProcessor,
 		ContentsRequestProcessor,
 		// Some implementations of NL Planning mark planning contents as thoughts in the post processor.
@@ -79,6 +99,9 @@ var (
 )
 
 func (f *Flow) Run(ctx agent.InvocationContext) iter.Seq2[*session.Event, error] {
+	if runCfg := runconfig.FromContext(ctx); runCfg != nil && runCfg.StreamingMode == runconfig.StreamingModeBidi && runCfg.LiveRequestQueue != nil {
+		return f.runLive(ctx, runCfg.LiveRequestQueue)
+	}
 	return func(yield func(*session.Event, error) bool) {
 		for {
 			var lastEvent *session.Event
@@ -97,9 +120,10 @@ func (f *Flow) Run(ctx agent.InvocationContext) iter.Seq2[*session.Event, error]
 				return
 			}
 			if lastEvent.LLMResponse.Partial {
-				// We may have reached max token limit during streaming mode.
-				// TODO: handle Partial response in model level. CL 781377328
-				yield(nil, fmt.Errorf("TODO: last event is not final"))
+				// The partial responses ending a stream are aggregated by
+				// callLLM, so only an after model callback can end a step
+				// with one.
+				yield(nil, fmt.Errorf("agent %q: the last event of the model call is partial", ctx.Agent().Name()))
 				return
 			}
 		}
@@ -117,6 +141,31 @@ func (f *Flow) runOneStep(ctx agent.InvocationContext) iter.Seq2[*session.Event,
 			Model: f.Model.Name(),
 		}
 
+		// Run again the function calls whose credentials or confirmations
+		// were sent by the client, before building the request with their
+		// responses, as adk-python src/google/adk/auth/auth_preprocessor.py
+		// and src/google/adk/flows/llm_flows/request_confirmation.py do.
+		for _, resume := range []func(agent.InvocationContext, *partialEmitter) (*session.Event, error){f.resumeAuthorizedCalls, f.resumeConfirmedCalls} {
+			emitter := &partialEmitter{yield: yield}
+			resumed, err := resume(ctx, emitter)
+			if emitter.stopped {
+				return
+			}
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+			if resumed == nil {
+				continue
+			}
+			if !yield(resumed, nil) {
+				return
+			}
+			if !yieldRequestEvents(ctx, sessionFunctionCalls(ctx), resumed, yield) {
+				return
+			}
+		}
+
 		// Preprocess before calling the LLM.
 		if err := f.preprocess(ctx, req); err != nil {
 			yield(nil, err)
@@ -125,12 +174,18 @@ func (f *Flow) runOneStep(ctx agent.InvocationContext) iter.Seq2[*session.Event,
 		if ctx.Ended() {
 			return
 		}
-		spans := telemetry.StartTrace(ctx, "call_llm")
+		// The span of the model call is the parent of the spans of the
+		// model client and of the calls of the tools of the response.
+		spanCtx, spans := telemetry.StartTrace(ctx, "call_llm")
+		var llmErr error
+		defer func() { telemetry.EndTrace(spans, llmErr) }()
+		llmCtx := icontext.WithContext(ctx, spanCtx)
 		// Create event to pass to callback state delta
 		stateDelta := make(map[string]any)
 		// Calls the LLM.
-		for resp, err := range f.callLLM(ctx, req, stateDelta) {
+		for resp, err := range f.callLLM(llmCtx, req, stateDelta) {
 			if err != nil {
+				llmErr = err
 				yield(nil, err)
 				return
 			}
@@ -163,11 +218,32 @@ func (f *Flow) runOneStep(ctx agent.InvocationContext) iter.Seq2[*session.Event,
 			if !yield(modelResponseEvent, nil) {
 				return
 			}
-			// TODO: generate and yield an auth event if needed.
+			if err := countTokens(ctx, resp); err != nil {
+				yield(nil, err)
+				return
+			}
+
+			// Run the code of the response, whose result is sent to the
+			// model in the next step.
+			codeEvent, err := runCode(ctx, resp)
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+			if codeEvent != nil {
+				if !yield(codeEvent, nil) {
+					return
+				}
+				continue
+			}
 
 			// Handle function calls.
 
-			ev, err := f.handleFunctionCalls(ctx, tools, resp)
+			emitter := &partialEmitter{yield: yield}
+			ev, err := f.handleFunctionCalls(llmCtx, tools, resp, emitter, nil)
+			if emitter.stopped {
+				return
+			}
 			if err != nil {
 				yield(nil, err)
 				return
@@ -179,6 +255,13 @@ func (f *Flow) runOneStep(ctx agent.InvocationContext) iter.Seq2[*session.Event,
 			if !yield(ev, nil) {
 				return
 			}
+			calls := make(map[string]*genai.FunctionCall)
+			for _, fc := range utils.FunctionCalls(resp.Content) {
+				calls[fc.ID] = fc
+			}
+			if !yieldRequestEvents(ctx, calls, ev, yield) {
+				return
+			}
 
 			// Actually handle "transfer_to_agent" tool. The function call sets the ev.Actions.TransferToAgent field.
 			// We are following python's execution flow which is
@@ -202,6 +285,45 @@ func (f *Flow) runOneStep(ctx agent.InvocationContext) iter.Seq2[*session.Event,
 	}
 }
 
+// countTokens counts the tokens of the final response of a model call in the
+// token budget of the run, and returns the limit error once it is exceeded.
+func countTokens(ctx agent.InvocationContext, resp *model.LLMResponse) error {
+	if resp.Partial || resp.UsageMetadata == nil {
+		return nil
+	}
+	if runCfg := runconfig.FromContext(ctx); !runCfg.CountTokens(int(resp.UsageMetadata.TotalTokenCount)) {
+		return &agent.LimitExceededError{Limit: agent.LimitTokens, Max: runCfg.MaxTokens}
+	}
+	return nil
+}
+
+// yieldRequestEvents yields the events asking the client for the credentials
+// and the confirmations requested by the tools in the function response
+// event, calls being the function calls of the invocation keyed by ID. It
+// reports whether the invocation goes on: it ends once request events, or an
+// error, were yielded, until the client answers them.
+func yieldRequestEvents(ctx agent.InvocationContext, calls map[string]*genai.FunctionCall, fnResponseEvent *session.Event, yield func(*session.Event, error) bool) bool {
+	authEvent, err := generateAuthEvent(ctx, fnResponseEvent)
+	if err != nil {
+		yield(nil, err)
+		return false
+	}
+	confirmationEvent, err := generateConfirmationEvent(ctx, calls, fnResponseEvent)
+	if err != nil {
+		yield(nil, err)
+		return false
+	}
+	if authEvent == nil && confirmationEvent == nil {
+		return true
+	}
+	for _, ev := range []*session.Event{authEvent, confirmationEvent} {
+		if ev != nil && !yield(ev, nil) {
+			break
+		}
+	}
+	return false
+}
+
 func (f *Flow) preprocess(ctx agent.InvocationContext, req *model.LLMRequest) error {
 	llmAgent, ok := ctx.Agent().(Agent)
 	if !ok {
@@ -216,17 +338,63 @@ func (f *Flow) preprocess(ctx agent.InvocationContext, req *model.LLMRequest) er
 	}
 
 	// run processors for tools.
-	tools := Reveal(llmAgent).Tools
-	for _, toolSet := range Reveal(llmAgent).Toolsets {
+	state := Reveal(llmAgent)
+	tools := slices.Clone(state.Tools)
+	toolsetNames := make([]string, len(tools))
+	for _, toolSet := range state.Toolsets {
 		tsTools, err := toolSet.Tools(icontext.NewReadonlyContext(ctx))
 		if err != nil {
 			return fmt.Errorf("failed to extract tools from the tool set %q: %w", toolSet.Name(), err)
 		}
 
 		tools = append(tools, tsTools...)
+		for range tsTools {
+			toolsetNames = append(toolsetNames, toolSet.Name())
+		}
 	}
 
-	return toolPreprocess(ctx, req, tools)
+	return toolPreprocess(ctx, req, resolveToolConflicts(req, tools, toolsetNames, state.ToolConflicts))
+}
+
+// resolveToolConflicts returns the tools with those whose name is taken by a
+// previous tool, or by a tool already in the request, handled according to
+// the policy, see tool.ConflictPolicy. toolsetNames are the names of the
+// toolsets of the tools, empty for the tools of the agent.
+func resolveToolConflicts(req *model.LLMRequest, tools []tool.Tool, toolsetNames []string, policy tool.ConflictPolicy) []tool.Tool {
+	if policy == tool.ConflictError {
+		return tools
+	}
+	taken := func(name string) bool {
+		_, ok := req.Tools[name]
+		return ok
+	}
+	names := make(map[string]bool, len(tools))
+	var resolved []tool.Tool
+	for i, t := range tools {
+		name := t.Name()
+		if names[name] || taken(name) {
+			if policy == tool.ConflictPreferFirst {
+				continue
+			}
+			funcTool, ok := t.(toolinternal.FunctionTool)
+			if !ok {
+				// The collision is reported when the tool is registered.
+				resolved = append(resolved, t)
+				continue
+			}
+			var renamed string
+			if toolsetNames[i] != "" {
+				renamed = toolsetNames[i] + "_" + name
+			}
+			for n := 2; renamed == "" || names[renamed] || taken(renamed); n++ {
+				renamed = fmt.Sprintf("%s_%d", name, n)
+			}
+			t, name = toolinternal.Rename(funcTool, renamed, ""), renamed
+		}
+		names[name] = true
+		resolved = append(resolved, t)
+	}
+	return resolved
 }
 
 // toolPreprocess runs tool preprocess on the given request
@@ -249,7 +417,7 @@ func toolPreprocess(ctx agent.InvocationContext, req *model.LLMRequest, tools []
 
 func (f *Flow) callLLM(ctx agent.InvocationContext, req *model.LLMRequest, stateDelta map[string]any) iter.Seq2[*model.LLMResponse, error] {
 	return func(yield func(*model.LLMResponse, error) bool) {
-		for _, callback := range f.BeforeModelCallbacks {
+		for _, callback := range f.beforeModelCallbacks(ctx) {
 			cctx := icontext.NewCallbackContextWithDelta(ctx, stateDelta)
 			callbackResponse, callbackErr := callback(cctx, req)
 
@@ -262,39 +430,81 @@ func (f *Flow) callLLM(ctx agent.InvocationContext, req *model.LLMRequest, state
 		// TODO: Set _ADK_AGENT_NAME_LABEL_KEY in req.GenerateConfig.Labels
 		// to help with slicing the billing reports on a per-agent basis.
 
+		runCfg := runconfig.FromContext(ctx)
+		if !runCfg.CountLLMCall() {
+			yield(nil, &agent.LimitExceededError{Limit: agent.LimitLLMCalls, Max: runCfg.MaxLLMCalls})
+			return
+		}
+
 		// TODO: RunLive mode when invocation_context.run_config.support_cfc is true.
-		useStream := runconfig.FromContext(ctx).StreamingMode == runconfig.StreamingModeSSE
+		useStream := runCfg.StreamingMode == runconfig.StreamingModeSSE
 
+		// partials are the partial responses of the stream since its last
+		// complete response. They are aggregated in a final response if the
+		// model does not, so that the partial events are always followed by a
+		// complete one.
+		var partials []*model.LLMResponse
 		for resp, err := range f.Model.GenerateContent(ctx, req, useStream) {
-			callbackResp, callbackErr := f.runAfterModelCallbacks(ctx, resp, stateDelta, err)
-			// TODO: check if we should stop iterator on the first error from stream or continue yielding next results.
-			if callbackErr != nil {
-				yield(nil, callbackErr)
-				return
-			}
-
-			if callbackResp != nil {
-				if !yield(callbackResp, nil) {
-					return
+			if err == nil && resp != nil {
+				if resp.Partial {
+					partials = append(partials, resp)
+				} else {
+					partials = nil
 				}
-				continue
-			}
-
-			// TODO: check if we should stop iterator on the first error from stream or continue yielding next results.
-			if err != nil {
-				yield(nil, err)
-				return
 			}
-
-			if !yield(resp, nil) {
+			if !f.yieldModelResponse(ctx, req, resp, err, stateDelta, yield) {
 				return
 			}
 		}
+		if aggregated := aggregatePartialResponses(partials); aggregated != nil {
+			f.yieldModelResponse(ctx, req, aggregated, nil, stateDelta, yield)
+		}
+	}
+}
+
+// yieldModelResponse yields a response of the model, or the response or the
+// error of the after model callbacks. It reports whether the model call goes
+// on.
+func (f *Flow) yieldModelResponse(ctx agent.InvocationContext, req *model.LLMRequest, resp *model.LLMResponse, err error, stateDelta map[string]any, yield func(*model.LLMResponse, error) bool) bool {
+	callbackResp, callbackErr := f.runAfterModelCallbacks(ctx, resp, stateDelta, err)
+	// TODO: check if we should stop iterator on the first error from stream or continue yielding next results.
+	if callbackErr != nil {
+		yield(nil, callbackErr)
+		return false
 	}
+	if callbackResp != nil {
+		return yield(callbackResp, nil)
+	}
+
+	// TODO: check if we should stop iterator on the first error from stream or continue yielding next results.
+	if err != nil {
+		yield(nil, err)
+		return false
+	}
+
+	attributeToolUsage(req, resp)
+	return yield(resp, nil)
+}
+
+// attributeToolUsage records the prompt tokens attributed to each tool in the
+// custom metadata of the final response of a model call reporting usage.
+// See the usage package for the methodology.
+func attributeToolUsage(req *model.LLMRequest, resp *model.LLMResponse) {
+	if resp == nil || resp.Partial || resp.UsageMetadata == nil {
+		return
+	}
+	attribution := usage.AttributeTools(req, resp.UsageMetadata.PromptTokenCount)
+	if len(attribution) == 0 {
+		return
+	}
+	if resp.CustomMetadata == nil {
+		resp.CustomMetadata = make(map[string]any)
+	}
+	resp.CustomMetadata[usage.MetadataKey] = usage.Metadata(attribution)
 }
 
 func (f *Flow) runAfterModelCallbacks(ctx agent.InvocationContext, llmResp *model.LLMResponse, stateDelta map[string]any, llmErr error) (*model.LLMResponse, error) {
-	for _, callback := range f.AfterModelCallbacks {
+	for _, callback := range f.afterModelCallbacks(ctx) {
 		cctx := icontext.NewCallbackContextWithDelta(ctx, stateDelta)
 		callbackResponse, callbackErr := callback(cctx, llmResp, llmErr)
 
@@ -366,73 +576,282 @@ func findLongRunningFunctionCallIDs(c *genai.Content, tools map[string]tool.Tool
 // handleFunctionCalls calls the functions and returns the function response event.
 //
 // TODO: accept filters to include/exclude function calls.
-// TODO: check feasibility of running tool.Run concurrently.
-func (f *Flow) handleFunctionCalls(ctx agent.InvocationContext, toolsDict map[string]tool.Tool, resp *model.LLMResponse) (*session.Event, error) {
-	var fnResponseEvents []*session.Event
-
+//
+// The calls are run in order, or concurrently with ParallelToolCalls, at most
+// MaxParallelToolCalls at once, except those of the sequential tools, run one
+// at a time once the others returned. A panic in a concurrent call fails the
+// invocation rather than the process.
+// Either way, the function responses are in the order of the calls.
+//
+// The partial results of the tools are yielded with the emitter, if not nil,
+// while they run.
+//
+// The confirmations sent by the client, keyed by function call ID, are given
+// to the tools of the confirmed or declined calls.
+func (f *Flow) handleFunctionCalls(ctx agent.InvocationContext, toolsDict map[string]tool.Tool, resp *model.LLMResponse, emitter *partialEmitter, confirmations map[string]*toolconfirmation.ToolConfirmation) (*session.Event, error) {
 	fnCalls := utils.FunctionCalls(resp.Content)
-	for _, fnCall := range fnCalls {
+	funcTools := make([]toolinternal.FunctionTool, len(fnCalls))
+	for i, fnCall := range fnCalls {
 		curTool, ok := toolsDict[fnCall.Name]
 		if !ok {
-			return nil, fmt.Errorf("unknown tool: %q", fnCall.Name)
+			return nil, fmt.Errorf("%w: %q", tool.ErrToolNotFound, fnCall.Name)
 		}
 		funcTool, ok := curTool.(toolinternal.FunctionTool)
 		if !ok {
 			return nil, fmt.Errorf("tool %q is not a function tool", curTool.Name())
 		}
-		toolCtx := toolinternal.NewToolContext(ctx, fnCall.ID, &session.EventActions{StateDelta: make(map[string]any)})
-		// toolCtx := tool.
-		spans := telemetry.StartTrace(ctx, "execute_tool "+fnCall.Name)
+		funcTools[i] = funcTool
+	}
+	if runCfg := runconfig.FromContext(ctx); len(fnCalls) > 0 && !runCfg.CountToolCalls(len(fnCalls)) {
+		return nil, &agent.LimitExceededError{Limit: agent.LimitToolCalls, Max: runCfg.MaxToolCalls}
+	}
 
-		result := f.callTool(funcTool, fnCall.Args, toolCtx)
+	fnResponseEvents := make([]*session.Event, len(fnCalls))
+	errs := make([]error, len(fnCalls))
+	call := func(i int) {
+		fnResponseEvents[i], errs[i] = f.handleFunctionCall(ctx, funcTools[i], fnCalls[i], emitter, confirmations)
+	}
+	var sequential []int
+	if f.ParallelToolCalls && len(fnCalls) > 1 {
+		var parallel []int
+		var parallelCalls []*genai.FunctionCall
+		for i := range fnCalls {
+			if toolinternal.IsSequential(funcTools[i]) {
+				sequential = append(sequential, i)
+				continue
+			}
+			parallel = append(parallel, i)
+			parallelCalls = append(parallelCalls, fnCalls[i])
+		}
+		parallelErrs := toolinternal.RunParallel(ctx, parallelCalls, toolinternal.ParallelOptions{
+			MaxParallelism: f.MaxParallelToolCalls,
+		}, func(_ context.Context, j int) error {
+			call(parallel[j])
+			return nil
+		})
+		for j, err := range parallelErrs {
+			if err != nil {
+				errs[parallel[j]] = err
+			}
+		}
+	} else {
+		for i := range fnCalls {
+			sequential = append(sequential, i)
+		}
+	}
+	for _, i := range sequential {
+		call(i)
+		if errs[i] != nil {
+			break
+		}
+	}
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	mergedEvent, err := mergeParallelFunctionResponseEvents(fnResponseEvents)
+	if err != nil {
+		return mergedEvent, err
+	}
+	// this is needed for debug traces of parallel calls
+	_, spans := telemetry.StartTrace(ctx, "execute_tool (merged)")
+	telemetry.TraceMergedToolCalls(spans, mergedEvent)
+	return mergedEvent, nil
+}
+
+// handleFunctionCall runs the function call, and returns its function response event.
+// It only fails if the invocation is canceled, or if the arguments of the
+// call are invalid once InvalidArgsRetries is exhausted: the other failures
+// of the call are reported to the model.
+func (f *Flow) handleFunctionCall(ctx agent.InvocationContext, funcTool toolinternal.FunctionTool, fnCall *genai.FunctionCall, emitter *partialEmitter, confirmations map[string]*toolconfirmation.ToolConfirmation) (*session.Event, error) {
+	spanCtx, spans := telemetry.StartTrace(ctx, "execute_tool "+fnCall.Name)
+	toolCtx := toolinternal.NewToolContext(icontext.WithContext(ctx, spanCtx), fnCall.ID, &session.EventActions{StateDelta: make(map[string]any)})
+	toolCtx = toolinternal.WithFunctionCall(toolCtx, fnCall)
+	if emitter != nil {
+		toolCtx = toolinternal.WithPartialEmitter(toolCtx, emitter.emitFunc(ctx, fnCall))
+	}
+	if confirmation, ok := confirmations[fnCall.ID]; ok {
+		toolCtx = toolinternal.WithToolConfirmation(toolCtx, confirmation)
+	}
+
+	result, err := f.runTool(funcTool, fnCall.Args, toolCtx)
+	if err == nil {
+		err = toolinternal.CommitState(toolCtx)
+	}
+	if err != nil {
+		// The state changes of a failed call are discarded.
+		toolinternal.DiscardState(toolCtx)
+		// The failure is reported to the model, unless the invocation
+		// is canceled: see tool.ToolError.
+		if ctx.Err() != nil || f.invalidArgsExhausted(ctx, fnCall.Name, err) {
+			telemetry.EndTrace(spans, err)
+			return nil, fmt.Errorf("tool %q: %w", fnCall.Name, err)
+		}
+		result = toolinternal.ErrorResult(err)
+		// The stack of a panic is recorded in the event, not sent to the model.
+		var panicErr *tool.PanicError
+		if errors.As(err, &panicErr) {
+			toolinternal.SetResponseMetadata(toolCtx, tool.PanicMetadataKey, map[string]any{
+				"value": fmt.Sprint(panicErr.Value),
+				"stack": string(panicErr.Stack),
+			})
+		}
+	}
+	// The media parts of the result follow its function response.
+	result, contentParts := toolinternal.SplitContentResult(result)
+
+	// TODO: agent.canonical_after_tool_callbacks
+	// TODO: handle long-running tool.
+	ev := session.NewEvent(ctx.InvocationID())
+	ev.LLMResponse = model.LLMResponse{
+		Content: &genai.Content{
+			Role: "user",
+			Parts: append([]*genai.Part{
+				{
+					FunctionResponse: &genai.FunctionResponse{
+						ID:       fnCall.ID,
+						Name:     fnCall.Name,
+						Response: result,
+					},
+				},
+			}, contentParts...),
+		},
+	}
+	for key, val := range toolinternal.ResponseMetadata(toolCtx) {
+		if ev.CustomMetadata == nil {
+			ev.CustomMetadata = make(map[string]any)
+		}
+		ev.CustomMetadata[key] = map[string]any{fnCall.ID: val}
+	}
+	ev.Author = ctx.Agent().Name()
+	ev.Branch = ctx.Branch()
+	ev.Actions = *toolCtx.Actions()
+	telemetry.TraceToolCall(spans, funcTool, fnCall.Args, ev)
+	return ev, nil
+}
+
+// invalidArgsExhausted reports whether the call of the tool failed with err
+// because of invalid arguments, see tool.CodeInvalidArguments, after
+// InvalidArgsRetries consecutive such failures of the tool in the
+// invocation.
+func (f *Flow) invalidArgsExhausted(ctx agent.InvocationContext, toolName string, err error) bool {
+	var toolErr *tool.ToolError
+	if f.InvalidArgsRetries <= 0 || !errors.As(err, &toolErr) || toolErr.Code != tool.CodeInvalidArguments {
+		return false
+	}
+	failures := 0
+	events := ctx.Session().Events()
+	for i := events.Len() - 1; i >= 0 && failures < f.InvalidArgsRetries; i-- {
+		ev := events.At(i)
+		if ev.InvocationID != ctx.InvocationID() || ev.Partial {
+			continue
+		}
+		for _, fr := range slices.Backward(utils.FunctionResponses(ev.Content)) {
+			if fr.Name != toolName {
+				continue
+			}
+			if !isInvalidArgsResponse(fr.Response) {
+				return false
+			}
+			failures++
+		}
+	}
+	return failures >= f.InvalidArgsRetries
+}
 
-		// TODO: agent.canonical_after_tool_callbacks
-		// TODO: handle long-running tool.
+// isInvalidArgsResponse reports whether the function response reports
+// invalid arguments, see toolinternal.ErrorResult.
+func isInvalidArgsResponse(response map[string]any) bool {
+	payload, ok := response["error"].(map[string]any)
+	return ok && payload["code"] == tool.CodeInvalidArguments
+}
+
+// partialEmitter yields the partial results of the tools as partial
+// function response events.
+type partialEmitter struct {
+	yield func(*session.Event, error) bool
+
+	// mu serializes the partial results emitted from several goroutines.
+	mu sync.Mutex
+	// stopped is set once the consumer stopped: nothing must be yielded
+	// anymore.
+	stopped bool
+}
+
+func (e *partialEmitter) emitFunc(ctx agent.InvocationContext, fnCall *genai.FunctionCall) func(map[string]any) error {
+	return func(partial map[string]any) error {
+		e.mu.Lock()
+		defer e.mu.Unlock()
+		if e.stopped {
+			return toolinternal.ErrPartialsStopped
+		}
 		ev := session.NewEvent(ctx.InvocationID())
 		ev.LLMResponse = model.LLMResponse{
 			Content: &genai.Content{
 				Role: "user",
-				Parts: []*genai.Part{
-					{
-						FunctionResponse: &genai.FunctionResponse{
-							ID:       fnCall.ID,
-							Name:     fnCall.Name,
-							Response: result,
-						},
+				Parts: []*genai.Part{{
+					FunctionResponse: &genai.FunctionResponse{
+						ID:           fnCall.ID,
+						Name:         fnCall.Name,
+						Response:     partial,
+						WillContinue: genai.Ptr(true),
 					},
-				},
+				}},
 			},
+			Partial: true,
 		}
 		ev.Author = ctx.Agent().Name()
 		ev.Branch = ctx.Branch()
-		ev.Actions = *toolCtx.Actions()
-		telemetry.TraceToolCall(spans, curTool, fnCall.Args, ev)
-		fnResponseEvents = append(fnResponseEvents, ev)
-	}
-	mergedEvent, err := mergeParallelFunctionResponseEvents(fnResponseEvents)
-	if err != nil {
-		return mergedEvent, err
+		if !e.yield(ev, nil) {
+			e.stopped = true
+			return toolinternal.ErrPartialsStopped
+		}
+		return nil
 	}
-	// this is needed for debug traces of parallel calls
-	spans := telemetry.StartTrace(ctx, "execute_tool (merged)")
-	telemetry.TraceMergedToolCalls(spans, mergedEvent)
-	return mergedEvent, nil
 }
 
-func (f *Flow) callTool(tool toolinternal.FunctionTool, fArgs map[string]any, toolCtx tool.Context) map[string]any {
-	result, err := f.invokeBeforeToolCallbacks(tool, fArgs, toolCtx)
+// runTool runs the tool with the tool callbacks. A panic of the tool or of a
+// callback fails the call with a *tool.PanicError.
+func (f *Flow) runTool(funcTool toolinternal.FunctionTool, fArgs map[string]any, toolCtx tool.Context) (result map[string]any, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			result, err = nil, &tool.PanicError{Tool: funcTool.Name(), Value: r, Stack: debug.Stack()}
+		}
+	}()
+	result, err = f.invokeBeforeToolCallbacks(funcTool, fArgs, toolCtx)
 	if result == nil && err == nil {
-		result, err = tool.Run(toolCtx, fArgs)
+		result, err = f.toolHandler(toolCtx)(toolCtx, funcTool, fArgs)
 	}
-	result, err = f.invokeAfterToolCallbacks(tool, fArgs, toolCtx, result, err)
-	if err != nil {
-		return map[string]any{"error": err.Error()}
+	return f.invokeAfterToolCallbacks(funcTool, fArgs, toolCtx, result, err)
+}
+
+// toolHandler returns the handler running the tools, wrapped by the tool
+// interceptors of the run, then by the ones of the flow.
+func (f *Flow) toolHandler(ctx tool.Context) tool.Handler {
+	handler := func(ctx tool.Context, t tool.Tool, args map[string]any) (map[string]any, error) {
+		funcTool, ok := t.(toolinternal.FunctionTool)
+		if !ok {
+			return nil, fmt.Errorf("tool %q is not a function tool", t.Name())
+		}
+		return funcTool.Run(ctx, args)
+	}
+	var interceptors []tool.Interceptor
+	if ctx != nil {
+		if cfg := runconfig.FromContext(ctx); cfg != nil {
+			interceptors = append(interceptors, cfg.ToolInterceptors...)
+		}
+	}
+	interceptors = append(interceptors, f.ToolInterceptors...)
+	if len(interceptors) == 0 {
+		return handler
 	}
-	return result
+	return tool.Chain(interceptors...)(handler)
 }
 
 func (f *Flow) invokeBeforeToolCallbacks(tool toolinternal.FunctionTool, fArgs map[string]any, toolCtx tool.Context) (map[string]any, error) {
-	for _, callback := range f.BeforeToolCallbacks {
+	for _, callback := range f.beforeToolCallbacks(toolCtx) {
 		result, err := callback(toolCtx, tool, fArgs)
 		if err != nil {
 			return nil, err
@@ -447,7 +866,7 @@ func (f *Flow) invokeBeforeToolCallbacks(tool toolinternal.FunctionTool, fArgs m
 }
 
 func (f *Flow) invokeAfterToolCallbacks(tool toolinternal.FunctionTool, fArgs map[string]any, toolCtx tool.Context, fResult map[string]any, fErr error) (map[string]any, error) {
-	for _, callback := range f.AfterToolCallbacks {
+	for _, callback := range f.afterToolCallbacks(toolCtx) {
 		result, err := callback(toolCtx, tool, fArgs, fResult, fErr)
 		if err != nil {
 			return nil, err
@@ -462,6 +881,55 @@ func (f *Flow) invokeAfterToolCallbacks(tool toolinternal.FunctionTool, fArgs ma
 	return fResult, fErr
 }
 
+// beforeModelCallbacks returns the before model callbacks of the plugins of
+// the run, followed by the ones of the agent. The same goes for the other
+// callbacks below.
+func (f *Flow) beforeModelCallbacks(ctx context.Context) []BeforeModelCallback {
+	var callbacks []BeforeModelCallback
+	if runCfg := runconfig.FromContext(ctx); runCfg != nil {
+		for _, c := range runCfg.BeforeModelCallbacks {
+			callbacks = append(callbacks, c)
+		}
+	}
+	return append(callbacks, f.BeforeModelCallbacks...)
+}
+
+func (f *Flow) afterModelCallbacks(ctx context.Context) []AfterModelCallback {
+	var callbacks []AfterModelCallback
+	if runCfg := runconfig.FromContext(ctx); runCfg != nil {
+		for _, c := range runCfg.AfterModelCallbacks {
+			callbacks = append(callbacks, c)
+		}
+	}
+	return append(callbacks, f.AfterModelCallbacks...)
+}
+
+func (f *Flow) beforeToolCallbacks(ctx context.Context) []BeforeToolCallback {
+	var callbacks []BeforeToolCallback
+	if ctx == nil {
+		return f.BeforeToolCallbacks
+	}
+	if runCfg := runconfig.FromContext(ctx); runCfg != nil {
+		for _, c := range runCfg.BeforeToolCallbacks {
+			callbacks = append(callbacks, c)
+		}
+	}
+	return append(callbacks, f.BeforeToolCallbacks...)
+}
+
+func (f *Flow) afterToolCallbacks(ctx context.Context) []AfterToolCallback {
+	var callbacks []AfterToolCallback
+	if ctx == nil {
+		return f.AfterToolCallbacks
+	}
+	if runCfg := runconfig.FromContext(ctx); runCfg != nil {
+		for _, c := range runCfg.AfterToolCallbacks {
+			callbacks = append(callbacks, c)
+		}
+	}
+	return append(callbacks, f.AfterToolCallbacks...)
+}
+
 func mergeParallelFunctionResponseEvents(events []*session.Event) (*session.Event, error) {
 	switch len(events) {
 	case 0:
@@ -471,12 +939,27 @@ func mergeParallelFunctionResponseEvents(events []*session.Event) (*session.Even
 	}
 	var parts []*genai.Part
 	var actions *session.EventActions
+	var metadata map[string]any
 	for _, ev := range events {
 		if ev == nil || ev.LLMResponse.Content == nil {
 			continue
 		}
 		parts = append(parts, ev.LLMResponse.Content.Parts...)
 		actions = mergeEventActions(actions, &ev.Actions)
+		// The metadata of the calls are maps keyed by function call ID, see
+		// toolinternal.SetResponseMetadata.
+		for key, val := range ev.CustomMetadata {
+			calls, _ := val.(map[string]any)
+			if metadata == nil {
+				metadata = make(map[string]any)
+			}
+			merged, _ := metadata[key].(map[string]any)
+			if merged == nil {
+				merged = make(map[string]any)
+				metadata[key] = merged
+			}
+			maps.Copy(merged, calls)
+		}
 	}
 	// reuse events[0]
 	ev := events[0]
@@ -485,6 +968,7 @@ func mergeParallelFunctionResponseEvents(events []*session.Event) (*session.Even
 			Role:  "user",
 			Parts: parts,
 		},
+		CustomMetadata: metadata,
 	}
 	ev.Actions = *actions
 	return ev, nil
@@ -511,8 +995,24 @@ func mergeEventActions(base, other *session.EventActions) *session.EventActions
 	if other.Escalate {
 		base.Escalate = true
 	}
+	if other.RequestedAuthConfigs != nil {
+		if base.RequestedAuthConfigs == nil {
+			base.RequestedAuthConfigs = make(map[string]*auth.AuthConfig)
+		}
+		maps.Copy(base.RequestedAuthConfigs, other.RequestedAuthConfigs)
+	}
+	if other.RequestedToolConfirmations != nil {
+		if base.RequestedToolConfirmations == nil {
+			base.RequestedToolConfirmations = make(map[string]*toolconfirmation.ToolConfirmation)
+		}
+		maps.Copy(base.RequestedToolConfirmations, other.RequestedToolConfirmations)
+	}
 	if other.StateDelta != nil {
-		base.StateDelta = other.StateDelta
+		// Each call staged its own state changes, the later ones win.
+		if base.StateDelta == nil {
+			base.StateDelta = make(map[string]any)
+		}
+		maps.Copy(base.StateDelta, other.StateDelta)
 	}
 	return base
 }
@@ -16,6 +16,7 @@ package llminternal
 
 import (
 	"errors"
+	"fmt"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
@@ -257,6 +258,26 @@ func TestCallTool(t *testing.T) {
 			},
 			want: map[string]any{"result": "error_handled_in_after"},
 		},
+		{
+			name: "tool error is structured",
+			tool: &mockFunctionTool{
+				name: "testTool",
+				runFunc: func(ctx tool.Context, args map[string]any) (map[string]any, error) {
+					return nil, fmt.Errorf("lookup: %w", &tool.ToolError{Code: "not_found", Message: "no such city"})
+				},
+			},
+			want: map[string]any{"error": map[string]any{"code": "not_found", "message": "no such city"}},
+		},
+		{
+			name: "tool error without code",
+			tool: &mockFunctionTool{
+				name: "testTool",
+				runFunc: func(ctx tool.Context, args map[string]any) (map[string]any, error) {
+					return nil, &tool.ToolError{Message: "try again later"}
+				},
+			},
+			want: map[string]any{"error": map[string]any{"message": "try again later"}},
+		},
 	}
 
 	for _, tc := range tests {
@@ -266,9 +287,12 @@ func TestCallTool(t *testing.T) {
 				AfterToolCallbacks:  tc.afterToolCallbacks,
 			}
 
-			got := f.callTool(tc.tool, tc.args, nil)
+			got, err := f.runTool(tc.tool, tc.args, nil)
+			if err != nil {
+				got = toolinternal.ErrorResult(err)
+			}
 			if diff := cmp.Diff(tc.want, got); diff != "" {
-				t.Errorf("callTool() mismatch (-want +got):\n%s", diff)
+				t.Errorf("runTool() mismatch (-want +got):\n%s", diff)
 			}
 		})
 	}
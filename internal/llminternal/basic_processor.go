@@ -16,6 +16,7 @@ package llminternal
 
 import (
 	"fmt"
+	"maps"
 	"reflect"
 
 	"google.golang.org/genai"
@@ -33,7 +34,11 @@ func basicRequestProcessor(ctx agent.InvocationContext, req *model.LLMRequest) e
 	if llmAgent == nil {
 		return nil // do nothing.
 	}
-	req.Config = clone(llmAgent.internal().GenerateContentConfig)
+	var override *genai.GenerateContentConfig
+	if cfg := ctx.RunConfig(); cfg != nil {
+		override = cfg.GenerateContentConfig
+	}
+	req.Config = mergeGenerateContentConfig(llmAgent.internal().GenerateContentConfig, override)
 	if req.Config == nil {
 		req.Config = &genai.GenerateContentConfig{}
 	}
@@ -41,11 +46,62 @@ func basicRequestProcessor(ctx agent.InvocationContext, req *model.LLMRequest) e
 		req.Config.ResponseSchema = llmAgent.internal().OutputSchema
 		req.Config.ResponseMIMEType = "application/json"
 	}
-	// TODO: missing features
-	//  populate LLMRequest LiveConnectConfig setting
+	if cfg := ctx.RunConfig(); cfg != nil && cfg.StreamingMode == agent.StreamingModeBidi {
+		req.LiveConnectConfig = &genai.LiveConnectConfig{
+			ResponseModalities:       cfg.ResponseModalities,
+			SpeechConfig:             cfg.SpeechConfig,
+			InputAudioTranscription:  cfg.InputAudioTranscription,
+			OutputAudioTranscription: cfg.OutputAudioTranscription,
+			RealtimeInputConfig:      cfg.RealtimeInputConfig,
+		}
+	}
 	return nil
 }
 
+// mergeGenerateContentConfig returns a deep copy of the base config with the
+// fields set in override, see agent.RunConfig.GenerateContentConfig:
+//   - Tools and the parts of SystemInstruction are appended to the base ones;
+//   - Labels are merged, the override winning on common keys;
+//   - any other field set in override, i.e. not the zero value, replaces the
+//     base one as a whole, including slices such as StopSequences and
+//     pointers to structs such as ThinkingConfig.
+func mergeGenerateContentConfig(base, override *genai.GenerateContentConfig) *genai.GenerateContentConfig {
+	merged := clone(base)
+	if override == nil {
+		return merged
+	}
+	override = clone(override)
+	if merged == nil {
+		return override
+	}
+
+	dst, src := reflect.ValueOf(merged).Elem(), reflect.ValueOf(override).Elem()
+	for i := range src.NumField() {
+		name, field := src.Type().Field(i).Name, src.Field(i)
+		if field.IsZero() {
+			continue
+		}
+		switch name {
+		case "Tools":
+			merged.Tools = append(merged.Tools, override.Tools...)
+		case "SystemInstruction":
+			if merged.SystemInstruction == nil {
+				merged.SystemInstruction = override.SystemInstruction
+			} else {
+				merged.SystemInstruction.Parts = append(merged.SystemInstruction.Parts, override.SystemInstruction.Parts...)
+			}
+		case "Labels":
+			if merged.Labels == nil {
+				merged.Labels = make(map[string]string, len(override.Labels))
+			}
+			maps.Copy(merged.Labels, override.Labels)
+		default:
+			dst.Field(i).Set(field)
+		}
+	}
+	return merged
+}
+
 // clone returns a deep copy of the src.
 // NOTE: this does not work for types with unexported fields.
 func clone[M any](src M) M {
@@ -0,0 +1,107 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package llminternal
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/genai"
+)
+
+func TestMergeGenerateContentConfig(t *testing.T) {
+	search := &genai.Tool{GoogleSearch: &genai.GoogleSearch{}}
+	codeExecution := &genai.Tool{CodeExecution: &genai.ToolCodeExecution{}}
+	newBase := func() *genai.GenerateContentConfig {
+		return &genai.GenerateContentConfig{
+			SystemInstruction: genai.NewContentFromText("Be brief.", genai.RoleUser),
+			Temperature:       genai.Ptr[float32](0.2),
+			TopP:              genai.Ptr[float32](0.9),
+			MaxOutputTokens:   1024,
+			StopSequences:     []string{"END"},
+			Tools:             []*genai.Tool{search},
+			Labels:            map[string]string{"team": "support", "env": "prod"},
+			ThinkingConfig:    &genai.ThinkingConfig{IncludeThoughts: true, ThinkingBudget: genai.Ptr[int32](512)},
+		}
+	}
+
+	tests := []struct {
+		name           string
+		base, override *genai.GenerateContentConfig
+		want           *genai.GenerateContentConfig
+	}{
+		{
+			name: "no configs",
+		},
+		{
+			name: "no override",
+			base: newBase(),
+			want: newBase(),
+		},
+		{
+			name:     "no base",
+			override: &genai.GenerateContentConfig{Temperature: genai.Ptr[float32](1)},
+			want:     &genai.GenerateContentConfig{Temperature: genai.Ptr[float32](1)},
+		},
+		{
+			name: "override",
+			base: newBase(),
+			override: &genai.GenerateContentConfig{
+				SystemInstruction: genai.NewContentFromText("Answer in French.", genai.RoleUser),
+				Temperature:       genai.Ptr[float32](0),
+				StopSequences:     []string{"STOP", "DONE"},
+				Tools:             []*genai.Tool{codeExecution},
+				Labels:            map[string]string{"env": "test", "run": "42"},
+				ThinkingConfig:    &genai.ThinkingConfig{ThinkingBudget: genai.Ptr[int32](0)},
+			},
+			want: &genai.GenerateContentConfig{
+				SystemInstruction: &genai.Content{Role: genai.RoleUser, Parts: []*genai.Part{
+					genai.NewPartFromText("Be brief."),
+					genai.NewPartFromText("Answer in French."),
+				}},
+				// A pointer to zero is set.
+				Temperature:     genai.Ptr[float32](0),
+				TopP:            genai.Ptr[float32](0.9),
+				MaxOutputTokens: 1024,
+				StopSequences:   []string{"STOP", "DONE"},
+				Tools:           []*genai.Tool{search, codeExecution},
+				Labels:          map[string]string{"team": "support", "env": "test", "run": "42"},
+				ThinkingConfig:  &genai.ThinkingConfig{ThinkingBudget: genai.Ptr[int32](0)},
+			},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got := mergeGenerateContentConfig(tc.base, tc.override)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("mergeGenerateContentConfig() mismatch (-want +got):\n%s", diff)
+			}
+			if tc.base != nil && got == tc.base {
+				t.Error("mergeGenerateContentConfig() returned the base config, want a copy")
+			}
+		})
+	}
+
+	// The configs are left unchanged.
+	base := newBase()
+	mergeGenerateContentConfig(base, &genai.GenerateContentConfig{
+		SystemInstruction: genai.NewContentFromText("More.", genai.RoleUser),
+		Tools:             []*genai.Tool{codeExecution},
+		Labels:            map[string]string{"env": "test"},
+	})
+	if diff := cmp.Diff(newBase(), base); diff != "" {
+		t.Errorf("mergeGenerateContentConfig() modified the base config (-want +got):\n%s", diff)
+	}
+}
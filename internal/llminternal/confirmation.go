@@ -0,0 +1,139 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package llminternal
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/agent"
+	"google.golang.org/adk/internal/typeutil"
+	"google.golang.org/adk/internal/utils"
+	"google.golang.org/adk/model"
+	"google.golang.org/adk/session"
+	"google.golang.org/adk/tool/toolconfirmation"
+)
+
+// generateConfirmationEvent returns the event asking the client for the
+// confirmations requested by the tools in the function response event, or
+// nil if none was requested. The calls are the function calls of the
+// invocation, keyed by ID, among which the calls to confirm. Its function
+// calls are long-running: the client answers them with the confirmations.
+//
+// See adk-python src/google/adk/flows/llm_flows/functions.py
+// generate_request_confirmation_event.
+func generateConfirmationEvent(ctx agent.InvocationContext, calls map[string]*genai.FunctionCall, fnResponseEvent *session.Event) (*session.Event, error) {
+	confirmations := fnResponseEvent.Actions.RequestedToolConfirmations
+	if len(confirmations) == 0 {
+		return nil, nil
+	}
+	var parts []*genai.Part
+	for _, callID := range slices.Sorted(maps.Keys(confirmations)) {
+		fc, ok := calls[callID]
+		if !ok {
+			return nil, fmt.Errorf("no function call %q for the requested confirmation", callID)
+		}
+		args, err := typeutil.ConvertToWithJSONSchema[toolconfirmation.Request, map[string]any](toolconfirmation.Request{
+			OriginalFunctionCall: fc,
+			ToolConfirmation:     confirmations[callID],
+		}, nil)
+		if err != nil {
+			return nil, err
+		}
+		parts = append(parts, genai.NewPartFromFunctionCall(toolconfirmation.FunctionCallName, args))
+	}
+	content := genai.NewContentFromParts(parts, genai.RoleModel)
+	utils.PopulateClientFunctionCallID(content)
+
+	ev := session.NewEvent(ctx.InvocationID())
+	ev.Author = ctx.Agent().Name()
+	ev.Branch = ctx.Branch()
+	ev.LLMResponse = model.LLMResponse{Content: content}
+	for _, fc := range utils.FunctionCalls(content) {
+		ev.LongRunningToolIDs = append(ev.LongRunningToolIDs, fc.ID)
+	}
+	return ev, nil
+}
+
+// resumeConfirmedCalls handles the confirmations sent by the client in the
+// last event of the session, the answers to the function calls of a
+// confirmation event. The confirmed or declined function calls are run
+// again, with their confirmation. It returns their function response event,
+// or nil if the last event has no confirmations.
+//
+// See adk-python src/google/adk/flows/llm_flows/request_confirmation.py.
+func (f *Flow) resumeConfirmedCalls(ctx agent.InvocationContext, emitter *partialEmitter) (*session.Event, error) {
+	events := ctx.Session().Events()
+	if events.Len() == 0 {
+		return nil, nil
+	}
+	var responses []*genai.FunctionResponse
+	for _, resp := range utils.FunctionResponses(events.At(events.Len() - 1).Content) {
+		if resp.Name == toolconfirmation.FunctionCallName {
+			responses = append(responses, resp)
+		}
+	}
+	if len(responses) == 0 {
+		return nil, nil
+	}
+
+	calls := sessionFunctionCalls(ctx)
+	confirmations := make(map[string]*toolconfirmation.ToolConfirmation)
+	var resumed []*genai.Part
+	for _, resp := range responses {
+		requestCall, ok := calls[resp.ID]
+		if !ok {
+			return nil, fmt.Errorf("no confirmation request for the function response %q", resp.ID)
+		}
+		request, err := typeutil.ConvertToWithJSONSchema[map[string]any, toolconfirmation.Request](requestCall.Args, nil)
+		if err != nil || request.OriginalFunctionCall == nil {
+			return nil, fmt.Errorf("invalid confirmation request %q: %v", resp.ID, err)
+		}
+		confirmation, err := typeutil.ConvertToWithJSONSchema[map[string]any, *toolconfirmation.ToolConfirmation](resp.Response, nil)
+		if err != nil {
+			return nil, fmt.Errorf("invalid confirmation response %q: %w", resp.ID, err)
+		}
+		fc := request.OriginalFunctionCall
+		if original, ok := calls[fc.ID]; ok {
+			fc = original
+		}
+		if _, ok := confirmations[fc.ID]; ok {
+			continue
+		}
+		confirmations[fc.ID] = confirmation
+		resumed = append(resumed, &genai.Part{FunctionCall: fc})
+	}
+
+	tools, err := f.tools(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return f.handleFunctionCalls(ctx, tools, &model.LLMResponse{Content: genai.NewContentFromParts(resumed, genai.RoleModel)}, emitter, confirmations)
+}
+
+// sessionFunctionCalls returns the function calls of the events of the
+// session, keyed by ID.
+func sessionFunctionCalls(ctx agent.InvocationContext) map[string]*genai.FunctionCall {
+	calls := make(map[string]*genai.FunctionCall)
+	for ev := range ctx.Session().Events().All() {
+		for _, fc := range utils.FunctionCalls(ev.Content) {
+			calls[fc.ID] = fc
+		}
+	}
+	return calls
+}
@@ -25,9 +25,13 @@ import (
 	"google.golang.org/genai"
 
 	"google.golang.org/adk/agent"
+	"google.golang.org/adk/agent/compaction"
+	"google.golang.org/adk/artifact"
+	"google.golang.org/adk/auth"
 	"google.golang.org/adk/internal/utils"
 	"google.golang.org/adk/model"
 	"google.golang.org/adk/session"
+	"google.golang.org/adk/tool/toolconfirmation"
 )
 
 // ContentRequestProcessor populates the LLMRequest's Contents based on
@@ -50,14 +54,46 @@ func ContentsRequestProcessor(ctx agent.InvocationContext, req *model.LLMRequest
 			events = append(events, e)
 		}
 	}
+	if cc := llmAgent.internal().ContextCompaction; cc != nil {
+		events = cc.History(events)
+	} else {
+		events = slices.DeleteFunc(events, compaction.IsSummary)
+	}
 	contents, err := fn(ctx.Agent().Name(), ctx.Branch(), events)
 	if err != nil {
 		return err
 	}
+	resolveArtifactReferences(ctx, contents)
 	req.Contents = append(req.Contents, contents...)
 	return nil
 }
 
+// resolveArtifactReferences replaces the artifact references created for
+// offloaded parts (see agent.RunConfig.MaxInlinePartSize) with the artifact
+// content, so that the model receives data it supports. A reference that
+// cannot be resolved is replaced with a text note. The contents must not be
+// shared with the session events.
+func resolveArtifactReferences(ctx agent.InvocationContext, contents []*genai.Content) {
+	artifacts := ctx.Artifacts()
+	if artifacts == nil {
+		return
+	}
+	for _, content := range contents {
+		for i, part := range content.Parts {
+			fileName, version, ok := artifact.ParseReference(part)
+			if !ok {
+				continue
+			}
+			resp, err := artifacts.LoadVersion(ctx, fileName, int(version))
+			if err != nil || resp.Part == nil {
+				content.Parts[i] = genai.NewPartFromText(fmt.Sprintf("[artifact %q is not available]", fileName))
+				continue
+			}
+			content.Parts[i] = resp.Part
+		}
+	}
+}
+
 // buildContentsDefault returns the contents for the LLM request by applying
 // filtering, rearrangement, and content processing to the given events.
 func buildContentsDefault(agentName, invocationBranch string, events []*session.Event) ([]*genai.Content, error) {
@@ -79,7 +115,7 @@ func buildContentsDefault(agentName, invocationBranch string, events []*session.
 		if !eventBelongsToBranch(invocationBranch, ev) {
 			continue
 		}
-		if isAuthEvent(ev) {
+		if isAuthEvent(ev) || isConfirmationEvent(ev) {
 			continue
 		}
 		if isOtherAgentReply(agentName, ev) {
@@ -502,20 +538,26 @@ func stringify(v any) string {
 	return string(s)
 }
 
-// requestEUCFunctionCallName is a special function to handle credential
-// request.
-const requestEUCFunctionCallName = "adk_request_credential"
-
 func isAuthEvent(ev *session.Event) bool {
+	return hasFunctionPart(ev, auth.RequestCredentialFunctionName)
+}
+
+func isConfirmationEvent(ev *session.Event) bool {
+	return hasFunctionPart(ev, toolconfirmation.FunctionCallName)
+}
+
+// hasFunctionPart reports whether the event has a function call or response
+// with the given name.
+func hasFunctionPart(ev *session.Event, name string) bool {
 	c := utils.Content(ev)
 	if c == nil {
 		return false
 	}
 	for _, p := range c.Parts {
-		if p.FunctionCall != nil && p.FunctionCall.Name == requestEUCFunctionCallName {
+		if p.FunctionCall != nil && p.FunctionCall.Name == name {
 			return true
 		}
-		if p.FunctionResponse != nil && p.FunctionResponse.Name == requestEUCFunctionCallName {
+		if p.FunctionResponse != nil && p.FunctionResponse.Name == name {
 			return true
 		}
 	}
@@ -16,14 +16,18 @@ package llminternal
 
 import (
 	"fmt"
+	"mime"
 	"regexp"
 	"slices"
 	"strings"
 	"unicode"
 
+	"google.golang.org/genai"
+
 	"google.golang.org/adk/agent"
 	"google.golang.org/adk/internal/agent/parentmap"
 	icontext "google.golang.org/adk/internal/context"
+	"google.golang.org/adk/internal/localcontext"
 	"google.golang.org/adk/internal/utils"
 	"google.golang.org/adk/model"
 )
@@ -62,6 +66,15 @@ func instructionsRequestProcessor(ctx agent.InvocationContext, req *model.LLMReq
 	return nil
 }
 
+// localContextRequestProcessor appends the local context of the user, set by
+// the runner, to the instructions.
+func localContextRequestProcessor(ctx agent.InvocationContext, req *model.LLMRequest) error {
+	if info := localcontext.FromContext(ctx); info != nil {
+		utils.AppendInstructions(req, info.Instruction())
+	}
+	return nil
+}
+
 // The regex to find placeholders like {variable} or {artifact.file_name}.
 var placeholderRegex = regexp.MustCompile(`{+[^{}]*}+`)
 
@@ -69,7 +82,7 @@ func appendInstructions(ctx agent.InvocationContext, req *model.LLMRequest, agen
 	if agentState.InstructionProvider != nil {
 		instruction, err := agentState.InstructionProvider(icontext.NewReadonlyContext(ctx))
 		if err != nil {
-			return fmt.Errorf("failed to evaluate global instruction provider: %w", err)
+			return fmt.Errorf("failed to evaluate instruction provider: %w", err)
 		}
 
 		utils.AppendInstructions(req, instruction)
@@ -136,7 +149,7 @@ func replaceMatch(ctx agent.InvocationContext, match string) (string, error) {
 			}
 			return "", fmt.Errorf("failed to load artifact %s: %w", fileName, err)
 		}
-		return resp.Part.Text, nil
+		return artifactText(fileName, resp.Part)
 	}
 
 	if !isValidStateName(varName) {
@@ -159,6 +172,39 @@ func replaceMatch(ctx agent.InvocationContext, match string) (string, error) {
 	return fmt.Sprintf("%v", value), nil
 }
 
+// artifactText returns the text content of the artifact: its text, or its data
+// if it has a textual MIME type, e.g. an artifact saved from the bytes of a
+// text/markdown or application/json file.
+func artifactText(fileName string, part *genai.Part) (string, error) {
+	switch {
+	case part == nil:
+		return "", nil
+	case part.Text != "":
+		return part.Text, nil
+	case part.InlineData != nil:
+		if !isTextMIMEType(part.InlineData.MIMEType) {
+			return "", fmt.Errorf("artifact %s is not text: %s", fileName, part.InlineData.MIMEType)
+		}
+		return string(part.InlineData.Data), nil
+	}
+	return "", nil
+}
+
+func isTextMIMEType(mimeType string) bool {
+	mediaType, _, err := mime.ParseMediaType(mimeType)
+	if err != nil {
+		return false
+	}
+	if strings.HasPrefix(mediaType, "text/") {
+		return true
+	}
+	switch mediaType {
+	case "application/json", "application/xml", "application/yaml", "application/x-yaml":
+		return true
+	}
+	return strings.HasSuffix(mediaType, "+json") || strings.HasSuffix(mediaType, "+xml")
+}
+
 // isIdentifier checks if a string is a valid Go identifier.
 // This is the equivalent of Python's `str.isidentifier()`.
 func isIdentifier(s string) bool {
@@ -57,6 +57,24 @@ func TestInjectSessionState(t *testing.T) {
 			},
 			want: "The artifact content is: This is my artifact content.",
 		},
+		{
+			name:     "text data artifact injection",
+			template: "Notes: {artifact.notes.md} Data: {artifact.data.json}",
+			artifacts: map[string]*genai.Part{
+				"notes.md":  genai.NewPartFromBytes([]byte("# Notes"), "text/markdown; charset=utf-8"),
+				"data.json": genai.NewPartFromBytes([]byte(`{"a": 1}`), "application/json"),
+			},
+			want: `Notes: # Notes Data: {"a": 1}`,
+		},
+		{
+			name:     "binary artifact",
+			template: "The image is: {artifact.image.png}",
+			artifacts: map[string]*genai.Part{
+				"image.png": genai.NewPartFromBytes([]byte("png"), "image/png"),
+			},
+			wantErr:    true,
+			wantErrMsg: "artifact image.png is not text: image/png",
+		},
 		// Corresponds to: test_inject_session_state_with_optional_state
 		// and test_inject_session_state_with_optional_missing_state_returns_empty
 		{
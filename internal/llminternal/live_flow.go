@@ -0,0 +1,207 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package llminternal
+
+import (
+	"fmt"
+	"iter"
+	"sync"
+
+	"google.golang.org/adk/agent"
+	"google.golang.org/adk/internal/agent/runconfig"
+	"google.golang.org/adk/model"
+	"google.golang.org/adk/session"
+	"google.golang.org/adk/tool"
+)
+
+// runLive runs the agent over a live connection to its model, as
+// adk-python src/google/adk/flows/llm_flows/base_llm_flow.py
+// BaseLlmFlow.run_live: the requests of the live request queue are sent to
+// the model, and the responses of the model are yielded as events, the
+// function calls being run and their responses sent back, until the queue
+// is closed.
+func (f *Flow) runLive(ctx agent.InvocationContext, queue *agent.LiveRequestQueue) iter.Seq2[*session.Event, error] {
+	return func(yield func(*session.Event, error) bool) {
+		if f.Model == nil {
+			yield(nil, fmt.Errorf("agent %q: %w", ctx.Agent().Name(), ErrModelNotConfigured))
+			return
+		}
+		liveModel, ok := f.Model.(model.LiveLLM)
+		if !ok {
+			yield(nil, fmt.Errorf("agent %q: model %q does not support live connections", ctx.Agent().Name(), f.Model.Name()))
+			return
+		}
+
+		req := &model.LLMRequest{Model: f.Model.Name()}
+		if err := f.preprocess(ctx, req); err != nil {
+			yield(nil, err)
+			return
+		}
+		if ctx.Ended() {
+			return
+		}
+		tools := make(map[string]tool.Tool)
+		for name, v := range req.Tools {
+			t, ok := v.(tool.Tool)
+			if !ok {
+				yield(nil, fmt.Errorf("unexpected tool type %T for tool %v", v, name))
+				return
+			}
+			tools[name] = t
+		}
+
+		if !runconfig.FromContext(ctx).CountLLMCall() {
+			yield(nil, &agent.LimitExceededError{Limit: agent.LimitLLMCalls, Max: runconfig.FromContext(ctx).MaxLLMCalls})
+			return
+		}
+		conn, err := liveModel.ConnectLive(ctx, req)
+		if err != nil {
+			yield(nil, fmt.Errorf("failed to connect to model %q: %w", f.Model.Name(), err))
+			return
+		}
+		defer conn.Close()
+
+		sender := startLiveSender(queue, conn)
+		defer sender.stop()
+
+		for resp, err := range conn.Receive() {
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+			if err := f.postprocess(ctx, req, resp); err != nil {
+				yield(nil, err)
+				return
+			}
+			if resp.Content == nil && resp.InputTranscription == nil && resp.OutputTranscription == nil &&
+				!resp.TurnComplete && !resp.Interrupted && resp.ErrorCode == "" {
+				continue
+			}
+
+			ev := f.finalizeModelResponseEvent(ctx, resp, tools, make(map[string]any))
+			if resp.InputTranscription != nil {
+				ev.Author = "user"
+			}
+			if !yield(ev, nil) {
+				return
+			}
+			if err := countTokens(ctx, resp); err != nil {
+				yield(nil, err)
+				return
+			}
+
+			emitter := &partialEmitter{yield: yield}
+			fnResponseEvent, err := f.handleFunctionCalls(ctx, tools, resp, emitter, nil)
+			if emitter.stopped {
+				return
+			}
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+			if fnResponseEvent == nil {
+				continue
+			}
+			if !yield(fnResponseEvent, nil) {
+				return
+			}
+			if err := conn.Send(&model.LiveRequest{Content: fnResponseEvent.Content}); err != nil {
+				yield(nil, fmt.Errorf("failed to send the function responses to the model: %w", err))
+				return
+			}
+
+			if fnResponseEvent.Actions.TransferToAgent == "" {
+				continue
+			}
+			nextAgent := f.agentToRun(ctx, fnResponseEvent.Actions.TransferToAgent)
+			if nextAgent == nil {
+				yield(nil, fmt.Errorf("failed to find agent: %s", fnResponseEvent.Actions.TransferToAgent))
+				return
+			}
+			// The next agent reads the queue over its own connection.
+			sender.stop()
+			conn.Close()
+			for ev, err := range nextAgent.Run(ctx) {
+				if !yield(ev, err) || err != nil {
+					return
+				}
+			}
+			return
+		}
+		if err := sender.err(); err != nil {
+			yield(nil, err)
+		}
+	}
+}
+
+// liveSender sends the requests of a live request queue to a connection,
+// closing it once the queue is closed.
+type liveSender struct {
+	stopCh   chan struct{}
+	done     chan struct{}
+	stopOnce sync.Once
+	sendErr  error
+}
+
+func startLiveSender(queue *agent.LiveRequestQueue, conn model.LiveConnection) *liveSender {
+	s := &liveSender{stopCh: make(chan struct{}), done: make(chan struct{})}
+	go func() {
+		defer close(s.done)
+		send := func(req *model.LiveRequest) bool {
+			if err := conn.Send(req); err != nil {
+				s.sendErr = fmt.Errorf("failed to send a live request to the model: %w", err)
+				conn.Close()
+				return false
+			}
+			return true
+		}
+		for {
+			select {
+			case <-s.stopCh:
+				return
+			case req := <-queue.Requests():
+				if !send(req) {
+					return
+				}
+			case <-queue.Done():
+				// Send the requests buffered before the queue was closed.
+				for {
+					select {
+					case req := <-queue.Requests():
+						if !send(req) {
+							return
+						}
+					default:
+						conn.Close()
+						return
+					}
+				}
+			}
+		}
+	}()
+	return s
+}
+
+// stop stops sending, and waits for the request being sent.
+func (s *liveSender) stop() {
+	s.stopOnce.Do(func() { close(s.stopCh) })
+	<-s.done
+}
+
+// err returns the error of a failed send, once stopped.
+func (s *liveSender) err() error {
+	s.stop()
+	return s.sendErr
+}
@@ -15,8 +15,16 @@
 package llminternal
 
 import (
+	"fmt"
+	"slices"
+
+	"google.golang.org/genai"
+
 	"google.golang.org/adk/agent"
+	icontext "google.golang.org/adk/internal/context"
+	"google.golang.org/adk/internal/utils"
 	"google.golang.org/adk/model"
+	"google.golang.org/adk/session"
 )
 
 func identityRequestProcessor(ctx agent.InvocationContext, req *model.LLMRequest) error {
@@ -24,27 +32,151 @@ func identityRequestProcessor(ctx agent.InvocationContext, req *model.LLMRequest
 	return nil
 }
 
+// nlPlanningRequestProcessor appends the instruction of the planner of the
+// agent, and unmarks the thoughts of the contents, which the planner may have
+// marked in the previous responses.
 func nlPlanningRequestProcessor(ctx agent.InvocationContext, req *model.LLMRequest) error {
-	// TODO: implement (adk-python src/google/adk/flows/llm_flows/_nl_plnning.py)
+	// reference: adk-python src/google/adk/flows/llm_flows/_nl_planning.py
+	llmAgent := asLLMAgent(ctx.Agent())
+	if llmAgent == nil || llmAgent.internal().Planner == nil {
+		return nil
+	}
+	instruction, err := llmAgent.internal().Planner.BuildPlanningInstruction(icontext.NewReadonlyContext(ctx), req)
+	if err != nil {
+		return fmt.Errorf("failed to build the planning instruction: %w", err)
+	}
+	if instruction != "" {
+		utils.AppendInstructions(req, instruction)
+	}
+	for _, content := range req.Contents {
+		if content == nil {
+			continue
+		}
+		for i, part := range content.Parts {
+			if part != nil && part.Thought {
+				// The parts may be shared with the session events.
+				unmarked := *part
+				unmarked.Thought = false
+				content.Parts[i] = &unmarked
+			}
+		}
+	}
 	return nil
 }
 
+// codeExecutionRequestProcessor sends the code run by the code executor of
+// the agent, and its results, back to the model as code blocks.
 func codeExecutionRequestProcessor(ctx agent.InvocationContext, req *model.LLMRequest) error {
-	// TODO: implement (adk-python src/google/adk/flows/llm_flows/_code_execution.py)
-	return nil
-}
-
-func authPreprocessor(ctx agent.InvocationContext, req *model.LLMRequest) error {
-	// TODO: implement (adk-python src/google/adk/auth/auth_preprocessor.py)
+	// reference: adk-python src/google/adk/flows/llm_flows/_code_execution.py
+	llmAgent := asLLMAgent(ctx.Agent())
+	if llmAgent == nil || llmAgent.internal().CodeExecutor == nil {
+		return nil
+	}
+	cfg := llmAgent.internal().CodeExecutor
+	for i, content := range req.Contents {
+		if content == nil {
+			continue
+		}
+		var parts []*genai.Part
+		for j, part := range content.Parts {
+			text := cfg.ToText(part)
+			if text == part {
+				continue
+			}
+			if parts == nil {
+				// The contents may be shared with the session events.
+				parts = slices.Clone(content.Parts)
+			}
+			parts[j] = text
+		}
+		if parts != nil {
+			req.Contents[i] = &genai.Content{Role: content.Role, Parts: parts}
+		}
+	}
 	return nil
 }
 
+// nlPlanningResponseProcessor lets the planner of the agent process the parts
+// of the response, e.g. to mark the planning as thoughts.
 func nlPlanningResponseProcessor(ctx agent.InvocationContext, req *model.LLMRequest, resp *model.LLMResponse) error {
-	// TODO: implement (adk-python src/google/adk/_nl_planning.py)
+	// reference: adk-python src/google/adk/flows/llm_flows/_nl_planning.py
+	llmAgent := asLLMAgent(ctx.Agent())
+	if llmAgent == nil || llmAgent.internal().Planner == nil || resp.Content == nil || len(resp.Content.Parts) == 0 {
+		return nil
+	}
+	parts, err := llmAgent.internal().Planner.ProcessPlanningResponse(icontext.NewReadonlyContext(ctx), resp.Content.Parts)
+	if err != nil {
+		return fmt.Errorf("failed to process the planning response: %w", err)
+	}
+	if parts != nil {
+		resp.Content = &genai.Content{Role: resp.Content.Role, Parts: parts}
+	}
 	return nil
 }
 
+// codeExecutionResponseProcessor cuts the first code block of the response
+// into a part with executable code, which runCode then runs, unless the
+// code of the invocation failed more times in a row than the retries.
 func codeExecutionResponseProcessor(ctx agent.InvocationContext, req *model.LLMRequest, resp *model.LLMResponse) error {
-	// TODO: implement (adk-python src/google/adk_code_execution.py)
+	// reference: adk-python src/google/adk/flows/llm_flows/_code_execution.py
+	llmAgent := asLLMAgent(ctx.Agent())
+	if llmAgent == nil || llmAgent.internal().CodeExecutor == nil || resp.Partial || resp.Content == nil {
+		return nil
+	}
+	cfg := llmAgent.internal().CodeExecutor
+	if consecutiveCodeErrors(ctx) > cfg.RetryAttempts() {
+		return nil
+	}
+	if parts := cfg.ExtractCode(resp.Content.Parts); parts != nil {
+		resp.Content = &genai.Content{Role: resp.Content.Role, Parts: parts}
+	}
 	return nil
 }
+
+// runCode runs the code of the response with the code executor of the agent,
+// and returns the event with its result, or nil if the response has no code.
+func runCode(ctx agent.InvocationContext, resp *model.LLMResponse) (*session.Event, error) {
+	llmAgent := asLLMAgent(ctx.Agent())
+	if llmAgent == nil || llmAgent.internal().CodeExecutor == nil || resp.Partial || resp.Content == nil || len(resp.Content.Parts) == 0 {
+		return nil, nil
+	}
+	code := resp.Content.Parts[len(resp.Content.Parts)-1].ExecutableCode
+	if code == nil {
+		return nil, nil
+	}
+	result, err := llmAgent.internal().CodeExecutor.Run(ctx, code.Code, ctx.Session().ID())
+	if err != nil {
+		return nil, fmt.Errorf("failed to run code: %w", err)
+	}
+	ev := session.NewEvent(ctx.InvocationID())
+	ev.Author = ctx.Agent().Name()
+	ev.Branch = ctx.Branch()
+	// The result is the input of the next turn of the model.
+	ev.Content = genai.NewContentFromParts([]*genai.Part{result}, genai.RoleUser)
+	return ev, nil
+}
+
+// consecutiveCodeErrors returns the number of the latest code runs of the
+// invocation which failed in a row.
+func consecutiveCodeErrors(ctx agent.InvocationContext) int {
+	failed := 0
+	events := ctx.Session().Events()
+	for i := events.Len() - 1; i >= 0; i-- {
+		ev := events.At(i)
+		if ev.InvocationID != ctx.InvocationID() {
+			break
+		}
+		if ev.Content == nil || len(ev.Content.Parts) == 0 {
+			continue
+		}
+		result := ev.Content.Parts[len(ev.Content.Parts)-1].CodeExecutionResult
+		if result == nil {
+			continue
+		}
+		if result.Outcome == genai.OutcomeOK {
+			break
+		}
+		failed++
+	}
+	return failed
+}
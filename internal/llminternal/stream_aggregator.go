@@ -143,3 +143,41 @@ func (s *streamingResponseAggregator) clear() {
 	s.thoughtText = ""
 	s.role = ""
 }
+
+// aggregatePartialResponses returns the complete response of the partial
+// responses ending a stream, or nil if there are none: their texts and
+// thoughts are concatenated, and the metadata is the one of the last
+// response. It is used for the models whose streams do not end with an
+// aggregated response.
+func aggregatePartialResponses(partials []*model.LLMResponse) *model.LLMResponse {
+	if len(partials) == 0 {
+		return nil
+	}
+	var parts []*genai.Part
+	role := genai.RoleModel
+	for _, resp := range partials {
+		if resp.Content == nil {
+			continue
+		}
+		if resp.Content.Role != "" {
+			role = resp.Content.Role
+		}
+		for _, part := range resp.Content.Parts {
+			// Merge the consecutive texts of the same kind.
+			if n := len(parts); n > 0 && part.Text != "" && parts[n-1].Text != "" && parts[n-1].Thought == part.Thought {
+				parts[n-1] = &genai.Part{Text: parts[n-1].Text + part.Text, Thought: part.Thought}
+				continue
+			}
+			parts = append(parts, part)
+		}
+	}
+	last := partials[len(partials)-1]
+	aggregated := *last
+	aggregated.Partial = false
+	aggregated.TurnComplete = true
+	aggregated.Content = nil
+	if len(parts) > 0 {
+		aggregated.Content = &genai.Content{Role: role, Parts: parts}
+	}
+	return &aggregated
+}
@@ -0,0 +1,57 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package localcontext carries the local context of the user, the current
+// time in their time zone and their locale, from the runner to the model
+// requests of an invocation.
+package localcontext
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Info is the local context of the user.
+type Info struct {
+	// Time is the start time of the invocation, in the time zone of the user.
+	Time   time.Time
+	Locale string
+}
+
+// Instruction returns the system instruction describing the local context,
+// e.g.:
+//
+//	Current date and time: Wednesday, 2026-10-14 20:31 (Europe/Paris, UTC+02:00).
+//	User locale: fr-FR.
+func (i *Info) Instruction() string {
+	return fmt.Sprintf("Current date and time: %s (%s, UTC%s).\nUser locale: %s.",
+		i.Time.Format("Monday, 2006-01-02 15:04"), i.Time.Location(), i.Time.Format("-07:00"), i.Locale)
+}
+
+func ToContext(ctx context.Context, info *Info) context.Context {
+	return context.WithValue(ctx, infoCtxKey, info)
+}
+
+func FromContext(ctx context.Context) *Info {
+	info, ok := ctx.Value(infoCtxKey).(*Info)
+	if !ok {
+		return nil
+	}
+	return info
+}
+
+type ctxKey int
+
+const infoCtxKey ctxKey = 0
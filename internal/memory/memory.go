@@ -16,6 +16,9 @@ package memory
 
 import (
 	"context"
+	"strings"
+
+	"google.golang.org/genai"
 
 	"google.golang.org/adk/memory"
 	"google.golang.org/adk/session"
@@ -39,3 +42,18 @@ func (a *Memory) Search(ctx context.Context, query string) (*memory.SearchRespon
 		Query:   query,
 	})
 }
+
+// Text returns the text of the parts of the content of a memory entry,
+// without the thoughts, joined by spaces.
+func Text(content *genai.Content) string {
+	if content == nil {
+		return ""
+	}
+	var texts []string
+	for _, part := range content.Parts {
+		if part.Text != "" && !part.Thought {
+			texts = append(texts, part.Text)
+		}
+	}
+	return strings.Join(texts, " ")
+}
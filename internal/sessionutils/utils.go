@@ -72,3 +72,18 @@ func MergeStates(appState, userState, sessionState map[string]any) map[string]an
 
 	return mergedState
 }
+
+// TempState returns the temporary keys of a state delta, which are visible
+// in the session for the rest of the invocation but are never stored.
+func TempState(delta map[string]any) map[string]any {
+	var temp map[string]any
+	for key, value := range delta {
+		if strings.HasPrefix(key, tempPrefix) {
+			if temp == nil {
+				temp = make(map[string]any)
+			}
+			temp[key] = value
+		}
+	}
+	return temp
+}
@@ -0,0 +1,45 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package telemetry
+
+import (
+	"context"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	modelRetryEventName = "adk.model.retry"
+	modelRetryAttempt   = "adk.model.retry.attempt"
+	modelRetryDelay     = "adk.model.retry.delay"
+	modelRetryError     = "adk.model.retry.error"
+)
+
+// RecordModelRetry records the retry of a failed call of the model, after the
+// given attempt and delay: an event on the span of ctx, if any, and the
+// counter adk.model.retries.
+func RecordModelRetry(ctx context.Context, modelName string, attempt int, delay time.Duration, err error) {
+	trace.SpanFromContext(ctx).AddEvent(modelRetryEventName, trace.WithAttributes(
+		attribute.String(genAiRequestModelName, modelName),
+		attribute.Int(modelRetryAttempt, attempt),
+		attribute.Float64(modelRetryDelay, delay.Seconds()),
+		attribute.String(modelRetryError, err.Error()),
+	))
+	currentToolInstruments().modelRetries.Add(context.WithoutCancel(ctx), 1,
+		metric.WithAttributes(attribute.String(genAiRequestModelName, modelName)))
+}
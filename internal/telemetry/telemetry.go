@@ -21,45 +21,28 @@ package telemetry
 import (
 	"context"
 	"encoding/json"
-	"sync"
 
-	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
 	sdktrace "go.opentelemetry.io/otel/sdk/trace"
 	"go.opentelemetry.io/otel/trace"
 	"google.golang.org/genai"
 
 	"google.golang.org/adk/agent"
+	"google.golang.org/adk/internal/telemetry/tracing"
 	"google.golang.org/adk/model"
 	"google.golang.org/adk/session"
 	"google.golang.org/adk/tool"
 )
 
-type tracerProviderHolder struct {
-	tp trace.TracerProvider
-}
-
-type tracerProviderConfig struct {
-	spanProcessors []sdktrace.SpanProcessor
-	mu             *sync.RWMutex
-}
-
-var (
-	once              sync.Once
-	localTracer       tracerProviderHolder
-	localTracerConfig = tracerProviderConfig{
-		spanProcessors: []sdktrace.SpanProcessor{},
-		mu:             &sync.RWMutex{},
-	}
-)
-
 const (
-	systemName           = "gcp.vertex.agent"
+	systemName           = tracing.SystemName
 	genAiOperationName   = "gen_ai.operation.name"
 	genAiToolDescription = "gen_ai.tool.description"
 	genAiToolName        = "gen_ai.tool.name"
 	genAiToolCallID      = "gen_ai.tool.call.id"
 	genAiSystemName      = "gen_ai.system"
+	genAiAgentName       = "gen_ai.agent.name"
+	genAiConversationID  = "gen_ai.conversation.id"
 
 	genAiRequestModelName = "gen_ai.request.model"
 	genAiRequestTopP      = "gen_ai.request.top_p"
@@ -70,6 +53,9 @@ const (
 	genAiResponseCandidatesTokenCount    = "gen_ai.response.candidates_token_count"
 	genAiResponseCachedContentTokenCount = "gen_ai.response.cached_content_token_count"
 	genAiResponseTotalTokenCount         = "gen_ai.response.total_token_count"
+	genAiResponseFinishReasons           = "gen_ai.response.finish_reasons"
+	genAiUsageInputTokens                = "gen_ai.usage.input_tokens"
+	genAiUsageOutputTokens               = "gen_ai.usage.output_tokens"
 
 	gcpVertexAgentLLMRequestName   = "gcp.vertex.agent.llm_request"
 	gcpVertexAgentToolCallArgsName = "gcp.vertex.agent.tool_call_args"
@@ -78,55 +64,57 @@ const (
 	gcpVertexAgentLLMResponseName  = "gcp.vertex.agent.llm_response"
 	gcpVertexAgentInvocationID     = "gcp.vertex.agent.invocation_id"
 	gcpVertexAgentSessionID        = "gcp.vertex.agent.session_id"
+	gcpVertexAgentCorrelationID    = "gcp.vertex.agent.correlation_id"
+	gcpVertexAgentAppName          = "gcp.vertex.agent.app_name"
+	gcpVertexAgentUserID           = "gcp.vertex.agent.user_id"
 
 	executeToolName = "execute_tool"
+	chatName        = "chat"
 	mergeToolName   = "(merged tools)"
 )
 
 // AddSpanProcessor adds a span processor to the local tracer config.
 func AddSpanProcessor(processor sdktrace.SpanProcessor) {
-	localTracerConfig.mu.Lock()
-	defer localTracerConfig.mu.Unlock()
-	localTracerConfig.spanProcessors = append(localTracerConfig.spanProcessors, processor)
+	tracing.AddSpanProcessor(processor)
 }
 
 // RegisterTelemetry sets up the local tracer that will be used to emit traces.
 // We use local tracer to respect the global tracer configurations.
 func RegisterTelemetry() {
-	once.Do(func() {
-		traceProvider := sdktrace.NewTracerProvider()
-		localTracerConfig.mu.RLock()
-		spanProcessors := localTracerConfig.spanProcessors
-		localTracerConfig.mu.RUnlock()
-		for _, processor := range spanProcessors {
-			traceProvider.RegisterSpanProcessor(processor)
-		}
-		localTracer = tracerProviderHolder{tp: traceProvider}
-	})
+	tracing.Register()
 }
 
-// If the global tracer is not set, the default NoopTracerProvider will be used.
-// That means that the spans are NOT recording/exporting
-// If the local tracer is not set, we'll set up tracer with all registered span processors.
-func getTracers() []trace.Tracer {
-	if localTracer.tp == nil {
-		RegisterTelemetry()
-	}
-	return []trace.Tracer{
-		localTracer.tp.Tracer(systemName),
-		otel.GetTracerProvider().Tracer(systemName),
+// StartTrace starts a span from the local and from the global tracer, the
+// children of the spans of ctx, and returns a copy of ctx holding them, see
+// tracing.Start. If ctx carries a correlation ID, it is recorded as a span
+// attribute.
+func StartTrace(ctx context.Context, traceName string, attrs ...attribute.KeyValue) (context.Context, []trace.Span) {
+	if correlationID := agent.CorrelationIDFromContext(ctx); correlationID != "" {
+		attrs = append(attrs, attribute.String(gcpVertexAgentCorrelationID, correlationID))
 	}
+	return tracing.Start(ctx, traceName, attrs...)
 }
 
-// StartTrace returns two spans to start emitting events, one from global tracer and second from the local.
-func StartTrace(ctx context.Context, traceName string) []trace.Span {
-	tracers := getTracers()
-	spans := make([]trace.Span, len(tracers))
-	for i, tracer := range tracers {
-		_, span := tracer.Start(ctx, traceName)
-		spans[i] = span
-	}
-	return spans
+// StartInvocation starts the spans of an invocation of the runner, the
+// parents of the spans of its agent runs.
+func StartInvocation(ctx context.Context, appName, userID, sessionID string) (context.Context, []trace.Span) {
+	return StartTrace(ctx, "invocation",
+		attribute.String(genAiSystemName, systemName),
+		attribute.String(genAiConversationID, sessionID),
+		attribute.String(gcpVertexAgentSessionID, sessionID),
+		attribute.String(gcpVertexAgentAppName, appName),
+		attribute.String(gcpVertexAgentUserID, userID),
+	)
+}
+
+// TraceInvocation records the ID of the invocation on its spans.
+func TraceInvocation(spans []trace.Span, invocationID string) {
+	tracing.SetAttributes(spans, attribute.String(gcpVertexAgentInvocationID, invocationID))
+}
+
+// EndTrace ends the spans, recording the error, if any.
+func EndTrace(spans []trace.Span, err error) {
+	tracing.End(spans, err)
 }
 
 // TraceMergedToolCalls traces the tool execution events.
@@ -168,7 +156,7 @@ func TraceToolCall(spans []trace.Span, tool tool.Tool, fnArgs map[string]any, fn
 			// applicable for tool_response.
 			attribute.String(gcpVertexAgentLLMRequestName, "{}"),
 			attribute.String(gcpVertexAgentLLMRequestName, "{}"),
-			attribute.String(gcpVertexAgentToolCallArgsName, safeSerialize(fnArgs)),
+			attribute.String(gcpVertexAgentToolCallArgsName, safeSerialize(RedactToolArgs(tool.Name(), fnArgs))),
 			attribute.String(gcpVertexAgentEventID, fnResponseEvent.ID),
 		}
 
@@ -199,11 +187,15 @@ func TraceToolCall(spans []trace.Span, tool tool.Tool, fnArgs map[string]any, fn
 	}
 }
 
-// TraceLLMCall fills the call_llm event details.
+// TraceLLMCall fills the call_llm span with the details of the request and
+// of the response event. The spans are ended by the caller, see EndTrace,
+// once the function calls of the response are handled.
 func TraceLLMCall(spans []trace.Span, agentCtx agent.InvocationContext, llmRequest *model.LLMRequest, event *session.Event) {
 	for _, span := range spans {
 		attributes := []attribute.KeyValue{
 			attribute.String(genAiSystemName, systemName),
+			attribute.String(genAiOperationName, chatName),
+			attribute.String(genAiAgentName, agentCtx.Agent().Name()),
 			attribute.String(genAiRequestModelName, llmRequest.Model),
 			attribute.String(gcpVertexAgentInvocationID, event.InvocationID),
 			attribute.String(gcpVertexAgentSessionID, agentCtx.Session().ID()),
@@ -212,22 +204,28 @@ func TraceLLMCall(spans []trace.Span, agentCtx agent.InvocationContext, llmReque
 			attribute.String(gcpVertexAgentLLMResponseName, safeSerialize(event.LLMResponse)),
 		}
 
-		if llmRequest.Config.TopP != nil {
+		if llmRequest.Config != nil && llmRequest.Config.TopP != nil {
 			attributes = append(attributes, attribute.Float64(genAiRequestTopP, float64(*llmRequest.Config.TopP)))
 		}
 
-		if llmRequest.Config.MaxOutputTokens != 0 {
+		if llmRequest.Config != nil && llmRequest.Config.MaxOutputTokens != 0 {
 			attributes = append(attributes, attribute.Int(genAiRequestMaxTokens, int(llmRequest.Config.MaxOutputTokens)))
 		}
 		if event.FinishReason != "" {
-			attributes = append(attributes, attribute.String(genAiResponseFinishReason, string(event.FinishReason)))
+			attributes = append(attributes,
+				attribute.String(genAiResponseFinishReason, string(event.FinishReason)),
+				attribute.StringSlice(genAiResponseFinishReasons, []string{string(event.FinishReason)}))
 		}
 		if event.UsageMetadata != nil {
 			if event.UsageMetadata.PromptTokenCount > 0 {
-				attributes = append(attributes, attribute.Int(genAiResponsePromptTokenCount, int(event.UsageMetadata.PromptTokenCount)))
+				attributes = append(attributes,
+					attribute.Int(genAiResponsePromptTokenCount, int(event.UsageMetadata.PromptTokenCount)),
+					attribute.Int(genAiUsageInputTokens, int(event.UsageMetadata.PromptTokenCount)))
 			}
 			if event.UsageMetadata.CandidatesTokenCount > 0 {
-				attributes = append(attributes, attribute.Int(genAiResponseCandidatesTokenCount, int(event.UsageMetadata.CandidatesTokenCount)))
+				attributes = append(attributes,
+					attribute.Int(genAiResponseCandidatesTokenCount, int(event.UsageMetadata.CandidatesTokenCount)),
+					attribute.Int(genAiUsageOutputTokens, int(event.UsageMetadata.CandidatesTokenCount)))
 			}
 			if event.UsageMetadata.CachedContentTokenCount > 0 {
 				attributes = append(attributes, attribute.Int(genAiResponseCachedContentTokenCount, int(event.UsageMetadata.CachedContentTokenCount)))
@@ -238,7 +236,6 @@ func TraceLLMCall(spans []trace.Span, agentCtx agent.InvocationContext, llmReque
 		}
 
 		span.SetAttributes(attributes...)
-		span.End()
 	}
 }
 
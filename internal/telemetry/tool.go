@@ -0,0 +1,193 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package telemetry
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	metricnoop "go.opentelemetry.io/otel/metric/noop"
+	"go.opentelemetry.io/otel/trace"
+	tracenoop "go.opentelemetry.io/otel/trace/noop"
+)
+
+const (
+	toolRunArgsSize    = "adk.tool.args_size"
+	toolRunResultSize  = "adk.tool.result_size"
+	toolRunSuccess     = "adk.tool.success"
+	toolRunCounterName = "adk.tool.invocations"
+	toolRunHistName    = "adk.tool.duration"
+
+	modelRetryCounterName = "adk.model.retries"
+)
+
+// ArgsRedactor returns the arguments of a call of the tool to record in the
+// telemetry, e.g. with the sensitive values replaced.
+type ArgsRedactor func(toolName string, args map[string]any) map[string]any
+
+// toolInstruments are the tracer and the instruments of the tool runs, and
+// the counter of the model call retries.
+type toolInstruments struct {
+	tracer       trace.Tracer
+	invocations  metric.Int64Counter
+	duration     metric.Float64Histogram
+	modelRetries metric.Int64Counter
+	redact       ArgsRedactor
+}
+
+var (
+	toolMu           sync.Mutex
+	toolTP           trace.TracerProvider
+	toolMP           metric.MeterProvider
+	toolRedactor     ArgsRedactor
+	toolInstrumentsV = newToolInstruments(nil, nil, nil)
+)
+
+// SetToolTracerProvider sets the provider of the spans of the tool runs. A nil
+// provider disables them.
+func SetToolTracerProvider(tp trace.TracerProvider) {
+	toolMu.Lock()
+	defer toolMu.Unlock()
+	toolTP = tp
+	toolInstrumentsV = newToolInstruments(toolTP, toolMP, toolRedactor)
+}
+
+// SetToolMeterProvider sets the provider of the metrics of the tool runs. A
+// nil provider disables them.
+func SetToolMeterProvider(mp metric.MeterProvider) {
+	toolMu.Lock()
+	defer toolMu.Unlock()
+	toolMP = mp
+	toolInstrumentsV = newToolInstruments(toolTP, toolMP, toolRedactor)
+}
+
+// SetToolArgsRedactor sets the redactor of the recorded tool arguments. A nil
+// redactor records them as they are.
+func SetToolArgsRedactor(redact ArgsRedactor) {
+	toolMu.Lock()
+	defer toolMu.Unlock()
+	toolRedactor = redact
+	toolInstrumentsV = newToolInstruments(toolTP, toolMP, toolRedactor)
+}
+
+func currentToolInstruments() *toolInstruments {
+	toolMu.Lock()
+	defer toolMu.Unlock()
+	return toolInstrumentsV
+}
+
+func newToolInstruments(tp trace.TracerProvider, mp metric.MeterProvider, redact ArgsRedactor) *toolInstruments {
+	if tp == nil {
+		tp = tracenoop.NewTracerProvider()
+	}
+	if mp == nil {
+		mp = metricnoop.NewMeterProvider()
+	}
+	meter := mp.Meter(systemName)
+	ti := &toolInstruments{tracer: tp.Tracer(systemName), redact: redact}
+	var err error
+	if ti.invocations, err = meter.Int64Counter(toolRunCounterName,
+		metric.WithDescription("Number of tool runs."),
+		metric.WithUnit("{run}")); err != nil {
+		ti.invocations, _ = metricnoop.Meter{}.Int64Counter(toolRunCounterName)
+	}
+	if ti.duration, err = meter.Float64Histogram(toolRunHistName,
+		metric.WithDescription("Duration of the tool runs."),
+		metric.WithUnit("s")); err != nil {
+		ti.duration, _ = metricnoop.Meter{}.Float64Histogram(toolRunHistName)
+	}
+	if ti.modelRetries, err = meter.Int64Counter(modelRetryCounterName,
+		metric.WithDescription("Number of retries of failed model calls."),
+		metric.WithUnit("{retry}")); err != nil {
+		ti.modelRetries, _ = metricnoop.Meter{}.Int64Counter(modelRetryCounterName)
+	}
+	return ti
+}
+
+// RedactToolArgs returns the arguments of the call of the tool as they are
+// recorded in the telemetry.
+func RedactToolArgs(toolName string, args map[string]any) map[string]any {
+	redact := currentToolInstruments().redact
+	if redact == nil || args == nil {
+		return args
+	}
+	return redact(toolName, args)
+}
+
+// StartToolRun starts the span of a run of the tool, a child of the span of
+// ctx if any, named after the tool. It returns the context of the span and
+// the function ending the run, which records the result or the error on the
+// span and in the metrics.
+//
+// Nothing is recorded unless a provider is set, see SetToolTracerProvider and
+// SetToolMeterProvider.
+func StartToolRun(ctx context.Context, toolName string, args any) (context.Context, func(result map[string]any, err error)) {
+	ti := currentToolInstruments()
+	attrs := []attribute.KeyValue{
+		attribute.String(genAiOperationName, executeToolName),
+		attribute.String(genAiToolName, toolName),
+	}
+	ctx, span := ti.tracer.Start(ctx, toolName, trace.WithAttributes(attrs...))
+	if span.IsRecording() {
+		recorded := args
+		if m, ok := args.(map[string]any); ok && ti.redact != nil {
+			recorded = ti.redact(toolName, m)
+		}
+		span.SetAttributes(
+			attribute.Int(toolRunArgsSize, jsonSize(args)),
+			attribute.String(gcpVertexAgentToolCallArgsName, safeSerialize(recorded)),
+		)
+	}
+	start := time.Now()
+
+	return ctx, func(result map[string]any, err error) {
+		success := err == nil
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		} else {
+			if span.IsRecording() {
+				span.SetAttributes(attribute.Int(toolRunResultSize, jsonSize(result)))
+			}
+			span.SetStatus(codes.Ok, "")
+		}
+		span.SetAttributes(attribute.Bool(toolRunSuccess, success))
+		span.End()
+
+		metricAttrs := metric.WithAttributes(
+			attribute.String(genAiToolName, toolName),
+			attribute.Bool(toolRunSuccess, success),
+		)
+		// The context may be done, the measurements are recorded anyway.
+		mctx := context.WithoutCancel(ctx)
+		ti.invocations.Add(mctx, 1, metricAttrs)
+		ti.duration.Record(mctx, time.Since(start).Seconds(), metricAttrs)
+	}
+}
+
+// jsonSize returns the size of the JSON encoding of v, or 0 if v does not
+// encode.
+func jsonSize(v any) int {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return 0
+	}
+	return len(b)
+}
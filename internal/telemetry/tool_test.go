@@ -0,0 +1,84 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package telemetry
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestStartToolRun(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	SetToolTracerProvider(tp)
+	SetToolArgsRedactor(func(_ string, args map[string]any) map[string]any {
+		return map[string]any{"secret": "REDACTED"}
+	})
+	t.Cleanup(func() {
+		SetToolTracerProvider(nil)
+		SetToolArgsRedactor(nil)
+	})
+
+	parentCtx, parent := tp.Tracer("test").Start(context.Background(), "parent")
+	_, end := StartToolRun(parentCtx, "lookup", map[string]any{"secret": "hunter2"})
+	end(map[string]any{"ok": true}, nil)
+	_, end = StartToolRun(parentCtx, "lookup", map[string]any{})
+	end(nil, errors.New("boom"))
+	parent.End()
+
+	spans := recorder.Ended()
+	if len(spans) != 3 {
+		t.Fatalf("got %d ended spans, want 3", len(spans))
+	}
+	ok, failed := spans[0], spans[1]
+	for _, s := range []sdktrace.ReadOnlySpan{ok, failed} {
+		if s.Name() != "lookup" {
+			t.Errorf("span name = %q, want %q", s.Name(), "lookup")
+		}
+		if s.Parent().SpanID() != parent.SpanContext().SpanID() {
+			t.Errorf("span %q is not a child of the span of the context", s.Name())
+		}
+	}
+	if got := ok.Status().Code; got != codes.Ok {
+		t.Errorf("status of the successful run = %v, want %v", got, codes.Ok)
+	}
+	if got := failed.Status().Code; got != codes.Error {
+		t.Errorf("status of the failed run = %v, want %v", got, codes.Error)
+	}
+	if len(failed.Events()) == 0 {
+		t.Errorf("the error of the failed run was not recorded")
+	}
+
+	attrs := map[attribute.Key]attribute.Value{}
+	for _, kv := range ok.Attributes() {
+		attrs[kv.Key] = kv.Value
+	}
+	if got := attrs[gcpVertexAgentToolCallArgsName].AsString(); strings.Contains(got, "hunter2") || !strings.Contains(got, "REDACTED") {
+		t.Errorf("recorded args = %q, want them redacted", got)
+	}
+	if got := attrs[toolRunResultSize].AsInt64(); got != int64(len(`{"ok":true}`)) {
+		t.Errorf("result size = %d, want %d", got, len(`{"ok":true}`))
+	}
+	if !attrs[toolRunSuccess].AsBool() {
+		t.Errorf("%s = false, want true", toolRunSuccess)
+	}
+}
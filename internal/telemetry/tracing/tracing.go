@@ -0,0 +1,127 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package tracing holds the tracers of the spans of the ADK: the local one,
+// exporting to the registered span processors, and the one of the global
+// tracer provider. It depends on no ADK package, so that the agent package
+// can trace the agent runs.
+package tracing
+
+import (
+	"context"
+	"sync"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// SystemName is the name of the tracers, and the gen_ai.system of the spans.
+const SystemName = "gcp.vertex.agent"
+
+var (
+	once           sync.Once
+	localTP        trace.TracerProvider
+	mu             sync.RWMutex
+	spanProcessors []sdktrace.SpanProcessor
+)
+
+// AddSpanProcessor adds a span processor to the local tracer provider. It is
+// ignored once the provider is registered, see Register.
+func AddSpanProcessor(processor sdktrace.SpanProcessor) {
+	mu.Lock()
+	defer mu.Unlock()
+	spanProcessors = append(spanProcessors, processor)
+}
+
+// Register sets up the local tracer provider with the span processors added
+// so far. It is called by the first span, if not before.
+func Register() {
+	once.Do(func() {
+		tp := sdktrace.NewTracerProvider()
+		mu.RLock()
+		for _, processor := range spanProcessors {
+			tp.RegisterSpanProcessor(processor)
+		}
+		mu.RUnlock()
+		localTP = tp
+	})
+}
+
+// tracers returns the local tracer and the tracer of the global provider,
+// which is a no-op unless the global provider is set.
+func tracers() []trace.Tracer {
+	Register()
+	return []trace.Tracer{
+		localTP.Tracer(SystemName),
+		otel.GetTracerProvider().Tracer(SystemName),
+	}
+}
+
+type parentsKey struct{}
+
+// Start starts a span with each tracer, the child of the spans of ctx
+// started by the same tracer, if any. It returns the spans and a copy of ctx
+// holding them, in which the span of the global tracer is the current span,
+// see trace.SpanFromContext, so that the spans of the instrumented clients,
+// e.g. of the model calls, are its children.
+func Start(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, []trace.Span) {
+	parents, _ := ctx.Value(parentsKey{}).([]trace.Span)
+	tracers := tracers()
+	spans := make([]trace.Span, len(tracers))
+	for i, tracer := range tracers {
+		parentCtx := ctx
+		if i < len(parents) {
+			parentCtx = trace.ContextWithSpan(ctx, parents[i])
+		}
+		_, spans[i] = tracer.Start(parentCtx, name, trace.WithAttributes(attrs...))
+	}
+	ctx = context.WithValue(ctx, parentsKey{}, spans)
+	return trace.ContextWithSpan(ctx, spans[len(spans)-1]), spans
+}
+
+// StartAgent starts the spans of a run of the agent, see Start.
+func StartAgent(ctx context.Context, name, description, invocationID, correlationID string) (context.Context, []trace.Span) {
+	attrs := []attribute.KeyValue{
+		attribute.String("gen_ai.system", SystemName),
+		attribute.String("gen_ai.operation.name", "invoke_agent"),
+		attribute.String("gen_ai.agent.name", name),
+		attribute.String("gen_ai.agent.description", description),
+		attribute.String("gcp.vertex.agent.invocation_id", invocationID),
+	}
+	if correlationID != "" {
+		attrs = append(attrs, attribute.String("gcp.vertex.agent.correlation_id", correlationID))
+	}
+	return Start(ctx, "invoke_agent "+name, attrs...)
+}
+
+// SetAttributes sets the attributes on the spans.
+func SetAttributes(spans []trace.Span, attrs ...attribute.KeyValue) {
+	for _, span := range spans {
+		span.SetAttributes(attrs...)
+	}
+}
+
+// End ends the spans, recording the error, if any.
+func End(spans []trace.Span, err error) {
+	for _, span := range spans {
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		}
+		span.End()
+	}
+}
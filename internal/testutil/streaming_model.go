@@ -0,0 +1,275 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package testutil
+
+import (
+	"context"
+	"fmt"
+	"iter"
+	"maps"
+	"reflect"
+	"regexp"
+	"slices"
+	"strings"
+
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/internal/llminternal"
+	"google.golang.org/adk/model"
+)
+
+// MockStream is the scripted stream of one model call: the chunks the model
+// sends, in order, and the error ending the stream, if any.
+type MockStream struct {
+	Chunks []*genai.GenerateContentResponse
+	// Err, if set, is yielded after the chunks.
+	Err error
+}
+
+// MockStreamingModel is a model replaying scripted streams, one per call.
+// Like the Gemini model, it passes the chunks through the streaming
+// aggregator, so the partial responses and the aggregated responses it
+// yields are the ones the agents get from a real streaming model.
+//
+// Called without streaming, it yields only the non-partial responses.
+type MockStreamingModel struct {
+	Requests []*model.LLMRequest
+	Streams  []MockStream
+}
+
+// GenerateContent implements model.LLM.
+func (m *MockStreamingModel) GenerateContent(ctx context.Context, req *model.LLMRequest, stream bool) iter.Seq2[*model.LLMResponse, error] {
+	return func(yield func(*model.LLMResponse, error) bool) {
+		m.Requests = append(m.Requests, req)
+		if len(m.Streams) == 0 {
+			yield(nil, errNoModelData)
+			return
+		}
+		script := m.Streams[0]
+		m.Streams = m.Streams[1:]
+
+		aggregator := llminternal.NewStreamingResponseAggregator()
+		for _, chunk := range script.Chunks {
+			for resp, err := range aggregator.ProcessResponse(ctx, chunk) {
+				if !stream && err == nil && resp.Partial {
+					continue
+				}
+				if !yield(resp, err) {
+					return // Consumer stopped
+				}
+			}
+		}
+		if closeResult := aggregator.Close(); closeResult != nil {
+			if !yield(closeResult, nil) {
+				return
+			}
+		}
+		if script.Err != nil {
+			yield(nil, script.Err)
+		}
+	}
+}
+
+// Name implements model.LLM.
+func (m *MockStreamingModel) Name() string {
+	return "mock-streaming"
+}
+
+var _ model.LLM = (*MockStreamingModel)(nil)
+
+// Chunk returns a chunk of a model stream with the given parts.
+func Chunk(parts ...*genai.Part) *genai.GenerateContentResponse {
+	return &genai.GenerateContentResponse{
+		Candidates: []*genai.Candidate{{Content: &genai.Content{Role: genai.RoleModel, Parts: parts}}},
+	}
+}
+
+// FinalChunk returns the last chunk of a model stream, with the finish reason
+// and the given parts.
+func FinalChunk(reason genai.FinishReason, parts ...*genai.Part) *genai.GenerateContentResponse {
+	chunk := Chunk(parts...)
+	chunk.Candidates[0].FinishReason = reason
+	return chunk
+}
+
+// Chunks returns one chunk per part, e.g. for the parts returned by
+// SplitFunctionCall.
+func Chunks(parts ...*genai.Part) []*genai.GenerateContentResponse {
+	chunks := make([]*genai.GenerateContentResponse, 0, len(parts))
+	for _, part := range parts {
+		chunks = append(chunks, Chunk(part))
+	}
+	return chunks
+}
+
+// TextChunks splits the text into chunks at the given character offsets.
+// Thought marks the parts as thoughts.
+func TextChunks(text string, thought bool, cuts ...int) []*genai.GenerateContentResponse {
+	pieces := splitAt(text, 0, cuts)
+	chunks := make([]*genai.GenerateContentResponse, 0, len(pieces))
+	for _, piece := range pieces {
+		chunks = append(chunks, Chunk(&genai.Part{Text: piece.text, Thought: thought}))
+	}
+	return chunks
+}
+
+// SplitFunctionCall splits the function call into the parts streamed by a
+// model streaming function call arguments, one part per chunk.
+//
+// The arguments are flattened into one partial argument per leaf value,
+// addressed by its JSON path, in the order of the sorted keys. The cuts are
+// offsets in the sequence of the leaf values, where a string counts for its
+// number of characters and any other value for one: a cut inside a string
+// splits it into several partial arguments with the same path, all but the
+// last marked WillContinue. Hence SplitFunctionCall(call, 2, 5) of a call with
+// the arguments {"city": "Paris", "unit": "C"} returns three parts holding
+// "Pa", "ris" and "C".
+//
+// The first part holds the name and the ID of the call. All the parts but the
+// last are marked WillContinue. Empty maps and lists are omitted. The cuts
+// must be increasing and within the leaf values, SplitFunctionCall panics
+// otherwise.
+func SplitFunctionCall(call *genai.FunctionCall, cuts ...int) []*genai.Part {
+	leaves := flattenArgs("$", call.Args, nil)
+	var total int
+	for _, leaf := range leaves {
+		total += leaf.size()
+	}
+	for i, cut := range cuts {
+		if cut <= 0 || cut >= total || (i > 0 && cut <= cuts[i-1]) {
+			panic(fmt.Sprintf("invalid cuts %v of function call arguments of size %d", cuts, total))
+		}
+	}
+
+	calls := make([]*genai.FunctionCall, len(cuts)+1)
+	for i := range calls {
+		calls[i] = &genai.FunctionCall{WillContinue: genai.Ptr(i < len(cuts))}
+	}
+	calls[0].Name = call.Name
+	calls[0].ID = call.ID
+	// chunkAt returns the index of the chunk holding the offset.
+	chunkAt := func(offset int) int {
+		n, _ := slices.BinarySearch(cuts, offset+1)
+		return n
+	}
+
+	var offset int
+	for _, leaf := range leaves {
+		s, ok := leaf.value.(string)
+		if !ok || s == "" {
+			arg := leaf.partialArg()
+			c := calls[chunkAt(offset)]
+			c.PartialArgs = append(c.PartialArgs, arg)
+			offset += leaf.size()
+			continue
+		}
+		pieces := splitAt(s, offset, cuts)
+		for i, piece := range pieces {
+			arg := &genai.PartialArg{JsonPath: leaf.path, StringValue: piece.text}
+			if i < len(pieces)-1 {
+				arg.WillContinue = genai.Ptr(true)
+			}
+			c := calls[chunkAt(piece.offset)]
+			c.PartialArgs = append(c.PartialArgs, arg)
+		}
+		offset += leaf.size()
+	}
+
+	parts := make([]*genai.Part, 0, len(calls))
+	for _, c := range calls {
+		parts = append(parts, &genai.Part{FunctionCall: c})
+	}
+	return parts
+}
+
+type piece struct {
+	text   string
+	offset int // offset of the piece in the sequence cut
+}
+
+// splitAt splits s, found at the offset of the sequence, at the cuts within
+// it.
+func splitAt(s string, offset int, cuts []int) []piece {
+	var pieces []piece
+	start, n := 0, offset
+	for i := range s {
+		if i > 0 && slices.Contains(cuts, n) {
+			pieces = append(pieces, piece{text: s[start:i], offset: offset})
+			start, offset = i, n
+		}
+		n++
+	}
+	return append(pieces, piece{text: s[start:], offset: offset})
+}
+
+type leaf struct {
+	path  string
+	value any
+}
+
+func (l leaf) size() int {
+	if s, ok := l.value.(string); ok {
+		return len([]rune(s))
+	}
+	return 1
+}
+
+func (l leaf) partialArg() *genai.PartialArg {
+	arg := &genai.PartialArg{JsonPath: l.path}
+	switch v := l.value.(type) {
+	case nil:
+		arg.NULLValue = "NULL_VALUE"
+	case string:
+		arg.StringValue = v
+	case bool:
+		arg.BoolValue = genai.Ptr(v)
+	default:
+		rv := reflect.ValueOf(v)
+		switch {
+		case rv.CanFloat():
+			arg.NumberValue = genai.Ptr(rv.Float())
+		case rv.CanInt():
+			arg.NumberValue = genai.Ptr(float64(rv.Int()))
+		case rv.CanUint():
+			arg.NumberValue = genai.Ptr(float64(rv.Uint()))
+		default:
+			panic(fmt.Sprintf("unsupported function call argument %s of type %T", l.path, v))
+		}
+	}
+	return arg
+}
+
+var identifierRE = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// flattenArgs appends the leaf values of v, at the JSON path, to leaves.
+func flattenArgs(path string, v any, leaves []leaf) []leaf {
+	switch v := v.(type) {
+	case map[string]any:
+		for _, key := range slices.Sorted(maps.Keys(v)) {
+			keyPath := path + "." + key
+			if !identifierRE.MatchString(key) {
+				keyPath = path + "['" + strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(key) + "']"
+			}
+			leaves = flattenArgs(keyPath, v[key], leaves)
+		}
+	case []any:
+		for i, item := range v {
+			leaves = flattenArgs(fmt.Sprintf("%s[%d]", path, i), item, leaves)
+		}
+	default:
+		leaves = append(leaves, leaf{path: path, value: v})
+	}
+	return leaves
+}
@@ -0,0 +1,143 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package testutil_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/internal/testutil"
+	"google.golang.org/adk/model"
+)
+
+func TestSplitFunctionCall(t *testing.T) {
+	call := &genai.FunctionCall{
+		ID:   "call1",
+		Name: "get_weather",
+		Args: map[string]any{
+			"city":   "Paris",
+			"days":   3,
+			"hourly": true,
+			"place":  map[string]any{"zip code": "75001"},
+			"tags":   []any{"rain", nil},
+		},
+	}
+	// The leaf values, in order: $.city "Paris" (0-5), $.days 3 (5),
+	// $.hourly true (6), $.place['zip code'] "75001" (7-12), $.tags[0] "rain"
+	// (12-16), $.tags[1] null (16).
+	got := testutil.SplitFunctionCall(call, 2, 6, 9, 16)
+
+	want := []*genai.Part{
+		{FunctionCall: &genai.FunctionCall{ID: "call1", Name: "get_weather", WillContinue: genai.Ptr(true), PartialArgs: []*genai.PartialArg{
+			{JsonPath: "$.city", StringValue: "Pa", WillContinue: genai.Ptr(true)},
+		}}},
+		{FunctionCall: &genai.FunctionCall{WillContinue: genai.Ptr(true), PartialArgs: []*genai.PartialArg{
+			{JsonPath: "$.city", StringValue: "ris"},
+			{JsonPath: "$.days", NumberValue: genai.Ptr(3.0)},
+		}}},
+		{FunctionCall: &genai.FunctionCall{WillContinue: genai.Ptr(true), PartialArgs: []*genai.PartialArg{
+			{JsonPath: "$.hourly", BoolValue: genai.Ptr(true)},
+			{JsonPath: "$.place['zip code']", StringValue: "75", WillContinue: genai.Ptr(true)},
+		}}},
+		{FunctionCall: &genai.FunctionCall{WillContinue: genai.Ptr(true), PartialArgs: []*genai.PartialArg{
+			{JsonPath: "$.place['zip code']", StringValue: "001"},
+			{JsonPath: "$.tags[0]", StringValue: "rain"},
+		}}},
+		{FunctionCall: &genai.FunctionCall{WillContinue: genai.Ptr(false), PartialArgs: []*genai.PartialArg{
+			{JsonPath: "$.tags[1]", NULLValue: "NULL_VALUE"},
+		}}},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("SplitFunctionCall() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestSplitFunctionCall_InvalidCuts(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("SplitFunctionCall() with a cut past the arguments did not panic")
+		}
+	}()
+	testutil.SplitFunctionCall(&genai.FunctionCall{Name: "f", Args: map[string]any{"a": "xy"}}, 2)
+}
+
+func TestMockStreamingModel(t *testing.T) {
+	errInterrupted := errors.New("interrupted")
+	newModel := func() *testutil.MockStreamingModel {
+		var chunks []*genai.GenerateContentResponse
+		chunks = append(chunks, testutil.TextChunks("Let me think.", true, 4)...)
+		chunks = append(chunks, testutil.TextChunks("Hello!", false, 2)...)
+		chunks = append(chunks, testutil.FinalChunk(genai.FinishReasonStop))
+		return &testutil.MockStreamingModel{Streams: []testutil.MockStream{
+			{Chunks: chunks},
+			{Chunks: testutil.TextChunks("Bye", false, 1), Err: errInterrupted},
+		}}
+	}
+	type response struct {
+		text    string
+		partial bool
+		err     error
+	}
+	collect := func(m model.LLM, stream bool) []response {
+		var got []response
+		for resp, err := range m.GenerateContent(t.Context(), &model.LLMRequest{}, stream) {
+			r := response{err: err}
+			if resp != nil && resp.Content != nil {
+				r.partial = resp.Partial
+				for _, p := range resp.Content.Parts {
+					r.text += p.Text + "|"
+				}
+			}
+			got = append(got, r)
+		}
+		return got
+	}
+	opts := cmp.Comparer(func(a, b error) bool { return errors.Is(a, b) })
+
+	m := newModel()
+	want := [][]response{
+		{
+			{text: "Let |", partial: true},
+			{text: "me think.|", partial: true},
+			{text: "He|", partial: true},
+			{text: "llo!|", partial: true},
+			{text: "Let me think.|Hello!|"},
+			{},
+		},
+		{
+			{text: "B|", partial: true},
+			{text: "ye|", partial: true},
+			{text: "Bye|"},
+			{err: errInterrupted},
+		},
+	}
+	for i := range want {
+		if diff := cmp.Diff(want[i], collect(m, true), cmp.AllowUnexported(response{}), opts); diff != "" {
+			t.Errorf("stream %d mismatch (-want +got):\n%s", i, diff)
+		}
+	}
+	if len(m.Requests) != 2 {
+		t.Errorf("got %d requests, want 2", len(m.Requests))
+	}
+
+	m = newModel()
+	wantNonStreaming := []response{{text: "Let me think.|Hello!|"}, {}}
+	if diff := cmp.Diff(wantNonStreaming, collect(m, false), cmp.AllowUnexported(response{}), opts); diff != "" {
+		t.Errorf("non-streaming mismatch (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,62 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package toolinternal
+
+import (
+	"google.golang.org/adk/auth"
+	"google.golang.org/adk/internal/typeutil"
+)
+
+func (c *toolContext) RequestCredential(cfg *auth.AuthConfig) {
+	if c.eventActions.RequestedAuthConfigs == nil {
+		c.eventActions.RequestedAuthConfigs = make(map[string]*auth.AuthConfig)
+	}
+	c.eventActions.RequestedAuthConfigs[c.functionCallID] = cfg
+}
+
+func (c *toolContext) GetAuthResponse(cfg *auth.AuthConfig) *auth.AuthCredential {
+	key, err := cfg.Key()
+	if err != nil {
+		return nil
+	}
+	val, err := c.State().Get(key)
+	if err != nil {
+		return nil
+	}
+	cred, err := typeutil.ConvertToWithJSONSchema[any, *auth.AuthCredential](val, nil)
+	if err != nil {
+		return nil
+	}
+	// An expired access token is refreshed, and the new one stored in its
+	// place. A credential which cannot be refreshed is requested again.
+	refreshed, ok, err := auth.RefreshCredential(c, cfg.AuthScheme, cfg.RawAuthCredential, cred)
+	if err != nil {
+		return nil
+	}
+	if ok {
+		val, err := CredentialStateValue(refreshed)
+		if err != nil || c.State().Set(key, val) != nil {
+			return nil
+		}
+	}
+	return refreshed
+}
+
+// CredentialStateValue returns the value of the credential stored in the
+// session state, read back by GetAuthResponse. It is the JSON form of the
+// credential, kept as is by the session services.
+func CredentialStateValue(cred *auth.AuthCredential) (map[string]any, error) {
+	return typeutil.ConvertToWithJSONSchema[*auth.AuthCredential, map[string]any](cred, nil)
+}
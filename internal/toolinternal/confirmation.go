@@ -0,0 +1,43 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package toolinternal
+
+import (
+	"google.golang.org/adk/tool"
+	"google.golang.org/adk/tool/toolconfirmation"
+)
+
+func (c *toolContext) RequestConfirmation(hint string, payload any) {
+	if c.eventActions.RequestedToolConfirmations == nil {
+		c.eventActions.RequestedToolConfirmations = make(map[string]*toolconfirmation.ToolConfirmation)
+	}
+	c.eventActions.RequestedToolConfirmations[c.functionCallID] = &toolconfirmation.ToolConfirmation{
+		Hint:    hint,
+		Payload: payload,
+	}
+}
+
+func (c *toolContext) ToolConfirmation() *toolconfirmation.ToolConfirmation {
+	return c.confirmation
+}
+
+// WithToolConfirmation sets the confirmation sent by the client for the call
+// of the tool context created by NewToolContext, and returns the context.
+func WithToolConfirmation(ctx tool.Context, confirmation *toolconfirmation.ToolConfirmation) tool.Context {
+	if tc, ok := ctx.(*toolContext); ok {
+		tc.confirmation = confirmation
+	}
+	return ctx
+}
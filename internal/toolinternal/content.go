@@ -0,0 +1,68 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package toolinternal
+
+import (
+	"fmt"
+	"maps"
+	"strings"
+
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/tool"
+)
+
+// contentPartsKey is the key of the result holding the media parts of a
+// tool.Content, moved out of the function response by SplitContentResult.
+const contentPartsKey = "__adk_content_parts"
+
+// ContentResult returns the result of a tool returning the content: the text
+// parts are joined in "output", and the other parts are kept for
+// SplitContentResult.
+func ContentResult(c *tool.Content) (map[string]any, error) {
+	var texts []string
+	var parts []*genai.Part
+	if c != nil {
+		for i, p := range c.Parts {
+			switch {
+			case p == nil:
+			case p.Text != "":
+				texts = append(texts, p.Text)
+			case p.InlineData != nil || p.FileData != nil:
+				parts = append(parts, p)
+			default:
+				return nil, fmt.Errorf("unsupported part %d of the content, only text, inline data and file data parts are supported", i)
+			}
+		}
+	}
+	result := map[string]any{"output": strings.Join(texts, "\n")}
+	if len(parts) > 0 {
+		result[contentPartsKey] = parts
+	}
+	return result, nil
+}
+
+// SplitContentResult splits the result of a tool into the response of the
+// function and the parts to send with it, see ContentResult. The result is
+// not modified.
+func SplitContentResult(result map[string]any) (map[string]any, []*genai.Part) {
+	parts, ok := result[contentPartsKey].([]*genai.Part)
+	if !ok {
+		return result, nil
+	}
+	response := maps.Clone(result)
+	delete(response, contentPartsKey)
+	return response, parts
+}
@@ -16,6 +16,10 @@ package toolinternal
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"iter"
+	"time"
 
 	"github.com/google/uuid"
 	"google.golang.org/genai"
@@ -26,6 +30,7 @@ import (
 	"google.golang.org/adk/memory"
 	"google.golang.org/adk/session"
 	"google.golang.org/adk/tool"
+	"google.golang.org/adk/tool/toolconfirmation"
 )
 
 type internalArtifacts struct {
@@ -34,6 +39,9 @@ type internalArtifacts struct {
 }
 
 func (ia *internalArtifacts) Save(ctx context.Context, name string, data *genai.Part) (*artifact.SaveResponse, error) {
+	if ia.Artifacts == nil {
+		return nil, errors.New("artifact service is not configured")
+	}
 	resp, err := ia.Artifacts.Save(ctx, name, data)
 	if err != nil {
 		return resp, err
@@ -78,6 +86,82 @@ type toolContext struct {
 	functionCallID    string
 	eventActions      *session.EventActions
 	artifacts         *internalArtifacts
+	// functionCall is the function call of the model, see WithFunctionCall.
+	functionCall *genai.FunctionCall
+	// emitPartial emits the partial results of the call, see EmitPartial.
+	emitPartial func(map[string]any) error
+	// confirmation is the confirmation of the call sent by the client, see
+	// WithToolConfirmation.
+	confirmation *toolconfirmation.ToolConfirmation
+	// metadata is the metadata of the function response, see
+	// SetResponseMetadata.
+	metadata map[string]any
+}
+
+// WithFunctionCall sets the function call of the model run with the tool
+// context created by NewToolContext, and returns the context. The ID of the
+// call must be the one of the context.
+func WithFunctionCall(ctx tool.Context, fc *genai.FunctionCall) tool.Context {
+	if tc, ok := ctx.(*toolContext); ok {
+		tc.functionCall = fc
+	}
+	return ctx
+}
+
+// ErrPartialsStopped is returned by EmitPartial once the consumer of the
+// events stopped listening.
+var ErrPartialsStopped = errors.New("the consumer of the partial results is gone")
+
+// WithPartialEmitter sets the function emitting the partial results of the
+// call of the tool context created by NewToolContext, and returns the
+// context.
+func WithPartialEmitter(ctx tool.Context, emit func(map[string]any) error) tool.Context {
+	if tc, ok := ctx.(*toolContext); ok {
+		tc.emitPartial = emit
+	}
+	return ctx
+}
+
+// EmitPartial emits a partial result of the call, before its final result.
+// The partial results are dropped if the caller of the tool does not
+// consume them.
+func EmitPartial(ctx tool.Context, partial map[string]any) error {
+	if dc, ok := ctx.(*derivedToolContext); ok {
+		ctx = dc.Context
+	}
+	tc, ok := ctx.(*toolContext)
+	if !ok || tc.emitPartial == nil {
+		return nil
+	}
+	return tc.emitPartial(partial)
+}
+
+// SetResponseMetadata sets the value of the key in the metadata of the
+// function response of the call, e.g. to report that its result was cached.
+// The flow reports it in the CustomMetadata of the function response event,
+// under the key, in a map keyed by the function call ID, so that the values
+// of the parallel calls are kept when their events are merged.
+func SetResponseMetadata(ctx tool.Context, key string, value any) {
+	if dc, ok := ctx.(*derivedToolContext); ok {
+		ctx = dc.Context
+	}
+	tc, ok := ctx.(*toolContext)
+	if !ok {
+		return
+	}
+	if tc.metadata == nil {
+		tc.metadata = make(map[string]any)
+	}
+	tc.metadata[key] = value
+}
+
+// ResponseMetadata returns the metadata of the function response set with
+// SetResponseMetadata.
+func ResponseMetadata(ctx tool.Context) map[string]any {
+	if tc, ok := ctx.(*toolContext); ok {
+		return tc.metadata
+	}
+	return nil
 }
 
 func (c *toolContext) Artifacts() agent.Artifacts {
@@ -88,6 +172,14 @@ func (c *toolContext) FunctionCallID() string {
 	return c.functionCallID
 }
 
+func (c *toolContext) FunctionCall() *genai.FunctionCall {
+	return c.functionCall
+}
+
+func (c *toolContext) CorrelationID() string {
+	return agent.CorrelationIDFromContext(c.invocationContext)
+}
+
 func (c *toolContext) Actions() *session.EventActions {
 	return c.eventActions
 }
@@ -96,6 +188,101 @@ func (c *toolContext) AgentName() string {
 	return c.invocationContext.Agent().Name()
 }
 
+// State returns the session state with the changes staged by the tool, see
+// tool.Context.
+func (c *toolContext) State() session.State {
+	return &stagedState{base: c.invocationContext.Session().State(), delta: c.eventActions.StateDelta}
+}
+
+// stagedState is a session state whose changes are staged in a state delta,
+// instead of being applied to the base state.
+type stagedState struct {
+	base  session.State
+	delta map[string]any
+}
+
+func (s *stagedState) Get(key string) (any, error) {
+	if val, ok := s.delta[key]; ok {
+		return val, nil
+	}
+	return s.base.Get(key)
+}
+
+func (s *stagedState) Set(key string, val any) error {
+	s.delta[key] = val
+	return nil
+}
+
+func (s *stagedState) All() iter.Seq2[string, any] {
+	return func(yield func(string, any) bool) {
+		for key, val := range s.base.All() {
+			if _, ok := s.delta[key]; ok {
+				continue
+			}
+			if !yield(key, val) {
+				return
+			}
+		}
+		for key, val := range s.delta {
+			if !yield(key, val) {
+				return
+			}
+		}
+	}
+}
+
+// CommitState applies the state changes staged by the tool to the session
+// state of the invocation, making them visible to the next tools.
+func CommitState(ctx tool.Context) error {
+	tc, ok := ctx.(*toolContext)
+	if !ok {
+		return nil
+	}
+	state := tc.invocationContext.Session().State()
+	for key, val := range tc.eventActions.StateDelta {
+		if err := state.Set(key, val); err != nil {
+			return fmt.Errorf("failed to set state %q: %w", key, err)
+		}
+	}
+	return nil
+}
+
+// DiscardState discards the state changes staged by the tool.
+func DiscardState(ctx tool.Context) {
+	clear(ctx.Actions().StateDelta)
+}
+
 func (c *toolContext) SearchMemory(ctx context.Context, query string) (*memory.SearchResponse, error) {
-	return c.invocationContext.Memory().Search(ctx, query)
+	mem := c.invocationContext.Memory()
+	if mem == nil {
+		return nil, fmt.Errorf("memory service is not configured")
+	}
+	return mem.Search(ctx, query)
+}
+
+// WithContext returns the tool context with the deadline, cancellation and
+// values of ctx, which is usually derived from it, e.g. with a timeout.
+func WithContext(toolCtx tool.Context, ctx context.Context) tool.Context {
+	return &derivedToolContext{Context: toolCtx, ctx: ctx}
+}
+
+type derivedToolContext struct {
+	tool.Context
+	ctx context.Context
+}
+
+func (c *derivedToolContext) Deadline() (time.Time, bool) {
+	return c.ctx.Deadline()
+}
+
+func (c *derivedToolContext) Done() <-chan struct{} {
+	return c.ctx.Done()
+}
+
+func (c *derivedToolContext) Err() error {
+	return c.ctx.Err()
+}
+
+func (c *derivedToolContext) Value(key any) any {
+	return c.ctx.Value(key)
 }
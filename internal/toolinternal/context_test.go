@@ -15,11 +15,13 @@
 package toolinternal
 
 import (
+	"maps"
 	"testing"
 
 	"google.golang.org/adk/agent"
 	contextinternal "google.golang.org/adk/internal/context"
 	"google.golang.org/adk/session"
+	"google.golang.org/adk/tool"
 )
 
 func TestToolContext(t *testing.T) {
@@ -36,3 +38,56 @@ func TestToolContext(t *testing.T) {
 		t.Errorf("ToolContext(%+T) is unexpectedly an InvocationContext", got)
 	}
 }
+
+func TestToolContext_State(t *testing.T) {
+	service := session.InMemoryService()
+	resp, err := service.Create(t.Context(), &session.CreateRequest{AppName: "app", UserID: "user", State: map[string]any{"count": 1}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sess := resp.Session
+	inv := contextinternal.NewInvocationContext(t.Context(), contextinternal.InvocationContextParams{Session: sess})
+
+	newCall := func() tool.Context {
+		toolCtx := NewToolContext(inv, "", nil)
+		val, err := toolCtx.State().Get("count")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := toolCtx.State().Set("count", val.(int)+1); err != nil {
+			t.Fatal(err)
+		}
+		return toolCtx
+	}
+
+	// The changes are staged until they are committed.
+	toolCtx := newCall()
+	if got, _ := toolCtx.State().Get("count"); got != 2 {
+		t.Errorf("staged count = %v, want 2", got)
+	}
+	if got, _ := sess.State().Get("count"); got != 1 {
+		t.Errorf("session count before the commit = %v, want 1", got)
+	}
+	if got := maps.Collect(toolCtx.State().All()); !maps.Equal(got, map[string]any{"count": 2}) {
+		t.Errorf("State().All() = %v, want the staged count", got)
+	}
+	if err := CommitState(toolCtx); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := sess.State().Get("count"); got != 2 {
+		t.Errorf("session count after the commit = %v, want 2", got)
+	}
+
+	// Discarded changes are neither applied nor in the state delta.
+	toolCtx = newCall()
+	DiscardState(toolCtx)
+	if err := CommitState(toolCtx); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := sess.State().Get("count"); got != 2 {
+		t.Errorf("session count after the discard = %v, want 2", got)
+	}
+	if delta := toolCtx.Actions().StateDelta; len(delta) != 0 {
+		t.Errorf("state delta after the discard = %v, want empty", delta)
+	}
+}
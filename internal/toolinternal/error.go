@@ -0,0 +1,48 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package toolinternal
+
+import (
+	"errors"
+	"fmt"
+
+	"google.golang.org/adk/tool"
+)
+
+// ErrorResult returns the result reporting the error of a call to the model:
+// the structured payload of a tool.ToolError, the value of a
+// tool.PanicError, without its stack, with the "panic" code, and
+// {"error": err.Error()} otherwise.
+func ErrorResult(err error) map[string]any {
+	var panicErr *tool.PanicError
+	if errors.As(err, &panicErr) {
+		return map[string]any{"error": map[string]any{
+			"code":    "panic",
+			"message": fmt.Sprintf("panic in tool %q: %v", panicErr.Tool, panicErr.Value),
+		}}
+	}
+	var toolErr *tool.ToolError
+	if !errors.As(err, &toolErr) {
+		return map[string]any{"error": err.Error()}
+	}
+	payload := map[string]any{"message": toolErr.Message}
+	if toolErr.Code != "" {
+		payload["code"] = toolErr.Code
+	}
+	if len(toolErr.Details) > 0 {
+		payload["details"] = toolErr.Details
+	}
+	return map[string]any{"error": payload}
+}
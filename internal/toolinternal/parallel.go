@@ -0,0 +1,95 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package toolinternal
+
+import (
+	"context"
+	"fmt"
+	"runtime/debug"
+	"sync"
+
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/tool"
+)
+
+// ParallelOptions configures RunParallel.
+type ParallelOptions struct {
+	// MaxParallelism is the maximum number of calls run at once. Zero or
+	// less means no limit.
+	MaxParallelism int
+	// AbortOnError cancels the context of the running calls, and skips the
+	// calls not started yet, once a call fails.
+	AbortOnError bool
+}
+
+// RunParallel runs the function calls concurrently, calling run with the
+// index of each call, and returns the error of each call, in the order of
+// calls. It is the executor of the parallel calls of the flow and of
+// functiontool.RunToolCalls.
+//
+// A panic in run fails its call only, with a *tool.PanicError. The calls are
+// canceled with ctx, and the calls skipped because of it, or of
+// AbortOnError, fail with an error wrapping the error of the context.
+func RunParallel(ctx context.Context, calls []*genai.FunctionCall, opts ParallelOptions, run func(ctx context.Context, i int) error) []error {
+	runCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var sem chan struct{}
+	if opts.MaxParallelism > 0 {
+		sem = make(chan struct{}, opts.MaxParallelism)
+	}
+	errs := make([]error, len(calls))
+	var wg sync.WaitGroup
+	for i, call := range calls {
+		acquired := sem == nil
+		if !acquired {
+			select {
+			case sem <- struct{}{}:
+				acquired = true
+			case <-runCtx.Done():
+			}
+		}
+		if err := runCtx.Err(); err != nil {
+			errs[i] = fmt.Errorf("function call %q not run: %w", call.Name, err)
+			if acquired && sem != nil {
+				<-sem
+			}
+			continue
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if sem != nil {
+				defer func() { <-sem }()
+			}
+			errs[i] = runRecovered(runCtx, call, i, run)
+			if errs[i] != nil && opts.AbortOnError {
+				cancel()
+			}
+		}()
+	}
+	wg.Wait()
+	return errs
+}
+
+func runRecovered(ctx context.Context, call *genai.FunctionCall, i int, run func(ctx context.Context, i int) error) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = &tool.PanicError{Tool: call.Name, Value: r, Stack: debug.Stack()}
+		}
+	}()
+	return run(ctx, i)
+}
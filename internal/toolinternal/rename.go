@@ -0,0 +1,76 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package toolinternal
+
+import (
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/model"
+	"google.golang.org/adk/tool"
+)
+
+// Rename returns the function tool t under another name and description:
+// the model calls it with the name, and its declaration has the name and the
+// description. An empty description keeps the one of t. It is the wrapper of
+// toolset.Rename, toolset.Prefix and metadatatool.WithMetadata.
+func Rename(t FunctionTool, name, description string) FunctionTool {
+	return &renamedTool{FunctionTool: t, name: name, description: description}
+}
+
+type renamedTool struct {
+	FunctionTool
+	name        string
+	description string
+}
+
+func (t *renamedTool) Name() string {
+	return t.name
+}
+
+func (t *renamedTool) Description() string {
+	if t.description == "" {
+		return t.FunctionTool.Description()
+	}
+	return t.description
+}
+
+// Unwrap returns the wrapped tool.
+func (t *renamedTool) Unwrap() FunctionTool {
+	return t.FunctionTool
+}
+
+// Declaration returns the declaration of the wrapped tool, with the name and
+// description of the wrapper.
+func (t *renamedTool) Declaration() *genai.FunctionDeclaration {
+	decl := t.FunctionTool.Declaration()
+	if decl == nil {
+		return nil
+	}
+	renamed := *decl
+	renamed.Name = t.name
+	renamed.Description = t.Description()
+	return &renamed
+}
+
+// ProcessRequest lets the wrapped tool process the request, and registers the
+// wrapper in its place under its name, see RegisterWrapper.
+func (t *renamedTool) ProcessRequest(ctx tool.Context, req *model.LLMRequest) error {
+	return RegisterWrapper(ctx, req, t.FunctionTool, t)
+}
+
+var (
+	_ RequestProcessor = (*renamedTool)(nil)
+	_ WrapperTool      = (*renamedTool)(nil)
+)
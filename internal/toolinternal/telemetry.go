@@ -0,0 +1,39 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package toolinternal
+
+import (
+	"context"
+
+	"google.golang.org/adk/internal/telemetry"
+	"google.golang.org/adk/tool"
+)
+
+// TraceRun runs the tool within the span of its run, see
+// telemetry.StartToolRun. The context given to run carries the span, so that
+// the spans started by the tool are its children.
+func TraceRun(ctx tool.Context, name string, args any, run func(tool.Context) (map[string]any, error)) (map[string]any, error) {
+	parent := context.Context(context.Background())
+	if ctx != nil {
+		parent = ctx
+	}
+	spanCtx, end := telemetry.StartToolRun(parent, name, args)
+	if ctx != nil {
+		ctx = WithContext(ctx, spanCtx)
+	}
+	result, err := run(ctx)
+	end(result, err)
+	return result, err
+}
@@ -31,3 +31,31 @@ type FunctionTool interface {
 type RequestProcessor interface {
 	ProcessRequest(ctx tool.Context, req *model.LLMRequest) error
 }
+
+// SequentialTool is implemented by the tools whose calls must not run
+// concurrently with the other calls of a model response, e.g. because they
+// are not safe for concurrent use.
+type SequentialTool interface {
+	IsSequential() bool
+}
+
+// WrapperTool is implemented by the tools wrapping another tool, e.g. to
+// retry or cache its calls.
+type WrapperTool interface {
+	Unwrap() FunctionTool
+}
+
+// IsSequential reports whether the tool, or a tool it wraps, is sequential.
+func IsSequential(t tool.Tool) bool {
+	for t != nil {
+		if s, ok := t.(SequentialTool); ok && s.IsSequential() {
+			return true
+		}
+		w, ok := t.(WrapperTool)
+		if !ok {
+			return false
+		}
+		t = w.Unwrap()
+	}
+	return false
+}
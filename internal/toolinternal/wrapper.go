@@ -0,0 +1,74 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package toolinternal
+
+import (
+	"fmt"
+
+	"google.golang.org/adk/internal/toolinternal/toolutils"
+	"google.golang.org/adk/model"
+	"google.golang.org/adk/tool"
+)
+
+// RegisterWrapper lets the wrapped tool process the request, and registers
+// the wrapper and its declaration under the name of the wrapper in place of
+// the wrapped tool, so that the model's calls of that name reach the wrapper.
+// It is the ProcessRequest of the tools wrapping a function tool, e.g. to
+// rename it or to retry its calls.
+//
+// The request may have another tool with the name of the wrapped tool, e.g.
+// the wrapped tool itself next to a renamed wrapper; it is kept. The wrapped
+// tool may not register itself, e.g. if it is disabled, in which case neither
+// is the wrapper.
+func RegisterWrapper(ctx tool.Context, req *model.LLMRequest, wrapped, wrapper FunctionTool) error {
+	processor, ok := wrapped.(RequestProcessor)
+	if !ok {
+		return toolutils.PackTool(req, wrapper)
+	}
+	wrappedName := wrapped.Name()
+	other, hasOther := req.Tools[wrappedName]
+	delete(req.Tools, wrappedName)
+	err := processor.ProcessRequest(ctx, req)
+	_, registered := req.Tools[wrappedName]
+	delete(req.Tools, wrappedName)
+	if hasOther {
+		req.Tools[wrappedName] = other
+	}
+	if err != nil || !registered {
+		return err
+	}
+
+	name := wrapper.Name()
+	if _, ok := req.Tools[name]; ok {
+		return fmt.Errorf("duplicate tool: %q", name)
+	}
+	req.Tools[name] = wrapper
+	// The declaration of the wrapped tool is the last one of its name.
+	if req.Config != nil {
+		for i := len(req.Config.Tools) - 1; i >= 0; i-- {
+			genaiTool := req.Config.Tools[i]
+			if genaiTool == nil {
+				continue
+			}
+			for j := len(genaiTool.FunctionDeclarations) - 1; j >= 0; j-- {
+				if genaiTool.FunctionDeclarations[j].Name == wrappedName {
+					genaiTool.FunctionDeclarations[j] = wrapper.Declaration()
+					return nil
+				}
+			}
+		}
+	}
+	return nil
+}
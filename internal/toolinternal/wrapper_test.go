@@ -0,0 +1,64 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package toolinternal_test
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"google.golang.org/adk/internal/toolinternal"
+	"google.golang.org/adk/model"
+	"google.golang.org/adk/tool"
+	"google.golang.org/adk/tool/functiontool"
+	"google.golang.org/adk/tool/metadatatool"
+	"google.golang.org/adk/tool/retrytool"
+)
+
+func TestRegisterWrapper(t *testing.T) {
+	search, err := functiontool.New(functiontool.Config{Name: "search", Description: "Searches the web."}, func(tool.Context, struct{}) (string, error) {
+		return "hit", nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	retrying := retrytool.WithRetry(search, retrytool.RetryPolicy{})
+	recipes := metadatatool.WithMetadata(retrying, "search_recipes", "Searches recipes.")
+
+	// The outermost wrappers are registered under their name, next to the
+	// wrapped tool.
+	req := &model.LLMRequest{}
+	for _, tl := range []tool.Tool{search, recipes} {
+		if err := tl.(toolinternal.RequestProcessor).ProcessRequest(nil, req); err != nil {
+			t.Fatalf("ProcessRequest(%q) error = %v", tl.Name(), err)
+		}
+	}
+	if req.Tools["search"] != search || req.Tools["search_recipes"] != recipes || len(req.Tools) != 2 {
+		t.Errorf("Tools = %v, want the tool and its outermost wrapper", req.Tools)
+	}
+	var declared [][2]string
+	for _, decl := range req.Config.Tools[0].FunctionDeclarations {
+		declared = append(declared, [2]string{decl.Name, decl.Description})
+	}
+	want := [][2]string{{"search", "Searches the web."}, {"search_recipes", "Searches recipes."}}
+	if diff := cmp.Diff(want, declared); diff != "" {
+		t.Errorf("declarations mismatch (-want +got):\n%s", diff)
+	}
+
+	// A wrapper under the name of another tool is a duplicate.
+	if err := retrying.(toolinternal.RequestProcessor).ProcessRequest(nil, req); err == nil {
+		t.Error("ProcessRequest() of a duplicate name succeeded, want error")
+	}
+}
@@ -0,0 +1,130 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package typeutil
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/google/jsonschema-go/jsonschema"
+)
+
+// defsPrefix is the prefix of the references to the schemas of $defs.
+const defsPrefix = "#/$defs/"
+
+// defNameRegexp matches the characters which are not allowed in the names of
+// $defs, e.g. the brackets and package paths of the generic types.
+var defNameRegexp = regexp.MustCompile(`[^A-Za-z0-9_]+`)
+
+// refTypes returns the named struct types reachable from t whose schemas are
+// defined once in $defs and referenced with $ref: the recursive types, which
+// cannot be inlined, and the types of several fields, which are not inlined
+// several times. The types are mapped to their names in $defs.
+func refTypes(t reflect.Type) map[reflect.Type]string {
+	counts := make(map[reflect.Type]int)
+	recursive := make(map[reflect.Type]bool)
+	walked := make(map[reflect.Type]bool)
+	walking := make(map[reflect.Type]bool)
+	var walk func(reflect.Type)
+	walk = func(t reflect.Type) {
+		t = baseType(t)
+		if !isPlainStruct(t) {
+			return
+		}
+		if walking[t] {
+			recursive[t] = true
+			return
+		}
+		if walked[t] {
+			return
+		}
+		walked[t] = true
+		walking[t] = true
+		defer delete(walking, t)
+		for _, field := range reflect.VisibleFields(t) {
+			if field.Anonymous {
+				continue
+			}
+			if _, _, ok := fieldJSONInfo(field); !ok {
+				continue
+			}
+			if ft := baseType(field.Type); isPlainStruct(ft) && ft.Name() != "" {
+				counts[ft]++
+			}
+			walk(field.Type)
+		}
+	}
+	walk(t)
+
+	var types []reflect.Type
+	for ft, n := range counts {
+		if n > 1 || recursive[ft] {
+			types = append(types, ft)
+		}
+	}
+	if len(types) == 0 {
+		return nil
+	}
+	slices.SortFunc(types, func(a, b reflect.Type) int { return strings.Compare(a.String(), b.String()) })
+	names := make(map[reflect.Type]string, len(types))
+	used := make(map[string]bool, len(types))
+	for _, ft := range types {
+		name := defNameRegexp.ReplaceAllString(ft.Name(), "_")
+		for i := 2; used[name]; i++ {
+			name = fmt.Sprintf("%s%d", defNameRegexp.ReplaceAllString(ft.Name(), "_"), i)
+		}
+		used[name] = true
+		names[ft] = name
+	}
+	return names
+}
+
+// baseType returns the type of the values of t, following the pointers,
+// slices, arrays and maps.
+func baseType(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Pointer || t.Kind() == reflect.Slice || t.Kind() == reflect.Array || t.Kind() == reflect.Map {
+		t = t.Elem()
+	}
+	return t
+}
+
+// isPlainStruct reports whether t is a struct encoded as the object of its
+// fields, unlike e.g. time.Time.
+func isPlainStruct(t reflect.Type) bool {
+	if t.Kind() != reflect.Struct {
+		return false
+	}
+	ptr := reflect.PointerTo(t)
+	for _, m := range []reflect.Type{reflect.TypeFor[json.Marshaler](), reflect.TypeFor[encoding.TextMarshaler]()} {
+		if ptr.Implements(m) {
+			return false
+		}
+	}
+	return true
+}
+
+// nullableRef returns the reference of a schema allowing null or the
+// referenced schema, as inferred for the pointers to the types of $defs.
+func nullableRef(s *jsonschema.Schema) (*jsonschema.Schema, bool) {
+	if len(s.AnyOf) != 2 || s.AnyOf[0].Type != "null" || s.AnyOf[1].Ref == "" {
+		return nil, false
+	}
+	return &jsonschema.Schema{Ref: s.AnyOf[1].Ref, Description: s.Description}, true
+}
@@ -0,0 +1,613 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package typeutil
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"google.golang.org/genai"
+)
+
+// ResolvedSchema returns the resolved JSON schema inferred from T, or the
+// resolved override if it is non-nil. Field descriptions, enums and whether
+// fields are required are taken from the `jsonschema` struct tags, see
+// inferSchema.
+//
+// If augment is non-nil, it is called with the inferred schema, or a copy of
+// the override, and the schema it returns is resolved instead, which
+// validates it.
+//
+// An override or augmented schema is checked against the schema inferred from
+// T, when it can be inferred: its type, and the names and types of its
+// properties, down to the fields of the nested structs and the items of the
+// slices, must match T, so that a misconfiguration fails when the tool is
+// created rather than when the model calls it.
+func ResolvedSchema[T any](override *jsonschema.Schema, augment func(*jsonschema.Schema) (*jsonschema.Schema, error)) (*jsonschema.Resolved, error) {
+	schema := override
+	if schema == nil {
+		var err error
+		if schema, err = inferSchema(reflect.TypeFor[T]()); err != nil {
+			return nil, err
+		}
+	} else if augment != nil {
+		schema = schema.CloneSchemas()
+	}
+	if augment != nil {
+		augmented, err := augment(schema)
+		if err != nil {
+			return nil, fmt.Errorf("failed to augment schema: %w", err)
+		}
+		if augmented == nil {
+			return nil, errors.New("failed to augment schema: the augmenter returned no schema")
+		}
+		schema = augmented
+	}
+	if override != nil || augment != nil {
+		// An override may be the way around a type jsonschema.For cannot
+		// infer a schema for: the check is skipped for such types.
+		if inferred, err := inferSchema(reflect.TypeFor[T]()); err == nil {
+			if err := checkCompatible(schema, inferred); err != nil {
+				return nil, fmt.Errorf("schema is not compatible with %v: %w", reflect.TypeFor[T](), err)
+			}
+		}
+	}
+	return schema.Resolve(nil)
+}
+
+// checkCompatible checks the type and properties of the schema against the
+// inferred one, down to the properties of the nested structs and the items of
+// the arrays.
+func checkCompatible(schema, inferred *jsonschema.Schema) error {
+	if !compatibleTypes(schemaTypes(schema), schemaTypes(inferred)) {
+		return fmt.Errorf("the schema has type %v, want %v", schemaTypes(schema), schemaTypes(inferred))
+	}
+	return checkProperties(schema, inferred, inferred.Defs, "")
+}
+
+// checkProperties checks the items and properties of the schema against the
+// inferred schema of the value at path, whose references are to defs.
+func checkProperties(schema, inferred *jsonschema.Schema, defs map[string]*jsonschema.Schema, path string) error {
+	if schema.Items != nil && inferred.Items != nil {
+		itemsPath := path + "[]"
+		items := inferredSchema(inferred.Items, defs)
+		if !compatibleTypes(schemaTypes(schema.Items), schemaTypes(items)) {
+			return fmt.Errorf("property %q has type %v, but its field has type %v", itemsPath, schemaTypes(schema.Items), schemaTypes(items))
+		}
+		if err := checkProperties(schema.Items, items, defs, itemsPath); err != nil {
+			return err
+		}
+	}
+	// Only structs have inferred properties; maps accept any property.
+	if inferred.Properties == nil {
+		return nil
+	}
+	propertyPath := func(name string) string {
+		if path == "" {
+			return name
+		}
+		return path + "." + name
+	}
+	for _, name := range slices.Sorted(maps.Keys(schema.Properties)) {
+		field, ok := inferred.Properties[name]
+		if !ok {
+			return fmt.Errorf("property %q has no matching field", propertyPath(name))
+		}
+		prop := schema.Properties[name]
+		if prop == nil || field == nil {
+			continue
+		}
+		field = inferredSchema(field, defs)
+		if !compatibleTypes(schemaTypes(prop), schemaTypes(field)) {
+			return fmt.Errorf("property %q has type %v, but its field has type %v", propertyPath(name), schemaTypes(prop), schemaTypes(field))
+		}
+		if err := checkProperties(prop, field, defs, propertyPath(name)); err != nil {
+			return err
+		}
+	}
+	for _, name := range schema.Required {
+		if _, ok := inferred.Properties[name]; !ok {
+			return fmt.Errorf("required property %q has no matching field", propertyPath(name))
+		}
+	}
+	return nil
+}
+
+// inferredSchema returns the inferred schema s, or the schema of defs it
+// references, directly or as a pointer. Unknown references are returned as
+// is: they match any schema.
+func inferredSchema(s *jsonschema.Schema, defs map[string]*jsonschema.Schema) *jsonschema.Schema {
+	if ref, ok := nullableRef(s); ok {
+		s = ref
+	}
+	if name, ok := strings.CutPrefix(s.Ref, defsPrefix); ok && defs[name] != nil {
+		return defs[name]
+	}
+	return s
+}
+
+func schemaTypes(s *jsonschema.Schema) []string {
+	if s.Type != "" {
+		return []string{s.Type}
+	}
+	return s.Types
+}
+
+// compatibleTypes reports whether the values of the types are all values of
+// the inferred types. Untyped schemas are compatible with any type, and null
+// with all types, since it decodes to the zero value.
+func compatibleTypes(types, inferred []string) bool {
+	if len(types) == 0 || len(inferred) == 0 {
+		return true
+	}
+	for _, t := range types {
+		if t != "null" && !slices.Contains(inferred, t) && !(t == "integer" && slices.Contains(inferred, "number")) {
+			return false
+		}
+	}
+	return true
+}
+
+// GenaiSchemaFor returns the genai.Schema inferred from T, as accepted by
+// Gemini in genai.GenerateContentConfig.ResponseSchema.
+//
+// The schema is inferred as in [ResolvedSchema], so it carries the field
+// descriptions of the `jsonschema` struct tags, and converted as in
+// [GenaiSchema]: keywords Gemini does not support, such as
+// additionalProperties, are dropped, and the properties of structs are
+// ordered as the fields. The schemas of $defs are inlined, so recursive types
+// are not supported.
+func GenaiSchemaFor[T any]() (*genai.Schema, error) {
+	resolved, err := ResolvedSchema[T](nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	c := &genaiConverter{root: resolved.Schema()}
+	gs := c.convert("#", resolved.Schema(), reflect.TypeFor[T]())
+	if c.err != nil {
+		return nil, c.err
+	}
+	return gs, nil
+}
+
+// GenaiSchema converts the JSON form of a JSON Schema to a genai.Schema, for
+// the Gemini endpoints which do not accept JSON Schema. It fails if s is not
+// a JSON Schema.
+//
+// The keywords genai.Schema has no field for are downgraded:
+//   - the "$ref"s to the schemas of "$defs" are inlined, and the recursive
+//     ones are replaced with an object schema;
+//   - a type list becomes "anyOf", and "null" makes the schema "nullable";
+//   - "oneOf" becomes "anyOf", the nested "anyOf"s are flattened, and an
+//     "anyOf" with a single alternative is merged in the schema, as are the
+//     schemas of "allOf";
+//   - "const" becomes a single value "enum", and "exclusiveMinimum" and
+//     "exclusiveMaximum" become inclusive bounds;
+//   - the other keywords, and the formats Gemini does not support, are
+//     dropped.
+//
+// It returns the warnings about the keywords it dropped or approximated,
+// each prefixed with the JSON pointer of its schema.
+func GenaiSchema(s map[string]any) (*genai.Schema, []string, error) {
+	var schema jsonschema.Schema
+	b, err := json.Marshal(s)
+	if err == nil {
+		err = json.Unmarshal(b, &schema)
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid JSON Schema: %w", err)
+	}
+	c := &genaiConverter{root: &schema}
+	return c.convert("#", &schema, nil), c.warnings, nil
+}
+
+// supportedFormats are the formats Gemini accepts; others are dropped.
+var supportedFormats = []string{"date-time", "enum", "int32", "int64", "float", "double"}
+
+// ignoredKeywords are the annotations dropped without a warning.
+var ignoredKeywords = []string{
+	"$schema", "$id", "$anchor", "$comment", "$vocabulary", "$dynamicAnchor",
+	"$defs", "definitions", "deprecated", "readOnly", "writeOnly",
+	"contentEncoding", "contentMediaType",
+}
+
+// convertedKeywords are the keywords converted to the fields of genai.Schema.
+var convertedKeywords = []string{
+	"$ref", "title", "description", "default", "examples", "enum", "const",
+	"minimum", "maximum", "exclusiveMinimum", "exclusiveMaximum",
+	"minLength", "maxLength", "pattern", "format", "items", "minItems",
+	"maxItems", "minProperties", "maxProperties", "required", "properties",
+	"additionalProperties", "allOf", "anyOf", "oneOf",
+}
+
+// genaiConverter converts JSON schemas to genai.Schemas, which have no
+// references, inlining the schemas of $defs.
+type genaiConverter struct {
+	root *jsonschema.Schema
+	// refs are the references being inlined, to detect the recursive ones.
+	refs []string
+	// warnings are the keywords dropped or approximated, and the schemas
+	// replaced, prefixed with the JSON pointers of their schemas.
+	warnings []string
+	// err is the first schema which could not be converted, and was replaced.
+	err error
+}
+
+func (c *genaiConverter) warn(path, format string, args ...any) {
+	c.warnings = append(c.warnings, path+": "+fmt.Sprintf(format, args...))
+}
+
+// fail records a warning about a schema which could not be converted, which
+// is an error for the schemas inferred from Go types.
+func (c *genaiConverter) fail(path, format string, args ...any) {
+	c.warn(path, format, args...)
+	if c.err == nil {
+		c.err = errors.New(c.warnings[len(c.warnings)-1])
+	}
+}
+
+// convert converts the JSON schema at path to a genai.Schema. t is the Go
+// type the schema was inferred from, used for property ordering; it may be
+// nil.
+func (c *genaiConverter) convert(path string, s *jsonschema.Schema, t reflect.Type) *genai.Schema {
+	if s == nil {
+		return nil
+	}
+	if s.Ref != "" {
+		return c.convertRef(path, s, t)
+	}
+	for t != nil && t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	c.warnDropped(path, s)
+
+	gs := &genai.Schema{
+		Title:         s.Title,
+		Description:   s.Description,
+		Pattern:       s.Pattern,
+		Minimum:       s.Minimum,
+		Maximum:       s.Maximum,
+		MinLength:     toInt64(s.MinLength),
+		MaxLength:     toInt64(s.MaxLength),
+		MinItems:      toInt64(s.MinItems),
+		MaxItems:      toInt64(s.MaxItems),
+		MinProperties: toInt64(s.MinProperties),
+		MaxProperties: toInt64(s.MaxProperties),
+		Required:      s.Required,
+	}
+	switch {
+	case slices.Contains(supportedFormats, s.Format):
+		gs.Format = s.Format
+	case s.Format != "":
+		c.warn(path, "format %q is not supported, dropped", s.Format)
+	}
+	c.convertType(s, gs)
+
+	switch {
+	case s.Enum != nil:
+		c.convertEnum(path, s.Enum, gs)
+	case s.Const != nil:
+		c.convertEnum(path, []any{*s.Const}, gs)
+	}
+	if len(s.Default) > 0 {
+		if err := json.Unmarshal(s.Default, &gs.Default); err != nil {
+			c.fail(path, "invalid default: %v", err)
+		}
+	}
+	if len(s.Examples) > 0 {
+		gs.Example = s.Examples[0]
+	}
+	if s.ExclusiveMinimum != nil {
+		c.warn(path, "exclusiveMinimum approximated with an inclusive bound")
+		if gs.Minimum == nil {
+			gs.Minimum = s.ExclusiveMinimum
+		}
+	}
+	if s.ExclusiveMaximum != nil {
+		c.warn(path, "exclusiveMaximum approximated with an inclusive bound")
+		if gs.Maximum == nil {
+			gs.Maximum = s.ExclusiveMaximum
+		}
+	}
+	// additionalProperties: false is the default of Gemini.
+	if s.AdditionalProperties != nil && !reflect.DeepEqual(s.AdditionalProperties, &jsonschema.Schema{Not: &jsonschema.Schema{}}) {
+		c.warn(path, "additionalProperties is not supported, dropped")
+	}
+
+	var elem reflect.Type
+	if t != nil && (t.Kind() == reflect.Slice || t.Kind() == reflect.Array) {
+		elem = t.Elem()
+	}
+	gs.Items = c.convert(path+"/items", s.Items, elem)
+
+	if len(s.Properties) > 0 {
+		fields := structFields(t)
+		gs.Properties = make(map[string]*genai.Schema, len(s.Properties))
+		for name, prop := range s.Properties {
+			gs.Properties[name] = c.convert(path+"/properties/"+escapePointer(name), prop, fields[name])
+		}
+		if t != nil && t.Kind() == reflect.Struct {
+			gs.PropertyOrdering = propertyOrdering(t, s.Properties)
+		}
+	}
+	c.convertExtra(path, s.Extra, gs)
+
+	if len(s.OneOf) > 0 {
+		c.warn(path, "oneOf approximated with anyOf")
+	}
+	c.convertAnyOf(path, s, t, gs)
+	for i, sub := range s.AllOf {
+		mergeSchema(gs, c.convert(path+"/allOf/"+strconv.Itoa(i), sub, t))
+	}
+	return gs
+}
+
+// convertRef inlines the schema the reference of s points to. The other
+// keywords of s are applied on top of it.
+func (c *genaiConverter) convertRef(path string, s *jsonschema.Schema, t reflect.Type) *genai.Schema {
+	if slices.Contains(c.refs, s.Ref) {
+		c.fail(path, "recursive $ref %q is not supported, replaced with an object schema", s.Ref)
+		return &genai.Schema{Type: genai.TypeObject, Description: s.Description}
+	}
+	target := c.resolve(s.Ref)
+	if target == nil {
+		c.fail(path, "$ref %q cannot be resolved, replaced with an empty schema", s.Ref)
+		return &genai.Schema{}
+	}
+	c.refs = append(c.refs, s.Ref)
+	gs := c.convert(path, target, t)
+	c.refs = c.refs[:len(c.refs)-1]
+
+	siblings := *s
+	siblings.Ref = ""
+	if !reflect.ValueOf(siblings).IsZero() {
+		overrides := c.convert(path, &siblings, t)
+		mergeSchema(overrides, gs)
+		gs = overrides
+	}
+	return gs
+}
+
+// resolve returns the schema of $defs or definitions a reference points to,
+// or nil.
+func (c *genaiConverter) resolve(ref string) *jsonschema.Schema {
+	if name, ok := strings.CutPrefix(ref, defsPrefix); ok {
+		return c.root.Defs[unescapePointer(name)]
+	}
+	if name, ok := strings.CutPrefix(ref, "#/definitions/"); ok {
+		return c.root.Definitions[unescapePointer(name)]
+	}
+	return nil
+}
+
+// convertType sets the type of the schema. A list of types becomes the
+// alternatives of anyOf, and "null" makes the schema nullable.
+func (c *genaiConverter) convertType(s *jsonschema.Schema, gs *genai.Schema) {
+	types := s.Types
+	if s.Type != "" {
+		types = []string{s.Type}
+	}
+	var nonNull []string
+	for _, t := range types {
+		if t == "null" {
+			gs.Nullable = genai.Ptr(true)
+		} else {
+			nonNull = append(nonNull, t)
+		}
+	}
+	switch len(nonNull) {
+	case 0:
+	case 1:
+		gs.Type = genai.Type(strings.ToUpper(nonNull[0]))
+	default:
+		for _, t := range nonNull {
+			gs.AnyOf = append(gs.AnyOf, &genai.Schema{Type: genai.Type(strings.ToUpper(t))})
+		}
+	}
+}
+
+// convertEnum sets the enum of the schema, which genai.Schema only supports
+// for strings. A null value makes the schema nullable.
+func (c *genaiConverter) convertEnum(path string, values []any, gs *genai.Schema) {
+	var enum []string
+	for _, v := range values {
+		switch v := v.(type) {
+		case nil:
+			gs.Nullable = genai.Ptr(true)
+		case string:
+			enum = append(enum, v)
+		default:
+			c.fail(path, "non-string enum value %v is not supported, dropped", v)
+			return
+		}
+	}
+	gs.Enum = enum
+	if gs.Type == "" && len(enum) > 0 {
+		gs.Type = genai.TypeString
+	}
+}
+
+// convertExtra converts the keywords of genai.Schema which are not JSON
+// Schema keywords, and warns about the others.
+func (c *genaiConverter) convertExtra(path string, extra map[string]any, gs *genai.Schema) {
+	for _, key := range slices.Sorted(maps.Keys(extra)) {
+		switch v := extra[key]; key {
+		case "nullable":
+			if b, ok := v.(bool); ok && b {
+				gs.Nullable = genai.Ptr(true)
+			}
+		case "example":
+			gs.Example = v
+		case "propertyOrdering":
+			list, _ := v.([]any)
+			for _, name := range list {
+				if name, ok := name.(string); ok {
+					gs.PropertyOrdering = append(gs.PropertyOrdering, name)
+				}
+			}
+		default:
+			c.warn(path, "%s is not supported, dropped", key)
+		}
+	}
+}
+
+// convertAnyOf sets the alternatives of anyOf and oneOf: the nested ones are
+// flattened, a null one makes the schema nullable, and a single one is
+// merged in the schema.
+func (c *genaiConverter) convertAnyOf(path string, s *jsonschema.Schema, t reflect.Type, gs *genai.Schema) {
+	var alternatives []*genai.Schema
+	var flatten func(*genai.Schema)
+	flatten = func(alt *genai.Schema) {
+		switch {
+		case len(alt.AnyOf) > 0 && reflect.DeepEqual(*alt, genai.Schema{AnyOf: alt.AnyOf}):
+			for _, nested := range alt.AnyOf {
+				flatten(nested)
+			}
+		case reflect.DeepEqual(*alt, genai.Schema{Nullable: genai.Ptr(true)}):
+			gs.Nullable = genai.Ptr(true)
+		default:
+			alternatives = append(alternatives, alt)
+		}
+	}
+	for _, key := range []string{"anyOf", "oneOf"} {
+		subs := s.AnyOf
+		if key == "oneOf" {
+			subs = s.OneOf
+		}
+		for i, sub := range subs {
+			flatten(c.convert(path+"/"+key+"/"+strconv.Itoa(i), sub, t))
+		}
+	}
+	if len(alternatives) == 1 {
+		mergeSchema(gs, alternatives[0])
+		return
+	}
+	gs.AnyOf = append(gs.AnyOf, alternatives...)
+}
+
+// warnDropped warns about the keywords of s which are neither converted nor
+// ignored.
+func (c *genaiConverter) warnDropped(path string, s *jsonschema.Schema) {
+	v := reflect.ValueOf(s).Elem()
+	for i := range v.NumField() {
+		key, _, _ := strings.Cut(v.Type().Field(i).Tag.Get("json"), ",")
+		if key == "-" || v.Field(i).IsZero() || slices.Contains(convertedKeywords, key) || slices.Contains(ignoredKeywords, key) {
+			continue
+		}
+		c.warn(path, "%s is not supported, dropped", key)
+	}
+}
+
+// mergeSchema sets the fields of dst which are not set from src. The
+// properties and the required properties are merged.
+func mergeSchema(dst, src *genai.Schema) {
+	for name, prop := range src.Properties {
+		if dst.Properties == nil {
+			dst.Properties = make(map[string]*genai.Schema)
+		}
+		if _, ok := dst.Properties[name]; !ok {
+			dst.Properties[name] = prop
+		}
+	}
+	for _, name := range src.Required {
+		if !slices.Contains(dst.Required, name) {
+			dst.Required = append(dst.Required, name)
+		}
+	}
+	d, s := reflect.ValueOf(dst).Elem(), reflect.ValueOf(src).Elem()
+	for i := range d.NumField() {
+		if d.Field(i).IsZero() {
+			d.Field(i).Set(s.Field(i))
+		}
+	}
+}
+
+func escapePointer(s string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(s, "~", "~0"), "/", "~1")
+}
+
+func unescapePointer(s string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(s, "~1", "/"), "~0", "~")
+}
+
+// structFields returns the types of the JSON fields of a struct type.
+func structFields(t reflect.Type) map[string]reflect.Type {
+	fields := make(map[string]reflect.Type)
+	if t == nil || t.Kind() != reflect.Struct {
+		return fields
+	}
+	for _, f := range reflect.VisibleFields(t) {
+		if name, ok := jsonFieldName(f); ok {
+			fields[name] = f.Type
+		}
+	}
+	return fields
+}
+
+// propertyOrdering returns the properties in the order of the fields of the
+// struct type t, followed by the other properties sorted by name.
+func propertyOrdering(t reflect.Type, properties map[string]*jsonschema.Schema) []string {
+	var order []string
+	if t != nil && t.Kind() == reflect.Struct {
+		for _, f := range reflect.VisibleFields(t) {
+			if name, ok := jsonFieldName(f); ok && properties[name] != nil && !slices.Contains(order, name) {
+				order = append(order, name)
+			}
+		}
+	}
+	var rest []string
+	for name := range properties {
+		if !slices.Contains(order, name) {
+			rest = append(rest, name)
+		}
+	}
+	slices.Sort(rest)
+	return append(order, rest...)
+}
+
+// jsonFieldName returns the JSON name of a struct field, reporting false if
+// the field is not encoded as a property.
+func jsonFieldName(f reflect.StructField) (string, bool) {
+	if !f.IsExported() {
+		return "", false
+	}
+	tag := f.Tag.Get("json")
+	if tag == "-" {
+		return "", false
+	}
+	name, _, _ := strings.Cut(tag, ",")
+	if f.Anonymous && name == "" {
+		// Embedded struct fields are promoted.
+		return "", false
+	}
+	if name == "" {
+		name = f.Name
+	}
+	return name, true
+}
+
+func toInt64(v *int) *int64 {
+	if v == nil {
+		return nil
+	}
+	return genai.Ptr(int64(*v))
+}
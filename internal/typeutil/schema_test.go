@@ -0,0 +1,585 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package typeutil
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/google/jsonschema-go/jsonschema"
+	"google.golang.org/genai"
+)
+
+type lineItem struct {
+	Description string  `json:"description" jsonschema:"the description of the item"`
+	Amount      float64 `json:"amount" jsonschema:"the amount in euros"`
+}
+
+type invoice struct {
+	Number   string     `json:"number" jsonschema:"the invoice number"`
+	Customer *string    `json:"customer,omitempty" jsonschema:"the name of the customer, if known"`
+	Items    []lineItem `json:"items" jsonschema:"the line items"`
+	Paid     bool       `json:"paid"`
+	internal string
+}
+
+func TestGenaiSchemaFor(t *testing.T) {
+	got, err := GenaiSchemaFor[invoice]()
+	if err != nil {
+		t.Fatalf("GenaiSchemaFor() error = %v", err)
+	}
+	want := &genai.Schema{
+		Type: genai.TypeObject,
+		Properties: map[string]*genai.Schema{
+			"number":   {Type: genai.TypeString, Description: "the invoice number"},
+			"customer": {Type: genai.TypeString, Description: "the name of the customer, if known", Nullable: genai.Ptr(true)},
+			"items": {
+				Type:        genai.TypeArray,
+				Description: "the line items",
+				Items: &genai.Schema{
+					Type: genai.TypeObject,
+					Properties: map[string]*genai.Schema{
+						"description": {Type: genai.TypeString, Description: "the description of the item"},
+						"amount":      {Type: genai.TypeNumber, Description: "the amount in euros"},
+					},
+					PropertyOrdering: []string{"description", "amount"},
+					Required:         []string{"description", "amount"},
+				},
+			},
+			"paid": {Type: genai.TypeBoolean},
+		},
+		PropertyOrdering: []string{"number", "customer", "items", "paid"},
+		Required:         []string{"number", "items", "paid"},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("GenaiSchemaFor() mismatch (-want +got):\n%s", diff)
+	}
+
+	// Unsupported keywords, such as additionalProperties, must not be sent.
+	b, err := json.Marshal(got)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var m map[string]any
+	if err := json.Unmarshal(b, &m); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := m["additionalProperties"]; ok {
+		t.Errorf("GenaiSchemaFor() = %s, want no additionalProperties", b)
+	}
+}
+
+func TestResolvedSchema_Compatibility(t *testing.T) {
+	tests := []struct {
+		name     string
+		override *jsonschema.Schema
+		wantErr  string
+	}{
+		{
+			name: "compatible",
+			override: &jsonschema.Schema{
+				Type: "object",
+				Properties: map[string]*jsonschema.Schema{
+					"number": {Type: "string", Description: "the invoice number"},
+					"items":  {Type: "array"},
+				},
+				Required: []string{"number"},
+			},
+		},
+		{
+			name: "nullable",
+			override: &jsonschema.Schema{
+				Type:       "object",
+				Properties: map[string]*jsonschema.Schema{"items": {Types: []string{"null", "array"}}},
+			},
+		},
+		{
+			name:     "wrong top-level type",
+			override: &jsonschema.Schema{Type: "string"},
+			wantErr:  `schema is not compatible with typeutil.invoice: the schema has type [string], want [object]`,
+		},
+		{
+			name: "unknown property",
+			override: &jsonschema.Schema{
+				Type:       "object",
+				Properties: map[string]*jsonschema.Schema{"total": {Type: "number"}},
+			},
+			wantErr: `property "total" has no matching field`,
+		},
+		{
+			name: "type mismatch",
+			override: &jsonschema.Schema{
+				Type:       "object",
+				Properties: map[string]*jsonschema.Schema{"paid": {Type: "string"}},
+			},
+			wantErr: `property "paid" has type [string], but its field has type [boolean]`,
+		},
+		{
+			name: "nested type mismatch",
+			override: &jsonschema.Schema{
+				Type: "object",
+				Properties: map[string]*jsonschema.Schema{"items": {
+					Type:  "array",
+					Items: &jsonschema.Schema{Type: "object", Properties: map[string]*jsonschema.Schema{"amount": {Type: "string"}}},
+				}},
+			},
+			wantErr: `property "items[].amount" has type [string], but its field has type [number]`,
+		},
+		{
+			name: "unknown nested property",
+			override: &jsonschema.Schema{
+				Type: "object",
+				Properties: map[string]*jsonschema.Schema{"items": {
+					Type:  "array",
+					Items: &jsonschema.Schema{Type: "object", Required: []string{"price"}},
+				}},
+			},
+			wantErr: `required property "items[].price" has no matching field`,
+		},
+		{
+			name: "items type mismatch",
+			override: &jsonschema.Schema{
+				Type:       "object",
+				Properties: map[string]*jsonschema.Schema{"items": {Type: "array", Items: &jsonschema.Schema{Type: "string"}}},
+			},
+			wantErr: `property "items[]" has type [string], but its field has type [object]`,
+		},
+		{
+			name:     "unknown required property",
+			override: &jsonschema.Schema{Type: "object", Required: []string{"total"}},
+			wantErr:  `required property "total" has no matching field`,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := ResolvedSchema[invoice](tc.override, nil)
+			if tc.wantErr == "" {
+				if err != nil {
+					t.Fatalf("ResolvedSchema() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+				t.Fatalf("ResolvedSchema() error = %v, want %q", err, tc.wantErr)
+			}
+		})
+	}
+
+	// The fields of the types of $defs are checked too.
+	type node struct {
+		Name     string  `json:"name"`
+		Children []*node `json:"children,omitempty"`
+	}
+	override := &jsonschema.Schema{
+		Type: "object",
+		Properties: map[string]*jsonschema.Schema{"children": {
+			Type:  "array",
+			Items: &jsonschema.Schema{Type: "object", Properties: map[string]*jsonschema.Schema{"name": {Type: "integer"}}},
+		}},
+	}
+	if _, err := ResolvedSchema[node](override, nil); err == nil || !strings.Contains(err.Error(), `property "children[].name"`) {
+		t.Errorf("ResolvedSchema() of a recursive type error = %v, want the nested name mismatch", err)
+	}
+
+	// Integers are numbers.
+	if _, err := ResolvedSchema[lineItem](&jsonschema.Schema{Type: "object", Properties: map[string]*jsonschema.Schema{"amount": {Type: "integer"}}}, nil); err != nil {
+		t.Errorf("ResolvedSchema() with an integer for a number error = %v", err)
+	}
+	// Maps accept any property, and the augmented schemas are checked too.
+	if _, err := ResolvedSchema[map[string]any](&jsonschema.Schema{Type: "object", Properties: map[string]*jsonschema.Schema{"any": {Type: "string"}}}, nil); err != nil {
+		t.Errorf("ResolvedSchema[map]() error = %v", err)
+	}
+	_, err := ResolvedSchema[invoice](nil, func(s *jsonschema.Schema) (*jsonschema.Schema, error) {
+		s.Properties["paid"].Type = "integer"
+		return s, nil
+	})
+	if err == nil {
+		t.Error("ResolvedSchema() with an incompatible augmented schema succeeded, want error")
+	}
+}
+
+type weatherQuery struct {
+	City  string     `json:"city" jsonschema:"description=The city name, as written locally,enum=NYC|LA|SF"`
+	Days  int        `json:"days,omitempty" jsonschema:"description=Number of days,enum=1|3|7,required=true"`
+	Units []string   `json:"units" jsonschema:"enum=metric|imperial,required=false"`
+	Note  string     `json:"note,omitempty" jsonschema:"a free-form note"`
+	Place *place     `json:"place,omitempty"`
+	Since *time.Time `json:"since,omitempty"`
+	Tags  []string   `json:"tags,omitempty" jsonschema:"nullable=true"`
+}
+
+type place struct {
+	Country string `json:"country" jsonschema:"description=ISO country code,required=false"`
+}
+
+func TestResolvedSchema_Tags(t *testing.T) {
+	resolved, err := ResolvedSchema[weatherQuery](nil, nil)
+	if err != nil {
+		t.Fatalf("ResolvedSchema() error = %v", err)
+	}
+	falseSchema := &jsonschema.Schema{Not: &jsonschema.Schema{}}
+	want := &jsonschema.Schema{
+		Type: "object",
+		Properties: map[string]*jsonschema.Schema{
+			"city":  {Type: "string", Description: "The city name, as written locally", Enum: []any{"NYC", "LA", "SF"}},
+			"days":  {Type: "integer", Description: "Number of days", Enum: []any{int64(1), int64(3), int64(7)}},
+			"units": {Type: "array", Items: &jsonschema.Schema{Type: "string", Enum: []any{"metric", "imperial"}}},
+			"note":  {Type: "string", Description: "a free-form note"},
+			"place": {
+				Types:                []string{"null", "object"},
+				Properties:           map[string]*jsonschema.Schema{"country": {Type: "string", Description: "ISO country code"}},
+				AdditionalProperties: falseSchema,
+			},
+			"since": {Types: []string{"null", "string"}},
+			"tags":  {Types: []string{"null", "array"}, Items: &jsonschema.Schema{Type: "string"}},
+		},
+		Required:             []string{"city", "days"},
+		AdditionalProperties: falseSchema,
+	}
+	if diff := cmp.Diff(want, resolved.Schema(), cmpopts.IgnoreUnexported(jsonschema.Schema{})); diff != "" {
+		t.Errorf("ResolvedSchema() mismatch (-want +got):\n%s", diff)
+	}
+
+	if err := resolved.Validate(map[string]any{"city": "Paris", "days": 1}); err == nil {
+		t.Error("Validate() of a value outside the enum succeeded, want error")
+	}
+
+	type badTag struct {
+		Size string `json:"size" jsonschema:"max=3"`
+	}
+	if _, err := ResolvedSchema[badTag](nil, nil); err == nil || !strings.Contains(err.Error(), `unknown setting "max"`) {
+		t.Errorf("ResolvedSchema() with an unknown setting error = %v, want an unknown setting error", err)
+	}
+}
+
+type bookingQuery struct {
+	Nights int      `json:"nights" jsonschema:"description=Number of nights,minimum=1,maximum=30,default=1"`
+	Email  string   `json:"email" jsonschema:"format=email,maxLength=254"`
+	Code   string   `json:"code,omitempty" jsonschema:"pattern=^[A-Z]{3}(,[A-Z]{3})?$,minLength=3"`
+	Guests []string `json:"guests" jsonschema:"minItems=1,maxItems=4,minLength=1"`
+}
+
+func TestResolvedSchema_Constraints(t *testing.T) {
+	resolved, err := ResolvedSchema[bookingQuery](nil, nil)
+	if err != nil {
+		t.Fatalf("ResolvedSchema() error = %v", err)
+	}
+	want := &jsonschema.Schema{
+		Type: "object",
+		Properties: map[string]*jsonschema.Schema{
+			"nights": {Type: "integer", Description: "Number of nights", Minimum: jsonschema.Ptr(1.0), Maximum: jsonschema.Ptr(30.0), Default: json.RawMessage("1")},
+			"email":  {Type: "string", Format: "email", MaxLength: jsonschema.Ptr(254)},
+			"code":   {Type: "string", Pattern: "^[A-Z]{3}(,[A-Z]{3})?$", MinLength: jsonschema.Ptr(3)},
+			"guests": {Type: "array", MinItems: jsonschema.Ptr(1), MaxItems: jsonschema.Ptr(4), Items: &jsonschema.Schema{Type: "string", MinLength: jsonschema.Ptr(1)}},
+		},
+		Required:             []string{"nights", "email", "guests"},
+		AdditionalProperties: &jsonschema.Schema{Not: &jsonschema.Schema{}},
+	}
+	if diff := cmp.Diff(want, resolved.Schema(), cmpopts.IgnoreUnexported(jsonschema.Schema{})); diff != "" {
+		t.Errorf("ResolvedSchema() mismatch (-want +got):\n%s", diff)
+	}
+	if err := resolved.Validate(map[string]any{"nights": 31, "email": "a@b.c", "guests": []any{"Ann"}}); err == nil {
+		t.Error("Validate() of a value above the maximum succeeded, want error")
+	}
+
+	for _, tc := range []struct {
+		name    string
+		resolve func() error
+		wantErr string
+	}{
+		{
+			name: "minimum of a string",
+			resolve: func() error {
+				_, err := ResolvedSchema[struct {
+					S string `json:"s" jsonschema:"minimum=1"`
+				}](nil, nil)
+				return err
+			},
+			wantErr: "minimum setting on a non-numeric field",
+		},
+		{
+			name: "invalid pattern",
+			resolve: func() error {
+				_, err := ResolvedSchema[struct {
+					S string `json:"s" jsonschema:"pattern=("`
+				}](nil, nil)
+				return err
+			},
+			wantErr: "invalid pattern setting",
+		},
+		{
+			name: "negative length",
+			resolve: func() error {
+				_, err := ResolvedSchema[struct {
+					S []int `json:"s" jsonschema:"maxItems=-1"`
+				}](nil, nil)
+				return err
+			},
+			wantErr: `invalid maxItems setting "-1"`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if err := tc.resolve(); err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+				t.Errorf("ResolvedSchema() error = %v, want %q", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+type treeNode struct {
+	Value    string      `json:"value"`
+	Children []*treeNode `json:"children,omitempty"`
+	Parent   *treeNode   `json:"parent,omitempty"`
+}
+
+type address struct {
+	Street string `json:"street"`
+	City   string `json:"city" jsonschema:"description=The city name"`
+}
+
+type shipment struct {
+	From address  `json:"from"`
+	To   *address `json:"to,omitempty" jsonschema:"the destination"`
+}
+
+func TestResolvedSchema_Defs(t *testing.T) {
+	falseSchema := &jsonschema.Schema{Not: &jsonschema.Schema{}}
+	opts := cmpopts.IgnoreUnexported(jsonschema.Schema{})
+
+	t.Run("recursive", func(t *testing.T) {
+		resolved, err := ResolvedSchema[treeNode](nil, nil)
+		if err != nil {
+			t.Fatalf("ResolvedSchema() error = %v", err)
+		}
+		node := func() *jsonschema.Schema {
+			return &jsonschema.Schema{
+				Type: "object",
+				Properties: map[string]*jsonschema.Schema{
+					"value": {Type: "string"},
+					"children": {
+						Type:  "array",
+						Items: &jsonschema.Schema{AnyOf: []*jsonschema.Schema{{Type: "null"}, {Ref: "#/$defs/treeNode"}}},
+					},
+					"parent": {AnyOf: []*jsonschema.Schema{{Type: "null"}, {Ref: "#/$defs/treeNode"}}},
+				},
+				Required:             []string{"value"},
+				AdditionalProperties: falseSchema,
+			}
+		}
+		want := node()
+		want.Defs = map[string]*jsonschema.Schema{"treeNode": node()}
+		if diff := cmp.Diff(want, resolved.Schema(), opts); diff != "" {
+			t.Errorf("ResolvedSchema() mismatch (-want +got):\n%s", diff)
+		}
+
+		valid := map[string]any{"value": "root", "children": []any{
+			map[string]any{"value": "leaf", "children": []any{map[string]any{"value": "deep"}}},
+		}}
+		if err := resolved.Validate(valid); err != nil {
+			t.Errorf("Validate() of a tree error = %v", err)
+		}
+		invalid := map[string]any{"value": "root", "children": []any{map[string]any{"value": 1}}}
+		if err := resolved.Validate(invalid); err == nil {
+			t.Error("Validate() of a tree with an invalid child succeeded, want error")
+		}
+
+		if _, err := GenaiSchemaFor[treeNode](); err == nil || !strings.Contains(err.Error(), "recursive") {
+			t.Errorf("GenaiSchemaFor() of a recursive type error = %v, want a recursive schema error", err)
+		}
+	})
+
+	t.Run("shared", func(t *testing.T) {
+		resolved, err := ResolvedSchema[shipment](nil, nil)
+		if err != nil {
+			t.Fatalf("ResolvedSchema() error = %v", err)
+		}
+		want := &jsonschema.Schema{
+			Type: "object",
+			Properties: map[string]*jsonschema.Schema{
+				"from": {Ref: "#/$defs/address"},
+				"to": {
+					Description: "the destination",
+					AnyOf:       []*jsonschema.Schema{{Type: "null"}, {Ref: "#/$defs/address"}},
+				},
+			},
+			Required:             []string{"from"},
+			AdditionalProperties: falseSchema,
+			Defs: map[string]*jsonschema.Schema{
+				"address": {
+					Type: "object",
+					Properties: map[string]*jsonschema.Schema{
+						"street": {Type: "string"},
+						"city":   {Type: "string", Description: "The city name"},
+					},
+					Required:             []string{"street", "city"},
+					AdditionalProperties: falseSchema,
+				},
+			},
+		}
+		if diff := cmp.Diff(want, resolved.Schema(), opts); diff != "" {
+			t.Errorf("ResolvedSchema() mismatch (-want +got):\n%s", diff)
+		}
+		if err := resolved.Validate(map[string]any{"from": map[string]any{"street": "Main St"}}); err == nil {
+			t.Error("Validate() of an address without city succeeded, want error")
+		}
+
+		// Gemini schemas have no references: the addresses are inlined.
+		got, err := GenaiSchemaFor[shipment]()
+		if err != nil {
+			t.Fatalf("GenaiSchemaFor() error = %v", err)
+		}
+		addr := func() *genai.Schema {
+			return &genai.Schema{
+				Type: genai.TypeObject,
+				Properties: map[string]*genai.Schema{
+					"street": {Type: genai.TypeString},
+					"city":   {Type: genai.TypeString, Description: "The city name"},
+				},
+				PropertyOrdering: []string{"street", "city"},
+				Required:         []string{"street", "city"},
+			}
+		}
+		to := addr()
+		to.Description = "the destination"
+		to.Nullable = genai.Ptr(true)
+		wantGenai := &genai.Schema{
+			Type:             genai.TypeObject,
+			Properties:       map[string]*genai.Schema{"from": addr(), "to": to},
+			PropertyOrdering: []string{"from", "to"},
+			Required:         []string{"from"},
+		}
+		if diff := cmp.Diff(wantGenai, got); diff != "" {
+			t.Errorf("GenaiSchemaFor() mismatch (-want +got):\n%s", diff)
+		}
+	})
+}
+
+func TestGenaiSchema(t *testing.T) {
+	tests := []struct {
+		name         string
+		schema       string
+		want         *genai.Schema
+		wantWarnings []string
+	}{
+		{
+			name: "object",
+			schema: `{"$schema": "https://json-schema.org/draft/2020-12/schema", "type": "object",
+				"properties": {
+					"city": {"type": "string", "description": "The city.", "minLength": 1},
+					"days": {"type": "integer", "minimum": 1, "maximum": 7, "default": 3},
+					"tags": {"type": "array", "items": {"type": "string"}, "maxItems": 5}
+				},
+				"required": ["city"], "additionalProperties": false}`,
+			want: &genai.Schema{
+				Type: genai.TypeObject,
+				Properties: map[string]*genai.Schema{
+					"city": {Type: genai.TypeString, Description: "The city.", MinLength: genai.Ptr[int64](1)},
+					"days": {Type: genai.TypeInteger, Minimum: genai.Ptr(1.0), Maximum: genai.Ptr(7.0), Default: 3.0},
+					"tags": {Type: genai.TypeArray, Items: &genai.Schema{Type: genai.TypeString}, MaxItems: genai.Ptr[int64](5)},
+				},
+				Required: []string{"city"},
+			},
+		},
+		{
+			name:   "nullable type",
+			schema: `{"type": ["string", "null"], "enum": ["a", "b", null]}`,
+			want:   &genai.Schema{Type: genai.TypeString, Nullable: genai.Ptr(true), Enum: []string{"a", "b"}},
+		},
+		{
+			name:   "type list",
+			schema: `{"type": ["string", "integer"]}`,
+			want:   &genai.Schema{AnyOf: []*genai.Schema{{Type: genai.TypeString}, {Type: genai.TypeInteger}}},
+		},
+		{
+			name:   "anyOf flattened",
+			schema: `{"anyOf": [{"anyOf": [{"type": "string"}, {"type": "integer"}]}, {"type": "null"}]}`,
+			want: &genai.Schema{
+				Nullable: genai.Ptr(true),
+				AnyOf:    []*genai.Schema{{Type: genai.TypeString}, {Type: genai.TypeInteger}},
+			},
+		},
+		{
+			name:   "single alternative merged",
+			schema: `{"description": "A count.", "anyOf": [{"type": "integer"}, {"type": "null"}]}`,
+			want:   &genai.Schema{Type: genai.TypeInteger, Description: "A count.", Nullable: genai.Ptr(true)},
+		},
+		{
+			name:         "oneOf",
+			schema:       `{"oneOf": [{"type": "string"}, {"type": "number"}]}`,
+			want:         &genai.Schema{AnyOf: []*genai.Schema{{Type: genai.TypeString}, {Type: genai.TypeNumber}}},
+			wantWarnings: []string{"#: oneOf approximated with anyOf"},
+		},
+		{
+			name: "allOf merged",
+			schema: `{"allOf": [
+				{"type": "object", "properties": {"a": {"type": "string"}}, "required": ["a"]},
+				{"properties": {"b": {"type": "boolean"}}, "required": ["b"]}]}`,
+			want: &genai.Schema{
+				Type:       genai.TypeObject,
+				Properties: map[string]*genai.Schema{"a": {Type: genai.TypeString}, "b": {Type: genai.TypeBoolean}},
+				Required:   []string{"a", "b"},
+			},
+		},
+		{
+			name: "refs",
+			schema: `{"type": "object", "properties": {
+					"home": {"$ref": "#/$defs/address", "description": "Home."},
+					"node": {"$ref": "#/$defs/node"}},
+				"$defs": {
+					"address": {"type": "object", "properties": {"street": {"type": "string"}}},
+					"node": {"type": "object", "properties": {"next": {"$ref": "#/$defs/node"}}}}}`,
+			want: &genai.Schema{
+				Type: genai.TypeObject,
+				Properties: map[string]*genai.Schema{
+					"home": {Type: genai.TypeObject, Description: "Home.", Properties: map[string]*genai.Schema{"street": {Type: genai.TypeString}}},
+					"node": {Type: genai.TypeObject, Properties: map[string]*genai.Schema{"next": {Type: genai.TypeObject}}},
+				},
+			},
+			wantWarnings: []string{`#/properties/node/properties/next: recursive $ref "#/$defs/node" is not supported, replaced with an object schema`},
+		},
+		{
+			name:   "downgraded keywords",
+			schema: `{"type": "number", "const": "x", "exclusiveMinimum": 0, "not": {"type": "string"}, "enum": [1, 2]}`,
+			want:   &genai.Schema{Type: genai.TypeNumber, Minimum: genai.Ptr(0.0)},
+			wantWarnings: []string{
+				"#: not is not supported, dropped",
+				"#: non-string enum value 1 is not supported, dropped",
+				"#: exclusiveMinimum approximated with an inclusive bound",
+			},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var s map[string]any
+			if err := json.Unmarshal([]byte(tc.schema), &s); err != nil {
+				t.Fatal(err)
+			}
+			got, warnings, err := GenaiSchema(s)
+			if err != nil {
+				t.Fatalf("GenaiSchema() error = %v", err)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("GenaiSchema() mismatch (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.wantWarnings, warnings); diff != "" {
+				t.Errorf("GenaiSchema() warnings mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,426 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package typeutil
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/google/jsonschema-go/jsonschema"
+)
+
+// keyedTagRegexp matches the jsonschema tags made of keyed settings, which
+// jsonschema.For rejects.
+var keyedTagRegexp = regexp.MustCompile(`^[^ \t\n]*=`)
+
+// tagKeys are the recognized keys of the keyed jsonschema tags.
+var tagKeys = []string{
+	"description", "enum", "required", "nullable", "default", "minimum", "maximum",
+	"minLength", "maxLength", "pattern", "format", "minItems", "maxItems",
+}
+
+// inferSchema infers the schema of t as jsonschema.ForType does, and also
+// honors the jsonschema tags made of comma-separated keyed settings, e.g.
+//
+//	City string `json:"city" jsonschema:"description=The city name,enum=NYC|LA|SF"`
+//
+// The recognized settings are:
+//   - description: the description of the property. It may contain commas,
+//     as long as they are not followed by another setting.
+//   - enum: the allowed values, separated by "|". They are converted to the
+//     type of the field, or of its elements for slices.
+//   - required: true or false, overrides whether the property is required,
+//     which is by default whether the field is not a pointer and its json tag
+//     has no omitempty or omitzero.
+//   - nullable: true or false, whether the property allows null, which is by
+//     default whether the field is a pointer. A null value decodes to the zero
+//     value of a non-pointer field.
+//   - default: the default value, converted as the enum values. The models
+//     read it as a hint: the missing fields still decode to their zero value.
+//   - minimum, maximum: the inclusive bounds of a number, or of the numbers
+//     of a slice.
+//   - minLength, maxLength, pattern, format: the bounds of the length, the
+//     regular expression and the format, e.g. "email", of a string, or of the
+//     strings of a slice.
+//   - minItems, maxItems: the bounds of the length of a slice.
+//
+// A tag without settings is the description, as for jsonschema.For.
+//
+// The schemas of the recursive struct types, and of the struct types of
+// several fields, are defined once in $defs and referenced with $ref, see
+// refTypes. A recursive argument type is also inlined at the top level, as
+// the models expect an object there.
+func inferSchema(t reflect.Type) (*jsonschema.Schema, error) {
+	schemas := make(map[reflect.Type]*jsonschema.Schema)
+	refs := refTypes(t)
+	for rt, name := range refs {
+		schemas[rt] = &jsonschema.Schema{Ref: defsPrefix + name}
+	}
+	if err := keyedTagSchemas(t, schemas, make(map[reflect.Type]bool)); err != nil {
+		return nil, err
+	}
+	var defs map[string]*jsonschema.Schema
+	for rt, name := range refs {
+		def, err := structSchema(rt, schemas)
+		if err != nil {
+			return nil, err
+		}
+		allowNull(def, rt)
+		if defs == nil {
+			defs = make(map[string]*jsonschema.Schema)
+		}
+		defs[name] = def
+	}
+
+	var s *jsonschema.Schema
+	var err error
+	if base := derefType(t); refs[base] != "" {
+		s, err = structSchema(base, schemas)
+	} else {
+		s, err = jsonschema.ForType(t, &jsonschema.ForOptions{TypeSchemas: schemas})
+	}
+	if err != nil {
+		return nil, err
+	}
+	allowNull(s, t)
+	s.Defs = defs
+	return s, nil
+}
+
+// derefType returns the type pointed to by t, following the pointers.
+func derefType(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return t
+}
+
+// allowNull allows null for all the pointers, which jsonschema.ForType does
+// not for the types with predefined schemas, e.g. *time.Time, nor for those
+// of ForOptions.TypeSchemas, e.g. the pointers to the structs with keyed tags
+// or to those defined in $defs.
+func allowNull(s *jsonschema.Schema, t reflect.Type) {
+	if s == nil {
+		return
+	}
+	if t.Kind() == reflect.Pointer {
+		t = derefType(t)
+		switch {
+		case s.Type != "":
+			s.Types = []string{"null", s.Type}
+			s.Type = ""
+		case s.Ref != "":
+			// A reference cannot be combined with a type: the referenced
+			// schema would still reject null.
+			s.AnyOf = []*jsonschema.Schema{{Type: "null"}, {Ref: s.Ref}}
+			s.Ref = ""
+		}
+	}
+	switch t.Kind() {
+	case reflect.Slice, reflect.Array:
+		allowNull(s.Items, t.Elem())
+	case reflect.Map:
+		allowNull(s.AdditionalProperties, t.Elem())
+	case reflect.Struct:
+		for _, field := range reflect.VisibleFields(t) {
+			if field.Anonymous {
+				continue
+			}
+			if name, _, ok := fieldJSONInfo(field); ok {
+				allowNull(s.Properties[name], field.Type)
+			}
+		}
+	}
+}
+
+// keyedTagSchemas adds to schemas the schemas of the struct types reachable
+// from t that have keyed jsonschema tags, or optional pointer fields, which
+// jsonschema.ForType would require.
+func keyedTagSchemas(t reflect.Type, schemas map[reflect.Type]*jsonschema.Schema, seen map[reflect.Type]bool) error {
+	for t.Kind() == reflect.Pointer || t.Kind() == reflect.Slice || t.Kind() == reflect.Array || t.Kind() == reflect.Map {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct || seen[t] {
+		return nil
+	}
+	seen[t] = true
+
+	keyed := false
+	for _, field := range reflect.VisibleFields(t) {
+		if field.Anonymous || !field.IsExported() {
+			continue
+		}
+		if err := keyedTagSchemas(field.Type, schemas, seen); err != nil {
+			return err
+		}
+		keyed = keyed || keyedTagRegexp.MatchString(field.Tag.Get("jsonschema")) || optionalPointer(field)
+	}
+	// The schemas of the types of $defs are references.
+	if !keyed || schemas[t] != nil {
+		return nil
+	}
+	s, err := structSchema(t, schemas)
+	if err != nil {
+		return err
+	}
+	schemas[t] = s
+	return nil
+}
+
+// structSchema returns the schema of the struct type t, built from the
+// schemas of its fields, with their jsonschema tags.
+func structSchema(t reflect.Type, schemas map[reflect.Type]*jsonschema.Schema) (*jsonschema.Schema, error) {
+	s := &jsonschema.Schema{
+		Type: "object",
+		// No additional properties are allowed, as for jsonschema.For.
+		AdditionalProperties: &jsonschema.Schema{Not: &jsonschema.Schema{}},
+	}
+	for _, field := range reflect.VisibleFields(t) {
+		if field.Anonymous {
+			continue
+		}
+		name, required, ok := fieldJSONInfo(field)
+		if !ok {
+			continue
+		}
+		fs, err := jsonschema.ForType(field.Type, &jsonschema.ForOptions{TypeSchemas: schemas})
+		if err != nil {
+			return nil, err
+		}
+		if tag, ok := field.Tag.Lookup("jsonschema"); ok {
+			if required, err = applyTag(fs, tag, required); err != nil {
+				return nil, fmt.Errorf("invalid jsonschema tag on struct field %s.%s: %w", t, field.Name, err)
+			}
+		}
+		if s.Properties == nil {
+			s.Properties = make(map[string]*jsonschema.Schema)
+		}
+		s.Properties[name] = fs
+		if required {
+			s.Required = append(s.Required, name)
+		}
+	}
+	return s, nil
+}
+
+// optionalPointer reports whether f is a pointer field without omitempty or
+// omitzero, which is optional although jsonschema.For requires it.
+func optionalPointer(f reflect.StructField) bool {
+	_, required, ok := jsonTagInfo(f)
+	return ok && required && f.Type.Kind() == reflect.Pointer
+}
+
+// fieldJSONInfo returns the JSON name of the field and whether it is
+// required by default: the fields are required unless their json tag has
+// omitempty or omitzero, as for jsonschema.For, or they are pointers.
+func fieldJSONInfo(f reflect.StructField) (name string, required, ok bool) {
+	name, required, ok = jsonTagInfo(f)
+	return name, required && f.Type.Kind() != reflect.Pointer, ok
+}
+
+// jsonTagInfo returns the JSON name of the field and whether its json tag
+// has neither omitempty nor omitzero.
+func jsonTagInfo(f reflect.StructField) (name string, required, ok bool) {
+	if !f.IsExported() {
+		return "", false, false
+	}
+	tag, hasTag := f.Tag.Lookup("json")
+	name, rest, found := strings.Cut(tag, ",")
+	// "-" means omit, but "-," means the name is "-".
+	if hasTag && name == "-" && !found {
+		return "", false, false
+	}
+	if name == "" {
+		name = f.Name
+	}
+	settings := strings.Split(rest, ",")
+	return name, !slices.Contains(settings, "omitempty") && !slices.Contains(settings, "omitzero"), true
+}
+
+// applyTag applies the jsonschema tag to the schema of the field and returns
+// whether the field is required.
+func applyTag(s *jsonschema.Schema, tag string, required bool) (bool, error) {
+	if tag == "" {
+		return required, fmt.Errorf("empty tag")
+	}
+	if !keyedTagRegexp.MatchString(tag) {
+		s.Description = tag
+		return required, nil
+	}
+	for key, value := range tagSettings(tag) {
+		switch key {
+		case "description":
+			s.Description = value
+		case "enum":
+			target := elemSchema(s)
+			for _, v := range strings.Split(value, "|") {
+				ev, err := enumValue(target, v)
+				if err != nil {
+					return required, err
+				}
+				target.Enum = append(target.Enum, ev)
+			}
+		case "required":
+			b, err := strconv.ParseBool(value)
+			if err != nil {
+				return required, fmt.Errorf("invalid required setting %q", value)
+			}
+			required = b
+		case "nullable":
+			b, err := strconv.ParseBool(value)
+			if err != nil {
+				return required, fmt.Errorf("invalid nullable setting %q", value)
+			}
+			if b && s.Type != "" {
+				s.Types = []string{"null", s.Type}
+				s.Type = ""
+			}
+		case "default":
+			if schemaTypeIs(s, "array") || schemaTypeIs(s, "object") {
+				return required, fmt.Errorf("default setting on a %v field is not supported", schemaTypes(s))
+			}
+			v, err := enumValue(s, value)
+			if err != nil {
+				return required, err
+			}
+			if s.Default, err = json.Marshal(v); err != nil {
+				return required, fmt.Errorf("invalid default setting %q: %w", value, err)
+			}
+		case "minimum", "maximum":
+			target := elemSchema(s)
+			if !schemaTypeIs(target, "number") && !schemaTypeIs(target, "integer") {
+				return required, fmt.Errorf("%s setting on a non-numeric field", key)
+			}
+			f, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				return required, fmt.Errorf("invalid %s setting %q", key, value)
+			}
+			if key == "minimum" {
+				target.Minimum = &f
+			} else {
+				target.Maximum = &f
+			}
+		case "minLength", "maxLength", "pattern", "format":
+			target := elemSchema(s)
+			if !schemaTypeIs(target, "string") {
+				return required, fmt.Errorf("%s setting on a non-string field", key)
+			}
+			switch key {
+			case "pattern":
+				if _, err := regexp.Compile(value); err != nil {
+					return required, fmt.Errorf("invalid pattern setting %q: %w", value, err)
+				}
+				target.Pattern = value
+			case "format":
+				target.Format = value
+			default:
+				n, err := lengthSetting(key, value)
+				if err != nil {
+					return required, err
+				}
+				if key == "minLength" {
+					target.MinLength = &n
+				} else {
+					target.MaxLength = &n
+				}
+			}
+		case "minItems", "maxItems":
+			if !schemaTypeIs(s, "array") {
+				return required, fmt.Errorf("%s setting on a non-slice field", key)
+			}
+			n, err := lengthSetting(key, value)
+			if err != nil {
+				return required, err
+			}
+			if key == "minItems" {
+				s.MinItems = &n
+			} else {
+				s.MaxItems = &n
+			}
+		default:
+			return required, fmt.Errorf("unknown setting %q, want one of %s", key, strings.Join(tagKeys, ", "))
+		}
+	}
+	return required, nil
+}
+
+// elemSchema returns the schema of the elements of the array schema s, or s
+// if it is not an array schema.
+func elemSchema(s *jsonschema.Schema) *jsonschema.Schema {
+	if schemaTypeIs(s, "array") && s.Items != nil {
+		return s.Items
+	}
+	return s
+}
+
+// lengthSetting parses the value of a length setting, a non-negative integer.
+func lengthSetting(key, value string) (int, error) {
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("invalid %s setting %q", key, value)
+	}
+	return n, nil
+}
+
+// tagSettings splits the keyed tag into its settings. A comma not followed
+// by a recognized key is part of the value.
+func tagSettings(tag string) map[string]string {
+	settings := make(map[string]string)
+	var key string
+	for i, part := range strings.Split(tag, ",") {
+		k, v, found := strings.Cut(part, "=")
+		if i == 0 || (found && slices.Contains(tagKeys, k)) {
+			key = k
+			settings[key] = v
+			continue
+		}
+		settings[key] += "," + part
+	}
+	return settings
+}
+
+// enumValue converts the enum value to the type of the schema.
+func enumValue(s *jsonschema.Schema, v string) (any, error) {
+	switch {
+	case schemaTypeIs(s, "integer"):
+		n, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid integer enum value %q", v)
+		}
+		return n, nil
+	case schemaTypeIs(s, "number"):
+		f, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid number enum value %q", v)
+		}
+		return f, nil
+	case schemaTypeIs(s, "boolean"):
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid boolean enum value %q", v)
+		}
+		return b, nil
+	}
+	return v, nil
+}
+
+func schemaTypeIs(s *jsonschema.Schema, t string) bool {
+	return s.Type == t || slices.Contains(s.Types, t)
+}
@@ -0,0 +1,63 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"google.golang.org/adk/model"
+)
+
+// NewAPIError returns the error of a model API response with an error status.
+// The message is read from the {"error": {"message": ...}} body of the OpenAI
+// and Anthropic APIs, or is the body as is.
+func NewAPIError(resp *http.Response) *model.APIError {
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+	apiErr := &model.APIError{
+		StatusCode: resp.StatusCode,
+		Status:     resp.Status,
+		Message:    string(bytes.TrimSpace(body)),
+		RetryAfter: ParseRetryAfter(resp.Header.Get("Retry-After"), time.Now()),
+	}
+	var errBody struct {
+		Error *struct {
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	if json.Unmarshal(body, &errBody) == nil && errBody.Error != nil && errBody.Error.Message != "" {
+		apiErr.Message = errBody.Error.Message
+	}
+	return apiErr
+}
+
+// ParseRetryAfter returns the delay of a Retry-After header value, in seconds
+// or an HTTP date, or 0 if it is empty or invalid.
+func ParseRetryAfter(value string, now time.Time) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if secs, err := strconv.Atoi(value); err == nil {
+		return max(time.Duration(secs)*time.Second, 0)
+	}
+	if t, err := http.ParseTime(value); err == nil {
+		return max(t.Sub(now), 0)
+	}
+	return 0
+}
@@ -0,0 +1,128 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"slices"
+	"strings"
+)
+
+// unsupportedOpenAIKeywords are the schema keywords removed from the
+// OpenAI declarations: annotations and keywords of the recent JSON Schema
+// drafts that the OpenAI endpoints reject, and the Gemini specific ones.
+var unsupportedOpenAIKeywords = []string{
+	"$schema", "$id", "$anchor", "$comment", "$vocabulary", "$dynamicAnchor", "$dynamicRef",
+	"contentEncoding", "contentMediaType", "contentSchema",
+	"deprecated", "readOnly", "writeOnly", "examples", "example",
+	"not", "if", "then", "else", "dependentRequired", "dependentSchemas",
+	"unevaluatedProperties", "unevaluatedItems", "propertyNames",
+	"propertyOrdering",
+}
+
+// OpenAISchema translates the JSON form of a schema, e.g. of a Gemini schema,
+// to the JSON Schema dialect of OpenAI: the types are lower-cased,
+// "nullable" is merged in the type, "const" becomes a single value "enum",
+// "oneOf" becomes "anyOf", and the keywords OpenAI rejects are removed.
+func OpenAISchema(s map[string]any) map[string]any {
+	out := make(map[string]any, len(s))
+	for key, val := range s {
+		if slices.Contains(unsupportedOpenAIKeywords, key) {
+			continue
+		}
+		switch key {
+		case "type":
+			out[key] = lowerTypes(val)
+		case "nullable":
+			// Merged in the type below.
+		case "const":
+			if _, ok := s["enum"]; !ok {
+				out["enum"] = []any{val}
+			}
+		case "oneOf", "anyOf", "allOf", "prefixItems":
+			if key == "oneOf" {
+				key = "anyOf"
+			}
+			out[key] = mapSchemas(val)
+		case "properties", "$defs", "definitions", "patternProperties":
+			if props, ok := val.(map[string]any); ok {
+				translated := make(map[string]any, len(props))
+				for name, prop := range props {
+					translated[name] = mapSchema(prop)
+				}
+				val = translated
+			}
+			out[key] = val
+		case "items", "additionalProperties", "contains":
+			out[key] = mapSchema(val)
+		default:
+			out[key] = val
+		}
+	}
+	if nullable, _ := s["nullable"].(bool); nullable {
+		switch t := out["type"].(type) {
+		case string:
+			out["type"] = []any{t, "null"}
+		case []any:
+			if !slices.Contains(t, any("null")) {
+				out["type"] = append(t, "null")
+			}
+		}
+		if enum, ok := out["enum"].([]any); ok && !slices.Contains(enum, nil) {
+			out["enum"] = append(enum, nil)
+		}
+	}
+	return out
+}
+
+// mapSchema translates the value if it is a schema, and keeps the boolean
+// schemas.
+func mapSchema(v any) any {
+	if s, ok := v.(map[string]any); ok {
+		return OpenAISchema(s)
+	}
+	return v
+}
+
+func mapSchemas(v any) any {
+	list, ok := v.([]any)
+	if !ok {
+		return v
+	}
+	out := make([]any, len(list))
+	for i, s := range list {
+		out[i] = mapSchema(s)
+	}
+	return out
+}
+
+// lowerTypes returns the types in lower case, as the Gemini schemas use upper
+// case.
+func lowerTypes(v any) any {
+	switch t := v.(type) {
+	case string:
+		return strings.ToLower(t)
+	case []any:
+		out := make([]any, len(t))
+		for i, e := range t {
+			if s, ok := e.(string); ok {
+				out[i] = strings.ToLower(s)
+			} else {
+				out[i] = e
+			}
+		}
+		return out
+	}
+	return v
+}
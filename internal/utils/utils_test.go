@@ -14,8 +14,31 @@
 
 package utils_test
 
-import "testing"
+import (
+	"testing"
+	"time"
+
+	"google.golang.org/adk/internal/utils"
+)
 
 func TestNothing(t *testing.T) {
 	// To make it buildable.
 }
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	for _, tc := range []struct {
+		value string
+		want  time.Duration
+	}{
+		{value: "", want: 0},
+		{value: "30", want: 30 * time.Second},
+		{value: "Wed, 01 Jan 2025 00:01:00 GMT", want: time.Minute},
+		{value: "Tue, 31 Dec 2024 00:00:00 GMT", want: 0},
+		{value: "soon", want: 0},
+	} {
+		if got := utils.ParseRetryAfter(tc.value, now); got != tc.want {
+			t.Errorf("ParseRetryAfter(%q) = %v, want %v", tc.value, got, tc.want)
+		}
+	}
+}
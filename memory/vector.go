@@ -0,0 +1,231 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package memory
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+	"sync"
+
+	"google.golang.org/adk/session"
+)
+
+const defaultTopK = 5
+
+// Embedder computes the embeddings of texts, e.g. the one of
+// dedupetool.NewGenaiEmbedder.
+type Embedder interface {
+	// Embed returns the embedding of each text, in order.
+	Embed(ctx context.Context, texts []string) ([][]float32, error)
+}
+
+// VectorRecord is an entry of a VectorStore.
+type VectorRecord struct {
+	Entry
+	// Embedding is the embedding of the text of the entry.
+	Embedding []float32
+}
+
+// VectorMatch is a record found by VectorStore.Search.
+type VectorMatch struct {
+	Entry
+	// Score is the cosine similarity of the record with the query, between
+	// -1 and 1.
+	Score float64
+}
+
+// VectorStore stores the embedded entries of the sessions, scoped to their
+// app and user. It must be safe for concurrent use.
+type VectorStore interface {
+	// Replace stores the records of a session, in place of the ones stored
+	// before for the session.
+	Replace(ctx context.Context, appName, userID, sessionID string, records []VectorRecord) error
+	// Search returns at most topK records of the sessions of the user, the
+	// most similar to the embedding first.
+	Search(ctx context.Context, appName, userID string, embedding []float32, topK int) ([]VectorMatch, error)
+}
+
+// VectorServiceConfig is the configuration of the vector memory service.
+type VectorServiceConfig struct {
+	// Embedder computing the embeddings of the entries and the queries.
+	// Required.
+	Embedder Embedder
+	// Store holding the embedded entries. Defaults to NewInMemoryVectorStore().
+	Store VectorStore
+	// TopK is the maximum number of memories returned by a search. Defaults
+	// to 5.
+	TopK int
+	// MinScore is the cosine similarity with the query below which the
+	// memories are not returned. Zero, the default, returns the TopK most
+	// similar memories.
+	MinScore float64
+}
+
+// VectorService returns a memory service searching the memories by semantic
+// similarity: the text of each event of the sessions is embedded by the
+// embedder, and the memories returned are those most similar to the query.
+func VectorService(cfg VectorServiceConfig) (Service, error) {
+	if cfg.Embedder == nil {
+		return nil, errors.New("embedder is required")
+	}
+	if cfg.Store == nil {
+		cfg.Store = NewInMemoryVectorStore()
+	}
+	if cfg.TopK <= 0 {
+		cfg.TopK = defaultTopK
+	}
+	return &vectorService{cfg: cfg}, nil
+}
+
+type vectorService struct {
+	cfg VectorServiceConfig
+}
+
+func (s *vectorService) AddSession(ctx context.Context, curSession session.Session) error {
+	var entries []Entry
+	var texts []string
+	for event := range curSession.Events().All() {
+		if event.Content == nil {
+			continue
+		}
+		var text strings.Builder
+		for _, part := range event.Content.Parts {
+			if part.Text == "" || part.Thought {
+				continue
+			}
+			if text.Len() > 0 {
+				text.WriteString("\n")
+			}
+			text.WriteString(part.Text)
+		}
+		if text.Len() == 0 {
+			continue
+		}
+		entries = append(entries, Entry{Content: event.Content, Author: event.Author, Timestamp: event.Timestamp})
+		texts = append(texts, text.String())
+	}
+
+	var records []VectorRecord
+	if len(texts) > 0 {
+		embeddings, err := s.cfg.Embedder.Embed(ctx, texts)
+		if err != nil {
+			return fmt.Errorf("failed to embed the events of session %s: %w", curSession.ID(), err)
+		}
+		if len(embeddings) != len(texts) {
+			return fmt.Errorf("got %d embeddings for %d events", len(embeddings), len(texts))
+		}
+		for i, entry := range entries {
+			records = append(records, VectorRecord{Entry: entry, Embedding: embeddings[i]})
+		}
+	}
+	return s.cfg.Store.Replace(ctx, curSession.AppName(), curSession.UserID(), curSession.ID(), records)
+}
+
+func (s *vectorService) Search(ctx context.Context, req *SearchRequest) (*SearchResponse, error) {
+	if strings.TrimSpace(req.Query) == "" {
+		return &SearchResponse{}, nil
+	}
+	embeddings, err := s.cfg.Embedder.Embed(ctx, []string{req.Query})
+	if err != nil {
+		return nil, fmt.Errorf("failed to embed the query: %w", err)
+	}
+	if len(embeddings) != 1 {
+		return nil, fmt.Errorf("got %d embeddings for the query", len(embeddings))
+	}
+	matches, err := s.cfg.Store.Search(ctx, req.AppName, req.UserID, embeddings[0], s.cfg.TopK)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search the memories: %w", err)
+	}
+
+	res := &SearchResponse{}
+	for _, m := range matches {
+		if m.Score < s.cfg.MinScore {
+			continue
+		}
+		res.Memories = append(res.Memories, m.Entry)
+	}
+	return res, nil
+}
+
+// NewInMemoryVectorStore returns a vector store holding the records in
+// memory, searched exhaustively.
+func NewInMemoryVectorStore() VectorStore {
+	return &inMemoryVectorStore{store: make(map[key]map[sessionID][]VectorRecord)}
+}
+
+type inMemoryVectorStore struct {
+	mu    sync.RWMutex
+	store map[key]map[sessionID][]VectorRecord
+}
+
+func (s *inMemoryVectorStore) Replace(_ context.Context, appName, userID, id string, records []VectorRecord) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	k := key{appName: appName, userID: userID}
+	v, ok := s.store[k]
+	if !ok {
+		v = make(map[sessionID][]VectorRecord)
+		s.store[k] = v
+	}
+	v[sessionID(id)] = slices.Clone(records)
+	return nil
+}
+
+func (s *inMemoryVectorStore) Search(_ context.Context, appName, userID string, embedding []float32, topK int) ([]VectorMatch, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var matches []VectorMatch
+	for _, records := range s.store[key{appName: appName, userID: userID}] {
+		for _, r := range records {
+			matches = append(matches, VectorMatch{Entry: r.Entry, Score: cosineSimilarity(embedding, r.Embedding)})
+		}
+	}
+	// The most similar first, the most recent first among equal scores.
+	slices.SortStableFunc(matches, func(a, b VectorMatch) int {
+		if c := cmp.Compare(b.Score, a.Score); c != 0 {
+			return c
+		}
+		return b.Timestamp.Compare(a.Timestamp)
+	})
+	if len(matches) > topK {
+		matches = matches[:topK]
+	}
+	return matches, nil
+}
+
+// cosineSimilarity returns the cosine similarity of two vectors, or 0 if
+// their lengths differ or one of them is zero.
+func cosineSimilarity(a, b []float32) float64 {
+	if len(a) != len(b) {
+		return 0
+	}
+	var dot, na, nb float64
+	for i := range a {
+		dot += float64(a[i]) * float64(b[i])
+		na += float64(a[i]) * float64(a[i])
+		nb += float64(b[i]) * float64(b[i])
+	}
+	if na == 0 || nb == 0 {
+		return 0
+	}
+	return dot / (math.Sqrt(na) * math.Sqrt(nb))
+}
@@ -0,0 +1,130 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package memory_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/memory"
+	"google.golang.org/adk/model"
+	"google.golang.org/adk/session"
+)
+
+// topicEmbedder embeds a text as the counts of the words of each topic.
+type topicEmbedder struct{}
+
+var topics = [][]string{
+	{"tea", "coffee", "drink", "drinks"},
+	{"cat", "dog", "pet", "pets"},
+	{"paris", "rome", "trip", "travel"},
+}
+
+func (topicEmbedder) Embed(_ context.Context, texts []string) ([][]float32, error) {
+	var embeddings [][]float32
+	for _, text := range texts {
+		embedding := make([]float32, len(topics))
+		for _, word := range strings.Fields(strings.ToLower(text)) {
+			for i, topic := range topics {
+				for _, w := range topic {
+					if strings.Trim(word, ".?!") == w {
+						embedding[i]++
+					}
+				}
+			}
+		}
+		embeddings = append(embeddings, embedding)
+	}
+	return embeddings, nil
+}
+
+func TestVectorService(t *testing.T) {
+	ctx := t.Context()
+	s, err := memory.VectorService(memory.VectorServiceConfig{Embedder: topicEmbedder{}, TopK: 2, MinScore: 0.5})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	event := func(author, text string, ts time.Time) *session.Event {
+		return &session.Event{
+			Author:      author,
+			LLMResponse: model.LLMResponse{Content: genai.NewContentFromText(text, genai.RoleUser)},
+			Timestamp:   ts,
+		}
+	}
+	t1 := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	t2 := t1.Add(time.Hour)
+	for _, sess := range []session.Session{
+		makeSession(t, "app", "user", "s1", []*session.Event{
+			event("user", "I drink tea every morning.", t1),
+			event("user", "My dog is a good pet.", t1),
+			{LLMResponse: model.LLMResponse{Content: &genai.Content{Parts: []*genai.Part{{Text: "thinking about coffee", Thought: true}}}}},
+		}),
+		makeSession(t, "app", "user", "s2", []*session.Event{
+			event("user", "Coffee is my favorite drink.", t2),
+			event("user", "A trip to Rome.", t2),
+		}),
+		makeSession(t, "app", "other", "s3", []*session.Event{
+			event("user", "tea tea tea", t2),
+		}),
+	} {
+		if err := s.AddSession(ctx, sess); err != nil {
+			t.Fatalf("AddSession() error = %v", err)
+		}
+	}
+
+	got, err := s.Search(ctx, &memory.SearchRequest{AppName: "app", UserID: "user", Query: "What drinks?"})
+	if err != nil {
+		t.Fatalf("Search() error = %v", err)
+	}
+	want := &memory.SearchResponse{Memories: []memory.Entry{
+		{Content: genai.NewContentFromText("Coffee is my favorite drink.", genai.RoleUser), Author: "user", Timestamp: t2},
+		{Content: genai.NewContentFromText("I drink tea every morning.", genai.RoleUser), Author: "user", Timestamp: t1},
+	}}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Search() mismatch (-want +got):\n%s", diff)
+	}
+
+	// Adding a session again replaces its memories.
+	if err := s.AddSession(ctx, makeSession(t, "app", "user", "s2", []*session.Event{event("user", "Paris travel.", t2)})); err != nil {
+		t.Fatalf("AddSession() error = %v", err)
+	}
+	got, err = s.Search(ctx, &memory.SearchRequest{AppName: "app", UserID: "user", Query: "drinks"})
+	if err != nil {
+		t.Fatalf("Search() error = %v", err)
+	}
+	if len(got.Memories) != 1 || got.Memories[0].Timestamp != t1 {
+		t.Errorf("Search() after replacing the session = %v, want the memory of session s1", got.Memories)
+	}
+
+	got, err = s.Search(ctx, &memory.SearchRequest{AppName: "app", UserID: "user", Query: "nothing related"})
+	if err != nil {
+		t.Fatalf("Search() error = %v", err)
+	}
+	if len(got.Memories) != 0 {
+		t.Errorf("Search() with an unrelated query = %v, want no memories below MinScore", got.Memories)
+	}
+}
+
+func TestVectorService_NoEmbedder(t *testing.T) {
+	if _, err := memory.VectorService(memory.VectorServiceConfig{}); err == nil {
+		t.Error("VectorService() without an embedder succeeded, want error")
+	}
+}
@@ -0,0 +1,163 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package vertexai provides a [memory.Service] backed by the Memory Bank of
+// the Vertex AI Agent Engine, see
+// https://cloud.google.com/vertex-ai/generative-ai/docs/agent-engine/memory-bank/overview.
+//
+// The same agent runs locally with [memory.InMemoryService], and in
+// production with the memories generated and searched by Agent Engine.
+package vertexai
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/internal/agentengine"
+	"google.golang.org/adk/memory"
+	"google.golang.org/adk/session"
+)
+
+// Config configures the Agent Engine of the memory service.
+type Config struct {
+	// ProjectID is the Google Cloud project of the Agent Engine.
+	ProjectID string
+	// Location is the region of the Agent Engine, e.g. "us-central1".
+	Location string
+	// AgentEngineID is the ID or the resource name of the Agent Engine.
+	// Optional: if empty, the app name of each request is used.
+	AgentEngineID string
+	// HTTPClient sends the requests to the API.
+	// Optional: if nil, the requests are authorized with the application
+	// default credentials.
+	HTTPClient *http.Client
+	// Endpoint is the base URL of the API.
+	// Optional: if empty, the regional endpoint of the location is used.
+	Endpoint string
+}
+
+// memoryBankService is a memory.Service over the Agent Engine Memory Bank API.
+type memoryBankService struct {
+	client *agentengine.Client
+}
+
+// NewMemoryService returns a [memory.Service] generating memories from the
+// sessions and searching them with the Memory Bank of an Agent Engine. The
+// memories are scoped to the app and the user of the sessions.
+//
+// The memories are generated asynchronously: AddSession returns once the
+// generation is started, and the memories of a session are found by Search
+// once it is done.
+func NewMemoryService(ctx context.Context, cfg Config) (memory.Service, error) {
+	client, err := agentengine.NewClient(ctx, cfg.ProjectID, cfg.Location, cfg.AgentEngineID, cfg.HTTPClient, cfg.Endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("error creating Vertex AI memory service: %w", err)
+	}
+	return &memoryBankService{client: client}, nil
+}
+
+type scope struct {
+	AppName string `json:"app_name"`
+	UserID  string `json:"user_id"`
+}
+
+type generateRequest struct {
+	DirectContentsSource *directContentsSource `json:"directContentsSource"`
+	Scope                scope                 `json:"scope"`
+}
+
+type directContentsSource struct {
+	Events []directContentsEvent `json:"events"`
+}
+
+type directContentsEvent struct {
+	Content *genai.Content `json:"content"`
+}
+
+func (s *memoryBankService) AddSession(ctx context.Context, curSession session.Session) error {
+	engine, err := s.client.EngineName(curSession.AppName())
+	if err != nil {
+		return err
+	}
+
+	var events []directContentsEvent
+	for event := range curSession.Events().All() {
+		if event.Content == nil || len(event.Content.Parts) == 0 {
+			continue
+		}
+		events = append(events, directContentsEvent{Content: event.Content})
+	}
+	if len(events) == 0 {
+		return nil
+	}
+
+	req := &generateRequest{
+		DirectContentsSource: &directContentsSource{Events: events},
+		Scope:                scope{AppName: curSession.AppName(), UserID: curSession.UserID()},
+	}
+	if err := s.client.Do(ctx, http.MethodPost, engine+"/memories:generate", nil, req, nil); err != nil {
+		return fmt.Errorf("failed to generate memories of session %s: %w", curSession.ID(), err)
+	}
+	return nil
+}
+
+type retrieveRequest struct {
+	Scope                  scope                   `json:"scope"`
+	SimilaritySearchParams *similaritySearchParams `json:"similaritySearchParams,omitempty"`
+}
+
+type similaritySearchParams struct {
+	SearchQuery string `json:"searchQuery"`
+}
+
+type retrieveResponse struct {
+	RetrievedMemories []struct {
+		Memory struct {
+			Fact       string    `json:"fact"`
+			UpdateTime time.Time `json:"updateTime"`
+		} `json:"memory"`
+	} `json:"retrievedMemories"`
+}
+
+func (s *memoryBankService) Search(ctx context.Context, req *memory.SearchRequest) (*memory.SearchResponse, error) {
+	engine, err := s.client.EngineName(req.AppName)
+	if err != nil {
+		return nil, err
+	}
+
+	body := &retrieveRequest{Scope: scope{AppName: req.AppName, UserID: req.UserID}}
+	if req.Query != "" {
+		body.SimilaritySearchParams = &similaritySearchParams{SearchQuery: req.Query}
+	}
+	var resp retrieveResponse
+	if err := s.client.Do(ctx, http.MethodPost, engine+"/memories:retrieve", nil, body, &resp); err != nil {
+		return nil, fmt.Errorf("failed to retrieve memories: %w", err)
+	}
+
+	res := &memory.SearchResponse{}
+	for _, m := range resp.RetrievedMemories {
+		res.Memories = append(res.Memories, memory.Entry{
+			Content:   genai.NewContentFromText(m.Memory.Fact, genai.RoleUser),
+			Author:    genai.RoleUser,
+			Timestamp: m.Memory.UpdateTime,
+		})
+	}
+	return res, nil
+}
+
+var _ memory.Service = (*memoryBankService)(nil)
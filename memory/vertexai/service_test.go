@@ -0,0 +1,111 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vertexai
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/memory"
+	"google.golang.org/adk/model"
+	"google.golang.org/adk/session"
+)
+
+func TestMemoryBankService(t *testing.T) {
+	ctx := t.Context()
+	updateTime := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	requests := make(map[string]any)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body any
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("invalid request body: %v", err)
+		}
+		requests[r.URL.Path] = body
+		switch r.URL.Path {
+		case "/v1beta1/projects/p/locations/l/reasoningEngines/123/memories:generate":
+			w.Write([]byte(`{"name": "projects/p/locations/l/reasoningEngines/123/operations/1"}`))
+		case "/v1beta1/projects/p/locations/l/reasoningEngines/123/memories:retrieve":
+			json.NewEncoder(w).Encode(map[string]any{"retrievedMemories": []any{
+				map[string]any{"memory": map[string]any{"fact": "The user likes tea.", "updateTime": updateTime}},
+			}})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	s, err := NewMemoryService(ctx, Config{
+		ProjectID:  "p",
+		Location:   "l",
+		HTTPClient: server.Client(),
+		Endpoint:   server.URL,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sessions := session.InMemoryService()
+	created, err := sessions.Create(ctx, &session.CreateRequest{AppName: "123", UserID: "user"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, content := range []*genai.Content{genai.NewContentFromText("I like tea.", genai.RoleUser), nil} {
+		event := session.NewEvent("inv")
+		event.LLMResponse = model.LLMResponse{Content: content}
+		if err := sessions.AppendEvent(ctx, created.Session, event); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := s.AddSession(ctx, created.Session); err != nil {
+		t.Fatalf("AddSession() error = %v", err)
+	}
+	wantGenerate := map[string]any{
+		"directContentsSource": map[string]any{"events": []any{
+			map[string]any{"content": map[string]any{"role": "user", "parts": []any{map[string]any{"text": "I like tea."}}}},
+		}},
+		"scope": map[string]any{"app_name": "123", "user_id": "user"},
+	}
+	if diff := cmp.Diff(wantGenerate, requests["/v1beta1/projects/p/locations/l/reasoningEngines/123/memories:generate"]); diff != "" {
+		t.Errorf("AddSession() request mismatch (-want +got):\n%s", diff)
+	}
+
+	got, err := s.Search(ctx, &memory.SearchRequest{AppName: "123", UserID: "user", Query: "drinks"})
+	if err != nil {
+		t.Fatalf("Search() error = %v", err)
+	}
+	wantRetrieve := map[string]any{
+		"scope":                  map[string]any{"app_name": "123", "user_id": "user"},
+		"similaritySearchParams": map[string]any{"searchQuery": "drinks"},
+	}
+	if diff := cmp.Diff(wantRetrieve, requests["/v1beta1/projects/p/locations/l/reasoningEngines/123/memories:retrieve"]); diff != "" {
+		t.Errorf("Search() request mismatch (-want +got):\n%s", diff)
+	}
+	want := &memory.SearchResponse{Memories: []memory.Entry{{
+		Content:   genai.NewContentFromText("The user likes tea.", genai.RoleUser),
+		Author:    genai.RoleUser,
+		Timestamp: updateTime,
+	}}}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Search() mismatch (-want +got):\n%s", diff)
+	}
+}
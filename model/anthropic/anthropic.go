@@ -0,0 +1,629 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package anthropic implements the [model.LLM] interface for the Claude
+// models, served by the Anthropic Messages API.
+//
+// The requests are translated from the Gemini types of the ADK to content
+// blocks: the system instruction becomes the system prompt, the function
+// calls and responses become tool_use and tool_result blocks, the thoughts
+// with a signature become thinking blocks, and the consecutive contents of a
+// role are merged in a message, as the roles of the messages must alternate.
+// The responses are translated back, so that the contents of a session can be
+// sent again. The tools which are not function tools, e.g. the Gemini
+// built-in tools, and the output schemas are not supported.
+package anthropic
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+	"net/http"
+	"os"
+	"runtime"
+	"slices"
+	"strings"
+
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/internal/toolinternal"
+	"google.golang.org/adk/internal/utils"
+	"google.golang.org/adk/internal/version"
+	"google.golang.org/adk/model"
+)
+
+const (
+	// DefaultBaseURL is the base URL of the Anthropic API.
+	DefaultBaseURL = "https://api.anthropic.com"
+	// DefaultMaxTokens is the maximum number of tokens to generate of the
+	// requests without MaxOutputTokens, as the API requires one.
+	DefaultMaxTokens = 4096
+	// apiVersion is the version of the API of the requests.
+	apiVersion = "2023-06-01"
+)
+
+// Config is the config of a Claude model.
+type Config struct {
+	// BaseURL is the base URL of the API. Defaults to DefaultBaseURL.
+	BaseURL string
+	// APIKey is the API key of the requests. Defaults to the
+	// ANTHROPIC_API_KEY environment variable.
+	APIKey string
+	// HTTPClient sends the requests. Defaults to http.DefaultClient.
+	HTTPClient *http.Client
+	// Headers are added to the requests, e.g. anthropic-beta.
+	Headers http.Header
+}
+
+type claudeModel struct {
+	name               string
+	url                string
+	apiKey             string
+	client             *http.Client
+	headers            http.Header
+	versionHeaderValue string
+}
+
+// NewModel returns [model.LLM], backed by the Anthropic Messages API. The
+// modelName specifies which Claude model to target (e.g.,
+// "claude-sonnet-4-5").
+func NewModel(ctx context.Context, modelName string, cfg *Config) (model.LLM, error) {
+	if modelName == "" {
+		return nil, errors.New("model name is required")
+	}
+	if cfg == nil {
+		cfg = &Config{}
+	}
+	baseURL := cfg.BaseURL
+	if baseURL == "" {
+		baseURL = DefaultBaseURL
+	}
+	apiKey := cfg.APIKey
+	if apiKey == "" {
+		apiKey = os.Getenv("ANTHROPIC_API_KEY")
+	}
+	client := cfg.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	return &claudeModel{
+		name:    modelName,
+		url:     strings.TrimSuffix(baseURL, "/") + "/v1/messages",
+		apiKey:  apiKey,
+		client:  client,
+		headers: cfg.Headers,
+		versionHeaderValue: fmt.Sprintf("google-adk/%s gl-go/%s", version.Version,
+			strings.TrimPrefix(runtime.Version(), "go")),
+	}, nil
+}
+
+func (m *claudeModel) Name() string {
+	return m.name
+}
+
+// GenerateContent calls the underlying model.
+func (m *claudeModel) GenerateContent(ctx context.Context, req *model.LLMRequest, stream bool) iter.Seq2[*model.LLMResponse, error] {
+	return func(yield func(*model.LLMResponse, error) bool) {
+		msgReq, err := m.messagesRequest(req, stream)
+		if err != nil {
+			yield(nil, err)
+			return
+		}
+		body, err := m.send(ctx, msgReq)
+		if err != nil {
+			yield(nil, err)
+			return
+		}
+		defer body.Close()
+
+		if !stream {
+			var resp messageResponse
+			if err := json.NewDecoder(body).Decode(&resp); err != nil {
+				yield(nil, fmt.Errorf("failed to decode the model response: %w", err))
+				return
+			}
+			yield(resp.llmResponse())
+			return
+		}
+		for resp, err := range readStream(body) {
+			if !yield(resp, err) {
+				return
+			}
+		}
+	}
+}
+
+// send posts the request and returns the body of the response.
+func (m *claudeModel) send(ctx context.Context, msgReq *messagesRequest) (io.ReadCloser, error) {
+	payload, err := json.Marshal(msgReq)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode the model request: %w", err)
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, m.url, bytes.NewReader(payload))
+	if err != nil {
+		return nil, err
+	}
+	for name, values := range m.headers {
+		for _, v := range values {
+			httpReq.Header.Add(name, v)
+		}
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("User-Agent", m.versionHeaderValue)
+	httpReq.Header.Set("anthropic-version", apiVersion)
+	if m.apiKey != "" {
+		httpReq.Header.Set("x-api-key", m.apiKey)
+	}
+
+	resp, err := m.client.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call model: %w", err)
+	}
+	if resp.StatusCode/100 != 2 {
+		defer resp.Body.Close()
+		return nil, fmt.Errorf("failed to call model: %w", utils.NewAPIError(resp))
+	}
+	return resp.Body, nil
+}
+
+// messagesRequest translates the request to a Messages API request.
+func (m *claudeModel) messagesRequest(req *model.LLMRequest, stream bool) (*messagesRequest, error) {
+	cfg := req.Config
+	if cfg == nil {
+		cfg = &genai.GenerateContentConfig{}
+	}
+	if cfg.ResponseSchema != nil || cfg.ResponseJsonSchema != nil {
+		return nil, errors.New("output schemas are not supported by the Anthropic models")
+	}
+	msgReq := &messagesRequest{
+		Model:         m.name,
+		MaxTokens:     cfg.MaxOutputTokens,
+		System:        textOf(cfg.SystemInstruction),
+		Temperature:   cfg.Temperature,
+		TopP:          cfg.TopP,
+		StopSequences: cfg.StopSequences,
+		Stream:        stream,
+	}
+	if msgReq.MaxTokens == 0 {
+		msgReq.MaxTokens = DefaultMaxTokens
+	}
+	if cfg.TopK != nil {
+		topK := int32(*cfg.TopK)
+		msgReq.TopK = &topK
+	}
+	if cfg.ThinkingConfig != nil && cfg.ThinkingConfig.ThinkingBudget != nil && *cfg.ThinkingConfig.ThinkingBudget > 0 {
+		msgReq.Thinking = &thinking{Type: "enabled", BudgetTokens: *cfg.ThinkingConfig.ThinkingBudget}
+	}
+
+	for _, content := range req.Contents {
+		msg, err := message(content)
+		if err != nil {
+			return nil, err
+		}
+		if len(msg.Content) == 0 {
+			continue
+		}
+		// The roles of the messages must alternate.
+		if n := len(msgReq.Messages); n > 0 && msgReq.Messages[n-1].Role == msg.Role {
+			msgReq.Messages[n-1].Content = append(msgReq.Messages[n-1].Content, msg.Content...)
+			continue
+		}
+		msgReq.Messages = append(msgReq.Messages, msg)
+	}
+
+	// The tools are sorted by name for the requests to be stable.
+	names := make([]string, 0, len(req.Tools))
+	for name := range req.Tools {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	for _, name := range names {
+		t, err := toolOf(name, req.Tools[name])
+		if err != nil {
+			return nil, err
+		}
+		msgReq.Tools = append(msgReq.Tools, t)
+	}
+	return msgReq, nil
+}
+
+// message translates a content to the content blocks of a message.
+func message(content *genai.Content) (messageParam, error) {
+	if content == nil {
+		return messageParam{}, nil
+	}
+	msg := messageParam{Role: "user"}
+	if content.Role == genai.RoleModel {
+		msg.Role = "assistant"
+	}
+	for _, part := range content.Parts {
+		var block contentBlock
+		switch {
+		case part.Thought:
+			// The thoughts can only be sent back with their signature.
+			if len(part.ThoughtSignature) == 0 {
+				continue
+			}
+			block = contentBlock{Type: "thinking", Thinking: part.Text, Signature: string(part.ThoughtSignature)}
+		case part.FunctionCall != nil:
+			input := part.FunctionCall.Args
+			if input == nil {
+				input = map[string]any{}
+			}
+			block = contentBlock{Type: "tool_use", ID: part.FunctionCall.ID, Name: part.FunctionCall.Name, Input: input}
+		case part.FunctionResponse != nil:
+			response, err := json.Marshal(part.FunctionResponse.Response)
+			if err != nil {
+				return messageParam{}, fmt.Errorf("failed to encode the response of function %q: %w", part.FunctionResponse.Name, err)
+			}
+			_, isError := part.FunctionResponse.Response["error"]
+			block = contentBlock{Type: "tool_result", ToolUseID: part.FunctionResponse.ID, Content: string(response), IsError: isError}
+		case part.Text != "":
+			block = contentBlock{Type: "text", Text: part.Text}
+		case part.InlineData != nil:
+			blockType := blockTypeOf(part.InlineData.MIMEType)
+			if blockType == "" {
+				return messageParam{}, fmt.Errorf("unsupported inline data of MIME type %q", part.InlineData.MIMEType)
+			}
+			data := base64.StdEncoding.EncodeToString(part.InlineData.Data)
+			block = contentBlock{Type: blockType, Source: &source{Type: "base64", MediaType: part.InlineData.MIMEType, Data: data}}
+		case part.FileData != nil:
+			blockType := blockTypeOf(part.FileData.MIMEType)
+			if blockType == "" {
+				return messageParam{}, fmt.Errorf("unsupported file data of MIME type %q", part.FileData.MIMEType)
+			}
+			block = contentBlock{Type: blockType, Source: &source{Type: "url", URL: part.FileData.FileURI}}
+		default:
+			continue
+		}
+		msg.Content = append(msg.Content, block)
+	}
+	return msg, nil
+}
+
+// blockTypeOf returns the type of the blocks of the data of the MIME type,
+// or "" if it is not supported.
+func blockTypeOf(mimeType string) string {
+	switch {
+	case strings.HasPrefix(mimeType, "image/"):
+		return "image"
+	case mimeType == "application/pdf":
+		return "document"
+	}
+	return ""
+}
+
+// toolOf translates a function tool of the request.
+func toolOf(name string, t any) (toolParam, error) {
+	funcTool, ok := t.(toolinternal.FunctionTool)
+	if !ok || funcTool.Declaration() == nil {
+		return toolParam{}, fmt.Errorf("tool %q is not a function tool", name)
+	}
+	decl := funcTool.Declaration()
+	var schema any = decl.ParametersJsonSchema
+	if schema == nil && decl.Parameters != nil {
+		schema = decl.Parameters
+	}
+	inputSchema := map[string]any{}
+	if schema != nil {
+		b, err := json.Marshal(schema)
+		if err != nil {
+			return toolParam{}, fmt.Errorf("failed to encode the parameters of tool %q: %w", decl.Name, err)
+		}
+		if err := json.Unmarshal(b, &inputSchema); err != nil {
+			return toolParam{}, fmt.Errorf("parameters of tool %q are not an object schema: %w", decl.Name, err)
+		}
+		// Claude accepts the same JSON Schema dialect as OpenAI.
+		inputSchema = utils.OpenAISchema(inputSchema)
+	}
+	if inputSchema["type"] == nil {
+		inputSchema["type"] = "object"
+	}
+	return toolParam{Name: decl.Name, Description: decl.Description, InputSchema: inputSchema}, nil
+}
+
+func textOf(content *genai.Content) string {
+	if content == nil {
+		return ""
+	}
+	var texts []string
+	for _, part := range content.Parts {
+		if part.Text != "" {
+			texts = append(texts, part.Text)
+		}
+	}
+	return strings.Join(texts, "\n")
+}
+
+// llmResponse translates the response.
+func (r *messageResponse) llmResponse() (*model.LLMResponse, error) {
+	if r.Error != nil {
+		return nil, fmt.Errorf("model error: %s", r.Error.Message)
+	}
+	content, err := modelContent(r.Content)
+	if err != nil {
+		return nil, err
+	}
+	return &model.LLMResponse{
+		Content:       content,
+		FinishReason:  finishReason(r.StopReason),
+		UsageMetadata: r.Usage.usageMetadata(),
+	}, nil
+}
+
+// modelContent translates the content blocks of the model, or returns nil if
+// there are none.
+func modelContent(blocks []contentBlock) (*genai.Content, error) {
+	var parts []*genai.Part
+	for _, block := range blocks {
+		switch block.Type {
+		case "text":
+			if block.Text != "" {
+				parts = append(parts, genai.NewPartFromText(block.Text))
+			}
+		case "thinking":
+			parts = append(parts, &genai.Part{Text: block.Thinking, Thought: true, ThoughtSignature: []byte(block.Signature)})
+		case "tool_use":
+			args, ok := block.Input.(map[string]any)
+			if !ok && block.Input != nil {
+				return nil, fmt.Errorf("input of tool use %q is not an object", block.Name)
+			}
+			parts = append(parts, &genai.Part{FunctionCall: &genai.FunctionCall{ID: block.ID, Name: block.Name, Args: args}})
+		}
+	}
+	if len(parts) == 0 {
+		return nil, nil
+	}
+	return &genai.Content{Role: genai.RoleModel, Parts: parts}, nil
+}
+
+// readStream reads the server-sent events of a streamed message. It yields a
+// partial response for each text and thinking delta, then the aggregated
+// content blocks, whose tool inputs are streamed in JSON fragments, in a last
+// response.
+func readStream(body io.Reader) iter.Seq2[*model.LLMResponse, error] {
+	return func(yield func(*model.LLMResponse, error) bool) {
+		var blocks []contentBlock
+		// inputs are the JSON fragments of the inputs of the tool uses, by
+		// index of block.
+		inputs := map[int]*strings.Builder{}
+		var stopReason string
+		var usage usage
+
+		scanner := bufio.NewScanner(body)
+		scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+		for scanner.Scan() {
+			data, ok := strings.CutPrefix(scanner.Text(), "data:")
+			if !ok {
+				continue
+			}
+			var event streamEvent
+			if err := json.Unmarshal([]byte(strings.TrimSpace(data)), &event); err != nil {
+				yield(nil, fmt.Errorf("failed to decode the model response: %w", err))
+				return
+			}
+			switch event.Type {
+			case "error":
+				yield(nil, fmt.Errorf("model error: %s", event.Error.Message))
+				return
+			case "message_start":
+				usage = event.Message.Usage
+			case "content_block_start":
+				for len(blocks) <= event.Index {
+					blocks = append(blocks, contentBlock{})
+				}
+				blocks[event.Index] = event.ContentBlock
+				if event.ContentBlock.Type == "tool_use" {
+					inputs[event.Index] = &strings.Builder{}
+				}
+			case "content_block_delta":
+				if event.Index >= len(blocks) {
+					yield(nil, fmt.Errorf("delta of unknown content block %d", event.Index))
+					return
+				}
+				block := &blocks[event.Index]
+				var partial *genai.Part
+				switch event.Delta.Type {
+				case "text_delta":
+					block.Text += event.Delta.Text
+					partial = genai.NewPartFromText(event.Delta.Text)
+				case "thinking_delta":
+					block.Thinking += event.Delta.Thinking
+					partial = &genai.Part{Text: event.Delta.Thinking, Thought: true}
+				case "signature_delta":
+					block.Signature += event.Delta.Signature
+				case "input_json_delta":
+					if input, ok := inputs[event.Index]; ok {
+						input.WriteString(event.Delta.PartialJSON)
+					}
+				}
+				if partial != nil && partial.Text != "" {
+					if !yield(&model.LLMResponse{
+						Content: &genai.Content{Role: genai.RoleModel, Parts: []*genai.Part{partial}},
+						Partial: true,
+					}, nil) {
+						return
+					}
+				}
+			case "message_delta":
+				if event.Delta.StopReason != "" {
+					stopReason = event.Delta.StopReason
+				}
+				if event.Usage != nil {
+					usage.OutputTokens = event.Usage.OutputTokens
+				}
+			case "message_stop":
+				// The stream ends.
+			}
+		}
+		if err := scanner.Err(); err != nil {
+			yield(nil, fmt.Errorf("failed to read the model response: %w", err))
+			return
+		}
+
+		for i, input := range inputs {
+			if strings.TrimSpace(input.String()) == "" {
+				continue
+			}
+			var args map[string]any
+			if err := json.Unmarshal([]byte(input.String()), &args); err != nil {
+				yield(nil, fmt.Errorf("invalid input of tool use %q: %w", blocks[i].Name, err))
+				return
+			}
+			blocks[i].Input = args
+		}
+		content, err := modelContent(blocks)
+		if err != nil {
+			yield(nil, err)
+			return
+		}
+		yield(&model.LLMResponse{
+			Content:       content,
+			UsageMetadata: usage.usageMetadata(),
+			FinishReason:  finishReason(stopReason),
+			TurnComplete:  true,
+		}, nil)
+	}
+}
+
+func finishReason(reason string) genai.FinishReason {
+	switch reason {
+	case "":
+		return ""
+	case "end_turn", "stop_sequence", "tool_use", "pause_turn":
+		return genai.FinishReasonStop
+	case "max_tokens":
+		return genai.FinishReasonMaxTokens
+	case "refusal":
+		return genai.FinishReasonSafety
+	}
+	return genai.FinishReasonOther
+}
+
+func (u usage) usageMetadata() *genai.GenerateContentResponseUsageMetadata {
+	if u == (usage{}) {
+		return nil
+	}
+	prompt := u.InputTokens + u.CacheCreationInputTokens + u.CacheReadInputTokens
+	return &genai.GenerateContentResponseUsageMetadata{
+		PromptTokenCount:        prompt,
+		CachedContentTokenCount: u.CacheReadInputTokens,
+		CandidatesTokenCount:    u.OutputTokens,
+		TotalTokenCount:         prompt + u.OutputTokens,
+	}
+}
+
+// The types of the Messages API.
+
+type messagesRequest struct {
+	Model         string         `json:"model"`
+	MaxTokens     int32          `json:"max_tokens"`
+	System        string         `json:"system,omitempty"`
+	Messages      []messageParam `json:"messages"`
+	Tools         []toolParam    `json:"tools,omitempty"`
+	Temperature   *float32       `json:"temperature,omitempty"`
+	TopP          *float32       `json:"top_p,omitempty"`
+	TopK          *int32         `json:"top_k,omitempty"`
+	StopSequences []string       `json:"stop_sequences,omitempty"`
+	Thinking      *thinking      `json:"thinking,omitempty"`
+	Stream        bool           `json:"stream,omitempty"`
+}
+
+type messageParam struct {
+	Role    string         `json:"role"`
+	Content []contentBlock `json:"content"`
+}
+
+// contentBlock is a block of any type: text, image, document, thinking,
+// tool_use or tool_result.
+type contentBlock struct {
+	Type string `json:"type"`
+	// Text is the text of the text blocks.
+	Text string `json:"text,omitempty"`
+	// Source is the data of the image and document blocks.
+	Source *source `json:"source,omitempty"`
+	// Thinking and Signature are the thoughts of the thinking blocks.
+	Thinking  string `json:"thinking,omitempty"`
+	Signature string `json:"signature,omitempty"`
+	// ID, Name and Input are the call of the tool_use blocks.
+	ID    string `json:"id,omitempty"`
+	Name  string `json:"name,omitempty"`
+	Input any    `json:"input,omitempty"`
+	// ToolUseID, Content and IsError are the result of the tool_result
+	// blocks.
+	ToolUseID string `json:"tool_use_id,omitempty"`
+	Content   string `json:"content,omitempty"`
+	IsError   bool   `json:"is_error,omitempty"`
+}
+
+type source struct {
+	Type      string `json:"type"`
+	MediaType string `json:"media_type,omitempty"`
+	Data      string `json:"data,omitempty"`
+	URL       string `json:"url,omitempty"`
+}
+
+type toolParam struct {
+	Name        string         `json:"name"`
+	Description string         `json:"description,omitempty"`
+	InputSchema map[string]any `json:"input_schema"`
+}
+
+type thinking struct {
+	Type         string `json:"type"`
+	BudgetTokens int32  `json:"budget_tokens"`
+}
+
+type messageResponse struct {
+	Content    []contentBlock `json:"content"`
+	StopReason string         `json:"stop_reason"`
+	Usage      usage          `json:"usage"`
+	Error      *apiError      `json:"error"`
+}
+
+type usage struct {
+	InputTokens              int32 `json:"input_tokens"`
+	OutputTokens             int32 `json:"output_tokens"`
+	CacheCreationInputTokens int32 `json:"cache_creation_input_tokens"`
+	CacheReadInputTokens     int32 `json:"cache_read_input_tokens"`
+}
+
+type streamEvent struct {
+	Type         string          `json:"type"`
+	Message      messageResponse `json:"message"`
+	Index        int             `json:"index"`
+	ContentBlock contentBlock    `json:"content_block"`
+	Delta        struct {
+		Type        string `json:"type"`
+		Text        string `json:"text"`
+		Thinking    string `json:"thinking"`
+		Signature   string `json:"signature"`
+		PartialJSON string `json:"partial_json"`
+		StopReason  string `json:"stop_reason"`
+	} `json:"delta"`
+	Usage *usage   `json:"usage"`
+	Error apiError `json:"error"`
+}
+
+type apiError struct {
+	Type    string `json:"type"`
+	Message string `json:"message"`
+}
@@ -0,0 +1,349 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package anthropic_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/model"
+	"google.golang.org/adk/model/anthropic"
+	"google.golang.org/adk/tool"
+	"google.golang.org/adk/tool/functiontool"
+	"google.golang.org/adk/tool/geminitool"
+)
+
+// server answers the messages requests with its bodies, in order, and
+// records the decoded requests.
+type server struct {
+	*httptest.Server
+	status   int
+	bodies   []string
+	requests []map[string]any
+}
+
+func newServer(t *testing.T, status int, bodies ...string) *server {
+	t.Helper()
+	s := &server{status: status, bodies: bodies}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/messages" {
+			t.Errorf("path = %q, want /v1/messages", r.URL.Path)
+		}
+		if key := r.Header.Get("x-api-key"); key != "key" {
+			t.Errorf("x-api-key = %q, want key", key)
+		}
+		if v := r.Header.Get("anthropic-version"); v == "" {
+			t.Error("no anthropic-version header")
+		}
+		b, err := io.ReadAll(r.Body)
+		if err != nil {
+			t.Error(err)
+		}
+		var got map[string]any
+		if err := json.Unmarshal(b, &got); err != nil {
+			t.Errorf("invalid request %s: %v", b, err)
+		}
+		s.requests = append(s.requests, got)
+		w.WriteHeader(s.status)
+		if len(s.bodies) > 0 {
+			fmt.Fprint(w, s.bodies[0])
+			s.bodies = s.bodies[1:]
+		}
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func newModel(t *testing.T, srv *server) model.LLM {
+	t.Helper()
+	m, err := anthropic.NewModel(t.Context(), "claude-test", &anthropic.Config{BaseURL: srv.URL, APIKey: "key"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return m
+}
+
+func generate(t *testing.T, m model.LLM, req *model.LLMRequest, stream bool) []*model.LLMResponse {
+	t.Helper()
+	var responses []*model.LLMResponse
+	for resp, err := range m.GenerateContent(t.Context(), req, stream) {
+		if err != nil {
+			t.Fatalf("GenerateContent() error = %v", err)
+		}
+		responses = append(responses, resp)
+	}
+	return responses
+}
+
+func weatherTool(t *testing.T) tool.Tool {
+	t.Helper()
+	weather, err := functiontool.New(functiontool.Config{Name: "get_weather", Description: "Returns the weather."},
+		func(tool.Context, struct {
+			City string `json:"city"`
+		}) (string, error) {
+			return "sunny", nil
+		})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return weather
+}
+
+func TestModel_Generate(t *testing.T) {
+	srv := newServer(t, http.StatusOK, `{
+		"type": "message",
+		"role": "assistant",
+		"content": [{"type": "text", "text": "It is sunny."}],
+		"stop_reason": "end_turn",
+		"usage": {"input_tokens": 10, "output_tokens": 5, "cache_read_input_tokens": 2}
+	}`)
+
+	req := &model.LLMRequest{
+		Contents: []*genai.Content{
+			{Role: genai.RoleUser, Parts: []*genai.Part{
+				{Text: "Weather of this city?"},
+				{InlineData: &genai.Blob{MIMEType: "image/png", Data: []byte("png")}},
+			}},
+			{Role: genai.RoleModel, Parts: []*genai.Part{
+				{Text: "unsigned thought", Thought: true},
+				{FunctionCall: &genai.FunctionCall{ID: "toolu_1", Name: "get_weather", Args: map[string]any{"city": "Rome"}}},
+			}},
+			{Role: genai.RoleUser, Parts: []*genai.Part{
+				{FunctionResponse: &genai.FunctionResponse{ID: "toolu_1", Name: "get_weather", Response: map[string]any{"result": "rainy"}}},
+			}},
+			genai.NewContentFromText("Thanks.", genai.RoleUser),
+		},
+		Config: &genai.GenerateContentConfig{
+			SystemInstruction: genai.NewContentFromText("Be brief.", genai.RoleUser),
+			Temperature:       genai.Ptr[float32](0.5),
+			TopK:              genai.Ptr[float32](40),
+			StopSequences:     []string{"END"},
+		},
+		Tools: map[string]any{"get_weather": weatherTool(t)},
+	}
+	responses := generate(t, newModel(t, srv), req, false)
+
+	wantRequest := map[string]any{
+		"model":      "claude-test",
+		"max_tokens": float64(anthropic.DefaultMaxTokens),
+		"system":     "Be brief.",
+		"messages": []any{
+			map[string]any{"role": "user", "content": []any{
+				map[string]any{"type": "text", "text": "Weather of this city?"},
+				map[string]any{"type": "image", "source": map[string]any{"type": "base64", "media_type": "image/png", "data": "cG5n"}},
+			}},
+			map[string]any{"role": "assistant", "content": []any{
+				map[string]any{"type": "tool_use", "id": "toolu_1", "name": "get_weather", "input": map[string]any{"city": "Rome"}},
+			}},
+			// The function response and the text are merged in a message.
+			map[string]any{"role": "user", "content": []any{
+				map[string]any{"type": "tool_result", "tool_use_id": "toolu_1", "content": `{"result":"rainy"}`},
+				map[string]any{"type": "text", "text": "Thanks."},
+			}},
+		},
+		"tools": []any{map[string]any{
+			"name":        "get_weather",
+			"description": "Returns the weather.",
+			"input_schema": map[string]any{
+				"type":                 "object",
+				"properties":           map[string]any{"city": map[string]any{"type": "string"}},
+				"required":             []any{"city"},
+				"additionalProperties": false,
+			},
+		}},
+		"temperature":    0.5,
+		"top_k":          40.0,
+		"stop_sequences": []any{"END"},
+	}
+	if diff := cmp.Diff(wantRequest, srv.requests[0]); diff != "" {
+		t.Errorf("request mismatch (-want +got):\n%s", diff)
+	}
+
+	want := []*model.LLMResponse{{
+		Content: genai.NewContentFromText("It is sunny.", genai.RoleModel),
+		UsageMetadata: &genai.GenerateContentResponseUsageMetadata{
+			PromptTokenCount:        12,
+			CachedContentTokenCount: 2,
+			CandidatesTokenCount:    5,
+			TotalTokenCount:         17,
+		},
+		FinishReason: genai.FinishReasonStop,
+	}}
+	if diff := cmp.Diff(want, responses); diff != "" {
+		t.Errorf("responses mismatch (-want +got):\n%s", diff)
+	}
+}
+
+// TestModel_RoundTrip checks that the content blocks of the model are sent
+// back as is, once translated to contents.
+func TestModel_RoundTrip(t *testing.T) {
+	blocks := `[
+		{"type": "thinking", "thinking": "The user wants the weather.", "signature": "sig"},
+		{"type": "text", "text": "Let me check."},
+		{"type": "tool_use", "id": "toolu_1", "name": "get_weather", "input": {"city": "Paris"}}
+	]`
+	srv := newServer(t, http.StatusOK,
+		`{"content": `+blocks+`, "stop_reason": "tool_use", "usage": {"input_tokens": 1, "output_tokens": 1}}`,
+		`{"content": [{"type": "text", "text": "Sunny."}], "stop_reason": "end_turn", "usage": {"input_tokens": 1, "output_tokens": 1}}`,
+	)
+	m := newModel(t, srv)
+
+	contents := []*genai.Content{genai.NewContentFromText("Weather in Paris?", genai.RoleUser)}
+	responses := generate(t, m, &model.LLMRequest{Contents: contents}, false)
+
+	wantContent := &genai.Content{Role: genai.RoleModel, Parts: []*genai.Part{
+		{Text: "The user wants the weather.", Thought: true, ThoughtSignature: []byte("sig")},
+		{Text: "Let me check."},
+		{FunctionCall: &genai.FunctionCall{ID: "toolu_1", Name: "get_weather", Args: map[string]any{"city": "Paris"}}},
+	}}
+	if diff := cmp.Diff(wantContent, responses[0].Content); diff != "" {
+		t.Fatalf("content mismatch (-want +got):\n%s", diff)
+	}
+
+	contents = append(contents, responses[0].Content, &genai.Content{Role: genai.RoleUser, Parts: []*genai.Part{
+		{FunctionResponse: &genai.FunctionResponse{ID: "toolu_1", Name: "get_weather", Response: map[string]any{"error": "unavailable"}}},
+	}})
+	generate(t, m, &model.LLMRequest{Contents: contents}, false)
+
+	var wantBlocks []any
+	if err := json.Unmarshal([]byte(blocks), &wantBlocks); err != nil {
+		t.Fatal(err)
+	}
+	wantMessages := []any{
+		map[string]any{"role": "user", "content": []any{map[string]any{"type": "text", "text": "Weather in Paris?"}}},
+		map[string]any{"role": "assistant", "content": wantBlocks},
+		map[string]any{"role": "user", "content": []any{
+			map[string]any{"type": "tool_result", "tool_use_id": "toolu_1", "content": `{"error":"unavailable"}`, "is_error": true},
+		}},
+	}
+	if diff := cmp.Diff(wantMessages, srv.requests[1]["messages"]); diff != "" {
+		t.Errorf("messages mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestModel_GenerateStream(t *testing.T) {
+	events := []string{
+		`event: message_start`,
+		`data: {"type": "message_start", "message": {"content": [], "usage": {"input_tokens": 3, "output_tokens": 1}}}`,
+		`event: content_block_start`,
+		`data: {"type": "content_block_start", "index": 0, "content_block": {"type": "thinking", "thinking": ""}}`,
+		`data: {"type": "content_block_delta", "index": 0, "delta": {"type": "thinking_delta", "thinking": "Hmm."}}`,
+		`data: {"type": "content_block_delta", "index": 0, "delta": {"type": "signature_delta", "signature": "sig"}}`,
+		`data: {"type": "content_block_stop", "index": 0}`,
+		`data: {"type": "content_block_start", "index": 1, "content_block": {"type": "text", "text": ""}}`,
+		`data: {"type": "ping"}`,
+		`data: {"type": "content_block_delta", "index": 1, "delta": {"type": "text_delta", "text": "Hel"}}`,
+		`data: {"type": "content_block_delta", "index": 1, "delta": {"type": "text_delta", "text": "lo"}}`,
+		`data: {"type": "content_block_stop", "index": 1}`,
+		`data: {"type": "content_block_start", "index": 2, "content_block": {"type": "tool_use", "id": "toolu_1", "name": "get_weather", "input": {}}}`,
+		`data: {"type": "content_block_delta", "index": 2, "delta": {"type": "input_json_delta", "partial_json": "{\"city\":"}}`,
+		`data: {"type": "content_block_delta", "index": 2, "delta": {"type": "input_json_delta", "partial_json": " \"Paris\"}"}}`,
+		`data: {"type": "content_block_stop", "index": 2}`,
+		`data: {"type": "message_delta", "delta": {"stop_reason": "tool_use"}, "usage": {"output_tokens": 7}}`,
+		`data: {"type": "message_stop"}`,
+		``,
+	}
+	srv := newServer(t, http.StatusOK, strings.Join(events, "\n"))
+
+	responses := generate(t, newModel(t, srv), &model.LLMRequest{Contents: genai.Text("Hi")}, true)
+
+	if stream, _ := srv.requests[0]["stream"].(bool); !stream {
+		t.Errorf("stream = %v, want true", srv.requests[0]["stream"])
+	}
+	want := []*model.LLMResponse{
+		{Content: &genai.Content{Role: genai.RoleModel, Parts: []*genai.Part{{Text: "Hmm.", Thought: true}}}, Partial: true},
+		{Content: genai.NewContentFromText("Hel", genai.RoleModel), Partial: true},
+		{Content: genai.NewContentFromText("lo", genai.RoleModel), Partial: true},
+		{
+			Content: &genai.Content{Role: genai.RoleModel, Parts: []*genai.Part{
+				{Text: "Hmm.", Thought: true, ThoughtSignature: []byte("sig")},
+				{Text: "Hello"},
+				{FunctionCall: &genai.FunctionCall{ID: "toolu_1", Name: "get_weather", Args: map[string]any{"city": "Paris"}}},
+			}},
+			UsageMetadata: &genai.GenerateContentResponseUsageMetadata{PromptTokenCount: 3, CandidatesTokenCount: 7, TotalTokenCount: 10},
+			FinishReason:  genai.FinishReasonStop,
+			TurnComplete:  true,
+		},
+	}
+	if diff := cmp.Diff(want, responses); diff != "" {
+		t.Errorf("responses mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestModel_Errors(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		status  int
+		body    string
+		stream  bool
+		req     *model.LLMRequest
+		wantErr string
+	}{
+		{
+			name:    "error status",
+			status:  http.StatusTooManyRequests,
+			body:    `{"type": "error", "error": {"type": "rate_limit_error", "message": "rate limited"}}`,
+			req:     &model.LLMRequest{Contents: genai.Text("Hi")},
+			wantErr: "rate limited",
+		},
+		{
+			name:    "stream error",
+			status:  http.StatusOK,
+			body:    `data: {"type": "error", "error": {"type": "overloaded_error", "message": "overloaded"}}` + "\n",
+			stream:  true,
+			req:     &model.LLMRequest{Contents: genai.Text("Hi")},
+			wantErr: "overloaded",
+		},
+		{
+			name:    "built-in tool",
+			status:  http.StatusOK,
+			req:     &model.LLMRequest{Contents: genai.Text("Hi"), Tools: map[string]any{"google_search": geminitool.GoogleSearch{}}},
+			wantErr: "not a function tool",
+		},
+		{
+			name:    "output schema",
+			status:  http.StatusOK,
+			req:     &model.LLMRequest{Contents: genai.Text("Hi"), Config: &genai.GenerateContentConfig{ResponseSchema: &genai.Schema{Type: genai.TypeObject}}},
+			wantErr: "not supported",
+		},
+		{
+			name:   "unsupported data",
+			status: http.StatusOK,
+			req: &model.LLMRequest{Contents: []*genai.Content{{Role: genai.RoleUser, Parts: []*genai.Part{
+				{InlineData: &genai.Blob{MIMEType: "audio/wav", Data: []byte("wav")}},
+			}}}},
+			wantErr: "audio/wav",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			srv := newServer(t, tc.status, tc.body)
+			var gotErr error
+			for _, err := range newModel(t, srv).GenerateContent(t.Context(), tc.req, tc.stream) {
+				gotErr = err
+			}
+			if gotErr == nil || !strings.Contains(gotErr.Error(), tc.wantErr) {
+				t.Errorf("GenerateContent() error = %v, want %q", gotErr, tc.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,129 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cachemodel
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"google.golang.org/adk/model"
+)
+
+// NewInMemoryCache returns a cache holding the responses in memory, without
+// eviction.
+func NewInMemoryCache() Cache {
+	return &inMemoryCache{entries: make(map[string][]byte)}
+}
+
+type inMemoryCache struct {
+	mu sync.RWMutex
+	// entries are the encoded responses, decoded into new responses on each
+	// Get.
+	entries map[string][]byte
+}
+
+func (c *inMemoryCache) Get(_ context.Context, key string) ([]*model.LLMResponse, bool, error) {
+	c.mu.RLock()
+	b, ok := c.entries[key]
+	c.mu.RUnlock()
+	if !ok {
+		return nil, false, nil
+	}
+	resps, err := decodeResponses(b)
+	if err != nil {
+		return nil, false, err
+	}
+	return resps, true, nil
+}
+
+func (c *inMemoryCache) Put(_ context.Context, key string, resps []*model.LLMResponse) error {
+	b, err := json.Marshal(resps)
+	if err != nil {
+		return fmt.Errorf("failed to encode the model responses: %w", err)
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries[key] = b
+	return nil
+}
+
+// NewDiskCache returns a cache storing the responses in JSON files in the
+// directory, which is created if needed. The files can be committed with the
+// tests replaying them.
+func NewDiskCache(dir string) (Cache, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create the cache directory: %w", err)
+	}
+	return &diskCache{dir: dir}, nil
+}
+
+type diskCache struct {
+	dir string
+}
+
+func (c *diskCache) path(key string) string {
+	return filepath.Join(c.dir, key+".json")
+}
+
+func (c *diskCache) Get(_ context.Context, key string) ([]*model.LLMResponse, bool, error) {
+	b, err := os.ReadFile(c.path(key))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	resps, err := decodeResponses(b)
+	if err != nil {
+		return nil, false, fmt.Errorf("invalid cache entry %s: %w", c.path(key), err)
+	}
+	return resps, true, nil
+}
+
+// Put writes the entry to a temporary file renamed into place, so that the
+// concurrent readers never see a partial entry.
+func (c *diskCache) Put(_ context.Context, key string, resps []*model.LLMResponse) error {
+	b, err := json.MarshalIndent(resps, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode the model responses: %w", err)
+	}
+	f, err := os.CreateTemp(c.dir, key+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+	if _, err := f.Write(b); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), c.path(key))
+}
+
+func decodeResponses(b []byte) ([]*model.LLMResponse, error) {
+	var resps []*model.LLMResponse
+	if err := json.Unmarshal(b, &resps); err != nil {
+		return nil, fmt.Errorf("failed to decode the model responses: %w", err)
+	}
+	return resps, nil
+}
@@ -0,0 +1,163 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package cachemodel wraps models to cache their responses, keyed by a hash
+// of the request, so that identical requests are answered without calling
+// the model again, e.g. to make tests deterministic or to replay evaluation
+// suites.
+//
+// The responses are cached in a [Cache]: [NewInMemoryCache] for the lifetime
+// of the process, or [NewDiskCache] across processes.
+package cachemodel
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"iter"
+	"log"
+
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/model"
+)
+
+// Cache stores the responses of model calls by key. It must be safe for
+// concurrent use.
+type Cache interface {
+	// Get returns the responses stored for the key, and whether there are.
+	Get(ctx context.Context, key string) ([]*model.LLMResponse, bool, error)
+	// Put stores the responses for the key.
+	Put(ctx context.Context, key string, resps []*model.LLMResponse) error
+}
+
+// WithCache returns a model behaving like m, except that its responses are
+// stored in the cache, and replayed for the identical requests. Two requests
+// are identical if they have the same key, see Key. The calls failing are
+// not cached.
+//
+// The live connections of a model.LiveLLM are not cached.
+func WithCache(m model.LLM, cache Cache) model.LLM {
+	c := &cachingModel{LLM: m, cache: cache}
+	if live, ok := m.(model.LiveLLM); ok {
+		return &cachingLiveModel{cachingModel: c, live: live}
+	}
+	return c
+}
+
+type cachingModel struct {
+	model.LLM
+	cache Cache
+}
+
+// Unwrap returns the wrapped model.
+func (m *cachingModel) Unwrap() model.LLM {
+	return m.LLM
+}
+
+// GenerateContent replays the cached responses of the request, or calls the
+// wrapped model and caches its responses.
+func (m *cachingModel) GenerateContent(ctx context.Context, req *model.LLMRequest, stream bool) iter.Seq2[*model.LLMResponse, error] {
+	return func(yield func(*model.LLMResponse, error) bool) {
+		key, err := Key(m.Name(), req, stream)
+		if err != nil {
+			yield(nil, err)
+			return
+		}
+		cached, ok, err := m.cache.Get(ctx, key)
+		if err != nil {
+			yield(nil, fmt.Errorf("failed to read the model response cache: %w", err))
+			return
+		}
+		if ok {
+			for _, resp := range cached {
+				if !yield(resp, nil) {
+					return
+				}
+			}
+			return
+		}
+
+		var resps []*model.LLMResponse
+		for resp, err := range m.LLM.GenerateContent(ctx, req, stream) {
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+			// The copy is cached before the flow modifies the response.
+			copied, err := clone(resp)
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+			resps = append(resps, copied)
+			if !yield(resp, nil) {
+				return
+			}
+		}
+		if err := m.cache.Put(ctx, key, resps); err != nil {
+			log.Printf("Failed to cache the responses of model %s: %v", m.Name(), err)
+		}
+	}
+}
+
+// cachingLiveModel is a cachingModel of a model.LiveLLM.
+type cachingLiveModel struct {
+	*cachingModel
+	live model.LiveLLM
+}
+
+func (m *cachingLiveModel) ConnectLive(ctx context.Context, req *model.LLMRequest) (model.LiveConnection, error) {
+	return m.live.ConnectLive(ctx, req)
+}
+
+// Key returns the cache key of a request to the model: the SHA-256 hash of
+// the canonical JSON encoding of the model name, whether the call streams,
+// and the contents and the config of the request, tools included. The HTTP
+// options of the config are not part of the key.
+func Key(modelName string, req *model.LLMRequest, stream bool) (string, error) {
+	var cfg *genai.GenerateContentConfig
+	if req.Config != nil {
+		copied := *req.Config
+		copied.HTTPOptions = nil
+		cfg = &copied
+	}
+	// The encoding of the maps, e.g. of the function call arguments, sorts
+	// their keys.
+	b, err := json.Marshal(struct {
+		Model    string                       `json:"model"`
+		Stream   bool                         `json:"stream"`
+		Contents []*genai.Content             `json:"contents"`
+		Config   *genai.GenerateContentConfig `json:"config"`
+	}{modelName, stream, req.Contents, cfg})
+	if err != nil {
+		return "", fmt.Errorf("failed to encode the model request: %w", err)
+	}
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+func clone(resp *model.LLMResponse) (*model.LLMResponse, error) {
+	b, err := json.Marshal(resp)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode the model response: %w", err)
+	}
+	var copied model.LLMResponse
+	if err := json.Unmarshal(b, &copied); err != nil {
+		return nil, fmt.Errorf("failed to decode the model response: %w", err)
+	}
+	return &copied, nil
+}
@@ -0,0 +1,168 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cachemodel_test
+
+import (
+	"context"
+	"errors"
+	"iter"
+	"net/http"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/model"
+	"google.golang.org/adk/model/cachemodel"
+)
+
+// countingModel streams a response, counting its calls.
+type countingModel struct {
+	calls int
+	err   error
+}
+
+func (m *countingModel) Name() string {
+	return "counting"
+}
+
+func (m *countingModel) GenerateContent(ctx context.Context, req *model.LLMRequest, stream bool) iter.Seq2[*model.LLMResponse, error] {
+	return func(yield func(*model.LLMResponse, error) bool) {
+		m.calls++
+		if !yield(&model.LLMResponse{Content: genai.NewContentFromText("Hel", genai.RoleModel), Partial: true}, nil) {
+			return
+		}
+		if m.err != nil {
+			yield(nil, m.err)
+			return
+		}
+		yield(&model.LLMResponse{
+			Content: genai.NewContentFromParts([]*genai.Part{
+				genai.NewPartFromText("Hello"),
+				genai.NewPartFromFunctionCall("weather", map[string]any{"city": "Paris"}),
+			}, genai.RoleModel),
+			UsageMetadata: &genai.GenerateContentResponseUsageMetadata{TotalTokenCount: 10},
+		}, nil)
+	}
+}
+
+func collect(t *testing.T, m model.LLM, req *model.LLMRequest) []*model.LLMResponse {
+	t.Helper()
+	var resps []*model.LLMResponse
+	for resp, err := range m.GenerateContent(t.Context(), req, true) {
+		if err != nil {
+			t.Fatalf("GenerateContent() error = %v", err)
+		}
+		resps = append(resps, resp)
+	}
+	return resps
+}
+
+func newRequest(text string) *model.LLMRequest {
+	return &model.LLMRequest{
+		Contents: genai.Text(text),
+		Config: &genai.GenerateContentConfig{
+			Temperature: genai.Ptr[float32](0),
+			Tools:       []*genai.Tool{{FunctionDeclarations: []*genai.FunctionDeclaration{{Name: "weather"}}}},
+		},
+	}
+}
+
+func TestWithCache(t *testing.T) {
+	diskCache, err := cachemodel.NewDiskCache(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, cache := range map[string]cachemodel.Cache{
+		"in memory": cachemodel.NewInMemoryCache(),
+		"disk":      diskCache,
+	} {
+		t.Run(name, func(t *testing.T) {
+			llm := &countingModel{}
+			m := cachemodel.WithCache(llm, cache)
+
+			first := collect(t, m, newRequest("Hi"))
+			// The caller may modify the responses, e.g. set the function call
+			// IDs.
+			first[1].Content.Parts[1].FunctionCall.ID = "adk-1"
+
+			second := collect(t, m, newRequest("Hi"))
+			if llm.calls != 1 {
+				t.Errorf("got %d model calls, want 1", llm.calls)
+			}
+			first[1].Content.Parts[1].FunctionCall.ID = ""
+			if diff := cmp.Diff(first, second); diff != "" {
+				t.Errorf("cached responses mismatch (-want +got):\n%s", diff)
+			}
+
+			collect(t, m, newRequest("Hello"))
+			if llm.calls != 2 {
+				t.Errorf("got %d model calls, want 2 after a different request", llm.calls)
+			}
+		})
+	}
+}
+
+func TestWithCache_Error(t *testing.T) {
+	errModel := errors.New("model error")
+	llm := &countingModel{err: errModel}
+	m := cachemodel.WithCache(llm, cachemodel.NewInMemoryCache())
+	for range 2 {
+		var gotErr error
+		for _, err := range m.GenerateContent(t.Context(), newRequest("Hi"), true) {
+			gotErr = err
+		}
+		if !errors.Is(gotErr, errModel) {
+			t.Errorf("GenerateContent() error = %v, want %v", gotErr, errModel)
+		}
+	}
+	if llm.calls != 2 {
+		t.Errorf("got %d model calls, want 2: the failed calls are not cached", llm.calls)
+	}
+}
+
+func TestKey(t *testing.T) {
+	key := func(modelName string, req *model.LLMRequest, stream bool) string {
+		t.Helper()
+		k, err := cachemodel.Key(modelName, req, stream)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return k
+	}
+	base := key("m", newRequest("Hi"), false)
+
+	withHeaders := newRequest("Hi")
+	withHeaders.Config.HTTPOptions = &genai.HTTPOptions{Headers: http.Header{"X-Request-Id": {"1"}}}
+	if got := key("m", withHeaders, false); got != base {
+		t.Errorf("Key() with HTTP options = %s, want %s", got, base)
+	}
+	if withHeaders.Config.HTTPOptions == nil {
+		t.Error("Key() modified the config of the request")
+	}
+
+	otherTools := newRequest("Hi")
+	otherTools.Config.Tools[0].FunctionDeclarations[0].Name = "time"
+	for name, got := range map[string]string{
+		"model":    key("other", newRequest("Hi"), false),
+		"stream":   key("m", newRequest("Hi"), true),
+		"contents": key("m", newRequest("Hello"), false),
+		"tools":    key("m", otherTools, false),
+	} {
+		if got == base {
+			t.Errorf("Key() with another %s = the key of the request", name)
+		}
+	}
+}
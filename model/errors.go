@@ -0,0 +1,51 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// ErrRateLimited matches, with errors.Is, the errors of the model calls
+// rejected because of the rate limits or the quota of the API: an APIError
+// with the status 429. The Gemini models return a genai.APIError with the
+// code 429 instead, which runner.ClassifyError recognizes too.
+var ErrRateLimited = errors.New("model rate limited")
+
+// APIError is the error of a model call rejected by the API of the model,
+// e.g. rate limited. The Gemini models return a genai.APIError instead.
+type APIError struct {
+	// StatusCode is the HTTP status code of the response, e.g. 429.
+	StatusCode int
+	// Status is the HTTP status of the response, e.g. "429 Too Many Requests".
+	Status string
+	// Message is the error message of the API.
+	Message string
+	// RetryAfter is the delay after which the call may be retried, from the
+	// Retry-After header of the response, or 0.
+	RetryAfter time.Duration
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Status, e.Message)
+}
+
+// Is reports whether the error is rate limited, see ErrRateLimited.
+func (e *APIError) Is(target error) bool {
+	return target == ErrRateLimited && e.StatusCode == http.StatusTooManyRequests
+}
@@ -0,0 +1,180 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gemini
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/model"
+	"google.golang.org/adk/usage"
+)
+
+const (
+	defaultContextCacheTTL           = 30 * time.Minute
+	defaultContextCacheRefreshWindow = time.Minute
+	defaultContextCacheMinTokens     = 4096
+)
+
+// ContextCacheConfig configures the context caching of the static part of
+// the requests: the system instruction, the tools and the tool config, see
+// https://ai.google.dev/gemini-api/docs/caching.
+type ContextCacheConfig struct {
+	// TTL is the lifetime of the cached contents. Defaults to 30m.
+	TTL time.Duration
+	// RefreshWindow is the time before the expiry of a cached content after
+	// which a new one is created for the next requests, so that no request
+	// refers to an expired cache. Defaults to 1m.
+	RefreshWindow time.Duration
+	// MinTokens is the estimated number of tokens of the static part of the
+	// requests below which it is not cached. It should not be below the
+	// minimum of the model, e.g. 1024 tokens for Gemini 2.5 Flash. Defaults
+	// to 4096.
+	MinTokens int64
+}
+
+// NewModelWithContextCache returns a Gemini model like NewModel, which caches
+// the static part of the requests, e.g. the large instructions and tool
+// declarations of an agent, in cached contents of the Gemini API.
+//
+// A cached content is created on the first request with a given system
+// instruction, tools and tool config, estimated to be at least
+// cacheCfg.MinTokens tokens. The next requests with the same static part
+// refer to it, instead of sending it again, until it nears its expiry and is
+// replaced; the expired cached contents are deleted by the Gemini API. If
+// the creation of a cached content fails, the requests are sent uncached.
+func NewModelWithContextCache(ctx context.Context, modelName string, cfg *genai.ClientConfig, cacheCfg ContextCacheConfig) (model.LLM, error) {
+	llm, err := NewModel(ctx, modelName, cfg)
+	if err != nil {
+		return nil, err
+	}
+	if cacheCfg.TTL <= 0 {
+		cacheCfg.TTL = defaultContextCacheTTL
+	}
+	if cacheCfg.RefreshWindow <= 0 {
+		cacheCfg.RefreshWindow = defaultContextCacheRefreshWindow
+	}
+	if cacheCfg.MinTokens <= 0 {
+		cacheCfg.MinTokens = defaultContextCacheMinTokens
+	}
+	m := llm.(*geminiModel)
+	m.contextCache = &contextCache{cfg: cacheCfg, entries: make(map[string]*contextCacheEntry)}
+	return m, nil
+}
+
+// contextCache holds the cached contents of a model, by the fingerprint of
+// the static part of the requests they hold.
+type contextCache struct {
+	cfg ContextCacheConfig
+	// mu is held while a cached content is created, so that concurrent
+	// requests don't create duplicates.
+	mu      sync.Mutex
+	entries map[string]*contextCacheEntry
+}
+
+// contextCacheEntry is a cached content, or a failure to create one, which
+// is not retried before its expiry.
+type contextCacheEntry struct {
+	name       string
+	expireTime time.Time
+}
+
+// staticRequest is the part of a request held by a cached content.
+type staticRequest struct {
+	SystemInstruction *genai.Content    `json:"systemInstruction,omitempty"`
+	Tools             []*genai.Tool     `json:"tools,omitempty"`
+	ToolConfig        *genai.ToolConfig `json:"toolConfig,omitempty"`
+}
+
+// withContextCache returns the request referring to the cached content of
+// its static part, or req if it is not cached.
+func (m *geminiModel) withContextCache(ctx context.Context, req *model.LLMRequest) *model.LLMRequest {
+	if m.contextCache == nil || req.Config == nil || req.Config.CachedContent != "" {
+		return req
+	}
+	static := staticRequest{
+		SystemInstruction: req.Config.SystemInstruction,
+		Tools:             req.Config.Tools,
+		ToolConfig:        req.Config.ToolConfig,
+	}
+	if usage.EstimateTokens(static) < m.contextCache.cfg.MinTokens {
+		return req
+	}
+	name := m.contextCache.cachedContent(ctx, m, static, req.Config.HTTPOptions)
+	if name == "" {
+		return req
+	}
+
+	cfg := *req.Config
+	cfg.CachedContent = name
+	cfg.SystemInstruction = nil
+	cfg.Tools = nil
+	cfg.ToolConfig = nil
+	cached := *req
+	cached.Config = &cfg
+	return &cached
+}
+
+// cachedContent returns the name of the cached content of the static part of
+// the requests, created if needed, or "" if it could not be created.
+func (c *contextCache) cachedContent(ctx context.Context, m *geminiModel, static staticRequest, httpOptions *genai.HTTPOptions) string {
+	b, err := json.Marshal(static)
+	if err != nil {
+		return ""
+	}
+	sum := sha256.Sum256(b)
+	fingerprint := hex.EncodeToString(sum[:])
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := time.Now()
+	if e, ok := c.entries[fingerprint]; ok && now.Add(c.cfg.RefreshWindow).Before(e.expireTime) {
+		return e.name
+	}
+
+	createCfg := &genai.CreateCachedContentConfig{
+		TTL:               c.cfg.TTL,
+		DisplayName:       "adk-" + fingerprint[:16],
+		SystemInstruction: static.SystemInstruction,
+		Tools:             static.Tools,
+		ToolConfig:        static.ToolConfig,
+	}
+	if httpOptions != nil {
+		createCfg.HTTPOptions = &genai.HTTPOptions{Headers: httpOptions.Headers.Clone()}
+	} else {
+		createCfg.HTTPOptions = &genai.HTTPOptions{Headers: make(http.Header)}
+	}
+	m.addHeaders(createCfg.HTTPOptions.Headers)
+	cached, err := m.client.Caches.Create(ctx, m.name, createCfg)
+	if err != nil {
+		log.Printf("Failed to create the context cache of model %s, sending the requests uncached: %v", m.name, err)
+		c.entries[fingerprint] = &contextCacheEntry{expireTime: now.Add(c.cfg.TTL)}
+		return ""
+	}
+	expireTime := cached.ExpireTime
+	if expireTime.IsZero() {
+		expireTime = now.Add(c.cfg.TTL)
+	}
+	c.entries[fingerprint] = &contextCacheEntry{name: cached.Name, expireTime: expireTime}
+	return cached.Name
+}
@@ -0,0 +1,157 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gemini
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/model"
+)
+
+// fakeGeminiAPI serves the creation of cached contents and the generation of
+// contents, recording the requests.
+type fakeGeminiAPI struct {
+	mu            sync.Mutex
+	caches        int
+	failCaches    bool
+	expireIn      time.Duration
+	generateReqs  []map[string]any
+	cacheRequests []map[string]any
+}
+
+func (f *fakeGeminiAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var body map[string]any
+	b, _ := io.ReadAll(r.Body)
+	_ = json.Unmarshal(b, &body)
+	switch {
+	case strings.HasSuffix(r.URL.Path, "/cachedContents"):
+		if f.failCaches {
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprint(w, `{"error": {"code": 400, "message": "too few tokens"}}`)
+			return
+		}
+		f.caches++
+		f.cacheRequests = append(f.cacheRequests, body)
+		fmt.Fprintf(w, `{"name": "cachedContents/%d", "expireTime": %q}`, f.caches, time.Now().Add(f.expireIn).Format(time.RFC3339))
+	case strings.HasSuffix(r.URL.Path, ":generateContent"):
+		f.generateReqs = append(f.generateReqs, body)
+		fmt.Fprint(w, `{"candidates": [{"content": {"role": "model", "parts": [{"text": "ok"}]}}]}`)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func newContextCacheModel(t *testing.T, api *fakeGeminiAPI, cacheCfg ContextCacheConfig) model.LLM {
+	t.Helper()
+	srv := httptest.NewServer(api)
+	t.Cleanup(srv.Close)
+	m, err := NewModelWithContextCache(t.Context(), "gemini-2.5-flash", &genai.ClientConfig{
+		APIKey:      "key",
+		Backend:     genai.BackendGeminiAPI,
+		HTTPOptions: genai.HTTPOptions{BaseURL: srv.URL},
+	}, cacheCfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return m
+}
+
+func cacheTestRequest(instruction string) *model.LLMRequest {
+	return &model.LLMRequest{
+		Contents: genai.Text("Hi"),
+		Config: &genai.GenerateContentConfig{
+			SystemInstruction: genai.NewContentFromText(instruction, genai.RoleUser),
+			Tools:             []*genai.Tool{{FunctionDeclarations: []*genai.FunctionDeclaration{{Name: "lookup"}}}},
+		},
+	}
+}
+
+func generateOnce(t *testing.T, m model.LLM, req *model.LLMRequest) {
+	t.Helper()
+	for _, err := range m.GenerateContent(t.Context(), req, false) {
+		if err != nil {
+			t.Fatalf("GenerateContent() error = %v", err)
+		}
+	}
+}
+
+func TestContextCache(t *testing.T) {
+	api := &fakeGeminiAPI{expireIn: time.Hour}
+	m := newContextCacheModel(t, api, ContextCacheConfig{MinTokens: 100})
+	large := strings.Repeat("You are a helpful assistant. ", 100)
+
+	req := cacheTestRequest(large)
+	generateOnce(t, m, req)
+	generateOnce(t, m, cacheTestRequest(large))
+	// Too small to be cached.
+	generateOnce(t, m, cacheTestRequest("Be brief."))
+
+	if api.caches != 1 {
+		t.Fatalf("created %d cached contents, want 1", api.caches)
+	}
+	if got := api.cacheRequests[0]["systemInstruction"]; got == nil {
+		t.Errorf("cached content = %v, want the system instruction", api.cacheRequests[0])
+	}
+	for i, body := range api.generateReqs[:2] {
+		if body["cachedContent"] != "cachedContents/1" || body["systemInstruction"] != nil || body["tools"] != nil {
+			t.Errorf("request %d = %v, want the cached content instead of the system instruction and the tools", i, body)
+		}
+	}
+	if body := api.generateReqs[2]; body["cachedContent"] != nil || body["systemInstruction"] == nil {
+		t.Errorf("small request = %v, want it uncached", body)
+	}
+	if req.Config.SystemInstruction == nil || req.Config.CachedContent != "" {
+		t.Error("GenerateContent() modified the config of the request")
+	}
+}
+
+func TestContextCache_Refresh(t *testing.T) {
+	// The cached contents expire within the refresh window.
+	api := &fakeGeminiAPI{expireIn: 30 * time.Second}
+	m := newContextCacheModel(t, api, ContextCacheConfig{MinTokens: 100})
+	large := strings.Repeat("You are a helpful assistant. ", 100)
+
+	generateOnce(t, m, cacheTestRequest(large))
+	generateOnce(t, m, cacheTestRequest(large))
+	if api.caches != 2 {
+		t.Errorf("created %d cached contents, want 2", api.caches)
+	}
+	if got := api.generateReqs[1]["cachedContent"]; got != "cachedContents/2" {
+		t.Errorf("cachedContent = %v, want the refreshed cache", got)
+	}
+}
+
+func TestContextCache_CreateFailure(t *testing.T) {
+	api := &fakeGeminiAPI{failCaches: true}
+	m := newContextCacheModel(t, api, ContextCacheConfig{MinTokens: 100})
+	large := strings.Repeat("You are a helpful assistant. ", 100)
+
+	generateOnce(t, m, cacheTestRequest(large))
+	if body := api.generateReqs[0]; body["cachedContent"] != nil || body["systemInstruction"] == nil {
+		t.Errorf("request = %v, want it uncached", body)
+	}
+}
@@ -22,6 +22,7 @@ import (
 	"net/http"
 	"runtime"
 	"strings"
+	"sync"
 
 	"google.golang.org/genai"
 
@@ -36,6 +37,13 @@ type geminiModel struct {
 	client             *genai.Client
 	name               string
 	versionHeaderValue string
+	// contextCache is set by NewModelWithContextCache.
+	contextCache *contextCache
+	// structuredSchemas is set for the models which only accept
+	// genai.Schema, see withStructuredSchemas.
+	structuredSchemas bool
+	// schemaWarnings are the schema conversion warnings already logged.
+	schemaWarnings sync.Map
 }
 
 // NewModel returns [model.LLM], backed by the Gemini API.
@@ -44,6 +52,13 @@ type geminiModel struct {
 // [genai.Client]. The modelName specifies which Gemini model to target
 // (e.g., "gemini-2.5-flash").
 //
+// The function declarations and the response schemas are sent as JSON
+// Schema, except to the models which only accept the structured
+// genai.Schema form: the Gemini 1.x models and the models served by Vertex
+// AI endpoints. For them, the JSON Schemas are converted, and the keywords
+// genai.Schema does not support are approximated or dropped, with a logged
+// warning.
+//
 // An error is returned if the [genai.Client] fails to initialize.
 func NewModel(ctx context.Context, modelName string, cfg *genai.ClientConfig) (model.LLM, error) {
 	client, err := genai.NewClient(ctx, cfg)
@@ -59,6 +74,7 @@ func NewModel(ctx context.Context, modelName string, cfg *genai.ClientConfig) (m
 		name:               modelName,
 		client:             client,
 		versionHeaderValue: headerValue,
+		structuredSchemas:  structuredSchemas(client.ClientConfig().Backend, modelName),
 	}, nil
 }
 
@@ -79,6 +95,8 @@ func (m *geminiModel) GenerateContent(ctx context.Context, req *model.LLMRequest
 		req.Config.HTTPOptions.Headers = make(http.Header)
 	}
 	m.addHeaders(req.Config.HTTPOptions.Headers)
+	req = m.withStructuredSchemas(req)
+	req = m.withContextCache(ctx, req)
 
 	if stream {
 		return m.generateStream(ctx, req)
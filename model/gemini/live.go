@@ -0,0 +1,268 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gemini
+
+import (
+	"context"
+	"fmt"
+	"iter"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/model"
+)
+
+var _ model.LiveLLM = (*geminiModel)(nil)
+
+// ConnectLive opens a connection to the Gemini Live API.
+func (m *geminiModel) ConnectLive(ctx context.Context, req *model.LLMRequest) (model.LiveConnection, error) {
+	session, err := m.client.Live.Connect(ctx, m.name, liveConnectConfig(req))
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to model: %w", err)
+	}
+	conn := &liveConnection{session: session}
+	if len(req.Contents) > 0 {
+		// The model replies to the history if it ends with the user turn.
+		turnComplete := req.Contents[len(req.Contents)-1].Role == genai.RoleUser
+		if err := session.SendClientContent(genai.LiveClientContentInput{Turns: req.Contents, TurnComplete: &turnComplete}); err != nil {
+			session.Close()
+			return nil, fmt.Errorf("failed to send the history: %w", err)
+		}
+	}
+	return conn, nil
+}
+
+// liveConnectConfig returns the config of the live connection: the live
+// config of the request, completed by its generation config.
+func liveConnectConfig(req *model.LLMRequest) *genai.LiveConnectConfig {
+	cfg := &genai.LiveConnectConfig{}
+	if req.LiveConnectConfig != nil {
+		*cfg = *req.LiveConnectConfig
+	}
+	if c := req.Config; c != nil {
+		cfg.SystemInstruction = c.SystemInstruction
+		cfg.Tools = c.Tools
+		cfg.Temperature = c.Temperature
+		cfg.TopP = c.TopP
+		cfg.TopK = c.TopK
+		cfg.MaxOutputTokens = c.MaxOutputTokens
+		cfg.Seed = c.Seed
+		cfg.ThinkingConfig = c.ThinkingConfig
+		if cfg.MediaResolution == "" {
+			cfg.MediaResolution = c.MediaResolution
+		}
+	}
+	return cfg
+}
+
+// liveConnection is a [model.LiveConnection] over a Gemini Live API session.
+type liveConnection struct {
+	session *genai.Session
+	mu      sync.Mutex // serializes the sends
+	closed  atomic.Bool
+}
+
+func (c *liveConnection) Send(req *model.LiveRequest) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	switch {
+	case req.Content != nil:
+		var responses []*genai.FunctionResponse
+		var parts []*genai.Part
+		for _, p := range req.Content.Parts {
+			if p.FunctionResponse != nil {
+				responses = append(responses, p.FunctionResponse)
+			} else {
+				parts = append(parts, p)
+			}
+		}
+		if len(responses) > 0 {
+			if err := c.session.SendToolResponse(genai.LiveToolResponseInput{FunctionResponses: responses}); err != nil {
+				return err
+			}
+		}
+		if len(parts) > 0 {
+			turn := &genai.Content{Role: req.Content.Role, Parts: parts}
+			return c.session.SendClientContent(genai.LiveClientContentInput{Turns: []*genai.Content{turn}, TurnComplete: genai.Ptr(true)})
+		}
+		return nil
+	case req.Blob != nil:
+		return c.session.SendRealtimeInput(realtimeInput(req.Blob))
+	case req.ActivityStart:
+		return c.session.SendRealtimeInput(genai.LiveRealtimeInput{ActivityStart: &genai.ActivityStart{}})
+	case req.ActivityEnd:
+		return c.session.SendRealtimeInput(genai.LiveRealtimeInput{ActivityEnd: &genai.ActivityEnd{}})
+	}
+	return nil
+}
+
+// realtimeInput returns the realtime input of a blob, by its MIME type.
+func realtimeInput(blob *genai.Blob) genai.LiveRealtimeInput {
+	switch {
+	case strings.HasPrefix(blob.MIMEType, "audio/"):
+		return genai.LiveRealtimeInput{Audio: blob}
+	case strings.HasPrefix(blob.MIMEType, "image/"), strings.HasPrefix(blob.MIMEType, "video/"):
+		return genai.LiveRealtimeInput{Video: blob}
+	default:
+		return genai.LiveRealtimeInput{Media: blob}
+	}
+}
+
+func (c *liveConnection) Receive() iter.Seq2[*model.LLMResponse, error] {
+	return func(yield func(*model.LLMResponse, error) bool) {
+		conv := &liveResponseConverter{}
+		for {
+			msg, err := c.session.Receive()
+			if err != nil {
+				if !c.closed.Load() {
+					yield(nil, fmt.Errorf("failed to receive from model: %w", err))
+				}
+				return
+			}
+			for _, resp := range conv.convert(msg) {
+				if !yield(resp, nil) {
+					return
+				}
+			}
+		}
+	}
+}
+
+func (c *liveConnection) Close() error {
+	if c.closed.Swap(true) {
+		return nil
+	}
+	return c.session.Close()
+}
+
+// liveResponseConverter converts the messages of a Live API session to
+// responses, aggregating the text and the transcriptions of each turn.
+type liveResponseConverter struct {
+	text             strings.Builder
+	thought          strings.Builder
+	inputTranscript  strings.Builder
+	outputTranscript strings.Builder
+	usage            *genai.GenerateContentResponseUsageMetadata
+}
+
+func (c *liveResponseConverter) convert(msg *genai.LiveServerMessage) []*model.LLMResponse {
+	var resps []*model.LLMResponse
+	if u := msg.UsageMetadata; u != nil {
+		c.usage = &genai.GenerateContentResponseUsageMetadata{
+			PromptTokenCount:        u.PromptTokenCount,
+			CachedContentTokenCount: u.CachedContentTokenCount,
+			CandidatesTokenCount:    u.ResponseTokenCount,
+			ToolUsePromptTokenCount: u.ToolUsePromptTokenCount,
+			ThoughtsTokenCount:      u.ThoughtsTokenCount,
+			TotalTokenCount:         u.TotalTokenCount,
+		}
+	}
+	if sc := msg.ServerContent; sc != nil {
+		if t := sc.InputTranscription; t != nil {
+			resps = append(resps, transcriptionResponses(&c.inputTranscript, t, func(r *model.LLMResponse, t *genai.Transcription) {
+				r.InputTranscription = t
+			})...)
+		}
+		if t := sc.OutputTranscription; t != nil {
+			resps = append(resps, transcriptionResponses(&c.outputTranscript, t, func(r *model.LLMResponse, t *genai.Transcription) {
+				r.OutputTranscription = t
+			})...)
+		}
+		if sc.ModelTurn != nil {
+			for _, p := range sc.ModelTurn.Parts {
+				switch {
+				case p.Text != "" && p.Thought:
+					c.thought.WriteString(p.Text)
+				case p.Text != "":
+					c.text.WriteString(p.Text)
+				}
+				resps = append(resps, &model.LLMResponse{
+					Content: &genai.Content{Role: genai.RoleModel, Parts: []*genai.Part{p}},
+					Partial: true,
+				})
+			}
+		}
+		if sc.Interrupted {
+			resps = append(resps, c.flush()...)
+			resps = append(resps, &model.LLMResponse{Interrupted: true})
+		}
+		if sc.TurnComplete {
+			resps = append(resps, c.flush()...)
+			if c.inputTranscript.Len() > 0 {
+				resps = append(resps, &model.LLMResponse{InputTranscription: &genai.Transcription{Text: c.inputTranscript.String(), Finished: true}})
+				c.inputTranscript.Reset()
+			}
+			if c.outputTranscript.Len() > 0 {
+				resps = append(resps, &model.LLMResponse{OutputTranscription: &genai.Transcription{Text: c.outputTranscript.String(), Finished: true}})
+				c.outputTranscript.Reset()
+			}
+			resps = append(resps, &model.LLMResponse{
+				TurnComplete:      true,
+				GroundingMetadata: sc.GroundingMetadata,
+				UsageMetadata:     c.usage,
+			})
+			c.usage = nil
+		}
+	}
+	if tc := msg.ToolCall; tc != nil && len(tc.FunctionCalls) > 0 {
+		resps = append(resps, c.flush()...)
+		content := &genai.Content{Role: genai.RoleModel}
+		for _, fc := range tc.FunctionCalls {
+			content.Parts = append(content.Parts, &genai.Part{FunctionCall: fc})
+		}
+		resps = append(resps, &model.LLMResponse{Content: content})
+	}
+	return resps
+}
+
+// flush returns the response aggregating the text streamed since the last
+// flush, if any.
+func (c *liveResponseConverter) flush() []*model.LLMResponse {
+	if c.text.Len() == 0 && c.thought.Len() == 0 {
+		return nil
+	}
+	content := &genai.Content{Role: genai.RoleModel}
+	if c.thought.Len() > 0 {
+		content.Parts = append(content.Parts, &genai.Part{Text: c.thought.String(), Thought: true})
+	}
+	if c.text.Len() > 0 {
+		content.Parts = append(content.Parts, &genai.Part{Text: c.text.String()})
+	}
+	c.text.Reset()
+	c.thought.Reset()
+	return []*model.LLMResponse{{Content: content}}
+}
+
+// transcriptionResponses returns the partial response of a transcription
+// chunk, followed by the aggregated transcription once finished.
+func transcriptionResponses(transcript *strings.Builder, t *genai.Transcription, set func(*model.LLMResponse, *genai.Transcription)) []*model.LLMResponse {
+	var resps []*model.LLMResponse
+	if t.Text != "" {
+		transcript.WriteString(t.Text)
+		r := &model.LLMResponse{Partial: true}
+		set(r, &genai.Transcription{Text: t.Text})
+		resps = append(resps, r)
+	}
+	if t.Finished {
+		r := &model.LLMResponse{}
+		set(r, &genai.Transcription{Text: transcript.String(), Finished: true})
+		transcript.Reset()
+		resps = append(resps, r)
+	}
+	return resps
+}
@@ -0,0 +1,108 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gemini
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/model"
+)
+
+func TestLiveResponseConverter(t *testing.T) {
+	conv := &liveResponseConverter{}
+	var got []*model.LLMResponse
+	for _, msg := range []*genai.LiveServerMessage{
+		{ServerContent: &genai.LiveServerContent{InputTranscription: &genai.Transcription{Text: "What's the "}}},
+		{ServerContent: &genai.LiveServerContent{InputTranscription: &genai.Transcription{Text: "weather?", Finished: true}}},
+		{ToolCall: &genai.LiveServerToolCall{FunctionCalls: []*genai.FunctionCall{{ID: "1", Name: "weather"}}}},
+		{ServerContent: &genai.LiveServerContent{ModelTurn: genai.NewContentFromText("It is ", genai.RoleModel)}},
+		{ServerContent: &genai.LiveServerContent{ModelTurn: genai.NewContentFromText("sunny.", genai.RoleModel)}},
+		{ServerContent: &genai.LiveServerContent{OutputTranscription: &genai.Transcription{Text: "It is sunny."}}},
+		{UsageMetadata: &genai.UsageMetadata{PromptTokenCount: 3, ResponseTokenCount: 4, TotalTokenCount: 7}},
+		{ServerContent: &genai.LiveServerContent{TurnComplete: true}},
+		{ServerContent: &genai.LiveServerContent{ModelTurn: genai.NewContentFromText("Also", genai.RoleModel)}},
+		{ServerContent: &genai.LiveServerContent{Interrupted: true}},
+	} {
+		got = append(got, conv.convert(msg)...)
+	}
+
+	want := []*model.LLMResponse{
+		{InputTranscription: &genai.Transcription{Text: "What's the "}, Partial: true},
+		{InputTranscription: &genai.Transcription{Text: "weather?"}, Partial: true},
+		{InputTranscription: &genai.Transcription{Text: "What's the weather?", Finished: true}},
+		{Content: &genai.Content{Role: genai.RoleModel, Parts: []*genai.Part{{FunctionCall: &genai.FunctionCall{ID: "1", Name: "weather"}}}}},
+		{Content: genai.NewContentFromText("It is ", genai.RoleModel), Partial: true},
+		{Content: genai.NewContentFromText("sunny.", genai.RoleModel), Partial: true},
+		{OutputTranscription: &genai.Transcription{Text: "It is sunny."}, Partial: true},
+		{Content: genai.NewContentFromText("It is sunny.", genai.RoleModel)},
+		{OutputTranscription: &genai.Transcription{Text: "It is sunny.", Finished: true}},
+		{
+			TurnComplete:  true,
+			UsageMetadata: &genai.GenerateContentResponseUsageMetadata{PromptTokenCount: 3, CandidatesTokenCount: 4, TotalTokenCount: 7},
+		},
+		{Content: genai.NewContentFromText("Also", genai.RoleModel), Partial: true},
+		{Content: genai.NewContentFromText("Also", genai.RoleModel)},
+		{Interrupted: true},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("convert() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestLiveConnectConfig(t *testing.T) {
+	req := &model.LLMRequest{
+		Config: &genai.GenerateContentConfig{
+			SystemInstruction: genai.NewContentFromText("Be brief.", genai.RoleUser),
+			Temperature:       genai.Ptr[float32](0.5),
+			Tools:             []*genai.Tool{{FunctionDeclarations: []*genai.FunctionDeclaration{{Name: "weather"}}}},
+		},
+		LiveConnectConfig: &genai.LiveConnectConfig{
+			ResponseModalities:      []genai.Modality{genai.ModalityAudio},
+			InputAudioTranscription: &genai.AudioTranscriptionConfig{},
+		},
+	}
+	want := &genai.LiveConnectConfig{
+		ResponseModalities:      []genai.Modality{genai.ModalityAudio},
+		InputAudioTranscription: &genai.AudioTranscriptionConfig{},
+		SystemInstruction:       req.Config.SystemInstruction,
+		Temperature:             req.Config.Temperature,
+		Tools:                   req.Config.Tools,
+	}
+	if diff := cmp.Diff(want, liveConnectConfig(req)); diff != "" {
+		t.Errorf("liveConnectConfig() mismatch (-want +got):\n%s", diff)
+	}
+	if req.LiveConnectConfig.SystemInstruction != nil {
+		t.Error("liveConnectConfig() modified the live config of the request")
+	}
+}
+
+func TestRealtimeInput(t *testing.T) {
+	for _, tc := range []struct {
+		mimeType string
+		want     func(*genai.Blob) genai.LiveRealtimeInput
+	}{
+		{"audio/pcm;rate=16000", func(b *genai.Blob) genai.LiveRealtimeInput { return genai.LiveRealtimeInput{Audio: b} }},
+		{"image/jpeg", func(b *genai.Blob) genai.LiveRealtimeInput { return genai.LiveRealtimeInput{Video: b} }},
+		{"application/octet-stream", func(b *genai.Blob) genai.LiveRealtimeInput { return genai.LiveRealtimeInput{Media: b} }},
+	} {
+		blob := &genai.Blob{MIMEType: tc.mimeType, Data: []byte("data")}
+		if diff := cmp.Diff(tc.want(blob), realtimeInput(blob)); diff != "" {
+			t.Errorf("realtimeInput(%q) mismatch (-want +got):\n%s", tc.mimeType, diff)
+		}
+	}
+}
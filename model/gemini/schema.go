@@ -0,0 +1,130 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gemini
+
+import (
+	"encoding/json"
+	"log"
+	"slices"
+	"strings"
+
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/internal/typeutil"
+	"google.golang.org/adk/model"
+)
+
+// structuredSchemas reports whether the model only accepts the schemas in
+// the structured genai.Schema form, and ignores the JSON Schema fields, e.g.
+// FunctionDeclaration.ParametersJsonSchema: the Gemini 1.x models, and the
+// models served by Vertex AI endpoints, such as the tuned models.
+func structuredSchemas(backend genai.Backend, modelName string) bool {
+	if backend == genai.BackendVertexAI && strings.Contains(modelName, "/endpoints/") {
+		return true
+	}
+	name := modelName[strings.LastIndex(modelName, "/")+1:]
+	return strings.HasPrefix(name, "gemini-1.")
+}
+
+// withStructuredSchemas returns the request with the JSON Schemas of its
+// function declarations and of its response converted to genai.Schema, if
+// the model requires it. The conversion warnings are logged once per model.
+func (m *geminiModel) withStructuredSchemas(req *model.LLMRequest) *model.LLMRequest {
+	if !m.structuredSchemas || req.Config == nil {
+		return req
+	}
+	cfg := *req.Config
+	changed := false
+	if cfg.ResponseJsonSchema != nil && cfg.ResponseSchema == nil {
+		cfg.ResponseSchema = m.convertSchema("response", cfg.ResponseJsonSchema)
+		cfg.ResponseJsonSchema = nil
+		changed = true
+	}
+	toolsCopied := false
+	for i, t := range cfg.Tools {
+		if t == nil || !slices.ContainsFunc(t.FunctionDeclarations, hasJSONSchemas) {
+			continue
+		}
+		// The tools and the declarations may be shared with other requests.
+		if !toolsCopied {
+			cfg.Tools = slices.Clone(cfg.Tools)
+			toolsCopied = true
+		}
+		copied := *t
+		copied.FunctionDeclarations = make([]*genai.FunctionDeclaration, len(t.FunctionDeclarations))
+		for j, decl := range t.FunctionDeclarations {
+			copied.FunctionDeclarations[j] = m.structuredDeclaration(decl)
+		}
+		cfg.Tools[i] = &copied
+		changed = true
+	}
+	if !changed {
+		return req
+	}
+	copied := *req
+	copied.Config = &cfg
+	return &copied
+}
+
+func hasJSONSchemas(decl *genai.FunctionDeclaration) bool {
+	return decl != nil && (decl.ParametersJsonSchema != nil || decl.ResponseJsonSchema != nil)
+}
+
+// structuredDeclaration returns the declaration with its JSON Schemas
+// converted to genai.Schema.
+func (m *geminiModel) structuredDeclaration(decl *genai.FunctionDeclaration) *genai.FunctionDeclaration {
+	if !hasJSONSchemas(decl) {
+		return decl
+	}
+	d := *decl
+	if d.ParametersJsonSchema != nil && d.Parameters == nil {
+		d.Parameters = m.convertSchema("parameters of tool "+d.Name, d.ParametersJsonSchema)
+	}
+	if d.ResponseJsonSchema != nil && d.Response == nil {
+		d.Response = m.convertSchema("response of tool "+d.Name, d.ResponseJsonSchema)
+	}
+	d.ParametersJsonSchema, d.ResponseJsonSchema = nil, nil
+	return &d
+}
+
+// convertSchema converts a JSON Schema to genai.Schema, logging the
+// warnings.
+func (m *geminiModel) convertSchema(what string, schema any) *genai.Schema {
+	var s map[string]any
+	b, err := json.Marshal(schema)
+	if err == nil {
+		err = json.Unmarshal(b, &s)
+	}
+	if err != nil {
+		m.logSchemaWarning(what, "the schema is not a JSON object, replaced with an empty schema: "+err.Error())
+		return &genai.Schema{}
+	}
+	converted, warnings, err := typeutil.GenaiSchema(s)
+	if err != nil {
+		m.logSchemaWarning(what, "the schema is not a JSON Schema, replaced with an empty schema: "+err.Error())
+		return &genai.Schema{}
+	}
+	for _, w := range warnings {
+		m.logSchemaWarning(what, w)
+	}
+	return converted
+}
+
+func (m *geminiModel) logSchemaWarning(what, warning string) {
+	msg := what + ": " + warning
+	if _, logged := m.schemaWarnings.LoadOrStore(msg, true); !logged {
+		log.Printf("Model %s does not support JSON Schema, converting the %s", m.name, msg)
+	}
+}
@@ -0,0 +1,94 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gemini
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/model"
+)
+
+func TestStructuredSchemas(t *testing.T) {
+	tests := []struct {
+		backend genai.Backend
+		model   string
+		want    bool
+	}{
+		{genai.BackendGeminiAPI, "gemini-2.5-flash", false},
+		{genai.BackendGeminiAPI, "gemini-2.0-flash", false},
+		{genai.BackendGeminiAPI, "gemini-1.5-pro", true},
+		{genai.BackendVertexAI, "models/gemini-1.5-flash-002", true},
+		{genai.BackendVertexAI, "gemini-2.5-pro", false},
+		{genai.BackendVertexAI, "projects/p/locations/us-central1/endpoints/123", true},
+	}
+	for _, tc := range tests {
+		if got := structuredSchemas(tc.backend, tc.model); got != tc.want {
+			t.Errorf("structuredSchemas(%v, %q) = %v, want %v", tc.backend, tc.model, got, tc.want)
+		}
+	}
+}
+
+func TestWithStructuredSchemas(t *testing.T) {
+	jsonSchema := map[string]any{
+		"type":       "object",
+		"properties": map[string]any{"city": map[string]any{"type": "string"}},
+		"required":   []string{"city"},
+	}
+	structured := &genai.Schema{
+		Type:       genai.TypeObject,
+		Properties: map[string]*genai.Schema{"city": {Type: genai.TypeString}},
+		Required:   []string{"city"},
+	}
+	newRequest := func() *model.LLMRequest {
+		return &model.LLMRequest{Config: &genai.GenerateContentConfig{
+			ResponseJsonSchema: jsonSchema,
+			Tools: []*genai.Tool{
+				{GoogleSearch: &genai.GoogleSearch{}},
+				{FunctionDeclarations: []*genai.FunctionDeclaration{
+					{Name: "get_weather", ParametersJsonSchema: jsonSchema, ResponseJsonSchema: jsonSchema},
+					{Name: "get_time", Parameters: &genai.Schema{Type: genai.TypeObject}},
+				}},
+			},
+		}}
+	}
+
+	req := newRequest()
+	m := &geminiModel{name: "gemini-1.5-flash", structuredSchemas: true}
+	got := m.withStructuredSchemas(req)
+	want := &model.LLMRequest{Config: &genai.GenerateContentConfig{
+		ResponseSchema: structured,
+		Tools: []*genai.Tool{
+			{GoogleSearch: &genai.GoogleSearch{}},
+			{FunctionDeclarations: []*genai.FunctionDeclaration{
+				{Name: "get_weather", Parameters: structured, Response: structured},
+				{Name: "get_time", Parameters: &genai.Schema{Type: genai.TypeObject}},
+			}},
+		},
+	}}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("withStructuredSchemas() mismatch (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff(newRequest(), req); diff != "" {
+		t.Errorf("withStructuredSchemas() modified the request (-want +got):\n%s", diff)
+	}
+
+	m = &geminiModel{name: "gemini-2.5-flash"}
+	if got := m.withStructuredSchemas(req); got != req {
+		t.Errorf("withStructuredSchemas() changed the request of a model accepting JSON Schema")
+	}
+}
@@ -0,0 +1,62 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"context"
+	"iter"
+
+	"google.golang.org/genai"
+)
+
+// LiveLLM is implemented by the models supporting live, bidirectional
+// streaming, e.g. of audio, as the Gemini Live API.
+type LiveLLM interface {
+	LLM
+	// ConnectLive opens a live connection configured by the request: its
+	// config, its live config and its tools. The contents of the request are
+	// sent as the history of the conversation.
+	ConnectLive(ctx context.Context, req *LLMRequest) (LiveConnection, error)
+}
+
+// LiveConnection is a live connection to a model.
+type LiveConnection interface {
+	// Send sends a request to the model. It may be called concurrently with
+	// Receive.
+	Send(req *LiveRequest) error
+	// Receive yields the responses of the model until the connection is
+	// closed. The text and the audio are streamed in partial responses;
+	// the text and the transcriptions are then aggregated in complete
+	// responses, and the end of each turn of the model is marked by a
+	// response with TurnComplete.
+	Receive() iter.Seq2[*LLMResponse, error]
+	// Close closes the connection, ending Receive.
+	Close() error
+}
+
+// LiveRequest is a request sent on a live connection. Only one of its fields
+// is set.
+type LiveRequest struct {
+	// Content is a turn of the conversation, e.g. a text message or function
+	// responses.
+	Content *genai.Content
+	// Blob is a chunk of realtime input, e.g. audio or video.
+	Blob *genai.Blob
+	// ActivityStart and ActivityEnd mark the start and the end of the user
+	// activity, e.g. speech, when the automatic activity detection of the
+	// model is disabled.
+	ActivityStart bool
+	ActivityEnd   bool
+}
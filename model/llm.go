@@ -35,6 +35,11 @@ type LLMRequest struct {
 	Config   *genai.GenerateContentConfig
 
 	Tools map[string]any `json:"-"`
+
+	// LiveConnectConfig is the config of the live connections, see LiveLLM.
+	// Its fields set in Config, e.g. the system instruction or the tools, are
+	// taken from Config.
+	LiveConnectConfig *genai.LiveConnectConfig `json:"-"`
 }
 
 // LLMResponse is the raw LLM response.
@@ -46,6 +51,10 @@ type LLMResponse struct {
 	UsageMetadata     *genai.GenerateContentResponseUsageMetadata
 	CustomMetadata    map[string]any
 	LogprobsResult    *genai.LogprobsResult
+	// InputTranscription and OutputTranscription are the transcriptions of
+	// the audio of the user and of the model, on live connections.
+	InputTranscription  *genai.Transcription
+	OutputTranscription *genai.Transcription
 	// Partial indicates whether the content is part of a unfinished content stream.
 	// Only used for streaming mode and when the content is plain text.
 	Partial bool
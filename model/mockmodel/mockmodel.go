@@ -0,0 +1,182 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package mockmodel provides deterministic models to unit test agents and
+// tools without calling a real model, e.g. in CI without API keys.
+//
+// [New] returns a model answering with scripted responses, in order, and
+// [NewFunc] a model computing them from the requests. Both record the
+// requests, see [Model.Requests].
+//
+// [Replay] returns a model replaying the responses of a real model recorded
+// in golden files, keyed by a hash of the request, see cachemodel.Key.
+// [ReplayOrRecord] records them when the environment variable RecordEnv is
+// set, e.g.
+//
+//	ADK_RECORD_MODEL=1 go test ./...
+package mockmodel
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"iter"
+	"os"
+	"sync"
+
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/model"
+	"google.golang.org/adk/model/cachemodel"
+)
+
+// ErrNoResponse is returned by the calls of a model of New once its scripted
+// responses are exhausted.
+var ErrNoResponse = errors.New("no scripted response left")
+
+// ErrNotRecorded is returned by the calls of a model of Replay for the
+// requests without a recorded response.
+var ErrNotRecorded = errors.New("no recorded response")
+
+// RecordEnv is the environment variable making ReplayOrRecord record the
+// responses of the real model.
+const RecordEnv = "ADK_RECORD_MODEL"
+
+// Model is a model whose responses are scripted. It is safe for concurrent
+// use.
+type Model struct {
+	respond func(ctx context.Context, req *model.LLMRequest) (*model.LLMResponse, error)
+
+	mu       sync.Mutex
+	requests []*model.LLMRequest
+}
+
+// New returns a model answering each call with the next response, and with
+// ErrNoResponse once they are exhausted.
+func New(responses ...*model.LLMResponse) *Model {
+	var mu sync.Mutex
+	return NewFunc(func(ctx context.Context, req *model.LLMRequest) (*model.LLMResponse, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		if len(responses) == 0 {
+			return nil, ErrNoResponse
+		}
+		resp := responses[0]
+		responses = responses[1:]
+		return resp, nil
+	})
+}
+
+// NewFunc returns a model answering each call with the response of the
+// function.
+func NewFunc(respond func(ctx context.Context, req *model.LLMRequest) (*model.LLMResponse, error)) *Model {
+	return &Model{respond: respond}
+}
+
+// Text returns a model response with the text.
+func Text(text string) *model.LLMResponse {
+	return &model.LLMResponse{Content: genai.NewContentFromText(text, genai.RoleModel)}
+}
+
+// FunctionCall returns a model response calling the function.
+func FunctionCall(name string, args map[string]any) *model.LLMResponse {
+	return &model.LLMResponse{Content: genai.NewContentFromFunctionCall(name, args, genai.RoleModel)}
+}
+
+// Name returns "mock".
+func (m *Model) Name() string {
+	return "mock"
+}
+
+// GenerateContent records the request and yields the scripted response,
+// whether the call streams or not.
+func (m *Model) GenerateContent(ctx context.Context, req *model.LLMRequest, stream bool) iter.Seq2[*model.LLMResponse, error] {
+	return func(yield func(*model.LLMResponse, error) bool) {
+		m.mu.Lock()
+		m.requests = append(m.requests, req)
+		m.mu.Unlock()
+		resp, err := m.respond(ctx, req)
+		if err != nil {
+			yield(nil, err)
+			return
+		}
+		yield(resp, nil)
+	}
+}
+
+// Requests returns the requests of the calls so far, in order.
+func (m *Model) Requests() []*model.LLMRequest {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]*model.LLMRequest(nil), m.requests...)
+}
+
+// Replay returns a model replaying the responses of the named model recorded
+// in the directory, see Record. The calls whose request was not recorded
+// fail with ErrNotRecorded.
+func Replay(dir, name string) (model.LLM, error) {
+	cache, err := cachemodel.NewDiskCache(dir)
+	if err != nil {
+		return nil, err
+	}
+	return cachemodel.WithCache(notRecorded(name), cache), nil
+}
+
+// Record returns a model calling m, and recording its responses in the
+// directory, one JSON file per request, for Replay. The responses already
+// recorded are replayed.
+func Record(m model.LLM, dir string) (model.LLM, error) {
+	cache, err := cachemodel.NewDiskCache(dir)
+	if err != nil {
+		return nil, err
+	}
+	return cachemodel.WithCache(m, cache), nil
+}
+
+// ReplayOrRecord returns the model of Record if the environment variable
+// RecordEnv is set, creating the real model with newModel, and otherwise the
+// model of Replay, without calling newModel.
+func ReplayOrRecord(dir, name string, newModel func() (model.LLM, error)) (model.LLM, error) {
+	if os.Getenv(RecordEnv) == "" {
+		return Replay(dir, name)
+	}
+	m, err := newModel()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the recorded model: %w", err)
+	}
+	if m.Name() != name {
+		return nil, fmt.Errorf("recorded model is named %q, want %q", m.Name(), name)
+	}
+	return Record(m, dir)
+}
+
+// notRecorded is the model called by Replay on the cache misses.
+type notRecorded string
+
+func (m notRecorded) Name() string {
+	return string(m)
+}
+
+func (m notRecorded) GenerateContent(ctx context.Context, req *model.LLMRequest, stream bool) iter.Seq2[*model.LLMResponse, error] {
+	return func(yield func(*model.LLMResponse, error) bool) {
+		key, err := cachemodel.Key(string(m), req, stream)
+		if err != nil {
+			yield(nil, err)
+			return
+		}
+		yield(nil, fmt.Errorf("model %s: request %s: %w; set %s=1 to record it", m, key, ErrNotRecorded, RecordEnv))
+	}
+}
+
+var _ model.LLM = (*Model)(nil)
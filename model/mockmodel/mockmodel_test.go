@@ -0,0 +1,142 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mockmodel_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/agent/llmagent"
+	"google.golang.org/adk/internal/testutil"
+	"google.golang.org/adk/model"
+	"google.golang.org/adk/model/mockmodel"
+	"google.golang.org/adk/tool"
+	"google.golang.org/adk/tool/functiontool"
+)
+
+func TestNew(t *testing.T) {
+	type args struct {
+		City string `json:"city"`
+	}
+	weather, err := functiontool.New(functiontool.Config{Name: "get_weather", Description: "Returns the weather."},
+		func(ctx tool.Context, a args) (string, error) { return "sunny in " + a.City, nil })
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := mockmodel.New(
+		mockmodel.FunctionCall("get_weather", map[string]any{"city": "Paris"}),
+		mockmodel.Text("It is sunny."),
+	)
+	a, err := llmagent.New(llmagent.Config{Name: "weather_agent", Model: m, Tools: []tool.Tool{weather}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	events, err := testutil.CollectEvents(testutil.NewTestAgentRunner(t, a).Run(t, "session", "Weather in Paris?"))
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if got := events[len(events)-1].Content.Parts[0].Text; got != "It is sunny." {
+		t.Errorf("got final response %q, want %q", got, "It is sunny.")
+	}
+	reqs := m.Requests()
+	if len(reqs) != 2 {
+		t.Fatalf("got %d requests, want 2", len(reqs))
+	}
+	last := reqs[1].Contents[len(reqs[1].Contents)-1].Parts[0].FunctionResponse
+	if diff := cmp.Diff(map[string]any{"result": "sunny in Paris"}, last.Response); diff != "" {
+		t.Errorf("function response mismatch (-want +got):\n%s", diff)
+	}
+
+	for _, err := range m.GenerateContent(t.Context(), &model.LLMRequest{}, false) {
+		if !errors.Is(err, mockmodel.ErrNoResponse) {
+			t.Errorf("GenerateContent() error = %v, want ErrNoResponse", err)
+		}
+	}
+}
+
+func TestNewFunc(t *testing.T) {
+	m := mockmodel.NewFunc(func(ctx context.Context, req *model.LLMRequest) (*model.LLMResponse, error) {
+		return mockmodel.Text("echo: " + req.Contents[0].Parts[0].Text), nil
+	})
+	for _, stream := range []bool{false, true} {
+		req := &model.LLMRequest{Contents: []*genai.Content{genai.NewContentFromText("hi", genai.RoleUser)}}
+		for resp, err := range m.GenerateContent(t.Context(), req, stream) {
+			if err != nil || resp.Content.Parts[0].Text != "echo: hi" {
+				t.Errorf("GenerateContent(stream=%v) = %v, %v, want echo: hi", stream, resp, err)
+			}
+		}
+	}
+}
+
+func generate(t *testing.T, m model.LLM, text string) (string, error) {
+	t.Helper()
+	req := &model.LLMRequest{Contents: []*genai.Content{genai.NewContentFromText(text, genai.RoleUser)}}
+	var got string
+	for resp, err := range m.GenerateContent(t.Context(), req, false) {
+		if err != nil {
+			return "", err
+		}
+		got += resp.Content.Parts[0].Text
+	}
+	return got, nil
+}
+
+func TestReplayOrRecord(t *testing.T) {
+	dir := t.TempDir()
+	upstream := &testutil.MockModel{Responses: []*genai.Content{genai.NewContentFromText("recorded", genai.RoleModel)}}
+	newModel := func() (model.LLM, error) { return mockmodel.NewFunc(upstream.Generate), nil }
+
+	m, err := mockmodel.ReplayOrRecord(dir, "mock", newModel)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := generate(t, m, "q"); !errors.Is(err, mockmodel.ErrNotRecorded) {
+		t.Fatalf("replay error = %v, want ErrNotRecorded", err)
+	}
+
+	t.Setenv(mockmodel.RecordEnv, "1")
+	m, err = mockmodel.ReplayOrRecord(dir, "mock", newModel)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, err := generate(t, m, "q"); err != nil || got != "recorded" {
+		t.Fatalf("record = %q, %v, want recorded", got, err)
+	}
+	if _, err := mockmodel.ReplayOrRecord(dir, "other", newModel); err == nil {
+		t.Errorf("ReplayOrRecord() with another model name succeeded, want error")
+	}
+
+	t.Setenv(mockmodel.RecordEnv, "")
+	m, err = mockmodel.ReplayOrRecord(dir, "mock", newModel)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for range 2 {
+		if got, err := generate(t, m, "q"); err != nil || got != "recorded" {
+			t.Errorf("replay = %q, %v, want recorded", got, err)
+		}
+	}
+	if _, err := generate(t, m, "other question"); !errors.Is(err, mockmodel.ErrNotRecorded) {
+		t.Errorf("replay of another request error = %v, want ErrNotRecorded", err)
+	}
+	if len(upstream.Requests) != 1 {
+		t.Errorf("real model called %d times, want 1", len(upstream.Requests))
+	}
+}
@@ -0,0 +1,249 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package ollama implements the [model.LLM] interface for the local models
+// served by Ollama, or by any OpenAI-compatible local endpoint, e.g. to run
+// agents offline while developing or testing them.
+//
+// The models are called through the OpenAI-compatible API of the server, see
+// package [openai]. For the models without native tool support, the tools can
+// be declared in the system instruction instead, see Config.PromptTools.
+package ollama
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"iter"
+	"maps"
+	"net/http"
+	"slices"
+	"strings"
+
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/model"
+	"google.golang.org/adk/model/openai"
+	"google.golang.org/adk/tool"
+	"google.golang.org/adk/tool/functiontool"
+)
+
+// DefaultBaseURL is the base URL of the OpenAI-compatible API of a local
+// Ollama server.
+const DefaultBaseURL = "http://localhost:11434/v1"
+
+// Config is the config of a local model.
+type Config struct {
+	// BaseURL is the base URL of the OpenAI-compatible API of the server.
+	// Defaults to DefaultBaseURL.
+	BaseURL string
+	// HTTPClient sends the requests. Defaults to http.DefaultClient.
+	HTTPClient *http.Client
+	// PromptTools enables the function calling of the models without native
+	// tool support, on a best-effort basis: the declarations of the tools are
+	// appended to the system instruction, the model is asked to answer in
+	// JSON mode, either with the tool calls or with its answer, and the
+	// function calls and responses of the history are sent as text. The
+	// responses are not streamed when the request has tools.
+	PromptTools bool
+}
+
+type localModel struct {
+	model.LLM
+	promptTools bool
+}
+
+// NewModel returns [model.LLM], backed by a local model. The modelName
+// specifies which model to target (e.g., "llama3.2").
+func NewModel(ctx context.Context, modelName string, cfg *Config) (model.LLM, error) {
+	if cfg == nil {
+		cfg = &Config{}
+	}
+	baseURL := cfg.BaseURL
+	if baseURL == "" {
+		baseURL = DefaultBaseURL
+	}
+	// The API key is ignored by the local servers. It is set, so that the
+	// OPENAI_API_KEY of the environment is not sent to them.
+	m, err := openai.NewModel(ctx, modelName, &openai.Config{BaseURL: baseURL, APIKey: "ollama", HTTPClient: cfg.HTTPClient})
+	if err != nil {
+		return nil, err
+	}
+	return &localModel{LLM: m, promptTools: cfg.PromptTools}, nil
+}
+
+// GenerateContent calls the underlying model.
+func (m *localModel) GenerateContent(ctx context.Context, req *model.LLMRequest, stream bool) iter.Seq2[*model.LLMResponse, error] {
+	if !m.promptTools || len(req.Tools) == 0 {
+		return m.LLM.GenerateContent(ctx, req, stream)
+	}
+	return func(yield func(*model.LLMResponse, error) bool) {
+		promptReq, err := promptToolsRequest(req)
+		if err != nil {
+			yield(nil, err)
+			return
+		}
+		// The responses in JSON mode are not streamed, as their text is not
+		// the answer.
+		for resp, err := range m.LLM.GenerateContent(ctx, promptReq, false) {
+			if err == nil {
+				resp = parseToolCalls(resp)
+			}
+			if !yield(resp, err) {
+				return
+			}
+		}
+	}
+}
+
+// promptReply is the JSON reply the model is asked for when the tools are
+// declared in the system instruction.
+type promptReply struct {
+	ToolCalls []promptToolCall `json:"tool_calls,omitempty"`
+	Answer    any              `json:"answer,omitempty"`
+}
+
+type promptToolCall struct {
+	Name      string         `json:"name"`
+	Arguments map[string]any `json:"arguments"`
+}
+
+const toolsInstruction = `You can use the following tools, described with their JSON schemas:
+
+%s
+
+Reply with a JSON object only, with no other text:
+- to call tools, reply {"tool_calls": [{"name": "<tool name>", "arguments": {<arguments matching the parameters of the tool>}}]}; the results of the tools will be given to you in the next message;
+- to answer, reply {"answer": <your answer>}.`
+
+// promptToolsRequest returns a copy of the request, with the tools declared
+// in the system instruction, and the function calls and responses as text.
+func promptToolsRequest(req *model.LLMRequest) (*model.LLMRequest, error) {
+	names := slices.Sorted(maps.Keys(req.Tools))
+	var decls []string
+	for _, name := range names {
+		t, ok := req.Tools[name].(tool.Tool)
+		if !ok {
+			return nil, fmt.Errorf("tool %q is not a tool.Tool", name)
+		}
+		decl, err := functiontool.OpenAIDeclaration(t)
+		if err != nil {
+			return nil, err
+		}
+		decls = append(decls, string(decl))
+	}
+
+	cfg := &genai.GenerateContentConfig{}
+	if req.Config != nil {
+		copied := *req.Config
+		cfg = &copied
+	}
+	var instruction []*genai.Part
+	if cfg.SystemInstruction != nil {
+		instruction = slices.Clone(cfg.SystemInstruction.Parts)
+	}
+	instruction = append(instruction, genai.NewPartFromText(fmt.Sprintf(toolsInstruction, strings.Join(decls, "\n"))))
+	cfg.SystemInstruction = &genai.Content{Role: genai.RoleUser, Parts: instruction}
+	if cfg.ResponseSchema == nil && cfg.ResponseJsonSchema == nil {
+		cfg.ResponseMIMEType = "application/json"
+	}
+
+	contents := make([]*genai.Content, 0, len(req.Contents))
+	for _, content := range req.Contents {
+		textContent, err := toolsAsText(content)
+		if err != nil {
+			return nil, err
+		}
+		contents = append(contents, textContent)
+	}
+	return &model.LLMRequest{Model: req.Model, Contents: contents, Config: cfg}, nil
+}
+
+// toolsAsText returns the content with its function calls and responses
+// replaced by text: the calls in the JSON reply format, and the responses
+// with the name of their tool.
+func toolsAsText(content *genai.Content) (*genai.Content, error) {
+	if content == nil {
+		return nil, nil
+	}
+	var calls []promptToolCall
+	var parts []*genai.Part
+	for _, part := range content.Parts {
+		switch {
+		case part.FunctionCall != nil:
+			calls = append(calls, promptToolCall{Name: part.FunctionCall.Name, Arguments: part.FunctionCall.Args})
+		case part.FunctionResponse != nil:
+			response, err := json.Marshal(part.FunctionResponse.Response)
+			if err != nil {
+				return nil, fmt.Errorf("failed to encode the response of function %q: %w", part.FunctionResponse.Name, err)
+			}
+			parts = append(parts, genai.NewPartFromText(fmt.Sprintf("Result of tool %s: %s", part.FunctionResponse.Name, response)))
+		default:
+			parts = append(parts, part)
+		}
+	}
+	if len(calls) > 0 {
+		reply, err := json.Marshal(promptReply{ToolCalls: calls})
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode the function calls: %w", err)
+		}
+		parts = append(parts, genai.NewPartFromText(string(reply)))
+	}
+	return &genai.Content{Role: content.Role, Parts: parts}, nil
+}
+
+// parseToolCalls translates the JSON reply of the model to function calls or
+// to its answer. The replies which are not in the format are kept as is.
+func parseToolCalls(resp *model.LLMResponse) *model.LLMResponse {
+	if resp == nil || resp.Content == nil {
+		return resp
+	}
+	var texts []string
+	for _, part := range resp.Content.Parts {
+		if part.Text != "" && !part.Thought {
+			texts = append(texts, part.Text)
+		}
+	}
+	var reply promptReply
+	if err := json.Unmarshal([]byte(strings.Join(texts, "")), &reply); err != nil {
+		return resp
+	}
+
+	var parts []*genai.Part
+	for _, call := range reply.ToolCalls {
+		if call.Name == "" {
+			continue
+		}
+		// The IDs are populated by the agents.
+		parts = append(parts, &genai.Part{FunctionCall: &genai.FunctionCall{Name: call.Name, Args: call.Arguments}})
+	}
+	if len(parts) == 0 {
+		switch answer := reply.Answer.(type) {
+		case nil:
+			return resp
+		case string:
+			parts = append(parts, genai.NewPartFromText(answer))
+		default:
+			// E.g. the answers conforming to an output schema.
+			b, err := json.Marshal(answer)
+			if err != nil {
+				return resp
+			}
+			parts = append(parts, genai.NewPartFromText(string(b)))
+		}
+	}
+	parsed := *resp
+	parsed.Content = &genai.Content{Role: resp.Content.Role, Parts: parts}
+	return &parsed
+}
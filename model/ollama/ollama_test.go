@@ -0,0 +1,179 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ollama_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/model"
+	"google.golang.org/adk/model/ollama"
+	"google.golang.org/adk/tool"
+	"google.golang.org/adk/tool/functiontool"
+)
+
+// newServer returns a server answering the chat completions with the text,
+// and the decoded request it received.
+func newServer(t *testing.T, text string) (*httptest.Server, *map[string]any) {
+	t.Helper()
+	var got map[string]any
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/chat/completions" {
+			t.Errorf("path = %q, want /v1/chat/completions", r.URL.Path)
+		}
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Error(err)
+		}
+		json.NewEncoder(w).Encode(map[string]any{
+			"choices": []any{map[string]any{"message": map[string]any{"content": text}, "finish_reason": "stop"}},
+		})
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &got
+}
+
+func newRequest(t *testing.T) *model.LLMRequest {
+	t.Helper()
+	weather, err := functiontool.New(functiontool.Config{Name: "get_weather", Description: "Returns the weather."},
+		func(tool.Context, struct {
+			City string `json:"city"`
+		}) (string, error) {
+			return "sunny", nil
+		})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &model.LLMRequest{
+		Contents: []*genai.Content{
+			genai.NewContentFromText("Weather in Paris?", genai.RoleUser),
+			genai.NewContentFromFunctionCall("get_weather", map[string]any{"city": "Paris"}, genai.RoleModel),
+			genai.NewContentFromFunctionResponse("get_weather", map[string]any{"result": "sunny"}, genai.RoleUser),
+		},
+		Config: &genai.GenerateContentConfig{SystemInstruction: genai.NewContentFromText("Be brief.", genai.RoleUser)},
+		Tools:  map[string]any{"get_weather": weather},
+	}
+}
+
+func generate(t *testing.T, m model.LLM, req *model.LLMRequest, stream bool) []*model.LLMResponse {
+	t.Helper()
+	var responses []*model.LLMResponse
+	for resp, err := range m.GenerateContent(t.Context(), req, stream) {
+		if err != nil {
+			t.Fatalf("GenerateContent() error = %v", err)
+		}
+		responses = append(responses, resp)
+	}
+	return responses
+}
+
+func TestModel_PromptTools(t *testing.T) {
+	for _, tc := range []struct {
+		name  string
+		reply string
+		want  *genai.Content
+	}{
+		{
+			name:  "tool calls",
+			reply: `{"tool_calls": [{"name": "get_weather", "arguments": {"city": "Rome"}}]}`,
+			want:  genai.NewContentFromFunctionCall("get_weather", map[string]any{"city": "Rome"}, genai.RoleModel),
+		},
+		{
+			name:  "answer",
+			reply: `{"answer": "Sunny."}`,
+			want:  genai.NewContentFromText("Sunny.", genai.RoleModel),
+		},
+		{
+			name:  "structured answer",
+			reply: `{"answer": {"weather": "sunny"}}`,
+			want:  genai.NewContentFromText(`{"weather":"sunny"}`, genai.RoleModel),
+		},
+		{
+			name:  "not in the format",
+			reply: `Sunny.`,
+			want:  genai.NewContentFromText("Sunny.", genai.RoleModel),
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			srv, got := newServer(t, tc.reply)
+			m, err := ollama.NewModel(t.Context(), "llama-test", &ollama.Config{BaseURL: srv.URL + "/v1", PromptTools: true})
+			if err != nil {
+				t.Fatal(err)
+			}
+			req := newRequest(t)
+
+			responses := generate(t, m, req, true)
+
+			if len(responses) != 1 {
+				t.Fatalf("got %d responses, want 1", len(responses))
+			}
+			if diff := cmp.Diff(tc.want, responses[0].Content); diff != "" {
+				t.Errorf("content mismatch (-want +got):\n%s", diff)
+			}
+
+			if (*got)["tools"] != nil || (*got)["stream"] != nil {
+				t.Errorf("request has tools %v and stream %v, want neither", (*got)["tools"], (*got)["stream"])
+			}
+			if diff := cmp.Diff(map[string]any{"type": "json_object"}, (*got)["response_format"]); diff != "" {
+				t.Errorf("response_format mismatch (-want +got):\n%s", diff)
+			}
+			messages := (*got)["messages"].([]any)
+			system := messages[0].(map[string]any)["content"].(string)
+			if !strings.HasPrefix(system, "Be brief.\n") || !strings.Contains(system, `"name":"get_weather"`) {
+				t.Errorf("system message = %q, want the instruction and the declaration of the tool", system)
+			}
+			wantHistory := []any{
+				map[string]any{"role": "user", "content": "Weather in Paris?"},
+				map[string]any{"role": "assistant", "content": `{"tool_calls":[{"name":"get_weather","arguments":{"city":"Paris"}}]}`},
+				map[string]any{"role": "user", "content": `Result of tool get_weather: {"result":"sunny"}`},
+			}
+			if diff := cmp.Diff(wantHistory, messages[1:]); diff != "" {
+				t.Errorf("history mismatch (-want +got):\n%s", diff)
+			}
+			// The request of the agent is left unchanged.
+			if len(req.Config.SystemInstruction.Parts) != 1 || req.Config.ResponseMIMEType != "" || req.Contents[1].Parts[0].FunctionCall == nil {
+				t.Error("request of the agent was modified")
+			}
+		})
+	}
+}
+
+func TestModel_NativeTools(t *testing.T) {
+	srv, got := newServer(t, "Sunny.")
+	m, err := ollama.NewModel(t.Context(), "llama-test", &ollama.Config{BaseURL: srv.URL + "/v1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.Name() != "llama-test" {
+		t.Errorf("Name() = %q, want llama-test", m.Name())
+	}
+
+	responses := generate(t, m, newRequest(t), false)
+
+	if diff := cmp.Diff(genai.NewContentFromText("Sunny.", genai.RoleModel), responses[0].Content); diff != "" {
+		t.Errorf("content mismatch (-want +got):\n%s", diff)
+	}
+	if tools, _ := (*got)["tools"].([]any); len(tools) != 1 {
+		t.Errorf("request tools = %v, want the native declaration", (*got)["tools"])
+	}
+	if (*got)["response_format"] != nil {
+		t.Errorf("response_format = %v, want none", (*got)["response_format"])
+	}
+}
@@ -0,0 +1,585 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package openai implements the [model.LLM] interface for the models served
+// by the OpenAI Chat Completions API, and by the OpenAI-compatible endpoints,
+// e.g. of vLLM, Ollama or Groq.
+//
+// The requests are translated from the Gemini types of the ADK: the system
+// instruction becomes a system message, the function calls and responses
+// become tool calls and tool messages, the tools of the request are declared
+// with [functiontool.OpenAIDeclaration], and the output schema becomes a JSON
+// schema response format. The tools which are not function tools, e.g. the
+// Gemini built-in tools, are not supported.
+package openai
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+	"net/http"
+	"os"
+	"runtime"
+	"slices"
+	"strings"
+
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/internal/utils"
+	"google.golang.org/adk/internal/version"
+	"google.golang.org/adk/model"
+	"google.golang.org/adk/tool"
+	"google.golang.org/adk/tool/functiontool"
+)
+
+// DefaultBaseURL is the base URL of the OpenAI API.
+const DefaultBaseURL = "https://api.openai.com/v1"
+
+// Config is the config of an OpenAI model.
+type Config struct {
+	// BaseURL is the base URL of the API, e.g. http://localhost:8000/v1 for
+	// a local vLLM server. Defaults to DefaultBaseURL.
+	BaseURL string
+	// APIKey is sent as a bearer token. Defaults to the OPENAI_API_KEY
+	// environment variable. No token is sent if it is empty.
+	APIKey string
+	// HTTPClient sends the requests. Defaults to http.DefaultClient.
+	HTTPClient *http.Client
+	// Headers are added to the requests.
+	Headers http.Header
+}
+
+type openAIModel struct {
+	name               string
+	url                string
+	apiKey             string
+	client             *http.Client
+	headers            http.Header
+	versionHeaderValue string
+}
+
+// NewModel returns [model.LLM], backed by the OpenAI Chat Completions API or
+// an endpoint compatible with it. The modelName specifies which model to
+// target (e.g., "gpt-4o").
+func NewModel(ctx context.Context, modelName string, cfg *Config) (model.LLM, error) {
+	if modelName == "" {
+		return nil, errors.New("model name is required")
+	}
+	if cfg == nil {
+		cfg = &Config{}
+	}
+	baseURL := cfg.BaseURL
+	if baseURL == "" {
+		baseURL = DefaultBaseURL
+	}
+	apiKey := cfg.APIKey
+	if apiKey == "" {
+		apiKey = os.Getenv("OPENAI_API_KEY")
+	}
+	client := cfg.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	return &openAIModel{
+		name:    modelName,
+		url:     strings.TrimSuffix(baseURL, "/") + "/chat/completions",
+		apiKey:  apiKey,
+		client:  client,
+		headers: cfg.Headers,
+		versionHeaderValue: fmt.Sprintf("google-adk/%s gl-go/%s", version.Version,
+			strings.TrimPrefix(runtime.Version(), "go")),
+	}, nil
+}
+
+func (m *openAIModel) Name() string {
+	return m.name
+}
+
+// GenerateContent calls the underlying model.
+func (m *openAIModel) GenerateContent(ctx context.Context, req *model.LLMRequest, stream bool) iter.Seq2[*model.LLMResponse, error] {
+	return func(yield func(*model.LLMResponse, error) bool) {
+		chatReq, err := m.chatRequest(req, stream)
+		if err != nil {
+			yield(nil, err)
+			return
+		}
+		body, err := m.send(ctx, chatReq)
+		if err != nil {
+			yield(nil, err)
+			return
+		}
+		defer body.Close()
+
+		if !stream {
+			var resp chatResponse
+			if err := json.NewDecoder(body).Decode(&resp); err != nil {
+				yield(nil, fmt.Errorf("failed to decode the model response: %w", err))
+				return
+			}
+			yield(resp.llmResponse())
+			return
+		}
+		for resp, err := range readStream(body) {
+			if !yield(resp, err) {
+				return
+			}
+		}
+	}
+}
+
+// send posts the request and returns the body of the response.
+func (m *openAIModel) send(ctx context.Context, chatReq *chatRequest) (io.ReadCloser, error) {
+	payload, err := json.Marshal(chatReq)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode the model request: %w", err)
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, m.url, bytes.NewReader(payload))
+	if err != nil {
+		return nil, err
+	}
+	for name, values := range m.headers {
+		for _, v := range values {
+			httpReq.Header.Add(name, v)
+		}
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("User-Agent", m.versionHeaderValue)
+	if m.apiKey != "" {
+		httpReq.Header.Set("Authorization", "Bearer "+m.apiKey)
+	}
+
+	resp, err := m.client.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call model: %w", err)
+	}
+	if resp.StatusCode/100 != 2 {
+		defer resp.Body.Close()
+		return nil, fmt.Errorf("failed to call model: %w", utils.NewAPIError(resp))
+	}
+	return resp.Body, nil
+}
+
+// chatRequest translates the request to a chat completion request.
+func (m *openAIModel) chatRequest(req *model.LLMRequest, stream bool) (*chatRequest, error) {
+	cfg := req.Config
+	if cfg == nil {
+		cfg = &genai.GenerateContentConfig{}
+	}
+	chatReq := &chatRequest{
+		Model:            m.name,
+		Temperature:      cfg.Temperature,
+		TopP:             cfg.TopP,
+		MaxTokens:        cfg.MaxOutputTokens,
+		Stop:             cfg.StopSequences,
+		Seed:             cfg.Seed,
+		PresencePenalty:  cfg.PresencePenalty,
+		FrequencyPenalty: cfg.FrequencyPenalty,
+		Stream:           stream,
+	}
+	if stream {
+		chatReq.StreamOptions = &streamOptions{IncludeUsage: true}
+	}
+
+	if instruction := textOf(cfg.SystemInstruction); instruction != "" {
+		chatReq.Messages = append(chatReq.Messages, chatMessage{Role: "system", Content: instruction})
+	}
+	for _, content := range req.Contents {
+		messages, err := chatMessages(content)
+		if err != nil {
+			return nil, err
+		}
+		chatReq.Messages = append(chatReq.Messages, messages...)
+	}
+
+	// The tools are sorted by name for the requests to be stable.
+	names := make([]string, 0, len(req.Tools))
+	for name := range req.Tools {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	for _, name := range names {
+		t, ok := req.Tools[name].(tool.Tool)
+		if !ok {
+			return nil, fmt.Errorf("tool %q is not a tool.Tool", name)
+		}
+		decl, err := functiontool.OpenAIDeclaration(t)
+		if err != nil {
+			return nil, err
+		}
+		chatReq.Tools = append(chatReq.Tools, decl)
+	}
+
+	var err error
+	if chatReq.ResponseFormat, err = responseFormatOf(cfg); err != nil {
+		return nil, err
+	}
+	return chatReq, nil
+}
+
+// chatMessages translates a content: the function responses become tool
+// messages, and the other parts a user or an assistant message.
+func chatMessages(content *genai.Content) ([]chatMessage, error) {
+	if content == nil {
+		return nil, nil
+	}
+	role := "user"
+	if content.Role == genai.RoleModel {
+		role = "assistant"
+	}
+	var messages []chatMessage
+	var parts []contentPart
+	var toolCalls []toolCall
+	for _, part := range content.Parts {
+		switch {
+		case part.Thought:
+			// The thoughts of the model are not sent back.
+		case part.FunctionCall != nil:
+			args, err := json.Marshal(part.FunctionCall.Args)
+			if err != nil {
+				return nil, fmt.Errorf("failed to encode the arguments of function call %q: %w", part.FunctionCall.Name, err)
+			}
+			if part.FunctionCall.Args == nil {
+				args = []byte("{}")
+			}
+			toolCalls = append(toolCalls, toolCall{
+				ID:       part.FunctionCall.ID,
+				Type:     "function",
+				Function: functionCall{Name: part.FunctionCall.Name, Arguments: string(args)},
+			})
+		case part.FunctionResponse != nil:
+			response, err := json.Marshal(part.FunctionResponse.Response)
+			if err != nil {
+				return nil, fmt.Errorf("failed to encode the response of function %q: %w", part.FunctionResponse.Name, err)
+			}
+			messages = append(messages, chatMessage{Role: "tool", ToolCallID: part.FunctionResponse.ID, Content: string(response)})
+		case part.Text != "":
+			parts = append(parts, contentPart{Type: "text", Text: part.Text})
+		case part.InlineData != nil && strings.HasPrefix(part.InlineData.MIMEType, "image/"):
+			url := "data:" + part.InlineData.MIMEType + ";base64," + base64.StdEncoding.EncodeToString(part.InlineData.Data)
+			parts = append(parts, contentPart{Type: "image_url", ImageURL: &imageURL{URL: url}})
+		case part.FileData != nil && strings.HasPrefix(part.FileData.MIMEType, "image/"):
+			parts = append(parts, contentPart{Type: "image_url", ImageURL: &imageURL{URL: part.FileData.FileURI}})
+		case part.InlineData != nil:
+			return nil, fmt.Errorf("unsupported inline data of MIME type %q", part.InlineData.MIMEType)
+		case part.FileData != nil:
+			return nil, fmt.Errorf("unsupported file data of MIME type %q", part.FileData.MIMEType)
+		}
+	}
+	if len(parts) == 0 && len(toolCalls) == 0 {
+		return messages, nil
+	}
+
+	msg := chatMessage{Role: role, ToolCalls: toolCalls}
+	switch {
+	case len(parts) == 0:
+		// The content of the assistant messages with only tool calls is null.
+	case role == "assistant" || !slices.ContainsFunc(parts, func(p contentPart) bool { return p.Type != "text" }):
+		// The messages with only text have a string content, as not all the
+		// compatible endpoints accept the content parts.
+		var texts []string
+		for _, p := range parts {
+			texts = append(texts, p.Text)
+		}
+		msg.Content = strings.Join(texts, "")
+	default:
+		msg.Content = parts
+	}
+	return append(messages, msg), nil
+}
+
+// responseFormatOf returns the response format of the output schema or MIME
+// type of the config.
+func responseFormatOf(cfg *genai.GenerateContentConfig) (*responseFormat, error) {
+	var schema any
+	switch {
+	case cfg.ResponseJsonSchema != nil:
+		schema = cfg.ResponseJsonSchema
+	case cfg.ResponseSchema != nil:
+		schema = cfg.ResponseSchema
+	case cfg.ResponseMIMEType == "application/json":
+		return &responseFormat{Type: "json_object"}, nil
+	default:
+		return nil, nil
+	}
+	b, err := json.Marshal(schema)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode the response schema: %w", err)
+	}
+	var s map[string]any
+	if err := json.Unmarshal(b, &s); err != nil {
+		return nil, fmt.Errorf("response schema is not an object schema: %w", err)
+	}
+	return &responseFormat{
+		Type:       "json_schema",
+		JSONSchema: &jsonSchema{Name: "response", Schema: utils.OpenAISchema(s)},
+	}, nil
+}
+
+func textOf(content *genai.Content) string {
+	if content == nil {
+		return ""
+	}
+	var texts []string
+	for _, part := range content.Parts {
+		if part.Text != "" {
+			texts = append(texts, part.Text)
+		}
+	}
+	return strings.Join(texts, "\n")
+}
+
+// llmResponse translates the first choice of the response.
+func (r *chatResponse) llmResponse() (*model.LLMResponse, error) {
+	if r.Error != nil {
+		return nil, fmt.Errorf("model error: %s", r.Error.Message)
+	}
+	if len(r.Choices) == 0 {
+		return nil, errors.New("empty response")
+	}
+	choice := r.Choices[0]
+	resp := &model.LLMResponse{
+		FinishReason:  finishReason(choice.FinishReason),
+		UsageMetadata: r.Usage.usageMetadata(),
+	}
+	var err error
+	resp.Content, err = modelContent(choice.Message.Content, choice.Message.ToolCalls)
+	return resp, err
+}
+
+// modelContent returns the content of the text and the tool calls of the
+// model, or nil if both are empty.
+func modelContent(text string, toolCalls []toolCall) (*genai.Content, error) {
+	var parts []*genai.Part
+	if text != "" {
+		parts = append(parts, genai.NewPartFromText(text))
+	}
+	for _, call := range toolCalls {
+		var args map[string]any
+		if strings.TrimSpace(call.Function.Arguments) != "" {
+			if err := json.Unmarshal([]byte(call.Function.Arguments), &args); err != nil {
+				return nil, fmt.Errorf("invalid arguments of tool call %q: %w", call.Function.Name, err)
+			}
+		}
+		parts = append(parts, &genai.Part{FunctionCall: &genai.FunctionCall{ID: call.ID, Name: call.Function.Name, Args: args}})
+	}
+	if len(parts) == 0 {
+		return nil, nil
+	}
+	return &genai.Content{Role: genai.RoleModel, Parts: parts}, nil
+}
+
+// readStream reads the server-sent events of a streamed chat completion. It
+// yields a partial response for each text delta, then the aggregated text and
+// tool calls, whose arguments are streamed in fragments, in a last response.
+func readStream(body io.Reader) iter.Seq2[*model.LLMResponse, error] {
+	return func(yield func(*model.LLMResponse, error) bool) {
+		var text strings.Builder
+		var toolCalls []toolCall
+		var finish string
+		var usage *usage
+
+		scanner := bufio.NewScanner(body)
+		scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+		for scanner.Scan() {
+			data, ok := strings.CutPrefix(scanner.Text(), "data:")
+			if !ok {
+				continue
+			}
+			data = strings.TrimSpace(data)
+			if data == "[DONE]" {
+				break
+			}
+			var chunk chatResponse
+			if err := json.Unmarshal([]byte(data), &chunk); err != nil {
+				yield(nil, fmt.Errorf("failed to decode the model response: %w", err))
+				return
+			}
+			if chunk.Error != nil {
+				yield(nil, fmt.Errorf("model error: %s", chunk.Error.Message))
+				return
+			}
+			if chunk.Usage != nil {
+				usage = chunk.Usage
+			}
+			if len(chunk.Choices) == 0 {
+				continue
+			}
+			choice := chunk.Choices[0]
+			if choice.FinishReason != "" {
+				finish = choice.FinishReason
+			}
+			for _, delta := range choice.Delta.ToolCalls {
+				i := len(toolCalls)
+				if delta.Index != nil {
+					i = *delta.Index
+				}
+				for len(toolCalls) <= i {
+					toolCalls = append(toolCalls, toolCall{Type: "function"})
+				}
+				if delta.ID != "" {
+					toolCalls[i].ID = delta.ID
+				}
+				if delta.Function.Name != "" {
+					toolCalls[i].Function.Name = delta.Function.Name
+				}
+				toolCalls[i].Function.Arguments += delta.Function.Arguments
+			}
+			if choice.Delta.Content != "" {
+				text.WriteString(choice.Delta.Content)
+				if !yield(&model.LLMResponse{
+					Content: genai.NewContentFromText(choice.Delta.Content, genai.RoleModel),
+					Partial: true,
+				}, nil) {
+					return
+				}
+			}
+		}
+		if err := scanner.Err(); err != nil {
+			yield(nil, fmt.Errorf("failed to read the model response: %w", err))
+			return
+		}
+
+		content, err := modelContent(text.String(), toolCalls)
+		if err != nil {
+			yield(nil, err)
+			return
+		}
+		yield(&model.LLMResponse{
+			Content:       content,
+			UsageMetadata: usage.usageMetadata(),
+			FinishReason:  finishReason(finish),
+			TurnComplete:  true,
+		}, nil)
+	}
+}
+
+func finishReason(reason string) genai.FinishReason {
+	switch reason {
+	case "":
+		return ""
+	case "stop", "tool_calls", "function_call":
+		return genai.FinishReasonStop
+	case "length":
+		return genai.FinishReasonMaxTokens
+	case "content_filter":
+		return genai.FinishReasonSafety
+	}
+	return genai.FinishReasonOther
+}
+
+func (u *usage) usageMetadata() *genai.GenerateContentResponseUsageMetadata {
+	if u == nil {
+		return nil
+	}
+	return &genai.GenerateContentResponseUsageMetadata{
+		PromptTokenCount:     u.PromptTokens,
+		CandidatesTokenCount: u.CompletionTokens,
+		TotalTokenCount:      u.TotalTokens,
+	}
+}
+
+// The types of the Chat Completions API.
+
+type chatRequest struct {
+	Model            string            `json:"model"`
+	Messages         []chatMessage     `json:"messages"`
+	Tools            []json.RawMessage `json:"tools,omitempty"`
+	Temperature      *float32          `json:"temperature,omitempty"`
+	TopP             *float32          `json:"top_p,omitempty"`
+	MaxTokens        int32             `json:"max_tokens,omitempty"`
+	Stop             []string          `json:"stop,omitempty"`
+	Seed             *int32            `json:"seed,omitempty"`
+	PresencePenalty  *float32          `json:"presence_penalty,omitempty"`
+	FrequencyPenalty *float32          `json:"frequency_penalty,omitempty"`
+	ResponseFormat   *responseFormat   `json:"response_format,omitempty"`
+	Stream           bool              `json:"stream,omitempty"`
+	StreamOptions    *streamOptions    `json:"stream_options,omitempty"`
+}
+
+type chatMessage struct {
+	Role string `json:"role"`
+	// Content is a string, a list of content parts, or nil.
+	Content    any        `json:"content"`
+	ToolCalls  []toolCall `json:"tool_calls,omitempty"`
+	ToolCallID string     `json:"tool_call_id,omitempty"`
+}
+
+type contentPart struct {
+	Type     string    `json:"type"`
+	Text     string    `json:"text,omitempty"`
+	ImageURL *imageURL `json:"image_url,omitempty"`
+}
+
+type imageURL struct {
+	URL string `json:"url"`
+}
+
+type toolCall struct {
+	// Index identifies the tool call of the deltas of a stream.
+	Index    *int         `json:"index,omitempty"`
+	ID       string       `json:"id,omitempty"`
+	Type     string       `json:"type,omitempty"`
+	Function functionCall `json:"function"`
+}
+
+type functionCall struct {
+	Name      string `json:"name,omitempty"`
+	Arguments string `json:"arguments"`
+}
+
+type responseFormat struct {
+	Type       string      `json:"type"`
+	JSONSchema *jsonSchema `json:"json_schema,omitempty"`
+}
+
+type jsonSchema struct {
+	Name   string         `json:"name"`
+	Schema map[string]any `json:"schema"`
+}
+
+type streamOptions struct {
+	IncludeUsage bool `json:"include_usage"`
+}
+
+type chatResponse struct {
+	Choices []struct {
+		Message      responseMessage `json:"message"`
+		Delta        responseMessage `json:"delta"`
+		FinishReason string          `json:"finish_reason"`
+	} `json:"choices"`
+	Usage *usage    `json:"usage"`
+	Error *apiError `json:"error"`
+}
+
+type responseMessage struct {
+	Content   string     `json:"content"`
+	ToolCalls []toolCall `json:"tool_calls"`
+}
+
+type usage struct {
+	PromptTokens     int32 `json:"prompt_tokens"`
+	CompletionTokens int32 `json:"completion_tokens"`
+	TotalTokens      int32 `json:"total_tokens"`
+}
+
+type apiError struct {
+	Message string `json:"message"`
+}
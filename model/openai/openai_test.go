@@ -0,0 +1,307 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openai_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/model"
+	"google.golang.org/adk/model/openai"
+	"google.golang.org/adk/tool"
+	"google.golang.org/adk/tool/functiontool"
+	"google.golang.org/adk/tool/geminitool"
+)
+
+// newServer returns a server answering the chat completions with the body,
+// and the decoded request it received.
+func newServer(t *testing.T, status int, body string) (*httptest.Server, *map[string]any) {
+	t.Helper()
+	var got map[string]any
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/chat/completions" {
+			t.Errorf("path = %q, want /v1/chat/completions", r.URL.Path)
+		}
+		if auth := r.Header.Get("Authorization"); auth != "Bearer key" {
+			t.Errorf("Authorization = %q, want Bearer key", auth)
+		}
+		if h := r.Header.Get("X-Custom"); h != "custom" {
+			t.Errorf("X-Custom = %q, want custom", h)
+		}
+		b, err := io.ReadAll(r.Body)
+		if err != nil {
+			t.Error(err)
+		}
+		if err := json.Unmarshal(b, &got); err != nil {
+			t.Errorf("invalid request %s: %v", b, err)
+		}
+		w.WriteHeader(status)
+		fmt.Fprint(w, body)
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &got
+}
+
+func newModel(t *testing.T, srv *httptest.Server) model.LLM {
+	t.Helper()
+	m, err := openai.NewModel(t.Context(), "gpt-test", &openai.Config{
+		BaseURL: srv.URL + "/v1/",
+		APIKey:  "key",
+		Headers: http.Header{"X-Custom": []string{"custom"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return m
+}
+
+func TestModel_Generate(t *testing.T) {
+	srv, got := newServer(t, http.StatusOK, `{
+		"choices": [{
+			"message": {
+				"role": "assistant",
+				"content": "Checking.",
+				"tool_calls": [{"id": "call_2", "type": "function", "function": {"name": "get_weather", "arguments": "{\"city\": \"Paris\"}"}}]
+			},
+			"finish_reason": "tool_calls"
+		}],
+		"usage": {"prompt_tokens": 10, "completion_tokens": 5, "total_tokens": 15}
+	}`)
+
+	weather, err := functiontool.New(functiontool.Config{Name: "get_weather", Description: "Returns the weather."},
+		func(tool.Context, struct {
+			City string `json:"city"`
+		}) (string, error) {
+			return "sunny", nil
+		})
+	if err != nil {
+		t.Fatal(err)
+	}
+	req := &model.LLMRequest{
+		Contents: []*genai.Content{
+			{Role: genai.RoleUser, Parts: []*genai.Part{
+				{Text: "Weather of this city?"},
+				{InlineData: &genai.Blob{MIMEType: "image/png", Data: []byte("png")}},
+			}},
+			{Role: genai.RoleModel, Parts: []*genai.Part{
+				{Text: "thinking", Thought: true},
+				{FunctionCall: &genai.FunctionCall{ID: "call_1", Name: "get_weather", Args: map[string]any{"city": "Rome"}}},
+			}},
+			{Role: genai.RoleUser, Parts: []*genai.Part{
+				{FunctionResponse: &genai.FunctionResponse{ID: "call_1", Name: "get_weather", Response: map[string]any{"result": "rainy"}}},
+			}},
+		},
+		Config: &genai.GenerateContentConfig{
+			SystemInstruction: genai.NewContentFromText("Be brief.", genai.RoleUser),
+			Temperature:       genai.Ptr[float32](0.5),
+			MaxOutputTokens:   100,
+			StopSequences:     []string{"END"},
+		},
+		Tools: map[string]any{"get_weather": weather},
+	}
+
+	var responses []*model.LLMResponse
+	for resp, err := range newModel(t, srv).GenerateContent(t.Context(), req, false) {
+		if err != nil {
+			t.Fatalf("GenerateContent() error = %v", err)
+		}
+		responses = append(responses, resp)
+	}
+
+	wantRequest := map[string]any{
+		"model": "gpt-test",
+		"messages": []any{
+			map[string]any{"role": "system", "content": "Be brief."},
+			map[string]any{"role": "user", "content": []any{
+				map[string]any{"type": "text", "text": "Weather of this city?"},
+				map[string]any{"type": "image_url", "image_url": map[string]any{"url": "data:image/png;base64,cG5n"}},
+			}},
+			map[string]any{"role": "assistant", "content": nil, "tool_calls": []any{
+				map[string]any{"id": "call_1", "type": "function", "function": map[string]any{"name": "get_weather", "arguments": `{"city":"Rome"}`}},
+			}},
+			map[string]any{"role": "tool", "tool_call_id": "call_1", "content": `{"result":"rainy"}`},
+		},
+		"tools": []any{map[string]any{
+			"type": "function",
+			"function": map[string]any{
+				"name":        "get_weather",
+				"description": "Returns the weather.",
+				"parameters": map[string]any{
+					"type":                 "object",
+					"properties":           map[string]any{"city": map[string]any{"type": "string"}},
+					"required":             []any{"city"},
+					"additionalProperties": false,
+				},
+			},
+		}},
+		"temperature": 0.5,
+		"max_tokens":  100.0,
+		"stop":        []any{"END"},
+	}
+	if diff := cmp.Diff(wantRequest, *got); diff != "" {
+		t.Errorf("request mismatch (-want +got):\n%s", diff)
+	}
+
+	want := []*model.LLMResponse{{
+		Content: &genai.Content{Role: genai.RoleModel, Parts: []*genai.Part{
+			{Text: "Checking."},
+			{FunctionCall: &genai.FunctionCall{ID: "call_2", Name: "get_weather", Args: map[string]any{"city": "Paris"}}},
+		}},
+		UsageMetadata: &genai.GenerateContentResponseUsageMetadata{PromptTokenCount: 10, CandidatesTokenCount: 5, TotalTokenCount: 15},
+		FinishReason:  genai.FinishReasonStop,
+	}}
+	if diff := cmp.Diff(want, responses); diff != "" {
+		t.Errorf("responses mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestModel_GenerateStream(t *testing.T) {
+	srv, got := newServer(t, http.StatusOK, strings.Join([]string{
+		`data: {"choices": [{"delta": {"role": "assistant", "content": "Hel"}}]}`,
+		`data: {"choices": [{"delta": {"content": "lo"}}]}`,
+		`data: {"choices": [{"delta": {"tool_calls": [{"index": 0, "id": "call_1", "type": "function", "function": {"name": "get_weather", "arguments": ""}}]}}]}`,
+		`data: {"choices": [{"delta": {"tool_calls": [{"index": 0, "function": {"arguments": "{\"city\":"}}]}}]}`,
+		`data: {"choices": [{"delta": {"tool_calls": [{"index": 0, "function": {"arguments": " \"Paris\"}"}}]}, "finish_reason": "tool_calls"}]}`,
+		`data: {"choices": [], "usage": {"prompt_tokens": 3, "completion_tokens": 4, "total_tokens": 7}}`,
+		`data: [DONE]`,
+		``,
+	}, "\n\n"))
+
+	req := &model.LLMRequest{
+		Contents: genai.Text("Hi"),
+		Config:   &genai.GenerateContentConfig{ResponseMIMEType: "application/json"},
+	}
+	var responses []*model.LLMResponse
+	for resp, err := range newModel(t, srv).GenerateContent(t.Context(), req, true) {
+		if err != nil {
+			t.Fatalf("GenerateContent() error = %v", err)
+		}
+		responses = append(responses, resp)
+	}
+
+	wantRequest := map[string]any{
+		"model":           "gpt-test",
+		"messages":        []any{map[string]any{"role": "user", "content": "Hi"}},
+		"response_format": map[string]any{"type": "json_object"},
+		"stream":          true,
+		"stream_options":  map[string]any{"include_usage": true},
+	}
+	if diff := cmp.Diff(wantRequest, *got); diff != "" {
+		t.Errorf("request mismatch (-want +got):\n%s", diff)
+	}
+
+	want := []*model.LLMResponse{
+		{Content: genai.NewContentFromText("Hel", genai.RoleModel), Partial: true},
+		{Content: genai.NewContentFromText("lo", genai.RoleModel), Partial: true},
+		{
+			Content: &genai.Content{Role: genai.RoleModel, Parts: []*genai.Part{
+				{Text: "Hello"},
+				{FunctionCall: &genai.FunctionCall{ID: "call_1", Name: "get_weather", Args: map[string]any{"city": "Paris"}}},
+			}},
+			UsageMetadata: &genai.GenerateContentResponseUsageMetadata{PromptTokenCount: 3, CandidatesTokenCount: 4, TotalTokenCount: 7},
+			FinishReason:  genai.FinishReasonStop,
+			TurnComplete:  true,
+		},
+	}
+	if diff := cmp.Diff(want, responses); diff != "" {
+		t.Errorf("responses mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestModel_ResponseSchema(t *testing.T) {
+	srv, got := newServer(t, http.StatusOK, `{"choices": [{"message": {"content": "{\"a\": 1}"}, "finish_reason": "stop"}]}`)
+
+	req := &model.LLMRequest{
+		Contents: genai.Text("Hi"),
+		Config: &genai.GenerateContentConfig{ResponseSchema: &genai.Schema{
+			Type:       genai.TypeObject,
+			Properties: map[string]*genai.Schema{"a": {Type: genai.TypeInteger, Nullable: genai.Ptr(true)}},
+		}},
+	}
+	for _, err := range newModel(t, srv).GenerateContent(t.Context(), req, false) {
+		if err != nil {
+			t.Fatalf("GenerateContent() error = %v", err)
+		}
+	}
+
+	want := map[string]any{
+		"type": "json_schema",
+		"json_schema": map[string]any{
+			"name": "response",
+			"schema": map[string]any{
+				"type":       "object",
+				"properties": map[string]any{"a": map[string]any{"type": []any{"integer", "null"}}},
+			},
+		},
+	}
+	if diff := cmp.Diff(want, (*got)["response_format"]); diff != "" {
+		t.Errorf("response_format mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestModel_Errors(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		status  int
+		body    string
+		req     *model.LLMRequest
+		wantErr string
+	}{
+		{
+			name:    "error status",
+			status:  http.StatusTooManyRequests,
+			body:    `{"error": {"message": "rate limited"}}`,
+			req:     &model.LLMRequest{Contents: genai.Text("Hi")},
+			wantErr: "rate limited",
+		},
+		{
+			name:    "invalid tool call arguments",
+			status:  http.StatusOK,
+			body:    `{"choices": [{"message": {"tool_calls": [{"id": "1", "function": {"name": "f", "arguments": "{"}}]}}]}`,
+			req:     &model.LLMRequest{Contents: genai.Text("Hi")},
+			wantErr: "invalid arguments",
+		},
+		{
+			name:    "built-in tool",
+			status:  http.StatusOK,
+			req:     &model.LLMRequest{Contents: genai.Text("Hi"), Tools: map[string]any{"google_search": geminitool.GoogleSearch{}}},
+			wantErr: "not a function tool",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			srv, _ := newServer(t, tc.status, tc.body)
+			var gotErr error
+			for _, err := range newModel(t, srv).GenerateContent(t.Context(), tc.req, false) {
+				gotErr = err
+			}
+			if gotErr == nil || !strings.Contains(gotErr.Error(), tc.wantErr) {
+				t.Errorf("GenerateContent() error = %v, want %q", gotErr, tc.wantErr)
+			}
+			var apiErr *model.APIError
+			if tc.status != http.StatusOK && (!errors.As(gotErr, &apiErr) || apiErr.StatusCode != tc.status) {
+				t.Errorf("GenerateContent() error = %v, want a model.APIError of status %d", gotErr, tc.status)
+			}
+		})
+	}
+}
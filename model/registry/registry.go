@@ -0,0 +1,121 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package registry resolves the models of model strings, prefixed by their
+// provider: "openai/gpt-4o" is the model gpt-4o of the openai provider. The
+// strings without a registered provider prefix, e.g. "gemini-2.0-flash", are
+// Gemini models.
+//
+// The built-in providers are:
+//   - gemini: [gemini.NewModel], configured by the environment, see
+//     genai.ClientConfig;
+//   - openai: [openai.NewModel], configured by the environment;
+//   - anthropic: [anthropic.NewModel], configured by the environment;
+//   - ollama: [ollama.NewModel] of a local Ollama server.
+//
+// Other providers are added with [RegisterProvider].
+package registry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/model"
+	"google.golang.org/adk/model/anthropic"
+	"google.golang.org/adk/model/gemini"
+	"google.golang.org/adk/model/ollama"
+	"google.golang.org/adk/model/openai"
+)
+
+// Factory creates the model of a name, without the provider prefix.
+type Factory func(ctx context.Context, name string) (model.LLM, error)
+
+// DefaultProvider is the provider of the model strings without a registered
+// provider prefix.
+const DefaultProvider = "gemini"
+
+var (
+	mu        sync.RWMutex
+	providers = map[string]Factory{
+		"gemini": func(ctx context.Context, name string) (model.LLM, error) {
+			return gemini.NewModel(ctx, name, &genai.ClientConfig{})
+		},
+		"openai": func(ctx context.Context, name string) (model.LLM, error) {
+			return openai.NewModel(ctx, name, nil)
+		},
+		"anthropic": func(ctx context.Context, name string) (model.LLM, error) {
+			return anthropic.NewModel(ctx, name, nil)
+		},
+		"ollama": func(ctx context.Context, name string) (model.LLM, error) {
+			return ollama.NewModel(ctx, name, nil)
+		},
+	}
+)
+
+// RegisterProvider registers the factory of the models of a provider, used for
+// the model strings prefixed by "<provider>/". It fails if the provider is
+// already registered.
+func RegisterProvider(provider string, factory Factory) error {
+	if provider == "" || strings.Contains(provider, "/") {
+		return fmt.Errorf("invalid provider %q", provider)
+	}
+	if factory == nil {
+		return errors.New("factory is required")
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if _, ok := providers[provider]; ok {
+		return fmt.Errorf("provider %q is already registered", provider)
+	}
+	providers[provider] = factory
+	return nil
+}
+
+// NewModel returns the model of a model string, created by the factory of its
+// provider.
+func NewModel(ctx context.Context, modelString string) (model.LLM, error) {
+	provider, name := Resolve(modelString)
+	if name == "" {
+		return nil, fmt.Errorf("invalid model string %q", modelString)
+	}
+	mu.RLock()
+	factory := providers[provider]
+	mu.RUnlock()
+	m, err := factory(ctx, name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create %s model %q: %w", provider, name, err)
+	}
+	return m, nil
+}
+
+// Resolve returns the provider and the name of the model of a model string.
+// The strings whose prefix is not a registered provider, e.g. the Vertex AI
+// resource names "projects/.../models/...", are names of the default
+// provider.
+func Resolve(modelString string) (provider, name string) {
+	if prefix, rest, ok := strings.Cut(modelString, "/"); ok {
+		mu.RLock()
+		_, registered := providers[prefix]
+		mu.RUnlock()
+		if registered {
+			return prefix, rest
+		}
+	}
+	return DefaultProvider, modelString
+}
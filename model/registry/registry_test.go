@@ -0,0 +1,94 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package registry_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"google.golang.org/adk/internal/testutil"
+	"google.golang.org/adk/model"
+	"google.golang.org/adk/model/registry"
+)
+
+func TestResolve(t *testing.T) {
+	for _, tc := range []struct {
+		modelString  string
+		wantProvider string
+		wantName     string
+	}{
+		{modelString: "gemini-2.0-flash", wantProvider: "gemini", wantName: "gemini-2.0-flash"},
+		{modelString: "gemini/gemini-2.0-flash", wantProvider: "gemini", wantName: "gemini-2.0-flash"},
+		{modelString: "openai/gpt-4o", wantProvider: "openai", wantName: "gpt-4o"},
+		{modelString: "anthropic/claude-3-7-sonnet", wantProvider: "anthropic", wantName: "claude-3-7-sonnet"},
+		{modelString: "ollama/library/llama3", wantProvider: "ollama", wantName: "library/llama3"},
+		{
+			modelString:  "projects/p/locations/l/publishers/google/models/gemini-2.0-flash",
+			wantProvider: "gemini",
+			wantName:     "projects/p/locations/l/publishers/google/models/gemini-2.0-flash",
+		},
+	} {
+		provider, name := registry.Resolve(tc.modelString)
+		if provider != tc.wantProvider || name != tc.wantName {
+			t.Errorf("Resolve(%q) = %q, %q, want %q, %q", tc.modelString, provider, name, tc.wantProvider, tc.wantName)
+		}
+	}
+}
+
+func TestRegisterProvider(t *testing.T) {
+	mock := &testutil.MockModel{}
+	var gotName string
+	if err := registry.RegisterProvider("test", func(_ context.Context, name string) (model.LLM, error) {
+		gotName = name
+		if name == "broken" {
+			return nil, errors.New("broken model")
+		}
+		return mock, nil
+	}); err != nil {
+		t.Fatalf("RegisterProvider() error = %v", err)
+	}
+
+	m, err := registry.NewModel(t.Context(), "test/my-model")
+	if err != nil {
+		t.Fatalf("NewModel() error = %v", err)
+	}
+	if m != mock || gotName != "my-model" {
+		t.Errorf("NewModel() = %v for name %q, want the model of the provider for my-model", m, gotName)
+	}
+	if _, err := registry.NewModel(t.Context(), "test/broken"); err == nil {
+		t.Error("NewModel() succeeded, want the error of the factory")
+	}
+	if _, err := registry.NewModel(t.Context(), ""); err == nil {
+		t.Error("NewModel(\"\") succeeded, want error")
+	}
+
+	for _, provider := range []string{"test", "openai", "", "a/b"} {
+		if err := registry.RegisterProvider(provider, func(context.Context, string) (model.LLM, error) { return mock, nil }); err == nil {
+			t.Errorf("RegisterProvider(%q) succeeded, want error", provider)
+		}
+	}
+}
+
+func TestNewModel_BuiltIn(t *testing.T) {
+	t.Setenv("OPENAI_API_KEY", "key")
+	m, err := registry.NewModel(t.Context(), "openai/gpt-4o")
+	if err != nil {
+		t.Fatalf("NewModel() error = %v", err)
+	}
+	if m.Name() != "gpt-4o" {
+		t.Errorf("Name() = %q, want gpt-4o", m.Name())
+	}
+}
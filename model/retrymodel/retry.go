@@ -0,0 +1,229 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package retrymodel wraps models to retry their calls failing with a
+// transient error, e.g. rate limited with the status 429, or with the status
+// 503 of an overloaded service.
+//
+// The attempts are spaced by a jittered exponential backoff, or by the delay
+// the API asks for, e.g. in the Retry-After header. The retries never outlast
+// the deadline of the context of the call, e.g. the timeout of the
+// invocation, and each retry is recorded in the telemetry, see
+// telemetry.SetMeterProvider.
+package retrymodel
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"iter"
+	"math/rand/v2"
+	"net/http"
+	"time"
+
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/internal/telemetry"
+	"google.golang.org/adk/model"
+)
+
+const (
+	defaultMaxAttempts = 3
+	defaultBaseBackoff = time.Second
+	defaultMaxBackoff  = 30 * time.Second
+	defaultMaxDelay    = time.Minute
+)
+
+// RetryPolicy configures the retries of the failed model calls.
+type RetryPolicy struct {
+	// MaxAttempts is the maximum number of calls of the model for a request,
+	// including the first one. Defaults to 3.
+	MaxAttempts int
+	// BaseBackoff is the delay before the first retry, doubled for each next
+	// retry. Defaults to 1s.
+	BaseBackoff time.Duration
+	// MaxBackoff caps the backoff between two attempts. Defaults to 30s.
+	MaxBackoff time.Duration
+	// MaxDelay is the hard cap of the delay before a retry: the retries
+	// stop, returning the error, when the API asks to wait longer. Defaults
+	// to 1m.
+	MaxDelay time.Duration
+	// Retryable reports whether the call is retried after the error. If it is
+	// nil, IsRetryable is used.
+	Retryable func(error) bool
+}
+
+// WithRetry returns a model behaving like m, except that its calls failing
+// with a retryable error before any response are made again, up to
+// policy.MaxAttempts times. The last error is returned if all the attempts
+// fail, or if the next attempt would start after the deadline of the context
+// of the call. The streamed calls failing after a response are not retried.
+//
+// The live connections of a model.LiveLLM are not retried.
+func WithRetry(m model.LLM, policy RetryPolicy) model.LLM {
+	if policy.MaxAttempts <= 0 {
+		policy.MaxAttempts = defaultMaxAttempts
+	}
+	if policy.BaseBackoff <= 0 {
+		policy.BaseBackoff = defaultBaseBackoff
+	}
+	if policy.MaxBackoff <= 0 {
+		policy.MaxBackoff = defaultMaxBackoff
+	}
+	if policy.MaxDelay <= 0 {
+		policy.MaxDelay = defaultMaxDelay
+	}
+	if policy.Retryable == nil {
+		policy.Retryable = IsRetryable
+	}
+	r := &retryingModel{LLM: m, policy: policy}
+	if live, ok := m.(model.LiveLLM); ok {
+		return &retryingLiveModel{retryingModel: r, live: live}
+	}
+	return r
+}
+
+type retryingModel struct {
+	model.LLM
+	policy RetryPolicy
+}
+
+// Unwrap returns the wrapped model.
+func (m *retryingModel) Unwrap() model.LLM {
+	return m.LLM
+}
+
+// GenerateContent calls the wrapped model until it responds, fails with an
+// error which is not retryable, or the attempts are exhausted.
+func (m *retryingModel) GenerateContent(ctx context.Context, req *model.LLMRequest, stream bool) iter.Seq2[*model.LLMResponse, error] {
+	return func(yield func(*model.LLMResponse, error) bool) {
+		for attempt := 1; ; attempt++ {
+			responded := false
+			var callErr error
+			for resp, err := range m.LLM.GenerateContent(ctx, req, stream) {
+				if err != nil && !responded {
+					callErr = err
+					break
+				}
+				responded = true
+				if !yield(resp, err) {
+					return
+				}
+			}
+			if callErr == nil {
+				return
+			}
+
+			delay, ok := m.retryDelay(ctx, attempt, callErr)
+			if !ok {
+				yield(nil, callErr)
+				return
+			}
+			telemetry.RecordModelRetry(ctx, m.Name(), attempt, delay, callErr)
+			timer := time.NewTimer(delay)
+			select {
+			case <-timer.C:
+			case <-ctx.Done():
+				timer.Stop()
+				yield(nil, fmt.Errorf("retries of model %q stopped: %w", m.Name(), errors.Join(ctx.Err(), callErr)))
+				return
+			}
+		}
+	}
+}
+
+// retryDelay returns the delay before the retry following the given failed
+// attempt, and whether to retry.
+func (m *retryingModel) retryDelay(ctx context.Context, attempt int, err error) (time.Duration, bool) {
+	if attempt >= m.policy.MaxAttempts || !m.policy.Retryable(err) || ctx.Err() != nil {
+		return 0, false
+	}
+	delay := max(m.backoff(attempt), RetryAfter(err))
+	if delay > m.policy.MaxDelay {
+		return 0, false
+	}
+	if deadline, ok := ctx.Deadline(); ok && time.Now().Add(delay).After(deadline) {
+		return 0, false
+	}
+	return delay, true
+}
+
+// backoff returns the backoff after the given attempt: BaseBackoff doubled
+// for each previous retry, capped by MaxBackoff, and jittered between half
+// and all of it.
+func (m *retryingModel) backoff(attempt int) time.Duration {
+	d := m.policy.BaseBackoff
+	for i := 1; i < attempt && d < m.policy.MaxBackoff; i++ {
+		d *= 2
+	}
+	d = min(d, m.policy.MaxBackoff)
+	return d/2 + rand.N(d/2+1)
+}
+
+// retryingLiveModel is a retryingModel of a model.LiveLLM.
+type retryingLiveModel struct {
+	*retryingModel
+	live model.LiveLLM
+}
+
+func (m *retryingLiveModel) ConnectLive(ctx context.Context, req *model.LLMRequest) (model.LiveConnection, error) {
+	return m.live.ConnectLive(ctx, req)
+}
+
+// IsRetryable reports whether the error of a model call is transient: a
+// model.APIError or a genai.APIError of the status 408, 429, 500, 502, 503
+// or 504.
+func IsRetryable(err error) bool {
+	var code int
+	var apiErr *model.APIError
+	var genaiErr genai.APIError
+	switch {
+	case errors.As(err, &apiErr):
+		code = apiErr.StatusCode
+	case errors.As(err, &genaiErr):
+		code = genaiErr.Code
+	default:
+		return false
+	}
+	switch code {
+	case http.StatusRequestTimeout, http.StatusTooManyRequests, http.StatusInternalServerError,
+		http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// RetryAfter returns the delay the API asks to wait for before retrying the
+// failed call, or 0: the Retry-After header of a model.APIError, or the
+// google.rpc.RetryInfo details of a genai.APIError.
+func RetryAfter(err error) time.Duration {
+	var apiErr *model.APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.RetryAfter
+	}
+	var genaiErr genai.APIError
+	if errors.As(err, &genaiErr) {
+		for _, detail := range genaiErr.Details {
+			if detail["@type"] != "type.googleapis.com/google.rpc.RetryInfo" {
+				continue
+			}
+			if s, ok := detail["retryDelay"].(string); ok {
+				if d, err := time.ParseDuration(s); err == nil && d > 0 {
+					return d
+				}
+			}
+		}
+	}
+	return 0
+}
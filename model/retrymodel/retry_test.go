@@ -0,0 +1,192 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package retrymodel_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"iter"
+	"testing"
+	"time"
+
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/model"
+	"google.golang.org/adk/model/retrymodel"
+)
+
+var (
+	errRateLimited = &model.APIError{StatusCode: 429, Status: "429 Too Many Requests", Message: "slow down"}
+	errBadRequest  = &model.APIError{StatusCode: 400, Status: "400 Bad Request", Message: "invalid"}
+	errRetryLater  = &model.APIError{StatusCode: 429, Status: "429 Too Many Requests", RetryAfter: time.Hour}
+)
+
+// flakyModel fails with the given errors before responding, counting its
+// calls. With partial, it streams a response before each failure.
+type flakyModel struct {
+	failures []error
+	partial  bool
+	calls    int
+}
+
+func (m *flakyModel) Name() string {
+	return "flaky"
+}
+
+func (m *flakyModel) GenerateContent(ctx context.Context, req *model.LLMRequest, stream bool) iter.Seq2[*model.LLMResponse, error] {
+	return func(yield func(*model.LLMResponse, error) bool) {
+		m.calls++
+		if m.partial && !yield(&model.LLMResponse{Content: genai.NewContentFromText("Hel", genai.RoleModel), Partial: true}, nil) {
+			return
+		}
+		if m.calls <= len(m.failures) {
+			yield(nil, m.failures[m.calls-1])
+			return
+		}
+		yield(&model.LLMResponse{Content: genai.NewContentFromText("Hello", genai.RoleModel)}, nil)
+	}
+}
+
+func generate(ctx context.Context, m model.LLM) ([]*model.LLMResponse, error) {
+	var resps []*model.LLMResponse
+	for resp, err := range m.GenerateContent(ctx, &model.LLMRequest{}, false) {
+		if err != nil {
+			return resps, err
+		}
+		resps = append(resps, resp)
+	}
+	return resps, nil
+}
+
+func TestWithRetry(t *testing.T) {
+	policy := retrymodel.RetryPolicy{BaseBackoff: time.Millisecond, MaxBackoff: 2 * time.Millisecond}
+	for _, tc := range []struct {
+		name      string
+		llm       *flakyModel
+		policy    retrymodel.RetryPolicy
+		wantCalls int
+		wantErr   error
+	}{
+		{
+			name:      "retried until success",
+			llm:       &flakyModel{failures: []error{errRateLimited, fmt.Errorf("failed to call model: %w", errRateLimited)}},
+			policy:    policy,
+			wantCalls: 3,
+		},
+		{
+			name:      "attempts exhausted",
+			llm:       &flakyModel{failures: []error{errRateLimited, errRateLimited}},
+			policy:    retrymodel.RetryPolicy{MaxAttempts: 2, BaseBackoff: time.Millisecond},
+			wantCalls: 2,
+			wantErr:   errRateLimited,
+		},
+		{
+			name:      "not retryable",
+			llm:       &flakyModel{failures: []error{errBadRequest}},
+			policy:    policy,
+			wantCalls: 1,
+			wantErr:   errBadRequest,
+		},
+		{
+			name:      "gemini error",
+			llm:       &flakyModel{failures: []error{genai.APIError{Code: 503, Message: "overloaded"}}},
+			policy:    policy,
+			wantCalls: 2,
+		},
+		{
+			name:      "retry after above the cap",
+			llm:       &flakyModel{failures: []error{errRetryLater}},
+			policy:    policy,
+			wantCalls: 1,
+			wantErr:   errRetryLater,
+		},
+		{
+			name:      "failure after a streamed response",
+			llm:       &flakyModel{failures: []error{errRateLimited}, partial: true},
+			policy:    policy,
+			wantCalls: 1,
+			wantErr:   errRateLimited,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			resps, err := generate(t.Context(), retrymodel.WithRetry(tc.llm, tc.policy))
+			if tc.llm.calls != tc.wantCalls {
+				t.Errorf("got %d calls, want %d", tc.llm.calls, tc.wantCalls)
+			}
+			if tc.wantErr == nil {
+				if err != nil || len(resps) == 0 || resps[len(resps)-1].Content.Parts[0].Text != "Hello" {
+					t.Errorf("GenerateContent() = %v, %v, want the response", resps, err)
+				}
+				return
+			}
+			if !errors.Is(err, tc.wantErr) {
+				t.Errorf("GenerateContent() error = %v, want %v", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestWithRetry_Deadline(t *testing.T) {
+	llm := &flakyModel{failures: []error{
+		&model.APIError{StatusCode: 503, Status: "503 Service Unavailable", RetryAfter: 10 * time.Second},
+	}}
+	ctx, cancel := context.WithTimeout(t.Context(), time.Second)
+	defer cancel()
+
+	start := time.Now()
+	_, err := generate(ctx, retrymodel.WithRetry(llm, retrymodel.RetryPolicy{}))
+	if err == nil || llm.calls != 1 {
+		t.Errorf("GenerateContent() = %v after %d calls, want the error of the only call", err, llm.calls)
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("GenerateContent() took %v, want no wait past the deadline", elapsed)
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	for _, tc := range []struct {
+		err  error
+		want time.Duration
+	}{
+		{err: fmt.Errorf("failed: %w", &model.APIError{StatusCode: 429, RetryAfter: 3 * time.Second}), want: 3 * time.Second},
+		{err: genai.APIError{Code: 429, Details: []map[string]any{
+			{"@type": "type.googleapis.com/google.rpc.QuotaFailure"},
+			{"@type": "type.googleapis.com/google.rpc.RetryInfo", "retryDelay": "12s"},
+		}}, want: 12 * time.Second},
+		{err: errors.New("other"), want: 0},
+	} {
+		if got := retrymodel.RetryAfter(tc.err); got != tc.want {
+			t.Errorf("RetryAfter(%v) = %v, want %v", tc.err, got, tc.want)
+		}
+	}
+}
+
+type liveModel struct {
+	flakyModel
+}
+
+func (liveModel) ConnectLive(context.Context, *model.LLMRequest) (model.LiveConnection, error) {
+	return nil, nil
+}
+
+func TestWithRetry_Live(t *testing.T) {
+	if _, ok := retrymodel.WithRetry(&liveModel{}, retrymodel.RetryPolicy{}).(model.LiveLLM); !ok {
+		t.Error("WithRetry() of a live model is not a model.LiveLLM")
+	}
+	if _, ok := retrymodel.WithRetry(&flakyModel{}, retrymodel.RetryPolicy{}).(model.LiveLLM); ok {
+		t.Error("WithRetry() of a model is a model.LiveLLM")
+	}
+}
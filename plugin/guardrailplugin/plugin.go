@@ -0,0 +1,276 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package guardrailplugin provides a plugin running guardrails, see package
+// guardrail, on the input of the user and on the output of the models of all
+// the agents of a runner, and adding safety settings to the requests of the
+// Gemini and Vertex AI models.
+//
+// Unlike llmagent.Config.OutputGuardrails, which check the final answer of
+// an agent, the plugin checks each model call: the texts of the user before
+// they reach the model, and each response, partial ones included, before it
+// reaches the client. The responses it rewrites or blocks carry the outcomes
+// of the guardrails in their custom metadata, under [MetadataKey].
+//
+//	secrets, err := guardrail.NewBlocklist(guardrail.BlocklistConfig{
+//		Patterns: []*regexp.Regexp{regexp.MustCompile(`(?i)\bpassword\b`)},
+//	})
+//	...
+//	r, err := runner.New(runner.Config{
+//		...
+//		Plugins: []*plugin.Plugin{guardrailplugin.New(guardrailplugin.Config{
+//			InputGuardrails:  []guardrail.Guardrail{secrets},
+//			OutputGuardrails: []guardrail.Guardrail{policyCheck},
+//		})},
+//	})
+package guardrailplugin
+
+import (
+	"slices"
+	"strings"
+
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/agent"
+	"google.golang.org/adk/agent/guardrail"
+	"google.golang.org/adk/model"
+	"google.golang.org/adk/plugin"
+)
+
+const (
+	// MetadataKey is the key in model.LLMResponse.CustomMetadata of the
+	// outcomes of the guardrails which rewrote or blocked the input or the
+	// response: a list of the maps of guardrail.Outcome.Metadata, with the
+	// "input" key telling whether the outcome is on the input of the user.
+	MetadataKey = "adk_guardrail_outcomes"
+
+	// RemovedInput replaces the blocked inputs of the user in the history
+	// sent to the models.
+	RemovedInput = "[input removed by a guardrail]"
+
+	// SafetySettingsGuardrail is the guardrail name of the outcomes of the
+	// responses blocked by the safety settings of the model.
+	SafetySettingsGuardrail = "safety_settings"
+)
+
+// Config is the configuration of the plugin.
+type Config struct {
+	// Name of the plugin. Defaults to "guardrail".
+	Name string
+	// InputGuardrails check the texts of the user sent to the models, in
+	// order. A blocked input is not sent to the model: the fallback message
+	// is returned instead.
+	InputGuardrails []guardrail.Guardrail
+	// OutputGuardrails check the texts of the model responses, in order. The
+	// partial responses of a stream are checked one by one.
+	OutputGuardrails []guardrail.Guardrail
+	// SafetySettings are added to the model requests which do not set them
+	// for the same category. The responses they block are replaced with the
+	// fallback message.
+	SafetySettings []*genai.SafetySetting
+	// Fallback is the message returned instead of a blocked input or
+	// response. Defaults to guardrail.DefaultFallback.
+	Fallback string
+	// OnOutcome, if set, is called for each guardrail which rewrote or
+	// blocked an input or a response, e.g. to log it.
+	OnOutcome func(ctx agent.CallbackContext, o guardrail.Outcome, input bool)
+}
+
+// New returns the guardrail plugin.
+func New(cfg Config) *plugin.Plugin {
+	if cfg.Name == "" {
+		cfg.Name = "guardrail"
+	}
+	if cfg.Fallback == "" {
+		cfg.Fallback = guardrail.DefaultFallback
+	}
+	g := &guardrails{cfg: cfg}
+	return &plugin.Plugin{
+		Name:                cfg.Name,
+		BeforeModelCallback: g.beforeModel,
+		AfterModelCallback:  g.afterModel,
+	}
+}
+
+type guardrails struct {
+	cfg Config
+}
+
+// beforeModel adds the safety settings to the request, and checks the texts
+// of the user. A blocked input skips the model call.
+//
+// The earlier inputs are checked again, since the session keeps them as the
+// user sent them, but only the outcomes of the latest one are reported. The
+// earlier blocked ones are replaced with RemovedInput.
+func (g *guardrails) beforeModel(ctx agent.CallbackContext, req *model.LLMRequest) (*model.LLMResponse, error) {
+	g.addSafetySettings(req)
+	if len(g.cfg.InputGuardrails) == 0 {
+		return nil, nil
+	}
+
+	latest := -1
+	for i, content := range slices.Backward(req.Contents) {
+		if isUserText(content) {
+			latest = i
+			break
+		}
+	}
+	for i, content := range req.Contents {
+		if !isUserText(content) {
+			continue
+		}
+		output, blocked, outcomes, err := guardrail.Apply(ctx, g.cfg.InputGuardrails, text(content))
+		if err != nil {
+			return nil, err
+		}
+		if i == latest {
+			g.report(ctx, outcomes, true)
+			if blocked {
+				return &model.LLMResponse{
+					Content:        genai.NewContentFromText(g.cfg.Fallback, genai.RoleModel),
+					CustomMetadata: map[string]any{MetadataKey: metadata(outcomes, true)},
+				}, nil
+			}
+		}
+		if blocked {
+			output = RemovedInput
+		}
+		if len(outcomes) > 0 {
+			// The contents of the request are shared with the session, so
+			// the rewritten ones are copies.
+			req.Contents[i] = rewrite(content, output)
+		}
+	}
+	return nil, nil
+}
+
+// afterModel checks the texts of the response, and blocks the responses
+// blocked by the safety settings.
+func (g *guardrails) afterModel(ctx agent.CallbackContext, resp *model.LLMResponse, respErr error) (*model.LLMResponse, error) {
+	if respErr != nil || resp == nil {
+		return nil, nil
+	}
+	if blockedBySafety(resp.FinishReason) {
+		o := guardrail.Outcome{
+			Guardrail: SafetySettingsGuardrail,
+			Verdict:   guardrail.Verdict{Action: guardrail.ActionBlock, Reason: string(resp.FinishReason)},
+		}
+		g.report(ctx, []guardrail.Outcome{o}, false)
+		return g.replace(resp, genai.NewContentFromText(g.cfg.Fallback, genai.RoleModel), []guardrail.Outcome{o}), nil
+	}
+	if len(g.cfg.OutputGuardrails) == 0 || resp.Content == nil {
+		return nil, nil
+	}
+	t := text(resp.Content)
+	if t == "" {
+		return nil, nil
+	}
+	output, blocked, outcomes, err := guardrail.Apply(ctx, g.cfg.OutputGuardrails, t)
+	if err != nil {
+		return nil, err
+	}
+	if len(outcomes) == 0 {
+		return nil, nil
+	}
+	g.report(ctx, outcomes, false)
+	if blocked {
+		return g.replace(resp, genai.NewContentFromText(g.cfg.Fallback, genai.RoleModel), outcomes), nil
+	}
+	return g.replace(resp, rewrite(resp.Content, output), outcomes), nil
+}
+
+func (g *guardrails) report(ctx agent.CallbackContext, outcomes []guardrail.Outcome, input bool) {
+	if g.cfg.OnOutcome == nil {
+		return
+	}
+	for _, o := range outcomes {
+		g.cfg.OnOutcome(ctx, o, input)
+	}
+}
+
+// replace returns a copy of the response with the content and the outcomes.
+func (g *guardrails) replace(resp *model.LLMResponse, content *genai.Content, outcomes []guardrail.Outcome) *model.LLMResponse {
+	replaced := *resp
+	replaced.Content = content
+	replaced.CustomMetadata = make(map[string]any, len(resp.CustomMetadata)+1)
+	for k, v := range resp.CustomMetadata {
+		replaced.CustomMetadata[k] = v
+	}
+	replaced.CustomMetadata[MetadataKey] = metadata(outcomes, false)
+	return &replaced
+}
+
+func (g *guardrails) addSafetySettings(req *model.LLMRequest) {
+	if len(g.cfg.SafetySettings) == 0 {
+		return
+	}
+	if req.Config == nil {
+		req.Config = &genai.GenerateContentConfig{}
+	}
+	for _, s := range g.cfg.SafetySettings {
+		if !slices.ContainsFunc(req.Config.SafetySettings, func(set *genai.SafetySetting) bool { return set.Category == s.Category }) {
+			req.Config.SafetySettings = append(req.Config.SafetySettings, s)
+		}
+	}
+}
+
+func blockedBySafety(reason genai.FinishReason) bool {
+	switch reason {
+	case genai.FinishReasonSafety, genai.FinishReasonBlocklist, genai.FinishReasonProhibitedContent,
+		genai.FinishReasonSPII, genai.FinishReasonImageSafety, genai.FinishReasonImageProhibitedContent:
+		return true
+	}
+	return false
+}
+
+func isUserText(content *genai.Content) bool {
+	return content != nil && content.Role == genai.RoleUser && text(content) != ""
+}
+
+// text returns the text of the content, thoughts excluded.
+func text(content *genai.Content) string {
+	var sb strings.Builder
+	for _, part := range content.Parts {
+		if part != nil && !part.Thought {
+			sb.WriteString(part.Text)
+		}
+	}
+	return sb.String()
+}
+
+// rewrite returns a copy of the content with its text parts replaced by the
+// text; the other parts are kept.
+func rewrite(content *genai.Content, text string) *genai.Content {
+	parts := make([]*genai.Part, 0, len(content.Parts))
+	rewritten := false
+	for _, part := range content.Parts {
+		if part == nil || part.Text == "" {
+			parts = append(parts, part)
+		} else if !rewritten {
+			parts = append(parts, genai.NewPartFromText(text))
+			rewritten = true
+		}
+	}
+	return &genai.Content{Role: content.Role, Parts: parts}
+}
+
+func metadata(outcomes []guardrail.Outcome, input bool) []any {
+	m := make([]any, 0, len(outcomes))
+	for _, o := range outcomes {
+		md := o.Metadata()
+		md["input"] = input
+		m = append(m, md)
+	}
+	return m
+}
@@ -0,0 +1,183 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package guardrailplugin_test
+
+import (
+	"context"
+	"iter"
+	"regexp"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/agent"
+	"google.golang.org/adk/agent/guardrail"
+	"google.golang.org/adk/agent/llmagent"
+	"google.golang.org/adk/internal/testutil"
+	"google.golang.org/adk/model"
+	"google.golang.org/adk/plugin"
+	"google.golang.org/adk/plugin/guardrailplugin"
+	"google.golang.org/adk/runner"
+	"google.golang.org/adk/session"
+)
+
+// run runs the agent of the model with the plugin on the inputs, in one
+// session, and returns the texts and the guardrail outcomes of the events.
+func run(t *testing.T, m model.LLM, cfg guardrailplugin.Config, inputs ...string) (texts []string, outcomes []any) {
+	t.Helper()
+	a, err := llmagent.New(llmagent.Config{Name: "agent", Model: m})
+	if err != nil {
+		t.Fatal(err)
+	}
+	r, err := runner.New(runner.Config{
+		AppName:           "app",
+		Agent:             a,
+		SessionService:    session.InMemoryService(),
+		Plugins:           []*plugin.Plugin{guardrailplugin.New(cfg)},
+		AutoCreateSession: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, input := range inputs {
+		for ev, err := range r.Run(t.Context(), "user", "session", genai.NewContentFromText(input, genai.RoleUser), agent.RunConfig{}) {
+			if err != nil {
+				t.Fatal(err)
+			}
+			texts = append(texts, ev.Content.Parts[0].Text)
+			if o, ok := ev.CustomMetadata[guardrailplugin.MetadataKey]; ok {
+				outcomes = append(outcomes, o.([]any)...)
+			}
+		}
+	}
+	return texts, outcomes
+}
+
+func blocklist(t *testing.T, name, pattern string, redact bool) guardrail.Guardrail {
+	t.Helper()
+	g, err := guardrail.NewBlocklist(guardrail.BlocklistConfig{Name: name, Patterns: []*regexp.Regexp{regexp.MustCompile(pattern)}, Redact: redact})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return g
+}
+
+func requestTexts(reqs []*model.LLMRequest) [][]string {
+	var texts [][]string
+	for _, req := range reqs {
+		var contents []string
+		for _, c := range req.Contents {
+			contents = append(contents, c.Parts[0].Text)
+		}
+		texts = append(texts, contents)
+	}
+	return texts
+}
+
+func TestPlugin_Input(t *testing.T) {
+	m := &testutil.MockModel{Responses: []*genai.Content{
+		genai.NewContentFromText("noted", genai.RoleModel),
+	}}
+	var reported []string
+	texts, outcomes := run(t, m, guardrailplugin.Config{
+		InputGuardrails: []guardrail.Guardrail{
+			blocklist(t, "secrets", `(?i)\bpassword\b`, false),
+			blocklist(t, "emails", `[\w.]+@[\w.]+`, true),
+		},
+		OnOutcome: func(ctx agent.CallbackContext, o guardrail.Outcome, input bool) {
+			reported = append(reported, o.Guardrail)
+		},
+	}, "my PASSWORD is 1234", "mail me at jo@example.com")
+
+	if diff := cmp.Diff([]string{guardrail.DefaultFallback, "noted"}, texts); diff != "" {
+		t.Errorf("event texts mismatch (-want +got):\n%s", diff)
+	}
+	wantOutcomes := []any{map[string]any{"guardrail": "secrets", "action": "block", "reason": `matched ["(?i)\\bpassword\\b"]`, "input": true}}
+	if diff := cmp.Diff(wantOutcomes, outcomes); diff != "" {
+		t.Errorf("outcomes mismatch (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff([]string{"secrets", "emails"}, reported); diff != "" {
+		t.Errorf("reported outcomes mismatch (-want +got):\n%s", diff)
+	}
+	// The blocked input never reaches the model, and is removed from the
+	// history.
+	wantRequests := [][]string{{guardrailplugin.RemovedInput, guardrail.DefaultFallback, "mail me at [redacted]"}}
+	if diff := cmp.Diff(wantRequests, requestTexts(m.Requests)); diff != "" {
+		t.Errorf("model requests mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestPlugin_Output(t *testing.T) {
+	judge := &testutil.MockModel{Responses: []*genai.Content{
+		genai.NewContentFromText(`{"action": "allow"}`, genai.RoleModel),
+		genai.NewContentFromText(`{"action": "block", "reason": "insult"}`, genai.RoleModel),
+	}}
+	check, err := guardrail.NewModelCheck(guardrail.ModelCheckConfig{Name: "policy", Model: judge, Policy: "Be polite."})
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := &testutil.MockModel{Responses: []*genai.Content{
+		genai.NewContentFromText("call 555-1234 or 555-9876", genai.RoleModel),
+		genai.NewContentFromText("you fool", genai.RoleModel),
+	}}
+	texts, outcomes := run(t, m, guardrailplugin.Config{
+		OutputGuardrails: []guardrail.Guardrail{blocklist(t, "phones", `\d{3}-\d{4}`, true), check},
+		Fallback:         "blocked",
+	}, "q1", "q2")
+
+	if diff := cmp.Diff([]string{"call [redacted] or [redacted]", "blocked"}, texts); diff != "" {
+		t.Errorf("event texts mismatch (-want +got):\n%s", diff)
+	}
+	wantOutcomes := []any{
+		map[string]any{"guardrail": "phones", "action": "rewrite", "reason": `matched ["\\d{3}-\\d{4}"]`, "input": false},
+		map[string]any{"guardrail": "policy", "action": "block", "reason": "insult", "input": false},
+	}
+	if diff := cmp.Diff(wantOutcomes, outcomes); diff != "" {
+		t.Errorf("outcomes mismatch (-want +got):\n%s", diff)
+	}
+}
+
+// safetyModel answers as a model whose safety settings blocked the
+// response.
+type safetyModel struct {
+	requests []*model.LLMRequest
+}
+
+func (m *safetyModel) Name() string { return "safety" }
+
+func (m *safetyModel) GenerateContent(ctx context.Context, req *model.LLMRequest, stream bool) iter.Seq2[*model.LLMResponse, error] {
+	m.requests = append(m.requests, req)
+	return func(yield func(*model.LLMResponse, error) bool) {
+		yield(&model.LLMResponse{FinishReason: genai.FinishReasonSafety}, nil)
+	}
+}
+
+func TestPlugin_SafetySettings(t *testing.T) {
+	setting := &genai.SafetySetting{Category: genai.HarmCategoryHarassment, Threshold: genai.HarmBlockThresholdBlockLowAndAbove}
+	m := &safetyModel{}
+	texts, outcomes := run(t, m, guardrailplugin.Config{SafetySettings: []*genai.SafetySetting{setting}}, "hi")
+
+	if diff := cmp.Diff([]string{guardrail.DefaultFallback}, texts); diff != "" {
+		t.Errorf("event texts mismatch (-want +got):\n%s", diff)
+	}
+	wantOutcomes := []any{map[string]any{"guardrail": guardrailplugin.SafetySettingsGuardrail, "action": "block", "reason": "SAFETY", "input": false}}
+	if diff := cmp.Diff(wantOutcomes, outcomes); diff != "" {
+		t.Errorf("outcomes mismatch (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff([]*genai.SafetySetting{setting}, m.requests[0].Config.SafetySettings); diff != "" {
+		t.Errorf("safety settings mismatch (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,156 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+type kind string
+
+const (
+	counter   kind = "counter"
+	gauge     kind = "gauge"
+	histogram kind = "histogram"
+)
+
+// family is a metric with its series, one per combination of label values.
+type family struct {
+	name, help string
+	kind       kind
+	buckets    []float64
+	labels     []string
+
+	mu     sync.Mutex
+	series map[string]*series
+}
+
+type series struct {
+	labelValues []string
+	// value is the value of the counters and gauges, and the sum of the
+	// histograms.
+	value float64
+	// counts are the cumulative counts of the buckets of the histograms,
+	// +Inf last.
+	counts []uint64
+}
+
+func newFamily(name, help string, k kind, buckets []float64, labels ...string) *family {
+	return &family{name: name, help: help, kind: k, buckets: buckets, labels: labels, series: make(map[string]*series)}
+}
+
+func (f *family) get(labelValues []string) *series {
+	key := strings.Join(labelValues, "\xff")
+	s, ok := f.series[key]
+	if !ok {
+		s = &series{labelValues: labelValues}
+		if f.kind == histogram {
+			s.counts = make([]uint64, len(f.buckets)+1)
+		}
+		f.series[key] = s
+	}
+	return s
+}
+
+// add adds v to the counter or the gauge.
+func (f *family) add(v float64, labelValues ...string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.get(labelValues).value += v
+}
+
+// observe records v in the histogram.
+func (f *family) observe(v float64, labelValues ...string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	s := f.get(labelValues)
+	s.value += v
+	for i, bound := range f.buckets {
+		if v <= bound {
+			s.counts[i]++
+		}
+	}
+	s.counts[len(f.buckets)]++
+}
+
+// writeTo writes the family in the Prometheus text format, the series sorted
+// by their label values.
+func (f *family) writeTo(w io.Writer) (int64, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "# HELP %s %s\n# TYPE %s %s\n", f.name, f.help, f.name, f.kind)
+	keys := make([]string, 0, len(f.series))
+	for key := range f.series {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	for _, key := range keys {
+		s := f.series[key]
+		if f.kind != histogram {
+			fmt.Fprintf(&b, "%s%s %s\n", f.name, f.labelSet(s.labelValues, ""), formatFloat(s.value))
+			continue
+		}
+		for i, count := range s.counts {
+			le := math.Inf(1)
+			if i < len(f.buckets) {
+				le = f.buckets[i]
+			}
+			fmt.Fprintf(&b, "%s_bucket%s %d\n", f.name, f.labelSet(s.labelValues, formatFloat(le)), count)
+		}
+		fmt.Fprintf(&b, "%s_sum%s %s\n", f.name, f.labelSet(s.labelValues, ""), formatFloat(s.value))
+		fmt.Fprintf(&b, "%s_count%s %d\n", f.name, f.labelSet(s.labelValues, ""), s.counts[len(f.buckets)])
+	}
+	return b.WriteTo(w)
+}
+
+// labelSet returns the labels of the series, with the le label of the
+// histogram buckets if not empty.
+func (f *family) labelSet(values []string, le string) string {
+	var pairs []string
+	for i, name := range f.labels {
+		pairs = append(pairs, name+"="+quote(values[i]))
+	}
+	if le != "" {
+		pairs = append(pairs, "le="+quote(le))
+	}
+	if len(pairs) == 0 {
+		return ""
+	}
+	return "{" + strings.Join(pairs, ",") + "}"
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func quote(s string) string {
+	return `"` + labelEscaper.Replace(s) + `"`
+}
+
+func formatFloat(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
@@ -0,0 +1,235 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package metrics provides a plugin measuring the runs, the model calls and
+// the tool calls, and exposing the metrics in the Prometheus text format,
+// e.g.
+//
+//	m := metrics.New(metrics.Config{})
+//	runner, err := runner.New(runner.Config{
+//		...
+//		Plugins: []*plugin.Plugin{m.Plugin()},
+//	})
+//	http.Handle("/metrics", m.Handler())
+//
+// The metrics, all with the name of the agent as the "agent" label, are:
+//
+//   - adk_invocations_in_flight, a gauge of the runs in progress, by root
+//     agent;
+//   - adk_model_calls_total, a counter of the model calls, by model and
+//     status, "ok" or "error";
+//   - adk_model_call_duration_seconds, a histogram of the durations of the
+//     model calls, by model;
+//   - adk_model_call_tokens, a histogram of the tokens of the model calls, by
+//     model and type, "input" or "output";
+//   - adk_tool_calls_total, a counter of the tool calls, by tool and status;
+//   - adk_tool_call_duration_seconds, a histogram of the durations of the
+//     tool calls, by tool.
+//
+// The callbacks of the plugin short-circuit nothing, but those of the
+// plugins before it may skip them: it should come first.
+package metrics
+
+import (
+	"net/http"
+	"sync"
+	"time"
+
+	"google.golang.org/adk/agent"
+	"google.golang.org/adk/model"
+	"google.golang.org/adk/plugin"
+	"google.golang.org/adk/tool"
+)
+
+var (
+	// DefaultDurationBuckets are the upper bounds of the buckets of the
+	// duration histograms, in seconds.
+	DefaultDurationBuckets = []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60}
+	// DefaultTokenBuckets are the upper bounds of the buckets of the token
+	// histograms.
+	DefaultTokenBuckets = []float64{16, 64, 256, 1024, 4096, 16384, 65536, 262144}
+)
+
+// Config is the configuration of the metrics.
+type Config struct {
+	// Name of the plugin. Defaults to "metrics".
+	Name string
+	// DurationBuckets default to DefaultDurationBuckets.
+	DurationBuckets []float64
+	// TokenBuckets default to DefaultTokenBuckets.
+	TokenBuckets []float64
+}
+
+// Metrics measures the runs of the runners using its plugin. It is safe for
+// concurrent use.
+type Metrics struct {
+	name string
+
+	invocationsInFlight *family
+	modelCalls          *family
+	modelDuration       *family
+	modelTokens         *family
+	toolCalls           *family
+	toolDuration        *family
+
+	mu sync.Mutex
+	// calls are the start times of the model and tool calls in progress, by
+	// invocation ID.
+	calls map[string]map[string]call
+}
+
+type call struct {
+	start time.Time
+	model string
+}
+
+// New returns the metrics.
+func New(cfg Config) *Metrics {
+	if cfg.Name == "" {
+		cfg.Name = "metrics"
+	}
+	if cfg.DurationBuckets == nil {
+		cfg.DurationBuckets = DefaultDurationBuckets
+	}
+	if cfg.TokenBuckets == nil {
+		cfg.TokenBuckets = DefaultTokenBuckets
+	}
+	return &Metrics{
+		name:                cfg.Name,
+		invocationsInFlight: newFamily("adk_invocations_in_flight", "Number of runs in progress.", gauge, nil, "agent"),
+		modelCalls:          newFamily("adk_model_calls_total", "Number of model calls.", counter, nil, "agent", "model", "status"),
+		modelDuration:       newFamily("adk_model_call_duration_seconds", "Duration of the model calls.", histogram, cfg.DurationBuckets, "agent", "model"),
+		modelTokens:         newFamily("adk_model_call_tokens", "Number of tokens of the model calls.", histogram, cfg.TokenBuckets, "agent", "model", "type"),
+		toolCalls:           newFamily("adk_tool_calls_total", "Number of tool calls.", counter, nil, "agent", "tool", "status"),
+		toolDuration:        newFamily("adk_tool_call_duration_seconds", "Duration of the tool calls.", histogram, cfg.DurationBuckets, "agent", "tool"),
+		calls:               make(map[string]map[string]call),
+	}
+}
+
+// Plugin returns the plugin measuring the runs.
+func (m *Metrics) Plugin() *plugin.Plugin {
+	return &plugin.Plugin{
+		Name:                m.name,
+		BeforeRunCallback:   m.beforeRun,
+		AfterRunCallback:    m.afterRun,
+		BeforeModelCallback: m.beforeModel,
+		AfterModelCallback:  m.afterModel,
+		BeforeToolCallback:  m.beforeTool,
+		AfterToolCallback:   m.afterTool,
+	}
+}
+
+// Handler returns the handler serving the metrics in the Prometheus text
+// format.
+func (m *Metrics) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		for _, f := range m.families() {
+			if _, err := f.writeTo(w); err != nil {
+				return
+			}
+		}
+	})
+}
+
+func (m *Metrics) families() []*family {
+	return []*family{m.invocationsInFlight, m.modelCalls, m.modelDuration, m.modelTokens, m.toolCalls, m.toolDuration}
+}
+
+func (m *Metrics) beforeRun(ctx agent.InvocationContext) error {
+	m.invocationsInFlight.add(1, ctx.Agent().Name())
+	return nil
+}
+
+func (m *Metrics) afterRun(ctx agent.InvocationContext) {
+	m.invocationsInFlight.add(-1, ctx.Agent().Name())
+	// The calls skipped by the callbacks of other plugins or of the agents
+	// are never ended.
+	m.mu.Lock()
+	delete(m.calls, ctx.InvocationID())
+	m.mu.Unlock()
+}
+
+// modelCallID identifies the model call of the agent in progress: the calls
+// of an agent are sequential.
+func modelCallID(ctx agent.CallbackContext) string {
+	return ctx.Branch() + "/" + ctx.AgentName()
+}
+
+func (m *Metrics) start(invocationID, id string, c call) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	calls, ok := m.calls[invocationID]
+	if !ok {
+		calls = make(map[string]call)
+		m.calls[invocationID] = calls
+	}
+	calls[id] = c
+}
+
+func (m *Metrics) end(invocationID, id string) (call, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	c, ok := m.calls[invocationID][id]
+	delete(m.calls[invocationID], id)
+	return c, ok
+}
+
+func (m *Metrics) beforeModel(ctx agent.CallbackContext, req *model.LLMRequest) (*model.LLMResponse, error) {
+	m.start(ctx.InvocationID(), modelCallID(ctx), call{start: time.Now(), model: req.Model})
+	return nil, nil
+}
+
+func (m *Metrics) afterModel(ctx agent.CallbackContext, resp *model.LLMResponse, respErr error) (*model.LLMResponse, error) {
+	// The call ends with its first complete response.
+	if respErr == nil && (resp == nil || resp.Partial) {
+		return nil, nil
+	}
+	c, ok := m.end(ctx.InvocationID(), modelCallID(ctx))
+	if !ok {
+		return nil, nil
+	}
+	agentName := ctx.AgentName()
+	m.modelDuration.observe(time.Since(c.start).Seconds(), agentName, c.model)
+	if respErr != nil || resp.ErrorCode != "" {
+		m.modelCalls.add(1, agentName, c.model, "error")
+		return nil, nil
+	}
+	m.modelCalls.add(1, agentName, c.model, "ok")
+	if usage := resp.UsageMetadata; usage != nil {
+		m.modelTokens.observe(float64(usage.PromptTokenCount), agentName, c.model, "input")
+		m.modelTokens.observe(float64(usage.CandidatesTokenCount), agentName, c.model, "output")
+	}
+	return nil, nil
+}
+
+func (m *Metrics) beforeTool(ctx tool.Context, t tool.Tool, args map[string]any) (map[string]any, error) {
+	m.start(ctx.InvocationID(), ctx.FunctionCallID(), call{start: time.Now()})
+	return nil, nil
+}
+
+func (m *Metrics) afterTool(ctx tool.Context, t tool.Tool, args, result map[string]any, err error) (map[string]any, error) {
+	c, ok := m.end(ctx.InvocationID(), ctx.FunctionCallID())
+	if !ok {
+		return nil, nil
+	}
+	agentName := ctx.AgentName()
+	m.toolDuration.observe(time.Since(c.start).Seconds(), agentName, t.Name())
+	status := "ok"
+	if err != nil {
+		status = "error"
+	}
+	m.toolCalls.add(1, agentName, t.Name(), status)
+	return nil, nil
+}
@@ -0,0 +1,114 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics_test
+
+import (
+	"errors"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/agent"
+	"google.golang.org/adk/agent/llmagent"
+	"google.golang.org/adk/model"
+	"google.golang.org/adk/model/mockmodel"
+	"google.golang.org/adk/plugin"
+	"google.golang.org/adk/plugin/metrics"
+	"google.golang.org/adk/runner"
+	"google.golang.org/adk/session"
+	"google.golang.org/adk/tool"
+	"google.golang.org/adk/tool/functiontool"
+)
+
+func TestMetrics(t *testing.T) {
+	type args struct {
+		City string `json:"city"`
+	}
+	newTool := func(name string, err error) tool.Tool {
+		tl, terr := functiontool.New(functiontool.Config{Name: name, Description: name},
+			func(ctx tool.Context, a args) (string, error) { return "sunny", err })
+		if terr != nil {
+			t.Fatal(terr)
+		}
+		return tl
+	}
+	calls := &model.LLMResponse{Content: genai.NewContentFromParts([]*genai.Part{
+		genai.NewPartFromFunctionCall("get_weather", map[string]any{"city": "Paris"}),
+		genai.NewPartFromFunctionCall("get_forecast", map[string]any{"city": "Paris"}),
+	}, genai.RoleModel)}
+	final := mockmodel.Text("It is sunny.")
+	final.UsageMetadata = &genai.GenerateContentResponseUsageMetadata{PromptTokenCount: 100, CandidatesTokenCount: 10}
+	a, err := llmagent.New(llmagent.Config{
+		Name:  "weather_agent",
+		Model: mockmodel.New(calls, final),
+		Tools: []tool.Tool{newTool("get_weather", nil), newTool("get_forecast", errors.New("unavailable"))},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := metrics.New(metrics.Config{DurationBuckets: []float64{60}, TokenBuckets: []float64{50, 500}})
+	r, err := runner.New(runner.Config{
+		AppName:           "app",
+		Agent:             a,
+		SessionService:    session.InMemoryService(),
+		Plugins:           []*plugin.Plugin{m.Plugin()},
+		AutoCreateSession: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	scrape := func() string {
+		rec := httptest.NewRecorder()
+		m.Handler().ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+		if got := rec.Header().Get("Content-Type"); !strings.HasPrefix(got, "text/plain; version=0.0.4") {
+			t.Errorf("Content-Type = %q, want the Prometheus text format", got)
+		}
+		return rec.Body.String()
+	}
+	for _, err := range r.Run(t.Context(), "user", "session", genai.NewContentFromText("Weather?", genai.RoleUser), agent.RunConfig{}) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got, want := scrape(), `adk_invocations_in_flight{agent="weather_agent"} 1`; !strings.Contains(got, want) {
+			t.Errorf("metrics during the run do not contain %q:\n%s", want, got)
+		}
+	}
+
+	got := scrape()
+	for _, want := range []string{
+		"# TYPE adk_invocations_in_flight gauge",
+		`adk_invocations_in_flight{agent="weather_agent"} 0`,
+		"# TYPE adk_model_calls_total counter",
+		`adk_model_calls_total{agent="weather_agent",model="mock",status="ok"} 2`,
+		"# TYPE adk_model_call_duration_seconds histogram",
+		`adk_model_call_duration_seconds_bucket{agent="weather_agent",model="mock",le="60"} 2`,
+		`adk_model_call_duration_seconds_bucket{agent="weather_agent",model="mock",le="+Inf"} 2`,
+		`adk_model_call_duration_seconds_count{agent="weather_agent",model="mock"} 2`,
+		`adk_model_call_tokens_bucket{agent="weather_agent",model="mock",type="input",le="50"} 0`,
+		`adk_model_call_tokens_bucket{agent="weather_agent",model="mock",type="input",le="500"} 1`,
+		`adk_model_call_tokens_sum{agent="weather_agent",model="mock",type="input"} 100`,
+		`adk_model_call_tokens_bucket{agent="weather_agent",model="mock",type="output",le="50"} 1`,
+		`adk_tool_calls_total{agent="weather_agent",tool="get_forecast",status="error"} 1`,
+		`adk_tool_calls_total{agent="weather_agent",tool="get_weather",status="ok"} 1`,
+		`adk_tool_call_duration_seconds_count{agent="weather_agent",tool="get_weather"} 1`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("metrics do not contain %q:\n%s", want, got)
+		}
+	}
+}
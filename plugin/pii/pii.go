@@ -0,0 +1,293 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package pii provides a plugin masking personally identifiable information,
+// e.g. emails, phone numbers or credit card numbers, in the requests sent to
+// the models, in the arguments of the tools and in the telemetry.
+//
+// The sessions keep the information as it is: it is masked in each request,
+// and in the tool arguments the models send back.
+//
+// In the tokenization mode, each value is replaced with a token, e.g.
+// "[EMAIL_3f2a9c1d]", always the same for the same value, so the models can
+// still refer to it. The tools allowed by Config.Authorize receive the
+// original values in place of the tokens in their arguments:
+//
+//	r := pii.New(pii.Config{
+//		Tokenize: true,
+//		Authorize: func(ctx tool.Context, t tool.Tool) bool {
+//			return t.Name() == "send_email"
+//		},
+//	})
+//	telemetry.SetArgsRedactor(r.RedactArgs)
+//	runner, err := runner.New(runner.Config{
+//		...
+//		Plugins: []*plugin.Plugin{r.Plugin()},
+//	})
+package pii
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"regexp"
+	"slices"
+	"sync"
+
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/agent"
+	"google.golang.org/adk/model"
+	"google.golang.org/adk/plugin"
+	"google.golang.org/adk/tool"
+)
+
+// Pattern is a kind of personally identifiable information.
+type Pattern struct {
+	// Name of the kind of information, in upper case, e.g. "EMAIL". It names
+	// the masks and the tokens.
+	Name string
+	// Regexp matches the values.
+	Regexp *regexp.Regexp
+	// Valid, if set, filters the matches, e.g. with a checksum.
+	Valid func(match string) bool
+}
+
+var (
+	// Email matches email addresses.
+	Email = Pattern{Name: "EMAIL", Regexp: regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`)}
+	// Phone matches phone numbers of 10 digits, with an optional country
+	// code.
+	Phone = Pattern{Name: "PHONE", Regexp: regexp.MustCompile(`(?:\+\d{1,3}[-. ]?)?(?:\(\d{3}\)|\b\d{3})[-. ]?\d{3}[-. ]?\d{4}\b`)}
+	// CreditCard matches credit card numbers, checked with the Luhn
+	// algorithm.
+	CreditCard = Pattern{Name: "CREDIT_CARD", Regexp: regexp.MustCompile(`\b(?:\d[ -]?){12,18}\d\b`), Valid: luhn}
+
+	// DefaultPatterns are the patterns of the redactors without ones.
+	DefaultPatterns = []Pattern{Email, CreditCard, Phone}
+)
+
+// tokenRegexp matches the tokens.
+var tokenRegexp = regexp.MustCompile(`\[[A-Z0-9_]+_[0-9a-f]{8}\]`)
+
+// Config is the configuration of a Redactor.
+type Config struct {
+	// Name of the plugin, "pii" if empty.
+	Name string
+	// Patterns are the kinds of information to mask, in order,
+	// DefaultPatterns if empty.
+	Patterns []Pattern
+	// Tokenize replaces the values with tokens instead of masks, e.g.
+	// "[EMAIL_3f2a9c1d]" instead of "[EMAIL]".
+	Tokenize bool
+	// Authorize reports whether the tool receives the original values of
+	// the tokens in its arguments. If nil, no tool does. The arguments of
+	// the other tools are masked.
+	Authorize func(ctx tool.Context, t tool.Tool) bool
+}
+
+// Redactor masks personally identifiable information.
+type Redactor struct {
+	cfg Config
+	key []byte
+
+	mu sync.Mutex
+	// values are the values of the tokens. Since the tokens of a value are
+	// always the same, there is one entry per value seen.
+	values map[string]string
+}
+
+// New returns a redactor.
+func New(cfg Config) *Redactor {
+	if cfg.Name == "" {
+		cfg.Name = "pii"
+	}
+	if len(cfg.Patterns) == 0 {
+		cfg.Patterns = DefaultPatterns
+	}
+	return &Redactor{cfg: cfg, key: []byte(rand.Text()), values: make(map[string]string)}
+}
+
+// Plugin returns the plugin masking the model requests and the tool
+// arguments.
+func (r *Redactor) Plugin() *plugin.Plugin {
+	return &plugin.Plugin{
+		Name:                r.cfg.Name,
+		BeforeModelCallback: r.beforeModel,
+		BeforeToolCallback:  r.beforeTool,
+	}
+}
+
+// Mask returns the text with the information masked or tokenized.
+func (r *Redactor) Mask(text string) string {
+	for _, p := range r.cfg.Patterns {
+		text = p.Regexp.ReplaceAllStringFunc(text, func(match string) string {
+			if p.Valid != nil && !p.Valid(match) {
+				return match
+			}
+			if !r.cfg.Tokenize {
+				return "[" + p.Name + "]"
+			}
+			return r.token(p.Name, match)
+		})
+	}
+	return text
+}
+
+// Unmask returns the text with the tokens replaced with their values. The
+// unknown tokens are kept.
+func (r *Redactor) Unmask(text string) string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return tokenRegexp.ReplaceAllStringFunc(text, func(token string) string {
+		if value, ok := r.values[token]; ok {
+			return value
+		}
+		return token
+	})
+}
+
+// RedactArgs returns a copy of the arguments with the information masked.
+// It is a telemetry.ArgsRedactor.
+func (r *Redactor) RedactArgs(toolName string, args map[string]any) map[string]any {
+	masked, _ := r.maskValue(args, r.Mask).(map[string]any)
+	return masked
+}
+
+func (r *Redactor) token(name, value string) string {
+	mac := hmac.New(sha256.New, r.key)
+	mac.Write([]byte(value))
+	token := "[" + name + "_" + hex.EncodeToString(mac.Sum(nil)[:4]) + "]"
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.values[token] = value
+	return token
+}
+
+// beforeModel masks the texts, the function calls and the function responses
+// of the request.
+func (r *Redactor) beforeModel(ctx agent.CallbackContext, req *model.LLMRequest) (*model.LLMResponse, error) {
+	for i, content := range req.Contents {
+		if content == nil {
+			continue
+		}
+		var parts []*genai.Part
+		for j, part := range content.Parts {
+			masked := r.maskPart(part)
+			if masked == part {
+				continue
+			}
+			if parts == nil {
+				parts = slices.Clone(content.Parts)
+			}
+			parts[j] = masked
+		}
+		// The contents of the request are shared with the session, so the
+		// masked ones are copies.
+		if parts != nil {
+			req.Contents[i] = &genai.Content{Role: content.Role, Parts: parts}
+		}
+	}
+	return nil, nil
+}
+
+// maskPart returns the part, or a masked copy of it.
+func (r *Redactor) maskPart(part *genai.Part) *genai.Part {
+	if part == nil {
+		return part
+	}
+	masked := *part
+	changed := false
+	if part.Text != "" {
+		masked.Text = r.Mask(part.Text)
+		changed = masked.Text != part.Text
+	}
+	if fc := part.FunctionCall; fc != nil {
+		call := *fc
+		call.Args, _ = r.maskValue(fc.Args, r.Mask).(map[string]any)
+		masked.FunctionCall = &call
+		changed = true
+	}
+	if fr := part.FunctionResponse; fr != nil {
+		resp := *fr
+		resp.Response, _ = r.maskValue(fr.Response, r.Mask).(map[string]any)
+		masked.FunctionResponse = &resp
+		changed = true
+	}
+	if !changed {
+		return part
+	}
+	return &masked
+}
+
+// beforeTool replaces the tokens of the arguments with their values if the
+// tool is authorized, or masks them otherwise. The arguments are changed in
+// place, since a callback returning arguments skips the tool.
+func (r *Redactor) beforeTool(ctx tool.Context, t tool.Tool, args map[string]any) (map[string]any, error) {
+	f := r.Mask
+	if r.cfg.Tokenize && r.cfg.Authorize != nil && r.cfg.Authorize(ctx, t) {
+		f = r.Unmask
+	}
+	for k, v := range args {
+		args[k] = r.maskValue(v, f)
+	}
+	return nil, nil
+}
+
+// maskValue returns a copy of the value with f applied to its strings.
+func (r *Redactor) maskValue(v any, f func(string) string) any {
+	switch v := v.(type) {
+	case string:
+		return f(v)
+	case map[string]any:
+		if v == nil {
+			return v
+		}
+		m := make(map[string]any, len(v))
+		for k, e := range v {
+			m[k] = r.maskValue(e, f)
+		}
+		return m
+	case []any:
+		s := make([]any, len(v))
+		for i, e := range v {
+			s[i] = r.maskValue(e, f)
+		}
+		return s
+	default:
+		return v
+	}
+}
+
+// luhn reports whether the digits of the number pass the Luhn checksum.
+func luhn(number string) bool {
+	sum, double := 0, false
+	for i := len(number) - 1; i >= 0; i-- {
+		c := number[i]
+		if c < '0' || c > '9' {
+			continue
+		}
+		d := int(c - '0')
+		if double {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		double = !double
+	}
+	return sum%10 == 0
+}
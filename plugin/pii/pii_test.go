@@ -0,0 +1,157 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pii_test
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/agent"
+	"google.golang.org/adk/agent/llmagent"
+	"google.golang.org/adk/internal/testutil"
+	"google.golang.org/adk/plugin"
+	"google.golang.org/adk/plugin/pii"
+	"google.golang.org/adk/runner"
+	"google.golang.org/adk/session"
+	"google.golang.org/adk/tool"
+	"google.golang.org/adk/tool/functiontool"
+)
+
+func TestRedactor_Mask(t *testing.T) {
+	ssn := pii.Pattern{Name: "SSN", Regexp: regexp.MustCompile(`\b\d{3}-\d{2}-\d{4}\b`)}
+	r := pii.New(pii.Config{Patterns: append([]pii.Pattern{ssn}, pii.DefaultPatterns...)})
+
+	tests := []struct {
+		text, want string
+	}{
+		{"mail jo.doe+x@example.co.uk now", "mail [EMAIL] now"},
+		{"call (555) 123-4567 or +1 555.123.4567", "call [PHONE] or [PHONE]"},
+		{"card 4111 1111 1111 1111, not 4111 1111 1111 1112", "card [CREDIT_CARD], not 4111 1111 1111 1112"},
+		{"ssn 123-45-6789", "ssn [SSN]"},
+		{"order 12345", "order 12345"},
+	}
+	for _, tc := range tests {
+		if got := r.Mask(tc.text); got != tc.want {
+			t.Errorf("Mask(%q) = %q, want %q", tc.text, got, tc.want)
+		}
+	}
+}
+
+func TestRedactor_Tokenize(t *testing.T) {
+	r := pii.New(pii.Config{Tokenize: true})
+
+	masked := r.Mask("jo@example.com and ann@example.com, again jo@example.com")
+	tokens := regexp.MustCompile(`\[EMAIL_[0-9a-f]{8}\]`).FindAllString(masked, -1)
+	if len(tokens) != 3 || tokens[0] != tokens[2] || tokens[0] == tokens[1] {
+		t.Fatalf("Mask() = %q, want the same token for the same value", masked)
+	}
+	if got, want := r.Unmask(masked+" [EMAIL_00000000]"), "jo@example.com and ann@example.com, again jo@example.com [EMAIL_00000000]"; got != want {
+		t.Errorf("Unmask() = %q, want %q", got, want)
+	}
+	if got := pii.New(pii.Config{Tokenize: true}).Unmask(masked); got != masked {
+		t.Errorf("Unmask() with another redactor = %q, want %q", got, masked)
+	}
+}
+
+func TestRedactor_Plugin(t *testing.T) {
+	r := pii.New(pii.Config{
+		Tokenize: true,
+		Authorize: func(ctx tool.Context, t tool.Tool) bool {
+			return t.Name() == "send_email"
+		},
+	})
+	token := r.Mask("jo@example.com")
+
+	got := map[string]string{}
+	newTool := func(name string) tool.Tool {
+		tl, err := functiontool.New(functiontool.Config{Name: name, Description: name},
+			func(ctx tool.Context, args struct {
+				To string `json:"to"`
+			}) (string, error) {
+				got[name] = args.To
+				return "sent to " + args.To, nil
+			})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return tl
+	}
+
+	m := &testutil.MockModel{Responses: []*genai.Content{
+		genai.NewContentFromParts([]*genai.Part{
+			genai.NewPartFromFunctionCall("send_email", map[string]any{"to": token}),
+			genai.NewPartFromFunctionCall("audit", map[string]any{"to": "ann@example.com"}),
+		}, genai.RoleModel),
+		genai.NewContentFromText("done", genai.RoleModel),
+	}}
+	a, err := llmagent.New(llmagent.Config{
+		Name:  "agent",
+		Model: m,
+		Tools: []tool.Tool{newTool("send_email"), newTool("audit")},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	run, err := runner.New(runner.Config{
+		AppName:           "app",
+		Agent:             a,
+		SessionService:    session.InMemoryService(),
+		Plugins:           []*plugin.Plugin{r.Plugin()},
+		AutoCreateSession: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, err := range run.Run(t.Context(), "user", "session", genai.NewContentFromText("write to jo@example.com", genai.RoleUser), agent.RunConfig{}) {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	want := map[string]string{"send_email": "jo@example.com", "audit": r.Mask("ann@example.com")}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("tool arguments mismatch (-want +got):\n%s", diff)
+	}
+	if len(m.Requests) != 2 {
+		t.Fatalf("got %d model requests, want 2", len(m.Requests))
+	}
+	if got, want := m.Requests[0].Contents[0].Parts[0].Text, "write to "+token; got != want {
+		t.Errorf("first request text = %q, want %q", got, want)
+	}
+	for _, content := range m.Requests[1].Contents {
+		for _, part := range content.Parts {
+			if fr := part.FunctionResponse; fr != nil && fr.Name == "send_email" {
+				if got, want := fr.Response["result"], "sent to "+token; got != want {
+					t.Errorf("send_email response sent to the model = %q, want %q", got, want)
+				}
+			}
+		}
+	}
+}
+
+func TestRedactor_RedactArgs(t *testing.T) {
+	r := pii.New(pii.Config{})
+	args := map[string]any{"to": []any{"jo@example.com"}, "meta": map[string]any{"phone": "555-123-4567"}, "n": 3}
+	want := map[string]any{"to": []any{"[EMAIL]"}, "meta": map[string]any{"phone": "[PHONE]"}, "n": 3}
+	if diff := cmp.Diff(want, r.RedactArgs("tool", args)); diff != "" {
+		t.Errorf("RedactArgs() mismatch (-want +got):\n%s", diff)
+	}
+	if args["to"].([]any)[0] != "jo@example.com" {
+		t.Errorf("RedactArgs() modified the arguments")
+	}
+}
@@ -0,0 +1,99 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package plugin defines plugins: sets of callbacks registered on a runner,
+// see runner.Config.Plugins, which apply across all its agents, models and
+// tools, e.g. for guardrails, caching or observability, without changing the
+// agents.
+//
+// The callbacks of a plugin have the semantics of the callbacks of the
+// agents, see agent.Config and llmagent.Config: a callback returning a
+// non-nil result or an error short-circuits the step, e.g. a before model
+// callback returning a response skips the model call. The callbacks of the
+// plugins run in the order of the plugins, before the callbacks of the
+// agents, which are skipped once a plugin short-circuits the step.
+package plugin
+
+import (
+	"errors"
+	"fmt"
+
+	"google.golang.org/adk/agent"
+	"google.golang.org/adk/agent/llmagent"
+	"google.golang.org/adk/session"
+)
+
+// Plugin is a set of callbacks. All of them are optional.
+type Plugin struct {
+	// Name identifies the plugin. It must be unique among the plugins of a
+	// runner.
+	Name string
+
+	// BeforeRunCallback is called at the start of each run of the runner,
+	// once the user message is stored, before the agent runs.
+	BeforeRunCallback BeforeRunCallback
+	// AfterRunCallback is called once each run of the runner ends, however
+	// it ends: completed, failed or stopped by the caller. It is only called
+	// if the before run callback of the plugin, if any, succeeded.
+	AfterRunCallback AfterRunCallback
+	// BeforeAgentCallback is called before each agent run.
+	BeforeAgentCallback agent.BeforeAgentCallback
+	// AfterAgentCallback is called after each agent run.
+	AfterAgentCallback agent.AfterAgentCallback
+	// BeforeModelCallback is called before each model call of the LLM
+	// agents, and may change the request.
+	BeforeModelCallback llmagent.BeforeModelCallback
+	// AfterModelCallback is called after each model response of the LLM
+	// agents.
+	AfterModelCallback llmagent.AfterModelCallback
+	// BeforeToolCallback is called before each tool call of the LLM agents,
+	// and may change the arguments.
+	BeforeToolCallback llmagent.BeforeToolCallback
+	// AfterToolCallback is called after each tool call of the LLM agents.
+	AfterToolCallback llmagent.AfterToolCallback
+	// OnEventCallback is called for each event of the runs, partial ones
+	// included, before it is stored in the session and yielded. It may
+	// modify the event, or return another one to replace it.
+	OnEventCallback OnEventCallback
+}
+
+// BeforeRunCallback is called at the start of a run. An error fails the run,
+// without running the agent.
+type BeforeRunCallback func(ctx agent.InvocationContext) error
+
+// AfterRunCallback is called once a run ends.
+type AfterRunCallback func(ctx agent.InvocationContext)
+
+// OnEventCallback is called for each event of a run. If it returns a non-nil
+// event, the event is replaced with it, and the callbacks of the next plugins
+// receive it.
+type OnEventCallback func(ctx agent.InvocationContext, event *session.Event) (*session.Event, error)
+
+// Validate checks the names of the plugins.
+func Validate(plugins []*Plugin) error {
+	names := make(map[string]bool)
+	for _, p := range plugins {
+		if p == nil {
+			return errors.New("plugin is nil")
+		}
+		if p.Name == "" {
+			return errors.New("plugin name is required")
+		}
+		if names[p.Name] {
+			return fmt.Errorf("duplicate plugin name %q", p.Name)
+		}
+		names[p.Name] = true
+	}
+	return nil
+}
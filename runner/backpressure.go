@@ -0,0 +1,72 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package runner
+
+import (
+	"iter"
+
+	"google.golang.org/adk/agent"
+	"google.golang.org/adk/session"
+)
+
+// bufferEvents runs events in a goroutine, buffering up to size events ahead
+// of the consumer. When the buffer is full, the producer blocks, or drops
+// partial events with agent.EventOverflowDropPartial.
+//
+// The returned iterator does not return before the producer has stopped, so
+// that no agent work outlives the run.
+func bufferEvents(events iter.Seq2[*session.Event, error], size int, overflow agent.EventOverflow) iter.Seq2[*session.Event, error] {
+	type item struct {
+		event *session.Event
+		err   error
+	}
+	return func(yield func(*session.Event, error) bool) {
+		items := make(chan item, size)
+		stop := make(chan struct{})
+		go func() {
+			defer close(items)
+			events(func(event *session.Event, err error) bool {
+				it := item{event: event, err: err}
+				if overflow == agent.EventOverflowDropPartial && err == nil && event != nil && event.Partial {
+					select {
+					case items <- it:
+					case <-stop:
+						return false
+					default:
+						// The consumer is behind, drop the progress event.
+					}
+					return true
+				}
+				select {
+				case items <- it:
+					return true
+				case <-stop:
+					return false
+				}
+			})
+		}()
+		defer func() {
+			close(stop)
+			for range items {
+			}
+		}()
+
+		for it := range items {
+			if !yield(it.event, it.err) {
+				return
+			}
+		}
+	}
+}
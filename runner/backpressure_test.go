@@ -0,0 +1,105 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package runner
+
+import (
+	"iter"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"google.golang.org/adk/agent"
+	"google.golang.org/adk/session"
+)
+
+// producer yields n events, every fifth one non-partial, and records how many
+// it has produced.
+func producer(n int, produced *atomic.Int64) iter.Seq2[*session.Event, error] {
+	return func(yield func(*session.Event, error) bool) {
+		for i := 1; i <= n; i++ {
+			ev := session.NewEvent("inv")
+			ev.Sequence = int64(i)
+			ev.Partial = i%5 != 0
+			produced.Add(1)
+			if !yield(ev, nil) {
+				return
+			}
+		}
+	}
+}
+
+func TestBufferEvents_Block(t *testing.T) {
+	var produced atomic.Int64
+	events := bufferEvents(producer(20, &produced), 3, agent.EventOverflowBlock)
+
+	var got []int64
+	for ev, err := range events {
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(got) == 0 {
+			// Let the producer fill the buffer while the consumer is slow.
+			time.Sleep(50 * time.Millisecond)
+			// The first event, the buffer and the event blocked on the full
+			// buffer.
+			if p := produced.Load(); p > 5 {
+				t.Errorf("produced %d events ahead of the consumer, want at most 5", p)
+			}
+		}
+		got = append(got, ev.Sequence)
+	}
+	if len(got) != 20 {
+		t.Errorf("got %d events, want all 20", len(got))
+	}
+}
+
+func TestBufferEvents_DropPartial(t *testing.T) {
+	var produced atomic.Int64
+	events := bufferEvents(producer(100, &produced), 2, agent.EventOverflowDropPartial)
+
+	var got, nonPartial int
+	for ev, err := range events {
+		if err != nil {
+			t.Fatal(err)
+		}
+		got++
+		if !ev.Partial {
+			nonPartial++
+		}
+		time.Sleep(time.Millisecond)
+	}
+	if produced.Load() != 100 {
+		t.Errorf("produced %d events, want 100", produced.Load())
+	}
+	if nonPartial != 20 {
+		t.Errorf("got %d non-partial events, want all 20", nonPartial)
+	}
+	if got >= 100 {
+		t.Errorf("got %d events, want partial events to be dropped", got)
+	}
+}
+
+func TestBufferEvents_EarlyStop(t *testing.T) {
+	var produced atomic.Int64
+	for range bufferEvents(producer(1000, &produced), 5, agent.EventOverflowBlock) {
+		break
+	}
+	// The iterator waits for the producer to stop.
+	p := produced.Load()
+	time.Sleep(20 * time.Millisecond)
+	if produced.Load() != p || p > 10 {
+		t.Errorf("producer kept running after the consumer stopped: produced %d then %d", p, produced.Load())
+	}
+}
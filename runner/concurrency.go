@@ -0,0 +1,250 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package runner
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"google.golang.org/adk/agent"
+	"google.golang.org/adk/session"
+)
+
+// ErrRunnerClosed is the error of the runs started after [Runner.Drain] or
+// [Runner.Close], and of the runs they interrupt.
+var ErrRunnerClosed = errors.New("runner is closed")
+
+// ErrRunnerBusy is the error of a run which waited longer than
+// Concurrency.MaxWait for its session or for a slot.
+var ErrRunnerBusy = errors.New("runner is busy")
+
+// ClosedErrorCode is the error code of the event reporting that a run was
+// interrupted by the shutdown of the runner.
+const ClosedErrorCode = "RUNNER_CLOSED"
+
+// Concurrency configures how the runner shares itself between the runs of
+// many sessions, e.g. in a server.
+type Concurrency struct {
+	// MaxRuns is the maximum number of runs at the same time, across the
+	// sessions. The runs over it wait for a slot. Zero means no limit.
+	MaxRuns int
+	// SerializeSessions makes a run wait for the end of the runs of its
+	// session started before it, so that the concurrent messages to a
+	// session, e.g. from two tabs of the user, do not interleave their
+	// events and state changes.
+	SerializeSessions bool
+	// MaxWait is how long a run may wait for its session or for a slot
+	// before failing with ErrRunnerBusy. With the default of zero, it waits
+	// as long as its context allows.
+	MaxWait time.Duration
+}
+
+// runs tracks the active runs of a runner, and bounds and serializes them.
+type runs struct {
+	slots     chan struct{} // nil without limit
+	serialize bool
+	maxWait   time.Duration
+
+	mu       sync.Mutex
+	closed   bool
+	nextID   int64
+	active   map[int64]context.CancelCauseFunc
+	sessions map[string]*sessionLock
+	wg       sync.WaitGroup
+}
+
+// sessionLock serializes the runs of a session.
+type sessionLock struct {
+	ch   chan struct{}
+	refs int
+}
+
+func newRuns(c *Concurrency) *runs {
+	rs := &runs{
+		active:   make(map[int64]context.CancelCauseFunc),
+		sessions: make(map[string]*sessionLock),
+	}
+	if c != nil {
+		if c.MaxRuns > 0 {
+			rs.slots = make(chan struct{}, c.MaxRuns)
+		}
+		rs.serialize, rs.maxWait = c.SerializeSessions, c.MaxWait
+	}
+	return rs
+}
+
+// start admits a run of the session: it waits for the session and for a
+// slot. It returns the context of the run, canceled with ErrRunnerClosed if
+// the runner is closed before the run ends, and the function to call once
+// the run ends.
+func (rs *runs) start(ctx context.Context, userID, sessionID string) (context.Context, func(), error) {
+	rs.mu.Lock()
+	if rs.closed {
+		rs.mu.Unlock()
+		return nil, nil, ErrRunnerClosed
+	}
+	ctx, cancel := context.WithCancelCause(ctx)
+	id := rs.nextID
+	rs.nextID++
+	rs.active[id] = cancel
+	rs.wg.Add(1)
+	rs.mu.Unlock()
+
+	releases := []func(){func() {
+		rs.mu.Lock()
+		delete(rs.active, id)
+		rs.mu.Unlock()
+		cancel(nil)
+		rs.wg.Done()
+	}}
+	release := func() {
+		for i := len(releases) - 1; i >= 0; i-- {
+			releases[i]()
+		}
+	}
+
+	waitCtx := ctx
+	if rs.maxWait > 0 {
+		var cancelWait context.CancelFunc
+		waitCtx, cancelWait = context.WithTimeout(ctx, rs.maxWait)
+		defer cancelWait()
+	}
+	// The run takes its session before its slot, so that the runs waiting
+	// for their session hold no slot.
+	if rs.serialize && sessionID != "" {
+		unlock, err := rs.lockSession(waitCtx, userID+"/"+sessionID)
+		if err != nil {
+			err = waitError(ctx)
+			release()
+			return nil, nil, err
+		}
+		releases = append(releases, unlock)
+	}
+	if rs.slots != nil {
+		select {
+		case rs.slots <- struct{}{}:
+			releases = append(releases, func() { <-rs.slots })
+		case <-waitCtx.Done():
+			err := waitError(ctx)
+			release()
+			return nil, nil, err
+		}
+	}
+	return ctx, release, nil
+}
+
+// waitError returns the error of a run which stopped waiting.
+func waitError(ctx context.Context) error {
+	if ctx.Err() != nil {
+		return context.Cause(ctx)
+	}
+	return ErrRunnerBusy
+}
+
+// lockSession waits for the session with the key, and returns the function
+// to release it.
+func (rs *runs) lockSession(ctx context.Context, key string) (func(), error) {
+	rs.mu.Lock()
+	l := rs.sessions[key]
+	if l == nil {
+		l = &sessionLock{ch: make(chan struct{}, 1)}
+		rs.sessions[key] = l
+	}
+	l.refs++
+	rs.mu.Unlock()
+
+	unref := func() {
+		rs.mu.Lock()
+		l.refs--
+		if l.refs == 0 {
+			delete(rs.sessions, key)
+		}
+		rs.mu.Unlock()
+	}
+	select {
+	case l.ch <- struct{}{}:
+		return func() {
+			<-l.ch
+			unref()
+		}, nil
+	case <-ctx.Done():
+		unref()
+		return nil, ctx.Err()
+	}
+}
+
+// Drain shuts the runner down gracefully, e.g. on SIGTERM: the runs started
+// after it fail with ErrRunnerClosed, and it waits for the active runs to
+// end. Once ctx is done, it cancels the remaining runs, whose model and tool
+// calls see their context canceled with ErrRunnerClosed as cause, waits for
+// them to return, and returns the error of ctx.
+//
+// The events of an interrupted run are stored in the session as they are
+// yielded, so the session holds its progress so far, followed by an event
+// with ClosedErrorCode as error code; the run then fails with
+// ErrRunnerClosed. The conversation can continue from there with another
+// runner sharing the session service, and the paused invocations remain
+// resumable, see PausedInvocations.
+//
+// The runs wait for their caller to consume their events, so the callers must
+// keep iterating, or stop, for Drain to return.
+func (r *Runner) Drain(ctx context.Context) error {
+	rs := r.runs
+	rs.mu.Lock()
+	rs.closed = true
+	rs.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		rs.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+	}
+
+	rs.mu.Lock()
+	for _, cancel := range rs.active {
+		cancel(ErrRunnerClosed)
+	}
+	rs.mu.Unlock()
+	<-done
+	return ctx.Err()
+}
+
+// Close shuts the runner down right away: it is Drain with no time for the
+// active runs to end.
+func (r *Runner) Close() error {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	r.Drain(ctx)
+	return nil
+}
+
+// closedEvent returns the event reporting that the run was interrupted by the
+// shutdown of the runner, which is stored in the session.
+func closedEvent(ctx agent.InvocationContext, author, correlationID string) *session.Event {
+	event := ErrorEvent(ErrRunnerClosed)
+	event.InvocationID = ctx.InvocationID()
+	event.Author = author
+	event.CorrelationID = correlationID
+	event.TurnID = ctx.InvocationID()
+	event.ErrorMessage = "the run was interrupted by the shutdown of the runner"
+	return event
+}
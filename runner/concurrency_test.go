@@ -0,0 +1,242 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package runner
+
+import (
+	"context"
+	"errors"
+	"iter"
+	"testing"
+	"time"
+
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/agent"
+	"google.golang.org/adk/agent/llmagent"
+	"google.golang.org/adk/model"
+	"google.golang.org/adk/session"
+)
+
+// gatedLLM signals each call on started, and answers it once released.
+type gatedLLM struct {
+	started chan struct{}
+	release chan struct{}
+}
+
+func newGatedLLM() *gatedLLM {
+	return &gatedLLM{started: make(chan struct{}, 10), release: make(chan struct{})}
+}
+
+func (m *gatedLLM) Name() string {
+	return "gated"
+}
+
+func (m *gatedLLM) GenerateContent(ctx context.Context, req *model.LLMRequest, stream bool) iter.Seq2[*model.LLMResponse, error] {
+	return func(yield func(*model.LLMResponse, error) bool) {
+		m.started <- struct{}{}
+		select {
+		case <-m.release:
+			yield(&model.LLMResponse{Content: genai.NewContentFromText("done", genai.RoleModel)}, nil)
+		case <-ctx.Done():
+			yield(nil, ctx.Err())
+		}
+	}
+}
+
+func newConcurrentRunner(t *testing.T, llm model.LLM, c *Concurrency) (*Runner, session.Service) {
+	t.Helper()
+	sessionService := session.InMemoryService()
+	r, err := New(Config{
+		AppName:           "testApp",
+		Agent:             must(llmagent.New(llmagent.Config{Name: "agent", Model: llm})),
+		SessionService:    sessionService,
+		Concurrency:       c,
+		AutoCreateSession: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return r, sessionService
+}
+
+// runAsync runs the runner in the background, and returns the channel
+// receiving the errors of the run once it ends.
+func runAsync(r *Runner, ctx context.Context, sessionID string) <-chan []error {
+	done := make(chan []error, 1)
+	go func() {
+		var errs []error
+		for _, err := range r.Run(ctx, "user", sessionID, genai.NewContentFromText("hi", genai.RoleUser), agent.RunConfig{}) {
+			if err != nil {
+				errs = append(errs, err)
+			}
+		}
+		done <- errs
+	}()
+	return done
+}
+
+func waitStarted(t *testing.T, llm *gatedLLM) {
+	t.Helper()
+	select {
+	case <-llm.started:
+	case <-time.After(5 * time.Second):
+		t.Fatal("model call did not start")
+	}
+}
+
+func notStarted(t *testing.T, llm *gatedLLM) {
+	t.Helper()
+	select {
+	case <-llm.started:
+		t.Fatal("model call started, want it to wait")
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestRunner_SerializeSessions(t *testing.T) {
+	llm := newGatedLLM()
+	r, _ := newConcurrentRunner(t, llm, &Concurrency{SerializeSessions: true})
+
+	first := runAsync(r, t.Context(), "s1")
+	waitStarted(t, llm)
+	second := runAsync(r, t.Context(), "s1")
+	other := runAsync(r, t.Context(), "s2")
+	// The run of another session is not serialized with the first one.
+	waitStarted(t, llm)
+	notStarted(t, llm)
+
+	llm.release <- struct{}{}
+	llm.release <- struct{}{}
+	waitStarted(t, llm)
+	llm.release <- struct{}{}
+	for _, done := range []<-chan []error{first, second, other} {
+		if errs := <-done; len(errs) > 0 {
+			t.Errorf("Run() errors = %v", errs)
+		}
+	}
+}
+
+func TestRunner_MaxRuns(t *testing.T) {
+	llm := newGatedLLM()
+	r, _ := newConcurrentRunner(t, llm, &Concurrency{MaxRuns: 1, MaxWait: 20 * time.Millisecond})
+
+	first := runAsync(r, t.Context(), "s1")
+	waitStarted(t, llm)
+	if errs := <-runAsync(r, t.Context(), "s2"); len(errs) != 1 || !errors.Is(errs[0], ErrRunnerBusy) {
+		t.Errorf("Run() over MaxRuns errors = %v, want %v", errs, ErrRunnerBusy)
+	}
+
+	llm.release <- struct{}{}
+	if errs := <-first; len(errs) > 0 {
+		t.Errorf("Run() errors = %v", errs)
+	}
+	third := runAsync(r, t.Context(), "s3")
+	waitStarted(t, llm)
+	llm.release <- struct{}{}
+	if errs := <-third; len(errs) > 0 {
+		t.Errorf("Run() after the first run errors = %v", errs)
+	}
+}
+
+func TestRunner_Drain(t *testing.T) {
+	t.Run("waits for active runs", func(t *testing.T) {
+		llm := newGatedLLM()
+		r, _ := newConcurrentRunner(t, llm, nil)
+
+		run := runAsync(r, t.Context(), "s1")
+		waitStarted(t, llm)
+		drained := make(chan error, 1)
+		go func() { drained <- r.Drain(t.Context()) }()
+		// Wait for Drain to close the runner.
+		for {
+			r.runs.mu.Lock()
+			closed := r.runs.closed
+			r.runs.mu.Unlock()
+			if closed {
+				break
+			}
+			time.Sleep(time.Millisecond)
+		}
+		if errs := <-runAsync(r, t.Context(), "s2"); len(errs) != 1 || !errors.Is(errs[0], ErrRunnerClosed) {
+			t.Errorf("Run() after Drain errors = %v, want %v", errs, ErrRunnerClosed)
+		}
+
+		llm.release <- struct{}{}
+		if errs := <-run; len(errs) > 0 {
+			t.Errorf("Run() errors = %v", errs)
+		}
+		if err := <-drained; err != nil {
+			t.Errorf("Drain() error = %v", err)
+		}
+	})
+
+	t.Run("interrupts remaining runs", func(t *testing.T) {
+		llm := newGatedLLM()
+		r, sessionService := newConcurrentRunner(t, llm, nil)
+
+		run := runAsync(r, t.Context(), "s1")
+		waitStarted(t, llm)
+		ctx, cancel := context.WithTimeout(t.Context(), 20*time.Millisecond)
+		defer cancel()
+		if err := r.Drain(ctx); !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("Drain() error = %v, want %v", err, context.DeadlineExceeded)
+		}
+		if errs := <-run; len(errs) != 1 || !errors.Is(errs[0], ErrRunnerClosed) {
+			t.Errorf("interrupted Run() errors = %v, want %v", errs, ErrRunnerClosed)
+		}
+
+		resp, err := sessionService.Get(t.Context(), &session.GetRequest{AppName: "testApp", UserID: "user", SessionID: "s1"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		events := resp.Session.Events()
+		last := events.At(events.Len() - 1)
+		if last.ErrorCode != ClosedErrorCode || last.Author != "agent" {
+			t.Errorf("last stored event = {Author: %q, ErrorCode: %q}, want {Author: %q, ErrorCode: %q}", last.Author, last.ErrorCode, "agent", ClosedErrorCode)
+		}
+	})
+
+	t.Run("close interrupts right away", func(t *testing.T) {
+		r, _ := newConcurrentRunner(t, blockingLLM{}, nil)
+		done := make(chan []error, 1)
+		go func() {
+			var errs []error
+			for ev, err := range r.Run(t.Context(), "user", "s1", genai.NewContentFromText("hi", genai.RoleUser), agent.RunConfig{}) {
+				if err != nil {
+					errs = append(errs, err)
+				} else if ev.ErrorCode != ClosedErrorCode {
+					t.Errorf("event error code = %q, want %q", ev.ErrorCode, ClosedErrorCode)
+				}
+			}
+			done <- errs
+		}()
+		// Wait for the run to be active.
+		for {
+			r.runs.mu.Lock()
+			active := len(r.runs.active)
+			r.runs.mu.Unlock()
+			if active > 0 {
+				break
+			}
+			time.Sleep(time.Millisecond)
+		}
+		if err := r.Close(); err != nil {
+			t.Errorf("Close() error = %v", err)
+		}
+		if errs := <-done; len(errs) != 1 || !errors.Is(errs[0], ErrRunnerClosed) {
+			t.Errorf("closed Run() errors = %v, want %v", errs, ErrRunnerClosed)
+		}
+	})
+}
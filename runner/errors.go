@@ -0,0 +1,146 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package runner
+
+import (
+	"context"
+	"errors"
+	"math"
+	"net/http"
+	"time"
+
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/agent"
+	"google.golang.org/adk/model"
+	"google.golang.org/adk/model/retrymodel"
+	"google.golang.org/adk/session"
+	"google.golang.org/adk/tool"
+)
+
+// The error codes of ClassifyError, besides LimitErrorCode, RateLimitErrorCode
+// and ClosedErrorCode.
+const (
+	// ToolNotFoundErrorCode is the code of tool.ErrToolNotFound.
+	ToolNotFoundErrorCode = "TOOL_NOT_FOUND"
+	// SchemaValidationErrorCode is the code of tool.ErrSchemaValidation.
+	SchemaValidationErrorCode = "SCHEMA_VALIDATION"
+	// AuthRequiredErrorCode is the code of tool.ErrAuthRequired.
+	AuthRequiredErrorCode = "AUTH_REQUIRED"
+	// ModelRateLimitedErrorCode is the code of the model calls rejected by
+	// the rate limits of the API, see model.ErrRateLimited.
+	ModelRateLimitedErrorCode = "MODEL_RATE_LIMITED"
+	// ModelUnavailableErrorCode is the code of the model calls failing with
+	// a transient error of the API, e.g. the status 503 of an overloaded
+	// service.
+	ModelUnavailableErrorCode = "MODEL_UNAVAILABLE"
+	// BusyErrorCode is the code of ErrRunnerBusy.
+	BusyErrorCode = "RUNNER_BUSY"
+	// CanceledErrorCode is the code of the runs canceled by their caller.
+	CanceledErrorCode = "CANCELED"
+	// DeadlineExceededErrorCode is the code of the runs whose context
+	// deadline expired.
+	DeadlineExceededErrorCode = "DEADLINE_EXCEEDED"
+	// InternalErrorCode is the code of the other errors.
+	InternalErrorCode = "INTERNAL"
+)
+
+// ErrorMetadataKey is the key, in the custom metadata of the events reporting
+// an error, see ErrorEvent, of its classification: whether the run may be
+// retried under "retryable", and the delay to wait for before, if known,
+// under "retry_after_seconds".
+const ErrorMetadataKey = "adk_error"
+
+// ErrorInfo is the classification of an error of a run.
+type ErrorInfo struct {
+	// Code identifies the kind of error, e.g. ModelRateLimitedErrorCode.
+	Code string
+	// Retryable reports whether the error is transient, so that running
+	// again the same message may succeed, e.g. later or on another runner.
+	// The other errors are fatal: they need a change of the request, of the
+	// configuration or an action of the user.
+	Retryable bool
+	// RetryAfter is the delay to wait for before retrying, or 0 if unknown.
+	RetryAfter time.Duration
+}
+
+// ClassifyError classifies an error of a run, e.g. yielded by Run, so that
+// callers can tell the transient failures from the fatal ones without
+// parsing the messages.
+func ClassifyError(err error) ErrorInfo {
+	var limitErr *agent.LimitExceededError
+	var rateLimitErr *RateLimitError
+	switch {
+	case errors.As(err, &limitErr):
+		return ErrorInfo{Code: LimitErrorCode}
+	case errors.As(err, &rateLimitErr):
+		return ErrorInfo{Code: RateLimitErrorCode, Retryable: true, RetryAfter: rateLimitErr.RetryAfter}
+	case errors.Is(err, ErrRunnerClosed):
+		return ErrorInfo{Code: ClosedErrorCode, Retryable: true}
+	case errors.Is(err, ErrRunnerBusy):
+		return ErrorInfo{Code: BusyErrorCode, Retryable: true}
+	case errors.Is(err, tool.ErrToolNotFound):
+		return ErrorInfo{Code: ToolNotFoundErrorCode}
+	case errors.Is(err, tool.ErrSchemaValidation):
+		return ErrorInfo{Code: SchemaValidationErrorCode}
+	case errors.Is(err, tool.ErrAuthRequired):
+		return ErrorInfo{Code: AuthRequiredErrorCode}
+	case isModelRateLimited(err):
+		return ErrorInfo{Code: ModelRateLimitedErrorCode, Retryable: true, RetryAfter: retrymodel.RetryAfter(err)}
+	case retrymodel.IsRetryable(err):
+		return ErrorInfo{Code: ModelUnavailableErrorCode, Retryable: true, RetryAfter: retrymodel.RetryAfter(err)}
+	case errors.Is(err, context.Canceled):
+		return ErrorInfo{Code: CanceledErrorCode}
+	case errors.Is(err, context.DeadlineExceeded):
+		return ErrorInfo{Code: DeadlineExceededErrorCode, Retryable: true}
+	}
+	return ErrorInfo{Code: InternalErrorCode}
+}
+
+// isModelRateLimited reports whether the error is a model call rejected by
+// the rate limits of the API, as a model.APIError or a genai.APIError.
+func isModelRateLimited(err error) bool {
+	var genaiErr genai.APIError
+	return errors.Is(err, model.ErrRateLimited) || (errors.As(err, &genaiErr) && genaiErr.Code == http.StatusTooManyRequests)
+}
+
+// ErrorEvent returns the event reporting the error, for the clients
+// consuming events rather than Go errors, e.g. over a network: its error code
+// is the code of ClassifyError, and its custom metadata has the
+// classification under ErrorMetadataKey. The events reporting the limits of
+// the runs, with LimitErrorCode or RateLimitErrorCode, are the ones of
+// ErrorEvent, with their details.
+func ErrorEvent(err error) *session.Event {
+	info := ClassifyError(err)
+	metadata := map[string]any{"retryable": info.Retryable}
+	if info.RetryAfter > 0 {
+		metadata["retry_after_seconds"] = math.Ceil(info.RetryAfter.Seconds())
+	}
+	event := session.NewEvent("")
+	event.LLMResponse = model.LLMResponse{
+		ErrorCode:      info.Code,
+		ErrorMessage:   err.Error(),
+		CustomMetadata: map[string]any{ErrorMetadataKey: metadata},
+	}
+	var limitErr *agent.LimitExceededError
+	if errors.As(err, &limitErr) {
+		event.CustomMetadata[LimitMetadataKey] = limitDetails(limitErr)
+	}
+	var rateLimitErr *RateLimitError
+	if errors.As(err, &rateLimitErr) {
+		event.CustomMetadata[RateLimitMetadataKey] = rateLimitErr.details()
+	}
+	return event
+}
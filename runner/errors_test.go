@@ -0,0 +1,123 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package runner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/agent"
+	"google.golang.org/adk/agent/llmagent"
+	"google.golang.org/adk/model"
+	"google.golang.org/adk/session"
+	"google.golang.org/adk/tool"
+	"google.golang.org/adk/tool/functiontool"
+)
+
+func TestClassifyError(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		err  error
+		want ErrorInfo
+	}{
+		{"tool not found", fmt.Errorf("%w: %q", tool.ErrToolNotFound, "missing"), ErrorInfo{Code: ToolNotFoundErrorCode}},
+		{"schema validation", fmt.Errorf("call failed: %w", &functiontool.ValidationError{Tool: "t"}), ErrorInfo{Code: SchemaValidationErrorCode}},
+		{"auth required", fmt.Errorf("bigquery: %w", tool.ErrAuthRequired), ErrorInfo{Code: AuthRequiredErrorCode}},
+		{"max calls", &agent.LimitExceededError{Limit: agent.LimitToolCalls, Max: 3}, ErrorInfo{Code: LimitErrorCode}},
+		{"rate limited run", &RateLimitError{UserID: "u", RetryAfter: 2 * time.Second}, ErrorInfo{Code: RateLimitErrorCode, Retryable: true, RetryAfter: 2 * time.Second}},
+		{"model rate limited", &model.APIError{StatusCode: 429, RetryAfter: time.Second}, ErrorInfo{Code: ModelRateLimitedErrorCode, Retryable: true, RetryAfter: time.Second}},
+		{"gemini rate limited", genai.APIError{Code: 429}, ErrorInfo{Code: ModelRateLimitedErrorCode, Retryable: true}},
+		{"gemini unavailable", genai.APIError{Code: 503}, ErrorInfo{Code: ModelUnavailableErrorCode, Retryable: true}},
+		{"gemini bad request", genai.APIError{Code: 400}, ErrorInfo{Code: InternalErrorCode}},
+		{"runner busy", ErrRunnerBusy, ErrorInfo{Code: BusyErrorCode, Retryable: true}},
+		{"runner closed", ErrRunnerClosed, ErrorInfo{Code: ClosedErrorCode, Retryable: true}},
+		{"canceled", context.Canceled, ErrorInfo{Code: CanceledErrorCode}},
+		{"deadline exceeded", context.DeadlineExceeded, ErrorInfo{Code: DeadlineExceededErrorCode, Retryable: true}},
+		{"other", errors.New("boom"), ErrorInfo{Code: InternalErrorCode}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if diff := cmp.Diff(tc.want, ClassifyError(tc.err)); diff != "" {
+				t.Errorf("ClassifyError(%v) mismatch (-want +got):\n%s", tc.err, diff)
+			}
+		})
+	}
+}
+
+func TestErrorSentinels(t *testing.T) {
+	if !errors.Is(&agent.LimitExceededError{Limit: agent.LimitLLMCalls}, agent.ErrMaxCallsExceeded) {
+		t.Error("LLM calls limit error is not ErrMaxCallsExceeded")
+	}
+	if errors.Is(&agent.LimitExceededError{Limit: agent.LimitTokens}, agent.ErrMaxCallsExceeded) {
+		t.Error("tokens limit error is ErrMaxCallsExceeded")
+	}
+	if !errors.Is(fmt.Errorf("call: %w", &model.APIError{StatusCode: 429}), model.ErrRateLimited) {
+		t.Error("429 API error is not ErrRateLimited")
+	}
+	if errors.Is(&model.APIError{StatusCode: 500}, model.ErrRateLimited) {
+		t.Error("500 API error is ErrRateLimited")
+	}
+}
+
+func TestErrorEvent(t *testing.T) {
+	ev := ErrorEvent(&model.APIError{StatusCode: 429, Status: "429 Too Many Requests", Message: "slow down", RetryAfter: 1500 * time.Millisecond})
+	if ev.ErrorCode != ModelRateLimitedErrorCode || ev.ErrorMessage != "429 Too Many Requests: slow down" {
+		t.Errorf("ErrorEvent() = {ErrorCode: %q, ErrorMessage: %q}, want the rate limit", ev.ErrorCode, ev.ErrorMessage)
+	}
+	want := map[string]any{ErrorMetadataKey: map[string]any{"retryable": true, "retry_after_seconds": 2.0}}
+	if diff := cmp.Diff(want, ev.CustomMetadata); diff != "" {
+		t.Errorf("ErrorEvent() metadata mismatch (-want +got):\n%s", diff)
+	}
+
+	ev = ErrorEvent(&agent.LimitExceededError{Limit: agent.LimitLLMCalls, Max: 2})
+	want = map[string]any{
+		ErrorMetadataKey: map[string]any{"retryable": false},
+		LimitMetadataKey: map[string]any{"limit": agent.LimitLLMCalls, "max": 2},
+	}
+	if diff := cmp.Diff(want, ev.CustomMetadata); diff != "" {
+		t.Errorf("ErrorEvent() of a limit metadata mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestRunner_UnknownTool(t *testing.T) {
+	llm := &fakeLLM{response: genai.NewContentFromFunctionCall("missing", map[string]any{}, genai.RoleModel)}
+	sessionService := session.InMemoryService()
+	r, err := New(Config{
+		AppName:           "testApp",
+		Agent:             must(llmagent.New(llmagent.Config{Name: "agent", Model: llm})),
+		SessionService:    sessionService,
+		AutoCreateSession: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var runErr error
+	for _, err := range r.Run(t.Context(), "user", "s1", genai.NewContentFromText("hi", genai.RoleUser), agent.RunConfig{}) {
+		if err != nil {
+			runErr = err
+		}
+	}
+	if !errors.Is(runErr, tool.ErrToolNotFound) {
+		t.Errorf("Run() error = %v, want %v", runErr, tool.ErrToolNotFound)
+	}
+	if got := ClassifyError(runErr).Code; got != ToolNotFoundErrorCode {
+		t.Errorf("ClassifyError(%v).Code = %q, want %q", runErr, got, ToolNotFoundErrorCode)
+	}
+}
@@ -0,0 +1,130 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package runner
+
+import (
+	"cmp"
+	"fmt"
+	"time"
+
+	"google.golang.org/adk/internal/localcontext"
+	"google.golang.org/adk/session"
+)
+
+// LocalContextTarget is where the local context is injected.
+type LocalContextTarget string
+
+const (
+	// LocalContextInstruction appends the local context to the system
+	// instruction of every model request of the invocation, as:
+	//
+	//	Current date and time: Wednesday, 2026-10-14 20:31 (Europe/Paris, UTC+02:00).
+	//	User locale: fr-FR.
+	LocalContextInstruction LocalContextTarget = "instruction"
+	// LocalContextState sets the local context in the temporary state of
+	// the invocation, under the StateKeyCurrentTime, StateKeyCurrentDate,
+	// StateKeyCurrentWeekday, StateKeyTimezone and StateKeyLocale keys, so
+	// that instructions can refer to it, e.g. "Today is
+	// {temp:current_date}.".
+	LocalContextState LocalContextTarget = "state"
+)
+
+// The state keys set by LocalContextState.
+const (
+	// StateKeyCurrentTime holds the current time in RFC 3339 format, e.g.
+	// "2026-10-14T20:31:00+02:00".
+	StateKeyCurrentTime = session.KeyPrefixTemp + "current_time"
+	// StateKeyCurrentDate holds the current date, e.g. "2026-10-14".
+	StateKeyCurrentDate = session.KeyPrefixTemp + "current_date"
+	// StateKeyCurrentWeekday holds the current day of the week, e.g.
+	// "Wednesday".
+	StateKeyCurrentWeekday = session.KeyPrefixTemp + "current_weekday"
+	// StateKeyTimezone holds the IANA time zone, e.g. "Europe/Paris".
+	StateKeyTimezone = session.KeyPrefixTemp + "timezone"
+	// StateKeyLocale holds the BCP 47 locale, e.g. "fr-FR".
+	StateKeyLocale = session.KeyPrefixTemp + "locale"
+)
+
+// LocalContext configures the injection of the current date and time, in
+// the time zone of the user, and of the locale of the user into each
+// invocation, so that the model can answer time and locale sensitive
+// questions without a tool.
+//
+// The time is taken once, when the invocation starts. The time zone and the
+// locale of the user are read from the session state, typically from
+// user-scoped keys set by the application.
+type LocalContext struct {
+	// Target is where the local context is injected. Defaults to
+	// LocalContextInstruction.
+	Target LocalContextTarget
+	// TimezoneStateKey is the state key holding the IANA time zone of the
+	// user, e.g. "Europe/Paris". Defaults to "user:timezone".
+	TimezoneStateKey string
+	// LocaleStateKey is the state key holding the BCP 47 locale of the user,
+	// e.g. "fr-FR". Defaults to "user:locale".
+	LocaleStateKey string
+	// DefaultTimezone is used when the state has no valid time zone.
+	// Defaults to UTC.
+	DefaultTimezone *time.Location
+	// DefaultLocale is used when the state has no locale. Defaults to
+	// "en-US".
+	DefaultLocale string
+	// Now returns the current time. Defaults to time.Now.
+	Now func() time.Time
+}
+
+// info returns the local context of the user of the session.
+func (lc *LocalContext) info(state session.ReadonlyState) *localcontext.Info {
+	loc := lc.DefaultTimezone
+	if loc == nil {
+		loc = time.UTC
+	}
+	if name := stateString(state, cmp.Or(lc.TimezoneStateKey, session.KeyPrefixUser+"timezone")); name != "" {
+		if l, err := time.LoadLocation(name); err == nil {
+			loc = l
+		}
+	}
+	locale := cmp.Or(stateString(state, cmp.Or(lc.LocaleStateKey, session.KeyPrefixUser+"locale")), lc.DefaultLocale, "en-US")
+	now := time.Now
+	if lc.Now != nil {
+		now = lc.Now
+	}
+	return &localcontext.Info{Time: now().In(loc), Locale: locale}
+}
+
+// setLocalContextState sets the local context in the temporary state.
+func setLocalContextState(state session.State, info *localcontext.Info) error {
+	for key, value := range map[string]string{
+		StateKeyCurrentTime:    info.Time.Format(time.RFC3339),
+		StateKeyCurrentDate:    info.Time.Format(time.DateOnly),
+		StateKeyCurrentWeekday: info.Time.Weekday().String(),
+		StateKeyTimezone:       info.Time.Location().String(),
+		StateKeyLocale:         info.Locale,
+	} {
+		if err := state.Set(key, value); err != nil {
+			return fmt.Errorf("failed to set the local context in state: %w", err)
+		}
+	}
+	return nil
+}
+
+func stateString(state session.ReadonlyState, key string) string {
+	v, err := state.Get(key)
+	if err != nil {
+		return ""
+	}
+	s, _ := v.(string)
+	return s
+}
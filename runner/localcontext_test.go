@@ -0,0 +1,122 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package runner
+
+import (
+	"strings"
+	"testing"
+	"time"
+	_ "time/tzdata"
+
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/agent"
+	"google.golang.org/adk/agent/llmagent"
+	"google.golang.org/adk/session"
+)
+
+func TestRunner_LocalContext(t *testing.T) {
+	now := time.Date(2026, 10, 14, 18, 31, 0, 0, time.UTC)
+
+	tests := []struct {
+		name            string
+		localContext    *LocalContext
+		state           map[string]any
+		wantInstruction string
+	}{
+		{
+			name:            "disabled",
+			wantInstruction: "Answer briefly.",
+		},
+		{
+			name:         "instruction with the user time zone and locale",
+			localContext: &LocalContext{Now: func() time.Time { return now }},
+			state:        map[string]any{"user:timezone": "Europe/Paris", "user:locale": "fr-FR"},
+			wantInstruction: "Answer briefly.\n\n" +
+				"Current date and time: Wednesday, 2026-10-14 20:31 (Europe/Paris, UTC+02:00).\nUser locale: fr-FR.",
+		},
+		{
+			name:         "instruction with defaults",
+			localContext: &LocalContext{Now: func() time.Time { return now }},
+			state:        map[string]any{"user:timezone": "Not/AZone"},
+			wantInstruction: "Answer briefly.\n\n" +
+				"Current date and time: Wednesday, 2026-10-14 18:31 (UTC, UTC+00:00).\nUser locale: en-US.",
+		},
+		{
+			name: "state",
+			localContext: &LocalContext{
+				Target:           LocalContextState,
+				TimezoneStateKey: "tz",
+				Now:              func() time.Time { return now },
+			},
+			state:           map[string]any{"tz": "America/New_York"},
+			wantInstruction: "Answer briefly. Today is Wednesday 2026-10-14, it is 2026-10-14T14:31:00-04:00 in America/New_York (en-US).",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := t.Context()
+			appName, userID := "testApp", "testUser"
+			sessionService := session.InMemoryService()
+
+			instruction := "Answer briefly."
+			if tt.localContext != nil && tt.localContext.Target == LocalContextState {
+				instruction += " Today is {temp:current_weekday} {temp:current_date}, it is {temp:current_time} in {temp:timezone} ({temp:locale})."
+			}
+			llm := &fakeLLM{response: genai.NewContentFromText("ok", genai.RoleModel)}
+			r, err := New(Config{
+				AppName:        appName,
+				Agent:          must(llmagent.New(llmagent.Config{Name: "agent", Model: llm, Instruction: instruction})),
+				SessionService: sessionService,
+				LocalContext:   tt.localContext,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			createResp, err := sessionService.Create(ctx, &session.CreateRequest{AppName: appName, UserID: userID, State: tt.state})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			for _, err := range r.Run(ctx, userID, createResp.Session.ID(), genai.NewContentFromText("what day is it?", genai.RoleUser), agent.RunConfig{}) {
+				if err != nil {
+					t.Fatalf("r.Run() returned an error: %v", err)
+				}
+			}
+
+			if len(llm.requests) != 1 {
+				t.Fatalf("got %d model requests, want 1", len(llm.requests))
+			}
+			var parts []string
+			for _, p := range llm.requests[0].Config.SystemInstruction.Parts {
+				parts = append(parts, p.Text)
+			}
+			if got := strings.Join(parts, "\n\n"); got != tt.wantInstruction {
+				t.Errorf("system instruction = %q, want %q", got, tt.wantInstruction)
+			}
+		})
+	}
+}
+
+func TestNew_InvalidLocalContextTarget(t *testing.T) {
+	_, err := New(Config{
+		Agent:          must(llmagent.New(llmagent.Config{Name: "agent"})),
+		SessionService: session.InMemoryService(),
+		LocalContext:   &LocalContext{Target: "header"},
+	})
+	if err == nil {
+		t.Error("New() with an unknown local context target succeeded, want error")
+	}
+}
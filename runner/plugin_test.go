@@ -0,0 +1,190 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package runner
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/agent"
+	"google.golang.org/adk/agent/llmagent"
+	"google.golang.org/adk/model"
+	"google.golang.org/adk/plugin"
+	"google.golang.org/adk/session"
+)
+
+func TestRunner_Plugins(t *testing.T) {
+	ctx := t.Context()
+	appName, userID := "testApp", "testUser"
+	sessionService := session.InMemoryService()
+
+	var calls []string
+	llm := &fakeLLM{response: genai.NewContentFromText("from model", genai.RoleModel)}
+	a := must(llmagent.New(llmagent.Config{
+		Name:  "agent",
+		Model: llm,
+		BeforeAgentCallbacks: []agent.BeforeAgentCallback{
+			func(agent.CallbackContext) (*genai.Content, error) {
+				calls = append(calls, "agent before agent")
+				return nil, nil
+			},
+		},
+		BeforeModelCallbacks: []llmagent.BeforeModelCallback{
+			func(agent.CallbackContext, *model.LLMRequest) (*model.LLMResponse, error) {
+				calls = append(calls, "agent before model")
+				return nil, nil
+			},
+		},
+	}))
+
+	plugins := []*plugin.Plugin{
+		{
+			Name: "recorder",
+			BeforeAgentCallback: func(ctx agent.CallbackContext) (*genai.Content, error) {
+				calls = append(calls, "plugin before agent "+ctx.AgentName())
+				return nil, nil
+			},
+			OnEventCallback: func(ctx agent.InvocationContext, event *session.Event) (*session.Event, error) {
+				event.Content.Parts[0].Text = strings.ToUpper(event.Content.Parts[0].Text)
+				return nil, nil
+			},
+		},
+		{
+			Name: "cache",
+			BeforeModelCallback: func(ctx agent.CallbackContext, req *model.LLMRequest) (*model.LLMResponse, error) {
+				calls = append(calls, "plugin before model")
+				return &model.LLMResponse{Content: genai.NewContentFromText("from cache", genai.RoleModel)}, nil
+			},
+		},
+	}
+	r, err := New(Config{AppName: appName, Agent: a, SessionService: sessionService, Plugins: plugins})
+	if err != nil {
+		t.Fatal(err)
+	}
+	created, err := sessionService.Create(ctx, &session.CreateRequest{AppName: appName, UserID: userID})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var texts []string
+	for ev, err := range r.Run(ctx, userID, created.Session.ID(), genai.NewContentFromText("hi", genai.RoleUser), agent.RunConfig{}) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		texts = append(texts, ev.Content.Parts[0].Text)
+	}
+
+	if diff := cmp.Diff([]string{"FROM CACHE"}, texts); diff != "" {
+		t.Errorf("event texts mismatch (-want +got):\n%s", diff)
+	}
+	wantCalls := []string{"plugin before agent agent", "agent before agent", "plugin before model"}
+	if diff := cmp.Diff(wantCalls, calls); diff != "" {
+		t.Errorf("callbacks mismatch (-want +got):\n%s", diff)
+	}
+	if len(llm.requests) != 0 {
+		t.Errorf("got %d model requests, want none", len(llm.requests))
+	}
+
+	got, err := sessionService.Get(ctx, &session.GetRequest{AppName: appName, UserID: userID, SessionID: created.Session.ID()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	events := got.Session.Events()
+	if last := events.At(events.Len() - 1); last.Content.Parts[0].Text != "FROM CACHE" {
+		t.Errorf("stored event text = %q, want %q", last.Content.Parts[0].Text, "FROM CACHE")
+	}
+}
+
+func TestRunner_RunPlugins(t *testing.T) {
+	var calls []string
+	newPlugin := func(name string, beforeErr error) *plugin.Plugin {
+		return &plugin.Plugin{
+			Name: name,
+			BeforeRunCallback: func(ctx agent.InvocationContext) error {
+				calls = append(calls, name+" before run")
+				return beforeErr
+			},
+			AfterRunCallback: func(ctx agent.InvocationContext) {
+				calls = append(calls, name+" after run")
+			},
+		}
+	}
+	tests := []struct {
+		name      string
+		plugins   []*plugin.Plugin
+		wantErr   bool
+		wantCalls []string
+	}{
+		{
+			name:      "run",
+			plugins:   []*plugin.Plugin{newPlugin("p1", nil), newPlugin("p2", nil)},
+			wantCalls: []string{"p1 before run", "p2 before run", "model", "p1 after run", "p2 after run"},
+		},
+		{
+			name:      "before run fails",
+			plugins:   []*plugin.Plugin{newPlugin("p1", nil), newPlugin("p2", errors.New("denied")), newPlugin("p3", nil)},
+			wantErr:   true,
+			wantCalls: []string{"p1 before run", "p2 before run", "p1 after run"},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			calls = nil
+			a := must(llmagent.New(llmagent.Config{
+				Name:  "agent",
+				Model: &fakeLLM{response: genai.NewContentFromText("from model", genai.RoleModel)},
+				BeforeModelCallbacks: []llmagent.BeforeModelCallback{
+					func(agent.CallbackContext, *model.LLMRequest) (*model.LLMResponse, error) {
+						calls = append(calls, "model")
+						return nil, nil
+					},
+				},
+			}))
+			r, err := New(Config{AppName: "app", Agent: a, SessionService: session.InMemoryService(), Plugins: tc.plugins, AutoCreateSession: true})
+			if err != nil {
+				t.Fatal(err)
+			}
+			var runErr error
+			for _, err := range r.Run(t.Context(), "user", "session", genai.NewContentFromText("hi", genai.RoleUser), agent.RunConfig{}) {
+				if err != nil {
+					runErr = err
+				}
+			}
+			if (runErr != nil) != tc.wantErr {
+				t.Errorf("Run() error = %v, wantErr %v", runErr, tc.wantErr)
+			}
+			if diff := cmp.Diff(tc.wantCalls, calls); diff != "" {
+				t.Errorf("calls mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestNew_InvalidPlugins(t *testing.T) {
+	a := must(agent.New(agent.Config{Name: "agent"}))
+	for _, plugins := range [][]*plugin.Plugin{
+		{nil},
+		{{}},
+		{{Name: "p"}, {Name: "p"}},
+	} {
+		if _, err := New(Config{AppName: "app", Agent: a, SessionService: session.InMemoryService(), Plugins: plugins}); err == nil {
+			t.Errorf("New() with plugins %v succeeded, want error", plugins)
+		}
+	}
+}
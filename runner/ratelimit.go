@@ -0,0 +1,214 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package runner
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"google.golang.org/adk/session"
+)
+
+// RateLimitMetadataKey is the key, in the custom metadata of the event
+// reporting a rate limited run, of the details of the limit: the user ID
+// under "user_id" and the retry-after hint under "retry_after_seconds".
+const RateLimitMetadataKey = "adk_rate_limit"
+
+// RateLimitErrorCode is the error code of the event reporting a rate limited
+// run.
+const RateLimitErrorCode = "RATE_LIMITED"
+
+const defaultRateLimitPeriod = time.Minute
+
+// RateLimiter limits the rate of the runs of each user.
+//
+// Unlike the rate limits of the model providers, which protect their
+// quotas, it protects the application from the abuse of a user and keeps
+// the service fair between users.
+type RateLimiter interface {
+	// Allow takes a run from the allowance of the user. If the user is over
+	// the limit, it returns false and the delay after which a run may be
+	// allowed.
+	Allow(ctx context.Context, userID string) (allowed bool, retryAfter time.Duration, err error)
+}
+
+// RateLimit configures the per-user rate limiting of the runs. A run over the
+// limit fails before the session is read: Run yields an event with
+// RateLimitErrorCode as error code and the details of the limit in its
+// custom metadata under RateLimitMetadataKey, which is not stored in the
+// session, followed by a *RateLimitError.
+type RateLimit struct {
+	// Limiter limits the runs. Defaults to an in-memory token bucket limiter
+	// allowing Runs runs per Period, see NewTokenBucketLimiter.
+	Limiter RateLimiter
+	// Runs is the number of runs a user may start per Period. Required
+	// without Limiter.
+	Runs int
+	// Period defaults to one minute.
+	Period time.Duration
+	// Burst is the number of runs a user may start at once. Defaults to
+	// Runs.
+	Burst int
+	// MaxWait is how long a run over the limit may wait for the allowance
+	// of the user before failing. With the default of zero, the runs over
+	// the limit fail right away.
+	MaxWait time.Duration
+}
+
+// RateLimitError is the error of a run over the rate limit of the user.
+type RateLimitError struct {
+	UserID string
+	// RetryAfter is the delay after which the user may retry.
+	RetryAfter time.Duration
+}
+
+func (e *RateLimitError) Error() string {
+	return fmt.Sprintf("user %q exceeded the rate limit, retry after %v", e.UserID, e.RetryAfter)
+}
+
+// event returns the event reporting the error.
+func (e *RateLimitError) event() *session.Event {
+	return ErrorEvent(e)
+}
+
+// details returns the details of the limit, see RateLimitMetadataKey.
+func (e *RateLimitError) details() map[string]any {
+	return map[string]any{
+		"user_id":             e.UserID,
+		"retry_after_seconds": math.Ceil(e.RetryAfter.Seconds()),
+	}
+}
+
+func (rl *RateLimit) validate() error {
+	if rl.Limiter == nil && rl.Runs <= 0 {
+		return fmt.Errorf("rate limit requires a limiter or a positive number of runs, got %d", rl.Runs)
+	}
+	return nil
+}
+
+func (rl *RateLimit) limiter() RateLimiter {
+	if rl.Limiter != nil {
+		return rl.Limiter
+	}
+	return NewTokenBucketLimiter(rl.Runs, rl.Period, rl.Burst)
+}
+
+// wait waits, up to MaxWait, for the limiter to allow a run of the user.
+func wait(ctx context.Context, limiter RateLimiter, maxWait time.Duration, userID string) error {
+	deadline := time.Now().Add(maxWait)
+	for {
+		allowed, retryAfter, err := limiter.Allow(ctx, userID)
+		if err != nil {
+			return fmt.Errorf("failed to check the rate limit: %w", err)
+		}
+		if allowed {
+			return nil
+		}
+		if time.Now().Add(retryAfter).After(deadline) {
+			return &RateLimitError{UserID: userID, RetryAfter: retryAfter}
+		}
+		timer := time.NewTimer(retryAfter)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// NewTokenBucketLimiter returns an in-memory RateLimiter giving each user a
+// bucket of burst tokens, refilled at the rate of runs tokens per period.
+// Each run takes a token. The period defaults to one minute and the burst to
+// runs.
+//
+// The buckets are kept in memory: the limits apply per process.
+func NewTokenBucketLimiter(runs int, period time.Duration, burst int) RateLimiter {
+	if period <= 0 {
+		period = defaultRateLimitPeriod
+	}
+	if burst <= 0 {
+		burst = runs
+	}
+	return &tokenBucketLimiter{
+		rate:    float64(runs) / period.Seconds(),
+		burst:   float64(burst),
+		buckets: make(map[string]*bucket),
+		now:     time.Now,
+	}
+}
+
+type tokenBucketLimiter struct {
+	rate  float64 // tokens per second
+	burst float64
+	now   func() time.Time
+
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	sweepSize int
+}
+
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+const minSweepSize = 1024
+
+// Allow implements RateLimiter.
+func (l *tokenBucketLimiter) Allow(_ context.Context, userID string) (bool, time.Duration, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	b, ok := l.buckets[userID]
+	if !ok {
+		l.sweep(now)
+		b = &bucket{tokens: l.burst, last: now}
+		l.buckets[userID] = b
+	}
+	l.refill(b, now)
+	if b.tokens >= 1 {
+		b.tokens--
+		return true, 0, nil
+	}
+	retryAfter := time.Duration(math.Ceil((1 - b.tokens) / l.rate * float64(time.Second)))
+	return false, retryAfter, nil
+}
+
+func (l *tokenBucketLimiter) refill(b *bucket, now time.Time) {
+	if elapsed := now.Sub(b.last).Seconds(); elapsed > 0 {
+		b.tokens = min(l.burst, b.tokens+elapsed*l.rate)
+		b.last = now
+	}
+}
+
+// sweep drops the full buckets, equivalent to missing ones, when the number
+// of buckets doubled since the last sweep.
+func (l *tokenBucketLimiter) sweep(now time.Time) {
+	if len(l.buckets) < max(minSweepSize, 2*l.sweepSize) {
+		return
+	}
+	for userID, b := range l.buckets {
+		l.refill(b, now)
+		if b.tokens >= l.burst {
+			delete(l.buckets, userID)
+		}
+	}
+	l.sweepSize = len(l.buckets)
+}
@@ -0,0 +1,157 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package runner
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/agent"
+	"google.golang.org/adk/agent/llmagent"
+	"google.golang.org/adk/session"
+)
+
+func TestTokenBucketLimiter(t *testing.T) {
+	now := time.Date(2026, 10, 14, 12, 0, 0, 0, time.UTC)
+	limiter := NewTokenBucketLimiter(2, time.Minute, 3).(*tokenBucketLimiter)
+	limiter.now = func() time.Time { return now }
+
+	allow := func(userID string) (bool, time.Duration) {
+		t.Helper()
+		allowed, retryAfter, err := limiter.Allow(t.Context(), userID)
+		if err != nil {
+			t.Fatalf("Allow(%q) error = %v", userID, err)
+		}
+		return allowed, retryAfter
+	}
+
+	// The burst is allowed at once.
+	for i := range 3 {
+		if allowed, _ := allow("alice"); !allowed {
+			t.Fatalf("run %d of the burst not allowed", i+1)
+		}
+	}
+	if allowed, retryAfter := allow("alice"); allowed || retryAfter.Round(time.Millisecond) != 30*time.Second {
+		t.Errorf("Allow() over the burst = (%v, %v), want (false, 30s)", allowed, retryAfter)
+	}
+	// Other users have their own bucket.
+	if allowed, _ := allow("bob"); !allowed {
+		t.Error("Allow(bob) not allowed, want allowed")
+	}
+
+	// Tokens are refilled at 2 per minute.
+	now = now.Add(20 * time.Second)
+	if allowed, retryAfter := allow("alice"); allowed || retryAfter.Round(time.Millisecond) != 10*time.Second {
+		t.Errorf("Allow() after 20s = (%v, %v), want (false, 10s)", allowed, retryAfter)
+	}
+	now = now.Add(10 * time.Second)
+	if allowed, _ := allow("alice"); !allowed {
+		t.Error("Allow() after 30s not allowed, want allowed")
+	}
+	if allowed, _ := allow("alice"); allowed {
+		t.Error("second Allow() after 30s allowed, want not allowed")
+	}
+}
+
+func TestRunner_RateLimit(t *testing.T) {
+	ctx := t.Context()
+	appName := "testApp"
+	sessionService := session.InMemoryService()
+	newRunner := func(rl *RateLimit) *Runner {
+		r, err := New(Config{
+			AppName:        appName,
+			Agent:          must(llmagent.New(llmagent.Config{Name: "agent", Model: &fakeLLM{response: genai.NewContentFromText("hello", genai.RoleModel)}})),
+			SessionService: sessionService,
+			RateLimit:      rl,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return r
+	}
+	newSession := func(userID string) string {
+		resp, err := sessionService.Create(ctx, &session.CreateRequest{AppName: appName, UserID: userID})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return resp.Session.ID()
+	}
+	run := func(r *Runner, userID, sessionID string) ([]*session.Event, error) {
+		var events []*session.Event
+		for ev, err := range r.Run(ctx, userID, sessionID, genai.NewContentFromText("hi", genai.RoleUser), agent.RunConfig{}) {
+			if err != nil {
+				return events, err
+			}
+			events = append(events, ev)
+		}
+		return events, nil
+	}
+
+	if _, err := New(Config{AppName: appName, Agent: must(llmagent.New(llmagent.Config{Name: "agent"})), SessionService: sessionService, RateLimit: &RateLimit{}}); err == nil {
+		t.Error("New() with a rate limit without runs succeeded, want error")
+	}
+
+	t.Run("reject", func(t *testing.T) {
+		r := newRunner(&RateLimit{Runs: 1, Period: time.Hour})
+		sessionID := newSession("alice")
+		if _, err := run(r, "alice", sessionID); err != nil {
+			t.Fatalf("first run error = %v", err)
+		}
+
+		events, err := run(r, "alice", sessionID)
+		var limited *RateLimitError
+		if !errors.As(err, &limited) || limited.UserID != "alice" || limited.RetryAfter <= 59*time.Minute {
+			t.Fatalf("second run error = %v, want a RateLimitError with a retry after of about an hour", err)
+		}
+		if len(events) != 1 || events[0].ErrorCode != RateLimitErrorCode {
+			t.Fatalf("second run events = %v, want one rate limited event", events)
+		}
+		details, _ := events[0].CustomMetadata[RateLimitMetadataKey].(map[string]any)
+		if details["user_id"] != "alice" || details["retry_after_seconds"] != 3600.0 {
+			t.Errorf("rate limited event metadata = %v, want the user and a retry after of 3600 seconds", details)
+		}
+
+		// The rejected run is not stored.
+		getResp, err := sessionService.Get(ctx, &session.GetRequest{AppName: appName, UserID: "alice", SessionID: sessionID})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := getResp.Session.Events().Len(); got != 2 {
+			t.Errorf("got %d stored events, want the 2 of the first run", got)
+		}
+
+		// Other users are not limited.
+		if _, err := run(r, "bob", newSession("bob")); err != nil {
+			t.Errorf("run of another user error = %v", err)
+		}
+	})
+
+	t.Run("wait", func(t *testing.T) {
+		r := newRunner(&RateLimit{Runs: 1, Period: 50 * time.Millisecond, MaxWait: time.Second})
+		sessionID := newSession("carol")
+		start := time.Now()
+		for i := range 2 {
+			if _, err := run(r, "carol", sessionID); err != nil {
+				t.Fatalf("run %d error = %v", i+1, err)
+			}
+		}
+		if elapsed := time.Since(start); elapsed < 40*time.Millisecond {
+			t.Errorf("the second run started after %v, want it to wait for the allowance", elapsed)
+		}
+	})
+}
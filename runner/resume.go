@@ -0,0 +1,119 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package runner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"iter"
+	"slices"
+
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/agent"
+	"google.golang.org/adk/internal/utils"
+	"google.golang.org/adk/session"
+)
+
+// ErrInvocationNotPaused is returned by [Runner.Resume] if the session has no
+// paused invocation with the given ID, or if the message does not answer any
+// of its pending calls.
+var ErrInvocationNotPaused = errors.New("invocation is not paused")
+
+// PausedInvocation is an invocation waiting for the client: for the result
+// of a long-running tool, a tool confirmation or a credential.
+type PausedInvocation struct {
+	// ID of the invocation.
+	ID string
+	// Agent that made the pending calls.
+	Agent string
+	// Branch of the agent.
+	Branch string
+	// PendingCallIDs are the IDs of the function calls waiting for a
+	// response from the client.
+	PendingCallIDs []string
+}
+
+// PausedInvocations returns the invocations of the session waiting for the
+// client, the oldest first.
+//
+// Everything needed to resume them is stored in the session, so they can be
+// resumed with [Runner.Resume] by any process sharing the session service,
+// e.g. after a restart.
+func PausedInvocations(sess session.Session) []PausedInvocation {
+	var paused []PausedInvocation
+	answered := make(map[string]bool)
+	events := sess.Events()
+	for i := events.Len() - 1; i >= 0; i-- {
+		ev := events.At(i)
+		if ev.Author == "user" {
+			for _, fr := range utils.FunctionResponses(ev.Content) {
+				answered[fr.ID] = true
+			}
+			continue
+		}
+		var pending []string
+		for _, id := range ev.LongRunningToolIDs {
+			if !answered[id] {
+				pending = append(pending, id)
+			}
+		}
+		if len(pending) == 0 {
+			continue
+		}
+		if j := slices.IndexFunc(paused, func(p PausedInvocation) bool { return p.ID == ev.InvocationID }); j >= 0 {
+			paused[j].PendingCallIDs = append(pending, paused[j].PendingCallIDs...)
+			continue
+		}
+		paused = append(paused, PausedInvocation{
+			ID:             ev.InvocationID,
+			Agent:          ev.Author,
+			Branch:         ev.Branch,
+			PendingCallIDs: pending,
+		})
+	}
+	slices.Reverse(paused)
+	return paused
+}
+
+// Resume resumes a paused invocation of the session, see PausedInvocations,
+// with a message answering some of its pending calls, e.g. the result of a
+// long-running tool or the confirmation of the user. The agent which made
+// the calls continues the invocation, and the events yielded carry the ID of
+// the invocation. Otherwise, Resume runs as Run does.
+//
+// The runner need not be the one which started the invocation: it only needs
+// the same agent tree and session service.
+func (r *Runner) Resume(ctx context.Context, userID, sessionID, invocationID string, msg *genai.Content, cfg agent.RunConfig) iter.Seq2[*session.Event, error] {
+	return r.run(ctx, userID, sessionID, invocationID, msg, nil, cfg)
+}
+
+// pausedInvocation returns the paused invocation of the session with the ID
+// whose pending calls the message answers.
+func pausedInvocation(sess session.Session, invocationID string, msg *genai.Content) (*PausedInvocation, error) {
+	for _, p := range PausedInvocations(sess) {
+		if p.ID != invocationID {
+			continue
+		}
+		for _, fr := range utils.FunctionResponses(msg) {
+			if slices.Contains(p.PendingCallIDs, fr.ID) {
+				return &p, nil
+			}
+		}
+		return nil, fmt.Errorf("invocation %q: the message answers none of the pending calls: %w", invocationID, ErrInvocationNotPaused)
+	}
+	return nil, fmt.Errorf("invocation %q: %w", invocationID, ErrInvocationNotPaused)
+}
@@ -0,0 +1,128 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package runner
+
+import (
+	"errors"
+	"iter"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/agent"
+	"google.golang.org/adk/internal/utils"
+	"google.golang.org/adk/session"
+)
+
+func TestRunner_Resume(t *testing.T) {
+	ctx := t.Context()
+	appName, userID := "testApp", "testUser"
+	sessionService := session.InMemoryService()
+
+	// The agent waits for the approval of a long-running call, and answers
+	// once it is approved.
+	newRunner := func() *Runner {
+		approver := must(agent.New(agent.Config{
+			Name: "approver",
+			Run: func(ctx agent.InvocationContext) iter.Seq2[*session.Event, error] {
+				return func(yield func(*session.Event, error) bool) {
+					ev := session.NewEvent(ctx.InvocationID())
+					ev.Author = "approver"
+					if utils.FunctionResponses(ctx.UserContent()) == nil {
+						ev.Content = genai.NewContentFromParts([]*genai.Part{{FunctionCall: &genai.FunctionCall{ID: "call-1", Name: "approve"}}}, genai.RoleModel)
+						ev.LongRunningToolIDs = []string{"call-1"}
+					} else {
+						ev.Content = genai.NewContentFromText("approved", genai.RoleModel)
+					}
+					yield(ev, nil)
+				}
+			},
+		}))
+		// The root delegates to the approver.
+		root := must(agent.New(agent.Config{
+			Name:      "root",
+			SubAgents: []agent.Agent{approver},
+			Run: func(ctx agent.InvocationContext) iter.Seq2[*session.Event, error] {
+				return approver.Run(ctx)
+			},
+		}))
+		r, err := New(Config{AppName: appName, Agent: root, SessionService: sessionService})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return r
+	}
+
+	created, err := sessionService.Create(ctx, &session.CreateRequest{AppName: appName, UserID: userID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sessionID := created.Session.ID()
+
+	for _, err := range newRunner().Run(ctx, userID, sessionID, genai.NewContentFromText("deploy", genai.RoleUser), agent.RunConfig{}) {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	got, err := sessionService.Get(ctx, &session.GetRequest{AppName: appName, UserID: userID, SessionID: sessionID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	paused := PausedInvocations(got.Session)
+	if len(paused) != 1 {
+		t.Fatalf("PausedInvocations() = %v, want one invocation", paused)
+	}
+	invocationID := paused[0].ID
+	if diff := cmp.Diff(PausedInvocation{ID: invocationID, Agent: "approver", PendingCallIDs: []string{"call-1"}}, paused[0]); diff != "" {
+		t.Errorf("PausedInvocations() mismatch (-want +got):\n%s", diff)
+	}
+
+	reply := genai.NewContentFromParts([]*genai.Part{{FunctionResponse: &genai.FunctionResponse{ID: "call-1", Name: "approve", Response: map[string]any{"approved": true}}}}, genai.RoleUser)
+
+	t.Run("unknown invocation", func(t *testing.T) {
+		for _, err := range newRunner().Resume(ctx, userID, sessionID, "unknown", reply, agent.RunConfig{}) {
+			if !errors.Is(err, ErrInvocationNotPaused) {
+				t.Errorf("Resume() error = %v, want %v", err, ErrInvocationNotPaused)
+			}
+		}
+	})
+
+	// A new runner, as in another process, resumes the invocation.
+	var texts []string
+	for ev, err := range newRunner().Resume(ctx, userID, sessionID, invocationID, reply, agent.RunConfig{}) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		if ev.InvocationID != invocationID {
+			t.Errorf("event InvocationID = %q, want %q", ev.InvocationID, invocationID)
+		}
+		if ev.Author == "approver" {
+			texts = append(texts, ev.Content.Parts[0].Text)
+		}
+	}
+	if diff := cmp.Diff([]string{"approved"}, texts); diff != "" {
+		t.Errorf("Resume() texts mismatch (-want +got):\n%s", diff)
+	}
+
+	got, err = sessionService.Get(ctx, &session.GetRequest{AppName: appName, UserID: userID, SessionID: sessionID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if paused := PausedInvocations(got.Session); len(paused) != 0 {
+		t.Errorf("PausedInvocations() after Resume() = %v, want none", paused)
+	}
+}
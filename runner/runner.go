@@ -17,9 +17,11 @@ package runner
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"iter"
 	"log"
+	"time"
 
 	"google.golang.org/genai"
 
@@ -30,11 +32,16 @@ import (
 	artifactinternal "google.golang.org/adk/internal/artifact"
 	icontext "google.golang.org/adk/internal/context"
 	"google.golang.org/adk/internal/llminternal"
+	"google.golang.org/adk/internal/localcontext"
 	imemory "google.golang.org/adk/internal/memory"
 	"google.golang.org/adk/internal/sessioninternal"
+	"google.golang.org/adk/internal/telemetry"
+	"google.golang.org/adk/internal/utils"
 	"google.golang.org/adk/memory"
 	"google.golang.org/adk/model"
+	"google.golang.org/adk/plugin"
 	"google.golang.org/adk/session"
+	"google.golang.org/adk/tool"
 )
 
 // Config is used to create a [Runner].
@@ -48,6 +55,31 @@ type Config struct {
 	ArtifactService artifact.Service
 	// optional
 	MemoryService memory.Service
+	// StreamBuffer retains the events yielded by Run so that clients can
+	// resume an interrupted stream with StreamBuffer.Resume. Optional.
+	StreamBuffer *StreamBuffer
+	// LocalContext injects the current date and time and the locale of the
+	// user into each invocation. Optional; nothing is injected if nil.
+	LocalContext *LocalContext
+	// RateLimit limits the rate of the runs of each user. Optional; the runs
+	// are not limited if nil.
+	RateLimit *RateLimit
+	// Concurrency bounds the runs at the same time and serializes the runs
+	// of each session. Optional; the runs are neither bounded nor serialized
+	// if nil. See also Runner.Drain for the shutdown of the runner.
+	Concurrency *Concurrency
+	// ToolInterceptors wrap the tool calls of all the agents, outside the
+	// interceptors of each agent, e.g. to log or meter the calls uniformly.
+	// See tool.Interceptor.
+	ToolInterceptors []tool.Interceptor
+	// Plugins are callbacks applied across all the agents, models and tools
+	// of the runs, before the callbacks of the agents, in order. See package
+	// plugin.
+	Plugins []*plugin.Plugin
+	// AutoCreateSession makes Run create the session with the given ID when
+	// the session service does not find it, see session.ErrSessionNotFound,
+	// rather than fail.
+	AutoCreateSession bool
 }
 
 // New creates a new [Runner].
@@ -60,18 +92,43 @@ func New(cfg Config) (*Runner, error) {
 		return nil, fmt.Errorf("session service is required")
 	}
 
+	if lc := cfg.LocalContext; lc != nil && lc.Target != "" && lc.Target != LocalContextInstruction && lc.Target != LocalContextState {
+		return nil, fmt.Errorf("unknown local context target %q", lc.Target)
+	}
+
+	var limiter RateLimiter
+	var maxWait time.Duration
+	if rl := cfg.RateLimit; rl != nil {
+		if err := rl.validate(); err != nil {
+			return nil, err
+		}
+		limiter, maxWait = rl.limiter(), rl.MaxWait
+	}
+
+	if err := plugin.Validate(cfg.Plugins); err != nil {
+		return nil, fmt.Errorf("invalid plugins: %w", err)
+	}
+
 	parents, err := parentmap.New(cfg.Agent)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create agent tree: %w", err)
 	}
 
 	return &Runner{
-		appName:         cfg.AppName,
-		rootAgent:       cfg.Agent,
-		sessionService:  cfg.SessionService,
-		artifactService: cfg.ArtifactService,
-		memoryService:   cfg.MemoryService,
-		parents:         parents,
+		appName:          cfg.AppName,
+		rootAgent:        cfg.Agent,
+		sessionService:   cfg.SessionService,
+		artifactService:  cfg.ArtifactService,
+		memoryService:    cfg.MemoryService,
+		streamBuffer:     cfg.StreamBuffer,
+		localContext:     cfg.LocalContext,
+		rateLimiter:      limiter,
+		rateLimitWait:    maxWait,
+		toolInterceptors: cfg.ToolInterceptors,
+		plugins:          cfg.Plugins,
+		autoCreate:       cfg.AutoCreateSession,
+		runs:             newRuns(cfg.Concurrency),
+		parents:          parents,
 	}, nil
 }
 
@@ -79,11 +136,19 @@ func New(cfg Config) (*Runner, error) {
 // processing, event generation, and interaction with various services like
 // artifact storage, session management, and memory.
 type Runner struct {
-	appName         string
-	rootAgent       agent.Agent
-	sessionService  session.Service
-	artifactService artifact.Service
-	memoryService   memory.Service
+	appName          string
+	rootAgent        agent.Agent
+	sessionService   session.Service
+	artifactService  artifact.Service
+	memoryService    memory.Service
+	streamBuffer     *StreamBuffer
+	localContext     *LocalContext
+	rateLimiter      RateLimiter
+	rateLimitWait    time.Duration
+	toolInterceptors []tool.Interceptor
+	plugins          []*plugin.Plugin
+	autoCreate       bool
+	runs             *runs
 
 	parents parentmap.Map
 }
@@ -91,33 +156,144 @@ type Runner struct {
 // Run runs the agent for the given user input, yielding events from agents.
 // For each user message it finds the proper agent within an agent tree to
 // continue the conversation within the session.
+//
+// If ctx carries a correlation ID (see agent.WithCorrelationID), it is
+// attached to every event of the invocation. Otherwise a new one is generated.
+//
+// Every yielded event carries a sequence number, starting at 1 and
+// incremented for each event of the invocation, partial ones included. If
+// the runner was configured with a StreamBuffer, the events are retained
+// there for resumption.
+//
+// A run exceeding a limit of cfg, such as MaxLLMCalls or Timeout, ends with
+// an event with LimitErrorCode as error code and the details of the limit in
+// its custom metadata under LimitMetadataKey, which is not stored in the
+// session, followed by an *agent.LimitExceededError.
+//
+// ClassifyError tells the transient errors of a run, e.g. a model rate
+// limited, from the fatal ones, and ErrorEvent maps them to events.
 func (r *Runner) Run(ctx context.Context, userID, sessionID string, msg *genai.Content, cfg agent.RunConfig) iter.Seq2[*session.Event, error] {
+	return r.run(ctx, userID, sessionID, "", msg, nil, cfg)
+}
+
+// RunLive runs the agent over live, bidirectional connections to its model,
+// e.g. for voice agents: the requests sent on queue, such as the text
+// messages or the audio chunks of the user, are streamed to the model, and
+// the responses of the model are yielded as events, until the queue is
+// closed. The model of the agent must implement model.LiveLLM, as the Gemini
+// models of the Live API do.
+//
+// The text and the audio of the model are streamed in partial events, which
+// are not stored in the session; the text and the transcriptions, see
+// cfg.InputAudioTranscription, are then aggregated in complete events, and
+// each turn of the model ends with an event with TurnComplete. The function
+// calls of the model are run as in Run, and their responses sent back to the
+// model. The requests sent on the queue are not stored in the session.
+//
+// cfg.StreamingMode is set to agent.StreamingModeBidi, and the other fields
+// of cfg apply as in Run.
+func (r *Runner) RunLive(ctx context.Context, userID, sessionID string, queue *agent.LiveRequestQueue, cfg agent.RunConfig) iter.Seq2[*session.Event, error] {
+	cfg.StreamingMode = agent.StreamingModeBidi
+	return r.run(ctx, userID, sessionID, "", nil, queue, cfg)
+}
+
+// run runs the agent for the user message, or for the requests of the live
+// request queue. The paused invocation with the ID resumeID, if any, is
+// resumed.
+func (r *Runner) run(ctx context.Context, userID, sessionID, resumeID string, msg *genai.Content, queue *agent.LiveRequestQueue, cfg agent.RunConfig) iter.Seq2[*session.Event, error] {
 	// TODO(hakim): we need to validate whether cfg is compatible with the Agent.
 	//   see adk-python/src/google/adk/runners.py Runner._new_invocation_context.
-	// TODO: setup tracer.
 	return func(yield func(*session.Event, error) bool) {
-		resp, err := r.sessionService.Get(ctx, &session.GetRequest{
-			AppName:   r.appName,
-			UserID:    userID,
-			SessionID: sessionID,
-		})
+		if r.rateLimiter != nil {
+			if err := wait(ctx, r.rateLimiter, r.rateLimitWait, userID); err != nil {
+				var limited *RateLimitError
+				if errors.As(err, &limited) && !yield(limited.event(), nil) {
+					return
+				}
+				yield(nil, err)
+				return
+			}
+		}
+
+		runCtx, release, err := r.runs.start(ctx, userID, sessionID)
 		if err != nil {
 			yield(nil, err)
 			return
 		}
+		defer release()
+		ctx = runCtx
+
+		if cfg.Timeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeoutCause(ctx, cfg.Timeout, &agent.LimitExceededError{Limit: agent.LimitTimeout, Timeout: cfg.Timeout})
+			defer cancel()
+		}
 
-		session := resp.Session
+		correlationID := agent.CorrelationIDFromContext(ctx)
+		if correlationID == "" {
+			correlationID = agent.NewCorrelationID()
+			ctx = agent.WithCorrelationID(ctx, correlationID)
+		}
 
-		agentToRun, err := r.findAgentToRun(session)
+		// The span of the invocation is the parent of the spans of its
+		// agent runs, model calls and tool calls.
+		spanCtx, spans := telemetry.StartInvocation(ctx, r.appName, userID, sessionID)
+		ctx = spanCtx
+		var runErr error
+		defer func() { telemetry.EndTrace(spans, runErr) }()
+		yieldEvent := yield
+		yield = func(event *session.Event, err error) bool {
+			if err != nil {
+				runErr = err
+			}
+			return yieldEvent(event, err)
+		}
+
+		session, err := r.session(ctx, userID, sessionID)
 		if err != nil {
 			yield(nil, err)
 			return
 		}
 
+		var paused *PausedInvocation
+		if resumeID != "" {
+			if paused, err = pausedInvocation(session, resumeID, msg); err != nil {
+				yield(nil, err)
+				return
+			}
+		}
+
+		agentToRun, err := r.findAgentToRun(session, msg)
+		if err != nil {
+			yield(nil, err)
+			return
+		}
+		if paused != nil {
+			if agentToRun = findAgent(r.rootAgent, paused.Agent); agentToRun == nil {
+				yield(nil, fmt.Errorf("failed to find agent %q of invocation %q", paused.Agent, paused.ID))
+				return
+			}
+		}
+
 		ctx = parentmap.ToContext(ctx, r.parents)
-		ctx = runconfig.ToContext(ctx, &runconfig.RunConfig{
-			StreamingMode: runconfig.StreamingMode(cfg.StreamingMode),
-		})
+		runCfg := &runconfig.RunConfig{
+			StreamingMode:    runconfig.StreamingMode(cfg.StreamingMode),
+			ToolInterceptors: r.toolInterceptors,
+			MaxLLMCalls:      cfg.MaxLLMCalls,
+			MaxToolCalls:     cfg.MaxToolCalls,
+			MaxTokens:        cfg.MaxTokens,
+			LiveRequestQueue: queue,
+		}
+		ctx = r.withPlugins(ctx, runCfg)
+		ctx = runconfig.ToContext(ctx, runCfg)
+
+		var localInfo *localcontext.Info
+		if r.localContext != nil {
+			localInfo = r.localContext.info(session.State())
+			if r.localContext.Target != LocalContextState {
+				ctx = localcontext.ToContext(ctx, localInfo)
+			}
+		}
 
 		var artifacts agent.Artifacts
 		if r.artifactService != nil {
@@ -139,46 +315,324 @@ func (r *Runner) Run(ctx context.Context, userID, sessionID string, msg *genai.C
 			}
 		}
 
-		ctx := icontext.NewInvocationContext(ctx, icontext.InvocationContextParams{
+		params := icontext.InvocationContextParams{
 			Artifacts:   artifacts,
 			Memory:      memoryImpl,
 			Session:     sessioninternal.NewMutableSession(r.sessionService, session),
 			Agent:       agentToRun,
 			UserContent: msg,
 			RunConfig:   &cfg,
-		})
+		}
+		if paused != nil {
+			params.InvocationID = paused.ID
+			params.Branch = paused.Branch
+		}
+		ctx := icontext.NewInvocationContext(ctx, params)
+		telemetry.TraceInvocation(spans, ctx.InvocationID())
 
-		if err := r.appendMessageToSession(ctx, session, msg, cfg.SaveInputBlobsAsArtifacts); err != nil {
+		if localInfo != nil && r.localContext.Target == LocalContextState {
+			if err := setLocalContextState(ctx.Session().State(), localInfo); err != nil {
+				yield(nil, err)
+				return
+			}
+		}
+
+		userEvent, err := r.appendMessageToSession(ctx, session, msg, cfg.SaveInputBlobsAsArtifacts)
+		if err != nil {
 			yield(nil, err)
 			return
 		}
 
+		afterRun, err := r.beforeRun(ctx)
+		defer afterRun()
+		if err != nil {
+			yield(nil, err)
+			return
+		}
+
+		if r.streamBuffer != nil {
+			r.streamBuffer.start(ctx.InvocationID())
+			defer r.streamBuffer.finish(ctx.InvocationID())
+		}
+
+		events := r.runAgent(ctx, agentToRun, session, userEvent, correlationID, cfg)
+		if cfg.EventBufferSize > 0 || cfg.EventOverflow == agent.EventOverflowDropPartial {
+			events = bufferEvents(events, cfg.EventBufferSize, cfg.EventOverflow)
+		}
+		events(yield)
+	}
+}
+
+// session returns the session of the run, created if needed and allowed by
+// Config.AutoCreateSession.
+func (r *Runner) session(ctx context.Context, userID, sessionID string) (session.Session, error) {
+	resp, err := r.sessionService.Get(ctx, &session.GetRequest{
+		AppName:   r.appName,
+		UserID:    userID,
+		SessionID: sessionID,
+	})
+	if err == nil {
+		return resp.Session, nil
+	}
+	if !r.autoCreate || !errors.Is(err, session.ErrSessionNotFound) {
+		return nil, err
+	}
+	created, err := r.sessionService.Create(ctx, &session.CreateRequest{
+		AppName:   r.appName,
+		UserID:    userID,
+		SessionID: sessionID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create session: %w", err)
+	}
+	return created.Session, nil
+}
+
+// withPlugins returns ctx running the agent callbacks of the plugins, and
+// adds their model and tool callbacks to runCfg.
+func (r *Runner) withPlugins(ctx context.Context, runCfg *runconfig.RunConfig) context.Context {
+	if len(r.plugins) == 0 {
+		return ctx
+	}
+	var before []agent.BeforeAgentCallback
+	var after []agent.AfterAgentCallback
+	for _, p := range r.plugins {
+		if p.BeforeAgentCallback != nil {
+			before = append(before, p.BeforeAgentCallback)
+		}
+		if p.AfterAgentCallback != nil {
+			after = append(after, p.AfterAgentCallback)
+		}
+		if p.BeforeModelCallback != nil {
+			runCfg.BeforeModelCallbacks = append(runCfg.BeforeModelCallbacks, p.BeforeModelCallback)
+		}
+		if p.AfterModelCallback != nil {
+			runCfg.AfterModelCallbacks = append(runCfg.AfterModelCallbacks, p.AfterModelCallback)
+		}
+		if p.BeforeToolCallback != nil {
+			runCfg.BeforeToolCallbacks = append(runCfg.BeforeToolCallbacks, p.BeforeToolCallback)
+		}
+		if p.AfterToolCallback != nil {
+			runCfg.AfterToolCallbacks = append(runCfg.AfterToolCallbacks, p.AfterToolCallback)
+		}
+	}
+	return agent.WithAgentCallbacks(ctx, before, after)
+}
+
+// beforeRun runs the before run callbacks of the plugins, and returns the
+// function running the after run callbacks of the plugins whose before run
+// callback, if any, succeeded.
+func (r *Runner) beforeRun(ctx agent.InvocationContext) (func(), error) {
+	var started []*plugin.Plugin
+	afterRun := func() {
+		for _, p := range started {
+			if p.AfterRunCallback != nil {
+				p.AfterRunCallback(ctx)
+			}
+		}
+	}
+	for _, p := range r.plugins {
+		if p.BeforeRunCallback != nil {
+			if err := p.BeforeRunCallback(ctx); err != nil {
+				return afterRun, fmt.Errorf("plugin %q: failed to run before run callback: %w", p.Name, err)
+			}
+		}
+		started = append(started, p)
+	}
+	return afterRun, nil
+}
+
+// onEvent runs the event callbacks of the plugins on the event, and returns
+// the event replacing it.
+func (r *Runner) onEvent(ctx agent.InvocationContext, event *session.Event) (*session.Event, error) {
+	for _, p := range r.plugins {
+		if p.OnEventCallback == nil {
+			continue
+		}
+		replaced, err := p.OnEventCallback(ctx, event)
+		if err != nil {
+			return nil, fmt.Errorf("plugin %q: failed to run event callback: %w", p.Name, err)
+		}
+		if replaced != nil {
+			event = replaced
+		}
+	}
+	return event, nil
+}
+
+// runAgent runs the agent and yields its events, after storing them in the
+// session. With cfg.EmitTurnBoundaries, the events are preceded by the turn
+// start event, a copy of the user event if any, and followed by the turn
+// complete event.
+func (r *Runner) runAgent(ctx agent.InvocationContext, agentToRun agent.Agent, storedSession session.Session, userEvent *session.Event, correlationID string, cfg agent.RunConfig) iter.Seq2[*session.Event, error] {
+	return func(yield func(*session.Event, error) bool) {
+		var sequence int64
+		// emit yields an event that is not stored in the session.
+		emit := func(event *session.Event) bool {
+			sequence++
+			event.Sequence = sequence
+			if r.streamBuffer != nil {
+				r.streamBuffer.add(ctx.InvocationID(), event)
+			}
+			return yield(event, nil)
+		}
+
+		if cfg.EmitTurnBoundaries {
+			start := session.NewEvent(ctx.InvocationID())
+			start.TurnID = ctx.InvocationID()
+			if userEvent != nil {
+				copied := *userEvent
+				start = &copied
+			}
+			start.CorrelationID = correlationID
+			start.TurnBoundary = session.TurnStart
+			if !emit(start) {
+				return
+			}
+		}
+
 		for event, err := range agentToRun.Run(ctx) {
 			if err != nil {
+				// A run over a limit of cfg ends with the limit error.
+				var limitErr *agent.LimitExceededError
+				if errors.As(context.Cause(ctx), &limitErr) || errors.As(err, &limitErr) {
+					if !emit(limitEvent(ctx, correlationID, limitErr)) || !yield(nil, limitErr) {
+						return
+					}
+					break
+				}
+				if errors.Is(context.Cause(ctx), ErrRunnerClosed) {
+					r.interrupted(ctx, storedSession, agentToRun, correlationID, emit, yield)
+					return
+				}
 				if !yield(event, err) {
 					return
 				}
 				continue
 			}
 
+			if event, err = r.onEvent(ctx, event); err != nil {
+				yield(nil, err)
+				return
+			}
+			if event.CorrelationID == "" {
+				event.CorrelationID = correlationID
+			}
+			event.TurnID = ctx.InvocationID()
+			// A partial event with only thoughts is neither yielded nor
+			// buffered, so it is not numbered: the sequences of the
+			// StreamBuffer have no gaps.
+			if cfg.StripThoughtsFromOutput && event.Partial && event.Content != nil && withoutThoughts(event).Content == nil {
+				continue
+			}
+			sequence++
+			event.Sequence = sequence
+
 			// only commit non-partial event to a session service
 			if !event.LLMResponse.Partial {
-				if err := r.sessionService.AppendEvent(ctx, session, event); err != nil {
+				if err := offloadLargeParts(ctx, event, cfg.MaxInlinePartSize); err != nil {
+					yield(nil, err)
+					return
+				}
+				stored := event
+				if cfg.StripThoughtsFromHistory {
+					stored = withoutThoughts(event)
+				}
+				if err := r.sessionService.AppendEvent(ctx, storedSession, stored); err != nil {
 					yield(nil, fmt.Errorf("failed to add event to session: %w", err))
 					return
 				}
 			}
+			if cfg.StripThoughtsFromOutput {
+				event = withoutThoughts(event)
+			}
+
+			if r.streamBuffer != nil {
+				r.streamBuffer.add(ctx.InvocationID(), event)
+			}
 
 			if !yield(event, nil) {
 				return
 			}
 		}
+
+		if errors.Is(context.Cause(ctx), ErrRunnerClosed) {
+			r.interrupted(ctx, storedSession, agentToRun, correlationID, emit, yield)
+			return
+		}
+
+		if cfg.EmitTurnBoundaries {
+			complete := session.NewEvent(ctx.InvocationID())
+			complete.CorrelationID = correlationID
+			complete.TurnID = ctx.InvocationID()
+			complete.TurnBoundary = session.TurnComplete
+			emit(complete)
+		}
 	}
 }
 
-func (r *Runner) appendMessageToSession(ctx agent.InvocationContext, storedSession session.Session, msg *genai.Content, saveInputBlobsAsArtifacts bool) error {
+// interrupted ends a run interrupted by the shutdown of the runner: it
+// stores and yields the event reporting it, then ErrRunnerClosed.
+func (r *Runner) interrupted(ctx agent.InvocationContext, storedSession session.Session, agentToRun agent.Agent, correlationID string, emit func(*session.Event) bool, yield func(*session.Event, error) bool) {
+	event := closedEvent(ctx, agentToRun.Name(), correlationID)
+	// The context of the run is canceled, but the event must be stored.
+	if err := r.sessionService.AppendEvent(context.WithoutCancel(ctx), storedSession, event); err != nil {
+		yield(nil, fmt.Errorf("failed to add event to session: %w", err))
+		return
+	}
+	if emit(event) {
+		yield(nil, ErrRunnerClosed)
+	}
+}
+
+// withoutThoughts returns the event without the thoughts of its content, a
+// copy if it has some.
+func withoutThoughts(event *session.Event) *session.Event {
+	content := session.StripThoughts(event.Content)
+	if content == event.Content {
+		return event
+	}
+	copied := *event
+	copied.Content = content
+	return &copied
+}
+
+// LimitMetadataKey is the key, in the custom metadata of the event reporting
+// that a run exceeded a limit of its agent.RunConfig, of the details of the
+// limit.
+const LimitMetadataKey = "adk_run_limit"
+
+// LimitErrorCode is the error code of the event reporting that a run exceeded
+// a limit of its agent.RunConfig.
+const LimitErrorCode = "LIMIT_EXCEEDED"
+
+// limitEvent returns the event reporting the limit error, which is not stored
+// in the session.
+func limitEvent(ctx agent.InvocationContext, correlationID string, err *agent.LimitExceededError) *session.Event {
+	event := ErrorEvent(err)
+	event.InvocationID = ctx.InvocationID()
+	event.CorrelationID = correlationID
+	event.TurnID = ctx.InvocationID()
+	return event
+}
+
+// limitDetails returns the details of the limit, see LimitMetadataKey.
+func limitDetails(err *agent.LimitExceededError) map[string]any {
+	details := map[string]any{"limit": err.Limit}
+	switch err.Limit {
+	case agent.LimitTimeout:
+		details["timeout_seconds"] = err.Timeout.Seconds()
+	default:
+		details["max"] = err.Max
+	}
+	return details
+}
+
+// appendMessageToSession stores the user message in the session, and returns
+// its event, or nil if there is no message.
+func (r *Runner) appendMessageToSession(ctx agent.InvocationContext, storedSession session.Session, msg *genai.Content, saveInputBlobsAsArtifacts bool) (*session.Event, error) {
 	if msg == nil {
-		return nil
+		return nil, nil
 	}
 
 	artifactsService := ctx.Artifacts()
@@ -189,7 +643,7 @@ func (r *Runner) appendMessageToSession(ctx agent.InvocationContext, storedSessi
 			}
 			fileName := fmt.Sprintf("artifact_%s_%d", ctx.InvocationID(), i)
 			if _, err := artifactsService.Save(ctx, fileName, part); err != nil {
-				return fmt.Errorf("failed to save artifact %s: %w", fileName, err)
+				return nil, fmt.Errorf("failed to save artifact %s: %w", fileName, err)
 			}
 			// Replace the part with a text placeholder
 			msg.Parts[i] = &genai.Part{
@@ -201,25 +655,60 @@ func (r *Runner) appendMessageToSession(ctx agent.InvocationContext, storedSessi
 	event := session.NewEvent(ctx.InvocationID())
 
 	event.Author = "user"
+	event.CorrelationID = agent.CorrelationIDFromContext(ctx)
+	event.TurnID = ctx.InvocationID()
 	event.LLMResponse = model.LLMResponse{
 		Content: msg,
 	}
 
+	if err := offloadLargeParts(ctx, event, ctx.RunConfig().MaxInlinePartSize); err != nil {
+		return nil, err
+	}
+
 	if err := r.sessionService.AppendEvent(ctx, storedSession, event); err != nil {
-		return fmt.Errorf("failed to append event to sessionService: %w", err)
+		return nil, fmt.Errorf("failed to append event to sessionService: %w", err)
+	}
+	return event, nil
+}
+
+// offloadLargeParts saves the inline data of event parts larger than maxSize
+// as artifacts and replaces the parts with artifact references.
+func offloadLargeParts(ctx agent.InvocationContext, event *session.Event, maxSize int) error {
+	artifacts := ctx.Artifacts()
+	if maxSize <= 0 || artifacts == nil || event.Content == nil {
+		return nil
+	}
+	for i, part := range event.Content.Parts {
+		if part == nil || part.InlineData == nil || len(part.InlineData.Data) <= maxSize {
+			continue
+		}
+		fileName := fmt.Sprintf("offloaded_%s_%d", event.ID, i)
+		resp, err := artifacts.Save(ctx, fileName, part)
+		if err != nil {
+			return fmt.Errorf("failed to offload part %d of event %s: %w", i, event.ID, err)
+		}
+		event.Content.Parts[i] = artifact.NewReference(fileName, resp.Version, part.InlineData.MIMEType)
 	}
 	return nil
 }
 
 // findAgentToRun returns the agent that should handle the next request based on
 // session history.
-func (r *Runner) findAgentToRun(session session.Session) (agent.Agent, error) {
+//
+// A message answering function calls, e.g. long-running tools or a request of
+// credentials, goes to the agent which made the calls, whether or not the
+// transfers lead to it.
+func (r *Runner) findAgentToRun(session session.Session, msg *genai.Content) (agent.Agent, error) {
+	if event := findMatchingFunctionCall(session, msg); event != nil {
+		if callingAgent := findAgent(r.rootAgent, event.Author); callingAgent != nil {
+			return callingAgent, nil
+		}
+	}
+
 	events := session.Events()
 	for i := events.Len() - 1; i >= 0; i-- {
 		event := events.At(i)
 
-		// TODO: findMatchingFunctionCall.
-
 		if event.Author == "user" {
 			continue
 		}
@@ -227,7 +716,7 @@ func (r *Runner) findAgentToRun(session session.Session) (agent.Agent, error) {
 		subAgent := findAgent(r.rootAgent, event.Author)
 		// Agent not found, continue looking for the other event.
 		if subAgent == nil {
-			log.Printf("Event from an unknown agent: %s, event id: %s", event.Author, event.ID)
+			log.Printf("Event from an unknown agent: %s, event id: %s, correlation id: %s", event.Author, event.ID, event.CorrelationID)
 			continue
 		}
 
@@ -240,6 +729,30 @@ func (r *Runner) findAgentToRun(session session.Session) (agent.Agent, error) {
 	return r.rootAgent, nil
 }
 
+// findMatchingFunctionCall returns the latest event of the session calling a
+// function the message responds to, or nil.
+func findMatchingFunctionCall(sess session.Session, msg *genai.Content) *session.Event {
+	ids := make(map[string]bool)
+	for _, fr := range utils.FunctionResponses(msg) {
+		if fr.ID != "" {
+			ids[fr.ID] = true
+		}
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+	events := sess.Events()
+	for i := events.Len() - 1; i >= 0; i-- {
+		event := events.At(i)
+		for _, fc := range utils.FunctionCalls(event.Content) {
+			if ids[fc.ID] {
+				return event
+			}
+		}
+	}
+	return nil
+}
+
 // checks if the agent and its parent chain allow transfer up the tree.
 func (r *Runner) isTransferableAcrossAgentTree(agentToRun agent.Agent) bool {
 	for curAgent := agentToRun; curAgent != nil; curAgent = r.parents[curAgent.Name()] {
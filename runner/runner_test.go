@@ -17,17 +17,25 @@ package runner
 import (
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"iter"
+	"slices"
 	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"google.golang.org/genai"
 
 	"google.golang.org/adk/agent"
 	"google.golang.org/adk/agent/llmagent"
 	"google.golang.org/adk/artifact"
+	"google.golang.org/adk/internal/utils"
+	"google.golang.org/adk/model"
 	"google.golang.org/adk/session"
+	"google.golang.org/adk/tool"
+	"google.golang.org/adk/tool/functiontool"
 )
 
 func TestRunner_findAgentToRun(t *testing.T) {
@@ -41,6 +49,7 @@ func TestRunner_findAgentToRun(t *testing.T) {
 		name      string
 		rootAgent agent.Agent
 		session   session.Session
+		msg       *genai.Content
 		wantAgent agent.Agent
 		wantErr   bool
 	}{
@@ -70,6 +79,37 @@ func TestRunner_findAgentToRun(t *testing.T) {
 			rootAgent: agentTree.root,
 			wantAgent: agentTree.root,
 		},
+		{
+			name: "function response to agent not allowing transfer",
+			session: createSession(t, t.Context(), appName, userID, sessionID, []*session.Event{
+				{
+					Author: "no_transfer_agent",
+					LLMResponse: model.LLMResponse{
+						Content: &genai.Content{
+							Role:  genai.RoleModel,
+							Parts: []*genai.Part{{FunctionCall: &genai.FunctionCall{ID: "call1", Name: "long_running"}}},
+						},
+					},
+				},
+				{
+					Author: "allows_transfer_agent",
+				},
+			}),
+			msg:       functionResponse("call1"),
+			rootAgent: agentTree.root,
+			wantAgent: agentTree.noTransferAgent,
+		},
+		{
+			name: "function response to unknown call",
+			session: createSession(t, t.Context(), appName, userID, sessionID, []*session.Event{
+				{
+					Author: "allows_transfer_agent",
+				},
+			}),
+			msg:       functionResponse("unknown"),
+			rootAgent: agentTree.root,
+			wantAgent: agentTree.allowsTransferAgent,
+		},
 		{
 			name: "no events from agents, call root",
 			session: createSession(t, t.Context(), appName, userID, sessionID, []*session.Event{
@@ -87,7 +127,7 @@ func TestRunner_findAgentToRun(t *testing.T) {
 			r := &Runner{
 				rootAgent: tt.rootAgent,
 			}
-			gotAgent, err := r.findAgentToRun(tt.session)
+			gotAgent, err := r.findAgentToRun(tt.session, tt.msg)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("Runner.findAgentToRun() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -99,6 +139,15 @@ func TestRunner_findAgentToRun(t *testing.T) {
 	}
 }
 
+func functionResponse(id string) *genai.Content {
+	return &genai.Content{
+		Role: genai.RoleUser,
+		Parts: []*genai.Part{{
+			FunctionResponse: &genai.FunctionResponse{ID: id, Name: "long_running", Response: map[string]any{"status": "done"}},
+		}},
+	}
+}
+
 func Test_findAgent(t *testing.T) {
 	agentTree := agentTree(t)
 
@@ -314,6 +363,151 @@ func TestRunner_SaveInputBlobsAsArtifacts(t *testing.T) {
 	}
 }
 
+func TestRunner_CorrelationID(t *testing.T) {
+	appName, userID := "testApp", "testUser"
+
+	tests := []struct {
+		name          string
+		correlationID string
+	}{
+		{
+			name:          "provided by the caller",
+			correlationID: "upstream-request-id",
+		},
+		{
+			name: "generated by the runner",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := t.Context()
+			sessionService := session.InMemoryService()
+
+			var gotAgentCorrelationID string
+			testAgent := must(agent.New(agent.Config{
+				Name: "test_agent",
+				Run: func(ctx agent.InvocationContext) iter.Seq2[*session.Event, error] {
+					return func(yield func(*session.Event, error) bool) {
+						gotAgentCorrelationID = agent.CorrelationIDFromContext(ctx)
+						ev := session.NewEvent(ctx.InvocationID())
+						ev.Content = genai.NewContentFromText("hello", genai.RoleModel)
+						yield(ev, nil)
+					}
+				},
+			}))
+
+			r, err := New(Config{
+				AppName:        appName,
+				Agent:          testAgent,
+				SessionService: sessionService,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			createResp, err := sessionService.Create(ctx, &session.CreateRequest{
+				AppName: appName,
+				UserID:  userID,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			sessionID := createResp.Session.ID()
+
+			runCtx := ctx
+			if tt.correlationID != "" {
+				runCtx = agent.WithCorrelationID(ctx, tt.correlationID)
+			}
+			for ev, err := range r.Run(runCtx, userID, sessionID, genai.NewContentFromText("hi", genai.RoleUser), agent.RunConfig{}) {
+				if err != nil {
+					t.Fatalf("r.Run() returned an error: %v", err)
+				}
+				if ev.CorrelationID != gotAgentCorrelationID {
+					t.Errorf("event CorrelationID = %q, want %q", ev.CorrelationID, gotAgentCorrelationID)
+				}
+			}
+
+			if gotAgentCorrelationID == "" {
+				t.Fatal("agent context does not carry a correlation ID")
+			}
+			if tt.correlationID != "" && gotAgentCorrelationID != tt.correlationID {
+				t.Errorf("agent correlation ID = %q, want %q", gotAgentCorrelationID, tt.correlationID)
+			}
+
+			getResp, err := sessionService.Get(ctx, &session.GetRequest{
+				AppName:   appName,
+				UserID:    userID,
+				SessionID: sessionID,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := getResp.Session.Events().Len(); got != 2 {
+				t.Fatalf("got %d persisted events, want 2", got)
+			}
+			for ev := range getResp.Session.Events().All() {
+				if ev.CorrelationID != gotAgentCorrelationID {
+					t.Errorf("persisted event (author %q) CorrelationID = %q, want %q", ev.Author, ev.CorrelationID, gotAgentCorrelationID)
+				}
+			}
+		})
+	}
+}
+
+func TestRunner_AutoCreateSession(t *testing.T) {
+	appName, userID, sessionID := "testApp", "testUser", "new_session"
+
+	for _, autoCreate := range []bool{false, true} {
+		t.Run(fmt.Sprintf("AutoCreateSession=%v", autoCreate), func(t *testing.T) {
+			ctx := t.Context()
+			sessionService := session.InMemoryService()
+			testAgent := must(agent.New(agent.Config{
+				Name: "test_agent",
+				Run: func(ctx agent.InvocationContext) iter.Seq2[*session.Event, error] {
+					return func(yield func(*session.Event, error) bool) {
+						ev := session.NewEvent(ctx.InvocationID())
+						ev.Content = genai.NewContentFromText("hello", genai.RoleModel)
+						yield(ev, nil)
+					}
+				},
+			}))
+			r, err := New(Config{
+				AppName:           appName,
+				Agent:             testAgent,
+				SessionService:    sessionService,
+				AutoCreateSession: autoCreate,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var runErr error
+			for _, err := range r.Run(ctx, userID, sessionID, genai.NewContentFromText("hi", genai.RoleUser), agent.RunConfig{}) {
+				if err != nil {
+					runErr = err
+				}
+			}
+			if !autoCreate {
+				if !errors.Is(runErr, session.ErrSessionNotFound) {
+					t.Fatalf("r.Run() error = %v, want %v", runErr, session.ErrSessionNotFound)
+				}
+				return
+			}
+			if runErr != nil {
+				t.Fatalf("r.Run() returned an error: %v", runErr)
+			}
+			getResp, err := sessionService.Get(ctx, &session.GetRequest{AppName: appName, UserID: userID, SessionID: sessionID})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := getResp.Session.Events().Len(); got != 2 {
+				t.Errorf("got %d persisted events, want 2", got)
+			}
+		})
+	}
+}
+
 // creates agentTree for tests and returns references to the agents
 func agentTree(t *testing.T) agentTreeStruct {
 	t.Helper()
@@ -370,3 +564,522 @@ func createSession(t *testing.T, ctx context.Context, sessionID, appName, userID
 
 	return resp.Session
 }
+
+// fakeLLM records the requests it receives and responds with fixed content
+// and usage.
+type fakeLLM struct {
+	response *genai.Content
+	usage    *genai.GenerateContentResponseUsageMetadata
+	requests []*model.LLMRequest
+}
+
+func (m *fakeLLM) Name() string {
+	return "fake"
+}
+
+func (m *fakeLLM) GenerateContent(ctx context.Context, req *model.LLMRequest, stream bool) iter.Seq2[*model.LLMResponse, error] {
+	m.requests = append(m.requests, req)
+	return func(yield func(*model.LLMResponse, error) bool) {
+		yield(&model.LLMResponse{Content: m.response, UsageMetadata: m.usage}, nil)
+	}
+}
+
+func TestRunner_MaxInlinePartSize(t *testing.T) {
+	ctx := t.Context()
+	appName, userID, sessionID := "testApp", "testUser", "testSession"
+
+	sessionService := session.InMemoryService()
+	artifactService := artifact.InMemoryService()
+
+	largeImage := bytes.Repeat([]byte{0xff}, 64)
+	llm := &fakeLLM{response: &genai.Content{
+		Role: genai.RoleModel,
+		Parts: []*genai.Part{
+			genai.NewPartFromText("here is the edited image"),
+			genai.NewPartFromBytes(largeImage, "image/png"),
+		},
+	}}
+	testAgent := must(llmagent.New(llmagent.Config{
+		Name:  "image_agent",
+		Model: llm,
+	}))
+	r, err := New(Config{
+		AppName:         appName,
+		Agent:           testAgent,
+		SessionService:  sessionService,
+		ArtifactService: artifactService,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := sessionService.Create(ctx, &session.CreateRequest{AppName: appName, UserID: userID, SessionID: sessionID}); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := agent.RunConfig{MaxInlinePartSize: 32}
+	run := func(msg *genai.Content) {
+		t.Helper()
+		for _, err := range r.Run(ctx, userID, sessionID, msg, cfg) {
+			if err != nil {
+				t.Fatalf("r.Run() returned an error: %v", err)
+			}
+		}
+	}
+	run(&genai.Content{Role: genai.RoleUser, Parts: []*genai.Part{
+		genai.NewPartFromText("edit this image"),
+		genai.NewPartFromBytes(largeImage, "image/png"),
+		genai.NewPartFromBytes([]byte("small"), "text/plain"),
+	}})
+	run(genai.NewContentFromText("thanks", genai.RoleUser))
+
+	getResp, err := sessionService.Get(ctx, &session.GetRequest{AppName: appName, UserID: userID, SessionID: sessionID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The stored events only hold references to the large parts.
+	var references int
+	for ev := range getResp.Session.Events().All() {
+		for _, part := range ev.Content.Parts {
+			if part.InlineData != nil && len(part.InlineData.Data) > cfg.MaxInlinePartSize {
+				t.Errorf("event %s from %s holds a part of %d bytes", ev.ID, ev.Author, len(part.InlineData.Data))
+			}
+			if fileName, _, ok := artifact.ParseReference(part); ok {
+				references++
+				if part.FileData.MIMEType != "image/png" {
+					t.Errorf("reference to %s has MIME type %q, want %q", fileName, part.FileData.MIMEType, "image/png")
+				}
+			}
+		}
+	}
+	if references != 3 {
+		t.Errorf("got %d artifact references in session, want 3", references)
+	}
+
+	// The model receives the original data.
+	if len(llm.requests) != 2 {
+		t.Fatalf("got %d model requests, want 2", len(llm.requests))
+	}
+	var inlineImages int
+	for _, content := range llm.requests[1].Contents {
+		for _, part := range content.Parts {
+			if _, _, ok := artifact.ParseReference(part); ok {
+				t.Errorf("model request holds an unresolved artifact reference %q", part.FileData.FileURI)
+			}
+			if part.InlineData != nil && bytes.Equal(part.InlineData.Data, largeImage) {
+				inlineImages++
+			}
+		}
+	}
+	if inlineImages != 2 {
+		t.Errorf("got %d inline images in the model request, want 2", inlineImages)
+	}
+}
+
+func TestRunner_EmitTurnBoundaries(t *testing.T) {
+	ctx := t.Context()
+	appName, userID := "testApp", "testUser"
+	sessionService := session.InMemoryService()
+	llm := &fakeLLM{response: genai.NewContentFromText("hello", genai.RoleModel)}
+	r, err := New(Config{
+		AppName:        appName,
+		Agent:          must(llmagent.New(llmagent.Config{Name: "agent", Model: llm})),
+		SessionService: sessionService,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	createResp, err := sessionService.Create(ctx, &session.CreateRequest{AppName: appName, UserID: userID})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var streamed []*session.Event
+	for ev, err := range r.Run(ctx, userID, createResp.Session.ID(), genai.NewContentFromText("hi", genai.RoleUser), agent.RunConfig{EmitTurnBoundaries: true}) {
+		if err != nil {
+			t.Fatalf("r.Run() returned an error: %v", err)
+		}
+		streamed = append(streamed, ev)
+	}
+
+	if len(streamed) != 3 {
+		t.Fatalf("got %d events, want 3: turn start, answer and turn complete", len(streamed))
+	}
+	start, answer, complete := streamed[0], streamed[1], streamed[2]
+	if start.TurnBoundary != session.TurnStart || start.Author != "user" {
+		t.Errorf("first event = (boundary %q, author %q), want the user message marked as turn start", start.TurnBoundary, start.Author)
+	}
+	if answer.TurnBoundary != "" {
+		t.Errorf("answer TurnBoundary = %q, want none", answer.TurnBoundary)
+	}
+	if complete.TurnBoundary != session.TurnComplete || complete.Content != nil {
+		t.Errorf("last event = (boundary %q, content %v), want an empty turn complete marker", complete.TurnBoundary, complete.Content)
+	}
+	for i, ev := range streamed {
+		if ev.TurnID == "" || ev.TurnID != start.TurnID {
+			t.Errorf("event %d TurnID = %q, want %q", i, ev.TurnID, start.TurnID)
+		}
+		if ev.Sequence != int64(i+1) {
+			t.Errorf("event %d Sequence = %d, want %d", i, ev.Sequence, i+1)
+		}
+	}
+
+	// The markers are not stored.
+	getResp, err := sessionService.Get(ctx, &session.GetRequest{AppName: appName, UserID: userID, SessionID: createResp.Session.ID()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := getResp.Session.Events().Len(); got != 2 {
+		t.Fatalf("got %d stored events, want 2", got)
+	}
+	for ev := range getResp.Session.Events().All() {
+		if ev.TurnBoundary != "" {
+			t.Errorf("stored event from %s has TurnBoundary %q, want none", ev.Author, ev.TurnBoundary)
+		}
+	}
+	turns := slices.Collect(session.Turns(getResp.Session.Events().All()))
+	if len(turns) != 1 || turns[0].ID != start.TurnID || turns[0].UserMessage() == nil || turns[0].FinalResponse() == nil {
+		t.Errorf("session.Turns() = %+v, want one complete turn %q", turns, start.TurnID)
+	}
+}
+
+// streamingLLM yields the responses.
+type streamingLLM struct {
+	responses []*model.LLMResponse
+}
+
+func (m *streamingLLM) Name() string {
+	return "streaming"
+}
+
+func (m *streamingLLM) GenerateContent(ctx context.Context, req *model.LLMRequest, stream bool) iter.Seq2[*model.LLMResponse, error] {
+	return func(yield func(*model.LLMResponse, error) bool) {
+		for _, resp := range m.responses {
+			if !yield(resp, nil) {
+				return
+			}
+		}
+	}
+}
+
+func TestRunner_StripThoughts(t *testing.T) {
+	thought := &genai.Part{Text: "Greeting.", Thought: true, ThoughtSignature: []byte("sig")}
+	answer := genai.NewPartFromText("Hello")
+	tests := []struct {
+		name        string
+		cfg         agent.RunConfig
+		wantYielded []string
+		wantStored  string
+	}{
+		{
+			name:        "kept",
+			wantYielded: []string{"thoughts Greeting.", "text Hel", "thoughts Greeting. text Hello"},
+			wantStored:  "thoughts Greeting. text Hello",
+		},
+		{
+			name:        "stripped from output",
+			cfg:         agent.RunConfig{StripThoughtsFromOutput: true},
+			wantYielded: []string{"text Hel", "text Hello"},
+			wantStored:  "thoughts Greeting. text Hello",
+		},
+		{
+			name:        "stripped from history",
+			cfg:         agent.RunConfig{StripThoughtsFromHistory: true},
+			wantYielded: []string{"thoughts Greeting.", "text Hel", "thoughts Greeting. text Hello"},
+			wantStored:  "text Hello",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := t.Context()
+			llm := &streamingLLM{responses: []*model.LLMResponse{
+				{Content: genai.NewContentFromParts([]*genai.Part{{Text: "Greeting.", Thought: true}}, genai.RoleModel), Partial: true},
+				{Content: genai.NewContentFromText("Hel", genai.RoleModel), Partial: true},
+				{Content: genai.NewContentFromParts([]*genai.Part{thought, answer}, genai.RoleModel)},
+			}}
+			sessionService := session.InMemoryService()
+			r, err := New(Config{
+				AppName:        "testApp",
+				Agent:          must(llmagent.New(llmagent.Config{Name: "agent", Model: llm})),
+				SessionService: sessionService,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			created, err := sessionService.Create(ctx, &session.CreateRequest{AppName: "testApp", UserID: "user"})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			describe := func(ev *session.Event) string {
+				var s []string
+				if th := ev.Thoughts(); th != "" {
+					s = append(s, "thoughts "+th)
+				}
+				if text := ev.Text(); text != "" {
+					s = append(s, "text "+text)
+				}
+				return strings.Join(s, " ")
+			}
+			var yielded []string
+			for ev, err := range r.Run(ctx, "user", created.Session.ID(), genai.NewContentFromText("hi", genai.RoleUser), tc.cfg) {
+				if err != nil {
+					t.Fatal(err)
+				}
+				yielded = append(yielded, describe(ev))
+			}
+			if !slices.Equal(yielded, tc.wantYielded) {
+				t.Errorf("yielded events = %q, want %q", yielded, tc.wantYielded)
+			}
+
+			resp, err := sessionService.Get(ctx, &session.GetRequest{AppName: "testApp", UserID: "user", SessionID: created.Session.ID()})
+			if err != nil {
+				t.Fatal(err)
+			}
+			stored := resp.Session.Events().At(resp.Session.Events().Len() - 1)
+			if got := describe(stored); got != tc.wantStored {
+				t.Errorf("stored event = %q, want %q", got, tc.wantStored)
+			}
+			parts := stored.Content.Parts
+			if sig := parts[0].ThoughtSignature; string(sig) != "sig" {
+				t.Errorf("stored thought signature = %q, want %q", sig, "sig")
+			}
+		})
+	}
+}
+
+// blockingLLM blocks until the request is canceled.
+type blockingLLM struct{}
+
+func (blockingLLM) Name() string {
+	return "blocking"
+}
+
+func (blockingLLM) GenerateContent(ctx context.Context, req *model.LLMRequest, stream bool) iter.Seq2[*model.LLMResponse, error] {
+	return func(yield func(*model.LLMResponse, error) bool) {
+		<-ctx.Done()
+		yield(nil, ctx.Err())
+	}
+}
+
+func TestRunner_Limits(t *testing.T) {
+	ping, err := functiontool.New(functiontool.Config{Name: "ping", Description: "Pings."},
+		func(tool.Context, struct{}) (string, error) { return "pong", nil })
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The model calls the tool forever.
+	looping := &fakeLLM{
+		response: &genai.Content{
+			Role: genai.RoleModel,
+			Parts: []*genai.Part{
+				{FunctionCall: &genai.FunctionCall{Name: "ping", Args: map[string]any{}}},
+				{FunctionCall: &genai.FunctionCall{Name: "ping", Args: map[string]any{}}},
+			},
+		},
+		usage: &genai.GenerateContentResponseUsageMetadata{TotalTokenCount: 40},
+	}
+
+	for _, tc := range []struct {
+		name      string
+		llm       model.LLM
+		cfg       agent.RunConfig
+		wantErr   *agent.LimitExceededError
+		wantCalls int
+	}{
+		{
+			name:      "LLM calls",
+			llm:       looping,
+			cfg:       agent.RunConfig{MaxLLMCalls: 3},
+			wantErr:   &agent.LimitExceededError{Limit: agent.LimitLLMCalls, Max: 3},
+			wantCalls: 3,
+		},
+		{
+			name:      "tool calls",
+			llm:       looping,
+			cfg:       agent.RunConfig{MaxToolCalls: 5},
+			wantErr:   &agent.LimitExceededError{Limit: agent.LimitToolCalls, Max: 5},
+			wantCalls: 3,
+		},
+		{
+			name:      "tokens",
+			llm:       looping,
+			cfg:       agent.RunConfig{MaxTokens: 100},
+			wantErr:   &agent.LimitExceededError{Limit: agent.LimitTokens, Max: 100},
+			wantCalls: 3,
+		},
+		{
+			name:    "timeout",
+			llm:     blockingLLM{},
+			cfg:     agent.RunConfig{Timeout: 10 * time.Millisecond},
+			wantErr: &agent.LimitExceededError{Limit: agent.LimitTimeout, Timeout: 10 * time.Millisecond},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			looping.requests = nil
+			sessionService := session.InMemoryService()
+			r, err := New(Config{
+				AppName:        "testApp",
+				Agent:          must(llmagent.New(llmagent.Config{Name: "agent", Model: tc.llm, Tools: []tool.Tool{ping}})),
+				SessionService: sessionService,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			resp, err := sessionService.Create(t.Context(), &session.CreateRequest{AppName: "testApp", UserID: "user"})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var limitEvent *session.Event
+			var gotErrs []error
+			for ev, err := range r.Run(t.Context(), "user", resp.Session.ID(), genai.NewContentFromText("hi", genai.RoleUser), tc.cfg) {
+				if err != nil {
+					gotErrs = append(gotErrs, err)
+					continue
+				}
+				if ev.ErrorCode == LimitErrorCode {
+					limitEvent = ev
+				}
+			}
+			if len(gotErrs) != 1 {
+				t.Fatalf("got errors %v, want one limit error", gotErrs)
+			}
+			var limitErr *agent.LimitExceededError
+			if !errors.As(gotErrs[0], &limitErr) {
+				t.Fatalf("error = %v, want a LimitExceededError", gotErrs[0])
+			}
+			if *limitErr != *tc.wantErr {
+				t.Errorf("error = %+v, want %+v", limitErr, tc.wantErr)
+			}
+			if limitEvent == nil || limitEvent.CustomMetadata[LimitMetadataKey].(map[string]any)["limit"] != tc.wantErr.Limit {
+				t.Errorf("limit event = %v, want one reporting the limit", limitEvent)
+			}
+			if tc.wantCalls > 0 && len(looping.requests) != tc.wantCalls {
+				t.Errorf("got %d model calls, want %d", len(looping.requests), tc.wantCalls)
+			}
+		})
+	}
+}
+
+// fakeLiveLLM answers over live connections: it calls the weather tool for a
+// user message, then answers with the function response.
+type fakeLiveLLM struct {
+	fakeLLM
+	mu   sync.Mutex
+	sent []*model.LiveRequest
+}
+
+func (m *fakeLiveLLM) ConnectLive(ctx context.Context, req *model.LLMRequest) (model.LiveConnection, error) {
+	return &fakeLiveConnection{llm: m, responses: make(chan *model.LLMResponse, 10), closed: make(chan struct{})}, nil
+}
+
+type fakeLiveConnection struct {
+	llm       *fakeLiveLLM
+	responses chan *model.LLMResponse
+	closed    chan struct{}
+	closeOnce sync.Once
+}
+
+func (c *fakeLiveConnection) Send(req *model.LiveRequest) error {
+	c.llm.mu.Lock()
+	c.llm.sent = append(c.llm.sent, req)
+	c.llm.mu.Unlock()
+	if req.Content == nil {
+		return nil
+	}
+	if len(utils.FunctionResponses(req.Content)) > 0 {
+		c.responses <- &model.LLMResponse{Content: genai.NewContentFromText("It is ", genai.RoleModel), Partial: true}
+		c.responses <- &model.LLMResponse{Content: genai.NewContentFromText("It is sunny.", genai.RoleModel)}
+		c.responses <- &model.LLMResponse{TurnComplete: true}
+		return nil
+	}
+	c.responses <- &model.LLMResponse{Content: genai.NewContentFromParts([]*genai.Part{
+		genai.NewPartFromFunctionCall("weather", map[string]any{}),
+	}, genai.RoleModel)}
+	return nil
+}
+
+func (c *fakeLiveConnection) Receive() iter.Seq2[*model.LLMResponse, error] {
+	return func(yield func(*model.LLMResponse, error) bool) {
+		for {
+			select {
+			case resp := <-c.responses:
+				if !yield(resp, nil) {
+					return
+				}
+			case <-c.closed:
+				return
+			}
+		}
+	}
+}
+
+func (c *fakeLiveConnection) Close() error {
+	c.closeOnce.Do(func() { close(c.closed) })
+	return nil
+}
+
+func TestRunner_RunLive(t *testing.T) {
+	ctx := t.Context()
+	appName, userID := "testApp", "testUser"
+	sessionService := session.InMemoryService()
+	weather, err := functiontool.New(functiontool.Config{Name: "weather", Description: "Returns the weather."},
+		func(tool.Context, struct{}) (string, error) { return "sunny", nil })
+	if err != nil {
+		t.Fatal(err)
+	}
+	llm := &fakeLiveLLM{}
+	r, err := New(Config{
+		AppName:        appName,
+		Agent:          must(llmagent.New(llmagent.Config{Name: "agent", Model: llm, Tools: []tool.Tool{weather}})),
+		SessionService: sessionService,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	createResp, err := sessionService.Create(ctx, &session.CreateRequest{AppName: appName, UserID: userID})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	queue := agent.NewLiveRequestQueue()
+	if err := queue.SendContent(genai.NewContentFromText("What's the weather?", genai.RoleUser)); err != nil {
+		t.Fatal(err)
+	}
+	var texts []string
+	var streamed int
+	for ev, err := range r.RunLive(ctx, userID, createResp.Session.ID(), queue, agent.RunConfig{}) {
+		if err != nil {
+			t.Fatalf("r.RunLive() returned an error: %v", err)
+		}
+		streamed++
+		if ev.Content != nil && len(ev.Content.Parts) > 0 && ev.Content.Parts[0].Text != "" {
+			texts = append(texts, ev.Content.Parts[0].Text)
+		}
+		if ev.TurnComplete {
+			queue.Close()
+		}
+	}
+
+	if want := []string{"It is ", "It is sunny."}; !slices.Equal(texts, want) {
+		t.Errorf("streamed texts = %q, want %q", texts, want)
+	}
+	if streamed != 5 {
+		t.Errorf("got %d events, want 5: function call, function response, partial text, text and turn complete", streamed)
+	}
+	if len(llm.sent) != 2 || len(utils.FunctionResponses(llm.sent[1].Content)) != 1 {
+		t.Errorf("sent %d requests, want the user message then the function response", len(llm.sent))
+	}
+	if err := queue.SendContent(genai.NewContentFromText("hi", genai.RoleUser)); !errors.Is(err, agent.ErrLiveRequestQueueClosed) {
+		t.Errorf("SendContent() on a closed queue = %v, want %v", err, agent.ErrLiveRequestQueueClosed)
+	}
+
+	// The partial event is not stored.
+	getResp, err := sessionService.Get(ctx, &session.GetRequest{AppName: appName, UserID: userID, SessionID: createResp.Session.ID()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := getResp.Session.Events().Len(); got != 4 {
+		t.Errorf("got %d stored events, want 4", got)
+	}
+}
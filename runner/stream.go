@@ -0,0 +1,208 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package runner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"iter"
+	"slices"
+	"sync"
+	"time"
+
+	"google.golang.org/adk/session"
+)
+
+const (
+	defaultStreamBufferMaxEvents = 1000
+	defaultStreamBufferRetention = 5 * time.Minute
+)
+
+var (
+	// ErrStreamNotFound is returned by [StreamBuffer.Resume] if the buffer
+	// holds no events for the invocation, e.g. because it has expired.
+	ErrStreamNotFound = errors.New("stream not found")
+	// ErrSequenceEvicted is returned by [StreamBuffer.Resume] if events
+	// following the requested sequence number are no longer buffered.
+	// Non-partial events can still be recovered from the session.
+	ErrSequenceEvicted = errors.New("requested sequence was evicted from the stream buffer")
+)
+
+// StreamBufferConfig is used to create a [StreamBuffer].
+type StreamBufferConfig struct {
+	// MaxEvents is the number of most recent events retained per invocation.
+	// Older events are evicted. Defaults to 1000.
+	MaxEvents int
+	// Retention is how long the events of a completed invocation remain
+	// available for resumption. Defaults to 5 minutes.
+	Retention time.Duration
+}
+
+// StreamBuffer retains the most recent events streamed by runners, so that a
+// client reconnecting over an unreliable transport can resume an invocation
+// from the last sequence number it received instead of losing or duplicating
+// output. See session.Event.Sequence.
+//
+// A StreamBuffer is safe for concurrent use and can be shared by several
+// runners through Config.StreamBuffer.
+type StreamBuffer struct {
+	maxEvents int
+	retention time.Duration
+	now       func() time.Time
+
+	mu      sync.Mutex
+	streams map[string]*invocationStream
+}
+
+// invocationStream holds the buffered events of one invocation.
+type invocationStream struct {
+	// events are the buffered events, with contiguous sequence numbers.
+	events []*session.Event
+	// next is the sequence number of the next event.
+	next int64
+	// updated is closed and replaced whenever the stream changes.
+	updated chan struct{}
+	done    bool
+	doneAt  time.Time
+}
+
+// NewStreamBuffer creates a new [StreamBuffer].
+func NewStreamBuffer(cfg StreamBufferConfig) *StreamBuffer {
+	if cfg.MaxEvents <= 0 {
+		cfg.MaxEvents = defaultStreamBufferMaxEvents
+	}
+	if cfg.Retention <= 0 {
+		cfg.Retention = defaultStreamBufferRetention
+	}
+	return &StreamBuffer{
+		maxEvents: cfg.MaxEvents,
+		retention: cfg.Retention,
+		now:       time.Now,
+		streams:   make(map[string]*invocationStream),
+	}
+}
+
+// Resume yields the events of the invocation with a sequence number greater
+// than afterSequence. Pass 0 to replay all buffered events. If the invocation
+// is still running, Resume keeps yielding its events as they are produced
+// until it completes or ctx is done.
+//
+// Resume yields ErrStreamNotFound if the invocation is unknown or expired,
+// and ErrSequenceEvicted if some of the requested events were already
+// evicted. In both cases the client should reload the session to recover.
+func (b *StreamBuffer) Resume(ctx context.Context, invocationID string, afterSequence int64) iter.Seq2[*session.Event, error] {
+	return func(yield func(*session.Event, error) bool) {
+		last := afterSequence
+		for {
+			events, updated, done, err := b.eventsAfter(invocationID, last)
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+			for _, ev := range events {
+				if !yield(ev, nil) {
+					return
+				}
+				last = ev.Sequence
+			}
+			if len(events) > 0 {
+				continue
+			}
+			if done {
+				return
+			}
+			select {
+			case <-updated:
+			case <-ctx.Done():
+				yield(nil, ctx.Err())
+				return
+			}
+		}
+	}
+}
+
+// eventsAfter returns the buffered events following the given sequence
+// number, a channel closed on the next update of the stream and whether the
+// invocation has completed.
+func (b *StreamBuffer) eventsAfter(invocationID string, sequence int64) ([]*session.Event, <-chan struct{}, bool, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	s, ok := b.streams[invocationID]
+	if !ok {
+		return nil, nil, false, fmt.Errorf("invocation %q: %w", invocationID, ErrStreamNotFound)
+	}
+	first := s.next - int64(len(s.events))
+	if sequence < first-1 {
+		return nil, nil, false, fmt.Errorf("invocation %q, sequence %d, oldest buffered sequence %d: %w", invocationID, sequence, first, ErrSequenceEvicted)
+	}
+	var events []*session.Event
+	if start := sequence - first + 1; start < int64(len(s.events)) {
+		events = append(events, s.events[start:]...)
+	}
+	return events, s.updated, s.done, nil
+}
+
+// start registers a new invocation and expires completed ones.
+func (b *StreamBuffer) start(invocationID string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := b.now()
+	for id, s := range b.streams {
+		if s.done && now.Sub(s.doneAt) > b.retention {
+			delete(b.streams, id)
+		}
+	}
+	b.streams[invocationID] = &invocationStream{next: 1, updated: make(chan struct{})}
+}
+
+// add buffers the event. The event must carry the next sequence number of
+// the invocation.
+func (b *StreamBuffer) add(invocationID string, ev *session.Event) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	s, ok := b.streams[invocationID]
+	if !ok {
+		return
+	}
+	s.events = append(s.events, ev)
+	if len(s.events) > b.maxEvents {
+		s.events = slices.Delete(s.events, 0, len(s.events)-b.maxEvents)
+	}
+	s.next = ev.Sequence + 1
+	s.notify()
+}
+
+// finish marks the invocation as completed.
+func (b *StreamBuffer) finish(invocationID string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	s, ok := b.streams[invocationID]
+	if !ok {
+		return
+	}
+	s.done = true
+	s.doneAt = b.now()
+	s.notify()
+}
+
+func (s *invocationStream) notify() {
+	close(s.updated)
+	s.updated = make(chan struct{})
+}
@@ -0,0 +1,234 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package runner
+
+import (
+	"errors"
+	"fmt"
+	"iter"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/agent"
+	"google.golang.org/adk/agent/llmagent"
+	"google.golang.org/adk/model"
+	"google.golang.org/adk/session"
+)
+
+// newStreamingRunner creates a runner whose agent yields one partial and one
+// final event for every value received from next, until next is closed.
+func newStreamingRunner(t *testing.T, buffer *StreamBuffer, next <-chan string, invocationIDs chan<- string) (*Runner, string) {
+	t.Helper()
+	testAgent := must(agent.New(agent.Config{
+		Name: "streaming_agent",
+		Run: func(ctx agent.InvocationContext) iter.Seq2[*session.Event, error] {
+			return func(yield func(*session.Event, error) bool) {
+				if invocationIDs != nil {
+					invocationIDs <- ctx.InvocationID()
+				}
+				for text := range next {
+					partial := session.NewEvent(ctx.InvocationID())
+					partial.Content = genai.NewContentFromText(text, genai.RoleModel)
+					partial.Partial = true
+					if !yield(partial, nil) {
+						return
+					}
+					final := session.NewEvent(ctx.InvocationID())
+					final.Content = genai.NewContentFromText(text+".", genai.RoleModel)
+					if !yield(final, nil) {
+						return
+					}
+				}
+			}
+		},
+	}))
+	sessionService := session.InMemoryService()
+	r, err := New(Config{
+		AppName:        "testApp",
+		Agent:          testAgent,
+		SessionService: sessionService,
+		StreamBuffer:   buffer,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	createResp, err := sessionService.Create(t.Context(), &session.CreateRequest{AppName: "testApp", UserID: "testUser"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return r, createResp.Session.ID()
+}
+
+func eventTexts(events iter.Seq2[*session.Event, error]) ([]string, error) {
+	var texts []string
+	for ev, err := range events {
+		if err != nil {
+			return texts, err
+		}
+		texts = append(texts, fmt.Sprintf("%d:%s", ev.Sequence, ev.Content.Parts[0].Text))
+	}
+	return texts, nil
+}
+
+func TestStreamBuffer_Resume(t *testing.T) {
+	buffer := NewStreamBuffer(StreamBufferConfig{MaxEvents: 3})
+	next := make(chan string, 2)
+	next <- "a"
+	next <- "b"
+	close(next)
+	r, sessionID := newStreamingRunner(t, buffer, next, nil)
+
+	var invocationID string
+	var got []string
+	for ev, err := range r.Run(t.Context(), "testUser", sessionID, genai.NewContentFromText("hi", genai.RoleUser), agent.RunConfig{}) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		invocationID = ev.InvocationID
+		got = append(got, fmt.Sprintf("%d:%s", ev.Sequence, ev.Content.Parts[0].Text))
+	}
+	if diff := cmp.Diff([]string{"1:a", "2:a.", "3:b", "4:b."}, got); diff != "" {
+		t.Errorf("Run() events mismatch (-want +got):\n%s", diff)
+	}
+
+	got, err := eventTexts(buffer.Resume(t.Context(), invocationID, 2))
+	if err != nil {
+		t.Fatalf("Resume() error = %v", err)
+	}
+	if diff := cmp.Diff([]string{"3:b", "4:b."}, got); diff != "" {
+		t.Errorf("Resume() events mismatch (-want +got):\n%s", diff)
+	}
+
+	got, err = eventTexts(buffer.Resume(t.Context(), invocationID, 4))
+	if err != nil || len(got) != 0 {
+		t.Errorf("Resume() after last event = %v, %v, want no events", got, err)
+	}
+
+	// Only the last 3 events are retained.
+	if _, err := eventTexts(buffer.Resume(t.Context(), invocationID, 0)); !errors.Is(err, ErrSequenceEvicted) {
+		t.Errorf("Resume() of evicted sequence error = %v, want %v", err, ErrSequenceEvicted)
+	}
+	if _, err := eventTexts(buffer.Resume(t.Context(), "unknown", 0)); !errors.Is(err, ErrStreamNotFound) {
+		t.Errorf("Resume() of unknown invocation error = %v, want %v", err, ErrStreamNotFound)
+	}
+}
+
+func TestStreamBuffer_ResumeWithoutThoughts(t *testing.T) {
+	thought := func(text string) *model.LLMResponse {
+		return &model.LLMResponse{Content: genai.NewContentFromParts([]*genai.Part{{Text: text, Thought: true}}, genai.RoleModel), Partial: true}
+	}
+	llm := &streamingLLM{responses: []*model.LLMResponse{
+		thought("Greeting."),
+		{Content: genai.NewContentFromText("Hel", genai.RoleModel), Partial: true},
+		thought("Still greeting."),
+		{Content: genai.NewContentFromText("lo", genai.RoleModel), Partial: true},
+		{Content: genai.NewContentFromText("Hello", genai.RoleModel)},
+	}}
+	buffer := NewStreamBuffer(StreamBufferConfig{})
+	sessionService := session.InMemoryService()
+	r, err := New(Config{
+		AppName:        "testApp",
+		Agent:          must(llmagent.New(llmagent.Config{Name: "agent", Model: llm})),
+		SessionService: sessionService,
+		StreamBuffer:   buffer,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	created, err := sessionService.Create(t.Context(), &session.CreateRequest{AppName: "testApp", UserID: "testUser"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var invocationID string
+	var got []string
+	for ev, err := range r.Run(t.Context(), "testUser", created.Session.ID(), genai.NewContentFromText("hi", genai.RoleUser), agent.RunConfig{StripThoughtsFromOutput: true}) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		invocationID = ev.InvocationID
+		got = append(got, fmt.Sprintf("%d:%s", ev.Sequence, ev.Content.Parts[0].Text))
+	}
+	// The dropped thought chunks are not numbered.
+	if diff := cmp.Diff([]string{"1:Hel", "2:lo", "3:Hello"}, got); diff != "" {
+		t.Errorf("Run() events mismatch (-want +got):\n%s", diff)
+	}
+	for after, want := range map[int64][]string{
+		1: {"2:lo", "3:Hello"},
+		2: {"3:Hello"},
+	} {
+		got, err := eventTexts(buffer.Resume(t.Context(), invocationID, after))
+		if err != nil {
+			t.Fatalf("Resume(%d) error = %v", after, err)
+		}
+		if diff := cmp.Diff(want, got); diff != "" {
+			t.Errorf("Resume(%d) events mismatch (-want +got):\n%s", after, diff)
+		}
+	}
+}
+
+func TestStreamBuffer_ResumeFollowsRunningInvocation(t *testing.T) {
+	buffer := NewStreamBuffer(StreamBufferConfig{})
+	next := make(chan string)
+	invocationIDs := make(chan string, 1)
+	r, sessionID := newStreamingRunner(t, buffer, next, invocationIDs)
+
+	runDone := make(chan error)
+	go func() {
+		_, err := eventTexts(r.Run(t.Context(), "testUser", sessionID, genai.NewContentFromText("hi", genai.RoleUser), agent.RunConfig{}))
+		runDone <- err
+	}()
+	invocationID := <-invocationIDs
+
+	go func() {
+		next <- "a"
+		next <- "b"
+		close(next)
+	}()
+	got, err := eventTexts(buffer.Resume(t.Context(), invocationID, 0))
+	if err != nil {
+		t.Fatalf("Resume() error = %v", err)
+	}
+	if diff := cmp.Diff([]string{"1:a", "2:a.", "3:b", "4:b."}, got); diff != "" {
+		t.Errorf("Resume() events mismatch (-want +got):\n%s", diff)
+	}
+	if err := <-runDone; err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+}
+
+func TestStreamBuffer_Retention(t *testing.T) {
+	buffer := NewStreamBuffer(StreamBufferConfig{Retention: time.Minute})
+	now := time.Now()
+	buffer.now = func() time.Time { return now }
+
+	buffer.start("old")
+	buffer.finish("old")
+	buffer.start("running")
+
+	now = now.Add(2 * time.Minute)
+	buffer.start("new")
+
+	if _, err := eventTexts(buffer.Resume(t.Context(), "old", 0)); !errors.Is(err, ErrStreamNotFound) {
+		t.Errorf("Resume() of expired invocation error = %v, want %v", err, ErrStreamNotFound)
+	}
+	buffer.finish("running")
+	if _, err := eventTexts(buffer.Resume(t.Context(), "running", 0)); err != nil {
+		t.Errorf("Resume() of invocation that was running error = %v, want nil", err)
+	}
+}
@@ -0,0 +1,119 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package runner
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/agent"
+	"google.golang.org/adk/agent/llmagent"
+	"google.golang.org/adk/model/mockmodel"
+	"google.golang.org/adk/session"
+	"google.golang.org/adk/tool"
+	"google.golang.org/adk/tool/functiontool"
+)
+
+func TestRunner_Tracing(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	prev := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	t.Cleanup(func() { otel.SetTracerProvider(prev) })
+
+	type args struct {
+		City string `json:"city"`
+	}
+	weather, err := functiontool.New(functiontool.Config{Name: "get_weather", Description: "Returns the weather."},
+		func(ctx tool.Context, a args) (string, error) { return "sunny", nil })
+	if err != nil {
+		t.Fatal(err)
+	}
+	final := mockmodel.Text("It is sunny.")
+	final.FinishReason = genai.FinishReasonStop
+	final.UsageMetadata = &genai.GenerateContentResponseUsageMetadata{PromptTokenCount: 12, CandidatesTokenCount: 4}
+	llm := mockmodel.New(mockmodel.FunctionCall("get_weather", map[string]any{"city": "Paris"}), final)
+	r, err := New(Config{
+		AppName:           "app",
+		Agent:             must(llmagent.New(llmagent.Config{Name: "weather_agent", Model: llm, Tools: []tool.Tool{weather}})),
+		SessionService:    session.InMemoryService(),
+		AutoCreateSession: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, err := range r.Run(t.Context(), "user", "session", genai.NewContentFromText("Weather?", genai.RoleUser), agent.RunConfig{}) {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	spans := recorder.Ended()
+	byID := make(map[string]sdktrace.ReadOnlySpan)
+	for _, span := range spans {
+		byID[span.SpanContext().SpanID().String()] = span
+	}
+	// path returns the names of the span and of its ancestors, the root
+	// first.
+	path := func(span sdktrace.ReadOnlySpan) []string {
+		var names []string
+		for span != nil {
+			names = append([]string{span.Name()}, names...)
+			span = byID[span.Parent().SpanID().String()]
+		}
+		return names
+	}
+	var got [][]string
+	var llmAttrs []attribute.KeyValue
+	for _, span := range spans {
+		got = append(got, path(span))
+		if span.Name() == "call_llm" {
+			llmAttrs = span.Attributes()
+		}
+	}
+	want := [][]string{
+		{"invocation", "invoke_agent weather_agent", "call_llm", "execute_tool get_weather"},
+		{"invocation", "invoke_agent weather_agent", "call_llm", "execute_tool (merged)"},
+		{"invocation", "invoke_agent weather_agent", "call_llm"},
+		{"invocation", "invoke_agent weather_agent", "call_llm"},
+		{"invocation", "invoke_agent weather_agent"},
+		{"invocation"},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("span paths mismatch (-want +got):\n%s", diff)
+	}
+
+	attrs := make(map[attribute.Key]attribute.Value)
+	for _, kv := range llmAttrs {
+		attrs[kv.Key] = kv.Value
+	}
+	for key, want := range map[attribute.Key]any{
+		"gen_ai.operation.name":          "chat",
+		"gen_ai.request.model":           "mock",
+		"gen_ai.agent.name":              "weather_agent",
+		"gen_ai.usage.input_tokens":      int64(12),
+		"gen_ai.usage.output_tokens":     int64(4),
+		"gen_ai.response.finish_reasons": []string{"STOP"},
+	} {
+		if got := attrs[key].AsInterface(); !cmp.Equal(got, want) {
+			t.Errorf("call_llm attribute %s = %v, want %v", key, got, want)
+		}
+	}
+}
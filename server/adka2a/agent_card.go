@@ -28,6 +28,22 @@ import (
 	"google.golang.org/adk/internal/llminternal"
 )
 
+// NewAgentCard returns the public card of the agent served over JSON-RPC at
+// the URL, with streaming, text input and output, and the skills of
+// [BuildAgentSkills].
+func NewAgentCard(agent agent.Agent, url string) *a2a.AgentCard {
+	return &a2a.AgentCard{
+		Name:               agent.Name(),
+		Description:        agent.Description(),
+		DefaultInputModes:  []string{"text/plain"},
+		DefaultOutputModes: []string{"text/plain"},
+		URL:                url,
+		PreferredTransport: a2a.TransportProtocolJSONRPC,
+		Skills:             BuildAgentSkills(agent),
+		Capabilities:       a2a.AgentCapabilities{Streaming: true},
+	}
+}
+
 // BuildAgentSkills attempts to create a list of [a2a.AgentSkill]s based on agent descriptions and types.
 // This information can be used in [a2a.AgentCard] to help clients understand agent capabilities.
 func BuildAgentSkills(agent agent.Agent) []a2a.AgentSkill {
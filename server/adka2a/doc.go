@@ -13,4 +13,8 @@
 // limitations under the License.
 
 // Package adka2a allows to expose ADK agents via A2A.
+//
+// [NewServer] returns an HTTP handler serving the agent card and the
+// JSON-RPC endpoint of an agent, which runs the messages of the A2A clients
+// through the [Executor].
 package adka2a
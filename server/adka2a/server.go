@@ -0,0 +1,83 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package adka2a
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/a2aproject/a2a-go/a2a"
+	"github.com/a2aproject/a2a-go/a2asrv"
+)
+
+// DefaultInvokePath is the default path of the JSON-RPC endpoint of the
+// servers, see ServerConfig.
+const DefaultInvokePath = "/invoke"
+
+// ServerConfig allows to configure the handler of NewServer.
+type ServerConfig struct {
+	ExecutorConfig
+
+	// URL is the public URL of the server, as seen by the clients. Required
+	// unless AgentCard is set.
+	URL string
+	// InvokePath is the path of the JSON-RPC endpoint. Defaults to
+	// DefaultInvokePath.
+	InvokePath string
+	// AgentCard is the public card of the agent. Defaults to NewAgentCard of
+	// the agent at the URL joined with the InvokePath.
+	AgentCard *a2a.AgentCard
+	// HandlerOptions are passed to [a2asrv.NewHandler].
+	HandlerOptions []a2asrv.RequestHandlerOption
+}
+
+// NewServer returns an HTTP handler exposing the agent of
+// ExecutorConfig.RunnerConfig to the A2A clients, e.g. remote agents of ADK
+// Python, Java or Go, see remoteagent.NewA2A. It serves the agent card at the
+// well-known path, see [a2asrv.WellKnownAgentCardPath], and the JSON-RPC
+// methods at the InvokePath: sending and streaming messages, over
+// server-sent events, and getting and canceling tasks. Each message runs the
+// agent through the [Executor], in the session of the A2A context of the
+// message.
+func NewServer(cfg ServerConfig) (http.Handler, error) {
+	rootAgent := cfg.RunnerConfig.Agent
+	if rootAgent == nil {
+		return nil, fmt.Errorf("root agent is required")
+	}
+	if cfg.InvokePath == "" {
+		cfg.InvokePath = DefaultInvokePath
+	}
+	card := cfg.AgentCard
+	if card == nil {
+		if cfg.URL == "" {
+			return nil, fmt.Errorf("server URL or agent card is required")
+		}
+		invokeURL, err := url.JoinPath(cfg.URL, cfg.InvokePath)
+		if err != nil {
+			return nil, fmt.Errorf("invalid server URL %q: %w", cfg.URL, err)
+		}
+		card = NewAgentCard(rootAgent, invokeURL)
+	}
+	if cfg.RunnerConfig.AppName == "" {
+		cfg.RunnerConfig.AppName = rootAgent.Name()
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle(a2asrv.WellKnownAgentCardPath, a2asrv.NewStaticAgentCardHandler(card))
+	handler := a2asrv.NewHandler(NewExecutor(cfg.ExecutorConfig), cfg.HandlerOptions...)
+	mux.Handle(cfg.InvokePath, a2asrv.NewJSONRPCHandler(handler))
+	return mux, nil
+}
@@ -0,0 +1,115 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package adka2a
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/a2aproject/a2a-go/a2a"
+	"github.com/a2aproject/a2a-go/a2aclient"
+	"github.com/a2aproject/a2a-go/a2aclient/agentcard"
+
+	"google.golang.org/adk/agent/llmagent"
+	"google.golang.org/adk/model/mockmodel"
+	"google.golang.org/adk/runner"
+	"google.golang.org/adk/session"
+)
+
+func TestNewServer(t *testing.T) {
+	ctx := t.Context()
+	a, err := llmagent.New(llmagent.Config{
+		Name:        "echo_agent",
+		Description: "Echoes the messages.",
+		Model:       mockmodel.New(mockmodel.Text("Hello!"), mockmodel.Text("Hello again!")),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := httptest.NewServer(nil)
+	defer srv.Close()
+	handler, err := NewServer(ServerConfig{
+		URL: srv.URL,
+		ExecutorConfig: ExecutorConfig{
+			RunnerConfig: runner.Config{Agent: a, SessionService: session.InMemoryService()},
+		},
+	})
+	if err != nil {
+		t.Fatalf("NewServer() error = %v", err)
+	}
+	srv.Config.Handler = handler
+
+	card, err := agentcard.DefaultResolver.Resolve(ctx, srv.URL)
+	if err != nil {
+		t.Fatalf("Resolve() error = %v", err)
+	}
+	if card.Name != "echo_agent" || card.Description != "Echoes the messages." || card.URL != srv.URL+DefaultInvokePath || !card.Capabilities.Streaming {
+		t.Errorf("got agent card %+v", card)
+	}
+	client, err := a2aclient.NewFromCard(ctx, card)
+	if err != nil {
+		t.Fatalf("NewFromCard() error = %v", err)
+	}
+
+	got, err := client.SendMessage(ctx, &a2a.MessageSendParams{
+		Message: a2a.NewMessage(a2a.MessageRoleUser, a2a.TextPart{Text: "Hi!"}),
+	})
+	if err != nil {
+		t.Fatalf("SendMessage() error = %v", err)
+	}
+	task, ok := got.(*a2a.Task)
+	if !ok || task.Status.State != a2a.TaskStateCompleted {
+		t.Fatalf("SendMessage() = %+v, want a completed task", got)
+	}
+	if text := task.Artifacts[0].Parts[0].(a2a.TextPart).Text; text != "Hello!" {
+		t.Errorf("task artifact text = %q, want %q", text, "Hello!")
+	}
+
+	msg := a2a.NewMessage(a2a.MessageRoleUser, a2a.TextPart{Text: "Hi again!"})
+	msg.ContextID = task.ContextID
+	var texts []string
+	var final a2a.TaskState
+	for ev, err := range client.SendStreamingMessage(ctx, &a2a.MessageSendParams{Message: msg}) {
+		if err != nil {
+			t.Fatalf("SendStreamingMessage() error = %v", err)
+		}
+		switch ev := ev.(type) {
+		case *a2a.TaskArtifactUpdateEvent:
+			for _, part := range ev.Artifact.Parts {
+				if text, ok := part.(a2a.TextPart); ok {
+					texts = append(texts, text.Text)
+				}
+			}
+		case *a2a.TaskStatusUpdateEvent:
+			final = ev.Status.State
+		}
+	}
+	if len(texts) != 1 || texts[0] != "Hello again!" || final != a2a.TaskStateCompleted {
+		t.Errorf("streamed texts %q with final state %q, want [Hello again!] and completed", texts, final)
+	}
+}
+
+func TestNewServer_Invalid(t *testing.T) {
+	if _, err := NewServer(ServerConfig{URL: "http://localhost"}); err == nil {
+		t.Errorf("NewServer() without agent succeeded, want error")
+	}
+	a, err := llmagent.New(llmagent.Config{Name: "agent", Model: mockmodel.New()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewServer(ServerConfig{ExecutorConfig: ExecutorConfig{RunnerConfig: runner.Config{Agent: a}}}); err == nil {
+		t.Errorf("NewServer() without URL succeeded, want error")
+	}
+}
@@ -0,0 +1,299 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package adkmcp allows to expose ADK tools via MCP, e.g. to desktop
+// assistants or other MCP clients. It is the reverse of package mcptoolset,
+// which imports the tools of an MCP server.
+package adkmcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/agent"
+	"google.golang.org/adk/artifact"
+	artifactinternal "google.golang.org/adk/internal/artifact"
+	icontext "google.golang.org/adk/internal/context"
+	"google.golang.org/adk/internal/sessioninternal"
+	"google.golang.org/adk/internal/toolinternal"
+	"google.golang.org/adk/internal/version"
+	"google.golang.org/adk/session"
+	"google.golang.org/adk/tool"
+	"google.golang.org/adk/tool/functiontool"
+)
+
+// Config is the configuration of an MCP server of ADK tools.
+type Config struct {
+	// Name is the name of the server reported to the clients, and the app
+	// name of the sessions of the tools. Defaults to "adk-mcp-server".
+	Name string
+	// Version is the version of the server reported to the clients. Defaults
+	// to the version of ADK.
+	Version string
+	// Tools are the tools served. They must be function tools, with a
+	// declaration and unique names; the Gemini built-in tools cannot be
+	// served.
+	Tools []tool.Tool
+	// SessionService optionally stores the sessions of the calls, one per MCP
+	// session, so that the state set by a call is seen by the next calls of
+	// the client. Defaults to an in-memory service.
+	SessionService session.Service
+	// ArtifactService optionally stores the artifacts saved by the tools.
+	// Defaults to an in-memory service.
+	ArtifactService artifact.Service
+	// UserID is the user of the sessions. Defaults to "mcp_user".
+	UserID string
+}
+
+// NewServer returns an MCP server offering the tools. The declaration of each
+// tool is its MCP definition, with its parameters translated to a JSON
+// schema, and the CallTool requests run the tool with a context of its own:
+// a new function call in the session of the MCP client.
+//
+// A result is returned as structured content, and as its JSON encoding in a
+// text content block for the clients ignoring structured content. The images
+// and audio of the tools returning tool.Content are returned as image and
+// audio blocks. An error is returned as a result marked as an error, with the
+// payload reported to the models, see tool.ToolError.
+func NewServer(cfg Config) (*mcp.Server, error) {
+	if cfg.Name == "" {
+		cfg.Name = "adk-mcp-server"
+	}
+	if cfg.Version == "" {
+		cfg.Version = version.Version
+	}
+	if cfg.SessionService == nil {
+		cfg.SessionService = session.InMemoryService()
+	}
+	if cfg.ArtifactService == nil {
+		cfg.ArtifactService = artifact.InMemoryService()
+	}
+	if cfg.UserID == "" {
+		cfg.UserID = "mcp_user"
+	}
+	if dups := tool.DuplicateNames(cfg.Tools); len(dups) > 0 {
+		return nil, fmt.Errorf("duplicate tool names: %s", strings.Join(dups, ", "))
+	}
+
+	s := &server{cfg: cfg, sessions: make(map[*mcp.ServerSession]string)}
+	mcpServer := mcp.NewServer(&mcp.Implementation{Name: cfg.Name, Version: cfg.Version}, nil)
+	for _, t := range cfg.Tools {
+		funcTool, ok := t.(toolinternal.FunctionTool)
+		if !ok || funcTool.Declaration() == nil {
+			return nil, fmt.Errorf("tool %q is not a function tool", t.Name())
+		}
+		def, err := mcpTool(t)
+		if err != nil {
+			return nil, err
+		}
+		mcpServer.AddTool(def, s.handler(funcTool))
+	}
+	return mcpServer, nil
+}
+
+// ServeStdio serves the tools over the standard input and output of the
+// process, as the MCP clients starting a server command expect, until the
+// client disconnects or ctx is done.
+func ServeStdio(ctx context.Context, cfg Config) error {
+	mcpServer, err := NewServer(cfg)
+	if err != nil {
+		return err
+	}
+	return mcpServer.Run(ctx, &mcp.StdioTransport{})
+}
+
+// NewSSEHandler returns an HTTP handler serving the tools over the SSE
+// transport of MCP.
+func NewSSEHandler(cfg Config) (http.Handler, error) {
+	mcpServer, err := NewServer(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return mcp.NewSSEHandler(func(*http.Request) *mcp.Server { return mcpServer }, nil), nil
+}
+
+// mcpTool returns the MCP definition of the tool. The parameters are
+// translated as for OpenAI, whose dialect is standard JSON schema.
+func mcpTool(t tool.Tool) (*mcp.Tool, error) {
+	encoded, err := functiontool.OpenAIDeclaration(t)
+	if err != nil {
+		return nil, err
+	}
+	var decl struct {
+		Function struct {
+			Name        string             `json:"name"`
+			Description string             `json:"description"`
+			Parameters  *jsonschema.Schema `json:"parameters"`
+		} `json:"function"`
+	}
+	if err := json.Unmarshal(encoded, &decl); err != nil {
+		return nil, fmt.Errorf("invalid parameters schema of tool %q: %w", t.Name(), err)
+	}
+	if decl.Function.Parameters.Type != "object" {
+		return nil, fmt.Errorf("parameters of tool %q are not an object schema", t.Name())
+	}
+	return &mcp.Tool{
+		Name:        decl.Function.Name,
+		Description: decl.Function.Description,
+		InputSchema: decl.Function.Parameters,
+	}, nil
+}
+
+type server struct {
+	cfg Config
+
+	mu sync.Mutex
+	// sessions are the IDs of the ADK sessions of the MCP sessions.
+	sessions map[*mcp.ServerSession]string
+}
+
+// handler returns the handler of the CallTool requests of the tool.
+func (s *server) handler(t toolinternal.FunctionTool) mcp.ToolHandler {
+	return func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		args := map[string]any{}
+		if len(req.Params.Arguments) > 0 {
+			if err := json.Unmarshal(req.Params.Arguments, &args); err != nil {
+				return errorResult(fmt.Errorf("invalid arguments of tool %q: %w", t.Name(), err))
+			}
+		}
+		sess, err := s.session(ctx, req.Session)
+		if err != nil {
+			return nil, err
+		}
+
+		artifacts := &artifactinternal.Artifacts{
+			Service:   s.cfg.ArtifactService,
+			AppName:   sess.AppName(),
+			UserID:    sess.UserID(),
+			SessionID: sess.ID(),
+		}
+		invCtx := icontext.NewInvocationContext(ctx, icontext.InvocationContextParams{
+			Artifacts: artifacts,
+			Session:   sessioninternal.NewMutableSession(s.cfg.SessionService, sess),
+		})
+		actions := &session.EventActions{}
+		result, err := t.Run(toolinternal.NewToolContext(invCtx, "", actions), args)
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		if err := s.saveActions(ctx, invCtx, sess, actions); err != nil {
+			return nil, err
+		}
+		if err != nil {
+			return errorResult(err)
+		}
+		return callResult(result)
+	}
+}
+
+// session returns the ADK session of the MCP session, created on its first
+// call.
+func (s *server) session(ctx context.Context, mcpSession *mcp.ServerSession) (session.Session, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if id, ok := s.sessions[mcpSession]; ok {
+		resp, err := s.cfg.SessionService.Get(ctx, &session.GetRequest{AppName: s.cfg.Name, UserID: s.cfg.UserID, SessionID: id})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get the session: %w", err)
+		}
+		return resp.Session, nil
+	}
+	resp, err := s.cfg.SessionService.Create(ctx, &session.CreateRequest{AppName: s.cfg.Name, UserID: s.cfg.UserID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the session: %w", err)
+	}
+	s.sessions[mcpSession] = resp.Session.ID()
+	if mcpSession != nil {
+		go func() {
+			_ = mcpSession.Wait()
+			s.mu.Lock()
+			defer s.mu.Unlock()
+			delete(s.sessions, mcpSession)
+		}()
+	}
+	return resp.Session, nil
+}
+
+// saveActions appends the actions of a call to the session, e.g. its state
+// changes, as the function response event of an agent would.
+func (s *server) saveActions(ctx context.Context, invCtx agent.InvocationContext, sess session.Session, actions *session.EventActions) error {
+	if len(actions.StateDelta) == 0 && len(actions.ArtifactDelta) == 0 {
+		return nil
+	}
+	ev := session.NewEvent(invCtx.InvocationID())
+	ev.Author = s.cfg.Name
+	ev.Actions = *actions
+	if err := s.cfg.SessionService.AppendEvent(ctx, sess, ev); err != nil {
+		return fmt.Errorf("failed to save the actions of the call: %w", err)
+	}
+	return nil
+}
+
+// callResult returns the MCP result of a successful call.
+func callResult(result map[string]any) (*mcp.CallToolResult, error) {
+	response, parts := toolinternal.SplitContentResult(result)
+	if response == nil {
+		response = map[string]any{}
+	}
+	text, err := tool.CanonicalJSON(response)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode the result: %w", err)
+	}
+	res := &mcp.CallToolResult{
+		Content:           []mcp.Content{&mcp.TextContent{Text: string(text)}},
+		StructuredContent: response,
+	}
+	for _, p := range parts {
+		if c := partContent(p); c != nil {
+			res.Content = append(res.Content, c)
+		}
+	}
+	return res, nil
+}
+
+// partContent returns the MCP content block of a part of a tool.Content, or
+// nil if MCP has no block for it.
+func partContent(p *genai.Part) mcp.Content {
+	switch {
+	case p.InlineData != nil && strings.HasPrefix(p.InlineData.MIMEType, "image/"):
+		return &mcp.ImageContent{Data: p.InlineData.Data, MIMEType: p.InlineData.MIMEType}
+	case p.InlineData != nil && strings.HasPrefix(p.InlineData.MIMEType, "audio/"):
+		return &mcp.AudioContent{Data: p.InlineData.Data, MIMEType: p.InlineData.MIMEType}
+	case p.FileData != nil:
+		return &mcp.ResourceLink{URI: p.FileData.FileURI, Name: p.FileData.DisplayName, MIMEType: p.FileData.MIMEType}
+	}
+	return nil
+}
+
+// errorResult returns the MCP result of a failed call.
+func errorResult(err error) (*mcp.CallToolResult, error) {
+	payload := toolinternal.ErrorResult(err)
+	text, encodeErr := tool.CanonicalJSON(payload)
+	if encodeErr != nil {
+		return nil, fmt.Errorf("failed to encode the error: %w", encodeErr)
+	}
+	return &mcp.CallToolResult{
+		Content:           []mcp.Content{&mcp.TextContent{Text: string(text)}},
+		StructuredContent: payload,
+		IsError:           true,
+	}, nil
+}
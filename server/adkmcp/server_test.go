@@ -0,0 +1,192 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package adkmcp_test
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/server/adkmcp"
+	"google.golang.org/adk/tool"
+	"google.golang.org/adk/tool/functiontool"
+	"google.golang.org/adk/tool/geminitool"
+)
+
+type addArgs struct {
+	Item  string `json:"item" jsonschema:"description=The item to add"`
+	Count *int   `json:"count"`
+}
+
+type cartResult struct {
+	Items []string `json:"items"`
+}
+
+// newCartTools returns a tool adding items to a cart kept in the session
+// state, and a tool drawing the cart.
+func newCartTools(t *testing.T) []tool.Tool {
+	t.Helper()
+	add, err := functiontool.New(functiontool.Config{
+		Name:        "add_to_cart",
+		Description: "Adds an item to the cart.",
+	}, func(ctx tool.Context, args addArgs) (cartResult, error) {
+		if args.Item == "" {
+			return cartResult{}, &tool.ToolError{Code: "invalid_item", Message: "the item is empty"}
+		}
+		var items []string
+		if v, err := ctx.State().Get("cart"); err == nil {
+			items = v.([]string)
+		}
+		items = append(items, args.Item)
+		if err := ctx.State().Set("cart", items); err != nil {
+			return cartResult{}, err
+		}
+		return cartResult{Items: items}, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	draw, err := functiontool.New(functiontool.Config{
+		Name:        "draw_cart",
+		Description: "Draws the cart.",
+	}, func(tool.Context, struct{}) (tool.Content, error) {
+		return tool.Content{Parts: []*genai.Part{
+			genai.NewPartFromText("a cart"),
+			genai.NewPartFromBytes([]byte("png"), "image/png"),
+		}}, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return []tool.Tool{add, draw}
+}
+
+// connect returns a client session of the server.
+func connect(t *testing.T, server *mcp.Server) *mcp.ClientSession {
+	t.Helper()
+	clientTransport, serverTransport := mcp.NewInMemoryTransports()
+	if _, err := server.Connect(t.Context(), serverTransport, nil); err != nil {
+		t.Fatal(err)
+	}
+	client := mcp.NewClient(&mcp.Implementation{Name: "client", Version: "v1.0.0"}, nil)
+	session, err := client.Connect(t.Context(), clientTransport, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { session.Close() })
+	return session
+}
+
+func TestNewServer(t *testing.T) {
+	server, err := adkmcp.NewServer(adkmcp.Config{Name: "shop", Tools: newCartTools(t)})
+	if err != nil {
+		t.Fatalf("NewServer() error = %v", err)
+	}
+	session := connect(t, server)
+
+	list, err := session.ListTools(t.Context(), nil)
+	if err != nil {
+		t.Fatalf("ListTools() error = %v", err)
+	}
+	var names []string
+	for _, tl := range list.Tools {
+		names = append(names, tl.Name)
+		if tl.Name != "add_to_cart" {
+			continue
+		}
+		if tl.Description != "Adds an item to the cart." {
+			t.Errorf("Description = %q, want the one of the tool", tl.Description)
+		}
+		schema := tl.InputSchema
+		if diff := cmp.Diff([]string{"item"}, schema.Required); diff != "" {
+			t.Errorf("Required mismatch (-want +got):\n%s", diff)
+		}
+		if got := schema.Properties["item"].Description; got != "The item to add" {
+			t.Errorf("item description = %q, want the one of the tag", got)
+		}
+	}
+	if diff := cmp.Diff([]string{"add_to_cart", "draw_cart"}, names); diff != "" {
+		t.Errorf("tool names mismatch (-want +got):\n%s", diff)
+	}
+
+	// The state of a client is kept across its calls.
+	call := func(session *mcp.ClientSession, name string, args map[string]any) *mcp.CallToolResult {
+		t.Helper()
+		res, err := session.CallTool(t.Context(), &mcp.CallToolParams{Name: name, Arguments: args})
+		if err != nil {
+			t.Fatalf("CallTool(%q) error = %v", name, err)
+		}
+		return res
+	}
+	call(session, "add_to_cart", map[string]any{"item": "apple"})
+	res := call(session, "add_to_cart", map[string]any{"item": "pear"})
+	if res.IsError {
+		t.Fatalf("CallTool() = %v, want a result", res.Content[0])
+	}
+	want := map[string]any{"items": []any{"apple", "pear"}}
+	if diff := cmp.Diff(want, res.StructuredContent); diff != "" {
+		t.Errorf("StructuredContent mismatch (-want +got):\n%s", diff)
+	}
+	if got := res.Content[0].(*mcp.TextContent).Text; got != `{"items":["apple","pear"]}` {
+		t.Errorf("text content = %s, want the JSON encoding of the result", got)
+	}
+	// Another client has its own state.
+	res = call(connect(t, server), "add_to_cart", map[string]any{"item": "plum"})
+	if diff := cmp.Diff(map[string]any{"items": []any{"plum"}}, res.StructuredContent); diff != "" {
+		t.Errorf("StructuredContent of another client mismatch (-want +got):\n%s", diff)
+	}
+
+	// The errors are results marked as errors.
+	res = call(session, "add_to_cart", map[string]any{"item": ""})
+	if !res.IsError {
+		t.Errorf("CallTool() of an empty item = %v, want an error", res.StructuredContent)
+	}
+	if got := res.Content[0].(*mcp.TextContent).Text; got != `{"error":{"code":"invalid_item","message":"the item is empty"}}` {
+		t.Errorf("error text content = %s, want the structured error", got)
+	}
+	res = call(session, "add_to_cart", map[string]any{"count": 1})
+	if !res.IsError {
+		t.Errorf("CallTool() without the required item = %v, want an error", res.StructuredContent)
+	}
+
+	// The images are image blocks.
+	res = call(session, "draw_cart", nil)
+	if len(res.Content) != 2 {
+		t.Fatalf("CallTool() content = %v, want text and image", res.Content)
+	}
+	image, ok := res.Content[1].(*mcp.ImageContent)
+	if !ok || image.MIMEType != "image/png" || string(image.Data) != "png" {
+		t.Errorf("content[1] = %v, want the image", res.Content[1])
+	}
+}
+
+func TestNewServer_InvalidTools(t *testing.T) {
+	tools := newCartTools(t)
+	for _, tc := range []struct {
+		name  string
+		tools []tool.Tool
+	}{
+		{name: "built-in tool", tools: []tool.Tool{tools[0], geminitool.GoogleSearch{}}},
+		{name: "duplicate names", tools: []tool.Tool{tools[0], tools[1], tools[0]}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := adkmcp.NewServer(adkmcp.Config{Tools: tc.tools}); err == nil {
+				t.Error("NewServer() succeeded, want error")
+			}
+		})
+	}
+}
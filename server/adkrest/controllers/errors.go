@@ -14,6 +14,12 @@
 
 package controllers
 
+import (
+	"net/http"
+
+	"google.golang.org/adk/runner"
+)
+
 type statusError struct {
 	Err  error
 	Code int
@@ -32,3 +38,16 @@ func (se statusError) Error() string {
 func (se statusError) Status() int {
 	return se.Code
 }
+
+// runErrorStatus returns the HTTP status of the error of a run: 429 for the
+// rate limited runs, 503 for the transient failures of the runner or of the
+// model, and 500 otherwise.
+func runErrorStatus(err error) int {
+	switch runner.ClassifyError(err).Code {
+	case runner.RateLimitErrorCode, runner.ModelRateLimitedErrorCode:
+		return http.StatusTooManyRequests
+	case runner.BusyErrorCode, runner.ClosedErrorCode, runner.ModelUnavailableErrorCode:
+		return http.StatusServiceUnavailable
+	}
+	return http.StatusInternalServerError
+}
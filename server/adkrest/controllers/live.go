@@ -0,0 +1,111 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gorilla/websocket"
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/agent"
+	"google.golang.org/adk/model"
+	"google.golang.org/adk/server/adkrest/internal/auth"
+	"google.golang.org/adk/server/adkrest/internal/models"
+)
+
+var liveUpgrader = websocket.Upgrader{}
+
+// RunLiveHandler runs an agent live, see runner.Runner.RunLive, over a
+// WebSocket connection. The app, the user and the existing session of the run
+// are the query parameters app_name, user_id and session_id; the optional
+// modalities parameters, e.g. AUDIO, are the response modalities of the
+// model.
+//
+// The client sends models.LiveRequest JSON messages: the messages, the audio
+// chunks and the tool confirmations of the user. The events of the run, the
+// partial ones included, are sent back as JSON messages, and its errors as
+// {"error": "..."} messages. The run ends, and the connection is closed, once
+// the client sends a close request or closes the connection.
+func (c *RuntimeAPIController) RunLiveHandler(rw http.ResponseWriter, req *http.Request) error {
+	query := req.URL.Query()
+	appName, userID, sessionID := query.Get("app_name"), query.Get("user_id"), query.Get("session_id")
+	if appName == "" || userID == "" || sessionID == "" {
+		return newStatusError(errors.New("app_name, user_id and session_id are required"), http.StatusBadRequest)
+	}
+	if err := auth.CheckUser(req.Context(), userID); err != nil {
+		return newStatusError(err, http.StatusForbidden)
+	}
+	if err := c.validateSessionExists(req.Context(), appName, userID, sessionID); err != nil {
+		return err
+	}
+	r, _, err := c.getRunner(models.RunAgentRequest{AppName: appName})
+	if err != nil {
+		return err
+	}
+	var cfg agent.RunConfig
+	for _, modality := range query["modalities"] {
+		cfg.ResponseModalities = append(cfg.ResponseModalities, genai.Modality(modality))
+	}
+
+	conn, err := liveUpgrader.Upgrade(rw, req, nil)
+	if err != nil {
+		// The upgrader already replied with the error.
+		return nil
+	}
+	defer conn.Close()
+
+	queue := agent.NewLiveRequestQueue()
+	defer queue.Close()
+	go readLiveRequests(conn, queue)
+
+	for event, err := range r.RunLive(requestContext(req), userID, sessionID, queue, cfg) {
+		var msg any
+		if err != nil {
+			msg = map[string]string{"error": fmt.Sprintf("failed to run agent: %v", err)}
+		} else {
+			msg = models.FromSessionEvent(*event)
+		}
+		if err := conn.WriteJSON(msg); err != nil {
+			// The connection is broken: stop the run.
+			return nil
+		}
+	}
+	_ = conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""))
+	return nil
+}
+
+// readLiveRequests sends the requests of the client on the queue, until the
+// client closes the run or the connection.
+func readLiveRequests(conn *websocket.Conn, queue *agent.LiveRequestQueue) {
+	defer queue.Close()
+	for {
+		var req models.LiveRequest
+		if err := conn.ReadJSON(&req); err != nil || req.Close {
+			return
+		}
+		err := queue.Send(&model.LiveRequest{
+			Content:       req.Content,
+			Blob:          req.Blob,
+			ActivityStart: req.ActivityStart,
+			ActivityEnd:   req.ActivityEnd,
+		})
+		if err != nil {
+			return
+		}
+	}
+}
@@ -23,22 +23,33 @@ import (
 
 	"google.golang.org/adk/agent"
 	"google.golang.org/adk/artifact"
+	"google.golang.org/adk/memory"
+	"google.golang.org/adk/plugin"
 	"google.golang.org/adk/runner"
+	"google.golang.org/adk/server/adkrest/internal/auth"
 	"google.golang.org/adk/server/adkrest/internal/models"
 	"google.golang.org/adk/session"
 )
 
+// CorrelationIDHeader is the HTTP request header used to propagate the
+// correlation ID of an agent run. If it is absent, the runner generates one.
+const CorrelationIDHeader = "X-Correlation-ID"
+
 // RuntimeAPIController is the controller for the Runtime API.
 type RuntimeAPIController struct {
 	sseTimeout      time.Duration
 	sessionService  session.Service
 	artifactService artifact.Service
+	memoryService   memory.Service
 	agentLoader     agent.Loader
+	plugins         []*plugin.Plugin
 }
 
-// NewRuntimeAPIController creates the controller for the Runtime API.
-func NewRuntimeAPIController(sessionService session.Service, agentLoader agent.Loader, artifactService artifact.Service, sseTimeout time.Duration) *RuntimeAPIController {
-	return &RuntimeAPIController{sessionService: sessionService, agentLoader: agentLoader, artifactService: artifactService, sseTimeout: sseTimeout}
+// NewRuntimeAPIController creates the controller for the Runtime API. The
+// runners of the agents use the services and the plugins; the artifact and
+// memory services are optional.
+func NewRuntimeAPIController(sessionService session.Service, agentLoader agent.Loader, artifactService artifact.Service, memoryService memory.Service, plugins []*plugin.Plugin, sseTimeout time.Duration) *RuntimeAPIController {
+	return &RuntimeAPIController{sessionService: sessionService, agentLoader: agentLoader, artifactService: artifactService, memoryService: memoryService, plugins: plugins, sseTimeout: sseTimeout}
 }
 
 // RunAgent executes a non-streaming agent run for a given session and message.
@@ -47,7 +58,7 @@ func (c *RuntimeAPIController) RunHandler(rw http.ResponseWriter, req *http.Requ
 	if err != nil {
 		return err
 	}
-	sessionEvents, err := c.runAgent(req.Context(), runAgentRequest)
+	sessionEvents, err := c.runAgent(requestContext(req), runAgentRequest)
 	if err != nil {
 		return err
 	}
@@ -76,7 +87,7 @@ func (c *RuntimeAPIController) runAgent(ctx context.Context, runAgentRequest mod
 	var events []*session.Event
 	for event, err := range resp {
 		if err != nil {
-			return nil, newStatusError(fmt.Errorf("failed to run agent: %w", err), http.StatusInternalServerError)
+			return nil, newStatusError(fmt.Errorf("failed to run agent: %w", err), runErrorStatus(err))
 		}
 		events = append(events, event)
 	}
@@ -112,23 +123,22 @@ func (c *RuntimeAPIController) RunSSEHandler(rw http.ResponseWriter, req *http.R
 		return err
 	}
 
-	resp := r.Run(req.Context(), runAgentRequest.UserId, runAgentRequest.SessionId, &runAgentRequest.NewMessage, *rCfg)
+	resp := r.Run(requestContext(req), runAgentRequest.UserId, runAgentRequest.SessionId, &runAgentRequest.NewMessage, *rCfg)
 
 	rw.WriteHeader(http.StatusOK)
 	for event, err := range resp {
 		if err != nil {
-			_, err := fmt.Fprintf(rw, "Error while running agent: %v\n", err)
-			if err != nil {
-				return newStatusError(fmt.Errorf("failed to write response: %w", err), http.StatusInternalServerError)
-			}
-			err = rc.Flush()
-			if err != nil {
-				return newStatusError(fmt.Errorf("failed to flush: %w", err), http.StatusInternalServerError)
+			// The status is already sent: the error is streamed as an event
+			// with its classification, e.g.
+			// data: {"error": "...", "code": "MODEL_RATE_LIMITED", "retryable": true}.
+			info := runner.ClassifyError(err)
+			data := map[string]any{"error": fmt.Sprintf("failed to run agent: %v", err), "code": info.Code, "retryable": info.Retryable}
+			if err := flashData(rc, rw, data); err != nil {
+				return err
 			}
-
 			continue
 		}
-		err := flashEvent(rc, rw, *event)
+		err := flashData(rc, rw, models.FromSessionEvent(*event))
 		if err != nil {
 			return err
 		}
@@ -136,12 +146,13 @@ func (c *RuntimeAPIController) RunSSEHandler(rw http.ResponseWriter, req *http.R
 	return nil
 }
 
-func flashEvent(rc *http.ResponseController, rw http.ResponseWriter, event session.Event) error {
+// flashData writes the data as a server-sent event, and flushes it.
+func flashData(rc *http.ResponseController, rw http.ResponseWriter, data any) error {
 	_, err := fmt.Fprintf(rw, "data: ")
 	if err != nil {
 		return newStatusError(fmt.Errorf("failed to write response: %w", err), http.StatusInternalServerError)
 	}
-	err = json.NewEncoder(rw).Encode(models.FromSessionEvent(event))
+	err = json.NewEncoder(rw).Encode(data)
 	if err != nil {
 		return newStatusError(fmt.Errorf("failed to encode response: %w", err), http.StatusInternalServerError)
 	}
@@ -179,6 +190,8 @@ func (c *RuntimeAPIController) getRunner(req models.RunAgentRequest) (*runner.Ru
 		Agent:           curAgent,
 		SessionService:  c.sessionService,
 		ArtifactService: c.artifactService,
+		MemoryService:   c.memoryService,
+		Plugins:         c.plugins,
 	},
 	)
 	if err != nil {
@@ -194,15 +207,30 @@ func (c *RuntimeAPIController) getRunner(req models.RunAgentRequest) (*runner.Ru
 	}, nil
 }
 
+// requestContext returns the request context carrying the correlation ID from
+// the request headers, if any.
+func requestContext(req *http.Request) context.Context {
+	ctx := req.Context()
+	if correlationID := req.Header.Get(CorrelationIDHeader); correlationID != "" {
+		ctx = agent.WithCorrelationID(ctx, correlationID)
+	}
+	return ctx
+}
+
 func decodeRequestBody(req *http.Request) (decodedReq models.RunAgentRequest, err error) {
 	var runAgentRequest models.RunAgentRequest
 	defer func() {
-		err = req.Body.Close()
+		if closeErr := req.Body.Close(); err == nil {
+			err = closeErr
+		}
 	}()
 	d := json.NewDecoder(req.Body)
 	d.DisallowUnknownFields()
 	if err := d.Decode(&runAgentRequest); err != nil {
 		return runAgentRequest, newStatusError(fmt.Errorf("failed to decode request: %w", err), http.StatusBadRequest)
 	}
+	if err := auth.CheckUser(req.Context(), runAgentRequest.UserId); err != nil {
+		return runAgentRequest, newStatusError(err, http.StatusForbidden)
+	}
 	return runAgentRequest, nil
 }
@@ -12,24 +12,118 @@
 // See the License for the specific language governing permissions and
 // limitations under the License.
 
+// Package adkrest provides the ADK REST API: an http.Handler serving a set of
+// agents, which creates and inspects their sessions and artifacts, and runs
+// the agents, streaming their events as server-sent events. It is the API
+// used by the ADK web UI, e.g. through the "api" web sublauncher.
+//
+// The main endpoints are, relative to the path where the handler is mounted:
+//
+//	GET    /list-apps
+//	POST   /apps/{app_name}/users/{user_id}/sessions[/{session_id}]
+//	GET    /apps/{app_name}/users/{user_id}/sessions[/{session_id}]
+//	DELETE /apps/{app_name}/users/{user_id}/sessions/{session_id}
+//	POST   /run
+//	POST   /run_sse
+//	GET    /run_live?app_name=...&user_id=...&session_id=...
+//
+// The run endpoints take a JSON body with the "appName", "userId",
+// "sessionId" and "newMessage" of the run, and the session must exist. /run
+// returns all the events of the run at once, while /run_sse streams them,
+// each one as a "data:" line with the JSON event; errors of the run are
+// streamed as {"error": "..."} data.
+//
+// /run_live upgrades to a WebSocket connection carrying a live run, see
+// runner.Runner.RunLive, in both directions: the client sends JSON messages
+// with the "content" (e.g. text or tool confirmations) or the audio "blob" of
+// the user, or "close": true to end the run, and the partial and complete
+// events of the run are sent back as they come.
 package adkrest
 
 import (
+	"errors"
 	"net/http"
 	"time"
 
 	"github.com/gorilla/mux"
 	sdktrace "go.opentelemetry.io/otel/sdk/trace"
 
+	"google.golang.org/adk/agent"
+	"google.golang.org/adk/artifact"
 	"google.golang.org/adk/cmd/launcher"
 	"google.golang.org/adk/internal/telemetry"
+	"google.golang.org/adk/memory"
+	"google.golang.org/adk/plugin"
 	"google.golang.org/adk/server/adkrest/controllers"
+	"google.golang.org/adk/server/adkrest/internal/auth"
 	"google.golang.org/adk/server/adkrest/internal/routers"
 	"google.golang.org/adk/server/adkrest/internal/services"
+	"google.golang.org/adk/session"
 )
 
+// DefaultSSEWriteTimeout is the default write timeout of the responses of the
+// /run_sse endpoint.
+const DefaultSSEWriteTimeout = 120 * time.Second
+
+// Config is the configuration of the ADK REST API.
+type Config struct {
+	// AgentLoader loads the agents served, one per app name. Required.
+	AgentLoader agent.Loader
+	// SessionService stores the sessions. Defaults to an in-memory service.
+	SessionService session.Service
+	// ArtifactService optionally stores the artifacts of the sessions.
+	ArtifactService artifact.Service
+	// MemoryService is the optional memory of the agents.
+	MemoryService memory.Service
+	// Plugins are the plugins of the runners of the agents.
+	Plugins []*plugin.Plugin
+	// SSEWriteTimeout is the write timeout of the responses of the /run_sse
+	// endpoint. Defaults to DefaultSSEWriteTimeout.
+	SSEWriteTimeout time.Duration
+	// Authenticator optionally authenticates the requests, see Authenticator.
+	// By default, the requests are not authenticated: any client may act on
+	// behalf of any user.
+	Authenticator Authenticator
+}
+
+// Authenticator authenticates a request of the REST API, e.g. from a bearer
+// token, and returns the ID of the user making it. The request fails with 401
+// Unauthorized if it returns an error.
+//
+// The authenticated user may only act on behalf of itself: the requests
+// with another user ID, in the path or in the body of a run, fail with 403
+// Forbidden, as do the debug trace endpoints, which are not scoped to a user.
+type Authenticator func(r *http.Request) (userID string, err error)
+
+// New creates an http.Handler for the ADK REST API.
+func New(cfg Config) (http.Handler, error) {
+	if cfg.AgentLoader == nil {
+		return nil, errors.New("agent loader is required")
+	}
+	if err := plugin.Validate(cfg.Plugins); err != nil {
+		return nil, err
+	}
+	if cfg.SessionService == nil {
+		cfg.SessionService = session.InMemoryService()
+	}
+	if cfg.SSEWriteTimeout == 0 {
+		cfg.SSEWriteTimeout = DefaultSSEWriteTimeout
+	}
+	return newHandler(cfg), nil
+}
+
 // NewHandler creates and returns an http.Handler for the ADK REST API.
 func NewHandler(config *launcher.Config, sseWriteTimeout time.Duration) http.Handler {
+	return newHandler(Config{
+		AgentLoader:     config.AgentLoader,
+		SessionService:  config.SessionService,
+		ArtifactService: config.ArtifactService,
+		MemoryService:   config.MemoryService,
+		SSEWriteTimeout: sseWriteTimeout,
+	})
+}
+
+func newHandler(cfg Config) http.Handler {
 	adkExporter := services.NewAPIServerSpanExporter()
 	telemetry.AddSpanProcessor(sdktrace.NewSimpleSpanProcessor(adkExporter))
 
@@ -37,13 +131,16 @@ func NewHandler(config *launcher.Config, sseWriteTimeout time.Duration) http.Han
 	// TODO: Allow taking a prefix to allow customizing the path
 	// where the ADK REST API will be served.
 	setupRouter(router,
-		routers.NewSessionsAPIRouter(controllers.NewSessionsAPIController(config.SessionService)),
-		routers.NewRuntimeAPIRouter(controllers.NewRuntimeAPIController(config.SessionService, config.AgentLoader, config.ArtifactService, sseWriteTimeout)),
-		routers.NewAppsAPIRouter(controllers.NewAppsAPIController(config.AgentLoader)),
-		routers.NewDebugAPIRouter(controllers.NewDebugAPIController(config.SessionService, config.AgentLoader, adkExporter)),
-		routers.NewArtifactsAPIRouter(controllers.NewArtifactsAPIController(config.ArtifactService)),
+		routers.NewSessionsAPIRouter(controllers.NewSessionsAPIController(cfg.SessionService)),
+		routers.NewRuntimeAPIRouter(controllers.NewRuntimeAPIController(cfg.SessionService, cfg.AgentLoader, cfg.ArtifactService, cfg.MemoryService, cfg.Plugins, cfg.SSEWriteTimeout)),
+		routers.NewAppsAPIRouter(controllers.NewAppsAPIController(cfg.AgentLoader)),
+		routers.NewDebugAPIRouter(controllers.NewDebugAPIController(cfg.SessionService, cfg.AgentLoader, adkExporter)),
+		routers.NewArtifactsAPIRouter(controllers.NewArtifactsAPIController(cfg.ArtifactService)),
 		&routers.EvalAPIRouter{},
 	)
+	if cfg.Authenticator != nil {
+		router.Use(authenticate(cfg.Authenticator))
+	}
 	return router
 }
 
@@ -51,3 +148,34 @@ func setupRouter(router *mux.Router, subrouters ...routers.Router) *mux.Router {
 	routers.SetupSubRouters(router, subrouters...)
 	return router
 }
+
+// authenticate returns the middleware authenticating the requests, and
+// checking the user ID of their path, if any. The user ID of the body of the
+// runs is checked by the controller.
+func authenticate(authenticator Authenticator) mux.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			if req.Method == http.MethodOptions {
+				next.ServeHTTP(rw, req)
+				return
+			}
+			userID, err := authenticator(req)
+			if err != nil {
+				http.Error(rw, err.Error(), http.StatusUnauthorized)
+				return
+			}
+			ctx := auth.WithUserID(req.Context(), userID)
+			if pathUserID, ok := mux.Vars(req)["user_id"]; ok {
+				if err := auth.CheckUser(ctx, pathUserID); err != nil {
+					http.Error(rw, err.Error(), http.StatusForbidden)
+					return
+				}
+			}
+			if route := mux.CurrentRoute(req); route != nil && (route.GetName() == "GetTraceDict" || route.GetName() == "GetSessionTrace") {
+				http.Error(rw, auth.ErrForbidden.Error(), http.StatusForbidden)
+				return
+			}
+			next.ServeHTTP(rw, req.WithContext(ctx))
+		})
+	}
+}
@@ -0,0 +1,272 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package adkrest_test
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"iter"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/gorilla/websocket"
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/agent"
+	"google.golang.org/adk/agent/llmagent"
+	"google.golang.org/adk/model"
+	"google.golang.org/adk/model/mockmodel"
+	"google.golang.org/adk/server/adkrest"
+)
+
+func newServer(t *testing.T, authenticator adkrest.Authenticator, texts ...string) *httptest.Server {
+	t.Helper()
+	var resps []*model.LLMResponse
+	for _, text := range texts {
+		resps = append(resps, mockmodel.Text(text))
+	}
+	a, err := llmagent.New(llmagent.Config{Name: "hello_agent", Model: mockmodel.New(resps...)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	handler, err := adkrest.New(adkrest.Config{AgentLoader: agent.NewSingleLoader(a), Authenticator: authenticator})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func do(t *testing.T, method, url, token, body string) *http.Response {
+	t.Helper()
+	req, err := http.NewRequestWithContext(t.Context(), method, url, strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("%s %s error = %v", method, url, err)
+	}
+	t.Cleanup(func() { resp.Body.Close() })
+	return resp
+}
+
+const runBody = `{"appName": "hello_agent", "userId": "alice", "sessionId": "s1", "newMessage": {"role": "user", "parts": [{"text": "Hi!"}]}}`
+
+func TestNew_RunSSE(t *testing.T) {
+	srv := newServer(t, nil, "Hello!", "Hello again!")
+
+	if resp := do(t, http.MethodPost, srv.URL+"/apps/hello_agent/users/alice/sessions/s1", "", ""); resp.StatusCode != http.StatusOK {
+		t.Fatalf("create session status = %d, want 200", resp.StatusCode)
+	}
+
+	resp := do(t, http.MethodPost, srv.URL+"/run_sse", "", runBody)
+	if resp.StatusCode != http.StatusOK || resp.Header.Get("Content-Type") != "text/event-stream" {
+		t.Fatalf("run_sse status = %d, content type = %q", resp.StatusCode, resp.Header.Get("Content-Type"))
+	}
+	var got []string
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		data, ok := strings.CutPrefix(scanner.Text(), "data: ")
+		if !ok {
+			continue
+		}
+		var event struct {
+			Author  string         `json:"author"`
+			Content *genai.Content `json:"content"`
+		}
+		if err := json.Unmarshal([]byte(data), &event); err != nil {
+			t.Fatalf("json.Unmarshal(%q) error = %v", data, err)
+		}
+		got = append(got, event.Author+": "+event.Content.Parts[0].Text)
+	}
+	if diff := cmp.Diff([]string{"hello_agent: Hello!"}, got); diff != "" {
+		t.Errorf("run_sse events mismatch (-want +got):\n%s", diff)
+	}
+
+	resp = do(t, http.MethodPost, srv.URL+"/run", "", runBody)
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != http.StatusOK || !strings.Contains(string(body), "Hello again!") {
+		t.Errorf("run = %d %s, want the events of the run", resp.StatusCode, body)
+	}
+}
+
+func TestNew_RunUnknownSession(t *testing.T) {
+	srv := newServer(t, nil)
+	if resp := do(t, http.MethodPost, srv.URL+"/run", "", runBody); resp.StatusCode != http.StatusNotFound {
+		t.Errorf("run status = %d, want 404", resp.StatusCode)
+	}
+}
+
+func TestNew_Authenticator(t *testing.T) {
+	srv := newServer(t, func(r *http.Request) (string, error) {
+		userID, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok {
+			return "", errors.New("missing bearer token")
+		}
+		return userID, nil
+	}, "Hello!")
+
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		token      string
+		body       string
+		wantStatus int
+	}{
+		{"unauthenticated", http.MethodGet, "/list-apps", "", "", http.StatusUnauthorized},
+		{"authenticated", http.MethodGet, "/list-apps", "alice", "", http.StatusOK},
+		{"own session", http.MethodPost, "/apps/hello_agent/users/alice/sessions/s1", "alice", "", http.StatusOK},
+		{"session of another user", http.MethodGet, "/apps/hello_agent/users/alice/sessions/s1", "bob", "", http.StatusForbidden},
+		{"run as another user", http.MethodPost, "/run", "bob", runBody, http.StatusForbidden},
+		{"debug trace", http.MethodGet, "/debug/trace/e1", "alice", "", http.StatusForbidden},
+		{"own run", http.MethodPost, "/run", "alice", runBody, http.StatusOK},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			resp := do(t, tc.method, srv.URL+tc.path, tc.token, tc.body)
+			if resp.StatusCode != tc.wantStatus {
+				body, _ := io.ReadAll(resp.Body)
+				t.Errorf("%s %s status = %d (%s), want %d", tc.method, tc.path, resp.StatusCode, body, tc.wantStatus)
+			}
+		})
+	}
+}
+
+func TestNew_NoAgentLoader(t *testing.T) {
+	if _, err := adkrest.New(adkrest.Config{}); err == nil {
+		t.Error("New() error = nil, want an error")
+	}
+}
+
+// echoLiveModel echoes the text messages over live connections.
+type echoLiveModel struct {
+	*mockmodel.Model
+}
+
+func (m echoLiveModel) ConnectLive(ctx context.Context, req *model.LLMRequest) (model.LiveConnection, error) {
+	return &echoLiveConnection{responses: make(chan *model.LLMResponse, 10), closed: make(chan struct{})}, nil
+}
+
+type echoLiveConnection struct {
+	responses chan *model.LLMResponse
+	closed    chan struct{}
+	closeOnce sync.Once
+}
+
+func (c *echoLiveConnection) Send(req *model.LiveRequest) error {
+	if req.Content != nil {
+		c.responses <- &model.LLMResponse{Content: genai.NewContentFromText(req.Content.Parts[0].Text, genai.RoleModel)}
+		c.responses <- &model.LLMResponse{TurnComplete: true}
+	}
+	return nil
+}
+
+func (c *echoLiveConnection) Receive() iter.Seq2[*model.LLMResponse, error] {
+	return func(yield func(*model.LLMResponse, error) bool) {
+		for {
+			select {
+			case resp := <-c.responses:
+				if !yield(resp, nil) {
+					return
+				}
+			case <-c.closed:
+				return
+			}
+		}
+	}
+}
+
+func (c *echoLiveConnection) Close() error {
+	c.closeOnce.Do(func() { close(c.closed) })
+	return nil
+}
+
+func TestNew_RunLive(t *testing.T) {
+	a, err := llmagent.New(llmagent.Config{Name: "echo_agent", Model: echoLiveModel{mockmodel.New()}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	handler, err := adkrest.New(adkrest.Config{AgentLoader: agent.NewSingleLoader(a)})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	srv := httptest.NewServer(handler)
+	defer srv.Close()
+	if resp := do(t, http.MethodPost, srv.URL+"/apps/echo_agent/users/alice/sessions/s1", "", ""); resp.StatusCode != http.StatusOK {
+		t.Fatalf("create session status = %d, want 200", resp.StatusCode)
+	}
+
+	wsURL := "ws" + strings.TrimPrefix(srv.URL, "http") + "/run_live?app_name=echo_agent&user_id=alice&session_id=s1"
+	conn, _, err := websocket.DefaultDialer.DialContext(t.Context(), wsURL, nil)
+	if err != nil {
+		t.Fatalf("Dial() error = %v", err)
+	}
+	defer conn.Close()
+
+	var got []string
+	for _, text := range []string{"Hi!", "Bye!"} {
+		if err := conn.WriteJSON(map[string]any{"content": genai.NewContentFromText(text, genai.RoleUser)}); err != nil {
+			t.Fatalf("WriteJSON() error = %v", err)
+		}
+		for {
+			var event struct {
+				Author       string         `json:"author"`
+				Content      *genai.Content `json:"content"`
+				TurnComplete bool           `json:"turnComplete"`
+			}
+			if err := conn.ReadJSON(&event); err != nil {
+				t.Fatalf("ReadJSON() error = %v", err)
+			}
+			if event.TurnComplete {
+				break
+			}
+			got = append(got, event.Author+": "+event.Content.Parts[0].Text)
+		}
+	}
+	if err := conn.WriteJSON(map[string]any{"close": true}); err != nil {
+		t.Fatalf("WriteJSON() error = %v", err)
+	}
+	if _, _, err := conn.ReadMessage(); !websocket.IsCloseError(err, websocket.CloseNormalClosure) {
+		t.Errorf("ReadMessage() after close error = %v, want a normal closure", err)
+	}
+	if diff := cmp.Diff([]string{"echo_agent: Hi!", "echo_agent: Bye!"}, got); diff != "" {
+		t.Errorf("live events mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestNew_RunLiveUnknownSession(t *testing.T) {
+	srv := newServer(t, nil)
+	wsURL := "ws" + strings.TrimPrefix(srv.URL, "http") + "/run_live?app_name=hello_agent&user_id=alice&session_id=s1"
+	_, resp, err := websocket.DefaultDialer.DialContext(t.Context(), wsURL, nil)
+	if err == nil || resp.StatusCode != http.StatusNotFound {
+		t.Errorf("Dial() = %v, want a 404 error", err)
+	}
+}
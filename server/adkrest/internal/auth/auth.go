@@ -0,0 +1,44 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package auth carries the user authenticated by the ADK REST API in the
+// request context.
+package auth
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrForbidden is returned by CheckUser if the request is made on behalf of
+// another user than the authenticated one.
+var ErrForbidden = errors.New("the authenticated user may not act on behalf of this user")
+
+type userIDKey struct{}
+
+// WithUserID returns a context carrying the ID of the authenticated user.
+func WithUserID(ctx context.Context, userID string) context.Context {
+	return context.WithValue(ctx, userIDKey{}, userID)
+}
+
+// CheckUser checks that the request with the context may act on behalf of the
+// user: either no user is authenticated, i.e. the API does not authenticate
+// the requests, or it is the authenticated user.
+func CheckUser(ctx context.Context, userID string) error {
+	authenticated, ok := ctx.Value(userIDKey{}).(string)
+	if !ok || authenticated == userID {
+		return nil
+	}
+	return ErrForbidden
+}
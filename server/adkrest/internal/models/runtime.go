@@ -50,3 +50,17 @@ func (req RunAgentRequest) AssertRunAgentRequestRequired() error {
 
 	return nil
 }
+
+// LiveRequest is a message of the client of a live run, see
+// RuntimeAPIController.RunLiveHandler. At most one of its fields is set.
+type LiveRequest struct {
+	// Content is a turn of the conversation, e.g. a text message or the
+	// responses of function calls, such as tool confirmations.
+	Content *genai.Content `json:"content,omitempty"`
+	// Blob is a chunk of realtime input, e.g. audio.
+	Blob          *genai.Blob `json:"blob,omitempty"`
+	ActivityStart bool        `json:"activityStart,omitempty"`
+	ActivityEnd   bool        `json:"activityEnd,omitempty"`
+	// Close ends the live run.
+	Close bool `json:"close,omitempty"`
+}
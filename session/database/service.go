@@ -19,15 +19,20 @@ import (
 	"errors"
 	"fmt"
 	"maps"
-	"strings"
 	"time"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
 
+	"google.golang.org/adk/internal/sessionutils"
 	"google.golang.org/adk/session"
 )
 
+// ErrStaleSession is returned by AppendEvent when the session was updated in
+// the database since it was read, e.g. by a concurrent AppendEvent. The
+// session must be read again with Get before appending to it.
+var ErrStaleSession = errors.New("stale session")
+
 // databaseService is an database implementation of sessionService.Service.
 type databaseService struct {
 	db *gorm.DB
@@ -104,7 +109,7 @@ func (s *databaseService) Create(ctx context.Context, req *session.CreateRequest
 			return fmt.Errorf("error on create session: %w", err)
 		}
 
-		appDelta, userDelta, sessionState := extractStateDeltas(req.State)
+		appDelta, userDelta, sessionState := sessionutils.ExtractStateDeltas(req.State)
 
 		// apply state delta
 		if len(appDelta) > 0 {
@@ -125,7 +130,7 @@ func (s *databaseService) Create(ctx context.Context, req *session.CreateRequest
 			return fmt.Errorf("error creating session on database: %w", err)
 		}
 
-		val.state = mergeStates(storageApp.State, storageUser.State, sessionState)
+		val.state = sessionutils.MergeStates(storageApp.State, storageUser.State, sessionState)
 		val.updatedAt = createdSession.UpdateTime
 		return nil
 	})
@@ -154,8 +159,10 @@ func (s *databaseService) Get(ctx context.Context, req *session.GetRequest) (*se
 			ID:      sessionID,
 		}).
 		First(&foundSession).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("session %q: %w", sessionID, session.ErrSessionNotFound)
+	}
 	if err != nil {
-		// For any error including ErrRecordNotFound, return it as a system error.
 		return nil, fmt.Errorf("database error while fetching session: %w", err)
 	}
 
@@ -195,7 +202,7 @@ func (s *databaseService) Get(ctx context.Context, req *session.GetRequest) (*se
 	}
 
 	responseSession, err := createSessionFromStorageSession(&foundSession)
-	responseSession.state = mergeStates(storageApp.State, storageUser.State, responseSession.state)
+	responseSession.state = sessionutils.MergeStates(storageApp.State, storageUser.State, responseSession.state)
 	if err != nil {
 		return nil, fmt.Errorf("failed to map storage object: %w", err)
 	}
@@ -283,7 +290,7 @@ func (s *databaseService) List(ctx context.Context, req *session.ListRequest) (*
 		if !ok {
 			userState = &storageUserState{AppName: appName, UserID: userID, State: make(map[string]any)}
 		}
-		sess.state = mergeStates(storageApp.State, userState.State, sess.state)
+		sess.state = sessionutils.MergeStates(storageApp.State, userState.State, sess.state)
 		responseSessions = append(responseSessions, sess)
 	}
 
@@ -331,7 +338,9 @@ func (s *databaseService) AppendEvent(ctx context.Context, curSession session.Se
 	// Truncate timestamp to microsecond precision to match database precision and prevent rounding errors.
 	event.Timestamp = time.UnixMicro(event.Timestamp.UnixMicro())
 
-	// Trim temp state before persisting
+	// Trim temp state before persisting: it is only visible in the session
+	// for the rest of the invocation.
+	temp := sessionutils.TempState(event.Actions.StateDelta)
 	event = trimTempDeltaState(event)
 
 	sess, ok := curSession.(*localSession)
@@ -346,7 +355,7 @@ func (s *databaseService) AppendEvent(ctx context.Context, curSession session.Se
 	}
 
 	// append it to session
-	return sess.appendEvent(event)
+	return sess.appendEvent(event, temp)
 }
 
 // applyEvent fetches the session, validates it, applies state changes from an
@@ -368,12 +377,13 @@ func (s *databaseService) applyEvent(ctx context.Context, session *localSession,
 		// Ensure the session object is not stale.
 		// We use UnixMicro() for microsecond-level precision, matching the Python code.
 		storageUpdateTime := storageSess.UpdateTime.UnixMicro()
-		sessionUpdateTime := session.updatedAt.UnixMicro()
+		sessionUpdateTime := session.LastUpdateTime().UnixMicro()
 		if storageUpdateTime > sessionUpdateTime {
 			return fmt.Errorf(
-				"stale session error: last update time from request (%s) is older than in database (%s)",
-				time.Unix(0, sessionUpdateTime).Format(time.RFC3339Nano),
-				time.Unix(0, storageUpdateTime).Format(time.RFC3339Nano),
+				"%w: last update time from request (%s) is older than in database (%s)",
+				ErrStaleSession,
+				time.UnixMicro(sessionUpdateTime).Format(time.RFC3339Nano),
+				time.UnixMicro(storageUpdateTime).Format(time.RFC3339Nano),
 			)
 		}
 
@@ -387,7 +397,7 @@ func (s *databaseService) applyEvent(ctx context.Context, session *localSession,
 			return err
 		}
 
-		appDelta, userDelta, sessionDelta := extractStateDeltas(event.Actions.StateDelta)
+		appDelta, userDelta, sessionDelta := sessionutils.ExtractStateDeltas(event.Actions.StateDelta)
 
 		// Merge state deltas and update the storage objects.
 		// GORM's .Save() method will correctly perform an INSERT or UPDATE.
@@ -417,13 +427,20 @@ func (s *databaseService) applyEvent(ctx context.Context, session *localSession,
 			return fmt.Errorf("failed to save event: %w", err)
 		}
 
-		storageSess.UpdateTime = event.Timestamp
-		// Save the session to update its state and UpdateTime.
-		if err := tx.Save(&storageSess).Error; err != nil {
-			return fmt.Errorf("failed to save session state: %w", err)
+		// Update the session state and UpdateTime, if the session was not
+		// updated since it was read: the update time is the version of the
+		// session, so that of two concurrent appends, the last one fails
+		// instead of overwriting the state of the first one.
+		res := tx.Model(&storageSession{}).
+			Where(&storageSession{AppName: storageSess.AppName, UserID: storageSess.UserID, ID: storageSess.ID}).
+			Where("update_time = ?", storageSess.UpdateTime).
+			Updates(map[string]any{"state": storageSess.State, "update_time": event.Timestamp})
+		if res.Error != nil {
+			return fmt.Errorf("failed to save session state: %w", res.Error)
+		}
+		if res.RowsAffected == 0 {
+			return fmt.Errorf("%w: the session was updated concurrently", ErrStaleSession)
 		}
-
-		session.updatedAt = storageSess.UpdateTime
 
 		return nil // Returning nil commits the transaction.
 	})
@@ -470,54 +487,3 @@ func fetchAllAppStorageUserState(tx *gorm.DB, appName string) (map[string]*stora
 	}
 	return statesByUserId, nil
 }
-
-// extractStateDeltas splits a single state delta map into three separate maps
-// for app, user, and session states based on key prefixes.
-// Temporary keys (starting with TempStatePrefix) are ignored.
-func extractStateDeltas(delta map[string]any) (
-	appStateDelta, userStateDelta, sessionStateDelta map[string]any,
-) {
-	// Initialize the maps to be returned.
-	appStateDelta = make(map[string]any)
-	userStateDelta = make(map[string]any)
-	sessionStateDelta = make(map[string]any)
-
-	if delta == nil {
-		return appStateDelta, userStateDelta, sessionStateDelta
-	}
-
-	for key, value := range delta {
-		if cleanKey, found := strings.CutPrefix(key, session.KeyPrefixApp); found {
-			appStateDelta[cleanKey] = value
-		} else if cleanKey, found := strings.CutPrefix(key, session.KeyPrefixUser); found {
-			userStateDelta[cleanKey] = value
-		} else if !strings.HasPrefix(key, session.KeyPrefixTemp) {
-			// This key belongs to the session state, as long as it's not temporary.
-			sessionStateDelta[key] = value
-		}
-	}
-	return appStateDelta, userStateDelta, sessionStateDelta
-}
-
-// mergeStates combines app, user, and session state maps into a single map
-// for client-side responses, adding the appropriate prefixes back.
-func mergeStates(appState, userState, sessionState map[string]any) map[string]any {
-	// Pre-allocate map capacity for efficiency.
-	totalSize := len(appState) + len(userState) + len(sessionState)
-	mergedState := make(map[string]any, totalSize)
-
-	// In Go, we create a new map and copy key-value pairs. This is equivalent
-	// to the goal of Python's copy.deepcopy() in this context, which is to
-	// avoid modifying the original sessionState map.
-	maps.Copy(mergedState, sessionState)
-
-	for key, value := range appState {
-		mergedState[session.KeyPrefixApp+key] = value
-	}
-
-	for key, value := range userState {
-		mergedState[session.KeyPrefixUser+key] = value
-	}
-
-	return mergedState
-}
@@ -15,6 +15,7 @@
 package database
 
 import (
+	"errors"
 	"maps"
 	"strconv"
 	"testing"
@@ -342,6 +343,9 @@ func Test_databaseService_Get(t *testing.T) {
 				t.Fatalf("databaseService.Get() error = %v, wantErr %v", err, tt.wantErr)
 				return
 			}
+			if tt.wantErr && !errors.Is(err, session.ErrSessionNotFound) {
+				t.Errorf("databaseService.Get() error = %v, want %v", err, session.ErrSessionNotFound)
+			}
 
 			if err != nil {
 				return
@@ -863,6 +867,11 @@ func Test_databaseService_StateManagement(t *testing.T) {
 			t.Fatalf("Failed to appendEvent: %v", err)
 		}
 
+		// The temp: keys are visible in the session of the invocation.
+		if got, err := s1.Session.State().Get("temp:k1"); err != nil || got != "v1" {
+			t.Errorf("State().Get(%q) of the appended session = %v, %v, want %q", "temp:k1", got, err, "v1")
+		}
+
 		s1_got, _ := s.Get(ctx, &session.GetRequest{AppName: appName, UserID: "u1", SessionID: "s1"})
 		wantState := map[string]any{"sk": "v2"}
 		gotState := maps.Collect(s1_got.Session.State().All())
@@ -1015,3 +1024,54 @@ func emptyService(t *testing.T) *databaseService {
 	}
 	return dbservice
 }
+
+func Test_databaseService_AppendEventStale(t *testing.T) {
+	s := emptyService(t)
+	created, err := s.Create(t.Context(), &session.CreateRequest{AppName: "app", UserID: "user", SessionID: "stale"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	get := func() session.Session {
+		t.Helper()
+		resp, err := s.Get(t.Context(), &session.GetRequest{AppName: "app", UserID: "user", SessionID: created.Session.ID()})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return resp.Session
+	}
+	newEvent := func(key string) *session.Event {
+		ev := session.NewEvent("inv")
+		ev.Actions.StateDelta = map[string]any{key: true}
+		return ev
+	}
+
+	first, second := get(), get()
+	if err := s.AppendEvent(t.Context(), first, newEvent("first")); err != nil {
+		t.Fatalf("AppendEvent() error = %v", err)
+	}
+	if err := s.AppendEvent(t.Context(), second, newEvent("second")); !errors.Is(err, ErrStaleSession) {
+		t.Errorf("AppendEvent() on a stale session error = %v, want %v", err, ErrStaleSession)
+	}
+
+	// The session is updated by another writer between the read and the
+	// update of the append.
+	current := get()
+	var updated bool
+	if err := s.db.Callback().Update().Before("gorm:update").Register("test:concurrent_append", func(db *gorm.DB) {
+		if db.Statement.Table != "sessions" || updated {
+			return
+		}
+		updated = true
+		db.Session(&gorm.Session{NewDB: true}).Exec("UPDATE sessions SET update_time = ? WHERE id = ?", time.Now().Add(time.Hour), created.Session.ID())
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.AppendEvent(t.Context(), current, newEvent("concurrent")); !errors.Is(err, ErrStaleSession) {
+		t.Errorf("AppendEvent() concurrent to an update error = %v, want %v", err, ErrStaleSession)
+	}
+
+	state := maps.Collect(get().State().All())
+	if diff := cmp.Diff(map[string]any{"first": true}, state); diff != "" {
+		t.Errorf("state mismatch (-want +got):\n%s", diff)
+	}
+}
@@ -17,6 +17,7 @@ package database
 import (
 	"fmt"
 	"iter"
+	"maps"
 	"strings"
 	"sync"
 	"time"
@@ -56,7 +57,12 @@ func (s *localSession) State() session.State {
 	}
 }
 
+// Events returns a snapshot of the events of the session: the events
+// appended after the call are not part of it.
 func (s *localSession) Events() session.Events {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
 	return events(s.events)
 }
 
@@ -67,7 +73,9 @@ func (s *localSession) LastUpdateTime() time.Time {
 	return s.updatedAt
 }
 
-func (s *localSession) appendEvent(event *session.Event) error {
+// appendEvent appends the event to the session and applies its state delta,
+// and the temp: keys of the invocation trimmed from it.
+func (s *localSession) appendEvent(event *session.Event, temp map[string]any) error {
 	if event.Partial {
 		return nil
 	}
@@ -80,6 +88,10 @@ func (s *localSession) appendEvent(event *session.Event) error {
 		return fmt.Errorf("failed to update localSession state: %w", err)
 	}
 
+	if s.state == nil {
+		s.state = make(map[string]any)
+	}
+	maps.Copy(s.state, temp)
 	s.events = append(s.events, event)
 	s.updatedAt = event.Timestamp
 	return nil
@@ -73,8 +73,10 @@ type storageEvent struct {
 	UserID    string `gorm:"primaryKey;"`
 	SessionID string `gorm:"primaryKey;"`
 
-	InvocationID string
-	Author       string
+	InvocationID  string
+	Author        string
+	CorrelationID *string
+	TurnID        *string
 	// In Python, this is a pickled object. In Go, the raw bytes are the closest
 	// equivalent. Unpickling would require a custom library or service.
 	Actions                []byte
@@ -140,6 +142,12 @@ func createStorageEvent(session session.Session, event *session.Event) (*storage
 	if event.Branch != "" {
 		storageEv.Branch = &event.Branch
 	}
+	if event.CorrelationID != "" {
+		storageEv.CorrelationID = &event.CorrelationID
+	}
+	if event.TurnID != "" {
+		storageEv.TurnID = &event.TurnID
+	}
 	if event.ErrorCode != "" {
 		storageEv.ErrorCode = &event.ErrorCode
 	}
@@ -268,6 +276,8 @@ func createEventFromStorageEvent(se *storageEvent) (*session.Event, error) {
 		Actions:            actions,
 		LongRunningToolIDs: toolIDs,
 		Branch:             branch,
+		CorrelationID:      derefOrZero(se.CorrelationID),
+		TurnID:             derefOrZero(se.TurnID),
 		LLMResponse: model.LLMResponse{
 			Content:           content,
 			GroundingMetadata: groundingMetadata,
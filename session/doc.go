@@ -13,4 +13,13 @@
 // limitations under the License.
 
 // Package session provides types to manage user sessions and their states.
+//
+// # State scopes
+//
+// The scope of a state key is given by its prefix: the keys prefixed with
+// [KeyPrefixApp] are shared by all the sessions of the app, those prefixed
+// with [KeyPrefixUser] by all the sessions of the user in the app, and the
+// other keys belong to the session. The keys prefixed with [KeyPrefixTemp]
+// are visible in the session for the rest of the invocation, but are never
+// stored, nor kept in the state delta of the stored events.
 package session
@@ -0,0 +1,283 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package eventlog exports the event stream of sessions to JSONL and
+// imports it back, e.g. to keep an audit trail, to replay a production
+// conversation into a session for debugging, or to build eval sets from
+// production traffic with eval.CaseFromEvents.
+//
+// Each line is a [Record]: one event with its content, its actions and its
+// token usage, in a schema independent of the Go types of the events. The
+// schema is versioned: fields may be added within a version, and readers
+// reject the records of newer versions. An event with a function call reads
+//
+//	{"version":1,"id":"e1","invocation_id":"inv","timestamp":"2025-01-02T03:04:05Z","author":"weather_agent","content":{"parts":[{"functionCall":{"name":"get_weather","args":{"city":"Paris"}}}],"role":"model"},"usage":{"prompt_tokens":120,"candidates_tokens":8,"total_tokens":128}}
+//
+// The content is in the format of the Gemini API. The credentials requested
+// by the tools are not exported, nor the temporary state.
+package eventlog
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"strings"
+	"time"
+
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/model"
+	"google.golang.org/adk/session"
+	"google.golang.org/adk/tool/toolconfirmation"
+)
+
+// SchemaVersion is the version of the schema of the records written.
+const SchemaVersion = 1
+
+// Record is the exported form of an event.
+type Record struct {
+	Version            int            `json:"version"`
+	ID                 string         `json:"id"`
+	InvocationID       string         `json:"invocation_id"`
+	Timestamp          time.Time      `json:"timestamp"`
+	Author             string         `json:"author"`
+	Branch             string         `json:"branch,omitempty"`
+	TurnID             string         `json:"turn_id,omitempty"`
+	CorrelationID      string         `json:"correlation_id,omitempty"`
+	Sequence           int64          `json:"sequence,omitempty"`
+	Content            *genai.Content `json:"content,omitempty"`
+	Usage              *Usage         `json:"usage,omitempty"`
+	FinishReason       string         `json:"finish_reason,omitempty"`
+	ErrorCode          string         `json:"error_code,omitempty"`
+	ErrorMessage       string         `json:"error_message,omitempty"`
+	CustomMetadata     map[string]any `json:"custom_metadata,omitempty"`
+	Actions            *Actions       `json:"actions,omitempty"`
+	LongRunningToolIDs []string       `json:"long_running_tool_ids,omitempty"`
+}
+
+// Actions are the exported actions of an event, see session.EventActions.
+type Actions struct {
+	StateDelta                 map[string]any                                `json:"state_delta,omitempty"`
+	ArtifactDelta              map[string]int64                              `json:"artifact_delta,omitempty"`
+	TransferToAgent            string                                        `json:"transfer_to_agent,omitempty"`
+	Escalate                   bool                                          `json:"escalate,omitempty"`
+	SkipSummarization          bool                                          `json:"skip_summarization,omitempty"`
+	RequestedToolConfirmations map[string]*toolconfirmation.ToolConfirmation `json:"requested_tool_confirmations,omitempty"`
+}
+
+// Usage is the token usage of the model response of an event.
+type Usage struct {
+	PromptTokens        int32 `json:"prompt_tokens,omitempty"`
+	CachedTokens        int32 `json:"cached_tokens,omitempty"`
+	CandidatesTokens    int32 `json:"candidates_tokens,omitempty"`
+	ThoughtsTokens      int32 `json:"thoughts_tokens,omitempty"`
+	ToolUsePromptTokens int32 `json:"tool_use_prompt_tokens,omitempty"`
+	TotalTokens         int32 `json:"total_tokens,omitempty"`
+}
+
+// NewRecord returns the record of the event.
+func NewRecord(ev *session.Event) *Record {
+	r := &Record{
+		Version:            SchemaVersion,
+		ID:                 ev.ID,
+		InvocationID:       ev.InvocationID,
+		Timestamp:          ev.Timestamp,
+		Author:             ev.Author,
+		Branch:             ev.Branch,
+		TurnID:             ev.TurnID,
+		CorrelationID:      ev.CorrelationID,
+		Sequence:           ev.Sequence,
+		Content:            ev.Content,
+		FinishReason:       string(ev.FinishReason),
+		ErrorCode:          ev.ErrorCode,
+		ErrorMessage:       ev.ErrorMessage,
+		CustomMetadata:     ev.CustomMetadata,
+		LongRunningToolIDs: ev.LongRunningToolIDs,
+	}
+	if u := ev.UsageMetadata; u != nil {
+		r.Usage = &Usage{
+			PromptTokens:        u.PromptTokenCount,
+			CachedTokens:        u.CachedContentTokenCount,
+			CandidatesTokens:    u.CandidatesTokenCount,
+			ThoughtsTokens:      u.ThoughtsTokenCount,
+			ToolUsePromptTokens: u.ToolUsePromptTokenCount,
+			TotalTokens:         u.TotalTokenCount,
+		}
+	}
+	a := ev.Actions
+	actions := &Actions{
+		ArtifactDelta:              a.ArtifactDelta,
+		TransferToAgent:            a.TransferToAgent,
+		Escalate:                   a.Escalate,
+		SkipSummarization:          a.SkipSummarization,
+		RequestedToolConfirmations: a.RequestedToolConfirmations,
+	}
+	for k, v := range a.StateDelta {
+		if strings.HasPrefix(k, session.KeyPrefixTemp) {
+			continue
+		}
+		if actions.StateDelta == nil {
+			actions.StateDelta = make(map[string]any)
+		}
+		actions.StateDelta[k] = v
+	}
+	if len(actions.StateDelta) > 0 || len(actions.ArtifactDelta) > 0 || actions.TransferToAgent != "" ||
+		actions.Escalate || actions.SkipSummarization || len(actions.RequestedToolConfirmations) > 0 {
+		r.Actions = actions
+	}
+	return r
+}
+
+// Event returns the event of the record.
+func (r *Record) Event() *session.Event {
+	ev := &session.Event{
+		LLMResponse: model.LLMResponse{
+			Content:        r.Content,
+			CustomMetadata: r.CustomMetadata,
+			ErrorCode:      r.ErrorCode,
+			ErrorMessage:   r.ErrorMessage,
+			FinishReason:   genai.FinishReason(r.FinishReason),
+		},
+		ID:                 r.ID,
+		Timestamp:          r.Timestamp,
+		InvocationID:       r.InvocationID,
+		Branch:             r.Branch,
+		Author:             r.Author,
+		CorrelationID:      r.CorrelationID,
+		Sequence:           r.Sequence,
+		TurnID:             r.TurnID,
+		LongRunningToolIDs: r.LongRunningToolIDs,
+		Actions:            session.EventActions{StateDelta: make(map[string]any)},
+	}
+	if u := r.Usage; u != nil {
+		ev.UsageMetadata = &genai.GenerateContentResponseUsageMetadata{
+			PromptTokenCount:        u.PromptTokens,
+			CachedContentTokenCount: u.CachedTokens,
+			CandidatesTokenCount:    u.CandidatesTokens,
+			ThoughtsTokenCount:      u.ThoughtsTokens,
+			ToolUsePromptTokenCount: u.ToolUsePromptTokens,
+			TotalTokenCount:         u.TotalTokens,
+		}
+	}
+	if a := r.Actions; a != nil {
+		maps.Copy(ev.Actions.StateDelta, a.StateDelta)
+		ev.Actions.ArtifactDelta = a.ArtifactDelta
+		ev.Actions.TransferToAgent = a.TransferToAgent
+		ev.Actions.Escalate = a.Escalate
+		ev.Actions.SkipSummarization = a.SkipSummarization
+		ev.Actions.RequestedToolConfirmations = a.RequestedToolConfirmations
+	}
+	return ev
+}
+
+// ExportOptions configure [Export].
+type ExportOptions struct {
+	// InvocationID restricts the export to the events of the invocation.
+	// All the events of the session are exported if empty.
+	InvocationID string
+	// Redact is called for every part before it is exported, as with
+	// session.ExportOptions.Redact. It returns the part to export instead,
+	// or nil to drop the part. The given part must not be modified.
+	Redact func(ev *session.Event, part *genai.Part) *genai.Part
+}
+
+// Export writes the events of the session to w, one record per line. opts
+// may be nil.
+func Export(w io.Writer, sess session.Session, opts *ExportOptions) error {
+	if opts == nil {
+		opts = &ExportOptions{}
+	}
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	for ev := range sess.Events().All() {
+		if opts.InvocationID != "" && ev.InvocationID != opts.InvocationID {
+			continue
+		}
+		r := NewRecord(ev)
+		if opts.Redact != nil && ev.Content != nil {
+			r.Content = redact(ev, opts.Redact)
+		}
+		if err := enc.Encode(r); err != nil {
+			return fmt.Errorf("failed to export event %q: %w", ev.ID, err)
+		}
+	}
+	return nil
+}
+
+func redact(ev *session.Event, f func(*session.Event, *genai.Part) *genai.Part) *genai.Content {
+	var parts []*genai.Part
+	for _, part := range ev.Content.Parts {
+		if part == nil {
+			continue
+		}
+		if part = f(ev, part); part != nil {
+			parts = append(parts, part)
+		}
+	}
+	if len(parts) == 0 {
+		return nil
+	}
+	return &genai.Content{Role: ev.Content.Role, Parts: parts}
+}
+
+// Read reads the events of an export. Empty lines are ignored.
+func Read(r io.Reader) ([]*session.Event, error) {
+	var events []*session.Event
+	dec := json.NewDecoder(r)
+	for i := 1; ; i++ {
+		var rec Record
+		err := dec.Decode(&rec)
+		if errors.Is(err, io.EOF) {
+			return events, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("record %d: %w", i, err)
+		}
+		if rec.Version < 1 || rec.Version > SchemaVersion {
+			return nil, fmt.Errorf("record %d: unsupported schema version %d", i, rec.Version)
+		}
+		events = append(events, rec.Event())
+	}
+}
+
+// Replay appends the events to the session, in order, through the service,
+// which applies their state deltas. The events keep their IDs and
+// timestamps.
+func Replay(ctx context.Context, service session.Service, sess session.Session, events []*session.Event) error {
+	for _, ev := range events {
+		if err := service.AppendEvent(ctx, sess, ev); err != nil {
+			return fmt.Errorf("failed to append event %q: %w", ev.ID, err)
+		}
+	}
+	return nil
+}
+
+// Import creates a session and replays the events of the export into it.
+func Import(ctx context.Context, service session.Service, req *session.CreateRequest, r io.Reader) (session.Session, error) {
+	events, err := Read(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the events: %w", err)
+	}
+	resp, err := service.Create(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create session: %w", err)
+	}
+	if err := Replay(ctx, service, resp.Session, events); err != nil {
+		return nil, err
+	}
+	return resp.Session, nil
+}
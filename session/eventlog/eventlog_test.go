@@ -0,0 +1,207 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eventlog_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/agent"
+	"google.golang.org/adk/agent/llmagent"
+	"google.golang.org/adk/internal/testutil"
+	"google.golang.org/adk/model"
+	"google.golang.org/adk/runner"
+	"google.golang.org/adk/session"
+	"google.golang.org/adk/session/eventlog"
+	"google.golang.org/adk/tool"
+	"google.golang.org/adk/tool/functiontool"
+)
+
+type weatherArgs struct {
+	City string `json:"city"`
+}
+
+// newSession runs a conversation with a tool call and returns its session.
+func newSession(t *testing.T) (session.Service, session.Session) {
+	t.Helper()
+	weather, err := functiontool.New(functiontool.Config{Name: "get_weather", Description: "Returns the weather."},
+		func(ctx tool.Context, args weatherArgs) (map[string]any, error) {
+			if err := ctx.State().Set("city", args.City); err != nil {
+				return nil, err
+			}
+			if err := ctx.State().Set(session.KeyPrefixTemp+"scratch", "x"); err != nil {
+				return nil, err
+			}
+			return map[string]any{"temp": 20}, nil
+		})
+	if err != nil {
+		t.Fatal(err)
+	}
+	a, err := llmagent.New(llmagent.Config{
+		Name: "weather_agent",
+		Model: &testutil.MockModel{Responses: []*genai.Content{
+			genai.NewContentFromFunctionCall("get_weather", map[string]any{"city": "Paris"}, genai.RoleModel),
+			genai.NewContentFromText("It is 20 degrees in Paris.", genai.RoleModel),
+			genai.NewContentFromText("You are welcome.", genai.RoleModel),
+		}},
+		Tools: []tool.Tool{weather},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	service := session.InMemoryService()
+	created, err := service.Create(t.Context(), &session.CreateRequest{AppName: "app", UserID: "user"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	r, err := runner.New(runner.Config{AppName: "app", Agent: a, SessionService: service})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, msg := range []string{"What's the weather in Paris?", "Thanks!"} {
+		for _, err := range r.Run(t.Context(), "user", created.Session.ID(), genai.NewContentFromText(msg, genai.RoleUser), agent.RunConfig{}) {
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	resp, err := service.Get(t.Context(), &session.GetRequest{AppName: "app", UserID: "user", SessionID: created.Session.ID()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return service, resp.Session
+}
+
+func TestExportImport(t *testing.T) {
+	_, sess := newSession(t)
+	var exported bytes.Buffer
+	if err := eventlog.Export(&exported, sess, nil); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(exported.String()), "\n")
+	if len(lines) != sess.Events().Len() {
+		t.Fatalf("got %d records, want %d", len(lines), sess.Events().Len())
+	}
+	var response map[string]any
+	if err := json.Unmarshal([]byte(lines[2]), &response); err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(map[string]any{"state_delta": map[string]any{"city": "Paris"}}, response["actions"]); diff != "" {
+		t.Errorf("actions of the function response mismatch (-want +got):\n%s", diff)
+	}
+
+	service := session.InMemoryService()
+	imported, err := eventlog.Import(t.Context(), service, &session.CreateRequest{AppName: "app", UserID: "user", SessionID: "replay"}, bytes.NewReader(exported.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, err := imported.State().Get("city"); err != nil || got != "Paris" {
+		t.Errorf("imported state city = %v, %v, want Paris", got, err)
+	}
+	var reexported bytes.Buffer
+	if err := eventlog.Export(&reexported, imported, nil); err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(exported.String(), reexported.String()); diff != "" {
+		t.Errorf("export of the imported session mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestExport_Options(t *testing.T) {
+	_, sess := newSession(t)
+	invocationID := sess.Events().At(sess.Events().Len() - 1).InvocationID
+	var buf bytes.Buffer
+	err := eventlog.Export(&buf, sess, &eventlog.ExportOptions{
+		InvocationID: invocationID,
+		Redact: func(ev *session.Event, part *genai.Part) *genai.Part {
+			if ev.Author == "user" {
+				return genai.NewPartFromText("[redacted]")
+			}
+			return part
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	events, err := eventlog.Read(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, ev := range events {
+		if ev.InvocationID != invocationID {
+			t.Errorf("exported event of invocation %q, want only %q", ev.InvocationID, invocationID)
+		}
+		got = append(got, ev.Author+": "+ev.Content.Parts[0].Text)
+	}
+	if diff := cmp.Diff([]string{"user: [redacted]", "weather_agent: You are welcome."}, got); diff != "" {
+		t.Errorf("exported events mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestRecord(t *testing.T) {
+	ev := &session.Event{
+		LLMResponse: model.LLMResponse{
+			Content:       genai.NewContentFromText("hi", genai.RoleModel),
+			UsageMetadata: &genai.GenerateContentResponseUsageMetadata{PromptTokenCount: 10, CandidatesTokenCount: 2, TotalTokenCount: 12},
+			FinishReason:  genai.FinishReasonStop,
+		},
+		ID:           "e1",
+		Timestamp:    time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC),
+		InvocationID: "inv",
+		Author:       "agent",
+		Actions: session.EventActions{
+			StateDelta:      map[string]any{"k": "v", session.KeyPrefixTemp + "t": "v"},
+			TransferToAgent: "other",
+		},
+	}
+	b, err := json.Marshal(eventlog.NewRecord(ev))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"version":1,"id":"e1","invocation_id":"inv","timestamp":"2025-01-02T03:04:05Z","author":"agent",` +
+		`"content":{"parts":[{"text":"hi"}],"role":"model"},"usage":{"prompt_tokens":10,"candidates_tokens":2,"total_tokens":12},` +
+		`"finish_reason":"STOP","actions":{"state_delta":{"k":"v"},"transfer_to_agent":"other"}}`
+	if diff := cmp.Diff(want, string(b)); diff != "" {
+		t.Errorf("record mismatch (-want +got):\n%s", diff)
+	}
+
+	events, err := eventlog.Read(bytes.NewReader(b))
+	if err != nil {
+		t.Fatal(err)
+	}
+	delete(ev.Actions.StateDelta, session.KeyPrefixTemp+"t")
+	if diff := cmp.Diff([]*session.Event{ev}, events); diff != "" {
+		t.Errorf("Read() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestRead_Invalid(t *testing.T) {
+	for _, data := range []string{
+		`{"version":2,"id":"e1"}`,
+		`{"id":"e1"}`,
+		`{"version":1,`,
+	} {
+		if _, err := eventlog.Read(strings.NewReader(data)); err == nil {
+			t.Errorf("Read(%s) succeeded, want an error", data)
+		}
+	}
+}
@@ -0,0 +1,210 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package session
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"google.golang.org/genai"
+)
+
+// ExportFormat is the format of a transcript produced by [Export].
+type ExportFormat string
+
+const (
+	// ExportFormatText renders one line per part, prefixed with the author
+	// in square brackets, e.g.
+	//
+	//	[user] What's the weather in Paris?
+	//	[weather_agent] call get_weather({"city":"Paris"})
+	//	[weather_agent] response get_weather: {"temp":20}
+	//	[weather_agent] (thought) The user wants the forecast.
+	ExportFormatText ExportFormat = "text"
+	// ExportFormatMarkdown renders one section per event, headed by the
+	// author in bold. Function calls and responses are rendered as JSON code
+	// blocks and thoughts as block quotes.
+	ExportFormatMarkdown ExportFormat = "markdown"
+	// ExportFormatContentJSON renders a JSON array of genai.Content, in the
+	// format accepted by the Gemini API as conversation history. Authorship
+	// is expressed only through the content role ("user" or "model").
+	ExportFormatContentJSON ExportFormat = "content_json"
+)
+
+// ExportOptions configure [Export].
+type ExportOptions struct {
+	// IncludeThoughts includes the model's thought parts in the transcript.
+	// Thoughts are omitted by default.
+	IncludeThoughts bool
+	// Redact is called for every part before it is exported. It returns the
+	// part to export instead, e.g. a copy with sensitive data masked, or nil
+	// to drop the part. The given part must not be modified.
+	Redact func(ev *Event, part *genai.Part) *genai.Part
+}
+
+// Export serializes the conversation of the session into a transcript of the
+// given format. Events without content, such as pure state updates, are
+// skipped. opts may be nil.
+func Export(s Session, format ExportFormat, opts *ExportOptions) ([]byte, error) {
+	if opts == nil {
+		opts = &ExportOptions{}
+	}
+
+	var contents []*genai.Content
+	var authors []string
+	for ev := range s.Events().All() {
+		content := exportedContent(ev, opts)
+		if content == nil {
+			continue
+		}
+		contents = append(contents, content)
+		authors = append(authors, ev.Author)
+	}
+
+	switch format {
+	case ExportFormatText:
+		return exportText(authors, contents), nil
+	case ExportFormatMarkdown:
+		return exportMarkdown(authors, contents), nil
+	case ExportFormatContentJSON:
+		if contents == nil {
+			contents = []*genai.Content{}
+		}
+		return json.MarshalIndent(contents, "", "  ")
+	default:
+		return nil, fmt.Errorf("unsupported export format %q", format)
+	}
+}
+
+// exportedContent returns the content of the event to export, with thoughts
+// filtered and redaction applied, or nil if there is nothing to export.
+func exportedContent(ev *Event, opts *ExportOptions) *genai.Content {
+	if ev.Content == nil {
+		return nil
+	}
+	var parts []*genai.Part
+	for _, part := range ev.Content.Parts {
+		if part == nil || (part.Thought && !opts.IncludeThoughts) {
+			continue
+		}
+		if opts.Redact != nil {
+			part = opts.Redact(ev, part)
+			if part == nil {
+				continue
+			}
+		}
+		parts = append(parts, part)
+	}
+	if len(parts) == 0 {
+		return nil
+	}
+	role := ev.Content.Role
+	if role == "" {
+		role = genai.RoleModel
+		if ev.Author == "user" {
+			role = genai.RoleUser
+		}
+	}
+	return &genai.Content{Role: role, Parts: parts}
+}
+
+func exportText(authors []string, contents []*genai.Content) []byte {
+	var buf bytes.Buffer
+	for i, content := range contents {
+		for _, part := range content.Parts {
+			fmt.Fprintf(&buf, "[%s] %s\n", authors[i], partText(part))
+		}
+	}
+	return buf.Bytes()
+}
+
+func partText(part *genai.Part) string {
+	switch {
+	case part.FunctionCall != nil:
+		return fmt.Sprintf("call %s(%s)", part.FunctionCall.Name, compactJSON(part.FunctionCall.Args))
+	case part.FunctionResponse != nil:
+		return fmt.Sprintf("response %s: %s", part.FunctionResponse.Name, compactJSON(part.FunctionResponse.Response))
+	case part.Thought:
+		return "(thought) " + oneLine(part.Text)
+	case part.ExecutableCode != nil:
+		return fmt.Sprintf("code (%s): %s", part.ExecutableCode.Language, oneLine(part.ExecutableCode.Code))
+	case part.CodeExecutionResult != nil:
+		return fmt.Sprintf("code result (%s): %s", part.CodeExecutionResult.Outcome, oneLine(part.CodeExecutionResult.Output))
+	case part.InlineData != nil:
+		return fmt.Sprintf("[inline data: %s]", part.InlineData.MIMEType)
+	case part.FileData != nil:
+		return fmt.Sprintf("[file: %s]", part.FileData.FileURI)
+	default:
+		return oneLine(part.Text)
+	}
+}
+
+func exportMarkdown(authors []string, contents []*genai.Content) []byte {
+	sections := make([]string, len(contents))
+	for i, content := range contents {
+		blocks := []string{fmt.Sprintf("**%s**:", authors[i])}
+		for _, part := range content.Parts {
+			blocks = append(blocks, partMarkdown(part))
+		}
+		sections[i] = strings.Join(blocks, "\n\n")
+	}
+	if len(sections) == 0 {
+		return nil
+	}
+	return []byte(strings.Join(sections, "\n\n") + "\n")
+}
+
+func partMarkdown(part *genai.Part) string {
+	switch {
+	case part.FunctionCall != nil:
+		return fmt.Sprintf("Function call `%s`:\n\n```json\n%s\n```", part.FunctionCall.Name, indentedJSON(part.FunctionCall.Args))
+	case part.FunctionResponse != nil:
+		return fmt.Sprintf("Function response `%s`:\n\n```json\n%s\n```", part.FunctionResponse.Name, indentedJSON(part.FunctionResponse.Response))
+	case part.Thought:
+		return "> *Thought:* " + strings.ReplaceAll(strings.TrimSpace(part.Text), "\n", "\n> ")
+	case part.ExecutableCode != nil:
+		return fmt.Sprintf("```%s\n%s\n```", strings.ToLower(string(part.ExecutableCode.Language)), strings.TrimSpace(part.ExecutableCode.Code))
+	case part.CodeExecutionResult != nil:
+		return fmt.Sprintf("Code execution result (%s):\n\n```\n%s\n```", part.CodeExecutionResult.Outcome, strings.TrimSpace(part.CodeExecutionResult.Output))
+	case part.InlineData != nil:
+		return fmt.Sprintf("*[inline data: %s]*", part.InlineData.MIMEType)
+	case part.FileData != nil:
+		return fmt.Sprintf("*[file: %s]*", part.FileData.FileURI)
+	default:
+		return strings.TrimSpace(part.Text)
+	}
+}
+
+func compactJSON(v any) string {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(b)
+}
+
+func indentedJSON(v any) string {
+	b, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(b)
+}
+
+func oneLine(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
@@ -0,0 +1,152 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package session
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/model"
+)
+
+func exportTestSession(t *testing.T) Session {
+	t.Helper()
+	service := InMemoryService()
+	resp, err := service.Create(t.Context(), &CreateRequest{AppName: "app", UserID: "user"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	contents := []struct {
+		author  string
+		content *genai.Content
+	}{
+		{"user", genai.NewContentFromText("What's the weather in Paris?", genai.RoleUser)},
+		{"weather_agent", &genai.Content{Role: genai.RoleModel, Parts: []*genai.Part{
+			{Text: "The user wants the forecast.", Thought: true},
+			{FunctionCall: &genai.FunctionCall{Name: "get_weather", Args: map[string]any{"city": "Paris"}}},
+		}}},
+		{"weather_agent", genai.NewContentFromFunctionResponse("get_weather", map[string]any{"temp": 20}, genai.RoleUser)},
+		{"weather_agent", nil},
+		{"weather_agent", genai.NewContentFromText("It is 20 degrees in Paris.", genai.RoleModel)},
+	}
+	for _, c := range contents {
+		ev := NewEvent("inv")
+		ev.Author = c.author
+		ev.LLMResponse = model.LLMResponse{Content: c.content}
+		if err := service.AppendEvent(t.Context(), resp.Session, ev); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return resp.Session
+}
+
+func TestExport(t *testing.T) {
+	s := exportTestSession(t)
+
+	tests := []struct {
+		name   string
+		format ExportFormat
+		opts   *ExportOptions
+		want   string
+	}{
+		{
+			name:   "text",
+			format: ExportFormatText,
+			want: `[user] What's the weather in Paris?
+[weather_agent] call get_weather({"city":"Paris"})
+[weather_agent] response get_weather: {"temp":20}
+[weather_agent] It is 20 degrees in Paris.
+`,
+		},
+		{
+			name:   "text with thoughts",
+			format: ExportFormatText,
+			opts:   &ExportOptions{IncludeThoughts: true},
+			want: `[user] What's the weather in Paris?
+[weather_agent] (thought) The user wants the forecast.
+[weather_agent] call get_weather({"city":"Paris"})
+[weather_agent] response get_weather: {"temp":20}
+[weather_agent] It is 20 degrees in Paris.
+`,
+		},
+		{
+			name:   "markdown",
+			format: ExportFormatMarkdown,
+			opts:   &ExportOptions{IncludeThoughts: true},
+			want: "**user**:\n\nWhat's the weather in Paris?\n\n" +
+				"**weather_agent**:\n\n> *Thought:* The user wants the forecast.\n\n" +
+				"Function call `get_weather`:\n\n```json\n{\n  \"city\": \"Paris\"\n}\n```\n\n" +
+				"**weather_agent**:\n\nFunction response `get_weather`:\n\n```json\n{\n  \"temp\": 20\n}\n```\n\n" +
+				"**weather_agent**:\n\nIt is 20 degrees in Paris.\n",
+		},
+		{
+			name:   "redacted",
+			format: ExportFormatText,
+			opts: &ExportOptions{Redact: func(ev *Event, part *genai.Part) *genai.Part {
+				if ev.Author == "user" {
+					return &genai.Part{Text: "[REDACTED]"}
+				}
+				if part.FunctionResponse != nil {
+					return nil
+				}
+				return part
+			}},
+			want: `[user] [REDACTED]
+[weather_agent] call get_weather({"city":"Paris"})
+[weather_agent] It is 20 degrees in Paris.
+`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Export(s, tt.format, tt.opts)
+			if err != nil {
+				t.Fatalf("Export() error = %v", err)
+			}
+			if diff := cmp.Diff(tt.want, string(got)); diff != "" {
+				t.Errorf("Export() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestExport_ContentJSON(t *testing.T) {
+	got, err := Export(exportTestSession(t), ExportFormatContentJSON, nil)
+	if err != nil {
+		t.Fatalf("Export() error = %v", err)
+	}
+	var contents []*genai.Content
+	if err := json.Unmarshal(got, &contents); err != nil {
+		t.Fatalf("failed to decode exported contents: %v", err)
+	}
+	want := []*genai.Content{
+		genai.NewContentFromText("What's the weather in Paris?", genai.RoleUser),
+		genai.NewContentFromFunctionCall("get_weather", map[string]any{"city": "Paris"}, genai.RoleModel),
+		genai.NewContentFromFunctionResponse("get_weather", map[string]any{"temp": float64(20)}, genai.RoleUser),
+		genai.NewContentFromText("It is 20 degrees in Paris.", genai.RoleModel),
+	}
+	if diff := cmp.Diff(want, contents); diff != "" {
+		t.Errorf("Export() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestExport_UnsupportedFormat(t *testing.T) {
+	if _, err := Export(exportTestSession(t), "yaml", nil); err == nil {
+		t.Error("Export() succeeded, want error")
+	}
+}
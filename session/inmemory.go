@@ -67,24 +67,22 @@ func (s *inMemoryService) Create(ctx context.Context, req *CreateRequest) (*Crea
 		return nil, fmt.Errorf("session %s already exists", req.SessionID)
 	}
 
-	state := req.State
-	if state == nil {
-		state = make(stateMap)
-	}
+	// Only the session-scoped keys are stored in the session: the app: and
+	// user: keys are stored with the app and the user, and the temp: keys
+	// are dropped.
+	appDelta, userDelta, sessionState := sessionutils.ExtractStateDeltas(req.State)
 	val := &session{
 		id:        key,
-		state:     state,
+		state:     sessionState,
 		updatedAt: time.Now(),
 	}
 
 	s.sessions.Set(encodedKey, val)
-	appDelta, userDelta, _ := sessionutils.ExtractStateDeltas(req.State)
 	appState := s.updateAppState(appDelta, req.AppName)
 	userState := s.updateUserState(userDelta, req.AppName, req.UserID)
-	val.state = sessionutils.MergeStates(appState, userState, state)
 
 	copiedSession := copySessionWithoutStateAndEvents(val)
-	copiedSession.state = maps.Clone(val.state)
+	copiedSession.state = sessionutils.MergeStates(appState, userState, sessionState)
 	copiedSession.events = slices.Clone(val.events)
 
 	return &CreateResponse{
@@ -109,7 +107,7 @@ func (s *inMemoryService) Get(ctx context.Context, req *GetRequest) (*GetRespons
 
 	res, ok := s.sessions.Get(id.Encode())
 	if !ok {
-		return nil, fmt.Errorf("session %+v not found", req.SessionID)
+		return nil, fmt.Errorf("session %q: %w", req.SessionID, ErrSessionNotFound)
 	}
 
 	copiedSession := copySessionWithoutStateAndEvents(res)
@@ -313,7 +311,12 @@ func (s *session) State() State {
 	}
 }
 
+// Events returns a snapshot of the events of the session: the events
+// appended after the call are not part of it.
 func (s *session) Events() Events {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
 	return events(s.events)
 }
 
@@ -329,11 +332,19 @@ func (s *session) appendEvent(event *Event) error {
 		return nil
 	}
 
+	// The temp: keys are visible in the session for the rest of the
+	// invocation, but are not part of the stored event.
+	temp := sessionutils.TempState(event.Actions.StateDelta)
 	processedEvent := trimTempDeltaState(event)
 	if err := updateSessionState(s, processedEvent); err != nil {
 		return fmt.Errorf("error on appendEvent: %w", err)
 	}
 
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	maps.Copy(s.state, temp)
+
 	s.events = append(s.events, event)
 	s.updatedAt = event.Timestamp
 	return nil
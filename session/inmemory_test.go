@@ -15,6 +15,7 @@
 package session
 
 import (
+	"errors"
 	"maps"
 	"strconv"
 	"strings"
@@ -346,6 +347,9 @@ func Test_databaseService_Get(t *testing.T) {
 				t.Fatalf("databaseService.Get() error = %v, wantErr %v", err, tt.wantErr)
 				return
 			}
+			if tt.wantErr && !errors.Is(err, ErrSessionNotFound) {
+				t.Errorf("databaseService.Get() error = %v, want %v", err, ErrSessionNotFound)
+			}
 
 			if err != nil {
 				return
@@ -905,6 +909,32 @@ func Test_databaseService_StateManagement(t *testing.T) {
 		}
 	})
 
+	t.Run("create_routes_scoped_keys", func(t *testing.T) {
+		s := emptyService(t)
+		s1, err := s.Create(ctx, &CreateRequest{AppName: appName, UserID: "u1", SessionID: "s1", State: map[string]any{
+			"app:k1": "v1", "user:k2": "v2", "temp:k3": "v3", "sk": "v4",
+		}})
+		if err != nil {
+			t.Fatalf("Failed to create session: %v", err)
+		}
+		wantState := map[string]any{"app:k1": "v1", "user:k2": "v2", "sk": "v4"}
+		if diff := cmp.Diff(wantState, maps.Collect(s1.Session.State().All())); diff != "" {
+			t.Errorf("Created state mismatch (-want +got):\n%s", diff)
+		}
+		s1Got, err := s.Get(ctx, &GetRequest{AppName: appName, UserID: "u1", SessionID: "s1"})
+		if err != nil {
+			t.Fatalf("Failed to get session: %v", err)
+		}
+		if diff := cmp.Diff(wantState, maps.Collect(s1Got.Session.State().All())); diff != "" {
+			t.Errorf("Stored state mismatch (-want +got):\n%s", diff)
+		}
+
+		s2, _ := s.Create(ctx, &CreateRequest{AppName: appName, UserID: "u2", SessionID: "s2"})
+		if diff := cmp.Diff(map[string]any{"app:k1": "v1"}, maps.Collect(s2.Session.State().All())); diff != "" {
+			t.Errorf("Other user state mismatch (-want +got):\n%s", diff)
+		}
+	})
+
 	t.Run("temp_state_is_not_persisted", func(t *testing.T) {
 		s := emptyService(t)
 		s1, _ := s.Create(ctx, &CreateRequest{AppName: appName, UserID: "u1", SessionID: "s1"})
@@ -916,6 +946,11 @@ func Test_databaseService_StateManagement(t *testing.T) {
 		}
 		_ = s.AppendEvent(ctx, s1.Session.(*session), event)
 
+		// The temp: keys are visible in the session of the invocation.
+		if got, err := s1.Session.State().Get("temp:k1"); err != nil || got != "v1" {
+			t.Errorf("State().Get(%q) of the appended session = %v, %v, want %q", "temp:k1", got, err, "v1")
+		}
+
 		s1_got, _ := s.Get(ctx, &GetRequest{AppName: appName, UserID: "u1", SessionID: "s1"})
 		wantState := map[string]any{"sk": "v2"}
 		gotState := maps.Collect(s1_got.Session.State().All())
@@ -1003,7 +1038,6 @@ func emptyService(t *testing.T) Service {
 	return InMemoryService()
 }
 
-// TODO: test concurrency
 func Test_inMemoryService_CreateConcurrentAccess(t *testing.T) {
 	s := InMemoryService()
 	const goroutines = 16
@@ -1049,3 +1083,52 @@ func Test_inMemoryService_CreateConcurrentAccess(t *testing.T) {
 		t.Errorf("expected %d 'already exists' errors, but got %d", expectedErrors, errorCount.Load())
 	}
 }
+
+// Test_inMemoryService_AppendEventConcurrentAccess appends events to a
+// session while it is read, as parallel agents do. Run with -race.
+func Test_inMemoryService_AppendEventConcurrentAccess(t *testing.T) {
+	s := InMemoryService()
+	resp, err := s.Create(t.Context(), &CreateRequest{AppName: "race-app", UserID: "race-user"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sess := resp.Session
+	const goroutines = 8
+	const events = 32
+
+	var wg sync.WaitGroup
+	for i := range goroutines {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for j := range events {
+				event := NewEvent("inv-" + strconv.Itoa(i))
+				event.Actions.StateDelta = map[string]any{"key-" + strconv.Itoa(i): j}
+				if err := s.AppendEvent(t.Context(), sess, event); err != nil {
+					t.Errorf("AppendEvent() error = %v", err)
+				}
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for range events {
+				for range sess.Events().All() {
+				}
+				_ = sess.LastUpdateTime()
+				_, _ = sess.State().Get("key-0")
+			}
+		}()
+	}
+	wg.Wait()
+
+	if got := sess.Events().Len(); got != goroutines*events {
+		t.Errorf("session has %d events, want %d", got, goroutines*events)
+	}
+	getResp, err := s.Get(t.Context(), &GetRequest{AppName: "race-app", UserID: "race-user", SessionID: sess.ID()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := getResp.Session.Events().Len(); got != goroutines*events {
+		t.Errorf("stored session has %d events, want %d", got, goroutines*events)
+	}
+}
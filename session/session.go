@@ -21,7 +21,9 @@ import (
 
 	"github.com/google/uuid"
 
+	"google.golang.org/adk/auth"
 	"google.golang.org/adk/model"
+	"google.golang.org/adk/tool/toolconfirmation"
 )
 
 // Session represents a series of interactions between a user and agents.
@@ -107,6 +109,21 @@ type Event struct {
 	Branch string
 	// Author is the name of the event's author
 	Author string
+	// CorrelationID joins the event with the activity of upstream and
+	// downstream systems. It is set by the runner for every event of an
+	// invocation, see agent.WithCorrelationID.
+	CorrelationID string
+	// Sequence is the position of the event in the stream of its invocation.
+	// It is set by the runner, starting at 1, and is used to resume an
+	// interrupted stream.
+	Sequence int64
+	// TurnID identifies the turn of the event, see Turn. It is set by the
+	// runner for every event of a run, including the user message.
+	TurnID string
+	// TurnBoundary marks the events starting and completing a turn in the
+	// event stream of the runner, see agent.RunConfig.EmitTurnBoundaries.
+	// It is empty for the other events, and for all stored events.
+	TurnBoundary TurnBoundary
 
 	// The actions taken by the agent.
 	Actions EventActions
@@ -154,6 +171,14 @@ type EventActions struct {
 	TransferToAgent string
 	// The agent is escalating to a higher level agent.
 	Escalate bool
+	// RequestedAuthConfigs are the credentials requested by the tools, keyed
+	// by the ID of the function call whose handler requested them.
+	// Only valid for function response event.
+	RequestedAuthConfigs map[string]*auth.AuthConfig
+	// RequestedToolConfirmations are the confirmations requested by the
+	// tools, keyed by the ID of the function call to confirm.
+	// Only valid for function response event.
+	RequestedToolConfirmations map[string]*toolconfirmation.ToolConfirmation
 }
 
 // Prefixes for defining session's state scopes
@@ -175,6 +200,10 @@ const (
 // ErrStateKeyNotExist is the error thrown when key does not exist.
 var ErrStateKeyNotExist = errors.New("state key does not exist")
 
+// ErrSessionNotFound is the error returned by Service.Get when the session
+// does not exist.
+var ErrSessionNotFound = errors.New("session not found")
+
 func hasFunctionCalls(resp *model.LLMResponse) bool {
 	if resp == nil || resp.Content == nil {
 		return false
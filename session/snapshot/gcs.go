@@ -0,0 +1,50 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package snapshot
+
+import (
+	"context"
+	"fmt"
+
+	"cloud.google.com/go/storage"
+)
+
+// WriteGCS writes the snapshot to the Cloud Storage object.
+func WriteGCS(ctx context.Context, obj *storage.ObjectHandle, snap *Snapshot) error {
+	// Canceling the context of the writer aborts the upload.
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	w := obj.NewWriter(ctx)
+	w.ContentType = "application/json"
+	if err := snap.Write(w); err != nil {
+		cancel()
+		w.Close()
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("failed to write snapshot to gs://%s/%s: %w", obj.BucketName(), obj.ObjectName(), err)
+	}
+	return nil
+}
+
+// ReadGCS reads a snapshot from the Cloud Storage object.
+func ReadGCS(ctx context.Context, obj *storage.ObjectHandle) (*Snapshot, error) {
+	r, err := obj.NewReader(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read snapshot from gs://%s/%s: %w", obj.BucketName(), obj.ObjectName(), err)
+	}
+	defer r.Close()
+	return Read(r)
+}
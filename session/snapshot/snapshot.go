@@ -0,0 +1,260 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package snapshot takes versioned snapshots of sessions and restores them,
+// e.g. to back up a session, to promote it from a staging environment to
+// production, or to attach the session of a user to a bug report and replay
+// it locally.
+//
+// A [Snapshot] holds the events of the session, in the records of package
+// eventlog, its state and the manifest of its artifacts, optionally with
+// their content. It is written as a single JSON document, to a writer with
+// [Snapshot.Write] or to a Cloud Storage object with [WriteGCS].
+//
+// The schema is versioned as the one of package eventlog: fields may be added
+// within a version, and readers reject the snapshots of newer versions. The
+// temporary state and the credentials requested by the tools are not
+// included.
+package snapshot
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/artifact"
+	"google.golang.org/adk/session"
+	"google.golang.org/adk/session/eventlog"
+)
+
+// SchemaVersion is the version of the schema of the snapshots written.
+const SchemaVersion = 1
+
+// Snapshot is a copy of a session.
+type Snapshot struct {
+	Version int `json:"version"`
+	// CreatedAt is when the snapshot was taken.
+	CreatedAt      time.Time `json:"created_at"`
+	AppName        string    `json:"app_name"`
+	UserID         string    `json:"user_id"`
+	SessionID      string    `json:"session_id"`
+	LastUpdateTime time.Time `json:"last_update_time"`
+	// State is the state of the session, app and user state included,
+	// without the temporary state.
+	State  map[string]any     `json:"state,omitempty"`
+	Events []*eventlog.Record `json:"events"`
+	// Artifacts is the manifest of the artifacts of the session, user
+	// scoped ones included, by file name.
+	Artifacts []*Artifact `json:"artifacts,omitempty"`
+}
+
+// Artifact is an artifact of a snapshot.
+type Artifact struct {
+	FileName string `json:"file_name"`
+	// Versions are the versions of the artifact, the oldest first.
+	Versions []*ArtifactVersion `json:"versions"`
+}
+
+// ArtifactVersion is a version of an artifact of a snapshot.
+type ArtifactVersion struct {
+	Version int64 `json:"version"`
+	// Part is the content of the version, included with
+	// ExportOptions.ArtifactData.
+	Part *genai.Part `json:"part,omitempty"`
+}
+
+// ExportOptions configure [Export].
+type ExportOptions struct {
+	// ArtifactService stores the artifacts of the session. The snapshot has
+	// no artifacts if nil.
+	ArtifactService artifact.Service
+	// ArtifactData includes the content of the artifacts in the snapshot,
+	// rather than their manifest only.
+	ArtifactData bool
+}
+
+// Export takes a snapshot of the session. opts may be nil.
+func Export(ctx context.Context, sess session.Session, opts *ExportOptions) (*Snapshot, error) {
+	if opts == nil {
+		opts = &ExportOptions{}
+	}
+	snap := &Snapshot{
+		Version:        SchemaVersion,
+		CreatedAt:      time.Now().UTC(),
+		AppName:        sess.AppName(),
+		UserID:         sess.UserID(),
+		SessionID:      sess.ID(),
+		LastUpdateTime: sess.LastUpdateTime(),
+		Events:         []*eventlog.Record{},
+	}
+	for k, v := range sess.State().All() {
+		if strings.HasPrefix(k, session.KeyPrefixTemp) {
+			continue
+		}
+		if snap.State == nil {
+			snap.State = make(map[string]any)
+		}
+		snap.State[k] = v
+	}
+	for ev := range sess.Events().All() {
+		snap.Events = append(snap.Events, eventlog.NewRecord(ev))
+	}
+	if opts.ArtifactService != nil {
+		artifacts, err := exportArtifacts(ctx, opts.ArtifactService, sess, opts.ArtifactData)
+		if err != nil {
+			return nil, err
+		}
+		snap.Artifacts = artifacts
+	}
+	return snap, nil
+}
+
+func exportArtifacts(ctx context.Context, service artifact.Service, sess session.Session, data bool) ([]*Artifact, error) {
+	list, err := service.List(ctx, &artifact.ListRequest{AppName: sess.AppName(), UserID: sess.UserID(), SessionID: sess.ID()})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list artifacts: %w", err)
+	}
+	var artifacts []*Artifact
+	for _, name := range list.FileNames {
+		resp, err := service.Versions(ctx, &artifact.VersionsRequest{AppName: sess.AppName(), UserID: sess.UserID(), SessionID: sess.ID(), FileName: name})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list the versions of artifact %q: %w", name, err)
+		}
+		versions := slices.Sorted(slices.Values(resp.Versions))
+		a := &Artifact{FileName: name}
+		for _, version := range versions {
+			v := &ArtifactVersion{Version: version}
+			if data {
+				loaded, err := service.Load(ctx, &artifact.LoadRequest{AppName: sess.AppName(), UserID: sess.UserID(), SessionID: sess.ID(), FileName: name, Version: version})
+				if err != nil {
+					return nil, fmt.Errorf("failed to load version %d of artifact %q: %w", version, name, err)
+				}
+				v.Part = loaded.Part
+			}
+			a.Versions = append(a.Versions, v)
+		}
+		artifacts = append(artifacts, a)
+	}
+	return artifacts, nil
+}
+
+// Write writes the snapshot to w.
+func (s *Snapshot) Write(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(s); err != nil {
+		return fmt.Errorf("failed to write snapshot: %w", err)
+	}
+	return nil
+}
+
+// Read reads a snapshot written by [Snapshot.Write].
+func Read(r io.Reader) (*Snapshot, error) {
+	var s Snapshot
+	if err := json.NewDecoder(r).Decode(&s); err != nil {
+		return nil, fmt.Errorf("failed to read snapshot: %w", err)
+	}
+	if s.Version < 1 || s.Version > SchemaVersion {
+		return nil, fmt.Errorf("unsupported snapshot schema version %d", s.Version)
+	}
+	for i, rec := range s.Events {
+		if rec == nil {
+			return nil, fmt.Errorf("event %d is null", i+1)
+		}
+		if rec.Version < 1 || rec.Version > eventlog.SchemaVersion {
+			return nil, fmt.Errorf("event %d: unsupported schema version %d", i+1, rec.Version)
+		}
+	}
+	return &s, nil
+}
+
+// ImportOptions configure [Import].
+type ImportOptions struct {
+	// AppName, UserID and SessionID identify the restored session. They
+	// default to the ones of the snapshot; e.g. a session attached to a bug
+	// report may be restored for a developer with UserID.
+	AppName   string
+	UserID    string
+	SessionID string
+	// ArtifactService stores the restored artifacts. The artifacts are not
+	// restored if nil, nor the ones exported without their content.
+	ArtifactService artifact.Service
+}
+
+// Import restores the snapshot in a new session: the session is created with
+// the state of the snapshot, and the events are appended to it, in order,
+// through the service, keeping their IDs and timestamps. As in the original
+// session, their state deltas update the app and user state. opts may be
+// nil.
+//
+// The versions of the artifacts are saved in order, so they keep their
+// numbers, to which the events refer, unless some versions were deleted
+// before the snapshot.
+func Import(ctx context.Context, service session.Service, snap *Snapshot, opts *ImportOptions) (session.Session, error) {
+	if opts == nil {
+		opts = &ImportOptions{}
+	}
+	if snap == nil {
+		return nil, errors.New("snapshot is nil")
+	}
+	req := &session.CreateRequest{
+		AppName:   cmp.Or(opts.AppName, snap.AppName),
+		UserID:    cmp.Or(opts.UserID, snap.UserID),
+		SessionID: cmp.Or(opts.SessionID, snap.SessionID),
+		State:     maps.Clone(snap.State),
+	}
+	resp, err := service.Create(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create session: %w", err)
+	}
+	sess := resp.Session
+	events := make([]*session.Event, len(snap.Events))
+	for i, rec := range snap.Events {
+		events[i] = rec.Event()
+	}
+	if err := eventlog.Replay(ctx, service, sess, events); err != nil {
+		return nil, err
+	}
+
+	if opts.ArtifactService == nil {
+		return sess, nil
+	}
+	for _, a := range snap.Artifacts {
+		for _, v := range a.Versions {
+			if v.Part == nil {
+				continue
+			}
+			_, err := opts.ArtifactService.Save(ctx, &artifact.SaveRequest{
+				AppName:   sess.AppName(),
+				UserID:    sess.UserID(),
+				SessionID: sess.ID(),
+				FileName:  a.FileName,
+				Part:      v.Part,
+			})
+			if err != nil {
+				return nil, fmt.Errorf("failed to save version %d of artifact %q: %w", v.Version, a.FileName, err)
+			}
+		}
+	}
+	return sess, nil
+}
@@ -0,0 +1,153 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package snapshot_test
+
+import (
+	"bytes"
+	"fmt"
+	"maps"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/artifact"
+	"google.golang.org/adk/model"
+	"google.golang.org/adk/session"
+	"google.golang.org/adk/session/eventlog"
+	"google.golang.org/adk/session/snapshot"
+)
+
+// newSession returns a session with state, events and two versions of an
+// artifact.
+func newSession(t *testing.T) (session.Session, artifact.Service) {
+	t.Helper()
+	ctx := t.Context()
+	service := session.InMemoryService()
+	created, err := service.Create(ctx, &session.CreateRequest{
+		AppName:   "app",
+		UserID:    "user",
+		SessionID: "s1",
+		State:     map[string]any{"topic": "weather", session.KeyPrefixUser + "name": "Ada"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	artifacts := artifact.InMemoryService()
+	for _, text := range []string{"draft", "final"} {
+		if _, err := artifacts.Save(ctx, &artifact.SaveRequest{AppName: "app", UserID: "user", SessionID: "s1", FileName: "report.txt", Part: genai.NewPartFromText(text)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	user := session.NewEvent("inv")
+	user.Author = "user"
+	user.LLMResponse = model.LLMResponse{Content: genai.NewContentFromText("Write the report.", genai.RoleUser)}
+	reply := session.NewEvent("inv")
+	reply.Author = "agent"
+	reply.LLMResponse = model.LLMResponse{Content: genai.NewContentFromText("Done.", genai.RoleModel)}
+	reply.Actions.StateDelta = map[string]any{"city": "Paris", session.KeyPrefixTemp + "scratch": "x"}
+	reply.Actions.ArtifactDelta = map[string]int64{"report.txt": 2}
+	for _, ev := range []*session.Event{user, reply} {
+		if err := service.AppendEvent(ctx, created.Session, ev); err != nil {
+			t.Fatal(err)
+		}
+	}
+	resp, err := service.Get(ctx, &session.GetRequest{AppName: "app", UserID: "user", SessionID: "s1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return resp.Session, artifacts
+}
+
+func eventIDs(sess session.Session) []string {
+	var ids []string
+	for ev := range sess.Events().All() {
+		ids = append(ids, ev.ID)
+	}
+	return ids
+}
+
+func TestExportImport(t *testing.T) {
+	ctx := t.Context()
+	sess, artifacts := newSession(t)
+
+	snap, err := snapshot.Export(ctx, sess, &snapshot.ExportOptions{ArtifactService: artifacts, ArtifactData: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantState := map[string]any{"topic": "weather", session.KeyPrefixUser + "name": "Ada", "city": "Paris"}
+	if diff := cmp.Diff(wantState, snap.State); diff != "" {
+		t.Errorf("snapshot state mismatch (-want +got):\n%s", diff)
+	}
+
+	var buf bytes.Buffer
+	if err := snap.Write(&buf); err != nil {
+		t.Fatal(err)
+	}
+	read, err := snapshot.Read(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sessions := session.InMemoryService()
+	restoredArtifacts := artifact.InMemoryService()
+	restored, err := snapshot.Import(ctx, sessions, read, &snapshot.ImportOptions{UserID: "dev", ArtifactService: restoredArtifacts})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if restored.UserID() != "dev" || restored.ID() != "s1" || restored.AppName() != "app" {
+		t.Errorf("restored session = %s/%s/%s, want app/dev/s1", restored.AppName(), restored.UserID(), restored.ID())
+	}
+	if diff := cmp.Diff(eventIDs(sess), eventIDs(restored)); diff != "" {
+		t.Errorf("restored events mismatch (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff(wantState, maps.Collect(restored.State().All())); diff != "" {
+		t.Errorf("restored state mismatch (-want +got):\n%s", diff)
+	}
+	for version, want := range map[int64]string{1: "draft", 2: "final"} {
+		resp, err := restoredArtifacts.Load(ctx, &artifact.LoadRequest{AppName: "app", UserID: "dev", SessionID: "s1", FileName: "report.txt", Version: version})
+		if err != nil {
+			t.Fatalf("Load(version %d) error = %v", version, err)
+		}
+		if resp.Part.Text != want {
+			t.Errorf("restored version %d = %q, want %q", version, resp.Part.Text, want)
+		}
+	}
+}
+
+func TestExport_manifest(t *testing.T) {
+	sess, artifacts := newSession(t)
+	snap, err := snapshot.Export(t.Context(), sess, &snapshot.ExportOptions{ArtifactService: artifacts})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []*snapshot.Artifact{{FileName: "report.txt", Versions: []*snapshot.ArtifactVersion{{Version: 1}, {Version: 2}}}}
+	if diff := cmp.Diff(want, snap.Artifacts); diff != "" {
+		t.Errorf("artifacts mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestRead_unsupportedVersion(t *testing.T) {
+	for _, doc := range []string{
+		`{"version":2,"events":[]}`,
+		fmt.Sprintf(`{"version":1,"events":[{"version":%d}]}`, eventlog.SchemaVersion+1),
+	} {
+		if _, err := snapshot.Read(strings.NewReader(doc)); err == nil || !strings.Contains(err.Error(), "unsupported") {
+			t.Errorf("Read(%s) error = %v, want unsupported schema version", doc, err)
+		}
+	}
+}
@@ -0,0 +1,84 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package session
+
+import (
+	"strings"
+
+	"google.golang.org/genai"
+)
+
+// Text returns the text of the content of the event, without the thoughts:
+// the answer of the model.
+func (e *Event) Text() string {
+	return partsText(e.Content, false)
+}
+
+// Thoughts returns the text of the thought parts of the content of the
+// event: the reasoning of the model, returned by the thinking models when
+// asked to, e.g. with genai.ThinkingConfig.IncludeThoughts, or the planning
+// of the planners.
+func (e *Event) Thoughts() string {
+	return partsText(e.Content, true)
+}
+
+func partsText(content *genai.Content, thought bool) string {
+	if content == nil {
+		return ""
+	}
+	var b strings.Builder
+	for _, part := range content.Parts {
+		if part != nil && part.Thought == thought {
+			b.WriteString(part.Text)
+		}
+	}
+	return b.String()
+}
+
+// StripThoughts returns the content without its thought parts, or the
+// content itself if it has none. It returns nil if nothing is left.
+//
+// The thought signatures, which the model needs in the following requests
+// to continue its reasoning, are kept: a thought part with a signature is
+// replaced with a part with the signature only.
+func StripThoughts(content *genai.Content) *genai.Content {
+	if content == nil {
+		return nil
+	}
+	hasThoughts := false
+	for _, part := range content.Parts {
+		if part != nil && part.Thought {
+			hasThoughts = true
+			break
+		}
+	}
+	if !hasThoughts {
+		return content
+	}
+	var parts []*genai.Part
+	for _, part := range content.Parts {
+		switch {
+		case part == nil:
+		case !part.Thought:
+			parts = append(parts, part)
+		case len(part.ThoughtSignature) > 0:
+			parts = append(parts, &genai.Part{Thought: true, ThoughtSignature: part.ThoughtSignature})
+		}
+	}
+	if len(parts) == 0 {
+		return nil
+	}
+	return &genai.Content{Role: content.Role, Parts: parts}
+}
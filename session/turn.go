@@ -0,0 +1,111 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package session
+
+import "iter"
+
+// TurnBoundary marks an event as the start or the end of a turn.
+type TurnBoundary string
+
+const (
+	// TurnStart marks the first event of a turn.
+	TurnStart TurnBoundary = "start"
+	// TurnComplete marks the end of a turn, after its last event.
+	TurnComplete TurnBoundary = "complete"
+)
+
+// Turn is a logical turn of a conversation: a user message, the activity of
+// the agents handling it, and their final answer.
+//
+// A turn is one run of the runner, and the runner sets its ID in the TurnID
+// of all its events. Hence:
+//
+//   - in multi-agent flows, the events of all the agents involved, through
+//     agent transfers or workflow agents, belong to the same turn, which may
+//     have several final responses, one per agent;
+//   - in tool-heavy flows, the function calls and responses, and the model
+//     responses in between, belong to the turn of the user message that
+//     triggered them;
+//   - the function response sent by the client to complete a long-running
+//     tool call starts a new turn, whose user message is the function
+//     response.
+type Turn struct {
+	// ID is the TurnID of the events of the turn.
+	ID string
+	// Events are the events of the turn, in order.
+	Events []*Event
+}
+
+// UserMessage returns the event holding the user message that started the
+// turn, or nil if the turn was started without a message.
+func (t *Turn) UserMessage() *Event {
+	if len(t.Events) > 0 && t.Events[0].Author == "user" {
+		return t.Events[0]
+	}
+	return nil
+}
+
+// FinalResponse returns the last final response of an agent in the turn, or
+// nil if there is none, e.g. if the turn was interrupted.
+func (t *Turn) FinalResponse() *Event {
+	for i := len(t.Events) - 1; i >= 0; i-- {
+		ev := t.Events[i]
+		if ev.Author != "user" && ev.Content != nil && ev.IsFinalResponse() {
+			return ev
+		}
+	}
+	return nil
+}
+
+// Turns groups the events, such as the events of a session, into turns by
+// TurnID. The events stored without a turn ID are grouped by user message:
+// each one starts a turn whose ID is its invocation ID. The partial events
+// and the turn complete markers of a runner stream are skipped; the events
+// of a turn are expected to be consecutive.
+func Turns(events iter.Seq[*Event]) iter.Seq[*Turn] {
+	return func(yield func(*Turn) bool) {
+		var turn *Turn
+		for ev := range events {
+			if ev.Partial || ev.TurnBoundary == TurnComplete {
+				continue
+			}
+			id := ev.TurnID
+			if id == "" {
+				if ev.Author == "user" || turn == nil {
+					id = ev.InvocationID
+				} else {
+					id = turn.ID
+				}
+			}
+			if turn != nil && (id != turn.ID || ev.TurnBoundary == TurnStart) {
+				if !yield(turn) {
+					return
+				}
+				turn = nil
+			}
+			if turn == nil {
+				turn = &Turn{ID: id}
+			}
+			// The start marker of a turn without user message has no content.
+			if ev.TurnBoundary == TurnStart && ev.Content == nil {
+				continue
+			}
+			turn.Events = append(turn.Events, ev)
+		}
+		if turn != nil {
+			yield(turn)
+		}
+	}
+}
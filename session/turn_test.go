@@ -0,0 +1,96 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package session_test
+
+import (
+	"slices"
+	"testing"
+
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/model"
+	"google.golang.org/adk/session"
+)
+
+func turnEvent(id, turnID, author string, content *genai.Content) *session.Event {
+	return &session.Event{
+		ID:           id,
+		TurnID:       turnID,
+		InvocationID: "e-" + author,
+		Author:       author,
+		LLMResponse:  model.LLMResponse{Content: content},
+	}
+}
+
+func TestTurns(t *testing.T) {
+	call := &genai.Content{Role: genai.RoleModel, Parts: []*genai.Part{genai.NewPartFromFunctionCall("lookup", nil)}}
+	response := &genai.Content{Role: genai.RoleUser, Parts: []*genai.Part{genai.NewPartFromFunctionResponse("lookup", nil)}}
+	partial := turnEvent("partial", "turn1", "agent", genai.NewContentFromText("Par", genai.RoleModel))
+	partial.Partial = true
+	start := turnEvent("start", "turn3", "", nil)
+	start.TurnBoundary = session.TurnStart
+	complete := turnEvent("complete", "turn3", "", nil)
+	complete.TurnBoundary = session.TurnComplete
+
+	events := []*session.Event{
+		turnEvent("u1", "turn1", "user", genai.NewContentFromText("Where is my order?", genai.RoleUser)),
+		turnEvent("c1", "turn1", "router", call),
+		turnEvent("r1", "turn1", "router", response),
+		partial,
+		turnEvent("a1", "turn1", "orders", genai.NewContentFromText("It ships today.", genai.RoleModel)),
+		turnEvent("u2", "turn2", "user", genai.NewContentFromText("Thanks!", genai.RoleUser)),
+		turnEvent("a2", "turn2", "router", genai.NewContentFromText("You're welcome.", genai.RoleModel)),
+		// Stored without a turn ID.
+		{ID: "u4", InvocationID: "e-4", Author: "user", LLMResponse: model.LLMResponse{Content: genai.NewContentFromText("Hello?", genai.RoleUser)}},
+		{ID: "a4", InvocationID: "e-5", Author: "router", LLMResponse: model.LLMResponse{Content: genai.NewContentFromText("Hi.", genai.RoleModel)}},
+		start,
+		turnEvent("c3", "turn3", "router", call),
+		complete,
+	}
+
+	type turnSummary struct {
+		id, user, final string
+		events          []string
+	}
+	var got []turnSummary
+	for turn := range session.Turns(slices.Values(events)) {
+		s := turnSummary{id: turn.ID}
+		if ev := turn.UserMessage(); ev != nil {
+			s.user = ev.ID
+		}
+		if ev := turn.FinalResponse(); ev != nil {
+			s.final = ev.ID
+		}
+		for _, ev := range turn.Events {
+			s.events = append(s.events, ev.ID)
+		}
+		got = append(got, s)
+	}
+
+	want := []turnSummary{
+		{id: "turn1", user: "u1", final: "a1", events: []string{"u1", "c1", "r1", "a1"}},
+		{id: "turn2", user: "u2", final: "a2", events: []string{"u2", "a2"}},
+		{id: "e-4", user: "u4", final: "a4", events: []string{"u4", "a4"}},
+		{id: "turn3", events: []string{"c3"}},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("Turns() = %+v, want %+v", got, want)
+	}
+	for i := range want {
+		if got[i].id != want[i].id || got[i].user != want[i].user || got[i].final != want[i].final || !slices.Equal(got[i].events, want[i].events) {
+			t.Errorf("turn %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
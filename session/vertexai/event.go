@@ -0,0 +1,201 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vertexai
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"time"
+
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/auth"
+	"google.golang.org/adk/internal/agentengine"
+	"google.golang.org/adk/model"
+	"google.golang.org/adk/session"
+	"google.golang.org/adk/tool/toolconfirmation"
+)
+
+// adkMetadataKey is the key of the custom metadata of the stored events
+// holding the fields of the events the API has no field for.
+const adkMetadataKey = "adk"
+
+// apiEvent is a session event of the Agent Engine API.
+type apiEvent struct {
+	Name          string            `json:"name,omitempty"`
+	Author        string            `json:"author"`
+	InvocationID  string            `json:"invocationId"`
+	Timestamp     time.Time         `json:"timestamp"`
+	Content       *genai.Content    `json:"content,omitempty"`
+	Actions       *apiEventActions  `json:"actions,omitempty"`
+	EventMetadata *apiEventMetadata `json:"eventMetadata,omitempty"`
+	ErrorCode     string            `json:"errorCode,omitempty"`
+	ErrorMessage  string            `json:"errorMessage,omitempty"`
+}
+
+type apiEventActions struct {
+	SkipSummarization    bool                        `json:"skipSummarization,omitempty"`
+	StateDelta           map[string]any              `json:"stateDelta,omitempty"`
+	ArtifactDelta        map[string]int64            `json:"artifactDelta,omitempty"`
+	TransferAgent        string                      `json:"transferAgent,omitempty"`
+	Escalate             bool                        `json:"escalate,omitempty"`
+	RequestedAuthConfigs map[string]*auth.AuthConfig `json:"requestedAuthConfigs,omitempty"`
+}
+
+type apiEventMetadata struct {
+	Partial            bool                     `json:"partial,omitempty"`
+	TurnComplete       bool                     `json:"turnComplete,omitempty"`
+	Interrupted        bool                     `json:"interrupted,omitempty"`
+	Branch             string                   `json:"branch,omitempty"`
+	CustomMetadata     map[string]any           `json:"customMetadata,omitempty"`
+	LongRunningToolIDs []string                 `json:"longRunningToolIds,omitempty"`
+	GroundingMetadata  *genai.GroundingMetadata `json:"groundingMetadata,omitempty"`
+}
+
+// adkMetadata holds the fields of an event the API has no field for.
+type adkMetadata struct {
+	ID                         string                                        `json:"id,omitempty"`
+	CorrelationID              string                                        `json:"correlationId,omitempty"`
+	Sequence                   int64                                         `json:"sequence,omitempty"`
+	TurnID                     string                                        `json:"turnId,omitempty"`
+	UsageMetadata              *genai.GenerateContentResponseUsageMetadata   `json:"usageMetadata,omitempty"`
+	FinishReason               genai.FinishReason                            `json:"finishReason,omitempty"`
+	InputTranscription         *genai.Transcription                          `json:"inputTranscription,omitempty"`
+	OutputTranscription        *genai.Transcription                          `json:"outputTranscription,omitempty"`
+	RequestedToolConfirmations map[string]*toolconfirmation.ToolConfirmation `json:"requestedToolConfirmations,omitempty"`
+}
+
+func newAPIEvent(event *session.Event) (*apiEvent, error) {
+	custom := make(map[string]any, len(event.CustomMetadata)+1)
+	maps.Copy(custom, event.CustomMetadata)
+	adk, err := toMap(&adkMetadata{
+		ID:                         event.ID,
+		CorrelationID:              event.CorrelationID,
+		Sequence:                   event.Sequence,
+		TurnID:                     event.TurnID,
+		UsageMetadata:              event.UsageMetadata,
+		FinishReason:               event.FinishReason,
+		InputTranscription:         event.InputTranscription,
+		OutputTranscription:        event.OutputTranscription,
+		RequestedToolConfirmations: event.Actions.RequestedToolConfirmations,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode event %s: %w", event.ID, err)
+	}
+	custom[adkMetadataKey] = adk
+
+	return &apiEvent{
+		Author:       event.Author,
+		InvocationID: event.InvocationID,
+		Timestamp:    event.Timestamp,
+		Content:      event.Content,
+		Actions: &apiEventActions{
+			SkipSummarization:    event.Actions.SkipSummarization,
+			StateDelta:           event.Actions.StateDelta,
+			ArtifactDelta:        event.Actions.ArtifactDelta,
+			TransferAgent:        event.Actions.TransferToAgent,
+			Escalate:             event.Actions.Escalate,
+			RequestedAuthConfigs: event.Actions.RequestedAuthConfigs,
+		},
+		EventMetadata: &apiEventMetadata{
+			Partial:            event.Partial,
+			TurnComplete:       event.TurnComplete,
+			Interrupted:        event.Interrupted,
+			Branch:             event.Branch,
+			CustomMetadata:     custom,
+			LongRunningToolIDs: event.LongRunningToolIDs,
+			GroundingMetadata:  event.GroundingMetadata,
+		},
+		ErrorCode:    event.ErrorCode,
+		ErrorMessage: event.ErrorMessage,
+	}, nil
+}
+
+func (e *apiEvent) toEvent() (*session.Event, error) {
+	event := &session.Event{
+		LLMResponse: model.LLMResponse{
+			Content:      e.Content,
+			ErrorCode:    e.ErrorCode,
+			ErrorMessage: e.ErrorMessage,
+		},
+		ID:           agentengine.LastSegment(e.Name),
+		Timestamp:    e.Timestamp,
+		InvocationID: e.InvocationID,
+		Author:       e.Author,
+		Actions:      session.EventActions{StateDelta: make(map[string]any)},
+	}
+	if a := e.Actions; a != nil {
+		if a.StateDelta != nil {
+			event.Actions.StateDelta = a.StateDelta
+		}
+		event.Actions.ArtifactDelta = a.ArtifactDelta
+		event.Actions.SkipSummarization = a.SkipSummarization
+		event.Actions.TransferToAgent = a.TransferAgent
+		event.Actions.Escalate = a.Escalate
+		event.Actions.RequestedAuthConfigs = a.RequestedAuthConfigs
+	}
+	if m := e.EventMetadata; m != nil {
+		event.Partial = m.Partial
+		event.TurnComplete = m.TurnComplete
+		event.Interrupted = m.Interrupted
+		event.Branch = m.Branch
+		event.LongRunningToolIDs = m.LongRunningToolIDs
+		event.GroundingMetadata = m.GroundingMetadata
+		if adk, ok := m.CustomMetadata[adkMetadataKey]; ok {
+			var md adkMetadata
+			if err := fromMap(adk, &md); err != nil {
+				return nil, fmt.Errorf("failed to decode event %s: %w", e.Name, err)
+			}
+			if md.ID != "" {
+				event.ID = md.ID
+			}
+			event.CorrelationID = md.CorrelationID
+			event.Sequence = md.Sequence
+			event.TurnID = md.TurnID
+			event.UsageMetadata = md.UsageMetadata
+			event.FinishReason = md.FinishReason
+			event.InputTranscription = md.InputTranscription
+			event.OutputTranscription = md.OutputTranscription
+			event.Actions.RequestedToolConfirmations = md.RequestedToolConfirmations
+		}
+		custom := maps.Clone(m.CustomMetadata)
+		delete(custom, adkMetadataKey)
+		if len(custom) > 0 {
+			event.CustomMetadata = custom
+		}
+	}
+	return event, nil
+}
+
+func toMap(v any) (map[string]any, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var m map[string]any
+	if err := json.Unmarshal(b, &m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func fromMap(m, v any) error {
+	b, err := json.Marshal(m)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, v)
+}
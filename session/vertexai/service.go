@@ -0,0 +1,270 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package vertexai provides a [session.Service] backed by the Sessions of the
+// Vertex AI Agent Engine, see
+// https://cloud.google.com/vertex-ai/generative-ai/docs/agent-engine/sessions/overview.
+//
+// The same agent runs locally with [session.InMemoryService], and in
+// production with the sessions managed by Agent Engine.
+package vertexai
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"google.golang.org/adk/internal/agentengine"
+	"google.golang.org/adk/internal/sessionutils"
+	"google.golang.org/adk/model"
+	"google.golang.org/adk/session"
+)
+
+// Config configures the Agent Engine of the session service.
+type Config struct {
+	// ProjectID is the Google Cloud project of the Agent Engine.
+	ProjectID string
+	// Location is the region of the Agent Engine, e.g. "us-central1".
+	Location string
+	// AgentEngineID is the ID or the resource name of the Agent Engine.
+	// Optional: if empty, the app name of each request is used, e.g. the
+	// app name of the runner is the ID of the deployed Agent Engine.
+	AgentEngineID string
+	// HTTPClient sends the requests to the API.
+	// Optional: if nil, the requests are authorized with the application
+	// default credentials.
+	HTTPClient *http.Client
+	// Endpoint is the base URL of the API.
+	// Optional: if empty, the regional endpoint of the location is used.
+	Endpoint string
+}
+
+// vertexAIService is a session.Service over the Agent Engine Sessions API.
+type vertexAIService struct {
+	client *agentengine.Client
+}
+
+// NewSessionService returns a [session.Service] storing the sessions and
+// their events in an Agent Engine.
+//
+// The state of a session, including its "app:" and "user:" keys, is stored in
+// the session: it is not shared with the other sessions of the app or of the
+// user, since Agent Engine has no app or user state. The "temp:" keys are not
+// stored.
+func NewSessionService(ctx context.Context, cfg Config) (session.Service, error) {
+	client, err := agentengine.NewClient(ctx, cfg.ProjectID, cfg.Location, cfg.AgentEngineID, cfg.HTTPClient, cfg.Endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("error creating Vertex AI session service: %w", err)
+	}
+	return &vertexAIService{client: client}, nil
+}
+
+// apiSession is a session of the Agent Engine API.
+type apiSession struct {
+	Name         string         `json:"name,omitempty"`
+	UserID       string         `json:"userId"`
+	SessionState map[string]any `json:"sessionState,omitempty"`
+	UpdateTime   time.Time      `json:"updateTime,omitzero"`
+}
+
+func (s *vertexAIService) Create(ctx context.Context, req *session.CreateRequest) (*session.CreateResponse, error) {
+	if req.AppName == "" || req.UserID == "" {
+		return nil, fmt.Errorf("app_name and user_id are required")
+	}
+	engine, err := s.client.EngineName(req.AppName)
+	if err != nil {
+		return nil, err
+	}
+
+	var query url.Values
+	if req.SessionID != "" {
+		query = url.Values{"sessionId": {req.SessionID}}
+	}
+	var op agentengine.Operation
+	if err := s.client.Do(ctx, http.MethodPost, engine+"/sessions", query, &apiSession{UserID: req.UserID, SessionState: withoutTempState(req.State)}, &op); err != nil {
+		return nil, fmt.Errorf("failed to create session: %w", err)
+	}
+	done, err := s.client.Wait(ctx, &op)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create session: %w", err)
+	}
+
+	var created apiSession
+	if len(done.Response) > 0 {
+		if err := json.Unmarshal(done.Response, &created); err != nil {
+			return nil, fmt.Errorf("failed to decode the created session: %w", err)
+		}
+	}
+	if created.Name == "" {
+		// The operation is named after the session, e.g.
+		// ".../sessions/{session}/operations/{operation}".
+		name, _, _ := strings.Cut(op.Name, "/operations/")
+		if err := s.client.Do(ctx, http.MethodGet, name, nil, nil, &created); err != nil {
+			return nil, fmt.Errorf("failed to get the created session: %w", err)
+		}
+	}
+	return &session.CreateResponse{Session: newSession(req.AppName, &created, nil)}, nil
+}
+
+func (s *vertexAIService) Get(ctx context.Context, req *session.GetRequest) (*session.GetResponse, error) {
+	appName, userID, sessionID := req.AppName, req.UserID, req.SessionID
+	if appName == "" || userID == "" || sessionID == "" {
+		return nil, fmt.Errorf("app_name, user_id, session_id are required, got app_name: %q, user_id: %q, session_id: %q", appName, userID, sessionID)
+	}
+	name, err := s.sessionName(appName, sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	var found apiSession
+	if err := s.client.Do(ctx, http.MethodGet, name, nil, nil, &found); err != nil {
+		var apiErr *model.APIError
+		if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound {
+			return nil, fmt.Errorf("session %q: %w", sessionID, session.ErrSessionNotFound)
+		}
+		return nil, fmt.Errorf("failed to get session: %w", err)
+	}
+	// The sessions of the other users are not found.
+	if found.UserID != userID {
+		return nil, fmt.Errorf("session %q: %w", sessionID, session.ErrSessionNotFound)
+	}
+
+	query := url.Values{}
+	if !req.After.IsZero() {
+		query.Set("filter", fmt.Sprintf("timestamp>=%q", req.After.UTC().Format(time.RFC3339Nano)))
+	}
+	var events []*session.Event
+	for {
+		var page struct {
+			SessionEvents []*apiEvent `json:"sessionEvents"`
+			NextPageToken string      `json:"nextPageToken"`
+		}
+		if err := s.client.Do(ctx, http.MethodGet, name+"/events", query, nil, &page); err != nil {
+			return nil, fmt.Errorf("failed to list the events of session %s: %w", sessionID, err)
+		}
+		for _, e := range page.SessionEvents {
+			event, err := e.toEvent()
+			if err != nil {
+				return nil, err
+			}
+			events = append(events, event)
+		}
+		if page.NextPageToken == "" {
+			break
+		}
+		query.Set("pageToken", page.NextPageToken)
+	}
+	if req.NumRecentEvents > 0 && len(events) > req.NumRecentEvents {
+		events = events[len(events)-req.NumRecentEvents:]
+	}
+
+	return &session.GetResponse{Session: newSession(appName, &found, events)}, nil
+}
+
+func (s *vertexAIService) List(ctx context.Context, req *session.ListRequest) (*session.ListResponse, error) {
+	if req.AppName == "" {
+		return nil, fmt.Errorf("app_name is required, got app_name: %q", req.AppName)
+	}
+	engine, err := s.client.EngineName(req.AppName)
+	if err != nil {
+		return nil, err
+	}
+
+	query := url.Values{}
+	if req.UserID != "" {
+		query.Set("filter", fmt.Sprintf("user_id=%q", req.UserID))
+	}
+	sessions := make([]session.Session, 0)
+	for {
+		var page struct {
+			Sessions      []*apiSession `json:"sessions"`
+			NextPageToken string        `json:"nextPageToken"`
+		}
+		if err := s.client.Do(ctx, http.MethodGet, engine+"/sessions", query, nil, &page); err != nil {
+			return nil, fmt.Errorf("failed to list sessions: %w", err)
+		}
+		for _, found := range page.Sessions {
+			sessions = append(sessions, newSession(req.AppName, found, nil))
+		}
+		if page.NextPageToken == "" {
+			break
+		}
+		query.Set("pageToken", page.NextPageToken)
+	}
+	return &session.ListResponse{Sessions: sessions}, nil
+}
+
+func (s *vertexAIService) Delete(ctx context.Context, req *session.DeleteRequest) error {
+	appName, userID, sessionID := req.AppName, req.UserID, req.SessionID
+	if appName == "" || userID == "" || sessionID == "" {
+		return fmt.Errorf("app_name, user_id, session_id are required, got app_name: %q, user_id: %q, session_id: %q", appName, userID, sessionID)
+	}
+	name, err := s.sessionName(appName, sessionID)
+	if err != nil {
+		return err
+	}
+	if err := s.client.Do(ctx, http.MethodDelete, name, nil, nil, nil); err != nil {
+		return fmt.Errorf("failed to delete session: %w", err)
+	}
+	return nil
+}
+
+func (s *vertexAIService) AppendEvent(ctx context.Context, curSession session.Session, event *session.Event) error {
+	if curSession == nil {
+		return fmt.Errorf("session is nil")
+	}
+	if event == nil {
+		return fmt.Errorf("event is nil")
+	}
+	// ignore partial events
+	if event.Partial {
+		return nil
+	}
+	sess, ok := curSession.(*vertexAISession)
+	if !ok {
+		return fmt.Errorf("unexpected session type %T", curSession)
+	}
+
+	// The temp: keys are only visible in the session for the rest of the
+	// invocation.
+	temp := sessionutils.TempState(event.Actions.StateDelta)
+	event = trimTempDeltaState(event)
+	body, err := newAPIEvent(event)
+	if err != nil {
+		return err
+	}
+	name, err := s.sessionName(sess.AppName(), sess.ID())
+	if err != nil {
+		return err
+	}
+	if err := s.client.Do(ctx, http.MethodPost, name+":appendEvent", nil, body, nil); err != nil {
+		return fmt.Errorf("failed to append event: %w", err)
+	}
+	return sess.appendEvent(event, temp)
+}
+
+func (s *vertexAIService) sessionName(appName, sessionID string) (string, error) {
+	engine, err := s.client.EngineName(appName)
+	if err != nil {
+		return "", err
+	}
+	return engine + "/sessions/" + sessionID, nil
+}
+
+var _ session.Service = (*vertexAIService)(nil)
@@ -0,0 +1,229 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vertexai
+
+import (
+	"encoding/json"
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/model"
+	"google.golang.org/adk/session"
+)
+
+const enginePath = "/v1beta1/projects/p/locations/l/reasoningEngines/123"
+
+// fakeAgentEngine serves the Sessions API of an Agent Engine.
+type fakeAgentEngine struct {
+	mu       sync.Mutex
+	sessions map[string]*apiSession
+	events   map[string][]*apiEvent
+	// ops are the pending operations, done on their first poll.
+	ops map[string]*apiSession
+}
+
+func (f *fakeAgentEngine) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	path, ok := strings.CutPrefix(r.URL.Path, enginePath+"/sessions")
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	id, rest, _ := strings.Cut(strings.TrimPrefix(path, "/"), "/")
+	id, method, _ := strings.Cut(id, ":")
+	name := strings.TrimPrefix(enginePath, "/v1beta1/") + "/sessions/" + id
+	switch {
+	case r.Method == http.MethodPost && id == "":
+		var s apiSession
+		json.NewDecoder(r.Body).Decode(&s)
+		id := r.URL.Query().Get("sessionId")
+		if id == "" {
+			id = "generated"
+		}
+		name := strings.TrimPrefix(enginePath, "/v1beta1/") + "/sessions/" + id
+		s.Name, s.UpdateTime = name, time.Now().UTC()
+		opName := name + "/operations/1"
+		f.ops[opName] = &s
+		json.NewEncoder(w).Encode(map[string]any{"name": opName})
+	case r.Method == http.MethodGet && strings.HasPrefix(rest, "operations/"):
+		s := f.ops[name+"/"+rest]
+		f.sessions[s.Name] = s
+		json.NewEncoder(w).Encode(map[string]any{"name": name + "/" + rest, "done": true})
+	case r.Method == http.MethodGet && id == "":
+		var sessions []*apiSession
+		for _, s := range f.sessions {
+			if filter := r.URL.Query().Get("filter"); filter == "" || filter == `user_id="`+s.UserID+`"` {
+				sessions = append(sessions, s)
+			}
+		}
+		json.NewEncoder(w).Encode(map[string]any{"sessions": sessions})
+	case f.sessions[name] == nil:
+		http.Error(w, `{"error": {"message": "session not found"}}`, http.StatusNotFound)
+	case r.Method == http.MethodGet && rest == "events":
+		// The events are served in pages of one event.
+		events := f.events[name]
+		i := 0
+		if token := r.URL.Query().Get("pageToken"); token != "" {
+			i = len(token)
+		}
+		resp := map[string]any{}
+		if i < len(events) {
+			resp["sessionEvents"] = events[i : i+1]
+		}
+		if i+1 < len(events) {
+			resp["nextPageToken"] = strings.Repeat("x", i+1)
+		}
+		json.NewEncoder(w).Encode(resp)
+	case r.Method == http.MethodGet:
+		json.NewEncoder(w).Encode(f.sessions[name])
+	case r.Method == http.MethodDelete:
+		delete(f.sessions, name)
+		delete(f.events, name)
+		w.Write([]byte("{}"))
+	case r.Method == http.MethodPost && method == "appendEvent":
+		var e apiEvent
+		json.NewDecoder(r.Body).Decode(&e)
+		e.Name = name + "/events/" + strings.Repeat("e", len(f.events[name])+1)
+		f.events[name] = append(f.events[name], &e)
+		s := f.sessions[name]
+		if s.SessionState == nil {
+			s.SessionState = make(map[string]any)
+		}
+		maps.Copy(s.SessionState, e.Actions.StateDelta)
+		s.UpdateTime = e.Timestamp
+		w.Write([]byte("{}"))
+	default:
+		http.Error(w, "unexpected request", http.StatusBadRequest)
+	}
+}
+
+func newTestService(t *testing.T) session.Service {
+	t.Helper()
+	server := httptest.NewServer(&fakeAgentEngine{
+		sessions: make(map[string]*apiSession),
+		events:   make(map[string][]*apiEvent),
+		ops:      make(map[string]*apiSession),
+	})
+	t.Cleanup(server.Close)
+	s, err := NewSessionService(t.Context(), Config{
+		ProjectID:  "p",
+		Location:   "l",
+		HTTPClient: server.Client(),
+		Endpoint:   server.URL,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func TestVertexAIService(t *testing.T) {
+	ctx := t.Context()
+	s := newTestService(t)
+
+	created, err := s.Create(ctx, &session.CreateRequest{AppName: "123", UserID: "user", SessionID: "s1", State: map[string]any{"k": "v"}})
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if got := created.Session.ID(); got != "s1" {
+		t.Errorf("Create() session ID = %q, want %q", got, "s1")
+	}
+	if _, err := s.Create(ctx, &session.CreateRequest{AppName: "123", UserID: "other"}); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	var appended []*session.Event
+	for i, text := range []string{"hello", "hi"} {
+		event := session.NewEvent("inv")
+		event.Author = "user"
+		event.Timestamp = time.Unix(int64(i+1), 0).UTC()
+		event.LLMResponse = model.LLMResponse{
+			Content:       genai.NewContentFromText(text, genai.RoleUser),
+			UsageMetadata: &genai.GenerateContentResponseUsageMetadata{TotalTokenCount: 3},
+		}
+		event.TurnID = "turn"
+		event.Actions.StateDelta = map[string]any{"count": float64(i), "temp:x": 1}
+		if err := s.AppendEvent(ctx, created.Session, event); err != nil {
+			t.Fatalf("AppendEvent() error = %v", err)
+		}
+		appended = append(appended, event)
+	}
+	if err := s.AppendEvent(ctx, created.Session, &session.Event{LLMResponse: model.LLMResponse{Partial: true}}); err != nil {
+		t.Fatalf("AppendEvent() of a partial event error = %v", err)
+	}
+	if got := created.Session.Events().Len(); got != 2 {
+		t.Errorf("Events().Len() after AppendEvent() = %d, want 2", got)
+	}
+
+	got, err := s.Get(ctx, &session.GetRequest{AppName: "123", UserID: "user", SessionID: "s1"})
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if diff := cmp.Diff(map[string]any{"k": "v", "count": float64(1)}, maps.Collect(got.Session.State().All())); diff != "" {
+		t.Errorf("Get() state mismatch (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff(appended, []*session.Event(got.Session.Events().(events)), cmpopts.EquateEmpty()); diff != "" {
+		t.Errorf("Get() events mismatch (-want +got):\n%s", diff)
+	}
+
+	recent, err := s.Get(ctx, &session.GetRequest{AppName: "123", UserID: "user", SessionID: "s1", NumRecentEvents: 1})
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if got := recent.Session.Events().Len(); got != 1 || recent.Session.Events().At(0).ID != appended[1].ID {
+		t.Errorf("Get() with NumRecentEvents = 1 returned %d events, want the last one", got)
+	}
+
+	if _, err := s.Get(ctx, &session.GetRequest{AppName: "123", UserID: "other", SessionID: "s1"}); err == nil {
+		t.Error("Get() of the session of another user succeeded, want error")
+	}
+
+	list, err := s.List(ctx, &session.ListRequest{AppName: "123", UserID: "user"})
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if len(list.Sessions) != 1 || list.Sessions[0].ID() != "s1" {
+		t.Errorf("List() = %d sessions, want session s1", len(list.Sessions))
+	}
+
+	if err := s.Delete(ctx, &session.DeleteRequest{AppName: "123", UserID: "user", SessionID: "s1"}); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if _, err := s.Get(ctx, &session.GetRequest{AppName: "123", UserID: "user", SessionID: "s1"}); err == nil {
+		t.Error("Get() of a deleted session succeeded, want error")
+	}
+}
+
+func TestVertexAIService_AppName(t *testing.T) {
+	s := newTestService(t)
+	if _, err := s.Create(t.Context(), &session.CreateRequest{AppName: "my_app", UserID: "user"}); err == nil {
+		t.Error("Create() with an app name which is not an Agent Engine succeeded, want error")
+	}
+	name := "projects/p/locations/l/reasoningEngines/123"
+	if _, err := s.Create(t.Context(), &session.CreateRequest{AppName: name, UserID: "user"}); err != nil {
+		t.Errorf("Create() with the resource name of the Agent Engine error = %v", err)
+	}
+}
@@ -0,0 +1,201 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vertexai
+
+import (
+	"iter"
+	"maps"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/adk/internal/agentengine"
+	"google.golang.org/adk/session"
+)
+
+// vertexAISession is a session read from the Agent Engine.
+type vertexAISession struct {
+	appName   string
+	userID    string
+	sessionID string
+
+	// guards all mutable fields
+	mu        sync.RWMutex
+	events    []*session.Event
+	state     map[string]any
+	updatedAt time.Time
+}
+
+func newSession(appName string, s *apiSession, evs []*session.Event) *vertexAISession {
+	state := make(map[string]any, len(s.SessionState))
+	maps.Copy(state, s.SessionState)
+	return &vertexAISession{
+		appName:   appName,
+		userID:    s.UserID,
+		sessionID: agentengine.LastSegment(s.Name),
+		events:    evs,
+		state:     state,
+		updatedAt: s.UpdateTime,
+	}
+}
+
+func (s *vertexAISession) ID() string {
+	return s.sessionID
+}
+
+func (s *vertexAISession) AppName() string {
+	return s.appName
+}
+
+func (s *vertexAISession) UserID() string {
+	return s.userID
+}
+
+func (s *vertexAISession) State() session.State {
+	return &state{
+		mu:    &s.mu,
+		state: s.state,
+	}
+}
+
+// Events returns a snapshot of the events of the session: the events
+// appended after the call are not part of it.
+func (s *vertexAISession) Events() session.Events {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return events(s.events)
+}
+
+func (s *vertexAISession) LastUpdateTime() time.Time {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.updatedAt
+}
+
+// appendEvent appends the event to the session and applies its state delta,
+// and the temp: keys of the invocation trimmed from it.
+func (s *vertexAISession) appendEvent(event *session.Event, temp map[string]any) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	maps.Copy(s.state, event.Actions.StateDelta)
+	maps.Copy(s.state, temp)
+	s.events = append(s.events, event)
+	s.updatedAt = event.Timestamp
+	return nil
+}
+
+type events []*session.Event
+
+func (e events) All() iter.Seq[*session.Event] {
+	return func(yield func(*session.Event) bool) {
+		for _, event := range e {
+			if !yield(event) {
+				return
+			}
+		}
+	}
+}
+
+func (e events) Len() int {
+	return len(e)
+}
+
+func (e events) At(i int) *session.Event {
+	if i >= 0 && i < len(e) {
+		return e[i]
+	}
+	return nil
+}
+
+type state struct {
+	mu    *sync.RWMutex
+	state map[string]any
+}
+
+func (s *state) Get(key string) (any, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	val, ok := s.state[key]
+	if !ok {
+		return nil, session.ErrStateKeyNotExist
+	}
+
+	return val, nil
+}
+
+func (s *state) All() iter.Seq2[string, any] {
+	return func(yield func(key string, val any) bool) {
+		s.mu.RLock()
+
+		for k, v := range s.state {
+			s.mu.RUnlock()
+			if !yield(k, v) {
+				return
+			}
+			s.mu.RLock()
+		}
+
+		s.mu.RUnlock()
+	}
+}
+
+func (s *state) Set(key string, value any) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.state[key] = value
+	return nil
+}
+
+// withoutTempState returns the state without its temporary keys.
+func withoutTempState(state map[string]any) map[string]any {
+	if len(state) == 0 {
+		return state
+	}
+	filtered := make(map[string]any, len(state))
+	for key, value := range state {
+		if !strings.HasPrefix(key, session.KeyPrefixTemp) {
+			filtered[key] = value
+		}
+	}
+	return filtered
+}
+
+// trimTempDeltaState removes temporary state delta keys from the event.
+func trimTempDeltaState(event *session.Event) *session.Event {
+	if len(event.Actions.StateDelta) == 0 {
+		return event
+	}
+
+	filteredStateDelta := make(map[string]any)
+	for key, value := range event.Actions.StateDelta {
+		if !strings.HasPrefix(key, session.KeyPrefixTemp) {
+			filteredStateDelta[key] = value
+		}
+	}
+	event.Actions.StateDelta = filteredStateDelta
+
+	return event
+}
+
+var (
+	_ session.Session = (*vertexAISession)(nil)
+	_ session.Events  = (*events)(nil)
+	_ session.State   = (*state)(nil)
+)
@@ -14,10 +14,22 @@
 
 // Package telemetry allows to set up custom telemetry processors that the ADK events
 // will be emitted to.
+//
+// The runs are traced with OpenTelemetry spans, following the GenAI semantic
+// conventions: each run of a runner creates an "invocation" span, the parent
+// of an "invoke_agent <agent>" span for each agent run, itself the parent of
+// a "call_llm" span for each model call, with the model name, the finish
+// reasons and the token counts as attributes, which is the parent of the
+// "execute_tool <tool>" spans of the function calls of the response. The
+// spans are emitted to the processors registered with RegisterSpanProcessor,
+// and to the global tracer provider, so that an OTLP exporter set with
+// otel.SetTracerProvider receives them without further setup.
 package telemetry
 
 import (
+	"go.opentelemetry.io/otel/metric"
 	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
 
 	internaltelemetry "google.golang.org/adk/internal/telemetry"
 )
@@ -30,3 +42,45 @@ import (
 func RegisterSpanProcessor(processor sdktrace.SpanProcessor) {
 	internaltelemetry.AddSpanProcessor(processor)
 }
+
+// SetTracerProvider sets the provider of the spans of the tool runs: each run
+// of a function, agent or MCP tool creates a span named after the tool, a
+// child of the span of the context of the call, recording the duration,
+// whether the run succeeded, its error, and the sizes of the arguments and of
+// the result. A nil provider, the default, disables the spans.
+//
+// Unlike RegisterSpanProcessor, the global trace provider is not used for the
+// spans of the tool runs, they are opt-in.
+func SetTracerProvider(tp trace.TracerProvider) {
+	internaltelemetry.SetToolTracerProvider(tp)
+}
+
+// SetMeterProvider sets the provider of the metrics of the tool runs: the
+// counter adk.tool.invocations and the histogram adk.tool.duration, in
+// seconds, both with the tool name and whether the run succeeded as
+// attributes, and the counter adk.model.retries of the retried model calls,
+// see retrymodel.WithRetry, with the model name as attribute. A nil provider,
+// the default, disables the metrics.
+func SetMeterProvider(mp metric.MeterProvider) {
+	internaltelemetry.SetToolMeterProvider(mp)
+}
+
+// ArgsRedactor returns the arguments of a call of the tool to record in the
+// telemetry. It must not modify args.
+type ArgsRedactor = internaltelemetry.ArgsRedactor
+
+// SetArgsRedactor sets the redactor of the tool arguments recorded in the
+// telemetry, e.g. to mask secrets:
+//
+//	telemetry.SetArgsRedactor(func(toolName string, args map[string]any) map[string]any {
+//		redacted := maps.Clone(args)
+//		if _, ok := redacted["api_key"]; ok {
+//			redacted["api_key"] = "<redacted>"
+//		}
+//		return redacted
+//	})
+//
+// A nil redactor, the default, records the arguments as they are.
+func SetArgsRedactor(redact ArgsRedactor) {
+	internaltelemetry.SetToolArgsRedactor(redact)
+}
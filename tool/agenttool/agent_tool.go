@@ -27,6 +27,7 @@ import (
 	"google.golang.org/adk/agent"
 	"google.golang.org/adk/artifact"
 	"google.golang.org/adk/internal/llminternal"
+	"google.golang.org/adk/internal/toolinternal"
 	"google.golang.org/adk/internal/utils"
 	"google.golang.org/adk/memory"
 	"google.golang.org/adk/model"
@@ -81,7 +82,8 @@ func (t *agentTool) IsLongRunning() bool {
 // Declaration returns the function declaration for the wrapped agent.
 // It generates a function declaration based on the agent's input schema.
 // If the agent does not have an input schema, a default schema with a
-// "request" string parameter is used.
+// "request" string parameter is used. The response schema is the output
+// schema of the agent, if any, whose final response is then the result.
 func (t *agentTool) Declaration() *genai.FunctionDeclaration {
 	decl := &genai.FunctionDeclaration{
 		Name:        t.Name(),
@@ -97,6 +99,7 @@ func (t *agentTool) Declaration() *genai.FunctionDeclaration {
 			return nil
 		}
 		agentInputSchema = llminternal.Reveal(internalLlmAgent).InputSchema
+		decl.Response = llminternal.Reveal(internalLlmAgent).OutputSchema
 	}
 
 	if agentInputSchema != nil {
@@ -118,6 +121,12 @@ func (t *agentTool) Declaration() *genai.FunctionDeclaration {
 // It creates a new session for the sub-agent, runs the agent, and returns
 // the final result.
 func (t *agentTool) Run(toolCtx tool.Context, args any) (map[string]any, error) {
+	return toolinternal.TraceRun(toolCtx, t.Name(), args, func(toolCtx tool.Context) (map[string]any, error) {
+		return t.run(toolCtx, args)
+	})
+}
+
+func (t *agentTool) run(toolCtx tool.Context, args any) (map[string]any, error) {
 	margs, ok := args.(map[string]any)
 	if !ok {
 		return nil, fmt.Errorf("agentTool expects map[string]any arguments, got %T", args)
@@ -201,16 +210,25 @@ func (t *agentTool) Run(toolCtx tool.Context, args any) (map[string]any, error)
 		StreamingMode: agent.StreamingModeSSE,
 	})
 
+	// The sub-agent runs with the context of the tool call: once it is
+	// canceled, the sub-agent is stopped at the next event and the call fails,
+	// even if the sub-agent ignores the cancellation.
 	var lastEvent *session.Event
 	for event, err := range eventCh {
 		if err != nil {
 			return nil, fmt.Errorf("error during execution of sub-agent %s: %w", t.agent.Name(), err)
 		}
+		if err := toolCtx.Err(); err != nil {
+			return nil, fmt.Errorf("execution of sub-agent %s canceled: %w", t.agent.Name(), err)
+		}
 		if event.LLMResponse.Content != nil {
 			lastEvent = event
 		}
 	}
 
+	if err := toolCtx.Err(); err != nil {
+		return nil, fmt.Errorf("execution of sub-agent %s canceled: %w", t.agent.Name(), err)
+	}
 	if lastEvent == nil {
 		return map[string]any{}, nil
 	}
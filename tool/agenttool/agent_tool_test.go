@@ -15,6 +15,8 @@
 package agenttool_test
 
 import (
+	"context"
+	"errors"
 	"log"
 	"testing"
 
@@ -309,6 +311,34 @@ func TestAgentTool_Run_SkipSummarization(t *testing.T) {
 	}
 }
 
+func TestAgentTool_Run_Canceled(t *testing.T) {
+	testLLM := &testutil.MockModel{
+		Responses: []*genai.Content{
+			genai.NewContentFromText("test response", genai.RoleModel),
+		},
+	}
+	agent := createAgentWithModel(t, nil, nil, testLLM)
+	sessionService := session.InMemoryService()
+	createResponse, err := sessionService.Create(t.Context(), &session.CreateRequest{AppName: "testApp", UserID: "testUser"})
+	if err != nil {
+		t.Fatalf("Failed to create session: %v", err)
+	}
+	ctx, cancel := context.WithCancel(t.Context())
+	cancel()
+	toolCtx := toolinternal.NewToolContext(icontext.NewInvocationContext(ctx, icontext.InvocationContextParams{
+		Session: sessioninternal.NewMutableSession(sessionService, createResponse.Session),
+	}), "", &session.EventActions{})
+
+	toolImpl, ok := agenttool.New(agent, nil).(toolinternal.FunctionTool)
+	if !ok {
+		t.Fatal("agentTool does not implement FunctionTool")
+	}
+	// The mock model ignores the cancellation, the agent tool does not.
+	if _, err := toolImpl.Run(toolCtx, map[string]any{"request": "magic"}); !errors.Is(err, context.Canceled) {
+		t.Errorf("Run() with a canceled context error = %v, want context.Canceled", err)
+	}
+}
+
 func createAgent(t *testing.T, inputSchema, outputSchema *genai.Schema) agent.Agent {
 	t.Helper()
 
@@ -369,3 +399,36 @@ func createToolContext(t *testing.T, testAgent agent.Agent) tool.Context {
 
 	return toolinternal.NewToolContext(ctx, "", &session.EventActions{})
 }
+
+func TestAgentTool_DeclarationWithOutputSchema(t *testing.T) {
+	outputSchema := &genai.Schema{
+		Type: "OBJECT",
+		Properties: map[string]*genai.Schema{
+			"answer": {Type: "INTEGER"},
+		},
+		Required: []string{"answer"},
+	}
+	agent := createAgent(t, nil, outputSchema)
+	toolImpl, ok := agenttool.New(agent, nil).(toolinternal.FunctionTool)
+	if !ok {
+		t.Fatal("agentTool does not implement FunctionTool")
+	}
+
+	decl := toolImpl.Declaration()
+
+	wantDecl := &genai.FunctionDeclaration{
+		Name:        "math_agent",
+		Description: "Solves math problems.",
+		Parameters: &genai.Schema{
+			Type: "OBJECT",
+			Properties: map[string]*genai.Schema{
+				"request": {Type: "STRING"},
+			},
+			Required: []string{"request"},
+		},
+		Response: outputSchema,
+	}
+	if diff := cmp.Diff(wantDecl, decl); diff != "" {
+		t.Errorf("Declaration() returned diff (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,341 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bigquerytool
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"google.golang.org/api/bigquery/v2"
+
+	"google.golang.org/adk/tool"
+)
+
+// ListDatasetsArgs are the arguments of the "list_dataset_ids" tool.
+type ListDatasetsArgs struct {
+	ProjectID string `json:"project_id,omitempty" jsonschema:"the project of the datasets; defaults to the project of the toolset"`
+}
+
+// ListDatasetsResult is the result of the "list_dataset_ids" tool.
+type ListDatasetsResult struct {
+	DatasetIDs []string `json:"dataset_ids"`
+}
+
+func (ts *Toolset) listDatasets(ctx tool.Context, svc *bigquery.Service, args ListDatasetsArgs) (ListDatasetsResult, error) {
+	result := ListDatasetsResult{DatasetIDs: []string{}}
+	err := svc.Datasets.List(ts.project(args.ProjectID)).Pages(ctx, func(page *bigquery.DatasetList) error {
+		for _, ds := range page.Datasets {
+			result.DatasetIDs = append(result.DatasetIDs, ds.DatasetReference.DatasetId)
+		}
+		return nil
+	})
+	if err != nil {
+		return ListDatasetsResult{}, fmt.Errorf("failed to list datasets: %w", err)
+	}
+	return result, nil
+}
+
+// ListTablesArgs are the arguments of the "list_table_ids" tool.
+type ListTablesArgs struct {
+	ProjectID string `json:"project_id,omitempty" jsonschema:"the project of the dataset; defaults to the project of the toolset"`
+	DatasetID string `json:"dataset_id" jsonschema:"the dataset of the tables"`
+}
+
+// ListTablesResult is the result of the "list_table_ids" tool.
+type ListTablesResult struct {
+	TableIDs []string `json:"table_ids"`
+}
+
+func (ts *Toolset) listTables(ctx tool.Context, svc *bigquery.Service, args ListTablesArgs) (ListTablesResult, error) {
+	result := ListTablesResult{TableIDs: []string{}}
+	err := svc.Tables.List(ts.project(args.ProjectID), args.DatasetID).Pages(ctx, func(page *bigquery.TableList) error {
+		for _, t := range page.Tables {
+			result.TableIDs = append(result.TableIDs, t.TableReference.TableId)
+		}
+		return nil
+	})
+	if err != nil {
+		return ListTablesResult{}, fmt.Errorf("failed to list tables: %w", err)
+	}
+	return result, nil
+}
+
+// TableInfoArgs are the arguments of the "get_table_info" tool.
+type TableInfoArgs struct {
+	ProjectID string `json:"project_id,omitempty" jsonschema:"the project of the dataset; defaults to the project of the toolset"`
+	DatasetID string `json:"dataset_id" jsonschema:"the dataset of the table"`
+	TableID   string `json:"table_id" jsonschema:"the table"`
+}
+
+// TableInfo is the result of the "get_table_info" tool.
+type TableInfo struct {
+	Description string   `json:"description,omitempty"`
+	Type        string   `json:"type,omitempty"`
+	NumRows     uint64   `json:"num_rows"`
+	NumBytes    int64    `json:"num_bytes"`
+	Schema      []*Field `json:"schema"`
+}
+
+// Field is a column of a table, or a field of a column of type RECORD.
+type Field struct {
+	Name        string   `json:"name"`
+	Type        string   `json:"type"`
+	Mode        string   `json:"mode,omitempty"`
+	Description string   `json:"description,omitempty"`
+	Fields      []*Field `json:"fields,omitempty"`
+}
+
+func (ts *Toolset) getTableInfo(ctx tool.Context, svc *bigquery.Service, args TableInfoArgs) (TableInfo, error) {
+	table, err := svc.Tables.Get(ts.project(args.ProjectID), args.DatasetID, args.TableID).Context(ctx).Do()
+	if err != nil {
+		return TableInfo{}, fmt.Errorf("failed to get table: %w", err)
+	}
+	info := TableInfo{
+		Description: table.Description,
+		Type:        table.Type,
+		NumRows:     table.NumRows,
+		NumBytes:    table.NumBytes,
+		Schema:      []*Field{},
+	}
+	if table.Schema != nil {
+		info.Schema = fields(table.Schema.Fields)
+	}
+	return info, nil
+}
+
+func fields(schema []*bigquery.TableFieldSchema) []*Field {
+	var fs []*Field
+	for _, f := range schema {
+		fs = append(fs, &Field{
+			Name:        f.Name,
+			Type:        f.Type,
+			Mode:        f.Mode,
+			Description: f.Description,
+			Fields:      fields(f.Fields),
+		})
+	}
+	return fs
+}
+
+// QueryArgs are the arguments of the "execute_sql" and
+// "estimate_query_cost" tools.
+type QueryArgs struct {
+	Query      string           `json:"query" jsonschema:"the GoogleSQL query"`
+	Parameters []QueryParameter `json:"parameters,omitempty" jsonschema:"the named parameters of the query"`
+}
+
+// QueryParameter is a named parameter of a query, referenced as @name.
+type QueryParameter struct {
+	Name  string `json:"name" jsonschema:"the name of the parameter, without @"`
+	Type  string `json:"type,omitempty" jsonschema:"the GoogleSQL type of the parameter, e.g. STRING, INT64, FLOAT64, BOOL, DATE or TIMESTAMP; defaults to STRING"`
+	Value string `json:"value" jsonschema:"the value of the parameter, e.g. 42 or 2024-01-31"`
+}
+
+// QueryResult is the result of the "execute_sql" tool.
+type QueryResult struct {
+	Columns []string         `json:"columns"`
+	Rows    []map[string]any `json:"rows"`
+	// TotalRows is the number of rows of the result, of which at most
+	// Config.MaxRows are returned.
+	TotalRows uint64 `json:"total_rows"`
+	// Truncated reports whether rows were left out.
+	Truncated bool `json:"truncated,omitempty"`
+	// AffectedRows is the number of rows modified by a DML statement.
+	AffectedRows int64 `json:"affected_rows,omitempty"`
+}
+
+// CostEstimate is the result of the "estimate_query_cost" tool.
+type CostEstimate struct {
+	StatementType    string  `json:"statement_type"`
+	BytesProcessed   int64   `json:"bytes_processed"`
+	EstimatedCostUSD float64 `json:"estimated_cost_usd"`
+}
+
+func (ts *Toolset) executeSQL(ctx tool.Context, svc *bigquery.Service, args QueryArgs) (QueryResult, error) {
+	if !ts.cfg.AllowWrites {
+		stats, err := ts.dryRun(ctx, svc, args)
+		if err != nil {
+			return QueryResult{}, err
+		}
+		if stats.StatementType != "SELECT" {
+			return QueryResult{}, &tool.ToolError{
+				Code:    "write_not_allowed",
+				Message: fmt.Sprintf("only SELECT statements are allowed, got a %s statement", stats.StatementType),
+			}
+		}
+	}
+
+	req := &bigquery.QueryRequest{
+		Query:              args.Query,
+		UseLegacySql:       new(bool),
+		Location:           ts.cfg.Location,
+		MaxResults:         int64(ts.cfg.MaxRows),
+		MaximumBytesBilled: ts.cfg.MaxBytesBilled,
+	}
+	req.ParameterMode, req.QueryParameters = queryParameters(args.Parameters)
+	resp, err := svc.Jobs.Query(ts.cfg.ProjectID, req).Context(ctx).Do()
+	if err != nil {
+		return QueryResult{}, fmt.Errorf("failed to run query: %w", err)
+	}
+	schema, rows, totalRows := resp.Schema, resp.Rows, resp.TotalRows
+	// The query did not complete within the timeout of the request: its
+	// results are polled until it does.
+	for complete := resp.JobComplete; !complete; {
+		ref := resp.JobReference
+		results, err := svc.Jobs.GetQueryResults(ref.ProjectId, ref.JobId).Location(ref.Location).MaxResults(int64(ts.cfg.MaxRows)).Context(ctx).Do()
+		if err != nil {
+			return QueryResult{}, fmt.Errorf("failed to get query results: %w", err)
+		}
+		complete, schema, rows, totalRows = results.JobComplete, results.Schema, results.Rows, results.TotalRows
+	}
+
+	result := QueryResult{
+		Columns:      []string{},
+		Rows:         []map[string]any{},
+		TotalRows:    totalRows,
+		AffectedRows: resp.NumDmlAffectedRows,
+	}
+	if schema != nil {
+		for _, f := range schema.Fields {
+			result.Columns = append(result.Columns, f.Name)
+		}
+		for _, row := range rows {
+			if len(result.Rows) == ts.cfg.MaxRows {
+				break
+			}
+			result.Rows = append(result.Rows, recordValue(schema.Fields, row.F))
+		}
+	}
+	result.Truncated = uint64(len(result.Rows)) < totalRows
+	return result, nil
+}
+
+func (ts *Toolset) estimateCost(ctx tool.Context, svc *bigquery.Service, args QueryArgs) (CostEstimate, error) {
+	stats, err := ts.dryRun(ctx, svc, args)
+	if err != nil {
+		return CostEstimate{}, err
+	}
+	return CostEstimate{
+		StatementType:    stats.StatementType,
+		BytesProcessed:   stats.TotalBytesProcessed,
+		EstimatedCostUSD: float64(stats.TotalBytesProcessed) / (1 << 40) * ts.cfg.PricePerTiB,
+	}, nil
+}
+
+// dryRun validates the query without running it, and returns its
+// statistics: its statement type and the bytes it would process.
+func (ts *Toolset) dryRun(ctx tool.Context, svc *bigquery.Service, args QueryArgs) (*bigquery.JobStatistics2, error) {
+	query := &bigquery.JobConfigurationQuery{
+		Query:        args.Query,
+		UseLegacySql: new(bool),
+	}
+	query.ParameterMode, query.QueryParameters = queryParameters(args.Parameters)
+	job := &bigquery.Job{
+		Configuration: &bigquery.JobConfiguration{DryRun: true, Query: query},
+		JobReference:  &bigquery.JobReference{ProjectId: ts.cfg.ProjectID, Location: ts.cfg.Location},
+	}
+	job, err := svc.Jobs.Insert(ts.cfg.ProjectID, job).Context(ctx).Do()
+	if err != nil {
+		return nil, fmt.Errorf("failed to dry-run query: %w", err)
+	}
+	if job.Statistics == nil || job.Statistics.Query == nil {
+		return nil, errors.New("dry run of the query returned no statistics")
+	}
+	return job.Statistics.Query, nil
+}
+
+// queryParameters returns the parameter mode and the parameters of a query.
+func queryParameters(params []QueryParameter) (string, []*bigquery.QueryParameter) {
+	if len(params) == 0 {
+		return "", nil
+	}
+	qps := make([]*bigquery.QueryParameter, 0, len(params))
+	for _, p := range params {
+		typ := strings.ToUpper(p.Type)
+		if typ == "" {
+			typ = "STRING"
+		}
+		qps = append(qps, &bigquery.QueryParameter{
+			Name:           p.Name,
+			ParameterType:  &bigquery.QueryParameterType{Type: typ},
+			ParameterValue: &bigquery.QueryParameterValue{Value: p.Value},
+		})
+	}
+	return "NAMED", qps
+}
+
+// recordValue returns the value of a row, or of a RECORD column, whose
+// cells are those of the fields.
+func recordValue(fields []*bigquery.TableFieldSchema, cells []*bigquery.TableCell) map[string]any {
+	record := make(map[string]any, len(fields))
+	for i, f := range fields {
+		if i < len(cells) {
+			record[f.Name] = value(f, cells[i].V)
+		}
+	}
+	return record
+}
+
+// value returns the value of a cell of the field, as encoded by the API:
+// the scalars are strings, the REPEATED values lists of {"v": value} and the
+// RECORD values {"f": [{"v": value}, ...]}.
+func value(f *bigquery.TableFieldSchema, v any) any {
+	if v == nil {
+		return nil
+	}
+	if f.Mode == "REPEATED" {
+		elems, _ := v.([]any)
+		values := make([]any, 0, len(elems))
+		elem := *f
+		elem.Mode = ""
+		for _, e := range elems {
+			cell, _ := e.(map[string]any)
+			values = append(values, value(&elem, cell["v"]))
+		}
+		return values
+	}
+	switch f.Type {
+	case "RECORD", "STRUCT":
+		m, _ := v.(map[string]any)
+		raw, _ := m["f"].([]any)
+		cells := make([]*bigquery.TableCell, 0, len(raw))
+		for _, c := range raw {
+			cell, _ := c.(map[string]any)
+			cells = append(cells, &bigquery.TableCell{V: cell["v"]})
+		}
+		return recordValue(f.Fields, cells)
+	}
+	s, ok := v.(string)
+	if !ok {
+		return v
+	}
+	switch f.Type {
+	case "INTEGER", "INT64":
+		if n, err := strconv.ParseInt(s, 10, 64); err == nil {
+			return n
+		}
+	case "FLOAT", "FLOAT64":
+		if x, err := strconv.ParseFloat(s, 64); err == nil {
+			return x
+		}
+	case "BOOLEAN", "BOOL":
+		if b, err := strconv.ParseBool(s); err == nil {
+			return b
+		}
+	}
+	return s
+}
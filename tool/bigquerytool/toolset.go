@@ -0,0 +1,261 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package bigquerytool provides a toolset that lets the model explore and
+// query BigQuery. It exposes five tools:
+//
+//   - "list_dataset_ids" lists the datasets of a project.
+//   - "list_table_ids" lists the tables of a dataset.
+//   - "get_table_info" returns the schema and the size of a table.
+//   - "execute_sql" runs a GoogleSQL query, with named parameters, and
+//     returns at most Config.MaxRows rows.
+//   - "estimate_query_cost" dry-runs a query and returns the bytes it would
+//     process and their on-demand cost.
+//
+// The queries are billed to Config.ProjectID. By default, only the SELECT
+// statements are executed: the other statements, e.g. DML, DDL or scripts,
+// are rejected before they run, unless Config.AllowWrites is set. A query
+// processing more than Config.MaxBytesBilled bytes fails without being
+// billed.
+//
+// # Credentials
+//
+// By default, the toolset uses the Application Default Credentials, or the
+// Config.ClientOptions, e.g. option.WithCredentialsFile. With
+// Config.AuthScheme, the calls use instead the OAuth2 credential of the
+// user, obtained with the auth flow of the tools, see package auth, e.g.
+//
+//	bigquerytool.New(ctx, bigquerytool.Config{
+//		ProjectID: "my-project",
+//		AuthScheme: &auth.AuthScheme{
+//			Type:             auth.OAuth2,
+//			AuthorizationURL: "https://accounts.google.com/o/oauth2/auth",
+//			TokenURL:         "https://oauth2.googleapis.com/token",
+//			Scopes:           []string{bigquery.BigqueryScope},
+//		},
+//		AuthCredential: &auth.AuthCredential{
+//			AuthType: auth.OAuth2,
+//			OAuth2:   &auth.OAuth2Auth{ClientID: clientID, ClientSecret: clientSecret},
+//		},
+//	})
+package bigquerytool
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"golang.org/x/oauth2"
+	"google.golang.org/api/bigquery/v2"
+	"google.golang.org/api/option"
+
+	"google.golang.org/adk/agent"
+	"google.golang.org/adk/auth"
+	"google.golang.org/adk/tool"
+	"google.golang.org/adk/tool/functiontool"
+)
+
+const (
+	defaultName    = "bigquery"
+	defaultMaxRows = 50
+	// defaultPricePerTiB is the on-demand price of the queries in the US
+	// multi-region, in USD per TiB processed.
+	defaultPricePerTiB = 6.25
+)
+
+// Config is the configuration of the toolset.
+type Config struct {
+	// Name of the toolset. Defaults to "bigquery".
+	Name string
+	// ProjectID is the project running and billed for the queries, and the
+	// default project of the datasets. Required.
+	ProjectID string
+	// Location is the location of the queries, e.g. "US". Defaults to the
+	// location of the datasets they reference.
+	Location string
+	// ClientOptions configure the BigQuery client, e.g. its credentials or
+	// its endpoint. Defaults to the Application Default Credentials.
+	ClientOptions []option.ClientOption
+	// AuthScheme, if set, makes the calls use the OAuth2 credential of the
+	// user, obtained with the auth flow of the tools for the scheme and
+	// AuthCredential, in place of the credentials of ClientOptions.
+	AuthScheme *auth.AuthScheme
+	// AuthCredential is the OAuth2 client of AuthScheme.
+	AuthCredential *auth.AuthCredential
+	// MaxRows is the maximum number of rows returned by a query. Defaults to
+	// 50.
+	MaxRows int
+	// MaxBytesBilled optionally limits the bytes billed for a query: the
+	// queries above the limit fail without being billed. Zero means no
+	// limit.
+	MaxBytesBilled int64
+	// AllowWrites lets the model run statements other than SELECT, e.g.
+	// INSERT or CREATE TABLE.
+	AllowWrites bool
+	// PricePerTiB is the price, in USD per TiB processed, used to estimate
+	// the cost of the queries. Defaults to 6.25, the on-demand price in the
+	// US multi-region.
+	PricePerTiB float64
+}
+
+// Toolset is the BigQuery toolset.
+type Toolset struct {
+	cfg   Config
+	tools []tool.Tool
+	// svc is the client of the calls, nil with an AuthScheme.
+	svc *bigquery.Service
+}
+
+// New creates a BigQuery toolset.
+func New(ctx context.Context, cfg Config) (*Toolset, error) {
+	if cfg.ProjectID == "" {
+		return nil, errors.New("project ID is required")
+	}
+	if cfg.AuthScheme != nil && cfg.AuthScheme.Type != auth.OAuth2 && cfg.AuthScheme.Type != auth.OpenIDConnect {
+		return nil, fmt.Errorf("unsupported auth scheme type %q, want OAuth2", cfg.AuthScheme.Type)
+	}
+	if cfg.Name == "" {
+		cfg.Name = defaultName
+	}
+	if cfg.MaxRows <= 0 {
+		cfg.MaxRows = defaultMaxRows
+	}
+	if cfg.PricePerTiB <= 0 {
+		cfg.PricePerTiB = defaultPricePerTiB
+	}
+
+	ts := &Toolset{cfg: cfg}
+	if cfg.AuthScheme == nil {
+		svc, err := bigquery.NewService(ctx, cfg.ClientOptions...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create BigQuery client: %w", err)
+		}
+		ts.svc = svc
+	}
+	tools, err := ts.newTools()
+	if err != nil {
+		return nil, fmt.Errorf("error creating BigQuery tools: %w", err)
+	}
+	ts.tools = tools
+	return ts, nil
+}
+
+// Name implements tool.Toolset.
+func (ts *Toolset) Name() string {
+	return ts.cfg.Name
+}
+
+// Tools implements tool.Toolset.
+func (ts *Toolset) Tools(agent.ReadonlyContext) ([]tool.Tool, error) {
+	return ts.tools, nil
+}
+
+// errWaitingForAuth is returned by service when the credential of the user
+// was requested.
+var errWaitingForAuth = fmt.Errorf("waiting for the user to authenticate: %w", tool.ErrAuthRequired)
+
+// service returns the client of the call: the one of the toolset, or one
+// with the credential of the user, which it requests if there is none yet.
+func (ts *Toolset) service(ctx tool.Context) (*bigquery.Service, error) {
+	if ts.svc != nil {
+		return ts.svc, nil
+	}
+	cfg := &auth.AuthConfig{AuthScheme: ts.cfg.AuthScheme, RawAuthCredential: ts.cfg.AuthCredential}
+	cred := ctx.GetAuthResponse(cfg)
+	if cred == nil || cred.OAuth2 == nil || cred.OAuth2.AccessToken == "" {
+		ctx.RequestCredential(cfg)
+		return nil, errWaitingForAuth
+	}
+	token := oauth2.StaticTokenSource(&oauth2.Token{AccessToken: cred.OAuth2.AccessToken})
+	opts := append(ts.cfg.ClientOptions[:len(ts.cfg.ClientOptions):len(ts.cfg.ClientOptions)], option.WithTokenSource(token))
+	svc, err := bigquery.NewService(ctx, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create BigQuery client: %w", err)
+	}
+	return svc, nil
+}
+
+// waitingForAuth is the result of the calls waiting for the credential of
+// the user.
+var waitingForAuth = map[string]any{"status": "waiting for the user to authenticate"}
+
+func (ts *Toolset) newTools() ([]tool.Tool, error) {
+	listDatasets, err := functiontool.New(functiontool.Config{
+		Name:        "list_dataset_ids",
+		Description: "Lists the IDs of the BigQuery datasets of a project.",
+	}, withAuth(ts, ts.listDatasets))
+	if err != nil {
+		return nil, err
+	}
+	listTables, err := functiontool.New(functiontool.Config{
+		Name:        "list_table_ids",
+		Description: "Lists the IDs of the tables of a BigQuery dataset.",
+	}, withAuth(ts, ts.listTables))
+	if err != nil {
+		return nil, err
+	}
+	getTableInfo, err := functiontool.New(functiontool.Config{
+		Name:        "get_table_info",
+		Description: "Returns the schema, the number of rows and the size of a BigQuery table.",
+	}, withAuth(ts, ts.getTableInfo))
+	if err != nil {
+		return nil, err
+	}
+	executeSQL, err := functiontool.New(functiontool.Config{
+		Name:        "execute_sql",
+		Description: ts.executeDescription(),
+	}, withAuth(ts, ts.executeSQL))
+	if err != nil {
+		return nil, err
+	}
+	estimateCost, err := functiontool.New(functiontool.Config{
+		Name:        "estimate_query_cost",
+		Description: "Dry-runs a GoogleSQL query, without running it, and returns the number of bytes it would process and its estimated on-demand cost in USD.",
+	}, withAuth(ts, ts.estimateCost))
+	if err != nil {
+		return nil, err
+	}
+	return []tool.Tool{listDatasets, listTables, getTableInfo, executeSQL, estimateCost}, nil
+}
+
+func (ts *Toolset) executeDescription() string {
+	desc := fmt.Sprintf("Runs a GoogleSQL query in BigQuery and returns at most %d rows. "+
+		"Reference the tables as `project.dataset.table`, and pass the values as named parameters, referenced as @name in the query.", ts.cfg.MaxRows)
+	if !ts.cfg.AllowWrites {
+		desc += " Only SELECT statements are allowed."
+	}
+	return desc
+}
+
+// withAuth returns the handler calling fn with the client of the call, and
+// reporting that it waits for the credential of the user if needed.
+func withAuth[TArgs, TResults any](ts *Toolset, fn func(tool.Context, *bigquery.Service, TArgs) (TResults, error)) functiontool.Func[TArgs, any] {
+	return func(ctx tool.Context, args TArgs) (any, error) {
+		svc, err := ts.service(ctx)
+		if errors.Is(err, errWaitingForAuth) {
+			return waitingForAuth, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		return fn(ctx, svc, args)
+	}
+}
+
+func (ts *Toolset) project(projectID string) string {
+	if projectID == "" {
+		return ts.cfg.ProjectID
+	}
+	return projectID
+}
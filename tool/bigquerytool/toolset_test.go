@@ -0,0 +1,326 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bigquerytool_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/api/bigquery/v2"
+	"google.golang.org/api/option"
+
+	"google.golang.org/adk/agent"
+	"google.golang.org/adk/auth"
+	icontext "google.golang.org/adk/internal/context"
+	"google.golang.org/adk/internal/sessioninternal"
+	"google.golang.org/adk/internal/toolinternal"
+	"google.golang.org/adk/session"
+	"google.golang.org/adk/tool"
+	"google.golang.org/adk/tool/bigquerytool"
+)
+
+// fakeBigQuery serves the endpoints of the BigQuery API used by the toolset.
+type fakeBigQuery struct {
+	mu      sync.Mutex
+	queries []*bigquery.QueryRequest
+	tokens  []string
+}
+
+func (f *fakeBigQuery) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	f.tokens = append(f.tokens, r.Header.Get("Authorization"))
+	f.mu.Unlock()
+	var resp any
+	switch r.Method + " " + r.URL.Path {
+	case "GET /projects/proj/datasets":
+		resp = map[string]any{"datasets": []any{
+			map[string]any{"datasetReference": map[string]any{"projectId": "proj", "datasetId": "sales"}},
+		}}
+	case "GET /projects/proj/datasets/sales/tables":
+		resp = map[string]any{"tables": []any{
+			map[string]any{"tableReference": map[string]any{"projectId": "proj", "datasetId": "sales", "tableId": "orders"}},
+		}}
+	case "GET /projects/proj/datasets/sales/tables/orders":
+		resp = map[string]any{
+			"type":     "TABLE",
+			"numRows":  "3",
+			"numBytes": "1024",
+			"schema":   map[string]any{"fields": ordersSchema},
+		}
+	case "POST /projects/proj/jobs":
+		var job bigquery.Job
+		if err := json.NewDecoder(r.Body).Decode(&job); err != nil || !job.Configuration.DryRun {
+			http.Error(w, "want a dry run", http.StatusBadRequest)
+			return
+		}
+		statementType := "SELECT"
+		if !strings.HasPrefix(job.Configuration.Query.Query, "SELECT") {
+			statementType = "DELETE"
+		}
+		resp = map[string]any{"statistics": map[string]any{"query": map[string]any{
+			"statementType":       statementType,
+			"totalBytesProcessed": "549755813888",
+		}}}
+	case "POST /projects/proj/queries":
+		var req bigquery.QueryRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		f.mu.Lock()
+		f.queries = append(f.queries, &req)
+		f.mu.Unlock()
+		resp = map[string]any{
+			"jobComplete": true,
+			"totalRows":   "3",
+			"schema":      map[string]any{"fields": ordersSchema},
+			"rows": []any{
+				map[string]any{"f": []any{
+					map[string]any{"v": "o1"},
+					map[string]any{"v": "42"},
+					map[string]any{"v": []any{map[string]any{"v": "gift"}, map[string]any{"v": "express"}}},
+					map[string]any{"v": map[string]any{"f": []any{map[string]any{"v": "Paris"}}}},
+				}},
+				map[string]any{"f": []any{
+					map[string]any{"v": "o2"},
+					map[string]any{"v": nil},
+					map[string]any{"v": []any{}},
+					map[string]any{"v": nil},
+				}},
+			},
+		}
+	default:
+		http.NotFound(w, r)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+var ordersSchema = []any{
+	map[string]any{"name": "id", "type": "STRING", "mode": "REQUIRED"},
+	map[string]any{"name": "amount", "type": "INTEGER"},
+	map[string]any{"name": "tags", "type": "STRING", "mode": "REPEATED"},
+	map[string]any{"name": "shipping", "type": "RECORD", "fields": []any{map[string]any{"name": "city", "type": "STRING"}}},
+}
+
+func newToolset(t *testing.T, cfg bigquerytool.Config) (*bigquerytool.Toolset, *fakeBigQuery) {
+	t.Helper()
+	fake := &fakeBigQuery{}
+	srv := httptest.NewServer(fake)
+	t.Cleanup(srv.Close)
+	cfg.ProjectID = "proj"
+	cfg.ClientOptions = append(cfg.ClientOptions, option.WithEndpoint(srv.URL+"/"))
+	if cfg.AuthScheme == nil {
+		cfg.ClientOptions = append(cfg.ClientOptions, option.WithoutAuthentication())
+	}
+	ts, err := bigquerytool.New(t.Context(), cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return ts, fake
+}
+
+// toolContext returns the context of a call in a new session with the
+// state.
+func toolContext(t *testing.T, state map[string]any) tool.Context {
+	t.Helper()
+	service := session.InMemoryService()
+	resp, err := service.Create(t.Context(), &session.CreateRequest{AppName: "app", UserID: "user", State: state})
+	if err != nil {
+		t.Fatal(err)
+	}
+	a, err := agent.New(agent.Config{Name: "data_agent"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := icontext.NewInvocationContext(t.Context(), icontext.InvocationContextParams{
+		Agent:   a,
+		Session: sessioninternal.NewMutableSession(service, resp.Session),
+	})
+	return toolinternal.NewToolContext(ctx, "call", &session.EventActions{StateDelta: map[string]any{}})
+}
+
+func run(t *testing.T, ctx tool.Context, ts *bigquerytool.Toolset, name string, args map[string]any) (map[string]any, error) {
+	t.Helper()
+	tools, err := ts.Tools(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tl := range tools {
+		if tl.Name() == name {
+			return tl.(toolinternal.FunctionTool).Run(ctx, args)
+		}
+	}
+	t.Fatalf("no tool %q", name)
+	return nil, nil
+}
+
+func TestToolset_Metadata(t *testing.T) {
+	ts, _ := newToolset(t, bigquerytool.Config{})
+	ctx := toolContext(t, nil)
+
+	for _, tc := range []struct {
+		tool string
+		args map[string]any
+		want map[string]any
+	}{
+		{
+			tool: "list_dataset_ids",
+			args: map[string]any{},
+			want: map[string]any{"dataset_ids": []any{"sales"}},
+		},
+		{
+			tool: "list_table_ids",
+			args: map[string]any{"dataset_id": "sales"},
+			want: map[string]any{"table_ids": []any{"orders"}},
+		},
+		{
+			tool: "get_table_info",
+			args: map[string]any{"dataset_id": "sales", "table_id": "orders"},
+			want: map[string]any{
+				"type":      "TABLE",
+				"num_rows":  float64(3),
+				"num_bytes": float64(1024),
+				"schema": []any{
+					map[string]any{"name": "id", "type": "STRING", "mode": "REQUIRED"},
+					map[string]any{"name": "amount", "type": "INTEGER"},
+					map[string]any{"name": "tags", "type": "STRING", "mode": "REPEATED"},
+					map[string]any{"name": "shipping", "type": "RECORD", "fields": []any{map[string]any{"name": "city", "type": "STRING"}}},
+				},
+			},
+		},
+	} {
+		t.Run(tc.tool, func(t *testing.T) {
+			got, err := run(t, ctx, ts, tc.tool, tc.args)
+			if err != nil {
+				t.Fatalf("Run() error = %v", err)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("Run() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestToolset_ExecuteSQL(t *testing.T) {
+	ts, fake := newToolset(t, bigquerytool.Config{MaxRows: 2, MaxBytesBilled: 1 << 30})
+	ctx := toolContext(t, nil)
+
+	got, err := run(t, ctx, ts, "execute_sql", map[string]any{
+		"query":      "SELECT * FROM sales.orders WHERE amount > @min",
+		"parameters": []any{map[string]any{"name": "min", "type": "int64", "value": "10"}},
+	})
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	want := map[string]any{
+		"columns": []any{"id", "amount", "tags", "shipping"},
+		"rows": []any{
+			map[string]any{"id": "o1", "amount": float64(42), "tags": []any{"gift", "express"}, "shipping": map[string]any{"city": "Paris"}},
+			map[string]any{"id": "o2", "amount": nil, "tags": []any{}, "shipping": nil},
+		},
+		"total_rows": float64(3),
+		"truncated":  true,
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Run() mismatch (-want +got):\n%s", diff)
+	}
+	if len(fake.queries) != 1 {
+		t.Fatalf("got %d queries, want 1", len(fake.queries))
+	}
+	req := fake.queries[0]
+	if req.MaxResults != 2 || req.MaximumBytesBilled != 1<<30 || req.UseLegacySql == nil || *req.UseLegacySql || req.ParameterMode != "NAMED" {
+		t.Errorf("query request = %+v, want the limits of the toolset, GoogleSQL and named parameters", req)
+	}
+	if p := req.QueryParameters[0]; p.Name != "min" || p.ParameterType.Type != "INT64" || p.ParameterValue.Value != "10" {
+		t.Errorf("query parameter = %+v, want min INT64 10", p)
+	}
+
+	// The statements other than SELECT are rejected before they run.
+	_, err = run(t, ctx, ts, "execute_sql", map[string]any{"query": "DELETE FROM sales.orders WHERE TRUE"})
+	var toolErr *tool.ToolError
+	if !errors.As(err, &toolErr) || toolErr.Code != "write_not_allowed" {
+		t.Errorf("Run() of a DELETE error = %v, want write_not_allowed", err)
+	}
+	if len(fake.queries) != 1 {
+		t.Errorf("got %d queries, want the DELETE not to run", len(fake.queries))
+	}
+}
+
+func TestToolset_EstimateQueryCost(t *testing.T) {
+	ts, fake := newToolset(t, bigquerytool.Config{})
+	got, err := run(t, toolContext(t, nil), ts, "estimate_query_cost", map[string]any{"query": "SELECT * FROM sales.orders"})
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	want := map[string]any{"statement_type": "SELECT", "bytes_processed": float64(1 << 39), "estimated_cost_usd": 3.125}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Run() mismatch (-want +got):\n%s", diff)
+	}
+	if len(fake.queries) != 0 {
+		t.Errorf("got %d queries, want none", len(fake.queries))
+	}
+}
+
+func TestToolset_UserCredential(t *testing.T) {
+	scheme := &auth.AuthScheme{
+		Type:             auth.OAuth2,
+		AuthorizationURL: "https://accounts.example.com/auth",
+		TokenURL:         "https://accounts.example.com/token",
+		Scopes:           []string{bigquery.BigqueryScope},
+	}
+	client := &auth.AuthCredential{AuthType: auth.OAuth2, OAuth2: &auth.OAuth2Auth{ClientID: "id", ClientSecret: "secret"}}
+	ts, fake := newToolset(t, bigquerytool.Config{AuthScheme: scheme, AuthCredential: client})
+
+	// Without a credential, the credential of the user is requested.
+	ctx := toolContext(t, nil)
+	got, err := run(t, ctx, ts, "list_dataset_ids", map[string]any{})
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if diff := cmp.Diff(map[string]any{"status": "waiting for the user to authenticate"}, got); diff != "" {
+		t.Errorf("Run() mismatch (-want +got):\n%s", diff)
+	}
+	if ctx.Actions().RequestedAuthConfigs["call"] == nil {
+		t.Error("Run() did not request the credential")
+	}
+	if len(fake.tokens) != 0 {
+		t.Errorf("got %d API calls, want none", len(fake.tokens))
+	}
+
+	// With the credential of the user, the calls use its token.
+	cfg := &auth.AuthConfig{AuthScheme: scheme, RawAuthCredential: client}
+	key, err := cfg.Key()
+	if err != nil {
+		t.Fatal(err)
+	}
+	cred, err := toolinternal.CredentialStateValue(&auth.AuthCredential{AuthType: auth.OAuth2, OAuth2: &auth.OAuth2Auth{AccessToken: "user-token"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := run(t, toolContext(t, map[string]any{key: cred}), ts, "list_dataset_ids", map[string]any{}); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if diff := cmp.Diff([]string{"Bearer user-token"}, fake.tokens); diff != "" {
+		t.Errorf("Authorization headers mismatch (-want +got):\n%s", diff)
+	}
+}
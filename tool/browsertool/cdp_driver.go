@@ -0,0 +1,633 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package browsertool
+
+import (
+	"bufio"
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"golang.org/x/net/websocket"
+)
+
+const (
+	defaultWindowWidth  = 1280
+	defaultWindowHeight = 800
+	browserStartTimeout = 20 * time.Second
+	loadPollInterval    = 100 * time.Millisecond
+)
+
+// browserExecutables are the names of the browser executables looked up in
+// the PATH, in order.
+var browserExecutables = []string{"chromium", "chromium-browser", "google-chrome", "google-chrome-stable", "headless_shell"}
+
+// CDPDriverConfig is the configuration of the driver returned by
+// [NewCDPDriver].
+type CDPDriverConfig struct {
+	// ExecPath is the path of the Chrome or Chromium executable started by
+	// the driver. Defaults to the first of chromium, chromium-browser,
+	// google-chrome, google-chrome-stable and headless_shell found in the
+	// PATH.
+	ExecPath string
+	// Args are additional command-line flags of the browser.
+	Args []string
+	// DebuggerURL is the DevTools WebSocket URL of a running browser, e.g.
+	// "ws://127.0.0.1:9222/devtools/browser/<id>", to use rather than
+	// starting one. The browser must accept the connection, e.g. with
+	// --remote-allow-origins=*.
+	DebuggerURL string
+	// WindowWidth and WindowHeight are the size of the viewport of the
+	// pages, and of their screenshots. Default to 1280x800.
+	WindowWidth  int
+	WindowHeight int
+}
+
+// CDPDriver is a [Driver] running a headless Chrome or Chromium through the
+// Chrome DevTools Protocol. Unlike the driver of NewHTTPDriver, its pages run
+// JavaScript, and support screenshots, see [ScreenshotPage]. It is
+// experimental.
+//
+// The browser is started with the first page, each page being a tab of its
+// own. Close stops it.
+type CDPDriver struct {
+	cfg CDPDriverConfig
+
+	mu         sync.Mutex
+	conn       *cdpConn
+	cmd        *exec.Cmd
+	dataDir    string
+	startedURL string
+}
+
+// NewCDPDriver returns a driver running a headless browser.
+func NewCDPDriver(cfg CDPDriverConfig) *CDPDriver {
+	cfg.WindowWidth = cmp.Or(cfg.WindowWidth, defaultWindowWidth)
+	cfg.WindowHeight = cmp.Or(cfg.WindowHeight, defaultWindowHeight)
+	return &CDPDriver{cfg: cfg}
+}
+
+// NewPage implements Driver.
+func (d *CDPDriver) NewPage(ctx context.Context, opts PageOptions) (Page, error) {
+	conn, err := d.connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var target struct {
+		TargetID string `json:"targetId"`
+	}
+	if err := conn.call(ctx, "", "Target.createTarget", map[string]any{"url": "about:blank"}, &target); err != nil {
+		return nil, err
+	}
+	var attached struct {
+		SessionID string `json:"sessionId"`
+	}
+	if err := conn.call(ctx, "", "Target.attachToTarget", map[string]any{"targetId": target.TargetID, "flatten": true}, &attached); err != nil {
+		return nil, err
+	}
+	p := &cdpPage{conn: conn, targetID: target.TargetID, sessionID: attached.SessionID, opts: opts}
+	conn.listen(p.sessionID, p.onEvent)
+	err = p.call(ctx, "Emulation.setDeviceMetricsOverride", map[string]any{
+		"width":             d.cfg.WindowWidth,
+		"height":            d.cfg.WindowHeight,
+		"deviceScaleFactor": 1,
+		"mobile":            false,
+	}, nil)
+	// The documents the page loads, in frames included, are checked as they
+	// are requested.
+	if err == nil && opts.CheckURL != nil {
+		err = p.call(ctx, "Fetch.enable", map[string]any{
+			"patterns": []map[string]any{{"urlPattern": "*", "resourceType": "Document", "requestStage": "Request"}},
+		}, nil)
+	}
+	if err != nil {
+		p.Close()
+		return nil, err
+	}
+	return p, nil
+}
+
+// Close closes the connection to the browser, and stops the browser if the
+// driver started it.
+func (d *CDPDriver) Close() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	var errs []error
+	if d.conn != nil {
+		errs = append(errs, d.conn.close())
+		d.conn = nil
+	}
+	if d.cmd != nil {
+		d.cmd.Process.Kill()
+		d.cmd.Wait()
+		d.cmd, d.startedURL = nil, ""
+	}
+	if d.dataDir != "" {
+		errs = append(errs, os.RemoveAll(d.dataDir))
+		d.dataDir = ""
+	}
+	return errors.Join(errs...)
+}
+
+// connect returns the connection to the browser, starting it if needed.
+func (d *CDPDriver) connect(ctx context.Context) (*cdpConn, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.conn != nil && !d.conn.closed() {
+		return d.conn, nil
+	}
+	debuggerURL := d.cfg.DebuggerURL
+	if debuggerURL == "" {
+		if d.cmd == nil {
+			if err := d.start(ctx); err != nil {
+				return nil, err
+			}
+		}
+		debuggerURL = d.startedURL
+	}
+	conn, err := dialCDP(ctx, debuggerURL)
+	if err != nil {
+		return nil, err
+	}
+	d.conn = conn
+	return conn, nil
+}
+
+// start starts the browser, and records its DevTools URL.
+func (d *CDPDriver) start(ctx context.Context) error {
+	path := d.cfg.ExecPath
+	if path == "" {
+		for _, name := range browserExecutables {
+			if p, err := exec.LookPath(name); err == nil {
+				path = p
+				break
+			}
+		}
+		if path == "" {
+			return fmt.Errorf("no browser found in the PATH among %s", strings.Join(browserExecutables, ", "))
+		}
+	}
+	dataDir, err := os.MkdirTemp("", "adk-browser-")
+	if err != nil {
+		return fmt.Errorf("failed to create the browser profile directory: %w", err)
+	}
+	args := append([]string{
+		"--headless=new",
+		"--remote-debugging-port=0",
+		"--remote-allow-origins=*",
+		"--user-data-dir=" + dataDir,
+		"--no-first-run",
+		"--no-default-browser-check",
+		"--disable-gpu",
+		fmt.Sprintf("--window-size=%d,%d", d.cfg.WindowWidth, d.cfg.WindowHeight),
+	}, d.cfg.Args...)
+	cmd := exec.Command(path, append(args, "about:blank")...)
+	stderr, err := cmd.StderrPipe()
+	if err != nil {
+		os.RemoveAll(dataDir)
+		return err
+	}
+	if err := cmd.Start(); err != nil {
+		os.RemoveAll(dataDir)
+		return fmt.Errorf("failed to start the browser: %w", err)
+	}
+
+	// The browser prints its DevTools URL on its standard error.
+	found := make(chan string, 1)
+	go func() {
+		scanner := bufio.NewScanner(stderr)
+		for scanner.Scan() {
+			if u, ok := strings.CutPrefix(scanner.Text(), "DevTools listening on "); ok {
+				found <- strings.TrimSpace(u)
+				break
+			}
+		}
+		close(found)
+		io.Copy(io.Discard, stderr)
+	}()
+	timer := time.NewTimer(browserStartTimeout)
+	defer timer.Stop()
+	var u string
+	select {
+	case u = <-found:
+	case <-timer.C:
+	case <-ctx.Done():
+	}
+	if u == "" {
+		cmd.Process.Kill()
+		cmd.Wait()
+		os.RemoveAll(dataDir)
+		return errors.New("the browser did not start")
+	}
+	d.cmd, d.dataDir, d.startedURL = cmd, dataDir, u
+	return nil
+}
+
+// cdpConn is a connection to the DevTools endpoint of a browser, shared by
+// its pages, whose messages carry the session ID of their page.
+type cdpConn struct {
+	ws     *websocket.Conn
+	nextID atomic.Int64
+	sendMu sync.Mutex
+	done   chan struct{}
+
+	mu        sync.Mutex
+	pending   map[int64]chan *cdpMessage
+	listeners map[string]func(method string, params json.RawMessage)
+}
+
+// cdpMessage is a message of the protocol: a command, its response or an
+// event.
+type cdpMessage struct {
+	ID        int64           `json:"id,omitempty"`
+	SessionID string          `json:"sessionId,omitempty"`
+	Method    string          `json:"method,omitempty"`
+	Params    any             `json:"params,omitempty"`
+	Result    json.RawMessage `json:"result,omitempty"`
+	Error     *cdpError       `json:"error,omitempty"`
+}
+
+type cdpError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func dialCDP(ctx context.Context, debuggerURL string) (*cdpConn, error) {
+	cfg, err := websocket.NewConfig(debuggerURL, "http://localhost")
+	if err != nil {
+		return nil, fmt.Errorf("invalid DevTools URL %q: %w", debuggerURL, err)
+	}
+	ws, err := cfg.DialContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to the browser: %w", err)
+	}
+	ws.MaxPayloadBytes = 64 << 20
+	c := &cdpConn{
+		ws:        ws,
+		done:      make(chan struct{}),
+		pending:   make(map[int64]chan *cdpMessage),
+		listeners: make(map[string]func(string, json.RawMessage)),
+	}
+	go c.read()
+	return c, nil
+}
+
+// read dispatches the responses and the events, until the connection closes.
+func (c *cdpConn) read() {
+	defer close(c.done)
+	for {
+		var msg struct {
+			cdpMessage
+			Params json.RawMessage `json:"params,omitempty"`
+		}
+		if err := websocket.JSON.Receive(c.ws, &msg); err != nil {
+			return
+		}
+		c.mu.Lock()
+		if msg.ID != 0 {
+			if ch, ok := c.pending[msg.ID]; ok {
+				delete(c.pending, msg.ID)
+				ch <- &msg.cdpMessage
+			}
+			c.mu.Unlock()
+			continue
+		}
+		listener := c.listeners[msg.SessionID]
+		c.mu.Unlock()
+		if listener != nil && msg.Method != "" {
+			listener(msg.Method, msg.Params)
+		}
+	}
+}
+
+// call sends the command, and decodes its result into result, if not nil.
+func (c *cdpConn) call(ctx context.Context, sessionID, method string, params, result any) error {
+	id := c.nextID.Add(1)
+	ch := make(chan *cdpMessage, 1)
+	c.mu.Lock()
+	c.pending[id] = ch
+	c.mu.Unlock()
+	defer func() {
+		c.mu.Lock()
+		delete(c.pending, id)
+		c.mu.Unlock()
+	}()
+
+	c.sendMu.Lock()
+	err := websocket.JSON.Send(c.ws, &cdpMessage{ID: id, SessionID: sessionID, Method: method, Params: params})
+	c.sendMu.Unlock()
+	if err != nil {
+		return fmt.Errorf("%s: %w", method, err)
+	}
+	select {
+	case msg := <-ch:
+		if msg.Error != nil {
+			return fmt.Errorf("%s: %s", method, msg.Error.Message)
+		}
+		if result != nil && len(msg.Result) > 0 {
+			if err := json.Unmarshal(msg.Result, result); err != nil {
+				return fmt.Errorf("%s: invalid result: %w", method, err)
+			}
+		}
+		return nil
+	case <-c.done:
+		return fmt.Errorf("%s: the connection to the browser was closed", method)
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// listen registers the listener of the events of the session, or removes it
+// if nil.
+func (c *cdpConn) listen(sessionID string, listener func(method string, params json.RawMessage)) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if listener == nil {
+		delete(c.listeners, sessionID)
+		return
+	}
+	c.listeners[sessionID] = listener
+}
+
+func (c *cdpConn) closed() bool {
+	select {
+	case <-c.done:
+		return true
+	default:
+		return false
+	}
+}
+
+func (c *cdpConn) close() error {
+	err := c.ws.Close()
+	<-c.done
+	return err
+}
+
+// cdpPage is a tab of the browser.
+type cdpPage struct {
+	conn      *cdpConn
+	targetID  string
+	sessionID string
+	opts      PageOptions
+
+	mu      sync.Mutex
+	blocked error
+}
+
+func (p *cdpPage) call(ctx context.Context, method string, params, result any) error {
+	return p.conn.call(ctx, p.sessionID, method, params, result)
+}
+
+// onEvent checks the documents requested by the page.
+func (p *cdpPage) onEvent(method string, params json.RawMessage) {
+	if method != "Fetch.requestPaused" {
+		return
+	}
+	var paused struct {
+		RequestID string `json:"requestId"`
+		Request   struct {
+			URL string `json:"url"`
+		} `json:"request"`
+	}
+	if err := json.Unmarshal(params, &paused); err != nil {
+		return
+	}
+	// The read loop must not wait for the responses.
+	go func() {
+		ctx := context.Background()
+		if err := p.opts.CheckURL(paused.Request.URL); err != nil {
+			p.mu.Lock()
+			p.blocked = err
+			p.mu.Unlock()
+			p.call(ctx, "Fetch.failRequest", map[string]any{"requestId": paused.RequestID, "errorReason": "BlockedByClient"}, nil)
+			return
+		}
+		p.call(ctx, "Fetch.continueRequest", map[string]any{"requestId": paused.RequestID}, nil)
+	}()
+}
+
+// takeBlocked returns and clears the error of the last blocked request.
+func (p *cdpPage) takeBlocked() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	err := p.blocked
+	p.blocked = nil
+	return err
+}
+
+func (p *cdpPage) Navigate(ctx context.Context, url string) error {
+	p.takeBlocked()
+	var nav struct {
+		ErrorText string `json:"errorText"`
+	}
+	if err := p.call(ctx, "Page.navigate", map[string]any{"url": url}, &nav); err != nil {
+		return err
+	}
+	if err := p.takeBlocked(); err != nil {
+		return err
+	}
+	if nav.ErrorText != "" {
+		return fmt.Errorf("failed to load %s: %s", url, nav.ErrorText)
+	}
+	return p.waitLoad(ctx)
+}
+
+func (p *cdpPage) Click(ctx context.Context, ref string) error {
+	p.takeBlocked()
+	if err := p.onElement(ctx, ref, "el.scrollIntoView({block: 'center'}); el.click();"); err != nil {
+		return err
+	}
+	// The click may start a navigation.
+	if err := sleep(ctx, loadPollInterval); err != nil {
+		return err
+	}
+	return p.waitLoad(ctx)
+}
+
+func (p *cdpPage) Fill(ctx context.Context, ref, value string) error {
+	v, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	// The value is set with the setter of the prototype, and the events are
+	// dispatched, so that the frameworks tracking the inputs see it.
+	return p.onElement(ctx, ref, fmt.Sprintf(`el.focus();
+const proto = el instanceof HTMLTextAreaElement ? HTMLTextAreaElement.prototype : el instanceof HTMLSelectElement ? HTMLSelectElement.prototype : HTMLInputElement.prototype;
+Object.getOwnPropertyDescriptor(proto, 'value').set.call(el, %s);
+el.dispatchEvent(new Event('input', {bubbles: true}));
+el.dispatchEvent(new Event('change', {bubbles: true}));`, v))
+}
+
+// onElement runs the script on the element with the reference, as el.
+func (p *cdpPage) onElement(ctx context.Context, ref, script string) error {
+	r, err := json.Marshal(ref)
+	if err != nil {
+		return err
+	}
+	var found bool
+	expr := fmt.Sprintf("(() => { const el = document.querySelector('[data-adk-ref=' + JSON.stringify(%s) + ']'); if (!el) return false; %s return true; })()", r, script)
+	if err := p.evaluate(ctx, expr, &found); err != nil {
+		return err
+	}
+	if !found {
+		return fmt.Errorf("unknown element %q; references change when the page navigates, take a new snapshot", ref)
+	}
+	return nil
+}
+
+// waitLoad waits for the document to be loaded.
+func (p *cdpPage) waitLoad(ctx context.Context) error {
+	for {
+		if err := p.takeBlocked(); err != nil {
+			return err
+		}
+		var state string
+		if err := p.evaluate(ctx, "document.readyState", &state); err != nil {
+			return err
+		}
+		if state == "complete" {
+			return nil
+		}
+		if err := sleep(ctx, loadPollInterval); err != nil {
+			return err
+		}
+	}
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// evaluate evaluates the JavaScript expression in the page, and decodes its
+// value into result.
+func (p *cdpPage) evaluate(ctx context.Context, expr string, result any) error {
+	var resp struct {
+		Result struct {
+			Value json.RawMessage `json:"value"`
+		} `json:"result"`
+		ExceptionDetails *struct {
+			Text      string `json:"text"`
+			Exception struct {
+				Description string `json:"description"`
+			} `json:"exception"`
+		} `json:"exceptionDetails"`
+	}
+	if err := p.call(ctx, "Runtime.evaluate", map[string]any{"expression": expr, "returnByValue": true, "awaitPromise": true}, &resp); err != nil {
+		return err
+	}
+	if d := resp.ExceptionDetails; d != nil {
+		return fmt.Errorf("script error: %s", cmp.Or(d.Exception.Description, d.Text))
+	}
+	if len(resp.Result.Value) == 0 {
+		return nil
+	}
+	return json.Unmarshal(resp.Result.Value, result)
+}
+
+// snapshotScript marks the interactive elements of the page with their
+// reference, and returns the snapshot of the page.
+const snapshotScript = `(() => {
+  for (const el of document.querySelectorAll('[data-adk-ref]')) el.removeAttribute('data-adk-ref');
+  const elements = [];
+  const selector = 'a[href], button, input, select, textarea, [role=button], [role=link], [role=checkbox], [onclick]';
+  for (const el of document.querySelectorAll(selector)) {
+    const type = (el.getAttribute('type') || '').toLowerCase();
+    if (type === 'hidden' || el.disabled) continue;
+    const rect = el.getBoundingClientRect();
+    if (rect.width === 0 && rect.height === 0) continue;
+    const tag = el.tagName.toLowerCase();
+    let role = el.getAttribute('role');
+    if (!role) {
+      if (tag === 'a') role = 'link';
+      else if (tag === 'button' || ['submit', 'button', 'reset', 'image'].includes(type)) role = 'button';
+      else if (type === 'checkbox' || type === 'radio') role = 'checkbox';
+      else if (tag === 'select') role = 'combobox';
+      else if (tag === 'input' || tag === 'textarea') role = 'textbox';
+      else role = 'button';
+    }
+    const label = el.labels && el.labels.length ? el.labels[0].innerText : '';
+    const name = el.getAttribute('aria-label') || label || el.innerText || el.getAttribute('placeholder') || el.getAttribute('title') || (role === 'button' ? el.value : '') || '';
+    let value = '';
+    if (role === 'link') value = el.href;
+    else if (role === 'textbox' || role === 'combobox') value = el.value || '';
+    else if (role === 'checkbox') value = el.checked ? 'checked' : '';
+    const ref = 'e' + (elements.length + 1);
+    el.setAttribute('data-adk-ref', ref);
+    elements.push({ref, role, name: name.replace(/\s+/g, ' ').trim(), value});
+  }
+  return {url: location.href, title: document.title, text: document.body ? document.body.innerText : '', elements};
+})()`
+
+func (p *cdpPage) Snapshot(ctx context.Context) (*Snapshot, error) {
+	var s Snapshot
+	var raw struct {
+		URL      string    `json:"url"`
+		Title    string    `json:"title"`
+		Text     string    `json:"text"`
+		Elements []Element `json:"elements"`
+	}
+	if err := p.evaluate(ctx, snapshotScript, &raw); err != nil {
+		return nil, err
+	}
+	s.URL, s.Title, s.Elements = raw.URL, strings.TrimSpace(raw.Title), raw.Elements
+	if s.Elements == nil {
+		s.Elements = []Element{}
+	}
+	var lines []string
+	for line := range strings.Lines(raw.Text) {
+		if line = strings.TrimSpace(line); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	s.Text = strings.Join(lines, "\n")
+	return &s, nil
+}
+
+// Screenshot implements ScreenshotPage.
+func (p *cdpPage) Screenshot(ctx context.Context) ([]byte, error) {
+	var shot struct {
+		Data []byte `json:"data"`
+	}
+	if err := p.call(ctx, "Page.captureScreenshot", map[string]any{"format": "png"}, &shot); err != nil {
+		return nil, err
+	}
+	return shot.Data, nil
+}
+
+func (p *cdpPage) Close() error {
+	p.conn.listen(p.sessionID, nil)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	return p.conn.call(ctx, "", "Target.closeTarget", map[string]any{"targetId": p.targetID}, nil)
+}
+
+var _ ScreenshotPage = (*cdpPage)(nil)
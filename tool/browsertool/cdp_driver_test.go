@@ -0,0 +1,165 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package browsertool_test
+
+import (
+	"errors"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"golang.org/x/net/websocket"
+
+	"google.golang.org/adk/tool/browsertool"
+)
+
+// fakeBrowser serves the subset of the DevTools protocol used by the driver,
+// for a single page.
+type fakeBrowser struct {
+	mu     sync.Mutex
+	url    string
+	filled string
+}
+
+type fakeMessage struct {
+	ID        int64          `json:"id,omitempty"`
+	SessionID string         `json:"sessionId,omitempty"`
+	Method    string         `json:"method,omitempty"`
+	Params    map[string]any `json:"params,omitempty"`
+	Result    any            `json:"result,omitempty"`
+}
+
+func (b *fakeBrowser) serve(ws *websocket.Conn) {
+	var navigation *fakeMessage
+	reply := func(id int64, result any) {
+		websocket.JSON.Send(ws, fakeMessage{ID: id, Result: result})
+	}
+	for {
+		var msg fakeMessage
+		if err := websocket.JSON.Receive(ws, &msg); err != nil {
+			return
+		}
+		b.mu.Lock()
+		switch msg.Method {
+		case "Target.createTarget":
+			reply(msg.ID, map[string]any{"targetId": "T1"})
+		case "Target.attachToTarget":
+			reply(msg.ID, map[string]any{"sessionId": "S1"})
+		case "Page.navigate":
+			// The document request is paused until the driver checks it.
+			navigation = &msg
+			websocket.JSON.Send(ws, fakeMessage{SessionID: "S1", Method: "Fetch.requestPaused", Params: map[string]any{
+				"requestId": "R1",
+				"request":   map[string]any{"url": msg.Params["url"]},
+			}})
+		case "Fetch.continueRequest":
+			reply(msg.ID, map[string]any{})
+			b.url = navigation.Params["url"].(string)
+			reply(navigation.ID, map[string]any{"frameId": "F1"})
+		case "Fetch.failRequest":
+			reply(msg.ID, map[string]any{})
+			reply(navigation.ID, map[string]any{"frameId": "F1", "errorText": "net::ERR_BLOCKED_BY_CLIENT"})
+		case "Runtime.evaluate":
+			reply(msg.ID, map[string]any{"result": map[string]any{"value": b.evaluate(msg.Params["expression"].(string))}})
+		case "Page.captureScreenshot":
+			reply(msg.ID, map[string]any{"data": "cG5n"}) // "png"
+		default:
+			reply(msg.ID, map[string]any{})
+		}
+		b.mu.Unlock()
+	}
+}
+
+func (b *fakeBrowser) evaluate(expr string) any {
+	switch {
+	case expr == "document.readyState":
+		return "complete"
+	case strings.Contains(expr, "getOwnPropertyDescriptor"):
+		if !strings.Contains(expr, `"e1"`) {
+			return false
+		}
+		b.filled = expr
+		return true
+	case strings.Contains(expr, "el.click()"):
+		return strings.Contains(expr, `"e1"`)
+	default:
+		return map[string]any{
+			"url":      b.url,
+			"title":    " Fake ",
+			"text":     "Hello\n\n  world \n",
+			"elements": []map[string]any{{"ref": "e1", "role": "textbox", "name": "Query"}},
+		}
+	}
+}
+
+func newFakeBrowser(t *testing.T) (*fakeBrowser, string) {
+	t.Helper()
+	b := &fakeBrowser{url: "about:blank"}
+	srv := httptest.NewServer(websocket.Handler(b.serve))
+	t.Cleanup(srv.Close)
+	return b, "ws" + strings.TrimPrefix(srv.URL, "http") + "/devtools/browser/fake"
+}
+
+func TestCDPDriver(t *testing.T) {
+	b, debuggerURL := newFakeBrowser(t)
+	d := browsertool.NewCDPDriver(browsertool.CDPDriverConfig{DebuggerURL: debuggerURL})
+	defer d.Close()
+
+	errBlocked := errors.New("blocked")
+	page, err := d.NewPage(t.Context(), browsertool.PageOptions{CheckURL: func(u string) error {
+		if strings.Contains(u, "blocked.example") {
+			return errBlocked
+		}
+		return nil
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer page.Close()
+
+	if err := page.Navigate(t.Context(), "https://example.com/"); err != nil {
+		t.Fatalf("Navigate() error = %v", err)
+	}
+	s, err := page.Snapshot(t.Context())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.URL != "https://example.com/" || s.Title != "Fake" || s.Text != "Hello\nworld" || len(s.Elements) != 1 {
+		t.Errorf("Snapshot() = %+v, want the page of example.com", s)
+	}
+
+	if err := page.Click(t.Context(), "e1"); err != nil {
+		t.Errorf("Click(e1) error = %v", err)
+	}
+	if err := page.Click(t.Context(), "e9"); err == nil {
+		t.Error("Click(e9) succeeded, want unknown element error")
+	}
+	if err := page.Fill(t.Context(), "e1", `say "hi"`); err != nil {
+		t.Errorf("Fill() error = %v", err)
+	}
+	if !strings.Contains(b.filled, `"say \"hi\""`) {
+		t.Errorf("Fill() script %q does not set the quoted value", b.filled)
+	}
+
+	if err := page.Navigate(t.Context(), "https://blocked.example/"); !errors.Is(err, errBlocked) {
+		t.Errorf("Navigate() to a blocked URL error = %v, want %v", err, errBlocked)
+	}
+
+	png, err := page.(browsertool.ScreenshotPage).Screenshot(t.Context())
+	if err != nil || string(png) != "png" {
+		t.Errorf("Screenshot() = %q, %v, want png", png, err)
+	}
+}
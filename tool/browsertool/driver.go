@@ -0,0 +1,79 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package browsertool
+
+import "context"
+
+// Driver opens browser pages.
+type Driver interface {
+	// NewPage opens a blank page. Every URL the page loads, including after
+	// clicks and redirects, must be checked with opts.CheckURL.
+	NewPage(ctx context.Context, opts PageOptions) (Page, error)
+}
+
+// PageOptions are the options of a new page.
+type PageOptions struct {
+	// CheckURL returns an error if the page must not load the URL.
+	CheckURL func(url string) error
+}
+
+// Page is a browser page, used by a single invocation at a time.
+type Page interface {
+	// Navigate loads the URL.
+	Navigate(ctx context.Context, url string) error
+	// Click clicks the element with the given reference, as returned in the
+	// snapshot of the page.
+	Click(ctx context.Context, ref string) error
+	// Fill sets the value of the input element with the given reference.
+	Fill(ctx context.Context, ref, value string) error
+	// Snapshot returns the current state of the page.
+	Snapshot(ctx context.Context) (*Snapshot, error)
+	// Close releases the page.
+	Close() error
+}
+
+// ScreenshotPage is a Page which can take screenshots, as the pages of the
+// CDPDriver can.
+type ScreenshotPage interface {
+	Page
+	// Screenshot returns a PNG image of the viewport of the page.
+	Screenshot(ctx context.Context) ([]byte, error)
+}
+
+// Snapshot is the state of a page, as presented to the model: the visible
+// text, and the interactive elements in document order, similar to an
+// accessibility tree reduced to the elements the model can act on.
+type Snapshot struct {
+	URL   string
+	Title string
+	// Text is the visible text of the page, one block per line.
+	Text     string
+	Elements []Element
+}
+
+// Element is an interactive element of a page.
+type Element struct {
+	// Ref identifies the element in Click and Fill calls. References are
+	// only valid until the page navigates.
+	Ref string `json:"ref"`
+	// Role is the accessibility role, e.g. "link", "button", "textbox" or
+	// "checkbox".
+	Role string `json:"role"`
+	// Name is the accessible name, e.g. the text of a link or the label of
+	// an input.
+	Name string `json:"name,omitempty"`
+	// Value is the current value of an input, or the target of a link.
+	Value string `json:"value,omitempty"`
+}
@@ -0,0 +1,549 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package browsertool
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/cookiejar"
+	"net/url"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+const maxPageSize = 5 << 20
+
+// HTTPDriverConfig is the configuration of the driver returned by
+// [NewHTTPDriver].
+type HTTPDriverConfig struct {
+	// Client used to load pages. Defaults to a client with its own cookie
+	// jar per page.
+	Client *http.Client
+	// UserAgent sent with the requests. Optional.
+	UserAgent string
+}
+
+// NewHTTPDriver returns a headless [Driver] that loads pages over HTTP and
+// parses their HTML, without running JavaScript. It supports following
+// links, filling inputs and submitting forms, which is enough for most
+// server-rendered sites.
+func NewHTTPDriver(cfg HTTPDriverConfig) Driver {
+	return &httpDriver{cfg: cfg}
+}
+
+type httpDriver struct {
+	cfg HTTPDriverConfig
+}
+
+func (d *httpDriver) NewPage(ctx context.Context, opts PageOptions) (Page, error) {
+	client := d.cfg.Client
+	if client == nil {
+		jar, err := cookiejar.New(nil)
+		if err != nil {
+			return nil, err
+		}
+		client = &http.Client{Jar: jar}
+	}
+	p := &httpPage{driver: d, opts: opts}
+	// Redirects are checked like any other navigation.
+	copied := *client
+	copied.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+		if len(via) >= 10 {
+			return errors.New("stopped after 10 redirects")
+		}
+		return p.checkURL(req.URL.String())
+	}
+	p.client = &copied
+	return p, nil
+}
+
+type httpPage struct {
+	driver *httpDriver
+	opts   PageOptions
+	client *http.Client
+
+	url      *url.URL
+	doc      *html.Node
+	elements []*pageElement
+}
+
+type pageElement struct {
+	Element
+	node *html.Node
+	form *html.Node
+}
+
+func (p *httpPage) checkURL(u string) error {
+	if p.opts.CheckURL == nil {
+		return nil
+	}
+	return p.opts.CheckURL(u)
+}
+
+func (p *httpPage) Navigate(ctx context.Context, rawURL string) error {
+	u, err := p.resolve(rawURL)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return err
+	}
+	return p.load(req)
+}
+
+func (p *httpPage) Click(ctx context.Context, ref string) error {
+	el, err := p.element(ref)
+	if err != nil {
+		return err
+	}
+	switch el.Role {
+	case "link":
+		return p.Navigate(ctx, attr(el.node, "href"))
+	case "checkbox":
+		checked := hasAttr(el.node, "checked")
+		if strings.EqualFold(attr(el.node, "type"), "radio") {
+			// Checking a radio button unchecks the others of its group.
+			for _, other := range p.elements {
+				if other.form == el.form && strings.EqualFold(attr(other.node, "type"), "radio") && attr(other.node, "name") == attr(el.node, "name") {
+					removeAttr(other.node, "checked")
+				}
+			}
+			checked = false
+		}
+		if checked {
+			removeAttr(el.node, "checked")
+		} else {
+			el.node.Attr = append(el.node.Attr, html.Attribute{Key: "checked"})
+		}
+		return nil
+	case "button":
+		if t := strings.ToLower(attr(el.node, "type")); el.form == nil || t == "button" || t == "reset" {
+			return fmt.Errorf("button %s does nothing without JavaScript", ref)
+		}
+		return p.submit(ctx, el)
+	default:
+		return fmt.Errorf("element %s is a %s and cannot be clicked; use fill", ref, el.Role)
+	}
+}
+
+func (p *httpPage) Fill(ctx context.Context, ref, value string) error {
+	el, err := p.element(ref)
+	if err != nil {
+		return err
+	}
+	if el.Role != "textbox" {
+		return fmt.Errorf("element %s is a %s and cannot be filled", ref, el.Role)
+	}
+	if el.node.DataAtom == atom.Textarea {
+		for c := el.node.FirstChild; c != nil; c = el.node.FirstChild {
+			el.node.RemoveChild(c)
+		}
+		el.node.AppendChild(&html.Node{Type: html.TextNode, Data: value})
+		return nil
+	}
+	removeAttr(el.node, "value")
+	el.node.Attr = append(el.node.Attr, html.Attribute{Key: "value", Val: value})
+	return nil
+}
+
+func (p *httpPage) Snapshot(ctx context.Context) (*Snapshot, error) {
+	if p.doc == nil {
+		return &Snapshot{URL: "about:blank", Elements: []Element{}}, nil
+	}
+	s := &Snapshot{
+		URL:      p.url.String(),
+		Title:    strings.TrimSpace(textOf(find(p.doc, atom.Title))),
+		Text:     visibleText(p.doc),
+		Elements: make([]Element, 0, len(p.elements)),
+	}
+	for _, el := range p.elements {
+		e := el.Element
+		e.Value = elementValue(el)
+		s.Elements = append(s.Elements, e)
+	}
+	return s, nil
+}
+
+func (p *httpPage) Close() error {
+	p.doc, p.elements = nil, nil
+	return nil
+}
+
+func (p *httpPage) resolve(rawURL string) (*url.URL, error) {
+	u, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil {
+		return nil, fmt.Errorf("invalid URL %q: %w", rawURL, err)
+	}
+	if p.url != nil {
+		u = p.url.ResolveReference(u)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, fmt.Errorf("unsupported URL %q, want an http or https URL", u)
+	}
+	if err := p.checkURL(u.String()); err != nil {
+		return nil, err
+	}
+	return u, nil
+}
+
+func (p *httpPage) load(req *http.Request) error {
+	if p.driver.cfg.UserAgent != "" {
+		req.Header.Set("User-Agent", p.driver.cfg.UserAgent)
+	}
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 400 {
+		return fmt.Errorf("loading %s failed: %s", req.URL, resp.Status)
+	}
+	if ct := resp.Header.Get("Content-Type"); ct != "" && !strings.Contains(ct, "html") && !strings.HasPrefix(ct, "text/") {
+		return fmt.Errorf("%s is not a web page but %s", req.URL, ct)
+	}
+	doc, err := html.Parse(io.LimitReader(resp.Body, maxPageSize))
+	if err != nil {
+		return fmt.Errorf("failed to parse %s: %w", req.URL, err)
+	}
+	p.url = resp.Request.URL
+	p.doc = doc
+	p.elements = collectElements(doc)
+	return nil
+}
+
+func (p *httpPage) element(ref string) (*pageElement, error) {
+	for _, el := range p.elements {
+		if el.Ref == ref {
+			return el, nil
+		}
+	}
+	return nil, fmt.Errorf("unknown element %q; references change when the page navigates, take a new snapshot", ref)
+}
+
+// submit submits the form of the button.
+func (p *httpPage) submit(ctx context.Context, button *pageElement) error {
+	values := url.Values{}
+	walk(button.form, func(n *html.Node) bool {
+		name := attr(n, "name")
+		if name == "" {
+			return true
+		}
+		switch n.DataAtom {
+		case atom.Input:
+			switch strings.ToLower(attr(n, "type")) {
+			case "submit", "button", "image", "reset", "file":
+				return true
+			case "checkbox", "radio":
+				if !hasAttr(n, "checked") {
+					return true
+				}
+				values.Add(name, cmp.Or(attr(n, "value"), "on"))
+			default:
+				values.Add(name, attr(n, "value"))
+			}
+		case atom.Textarea:
+			values.Add(name, textOf(n))
+		case atom.Select:
+			values.Add(name, selectedOption(n))
+		}
+		return true
+	})
+	if name := attr(button.node, "name"); name != "" {
+		values.Add(name, attr(button.node, "value"))
+	}
+
+	action, err := p.resolve(cmp.Or(attr(button.form, "action"), p.url.String()))
+	if err != nil {
+		return err
+	}
+	var req *http.Request
+	if strings.EqualFold(attr(button.form, "method"), http.MethodPost) {
+		req, err = http.NewRequestWithContext(ctx, http.MethodPost, action.String(), strings.NewReader(values.Encode()))
+		if err == nil {
+			req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		}
+	} else {
+		action.RawQuery = values.Encode()
+		req, err = http.NewRequestWithContext(ctx, http.MethodGet, action.String(), nil)
+	}
+	if err != nil {
+		return err
+	}
+	return p.load(req)
+}
+
+// collectElements returns the interactive elements of the document.
+func collectElements(doc *html.Node) []*pageElement {
+	labels := make(map[string]string)
+	walk(doc, func(n *html.Node) bool {
+		if n.DataAtom == atom.Label && attr(n, "for") != "" {
+			labels[attr(n, "for")] = collapse(textOf(n))
+		}
+		return true
+	})
+
+	var elements []*pageElement
+	var form *html.Node
+	var visit func(n *html.Node)
+	visit = func(n *html.Node) {
+		if n.Type == html.ElementNode {
+			if n.DataAtom == atom.Form {
+				outer := form
+				form = n
+				defer func() { form = outer }()
+			}
+			if hidden(n) {
+				return
+			}
+			if role := elementRole(n); role != "" {
+				elements = append(elements, &pageElement{
+					Element: Element{
+						Ref:  fmt.Sprintf("e%d", len(elements)+1),
+						Role: role,
+						Name: elementName(n, labels),
+					},
+					node: n,
+					form: form,
+				})
+			}
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			visit(c)
+		}
+	}
+	visit(doc)
+	return elements
+}
+
+func elementRole(n *html.Node) string {
+	switch n.DataAtom {
+	case atom.A:
+		if hasAttr(n, "href") {
+			return "link"
+		}
+	case atom.Button:
+		return "button"
+	case atom.Textarea:
+		return "textbox"
+	case atom.Input:
+		switch strings.ToLower(attr(n, "type")) {
+		case "submit", "button", "image":
+			return "button"
+		case "checkbox", "radio":
+			return "checkbox"
+		case "hidden", "reset", "file":
+			return ""
+		default:
+			return "textbox"
+		}
+	}
+	return ""
+}
+
+func elementName(n *html.Node, labels map[string]string) string {
+	if label := attr(n, "aria-label"); label != "" {
+		return label
+	}
+	if id := attr(n, "id"); id != "" && labels[id] != "" {
+		return labels[id]
+	}
+	if n.DataAtom == atom.Input {
+		if t := strings.ToLower(attr(n, "type")); t == "submit" || t == "button" {
+			return cmp.Or(attr(n, "value"), "Submit")
+		}
+		return cmp.Or(attr(n, "placeholder"), attr(n, "name"))
+	}
+	if n.DataAtom == atom.Textarea {
+		return cmp.Or(attr(n, "placeholder"), attr(n, "name"))
+	}
+	return collapse(textOf(n))
+}
+
+func elementValue(el *pageElement) string {
+	switch el.Role {
+	case "link":
+		return attr(el.node, "href")
+	case "textbox":
+		if el.node.DataAtom == atom.Textarea {
+			return textOf(el.node)
+		}
+		if strings.EqualFold(attr(el.node, "type"), "password") && attr(el.node, "value") != "" {
+			return "********"
+		}
+		return attr(el.node, "value")
+	case "checkbox":
+		if hasAttr(el.node, "checked") {
+			return "checked"
+		}
+		return "unchecked"
+	}
+	return ""
+}
+
+func selectedOption(n *html.Node) string {
+	var first, selected *html.Node
+	walk(n, func(c *html.Node) bool {
+		if c.DataAtom == atom.Option {
+			if first == nil {
+				first = c
+			}
+			if selected == nil && hasAttr(c, "selected") {
+				selected = c
+			}
+		}
+		return true
+	})
+	if selected == nil {
+		selected = first
+	}
+	if selected == nil {
+		return ""
+	}
+	if hasAttr(selected, "value") {
+		return attr(selected, "value")
+	}
+	return collapse(textOf(selected))
+}
+
+// blockElements start a new line in the visible text.
+var blockElements = map[atom.Atom]bool{
+	atom.P: true, atom.Div: true, atom.Br: true, atom.Li: true, atom.Tr: true,
+	atom.H1: true, atom.H2: true, atom.H3: true, atom.H4: true, atom.H5: true, atom.H6: true,
+	atom.Section: true, atom.Article: true, atom.Header: true, atom.Footer: true,
+	atom.Ul: true, atom.Ol: true, atom.Table: true, atom.Form: true, atom.Pre: true,
+}
+
+// visibleText returns the text of the body, one block per line.
+func visibleText(doc *html.Node) string {
+	var lines []string
+	var line strings.Builder
+	flush := func() {
+		if s := collapse(line.String()); s != "" {
+			lines = append(lines, s)
+		}
+		line.Reset()
+	}
+	var visit func(n *html.Node)
+	visit = func(n *html.Node) {
+		switch n.Type {
+		case html.TextNode:
+			line.WriteString(n.Data)
+			line.WriteString(" ")
+			return
+		case html.ElementNode:
+			if hidden(n) {
+				return
+			}
+			switch n.DataAtom {
+			case atom.Head, atom.Script, atom.Style, atom.Noscript, atom.Template:
+				return
+			}
+		}
+		block := n.Type == html.ElementNode && blockElements[n.DataAtom]
+		if block {
+			flush()
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			visit(c)
+		}
+		if block {
+			flush()
+		}
+	}
+	visit(doc)
+	flush()
+	return strings.Join(lines, "\n")
+}
+
+func hidden(n *html.Node) bool {
+	if hasAttr(n, "hidden") || attr(n, "aria-hidden") == "true" {
+		return true
+	}
+	style := strings.ReplaceAll(strings.ToLower(attr(n, "style")), " ", "")
+	return strings.Contains(style, "display:none") || strings.Contains(style, "visibility:hidden")
+}
+
+func walk(n *html.Node, f func(*html.Node) bool) {
+	if n == nil || !f(n) {
+		return
+	}
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		walk(c, f)
+	}
+}
+
+func find(n *html.Node, a atom.Atom) *html.Node {
+	var found *html.Node
+	walk(n, func(c *html.Node) bool {
+		if found == nil && c.DataAtom == a {
+			found = c
+		}
+		return found == nil
+	})
+	return found
+}
+
+func textOf(n *html.Node) string {
+	var b strings.Builder
+	walk(n, func(c *html.Node) bool {
+		if c.Type == html.TextNode {
+			b.WriteString(c.Data)
+		}
+		return true
+	})
+	return b.String()
+}
+
+func collapse(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+func attr(n *html.Node, key string) string {
+	if n == nil {
+		return ""
+	}
+	for _, a := range n.Attr {
+		if a.Key == key {
+			return a.Val
+		}
+	}
+	return ""
+}
+
+func hasAttr(n *html.Node, key string) bool {
+	for _, a := range n.Attr {
+		if a.Key == key {
+			return true
+		}
+	}
+	return false
+}
+
+func removeAttr(n *html.Node, key string) {
+	attrs := n.Attr[:0]
+	for _, a := range n.Attr {
+		if a.Key != key {
+			attrs = append(attrs, a)
+		}
+	}
+	n.Attr = attrs
+}
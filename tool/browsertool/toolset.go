@@ -0,0 +1,485 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package browsertool provides a toolset that lets the model browse the web
+// interactively through a pluggable browser [Driver].
+//
+// The toolset exposes four tools:
+//
+//   - "browser_navigate" loads a URL.
+//   - "browser_click" clicks a link, button or checkbox.
+//   - "browser_fill" types a value into an input.
+//   - "browser_get_text" reads the text of the page, page by page.
+//
+// With Config.Screenshots, a fifth tool, "browser_screenshot", returns a
+// screenshot of the page as an image part, so that vision-capable models can
+// operate web UIs from what they see. It requires a driver whose pages
+// implement [ScreenshotPage], such as the experimental [CDPDriver], which
+// drives a headless Chrome.
+//
+// # Page state
+//
+// After navigate, click and fill, the model receives the state of the page:
+// its URL and title, the interactive elements, each with a reference such as
+// "e3" to pass to click and fill, and the beginning of the visible text. The
+// elements are a flat list in document order with their accessible role and
+// name, a compact form of the accessibility tree; the text is plain text,
+// one block per line. Long texts are read with browser_get_text and an
+// offset.
+//
+// # Page lifecycle
+//
+// Each invocation has its own page, opened on the first call and kept
+// across the calls of the invocation, so the model can navigate and then
+// interact. The page is closed when the callback returned by
+// [Toolset.AfterAgentCallback] runs at the end of the invocation, after
+// Config.IdleTimeout without calls, or when the toolset is closed.
+//
+// # Guardrails
+//
+// Config.AllowedHosts restricts the pages to a list of domains, redirects
+// and the documents loaded by clicks included. Config.MaxActions bounds the
+// number of actions of an invocation, so that a model stuck on a page cannot
+// loop forever.
+//
+// # Timeouts
+//
+// Every operation is bounded by Config.OperationTimeout. The number of open
+// pages is bounded by Config.MaxPages.
+package browsertool
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/agent"
+	"google.golang.org/adk/tool"
+	"google.golang.org/adk/tool/functiontool"
+)
+
+const (
+	defaultName             = "browser"
+	defaultOperationTimeout = 30 * time.Second
+	defaultIdleTimeout      = 5 * time.Minute
+	defaultMaxPages         = 10
+	defaultMaxTextLength    = 4000
+	defaultMaxElements      = 100
+)
+
+// Config is the configuration of the browser toolset.
+type Config struct {
+	// Name of the toolset. Defaults to "browser".
+	Name string
+	// Driver opening the pages. Defaults to the driver returned by
+	// NewHTTPDriver.
+	Driver Driver
+	// AllowedHosts restricts the pages to these hosts and their subdomains.
+	// All hosts are allowed if empty.
+	AllowedHosts []string
+	// OperationTimeout bounds every operation. Defaults to 30 seconds.
+	OperationTimeout time.Duration
+	// IdleTimeout after which an unused page is closed. Defaults to 5
+	// minutes.
+	IdleTimeout time.Duration
+	// MaxPages is the maximum number of pages open at the same time, across
+	// invocations. Defaults to 10.
+	MaxPages int
+	// MaxTextLength is the maximum length in bytes of the text returned per
+	// call. Defaults to 4000.
+	MaxTextLength int
+	// MaxElements is the maximum number of elements returned. Defaults to
+	// 100.
+	MaxElements int
+	// MaxActions is the maximum number of navigate, click, fill and
+	// screenshot calls per invocation. The actions are not limited if zero.
+	MaxActions int
+	// Screenshots adds the browser_screenshot tool. The pages of Driver must
+	// implement ScreenshotPage.
+	Screenshots bool
+}
+
+// Toolset is the browser toolset.
+type Toolset struct {
+	cfg   Config
+	tools []tool.Tool
+
+	mu    sync.Mutex
+	pages map[string]*browserPage // by invocation ID
+}
+
+type browserPage struct {
+	invocationID string
+	page         Page
+	timer        *time.Timer
+	actions      int
+
+	// mu serializes the operations and the closing of the page.
+	mu     sync.Mutex
+	closed bool
+}
+
+// New creates a browser toolset.
+func New(cfg Config) (*Toolset, error) {
+	if cfg.Name == "" {
+		cfg.Name = defaultName
+	}
+	if cfg.Driver == nil {
+		cfg.Driver = NewHTTPDriver(HTTPDriverConfig{})
+	}
+	if cfg.OperationTimeout <= 0 {
+		cfg.OperationTimeout = defaultOperationTimeout
+	}
+	if cfg.IdleTimeout <= 0 {
+		cfg.IdleTimeout = defaultIdleTimeout
+	}
+	if cfg.MaxPages <= 0 {
+		cfg.MaxPages = defaultMaxPages
+	}
+	if cfg.MaxTextLength <= 0 {
+		cfg.MaxTextLength = defaultMaxTextLength
+	}
+	if cfg.MaxElements <= 0 {
+		cfg.MaxElements = defaultMaxElements
+	}
+
+	ts := &Toolset{cfg: cfg, pages: make(map[string]*browserPage)}
+	tools, err := ts.newTools()
+	if err != nil {
+		return nil, fmt.Errorf("error creating browser tools: %w", err)
+	}
+	ts.tools = tools
+	return ts, nil
+}
+
+// Name implements tool.Toolset.
+func (ts *Toolset) Name() string {
+	return ts.cfg.Name
+}
+
+// Tools implements tool.Toolset.
+func (ts *Toolset) Tools(agent.ReadonlyContext) ([]tool.Tool, error) {
+	return ts.tools, nil
+}
+
+// AfterAgentCallback returns a callback closing the page of the invocation.
+// It should be registered as an after agent callback of the agent using the
+// toolset.
+func (ts *Toolset) AfterAgentCallback() agent.AfterAgentCallback {
+	return func(ctx agent.CallbackContext) (*genai.Content, error) {
+		ts.mu.Lock()
+		p, ok := ts.pages[ctx.InvocationID()]
+		ts.mu.Unlock()
+		if ok {
+			return nil, ts.close(p)
+		}
+		return nil, nil
+	}
+}
+
+// Close closes all pages.
+func (ts *Toolset) Close() error {
+	ts.mu.Lock()
+	pages := make([]*browserPage, 0, len(ts.pages))
+	for _, p := range ts.pages {
+		pages = append(pages, p)
+	}
+	ts.mu.Unlock()
+
+	var errs []error
+	for _, p := range pages {
+		errs = append(errs, ts.close(p))
+	}
+	return errors.Join(errs...)
+}
+
+// NavigateArgs are the arguments of the browser_navigate tool.
+type NavigateArgs struct {
+	URL string `json:"url" jsonschema:"the URL to load"`
+}
+
+// ClickArgs are the arguments of the browser_click tool.
+type ClickArgs struct {
+	Ref string `json:"ref" jsonschema:"the reference of the element to click, e.g. e3"`
+}
+
+// FillArgs are the arguments of the browser_fill tool.
+type FillArgs struct {
+	Ref   string `json:"ref" jsonschema:"the reference of the input to fill, e.g. e3"`
+	Value string `json:"value" jsonschema:"the value to type into the input, replacing its content"`
+}
+
+// GetTextArgs are the arguments of the browser_get_text tool.
+type GetTextArgs struct {
+	Offset int `json:"offset,omitempty" jsonschema:"the offset in the text to read from, as returned in next_offset"`
+}
+
+// ScreenshotArgs are the arguments of the browser_screenshot tool.
+type ScreenshotArgs struct{}
+
+// PageState is the response of the tools.
+type PageState struct {
+	URL   string `json:"url"`
+	Title string `json:"title,omitempty"`
+	// Elements are the interactive elements of the page. They are omitted
+	// by browser_get_text.
+	Elements []Element `json:"elements,omitempty"`
+	// Text is the visible text of the page, from the requested offset.
+	Text string `json:"text"`
+	// TextLength is the length of the whole text.
+	TextLength int `json:"text_length"`
+	// NextOffset is the offset to pass to browser_get_text to read the rest
+	// of the text, or 0 if the text is complete.
+	NextOffset int `json:"next_offset,omitempty"`
+	// TruncatedElements is the number of elements omitted.
+	TruncatedElements int `json:"truncated_elements,omitempty"`
+}
+
+func (ts *Toolset) newTools() ([]tool.Tool, error) {
+	navigate, err := functiontool.New(functiontool.Config{
+		Name:        "browser_navigate",
+		Description: "Loads a URL in the browser and returns the state of the page: its interactive elements and the beginning of its text.",
+	}, func(ctx tool.Context, args NavigateArgs) (PageState, error) {
+		return ts.do(ctx, true, 0, func(ctx context.Context, p Page) error {
+			return p.Navigate(ctx, args.URL)
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	click, err := functiontool.New(functiontool.Config{
+		Name:        "browser_click",
+		Description: "Clicks a link, button or checkbox of the current page by reference, and returns the new state of the page.",
+	}, func(ctx tool.Context, args ClickArgs) (PageState, error) {
+		return ts.do(ctx, true, 0, func(ctx context.Context, p Page) error {
+			return p.Click(ctx, args.Ref)
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	fill, err := functiontool.New(functiontool.Config{
+		Name:        "browser_fill",
+		Description: "Types a value into an input of the current page by reference, and returns the new state of the page. Click the submit button afterwards to send a form.",
+	}, func(ctx tool.Context, args FillArgs) (PageState, error) {
+		return ts.do(ctx, true, 0, func(ctx context.Context, p Page) error {
+			return p.Fill(ctx, args.Ref, args.Value)
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	getText, err := functiontool.New(functiontool.Config{
+		Name:        "browser_get_text",
+		Description: "Returns the visible text of the current page from an offset. Use it to read the rest of long pages.",
+	}, func(ctx tool.Context, args GetTextArgs) (PageState, error) {
+		if args.Offset < 0 {
+			return PageState{}, errors.New("offset must not be negative")
+		}
+		return ts.do(ctx, false, args.Offset, nil)
+	})
+	if err != nil {
+		return nil, err
+	}
+	tools := []tool.Tool{navigate, click, fill, getText}
+	if !ts.cfg.Screenshots {
+		return tools, nil
+	}
+	screenshot, err := functiontool.New(functiontool.Config{
+		Name:        "browser_screenshot",
+		Description: "Takes a screenshot of the current page, to see its layout, images and visual state.",
+	}, func(ctx tool.Context, args ScreenshotArgs) (*tool.Content, error) {
+		return ts.screenshot(ctx)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return append(tools, screenshot), nil
+}
+
+// do runs the operation, an action if not nil, on the page of the invocation
+// and returns the state of the page.
+func (ts *Toolset) do(ctx tool.Context, withElements bool, offset int, op func(context.Context, Page) error) (PageState, error) {
+	var state PageState
+	err := ts.withPage(ctx, op != nil, func(ctx context.Context, p Page) error {
+		if op != nil {
+			if err := op(ctx, p); err != nil {
+				return err
+			}
+		}
+		snapshot, err := p.Snapshot(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to read the page: %w", err)
+		}
+		state = ts.state(snapshot, withElements, offset)
+		return nil
+	})
+	return state, err
+}
+
+// screenshot returns a screenshot of the page of the invocation, after its
+// URL and title.
+func (ts *Toolset) screenshot(ctx tool.Context) (*tool.Content, error) {
+	var content *tool.Content
+	err := ts.withPage(ctx, true, func(ctx context.Context, p Page) error {
+		sp, ok := p.(ScreenshotPage)
+		if !ok {
+			return errors.New("the browser does not support screenshots")
+		}
+		snapshot, err := p.Snapshot(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to read the page: %w", err)
+		}
+		png, err := sp.Screenshot(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to take a screenshot: %w", err)
+		}
+		caption := "Screenshot of " + snapshot.URL
+		if snapshot.Title != "" {
+			caption += " (" + snapshot.Title + ")"
+		}
+		content = &tool.Content{Parts: []*genai.Part{
+			genai.NewPartFromText(caption),
+			genai.NewPartFromBytes(png, "image/png"),
+		}}
+		return nil
+	})
+	return content, err
+}
+
+// withPage runs f on the page of the invocation, within the operation
+// timeout. Actions count towards Config.MaxActions.
+func (ts *Toolset) withPage(ctx tool.Context, action bool, f func(context.Context, Page) error) error {
+	p, err := ts.page(ctx)
+	if err != nil {
+		return err
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.closed {
+		return errors.New("the page was closed, navigate again")
+	}
+	p.timer.Reset(ts.cfg.IdleTimeout)
+	if action {
+		if ts.cfg.MaxActions > 0 && p.actions >= ts.cfg.MaxActions {
+			return fmt.Errorf("the limit of %d browser actions was reached, answer with what you found", ts.cfg.MaxActions)
+		}
+		p.actions++
+	}
+
+	opCtx, cancel := context.WithTimeout(ctx, ts.cfg.OperationTimeout)
+	defer cancel()
+	if err := f(opCtx, p.page); err != nil {
+		if errors.Is(err, context.DeadlineExceeded) {
+			return fmt.Errorf("the operation timed out after %v", ts.cfg.OperationTimeout)
+		}
+		return err
+	}
+	return nil
+}
+
+func (ts *Toolset) state(s *Snapshot, withElements bool, offset int) PageState {
+	state := PageState{
+		URL:        s.URL,
+		Title:      s.Title,
+		TextLength: len(s.Text),
+	}
+	if offset < len(s.Text) {
+		text := s.Text[offset:]
+		if len(text) > ts.cfg.MaxTextLength {
+			n := ts.cfg.MaxTextLength
+			for n > 0 && !utf8.RuneStart(text[n]) {
+				n--
+			}
+			text = text[:n]
+			state.NextOffset = offset + len(text)
+		}
+		state.Text = text
+	}
+	if withElements {
+		state.Elements = s.Elements
+		if len(state.Elements) > ts.cfg.MaxElements {
+			state.TruncatedElements = len(state.Elements) - ts.cfg.MaxElements
+			state.Elements = state.Elements[:ts.cfg.MaxElements]
+		}
+	}
+	return state
+}
+
+// page returns the page of the invocation, opening it if needed.
+func (ts *Toolset) page(ctx tool.Context) (*browserPage, error) {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+
+	if p, ok := ts.pages[ctx.InvocationID()]; ok {
+		return p, nil
+	}
+	if len(ts.pages) >= ts.cfg.MaxPages {
+		return nil, errors.New("too many browser pages are open, try again later")
+	}
+	page, err := ts.cfg.Driver.NewPage(ctx, PageOptions{CheckURL: ts.checkURL})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open a page: %w", err)
+	}
+	p := &browserPage{invocationID: ctx.InvocationID(), page: page}
+	p.mu.Lock()
+	p.timer = time.AfterFunc(ts.cfg.IdleTimeout, func() { ts.close(p) })
+	p.mu.Unlock()
+	ts.pages[p.invocationID] = p
+	return p, nil
+}
+
+// close closes the page, if it is still open.
+func (ts *Toolset) close(p *browserPage) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	ts.mu.Lock()
+	if ts.pages[p.invocationID] == p {
+		delete(ts.pages, p.invocationID)
+	}
+	ts.mu.Unlock()
+
+	if p.closed {
+		return nil
+	}
+	p.closed = true
+	p.timer.Stop()
+	return p.page.Close()
+}
+
+func (ts *Toolset) checkURL(rawURL string) error {
+	if len(ts.cfg.AllowedHosts) == 0 {
+		return nil
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return fmt.Errorf("invalid URL %q: %w", rawURL, err)
+	}
+	host := strings.ToLower(u.Hostname())
+	for _, allowed := range ts.cfg.AllowedHosts {
+		allowed = strings.ToLower(allowed)
+		if host == allowed || strings.HasSuffix(host, "."+allowed) {
+			return nil
+		}
+	}
+	return fmt.Errorf("browsing %s is not allowed", host)
+}
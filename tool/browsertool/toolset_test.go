@@ -0,0 +1,296 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package browsertool_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"google.golang.org/adk/agent"
+	icontext "google.golang.org/adk/internal/context"
+	"google.golang.org/adk/internal/sessioninternal"
+	"google.golang.org/adk/internal/toolinternal"
+	"google.golang.org/adk/session"
+	"google.golang.org/adk/tool"
+	"google.golang.org/adk/tool/browsertool"
+)
+
+const (
+	homePage = `<html><head><title>Home</title><script>alert("x")</script></head>
+<body><h1>Welcome</h1><p>Find your order.</p><a href="/search">Search orders</a></body></html>`
+	searchPage = `<html><head><title>Search</title></head><body>
+<form action="/results" method="%s">
+<label for="q">Order number</label><input id="q" name="q">
+<input type="checkbox" name="archived" value="yes"> Archived
+<button type="submit">Find</button>
+</form></body></html>`
+)
+
+func newServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, homePage)
+	})
+	mux.HandleFunc("/search", func(w http.ResponseWriter, r *http.Request) {
+		method := r.URL.Query().Get("method")
+		if method == "" {
+			method = "get"
+		}
+		fmt.Fprintf(w, searchPage, method)
+	})
+	mux.HandleFunc("/results", func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		fmt.Fprintf(w, "<html><body><p>%s results for %s archived=%s</p></body></html>", r.Method, r.Form.Get("q"), r.Form.Get("archived"))
+	})
+	mux.HandleFunc("/long", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, "<html><body><p>%s</p></body></html>", strings.Repeat("a", 250))
+	})
+	mux.HandleFunc("/elsewhere", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "http://example.com/", http.StatusFound)
+	})
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+type fixture struct {
+	t       *testing.T
+	ts      *browsertool.Toolset
+	service session.Service
+	session session.Session
+}
+
+func newFixture(t *testing.T, cfg browsertool.Config) *fixture {
+	t.Helper()
+	ts, err := browsertool.New(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { ts.Close() })
+
+	service := session.InMemoryService()
+	resp, err := service.Create(t.Context(), &session.CreateRequest{AppName: "app", UserID: "user"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &fixture{t: t, ts: ts, service: service, session: resp.Session}
+}
+
+// newInvocation returns a tool context of a new invocation in the session.
+func (f *fixture) newInvocation() tool.Context {
+	f.t.Helper()
+	a, err := agent.New(agent.Config{Name: "browser_agent"})
+	if err != nil {
+		f.t.Fatal(err)
+	}
+	ctx := icontext.NewInvocationContext(f.t.Context(), icontext.InvocationContextParams{
+		Agent:   a,
+		Session: sessioninternal.NewMutableSession(f.service, f.session),
+	})
+	return toolinternal.NewToolContext(ctx, "", nil)
+}
+
+func (f *fixture) run(ctx tool.Context, name string, args map[string]any) (map[string]any, error) {
+	f.t.Helper()
+	tools, err := f.ts.Tools(ctx)
+	if err != nil {
+		f.t.Fatal(err)
+	}
+	for _, tl := range tools {
+		if tl.Name() == name {
+			return tl.(toolinternal.FunctionTool).Run(ctx, args)
+		}
+	}
+	f.t.Fatalf("tool %q not found", name)
+	return nil, nil
+}
+
+func (f *fixture) mustRun(ctx tool.Context, name string, args map[string]any) map[string]any {
+	f.t.Helper()
+	got, err := f.run(ctx, name, args)
+	if err != nil {
+		f.t.Fatalf("%s(%v) error = %v", name, args, err)
+	}
+	return got
+}
+
+// ref returns the reference of the element with the given role and name.
+func ref(t *testing.T, state map[string]any, role, name string) string {
+	t.Helper()
+	elements, _ := state["elements"].([]any)
+	for _, e := range elements {
+		e := e.(map[string]any)
+		if e["role"] == role && e["name"] == name {
+			return e["ref"].(string)
+		}
+	}
+	t.Fatalf("no %s %q in %v", role, name, elements)
+	return ""
+}
+
+func TestNavigateAndClick(t *testing.T) {
+	srv := newServer(t)
+	f := newFixture(t, browsertool.Config{})
+	ctx := f.newInvocation()
+
+	state := f.mustRun(ctx, "browser_navigate", map[string]any{"url": srv.URL})
+	if got, want := state["title"], "Home"; got != want {
+		t.Errorf("title = %v, want %v", got, want)
+	}
+	if got, want := state["text"], "Welcome\nFind your order.\nSearch orders"; got != want {
+		t.Errorf("text = %q, want %q", got, want)
+	}
+
+	state = f.mustRun(ctx, "browser_click", map[string]any{"ref": ref(t, state, "link", "Search orders")})
+	if got, want := state["url"], srv.URL+"/search"; got != want {
+		t.Errorf("url = %v, want %v", got, want)
+	}
+	ref(t, state, "textbox", "Order number")
+	ref(t, state, "button", "Find")
+}
+
+func TestFillAndSubmit(t *testing.T) {
+	for _, method := range []string{"get", "post"} {
+		t.Run(method, func(t *testing.T) {
+			srv := newServer(t)
+			f := newFixture(t, browsertool.Config{})
+			ctx := f.newInvocation()
+
+			state := f.mustRun(ctx, "browser_navigate", map[string]any{"url": srv.URL + "/search?method=" + method})
+			input := ref(t, state, "textbox", "Order number")
+			state = f.mustRun(ctx, "browser_fill", map[string]any{"ref": input, "value": "A42"})
+			for _, e := range state["elements"].([]any) {
+				if e := e.(map[string]any); e["ref"] == input && e["value"] != "A42" {
+					t.Errorf("value after fill = %v, want A42", e["value"])
+				}
+			}
+			checkbox := ""
+			for _, e := range state["elements"].([]any) {
+				if e := e.(map[string]any); e["role"] == "checkbox" {
+					checkbox = e["ref"].(string)
+				}
+			}
+			f.mustRun(ctx, "browser_click", map[string]any{"ref": checkbox})
+
+			state = f.mustRun(ctx, "browser_click", map[string]any{"ref": ref(t, state, "button", "Find")})
+			want := fmt.Sprintf("%s results for A42 archived=yes", strings.ToUpper(method))
+			if got := state["text"]; got != want {
+				t.Errorf("text = %q, want %q", got, want)
+			}
+		})
+	}
+}
+
+func TestGetText(t *testing.T) {
+	srv := newServer(t)
+	f := newFixture(t, browsertool.Config{MaxTextLength: 100})
+	ctx := f.newInvocation()
+
+	state := f.mustRun(ctx, "browser_navigate", map[string]any{"url": srv.URL + "/long"})
+	var text strings.Builder
+	text.WriteString(state["text"].(string))
+	for state["next_offset"] != nil {
+		state = f.mustRun(ctx, "browser_get_text", map[string]any{"offset": state["next_offset"]})
+		if _, ok := state["elements"]; ok {
+			t.Error("browser_get_text returned elements")
+		}
+		text.WriteString(state["text"].(string))
+	}
+	if got, want := text.String(), strings.Repeat("a", 250); got != want {
+		t.Errorf("text = %q, want %q", got, want)
+	}
+}
+
+func TestAllowedHosts(t *testing.T) {
+	srv := newServer(t)
+	f := newFixture(t, browsertool.Config{AllowedHosts: []string{"127.0.0.1"}})
+	ctx := f.newInvocation()
+
+	f.mustRun(ctx, "browser_navigate", map[string]any{"url": srv.URL})
+	if _, err := f.run(ctx, "browser_navigate", map[string]any{"url": "http://example.com/"}); err == nil {
+		t.Error("browser_navigate() to a disallowed host succeeded, want error")
+	}
+	if _, err := f.run(ctx, "browser_navigate", map[string]any{"url": srv.URL + "/elsewhere"}); err == nil {
+		t.Error("browser_navigate() redirected to a disallowed host succeeded, want error")
+	}
+}
+
+func TestPageLifecycle(t *testing.T) {
+	srv := newServer(t)
+	f := newFixture(t, browsertool.Config{MaxPages: 1})
+	ctx := f.newInvocation()
+
+	state := f.mustRun(ctx, "browser_navigate", map[string]any{"url": srv.URL})
+	link := ref(t, state, "link", "Search orders")
+
+	if _, err := f.run(f.newInvocation(), "browser_navigate", map[string]any{"url": srv.URL}); err == nil {
+		t.Error("browser_navigate() beyond MaxPages succeeded, want error")
+	}
+
+	if _, err := f.ts.AfterAgentCallback()(ctx); err != nil {
+		t.Fatalf("AfterAgentCallback() error = %v", err)
+	}
+	if _, err := f.run(ctx, "browser_click", map[string]any{"ref": link}); err == nil {
+		t.Error("browser_click() after the page was closed succeeded, want error")
+	}
+
+	f.ts.AfterAgentCallback()(ctx)
+	f.mustRun(f.newInvocation(), "browser_navigate", map[string]any{"url": srv.URL})
+}
+
+func TestMaxActions(t *testing.T) {
+	srv := newServer(t)
+	f := newFixture(t, browsertool.Config{MaxActions: 2})
+	ctx := f.newInvocation()
+
+	state := f.mustRun(ctx, "browser_navigate", map[string]any{"url": srv.URL})
+	f.mustRun(ctx, "browser_click", map[string]any{"ref": ref(t, state, "link", "Search orders")})
+	// Reading the page is not an action.
+	f.mustRun(ctx, "browser_get_text", map[string]any{})
+	if _, err := f.run(ctx, "browser_navigate", map[string]any{"url": srv.URL}); err == nil {
+		t.Error("browser_navigate() beyond MaxActions succeeded, want error")
+	}
+	f.mustRun(f.newInvocation(), "browser_navigate", map[string]any{"url": srv.URL})
+}
+
+func TestScreenshot(t *testing.T) {
+	_, debuggerURL := newFakeBrowser(t)
+	driver := browsertool.NewCDPDriver(browsertool.CDPDriverConfig{DebuggerURL: debuggerURL})
+	t.Cleanup(func() { driver.Close() })
+	f := newFixture(t, browsertool.Config{Driver: driver, Screenshots: true})
+	ctx := f.newInvocation()
+
+	f.mustRun(ctx, "browser_navigate", map[string]any{"url": "https://example.com/"})
+	response, parts := toolinternal.SplitContentResult(f.mustRun(ctx, "browser_screenshot", map[string]any{}))
+	if got, want := response["output"], "Screenshot of https://example.com/ (Fake)"; got != want {
+		t.Errorf("output = %v, want %v", got, want)
+	}
+	if len(parts) != 1 || parts[0].InlineData == nil || parts[0].InlineData.MIMEType != "image/png" || string(parts[0].InlineData.Data) != "png" {
+		t.Errorf("parts = %v, want the PNG screenshot", parts)
+	}
+
+	// The pages of the HTTP driver do not support screenshots.
+	f = newFixture(t, browsertool.Config{Screenshots: true})
+	if _, err := f.run(f.newInvocation(), "browser_screenshot", map[string]any{}); err == nil {
+		t.Error("browser_screenshot() with the HTTP driver succeeded, want error")
+	}
+}
@@ -0,0 +1,116 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cachetool
+
+import (
+	"container/list"
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Cache stores the results of the tool calls. Implementations must be safe
+// for concurrent use.
+type Cache interface {
+	// Get returns the result stored under the key, and whether there is one
+	// that has not expired.
+	Get(ctx context.Context, key string) (result map[string]any, ok bool, err error)
+	// Set stores the result under the key for the ttl. A zero ttl means the
+	// result does not expire.
+	Set(ctx context.Context, key string, result map[string]any, ttl time.Duration) error
+}
+
+// InMemoryCache returns a Cache keeping the results in memory. The results
+// are stored as their JSON encoding, so that the callers get their own copy
+// of a result, as they would from a remote cache.
+func InMemoryCache() Cache {
+	return LRUCache(0)
+}
+
+// LRUCache returns an in-memory Cache, as InMemoryCache, holding at most
+// maxEntries results: storing another one evicts the least recently used
+// result. Zero means no limit.
+func LRUCache(maxEntries int) Cache {
+	return &inMemoryCache{entries: make(map[string]*list.Element), maxEntries: maxEntries, lru: list.New()}
+}
+
+type cacheEntry struct {
+	key     string
+	value   []byte
+	expires time.Time // zero if the entry does not expire
+}
+
+type inMemoryCache struct {
+	maxEntries int
+
+	mu      sync.Mutex
+	entries map[string]*list.Element
+	// lru holds the entries, the most recently used first.
+	lru *list.List
+}
+
+func (c *inMemoryCache) Get(_ context.Context, key string) (map[string]any, bool, error) {
+	c.mu.Lock()
+	elem, ok := c.entries[key]
+	var value []byte
+	if ok {
+		entry := elem.Value.(*cacheEntry)
+		if !entry.expires.IsZero() && !time.Now().Before(entry.expires) {
+			c.remove(elem)
+			ok = false
+		} else {
+			c.lru.MoveToFront(elem)
+			value = entry.value
+		}
+	}
+	c.mu.Unlock()
+	if !ok {
+		return nil, false, nil
+	}
+	var result map[string]any
+	if err := json.Unmarshal(value, &result); err != nil {
+		return nil, false, fmt.Errorf("failed to decode the cached result: %w", err)
+	}
+	return result, true, nil
+}
+
+func (c *inMemoryCache) Set(_ context.Context, key string, result map[string]any, ttl time.Duration) error {
+	value, err := json.Marshal(result)
+	if err != nil {
+		return fmt.Errorf("failed to encode the result: %w", err)
+	}
+	entry := &cacheEntry{key: key, value: value}
+	if ttl > 0 {
+		entry.expires = time.Now().Add(ttl)
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if elem, ok := c.entries[key]; ok {
+		c.remove(elem)
+	}
+	c.entries[key] = c.lru.PushFront(entry)
+	if c.maxEntries > 0 && c.lru.Len() > c.maxEntries {
+		c.remove(c.lru.Back())
+	}
+	return nil
+}
+
+// remove removes the entry of the element. c.mu must be held.
+func (c *inMemoryCache) remove(elem *list.Element) {
+	c.lru.Remove(elem)
+	delete(c.entries, elem.Value.(*cacheEntry).key)
+}
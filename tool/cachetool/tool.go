@@ -0,0 +1,163 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package cachetool wraps idempotent function tools, e.g. geocoding or
+// currency lookups, to cache their results.
+//
+// The results are cached under a key derived from the tool name and the
+// arguments, see [Key], so that the calls with the same arguments, in any
+// order, share their result. Only the results are cached: the state changes,
+// artifacts and other actions of a call are not replayed on a hit, which is
+// still recorded in the function response event of the call, see [Hits].
+package cachetool
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"maps"
+	"slices"
+	"time"
+
+	"google.golang.org/adk/internal/toolinternal"
+	"google.golang.org/adk/model"
+	"google.golang.org/adk/session"
+	"google.golang.org/adk/tool"
+)
+
+// Cacheable is implemented by the tools telling whether their results may be
+// cached. WithCache does not wrap the tools reporting false.
+type Cacheable interface {
+	Cacheable() bool
+}
+
+// NonCacheable marks the function tool t as non-cacheable, so that WithCache
+// returns it as is. The other tools are never cached, and returned as is.
+func NonCacheable(t tool.Tool) tool.Tool {
+	funcTool, ok := t.(toolinternal.FunctionTool)
+	if !ok {
+		return t
+	}
+	return &nonCacheableTool{FunctionTool: funcTool}
+}
+
+// WithCache returns a tool behaving like the function tool t, except that its
+// results are stored in the cache for the ttl, zero meaning no expiration,
+// and returned from the cache for the next calls with the same arguments.
+// Errors are not cached, and the cache errors are ignored: the tool runs as
+// if the result was not cached.
+//
+// The tools which are not function tools, long-running or marked with
+// NonCacheable are returned as is.
+func WithCache(t tool.Tool, cache Cache, ttl time.Duration) tool.Tool {
+	funcTool, ok := t.(toolinternal.FunctionTool)
+	if !ok || t.IsLongRunning() {
+		return t
+	}
+	if c, ok := t.(Cacheable); ok && !c.Cacheable() {
+		return t
+	}
+	return &cachingTool{FunctionTool: funcTool, cache: cache, ttl: ttl}
+}
+
+// MetadataKey is the key of the cache hits in the CustomMetadata of the
+// function response events: a map from the IDs of the calls whose result
+// came from the cache to their cache key. See Hits.
+const MetadataKey = "cache_hits"
+
+// Hits returns the IDs of the function calls of the function response event
+// whose results came from the cache, sorted, so that the hits are observable
+// in the session although the tools did not run.
+func Hits(ev *session.Event) []string {
+	if ev == nil {
+		return nil
+	}
+	hits, _ := ev.CustomMetadata[MetadataKey].(map[string]any)
+	return slices.Sorted(maps.Keys(hits))
+}
+
+// Key returns the cache key of a call of the tool: the hex-encoded SHA-256
+// hash of the tool name and of the canonical JSON encoding of the arguments,
+// see tool.CanonicalJSON, so that the key depends neither on the order of the
+// keys of the maps nor on the Go types of the numbers.
+func Key(toolName string, args any) (string, error) {
+	encoded, err := tool.CanonicalJSON(args)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode the arguments: %w", err)
+	}
+	h := sha256.New()
+	h.Write([]byte(toolName))
+	h.Write([]byte{0})
+	h.Write(encoded)
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+type cachingTool struct {
+	toolinternal.FunctionTool
+	cache Cache
+	ttl   time.Duration
+}
+
+// Unwrap returns the wrapped tool.
+func (t *cachingTool) Unwrap() toolinternal.FunctionTool {
+	return t.FunctionTool
+}
+
+// ProcessRequest lets the wrapped tool process the request, and registers the
+// wrapper in its place, see toolinternal.RegisterWrapper.
+func (t *cachingTool) ProcessRequest(ctx tool.Context, req *model.LLMRequest) error {
+	return toolinternal.RegisterWrapper(ctx, req, t.FunctionTool, t)
+}
+
+// Run returns the cached result of the call if any, and otherwise runs the
+// wrapped tool and caches its result.
+func (t *cachingTool) Run(ctx tool.Context, args any) (map[string]any, error) {
+	key, err := Key(t.Name(), args)
+	if err != nil {
+		return t.FunctionTool.Run(ctx, args)
+	}
+	cacheCtx := context.Context(context.Background())
+	if ctx != nil {
+		cacheCtx = ctx
+	}
+	if result, ok, err := t.cache.Get(cacheCtx, key); err == nil && ok {
+		toolinternal.SetResponseMetadata(ctx, MetadataKey, key)
+		return result, nil
+	}
+	result, err := t.FunctionTool.Run(ctx, args)
+	if err != nil {
+		return result, err
+	}
+	_ = t.cache.Set(cacheCtx, key, result, t.ttl)
+	return result, nil
+}
+
+type nonCacheableTool struct {
+	toolinternal.FunctionTool
+}
+
+// Cacheable implements Cacheable.
+func (t *nonCacheableTool) Cacheable() bool { return false }
+
+// Unwrap returns the wrapped tool.
+func (t *nonCacheableTool) Unwrap() toolinternal.FunctionTool {
+	return t.FunctionTool
+}
+
+// ProcessRequest lets the wrapped tool process the request, and registers the
+// wrapper in its place, see toolinternal.RegisterWrapper.
+func (t *nonCacheableTool) ProcessRequest(ctx tool.Context, req *model.LLMRequest) error {
+	return toolinternal.RegisterWrapper(ctx, req, t.FunctionTool, t)
+}
@@ -0,0 +1,222 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cachetool_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/agent/llmagent"
+	"google.golang.org/adk/internal/testutil"
+	"google.golang.org/adk/internal/toolinternal"
+	"google.golang.org/adk/model"
+	"google.golang.org/adk/tool"
+	"google.golang.org/adk/tool/cachetool"
+	"google.golang.org/adk/tool/functiontool"
+)
+
+type geocodeArgs struct {
+	City    string `json:"city"`
+	Country string `json:"country,omitempty"`
+}
+
+// newGeocodeTool returns a tool counting its runs, failing for the city
+// "fail".
+func newGeocodeTool(t *testing.T, runs *int) tool.Tool {
+	t.Helper()
+	geocodeTool, err := functiontool.New(functiontool.Config{
+		Name:        "geocode",
+		Description: "Returns the coordinates of a city.",
+	}, func(_ tool.Context, args geocodeArgs) (map[string]any, error) {
+		*runs++
+		if args.City == "fail" {
+			return nil, errors.New("lookup failed")
+		}
+		return map[string]any{"city": args.City, "run": *runs}, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return geocodeTool
+}
+
+func TestWithCache(t *testing.T) {
+	var runs int
+	cached := cachetool.WithCache(newGeocodeTool(t, &runs), cachetool.InMemoryCache(), 0)
+	run := cached.(toolinternal.FunctionTool).Run
+
+	first, err := run(nil, map[string]any{"city": "Paris", "country": "FR"})
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	// The same arguments, in another order, hit the cache.
+	second, err := run(nil, map[string]any{"country": "FR", "city": "Paris"})
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if diff := cmp.Diff(first, second); diff != "" {
+		t.Errorf("cached result mismatch (-first +second):\n%s", diff)
+	}
+	if runs != 1 {
+		t.Errorf("got %d runs, want 1", runs)
+	}
+
+	if _, err := run(nil, map[string]any{"city": "Lyon", "country": "FR"}); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if runs != 2 {
+		t.Errorf("got %d runs after a call with other arguments, want 2", runs)
+	}
+
+	// Errors are not cached.
+	for range 2 {
+		if _, err := run(nil, map[string]any{"city": "fail"}); err == nil {
+			t.Fatalf("Run() succeeded, want an error")
+		}
+	}
+	if runs != 4 {
+		t.Errorf("got %d runs after failed calls, want 4", runs)
+	}
+}
+
+func TestWithCache_TTL(t *testing.T) {
+	var runs int
+	cached := cachetool.WithCache(newGeocodeTool(t, &runs), cachetool.InMemoryCache(), time.Millisecond)
+	run := cached.(toolinternal.FunctionTool).Run
+	args := map[string]any{"city": "Paris"}
+
+	if _, err := run(nil, args); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	time.Sleep(5 * time.Millisecond)
+	if _, err := run(nil, args); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if runs != 2 {
+		t.Errorf("got %d runs after the expiration, want 2", runs)
+	}
+}
+
+func TestWithCache_NonCacheable(t *testing.T) {
+	var runs int
+	geocodeTool := cachetool.NonCacheable(newGeocodeTool(t, &runs))
+	if got := cachetool.WithCache(geocodeTool, cachetool.InMemoryCache(), 0); got != geocodeTool {
+		t.Errorf("WithCache() wrapped a non-cacheable tool")
+	}
+}
+
+func TestWithCache_ProcessRequest(t *testing.T) {
+	var runs int
+	cached := cachetool.WithCache(newGeocodeTool(t, &runs), cachetool.InMemoryCache(), 0)
+	req := &model.LLMRequest{}
+	if err := cached.(toolinternal.RequestProcessor).ProcessRequest(nil, req); err != nil {
+		t.Fatalf("ProcessRequest() error = %v", err)
+	}
+	if req.Tools["geocode"] != cached {
+		t.Errorf("Tools[%q] = %v, want the wrapper", "geocode", req.Tools["geocode"])
+	}
+}
+
+func TestKey(t *testing.T) {
+	a, err := cachetool.Key("geocode", map[string]any{"city": "Paris", "opts": map[string]any{"x": 1, "y": 2}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := cachetool.Key("geocode", map[string]any{"opts": map[string]any{"y": 2, "x": 1}, "city": "Paris"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a != b {
+		t.Errorf("Key() depends on the order of the map keys: %q != %q", a, b)
+	}
+	c, err := cachetool.Key("forecast", map[string]any{"city": "Paris", "opts": map[string]any{"x": 1, "y": 2}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a == c {
+		t.Errorf("Key() is the same for distinct tools")
+	}
+}
+
+func TestWithCache_Hits(t *testing.T) {
+	var runs int
+	cached := cachetool.WithCache(newGeocodeTool(t, &runs), cachetool.InMemoryCache(), 0)
+	call := func(id, city string) *genai.Part {
+		return &genai.Part{FunctionCall: &genai.FunctionCall{ID: id, Name: "geocode", Args: map[string]any{"city": city}}}
+	}
+	model := &testutil.MockModel{Responses: []*genai.Content{
+		genai.NewContentFromParts([]*genai.Part{call("c1", "Paris"), call("c2", "Lyon"), call("c3", "Paris")}, "model"),
+		genai.NewContentFromText("done", "model"),
+	}}
+	a, err := llmagent.New(llmagent.Config{Name: "agent", Model: model, Tools: []tool.Tool{cached}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var hits []string
+	for ev, err := range testutil.NewTestAgentRunner(t, a).Run(t, "session", "geocode") {
+		if err != nil {
+			t.Fatal(err)
+		}
+		hits = append(hits, cachetool.Hits(ev)...)
+	}
+	// The hit is recorded in the function response event.
+	if diff := cmp.Diff([]string{"c3"}, hits); diff != "" {
+		t.Errorf("Hits() mismatch (-want +got):\n%s", diff)
+	}
+	if runs != 2 {
+		t.Errorf("got %d runs, want 2", runs)
+	}
+}
+
+func TestLRUCache(t *testing.T) {
+	ctx := t.Context()
+	cache := cachetool.LRUCache(2)
+	set := func(key string) {
+		t.Helper()
+		if err := cache.Set(ctx, key, map[string]any{"key": key}, 0); err != nil {
+			t.Fatalf("Set(%q) error = %v", key, err)
+		}
+	}
+	has := func(key string) bool {
+		t.Helper()
+		_, ok, err := cache.Get(ctx, key)
+		if err != nil {
+			t.Fatalf("Get(%q) error = %v", key, err)
+		}
+		return ok
+	}
+	set("a")
+	set("b")
+	// Getting a makes b the least recently used result.
+	if !has("a") {
+		t.Fatal("Get(a) missed")
+	}
+	set("c")
+	if has("b") {
+		t.Error("Get(b) hit, want b evicted")
+	}
+	if !has("a") || !has("c") {
+		t.Error("Get() missed a recently used result")
+	}
+	// Storing a key again does not evict another result.
+	set("c")
+	if !has("a") {
+		t.Error("Get(a) missed after storing c again")
+	}
+}
@@ -0,0 +1,138 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package clarifytool provides a tool that lets the model ask the user a
+// clarifying question with optional suggested answers.
+//
+// The tool is long-running: when the model calls it, the agent emits a
+// function response event describing the question and ends its turn without
+// summarization. The client renders the question (e.g. as quick-reply buttons
+// built from the suggested options) and resumes the conversation by sending
+// the user's answer as a function response, built with [NewAnswer]. The answer
+// is then passed to the model as the result of the original tool call.
+package clarifytool
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/session"
+	"google.golang.org/adk/tool"
+	"google.golang.org/adk/tool/functiontool"
+)
+
+// Name is the name of the clarification tool as seen by the model.
+const Name = "ask_clarifying_question"
+
+// StatusAwaitingAnswer is the status reported in the function response while
+// the question is waiting for the user's answer.
+const StatusAwaitingAnswer = "awaiting_user_answer"
+
+// Args are the arguments the model provides when asking a question.
+type Args struct {
+	// Question to ask the user.
+	Question string `json:"question" jsonschema:"the clarifying question to ask the user"`
+	// Options are suggested answers the user can pick from.
+	Options []string `json:"options,omitempty" jsonschema:"optional short suggested answers the user can pick from"`
+}
+
+// Result is the function response emitted when the model asks a question.
+type Result struct {
+	Status   string   `json:"status"`
+	Question string   `json:"question"`
+	Options  []string `json:"options,omitempty"`
+}
+
+// Request is a clarification requested by the model, extracted from an event
+// with [FromEvent].
+type Request struct {
+	// FunctionCallID identifies the tool call the answer must refer to.
+	FunctionCallID string
+	Question       string
+	Options        []string
+}
+
+func askClarifyingQuestion(ctx tool.Context, args Args) (Result, error) {
+	question := strings.TrimSpace(args.Question)
+	if question == "" {
+		return Result{}, errors.New("question must not be empty")
+	}
+	// Suspend the agent until the user answers.
+	ctx.Actions().SkipSummarization = true
+	return Result{
+		Status:   StatusAwaitingAnswer,
+		Question: question,
+		Options:  args.Options,
+	}, nil
+}
+
+// New creates an instance of the clarification tool.
+func New() (tool.Tool, error) {
+	clarifyTool, err := functiontool.New(functiontool.Config{
+		Name: Name,
+		Description: "Asks the user a clarifying question when the request is ambiguous or information is missing.\n" +
+			"Provide suggested answers in options when there is a small set of likely answers.\n" +
+			"The user's answer is returned as the result of this call.",
+		IsLongRunning: true,
+	}, askClarifyingQuestion)
+	if err != nil {
+		return nil, fmt.Errorf("error creating clarify tool: %w", err)
+	}
+	return clarifyTool, nil
+}
+
+// FromEvent returns the clarifications requested in the given event.
+// It returns nil if the event does not contain any.
+func FromEvent(ev *session.Event) []*Request {
+	if ev == nil || ev.Content == nil {
+		return nil
+	}
+	var requests []*Request
+	for _, part := range ev.Content.Parts {
+		fr := part.FunctionResponse
+		if fr == nil || fr.Name != Name {
+			continue
+		}
+		var result Result
+		raw, err := json.Marshal(fr.Response)
+		if err != nil || json.Unmarshal(raw, &result) != nil || result.Status != StatusAwaitingAnswer {
+			continue
+		}
+		requests = append(requests, &Request{
+			FunctionCallID: fr.ID,
+			Question:       result.Question,
+			Options:        result.Options,
+		})
+	}
+	return requests
+}
+
+// NewAnswer creates the user content answering the clarification request.
+// It should be passed to runner.Run to resume the conversation.
+func NewAnswer(req *Request, answer string) *genai.Content {
+	return &genai.Content{
+		Role: genai.RoleUser,
+		Parts: []*genai.Part{{
+			FunctionResponse: &genai.FunctionResponse{
+				ID:       req.FunctionCallID,
+				Name:     Name,
+				Response: map[string]any{"answer": answer},
+			},
+		}},
+	}
+}
@@ -0,0 +1,130 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clarifytool_test
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/agent/llmagent"
+	"google.golang.org/adk/internal/testutil"
+	"google.golang.org/adk/tool"
+	"google.golang.org/adk/tool/clarifytool"
+)
+
+func TestClarifyToolSuspendsAndResumes(t *testing.T) {
+	clarifyTool, err := clarifytool.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !clarifyTool.IsLongRunning() {
+		t.Errorf("clarify tool IsLongRunning() = false, want true")
+	}
+
+	mockModel := &testutil.MockModel{
+		Responses: []*genai.Content{
+			genai.NewContentFromFunctionCall(clarifytool.Name, map[string]any{
+				"question": "Which city?",
+				"options":  []any{"Paris", "Rome"},
+			}, genai.RoleModel),
+			genai.NewContentFromText("Booked a trip to Paris.", genai.RoleModel),
+		},
+	}
+	a, err := llmagent.New(llmagent.Config{
+		Name:  "travel_agent",
+		Model: mockModel,
+		Tools: []tool.Tool{clarifyTool},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	runner := testutil.NewTestAgentRunner(t, a)
+
+	events, err := testutil.CollectEvents(runner.Run(t, "session", "book me a trip"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 2 {
+		t.Fatalf("got %d events, want 2 (function call and clarification)", len(events))
+	}
+	if len(mockModel.Requests) != 1 {
+		t.Fatalf("got %d model requests, want 1: the agent must wait for the answer", len(mockModel.Requests))
+	}
+
+	requests := clarifytool.FromEvent(events[1])
+	if len(requests) != 1 {
+		t.Fatalf("clarifytool.FromEvent() returned %d requests, want 1", len(requests))
+	}
+	want := &clarifytool.Request{
+		FunctionCallID: events[0].Content.Parts[0].FunctionCall.ID,
+		Question:       "Which city?",
+		Options:        []string{"Paris", "Rome"},
+	}
+	if diff := cmp.Diff(want, requests[0]); diff != "" {
+		t.Errorf("clarifytool.FromEvent() mismatch (-want +got):\n%s", diff)
+	}
+
+	texts, err := testutil.CollectTextParts(runner.RunContent(t, "session", clarifytool.NewAnswer(requests[0], "Paris")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff([]string{"Booked a trip to Paris."}, texts); diff != "" {
+		t.Errorf("response mismatch (-want +got):\n%s", diff)
+	}
+	if len(mockModel.Requests) != 2 {
+		t.Fatalf("got %d model requests, want 2", len(mockModel.Requests))
+	}
+	contents := mockModel.Requests[1].Contents
+	wantLast := genai.NewContentFromFunctionResponse(clarifytool.Name, map[string]any{"answer": "Paris"}, genai.RoleUser)
+	if diff := cmp.Diff(wantLast, contents[len(contents)-1]); diff != "" {
+		t.Errorf("last request content mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestClarifyToolRejectsEmptyQuestion(t *testing.T) {
+	mockModel := &testutil.MockModel{
+		Responses: []*genai.Content{
+			genai.NewContentFromFunctionCall(clarifytool.Name, map[string]any{"question": " "}, genai.RoleModel),
+			genai.NewContentFromText("Let me rephrase.", genai.RoleModel),
+		},
+	}
+	clarifyTool, err := clarifytool.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	a, err := llmagent.New(llmagent.Config{
+		Name:  "agent",
+		Model: mockModel,
+		Tools: []tool.Tool{clarifyTool},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	events, err := testutil.CollectEvents(testutil.NewTestAgentRunner(t, a).Run(t, "session", "hi"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 3 {
+		t.Fatalf("got %d events, want 3", len(events))
+	}
+	if got := clarifytool.FromEvent(events[1]); got != nil {
+		t.Errorf("clarifytool.FromEvent() = %v, want nil for a failed call", got)
+	}
+	if _, ok := events[1].Content.Parts[0].FunctionResponse.Response["error"]; !ok {
+		t.Errorf("function response = %v, want an error", events[1].Content.Parts[0].FunctionResponse.Response)
+	}
+}
@@ -0,0 +1,105 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package confirmtool wraps sensitive tools, e.g. mutating data, so that
+// their calls wait for the confirmation of the user, whatever their kind:
+// function tools, MCP tools or OpenAPI tools.
+//
+// A call requiring a confirmation is not run: the agent emits the
+// confirmation request, carrying the call, and the invocation ends. The call
+// is run once the client sends the confirmation of the user, and fails with
+// toolconfirmation.ErrDeclined if the user declined it. See package
+// toolconfirmation for the protocol.
+package confirmtool
+
+import (
+	"fmt"
+
+	"google.golang.org/adk/internal/toolinternal"
+	"google.golang.org/adk/model"
+	"google.golang.org/adk/tool"
+	"google.golang.org/adk/tool/toolconfirmation"
+)
+
+// Policy decides whether a call with the arguments requires the confirmation
+// of the user, e.g. only above an amount, and returns the hint shown to the
+// user to confirm it.
+type Policy func(ctx tool.Context, args map[string]any) (required bool, hint string)
+
+// Always is the policy requiring the confirmation of all the calls, with a
+// hint naming the tool.
+func Always(ctx tool.Context, args map[string]any) (bool, string) {
+	return true, ""
+}
+
+// WithConfirmation returns a tool behaving like the function tool t, except
+// that its calls for which the policy requires a confirmation wait for the
+// confirmation of the user before being run by t. A nil policy is Always. An
+// empty hint is replaced with one naming the tool.
+//
+// The tools which are not function tools, such as the built-in tools of the
+// models, are returned as is.
+func WithConfirmation(t tool.Tool, policy Policy) tool.Tool {
+	funcTool, ok := t.(toolinternal.FunctionTool)
+	if !ok || funcTool.Declaration() == nil {
+		return t
+	}
+	if policy == nil {
+		policy = Always
+	}
+	return &confirmingTool{FunctionTool: funcTool, policy: policy}
+}
+
+type confirmingTool struct {
+	toolinternal.FunctionTool
+	policy Policy
+}
+
+// Unwrap returns the wrapped tool.
+func (t *confirmingTool) Unwrap() toolinternal.FunctionTool {
+	return t.FunctionTool
+}
+
+// ProcessRequest lets the wrapped tool process the request, and registers the
+// wrapper in its place, see toolinternal.RegisterWrapper.
+func (t *confirmingTool) ProcessRequest(ctx tool.Context, req *model.LLMRequest) error {
+	return toolinternal.RegisterWrapper(ctx, req, t.FunctionTool, t)
+}
+
+// Run runs the wrapped tool if the call requires no confirmation, or was
+// confirmed. Otherwise, it requests the confirmation and returns a result
+// telling the model that the call waits for it, or fails if the call was
+// declined.
+func (t *confirmingTool) Run(ctx tool.Context, args any) (map[string]any, error) {
+	if ctx == nil {
+		return nil, fmt.Errorf("tool %q may require a confirmation, which needs a tool context", t.Name())
+	}
+	m, _ := args.(map[string]any)
+	required, hint := t.policy(ctx, m)
+	if !required {
+		return t.FunctionTool.Run(ctx, args)
+	}
+	confirmation := ctx.ToolConfirmation()
+	if confirmation == nil {
+		if hint == "" {
+			hint = fmt.Sprintf("Please approve or reject the call of the tool %q.", t.Name())
+		}
+		ctx.RequestConfirmation(hint, nil)
+		return map[string]any{"status": "This tool call requires the confirmation of the user, waiting for it."}, nil
+	}
+	if !confirmation.Confirmed {
+		return nil, fmt.Errorf("tool %q: %w", t.Name(), toolconfirmation.ErrDeclined)
+	}
+	return t.FunctionTool.Run(ctx, args)
+}
@@ -0,0 +1,150 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package confirmtool_test
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/agent/llmagent"
+	"google.golang.org/adk/internal/testutil"
+	"google.golang.org/adk/tool"
+	"google.golang.org/adk/tool/confirmtool"
+	"google.golang.org/adk/tool/functiontool"
+	"google.golang.org/adk/tool/geminitool"
+	"google.golang.org/adk/tool/toolconfirmation"
+)
+
+type refundArgs struct {
+	Amount float64 `json:"amount"`
+}
+
+func TestWithConfirmation(t *testing.T) {
+	var refunded []float64
+	refund, err := functiontool.New(functiontool.Config{
+		Name:        "refund",
+		Description: "refunds the customer",
+	}, func(ctx tool.Context, args refundArgs) (map[string]any, error) {
+		refunded = append(refunded, args.Amount)
+		return map[string]any{"refunded": args.Amount}, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The large refunds require a confirmation.
+	policy := func(ctx tool.Context, args map[string]any) (bool, string) {
+		amount, _ := args["amount"].(float64)
+		return amount > 100, fmt.Sprintf("Refund %v?", amount)
+	}
+	model := &testutil.MockModel{Responses: []*genai.Content{
+		genai.NewContentFromFunctionCall("refund", map[string]any{"amount": 10.0}, "model"),
+		genai.NewContentFromText("refunded", "model"),
+		genai.NewContentFromFunctionCall("refund", map[string]any{"amount": 500.0}, "model"),
+		genai.NewContentFromText("refunded", "model"),
+		genai.NewContentFromFunctionCall("refund", map[string]any{"amount": 900.0}, "model"),
+		genai.NewContentFromText("not refunded", "model"),
+	}}
+	a, err := llmagent.New(llmagent.Config{
+		Name:                     "agent",
+		Model:                    model,
+		DisallowTransferToParent: true,
+		DisallowTransferToPeers:  true,
+		Tools:                    []tool.Tool{confirmtool.WithConfirmation(refund, policy)},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	runner := testutil.NewTestAgentRunner(t, a)
+	// run runs the agent with the content, and returns the responses of the
+	// calls and the confirmation request the invocation ends with, if any.
+	run := func(content *genai.Content) ([]map[string]any, *genai.FunctionCall) {
+		t.Helper()
+		var responses []map[string]any
+		var request *genai.FunctionCall
+		for ev, err := range runner.RunContent(t, "session1", content) {
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, p := range ev.LLMResponse.Content.Parts {
+				if p.FunctionResponse != nil {
+					responses = append(responses, p.FunctionResponse.Response)
+				}
+				if p.FunctionCall != nil && p.FunctionCall.Name == toolconfirmation.FunctionCallName {
+					if !slices.Contains(ev.LongRunningToolIDs, p.FunctionCall.ID) {
+						t.Errorf("confirmation request %v is not long-running", p.FunctionCall)
+					}
+					request = p.FunctionCall
+				}
+			}
+		}
+		return responses, request
+	}
+	answer := func(request *genai.FunctionCall, confirmed bool) *genai.Content {
+		resp := genai.NewContentFromFunctionResponse(toolconfirmation.FunctionCallName, map[string]any{"confirmed": confirmed}, genai.RoleUser)
+		resp.Parts[0].FunctionResponse.ID = request.ID
+		return resp
+	}
+
+	// A small refund runs without confirmation.
+	responses, request := run(genai.NewContentFromText("refund 10", genai.RoleUser))
+	if request != nil {
+		t.Errorf("confirmation requested for a small refund: %v", request)
+	}
+	if diff := cmp.Diff([]map[string]any{{"refunded": 10.0}}, responses); diff != "" {
+		t.Errorf("responses mismatch (-want +got):\n%s", diff)
+	}
+
+	// A large refund waits for the confirmation, which carries the call.
+	_, request = run(genai.NewContentFromText("refund 500", genai.RoleUser))
+	if request == nil {
+		t.Fatal("no confirmation requested for a large refund")
+	}
+	original := request.Args["original_function_call"].(map[string]any)
+	if original["name"] != "refund" || original["args"].(map[string]any)["amount"] != 500.0 {
+		t.Errorf("original function call = %v, want the call of refund", original)
+	}
+	if got := request.Args["tool_confirmation"].(map[string]any)["hint"]; got != "Refund 500?" {
+		t.Errorf("hint = %v, want the hint of the policy", got)
+	}
+	if len(refunded) != 1 {
+		t.Fatalf("the tool ran before the confirmation: %v", refunded)
+	}
+	responses, _ = run(answer(request, true))
+	if diff := cmp.Diff([]map[string]any{{"refunded": 500.0}}, responses); diff != "" {
+		t.Errorf("responses after the confirmation mismatch (-want +got):\n%s", diff)
+	}
+
+	// A declined refund fails.
+	_, request = run(genai.NewContentFromText("refund 900", genai.RoleUser))
+	responses, _ = run(answer(request, false))
+	if len(responses) != 1 || !strings.Contains(fmt.Sprint(responses[0]["error"]), toolconfirmation.ErrDeclined.Error()) {
+		t.Errorf("responses after the denial = %v, want the declined error", responses)
+	}
+	if diff := cmp.Diff([]float64{10, 500}, refunded); diff != "" {
+		t.Errorf("refunds mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestWithConfirmation_BuiltinTool(t *testing.T) {
+	search := geminitool.GoogleSearch{}
+	if got := confirmtool.WithConfirmation(search, nil); got != tool.Tool(search) {
+		t.Errorf("WithConfirmation() of a built-in tool = %v, want the tool", got)
+	}
+}
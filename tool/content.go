@@ -0,0 +1,34 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tool
+
+import "google.golang.org/genai"
+
+// Content is a tool result made of multiple parts, e.g. a generated chart and
+// its caption. A function tool whose handler returns Content, or *Content,
+// passes the parts through to the model instead of encoding the result as
+// JSON: the text parts are joined in the "output" of the function response,
+// and the inline data and file data parts follow it in the content sent to
+// the model. Other kinds of parts are rejected.
+//
+// Example:
+//
+//	return tool.Content{Parts: []*genai.Part{
+//		genai.NewPartFromText("Sales per month"),
+//		genai.NewPartFromBytes(png, "image/png"),
+//	}}, nil
+type Content struct {
+	Parts []*genai.Part
+}
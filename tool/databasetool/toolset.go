@@ -0,0 +1,513 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package databasetool provides a toolset that lets the model mutate a SQL
+// database through an allow-list of parameterized statements, optionally
+// grouped in transactions.
+//
+// The model never writes SQL: it picks one of the configured [Statement]s by
+// name and provides its parameters, which are passed to the driver as query
+// arguments. The toolset exposes four tools:
+//
+//   - "begin_transaction" opens a transaction and returns its ID.
+//   - "execute_statement" executes a statement, in the given transaction or,
+//     without a transaction ID, in its own transaction committed immediately.
+//   - "commit_transaction" and "rollback_transaction" end a transaction.
+//
+// # Transaction lifecycle
+//
+// A transaction belongs to the session and the invocation that began it, and
+// a session has at most one open transaction. It stays open across tool calls
+// until it is committed or rolled back by the model, or until it is rolled back
+// automatically when:
+//
+//   - Config.TransactionTimeout elapses after it began,
+//   - the invocation that began it ends, either through the callback returned
+//     by [Toolset.AfterAgentCallback] or, lazily, when the toolset is next used
+//     in the session by another invocation,
+//   - the toolset is closed with [Toolset.Close].
+//
+// # Confirmation
+//
+// Statements marked as Destructive are only executed after Config.Confirm
+// approves them, e.g. by asking the user. A declined statement is reported to
+// the model as rejected and is not executed.
+package databasetool
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/agent"
+	"google.golang.org/adk/tool"
+	"google.golang.org/adk/tool/functiontool"
+)
+
+const (
+	defaultName               = "database_mutations"
+	defaultTransactionTimeout = 5 * time.Minute
+
+	// StatusExecuted is the status of an executed statement.
+	StatusExecuted = "executed"
+	// StatusRejected is the status of a destructive statement that was not
+	// confirmed.
+	StatusRejected = "rejected"
+)
+
+// Param is a parameter of a [Statement].
+type Param struct {
+	// Name of the parameter as seen by the model.
+	Name string
+	// Description of the parameter for the model.
+	Description string
+}
+
+// Statement is a SQL statement the model is allowed to execute.
+type Statement struct {
+	// Name identifies the statement in tool calls.
+	Name string
+	// Description of the statement for the model.
+	Description string
+	// SQL is the statement, with placeholders in the syntax of the driver.
+	SQL string
+	// Params are passed as the arguments of the placeholders, in order.
+	Params []Param
+	// Destructive statements, such as deletes, require a confirmation.
+	Destructive bool
+}
+
+// ConfirmFunc decides whether a destructive statement may be executed with
+// the given parameters.
+type ConfirmFunc func(ctx tool.Context, stmt *Statement, params map[string]any) (bool, error)
+
+// Config is the configuration of the toolset.
+type Config struct {
+	// Name of the toolset. Defaults to "database_mutations".
+	Name string
+	// DB is the database to mutate. Required.
+	DB *sql.DB
+	// Statements the model is allowed to execute. Required.
+	Statements []Statement
+	// TxOptions are used to begin transactions.
+	TxOptions *sql.TxOptions
+	// TransactionTimeout after which an open transaction is rolled back.
+	// Defaults to 5 minutes.
+	TransactionTimeout time.Duration
+	// Confirm is called before executing destructive statements. It is
+	// required if any statement is destructive.
+	Confirm ConfirmFunc
+}
+
+// Toolset is the database mutation toolset.
+type Toolset struct {
+	cfg        Config
+	statements map[string]*Statement
+	tools      []tool.Tool
+
+	mu  sync.Mutex
+	txs map[string]*transaction // by session ID
+}
+
+type transaction struct {
+	id           string
+	sessionID    string
+	invocationID string
+	tx           *sql.Tx
+	timer        *time.Timer
+
+	// mu serializes the statements and the end of the transaction.
+	mu    sync.Mutex
+	ended bool
+}
+
+// New creates a database mutation toolset.
+func New(cfg Config) (*Toolset, error) {
+	if cfg.DB == nil {
+		return nil, errors.New("database is required")
+	}
+	if len(cfg.Statements) == 0 {
+		return nil, errors.New("at least one statement is required")
+	}
+	if cfg.Name == "" {
+		cfg.Name = defaultName
+	}
+	if cfg.TransactionTimeout <= 0 {
+		cfg.TransactionTimeout = defaultTransactionTimeout
+	}
+
+	ts := &Toolset{
+		cfg:        cfg,
+		statements: make(map[string]*Statement, len(cfg.Statements)),
+		txs:        make(map[string]*transaction),
+	}
+	for i := range cfg.Statements {
+		stmt := &cfg.Statements[i]
+		if stmt.Name == "" || stmt.SQL == "" {
+			return nil, fmt.Errorf("statement %d must have a name and SQL", i)
+		}
+		if _, ok := ts.statements[stmt.Name]; ok {
+			return nil, fmt.Errorf("duplicate statement %q", stmt.Name)
+		}
+		if stmt.Destructive && cfg.Confirm == nil {
+			return nil, fmt.Errorf("statement %q is destructive but no confirmation function is configured", stmt.Name)
+		}
+		ts.statements[stmt.Name] = stmt
+	}
+
+	tools, err := ts.newTools()
+	if err != nil {
+		return nil, fmt.Errorf("error creating database tools: %w", err)
+	}
+	ts.tools = tools
+	return ts, nil
+}
+
+// Name implements tool.Toolset.
+func (ts *Toolset) Name() string {
+	return ts.cfg.Name
+}
+
+// Tools implements tool.Toolset.
+func (ts *Toolset) Tools(agent.ReadonlyContext) ([]tool.Tool, error) {
+	return ts.tools, nil
+}
+
+// AfterAgentCallback returns a callback rolling back the transaction left
+// open by the invocation. It should be registered as an after agent callback
+// of the agent using the toolset.
+func (ts *Toolset) AfterAgentCallback() agent.AfterAgentCallback {
+	return func(ctx agent.CallbackContext) (*genai.Content, error) {
+		ts.mu.Lock()
+		t, ok := ts.txs[ctx.SessionID()]
+		ts.mu.Unlock()
+		if ok && t.invocationID == ctx.InvocationID() {
+			return nil, ts.end(t, false)
+		}
+		return nil, nil
+	}
+}
+
+// Close rolls back all open transactions.
+func (ts *Toolset) Close() error {
+	ts.mu.Lock()
+	txs := make([]*transaction, 0, len(ts.txs))
+	for _, t := range ts.txs {
+		txs = append(txs, t)
+	}
+	ts.mu.Unlock()
+
+	var errs []error
+	for _, t := range txs {
+		errs = append(errs, ts.end(t, false))
+	}
+	return errors.Join(errs...)
+}
+
+// BeginArgs are the arguments of the begin_transaction tool.
+type BeginArgs struct{}
+
+// BeginResult is the response of the begin_transaction tool.
+type BeginResult struct {
+	TransactionID string `json:"transaction_id"`
+	// ExpiresAt is the RFC 3339 time at which the transaction is rolled back
+	// if it is still open.
+	ExpiresAt string `json:"expires_at"`
+}
+
+// ExecuteArgs are the arguments of the execute_statement tool.
+type ExecuteArgs struct {
+	Statement     string         `json:"statement" jsonschema:"the name of the statement to execute"`
+	Params        map[string]any `json:"params,omitempty" jsonschema:"the parameters of the statement, by name"`
+	TransactionID string         `json:"transaction_id,omitempty" jsonschema:"the transaction to execute the statement in; if empty the statement is committed immediately"`
+}
+
+// ExecuteResult is the response of the execute_statement tool.
+type ExecuteResult struct {
+	// Status is StatusExecuted or StatusRejected.
+	Status        string `json:"status"`
+	RowsAffected  int64  `json:"rows_affected"`
+	TransactionID string `json:"transaction_id,omitempty"`
+	Error         string `json:"error,omitempty"`
+}
+
+// EndArgs are the arguments of the commit_transaction and
+// rollback_transaction tools.
+type EndArgs struct {
+	TransactionID string `json:"transaction_id" jsonschema:"the transaction to end"`
+}
+
+// EndResult is the response of the commit_transaction and
+// rollback_transaction tools.
+type EndResult struct {
+	TransactionID string `json:"transaction_id"`
+	// Status is "committed" or "rolled_back".
+	Status string `json:"status"`
+}
+
+func (ts *Toolset) newTools() ([]tool.Tool, error) {
+	begin, err := functiontool.New(functiontool.Config{
+		Name: "begin_transaction",
+		Description: "Begins a database transaction and returns its ID. Pass the ID to execute_statement to group statements, " +
+			"then call commit_transaction to apply them or rollback_transaction to discard them. " +
+			fmt.Sprintf("Open transactions are rolled back after %v or when this conversation turn ends.", ts.cfg.TransactionTimeout),
+	}, ts.begin)
+	if err != nil {
+		return nil, err
+	}
+	execute, err := functiontool.New(functiontool.Config{
+		Name:        "execute_statement",
+		Description: ts.executeDescription(),
+	}, ts.execute)
+	if err != nil {
+		return nil, err
+	}
+	commit, err := functiontool.New(functiontool.Config{
+		Name:        "commit_transaction",
+		Description: "Commits a database transaction, applying its statements.",
+	}, func(ctx tool.Context, args EndArgs) (EndResult, error) {
+		return ts.endByID(ctx, args.TransactionID, true)
+	})
+	if err != nil {
+		return nil, err
+	}
+	rollback, err := functiontool.New(functiontool.Config{
+		Name:        "rollback_transaction",
+		Description: "Rolls back a database transaction, discarding its statements.",
+	}, func(ctx tool.Context, args EndArgs) (EndResult, error) {
+		return ts.endByID(ctx, args.TransactionID, false)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return []tool.Tool{begin, execute, commit, rollback}, nil
+}
+
+func (ts *Toolset) executeDescription() string {
+	var b strings.Builder
+	b.WriteString("Executes one of the following database statements with the given parameters:\n")
+	names := make([]string, 0, len(ts.statements))
+	for name := range ts.statements {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	for _, name := range names {
+		stmt := ts.statements[name]
+		fmt.Fprintf(&b, "- %s: %s", stmt.Name, stmt.Description)
+		if stmt.Destructive {
+			b.WriteString(" Requires the user's confirmation.")
+		}
+		b.WriteString("\n")
+		for _, p := range stmt.Params {
+			fmt.Fprintf(&b, "  - param %s: %s\n", p.Name, p.Description)
+		}
+	}
+	return b.String()
+}
+
+func (ts *Toolset) begin(ctx tool.Context, _ BeginArgs) (BeginResult, error) {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+
+	if t, ok := ts.txs[ctx.SessionID()]; ok {
+		if t.invocationID == ctx.InvocationID() {
+			return BeginResult{}, fmt.Errorf("transaction %s is already open, commit or roll it back first", t.id)
+		}
+		// The invocation that began the transaction has ended.
+		go ts.end(t, false)
+		delete(ts.txs, t.sessionID)
+	}
+
+	// The transaction outlives the tool call, so it must not be bound to its
+	// context.
+	tx, err := ts.cfg.DB.BeginTx(context.Background(), ts.cfg.TxOptions)
+	if err != nil {
+		return BeginResult{}, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	t := &transaction{
+		id:           newTransactionID(),
+		sessionID:    ctx.SessionID(),
+		invocationID: ctx.InvocationID(),
+		tx:           tx,
+	}
+	t.mu.Lock()
+	t.timer = time.AfterFunc(ts.cfg.TransactionTimeout, func() { ts.end(t, false) })
+	t.mu.Unlock()
+	ts.txs[t.sessionID] = t
+	return BeginResult{
+		TransactionID: t.id,
+		ExpiresAt:     time.Now().Add(ts.cfg.TransactionTimeout).Format(time.RFC3339),
+	}, nil
+}
+
+func (ts *Toolset) execute(ctx tool.Context, args ExecuteArgs) (ExecuteResult, error) {
+	stmt, ok := ts.statements[args.Statement]
+	if !ok {
+		return ExecuteResult{}, fmt.Errorf("unknown statement %q", args.Statement)
+	}
+	queryArgs, err := bindParams(stmt, args.Params)
+	if err != nil {
+		return ExecuteResult{}, err
+	}
+
+	var t *transaction
+	if args.TransactionID != "" {
+		if t, err = ts.lookup(ctx, args.TransactionID); err != nil {
+			return ExecuteResult{}, err
+		}
+	}
+
+	if stmt.Destructive {
+		confirmed, err := ts.cfg.Confirm(ctx, stmt, args.Params)
+		if err != nil {
+			return ExecuteResult{}, fmt.Errorf("failed to confirm statement %q: %w", stmt.Name, err)
+		}
+		if !confirmed {
+			return ExecuteResult{
+				Status:        StatusRejected,
+				TransactionID: args.TransactionID,
+				Error:         fmt.Sprintf("statement %q was not confirmed and was not executed", stmt.Name),
+			}, nil
+		}
+	}
+
+	if t == nil {
+		res, err := ts.cfg.DB.ExecContext(ctx, stmt.SQL, queryArgs...)
+		if err != nil {
+			return ExecuteResult{}, fmt.Errorf("statement %q failed: %w", stmt.Name, err)
+		}
+		return executed(res, "")
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.ended {
+		return ExecuteResult{}, fmt.Errorf("transaction %s has ended", t.id)
+	}
+	res, err := t.tx.ExecContext(ctx, stmt.SQL, queryArgs...)
+	if err != nil {
+		return ExecuteResult{}, fmt.Errorf("statement %q failed, the transaction is still open: %w", stmt.Name, err)
+	}
+	return executed(res, t.id)
+}
+
+func executed(res sql.Result, transactionID string) (ExecuteResult, error) {
+	rows, err := res.RowsAffected()
+	if err != nil {
+		rows = -1
+	}
+	return ExecuteResult{
+		Status:        StatusExecuted,
+		RowsAffected:  rows,
+		TransactionID: transactionID,
+	}, nil
+}
+
+func (ts *Toolset) endByID(ctx tool.Context, id string, commit bool) (EndResult, error) {
+	t, err := ts.lookup(ctx, id)
+	if err != nil {
+		return EndResult{}, err
+	}
+	if err := ts.end(t, commit); err != nil {
+		return EndResult{}, err
+	}
+	status := "rolled_back"
+	if commit {
+		status = "committed"
+	}
+	return EndResult{TransactionID: id, Status: status}, nil
+}
+
+// lookup returns the open transaction of the session with the given ID.
+func (ts *Toolset) lookup(ctx tool.Context, id string) (*transaction, error) {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+
+	t, ok := ts.txs[ctx.SessionID()]
+	if !ok || t.id != id {
+		return nil, fmt.Errorf("transaction %q is not open; it may have been committed, rolled back or timed out", id)
+	}
+	if t.invocationID != ctx.InvocationID() {
+		delete(ts.txs, t.sessionID)
+		go ts.end(t, false)
+		return nil, fmt.Errorf("transaction %q was rolled back because the turn that began it ended", id)
+	}
+	return t, nil
+}
+
+// end commits or rolls back the transaction, if it is still open.
+func (ts *Toolset) end(t *transaction, commit bool) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	ts.mu.Lock()
+	if ts.txs[t.sessionID] == t {
+		delete(ts.txs, t.sessionID)
+	}
+	ts.mu.Unlock()
+
+	if t.ended {
+		return nil
+	}
+	t.ended = true
+	t.timer.Stop()
+	if commit {
+		if err := t.tx.Commit(); err != nil {
+			return fmt.Errorf("failed to commit transaction %s: %w", t.id, err)
+		}
+		return nil
+	}
+	if err := t.tx.Rollback(); err != nil && !errors.Is(err, sql.ErrTxDone) {
+		return fmt.Errorf("failed to roll back transaction %s: %w", t.id, err)
+	}
+	return nil
+}
+
+// bindParams returns the query arguments of the statement.
+func bindParams(stmt *Statement, params map[string]any) ([]any, error) {
+	args := make([]any, len(stmt.Params))
+	for i, p := range stmt.Params {
+		v, ok := params[p.Name]
+		if !ok {
+			return nil, fmt.Errorf("missing parameter %q of statement %q", p.Name, stmt.Name)
+		}
+		switch v.(type) {
+		case nil, string, bool, float64, int, int64:
+		default:
+			return nil, fmt.Errorf("parameter %q of statement %q must be a string, number, boolean or null", p.Name, stmt.Name)
+		}
+		args[i] = v
+	}
+	for name := range params {
+		if !slices.ContainsFunc(stmt.Params, func(p Param) bool { return p.Name == name }) {
+			return nil, fmt.Errorf("unknown parameter %q of statement %q", name, stmt.Name)
+		}
+	}
+	return args, nil
+}
+
+func newTransactionID() string {
+	b := make([]byte, 8)
+	_, _ = rand.Read(b)
+	return "tx-" + hex.EncodeToString(b)
+}
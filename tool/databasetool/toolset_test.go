@@ -0,0 +1,278 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package databasetool_test
+
+import (
+	"database/sql"
+	"path/filepath"
+	"testing"
+	"time"
+
+	_ "github.com/glebarez/go-sqlite"
+
+	"google.golang.org/adk/agent"
+	icontext "google.golang.org/adk/internal/context"
+	"google.golang.org/adk/internal/sessioninternal"
+	"google.golang.org/adk/internal/toolinternal"
+	"google.golang.org/adk/session"
+	"google.golang.org/adk/tool"
+	"google.golang.org/adk/tool/databasetool"
+)
+
+type fixture struct {
+	t       *testing.T
+	db      *sql.DB
+	ts      *databasetool.Toolset
+	service session.Service
+	session session.Session
+}
+
+func newFixture(t *testing.T, cfg databasetool.Config) *fixture {
+	t.Helper()
+	db, err := sql.Open("sqlite", filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	if _, err := db.Exec("CREATE TABLE items (id INTEGER PRIMARY KEY, name TEXT NOT NULL)"); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg.DB = db
+	cfg.Statements = []databasetool.Statement{
+		{
+			Name:        "insert_item",
+			Description: "Inserts an item.",
+			SQL:         "INSERT INTO items (name) VALUES (?)",
+			Params:      []databasetool.Param{{Name: "name", Description: "the name of the item"}},
+		},
+		{
+			Name:        "delete_item",
+			Description: "Deletes an item.",
+			SQL:         "DELETE FROM items WHERE name = ?",
+			Params:      []databasetool.Param{{Name: "name", Description: "the name of the item"}},
+			Destructive: true,
+		},
+	}
+	if cfg.Confirm == nil {
+		cfg.Confirm = func(tool.Context, *databasetool.Statement, map[string]any) (bool, error) { return true, nil }
+	}
+	ts, err := databasetool.New(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { ts.Close() })
+
+	service := session.InMemoryService()
+	resp, err := service.Create(t.Context(), &session.CreateRequest{AppName: "app", UserID: "user"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &fixture{t: t, db: db, ts: ts, service: service, session: resp.Session}
+}
+
+// newInvocation returns a tool context of a new invocation in the session.
+func (f *fixture) newInvocation() tool.Context {
+	f.t.Helper()
+	a, err := agent.New(agent.Config{Name: "db_agent"})
+	if err != nil {
+		f.t.Fatal(err)
+	}
+	ctx := icontext.NewInvocationContext(f.t.Context(), icontext.InvocationContextParams{
+		Agent:   a,
+		Session: sessioninternal.NewMutableSession(f.service, f.session),
+	})
+	return toolinternal.NewToolContext(ctx, "", nil)
+}
+
+func (f *fixture) run(ctx tool.Context, name string, args map[string]any) (map[string]any, error) {
+	f.t.Helper()
+	tools, err := f.ts.Tools(ctx)
+	if err != nil {
+		f.t.Fatal(err)
+	}
+	for _, tl := range tools {
+		if tl.Name() == name {
+			return tl.(toolinternal.FunctionTool).Run(ctx, args)
+		}
+	}
+	f.t.Fatalf("tool %q not found", name)
+	return nil, nil
+}
+
+func (f *fixture) mustRun(ctx tool.Context, name string, args map[string]any) map[string]any {
+	f.t.Helper()
+	got, err := f.run(ctx, name, args)
+	if err != nil {
+		f.t.Fatalf("%s(%v) error = %v", name, args, err)
+	}
+	return got
+}
+
+func (f *fixture) count() int {
+	f.t.Helper()
+	var n int
+	if err := f.db.QueryRow("SELECT COUNT(*) FROM items").Scan(&n); err != nil {
+		f.t.Fatal(err)
+	}
+	return n
+}
+
+func insert(name string, txID any) map[string]any {
+	args := map[string]any{"statement": "insert_item", "params": map[string]any{"name": name}}
+	if txID != nil {
+		args["transaction_id"] = txID
+	}
+	return args
+}
+
+func TestAutoCommit(t *testing.T) {
+	f := newFixture(t, databasetool.Config{})
+	ctx := f.newInvocation()
+
+	got := f.mustRun(ctx, "execute_statement", insert("apple", nil))
+	if got["status"] != databasetool.StatusExecuted || got["rows_affected"] != float64(1) {
+		t.Errorf("execute_statement() = %v, want 1 row executed", got)
+	}
+	if got := f.count(); got != 1 {
+		t.Errorf("count = %d, want 1", got)
+	}
+
+	for _, args := range []map[string]any{
+		{"statement": "drop_table"},
+		{"statement": "insert_item"},
+		{"statement": "insert_item", "params": map[string]any{"name": "pear", "color": "green"}},
+	} {
+		if _, err := f.run(ctx, "execute_statement", args); err == nil {
+			t.Errorf("execute_statement(%v) succeeded, want error", args)
+		}
+	}
+}
+
+func TestTransaction(t *testing.T) {
+	f := newFixture(t, databasetool.Config{})
+	ctx := f.newInvocation()
+
+	txID := f.mustRun(ctx, "begin_transaction", map[string]any{})["transaction_id"]
+	if _, err := f.run(ctx, "begin_transaction", map[string]any{}); err == nil {
+		t.Error("second begin_transaction() succeeded, want error")
+	}
+	f.mustRun(ctx, "execute_statement", insert("apple", txID))
+	f.mustRun(ctx, "execute_statement", insert("pear", txID))
+	if got := f.mustRun(ctx, "rollback_transaction", map[string]any{"transaction_id": txID}); got["status"] != "rolled_back" {
+		t.Errorf("rollback_transaction() = %v, want rolled_back", got)
+	}
+	if got := f.count(); got != 0 {
+		t.Errorf("count after rollback = %d, want 0", got)
+	}
+	if _, err := f.run(ctx, "commit_transaction", map[string]any{"transaction_id": txID}); err == nil {
+		t.Error("commit_transaction() of a rolled back transaction succeeded, want error")
+	}
+
+	txID = f.mustRun(ctx, "begin_transaction", map[string]any{})["transaction_id"]
+	f.mustRun(ctx, "execute_statement", insert("apple", txID))
+	f.mustRun(ctx, "execute_statement", insert("pear", txID))
+	if got := f.mustRun(ctx, "commit_transaction", map[string]any{"transaction_id": txID}); got["status"] != "committed" {
+		t.Errorf("commit_transaction() = %v, want committed", got)
+	}
+	if got := f.count(); got != 2 {
+		t.Errorf("count after commit = %d, want 2", got)
+	}
+}
+
+func TestConfirmation(t *testing.T) {
+	confirm := false
+	f := newFixture(t, databasetool.Config{
+		Confirm: func(ctx tool.Context, stmt *databasetool.Statement, params map[string]any) (bool, error) {
+			return confirm, nil
+		},
+	})
+	ctx := f.newInvocation()
+	f.mustRun(ctx, "execute_statement", insert("apple", nil))
+
+	del := map[string]any{"statement": "delete_item", "params": map[string]any{"name": "apple"}}
+	if got := f.mustRun(ctx, "execute_statement", del); got["status"] != databasetool.StatusRejected {
+		t.Errorf("unconfirmed execute_statement() = %v, want rejected", got)
+	}
+	if got := f.count(); got != 1 {
+		t.Errorf("count after rejected delete = %d, want 1", got)
+	}
+
+	confirm = true
+	if got := f.mustRun(ctx, "execute_statement", del); got["status"] != databasetool.StatusExecuted {
+		t.Errorf("confirmed execute_statement() = %v, want executed", got)
+	}
+	if got := f.count(); got != 0 {
+		t.Errorf("count after delete = %d, want 0", got)
+	}
+
+	_, err := databasetool.New(databasetool.Config{
+		DB:         f.db,
+		Statements: []databasetool.Statement{{Name: "delete_all", SQL: "DELETE FROM items", Destructive: true}},
+	})
+	if err == nil {
+		t.Error("New() with a destructive statement and no Confirm succeeded, want error")
+	}
+}
+
+func TestAutomaticRollback(t *testing.T) {
+	t.Run("invocation ended", func(t *testing.T) {
+		f := newFixture(t, databasetool.Config{})
+		ctx := f.newInvocation()
+		txID := f.mustRun(ctx, "begin_transaction", map[string]any{})["transaction_id"]
+		f.mustRun(ctx, "execute_statement", insert("apple", txID))
+
+		next := f.newInvocation()
+		if _, err := f.run(next, "commit_transaction", map[string]any{"transaction_id": txID}); err == nil {
+			t.Error("commit_transaction() from another invocation succeeded, want error")
+		}
+		f.mustRun(next, "begin_transaction", map[string]any{})
+		if got := f.count(); got != 0 {
+			t.Errorf("count = %d, want 0", got)
+		}
+	})
+
+	t.Run("after agent callback", func(t *testing.T) {
+		f := newFixture(t, databasetool.Config{})
+		ctx := f.newInvocation()
+		txID := f.mustRun(ctx, "begin_transaction", map[string]any{})["transaction_id"]
+		f.mustRun(ctx, "execute_statement", insert("apple", txID))
+
+		if _, err := f.ts.AfterAgentCallback()(ctx); err != nil {
+			t.Fatalf("AfterAgentCallback() error = %v", err)
+		}
+		if _, err := f.run(ctx, "commit_transaction", map[string]any{"transaction_id": txID}); err == nil {
+			t.Error("commit_transaction() after the invocation ended succeeded, want error")
+		}
+		if got := f.count(); got != 0 {
+			t.Errorf("count = %d, want 0", got)
+		}
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		f := newFixture(t, databasetool.Config{TransactionTimeout: 50 * time.Millisecond})
+		ctx := f.newInvocation()
+		txID := f.mustRun(ctx, "begin_transaction", map[string]any{})["transaction_id"]
+		f.mustRun(ctx, "execute_statement", insert("apple", txID))
+
+		time.Sleep(200 * time.Millisecond)
+		if _, err := f.run(ctx, "commit_transaction", map[string]any{"transaction_id": txID}); err == nil {
+			t.Error("commit_transaction() after the timeout succeeded, want error")
+		}
+		if got := f.count(); got != 0 {
+			t.Errorf("count = %d, want 0", got)
+		}
+	})
+}
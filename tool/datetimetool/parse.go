@@ -0,0 +1,268 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package datetimetool
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var weekdays = map[string]time.Weekday{
+	"sunday":    time.Sunday,
+	"monday":    time.Monday,
+	"tuesday":   time.Tuesday,
+	"wednesday": time.Wednesday,
+	"thursday":  time.Thursday,
+	"friday":    time.Friday,
+	"saturday":  time.Saturday,
+}
+
+// localLayouts are the accepted layouts of timestamps without an offset.
+var localLayouts = []string{
+	"2006-01-02T15:04:05",
+	"2006-01-02T15:04",
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	time.DateOnly,
+}
+
+// resolve resolves a date expression relative to now.
+func resolve(expr string, now time.Time) (time.Time, []string, error) {
+	expr = strings.Join(strings.Fields(strings.ToLower(expr)), " ")
+	if expr == "" {
+		return time.Time{}, nil, errors.New("expression must not be empty")
+	}
+	if expr == "now" {
+		return now, nil, nil
+	}
+
+	datePart, timePart := expr, ""
+	if i := strings.LastIndex(" "+expr, " at "); i >= 0 {
+		datePart, timePart = strings.TrimSpace(expr[:max(i-1, 0)]), expr[i+3:]
+	}
+
+	day, keepTime, err := resolveDate(datePart, now)
+	if err != nil {
+		return time.Time{}, nil, err
+	}
+	if timePart == "" {
+		if keepTime {
+			return day, nil, nil
+		}
+		return localTime(day.Year(), day.Month(), day.Day(), 0, 0, 0, now.Location())
+	}
+	hour, minute, err := parseTimeOfDay(timePart)
+	if err != nil {
+		return time.Time{}, nil, err
+	}
+	return localTime(day.Year(), day.Month(), day.Day(), hour, minute, 0, now.Location())
+}
+
+// resolveDate resolves the date part of an expression. It reports whether
+// the time of day of the returned time is meaningful, e.g. for "in 2 hours".
+func resolveDate(expr string, now time.Time) (time.Time, bool, error) {
+	switch expr {
+	case "", "today":
+		return now, false, nil
+	case "tomorrow":
+		return now.AddDate(0, 0, 1), false, nil
+	case "yesterday":
+		return now.AddDate(0, 0, -1), false, nil
+	}
+
+	fields := strings.Fields(expr)
+	switch {
+	case len(fields) == 1 && isWeekday(fields[0]):
+		return nextWeekday(now, weekdays[fields[0]], 0), false, nil
+	case len(fields) == 2 && isWeekday(fields[1]):
+		wd := weekdays[fields[1]]
+		switch fields[0] {
+		case "this":
+			return nextWeekday(now, wd, 0), false, nil
+		case "next":
+			return nextWeekday(now, wd, 1), false, nil
+		case "last":
+			return previousWeekday(now, wd), false, nil
+		}
+	case len(fields) == 3 && fields[0] == "in":
+		t, err := offset(now, fields[1], fields[2], 1)
+		return t, true, err
+	case len(fields) == 3 && fields[2] == "ago":
+		t, err := offset(now, fields[0], fields[1], -1)
+		return t, true, err
+	case len(fields) == 1:
+		if t, err := time.ParseInLocation(time.DateOnly, fields[0], now.Location()); err == nil {
+			return t, false, nil
+		}
+		if _, err := parseDate(fields[0]); err != nil {
+			return time.Time{}, false, err
+		}
+	}
+	return time.Time{}, false, fmt.Errorf("cannot resolve %q; use e.g. 'tomorrow', 'next tuesday', 'in 3 days', '2 weeks ago' or a date such as 2025-03-09, optionally followed by 'at 3pm'", expr)
+}
+
+func isWeekday(s string) bool {
+	_, ok := weekdays[s]
+	return ok
+}
+
+// nextWeekday returns the first given weekday on or after now, plus skip
+// days; skip 1 excludes today.
+func nextWeekday(now time.Time, wd time.Weekday, skip int) time.Time {
+	start := now.AddDate(0, 0, skip)
+	days := (int(wd) - int(start.Weekday()) + 7) % 7
+	return start.AddDate(0, 0, days)
+}
+
+// previousWeekday returns the last given weekday strictly before now.
+func previousWeekday(now time.Time, wd time.Weekday) time.Time {
+	days := (int(now.Weekday()) - int(wd) + 7) % 7
+	if days == 0 {
+		days = 7
+	}
+	return now.AddDate(0, 0, -days)
+}
+
+func offset(now time.Time, amount, unit string, sign int) (time.Time, error) {
+	n, err := strconv.Atoi(amount)
+	if err != nil {
+		if amount != "a" && amount != "an" {
+			return time.Time{}, fmt.Errorf("invalid amount %q", amount)
+		}
+		n = 1
+	}
+	return add(now, sign*n, unit)
+}
+
+// parseDate validates a YYYY-MM-DD date, reporting out of range days such as
+// 2025-02-30 explicitly.
+func parseDate(s string) (time.Time, error) {
+	var year, month, day int
+	if _, err := fmt.Sscanf(s, "%4d-%2d-%2d", &year, &month, &day); err != nil {
+		return time.Time{}, fmt.Errorf("cannot parse date %q, want YYYY-MM-DD", s)
+	}
+	if month < 1 || month > 12 {
+		return time.Time{}, fmt.Errorf("invalid date %q: month %d does not exist", s, month)
+	}
+	t := time.Date(year, time.Month(month), day, 0, 0, 0, 0, time.UTC)
+	if t.Day() != day {
+		return time.Time{}, fmt.Errorf("invalid date %q: %s %d has no day %d", s, time.Month(month), year, day)
+	}
+	return t, nil
+}
+
+// parseTimeOfDay parses "3pm", "3:30 pm", "15:30", "noon" or "midnight".
+func parseTimeOfDay(s string) (hour, minute int, err error) {
+	s = strings.ReplaceAll(strings.TrimSpace(s), " ", "")
+	switch s {
+	case "noon", "midday":
+		return 12, 0, nil
+	case "midnight":
+		return 0, 0, nil
+	}
+	meridiem := ""
+	if strings.HasSuffix(s, "am") || strings.HasSuffix(s, "pm") {
+		meridiem, s = s[len(s)-2:], s[:len(s)-2]
+	}
+	hs, ms, hasMinutes := strings.Cut(s, ":")
+	hour, err = strconv.Atoi(hs)
+	if err == nil && hasMinutes {
+		minute, err = strconv.Atoi(ms)
+	}
+	if err != nil || minute < 0 || minute > 59 {
+		return 0, 0, fmt.Errorf("cannot parse time of day %q; use e.g. 3pm, 3:30pm or 15:30", s+meridiem)
+	}
+	switch meridiem {
+	case "":
+		if hour < 0 || hour > 23 {
+			return 0, 0, fmt.Errorf("invalid hour %d", hour)
+		}
+	default:
+		if hour < 1 || hour > 12 {
+			return 0, 0, fmt.Errorf("invalid hour %d%s", hour, meridiem)
+		}
+		hour %= 12
+		if meridiem == "pm" {
+			hour += 12
+		}
+	}
+	return hour, minute, nil
+}
+
+// parseTimestamp parses an RFC 3339 timestamp, or a local date time that is
+// interpreted in loc.
+func parseTimestamp(s string, loc *time.Location) (time.Time, []string, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return time.Time{}, nil, errors.New("timestamp must not be empty")
+	}
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil, nil
+	}
+	for _, layout := range localLayouts {
+		t, err := time.Parse(layout, s)
+		if err != nil {
+			continue
+		}
+		return localTime(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), loc)
+	}
+	if len(s) >= 10 {
+		if _, err := parseDate(s[:10]); err != nil {
+			return time.Time{}, nil, err
+		}
+	}
+	return time.Time{}, nil, fmt.Errorf("cannot parse timestamp %q, want RFC 3339 (2025-03-09T15:00:00+01:00) or a local date time (2025-03-09T15:00)", s)
+}
+
+// localTime returns the instant of the wall clock time in loc. It returns an
+// error if the time does not exist because of a DST transition, and the
+// earlier instant with a note if it is ambiguous.
+func localTime(year int, month time.Month, day, hour, minute, sec int, loc *time.Location) (time.Time, []string, error) {
+	t := time.Date(year, month, day, hour, minute, sec, 0, loc)
+	if t.Day() != day || t.Month() != month {
+		return time.Time{}, nil, fmt.Errorf("invalid date: %s %d has no day %d", month, year, day)
+	}
+
+	// Find the instants whose wall clock in loc matches, trying the offsets
+	// in effect around the time.
+	var candidates []time.Time
+	for _, probe := range []time.Time{t.Add(-24 * time.Hour), t.Add(24 * time.Hour)} {
+		_, offset := probe.Zone()
+		c := time.Date(year, month, day, hour, minute, sec, 0, time.UTC).Add(-time.Duration(offset) * time.Second).In(loc)
+		if c.Hour() != hour || c.Minute() != minute || c.Day() != day {
+			continue
+		}
+		if len(candidates) == 0 || !candidates[0].Equal(c) {
+			candidates = append(candidates, c)
+		}
+	}
+	switch len(candidates) {
+	case 0:
+		return time.Time{}, nil, fmt.Errorf("%04d-%02d-%02d %02d:%02d does not exist in %s because of a daylight saving time transition", year, month, day, hour, minute, loc)
+	case 1:
+		return candidates[0], nil, nil
+	default:
+		earlier, later := candidates[0], candidates[1]
+		if later.Before(earlier) {
+			earlier, later = later, earlier
+		}
+		note := fmt.Sprintf("%04d-%02d-%02d %02d:%02d is ambiguous in %s because of a daylight saving time transition; using the earlier time %s (the later one is %s)",
+			year, month, day, hour, minute, loc, earlier.Format(time.RFC3339), later.Format(time.RFC3339))
+		return earlier, []string{note}, nil
+	}
+}
@@ -0,0 +1,312 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package datetimetool provides a tool that performs date and time
+// computations for the model, in the timezone of the user.
+//
+// LLMs are unreliable at date arithmetic, relative dates and timezones. The
+// tool offloads them: the model calls it with an operation and its operands
+// and receives normalized RFC 3339 timestamps. Supported operations:
+//
+//   - "now": the current time.
+//   - "resolve": resolves an expression, see below.
+//   - "duration": the duration between start and end.
+//   - "add": adds amount units (minutes, hours, days, weeks, months, years)
+//     to a timestamp. Adding months or years clamps the day to the target
+//     month, e.g. January 31 plus one month is February 28.
+//   - "format": formats a timestamp in a human readable style.
+//   - "convert": converts a timestamp to the timezone.
+//
+// Expressions are "now", "today", "tomorrow", "yesterday", a weekday
+// ("tuesday", "this tuesday" on or after today, "next tuesday" strictly after
+// today, "last tuesday" strictly before today), "in 3 days", "2 weeks ago" or
+// a date ("2025-03-09"), optionally followed by a time of day ("at 3pm",
+// "at 15:30", "at noon", "at midnight").
+//
+// The timezone is taken from the timezone argument, or else from the session
+// state (Config.TimezoneStateKey), or else Config.DefaultTimezone. Timestamps
+// without an offset are interpreted in that timezone. Wall clock times that
+// do not exist because of a daylight saving time transition are reported as
+// errors; times that are ambiguous resolve to the earlier instant, with a
+// note in the result. Invalid dates, such as February 30, are errors.
+package datetimetool
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"google.golang.org/adk/session"
+	"google.golang.org/adk/tool"
+	"google.golang.org/adk/tool/functiontool"
+)
+
+const (
+	defaultName             = "date_time"
+	defaultTimezoneStateKey = session.KeyPrefixUser + "timezone"
+)
+
+// Config is the configuration of the date/time tool.
+type Config struct {
+	// Name of the tool as seen by the model. Defaults to "date_time".
+	Name string
+	// TimezoneStateKey is the session state key holding the user's IANA
+	// timezone name, e.g. "Europe/Paris". Defaults to "user:timezone".
+	TimezoneStateKey string
+	// DefaultTimezone is used when no timezone is provided or stored in the
+	// session. Defaults to UTC.
+	DefaultTimezone *time.Location
+	// Now returns the current time. Defaults to time.Now.
+	Now func() time.Time
+}
+
+// Args are the arguments the model provides.
+type Args struct {
+	Operation  string `json:"operation" jsonschema:"one of: now, resolve, duration, add, format, convert"`
+	Expression string `json:"expression,omitempty" jsonschema:"for resolve: the expression to resolve, e.g. 'next tuesday at 3pm' or 'in 2 days'"`
+	Timestamp  string `json:"timestamp,omitempty" jsonschema:"for add, format and convert: an RFC 3339 timestamp or a local date time such as 2025-03-09T15:00"`
+	Start      string `json:"start,omitempty" jsonschema:"for duration: the start timestamp"`
+	End        string `json:"end,omitempty" jsonschema:"for duration: the end timestamp"`
+	Amount     int    `json:"amount,omitempty" jsonschema:"for add: the amount to add, negative to subtract"`
+	Unit       string `json:"unit,omitempty" jsonschema:"for add: one of minutes, hours, days, weeks, months, years"`
+	Style      string `json:"style,omitempty" jsonschema:"for format: one of date, time, datetime, long (default)"`
+	Timezone   string `json:"timezone,omitempty" jsonschema:"IANA timezone name, e.g. America/New_York; defaults to the user's timezone"`
+}
+
+// Result is the response of the tool.
+type Result struct {
+	// Timestamp is the resulting time in RFC 3339 format.
+	Timestamp string `json:"timestamp,omitempty"`
+	// Date and Weekday of Timestamp in Timezone.
+	Date     string `json:"date,omitempty"`
+	Weekday  string `json:"weekday,omitempty"`
+	Timezone string `json:"timezone"`
+	// Formatted is the human readable time, for the format operation.
+	Formatted string `json:"formatted,omitempty"`
+	// DurationSeconds and Duration describe the result of the duration
+	// operation. Duration is human readable, e.g. "2 days 3 hours".
+	DurationSeconds int64  `json:"duration_seconds,omitempty"`
+	Duration        string `json:"duration,omitempty"`
+	// Notes explain adjustments, e.g. for an ambiguous DST time.
+	Notes []string `json:"notes,omitempty"`
+}
+
+// New creates a date/time tool.
+func New(cfg Config) (tool.Tool, error) {
+	if cfg.Name == "" {
+		cfg.Name = defaultName
+	}
+	if cfg.TimezoneStateKey == "" {
+		cfg.TimezoneStateKey = defaultTimezoneStateKey
+	}
+	if cfg.DefaultTimezone == nil {
+		cfg.DefaultTimezone = time.UTC
+	}
+	if cfg.Now == nil {
+		cfg.Now = time.Now
+	}
+	d := &dateTime{cfg: cfg}
+	dateTimeTool, err := functiontool.New(functiontool.Config{
+		Name: cfg.Name,
+		Description: "Performs date and time computations in the user's timezone: " +
+			"the current time, resolving relative dates such as 'next tuesday at 3pm', " +
+			"durations between dates, adding to dates, formatting and timezone conversion.\n" +
+			"Always use this tool instead of computing dates yourself.",
+	}, d.run)
+	if err != nil {
+		return nil, fmt.Errorf("error creating date time tool: %w", err)
+	}
+	return dateTimeTool, nil
+}
+
+type dateTime struct {
+	cfg Config
+}
+
+func (d *dateTime) run(ctx tool.Context, args Args) (Result, error) {
+	loc, err := d.location(ctx, args.Timezone)
+	if err != nil {
+		return Result{}, err
+	}
+	now := d.cfg.Now().In(loc)
+
+	switch strings.ToLower(strings.TrimSpace(args.Operation)) {
+	case "now":
+		return newResult(now, nil), nil
+	case "resolve":
+		t, notes, err := resolve(args.Expression, now)
+		if err != nil {
+			return Result{}, err
+		}
+		return newResult(t, notes), nil
+	case "duration":
+		start, startNotes, err := parseTimestamp(args.Start, loc)
+		if err != nil {
+			return Result{}, fmt.Errorf("invalid start: %w", err)
+		}
+		end, endNotes, err := parseTimestamp(args.End, loc)
+		if err != nil {
+			return Result{}, fmt.Errorf("invalid end: %w", err)
+		}
+		duration := end.Sub(start)
+		return Result{
+			Timezone:        loc.String(),
+			DurationSeconds: int64(duration / time.Second),
+			Duration:        humanDuration(duration),
+			Notes:           append(startNotes, endNotes...),
+		}, nil
+	case "add":
+		t, notes, err := parseTimestamp(args.Timestamp, loc)
+		if err != nil {
+			return Result{}, err
+		}
+		// Calendar arithmetic happens in the user's timezone, so that adding
+		// a day across a DST transition keeps the wall clock time.
+		t, err = add(t.In(loc), args.Amount, args.Unit)
+		if err != nil {
+			return Result{}, err
+		}
+		return newResult(t, notes), nil
+	case "format":
+		t, notes, err := parseTimestamp(args.Timestamp, loc)
+		if err != nil {
+			return Result{}, err
+		}
+		formatted, err := format(t.In(loc), args.Style)
+		if err != nil {
+			return Result{}, err
+		}
+		result := newResult(t.In(loc), notes)
+		result.Formatted = formatted
+		return result, nil
+	case "convert":
+		t, notes, err := parseTimestamp(args.Timestamp, loc)
+		if err != nil {
+			return Result{}, err
+		}
+		return newResult(t.In(loc), notes), nil
+	default:
+		return Result{}, fmt.Errorf("unknown operation %q, want one of: now, resolve, duration, add, format, convert", args.Operation)
+	}
+}
+
+// location returns the timezone of the request.
+func (d *dateTime) location(ctx tool.Context, name string) (*time.Location, error) {
+	if name == "" && ctx != nil {
+		if val, err := ctx.State().Get(d.cfg.TimezoneStateKey); err == nil {
+			name, _ = val.(string)
+		} else if !errors.Is(err, session.ErrStateKeyNotExist) {
+			return nil, fmt.Errorf("failed to read the user's timezone: %w", err)
+		}
+	}
+	if name == "" {
+		return d.cfg.DefaultTimezone, nil
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, fmt.Errorf("unknown timezone %q, use an IANA name such as Europe/Paris", name)
+	}
+	return loc, nil
+}
+
+func newResult(t time.Time, notes []string) Result {
+	return Result{
+		Timestamp: t.Format(time.RFC3339),
+		Date:      t.Format(time.DateOnly),
+		Weekday:   t.Weekday().String(),
+		Timezone:  t.Location().String(),
+		Notes:     notes,
+	}
+}
+
+func add(t time.Time, amount int, unit string) (time.Time, error) {
+	switch strings.TrimSuffix(strings.ToLower(strings.TrimSpace(unit)), "s") {
+	case "minute":
+		return t.Add(time.Duration(amount) * time.Minute), nil
+	case "hour":
+		return t.Add(time.Duration(amount) * time.Hour), nil
+	case "day":
+		return t.AddDate(0, 0, amount), nil
+	case "week":
+		return t.AddDate(0, 0, 7*amount), nil
+	case "month":
+		return addMonths(t, amount), nil
+	case "year":
+		return addMonths(t, 12*amount), nil
+	default:
+		return time.Time{}, fmt.Errorf("unknown unit %q, want one of: minutes, hours, days, weeks, months, years", unit)
+	}
+}
+
+// addMonths adds months to t, clamping the day to the last day of the target
+// month: January 31 plus one month is February 28, not March 3 as with
+// AddDate.
+func addMonths(t time.Time, months int) time.Time {
+	year, month, day := t.Date()
+	first := time.Date(year, month+time.Month(months), 1, t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), t.Location())
+	last := first.AddDate(0, 1, -1).Day()
+	return first.AddDate(0, 0, min(day, last)-1)
+}
+
+func format(t time.Time, style string) (string, error) {
+	switch strings.ToLower(strings.TrimSpace(style)) {
+	case "date":
+		return t.Format("Monday, January 2, 2006"), nil
+	case "time":
+		return t.Format("3:04 PM MST"), nil
+	case "datetime":
+		return t.Format("Jan 2, 2006 3:04 PM MST"), nil
+	case "", "long":
+		return t.Format("Monday, January 2, 2006 at 3:04 PM MST"), nil
+	default:
+		return "", fmt.Errorf("unknown style %q, want one of: date, time, datetime, long", style)
+	}
+}
+
+func humanDuration(d time.Duration) string {
+	if d == 0 {
+		return "0 minutes"
+	}
+	sign := ""
+	if d < 0 {
+		sign, d = "-", -d
+	}
+	units := []struct {
+		name string
+		size time.Duration
+	}{
+		{"day", 24 * time.Hour},
+		{"hour", time.Hour},
+		{"minute", time.Minute},
+		{"second", time.Second},
+	}
+	var parts []string
+	for _, u := range units {
+		n := d / u.size
+		if n == 0 {
+			continue
+		}
+		d -= n * u.size
+		name := u.name
+		if n > 1 {
+			name += "s"
+		}
+		parts = append(parts, fmt.Sprintf("%d %s", n, name))
+	}
+	if len(parts) == 0 {
+		return "less than a second"
+	}
+	return sign + strings.Join(parts, " ")
+}
@@ -0,0 +1,198 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package datetimetool_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	icontext "google.golang.org/adk/internal/context"
+	"google.golang.org/adk/internal/sessioninternal"
+	"google.golang.org/adk/internal/toolinternal"
+	"google.golang.org/adk/internal/typeutil"
+	"google.golang.org/adk/session"
+	"google.golang.org/adk/tool"
+	"google.golang.org/adk/tool/datetimetool"
+)
+
+func newToolContext(t *testing.T, state map[string]any) tool.Context {
+	t.Helper()
+	sessionService := session.InMemoryService()
+	resp, err := sessionService.Create(t.Context(), &session.CreateRequest{AppName: "app", UserID: "user", State: state})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := icontext.NewInvocationContext(t.Context(), icontext.InvocationContextParams{
+		Session: sessioninternal.NewMutableSession(sessionService, resp.Session),
+	})
+	return toolinternal.NewToolContext(ctx, "", nil)
+}
+
+func TestDateTimeTool(t *testing.T) {
+	// Thursday, March 6, 2025, 10:30 in New York. DST starts on March 9,
+	// and ends on November 2.
+	now := time.Date(2025, 3, 6, 15, 30, 0, 0, time.UTC)
+	dateTimeTool, err := datetimetool.New(datetimetool.Config{Now: func() time.Time { return now }})
+	if err != nil {
+		t.Fatal(err)
+	}
+	toolImpl := dateTimeTool.(toolinternal.FunctionTool)
+	ctx := newToolContext(t, map[string]any{"user:timezone": "America/New_York"})
+
+	tests := []struct {
+		name    string
+		args    map[string]any
+		want    datetimetool.Result
+		wantErr string
+	}{
+		{
+			name: "now in the user's timezone",
+			args: map[string]any{"operation": "now"},
+			want: datetimetool.Result{Timestamp: "2025-03-06T10:30:00-05:00", Date: "2025-03-06", Weekday: "Thursday", Timezone: "America/New_York"},
+		},
+		{
+			name: "next weekday across DST start",
+			args: map[string]any{"operation": "resolve", "expression": "next Tuesday at 3pm"},
+			want: datetimetool.Result{Timestamp: "2025-03-11T15:00:00-04:00", Date: "2025-03-11", Weekday: "Tuesday", Timezone: "America/New_York"},
+		},
+		{
+			name: "this weekday is today",
+			args: map[string]any{"operation": "resolve", "expression": "this thursday at noon"},
+			want: datetimetool.Result{Timestamp: "2025-03-06T12:00:00-05:00", Date: "2025-03-06", Weekday: "Thursday", Timezone: "America/New_York"},
+		},
+		{
+			name: "next weekday excludes today",
+			args: map[string]any{"operation": "resolve", "expression": "next thursday"},
+			want: datetimetool.Result{Timestamp: "2025-03-13T00:00:00-04:00", Date: "2025-03-13", Weekday: "Thursday", Timezone: "America/New_York"},
+		},
+		{
+			name: "last weekday",
+			args: map[string]any{"operation": "resolve", "expression": "last monday"},
+			want: datetimetool.Result{Timestamp: "2025-03-03T00:00:00-05:00", Date: "2025-03-03", Weekday: "Monday", Timezone: "America/New_York"},
+		},
+		{
+			name: "relative offset",
+			args: map[string]any{"operation": "resolve", "expression": "in 2 hours"},
+			want: datetimetool.Result{Timestamp: "2025-03-06T12:30:00-05:00", Date: "2025-03-06", Weekday: "Thursday", Timezone: "America/New_York"},
+		},
+		{
+			name: "timezone argument overrides the session",
+			args: map[string]any{"operation": "resolve", "expression": "tomorrow at 9:15am", "timezone": "Europe/Paris"},
+			want: datetimetool.Result{Timestamp: "2025-03-07T09:15:00+01:00", Date: "2025-03-07", Weekday: "Friday", Timezone: "Europe/Paris"},
+		},
+		{
+			name: "duration across DST start",
+			args: map[string]any{"operation": "duration", "start": "2025-03-08T12:00", "end": "2025-03-09T12:00"},
+			want: datetimetool.Result{Timezone: "America/New_York", DurationSeconds: 23 * 3600, Duration: "23 hours"},
+		},
+		{
+			name: "add days keeps the wall clock across DST",
+			args: map[string]any{"operation": "add", "timestamp": "2025-03-08T09:00:00-05:00", "amount": 2, "unit": "days"},
+			want: datetimetool.Result{Timestamp: "2025-03-10T09:00:00-04:00", Date: "2025-03-10", Weekday: "Monday", Timezone: "America/New_York"},
+		},
+		{
+			name: "add month to the end of a month",
+			args: map[string]any{"operation": "add", "timestamp": "2025-01-31T09:00:00-05:00", "amount": 1, "unit": "month"},
+			want: datetimetool.Result{Timestamp: "2025-02-28T09:00:00-05:00", Date: "2025-02-28", Weekday: "Friday", Timezone: "America/New_York"},
+		},
+		{
+			name: "add year to a leap day",
+			args: map[string]any{"operation": "add", "timestamp": "2024-02-29T09:00:00-05:00", "amount": 1, "unit": "years"},
+			want: datetimetool.Result{Timestamp: "2025-02-28T09:00:00-05:00", Date: "2025-02-28", Weekday: "Friday", Timezone: "America/New_York"},
+		},
+		{
+			name: "format",
+			args: map[string]any{"operation": "format", "timestamp": "2025-03-06T15:30:00Z", "style": "long"},
+			want: datetimetool.Result{Timestamp: "2025-03-06T10:30:00-05:00", Date: "2025-03-06", Weekday: "Thursday", Timezone: "America/New_York", Formatted: "Thursday, March 6, 2025 at 10:30 AM EST"},
+		},
+		{
+			name: "convert",
+			args: map[string]any{"operation": "convert", "timestamp": "2025-03-06T10:30:00-05:00", "timezone": "Asia/Tokyo"},
+			want: datetimetool.Result{Timestamp: "2025-03-07T00:30:00+09:00", Date: "2025-03-07", Weekday: "Friday", Timezone: "Asia/Tokyo"},
+		},
+		{
+			name: "ambiguous time at DST end",
+			args: map[string]any{"operation": "convert", "timestamp": "2025-11-02T01:30"},
+			want: datetimetool.Result{
+				Timestamp: "2025-11-02T01:30:00-04:00", Date: "2025-11-02", Weekday: "Sunday", Timezone: "America/New_York",
+				Notes: []string{"2025-11-02 01:30 is ambiguous in America/New_York because of a daylight saving time transition; using the earlier time 2025-11-02T01:30:00-04:00 (the later one is 2025-11-02T01:30:00-05:00)"},
+			},
+		},
+		{
+			name:    "nonexistent time at DST start",
+			args:    map[string]any{"operation": "resolve", "expression": "2025-03-09 at 2:30am"},
+			wantErr: "2025-03-09 02:30 does not exist in America/New_York because of a daylight saving time transition",
+		},
+		{
+			name:    "invalid date",
+			args:    map[string]any{"operation": "format", "timestamp": "2025-02-30"},
+			wantErr: `invalid date "2025-02-30": February 2025 has no day 30`,
+		},
+		{
+			name:    "unknown timezone",
+			args:    map[string]any{"operation": "now", "timezone": "Mars/Olympus"},
+			wantErr: `unknown timezone "Mars/Olympus"`,
+		},
+		{
+			name:    "unknown expression",
+			args:    map[string]any{"operation": "resolve", "expression": "the day after the party"},
+			wantErr: `cannot resolve "the day after the party"`,
+		},
+		{
+			name:    "unknown operation",
+			args:    map[string]any{"operation": "sleep"},
+			wantErr: `unknown operation "sleep"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := toolImpl.Run(ctx, tt.args)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Run() error = %v, want error containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Run() error = %v", err)
+			}
+			result, err := typeutil.ConvertToWithJSONSchema[map[string]any, datetimetool.Result](got, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tt.want, result); diff != "" {
+				t.Errorf("Run() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestDateTimeTool_DefaultTimezone(t *testing.T) {
+	now := time.Date(2025, 3, 6, 15, 30, 0, 0, time.UTC)
+	dateTimeTool, err := datetimetool.New(datetimetool.Config{Now: func() time.Time { return now }})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := dateTimeTool.(toolinternal.FunctionTool).Run(newToolContext(t, nil), map[string]any{"operation": "now"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got["timestamp"] != "2025-03-06T15:30:00Z" || got["timezone"] != "UTC" {
+		t.Errorf("Run() = %v, want the current time in UTC", got)
+	}
+}
@@ -0,0 +1,62 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tool
+
+import (
+	"slices"
+
+	"google.golang.org/genai"
+)
+
+// Declarer is implemented by the tools declared to the model as functions,
+// such as the tools of package functiontool. The built-in tools of the
+// model, such as geminitool.GoogleSearch, return a nil declaration.
+type Declarer interface {
+	Declaration() *genai.FunctionDeclaration
+}
+
+// Declarations returns the function declarations of the tools, in order, as
+// they are sent to the model, e.g. to list the tools of an agent in a UI.
+// The tools that are not Declarers or whose declaration is nil are skipped.
+func Declarations(tools []Tool) []*genai.FunctionDeclaration {
+	var decls []*genai.FunctionDeclaration
+	for _, t := range tools {
+		d, ok := t.(Declarer)
+		if !ok {
+			continue
+		}
+		if decl := d.Declaration(); decl != nil {
+			decls = append(decls, decl)
+		}
+	}
+	return decls
+}
+
+// DuplicateNames returns the sorted names shared by several of the tools.
+// A request to the model fails when two of its tools have the same name, so
+// it can be used to check a set of tools up front.
+func DuplicateNames(tools []Tool) []string {
+	count := make(map[string]int, len(tools))
+	var dups []string
+	for _, t := range tools {
+		name := t.Name()
+		count[name]++
+		if count[name] == 2 {
+			dups = append(dups, name)
+		}
+	}
+	slices.Sort(dups)
+	return dups
+}
@@ -0,0 +1,54 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dedupetool
+
+import (
+	"context"
+	"fmt"
+
+	"google.golang.org/genai"
+)
+
+// NewGenaiEmbedder returns an Embedder using an embedding model of the
+// Gemini API or Vertex AI, e.g. "gemini-embedding-001".
+func NewGenaiEmbedder(client *genai.Client, model string) Embedder {
+	return &genaiEmbedder{client: client, model: model}
+}
+
+type genaiEmbedder struct {
+	client *genai.Client
+	model  string
+}
+
+func (e *genaiEmbedder) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	contents := make([]*genai.Content, 0, len(texts))
+	for _, text := range texts {
+		contents = append(contents, genai.NewContentFromText(text, genai.RoleUser))
+	}
+	resp, err := e.client.Models.EmbedContent(ctx, e.model, contents, &genai.EmbedContentConfig{
+		TaskType: "SEMANTIC_SIMILARITY",
+	})
+	if err != nil {
+		return nil, err
+	}
+	embeddings := make([][]float32, 0, len(resp.Embeddings))
+	for i, emb := range resp.Embeddings {
+		if emb == nil {
+			return nil, fmt.Errorf("no embedding for item %d", i)
+		}
+		embeddings = append(embeddings, emb.Values)
+	}
+	return embeddings, nil
+}
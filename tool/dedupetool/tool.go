@@ -0,0 +1,260 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package dedupetool provides a tool that removes the near-duplicates from a
+// list of texts, such as overlapping search results, using the embeddings of
+// a pluggable [Embedder].
+//
+// # Grouping
+//
+// The items are processed in order. Each item is compared with the first
+// item of every group formed so far, by cosine similarity of their
+// embeddings; it joins the most similar group if the similarity reaches
+// Config.Threshold, the earliest group winning ties, and starts a new group
+// otherwise. Items equal after trimming spaces are duplicates without
+// comparing embeddings.
+//
+// # Tie-breaking
+//
+// Each group is returned as one item, in the order of the first item of the
+// groups. With KeepFirst, the default, the kept text is the first of the
+// group, which suits lists ranked by relevance. With KeepLongest, it is the
+// longest text of the group, the earliest one among texts of equal length.
+package dedupetool
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+
+	"google.golang.org/adk/tool"
+	"google.golang.org/adk/tool/functiontool"
+)
+
+const (
+	defaultName      = "dedupe"
+	defaultThreshold = 0.9
+	defaultMaxItems  = 500
+)
+
+// Embedder computes the embeddings of texts.
+type Embedder interface {
+	// Embed returns the embedding of each text, in order.
+	Embed(ctx context.Context, texts []string) ([][]float32, error)
+}
+
+// KeepRule chooses the text kept for a group of duplicates.
+type KeepRule string
+
+const (
+	// KeepFirst keeps the first text of the group.
+	KeepFirst KeepRule = "first"
+	// KeepLongest keeps the longest text of the group.
+	KeepLongest KeepRule = "longest"
+)
+
+// Config is the configuration of the deduplication tool.
+type Config struct {
+	// Name of the tool as seen by the model. Defaults to "dedupe".
+	Name string
+	// Embedder computing the embeddings of the items. Required.
+	Embedder Embedder
+	// Threshold is the cosine similarity, between 0 and 1, at or above which
+	// two items are duplicates. Defaults to 0.9.
+	Threshold float64
+	// Keep chooses the text kept for each group of duplicates. Defaults to
+	// KeepFirst.
+	Keep KeepRule
+	// MaxItems is the maximum number of items per call. Defaults to 500.
+	MaxItems int
+}
+
+// Args are the arguments the model provides.
+type Args struct {
+	Items []string `json:"items" jsonschema:"the texts to deduplicate, most relevant first"`
+}
+
+// Item is an item of the deduplicated list.
+type Item struct {
+	Text string `json:"text"`
+	// Index is the position of Text in the input list.
+	Index int `json:"index"`
+	// Merged is the number of duplicates merged into the item.
+	Merged int `json:"merged"`
+	// MergedIndexes are the positions in the input list of the duplicates,
+	// in increasing order.
+	MergedIndexes []int `json:"merged_indexes,omitempty"`
+}
+
+// Result is the response of the tool.
+type Result struct {
+	Items []Item `json:"items"`
+	// Removed is the number of items removed as duplicates.
+	Removed int `json:"removed"`
+}
+
+// New creates a deduplication tool.
+func New(cfg Config) (tool.Tool, error) {
+	if cfg.Embedder == nil {
+		return nil, errors.New("embedder is required")
+	}
+	if cfg.Threshold < 0 || cfg.Threshold > 1 {
+		return nil, fmt.Errorf("threshold %v is not between 0 and 1", cfg.Threshold)
+	}
+	if cfg.Name == "" {
+		cfg.Name = defaultName
+	}
+	if cfg.Threshold == 0 {
+		cfg.Threshold = defaultThreshold
+	}
+	if cfg.Keep == "" {
+		cfg.Keep = KeepFirst
+	}
+	if cfg.Keep != KeepFirst && cfg.Keep != KeepLongest {
+		return nil, fmt.Errorf("unknown keep rule %q", cfg.Keep)
+	}
+	if cfg.MaxItems <= 0 {
+		cfg.MaxItems = defaultMaxItems
+	}
+
+	d := &deduper{cfg: cfg}
+	dedupeTool, err := functiontool.New(functiontool.Config{
+		Name: cfg.Name,
+		Description: "Removes the near-duplicates from a list of texts, such as overlapping search results, " +
+			"and returns the remaining texts with the number of duplicates merged into each.",
+	}, d.dedupe)
+	if err != nil {
+		return nil, fmt.Errorf("error creating dedupe tool: %w", err)
+	}
+	return dedupeTool, nil
+}
+
+type deduper struct {
+	cfg Config
+}
+
+// group is a group of duplicates.
+type group struct {
+	first     int // index of the first item, compared with the next items
+	kept      int // index of the kept item
+	embedding []float32
+	merged    []int
+}
+
+func (d *deduper) dedupe(ctx tool.Context, args Args) (Result, error) {
+	if len(args.Items) > d.cfg.MaxItems {
+		return Result{}, fmt.Errorf("too many items: %d, the maximum is %d", len(args.Items), d.cfg.MaxItems)
+	}
+	groups, err := d.group(ctx, args.Items)
+	if err != nil {
+		return Result{}, err
+	}
+
+	result := Result{Items: make([]Item, 0, len(groups))}
+	for _, g := range groups {
+		item := Item{Text: args.Items[g.kept], Index: g.kept, Merged: len(g.merged)}
+		for _, i := range append([]int{g.first}, g.merged...) {
+			if i != g.kept {
+				item.MergedIndexes = append(item.MergedIndexes, i)
+			}
+		}
+		slices.Sort(item.MergedIndexes)
+		result.Items = append(result.Items, item)
+		result.Removed += len(g.merged)
+	}
+	return result, nil
+}
+
+// group groups the duplicates of items, see the package documentation.
+func (d *deduper) group(ctx context.Context, items []string) ([]*group, error) {
+	// Exact duplicates are grouped first, and only distinct texts are
+	// embedded.
+	byText := make(map[string]*group)
+	var groups []*group
+	var distinct []string
+	for i, item := range items {
+		key := strings.TrimSpace(item)
+		if g, ok := byText[key]; ok {
+			d.merge(g, items, i)
+			continue
+		}
+		g := &group{first: i, kept: i}
+		byText[key] = g
+		groups = append(groups, g)
+		distinct = append(distinct, item)
+	}
+	if len(distinct) < 2 {
+		return groups, nil
+	}
+
+	embeddings, err := d.cfg.Embedder.Embed(ctx, distinct)
+	if err != nil {
+		return nil, fmt.Errorf("failed to embed the items: %w", err)
+	}
+	if len(embeddings) != len(distinct) {
+		return nil, fmt.Errorf("the embedder returned %d embeddings for %d items", len(embeddings), len(distinct))
+	}
+
+	var merged []*group
+	for i, g := range groups {
+		g.embedding = embeddings[i]
+		var best *group
+		var bestSimilarity float64
+		for _, m := range merged {
+			// Strictly greater: the earliest group wins ties.
+			if s := cosine(m.embedding, g.embedding); s >= d.cfg.Threshold && (best == nil || s > bestSimilarity) {
+				best, bestSimilarity = m, s
+			}
+		}
+		if best == nil {
+			merged = append(merged, g)
+			continue
+		}
+		d.merge(best, items, g.first)
+		for _, i := range g.merged {
+			d.merge(best, items, i)
+		}
+	}
+	return merged, nil
+}
+
+// merge adds the item i to the group.
+func (d *deduper) merge(g *group, items []string, i int) {
+	g.merged = append(g.merged, i)
+	if d.cfg.Keep != KeepLongest {
+		return
+	}
+	if n, kept := len(items[i]), len(items[g.kept]); n > kept || (n == kept && i < g.kept) {
+		g.kept = i
+	}
+}
+
+func cosine(a, b []float32) float64 {
+	if len(a) != len(b) {
+		return 0
+	}
+	var dot, na, nb float64
+	for i := range a {
+		dot += float64(a[i]) * float64(b[i])
+		na += float64(a[i]) * float64(a[i])
+		nb += float64(b[i]) * float64(b[i])
+	}
+	if na == 0 || nb == 0 {
+		return 0
+	}
+	return dot / (math.Sqrt(na) * math.Sqrt(nb))
+}
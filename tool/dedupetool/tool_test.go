@@ -0,0 +1,174 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dedupetool_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"google.golang.org/adk/internal/toolinternal"
+	"google.golang.org/adk/tool/dedupetool"
+)
+
+// fakeEmbedder embeds the texts with fixed vectors.
+type fakeEmbedder struct {
+	vectors map[string][]float32
+	calls   [][]string
+}
+
+func (e *fakeEmbedder) Embed(_ context.Context, texts []string) ([][]float32, error) {
+	e.calls = append(e.calls, texts)
+	embeddings := make([][]float32, 0, len(texts))
+	for _, text := range texts {
+		v, ok := e.vectors[text]
+		if !ok {
+			return nil, fmt.Errorf("no vector for %q", text)
+		}
+		embeddings = append(embeddings, v)
+	}
+	return embeddings, nil
+}
+
+func newEmbedder() *fakeEmbedder {
+	return &fakeEmbedder{vectors: map[string][]float32{
+		"Go 1.24 released":                        {1, 0, 0},
+		"Go 1.24 is out":                          {0.98, 0.2, 0},
+		"The Go team released Go 1.24 today":      {0.97, 0.24, 0},
+		"Rust 1.85 released":                      {0, 1, 0},
+		"Weather in Paris":                        {0, 0, 1},
+		"Paris weather forecast for the weekend":  {0.1, 0, 0.99},
+		"Go 1.24 released and Rust 1.85 released": {0.7, 0.7, 0},
+	}}
+}
+
+func run(t *testing.T, cfg dedupetool.Config, items []string) map[string]any {
+	t.Helper()
+	dedupeTool, err := dedupetool.New(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := dedupeTool.(toolinternal.FunctionTool).Run(nil, map[string]any{"items": items})
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	return got
+}
+
+type item = map[string]any
+
+func TestDedupe(t *testing.T) {
+	items := []string{
+		"Go 1.24 released",
+		"Weather in Paris",
+		"Go 1.24 is out",
+		"Rust 1.85 released",
+		"Go 1.24 released and Rust 1.85 released",
+		"  Weather in Paris ",
+		"The Go team released Go 1.24 today",
+		"Paris weather forecast for the weekend",
+	}
+	tests := []struct {
+		name string
+		cfg  dedupetool.Config
+		want map[string]any
+	}{
+		{
+			name: "keep first",
+			want: map[string]any{
+				"items": []any{
+					item{"text": "Go 1.24 released", "index": 0.0, "merged": 2.0, "merged_indexes": []any{2.0, 6.0}},
+					item{"text": "Weather in Paris", "index": 1.0, "merged": 2.0, "merged_indexes": []any{5.0, 7.0}},
+					item{"text": "Rust 1.85 released", "index": 3.0, "merged": 0.0},
+					item{"text": "Go 1.24 released and Rust 1.85 released", "index": 4.0, "merged": 0.0},
+				},
+				"removed": 4.0,
+			},
+		},
+		{
+			name: "keep longest",
+			cfg:  dedupetool.Config{Keep: dedupetool.KeepLongest},
+			want: map[string]any{
+				"items": []any{
+					item{"text": "The Go team released Go 1.24 today", "index": 6.0, "merged": 2.0, "merged_indexes": []any{0.0, 2.0}},
+					item{"text": "Paris weather forecast for the weekend", "index": 7.0, "merged": 2.0, "merged_indexes": []any{1.0, 5.0}},
+					item{"text": "Rust 1.85 released", "index": 3.0, "merged": 0.0},
+					item{"text": "Go 1.24 released and Rust 1.85 released", "index": 4.0, "merged": 0.0},
+				},
+				"removed": 4.0,
+			},
+		},
+		{
+			name: "low threshold",
+			cfg:  dedupetool.Config{Threshold: 0.5},
+			want: map[string]any{
+				"items": []any{
+					item{"text": "Go 1.24 released", "index": 0.0, "merged": 3.0, "merged_indexes": []any{2.0, 4.0, 6.0}},
+					item{"text": "Weather in Paris", "index": 1.0, "merged": 2.0, "merged_indexes": []any{5.0, 7.0}},
+					item{"text": "Rust 1.85 released", "index": 3.0, "merged": 0.0},
+				},
+				"removed": 5.0,
+			},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			embedder := newEmbedder()
+			tc.cfg.Embedder = embedder
+			got := run(t, tc.cfg, items)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("Run() mismatch (-want +got):\n%s", diff)
+			}
+			// The exact duplicate is not embedded.
+			if len(embedder.calls) != 1 || len(embedder.calls[0]) != len(items)-1 {
+				t.Errorf("Embed() calls = %q, want one call with the %d distinct items", embedder.calls, len(items)-1)
+			}
+		})
+	}
+}
+
+func TestDedupe_Ties(t *testing.T) {
+	// "c" is as similar to "a" as to "b": it joins the earliest group.
+	embedder := &fakeEmbedder{vectors: map[string][]float32{
+		"a": {1, 0},
+		"b": {0, 1},
+		"c": {1, 1},
+	}}
+	got := run(t, dedupetool.Config{Embedder: embedder, Threshold: 0.7}, []string{"a", "b", "c"})
+	want := map[string]any{
+		"items": []any{
+			item{"text": "a", "index": 0.0, "merged": 1.0, "merged_indexes": []any{2.0}},
+			item{"text": "b", "index": 1.0, "merged": 0.0},
+		},
+		"removed": 1.0,
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Run() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestNew_InvalidConfig(t *testing.T) {
+	for name, cfg := range map[string]dedupetool.Config{
+		"no embedder":       {},
+		"invalid threshold": {Embedder: newEmbedder(), Threshold: 1.5},
+		"invalid keep rule": {Embedder: newEmbedder(), Keep: "shortest"},
+	} {
+		if _, err := dedupetool.New(cfg); err == nil {
+			t.Errorf("New() with %s succeeded, want error", name)
+		}
+	}
+}
@@ -0,0 +1,162 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package documenttool
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// MIME types of the documents.
+const (
+	MIMETypePDF  = "application/pdf"
+	MIMETypeDOCX = "application/vnd.openxmlformats-officedocument.wordprocessingml.document"
+)
+
+// ErrUnsupportedFormat is returned, possibly wrapped, by the extractors for
+// the formats they don't support.
+var ErrUnsupportedFormat = errors.New("unsupported document format")
+
+// Extractor extracts the text of documents.
+type Extractor interface {
+	// Extract extracts the text of the document of the MIME type. It returns
+	// an error wrapping ErrUnsupportedFormat if it doesn't support the
+	// format.
+	Extract(ctx context.Context, data []byte, mimeType string) (*Document, error)
+}
+
+// Document is the text of a document.
+type Document struct {
+	// Pages of the document, in order. Formats without pages have one page.
+	Pages []Page
+}
+
+// Page is the text of a page of a document.
+type Page struct {
+	// Number of the page, starting at 1.
+	Number int
+	Text   string
+}
+
+// Extractors combines extractors: the first one supporting the format of a
+// document extracts its text.
+type Extractors []Extractor
+
+// Extract implements Extractor.
+func (e Extractors) Extract(ctx context.Context, data []byte, mimeType string) (*Document, error) {
+	for _, extractor := range e {
+		doc, err := extractor.Extract(ctx, data, mimeType)
+		if errors.Is(err, ErrUnsupportedFormat) {
+			continue
+		}
+		return doc, err
+	}
+	return nil, fmt.Errorf("%w %q", ErrUnsupportedFormat, mimeType)
+}
+
+// TextExtractor extracts the text of text documents, such as text/plain and
+// text/markdown documents, as one page.
+type TextExtractor struct{}
+
+// Extract implements Extractor.
+func (TextExtractor) Extract(_ context.Context, data []byte, mimeType string) (*Document, error) {
+	if !strings.HasPrefix(mimeType, "text/") {
+		return nil, fmt.Errorf("%w %q", ErrUnsupportedFormat, mimeType)
+	}
+	return &Document{Pages: []Page{{Number: 1, Text: string(data)}}}, nil
+}
+
+// DOCXExtractor extracts the text of Word documents. The pages are separated
+// by the explicit page breaks of the document, since the layout of the
+// pages is computed by the word processors. The paragraphs are separated by
+// newlines.
+type DOCXExtractor struct{}
+
+// Extract implements Extractor.
+func (DOCXExtractor) Extract(_ context.Context, data []byte, mimeType string) (*Document, error) {
+	if mimeType != MIMETypeDOCX {
+		return nil, fmt.Errorf("%w %q", ErrUnsupportedFormat, mimeType)
+	}
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("invalid Word document: %w", err)
+	}
+	f, err := archive.Open("word/document.xml")
+	if err != nil {
+		return nil, fmt.Errorf("invalid Word document: %w", err)
+	}
+	defer f.Close()
+
+	doc := &Document{}
+	var text strings.Builder
+	endPage := func() {
+		doc.Pages = append(doc.Pages, Page{Number: len(doc.Pages) + 1, Text: strings.Trim(text.String(), "\n")})
+		text.Reset()
+	}
+	decoder := xml.NewDecoder(f)
+	var inText bool
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid Word document: %w", err)
+		}
+		switch t := token.(type) {
+		case xml.StartElement:
+			switch t.Name.Local {
+			case "t":
+				inText = true
+			case "tab":
+				text.WriteByte('\t')
+			case "br":
+				if attr(t, "type") == "page" {
+					endPage()
+				} else {
+					text.WriteByte('\n')
+				}
+			}
+		case xml.EndElement:
+			switch t.Name.Local {
+			case "t":
+				inText = false
+			case "p":
+				text.WriteByte('\n')
+			}
+		case xml.CharData:
+			if inText {
+				text.Write(t)
+			}
+		}
+	}
+	endPage()
+	return doc, nil
+}
+
+func attr(e xml.StartElement, name string) string {
+	for _, a := range e.Attr {
+		if a.Name.Local == name {
+			return a.Value
+		}
+	}
+	return ""
+}
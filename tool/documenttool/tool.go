@@ -0,0 +1,243 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package documenttool provides a tool that extracts the text of documents,
+// such as uploaded PDF and Word documents, stored as artifacts, with a
+// pluggable [Extractor].
+//
+// The text of the pages is joined with blank lines. The text returned per
+// call is limited to Config.MaxTextLength: for larger documents the tool
+// returns the first chunk with the offset of the next one, which the model
+// passes back to read the rest, and saves the whole text as a text artifact
+// named after the document, see [TextArtifactName], which the application or
+// the load_artifacts tool can use instead of reading chunk by chunk.
+//
+// The default extractor supports text and Word documents. PDF documents
+// need an extractor for PDF, combined with the default ones with
+// [Extractors].
+package documenttool
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"mime"
+	"path"
+	"strings"
+	"unicode/utf8"
+
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/artifact"
+	"google.golang.org/adk/tool"
+	"google.golang.org/adk/tool/functiontool"
+)
+
+const (
+	defaultName            = "extract_document"
+	defaultMaxTextLength   = 8000
+	defaultMaxDocumentSize = 20 << 20
+
+	pageSeparator = "\n\n"
+)
+
+// Config is the configuration of the document extraction tool.
+type Config struct {
+	// Name of the tool as seen by the model. Defaults to "extract_document".
+	Name string
+	// Extractor extracting the text of the documents. Defaults to
+	// Extractors{TextExtractor{}, DOCXExtractor{}}.
+	Extractor Extractor
+	// MaxTextLength is the maximum length in bytes of the text returned per
+	// call. Defaults to 8000.
+	MaxTextLength int
+	// MaxDocumentSize is the maximum size in bytes of the documents. Larger
+	// documents are rejected. Defaults to 20 MiB.
+	MaxDocumentSize int
+}
+
+// Args are the arguments the model provides.
+type Args struct {
+	Artifact     string `json:"artifact" jsonschema:"the name of the artifact holding the document"`
+	Version      int64  `json:"version,omitempty" jsonschema:"the version of the artifact, the latest by default"`
+	Offset       int    `json:"offset,omitempty" jsonschema:"the offset in the text to read from, as returned in next_offset"`
+	IncludePages bool   `json:"include_pages,omitempty" jsonschema:"whether to return the offsets of the pages in the text"`
+}
+
+// PageOffset locates a page in the text of a document.
+type PageOffset struct {
+	Number int `json:"number"`
+	// Offset of the text of the page in the text of the document.
+	Offset int `json:"offset"`
+}
+
+// Result is the response of the tool.
+type Result struct {
+	Artifact string `json:"artifact"`
+	MIMEType string `json:"mime_type"`
+	// PageCount is the number of pages of the document.
+	PageCount int `json:"page_count"`
+	// Text is the chunk of the text of the document starting at the offset.
+	Text string `json:"text"`
+	// TextLength is the length of the whole text.
+	TextLength int `json:"text_length"`
+	// NextOffset is the offset to pass to read the next chunk of the text,
+	// zero if Text holds the rest of the text.
+	NextOffset int `json:"next_offset,omitempty"`
+	// Pages are the offsets of the pages, if requested.
+	Pages []PageOffset `json:"pages,omitempty"`
+	// TextArtifact is the name of the artifact holding the whole text, saved
+	// when the text doesn't fit in one chunk, and TextReference its artifact
+	// reference (see artifact.NewReference).
+	TextArtifact  string `json:"text_artifact,omitempty"`
+	TextReference string `json:"text_reference,omitempty"`
+}
+
+// New creates a document extraction tool.
+func New(cfg Config) (tool.Tool, error) {
+	if cfg.Name == "" {
+		cfg.Name = defaultName
+	}
+	if cfg.Extractor == nil {
+		cfg.Extractor = Extractors{TextExtractor{}, DOCXExtractor{}}
+	}
+	if cfg.MaxTextLength <= 0 {
+		cfg.MaxTextLength = defaultMaxTextLength
+	}
+	if cfg.MaxDocumentSize <= 0 {
+		cfg.MaxDocumentSize = defaultMaxDocumentSize
+	}
+
+	e := &extractor{cfg: cfg}
+	documentTool, err := functiontool.New(functiontool.Config{
+		Name: cfg.Name,
+		Description: "Extracts the text of a document, such as an uploaded PDF or Word document, stored as an artifact. " +
+			"Long texts are returned in chunks: call again with next_offset to read the next chunk.",
+	}, e.extract)
+	if err != nil {
+		return nil, fmt.Errorf("error creating document extraction tool: %w", err)
+	}
+	return documentTool, nil
+}
+
+// TextArtifactName returns the name of the artifact holding the text
+// extracted from the document artifact: the name of the document followed by
+// ".txt", e.g. "report.pdf.txt".
+func TextArtifactName(documentArtifact string) string {
+	return documentArtifact + ".txt"
+}
+
+type extractor struct {
+	cfg Config
+}
+
+func (e *extractor) extract(ctx tool.Context, args Args) (Result, error) {
+	if args.Artifact == "" {
+		return Result{}, errors.New("artifact is required")
+	}
+	if args.Offset < 0 {
+		return Result{}, fmt.Errorf("invalid offset %d", args.Offset)
+	}
+	var loaded *artifact.LoadResponse
+	var err error
+	if args.Version > 0 {
+		loaded, err = ctx.Artifacts().LoadVersion(ctx, args.Artifact, int(args.Version))
+	} else {
+		loaded, err = ctx.Artifacts().Load(ctx, args.Artifact)
+	}
+	if err != nil {
+		return Result{}, fmt.Errorf("failed to load artifact %q: %w", args.Artifact, err)
+	}
+	data, mimeType := content(args.Artifact, loaded.Part)
+	if len(data) > e.cfg.MaxDocumentSize {
+		return Result{}, fmt.Errorf("the document is too large: %d bytes, the maximum is %d", len(data), e.cfg.MaxDocumentSize)
+	}
+
+	doc, err := e.cfg.Extractor.Extract(ctx, data, mimeType)
+	if errors.Is(err, ErrUnsupportedFormat) {
+		return Result{}, fmt.Errorf("%w %q of artifact %q", ErrUnsupportedFormat, mimeType, args.Artifact)
+	}
+	if err != nil {
+		return Result{}, fmt.Errorf("failed to extract the text of artifact %q: %w", args.Artifact, err)
+	}
+
+	var text strings.Builder
+	pages := make([]PageOffset, 0, len(doc.Pages))
+	for i, page := range doc.Pages {
+		if i > 0 {
+			text.WriteString(pageSeparator)
+		}
+		pages = append(pages, PageOffset{Number: page.Number, Offset: text.Len()})
+		text.WriteString(page.Text)
+	}
+	result := Result{
+		Artifact:   args.Artifact,
+		MIMEType:   mimeType,
+		PageCount:  len(doc.Pages),
+		TextLength: text.Len(),
+	}
+	if args.IncludePages {
+		result.Pages = pages
+	}
+
+	full := text.String()
+	if args.Offset < len(full) {
+		chunk := full[args.Offset:]
+		if len(chunk) > e.cfg.MaxTextLength {
+			n := e.cfg.MaxTextLength
+			for n > 0 && !utf8.RuneStart(chunk[n]) {
+				n--
+			}
+			chunk = chunk[:n]
+			result.NextOffset = args.Offset + len(chunk)
+		}
+		result.Text = chunk
+	}
+
+	// The whole text is saved once, with the first chunk.
+	if len(full) > e.cfg.MaxTextLength && args.Offset == 0 {
+		name := TextArtifactName(args.Artifact)
+		saved, err := ctx.Artifacts().Save(ctx, name, genai.NewPartFromText(full))
+		if err != nil {
+			return Result{}, fmt.Errorf("failed to save the extracted text: %w", err)
+		}
+		result.TextArtifact = name
+		result.TextReference = artifact.NewReference(name, saved.Version, "text/plain").FileData.FileURI
+	}
+	return result, nil
+}
+
+// content returns the data of the artifact and its MIME type, guessed from the
+// artifact name and the data when the part doesn't tell it.
+func content(name string, part *genai.Part) ([]byte, string) {
+	if part.InlineData == nil {
+		return []byte(part.Text), "text/plain"
+	}
+	data, mimeType := part.InlineData.Data, part.InlineData.MIMEType
+	if mimeType, _, err := mime.ParseMediaType(mimeType); err == nil && mimeType != "application/octet-stream" {
+		return data, mimeType
+	}
+	switch ext := strings.ToLower(path.Ext(name)); {
+	case ext == ".pdf" || bytes.HasPrefix(data, []byte("%PDF-")):
+		return data, MIMETypePDF
+	case ext == ".docx":
+		return data, MIMETypeDOCX
+	case ext == ".md":
+		return data, "text/markdown"
+	case ext != "" && mime.TypeByExtension(ext) != "":
+		mimeType, _, _ := mime.ParseMediaType(mime.TypeByExtension(ext))
+		return data, mimeType
+	}
+	return data, "application/octet-stream"
+}
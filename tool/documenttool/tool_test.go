@@ -0,0 +1,219 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package documenttool_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/artifact"
+	artifactinternal "google.golang.org/adk/internal/artifact"
+	icontext "google.golang.org/adk/internal/context"
+	"google.golang.org/adk/internal/toolinternal"
+	"google.golang.org/adk/tool"
+	"google.golang.org/adk/tool/documenttool"
+)
+
+// fakePDFExtractor "extracts" PDF documents whose pages are separated by
+// form feeds.
+type fakePDFExtractor struct{}
+
+func (fakePDFExtractor) Extract(_ context.Context, data []byte, mimeType string) (*documenttool.Document, error) {
+	if mimeType != documenttool.MIMETypePDF {
+		return nil, fmt.Errorf("%w %q", documenttool.ErrUnsupportedFormat, mimeType)
+	}
+	doc := &documenttool.Document{}
+	for i, text := range strings.Split(strings.TrimPrefix(string(data), "%PDF-1.7\n"), "\f") {
+		doc.Pages = append(doc.Pages, documenttool.Page{Number: i + 1, Text: text})
+	}
+	return doc, nil
+}
+
+func newDOCX(t *testing.T, body string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	f, err := w.Create("word/document.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	fmt.Fprintf(f, `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>`+
+		`<w:document xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main"><w:body>%s</w:body></w:document>`, body)
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func newToolContext(t *testing.T, artifacts map[string]*genai.Part) tool.Context {
+	t.Helper()
+	ctx := icontext.NewInvocationContext(t.Context(), icontext.InvocationContextParams{
+		Artifacts: &artifactinternal.Artifacts{
+			Service:   artifact.InMemoryService(),
+			AppName:   "app",
+			UserID:    "user",
+			SessionID: "session",
+		},
+	})
+	toolCtx := toolinternal.NewToolContext(ctx, "call1", nil)
+	for name, part := range artifacts {
+		if _, err := toolCtx.Artifacts().Save(t.Context(), name, part); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return toolCtx
+}
+
+func TestExtractDocument(t *testing.T) {
+	docx := newDOCX(t, `<w:p><w:r><w:t>Quarterly</w:t></w:r><w:r><w:tab/><w:t xml:space="preserve">report </w:t></w:r></w:p>`+
+		`<w:p><w:r><w:t>Revenue grew.</w:t><w:br w:type="page"/></w:r></w:p>`+
+		`<w:p><w:r><w:t>Appendix</w:t></w:r></w:p>`)
+	ctx := newToolContext(t, map[string]*genai.Part{
+		"notes.txt":   genai.NewPartFromText("Meeting at noon."),
+		"report.docx": genai.NewPartFromBytes(docx, "application/octet-stream"),
+		"scan.pdf":    genai.NewPartFromBytes([]byte("%PDF-1.7\nPage one.\fPage two."), documenttool.MIMETypePDF),
+		"photo.png":   genai.NewPartFromBytes([]byte("\x89PNG"), "image/png"),
+	})
+	documentTool, err := documenttool.New(documenttool.Config{
+		Extractor: documenttool.Extractors{fakePDFExtractor{}, documenttool.TextExtractor{}, documenttool.DOCXExtractor{}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		args    map[string]any
+		want    map[string]any
+		wantErr string
+	}{
+		{
+			name: "text",
+			args: map[string]any{"artifact": "notes.txt"},
+			want: map[string]any{"artifact": "notes.txt", "mime_type": "text/plain", "page_count": 1.0, "text": "Meeting at noon.", "text_length": 16.0},
+		},
+		{
+			name: "word",
+			args: map[string]any{"artifact": "report.docx", "include_pages": true},
+			want: map[string]any{
+				"artifact":    "report.docx",
+				"mime_type":   documenttool.MIMETypeDOCX,
+				"page_count":  2.0,
+				"text":        "Quarterly\treport \nRevenue grew.\n\nAppendix",
+				"text_length": 41.0,
+				"pages":       []any{map[string]any{"number": 1.0, "offset": 0.0}, map[string]any{"number": 2.0, "offset": 33.0}},
+			},
+		},
+		{
+			name: "pdf",
+			args: map[string]any{"artifact": "scan.pdf"},
+			want: map[string]any{"artifact": "scan.pdf", "mime_type": documenttool.MIMETypePDF, "page_count": 2.0, "text": "Page one.\n\nPage two.", "text_length": 20.0},
+		},
+		{
+			name:    "unsupported format",
+			args:    map[string]any{"artifact": "photo.png"},
+			wantErr: `unsupported document format "image/png"`,
+		},
+		{
+			name:    "missing artifact",
+			args:    map[string]any{"artifact": "missing.pdf"},
+			wantErr: `failed to load artifact "missing.pdf"`,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := documentTool.(toolinternal.FunctionTool).Run(ctx, tc.args)
+			if tc.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+					t.Fatalf("Run() error = %v, want %q", err, tc.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Run() error = %v", err)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("Run() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestExtractDocument_LargeDocument(t *testing.T) {
+	text := strings.Repeat("é", 15) // 30 bytes
+	ctx := newToolContext(t, map[string]*genai.Part{"big.md": genai.NewPartFromBytes([]byte(text), "")})
+	documentTool, err := documenttool.New(documenttool.Config{MaxTextLength: 11, MaxDocumentSize: 40})
+	if err != nil {
+		t.Fatal(err)
+	}
+	run := func(args map[string]any) (map[string]any, error) {
+		return documentTool.(toolinternal.FunctionTool).Run(ctx, args)
+	}
+
+	// The chunks end at character boundaries.
+	got, err := run(map[string]any{"artifact": "big.md"})
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	want := map[string]any{
+		"artifact":       "big.md",
+		"mime_type":      "text/markdown",
+		"page_count":     1.0,
+		"text":           strings.Repeat("é", 5),
+		"text_length":    30.0,
+		"next_offset":    10.0,
+		"text_artifact":  "big.md.txt",
+		"text_reference": artifact.NewReference("big.md.txt", 1, "text/plain").FileData.FileURI,
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Run() first chunk mismatch (-want +got):\n%s", diff)
+	}
+	saved, err := ctx.Artifacts().Load(t.Context(), documenttool.TextArtifactName("big.md"))
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if saved.Part.Text != text {
+		t.Errorf("saved text = %q, want %q", saved.Part.Text, text)
+	}
+
+	var chunks []string
+	for offset := 10.0; offset != 0; {
+		got, err := run(map[string]any{"artifact": "big.md", "offset": offset})
+		if err != nil {
+			t.Fatalf("Run(offset %v) error = %v", offset, err)
+		}
+		if _, ok := got["text_artifact"]; ok {
+			t.Errorf("Run(offset %v) saved the text again", offset)
+		}
+		chunks = append(chunks, got["text"].(string))
+		offset, _ = got["next_offset"].(float64)
+	}
+	if want := []string{strings.Repeat("é", 5), strings.Repeat("é", 5)}; !cmp.Equal(want, chunks) {
+		t.Errorf("next chunks = %q, want %q", chunks, want)
+	}
+
+	// Too large documents are rejected.
+	ctx = newToolContext(t, map[string]*genai.Part{"huge.txt": genai.NewPartFromText(strings.Repeat("a", 41))})
+	if _, err := documentTool.(toolinternal.FunctionTool).Run(ctx, map[string]any{"artifact": "huge.txt"}); err == nil {
+		t.Error("Run() of a too large document succeeded, want error")
+	}
+}
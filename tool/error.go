@@ -0,0 +1,103 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tool
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrToolNotFound is the error, possibly wrapped, of the function calls of
+// the model to a tool the agent does not have. It ends the invocation.
+var ErrToolNotFound = errors.New("unknown tool")
+
+// ErrSchemaValidation matches, with errors.Is, the errors of the calls whose
+// arguments don't match the input schema of the tool, e.g. the
+// *functiontool.ValidationError.
+var ErrSchemaValidation = errors.New("arguments do not match the schema of the tool")
+
+// ErrAuthRequired is returned, possibly wrapped, by the tools needing a
+// credential of the user they don't have, e.g. after requesting it with
+// Context.RequestCredential.
+var ErrAuthRequired = errors.New("authentication required")
+
+// ToolError is an error of a tool call reported to the model as a
+// structured function response, so that it can read the failure and adjust,
+// e.g. call the tool again with other arguments:
+//
+//	{"error": {"code": Code, "message": Message, "details": Details}}
+//
+// The code and the details are omitted if they are empty. A handler returns
+// it, possibly wrapped, to control the payload of its failure, e.g.
+//
+//	return nil, &tool.ToolError{Code: "not_found", Message: "no such city"}
+//
+// The errors of the calls are recoverable: a call failing with any other
+// error is reported to the model too, as {"error": err.Error()}, and the
+// agent goes on. They are fatal, ending the invocation with the error, only
+// if the invocation is canceled, e.g. when its context is done, since the
+// model cannot be called anymore.
+type ToolError struct {
+	// Code optionally identifies the kind of failure, e.g. "not_found".
+	Code string
+	// Message describes the failure to the model.
+	Message string
+	// Details optionally gives structured data about the failure, e.g. the
+	// offending fields of the arguments.
+	Details map[string]any
+}
+
+// CodeInvalidArguments is the code of the failures of the calls whose
+// arguments don't match the input schema of the tool, e.g. the
+// *functiontool.ValidationError, reported to the model so that it corrects
+// them. See llmagent.Config.InvalidArgumentsRetries.
+const CodeInvalidArguments = "invalid_arguments"
+
+func (e *ToolError) Error() string {
+	if e.Code == "" {
+		return e.Message
+	}
+	return e.Code + ": " + e.Message
+}
+
+// PanicMetadataKey is the key of the panics of the tools in the
+// CustomMetadata of the function response events: a map from the IDs of the
+// calls whose tool panicked to their panic value, under "value", and stack,
+// under "stack".
+const PanicMetadataKey = "tool_panics"
+
+// PanicError is the error of a tool call which panicked, recovered so that
+// the panic does not end the invocation. The call fails as if the tool
+// returned the error: the model is told that the tool failed, without the
+// stack, and the agent goes on. The stack is recorded in the function
+// response event, see PanicMetadataKey.
+type PanicError struct {
+	// Tool is the name of the tool.
+	Tool string
+	// Value is the value of the panic.
+	Value any
+	// Stack is the stack of the goroutine which panicked.
+	Stack []byte
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("panic in tool %q: %v\nstack: %s", e.Tool, e.Value, e.Stack)
+}
+
+// Unwrap returns the value of the panic if it is an error.
+func (e *PanicError) Unwrap() error {
+	err, _ := e.Value.(error)
+	return err
+}
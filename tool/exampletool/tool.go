@@ -0,0 +1,199 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package exampletool provides a tool adding few-shot examples to the system
+// instruction of the LLM requests, as the ExampleTool of adk-python: the
+// examples show the model how to answer queries, tool calls included.
+//
+// The examples are either fixed, see Examples, or retrieved for each user
+// query by a Provider, e.g. the examples most similar to the query from a
+// vector store.
+package exampletool
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/internal/toolinternal"
+	"google.golang.org/adk/internal/utils"
+	"google.golang.org/adk/model"
+	"google.golang.org/adk/tool"
+)
+
+// Example is a few-shot example: a user input, and the expected output, e.g.
+// the function calls of the model and their responses, then its answer.
+type Example struct {
+	Input  *genai.Content
+	Output []*genai.Content
+}
+
+// Provider provides the examples of a user query.
+type Provider interface {
+	Examples(ctx context.Context, query string) ([]Example, error)
+}
+
+// Examples are fixed examples, provided for all the queries.
+type Examples []Example
+
+// Examples implements Provider.
+func (e Examples) Examples(context.Context, string) ([]Example, error) {
+	return e, nil
+}
+
+// New returns a tool adding the examples of the provider for the text of the
+// user message to the system instruction. It adds nothing to the requests
+// without user text, e.g. the ones sending function responses.
+func New(provider Provider) tool.Tool {
+	return &exampleTool{provider: provider}
+}
+
+var _ toolinternal.RequestProcessor = (*exampleTool)(nil)
+
+type exampleTool struct {
+	provider Provider
+}
+
+// Name implements tool.Tool.
+func (t *exampleTool) Name() string {
+	return "example_tool"
+}
+
+// Description implements tool.Tool.
+func (t *exampleTool) Description() string {
+	return "Adds few-shot examples to the LLM requests."
+}
+
+// IsLongRunning implements tool.Tool.
+func (t *exampleTool) IsLongRunning() bool {
+	return false
+}
+
+// ProcessRequest adds the examples to the system instruction of the request.
+func (t *exampleTool) ProcessRequest(ctx tool.Context, req *model.LLMRequest) error {
+	query := strings.Join(utils.TextParts(ctx.UserContent()), "\n")
+	if strings.TrimSpace(query) == "" {
+		return nil
+	}
+	examples, err := t.provider.Examples(ctx, query)
+	if err != nil {
+		return fmt.Errorf("failed to get examples: %w", err)
+	}
+	if len(examples) == 0 {
+		return nil
+	}
+	utils.AppendInstructions(req, format(examples, req.Model))
+	return nil
+}
+
+// The parts of the formatted examples, as in adk-python.
+const (
+	examplesIntro          = "<EXAMPLES>\nBegin few-shot\nThe following are examples of user queries and model responses using the available tools.\n\n"
+	examplesEnd            = "End few-shot\nNow, try to follow these examples and complete the following conversation\n<EXAMPLES>"
+	exampleStart           = "EXAMPLE %d:\nBegin example\n"
+	exampleEnd             = "End example\n\n"
+	userPrefix             = "[user]\n"
+	modelPrefix            = "[model]\n"
+	functionPrefix         = "```\n"
+	functionCallPrefix     = "```tool_code\n"
+	functionResponsePrefix = "```tool_outputs\n"
+	functionSuffix         = "\n```\n"
+)
+
+// format formats the examples for the system instruction. The Gemini 2
+// models, the default, get the function calls and responses in plain code
+// blocks, the other models in tool_code and tool_outputs ones.
+func format(examples []Example, modelName string) string {
+	gemini2 := modelName == "" || strings.Contains(modelName, "gemini-2")
+	callPrefix, responsePrefix := functionCallPrefix, functionResponsePrefix
+	if gemini2 {
+		callPrefix, responsePrefix = functionPrefix, functionPrefix
+	}
+
+	var sb strings.Builder
+	sb.WriteString(examplesIntro)
+	for i, example := range examples {
+		fmt.Fprintf(&sb, exampleStart, i+1)
+		sb.WriteString(userPrefix)
+		if texts := utils.TextParts(example.Input); len(texts) > 0 {
+			sb.WriteString(strings.Join(texts, "\n") + "\n")
+		}
+		previousPrefix := ""
+		for _, content := range example.Output {
+			if content == nil {
+				continue
+			}
+			prefix := userPrefix
+			if content.Role == genai.RoleModel {
+				prefix = modelPrefix
+			}
+			if prefix != previousPrefix {
+				sb.WriteString(prefix)
+			}
+			previousPrefix = prefix
+			for _, part := range content.Parts {
+				switch {
+				case part.FunctionCall != nil:
+					sb.WriteString(callPrefix + formatCall(part.FunctionCall) + functionSuffix)
+				case part.FunctionResponse != nil:
+					sb.WriteString(responsePrefix + formatResponse(part.FunctionResponse) + functionSuffix)
+				case part.Text != "":
+					sb.WriteString(part.Text + "\n")
+				}
+			}
+		}
+		sb.WriteString(exampleEnd)
+	}
+	sb.WriteString(examplesEnd)
+	return sb.String()
+}
+
+// formatCall formats a function call as a call in code, e.g.
+// get_weather(city='Paris', days=2), with the arguments sorted by name.
+func formatCall(call *genai.FunctionCall) string {
+	names := make([]string, 0, len(call.Args))
+	for name := range call.Args {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	args := make([]string, 0, len(names))
+	for _, name := range names {
+		args = append(args, name+"="+formatValue(call.Args[name]))
+	}
+	return call.Name + "(" + strings.Join(args, ", ") + ")"
+}
+
+func formatValue(v any) string {
+	if s, ok := v.(string); ok {
+		return "'" + s + "'"
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(b)
+}
+
+// formatResponse formats a function response as JSON.
+func formatResponse(resp *genai.FunctionResponse) string {
+	b, err := json.Marshal(map[string]any{"name": resp.Name, "response": resp.Response})
+	if err != nil {
+		return fmt.Sprint(resp.Response)
+	}
+	return string(b)
+}
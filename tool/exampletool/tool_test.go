@@ -0,0 +1,145 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package exampletool_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/agent/llmagent"
+	"google.golang.org/adk/internal/testutil"
+	"google.golang.org/adk/tool"
+	"google.golang.org/adk/tool/exampletool"
+)
+
+var weatherExample = exampletool.Example{
+	Input: genai.NewContentFromText("What's the weather in Paris?", genai.RoleUser),
+	Output: []*genai.Content{
+		genai.NewContentFromParts([]*genai.Part{
+			genai.NewPartFromFunctionCall("get_weather", map[string]any{"city": "Paris", "days": 1}),
+		}, genai.RoleModel),
+		genai.NewContentFromParts([]*genai.Part{
+			genai.NewPartFromFunctionResponse("get_weather", map[string]any{"weather": "sunny"}),
+		}, genai.RoleUser),
+		genai.NewContentFromText("It is sunny in Paris.", genai.RoleModel),
+	},
+}
+
+// queryProvider provides the examples of the queries mentioning a city.
+type queryProvider struct {
+	queries []string
+}
+
+func (p *queryProvider) Examples(_ context.Context, query string) ([]exampletool.Example, error) {
+	p.queries = append(p.queries, query)
+	if strings.Contains(query, "Rome") {
+		return []exampletool.Example{weatherExample}, nil
+	}
+	return nil, nil
+}
+
+func systemInstruction(t *testing.T, provider exampletool.Provider, modelName, query string) string {
+	t.Helper()
+	m := &testutil.MockModel{Responses: []*genai.Content{genai.NewContentFromText("ok", genai.RoleModel)}}
+	a, err := llmagent.New(llmagent.Config{
+		Name:  "agent",
+		Model: namedModel{m, modelName},
+		Tools: []tool.Tool{exampletool.New(provider)},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := testutil.CollectEvents(testutil.NewTestAgentRunner(t, a).Run(t, "session", query)); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	cfg := m.Requests[0].Config
+	if cfg == nil || cfg.SystemInstruction == nil {
+		return ""
+	}
+	var texts []string
+	for _, p := range cfg.SystemInstruction.Parts {
+		texts = append(texts, p.Text)
+	}
+	return strings.Join(texts, "")
+}
+
+// namedModel overrides the name of a model.
+type namedModel struct {
+	*testutil.MockModel
+	name string
+}
+
+func (m namedModel) Name() string {
+	return m.name
+}
+
+func TestExampleTool(t *testing.T) {
+	wantGemini2 := "<EXAMPLES>\nBegin few-shot\nThe following are examples of user queries and model responses using the available tools.\n\n" +
+		"EXAMPLE 1:\nBegin example\n" +
+		"[user]\nWhat's the weather in Paris?\n" +
+		"[model]\n```\nget_weather(city='Paris', days=1)\n```\n" +
+		"[user]\n```\n{\"name\":\"get_weather\",\"response\":{\"weather\":\"sunny\"}}\n```\n" +
+		"[model]\nIt is sunny in Paris.\n" +
+		"End example\n\n" +
+		"End few-shot\nNow, try to follow these examples and complete the following conversation\n<EXAMPLES>"
+
+	got := systemInstruction(t, exampletool.Examples{weatherExample}, "gemini-2.5-flash", "Weather in Rome?")
+	if diff := cmp.Diff(wantGemini2, got); diff != "" {
+		t.Errorf("system instruction mismatch (-want +got):\n%s", diff)
+	}
+
+	got = systemInstruction(t, exampletool.Examples{weatherExample}, "gemini-1.5-pro", "Weather in Rome?")
+	for _, want := range []string{"```tool_code\nget_weather(city='Paris', days=1)\n```\n", "```tool_outputs\n{"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("system instruction %q does not contain %q", got, want)
+		}
+	}
+}
+
+func TestExampleTool_Provider(t *testing.T) {
+	provider := &queryProvider{}
+	if got := systemInstruction(t, provider, "gemini-2.5-flash", "Weather in Rome?"); !strings.Contains(got, "EXAMPLE 1:") {
+		t.Errorf("system instruction = %q, want the examples of the query", got)
+	}
+	if got := systemInstruction(t, provider, "gemini-2.5-flash", "Hello"); strings.Contains(got, "<EXAMPLES>") {
+		t.Errorf("system instruction = %q, want no examples", got)
+	}
+	if diff := cmp.Diff([]string{"Weather in Rome?", "Hello"}, provider.queries); diff != "" {
+		t.Errorf("provider queries mismatch (-want +got):\n%s", diff)
+	}
+}
+
+type failingProvider struct{}
+
+func (failingProvider) Examples(context.Context, string) ([]exampletool.Example, error) {
+	return nil, errors.New("vector store unavailable")
+}
+
+func TestExampleTool_ProviderError(t *testing.T) {
+	m := &testutil.MockModel{Responses: []*genai.Content{genai.NewContentFromText("ok", genai.RoleModel)}}
+	a, err := llmagent.New(llmagent.Config{Name: "agent", Model: m, Tools: []tool.Tool{exampletool.New(failingProvider{})}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = testutil.CollectEvents(testutil.NewTestAgentRunner(t, a).Run(t, "session", "Hi"))
+	if err == nil || !strings.Contains(err.Error(), "vector store unavailable") {
+		t.Errorf("Run() error = %v, want the provider error", err)
+	}
+}
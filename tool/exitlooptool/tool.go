@@ -13,6 +13,13 @@
 // limitations under the License.
 
 // Package exitlooptool provides a tool that allows an agent to exit a loop.
+//
+// The tool has no parameters and returns an empty result. It sets the
+// Escalate action, and SkipSummarization, on the event of its function
+// response: the loop agent running the agent, see the loopagent package,
+// watches the events of its sub-agents and stops once the sub-agent that
+// called the tool finishes, without running the next sub-agents or starting
+// another iteration.
 package exitlooptool
 
 import (
@@ -23,9 +30,11 @@ import (
 )
 
 // EmptyArgs is an empty struct used as an argument for the exitLoop tool.
+//
+// Deprecated: the tool has no arguments.
 type EmptyArgs struct{}
 
-func exitLoop(ctx tool.Context, myArgs EmptyArgs) (map[string]string, error) {
+func exitLoop(ctx tool.Context) (map[string]string, error) {
 	ctx.Actions().Escalate = true
 	ctx.Actions().SkipSummarization = true
 	return map[string]string{}, nil
@@ -33,7 +42,7 @@ func exitLoop(ctx tool.Context, myArgs EmptyArgs) (map[string]string, error) {
 
 // New creates an instance of an exitLoop tool.
 func New() (tool.Tool, error) {
-	exitLoopTool, err := functiontool.New(functiontool.Config{
+	exitLoopTool, err := functiontool.NewNoArgs(functiontool.Config{
 		Name:        "exit_loop",
 		Description: "Exits the loop.\nCall this function only when you are instructed to do so.\n",
 	}, exitLoop)
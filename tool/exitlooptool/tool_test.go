@@ -25,10 +25,22 @@ import (
 	"google.golang.org/adk/agent/llmagent"
 	"google.golang.org/adk/agent/workflowagents/loopagent"
 	"google.golang.org/adk/internal/testutil"
+	"google.golang.org/adk/internal/toolinternal"
 	"google.golang.org/adk/tool"
 	"google.golang.org/adk/tool/exitlooptool"
 )
 
+func TestExitLoopToolDeclaration(t *testing.T) {
+	exitLoopTool, err := exitlooptool.New()
+	if err != nil {
+		t.Fatalf("failed to create exit tool: %v", err)
+	}
+	decl := exitLoopTool.(toolinternal.FunctionTool).Declaration()
+	if decl.Parameters != nil || decl.ParametersJsonSchema != nil {
+		t.Errorf("Declaration() has parameters %v %v, want none", decl.Parameters, decl.ParametersJsonSchema)
+	}
+}
+
 // --- Test Suite ---
 func TestExitLoopToolExitsLoopAgent(t *testing.T) {
 	// Define the structure for our test cases
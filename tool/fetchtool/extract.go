@@ -0,0 +1,335 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fetchtool
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+var (
+	// skippedElements are never content.
+	skippedElements = map[atom.Atom]bool{
+		atom.Head: true, atom.Script: true, atom.Style: true, atom.Noscript: true, atom.Template: true,
+		atom.Svg: true, atom.Canvas: true, atom.Iframe: true, atom.Object: true, atom.Embed: true,
+		atom.Img: true, atom.Picture: true, atom.Video: true, atom.Audio: true,
+		atom.Nav: true, atom.Aside: true, atom.Form: true, atom.Button: true, atom.Input: true,
+		atom.Select: true, atom.Textarea: true, atom.Dialog: true,
+	}
+	blockElements = map[atom.Atom]bool{
+		atom.P: true, atom.Div: true, atom.Section: true, atom.Article: true, atom.Main: true,
+		atom.Header: true, atom.Footer: true, atom.Figure: true, atom.Figcaption: true,
+		atom.Dl: true, atom.Dt: true, atom.Dd: true, atom.Details: true, atom.Summary: true,
+		atom.Address: true, atom.Hr: true, atom.Br: true, atom.Table: true,
+	}
+	headings = map[atom.Atom]int{
+		atom.H1: 1, atom.H2: 2, atom.H3: 3, atom.H4: 4, atom.H5: 5, atom.H6: 6,
+	}
+)
+
+// extract returns the title and the main content of the page: the content
+// of its main element, or else of its article element, or else of its body
+// without the headers and footers.
+func extract(doc *html.Node, base *url.URL, format Format) (title, content string) {
+	title = collapse(textOf(find(doc, atom.Title)))
+	root := find(doc, atom.Main)
+	if root == nil {
+		root = find(doc, atom.Article)
+	}
+	c := &converter{markdown: format == FormatMarkdown, base: base}
+	if root == nil {
+		root = doc
+		c.skipChrome = true
+	}
+	c.children(root)
+	c.flush()
+	return title, c.String()
+}
+
+type block struct {
+	text string
+	// tight blocks, list items and table rows, are not separated by a
+	// blank line from the previous tight block.
+	tight bool
+}
+
+// converter renders HTML as a sequence of blocks of markdown or text.
+type converter struct {
+	markdown   bool
+	base       *url.URL
+	skipChrome bool
+
+	blocks []block
+	line   strings.Builder
+	// prefix and tight apply to the block of the current line.
+	prefix string
+	tight  bool
+	lists  []list
+	rows   int
+}
+
+type list struct {
+	ordered bool
+	items   int
+}
+
+func (c *converter) String() string {
+	var b strings.Builder
+	for i, bl := range c.blocks {
+		if i > 0 {
+			if !c.markdown || (bl.tight && c.blocks[i-1].tight) {
+				b.WriteString("\n")
+			} else {
+				b.WriteString("\n\n")
+			}
+		}
+		b.WriteString(bl.text)
+	}
+	return b.String()
+}
+
+// flush ends the block of the current line.
+func (c *converter) flush() {
+	s := collapse(c.line.String())
+	c.line.Reset()
+	if s == "" {
+		return
+	}
+	c.add(c.prefix+s, c.tight)
+	c.prefix, c.tight = "", false
+}
+
+func (c *converter) add(text string, tight bool) {
+	c.blocks = append(c.blocks, block{text: text, tight: tight})
+}
+
+func (c *converter) children(n *html.Node) {
+	for child := n.FirstChild; child != nil; child = child.NextSibling {
+		c.visit(child)
+	}
+}
+
+// inline returns the text of the children of the node on a single line.
+func (c *converter) inline(n *html.Node) string {
+	sub := &converter{markdown: c.markdown, base: c.base, skipChrome: c.skipChrome}
+	sub.children(n)
+	sub.flush()
+	texts := make([]string, len(sub.blocks))
+	for i, b := range sub.blocks {
+		texts[i] = b.text
+	}
+	return collapse(strings.Join(texts, " "))
+}
+
+func (c *converter) visit(n *html.Node) {
+	switch n.Type {
+	case html.TextNode:
+		c.line.WriteString(n.Data)
+		return
+	case html.ElementNode:
+	default:
+		c.children(n)
+		return
+	}
+	if skippedElements[n.DataAtom] || hidden(n) {
+		return
+	}
+	if c.skipChrome && (n.DataAtom == atom.Header || n.DataAtom == atom.Footer) {
+		return
+	}
+
+	if level, ok := headings[n.DataAtom]; ok {
+		c.flush()
+		if s := c.inline(n); s != "" {
+			if c.markdown {
+				s = strings.Repeat("#", level) + " " + s
+			}
+			c.add(s, false)
+		}
+		return
+	}
+	switch n.DataAtom {
+	case atom.Pre:
+		c.flush()
+		s := strings.Trim(textOf(n), "\n")
+		if strings.TrimSpace(s) == "" {
+			return
+		}
+		if c.markdown {
+			s = "```\n" + s + "\n```"
+		}
+		c.add(s, false)
+	case atom.Code, atom.Kbd, atom.Samp:
+		if s := collapse(textOf(n)); s != "" {
+			c.mark("`", s)
+		}
+	case atom.Strong, atom.B:
+		c.mark("**", c.inline(n))
+	case atom.Em, atom.I:
+		c.mark("_", c.inline(n))
+	case atom.A:
+		s := c.inline(n)
+		if href := c.resolve(attr(n, "href")); c.markdown && href != "" && s != "" {
+			s = "[" + s + "](" + href + ")"
+		}
+		c.line.WriteString(s)
+	case atom.Ul, atom.Ol:
+		c.flush()
+		c.lists = append(c.lists, list{ordered: n.DataAtom == atom.Ol})
+		c.children(n)
+		c.flush()
+		c.lists = c.lists[:len(c.lists)-1]
+	case atom.Li:
+		c.flush()
+		marker := "- "
+		indent := ""
+		if len(c.lists) > 0 {
+			l := &c.lists[len(c.lists)-1]
+			l.items++
+			if l.ordered {
+				marker = fmt.Sprintf("%d. ", l.items)
+			}
+			indent = strings.Repeat("  ", len(c.lists)-1)
+		}
+		c.prefix, c.tight = indent+marker, true
+		c.children(n)
+		c.flush()
+	case atom.Blockquote:
+		c.flush()
+		start := len(c.blocks)
+		c.children(n)
+		c.flush()
+		if c.markdown {
+			for i := start; i < len(c.blocks); i++ {
+				c.blocks[i].text = "> " + strings.ReplaceAll(c.blocks[i].text, "\n", "\n> ")
+			}
+		}
+	case atom.Table:
+		c.flush()
+		rows := c.rows
+		c.rows = 0
+		c.children(n)
+		c.flush()
+		c.rows = rows
+	case atom.Tr:
+		c.flush()
+		var cells []string
+		for cell := n.FirstChild; cell != nil; cell = cell.NextSibling {
+			if cell.DataAtom == atom.Td || cell.DataAtom == atom.Th {
+				cells = append(cells, c.inline(cell))
+			}
+		}
+		if len(cells) == 0 {
+			return
+		}
+		c.rows++
+		row := strings.Join(cells, " | ")
+		if c.markdown {
+			row = "| " + row + " |"
+			if c.rows == 1 {
+				row += "\n|" + strings.Repeat(" --- |", len(cells))
+			}
+		}
+		c.add(row, true)
+	default:
+		block := blockElements[n.DataAtom]
+		if block {
+			c.flush()
+		}
+		c.children(n)
+		if block {
+			c.flush()
+		}
+	}
+}
+
+// mark writes the text surrounded by the markdown mark, or as is for plain
+// text.
+func (c *converter) mark(mark, s string) {
+	if s == "" {
+		return
+	}
+	if c.markdown {
+		s = mark + s + mark
+	}
+	c.line.WriteString(s)
+}
+
+// resolve returns the absolute URL of a link, or "" for links within the
+// page or to scripts.
+func (c *converter) resolve(href string) string {
+	href = strings.TrimSpace(href)
+	if href == "" || strings.HasPrefix(href, "#") {
+		return ""
+	}
+	u, err := c.base.Parse(href)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https" && u.Scheme != "mailto") {
+		return ""
+	}
+	return u.String()
+}
+
+func hidden(n *html.Node) bool {
+	for _, a := range n.Attr {
+		if a.Key == "hidden" || (a.Key == "aria-hidden" && a.Val == "true") {
+			return true
+		}
+	}
+	style := strings.ReplaceAll(strings.ToLower(attr(n, "style")), " ", "")
+	return strings.Contains(style, "display:none") || strings.Contains(style, "visibility:hidden")
+}
+
+func find(n *html.Node, a atom.Atom) *html.Node {
+	if n.Type == html.ElementNode && n.DataAtom == a {
+		return n
+	}
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if found := find(c, a); found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
+func textOf(n *html.Node) string {
+	if n == nil {
+		return ""
+	}
+	if n.Type == html.TextNode {
+		return n.Data
+	}
+	var b strings.Builder
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		b.WriteString(textOf(c))
+	}
+	return b.String()
+}
+
+func collapse(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+func attr(n *html.Node, key string) string {
+	for _, a := range n.Attr {
+		if a.Key == key {
+			return a.Val
+		}
+	}
+	return ""
+}
@@ -0,0 +1,211 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fetchtool
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	robotsTTL      = time.Hour
+	maxRobotsBytes = 500 << 10
+)
+
+// robotsRules are the rules of a robots.txt for a user agent, see RFC 9309.
+type robotsRules struct {
+	rules []robotsRule
+}
+
+type robotsRule struct {
+	allow   bool
+	pattern string
+	re      *regexp.Regexp
+}
+
+var (
+	allowAll    = &robotsRules{}
+	disallowAll = &robotsRules{rules: []robotsRule{{pattern: "/", re: regexp.MustCompile("^/")}}}
+)
+
+// allowed reports whether the URL may be fetched: the rule with the longest
+// pattern matching its path applies, an allow rule winning a tie.
+func (r *robotsRules) allowed(u *url.URL) bool {
+	path := u.EscapedPath()
+	if path == "" {
+		path = "/"
+	}
+	if path == "/robots.txt" {
+		return true
+	}
+	if u.RawQuery != "" {
+		path += "?" + u.RawQuery
+	}
+	allowed, length := true, -1
+	for _, rule := range r.rules {
+		if !rule.re.MatchString(path) {
+			continue
+		}
+		if len(rule.pattern) > length || (len(rule.pattern) == length && rule.allow) {
+			allowed, length = rule.allow, len(rule.pattern)
+		}
+	}
+	return allowed
+}
+
+// parseRobots returns the rules of the robots.txt for the user agent: the
+// rules of the groups naming its product token, or else of the groups for
+// "*".
+func parseRobots(r io.Reader, userAgent string) *robotsRules {
+	token := strings.ToLower(userAgent)
+	if i := strings.IndexAny(token, "/ "); i >= 0 {
+		token = token[:i]
+	}
+
+	type group struct {
+		agents []string
+		rules  []robotsRule
+	}
+	var groups []*group
+	var current *group
+	inAgents := false
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line, _, _ := strings.Cut(scanner.Text(), "#")
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		key, value = strings.ToLower(strings.TrimSpace(key)), strings.TrimSpace(value)
+		switch key {
+		case "user-agent":
+			if !inAgents {
+				current = &group{}
+				groups = append(groups, current)
+				inAgents = true
+			}
+			current.agents = append(current.agents, strings.ToLower(value))
+		case "allow", "disallow":
+			inAgents = false
+			if current == nil || value == "" {
+				continue
+			}
+			current.rules = append(current.rules, robotsRule{
+				allow:   key == "allow",
+				pattern: value,
+				re:      patternRegexp(value),
+			})
+		}
+	}
+
+	var matched, wildcard []robotsRule
+	for _, g := range groups {
+		for _, agent := range g.agents {
+			switch agent {
+			case token:
+				matched = append(matched, g.rules...)
+			case "*":
+				wildcard = append(wildcard, g.rules...)
+			}
+		}
+	}
+	if matched == nil {
+		matched = wildcard
+	}
+	return &robotsRules{rules: matched}
+}
+
+// patternRegexp returns the regular expression of a path pattern, where "*"
+// matches any sequence of characters and a final "$" the end of the path.
+func patternRegexp(pattern string) *regexp.Regexp {
+	end := strings.HasSuffix(pattern, "$")
+	pattern = strings.TrimSuffix(pattern, "$")
+	parts := strings.Split(pattern, "*")
+	for i, p := range parts {
+		parts[i] = regexp.QuoteMeta(p)
+	}
+	expr := "^" + strings.Join(parts, ".*")
+	if end {
+		expr += "$"
+	}
+	return regexp.MustCompile(expr)
+}
+
+// robotsCache caches the robots.txt rules by origin.
+type robotsCache struct {
+	client *http.Client
+
+	mu      sync.Mutex
+	entries map[string]robotsEntry
+}
+
+type robotsEntry struct {
+	rules   *robotsRules
+	expires time.Time
+}
+
+func newRobotsCache() *robotsCache {
+	return &robotsCache{entries: make(map[string]robotsEntry)}
+}
+
+// get returns the robots.txt rules of the origin of the URL for the user
+// agent, fetching them if needed. As per RFC 9309, a missing robots.txt
+// allows everything and an unreachable one disallows everything.
+func (c *robotsCache) get(ctx context.Context, u *url.URL, userAgent string) (*robotsRules, error) {
+	origin := u.Scheme + "://" + strings.ToLower(u.Host)
+	c.mu.Lock()
+	entry, ok := c.entries[origin]
+	c.mu.Unlock()
+	if ok && time.Now().Before(entry.expires) {
+		return entry.rules, nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, origin+"/robots.txt", nil)
+	if err != nil {
+		return nil, fmt.Errorf("invalid URL %q: %w", u, err)
+	}
+	req.Header.Set("User-Agent", userAgent)
+	resp, err := c.client.Do(req)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, fmt.Errorf("failed to fetch the robots.txt of %s: %w", u.Host, err)
+		}
+		return c.store(origin, disallowAll), nil
+	}
+	defer resp.Body.Close()
+	switch {
+	case resp.StatusCode >= 200 && resp.StatusCode <= 299:
+		return c.store(origin, parseRobots(io.LimitReader(resp.Body, maxRobotsBytes), userAgent)), nil
+	case resp.StatusCode >= 400 && resp.StatusCode <= 499:
+		return c.store(origin, allowAll), nil
+	default:
+		return c.store(origin, disallowAll), nil
+	}
+}
+
+func (c *robotsCache) store(origin string, rules *robotsRules) *robotsRules {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries[origin] = robotsEntry{rules: rules, expires: time.Now().Add(robotsTTL)}
+	return rules
+}
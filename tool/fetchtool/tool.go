@@ -0,0 +1,331 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package fetchtool provides a tool that lets the model read web pages: it
+// fetches a URL and returns the main content of the page as markdown or
+// plain text, without the boilerplate such as the navigation, the headers
+// and footers, the scripts and the styles.
+//
+// Pair it with a search tool, e.g. geminitool.GoogleSearch or an agent tool
+// wrapping an agent which searches, so that the model can read the pages it
+// finds rather than rely on their snippets.
+//
+// # Access control
+//
+// Only http and https URLs are fetched. Config.AllowedHosts and
+// Config.DeniedHosts restrict the hosts, each entry matching the host and
+// its subdomains; the denied hosts take precedence. The robots.txt of each
+// site is honored for Config.UserAgent, unless Config.IgnoreRobotsTxt is
+// set. Redirects are checked as the URL requested.
+//
+// # Limits
+//
+// The response body is read up to Config.MaxBytes; the content of longer
+// bodies is truncated. The content is returned at most Config.MaxLength
+// bytes per call: the model reads the rest of long pages with an offset.
+// Every fetch is bounded by Config.Timeout.
+package fetchtool
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/charset"
+
+	"google.golang.org/adk/tool"
+	"google.golang.org/adk/tool/functiontool"
+)
+
+const (
+	defaultName        = "fetch_url"
+	defaultDescription = "Fetches a web page by URL and returns its main content. Long pages are returned in parts: call again with next_offset to read the rest."
+	defaultUserAgent   = "adk-fetch/1.0"
+	defaultMaxBytes    = 2 << 20
+	defaultMaxLength   = 8000
+	defaultTimeout     = 30 * time.Second
+	maxRedirects       = 10
+)
+
+// Format of the content returned for HTML pages.
+type Format string
+
+const (
+	// FormatMarkdown keeps the headings, lists, links, emphasis and code of
+	// the page as markdown.
+	FormatMarkdown Format = "markdown"
+	// FormatText returns the plain text of the page, one block per line.
+	FormatText Format = "text"
+)
+
+// Config is the configuration of the fetch tool.
+type Config struct {
+	// Name of the tool. Defaults to "fetch_url".
+	Name string
+	// Description of the tool. Defaults to a generic description.
+	Description string
+	// Client sending the requests. Defaults to a client with no timeout
+	// other than Config.Timeout. Its redirect policy is replaced to check
+	// the redirects.
+	Client *http.Client
+	// UserAgent sent with the requests and matched against the robots.txt
+	// rules. Defaults to "adk-fetch/1.0".
+	UserAgent string
+	// AllowedHosts restricts the URLs to these hosts and their subdomains.
+	// All hosts are allowed if empty.
+	AllowedHosts []string
+	// DeniedHosts forbids these hosts and their subdomains.
+	DeniedHosts []string
+	// IgnoreRobotsTxt disables the robots.txt checks.
+	IgnoreRobotsTxt bool
+	// MaxBytes is the maximum size in bytes of the response body read.
+	// Defaults to 2 MiB.
+	MaxBytes int64
+	// MaxLength is the maximum length in bytes of the content returned per
+	// call. Defaults to 8000.
+	MaxLength int
+	// Format of the content of HTML pages. Defaults to FormatMarkdown.
+	Format Format
+	// Timeout bounds every fetch, the robots.txt included. Defaults to 30
+	// seconds.
+	Timeout time.Duration
+}
+
+// Args are the arguments of the fetch tool.
+type Args struct {
+	URL    string `json:"url" jsonschema:"The http or https URL of the page."`
+	Offset int    `json:"offset,omitempty" jsonschema:"Offset in the content to read from, the next_offset of the previous call to read the rest of a long page."`
+}
+
+// Result is the result of the fetch tool.
+type Result struct {
+	// URL of the page, after the redirects.
+	URL   string `json:"url"`
+	Title string `json:"title,omitempty"`
+	// Content of the page from the offset.
+	Content string `json:"content"`
+	// ContentLength is the length in bytes of the whole content.
+	ContentLength int `json:"content_length"`
+	// NextOffset is the offset of the rest of the content, if any.
+	NextOffset int `json:"next_offset,omitempty"`
+	// Truncated reports that the page exceeded the size limit and its end
+	// is missing.
+	Truncated bool `json:"truncated,omitempty"`
+}
+
+type fetcher struct {
+	cfg    Config
+	client *http.Client
+	robots *robotsCache
+}
+
+// New creates a fetch tool.
+func New(cfg Config) (tool.Tool, error) {
+	if cfg.Name == "" {
+		cfg.Name = defaultName
+	}
+	if cfg.Description == "" {
+		cfg.Description = defaultDescription
+	}
+	if cfg.UserAgent == "" {
+		cfg.UserAgent = defaultUserAgent
+	}
+	if cfg.MaxBytes <= 0 {
+		cfg.MaxBytes = defaultMaxBytes
+	}
+	if cfg.MaxLength <= 0 {
+		cfg.MaxLength = defaultMaxLength
+	}
+	switch cfg.Format {
+	case "":
+		cfg.Format = FormatMarkdown
+	case FormatMarkdown, FormatText:
+	default:
+		return nil, fmt.Errorf("unknown format %q", cfg.Format)
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = defaultTimeout
+	}
+
+	f := &fetcher{cfg: cfg, robots: newRobotsCache()}
+	client := http.DefaultClient
+	if cfg.Client != nil {
+		client = cfg.Client
+	}
+	// The robots.txt is fetched with the client as configured, the pages
+	// with a copy checking the redirects.
+	f.robots.client = client
+	c := *client
+	c.CheckRedirect = f.checkRedirect
+	f.client = &c
+
+	return functiontool.New(functiontool.Config{
+		Name:        cfg.Name,
+		Description: cfg.Description,
+	}, f.fetch)
+}
+
+func (f *fetcher) fetch(ctx tool.Context, args Args) (Result, error) {
+	if args.Offset < 0 {
+		return Result{}, errors.New("offset must not be negative")
+	}
+	u, err := url.Parse(args.URL)
+	if err != nil {
+		return Result{}, fmt.Errorf("invalid URL %q: %w", args.URL, err)
+	}
+	reqCtx, cancel := context.WithTimeout(ctx, f.cfg.Timeout)
+	defer cancel()
+	if err := f.check(reqCtx, u); err != nil {
+		return Result{}, err
+	}
+
+	req, err := http.NewRequestWithContext(reqCtx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return Result{}, fmt.Errorf("invalid URL %q: %w", args.URL, err)
+	}
+	req.Header.Set("User-Agent", f.cfg.UserAgent)
+	req.Header.Set("Accept", "text/html,application/xhtml+xml,text/plain;q=0.9,*/*;q=0.8")
+	resp, err := f.client.Do(req)
+	if err != nil {
+		var toolErr *tool.ToolError
+		if errors.As(err, &toolErr) {
+			return Result{}, toolErr
+		}
+		if errors.Is(err, context.DeadlineExceeded) {
+			return Result{}, fmt.Errorf("fetching %s timed out after %v", u, f.cfg.Timeout)
+		}
+		return Result{}, fmt.Errorf("failed to fetch %s: %w", u, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return Result{}, fmt.Errorf("failed to fetch %s: %s", resp.Request.URL, resp.Status)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, f.cfg.MaxBytes+1))
+	if err != nil {
+		return Result{}, fmt.Errorf("failed to read %s: %w", resp.Request.URL, err)
+	}
+	result := Result{URL: resp.Request.URL.String()}
+	if int64(len(body)) > f.cfg.MaxBytes {
+		body = body[:f.cfg.MaxBytes]
+		result.Truncated = true
+	}
+
+	content, err := f.content(resp, body, &result)
+	if err != nil {
+		return Result{}, err
+	}
+	result.ContentLength = len(content)
+	if args.Offset < len(content) {
+		content = content[args.Offset:]
+		if len(content) > f.cfg.MaxLength {
+			n := f.cfg.MaxLength
+			for n > 0 && !utf8.RuneStart(content[n]) {
+				n--
+			}
+			content = content[:n]
+			result.NextOffset = args.Offset + len(content)
+		}
+		result.Content = content
+	}
+	return result, nil
+}
+
+// content returns the content of the response body according to its type,
+// setting the title of the result for HTML pages.
+func (f *fetcher) content(resp *http.Response, body []byte, result *Result) (string, error) {
+	contentType := resp.Header.Get("Content-Type")
+	if contentType == "" {
+		contentType = http.DetectContentType(body)
+	}
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return "", fmt.Errorf("invalid content type %q: %w", contentType, err)
+	}
+	switch {
+	case mediaType == "text/html" || mediaType == "application/xhtml+xml":
+		r, err := charset.NewReader(bytes.NewReader(body), contentType)
+		if err != nil {
+			return "", fmt.Errorf("failed to decode %s: %w", result.URL, err)
+		}
+		doc, err := html.Parse(r)
+		if err != nil {
+			return "", fmt.Errorf("failed to parse %s: %w", result.URL, err)
+		}
+		var content string
+		result.Title, content = extract(doc, resp.Request.URL, f.cfg.Format)
+		return content, nil
+	case strings.HasPrefix(mediaType, "text/") || mediaType == "application/json" || strings.HasSuffix(mediaType, "+json") ||
+		mediaType == "application/xml" || strings.HasSuffix(mediaType, "+xml"):
+		return strings.ToValidUTF8(string(body), "�"), nil
+	default:
+		return "", &tool.ToolError{
+			Code:    "unsupported_content_type",
+			Message: fmt.Sprintf("%s is %s, which cannot be read as text", result.URL, mediaType),
+		}
+	}
+}
+
+// check returns an error if the URL may not be fetched.
+func (f *fetcher) check(ctx context.Context, u *url.URL) error {
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return &tool.ToolError{Code: "invalid_url", Message: fmt.Sprintf("only http and https URLs can be fetched, got %q", u.String())}
+	}
+	if u.Host == "" {
+		return &tool.ToolError{Code: "invalid_url", Message: fmt.Sprintf("URL %q has no host", u.String())}
+	}
+	host := strings.ToLower(u.Hostname())
+	if matchHost(host, f.cfg.DeniedHosts) || (len(f.cfg.AllowedHosts) > 0 && !matchHost(host, f.cfg.AllowedHosts)) {
+		return &tool.ToolError{Code: "host_not_allowed", Message: fmt.Sprintf("fetching from %s is not allowed", host)}
+	}
+	if f.cfg.IgnoreRobotsTxt {
+		return nil
+	}
+	rules, err := f.robots.get(ctx, u, f.cfg.UserAgent)
+	if err != nil {
+		return err
+	}
+	if !rules.allowed(u) {
+		return &tool.ToolError{Code: "disallowed_by_robots_txt", Message: fmt.Sprintf("the robots.txt of %s disallows fetching %s", host, u.String())}
+	}
+	return nil
+}
+
+func (f *fetcher) checkRedirect(req *http.Request, via []*http.Request) error {
+	if len(via) >= maxRedirects {
+		return fmt.Errorf("stopped after %d redirects", maxRedirects)
+	}
+	return f.check(req.Context(), req.URL)
+}
+
+// matchHost reports whether the host is one of the hosts or a subdomain of
+// one of them.
+func matchHost(host string, hosts []string) bool {
+	for _, h := range hosts {
+		h = strings.ToLower(h)
+		if host == h || strings.HasSuffix(host, "."+h) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,271 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fetchtool_test
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"google.golang.org/adk/agent"
+	icontext "google.golang.org/adk/internal/context"
+	"google.golang.org/adk/internal/sessioninternal"
+	"google.golang.org/adk/internal/toolinternal"
+	"google.golang.org/adk/session"
+	"google.golang.org/adk/tool"
+	"google.golang.org/adk/tool/fetchtool"
+)
+
+const articlePage = `<html><head><title>The Title</title><style>p { color: red }</style></head>
+<body>
+<header><a href="/">Home</a></header>
+<nav><ul><li><a href="/a">Menu</a></li></ul></nav>
+<main>
+<h1>Heading</h1>
+<p>Some <strong>bold</strong> and <em>emphasized</em> text with a <a href="/docs?page=2">relative link</a>.</p>
+<ul><li>first</li><li>second<ol><li>nested</li></ol></li></ul>
+<pre>func main() {
+	fmt.Println("hi")
+}</pre>
+<p>Call <code>New</code> first.</p>
+<table><tr><th>Name</th><th>Value</th></tr><tr><td>a</td><td>1</td></tr></table>
+<blockquote><p>Quoted.</p></blockquote>
+<p hidden>Hidden.</p>
+<script>alert("x")</script>
+</main>
+<footer>Copyright</footer>
+</body></html>`
+
+func newServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	mux := http.NewServeMux()
+	mux.HandleFunc("/robots.txt", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "User-agent: *\nDisallow: /private\nAllow: /private/open$\n\nUser-agent: other-bot\nDisallow: /\n")
+	})
+	mux.HandleFunc("/article", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, articlePage)
+	})
+	mux.HandleFunc("/plain", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		fmt.Fprint(w, "just text")
+	})
+	mux.HandleFunc("/long", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain")
+		fmt.Fprint(w, strings.Repeat("é", 150))
+	})
+	mux.HandleFunc("/private/", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain")
+		fmt.Fprint(w, "private")
+	})
+	mux.HandleFunc("/image", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "image/png")
+		w.Write([]byte{0x89, 'P', 'N', 'G'})
+	})
+	mux.HandleFunc("/redirect", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, r.URL.Query().Get("to"), http.StatusFound)
+	})
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func run(t *testing.T, cfg fetchtool.Config, args map[string]any) (map[string]any, error) {
+	t.Helper()
+	fetch, err := fetchtool.New(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	a, err := agent.New(agent.Config{Name: "agent"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	service := session.InMemoryService()
+	resp, err := service.Create(t.Context(), &session.CreateRequest{AppName: "app", UserID: "user"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := toolinternal.NewToolContext(icontext.NewInvocationContext(t.Context(), icontext.InvocationContextParams{
+		Agent:   a,
+		Session: sessioninternal.NewMutableSession(service, resp.Session),
+	}), "", nil)
+	return fetch.(toolinternal.FunctionTool).Run(ctx, args)
+}
+
+func TestFetch_Markdown(t *testing.T) {
+	srv := newServer(t)
+	got, err := run(t, fetchtool.Config{}, map[string]any{"url": srv.URL + "/article"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "# Heading\n\n" +
+		"Some **bold** and _emphasized_ text with a [relative link](" + srv.URL + "/docs?page=2).\n\n" +
+		"- first\n- second\n  1. nested\n\n" +
+		"```\nfunc main() {\n\tfmt.Println(\"hi\")\n}\n```\n\n" +
+		"Call `New` first.\n\n" +
+		"| Name | Value |\n| --- | --- |\n| a | 1 |\n\n" +
+		"> Quoted."
+	if diff := cmp.Diff(map[string]any{
+		"url":            srv.URL + "/article",
+		"title":          "The Title",
+		"content":        want,
+		"content_length": float64(len(want)),
+	}, got); diff != "" {
+		t.Errorf("fetch_url() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestFetch_Text(t *testing.T) {
+	srv := newServer(t)
+	for path, want := range map[string]string{
+		"/article": "Heading\nSome bold and emphasized text with a relative link.\n- first\n- second\n  1. nested\n" +
+			"func main() {\n\tfmt.Println(\"hi\")\n}\nCall New first.\nName | Value\na | 1\nQuoted.",
+		"/plain": "just text",
+	} {
+		got, err := run(t, fetchtool.Config{Format: fetchtool.FormatText}, map[string]any{"url": srv.URL + path})
+		if err != nil {
+			t.Fatalf("fetch_url(%s) error = %v", path, err)
+		}
+		if diff := cmp.Diff(want, got["content"]); diff != "" {
+			t.Errorf("fetch_url(%s) content mismatch (-want +got):\n%s", path, diff)
+		}
+	}
+}
+
+func TestFetch_Offset(t *testing.T) {
+	srv := newServer(t)
+	cfg := fetchtool.Config{MaxLength: 101}
+	var content string
+	offset := 0
+	for calls := 0; ; calls++ {
+		if calls > 3 {
+			t.Fatal("too many calls")
+		}
+		got, err := run(t, cfg, map[string]any{"url": srv.URL + "/long", "offset": offset})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got["content_length"] != float64(300) {
+			t.Errorf("content_length = %v, want 300", got["content_length"])
+		}
+		content += got["content"].(string)
+		next, ok := got["next_offset"].(float64)
+		if !ok {
+			break
+		}
+		offset = int(next)
+	}
+	if want := strings.Repeat("é", 150); content != want {
+		t.Errorf("content = %q, want %q", content, want)
+	}
+}
+
+func TestFetch_MaxBytes(t *testing.T) {
+	srv := newServer(t)
+	got, err := run(t, fetchtool.Config{MaxBytes: 4}, map[string]any{"url": srv.URL + "/plain"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got["content"] != "just" || got["truncated"] != true {
+		t.Errorf("fetch_url() = %v, want the truncated content", got)
+	}
+}
+
+func TestFetch_Denied(t *testing.T) {
+	srv := newServer(t)
+	tests := []struct {
+		name     string
+		cfg      fetchtool.Config
+		url      string
+		wantCode string
+	}{
+		{
+			name:     "scheme",
+			url:      "file:///etc/passwd",
+			wantCode: "invalid_url",
+		},
+		{
+			name:     "denied host",
+			cfg:      fetchtool.Config{DeniedHosts: []string{"127.0.0.1"}},
+			url:      srv.URL + "/plain",
+			wantCode: "host_not_allowed",
+		},
+		{
+			name:     "host not allowed",
+			cfg:      fetchtool.Config{AllowedHosts: []string{"example.com"}},
+			url:      srv.URL + "/plain",
+			wantCode: "host_not_allowed",
+		},
+		{
+			name:     "redirect to a host not allowed",
+			cfg:      fetchtool.Config{AllowedHosts: []string{"127.0.0.1"}},
+			url:      srv.URL + "/redirect?to=http://example.com/",
+			wantCode: "host_not_allowed",
+		},
+		{
+			name:     "robots.txt",
+			url:      srv.URL + "/private/page",
+			wantCode: "disallowed_by_robots_txt",
+		},
+		{
+			name:     "redirect disallowed by robots.txt",
+			url:      srv.URL + "/redirect?to=/private/page",
+			wantCode: "disallowed_by_robots_txt",
+		},
+		{
+			name:     "robots.txt group of the user agent",
+			cfg:      fetchtool.Config{UserAgent: "Other-Bot/2.0"},
+			url:      srv.URL + "/plain",
+			wantCode: "disallowed_by_robots_txt",
+		},
+		{
+			name:     "unsupported content type",
+			url:      srv.URL + "/image",
+			wantCode: "unsupported_content_type",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := run(t, tc.cfg, map[string]any{"url": tc.url})
+			var toolErr *tool.ToolError
+			if !errors.As(err, &toolErr) || toolErr.Code != tc.wantCode {
+				t.Errorf("fetch_url(%s) error = %v, want a tool error with code %q", tc.url, err, tc.wantCode)
+			}
+		})
+	}
+}
+
+func TestFetch_RobotsTxt(t *testing.T) {
+	srv := newServer(t)
+	for _, tc := range []struct {
+		cfg  fetchtool.Config
+		path string
+	}{
+		{path: "/private/open"},
+		{cfg: fetchtool.Config{IgnoreRobotsTxt: true}, path: "/private/page"},
+	} {
+		got, err := run(t, tc.cfg, map[string]any{"url": srv.URL + tc.path})
+		if err != nil {
+			t.Fatalf("fetch_url(%s) error = %v", tc.path, err)
+		}
+		if got["content"] != "private" {
+			t.Errorf("fetch_url(%s) content = %v, want %q", tc.path, got["content"], "private")
+		}
+	}
+}
@@ -0,0 +1,64 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package functiontool
+
+import (
+	"reflect"
+
+	"github.com/google/jsonschema-go/jsonschema"
+)
+
+// errorResultSchema returns the schema of the results reporting the errors of
+// the calls, see tool.ToolError: {"error": "..."}, or
+// {"error": {"code": "...", "message": "...", "details": {...}}}.
+func errorResultSchema() *jsonschema.Schema {
+	return &jsonschema.Schema{
+		Type: "object",
+		Properties: map[string]*jsonschema.Schema{
+			"error": {
+				AnyOf: []*jsonschema.Schema{
+					{Type: "string"},
+					{
+						Type: "object",
+						Properties: map[string]*jsonschema.Schema{
+							"code":    {Type: "string"},
+							"message": {Type: "string"},
+							"details": {Type: "object"},
+						},
+						Required: []string{"message"},
+					},
+				},
+			},
+		},
+		Required: []string{"error"},
+	}
+}
+
+// withErrorResults returns the response schema declared with
+// Config.DeclareErrorResults: either a result, of the output schema, or an
+// error, of errorResultSchema.
+// The $defs of the output schema are moved to the root, where its references
+// point. An output schema accepting any value is returned as is.
+func withErrorResults(output *jsonschema.Schema) *jsonschema.Schema {
+	if reflect.DeepEqual(output, &jsonschema.Schema{}) {
+		return output
+	}
+	result := *output
+	result.Defs = nil
+	return &jsonschema.Schema{
+		AnyOf: []*jsonschema.Schema{&result, errorResultSchema()},
+		Defs:  output.Defs,
+	}
+}
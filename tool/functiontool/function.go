@@ -16,18 +16,23 @@
 package functiontool
 
 import (
+	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"reflect"
 	"runtime/debug"
+	"time"
 
 	"github.com/google/jsonschema-go/jsonschema"
 	"google.golang.org/genai"
 
+	"google.golang.org/adk/internal/toolinternal"
 	"google.golang.org/adk/internal/toolinternal/toolutils"
 	"google.golang.org/adk/internal/typeutil"
 	"google.golang.org/adk/model"
 	"google.golang.org/adk/tool"
+	"google.golang.org/adk/tool/toolconfirmation"
 )
 
 // FunctionTool: borrow implementation from MCP go.
@@ -40,27 +45,138 @@ type Config struct {
 	Description string
 	// An optional JSON schema object defining the expected parameters for the tool.
 	// If it is nil, FunctionTool tries to infer the schema based on the handler type.
+	// The `jsonschema` struct tags of the fields describe their properties:
+	// either a plain description, or comma-separated settings among
+	// description=..., enum=A|B|C, required=true|false,
+	// nullable=true|false, default=..., the bounds minimum=, maximum=,
+	// minLength=, maxLength=, minItems= and maxItems=, pattern=... and
+	// format=..., e.g.
+	// `jsonschema:"description=The city name,enum=NYC|LA|SF"` or
+	// `jsonschema:"description=Number of days,minimum=1,maximum=14"`.
+	// The inferred schema requires the fields which are not pointers and
+	// whose json tag has no omitempty or omitzero, unless their tag sets
+	// required.
+	// If it is set, its properties must match the fields of the argument
+	// type, in name and type, down to the nested structs and the items of the
+	// slices, or the tool creation fails.
 	InputSchema *jsonschema.Schema
 	// An optional JSON schema object defining the structure of the tool's output.
 	// If it is nil, FunctionTool tries to infer the schema based on the handler type.
+	// The tools returning tool.Content have no output schema.
 	OutputSchema *jsonschema.Schema
+	// InputSchemaAugmenter optionally modifies the input schema, inferred or
+	// set in InputSchema, e.g. to add a description or constrain the range of
+	// a field without writing the whole schema.
+	InputSchemaAugmenter SchemaAugmenter
+	// OutputSchemaAugmenter optionally modifies the output schema, as
+	// InputSchemaAugmenter does for the input schema.
+	OutputSchemaAugmenter SchemaAugmenter
+	// OnUnknownField is how the unknown fields of the arguments, which are not
+	// fields of the argument type, are handled, whether or not the input is
+	// validated. Defaults to IgnoreUnknown.
+	OnUnknownField UnknownFieldMode
+	// SkipInputValidation skips the validation of the arguments against the
+	// input schema. The arguments are only converted to the argument type,
+	// missing fields getting their zero values. By default, the calls with
+	// invalid arguments fail with a *ValidationError, returned to the model
+	// so that it retries.
+	SkipInputValidation bool
+	// Timeout optionally limits the duration of the calls. The handler gets a
+	// context canceled once the timeout elapsed, and the call then fails with
+	// an error wrapping context.DeadlineExceeded, even if the handler ignores
+	// the cancellation and keeps running. Zero means no timeout.
+	Timeout time.Duration
 	// IsLongRunning makes a FunctionTool a long-running operation.
 	IsLongRunning bool
+	// Sequential makes the calls of the tool run one at a time, after the
+	// other calls of the model response, when the agent runs the calls
+	// concurrently, e.g. if the handler is not safe for concurrent use. See
+	// llmagent.Config.ParallelToolCalls.
+	Sequential bool
+	// IsEnabled optionally decides, for each request to the model, whether
+	// the tool is offered to the model, e.g. based on a feature flag or the
+	// tier of the user in the session state. A disabled tool is not declared
+	// to the model, and its calls fail with ErrNotAvailable.
+	IsEnabled func(ctx tool.Context) bool
+	// RequireConfirmation makes the calls wait for the confirmation of the
+	// user before running the handler, see package toolconfirmation. The
+	// declined calls fail with toolconfirmation.ErrDeclined.
+	RequireConfirmation bool
+	// ConfirmationPrompt optionally returns the hint shown to the user to
+	// confirm a call with the given arguments, when RequireConfirmation is
+	// set. Defaults to a hint naming the tool.
+	ConfirmationPrompt func(args map[string]any) string
+	// ResultEncoding controls how the result is presented to the model.
+	// Defaults to JSONEncoding.
+	ResultEncoding ResultEncoding
+	// ResultKey is the key of the results which are not JSON objects, e.g.
+	// a string, a number or a slice, in the map returned to the model:
+	// {ResultKey: result}. The output schema is the one of that map. The
+	// fields of the struct and map results are returned as they are.
+	// Defaults to "result".
+	ResultKey string
+	// MaxResultBytes optionally limits the size of the results returned to
+	// the model: the number of bytes of the JSON encoding of the result map.
+	// A larger result is truncated with TruncateStrategy instead of filling
+	// the context window of the model. Zero means no limit.
+	MaxResultBytes int
+	// TruncateStrategy is how the results larger than MaxResultBytes are
+	// truncated. Defaults to TruncateHead.
+	TruncateStrategy TruncateStrategy
+	// MaxTableColumns is the maximum number of columns of a Markdown table
+	// rendered with MarkdownTableEncoding. Results with more columns are
+	// returned as JSON only. Defaults to 12.
+	MaxTableColumns int
+	// DeclareErrorResults declares to the model, in the response schema of
+	// the tool, that the calls return either a result, of the output schema,
+	// or an error result, see tool.ToolError, so that the model expects the
+	// failures of the handler.
+	DeclareErrorResults bool
+	// BeforeRun is optionally called before each call of the handler, e.g.
+	// for a guardrail or a mock, see BeforeRunFunc.
+	BeforeRun BeforeRunFunc
+	// AfterRun optionally rewrites the result or the error of each call of
+	// the handler, e.g. to redact a field, see AfterRunFunc.
+	AfterRun AfterRunFunc
 }
 
+// SchemaAugmenter receives a schema and returns the schema to use instead. It
+// may modify and return the given schema. The returned schema is resolved,
+// and the tool creation fails if it is invalid.
+type SchemaAugmenter func(*jsonschema.Schema) (*jsonschema.Schema, error)
+
 // Func represents a Go function that can be wrapped in a tool.
 // It takes a tool.Context and a generic argument type, and returns a generic result type.
+//
+// A non-nil error is returned as is by the Run method of the tool, and the
+// result is then discarded. The error is reported to the model as a
+// structured result, see tool.ToolError, so the handlers need not encode
+// their failures in TResults. The output schema is inferred from TResults
+// only; Config.DeclareErrorResults adds the error results to the response
+// schema declared to the model.
 type Func[TArgs, TResults any] func(tool.Context, TArgs) (TResults, error)
 
+// defaultResultKey is the default key of the wrapped results, see
+// Config.ResultKey.
+const defaultResultKey = "result"
+
 // ErrInvalidArgument indicates the input parameter type is invalid.
 var ErrInvalidArgument = errors.New("invalid argument")
 
+// ErrNotAvailable is returned by the calls of a tool disabled by
+// Config.IsEnabled.
+var ErrNotAvailable = errors.New("tool not available")
+
 // New creates a new tool with a name, description, and the provided handler.
 // Input schema is automatically inferred from the input and output types.
+//
+// The arguments sent by the model are decoded into TArgs as follows, at any
+// depth: an omitted field gets its zero value, e.g. a nil pointer, slice or
+// map; a null field is nil if it is a pointer, and is otherwise rejected by
+// the validation, unless the schema allows null for it, e.g. with the
+// nullable=true setting of its `jsonschema` tag, in which case it gets its
+// zero value.
 func New[TArgs, TResults any](cfg Config, handler Func[TArgs, TResults]) (tool.Tool, error) {
-	// TODO: How can we improve UX for functions that does not require an argument, returns a simple type value, or returns a no result?
-	//  https://github.com/modelcontextprotocol/go-sdk/discussions/37
-
 	var zeroArgs TArgs
 	argsType := reflect.TypeOf(zeroArgs)
 	for argsType != nil && argsType.Kind() == reflect.Ptr {
@@ -70,29 +186,108 @@ func New[TArgs, TResults any](cfg Config, handler Func[TArgs, TResults]) (tool.T
 		return nil, fmt.Errorf("input must be a struct or a map or a pointer to those types, but received: %v: %w", argsType, ErrInvalidArgument)
 	}
 
-	ischema, err := resolvedSchema[TArgs](cfg.InputSchema)
+	ischema, err := typeutil.ResolvedSchema[TArgs](cfg.InputSchema, cfg.InputSchemaAugmenter)
 	if err != nil {
 		return nil, fmt.Errorf("failed to infer input schema: %w", err)
 	}
-	oschema, err := resolvedSchema[TResults](cfg.OutputSchema)
+	knownFields, captureField, err := unknownFieldHandling(cfg.OnUnknownField, argsType, ischema)
 	if err != nil {
-		return nil, fmt.Errorf("failed to infer output schema: %w", err)
+		return nil, err
+	}
+	oschema, err := resolveOutputSchema[TResults](cfg)
+	if err != nil {
+		return nil, err
 	}
 
 	return &functionTool[TArgs, TResults]{
+		cfg:             cfg,
+		inputSchema:     ischema,
+		propertySchemas: resolvePropertySchemas(ischema),
+		knownFields:     knownFields,
+		captureField:    captureField,
+		outputSchema:    oschema,
+		handler:         handler,
+	}, nil
+}
+
+// NoArgsFunc represents a Go function without arguments that can be wrapped
+// in a tool with NewNoArgs.
+type NoArgsFunc[TResults any] func(tool.Context) (TResults, error)
+
+// NewNoArgs creates a new tool without parameters, with a name, description,
+// and the provided handler. Its declaration has no parameters schema, which
+// the models accept as a function without parameters, and the arguments sent
+// by the model, usually none or an empty object, are ignored. cfg.InputSchema
+// and cfg.InputSchemaAugmenter must not be set. The output schema is inferred
+// as with New.
+func NewNoArgs[TResults any](cfg Config, handler NoArgsFunc[TResults]) (tool.Tool, error) {
+	if cfg.InputSchema != nil || cfg.InputSchemaAugmenter != nil {
+		return nil, fmt.Errorf("a tool without arguments has no input schema: %w", ErrInvalidArgument)
+	}
+	oschema, err := resolveOutputSchema[TResults](cfg)
+	if err != nil {
+		return nil, err
+	}
+	return &functionTool[struct{}, TResults]{
 		cfg:          cfg,
-		inputSchema:  ischema,
 		outputSchema: oschema,
-		handler:      handler,
+		handler: func(ctx tool.Context, _ struct{}) (TResults, error) {
+			return handler(ctx)
+		},
+		noArgs: true,
 	}, nil
 }
 
+// ActionFunc represents a Go function without result, e.g. sending a message
+// or saving a record, that can be wrapped in a tool with NewAction.
+type ActionFunc[TArgs any] func(tool.Context, TArgs) error
+
+// actionDone is the result of the successful calls of the actions.
+const actionDone = "done"
+
+// NewAction creates a new tool whose handler returns no result, only an
+// error, with a name, description, and the provided handler. The arguments
+// are handled as with New, and a successful call is reported to the model as
+// {ResultKey: "done"}, see Config.ResultKey, rather than as an empty
+// response the model could read as a failure.
+func NewAction[TArgs any](cfg Config, handler ActionFunc[TArgs]) (tool.Tool, error) {
+	return New(cfg, func(ctx tool.Context, args TArgs) (string, error) {
+		if err := handler(ctx, args); err != nil {
+			return "", err
+		}
+		return actionDone, nil
+	})
+}
+
+// NewNoArgsAction creates a new tool without parameters whose handler
+// returns no result, as NewNoArgs does for the handlers with results. A
+// successful call is reported to the model as with NewAction.
+func NewNoArgsAction(cfg Config, handler func(tool.Context) error) (tool.Tool, error) {
+	return NewNoArgs(cfg, func(ctx tool.Context) (string, error) {
+		if err := handler(ctx); err != nil {
+			return "", err
+		}
+		return actionDone, nil
+	})
+}
+
 // functionTool wraps a Go function.
 type functionTool[TArgs, TResults any] struct {
 	cfg Config
+	// noArgs is set for the tools without arguments, see NewNoArgs.
+	noArgs bool
 
 	// A JSON Schema object defining the expected parameters for the tool.
 	inputSchema *jsonschema.Resolved
+	// propertySchemas are the resolved schemas of the parameters, used to
+	// report the validation errors per field.
+	propertySchemas map[string]*propertySchema
+	// knownFields are the top-level properties of the input schema, nil if
+	// the arguments have no unknown fields, see Config.OnUnknownField.
+	knownFields map[string]bool
+	// captureField is the index of the field of TArgs capturing the unknown
+	// fields, nil if they are not captured, see CaptureUnknown.
+	captureField []int
 	// A JSON Schema object defining the result of the tool.
 	outputSchema *jsonschema.Resolved
 
@@ -115,11 +310,26 @@ func (f *functionTool[TArgs, TResults]) IsLongRunning() bool {
 	return f.cfg.IsLongRunning
 }
 
-// ProcessRequest packs the function tool's declaration into the LLM request.
+// IsSequential reports whether the calls of the tool must not run
+// concurrently with the other calls.
+func (f *functionTool[TArgs, TResults]) IsSequential() bool {
+	return f.cfg.Sequential
+}
+
+// ProcessRequest packs the function tool's declaration into the LLM request,
+// unless the tool is disabled for the request.
 func (f *functionTool[TArgs, TResults]) ProcessRequest(ctx tool.Context, req *model.LLMRequest) error {
+	if !f.enabled(ctx) {
+		return nil
+	}
 	return toolutils.PackTool(req, f)
 }
 
+// enabled reports whether the tool is enabled, see Config.IsEnabled.
+func (f *functionTool[TArgs, TResults]) enabled(ctx tool.Context) bool {
+	return f.cfg.IsEnabled == nil || f.cfg.IsEnabled(ctx)
+}
+
 // FunctionDeclaration implements interfaces.FunctionTool.
 func (f *functionTool[TArgs, TResults]) Declaration() *genai.FunctionDeclaration {
 	decl := &genai.FunctionDeclaration{
@@ -131,6 +341,9 @@ func (f *functionTool[TArgs, TResults]) Declaration() *genai.FunctionDeclaration
 	}
 	if f.outputSchema != nil {
 		decl.ResponseJsonSchema = f.outputSchema.Schema()
+		if f.cfg.DeclareErrorResults {
+			decl.ResponseJsonSchema = withErrorResults(f.outputSchema.Schema())
+		}
 	}
 
 	if f.cfg.IsLongRunning {
@@ -145,43 +358,303 @@ func (f *functionTool[TArgs, TResults]) Declaration() *genai.FunctionDeclaration
 	return decl
 }
 
+// MarshalJSON returns the JSON description of the tool, its function
+// declaration, e.g. to document a toolset.
+func (f *functionTool[TArgs, TResults]) MarshalJSON() ([]byte, error) {
+	return json.Marshal(f.Declaration())
+}
+
+// InputSchema returns the resolved input schema of the tool, see the
+// package-level InputSchema.
+func (f *functionTool[TArgs, TResults]) InputSchema() *jsonschema.Resolved {
+	return f.inputSchema
+}
+
+// InputSchema returns the resolved input schema of a tool created by this
+// package, e.g. to render a form for its arguments, and whether t is such a
+// tool. The schema is nil for the tools without arguments. It must not be
+// modified.
+func InputSchema(t tool.Tool) (*jsonschema.Resolved, bool) {
+	st, ok := t.(interface{ InputSchema() *jsonschema.Resolved })
+	if !ok {
+		return nil, false
+	}
+	return st.InputSchema(), true
+}
+
 // Run executes the tool with the provided context and yields events.
-func (f *functionTool[TArgs, TResults]) Run(ctx tool.Context, args any) (result map[string]any, err error) {
-	// TODO: Handle function call request from tc.InvocationContext.
+func (f *functionTool[TArgs, TResults]) Run(ctx tool.Context, args any) (map[string]any, error) {
+	return toolinternal.TraceRun(ctx, f.Name(), args, func(ctx tool.Context) (map[string]any, error) {
+		return f.run(ctx, args)
+	})
+}
+
+func (f *functionTool[TArgs, TResults]) run(ctx tool.Context, args any) (result map[string]any, err error) {
 	defer func() {
 		if r := recover(); r != nil {
-			err = fmt.Errorf("panic in tool %q: %v\nstack: %s", f.Name(), r, debug.Stack())
+			err = panicError(f.Name(), r)
 		}
 	}()
 
-	m, ok := args.(map[string]any)
-	if !ok {
-		return nil, fmt.Errorf("unexpected args type, got: %T", args)
+	if !f.enabled(ctx) {
+		return nil, fmt.Errorf("tool %q: %w", f.Name(), ErrNotAvailable)
 	}
-	input, err := typeutil.ConvertToWithJSONSchema[map[string]any, TArgs](m, f.inputSchema)
+	if f.cfg.BeforeRun != nil {
+		m, _ := args.(map[string]any)
+		override, skip, err := f.cfg.BeforeRun(ctx, m)
+		if err != nil {
+			return nil, err
+		}
+		if skip {
+			return f.hookResult(override)
+		}
+	}
+	result, isContent, err := f.call(ctx, args)
+	if f.cfg.AfterRun != nil {
+		var rewritten any
+		if result != nil {
+			rewritten = result
+		}
+		if rewritten, err = f.cfg.AfterRun(ctx, rewritten, err); err != nil {
+			return nil, err
+		}
+		return f.hookResult(rewritten)
+	}
+	if err != nil || isContent {
+		return result, err
+	}
+	return f.truncateResult(result)
+}
+
+// call validates and converts the arguments, runs the handler and converts
+// its output to the result returned to the model, before its truncation. It
+// reports whether the result is the one of a tool.Content, which is not
+// truncated.
+func (f *functionTool[TArgs, TResults]) call(ctx tool.Context, args any) (map[string]any, bool, error) {
+	var input TArgs
+	if !f.noArgs {
+		m, ok := args.(map[string]any)
+		if !ok {
+			return nil, false, fmt.Errorf("unexpected args type, got: %T", args)
+		}
+		m, unknown, err := f.splitUnknownFields(m)
+		if err != nil {
+			return nil, false, err
+		}
+		if f.inputSchema != nil && !f.cfg.SkipInputValidation {
+			// Validate the JSON form of the arguments.
+			jsonArgs, err := typeutil.ConvertToWithJSONSchema[map[string]any, map[string]any](m, nil)
+			if err != nil {
+				return nil, false, f.conversionError(err)
+			}
+			if err := f.inputSchema.Validate(jsonArgs); err != nil {
+				return nil, false, f.validationError(jsonArgs, err)
+			}
+		}
+		input, err = typeutil.ConvertToWithJSONSchema[map[string]any, TArgs](m, nil)
+		if err != nil {
+			return nil, false, f.conversionError(err)
+		}
+		f.captureUnknownFields(&input, unknown)
+	}
+	if f.cfg.RequireConfirmation {
+		if result, err := f.confirm(ctx, args); result != nil || err != nil {
+			return result, false, err
+		}
+	}
+	output, err := f.callHandler(ctx, input)
 	if err != nil {
-		return nil, err
+		return nil, false, err
 	}
-	output, err := f.handler(ctx, input)
+	if content, ok := asContent(output); ok {
+		result, err := toolinternal.ContentResult(content)
+		return result, true, err
+	}
+	resp, err := resultMap(output, f.outputSchema, resultKey(f.cfg))
 	if err != nil {
-		return nil, err
+		return nil, false, err
 	}
-	resp, err := typeutil.ConvertToWithJSONSchema[TResults, map[string]any](output, f.outputSchema)
-	if err == nil { // all good
-		return resp, nil
+	return f.encodeResult(output, resp), false, nil
+}
+
+// confirm checks the confirmation of the call by the user. It requests the
+// confirmation and returns the result telling the model that the call waits
+// for it if there is none yet, fails if the call was declined, and returns
+// nil if the call was confirmed.
+func (f *functionTool[TArgs, TResults]) confirm(ctx tool.Context, args any) (map[string]any, error) {
+	if ctx == nil {
+		return nil, fmt.Errorf("tool %q requires a confirmation, which needs a tool context", f.Name())
+	}
+	confirmation := ctx.ToolConfirmation()
+	if confirmation == nil {
+		m, _ := args.(map[string]any)
+		hint := fmt.Sprintf("Please approve or reject the call of the tool %q.", f.Name())
+		if f.cfg.ConfirmationPrompt != nil {
+			hint = f.cfg.ConfirmationPrompt(m)
+		}
+		ctx.RequestConfirmation(hint, nil)
+		return map[string]any{"status": "This tool call requires the confirmation of the user, waiting for it."}, nil
 	}
+	if !confirmation.Confirmed {
+		return nil, fmt.Errorf("tool %q: %w", f.Name(), toolconfirmation.ErrDeclined)
+	}
+	return nil, nil
+}
 
-	// Specs requires the result to be a map (dict in python). python impl allows basic types when building response event
-	// functions.py __build_response_event does the following
-	// if not isinstance(function_result, dict):
-	// 		function_result = {'result': function_result}
-	if f.outputSchema != nil {
-		if err1 := f.outputSchema.Validate(output); err1 != nil {
-			return resp, err // if it fails propagate original err.
+// callHandler calls the handler, within the timeout of the tool if it has
+// one.
+func (f *functionTool[TArgs, TResults]) callHandler(ctx tool.Context, input TArgs) (TResults, error) {
+	if f.cfg.Timeout <= 0 {
+		return f.handler(ctx, input)
+	}
+	parent := context.Context(context.Background())
+	if ctx != nil {
+		parent = ctx
+	}
+	timeoutCtx, cancel := context.WithTimeout(parent, f.cfg.Timeout)
+	defer cancel()
+
+	type result struct {
+		output TResults
+		err    error
+	}
+	// Buffered, so that a handler ignoring the cancellation does not leak a
+	// blocked goroutine once it returns.
+	done := make(chan result, 1)
+	go func() {
+		var res result
+		defer func() {
+			if r := recover(); r != nil {
+				res.err = panicError(f.Name(), r)
+			}
+			done <- res
+		}()
+		res.output, res.err = f.handler(toolinternal.WithContext(ctx, timeoutCtx), input)
+	}()
+
+	var res result
+	select {
+	case res = <-done:
+	case <-timeoutCtx.Done():
+		res.err = timeoutCtx.Err()
+	}
+	if res.err != nil && errors.Is(timeoutCtx.Err(), context.DeadlineExceeded) && parent.Err() == nil {
+		return res.output, fmt.Errorf("tool %q timed out after %v: %w", f.Name(), f.cfg.Timeout, context.DeadlineExceeded)
+	}
+	return res.output, res.err
+}
+
+// panicError returns the error of a panic in the tool. A panic with an error
+// value keeps it in the chain of the returned error.
+func panicError(name string, r any) error {
+	return &tool.PanicError{Tool: name, Value: r, Stack: debug.Stack()}
+}
+
+// resolveOutputSchema returns the output schema of the tool, inferred from
+// TResults or set in cfg, wrapped as the results if they are wrapped, see
+// Config.ResultKey. The tools returning tool.Content have none.
+func resolveOutputSchema[TResults any](cfg Config) (*jsonschema.Resolved, error) {
+	if t := reflect.TypeFor[TResults](); t == reflect.TypeFor[tool.Content]() || t == reflect.TypeFor[*tool.Content]() {
+		return nil, nil
+	}
+	oschema, err := typeutil.ResolvedSchema[TResults](cfg.OutputSchema, cfg.OutputSchemaAugmenter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to infer output schema: %w", err)
+	}
+	if !wrapsResult[TResults]() {
+		return oschema, nil
+	}
+	key := resultKey(cfg)
+	wrapped := &jsonschema.Schema{
+		Type:       "object",
+		Properties: map[string]*jsonschema.Schema{key: oschema.Schema().CloneSchemas()},
+		Required:   []string{key},
+	}
+	oschema, err = wrapped.Resolve(nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to wrap output schema: %w", err)
+	}
+	return oschema, nil
+}
+
+// resultKey returns the key of the wrapped results, see Config.ResultKey.
+func resultKey(cfg Config) string {
+	if cfg.ResultKey == "" {
+		return defaultResultKey
+	}
+	return cfg.ResultKey
+}
+
+// wrapsResult reports whether the results of type TResults are wrapped in
+// a map, see Config.ResultKey: the types other than structs, maps and
+// interfaces, which are wrapped only if their value is not a JSON object.
+func wrapsResult[TResults any]() bool {
+	t := reflect.TypeFor[TResults]()
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.Struct, reflect.Map, reflect.Interface:
+		return false
+	}
+	return true
+}
+
+// resultMap converts the result to the map of a function response, checked
+// against the output schema if any. The results which are not JSON objects
+// are wrapped as {key: result}, as adk-python
+// src/google/adk/flows/llm_flows/functions.py __build_response_event does:
+// the specs require the result to be a map.
+func resultMap[TResults any](output TResults, oschema *jsonschema.Resolved, key string) (map[string]any, error) {
+	if !wrapsResult[TResults]() {
+		resp, err := typeutil.ConvertToWithJSONSchema[TResults, map[string]any](output, oschema)
+		if err == nil || reflect.TypeFor[TResults]().Kind() != reflect.Interface {
+			return resp, err
 		}
+		// The value of the interface is not a JSON object, it is wrapped.
+		oschema = nil
 	}
-	wrappedOutput := map[string]any{"result": output}
-	return wrappedOutput, nil
+	wrapped := map[string]any{key: output}
+	if oschema != nil {
+		// Validate the JSON form of the result, struct validation does not
+		// account for json tags (e.g. for a slice of structs).
+		jsonOutput, err := typeutil.ConvertToWithJSONSchema[map[string]any, map[string]any](wrapped, nil)
+		if err != nil {
+			return nil, err
+		}
+		if err := oschema.Validate(jsonOutput); err != nil {
+			return nil, err
+		}
+	}
+	return wrapped, nil
+}
+
+// asContent returns the output as a tool.Content if it is one.
+func asContent(output any) (*tool.Content, bool) {
+	switch c := output.(type) {
+	case tool.Content:
+		return &c, true
+	case *tool.Content:
+		return c, true
+	}
+	return nil, false
+}
+
+// encodeResult applies the configured ResultEncoding to the function response.
+func (f *functionTool[TArgs, TResults]) encodeResult(output TResults, resp map[string]any) map[string]any {
+	if f.cfg.ResultEncoding != MarkdownTableEncoding {
+		return resp
+	}
+	maxColumns := f.cfg.MaxTableColumns
+	if maxColumns <= 0 {
+		maxColumns = defaultMaxTableColumns
+	}
+	table, ok := markdownTable(output, maxColumns)
+	if !ok {
+		return resp
+	}
+	resp[markdownTableKey] = table
+	return resp
 }
 
 // ** NOTE FOR REVIEWERS **
@@ -201,15 +674,3 @@ func (f *functionTool[TArgs, TResults]) Run(ctx tool.Context, args any) (result
 // References
 //  [1] MCP SDK https://pkg.go.dev/github.com/modelcontextprotocol/go-sdk@v0.0.0-20250625213837-ff0d746521c4/mcp#ToolHandler
 //  [2] ADK Python https://github.com/google/adk-python/blob/04de3e197d7a57935488eb7bfa647c7ab62cd9d9/src/google/adk/tools/function_tool.py#L110-L112
-
-func resolvedSchema[T any](override *jsonschema.Schema) (*jsonschema.Resolved, error) {
-	// TODO: check if override schema is compatible with T.
-	if override != nil {
-		return override.Resolve(nil)
-	}
-	schema, err := jsonschema.For[T](nil)
-	if err != nil {
-		return nil, err
-	}
-	return schema.Resolve(nil)
-}
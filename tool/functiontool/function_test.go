@@ -15,27 +15,34 @@
 package functiontool_test
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"iter"
 	"net/http"
 	"path/filepath"
+	"slices"
 	"strings"
 	"testing"
+	"time"
+	"unicode/utf8"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/jsonschema-go/jsonschema"
 	"google.golang.org/genai"
 
+	icontext "google.golang.org/adk/internal/context"
 	"google.golang.org/adk/internal/httprr"
 	"google.golang.org/adk/internal/testutil"
 	"google.golang.org/adk/internal/toolinternal"
 	"google.golang.org/adk/internal/typeutil"
 	"google.golang.org/adk/model"
 	"google.golang.org/adk/model/gemini"
+	"google.golang.org/adk/session"
 	"google.golang.org/adk/tool"
 	"google.golang.org/adk/tool/functiontool"
+	"google.golang.org/adk/tool/geminitool"
 )
 
 func ExampleNew() {
@@ -537,6 +544,68 @@ func TestFunctionTool_CustomSchema(t *testing.T) {
 	})
 }
 
+func TestFunctionTool_SchemaAugmenter(t *testing.T) {
+	type Args struct {
+		City string `json:"city"`
+		Days int    `json:"days"`
+	}
+	forecastTool, err := functiontool.New(functiontool.Config{
+		Name:        "forecast",
+		Description: "returns the weather forecast.",
+		InputSchemaAugmenter: func(s *jsonschema.Schema) (*jsonschema.Schema, error) {
+			s.Properties["city"].Description = "the name of the city"
+			s.Properties["days"].Minimum = jsonschema.Ptr(1.0)
+			s.Properties["days"].Maximum = jsonschema.Ptr(7.0)
+			return s, nil
+		},
+	}, func(ctx tool.Context, input Args) (map[string]any, error) {
+		return map[string]any{"forecast": "sunny"}, nil
+	})
+	if err != nil {
+		t.Fatalf("functiontool.New() failed: %v", err)
+	}
+
+	var req model.LLMRequest
+	if err := forecastTool.(toolinternal.RequestProcessor).ProcessRequest(nil, &req); err != nil {
+		t.Fatalf("ProcessRequest() failed: %v", err)
+	}
+	schema, ok := toolDeclaration(req.Config).ParametersJsonSchema.(*jsonschema.Schema)
+	if !ok {
+		t.Fatalf("ParametersJsonSchema = %T, want *jsonschema.Schema", toolDeclaration(req.Config).ParametersJsonSchema)
+	}
+	// The augmented fields are set, the inferred ones are kept.
+	if got := schema.Properties["city"]; got.Description != "the name of the city" || got.Type != "string" {
+		t.Errorf("city schema = %+v, want an augmented string", got)
+	}
+	if got := schema.Properties["days"]; got.Type != "integer" || got.Maximum == nil || *got.Maximum != 7 {
+		t.Errorf("days schema = %+v, want an integer with a maximum of 7", got)
+	}
+
+	// The augmented schema is used for validation.
+	funcTool := forecastTool.(toolinternal.FunctionTool)
+	if _, err := funcTool.Run(nil, map[string]any{"city": "Paris", "days": 3}); err != nil {
+		t.Errorf("Run(days=3) failed: %v", err)
+	}
+	if _, err := funcTool.Run(nil, map[string]any{"city": "Paris", "days": 10}); err == nil {
+		t.Error("Run(days=10) succeeded, want a validation error")
+	}
+
+	// An invalid augmented schema fails the tool creation.
+	for name, augment := range map[string]functiontool.SchemaAugmenter{
+		"error":   func(*jsonschema.Schema) (*jsonschema.Schema, error) { return nil, errors.New("boom") },
+		"nil":     func(*jsonschema.Schema) (*jsonschema.Schema, error) { return nil, nil },
+		"invalid": func(s *jsonschema.Schema) (*jsonschema.Schema, error) { s.Pattern = "("; return s, nil },
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := functiontool.New(functiontool.Config{Name: "forecast", InputSchemaAugmenter: augment},
+				func(ctx tool.Context, input Args) (map[string]any, error) { return nil, nil })
+			if err == nil {
+				t.Error("functiontool.New() succeeded, want error")
+			}
+		})
+	}
+}
+
 func toolDeclaration(cfg *genai.GenerateContentConfig) *genai.FunctionDeclaration {
 	if cfg == nil || len(cfg.Tools) == 0 {
 		return nil
@@ -654,4 +723,1413 @@ func TestFunctionTool_PanicRecovery(t *testing.T) {
 			t.Errorf("expected error to contain %q, but it did not. Error: %v", part, err)
 		}
 	}
+	var panicErr *tool.PanicError
+	if !errors.As(err, &panicErr) || panicErr.Value != "intentional panic for testing" {
+		t.Errorf("Run() error = %v, want a *tool.PanicError with the panic value", err)
+	}
+}
+
+func TestFunctionTool_HandlerError(t *testing.T) {
+	type Args struct {
+		City string `json:"city"`
+	}
+	type Result struct {
+		Forecast string `json:"forecast"`
+	}
+	errUnknownCity := errors.New("unknown city")
+
+	weatherTool, err := functiontool.New(functiontool.Config{
+		Name:        "get_weather",
+		Description: "returns the weather in a city",
+	}, func(_ tool.Context, args Args) (Result, error) {
+		if args.City != "Paris" {
+			return Result{Forecast: "ignored"}, fmt.Errorf("%w: %s", errUnknownCity, args.City)
+		}
+		return Result{Forecast: "sunny"}, nil
+	})
+	if err != nil {
+		t.Fatalf("NewFunctionTool failed: %v", err)
+	}
+	funcTool := weatherTool.(toolinternal.FunctionTool)
+
+	// The output schema is inferred from the result type only.
+	wantSchema := &jsonschema.Schema{
+		Type:                 "object",
+		Properties:           map[string]*jsonschema.Schema{"forecast": {Type: "string"}},
+		Required:             []string{"forecast"},
+		AdditionalProperties: &jsonschema.Schema{Not: &jsonschema.Schema{}},
+	}
+	if diff := cmp.Diff(wantSchema, funcTool.Declaration().ResponseJsonSchema); diff != "" {
+		t.Errorf("ResponseJsonSchema mismatch (-want +got):\n%s", diff)
+	}
+
+	// The error of the handler is returned as is, without result.
+	result, err := funcTool.Run(nil, map[string]any{"city": "Atlantis"})
+	if !errors.Is(err, errUnknownCity) {
+		t.Errorf("Run() error = %v, want %v", err, errUnknownCity)
+	}
+	if result != nil {
+		t.Errorf("Run() result = %v, want nil", result)
+	}
+
+	result, err = funcTool.Run(nil, map[string]any{"city": "Paris"})
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if diff := cmp.Diff(map[string]any{"forecast": "sunny"}, result); diff != "" {
+		t.Errorf("Run() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestFunctionTool_PanicRecovery_Error(t *testing.T) {
+	type Args struct {
+		Value string `json:"value"`
+	}
+	errBoom := errors.New("boom")
+
+	panicTool, err := functiontool.New(functiontool.Config{
+		Name:        "panic_tool",
+		Description: "a tool that panics with an error",
+	}, func(ctx tool.Context, input Args) (string, error) {
+		panic(fmt.Errorf("handler failed: %w", errBoom))
+	})
+	if err != nil {
+		t.Fatalf("NewFunctionTool failed: %v", err)
+	}
+
+	result, err := panicTool.(toolinternal.FunctionTool).Run(nil, map[string]any{"value": "test"})
+	if !errors.Is(err, errBoom) {
+		t.Fatalf("Run() error = %v, want an error wrapping %v", err, errBoom)
+	}
+	if result != nil {
+		t.Errorf("expected nil result, got %v", result)
+	}
+	for _, part := range []string{"panic in tool", "panic_tool", "handler failed: boom", "stack:"} {
+		if !strings.Contains(err.Error(), part) {
+			t.Errorf("expected error to contain %q, but it did not. Error: %v", part, err)
+		}
+	}
+}
+
+func TestNewNoArgs(t *testing.T) {
+	type Result struct {
+		Time string `json:"time"`
+	}
+	clockTool, err := functiontool.NewNoArgs(functiontool.Config{
+		Name:        "get_time",
+		Description: "returns the current time",
+	}, func(tool.Context) (Result, error) {
+		return Result{Time: "12:00"}, nil
+	})
+	if err != nil {
+		t.Fatalf("NewNoArgs failed: %v", err)
+	}
+	funcTool := clockTool.(toolinternal.FunctionTool)
+
+	decl := funcTool.Declaration()
+	if decl.ParametersJsonSchema != nil || decl.Parameters != nil {
+		t.Errorf("Declaration() has parameters %v, want none", decl.ParametersJsonSchema)
+	}
+	if decl.ResponseJsonSchema == nil {
+		t.Error("Declaration() has no response schema, want the inferred one")
+	}
+
+	for _, args := range []any{nil, map[string]any(nil), map[string]any{}, map[string]any{"unexpected": 1}} {
+		got, err := funcTool.Run(nil, args)
+		if err != nil {
+			t.Fatalf("Run(%v) error = %v", args, err)
+		}
+		if diff := cmp.Diff(map[string]any{"time": "12:00"}, got); diff != "" {
+			t.Errorf("Run(%v) mismatch (-want +got):\n%s", args, diff)
+		}
+	}
+
+	if _, err := functiontool.NewNoArgs(functiontool.Config{
+		Name:        "get_time",
+		InputSchema: &jsonschema.Schema{Type: "object"},
+	}, func(tool.Context) (Result, error) { return Result{}, nil }); !errors.Is(err, functiontool.ErrInvalidArgument) {
+		t.Errorf("NewNoArgs() with an input schema error = %v, want %v", err, functiontool.ErrInvalidArgument)
+	}
+}
+
+func TestNewAction(t *testing.T) {
+	type Args struct {
+		To string `json:"to"`
+	}
+	var sent []string
+	sendTool, err := functiontool.NewAction(functiontool.Config{
+		Name:        "send",
+		Description: "sends a message",
+	}, func(_ tool.Context, args Args) error {
+		if args.To == "" {
+			return errors.New("no recipient")
+		}
+		sent = append(sent, args.To)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("NewAction() failed: %v", err)
+	}
+	got, err := sendTool.(toolinternal.FunctionTool).Run(nil, map[string]any{"to": "ann"})
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if diff := cmp.Diff(map[string]any{"result": "done"}, got); diff != "" {
+		t.Errorf("Run() mismatch (-want +got):\n%s", diff)
+	}
+	if _, err := sendTool.(toolinternal.FunctionTool).Run(nil, map[string]any{"to": ""}); err == nil || err.Error() != "no recipient" {
+		t.Errorf("Run() error = %v, want the error of the handler", err)
+	}
+	if diff := cmp.Diff([]string{"ann"}, sent); diff != "" {
+		t.Errorf("sent mismatch (-want +got):\n%s", diff)
+	}
+
+	var resets int
+	resetTool, err := functiontool.NewNoArgsAction(functiontool.Config{
+		Name:        "reset",
+		Description: "resets the counter",
+		ResultKey:   "status",
+	}, func(tool.Context) error {
+		resets++
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("NewNoArgsAction() failed: %v", err)
+	}
+	funcTool := resetTool.(toolinternal.FunctionTool)
+	if decl := funcTool.Declaration(); decl.ParametersJsonSchema != nil {
+		t.Errorf("Declaration() has parameters %v, want none", decl.ParametersJsonSchema)
+	}
+	got, err = funcTool.Run(nil, nil)
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if diff := cmp.Diff(map[string]any{"status": "done"}, got); diff != "" || resets != 1 {
+		t.Errorf("Run() = %v after %d resets, want the status after 1 reset", got, resets)
+	}
+}
+
+func TestFunctionTool_SchemaTags(t *testing.T) {
+	type Args struct {
+		City  string `json:"city" jsonschema:"description=The city name,enum=NYC|LA|SF"`
+		Units string `json:"units,omitempty" jsonschema:"description=The units,enum=metric|imperial,required=true"`
+	}
+	weatherTool, err := functiontool.New(functiontool.Config{
+		Name:        "get_weather",
+		Description: "returns the weather",
+	}, func(ctx tool.Context, args Args) (string, error) {
+		return "sunny in " + args.City, nil
+	})
+	if err != nil {
+		t.Fatalf("functiontool.New() failed: %v", err)
+	}
+
+	decl := weatherTool.(toolinternal.FunctionTool).Declaration()
+	got, err := json.Marshal(decl.ParametersJsonSchema)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"type":"object","required":["city","units"],` +
+		`"properties":{"city":{"type":"string","description":"The city name","enum":["NYC","LA","SF"]},` +
+		`"units":{"type":"string","description":"The units","enum":["metric","imperial"]}},` +
+		`"additionalProperties":false}`
+	if string(got) != want {
+		t.Errorf("Declaration().ParametersJsonSchema = %s, want %s", got, want)
+	}
+
+	// The arguments are validated against the enums.
+	if _, err := weatherTool.(toolinternal.FunctionTool).Run(nil, map[string]any{"city": "Paris", "units": "metric"}); err == nil {
+		t.Error("Run() with a city outside the enum succeeded, want error")
+	}
+}
+
+func TestFunctionTool_InputValidation(t *testing.T) {
+	type Args struct {
+		City  string `json:"city" jsonschema:"enum=NYC|LA|SF"`
+		Days  int    `json:"days"`
+		Units string `json:"units,omitempty"`
+	}
+	newTool := func(skip bool) toolinternal.FunctionTool {
+		t.Helper()
+		weatherTool, err := functiontool.New(functiontool.Config{
+			Name:                "get_weather",
+			Description:         "returns the weather",
+			SkipInputValidation: skip,
+		}, func(ctx tool.Context, args Args) (string, error) {
+			return fmt.Sprintf("sunny in %q for %d days", args.City, args.Days), nil
+		})
+		if err != nil {
+			t.Fatalf("functiontool.New() failed: %v", err)
+		}
+		return weatherTool.(toolinternal.FunctionTool)
+	}
+
+	tests := []struct {
+		name       string
+		args       map[string]any
+		wantFields []string
+	}{
+		{
+			name:       "missing required field",
+			args:       map[string]any{"city": "NYC"},
+			wantFields: []string{"days"},
+		},
+		{
+			name:       "enum violation",
+			args:       map[string]any{"city": "Paris", "days": 2},
+			wantFields: []string{"city"},
+		},
+		{
+			name:       "wrong type and ignored unknown field",
+			args:       map[string]any{"city": "LA", "days": "two", "country": "US"},
+			wantFields: []string{"days"},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := newTool(false).Run(nil, tc.args)
+			var verr *functiontool.ValidationError
+			if !errors.As(err, &verr) {
+				t.Fatalf("Run() error = %v, want a ValidationError", err)
+			}
+			var gotFields []string
+			for _, f := range verr.Fields {
+				gotFields = append(gotFields, f.Field)
+			}
+			if diff := cmp.Diff(tc.wantFields, gotFields); diff != "" {
+				t.Errorf("ValidationError.Fields mismatch (-want +got):\n%s", diff)
+			}
+			msg := err.Error()
+			if !strings.HasPrefix(msg, `invalid arguments for tool "get_weather":`) || !strings.HasSuffix(msg, "call the tool again") {
+				t.Errorf("Run() error = %q, want a message asking to retry", msg)
+			}
+		})
+	}
+
+	// Without validation, the missing fields get their zero values.
+	got, err := newTool(true).Run(nil, map[string]any{"city": "Paris"})
+	if err != nil {
+		t.Fatalf("Run() without validation error = %v", err)
+	}
+	if want := map[string]any{"result": `sunny in "Paris" for 0 days`}; !cmp.Equal(want, got) {
+		t.Errorf("Run() without validation = %v, want %v", got, want)
+	}
+
+	// The arguments which cannot be converted fail with a ValidationError
+	// too, reported to the model as invalid arguments.
+	_, err = newTool(true).Run(nil, map[string]any{"city": "Paris", "days": "two"})
+	var verr *functiontool.ValidationError
+	if !errors.As(err, &verr) {
+		t.Fatalf("Run() without validation error = %v, want a ValidationError", err)
+	}
+	if diff := cmp.Diff([]functiontool.FieldError{{Field: "days", Message: "cannot use a JSON string as int"}}, verr.Fields); diff != "" {
+		t.Errorf("ValidationError.Fields mismatch (-want +got):\n%s", diff)
+	}
+	wantResult := map[string]any{"error": map[string]any{
+		"code":    tool.CodeInvalidArguments,
+		"message": err.Error(),
+		"details": map[string]any{"fields": []any{map[string]any{"field": "days", "message": "cannot use a JSON string as int"}}},
+	}}
+	if diff := cmp.Diff(wantResult, toolinternal.ErrorResult(err)); diff != "" {
+		t.Errorf("ErrorResult() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestFunctionTool_Content(t *testing.T) {
+	type Args struct{}
+	chartTool, err := functiontool.New(functiontool.Config{
+		Name:        "chart",
+		Description: "draws a chart",
+	}, func(tool.Context, Args) (*tool.Content, error) {
+		return &tool.Content{Parts: []*genai.Part{
+			genai.NewPartFromText("Sales"),
+			genai.NewPartFromBytes([]byte("png"), "image/png"),
+			genai.NewPartFromText("per month"),
+			genai.NewPartFromURI("gs://bucket/data.csv", "text/csv"),
+		}}, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if decl := chartTool.(toolinternal.FunctionTool).Declaration(); decl.ResponseJsonSchema != nil {
+		t.Errorf("Declaration().ResponseJsonSchema = %v, want none", decl.ResponseJsonSchema)
+	}
+	result, err := chartTool.(toolinternal.FunctionTool).Run(nil, map[string]any{})
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	response, parts := toolinternal.SplitContentResult(result)
+	if diff := cmp.Diff(map[string]any{"output": "Sales\nper month"}, response); diff != "" {
+		t.Errorf("function response mismatch (-want +got):\n%s", diff)
+	}
+	wantParts := []*genai.Part{
+		genai.NewPartFromBytes([]byte("png"), "image/png"),
+		genai.NewPartFromURI("gs://bucket/data.csv", "text/csv"),
+	}
+	if diff := cmp.Diff(wantParts, parts); diff != "" {
+		t.Errorf("content parts mismatch (-want +got):\n%s", diff)
+	}
+
+	callTool, err := functiontool.New(functiontool.Config{Name: "call"}, func(tool.Context, Args) (tool.Content, error) {
+		return tool.Content{Parts: []*genai.Part{genai.NewPartFromFunctionCall("other", nil)}}, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := callTool.(toolinternal.FunctionTool).Run(nil, map[string]any{}); err == nil {
+		t.Error("Run() with a function call part succeeded, want error")
+	}
+}
+
+func TestFunctionTool_Timeout(t *testing.T) {
+	type Args struct{}
+	release := make(chan struct{})
+	defer close(release)
+
+	tests := []struct {
+		name    string
+		handler functiontool.Func[Args, string]
+	}{
+		{
+			name: "handler respects cancellation",
+			handler: func(ctx tool.Context, _ Args) (string, error) {
+				<-ctx.Done()
+				return "", ctx.Err()
+			},
+		},
+		{
+			name: "handler ignores cancellation",
+			handler: func(ctx tool.Context, _ Args) (string, error) {
+				<-release
+				return "late", nil
+			},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			slowTool, err := functiontool.New(functiontool.Config{
+				Name:    "slow",
+				Timeout: 10 * time.Millisecond,
+			}, tc.handler)
+			if err != nil {
+				t.Fatal(err)
+			}
+			start := time.Now()
+			_, err = slowTool.(toolinternal.FunctionTool).Run(newToolContext(t), map[string]any{})
+			if !errors.Is(err, context.DeadlineExceeded) || !strings.Contains(err.Error(), `tool "slow" timed out after 10ms`) {
+				t.Errorf("Run() error = %v, want a timeout error", err)
+			}
+			if elapsed := time.Since(start); elapsed > 5*time.Second {
+				t.Errorf("Run() returned after %v, want it to return on the timeout", elapsed)
+			}
+		})
+	}
+
+	t.Run("handler returns in time", func(t *testing.T) {
+		fastTool, err := functiontool.New(functiontool.Config{
+			Name:    "fast",
+			Timeout: time.Minute,
+		}, func(ctx tool.Context, _ Args) (string, error) {
+			if _, ok := ctx.Deadline(); !ok {
+				return "", errors.New("no deadline")
+			}
+			return ctx.FunctionCallID(), nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := fastTool.(toolinternal.FunctionTool).Run(newToolContext(t), map[string]any{})
+		if err != nil {
+			t.Fatalf("Run() error = %v", err)
+		}
+		if want := map[string]any{"result": "call1"}; !cmp.Equal(want, got) {
+			t.Errorf("Run() = %v, want %v", got, want)
+		}
+	})
+}
+
+func newToolContext(t *testing.T) tool.Context {
+	t.Helper()
+	return toolinternal.NewToolContext(icontext.NewInvocationContext(t.Context(), icontext.InvocationContextParams{}), "call1", nil)
+}
+
+func TestFunctionTool_IsEnabled(t *testing.T) {
+	type Args struct{}
+	resp, err := session.InMemoryService().Create(t.Context(), &session.CreateRequest{AppName: "app", UserID: "user"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sess := resp.Session
+	betaTool, err := functiontool.New(functiontool.Config{
+		Name:        "beta",
+		Description: "a tool in beta",
+		IsEnabled: func(ctx tool.Context) bool {
+			enabled, _ := ctx.State().Get("beta_enabled")
+			return enabled == true
+		},
+	}, func(_ tool.Context, _ Args) (string, error) {
+		return "ok", nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	newCtx := func() tool.Context {
+		inv := icontext.NewInvocationContext(t.Context(), icontext.InvocationContextParams{Session: sess})
+		return toolinternal.NewToolContext(inv, "call1", nil)
+	}
+	offered := func() bool {
+		t.Helper()
+		req := &model.LLMRequest{}
+		if err := betaTool.(toolinternal.RequestProcessor).ProcessRequest(newCtx(), req); err != nil {
+			t.Fatalf("ProcessRequest() error = %v", err)
+		}
+		_, inTools := req.Tools["beta"]
+		declared := req.Config != nil && len(req.Config.Tools) > 0
+		if inTools != declared {
+			t.Errorf("tool registered: %v, declared: %v, want both or neither", inTools, declared)
+		}
+		return inTools
+	}
+
+	if offered() {
+		t.Errorf("the tool is offered without the flag")
+	}
+	if _, err := betaTool.(toolinternal.FunctionTool).Run(newCtx(), map[string]any{}); !errors.Is(err, functiontool.ErrNotAvailable) {
+		t.Errorf("Run() of the disabled tool error = %v, want %v", err, functiontool.ErrNotAvailable)
+	}
+
+	if err := sess.State().Set("beta_enabled", true); err != nil {
+		t.Fatal(err)
+	}
+	if !offered() {
+		t.Errorf("the tool is not offered with the flag")
+	}
+	if _, err := betaTool.(toolinternal.FunctionTool).Run(newCtx(), map[string]any{}); err != nil {
+		t.Errorf("Run() of the enabled tool error = %v", err)
+	}
+
+	if err := sess.State().Set("beta_enabled", false); err != nil {
+		t.Fatal(err)
+	}
+	if offered() {
+		t.Errorf("the tool is still offered once the flag is unset")
+	}
+}
+
+func TestFunctionTool_ResultKey(t *testing.T) {
+	type Args struct{}
+	type Point struct {
+		X int `json:"x"`
+		Y int `json:"y"`
+	}
+	must := func(got tool.Tool, err error) tool.Tool {
+		t.Helper()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return got
+	}
+
+	tests := []struct {
+		name       string
+		tool       tool.Tool
+		want       map[string]any
+		wantSchema map[string]any
+	}{
+		{
+			name: "string",
+			tool: must(functiontool.New(functiontool.Config{Name: "weather", ResultKey: "report"},
+				func(tool.Context, Args) (string, error) { return "sunny", nil })),
+			want: map[string]any{"report": "sunny"},
+			wantSchema: map[string]any{
+				"type":       "object",
+				"properties": map[string]any{"report": map[string]any{"type": "string"}},
+				"required":   []any{"report"},
+			},
+		},
+		{
+			name: "int",
+			tool: must(functiontool.New(functiontool.Config{Name: "count", ResultKey: "count"},
+				func(tool.Context, Args) (int, error) { return 42, nil })),
+			want: map[string]any{"count": 42},
+			wantSchema: map[string]any{
+				"type":       "object",
+				"properties": map[string]any{"count": map[string]any{"type": "integer"}},
+				"required":   []any{"count"},
+			},
+		},
+		{
+			name: "slice with the default key",
+			tool: must(functiontool.New(functiontool.Config{Name: "list"},
+				func(tool.Context, Args) ([]string, error) { return []string{"a", "b"}, nil })),
+			want: map[string]any{"result": []string{"a", "b"}},
+			wantSchema: map[string]any{
+				"type": "object",
+				"properties": map[string]any{"result": map[string]any{
+					"type":  "array",
+					"items": map[string]any{"type": "string"},
+				}},
+				"required": []any{"result"},
+			},
+		},
+		{
+			name: "struct",
+			tool: must(functiontool.New(functiontool.Config{Name: "locate", ResultKey: "point"},
+				func(tool.Context, Args) (Point, error) { return Point{X: 1, Y: 2}, nil })),
+			want: map[string]any{"x": 1.0, "y": 2.0},
+			wantSchema: map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"x": map[string]any{"type": "integer"},
+					"y": map[string]any{"type": "integer"},
+				},
+				"required":             []any{"x", "y"},
+				"additionalProperties": false,
+			},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			funcTool := tc.tool.(toolinternal.FunctionTool)
+			got, err := funcTool.Run(nil, map[string]any{})
+			if err != nil {
+				t.Fatalf("Run() error = %v", err)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("Run() mismatch (-want +got):\n%s", diff)
+			}
+			schema, err := typeutil.ConvertToWithJSONSchema[any, map[string]any](funcTool.Declaration().ResponseJsonSchema, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tc.wantSchema, schema); diff != "" {
+				t.Errorf("response schema mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestFunctionTool_NullFields(t *testing.T) {
+	type Inner struct {
+		N int `json:"n,omitempty"`
+	}
+	type Args struct {
+		Ptr      *int           `json:"ptr,omitempty"`
+		Time     *time.Time     `json:"time,omitempty"`
+		Inner    *Inner         `json:"inner,omitempty"`
+		Value    Inner          `json:"value,omitempty"`
+		Slice    []string       `json:"slice,omitempty"`
+		Map      map[string]int `json:"map,omitempty"`
+		Str      string         `json:"str,omitempty"`
+		Nullable []string       `json:"nullable,omitempty" jsonschema:"nullable=true"`
+	}
+	var got Args
+	ft, err := functiontool.New(functiontool.Config{Name: "null"}, func(_ tool.Context, args Args) (map[string]any, error) {
+		got = args
+		return nil, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	funcTool := ft.(toolinternal.FunctionTool)
+
+	tests := []struct {
+		name       string
+		args       map[string]any
+		want       Args
+		wantFields []functiontool.FieldError
+	}{
+		{
+			name: "omitted fields",
+			args: map[string]any{},
+			want: Args{},
+		},
+		{
+			name: "null pointers",
+			args: map[string]any{"ptr": nil, "time": nil, "inner": nil},
+			want: Args{},
+		},
+		{
+			name: "nullable slice",
+			args: map[string]any{"nullable": nil},
+			want: Args{},
+		},
+		{
+			name: "set fields",
+			args: map[string]any{
+				"ptr":      1,
+				"time":     "2025-01-02T03:04:05Z",
+				"inner":    map[string]any{"n": 2},
+				"value":    map[string]any{"n": 3},
+				"slice":    []any{"a"},
+				"map":      map[string]any{"k": 4},
+				"nullable": []any{"b"},
+			},
+			want: Args{
+				Ptr:      genai.Ptr(1),
+				Time:     genai.Ptr(time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)),
+				Inner:    &Inner{N: 2},
+				Value:    Inner{N: 3},
+				Slice:    []string{"a"},
+				Map:      map[string]int{"k": 4},
+				Nullable: []string{"b"},
+			},
+		},
+		{
+			name: "null slice",
+			args: map[string]any{"slice": nil},
+			wantFields: []functiontool.FieldError{
+				{Field: "slice", Message: `null is not allowed, want "array"`},
+			},
+		},
+		{
+			name: "null map",
+			args: map[string]any{"map": nil},
+			wantFields: []functiontool.FieldError{
+				{Field: "map", Message: `null is not allowed, want "object"`},
+			},
+		},
+		{
+			name: "null struct",
+			args: map[string]any{"value": nil},
+			wantFields: []functiontool.FieldError{
+				{Field: "value", Message: `null is not allowed, want "object"`},
+			},
+		},
+		{
+			name: "null nested field",
+			args: map[string]any{"inner": map[string]any{"n": nil}},
+			wantFields: []functiontool.FieldError{
+				{Field: "inner", Message: `validating /properties/n: null is not allowed, want "integer"`},
+			},
+		},
+		{
+			name: "null string",
+			args: map[string]any{"str": nil},
+			wantFields: []functiontool.FieldError{
+				{Field: "str", Message: `null is not allowed, want "string"`},
+			},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got = Args{}
+			_, err := funcTool.Run(nil, tc.args)
+			if tc.wantFields != nil {
+				var verr *functiontool.ValidationError
+				if !errors.As(err, &verr) {
+					t.Fatalf("Run() error = %v, want a ValidationError", err)
+				}
+				if diff := cmp.Diff(tc.wantFields, verr.Fields); diff != "" {
+					t.Errorf("ValidationError.Fields mismatch (-want +got):\n%s", diff)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Run() error = %v", err)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("args mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestFunctionTool_Introspection(t *testing.T) {
+	type Args struct {
+		City string `json:"city" jsonschema:"The city name"`
+	}
+	ft, err := functiontool.New(functiontool.Config{Name: "weather", Description: "Gets the weather"}, func(tool.Context, Args) (string, error) {
+		return "sunny", nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	schema, ok := functiontool.InputSchema(ft)
+	if !ok || schema == nil {
+		t.Fatalf("InputSchema() = %v, %v, want a schema", schema, ok)
+	}
+	if got := schema.Schema().Properties["city"].Description; got != "The city name" {
+		t.Errorf("InputSchema() city description = %q, want %q", got, "The city name")
+	}
+	if err := schema.Validate(map[string]any{}); err == nil {
+		t.Error("InputSchema().Validate() without the required city succeeded, want error")
+	}
+
+	data, err := json.Marshal(ft)
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+	var got map[string]any
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]any{
+		"name":        "weather",
+		"description": "Gets the weather",
+		"parametersJsonSchema": map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"city": map[string]any{"type": "string", "description": "The city name"},
+			},
+			"required":             []any{"city"},
+			"additionalProperties": false,
+		},
+		"responseJsonSchema": map[string]any{
+			"type":       "object",
+			"properties": map[string]any{"result": map[string]any{"type": "string"}},
+			"required":   []any{"result"},
+		},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("json.Marshal() mismatch (-want +got):\n%s", diff)
+	}
+
+	noArgs, err := functiontool.NewNoArgs(functiontool.Config{Name: "now"}, func(tool.Context) (string, error) {
+		return "noon", nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if schema, ok := functiontool.InputSchema(noArgs); !ok || schema != nil {
+		t.Errorf("InputSchema() of a tool without arguments = %v, %v, want nil, true", schema, ok)
+	}
+	if _, ok := functiontool.InputSchema(geminitool.GoogleSearch{}); ok {
+		t.Error("InputSchema() of another tool succeeded, want false")
+	}
+}
+
+func TestFunctionTool_OnUnknownField(t *testing.T) {
+	type Args struct {
+		City  string         `json:"city"`
+		Extra map[string]any `json:"-" functiontool:"unknown"`
+	}
+	var got Args
+	newTool := func(mode functiontool.UnknownFieldMode, skipValidation bool) toolinternal.FunctionTool {
+		t.Helper()
+		ft, err := functiontool.New(functiontool.Config{
+			Name:                "weather",
+			OnUnknownField:      mode,
+			SkipInputValidation: skipValidation,
+		}, func(_ tool.Context, args Args) (string, error) {
+			got = args
+			return "sunny", nil
+		})
+		if err != nil {
+			t.Fatalf("functiontool.New() error = %v", err)
+		}
+		return ft.(toolinternal.FunctionTool)
+	}
+	args := map[string]any{"city": "Paris", "country": "FR", "units": "metric"}
+
+	tests := []struct {
+		name       string
+		mode       functiontool.UnknownFieldMode
+		want       Args
+		wantFields []functiontool.FieldError
+	}{
+		{
+			name: "ignore",
+			mode: functiontool.IgnoreUnknown,
+			want: Args{City: "Paris"},
+		},
+		{
+			name: "error",
+			mode: functiontool.ErrorOnUnknown,
+			wantFields: []functiontool.FieldError{
+				{Field: "country", Message: "unknown field"},
+				{Field: "units", Message: "unknown field"},
+			},
+		},
+		{
+			name: "capture",
+			mode: functiontool.CaptureUnknown,
+			want: Args{City: "Paris", Extra: map[string]any{"country": "FR", "units": "metric"}},
+		},
+	}
+	for _, tc := range tests {
+		for _, skip := range []bool{false, true} {
+			t.Run(fmt.Sprintf("%s/skip validation %v", tc.name, skip), func(t *testing.T) {
+				got = Args{}
+				_, err := newTool(tc.mode, skip).Run(nil, args)
+				if tc.wantFields != nil {
+					var verr *functiontool.ValidationError
+					if !errors.As(err, &verr) {
+						t.Fatalf("Run() error = %v, want a ValidationError", err)
+					}
+					if diff := cmp.Diff(tc.wantFields, verr.Fields); diff != "" {
+						t.Errorf("ValidationError.Fields mismatch (-want +got):\n%s", diff)
+					}
+					if !strings.Contains(err.Error(), `field "country": unknown field`) {
+						t.Errorf("Run() error = %q, want it to name the unknown fields", err)
+					}
+					return
+				}
+				if err != nil {
+					t.Fatalf("Run() error = %v", err)
+				}
+				if diff := cmp.Diff(tc.want, got); diff != "" {
+					t.Errorf("args mismatch (-want +got):\n%s", diff)
+				}
+			})
+		}
+	}
+
+	// Without unknown fields, nothing is captured.
+	got = Args{}
+	if _, err := newTool(functiontool.CaptureUnknown, false).Run(nil, map[string]any{"city": "Paris"}); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if got.Extra != nil {
+		t.Errorf("captured fields = %v, want nil", got.Extra)
+	}
+
+	// Capturing requires a single, well-typed, tagged field.
+	handler := func(tool.Context, struct {
+		City string `json:"city"`
+	}) (string, error) {
+		return "", nil
+	}
+	if _, err := functiontool.New(functiontool.Config{Name: "weather", OnUnknownField: functiontool.CaptureUnknown}, handler); !errors.Is(err, functiontool.ErrInvalidArgument) {
+		t.Errorf("New() without a capturing field error = %v, want ErrInvalidArgument", err)
+	}
+	badHandler := func(tool.Context, struct {
+		Extra map[string]string `json:"-" functiontool:"unknown"`
+	}) (string, error) {
+		return "", nil
+	}
+	if _, err := functiontool.New(functiontool.Config{Name: "weather", OnUnknownField: functiontool.CaptureUnknown}, badHandler); !errors.Is(err, functiontool.ErrInvalidArgument) {
+		t.Errorf("New() with a mistyped capturing field error = %v, want ErrInvalidArgument", err)
+	}
+}
+
+func TestFunctionTool_RecursiveArgs(t *testing.T) {
+	type TreeNode struct {
+		Name     string      `json:"name"`
+		Children []*TreeNode `json:"children,omitempty"`
+	}
+	countTool, err := functiontool.New(functiontool.Config{Name: "count"}, func(_ tool.Context, root TreeNode) (int, error) {
+		var count func(*TreeNode) int
+		count = func(n *TreeNode) int {
+			c := 1
+			for _, child := range n.Children {
+				c += count(child)
+			}
+			return c
+		}
+		return count(&root), nil
+	})
+	if err != nil {
+		t.Fatalf("functiontool.New() with recursive arguments error = %v", err)
+	}
+	funcTool := countTool.(toolinternal.FunctionTool)
+
+	got, err := funcTool.Run(nil, map[string]any{"name": "root", "children": []any{
+		map[string]any{"name": "a", "children": []any{map[string]any{"name": "b"}}},
+		map[string]any{"name": "c"},
+	}})
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if diff := cmp.Diff(map[string]any{"result": 4}, got); diff != "" {
+		t.Errorf("Run() mismatch (-want +got):\n%s", diff)
+	}
+
+	_, err = funcTool.Run(nil, map[string]any{"name": "root", "children": []any{map[string]any{"name": 1}}})
+	var verr *functiontool.ValidationError
+	if !errors.As(err, &verr) || len(verr.Fields) != 1 || verr.Fields[0].Field != "children" {
+		t.Errorf("Run() with an invalid child error = %v, want a ValidationError on children", err)
+	}
+}
+
+func TestFunctionTool_MaxResultBytes(t *testing.T) {
+	type Args struct {
+		Size int    `json:"size"`
+		Char string `json:"char,omitempty"`
+	}
+	// The JSON encoding of the result is {"data":"..."}: 11 bytes plus the
+	// data.
+	newTool := func(strategy functiontool.TruncateStrategy) toolinternal.FunctionTool {
+		t.Helper()
+		ft, err := functiontool.New(functiontool.Config{
+			Name:             "blob",
+			MaxResultBytes:   100,
+			TruncateStrategy: strategy,
+		}, func(_ tool.Context, args Args) (map[string]any, error) {
+			char := args.Char
+			if char == "" {
+				char = "x"
+			}
+			return map[string]any{"data": strings.Repeat(char, args.Size/len(char))}, nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return ft.(toolinternal.FunctionTool)
+	}
+	encodedSize := func(t *testing.T, m map[string]any) int {
+		t.Helper()
+		var buf strings.Builder
+		enc := json.NewEncoder(&buf)
+		enc.SetEscapeHTML(false)
+		if err := enc.Encode(m); err != nil {
+			t.Fatal(err)
+		}
+		return buf.Len() - 1
+	}
+
+	for _, tc := range []struct {
+		name string
+		size int
+	}{
+		{name: "just under", size: 88},
+		{name: "at", size: 89},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := newTool(functiontool.TruncateHead).Run(nil, map[string]any{"size": tc.size})
+			if err != nil {
+				t.Fatalf("Run() error = %v", err)
+			}
+			if want := map[string]any{"data": strings.Repeat("x", tc.size)}; !cmp.Equal(want, got) {
+				t.Errorf("Run() = %v, want the full result", got)
+			}
+		})
+	}
+
+	tests := []struct {
+		name     string
+		strategy functiontool.TruncateStrategy
+		char     string
+		check    func(t *testing.T, got map[string]any)
+	}{
+		{
+			name:     "well over, head",
+			strategy: functiontool.TruncateHead,
+			check: func(t *testing.T, got map[string]any) {
+				if s, _ := got["result"].(string); !strings.HasPrefix(s, `{"data":"xxx`) {
+					t.Errorf("result = %q, want the beginning of the result", s)
+				}
+			},
+		},
+		{
+			name: "well over, default strategy with multibyte characters",
+			char: "é",
+			check: func(t *testing.T, got map[string]any) {
+				if s, _ := got["result"].(string); !strings.HasPrefix(s, `{"data":"ééé`) || !utf8.ValidString(s) {
+					t.Errorf("result = %q, want the valid beginning of the result", s)
+				}
+			},
+		},
+		{
+			name:     "well over, tail",
+			strategy: functiontool.TruncateTail,
+			check: func(t *testing.T, got map[string]any) {
+				if s, _ := got["result"].(string); !strings.HasSuffix(s, `xxx"}`) {
+					t.Errorf("result = %q, want the end of the result", s)
+				}
+			},
+		},
+		{
+			name:     "well over, marker",
+			strategy: functiontool.TruncateMarker,
+			check: func(t *testing.T, got map[string]any) {
+				if _, ok := got["result"]; ok {
+					t.Errorf("Run() = %v, want no result", got)
+				}
+				if note, _ := got["note"].(string); !strings.Contains(note, "exceeds the size limit") {
+					t.Errorf("note = %q, want it to explain the truncation", note)
+				}
+			},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := newTool(tc.strategy).Run(nil, map[string]any{"size": 1000, "char": tc.char})
+			if err != nil {
+				t.Fatalf("Run() error = %v", err)
+			}
+			if got["truncated"] != true || got["original_bytes"] != 1011 {
+				t.Errorf("Run() = %v, want it marked as truncated from 1011 bytes", got)
+			}
+			if size := encodedSize(t, got); size > 100 {
+				t.Errorf("truncated result has %d bytes, want at most 100", size)
+			}
+			tc.check(t, got)
+		})
+	}
+
+	t.Run("custom result key", func(t *testing.T) {
+		ft, err := functiontool.New(functiontool.Config{
+			Name:           "text",
+			ResultKey:      "output",
+			MaxResultBytes: 100,
+		}, func(_ tool.Context, args Args) (string, error) {
+			return strings.Repeat("x", args.Size), nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := ft.(toolinternal.FunctionTool).Run(nil, map[string]any{"size": 1000})
+		if err != nil {
+			t.Fatalf("Run() error = %v", err)
+		}
+		if s, _ := got["output"].(string); !strings.HasPrefix(s, `{"output":"xxx`) || got["truncated"] != true {
+			t.Errorf("Run() = %v, want the beginning of the result under the output key", got)
+		}
+		if _, ok := got["result"]; ok {
+			t.Errorf("Run() = %v, want no result key", got)
+		}
+	})
+
+}
+
+func TestFunctionTool_CanonicalResults(t *testing.T) {
+	type Args struct {
+		Keys []string `json:"keys"`
+	}
+	// The handler builds its map in the order of the keys, and mixes the Go
+	// types of its numbers.
+	ft, err := functiontool.New(functiontool.Config{Name: "lookup"}, func(_ tool.Context, args Args) (map[string]any, error) {
+		result := make(map[string]any)
+		for i, k := range args.Keys {
+			if i%2 == 0 {
+				result[k] = map[string]any{"rank": i, "score": 1.0}
+			} else {
+				result[k] = map[string]any{"score": float32(1), "rank": float64(i)}
+			}
+		}
+		return result, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	funcTool := ft.(toolinternal.FunctionTool)
+
+	var encodings []string
+	for range 2 {
+		result, err := funcTool.Run(nil, map[string]any{"keys": []any{"zeta", "alpha", "mu", "beta"}})
+		if err != nil {
+			t.Fatalf("Run() error = %v", err)
+		}
+		encoded, err := tool.CanonicalJSON(result)
+		if err != nil {
+			t.Fatalf("CanonicalJSON() error = %v", err)
+		}
+		encodings = append(encodings, string(encoded))
+	}
+	want := `{"alpha":{"rank":1,"score":1},"beta":{"rank":3,"score":1},"mu":{"rank":2,"score":1},"zeta":{"rank":0,"score":1}}`
+	if diff := cmp.Diff([]string{want, want}, encodings); diff != "" {
+		t.Errorf("encodings of the results mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestFunctionTool_Hooks(t *testing.T) {
+	type Args struct {
+		Name string `json:"name"`
+	}
+	type Account struct {
+		Name  string `json:"name"`
+		Email string `json:"email"`
+	}
+	newTool := func(t *testing.T, cfg functiontool.Config, calls *int) toolinternal.FunctionTool {
+		t.Helper()
+		cfg.Name = "account"
+		ft, err := functiontool.New(cfg, func(ctx tool.Context, args Args) (Account, error) {
+			*calls++
+			if args.Name == "slow" {
+				<-ctx.Done()
+				return Account{}, ctx.Err()
+			}
+			return Account{Name: args.Name, Email: args.Name + "@example.com"}, nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return ft.(toolinternal.FunctionTool)
+	}
+	redact := func(_ tool.Context, result any, err error) (any, error) {
+		if err != nil {
+			return nil, err
+		}
+		m := result.(map[string]any)
+		m["email"] = "[redacted]"
+		return m, nil
+	}
+
+	t.Run("before short-circuits", func(t *testing.T) {
+		var calls int
+		var gotArgs map[string]any
+		ft := newTool(t, functiontool.Config{
+			BeforeRun: func(_ tool.Context, args map[string]any) (any, bool, error) {
+				gotArgs = args
+				return Account{Name: "mock"}, true, nil
+			},
+			AfterRun: redact,
+		}, &calls)
+		got, err := ft.Run(newToolContext(t), map[string]any{"name": "ada"})
+		if err != nil {
+			t.Fatalf("Run() error = %v", err)
+		}
+		if diff := cmp.Diff(map[string]any{"name": "mock", "email": ""}, got); diff != "" {
+			t.Errorf("Run() mismatch (-want +got):\n%s", diff)
+		}
+		if calls != 0 {
+			t.Errorf("handler called %d times, want 0", calls)
+		}
+		if diff := cmp.Diff(map[string]any{"name": "ada"}, gotArgs); diff != "" {
+			t.Errorf("BeforeRun() args mismatch (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("before passes through or fails", func(t *testing.T) {
+		var calls int
+		errBlocked := errors.New("blocked")
+		ft := newTool(t, functiontool.Config{
+			BeforeRun: func(_ tool.Context, args map[string]any) (any, bool, error) {
+				if args["name"] == "mallory" {
+					return nil, false, errBlocked
+				}
+				return nil, false, nil
+			},
+		}, &calls)
+		if _, err := ft.Run(newToolContext(t), map[string]any{"name": "mallory"}); !errors.Is(err, errBlocked) {
+			t.Errorf("Run() error = %v, want %v", err, errBlocked)
+		}
+		got, err := ft.Run(newToolContext(t), map[string]any{"name": "ada"})
+		if err != nil {
+			t.Fatalf("Run() error = %v", err)
+		}
+		if diff := cmp.Diff(map[string]any{"name": "ada", "email": "ada@example.com"}, got); diff != "" {
+			t.Errorf("Run() mismatch (-want +got):\n%s", diff)
+		}
+		if calls != 1 {
+			t.Errorf("handler called %d times, want 1", calls)
+		}
+	})
+
+	t.Run("after redacts a field", func(t *testing.T) {
+		var calls int
+		ft := newTool(t, functiontool.Config{AfterRun: redact}, &calls)
+		got, err := ft.Run(newToolContext(t), map[string]any{"name": "ada"})
+		if err != nil {
+			t.Fatalf("Run() error = %v", err)
+		}
+		if diff := cmp.Diff(map[string]any{"name": "ada", "email": "[redacted]"}, got); diff != "" {
+			t.Errorf("Run() mismatch (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("after recovers from a timeout", func(t *testing.T) {
+		var calls int
+		ft := newTool(t, functiontool.Config{
+			Timeout: 10 * time.Millisecond,
+			AfterRun: func(_ tool.Context, result any, err error) (any, error) {
+				if !errors.Is(err, context.DeadlineExceeded) || result != nil {
+					return nil, fmt.Errorf("AfterRun() got %v, %v, want a timeout", result, err)
+				}
+				return "unavailable", nil
+			},
+		}, &calls)
+		got, err := ft.Run(newToolContext(t), map[string]any{"name": "slow"})
+		if err != nil {
+			t.Fatalf("Run() error = %v", err)
+		}
+		if diff := cmp.Diff(map[string]any{"result": "unavailable"}, got); diff != "" {
+			t.Errorf("Run() mismatch (-want +got):\n%s", diff)
+		}
+	})
+}
+
+func TestFunctionTool_RequiredFields(t *testing.T) {
+	type Address struct {
+		City string  `json:"city"`
+		Zip  *string `json:"zip"`
+	}
+	type Office struct {
+		Floor *int   `json:"floor"`
+		Desk  string `json:"desk"`
+	}
+	type valueFields struct {
+		Name  string   `json:"name"`
+		Count int      `json:"count"`
+		Tags  []string `json:"tags"`
+	}
+	type pointerFields struct {
+		Name  *string `json:"name"`
+		Count *int    `json:"count"`
+	}
+	type omitFields struct {
+		Name  string  `json:"name,omitempty"`
+		Count int     `json:"count,omitzero"`
+		Limit *int    `json:"limit,omitempty"`
+		ID    string  `json:"id"`
+		Note  *string `json:"note"`
+	}
+	type skippedFields struct {
+		ID       string `json:"id"`
+		Internal string `json:"-"`
+		Dash     string `json:"-,"`
+		hidden   string
+	}
+	type taggedFields struct {
+		Name  *string `json:"name" jsonschema:"required=true"`
+		Count int     `json:"count" jsonschema:"required=false"`
+		ID    string  `json:"id" jsonschema:"The ID"`
+	}
+	type nestedFields struct {
+		Home   Address `json:"home"`
+		Office *Office `json:"office"`
+	}
+	newTool := func(t *testing.T, f func(cfg functiontool.Config) (tool.Tool, error)) toolinternal.FunctionTool {
+		t.Helper()
+		ft, err := f(functiontool.Config{Name: "required"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return ft.(toolinternal.FunctionTool)
+	}
+
+	tests := []struct {
+		name string
+		// newTool returns a tool whose argument type is the one of the case.
+		newTool    func(cfg functiontool.Config) (tool.Tool, error)
+		want       []string
+		wantNested map[string][]string
+	}{
+		{
+			name: "value fields are required",
+			newTool: func(cfg functiontool.Config) (tool.Tool, error) {
+				return functiontool.New(cfg, func(tool.Context, valueFields) (any, error) { return nil, nil })
+			},
+			want: []string{"count", "name", "tags"},
+		},
+		{
+			name: "pointer fields are optional",
+			newTool: func(cfg functiontool.Config) (tool.Tool, error) {
+				return functiontool.New(cfg, func(tool.Context, pointerFields) (any, error) { return nil, nil })
+			},
+			want: nil,
+		},
+		{
+			name: "omitempty and omitzero fields are optional",
+			newTool: func(cfg functiontool.Config) (tool.Tool, error) {
+				return functiontool.New(cfg, func(tool.Context, omitFields) (any, error) { return nil, nil })
+			},
+			want: []string{"id"},
+		},
+		{
+			name: "skipped fields are absent",
+			newTool: func(cfg functiontool.Config) (tool.Tool, error) {
+				return functiontool.New(cfg, func(tool.Context, skippedFields) (any, error) { return nil, nil })
+			},
+			want: []string{"-", "id"},
+		},
+		{
+			name: "tags override",
+			newTool: func(cfg functiontool.Config) (tool.Tool, error) {
+				return functiontool.New(cfg, func(tool.Context, taggedFields) (any, error) { return nil, nil })
+			},
+			want: []string{"id", "name"},
+		},
+		{
+			name: "nested structs",
+			newTool: func(cfg functiontool.Config) (tool.Tool, error) {
+				return functiontool.New(cfg, func(tool.Context, nestedFields) (any, error) { return nil, nil })
+			},
+			want:       []string{"home"},
+			wantNested: map[string][]string{"home": {"city"}, "office": {"desk"}},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			schema, ok := newTool(t, tc.newTool).Declaration().ParametersJsonSchema.(*jsonschema.Schema)
+			if !ok {
+				t.Fatal("ParametersJsonSchema is not a *jsonschema.Schema")
+			}
+			got := slices.Sorted(slices.Values(schema.Required))
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("Required mismatch (-want +got):\n%s", diff)
+			}
+			for name, want := range tc.wantNested {
+				prop := schema.Properties[name]
+				if prop == nil {
+					t.Fatalf("no property %q in %v", name, schema.Properties)
+				}
+				if diff := cmp.Diff(want, prop.Required); diff != "" {
+					t.Errorf("Required of %q mismatch (-want +got):\n%s", name, diff)
+				}
+			}
+		})
+	}
+}
+
+func TestFunctionTool_DeclareErrorResults(t *testing.T) {
+	type Args struct {
+		City string `json:"city"`
+	}
+	type Forecast struct {
+		Summary string `json:"summary"`
+	}
+	// Forecast is shared by two fields, so it is defined in $defs.
+	type Result struct {
+		Today    Forecast `json:"today"`
+		Tomorrow Forecast `json:"tomorrow"`
+	}
+	newTool := func(t *testing.T, declare bool) toolinternal.FunctionTool {
+		t.Helper()
+		ft, err := functiontool.New(functiontool.Config{Name: "forecast", DeclareErrorResults: declare}, func(_ tool.Context, args Args) (Result, error) {
+			if args.City != "Paris" {
+				return Result{}, &tool.ToolError{Code: "not_found", Message: "unknown city"}
+			}
+			return Result{Today: Forecast{"sunny"}, Tomorrow: Forecast{"rainy"}}, nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return ft.(toolinternal.FunctionTool)
+	}
+
+	outputSchema, ok := newTool(t, false).Declaration().ResponseJsonSchema.(*jsonschema.Schema)
+	if !ok || len(outputSchema.AnyOf) != 0 {
+		t.Fatalf("ResponseJsonSchema = %v, want the output schema by default", outputSchema)
+	}
+
+	ft := newTool(t, true)
+	declared, ok := ft.Declaration().ResponseJsonSchema.(*jsonschema.Schema)
+	if !ok {
+		t.Fatalf("ResponseJsonSchema = %T, want *jsonschema.Schema", ft.Declaration().ResponseJsonSchema)
+	}
+	if len(declared.AnyOf) != 2 || declared.Defs["Forecast"] == nil {
+		t.Fatalf("ResponseJsonSchema = %v, want the output and error schemas, with the $defs at the root", declared)
+	}
+	resolved, err := declared.Resolve(nil)
+	if err != nil {
+		t.Fatalf("Resolve() error = %v", err)
+	}
+
+	result, err := ft.Run(nil, map[string]any{"city": "Paris"})
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	_, err = ft.Run(nil, map[string]any{"city": "Atlantis"})
+	if err == nil {
+		t.Fatal("Run() succeeded, want error")
+	}
+	for _, response := range []map[string]any{
+		result,
+		toolinternal.ErrorResult(err),
+		toolinternal.ErrorResult(errors.New("failed")),
+	} {
+		jsonResponse, err := typeutil.ConvertToWithJSONSchema[map[string]any, map[string]any](response, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := resolved.Validate(jsonResponse); err != nil {
+			t.Errorf("Validate(%v) error = %v, want the response to match the declared schema", jsonResponse, err)
+		}
+	}
+	if err := resolved.Validate(map[string]any{"today": "sunny"}); err == nil {
+		t.Error("Validate() of an invalid response succeeded, want error")
+	}
 }
@@ -0,0 +1,61 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package functiontool
+
+import (
+	"google.golang.org/adk/internal/toolinternal"
+	"google.golang.org/adk/tool"
+)
+
+// BeforeRunFunc is called by Run before the arguments are validated and the
+// handler is called, with the context of the call and the arguments of the
+// model. It returns an error to fail the call, or skip to return override as
+// the result of the call without calling the handler, e.g. a canned result
+// for a test, or the refusal of a guardrail. AfterRun is not called then.
+//
+// The override is converted as the output of a handler: a map[string]any is
+// returned as is, a tool.Content as for the handlers returning it, a struct
+// as a JSON object, and any other value under Config.ResultKey.
+type BeforeRunFunc func(ctx tool.Context, args map[string]any) (override any, skip bool, err error)
+
+// AfterRunFunc is called by Run once the handler returned, with the context
+// of the call and either the result returned to the model, before its
+// truncation to Config.MaxResultBytes, or the error of the call, e.g. a
+// validation error or a timeout. It returns the result of the call instead,
+// converted as the override of BeforeRunFunc, or an error to fail the call.
+// It may modify and return the result it received, e.g. to redact a field,
+// or return a result for an error to recover from it.
+type AfterRunFunc func(ctx tool.Context, result any, err error) (any, error)
+
+// hookResult converts the result returned by a hook to the result of the
+// call, truncated as the results of the handler.
+func (f *functionTool[TArgs, TResults]) hookResult(v any) (map[string]any, error) {
+	var result map[string]any
+	switch r := v.(type) {
+	case nil:
+		return nil, nil
+	case map[string]any:
+		result = r
+	default:
+		if content, ok := asContent(v); ok {
+			return toolinternal.ContentResult(content)
+		}
+		var err error
+		if result, err = resultMap(v, nil, resultKey(f.cfg)); err != nil {
+			return nil, err
+		}
+	}
+	return f.truncateResult(result)
+}
@@ -0,0 +1,284 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package functiontool
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/google/uuid"
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/internal/toolinternal"
+	"google.golang.org/adk/tool"
+)
+
+// OperationStatus is the status of a background operation.
+type OperationStatus string
+
+// Statuses of the background operations.
+const (
+	OperationPending   OperationStatus = "pending"
+	OperationSucceeded OperationStatus = "succeeded"
+	OperationFailed    OperationStatus = "failed"
+	OperationCanceled  OperationStatus = "canceled"
+)
+
+// BackgroundFunc represents a Go function run in the background by a tool
+// created with NewLongRunning. It receives a context, canceled when the
+// operation is canceled, instead of the tool.Context, which must not be used
+// after the tool call returned.
+type BackgroundFunc[TArgs, TResults any] func(context.Context, TArgs) (TResults, error)
+
+// Ticket is the immediate result of a call of a tool created with
+// NewLongRunning.
+type Ticket struct {
+	OperationID string          `json:"operation_id"`
+	Status      OperationStatus `json:"status"`
+}
+
+// LongRunningResult is the result of a background operation, delivered once
+// the operation is done.
+type LongRunningResult interface {
+	// ID returns the ID of the operation, as in the ticket.
+	ID() string
+	// Status returns the current status of the operation.
+	Status() OperationStatus
+	// Done returns a channel closed when the operation is done.
+	Done() <-chan struct{}
+	// Result returns the result of the operation, or its error. It is only
+	// valid once Done is closed.
+	Result() (map[string]any, error)
+}
+
+// Operations tracks the background operations of the tools created with
+// NewLongRunning. The application polls them with Get or Wait, or receives
+// them on completion with the callback given to NewOperations, and sends the
+// final result to the agent as the function response of the call, see
+// Operation.FunctionResponse and Operation.Content.
+//
+// The operations are kept until they are deleted with Delete.
+type Operations struct {
+	onDone func(*Operation)
+
+	mu  sync.Mutex
+	ops map[string]*Operation
+}
+
+// NewOperations returns an empty set of operations. The optional onDone
+// callback is called, in the goroutine of the operation, when an operation is
+// done.
+func NewOperations(onDone func(*Operation)) *Operations {
+	return &Operations{onDone: onDone, ops: make(map[string]*Operation)}
+}
+
+// Get returns the operation with the ID.
+func (o *Operations) Get(id string) (*Operation, bool) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	op, ok := o.ops[id]
+	return op, ok
+}
+
+// Wait waits for the operation with the ID to be done.
+func (o *Operations) Wait(ctx context.Context, id string) (*Operation, error) {
+	op, ok := o.Get(id)
+	if !ok {
+		return nil, fmt.Errorf("operation %q not found", id)
+	}
+	select {
+	case <-op.Done():
+		return op, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// Delete forgets the operation with the ID, canceling it if it is pending.
+func (o *Operations) Delete(id string) {
+	o.mu.Lock()
+	op, ok := o.ops[id]
+	delete(o.ops, id)
+	o.mu.Unlock()
+	if ok {
+		op.Cancel()
+	}
+}
+
+func (o *Operations) add(op *Operation) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.ops[op.id] = op
+}
+
+// Operation is a background operation of a tool created with NewLongRunning.
+// It implements LongRunningResult.
+type Operation struct {
+	id             string
+	toolName       string
+	functionCallID string
+	cancel         context.CancelFunc
+	done           chan struct{}
+
+	mu       sync.Mutex
+	status   OperationStatus
+	result   map[string]any
+	err      error
+	canceled bool
+}
+
+var _ LongRunningResult = (*Operation)(nil)
+
+// ID implements LongRunningResult.
+func (op *Operation) ID() string { return op.id }
+
+// ToolName returns the name of the tool that started the operation.
+func (op *Operation) ToolName() string { return op.toolName }
+
+// FunctionCallID returns the ID of the function call that started the
+// operation.
+func (op *Operation) FunctionCallID() string { return op.functionCallID }
+
+// Status implements LongRunningResult.
+func (op *Operation) Status() OperationStatus {
+	op.mu.Lock()
+	defer op.mu.Unlock()
+	return op.status
+}
+
+// Done implements LongRunningResult.
+func (op *Operation) Done() <-chan struct{} { return op.done }
+
+// Result implements LongRunningResult.
+func (op *Operation) Result() (map[string]any, error) {
+	op.mu.Lock()
+	defer op.mu.Unlock()
+	return op.result, op.err
+}
+
+// Cancel cancels the context of the operation if it is pending. The
+// operation is done once its handler returned.
+func (op *Operation) Cancel() {
+	op.mu.Lock()
+	op.canceled = true
+	op.mu.Unlock()
+	op.cancel()
+}
+
+// FunctionResponse returns the final response of the function call that
+// started the operation, to send to the agent once the operation is done: the
+// result on success, and the error otherwise, structured as for the
+// tool.ToolError errors.
+func (op *Operation) FunctionResponse() *genai.FunctionResponse {
+	result, err := op.Result()
+	if err != nil {
+		result = toolinternal.ErrorResult(err)
+	}
+	return &genai.FunctionResponse{ID: op.functionCallID, Name: op.toolName, Response: result}
+}
+
+// Content returns the user content with the FunctionResponse of the
+// operation, which the application sends to the agent once the operation is
+// done, e.g. with runner.Runner.Run, so that the model gets the final result
+// of the call.
+func (op *Operation) Content() *genai.Content {
+	return &genai.Content{
+		Role:  genai.RoleUser,
+		Parts: []*genai.Part{{FunctionResponse: op.FunctionResponse()}},
+	}
+}
+
+func (op *Operation) finish(result map[string]any, err error) {
+	op.mu.Lock()
+	defer op.mu.Unlock()
+	switch {
+	case err == nil:
+		op.status = OperationSucceeded
+	case op.canceled && errors.Is(err, context.Canceled):
+		op.status = OperationCanceled
+	default:
+		op.status = OperationFailed
+	}
+	op.result, op.err = result, err
+}
+
+// NewLongRunning creates a long-running tool whose calls return a Ticket
+// right away, with the ID of an operation tracked by ops and the pending
+// status, and run the handler in the background. The final result, or error,
+// is delivered through ops.
+//
+// The declaration tells the model that the result arrives later, as for
+// Config.IsLongRunning, which is implied. The output schema, inferred or set
+// in cfg, is the one of the final result; the declared response schema is the
+// one of the ticket.
+//
+// The operations outlive the invocation: they are canceled with
+// Operation.Cancel, or Operations.Delete, not when the invocation ends.
+func NewLongRunning[TArgs, TResults any](cfg Config, ops *Operations, handler BackgroundFunc[TArgs, TResults]) (tool.Tool, error) {
+	if ops == nil {
+		return nil, fmt.Errorf("operations are required: %w", ErrInvalidArgument)
+	}
+	oschema, err := resolveOutputSchema[TResults](cfg)
+	if err != nil {
+		return nil, err
+	}
+	cfg.IsLongRunning = true
+	cfg.OutputSchema, cfg.OutputSchemaAugmenter = nil, nil
+
+	start := func(ctx tool.Context, args TArgs) (Ticket, error) {
+		parent, callID := context.Background(), ""
+		if ctx != nil {
+			parent, callID = ctx, ctx.FunctionCallID()
+		}
+		opCtx, cancel := context.WithCancel(context.WithoutCancel(parent))
+		op := &Operation{
+			id:             uuid.NewString(),
+			toolName:       cfg.Name,
+			functionCallID: callID,
+			cancel:         cancel,
+			done:           make(chan struct{}),
+			status:         OperationPending,
+		}
+		ops.add(op)
+		go func() {
+			defer close(op.done)
+			defer cancel()
+			op.finish(runBackground(opCtx, cfg.Name, oschema, resultKey(cfg), handler, args))
+			if ops.onDone != nil {
+				ops.onDone(op)
+			}
+		}()
+		return Ticket{OperationID: op.id, Status: OperationPending}, nil
+	}
+	return New(cfg, start)
+}
+
+// runBackground runs the handler of a background operation and converts its
+// result as Run does.
+func runBackground[TArgs, TResults any](ctx context.Context, name string, oschema *jsonschema.Resolved, key string, handler BackgroundFunc[TArgs, TResults], args TArgs) (result map[string]any, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = panicError(name, r)
+		}
+	}()
+	output, err := handler(ctx, args)
+	if err != nil {
+		return nil, err
+	}
+	return resultMap(output, oschema, key)
+}
@@ -0,0 +1,239 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package functiontool_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/agent/llmagent"
+	icontext "google.golang.org/adk/internal/context"
+	"google.golang.org/adk/internal/testutil"
+	"google.golang.org/adk/internal/toolinternal"
+	"google.golang.org/adk/tool"
+	"google.golang.org/adk/tool/functiontool"
+)
+
+func TestNewLongRunning(t *testing.T) {
+	type ExportArgs struct {
+		Table string `json:"table,omitempty"`
+	}
+	type ExportResult struct {
+		Rows int `json:"rows"`
+	}
+
+	finished := make(chan *functiontool.Operation, 1)
+	ops := functiontool.NewOperations(func(op *functiontool.Operation) { finished <- op })
+	release := make(chan struct{})
+	exportTool, err := functiontool.NewLongRunning(functiontool.Config{
+		Name:        "export",
+		Description: "exports a table",
+	}, ops, func(ctx context.Context, args ExportArgs) (ExportResult, error) {
+		select {
+		case <-release:
+		case <-ctx.Done():
+			return ExportResult{}, ctx.Err()
+		}
+		if args.Table == "" {
+			return ExportResult{}, errors.New("table is required")
+		}
+		return ExportResult{Rows: 42}, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !exportTool.IsLongRunning() {
+		t.Error("IsLongRunning() = false, want true")
+	}
+	decl := exportTool.(toolinternal.FunctionTool).Declaration()
+	if !strings.Contains(decl.Description, "NOTE: This is a long-running operation.") {
+		t.Errorf("Declaration().Description = %q, want the long-running note", decl.Description)
+	}
+
+	// The invocation ends before the operation does.
+	invocationCtx, cancelInvocation := context.WithCancel(t.Context())
+	run := func(args map[string]any) *functiontool.Operation {
+		t.Helper()
+		ctx := toolinternal.NewToolContext(icontext.NewInvocationContext(invocationCtx, icontext.InvocationContextParams{}), "call1", nil)
+		got, err := exportTool.(toolinternal.FunctionTool).Run(ctx, args)
+		if err != nil {
+			t.Fatalf("Run() error = %v", err)
+		}
+		if got["status"] != string(functiontool.OperationPending) {
+			t.Fatalf("Run() = %v, want a pending ticket", got)
+		}
+		op, ok := ops.Get(got["operation_id"].(string))
+		if !ok {
+			t.Fatalf("operation of ticket %v not found", got)
+		}
+		return op
+	}
+
+	op := run(map[string]any{"table": "orders"})
+	cancelInvocation()
+	if got := op.Status(); got != functiontool.OperationPending {
+		t.Errorf("Status() = %q before the handler returned, want pending", got)
+	}
+	close(release)
+	if got := <-finished; got != op {
+		t.Errorf("completion callback got operation %q, want %q", got.ID(), op.ID())
+	}
+	<-op.Done()
+	if got := op.Status(); got != functiontool.OperationSucceeded {
+		t.Errorf("Status() = %q, want succeeded", got)
+	}
+	want := &genai.FunctionResponse{ID: "call1", Name: "export", Response: map[string]any{"rows": 42.0}}
+	if diff := cmp.Diff(want, op.FunctionResponse()); diff != "" {
+		t.Errorf("FunctionResponse() mismatch (-want +got):\n%s", diff)
+	}
+
+	// Errors are delivered with the failed status.
+	op = run(map[string]any{})
+	waited, err := ops.Wait(t.Context(), op.ID())
+	if err != nil {
+		t.Fatalf("Wait() error = %v", err)
+	}
+	<-finished
+	if _, err := waited.Result(); waited.Status() != functiontool.OperationFailed || err == nil {
+		t.Errorf("failed operation = (%q, %v), want failed with an error", waited.Status(), err)
+	}
+	if got := waited.FunctionResponse().Response; got["error"] != "table is required" {
+		t.Errorf("FunctionResponse().Response = %v, want the error", got)
+	}
+	ops.Delete(op.ID())
+	if _, ok := ops.Get(op.ID()); ok {
+		t.Error("Get() found a deleted operation")
+	}
+}
+
+func TestNewLongRunning_Cancel(t *testing.T) {
+	type Args struct{}
+	ops := functiontool.NewOperations(nil)
+	waitTool, err := functiontool.NewLongRunning(functiontool.Config{Name: "wait"}, ops, func(ctx context.Context, _ Args) (string, error) {
+		<-ctx.Done()
+		return "", ctx.Err()
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := waitTool.(toolinternal.FunctionTool).Run(nil, map[string]any{})
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	op, ok := ops.Get(got["operation_id"].(string))
+	if !ok {
+		t.Fatalf("operation of ticket %v not found", got)
+	}
+	op.Cancel()
+	select {
+	case <-op.Done():
+	case <-time.After(5 * time.Second):
+		t.Fatal("canceled operation not done")
+	}
+	if _, err := op.Result(); op.Status() != functiontool.OperationCanceled || !errors.Is(err, context.Canceled) {
+		t.Errorf("canceled operation = (%q, %v), want canceled", op.Status(), err)
+	}
+
+	if _, err := functiontool.NewLongRunning(functiontool.Config{Name: "wait"}, nil, func(context.Context, Args) (string, error) { return "", nil }); !errors.Is(err, functiontool.ErrInvalidArgument) {
+		t.Errorf("NewLongRunning() without operations error = %v, want ErrInvalidArgument", err)
+	}
+}
+
+func TestNewLongRunning_Agent(t *testing.T) {
+	type ApprovalArgs struct {
+		Amount int `json:"amount"`
+	}
+	type ApprovalResult struct {
+		Approved bool `json:"approved"`
+	}
+
+	approved := make(chan bool)
+	ops := functiontool.NewOperations(nil)
+	approvalTool, err := functiontool.NewLongRunning(functiontool.Config{
+		Name:        "request_approval",
+		Description: "asks a manager to approve an expense",
+	}, ops, func(ctx context.Context, _ ApprovalArgs) (ApprovalResult, error) {
+		return ApprovalResult{Approved: <-approved}, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	mockModel := &testutil.MockModel{
+		Responses: []*genai.Content{
+			genai.NewContentFromFunctionCall("request_approval", map[string]any{"amount": 120}, genai.RoleModel),
+			genai.NewContentFromText("The approval is pending.", genai.RoleModel),
+			genai.NewContentFromText("The expense is approved.", genai.RoleModel),
+		},
+	}
+	a, err := llmagent.New(llmagent.Config{Name: "expenses", Model: mockModel, Tools: []tool.Tool{approvalTool}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	runner := testutil.NewTestAgentRunner(t, a)
+
+	events, err := testutil.CollectEvents(runner.Run(t, "session", "file my expense"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The function call event marks the call as long-running, and the
+	// ticket tells the model that it is pending.
+	var callID string
+	var ticket map[string]any
+	for _, ev := range events {
+		for _, p := range ev.Content.Parts {
+			if p.FunctionCall != nil {
+				callID = p.FunctionCall.ID
+				if diff := cmp.Diff([]string{callID}, ev.LongRunningToolIDs); diff != "" {
+					t.Errorf("LongRunningToolIDs mismatch (-want +got):\n%s", diff)
+				}
+			}
+			if p.FunctionResponse != nil {
+				ticket = p.FunctionResponse.Response
+			}
+		}
+	}
+	if ticket["status"] != string(functiontool.OperationPending) {
+		t.Fatalf("function response = %v, want a pending ticket", ticket)
+	}
+
+	// The application injects the final response once the operation is done.
+	approved <- true
+	op, err := ops.Wait(t.Context(), ticket["operation_id"].(string))
+	if err != nil {
+		t.Fatalf("Wait() error = %v", err)
+	}
+	texts, err := testutil.CollectTextParts(runner.RunContent(t, "session", op.Content()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff([]string{"The expense is approved."}, texts); diff != "" {
+		t.Errorf("texts mismatch (-want +got):\n%s", diff)
+	}
+	if got := op.FunctionCallID(); got != callID {
+		t.Errorf("FunctionCallID() = %q, want %q", got, callID)
+	}
+	// The client-generated IDs are not sent to the model.
+	contents := mockModel.Requests[len(mockModel.Requests)-1].Contents
+	want := &genai.FunctionResponse{Name: "request_approval", Response: map[string]any{"approved": true}}
+	if diff := cmp.Diff(want, contents[len(contents)-1].Parts[0].FunctionResponse); diff != "" {
+		t.Errorf("final function response mismatch (-want +got):\n%s", diff)
+	}
+}
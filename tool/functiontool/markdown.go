@@ -0,0 +1,167 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package functiontool
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// ResultEncoding controls how the result of a FunctionTool is presented to
+// the model.
+type ResultEncoding string
+
+const (
+	// JSONEncoding returns the result as structured JSON only. It is the
+	// default.
+	JSONEncoding ResultEncoding = "json"
+	// MarkdownTableEncoding additionally renders a result that is a list of
+	// records (JSON objects) as a Markdown table, stored under the
+	// "markdown_table" key of the function response next to the structured
+	// result. Records may have different fields; missing values are left
+	// empty. If the result is not a non-empty list of records, or has more
+	// columns than Config.MaxTableColumns, only the JSON result is returned.
+	MarkdownTableEncoding ResultEncoding = "markdown_table"
+)
+
+// defaultMaxTableColumns is the column limit used when Config.MaxTableColumns
+// is not set.
+const defaultMaxTableColumns = 12
+
+// markdownTableKey is the function response key holding the rendered table.
+const markdownTableKey = "markdown_table"
+
+// markdownTable renders v as a Markdown table. It returns false if v is not a
+// non-empty list of JSON objects or if there are more than maxColumns columns.
+func markdownTable(v any, maxColumns int) (string, bool) {
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return "", false
+	}
+	var records []json.RawMessage
+	if err := json.Unmarshal(raw, &records); err != nil || len(records) == 0 {
+		return "", false
+	}
+
+	// Columns are the union of the record fields, in the order of their first
+	// appearance.
+	var columns []string
+	seen := make(map[string]bool)
+	rows := make([]map[string]json.RawMessage, 0, len(records))
+	for _, rec := range records {
+		keys, err := objectKeys(rec)
+		if err != nil {
+			return "", false
+		}
+		var row map[string]json.RawMessage
+		if err := json.Unmarshal(rec, &row); err != nil {
+			return "", false
+		}
+		for _, k := range keys {
+			if !seen[k] {
+				seen[k] = true
+				columns = append(columns, k)
+			}
+		}
+		rows = append(rows, row)
+	}
+	if len(columns) == 0 || len(columns) > maxColumns {
+		return "", false
+	}
+
+	var sb strings.Builder
+	writeRow := func(cells []string) {
+		sb.WriteString("|")
+		for _, c := range cells {
+			sb.WriteString(" ")
+			sb.WriteString(c)
+			sb.WriteString(" |")
+		}
+		sb.WriteString("\n")
+	}
+	header := make([]string, len(columns))
+	separator := make([]string, len(columns))
+	for i, c := range columns {
+		header[i] = escapeCell(c)
+		separator[i] = "---"
+	}
+	writeRow(header)
+	writeRow(separator)
+	for _, row := range rows {
+		cells := make([]string, len(columns))
+		for i, c := range columns {
+			if val, ok := row[c]; ok {
+				cells[i] = escapeCell(cellText(val))
+			}
+		}
+		writeRow(cells)
+	}
+	return sb.String(), true
+}
+
+// objectKeys returns the keys of a JSON object in their original order.
+func objectKeys(raw json.RawMessage) ([]string, error) {
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+	if delim, ok := tok.(json.Delim); !ok || delim != '{' {
+		return nil, fmt.Errorf("not a JSON object")
+	}
+	var keys []string
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, err
+		}
+		key, ok := tok.(string)
+		if !ok {
+			return nil, fmt.Errorf("unexpected key token %v", tok)
+		}
+		keys = append(keys, key)
+		// Skip the value.
+		var skip json.RawMessage
+		if err := dec.Decode(&skip); err != nil {
+			return nil, err
+		}
+	}
+	return keys, nil
+}
+
+// cellText returns the text of a table cell. Strings are used verbatim, null
+// is left empty and any other value is rendered as compact JSON.
+func cellText(val json.RawMessage) string {
+	var s string
+	if err := json.Unmarshal(val, &s); err == nil {
+		return s
+	}
+	if string(val) == "null" {
+		return ""
+	}
+	var buf bytes.Buffer
+	if err := json.Compact(&buf, val); err != nil {
+		return string(val)
+	}
+	return buf.String()
+}
+
+var cellReplacer = strings.NewReplacer("|", `\|`, "\r\n", " ", "\n", " ", "\r", " ")
+
+func escapeCell(s string) string {
+	return cellReplacer.Replace(s)
+}
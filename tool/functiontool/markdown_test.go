@@ -0,0 +1,119 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package functiontool_test
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"google.golang.org/adk/internal/toolinternal"
+	"google.golang.org/adk/tool"
+	"google.golang.org/adk/tool/functiontool"
+)
+
+func TestFunctionTool_MarkdownTableEncoding(t *testing.T) {
+	type Args struct{}
+	type Book struct {
+		Title  string   `json:"title"`
+		Author string   `json:"author,omitempty"`
+		Year   int      `json:"year"`
+		Tags   []string `json:"tags,omitempty"`
+	}
+
+	for _, tc := range []struct {
+		name       string
+		maxColumns int
+		books      []Book
+		want       string // empty if no table is expected.
+	}{
+		{
+			name: "records",
+			books: []Book{
+				{Title: "Dune", Author: "Herbert", Year: 1965},
+				{Title: "A | B", Year: 2001, Tags: []string{"x", "y"}},
+			},
+			want: "| title | author | year | tags |\n" +
+				"| --- | --- | --- | --- |\n" +
+				"| Dune | Herbert | 1965 |  |\n" +
+				"| A \\| B |  | 2001 | [\"x\",\"y\"] |\n",
+		},
+		{
+			name:       "too many columns",
+			maxColumns: 2,
+			books:      []Book{{Title: "Dune", Author: "Herbert", Year: 1965}},
+		},
+		{
+			name:  "empty list",
+			books: []Book{},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			books := tc.books
+			listBooks, err := functiontool.New(functiontool.Config{
+				Name:            "list_books",
+				Description:     "lists books",
+				ResultEncoding:  functiontool.MarkdownTableEncoding,
+				MaxTableColumns: tc.maxColumns,
+			}, func(ctx tool.Context, args Args) ([]Book, error) {
+				return books, nil
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := listBooks.(toolinternal.FunctionTool).Run(nil, map[string]any{})
+			if err != nil {
+				t.Fatalf("Run() failed: %v", err)
+			}
+			if _, ok := got["result"]; !ok {
+				t.Errorf("Run() = %v, want the structured result to be kept", got)
+			}
+			table, ok := got["markdown_table"]
+			if tc.want == "" {
+				if ok {
+					t.Errorf("Run() returned a table %q, want JSON only", table)
+				}
+				return
+			}
+			if diff := cmp.Diff(tc.want, table); diff != "" {
+				t.Errorf("Run() table mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestFunctionTool_MarkdownTableEncoding_ObjectResult(t *testing.T) {
+	type Args struct{}
+	type Result struct {
+		Count int `json:"count"`
+	}
+	countTool, err := functiontool.New(functiontool.Config{
+		Name:           "count",
+		Description:    "counts",
+		ResultEncoding: functiontool.MarkdownTableEncoding,
+	}, func(ctx tool.Context, args Args) (Result, error) {
+		return Result{Count: 3}, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := countTool.(toolinternal.FunctionTool).Run(nil, map[string]any{})
+	if err != nil {
+		t.Fatalf("Run() failed: %v", err)
+	}
+	if diff := cmp.Diff(map[string]any{"count": float64(3)}, got); diff != "" {
+		t.Errorf("Run() mismatch (-want +got):\n%s", diff)
+	}
+}
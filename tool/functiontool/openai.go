@@ -0,0 +1,94 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package functiontool
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+
+	"google.golang.org/adk/internal/toolinternal"
+	"google.golang.org/adk/internal/utils"
+	"google.golang.org/adk/tool"
+)
+
+// openAIFunctionNameRegexp matches the function names accepted by OpenAI.
+var openAIFunctionNameRegexp = regexp.MustCompile(`^[a-zA-Z0-9_-]{1,64}$`)
+
+type openAITool struct {
+	Type     string         `json:"type"`
+	Function openAIFunction `json:"function"`
+}
+
+type openAIFunction struct {
+	Name        string         `json:"name"`
+	Description string         `json:"description,omitempty"`
+	Parameters  map[string]any `json:"parameters"`
+}
+
+// OpenAIDeclaration returns the declaration of the function tool in the
+// format of the OpenAI chat completions API:
+//
+//	{"type": "function", "function": {"name": ..., "description": ..., "parameters": {...}}}
+//
+// The parameters are the input schema of the tool, in the JSON Schema
+// dialect of OpenAI: the Gemini schemas are translated (e.g. the upper-case
+// types and "nullable"), "const" becomes a single value "enum", "oneOf"
+// becomes "anyOf", and the keywords OpenAI rejects, e.g. "$schema" or "not",
+// are removed. Tools without parameters get an empty object schema.
+//
+// It fails for the tools that are not function tools, e.g. the Gemini
+// built-in tools, and for the names OpenAI rejects.
+func OpenAIDeclaration(t tool.Tool) (json.RawMessage, error) {
+	funcTool, ok := t.(toolinternal.FunctionTool)
+	if !ok || funcTool.Declaration() == nil {
+		return nil, fmt.Errorf("tool %q is not a function tool: %w", t.Name(), ErrInvalidArgument)
+	}
+	decl := funcTool.Declaration()
+	if !openAIFunctionNameRegexp.MatchString(decl.Name) {
+		return nil, fmt.Errorf("invalid OpenAI function name %q, want at most 64 letters, digits, underscores or dashes: %w", decl.Name, ErrInvalidArgument)
+	}
+
+	var schema any = decl.ParametersJsonSchema
+	if schema == nil && decl.Parameters != nil {
+		schema = decl.Parameters
+	}
+	params := map[string]any{}
+	if schema != nil {
+		b, err := json.Marshal(schema)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode the parameters of tool %q: %w", decl.Name, err)
+		}
+		if err := json.Unmarshal(b, &params); err != nil {
+			return nil, fmt.Errorf("parameters of tool %q are not an object schema: %w", decl.Name, err)
+		}
+		params = utils.OpenAISchema(params)
+	}
+	if params["type"] == nil {
+		params["type"] = "object"
+	}
+	if params["type"] == "object" && params["properties"] == nil {
+		params["properties"] = map[string]any{}
+	}
+
+	return json.Marshal(openAITool{
+		Type: "function",
+		Function: openAIFunction{
+			Name:        decl.Name,
+			Description: decl.Description,
+			Parameters:  params,
+		},
+	})
+}
@@ -0,0 +1,197 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package functiontool_test
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/jsonschema-go/jsonschema"
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/model"
+	"google.golang.org/adk/tool"
+	"google.golang.org/adk/tool/functiontool"
+	"google.golang.org/adk/tool/geminitool"
+)
+
+// genaiSchemaTool is a function tool declared with a Gemini schema.
+type genaiSchemaTool struct{}
+
+func (genaiSchemaTool) Name() string        { return "lookup" }
+func (genaiSchemaTool) Description() string { return "looks up a word" }
+func (genaiSchemaTool) IsLongRunning() bool { return false }
+func (genaiSchemaTool) ProcessRequest(tool.Context, *model.LLMRequest) error {
+	return nil
+}
+
+func (genaiSchemaTool) Run(tool.Context, any) (map[string]any, error) {
+	return nil, nil
+}
+
+func (genaiSchemaTool) Declaration() *genai.FunctionDeclaration {
+	return &genai.FunctionDeclaration{
+		Name:        "lookup",
+		Description: "looks up a word",
+		Parameters: &genai.Schema{
+			Type: genai.TypeObject,
+			Properties: map[string]*genai.Schema{
+				"word":     {Type: genai.TypeString, Example: "hello"},
+				"language": {Type: genai.TypeString, Nullable: genai.Ptr(true), Enum: []string{"en", "fr"}},
+			},
+			PropertyOrdering: []string{"word", "language"},
+			Required:         []string{"word"},
+		},
+	}
+}
+
+func TestOpenAIDeclaration(t *testing.T) {
+	type Location struct {
+		City string `json:"city"`
+	}
+	type WeatherArgs struct {
+		Location *Location `json:"location"`
+		Units    string    `json:"units,omitempty" jsonschema:"description=The units,enum=metric|imperial"`
+		Days     []int     `json:"days,omitempty"`
+	}
+	weatherTool, err := functiontool.New(functiontool.Config{
+		Name:        "get_weather",
+		Description: "returns the weather",
+	}, func(tool.Context, WeatherArgs) (string, error) { return "", nil })
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	type SearchArgs map[string]any
+	searchTool, err := functiontool.New(functiontool.Config{
+		Name: "search",
+		InputSchema: &jsonschema.Schema{
+			Schema: "https://json-schema.org/draft/2020-12/schema",
+			Type:   "object",
+			Properties: map[string]*jsonschema.Schema{
+				"kind":  {Const: jsonschema.Ptr[any]("web")},
+				"query": {OneOf: []*jsonschema.Schema{{Type: "string"}, {Type: "array", Items: &jsonschema.Schema{Type: "string"}}}},
+				"tags":  {Type: "object", AdditionalProperties: &jsonschema.Schema{Type: "string", Deprecated: true}},
+				"page":  {Type: "integer", Not: &jsonschema.Schema{Const: jsonschema.Ptr[any](0)}},
+			},
+		},
+	}, func(tool.Context, SearchArgs) (string, error) { return "", nil })
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	timeTool, err := functiontool.NewNoArgs(functiontool.Config{Name: "now"}, func(tool.Context) (string, error) { return "", nil })
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		tool tool.Tool
+		want string
+		// args are valid arguments for the exported schema.
+		args map[string]any
+	}{
+		{
+			name: "inferred schema",
+			tool: weatherTool,
+			want: `{"type":"function","function":{"name":"get_weather","description":"returns the weather","parameters":{
+				"type":"object","additionalProperties":false,
+				"properties":{
+					"location":{"type":["null","object"],"required":["city"],"additionalProperties":false,"properties":{"city":{"type":"string"}}},
+					"units":{"type":"string","description":"The units","enum":["metric","imperial"]},
+					"days":{"type":"array","items":{"type":"integer"}}}}}}`,
+			args: map[string]any{"location": map[string]any{"city": "Paris"}, "units": "metric"},
+		},
+		{
+			name: "custom schema",
+			tool: searchTool,
+			want: `{"type":"function","function":{"name":"search","parameters":{
+				"type":"object",
+				"properties":{
+					"kind":{"enum":["web"]},
+					"query":{"anyOf":[{"type":"string"},{"type":"array","items":{"type":"string"}}]},
+					"tags":{"type":"object","additionalProperties":{"type":"string"}},
+					"page":{"type":"integer"}}}}}`,
+			args: map[string]any{"kind": "web", "query": []any{"go"}, "tags": map[string]any{"a": "b"}},
+		},
+		{
+			name: "gemini schema",
+			tool: genaiSchemaTool{},
+			want: `{"type":"function","function":{"name":"lookup","description":"looks up a word","parameters":{
+				"type":"object","required":["word"],
+				"properties":{
+					"word":{"type":"string"},
+					"language":{"type":["string","null"],"enum":["en","fr",null]}}}}}`,
+			args: map[string]any{"word": "hello", "language": nil},
+		},
+		{
+			name: "no parameters",
+			tool: timeTool,
+			want: `{"type":"function","function":{"name":"now","parameters":{"type":"object","properties":{}}}}`,
+			args: map[string]any{},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := functiontool.OpenAIDeclaration(tc.tool)
+			if err != nil {
+				t.Fatalf("OpenAIDeclaration() error = %v", err)
+			}
+			var gotMap, wantMap map[string]any
+			if err := json.Unmarshal(got, &gotMap); err != nil {
+				t.Fatal(err)
+			}
+			if err := json.Unmarshal([]byte(tc.want), &wantMap); err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(wantMap, gotMap); diff != "" {
+				t.Errorf("OpenAIDeclaration() mismatch (-want +got):\n%s", diff)
+			}
+
+			// The parameters are a valid JSON schema, accepting the arguments.
+			var decl struct {
+				Function struct {
+					Parameters *jsonschema.Schema `json:"parameters"`
+				} `json:"function"`
+			}
+			if err := json.Unmarshal(got, &decl); err != nil {
+				t.Fatal(err)
+			}
+			resolved, err := decl.Function.Parameters.Resolve(nil)
+			if err != nil {
+				t.Fatalf("Resolve() of the parameters error = %v", err)
+			}
+			if err := resolved.Validate(tc.args); err != nil {
+				t.Errorf("Validate(%v) error = %v", tc.args, err)
+			}
+		})
+	}
+}
+
+func TestOpenAIDeclaration_Errors(t *testing.T) {
+	type Args struct{}
+	badName, err := functiontool.New(functiontool.Config{Name: "get weather"}, func(tool.Context, Args) (string, error) { return "", nil })
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tl := range []tool.Tool{badName, geminitool.GoogleSearch{}} {
+		if _, err := functiontool.OpenAIDeclaration(tl); !errors.Is(err, functiontool.ErrInvalidArgument) {
+			t.Errorf("OpenAIDeclaration(%q) error = %v, want ErrInvalidArgument", tl.Name(), err)
+		}
+	}
+}
@@ -0,0 +1,88 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package functiontool
+
+import (
+	"context"
+	"fmt"
+
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/agent"
+	"google.golang.org/adk/internal/toolinternal"
+	"google.golang.org/adk/session"
+	"google.golang.org/adk/tool"
+)
+
+// RunOptions configures RunToolCalls.
+type RunOptions struct {
+	// MaxParallelism is the maximum number of calls run at once. Zero or
+	// less means no limit.
+	MaxParallelism int
+	// AbortOnError cancels the context of the running calls, and skips the
+	// calls not started yet, once a call fails.
+	AbortOnError bool
+}
+
+// ToolResult is the outcome of a function call run by RunToolCalls.
+type ToolResult struct {
+	Call *genai.FunctionCall
+	// Result is the result of the call, nil if it failed.
+	Result map[string]any
+	// Actions are the actions of the call, e.g. its state changes.
+	Actions *session.EventActions
+	Err     error
+}
+
+// RunToolCalls runs the independent function calls concurrently, e.g. the
+// calls of one model response, with the tools keyed by name. It returns the
+// result of each call, in the order of calls.
+//
+// A panic in a tool fails its call only. The calls are canceled with ctx,
+// and the calls skipped or canceled because of it, or of AbortOnError, fail
+// with an error wrapping context.Canceled.
+func RunToolCalls(ctx agent.InvocationContext, tools map[string]tool.Tool, calls []*genai.FunctionCall, opts RunOptions) []ToolResult {
+	results := make([]ToolResult, len(calls))
+	errs := toolinternal.RunParallel(ctx, calls, toolinternal.ParallelOptions{
+		MaxParallelism: opts.MaxParallelism,
+		AbortOnError:   opts.AbortOnError,
+	}, func(runCtx context.Context, i int) error {
+		res := &results[i]
+		res.Actions = &session.EventActions{StateDelta: make(map[string]any)}
+		var err error
+		res.Result, err = runToolCall(runCtx, ctx, tools, calls[i], res.Actions)
+		return err
+	})
+	for i, call := range calls {
+		results[i].Call = call
+		results[i].Err = errs[i]
+	}
+	return results
+}
+
+// runToolCall runs the function call with a tool context canceled with ctx.
+func runToolCall(ctx context.Context, invCtx agent.InvocationContext, tools map[string]tool.Tool, call *genai.FunctionCall, actions *session.EventActions) (map[string]any, error) {
+	t, ok := tools[call.Name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", tool.ErrToolNotFound, call.Name)
+	}
+	funcTool, ok := t.(toolinternal.FunctionTool)
+	if !ok {
+		return nil, fmt.Errorf("tool %q is not a function tool", call.Name)
+	}
+	toolCtx := toolinternal.WithFunctionCall(toolinternal.NewToolContext(invCtx, call.ID, actions), call)
+	toolCtx = toolinternal.WithContext(toolCtx, ctx)
+	return funcTool.Run(toolCtx, call.Args)
+}
@@ -0,0 +1,121 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package functiontool_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"google.golang.org/genai"
+
+	icontext "google.golang.org/adk/internal/context"
+	"google.golang.org/adk/tool"
+	"google.golang.org/adk/tool/functiontool"
+)
+
+func TestRunToolCalls(t *testing.T) {
+	type Args struct {
+		N     int    `json:"n"`
+		Sleep int    `json:"sleep,omitempty"`
+		Fail  string `json:"fail,omitempty"`
+	}
+	var running, maxRunning atomic.Int32
+	square, err := functiontool.New(functiontool.Config{Name: "square"}, func(ctx tool.Context, args Args) (int, error) {
+		n := running.Add(1)
+		defer running.Add(-1)
+		for {
+			m := maxRunning.Load()
+			if n <= m || maxRunning.CompareAndSwap(m, n) {
+				break
+			}
+		}
+		switch args.Fail {
+		case "error":
+			return 0, errors.New("failed")
+		case "panic":
+			panic("boom")
+		}
+		select {
+		case <-time.After(time.Duration(args.Sleep) * time.Millisecond):
+		case <-ctx.Done():
+			return 0, ctx.Err()
+		}
+		return args.N * args.N, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tools := map[string]tool.Tool{"square": square}
+	call := func(id string, args map[string]any) *genai.FunctionCall {
+		return &genai.FunctionCall{ID: id, Name: "square", Args: args}
+	}
+	invCtx := icontext.NewInvocationContext(t.Context(), icontext.InvocationContextParams{})
+
+	t.Run("results in call order", func(t *testing.T) {
+		maxRunning.Store(0)
+		results := functiontool.RunToolCalls(invCtx, tools, []*genai.FunctionCall{
+			call("1", map[string]any{"n": 1, "sleep": 30}),
+			call("2", map[string]any{"n": 2, "fail": "panic"}),
+			call("3", map[string]any{"n": 3, "sleep": 10}),
+			{ID: "4", Name: "unknown"},
+			call("5", map[string]any{"n": 5}),
+		}, functiontool.RunOptions{MaxParallelism: 2})
+		if len(results) != 5 {
+			t.Fatalf("got %d results, want 5", len(results))
+		}
+		for i, want := range []any{1, nil, 9, nil, 25} {
+			res := results[i]
+			if res.Call.ID != string(rune('1'+i)) {
+				t.Errorf("result %d is of call %q", i, res.Call.ID)
+			}
+			if want == nil {
+				if res.Err == nil {
+					t.Errorf("result %d error = nil, want an error", i)
+				}
+				continue
+			}
+			if res.Err != nil || res.Result["result"] != want {
+				t.Errorf("result %d = (%v, %v), want %v", i, res.Result, res.Err, want)
+			}
+		}
+		if !strings.Contains(results[1].Err.Error(), `panic in tool "square"`) {
+			t.Errorf("result of the panicking call error = %v, want the panic", results[1].Err)
+		}
+		if got := maxRunning.Load(); got > 2 {
+			t.Errorf("%d calls ran at once, want at most 2", got)
+		}
+	})
+
+	t.Run("abort on error", func(t *testing.T) {
+		results := functiontool.RunToolCalls(invCtx, tools, []*genai.FunctionCall{
+			call("1", map[string]any{"n": 1, "sleep": 10_000}),
+			call("2", map[string]any{"n": 2, "fail": "error"}),
+			call("3", map[string]any{"n": 3}),
+		}, functiontool.RunOptions{MaxParallelism: 2, AbortOnError: true})
+		if !errors.Is(results[0].Err, context.Canceled) {
+			t.Errorf("result of the running call error = %v, want canceled", results[0].Err)
+		}
+		if results[1].Err == nil || results[1].Err.Error() != "failed" {
+			t.Errorf("result of the failed call error = %v, want failed", results[1].Err)
+		}
+		if !errors.Is(results[2].Err, context.Canceled) {
+			t.Errorf("result of the skipped call error = %v, want canceled", results[2].Err)
+		}
+	})
+}
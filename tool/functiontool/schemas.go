@@ -0,0 +1,153 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package functiontool
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/google/jsonschema-go/jsonschema"
+
+	"google.golang.org/adk/tool"
+)
+
+// ToolSchema is the definition of a tool by its JSON schemas, as exported by
+// other agent frameworks.
+//
+// It unmarshals from the common JSON forms of tool definitions: a flat
+// object with "name", "description" and "parameters" (or "inputSchema", as
+// in MCP), optionally wrapped as {"type": "function", "function": {...}}.
+type ToolSchema struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	// InputSchema is the schema of the arguments.
+	InputSchema *jsonschema.Schema `json:"parameters,omitempty"`
+	// OutputSchema is the schema of the result. Optional.
+	OutputSchema *jsonschema.Schema `json:"outputSchema,omitempty"`
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *ToolSchema) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		Type         string             `json:"type"`
+		Function     *ToolSchema        `json:"function"`
+		Name         string             `json:"name"`
+		Description  string             `json:"description"`
+		Parameters   *jsonschema.Schema `json:"parameters"`
+		InputSchema  *jsonschema.Schema `json:"inputSchema"`
+		OutputSchema *jsonschema.Schema `json:"outputSchema"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	if raw.Function != nil {
+		*s = *raw.Function
+		return nil
+	}
+	*s = ToolSchema{
+		Name:         raw.Name,
+		Description:  raw.Description,
+		InputSchema:  raw.Parameters,
+		OutputSchema: raw.OutputSchema,
+	}
+	if s.InputSchema == nil {
+		s.InputSchema = raw.InputSchema
+	}
+	return nil
+}
+
+// SchemaHandler handles the calls of a tool loaded with LoadToolsFromSchemas.
+// It receives the arguments validated against the input schema.
+type SchemaHandler func(ctx tool.Context, args map[string]any) (map[string]any, error)
+
+// UnmatchedError reports the schemas and the handlers that could not be
+// matched by name in LoadToolsFromSchemas.
+type UnmatchedError struct {
+	// MissingHandlers are the names of the schemas without a handler.
+	MissingHandlers []string
+	// UnusedHandlers are the names of the handlers without a schema.
+	UnusedHandlers []string
+}
+
+func (e *UnmatchedError) Error() string {
+	var msgs []string
+	if len(e.MissingHandlers) > 0 {
+		msgs = append(msgs, "no handler for tools: "+strings.Join(e.MissingHandlers, ", "))
+	}
+	if len(e.UnusedHandlers) > 0 {
+		msgs = append(msgs, "no schema for handlers: "+strings.Join(e.UnusedHandlers, ", "))
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// LoadToolsFromSchemas creates a tool for each schema, calling the handler
+// registered under the name of the schema. The schemas are used as they are,
+// without inference from Go types; a schema without input schema takes an
+// empty object.
+//
+// Every schema must have a handler and every handler a schema; otherwise an
+// *UnmatchedError lists the unmatched names and no tool is returned.
+func LoadToolsFromSchemas(schemas []ToolSchema, handlers map[string]SchemaHandler) ([]tool.Tool, error) {
+	unmatched := &UnmatchedError{}
+	seen := make(map[string]bool, len(schemas))
+	for _, s := range schemas {
+		if s.Name == "" {
+			return nil, errors.New("error loading tools: a tool schema has no name")
+		}
+		if seen[s.Name] {
+			return nil, fmt.Errorf("error loading tools: duplicate tool %q", s.Name)
+		}
+		seen[s.Name] = true
+		if handlers[s.Name] == nil {
+			unmatched.MissingHandlers = append(unmatched.MissingHandlers, s.Name)
+		}
+	}
+	for _, name := range slices.Sorted(maps.Keys(handlers)) {
+		if !seen[name] {
+			unmatched.UnusedHandlers = append(unmatched.UnusedHandlers, name)
+		}
+	}
+	if len(unmatched.MissingHandlers) > 0 || len(unmatched.UnusedHandlers) > 0 {
+		return nil, fmt.Errorf("error loading tools: %w", unmatched)
+	}
+
+	tools := make([]tool.Tool, 0, len(schemas))
+	for _, s := range schemas {
+		inputSchema := s.InputSchema
+		if inputSchema == nil {
+			inputSchema = &jsonschema.Schema{Type: "object"}
+		}
+		outputSchema := s.OutputSchema
+		if outputSchema == nil {
+			// Any object is a valid result.
+			outputSchema = &jsonschema.Schema{Type: "object"}
+		}
+		t, err := New(Config{
+			Name:         s.Name,
+			Description:  s.Description,
+			InputSchema:  inputSchema,
+			OutputSchema: outputSchema,
+		}, Func[map[string]any, map[string]any](handlers[s.Name]))
+		if err != nil {
+			return nil, fmt.Errorf("error loading tool %q: %w", s.Name, err)
+		}
+		tools = append(tools, t)
+	}
+	return tools, nil
+}
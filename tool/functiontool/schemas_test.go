@@ -0,0 +1,168 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package functiontool_test
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/jsonschema-go/jsonschema"
+
+	"google.golang.org/adk/internal/toolinternal"
+	"google.golang.org/adk/tool"
+	"google.golang.org/adk/tool/functiontool"
+)
+
+const toolSchemasJSON = `[
+  {
+    "type": "function",
+    "function": {
+      "name": "get_weather",
+      "description": "Returns the weather in a city.",
+      "parameters": {
+        "type": "object",
+        "properties": {"city": {"type": "string"}},
+        "required": ["city"]
+      }
+    }
+  },
+  {
+    "name": "add",
+    "description": "Adds two numbers.",
+    "inputSchema": {
+      "type": "object",
+      "properties": {"a": {"type": "number"}, "b": {"type": "number"}},
+      "required": ["a", "b"]
+    }
+  },
+  {"name": "ping"}
+]`
+
+func TestLoadToolsFromSchemas(t *testing.T) {
+	var schemas []functiontool.ToolSchema
+	if err := json.Unmarshal([]byte(toolSchemasJSON), &schemas); err != nil {
+		t.Fatalf("json.Unmarshal() failed: %v", err)
+	}
+
+	tools, err := functiontool.LoadToolsFromSchemas(schemas, map[string]functiontool.SchemaHandler{
+		"get_weather": func(_ tool.Context, args map[string]any) (map[string]any, error) {
+			return map[string]any{"city": args["city"], "forecast": "sunny"}, nil
+		},
+		"add": func(_ tool.Context, args map[string]any) (map[string]any, error) {
+			return map[string]any{"sum": args["a"].(float64) + args["b"].(float64)}, nil
+		},
+		"ping": func(tool.Context, map[string]any) (map[string]any, error) {
+			return map[string]any{"pong": true}, nil
+		},
+	})
+	if err != nil {
+		t.Fatalf("LoadToolsFromSchemas() failed: %v", err)
+	}
+	if len(tools) != 3 {
+		t.Fatalf("LoadToolsFromSchemas() returned %d tools, want 3", len(tools))
+	}
+
+	// The declarations use the schemas as they are.
+	weather := tools[0].(toolinternal.FunctionTool)
+	if got, want := weather.Name(), "get_weather"; got != want {
+		t.Errorf("Name() = %q, want %q", got, want)
+	}
+	if got, want := weather.Description(), "Returns the weather in a city."; got != want {
+		t.Errorf("Description() = %q, want %q", got, want)
+	}
+	wantSchema := &jsonschema.Schema{
+		Type:       "object",
+		Properties: map[string]*jsonschema.Schema{"city": {Type: "string"}},
+		Required:   []string{"city"},
+	}
+	if diff := cmp.Diff(wantSchema, weather.Declaration().ParametersJsonSchema); diff != "" {
+		t.Errorf("ParametersJsonSchema mismatch (-want +got):\n%s", diff)
+	}
+
+	tests := []struct {
+		name    string
+		tool    tool.Tool
+		args    map[string]any
+		want    map[string]any
+		wantErr bool
+	}{
+		{
+			name: "get_weather",
+			tool: tools[0],
+			args: map[string]any{"city": "Paris"},
+			want: map[string]any{"city": "Paris", "forecast": "sunny"},
+		},
+		{
+			name:    "get_weather without city",
+			tool:    tools[0],
+			args:    map[string]any{},
+			wantErr: true,
+		},
+		{
+			name: "add",
+			tool: tools[1],
+			args: map[string]any{"a": 2, "b": 3},
+			want: map[string]any{"sum": 5.0},
+		},
+		{
+			name:    "add with a string",
+			tool:    tools[1],
+			args:    map[string]any{"a": "2", "b": 3},
+			wantErr: true,
+		},
+		{
+			name: "ping",
+			tool: tools[2],
+			args: map[string]any{},
+			want: map[string]any{"pong": true},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := tc.tool.(toolinternal.FunctionTool).Run(nil, tc.args)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("Run(%v) error = %v, wantErr %v", tc.args, err, tc.wantErr)
+			}
+			if diff := cmp.Diff(tc.want, got); !tc.wantErr && diff != "" {
+				t.Errorf("Run(%v) mismatch (-want +got):\n%s", tc.args, diff)
+			}
+		})
+	}
+}
+
+func TestLoadToolsFromSchemas_Unmatched(t *testing.T) {
+	handler := func(tool.Context, map[string]any) (map[string]any, error) { return nil, nil }
+	_, err := functiontool.LoadToolsFromSchemas(
+		[]functiontool.ToolSchema{{Name: "a"}, {Name: "b"}},
+		map[string]functiontool.SchemaHandler{"a": handler, "d": handler, "c": handler},
+	)
+	var unmatched *functiontool.UnmatchedError
+	if !errors.As(err, &unmatched) {
+		t.Fatalf("LoadToolsFromSchemas() error = %v, want an UnmatchedError", err)
+	}
+	want := &functiontool.UnmatchedError{MissingHandlers: []string{"b"}, UnusedHandlers: []string{"c", "d"}}
+	if diff := cmp.Diff(want, unmatched); diff != "" {
+		t.Errorf("UnmatchedError mismatch (-want +got):\n%s", diff)
+	}
+
+	if _, err := functiontool.LoadToolsFromSchemas(
+		[]functiontool.ToolSchema{{Name: "a"}, {Name: "a"}},
+		map[string]functiontool.SchemaHandler{"a": handler},
+	); err == nil {
+		t.Error("LoadToolsFromSchemas() with duplicate names succeeded, want error")
+	}
+}
@@ -0,0 +1,105 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package functiontool
+
+import (
+	"context"
+	"fmt"
+	"iter"
+
+	"google.golang.org/adk/internal/toolinternal"
+	"google.golang.org/adk/tool"
+)
+
+// StreamingFunc represents a Go function producing its result over time,
+// wrapped in a tool with NewStreaming. It emits the partial results with
+// emit, and returns the final result.
+//
+// emit fails once the consumer of the events is gone; the context is then
+// canceled, and the handler should return.
+type StreamingFunc[TArgs, TResults any] func(ctx context.Context, args TArgs, emit func(partial TResults) error) (TResults, error)
+
+// NewStreaming creates a tool whose handler emits partial results before
+// its final result, e.g. a log tailer or a progressive search. The agent
+// yields each partial result as a partial event with a function response,
+// flagged with WillContinue, while the tool runs; partial events are not
+// stored in the session, nor sent to the model, which only gets the final
+// result returned by Run.
+//
+// The partial results are converted as the final result, with the output
+// schema inferred from TResults or set in cfg.
+func NewStreaming[TArgs, TResults any](cfg Config, handler StreamingFunc[TArgs, TResults]) (tool.Tool, error) {
+	oschema, err := resolveOutputSchema[TResults](cfg)
+	if err != nil {
+		return nil, err
+	}
+	return New(cfg, func(ctx tool.Context, args TArgs) (TResults, error) {
+		parent := context.Context(context.Background())
+		if ctx != nil {
+			parent = ctx
+		}
+		handlerCtx, cancel := context.WithCancelCause(parent)
+		defer cancel(nil)
+
+		emit := func(partial TResults) error {
+			if handlerCtx.Err() != nil {
+				return context.Cause(handlerCtx)
+			}
+			if ctx == nil {
+				return nil
+			}
+			resp, err := resultMap(partial, oschema, resultKey(cfg))
+			if err != nil {
+				return fmt.Errorf("invalid partial result: %w", err)
+			}
+			if err := toolinternal.EmitPartial(ctx, resp); err != nil {
+				cancel(err)
+				return err
+			}
+			return nil
+		}
+		return handler(handlerCtx, args, emit)
+	})
+}
+
+// SeqFunc represents a Go function producing its result as a sequence of
+// chunks, e.g. the lines of a log, wrapped in a tool with NewStreamingSeq.
+// The sequence ends early with the first error it yields.
+type SeqFunc[TArgs, TChunk any] func(ctx context.Context, args TArgs) iter.Seq2[TChunk, error]
+
+// NewStreamingSeq creates a streaming tool, as NewStreaming does, whose
+// handler returns an iterator of chunks instead of calling emit. Each chunk
+// is emitted as a partial result, [chunk], and the final result sent to the
+// model is the aggregate of the chunks, [chunk1, chunk2, ...]: both are
+// wrapped under Config.ResultKey, with the output schema inferred from
+// []TChunk.
+//
+// The iteration stops, and the context of the handler is canceled, once the
+// consumer of the events is gone.
+func NewStreamingSeq[TArgs, TChunk any](cfg Config, handler SeqFunc[TArgs, TChunk]) (tool.Tool, error) {
+	return NewStreaming(cfg, func(ctx context.Context, args TArgs, emit func([]TChunk) error) ([]TChunk, error) {
+		var chunks []TChunk
+		for chunk, err := range handler(ctx, args) {
+			if err != nil {
+				return nil, err
+			}
+			chunks = append(chunks, chunk)
+			if err := emit([]TChunk{chunk}); err != nil {
+				return nil, err
+			}
+		}
+		return chunks, nil
+	})
+}
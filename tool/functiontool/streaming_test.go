@@ -0,0 +1,119 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package functiontool_test
+
+import (
+	"context"
+	"errors"
+	"iter"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"google.golang.org/adk/internal/toolinternal"
+	"google.golang.org/adk/tool/functiontool"
+)
+
+func TestNewStreaming(t *testing.T) {
+	type Args struct {
+		Query string `json:"query"`
+	}
+	var emitted int
+	searchTool, err := functiontool.NewStreaming(functiontool.Config{
+		Name:        "search",
+		Description: "searches progressively",
+	}, func(ctx context.Context, args Args, emit func([]string) error) ([]string, error) {
+		results := []string{args.Query + " 1", args.Query + " 2"}
+		for i := range results {
+			if err := emit(results[:i+1]); err != nil {
+				return nil, err
+			}
+			emitted++
+		}
+		return results, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Without a consumer of the partial results, Run returns the final
+	// result only.
+	got, err := searchTool.(toolinternal.FunctionTool).Run(newToolContext(t), map[string]any{"query": "go"})
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if diff := cmp.Diff(map[string]any{"result": []string{"go 1", "go 2"}}, got); diff != "" {
+		t.Errorf("Run() mismatch (-want +got):\n%s", diff)
+	}
+	if emitted != 2 {
+		t.Errorf("emitted %d partial results, want 2", emitted)
+	}
+}
+
+func TestNewStreamingSeq(t *testing.T) {
+	type Args struct {
+		File string `json:"file"`
+	}
+	tailTool, err := functiontool.NewStreamingSeq(functiontool.Config{
+		Name:        "tail",
+		Description: "tails a log",
+	}, func(ctx context.Context, args Args) iter.Seq2[string, error] {
+		return func(yield func(string, error) bool) {
+			if args.File == "" {
+				yield("", errors.New("no file"))
+				return
+			}
+			for _, line := range []string{"start", "ready"} {
+				if !yield(args.File+": "+line, nil) {
+					return
+				}
+			}
+		}
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var partials []map[string]any
+	ctx := toolinternal.WithPartialEmitter(newToolContext(t), func(partial map[string]any) error {
+		partials = append(partials, partial)
+		return nil
+	})
+	run := tailTool.(toolinternal.FunctionTool).Run
+
+	got, err := run(ctx, map[string]any{"file": "app.log"})
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	// Each chunk is a partial result, and the final result aggregates them.
+	wantPartials := []map[string]any{{"result": []string{"app.log: start"}}, {"result": []string{"app.log: ready"}}}
+	if diff := cmp.Diff(wantPartials, partials); diff != "" {
+		t.Errorf("partial results mismatch (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff(map[string]any{"result": []string{"app.log: start", "app.log: ready"}}, got); diff != "" {
+		t.Errorf("Run() mismatch (-want +got):\n%s", diff)
+	}
+
+	if _, err := run(ctx, map[string]any{"file": ""}); err == nil || err.Error() != "no file" {
+		t.Errorf("Run() error = %v, want the error of the sequence", err)
+	}
+
+	// The iteration stops once the consumer is gone.
+	stopped := toolinternal.WithPartialEmitter(newToolContext(t), func(map[string]any) error {
+		return toolinternal.ErrPartialsStopped
+	})
+	if _, err := run(stopped, map[string]any{"file": "app.log"}); !errors.Is(err, toolinternal.ErrPartialsStopped) {
+		t.Errorf("Run() error = %v, want %v", err, toolinternal.ErrPartialsStopped)
+	}
+}
@@ -0,0 +1,122 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package functiontool
+
+import (
+	"fmt"
+	"unicode/utf8"
+
+	"google.golang.org/adk/tool"
+)
+
+// TruncateStrategy controls how a result larger than Config.MaxResultBytes
+// is truncated. The truncated result is a JSON object of at most
+// MaxResultBytes bytes, unless the limit is too small even for the marker of
+// TruncateMarker, marked with "truncated": true and the size of the JSON
+// encoding of the full result in "original_bytes".
+type TruncateStrategy string
+
+const (
+	// TruncateHead keeps the beginning of the JSON encoding of the result,
+	// as a string under the result key, see Config.ResultKey. It is the
+	// default.
+	TruncateHead TruncateStrategy = "head"
+	// TruncateTail keeps the end of the JSON encoding of the result, as
+	// TruncateHead keeps its beginning.
+	TruncateTail TruncateStrategy = "tail"
+	// TruncateMarker drops the result, replaced by a note telling the model
+	// that it was too large, e.g. so that it narrows its request.
+	TruncateMarker TruncateStrategy = "marker"
+)
+
+// truncateResult truncates the result if the size of its JSON encoding
+// exceeds Config.MaxResultBytes.
+func (f *functionTool[TArgs, TResults]) truncateResult(result map[string]any) (map[string]any, error) {
+	limit := f.cfg.MaxResultBytes
+	if limit <= 0 {
+		return result, nil
+	}
+	encoded, err := encodeJSON(result)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode the result of tool %q: %w", f.Name(), err)
+	}
+	if len(encoded) <= limit {
+		return result, nil
+	}
+
+	marker := map[string]any{
+		"truncated":      true,
+		"original_bytes": len(encoded),
+		"note":           "the result exceeds the size limit",
+	}
+	if f.cfg.TruncateStrategy == TruncateMarker {
+		return marker, nil
+	}
+	// The kept part is escaped in the truncated result: it is shortened until
+	// the truncated result fits.
+	for keep := limit; keep > 0; {
+		part := encoded[:validPrefix(encoded, keep)]
+		if f.cfg.TruncateStrategy == TruncateTail {
+			part = encoded[len(encoded)-validSuffix(encoded, keep):]
+		}
+		truncated := map[string]any{
+			resultKey(f.cfg): part,
+			"truncated":      true,
+			"original_bytes": len(encoded),
+		}
+		size, err := encodedSize(truncated)
+		if err != nil {
+			return nil, err
+		}
+		if size <= limit {
+			return truncated, nil
+		}
+		keep -= size - limit
+	}
+	// The limit is too small for any part of the result.
+	return marker, nil
+}
+
+// encodeJSON returns the canonical JSON encoding of the result, see
+// tool.CanonicalJSON, whose HTML characters are not escaped so that the sizes
+// match the text the model sees.
+func encodeJSON(result map[string]any) (string, error) {
+	encoded, err := tool.CanonicalJSON(result)
+	return string(encoded), err
+}
+
+// encodedSize returns the size of the JSON encoding of the result.
+func encodedSize(result map[string]any) (int, error) {
+	encoded, err := encodeJSON(result)
+	return len(encoded), err
+}
+
+// validPrefix returns the length of the longest prefix of s of at most n
+// bytes which doesn't split a UTF-8 character.
+func validPrefix(s string, n int) int {
+	for n > 0 && n < len(s) && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return n
+}
+
+// validSuffix returns the length of the longest suffix of s of at most n
+// bytes which doesn't split a UTF-8 character.
+func validSuffix(s string, n int) int {
+	for n > 0 && n < len(s) && !utf8.RuneStart(s[len(s)-n]) {
+		n--
+	}
+	return n
+}
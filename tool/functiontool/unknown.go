@@ -0,0 +1,141 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package functiontool
+
+import (
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/google/jsonschema-go/jsonschema"
+)
+
+// UnknownFieldMode is how a tool handles the unknown fields of the arguments
+// sent by the model: the top-level properties which are not fields of its
+// struct argument type. The tools whose argument type is a map have no
+// unknown fields.
+type UnknownFieldMode int
+
+const (
+	// IgnoreUnknown drops the unknown fields, before the validation. It is
+	// the default.
+	IgnoreUnknown UnknownFieldMode = iota
+	// ErrorOnUnknown fails the calls with unknown fields with a
+	// *ValidationError naming them, returned to the model so that it retries.
+	ErrorOnUnknown
+	// CaptureUnknown drops the unknown fields, as IgnoreUnknown, and stores
+	// them in the field of the argument type tagged `functiontool:"unknown"`,
+	// which must be an exported map[string]any excluded from the JSON form of
+	// the arguments, e.g.
+	//
+	//	Extra map[string]any `json:"-" functiontool:"unknown"`
+	//
+	// The field is nil if the call has no unknown fields.
+	CaptureUnknown
+)
+
+// unknownFieldTag is the tag of the field capturing the unknown fields, see
+// CaptureUnknown.
+const unknownFieldTag = "unknown"
+
+// unknownFieldHandling returns the known fields of the arguments of type
+// argsType, nil if they have no unknown fields, and the index of the field
+// capturing the unknown fields, nil if they are not captured.
+func unknownFieldHandling(mode UnknownFieldMode, argsType reflect.Type, schema *jsonschema.Resolved) (known map[string]bool, capture []int, err error) {
+	if argsType.Kind() != reflect.Struct || schema == nil {
+		if mode == CaptureUnknown {
+			return nil, nil, fmt.Errorf("capturing the unknown fields requires struct arguments, got %v: %w", argsType, ErrInvalidArgument)
+		}
+		return nil, nil, nil
+	}
+	known = make(map[string]bool)
+	for name := range schema.Schema().Properties {
+		known[name] = true
+	}
+	if mode != CaptureUnknown {
+		return known, nil, nil
+	}
+	for i := range argsType.NumField() {
+		field := argsType.Field(i)
+		if field.Tag.Get("functiontool") != unknownFieldTag {
+			continue
+		}
+		if capture != nil {
+			return nil, nil, fmt.Errorf("several fields of %v are tagged to capture the unknown fields: %w", argsType, ErrInvalidArgument)
+		}
+		if !field.IsExported() || field.Type != reflect.TypeFor[map[string]any]() || field.Tag.Get("json") != "-" {
+			return nil, nil, fmt.Errorf("field %s of %v capturing the unknown fields must be an exported map[string]any tagged `json:\"-\"`: %w", field.Name, argsType, ErrInvalidArgument)
+		}
+		capture = field.Index
+	}
+	if capture == nil {
+		return nil, nil, fmt.Errorf("no field of %v is tagged `functiontool:%q` to capture the unknown fields: %w", argsType, unknownFieldTag, ErrInvalidArgument)
+	}
+	return known, capture, nil
+}
+
+// splitUnknownFields applies Config.OnUnknownField to the arguments. It
+// returns the arguments without their unknown fields, and the unknown
+// fields.
+func (f *functionTool[TArgs, TResults]) splitUnknownFields(args map[string]any) (map[string]any, map[string]any, error) {
+	if f.knownFields == nil {
+		return args, nil, nil
+	}
+	var unknown map[string]any
+	for name, val := range args {
+		if !f.knownFields[name] {
+			if unknown == nil {
+				unknown = make(map[string]any)
+			}
+			unknown[name] = val
+		}
+	}
+	if len(unknown) == 0 {
+		return args, nil, nil
+	}
+	if f.cfg.OnUnknownField == ErrorOnUnknown {
+		names := slices.Sorted(maps.Keys(unknown))
+		verr := &ValidationError{Tool: f.Name(), Err: fmt.Errorf("unknown fields %s", strings.Join(names, ", "))}
+		for _, name := range names {
+			verr.Fields = append(verr.Fields, FieldError{Field: name, Message: "unknown field"})
+		}
+		return nil, nil, verr
+	}
+	known := make(map[string]any, len(args)-len(unknown))
+	for name, val := range args {
+		if f.knownFields[name] {
+			known[name] = val
+		}
+	}
+	return known, unknown, nil
+}
+
+// captureUnknownFields stores the unknown fields in the capturing field of
+// the arguments, see CaptureUnknown.
+func (f *functionTool[TArgs, TResults]) captureUnknownFields(input *TArgs, unknown map[string]any) {
+	if f.captureField == nil || unknown == nil {
+		return
+	}
+	v := reflect.ValueOf(input).Elem()
+	for v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+		v = v.Elem()
+	}
+	v.FieldByIndex(f.captureField).Set(reflect.ValueOf(unknown))
+}
@@ -0,0 +1,214 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package functiontool
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/google/jsonschema-go/jsonschema"
+
+	"google.golang.org/adk/tool"
+)
+
+// ValidationError is the error of a call whose arguments don't match the
+// input schema of the tool, or cannot be converted to its argument type. Its
+// message lists the offending fields and asks the model to retry, as the
+// errors of the tools are returned to the model: it is reported as a
+// tool.ToolError with the tool.CodeInvalidArguments code, listing the fields
+// in its details, e.g.
+//
+//	{"error": {"code": "invalid_arguments", "message": "...", "details": {"fields": [{"field": "city", "message": "the field is required"}]}}}
+type ValidationError struct {
+	// Tool is the name of the tool.
+	Tool string
+	// Fields are the errors of the offending fields, sorted by field.
+	Fields []FieldError
+	// Err is the validation error.
+	Err error
+}
+
+// FieldError is the error of a field of the arguments of a call. Field is
+// empty for the errors not tied to a top-level field.
+type FieldError struct {
+	Field   string
+	Message string
+}
+
+func (e *ValidationError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "invalid arguments for tool %q:", e.Tool)
+	for _, f := range e.Fields {
+		if f.Field != "" {
+			fmt.Fprintf(&b, " field %q: %s;", f.Field, f.Message)
+		} else {
+			fmt.Fprintf(&b, " %s;", f.Message)
+		}
+	}
+	b.WriteString(" fix the arguments and call the tool again")
+	return b.String()
+}
+
+// Is reports that the error is a schema validation error, see
+// tool.ErrSchemaValidation.
+func (e *ValidationError) Is(target error) bool {
+	return target == tool.ErrSchemaValidation
+}
+
+func (e *ValidationError) Unwrap() error {
+	return e.Err
+}
+
+// As reports the error as a tool.ToolError, so that the model gets the
+// offending fields as structured data.
+func (e *ValidationError) As(target any) bool {
+	t, ok := target.(**tool.ToolError)
+	if !ok {
+		return false
+	}
+	fields := make([]any, 0, len(e.Fields))
+	for _, f := range e.Fields {
+		field := map[string]any{"message": f.Message}
+		if f.Field != "" {
+			field["field"] = f.Field
+		}
+		fields = append(fields, field)
+	}
+	*t = &tool.ToolError{
+		Code:    tool.CodeInvalidArguments,
+		Message: e.Error(),
+		Details: map[string]any{"fields": fields},
+	}
+	return true
+}
+
+// propertySchema is the resolved schema of a top-level property.
+type propertySchema struct {
+	schema *jsonschema.Resolved
+	// nonNull is the schema of the non-null values of a property whose schema
+	// is any of null or another schema, e.g. a pointer to a type of $defs,
+	// whose errors are more telling. It is nil for the other properties.
+	nonNull *jsonschema.Resolved
+}
+
+// validate validates the value of the property.
+func (p *propertySchema) validate(v any) error {
+	err := p.schema.Validate(v)
+	if err != nil && v != nil && p.nonNull != nil {
+		if nerr := p.nonNull.Validate(v); nerr != nil {
+			return nerr
+		}
+	}
+	return err
+}
+
+// resolvePropertySchemas resolves the schemas of the top-level properties,
+// used to report the errors per field, with the $defs of the schema. The
+// schemas that cannot be resolved on their own, e.g. with other references,
+// are skipped.
+func resolvePropertySchemas(schema *jsonschema.Resolved) map[string]*propertySchema {
+	if schema == nil {
+		return nil
+	}
+	defs := schema.Schema().Defs
+	resolve := func(s *jsonschema.Schema) (*jsonschema.Resolved, error) {
+		if len(defs) > 0 {
+			s = s.CloneSchemas()
+			s.Defs = defs
+		}
+		return s.Resolve(nil)
+	}
+	resolved := make(map[string]*propertySchema)
+	for name, prop := range schema.Schema().Properties {
+		if prop == nil {
+			continue
+		}
+		r, err := resolve(prop)
+		if err != nil {
+			continue
+		}
+		ps := &propertySchema{schema: r}
+		if len(prop.AnyOf) == 2 && prop.AnyOf[0].Type == "null" {
+			ps.nonNull, _ = resolve(prop.AnyOf[1])
+		}
+		resolved[name] = ps
+	}
+	return resolved
+}
+
+// validationError returns the error of the arguments that failed the
+// validation with err.
+func (f *functionTool[TArgs, TResults]) validationError(args map[string]any, err error) *ValidationError {
+	verr := &ValidationError{Tool: f.Name(), Err: err}
+	schema := f.inputSchema.Schema()
+	for _, name := range schema.Required {
+		if _, ok := args[name]; !ok {
+			verr.Fields = append(verr.Fields, FieldError{Field: name, Message: "the field is required"})
+		}
+	}
+	for _, name := range slices.Sorted(maps.Keys(args)) {
+		if _, ok := schema.Properties[name]; !ok {
+			if schema.AdditionalProperties != nil && schema.AdditionalProperties.Not != nil {
+				verr.Fields = append(verr.Fields, FieldError{Field: name, Message: "unknown field"})
+			}
+			continue
+		}
+		if prop := f.propertySchemas[name]; prop != nil {
+			if err := prop.validate(args[name]); err != nil {
+				verr.Fields = append(verr.Fields, FieldError{Field: name, Message: validationMessage(err)})
+			}
+		}
+	}
+	if len(verr.Fields) == 0 {
+		verr.Fields = []FieldError{{Message: validationMessage(err)}}
+	}
+	slices.SortStableFunc(verr.Fields, func(a, b FieldError) int { return strings.Compare(a.Field, b.Field) })
+	return verr
+}
+
+// conversionError returns the error of the arguments that cannot be
+// converted to the argument type with err, e.g. with the validation skipped.
+func (f *functionTool[TArgs, TResults]) conversionError(err error) *ValidationError {
+	field := FieldError{Message: err.Error()}
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) && typeErr.Field != "" {
+		name, _, _ := strings.Cut(typeErr.Field, ".")
+		field = FieldError{Field: name, Message: fmt.Sprintf("cannot use a JSON %s as %s", typeErr.Value, typeErr.Type)}
+	}
+	return &ValidationError{Tool: f.Name(), Fields: []FieldError{field}, Err: err}
+}
+
+// defsLocationRegexp matches the locations of the errors within the schemas
+// of $defs, which are meaningless to the model.
+var defsLocationRegexp = regexp.MustCompile(`validating /\$defs/[^/:]+(/[^:]*)?: `)
+
+// validationMessage returns the message of the validation error, without
+// the location of the root nor of the schemas of $defs, and with the null
+// values of the fields that are not nullable reported as such.
+func validationMessage(err error) string {
+	msg := strings.TrimPrefix(err.Error(), "validating root: ")
+	msg = defsLocationRegexp.ReplaceAllStringFunc(msg, func(loc string) string {
+		if sub := defsLocationRegexp.FindStringSubmatch(loc)[1]; sub != "" {
+			return "validating " + sub + ": "
+		}
+		return ""
+	})
+	return strings.ReplaceAll(msg, `type: <invalid reflect.Value> has type "null"`, "null is not allowed")
+}
@@ -0,0 +1,111 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package geminitool
+
+import (
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/model"
+	"google.golang.org/adk/tool"
+)
+
+// CodeExecution is a built-in tool that lets Gemini models write and run
+// Python code server-side, e.g. to compute or analyze data.
+//
+// The code and its output are parts of the model response: the events of
+// the agent carry a genai.Part with ExecutableCode for the code, followed by
+// a genai.Part with CodeExecutionResult for its outcome and output, usually
+// followed by the text of the answer. They are kept in the session history
+// and sent back to the model with the next requests. An event ending with a
+// code execution result is not a final response (see
+// session.Event.IsFinalResponse), since the model continues after it.
+// CodeRuns extracts the code and its output from the events.
+type CodeExecution struct{}
+
+// Name implements tool.Tool.
+func (c CodeExecution) Name() string {
+	return "code_execution"
+}
+
+// Description implements tool.Tool.
+func (c CodeExecution) Description() string {
+	return "Writes and executes Python code to compute the answer."
+}
+
+// ProcessRequest adds the CodeExecution tool to the LLM request.
+func (c CodeExecution) ProcessRequest(ctx tool.Context, req *model.LLMRequest) error {
+	return setTool(req, c, &genai.Tool{
+		CodeExecution: &genai.ToolCodeExecution{},
+	})
+}
+
+// Declaration implements toolinternal.FunctionTool. The tool has no function
+// declaration: it is declared in the request config by ProcessRequest.
+func (c CodeExecution) Declaration() *genai.FunctionDeclaration {
+	return nil
+}
+
+// Run implements toolinternal.FunctionTool. It always fails since the code is
+// executed by the model.
+func (c CodeExecution) Run(ctx tool.Context, args any) (map[string]any, error) {
+	return nil, builtinToolError(c.Name())
+}
+
+// IsLongRunning implements tool.Tool.
+func (c CodeExecution) IsLongRunning() bool {
+	return false
+}
+
+// CodeRun is a piece of code run by CodeExecution, and its outcome.
+type CodeRun struct {
+	// Language is the language of the code, e.g. genai.LanguagePython.
+	Language genai.Language
+	Code     string
+	// Outcome is the outcome of the run, or empty if the response does not
+	// have its result yet, e.g. in a partial response.
+	Outcome genai.Outcome
+	// Output is the stdout of a successful run, or the error otherwise.
+	Output string
+}
+
+// CodeRuns returns the code run by CodeExecution in the response, e.g. of the
+// session.Event of an agent, in order. The result of each run is the
+// CodeExecutionResult part following its ExecutableCode part. A result without
+// code, e.g. of code sent in a previous partial response, is returned as a run
+// without code.
+func CodeRuns(resp *model.LLMResponse) []CodeRun {
+	if resp == nil || resp.Content == nil {
+		return nil
+	}
+	var runs []CodeRun
+	// pending is whether the last run waits for its result.
+	pending := false
+	for _, p := range resp.Content.Parts {
+		switch {
+		case p == nil:
+		case p.ExecutableCode != nil:
+			runs = append(runs, CodeRun{Language: p.ExecutableCode.Language, Code: p.ExecutableCode.Code})
+			pending = true
+		case p.CodeExecutionResult != nil:
+			if !pending {
+				runs = append(runs, CodeRun{})
+			}
+			runs[len(runs)-1].Outcome = p.CodeExecutionResult.Outcome
+			runs[len(runs)-1].Output = p.CodeExecutionResult.Output
+			pending = false
+		}
+	}
+	return runs
+}
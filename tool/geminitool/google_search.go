@@ -25,6 +25,10 @@ import (
 // models to retrieve search results from Google Search.
 // The tool operates internally within the model and does not require or
 // perform local code execution.
+//
+// The responses grounded on the search results carry their grounding
+// metadata, e.g. in the events of the agent, from which SearchGrounding
+// extracts the queries, sources and citations.
 type GoogleSearch struct{}
 
 // Name implements tool.Tool.
@@ -39,11 +43,23 @@ func (s GoogleSearch) Description() string {
 
 // ProcessRequest adds the GoogleSearch tool to the LLM request.
 func (s GoogleSearch) ProcessRequest(ctx tool.Context, req *model.LLMRequest) error {
-	return setTool(req, &genai.Tool{
+	return setTool(req, s, &genai.Tool{
 		GoogleSearch: &genai.GoogleSearch{},
 	})
 }
 
+// Declaration implements toolinternal.FunctionTool. The tool has no function
+// declaration: it is declared in the request config by ProcessRequest.
+func (s GoogleSearch) Declaration() *genai.FunctionDeclaration {
+	return nil
+}
+
+// Run implements toolinternal.FunctionTool. It always fails since the search
+// is executed by the model.
+func (s GoogleSearch) Run(ctx tool.Context, args any) (map[string]any, error) {
+	return nil, builtinToolError(s.Name())
+}
+
 // IsLongRunning implements tool.Tool.
 func (t GoogleSearch) IsLongRunning() bool {
 	return false
@@ -0,0 +1,102 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package geminitool
+
+import (
+	"google.golang.org/adk/model"
+)
+
+// Grounding is the grounding of a response of the model on the results of
+// GoogleSearch, e.g. to render its citations.
+type Grounding struct {
+	// SearchQueries are the queries the model searched.
+	SearchQueries []string
+	// Sources are the sources the response is grounded on. The citations
+	// refer to them by index.
+	Sources []Source
+	// Citations are the segments of the response grounded on the sources.
+	Citations []Citation
+	// SearchSuggestions is the HTML rendering of the search suggestions,
+	// which the terms of Grounding with Google Search require applications
+	// to display with the response.
+	SearchSuggestions string
+}
+
+// Source is a source of a grounded response.
+type Source struct {
+	Title string
+	URI   string
+	// Domain is the domain of the web sources, e.g. "example.com".
+	Domain string
+}
+
+// Citation is a segment of the text of a grounded response, and the sources
+// it is grounded on.
+type Citation struct {
+	// Text is the text of the segment.
+	Text string
+	// StartIndex and EndIndex are the byte offsets of the segment in the text
+	// of its part of the response, whose index is PartIndex.
+	StartIndex, EndIndex int
+	PartIndex            int
+	// Sources are the indices of the sources of the segment in
+	// Grounding.Sources.
+	Sources []int
+}
+
+// SearchGrounding returns the grounding of the response, e.g. of the
+// session.Event of an agent using GoogleSearch, or nil if it has no grounding
+// metadata.
+func SearchGrounding(resp *model.LLMResponse) *Grounding {
+	if resp == nil || resp.GroundingMetadata == nil {
+		return nil
+	}
+	md := resp.GroundingMetadata
+	g := &Grounding{SearchQueries: md.WebSearchQueries}
+	if md.SearchEntryPoint != nil {
+		g.SearchSuggestions = md.SearchEntryPoint.RenderedContent
+	}
+	// The sources keep the indices of the chunks, to which the supports
+	// refer.
+	for _, chunk := range md.GroundingChunks {
+		var src Source
+		switch {
+		case chunk == nil:
+		case chunk.Web != nil:
+			src = Source{Title: chunk.Web.Title, URI: chunk.Web.URI, Domain: chunk.Web.Domain}
+		case chunk.RetrievedContext != nil:
+			src = Source{Title: chunk.RetrievedContext.Title, URI: chunk.RetrievedContext.URI}
+		}
+		g.Sources = append(g.Sources, src)
+	}
+	for _, support := range md.GroundingSupports {
+		if support == nil || support.Segment == nil {
+			continue
+		}
+		c := Citation{
+			Text:       support.Segment.Text,
+			StartIndex: int(support.Segment.StartIndex),
+			EndIndex:   int(support.Segment.EndIndex),
+			PartIndex:  int(support.Segment.PartIndex),
+		}
+		for _, i := range support.GroundingChunkIndices {
+			if int(i) < len(g.Sources) {
+				c.Sources = append(c.Sources, int(i))
+			}
+		}
+		g.Citations = append(g.Citations, c)
+	}
+	return g
+}
@@ -26,7 +26,7 @@
 //		},
 //	})
 //
-// Package also provides default tools like GoogleSearch.
+// Package also provides default tools like GoogleSearch and CodeExecution.
 package geminitool
 
 import (
@@ -34,10 +34,20 @@ import (
 
 	"google.golang.org/genai"
 
+	"google.golang.org/adk/internal/toolinternal"
 	"google.golang.org/adk/model"
 	"google.golang.org/adk/tool"
 )
 
+var (
+	_ toolinternal.FunctionTool     = (*geminiTool)(nil)
+	_ toolinternal.RequestProcessor = (*geminiTool)(nil)
+	_ toolinternal.FunctionTool     = GoogleSearch{}
+	_ toolinternal.RequestProcessor = GoogleSearch{}
+	_ toolinternal.FunctionTool     = CodeExecution{}
+	_ toolinternal.RequestProcessor = CodeExecution{}
+)
+
 // New creates  gemini API tool.
 func New(name string, t *genai.Tool) tool.Tool {
 	return &geminiTool{
@@ -54,7 +64,19 @@ type geminiTool struct {
 
 // ProcessRequest adds the Gemini tool to the LLM request.
 func (t *geminiTool) ProcessRequest(ctx tool.Context, req *model.LLMRequest) error {
-	return setTool(req, t.value)
+	return setTool(req, t, t.value)
+}
+
+// Declaration implements toolinternal.FunctionTool. The tool has no function
+// declaration: it is declared in the request config by ProcessRequest.
+func (t *geminiTool) Declaration() *genai.FunctionDeclaration {
+	return nil
+}
+
+// Run implements toolinternal.FunctionTool. It always fails since the tool is
+// executed by the model.
+func (t *geminiTool) Run(ctx tool.Context, args any) (map[string]any, error) {
+	return nil, builtinToolError(t.name)
 }
 
 // Name implements tool.Tool.
@@ -72,11 +94,25 @@ func (t *geminiTool) IsLongRunning() bool {
 	return false
 }
 
-func setTool(req *model.LLMRequest, t *genai.Tool) error {
+// setTool adds the built-in tool to the LLM request. Unlike the function
+// tools, whose declarations are gathered in one genai.Tool, each built-in
+// tool is a genai.Tool of its own in the request config, executed by the
+// model server-side. The tool is still registered by name in req.Tools, so
+// that a function tool with the same name is reported as a duplicate and a
+// call of the built-in tool as a function gets a clear error.
+func setTool(req *model.LLMRequest, self tool.Tool, t *genai.Tool) error {
 	if req == nil {
 		return fmt.Errorf("llm request is nil")
 	}
 
+	if req.Tools == nil {
+		req.Tools = make(map[string]any)
+	}
+	if _, ok := req.Tools[self.Name()]; ok {
+		return fmt.Errorf("duplicate tool: %q", self.Name())
+	}
+	req.Tools[self.Name()] = self
+
 	if req.Config == nil {
 		req.Config = &genai.GenerateContentConfig{}
 	}
@@ -84,3 +120,7 @@ func setTool(req *model.LLMRequest, t *genai.Tool) error {
 	req.Config.Tools = append(req.Config.Tools, t)
 	return nil
 }
+
+func builtinToolError(name string) error {
+	return fmt.Errorf("tool %q is a built-in tool executed by the model and cannot be called as a function", name)
+}
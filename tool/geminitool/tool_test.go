@@ -15,6 +15,7 @@
 package geminitool_test
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
@@ -22,6 +23,8 @@ import (
 
 	"google.golang.org/adk/internal/toolinternal"
 	"google.golang.org/adk/model"
+	"google.golang.org/adk/tool"
+	"google.golang.org/adk/tool/functiontool"
 	"google.golang.org/adk/tool/geminitool"
 )
 
@@ -91,3 +94,136 @@ func TestGeminiTool_ProcessRequest(t *testing.T) {
 		})
 	}
 }
+
+func TestGoogleSearch_WithFunctionTools(t *testing.T) {
+	type Args struct {
+		City string `json:"city"`
+	}
+	weatherTool, err := functiontool.New(functiontool.Config{Name: "get_weather", Description: "returns the weather"}, func(tool.Context, Args) (string, error) {
+		return "sunny", nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req := &model.LLMRequest{}
+	for _, tl := range []tool.Tool{weatherTool, geminitool.GoogleSearch{}} {
+		if err := tl.(toolinternal.RequestProcessor).ProcessRequest(nil, req); err != nil {
+			t.Fatalf("ProcessRequest(%q) error = %v", tl.Name(), err)
+		}
+	}
+	if len(req.Config.Tools) != 2 || len(req.Config.Tools[0].FunctionDeclarations) != 1 || req.Config.Tools[1].GoogleSearch == nil {
+		t.Errorf("ProcessRequest() tools = %v, want the function declarations and the search tool", req.Config.Tools)
+	}
+
+	// The search tool is registered by name.
+	if err := (geminitool.GoogleSearch{}).ProcessRequest(nil, req); err == nil {
+		t.Error("ProcessRequest() of a duplicate search tool succeeded, want error")
+	}
+	search, ok := req.Tools["google_search"].(toolinternal.FunctionTool)
+	if !ok {
+		t.Fatalf("req.Tools[google_search] = %T, want a FunctionTool", req.Tools["google_search"])
+	}
+	if _, err := search.Run(nil, map[string]any{}); err == nil || !strings.Contains(err.Error(), "built-in tool") {
+		t.Errorf("Run() error = %v, want a built-in tool error", err)
+	}
+}
+
+func TestCodeExecution_ProcessRequest(t *testing.T) {
+	type Args struct {
+		Table string `json:"table"`
+	}
+	queryTool, err := functiontool.New(functiontool.Config{Name: "query", Description: "queries a table"}, func(tool.Context, Args) (string, error) {
+		return "", nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req := &model.LLMRequest{}
+	for _, tl := range []tool.Tool{geminitool.CodeExecution{}, queryTool, geminitool.GoogleSearch{}} {
+		if err := tl.(toolinternal.RequestProcessor).ProcessRequest(nil, req); err != nil {
+			t.Fatalf("ProcessRequest(%q) error = %v", tl.Name(), err)
+		}
+	}
+	wantTools := []*genai.Tool{
+		{CodeExecution: &genai.ToolCodeExecution{}},
+		{FunctionDeclarations: []*genai.FunctionDeclaration{queryTool.(toolinternal.FunctionTool).Declaration()}},
+		{GoogleSearch: &genai.GoogleSearch{}},
+	}
+	if diff := cmp.Diff(wantTools, req.Config.Tools); diff != "" {
+		t.Errorf("ProcessRequest returned unexpected tools (-want +got):\n%s", diff)
+	}
+	if _, err := req.Tools["code_execution"].(toolinternal.FunctionTool).Run(nil, map[string]any{}); err == nil {
+		t.Error("Run() succeeded, want a built-in tool error")
+	}
+}
+
+func TestSearchGrounding(t *testing.T) {
+	resp := &model.LLMResponse{
+		Content: genai.NewContentFromText("Go 1.0 was released in 2012. It was designed at Google.", genai.RoleModel),
+		GroundingMetadata: &genai.GroundingMetadata{
+			WebSearchQueries: []string{"go 1.0 release date"},
+			GroundingChunks: []*genai.GroundingChunk{
+				{Web: &genai.GroundingChunkWeb{Title: "go.dev", URI: "https://go.dev/doc/go1", Domain: "go.dev"}},
+				{Web: &genai.GroundingChunkWeb{Title: "wikipedia.org", URI: "https://en.wikipedia.org/wiki/Go", Domain: "wikipedia.org"}},
+			},
+			GroundingSupports: []*genai.GroundingSupport{
+				{Segment: &genai.Segment{EndIndex: 28, Text: "Go 1.0 was released in 2012."}, GroundingChunkIndices: []int32{0, 1}},
+				{Segment: &genai.Segment{StartIndex: 29, EndIndex: 55, Text: "It was designed at Google."}, GroundingChunkIndices: []int32{1, 5}},
+				{GroundingChunkIndices: []int32{0}},
+			},
+			SearchEntryPoint: &genai.SearchEntryPoint{RenderedContent: "<div>suggestions</div>"},
+		},
+	}
+	want := &geminitool.Grounding{
+		SearchQueries: []string{"go 1.0 release date"},
+		Sources: []geminitool.Source{
+			{Title: "go.dev", URI: "https://go.dev/doc/go1", Domain: "go.dev"},
+			{Title: "wikipedia.org", URI: "https://en.wikipedia.org/wiki/Go", Domain: "wikipedia.org"},
+		},
+		Citations: []geminitool.Citation{
+			{Text: "Go 1.0 was released in 2012.", EndIndex: 28, Sources: []int{0, 1}},
+			// The index of a missing source is dropped.
+			{Text: "It was designed at Google.", StartIndex: 29, EndIndex: 55, Sources: []int{1}},
+		},
+		SearchSuggestions: "<div>suggestions</div>",
+	}
+	if diff := cmp.Diff(want, geminitool.SearchGrounding(resp)); diff != "" {
+		t.Errorf("SearchGrounding() mismatch (-want +got):\n%s", diff)
+	}
+
+	if got := geminitool.SearchGrounding(&model.LLMResponse{Content: resp.Content}); got != nil {
+		t.Errorf("SearchGrounding() of an ungrounded response = %v, want nil", got)
+	}
+}
+
+func TestCodeRuns(t *testing.T) {
+	resp := &model.LLMResponse{Content: &genai.Content{Role: genai.RoleModel, Parts: []*genai.Part{
+		genai.NewPartFromText("Let me compute it."),
+		genai.NewPartFromExecutableCode("print(2**10)", genai.LanguagePython),
+		genai.NewPartFromCodeExecutionResult(genai.OutcomeOK, "1024\n"),
+		genai.NewPartFromExecutableCode("print(1/0)", genai.LanguagePython),
+		genai.NewPartFromCodeExecutionResult(genai.OutcomeFailed, "ZeroDivisionError"),
+		genai.NewPartFromExecutableCode("print(3)", genai.LanguagePython),
+		genai.NewPartFromText("2**10 is 1024."),
+	}}}
+	want := []geminitool.CodeRun{
+		{Language: genai.LanguagePython, Code: "print(2**10)", Outcome: genai.OutcomeOK, Output: "1024\n"},
+		{Language: genai.LanguagePython, Code: "print(1/0)", Outcome: genai.OutcomeFailed, Output: "ZeroDivisionError"},
+		// Without its result yet.
+		{Language: genai.LanguagePython, Code: "print(3)"},
+	}
+	if diff := cmp.Diff(want, geminitool.CodeRuns(resp)); diff != "" {
+		t.Errorf("CodeRuns() mismatch (-want +got):\n%s", diff)
+	}
+
+	// The result of code of a previous response.
+	resp = &model.LLMResponse{Content: &genai.Content{Role: genai.RoleModel, Parts: []*genai.Part{
+		genai.NewPartFromCodeExecutionResult(genai.OutcomeOK, "3\n"),
+	}}}
+	want = []geminitool.CodeRun{{Outcome: genai.OutcomeOK, Output: "3\n"}}
+	if diff := cmp.Diff(want, geminitool.CodeRuns(resp)); diff != "" {
+		t.Errorf("CodeRuns() of a result mismatch (-want +got):\n%s", diff)
+	}
+}
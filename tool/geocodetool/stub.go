@@ -0,0 +1,87 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package geocodetool
+
+import (
+	"cmp"
+	"context"
+	"math"
+	"slices"
+	"strings"
+)
+
+const earthRadiusKm = 6371.0
+
+// StubProvider is a [Provider] resolving requests against a fixed list of
+// locations. It is meant for development and tests.
+type StubProvider struct {
+	// Locations known to the provider.
+	Locations []Location
+	// ReverseRadiusKm is the distance within which locations match a reverse
+	// geocoding request. Defaults to 1 km.
+	ReverseRadiusKm float64
+}
+
+// Geocode returns the locations whose formatted address contains the address,
+// ignoring case.
+func (p *StubProvider) Geocode(ctx context.Context, address string) ([]Location, error) {
+	var found []Location
+	for _, loc := range p.Locations {
+		if strings.Contains(strings.ToLower(loc.FormattedAddress), strings.ToLower(address)) {
+			found = append(found, loc)
+		}
+	}
+	return found, nil
+}
+
+// ReverseGeocode returns the locations within ReverseRadiusKm of the
+// coordinates, nearest first. Their confidence decreases linearly with the
+// distance.
+func (p *StubProvider) ReverseGeocode(ctx context.Context, latitude, longitude float64) ([]Location, error) {
+	radius := p.ReverseRadiusKm
+	if radius <= 0 {
+		radius = 1
+	}
+	type match struct {
+		loc      Location
+		distance float64
+	}
+	var matches []match
+	for _, loc := range p.Locations {
+		d := distanceKm(latitude, longitude, loc.Latitude, loc.Longitude)
+		if d <= radius {
+			loc.Confidence = 1 - d/radius
+			matches = append(matches, match{loc: loc, distance: d})
+		}
+	}
+	slices.SortFunc(matches, func(a, b match) int {
+		return cmp.Compare(a.distance, b.distance)
+	})
+	found := make([]Location, len(matches))
+	for i, m := range matches {
+		found[i] = m.loc
+	}
+	return found, nil
+}
+
+// distanceKm returns the great-circle distance between two points.
+func distanceKm(lat1, lng1, lat2, lng2 float64) float64 {
+	toRad := func(deg float64) float64 { return deg * math.Pi / 180 }
+	dLat := toRad(lat2 - lat1)
+	dLng := toRad(lng2 - lng1)
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(toRad(lat1))*math.Cos(toRad(lat2))*math.Sin(dLng/2)*math.Sin(dLng/2)
+	return 2 * earthRadiusKm * math.Asin(math.Sqrt(a))
+}
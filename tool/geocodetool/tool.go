@@ -0,0 +1,175 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package geocodetool provides a tool that converts addresses into
+// coordinates and coordinates into addresses using a pluggable [Provider].
+//
+// The model calls the tool with either an address (geocoding) or a latitude
+// and longitude (reverse geocoding). The provider's results are normalized,
+// ranked by confidence and returned as candidates. When the best candidates
+// are too close to call, the result is marked as ambiguous so the model can
+// ask the user which location was meant.
+package geocodetool
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+
+	"google.golang.org/adk/tool"
+	"google.golang.org/adk/tool/functiontool"
+)
+
+const (
+	defaultName            = "geocode"
+	defaultMaxCandidates   = 5
+	defaultAmbiguityMargin = 0.1
+)
+
+// Location is a geocoding result.
+type Location struct {
+	FormattedAddress string  `json:"formatted_address"`
+	Latitude         float64 `json:"latitude"`
+	Longitude        float64 `json:"longitude"`
+	// Confidence of the match, between 0 and 1.
+	Confidence  float64 `json:"confidence"`
+	Country     string  `json:"country,omitempty"`
+	Locality    string  `json:"locality,omitempty"`
+	PostalCode  string  `json:"postal_code,omitempty"`
+	Description string  `json:"description,omitempty"`
+}
+
+// Provider resolves addresses and coordinates, e.g. by calling a geocoding
+// service.
+type Provider interface {
+	// Geocode returns the locations matching the address.
+	Geocode(ctx context.Context, address string) ([]Location, error)
+	// ReverseGeocode returns the locations at the given coordinates.
+	ReverseGeocode(ctx context.Context, latitude, longitude float64) ([]Location, error)
+}
+
+// Config is the configuration of the geocoding tool.
+type Config struct {
+	// Name of the tool as seen by the model. Defaults to "geocode".
+	Name string
+	// Provider resolving the requests. Required.
+	Provider Provider
+	// MaxCandidates is the maximum number of candidates returned.
+	// Defaults to 5.
+	MaxCandidates int
+	// AmbiguityMargin is the confidence difference below which the two best
+	// candidates are considered ambiguous. Defaults to 0.1.
+	AmbiguityMargin float64
+}
+
+// Args are the arguments the model provides. Either Address or both
+// Latitude and Longitude must be set.
+type Args struct {
+	Address   string   `json:"address,omitempty" jsonschema:"the address or place to geocode"`
+	Latitude  *float64 `json:"latitude,omitempty" jsonschema:"the latitude to reverse geocode, in degrees"`
+	Longitude *float64 `json:"longitude,omitempty" jsonschema:"the longitude to reverse geocode, in degrees"`
+}
+
+// Result is the response of the tool.
+type Result struct {
+	// Candidates are the matching locations, best match first.
+	Candidates []Location `json:"candidates"`
+	// Ambiguous reports that several candidates match about equally well
+	// and the user should be asked which one is meant.
+	Ambiguous bool `json:"ambiguous"`
+}
+
+// New creates a geocoding tool.
+func New(cfg Config) (tool.Tool, error) {
+	if cfg.Provider == nil {
+		return nil, errors.New("geocoding provider is required")
+	}
+	if cfg.Name == "" {
+		cfg.Name = defaultName
+	}
+	if cfg.MaxCandidates <= 0 {
+		cfg.MaxCandidates = defaultMaxCandidates
+	}
+	if cfg.AmbiguityMargin <= 0 {
+		cfg.AmbiguityMargin = defaultAmbiguityMargin
+	}
+
+	g := &geocoder{cfg: cfg}
+	geocodeTool, err := functiontool.New(functiontool.Config{
+		Name: cfg.Name,
+		Description: "Converts an address or place name into coordinates, or coordinates into an address.\n" +
+			"Provide either address, or latitude and longitude. " +
+			"If the result is ambiguous, ask the user which of the candidates they meant.",
+	}, g.geocode)
+	if err != nil {
+		return nil, fmt.Errorf("error creating geocode tool: %w", err)
+	}
+	return geocodeTool, nil
+}
+
+type geocoder struct {
+	cfg Config
+}
+
+func (g *geocoder) geocode(ctx tool.Context, args Args) (Result, error) {
+	address := strings.TrimSpace(args.Address)
+	hasCoordinates := args.Latitude != nil || args.Longitude != nil
+
+	var locations []Location
+	var err error
+	switch {
+	case address != "" && hasCoordinates:
+		return Result{}, errors.New("provide either an address or coordinates, not both")
+	case address != "":
+		locations, err = g.cfg.Provider.Geocode(ctx, address)
+	case args.Latitude != nil && args.Longitude != nil:
+		lat, lng := *args.Latitude, *args.Longitude
+		if lat < -90 || lat > 90 || lng < -180 || lng > 180 {
+			return Result{}, fmt.Errorf("coordinates (%v, %v) are out of range", lat, lng)
+		}
+		locations, err = g.cfg.Provider.ReverseGeocode(ctx, lat, lng)
+	case hasCoordinates:
+		return Result{}, errors.New("both latitude and longitude are required")
+	default:
+		return Result{}, errors.New("an address or coordinates are required")
+	}
+	if err != nil {
+		return Result{}, fmt.Errorf("geocoding failed: %w", err)
+	}
+	return g.rank(locations), nil
+}
+
+// rank normalizes the locations and returns the best candidates.
+func (g *geocoder) rank(locations []Location) Result {
+	candidates := make([]Location, 0, len(locations))
+	for _, loc := range locations {
+		loc.FormattedAddress = strings.TrimSpace(loc.FormattedAddress)
+		loc.Confidence = math.Max(0, math.Min(1, loc.Confidence))
+		candidates = append(candidates, loc)
+	}
+	slices.SortStableFunc(candidates, func(a, b Location) int {
+		return cmp.Compare(b.Confidence, a.Confidence)
+	})
+	if len(candidates) > g.cfg.MaxCandidates {
+		candidates = candidates[:g.cfg.MaxCandidates]
+	}
+	return Result{
+		Candidates: candidates,
+		Ambiguous:  len(candidates) > 1 && candidates[0].Confidence-candidates[1].Confidence < g.cfg.AmbiguityMargin,
+	}
+}
@@ -0,0 +1,140 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package geocodetool_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"google.golang.org/adk/internal/toolinternal"
+	"google.golang.org/adk/internal/typeutil"
+	"google.golang.org/adk/tool/geocodetool"
+)
+
+var (
+	eiffelTower = geocodetool.Location{FormattedAddress: "Eiffel Tower, Paris, France", Latitude: 48.8584, Longitude: 2.2945, Confidence: 1, Country: "FR", Locality: "Paris"}
+	parisFrance = geocodetool.Location{FormattedAddress: "Paris, France", Latitude: 48.8566, Longitude: 2.3522, Confidence: 0.95, Country: "FR", Locality: "Paris"}
+	parisTexas  = geocodetool.Location{FormattedAddress: "Paris, Texas, USA", Latitude: 33.6609, Longitude: -95.5555, Confidence: 0.9, Country: "US", Locality: "Paris"}
+)
+
+func TestGeocodeTool(t *testing.T) {
+	provider := &geocodetool.StubProvider{
+		Locations: []geocodetool.Location{parisTexas, eiffelTower, parisFrance},
+	}
+	geocodeTool, err := geocodetool.New(geocodetool.Config{Provider: provider})
+	if err != nil {
+		t.Fatal(err)
+	}
+	toolImpl := geocodeTool.(toolinternal.FunctionTool)
+
+	reverseEiffel := eiffelTower
+	reverseEiffel.Confidence = 1
+
+	tests := []struct {
+		name    string
+		args    map[string]any
+		want    geocodetool.Result
+		wantErr bool
+	}{
+		{
+			name: "unambiguous address",
+			args: map[string]any{"address": "eiffel tower"},
+			want: geocodetool.Result{Candidates: []geocodetool.Location{eiffelTower}},
+		},
+		{
+			name: "ambiguous address ranked by confidence",
+			args: map[string]any{"address": "Paris"},
+			want: geocodetool.Result{
+				Candidates: []geocodetool.Location{eiffelTower, parisFrance, parisTexas},
+				Ambiguous:  true,
+			},
+		},
+		{
+			name: "no match",
+			args: map[string]any{"address": "Atlantis"},
+			want: geocodetool.Result{Candidates: []geocodetool.Location{}},
+		},
+		{
+			name: "reverse geocoding",
+			args: map[string]any{"latitude": 48.8584, "longitude": 2.2945},
+			want: geocodetool.Result{Candidates: []geocodetool.Location{reverseEiffel}},
+		},
+		{
+			name:    "both address and coordinates",
+			args:    map[string]any{"address": "Paris", "latitude": 48.8584, "longitude": 2.2945},
+			wantErr: true,
+		},
+		{
+			name:    "missing longitude",
+			args:    map[string]any{"latitude": 48.8584},
+			wantErr: true,
+		},
+		{
+			name:    "coordinates out of range",
+			args:    map[string]any{"latitude": 120.0, "longitude": 2.0},
+			wantErr: true,
+		},
+		{
+			name:    "empty",
+			args:    map[string]any{},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := toolImpl.Run(nil, tt.args)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Run() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			result, err := typeutil.ConvertToWithJSONSchema[map[string]any, geocodetool.Result](got, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tt.want, result); diff != "" {
+				t.Errorf("Run() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+type failingProvider struct{}
+
+func (failingProvider) Geocode(ctx context.Context, address string) ([]geocodetool.Location, error) {
+	return nil, errors.New("quota exceeded")
+}
+
+func (failingProvider) ReverseGeocode(ctx context.Context, latitude, longitude float64) ([]geocodetool.Location, error) {
+	return nil, errors.New("quota exceeded")
+}
+
+func TestGeocodeTool_ProviderError(t *testing.T) {
+	geocodeTool, err := geocodetool.New(geocodetool.Config{Provider: failingProvider{}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = geocodeTool.(toolinternal.FunctionTool).Run(nil, map[string]any{"address": "Paris"})
+	if err == nil {
+		t.Fatal("Run() succeeded, want error")
+	}
+	if want := "geocoding failed: quota exceeded"; err.Error() != want {
+		t.Errorf("Run() error = %q, want %q", err, want)
+	}
+}
@@ -0,0 +1,106 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package imagegentool
+
+import (
+	"context"
+	"errors"
+
+	"google.golang.org/genai"
+)
+
+// Request is an image generation request.
+type Request struct {
+	Prompt         string
+	NegativePrompt string
+	// AspectRatio of the image, e.g. "16:9".
+	AspectRatio string
+	// Size of the image, e.g. "1K" or "2K". Empty for the model default.
+	Size string
+}
+
+// Image is a generated image.
+type Image struct {
+	Data     []byte
+	MIMEType string
+	// RevisedPrompt is the prompt actually used by the model, if it rewrote
+	// the request.
+	RevisedPrompt string
+}
+
+// SafetyError is returned by an [ImageModel] when the prompt or the
+// generated image was rejected by safety filters.
+type SafetyError struct {
+	// Reason reported by the model, if any.
+	Reason string
+}
+
+func (e *SafetyError) Error() string {
+	if e.Reason == "" {
+		return "image generation was blocked by safety filters"
+	}
+	return "image generation was blocked by safety filters: " + e.Reason
+}
+
+// ImageModel generates images.
+type ImageModel interface {
+	// GenerateImage generates an image for the request. It returns a
+	// *SafetyError if the request was rejected by safety filters.
+	GenerateImage(ctx context.Context, req *Request) (*Image, error)
+}
+
+type genaiModel struct {
+	client *genai.Client
+	name   string
+}
+
+// NewGenaiModel returns an [ImageModel] generating images with the given
+// model, e.g. "imagen-4.0-generate-001", of the genai client.
+func NewGenaiModel(client *genai.Client, modelName string) ImageModel {
+	return &genaiModel{client: client, name: modelName}
+}
+
+func (m *genaiModel) GenerateImage(ctx context.Context, req *Request) (*Image, error) {
+	resp, err := m.client.Models.GenerateImages(ctx, m.name, req.Prompt, &genai.GenerateImagesConfig{
+		NegativePrompt:   req.NegativePrompt,
+		NumberOfImages:   1,
+		AspectRatio:      req.AspectRatio,
+		ImageSize:        req.Size,
+		IncludeRAIReason: true,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if resp == nil || len(resp.GeneratedImages) == 0 {
+		// Images filtered out without a reason are omitted from the response.
+		return nil, &SafetyError{}
+	}
+	generated := resp.GeneratedImages[0]
+	if generated.RAIFilteredReason != "" {
+		return nil, &SafetyError{Reason: generated.RAIFilteredReason}
+	}
+	if generated.Image == nil || len(generated.Image.ImageBytes) == 0 {
+		return nil, errors.New("the model returned no image data")
+	}
+	mimeType := generated.Image.MIMEType
+	if mimeType == "" {
+		mimeType = "image/png"
+	}
+	return &Image{
+		Data:          generated.Image.ImageBytes,
+		MIMEType:      mimeType,
+		RevisedPrompt: generated.EnhancedPrompt,
+	}, nil
+}
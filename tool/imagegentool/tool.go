@@ -0,0 +1,187 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package imagegentool provides a tool that generates images with a
+// pluggable [ImageModel] and stores them as artifacts.
+//
+// The generated image is not returned inline to the model: it is saved as an
+// artifact of the session and the tool returns its name, version and an
+// artifact reference (see artifact.NewReference) that clients can use to
+// display it. Requests rejected by the safety filters of the model are
+// reported in the result with the "rejected" status, so the model can
+// rephrase the prompt or explain the refusal to the user.
+package imagegentool
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/artifact"
+	"google.golang.org/adk/tool"
+	"google.golang.org/adk/tool/functiontool"
+)
+
+const (
+	defaultName = "generate_image"
+
+	// StatusGenerated is the status of a generated image.
+	StatusGenerated = "generated"
+	// StatusRejected is the status of a request rejected by safety filters.
+	StatusRejected = "rejected"
+)
+
+var (
+	defaultAspectRatios = []string{"1:1", "3:4", "4:3", "9:16", "16:9"}
+	defaultSizes        = []string{"1K", "2K"}
+)
+
+// Config is the configuration of the image generation tool.
+type Config struct {
+	// Name of the tool as seen by the model. Defaults to "generate_image".
+	Name string
+	// Model generating the images. Required.
+	Model ImageModel
+	// AspectRatios the model may request. The first one is the default.
+	// Defaults to 1:1, 3:4, 4:3, 9:16 and 16:9.
+	AspectRatios []string
+	// Sizes the model may request. Defaults to 1K and 2K. When the size is
+	// omitted, the image model default is used.
+	Sizes []string
+}
+
+// Args are the arguments the model provides.
+type Args struct {
+	Prompt         string `json:"prompt" jsonschema:"a detailed description of the image to generate"`
+	NegativePrompt string `json:"negative_prompt,omitempty" jsonschema:"what the image should not contain"`
+	AspectRatio    string `json:"aspect_ratio,omitempty" jsonschema:"the aspect ratio of the image"`
+	Size           string `json:"size,omitempty" jsonschema:"the size of the image"`
+}
+
+// Result is the response of the tool.
+type Result struct {
+	// Status is StatusGenerated or StatusRejected.
+	Status string `json:"status"`
+	// Artifact is the name of the artifact holding the image, and Version
+	// its version.
+	Artifact string `json:"artifact,omitempty"`
+	Version  int64  `json:"version,omitempty"`
+	// Reference is the URI of the artifact reference of the image.
+	Reference     string `json:"reference,omitempty"`
+	MIMEType      string `json:"mime_type,omitempty"`
+	AspectRatio   string `json:"aspect_ratio,omitempty"`
+	Size          string `json:"size,omitempty"`
+	RevisedPrompt string `json:"revised_prompt,omitempty"`
+	// Error explains why the request was rejected.
+	Error string `json:"error,omitempty"`
+}
+
+// New creates an image generation tool.
+func New(cfg Config) (tool.Tool, error) {
+	if cfg.Model == nil {
+		return nil, errors.New("image model is required")
+	}
+	if cfg.Name == "" {
+		cfg.Name = defaultName
+	}
+	if len(cfg.AspectRatios) == 0 {
+		cfg.AspectRatios = defaultAspectRatios
+	}
+	if len(cfg.Sizes) == 0 {
+		cfg.Sizes = defaultSizes
+	}
+
+	g := &generator{cfg: cfg}
+	imageTool, err := functiontool.New(functiontool.Config{
+		Name: cfg.Name,
+		Description: "Generates an image from a text prompt and stores it as an artifact.\n" +
+			fmt.Sprintf("Supported aspect ratios: %s (default %s). ", strings.Join(cfg.AspectRatios, ", "), cfg.AspectRatios[0]) +
+			fmt.Sprintf("Supported sizes: %s.\n", strings.Join(cfg.Sizes, ", ")) +
+			"If the request is rejected, do not retry the same prompt; rephrase it or tell the user why.",
+	}, g.generate)
+	if err != nil {
+		return nil, fmt.Errorf("error creating image generation tool: %w", err)
+	}
+	return imageTool, nil
+}
+
+type generator struct {
+	cfg Config
+}
+
+func (g *generator) generate(ctx tool.Context, args Args) (Result, error) {
+	prompt := strings.TrimSpace(args.Prompt)
+	if prompt == "" {
+		return Result{}, errors.New("prompt must not be empty")
+	}
+	aspectRatio := args.AspectRatio
+	if aspectRatio == "" {
+		aspectRatio = g.cfg.AspectRatios[0]
+	}
+	if !slices.Contains(g.cfg.AspectRatios, aspectRatio) {
+		return Result{}, fmt.Errorf("unsupported aspect ratio %q, want one of: %s", aspectRatio, strings.Join(g.cfg.AspectRatios, ", "))
+	}
+	size := strings.ToUpper(args.Size)
+	if size != "" && !slices.Contains(g.cfg.Sizes, size) {
+		return Result{}, fmt.Errorf("unsupported size %q, want one of: %s", args.Size, strings.Join(g.cfg.Sizes, ", "))
+	}
+
+	img, err := g.cfg.Model.GenerateImage(ctx, &Request{
+		Prompt:         prompt,
+		NegativePrompt: args.NegativePrompt,
+		AspectRatio:    aspectRatio,
+		Size:           size,
+	})
+	if safetyErr := (*SafetyError)(nil); errors.As(err, &safetyErr) {
+		return Result{Status: StatusRejected, Error: safetyErr.Error()}, nil
+	}
+	if err != nil {
+		return Result{}, fmt.Errorf("image generation failed: %w", err)
+	}
+
+	name := artifactName(ctx.FunctionCallID(), img.MIMEType)
+	saved, err := ctx.Artifacts().Save(ctx, name, genai.NewPartFromBytes(img.Data, img.MIMEType))
+	if err != nil {
+		return Result{}, fmt.Errorf("failed to save the generated image: %w", err)
+	}
+	return Result{
+		Status:        StatusGenerated,
+		Artifact:      name,
+		Version:       saved.Version,
+		Reference:     artifact.NewReference(name, saved.Version, img.MIMEType).FileData.FileURI,
+		MIMEType:      img.MIMEType,
+		AspectRatio:   aspectRatio,
+		Size:          size,
+		RevisedPrompt: img.RevisedPrompt,
+	}, nil
+}
+
+// artifactName returns the name of the artifact of the image generated by
+// the function call.
+func artifactName(functionCallID, mimeType string) string {
+	ext := ".png"
+	switch mimeType {
+	case "image/jpeg":
+		ext = ".jpg"
+	case "image/webp":
+		ext = ".webp"
+	}
+	if functionCallID == "" {
+		return "generated_image" + ext
+	}
+	return "generated_image_" + functionCallID + ext
+}
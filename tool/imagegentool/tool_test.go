@@ -0,0 +1,117 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package imagegentool_test
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"google.golang.org/adk/artifact"
+	artifactinternal "google.golang.org/adk/internal/artifact"
+	icontext "google.golang.org/adk/internal/context"
+	"google.golang.org/adk/internal/toolinternal"
+	"google.golang.org/adk/tool"
+	"google.golang.org/adk/tool/imagegentool"
+)
+
+type fakeModel struct {
+	requests []*imagegentool.Request
+}
+
+func (m *fakeModel) GenerateImage(ctx context.Context, req *imagegentool.Request) (*imagegentool.Image, error) {
+	m.requests = append(m.requests, req)
+	if strings.Contains(req.Prompt, "gore") {
+		return nil, &imagegentool.SafetyError{Reason: "violence"}
+	}
+	return &imagegentool.Image{Data: []byte("png data"), MIMEType: "image/png", RevisedPrompt: "a cute " + req.Prompt}, nil
+}
+
+func createToolContext(t *testing.T) tool.Context {
+	t.Helper()
+	ctx := icontext.NewInvocationContext(t.Context(), icontext.InvocationContextParams{
+		Artifacts: &artifactinternal.Artifacts{
+			Service:   artifact.InMemoryService(),
+			AppName:   "app",
+			UserID:    "user",
+			SessionID: "session",
+		},
+	})
+	return toolinternal.NewToolContext(ctx, "call1", nil)
+}
+
+func TestGenerateImage(t *testing.T) {
+	m := &fakeModel{}
+	imageTool, err := imagegentool.New(imagegentool.Config{Model: m})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := createToolContext(t)
+	run := func(args map[string]any) (map[string]any, error) {
+		return imageTool.(toolinternal.FunctionTool).Run(ctx, args)
+	}
+
+	got, err := run(map[string]any{"prompt": "a cat", "aspect_ratio": "16:9", "size": "2k"})
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	want := map[string]any{
+		"status":         imagegentool.StatusGenerated,
+		"artifact":       "generated_image_call1.png",
+		"version":        float64(1),
+		"reference":      "artifact:generated_image_call1.png?version=1",
+		"mime_type":      "image/png",
+		"aspect_ratio":   "16:9",
+		"size":           "2K",
+		"revised_prompt": "a cute a cat",
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Run() mismatch (-want +got):\n%s", diff)
+	}
+	loaded, err := ctx.Artifacts().Load(ctx, "generated_image_call1.png")
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if !bytes.Equal(loaded.Part.InlineData.Data, []byte("png data")) {
+		t.Errorf("saved image = %q, want %q", loaded.Part.InlineData.Data, "png data")
+	}
+	if diff := cmp.Diff(&imagegentool.Request{Prompt: "a cat", AspectRatio: "16:9", Size: "2K"}, m.requests[0]); diff != "" {
+		t.Errorf("request mismatch (-want +got):\n%s", diff)
+	}
+
+	got, err = run(map[string]any{"prompt": "some gore"})
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if got["status"] != imagegentool.StatusRejected || !strings.Contains(got["error"].(string), "violence") {
+		t.Errorf("Run() = %v, want a rejection mentioning the reason", got)
+	}
+	if got := m.requests[1].AspectRatio; got != "1:1" {
+		t.Errorf("default aspect ratio = %q, want 1:1", got)
+	}
+
+	for _, args := range []map[string]any{
+		{"prompt": " "},
+		{"prompt": "a cat", "aspect_ratio": "2:1"},
+		{"prompt": "a cat", "size": "8K"},
+	} {
+		if _, err := run(args); err == nil {
+			t.Errorf("Run(%v) succeeded, want error", args)
+		}
+	}
+}
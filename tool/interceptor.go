@@ -0,0 +1,52 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tool
+
+import "slices"
+
+// Handler runs a call of the tool with the arguments sent by the model, and
+// returns the result reported to the model, see Interceptor.
+type Handler func(ctx Context, t Tool, args map[string]any) (map[string]any, error)
+
+// Interceptor wraps the handler of the tool calls, e.g. to log the calls,
+// redact their arguments, check a quota or record metrics, uniformly across
+// the tools of an agent or of a runner, without wrapping each tool. It
+// returns the handler running the call, which usually calls next, possibly
+// with other arguments, and may inspect or replace its result and error:
+//
+//	func logCalls(next tool.Handler) tool.Handler {
+//		return func(ctx tool.Context, t tool.Tool, args map[string]any) (map[string]any, error) {
+//			result, err := next(ctx, t, args)
+//			log.Printf("tool %s: %v", t.Name(), err)
+//			return result, err
+//		}
+//	}
+//
+// The interceptors wrap the Run of the function tools, after the before tool
+// callbacks of the agent, which may skip the call, and before its after tool
+// callbacks. Their errors are reported to the model as the errors of the
+// tools, see ToolError.
+type Interceptor func(next Handler) Handler
+
+// Chain returns the interceptor applying the interceptors in order, the first
+// one being the outermost: it sees the call first, and its result last.
+func Chain(interceptors ...Interceptor) Interceptor {
+	return func(next Handler) Handler {
+		for _, interceptor := range slices.Backward(interceptors) {
+			next = interceptor(next)
+		}
+		return next
+	}
+}
@@ -21,8 +21,11 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"mime"
+	"strings"
+	"sync"
+	"unicode/utf8"
 
-	"golang.org/x/sync/errgroup"
 	"google.golang.org/genai"
 
 	"google.golang.org/adk/agent"
@@ -36,13 +39,30 @@ import (
 type artifactsTool struct {
 	name        string
 	description string
+	maxBytes    int
+}
+
+// Config is the configuration of the load artifacts tool.
+type Config struct {
+	// MaxBytes caps the total size in bytes of the artifacts loaded by a
+	// call, to keep them from filling the context window of the model. The
+	// artifacts are added in the requested order; those exceeding the
+	// remaining allowance are replaced with a note telling their size. Zero,
+	// the default, means no limit.
+	MaxBytes int
 }
 
 // New creates a new loadArtifactsTool.
 func New() tool.Tool {
+	return NewWithConfig(Config{})
+}
+
+// NewWithConfig creates a new loadArtifactsTool with the configuration.
+func NewWithConfig(cfg Config) tool.Tool {
 	return &artifactsTool{
 		name:        "load_artifacts",
 		description: "Loads the artifacts and adds them to the session.",
+		maxBytes:    cfg.MaxBytes,
 	}
 }
 
@@ -159,62 +179,133 @@ func (t *artifactsTool) processLoadArtifactsFunctionCall(ctx tool.Context, req *
 	if lastContent == nil || len(lastContent.Parts) == 0 {
 		return nil
 	}
-	firstPart := lastContent.Parts[0]
-	if firstPart.FunctionResponse == nil {
-		return nil
+	// The response is not the first part when the model called other
+	// functions in parallel.
+	var functionResponse *genai.FunctionResponse
+	for _, part := range lastContent.Parts {
+		if part != nil && part.FunctionResponse != nil && part.FunctionResponse.Name == t.name {
+			functionResponse = part.FunctionResponse
+			break
+		}
 	}
-
-	functionResponse := firstPart.FunctionResponse
-
-	if functionResponse.Name != "load_artifacts" {
+	if functionResponse == nil {
 		return nil
 	}
 	artifactNamesRaw, ok := functionResponse.Response["artifact_names"]
 	if !ok {
 		return nil
 	}
-	artifactNames, ok := artifactNamesRaw.([]string)
-	if !ok {
-		return fmt.Errorf("invalid artifact names type: %T, expected []string", artifactNamesRaw)
+	artifactNames, err := toStrings(artifactNamesRaw)
+	if err != nil {
+		return err
 	}
 	if len(artifactNames) == 0 {
 		return nil
 	}
 
-	results := make([]*genai.Content, len(artifactNames))
-	group, childCtx := errgroup.WithContext(ctx)
+	// An artifact that cannot be loaded, e.g. because it doesn't exist, is
+	// reported to the model in place of its content, without failing the
+	// others.
+	parts := make([]*genai.Part, len(artifactNames))
+	loadErrs := make([]error, len(artifactNames))
+	var wg sync.WaitGroup
 	artifactsService := ctx.Artifacts()
+	for i, artifactName := range artifactNames {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			parts[i], loadErrs[i] = loadIndividualArtifact(ctx, artifactsService, artifactName)
+		}()
+	}
+	wg.Wait()
 
+	remaining := t.maxBytes
 	for i, artifactName := range artifactNames {
-		group.Go(func() error {
-			// Although not used, we need to pass childCtx for early return in case of an error.
-			content, err := t.loadIndividualArtifact(childCtx, artifactsService, artifactName)
-			if err != nil {
-				return fmt.Errorf("failed to load artifact %s: %w", artifactName, err)
+		var content *genai.Content
+		switch size := partSize(parts[i]); {
+		case loadErrs[i] != nil:
+			content = genai.NewContentFromText(fmt.Sprintf("Artifact %s could not be loaded: %v", artifactName, loadErrs[i]), genai.RoleUser)
+		case t.maxBytes > 0 && size > remaining:
+			content = genai.NewContentFromText(fmt.Sprintf("Artifact %s is not loaded: its size of %d bytes exceeds the remaining limit of %d bytes.", artifactName, size, remaining), genai.RoleUser)
+		default:
+			remaining -= size
+			content = &genai.Content{
+				Parts: []*genai.Part{
+					genai.NewPartFromText("Artifact " + artifactName + " is:"),
+					parts[i],
+				},
+				Role: genai.RoleUser,
 			}
-			results[i] = content
-			return nil
-		})
+		}
+		req.Contents = append(req.Contents, content)
 	}
+	return nil
+}
 
-	if err := group.Wait(); err != nil {
-		return err
+// toStrings converts the artifact names of the function response, a []string
+// or, once stored in a session, a []any.
+func toStrings(v any) ([]string, error) {
+	switch v := v.(type) {
+	case []string:
+		return v, nil
+	case []any:
+		names := make([]string, len(v))
+		for i, name := range v {
+			s, ok := name.(string)
+			if !ok {
+				return nil, fmt.Errorf("invalid artifact name type: %T, expected string", name)
+			}
+			names[i] = s
+		}
+		return names, nil
 	}
+	return nil, fmt.Errorf("invalid artifact names type: %T, expected []string", v)
+}
 
-	req.Contents = append(req.Contents, results...)
-	return nil
+// partSize returns the size in bytes of the content of the part.
+func partSize(part *genai.Part) int {
+	if part == nil {
+		return 0
+	}
+	if part.InlineData != nil {
+		return len(part.InlineData.Data)
+	}
+	return len(part.Text)
 }
 
-func (t *artifactsTool) loadIndividualArtifact(ctx context.Context, artifactsService agent.Artifacts, artifactName string) (*genai.Content, error) {
+func loadIndividualArtifact(ctx context.Context, artifactsService agent.Artifacts, artifactName string) (*genai.Part, error) {
 	resp, err := artifactsService.Load(ctx, artifactName)
 	if err != nil {
-		return nil, fmt.Errorf("failed to load artifact %s: %w", artifactName, err)
+		return nil, err
 	}
-	return &genai.Content{
-		Parts: []*genai.Part{
-			genai.NewPartFromText("Artifact " + artifactName + " is:"),
-			resp.Part,
-		},
-		Role: genai.RoleUser,
-	}, nil
+	return textPart(resp.Part), nil
+}
+
+// textPart returns the part of a text artifact saved as inline data, e.g. a
+// CSV or JSON file, as a text part: the models reject the inline data of most
+// text MIME types. The other parts are returned as is.
+func textPart(part *genai.Part) *genai.Part {
+	if part == nil || part.InlineData == nil || !isText(part.InlineData.MIMEType) || !utf8.Valid(part.InlineData.Data) {
+		return part
+	}
+	return genai.NewPartFromText(string(part.InlineData.Data))
+}
+
+// isText reports whether the MIME type is of text.
+func isText(mimeType string) bool {
+	mediaType, _, err := mime.ParseMediaType(mimeType)
+	if err != nil {
+		return false
+	}
+	switch {
+	case strings.HasPrefix(mediaType, "text/"),
+		mediaType == "application/json",
+		strings.HasSuffix(mediaType, "+json"),
+		mediaType == "application/xml",
+		strings.HasSuffix(mediaType, "+xml"),
+		mediaType == "application/yaml",
+		mediaType == "application/x-yaml":
+		return true
+	}
+	return false
 }
@@ -229,6 +229,61 @@ func TestLoadArtifactsTool_ProcessRequest_Artifacts_LoadArtifactsFunctionCall(t
 	}
 }
 
+func TestLoadArtifactsTool_ProcessRequest_MissingAndLimited(t *testing.T) {
+	loadArtifactsTool := loadartifactstool.NewWithConfig(loadartifactstool.Config{MaxBytes: 10})
+
+	tc := createToolContext(t)
+	artifacts := map[string]*genai.Part{
+		"small.txt": {Text: "0123456"},
+		"large.txt": {Text: "0123456789"},
+		"tiny.txt":  {Text: "abc"},
+	}
+	for name, part := range artifacts {
+		if _, err := tc.Artifacts().Save(t.Context(), name, part); err != nil {
+			t.Fatalf("Failed to save artifact %s: %v", name, err)
+		}
+	}
+	llmRequest := &model.LLMRequest{
+		Contents: []*genai.Content{
+			{
+				Role: "model",
+				Parts: []*genai.Part{
+					// The artifact names of a response read from a session are a []any.
+					genai.NewPartFromFunctionResponse("load_artifacts", map[string]any{
+						"artifact_names": []any{"small.txt", "missing.txt", "large.txt", "tiny.txt"},
+					}),
+				},
+			},
+		},
+	}
+
+	if err := loadArtifactsTool.(toolinternal.RequestProcessor).ProcessRequest(tc, llmRequest); err != nil {
+		t.Fatalf("ProcessRequest failed: %v", err)
+	}
+	var got []string
+	for _, content := range llmRequest.Contents[1:] {
+		var texts []string
+		for _, part := range content.Parts {
+			texts = append(texts, part.Text)
+		}
+		got = append(got, strings.Join(texts, " "))
+	}
+	want := []string{
+		"Artifact small.txt is: 0123456",
+		"Artifact missing.txt could not be loaded: ",
+		"Artifact large.txt is not loaded: its size of 10 bytes exceeds the remaining limit of 3 bytes.",
+		"Artifact tiny.txt is: abc",
+	}
+	if len(got) != len(want) {
+		t.Fatalf("appended contents = %q, want %q", got, want)
+	}
+	for i := range want {
+		if !strings.HasPrefix(got[i], want[i]) {
+			t.Errorf("appended content %d = %q, want %q", i, got[i], want[i])
+		}
+	}
+}
+
 func TestLoadArtifactsTool_ProcessRequest_Artifacts_OtherFunctionCall(t *testing.T) {
 	loadArtifactsTool := loadartifactstool.New()
 
@@ -293,3 +348,46 @@ func createToolContext(t *testing.T) tool.Context {
 
 	return toolinternal.NewToolContext(ctx, "", nil)
 }
+
+func TestLoadArtifactsTool_ProcessRequest_ParallelCallsAndTextData(t *testing.T) {
+	tc := createToolContext(t)
+	artifacts := map[string]*genai.Part{
+		"data.csv":  genai.NewPartFromBytes([]byte("a,b\n1,2\n"), "text/csv; charset=utf-8"),
+		"data.json": genai.NewPartFromBytes([]byte(`{"a":1}`), "application/json"),
+		"image.png": genai.NewPartFromBytes([]byte("png"), "image/png"),
+	}
+	for name, part := range artifacts {
+		if _, err := tc.Artifacts().Save(t.Context(), name, part); err != nil {
+			t.Fatalf("Failed to save artifact %s: %v", name, err)
+		}
+	}
+	llmRequest := &model.LLMRequest{
+		Contents: []*genai.Content{
+			{
+				Role: "user",
+				Parts: []*genai.Part{
+					genai.NewPartFromFunctionResponse("other_function", map[string]any{}),
+					genai.NewPartFromFunctionResponse("load_artifacts", map[string]any{
+						"artifact_names": []string{"data.csv", "data.json", "image.png"},
+					}),
+				},
+			},
+		},
+	}
+
+	if err := loadartifactstool.New().(toolinternal.RequestProcessor).ProcessRequest(tc, llmRequest); err != nil {
+		t.Fatalf("ProcessRequest failed: %v", err)
+	}
+	if len(llmRequest.Contents) != 4 {
+		t.Fatalf("Expected 4 contents, but got: %v", llmRequest.Contents)
+	}
+	if got := llmRequest.Contents[1].Parts[1].Text; got != "a,b\n1,2\n" {
+		t.Errorf("CSV artifact part text = %q, want the CSV", got)
+	}
+	if got := llmRequest.Contents[2].Parts[1].Text; got != `{"a":1}` {
+		t.Errorf("JSON artifact part text = %q, want the JSON", got)
+	}
+	if got := llmRequest.Contents[3].Parts[1].InlineData; got == nil || got.MIMEType != "image/png" {
+		t.Errorf("image artifact part inline data = %v, want the image", got)
+	}
+}
@@ -0,0 +1,130 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package loadmemorytool defines the load_memory tool, which lets the model
+// search the memory of the past conversations of the user, see the memory
+// package and runner.Config.MemoryService.
+//
+// The model calls the tool with a query when it needs to look up the
+// memory; see the preloadmemorytool package to provide the memories relevant
+// to each user message without a call.
+package loadmemorytool
+
+import (
+	"fmt"
+	"time"
+
+	"google.golang.org/genai"
+
+	imemory "google.golang.org/adk/internal/memory"
+	"google.golang.org/adk/internal/toolinternal"
+	"google.golang.org/adk/internal/toolinternal/toolutils"
+	"google.golang.org/adk/internal/utils"
+	"google.golang.org/adk/model"
+	"google.golang.org/adk/tool"
+)
+
+var (
+	_ toolinternal.FunctionTool     = (*memoryTool)(nil)
+	_ toolinternal.RequestProcessor = (*memoryTool)(nil)
+)
+
+const instructions = "You have memory. You can use it to answer questions. If any questions need" +
+	" you to look up the memory, you should call the `load_memory` function with a query."
+
+// memoryTool is a tool that searches the memory.
+type memoryTool struct {
+	name        string
+	description string
+}
+
+// New creates a load_memory tool.
+func New() tool.Tool {
+	return &memoryTool{
+		name:        "load_memory",
+		description: "Loads the memory for the current user.",
+	}
+}
+
+// Name implements tool.Tool.
+func (t *memoryTool) Name() string {
+	return t.name
+}
+
+// Description implements tool.Tool.
+func (t *memoryTool) Description() string {
+	return t.description
+}
+
+// IsLongRunning implements tool.Tool.
+func (t *memoryTool) IsLongRunning() bool {
+	return false
+}
+
+// Declaration implements toolinternal.FunctionTool.
+func (t *memoryTool) Declaration() *genai.FunctionDeclaration {
+	return &genai.FunctionDeclaration{
+		Name:        t.name,
+		Description: t.description,
+		Parameters: &genai.Schema{
+			Type: "OBJECT",
+			Properties: map[string]*genai.Schema{
+				"query": {
+					Type:        "STRING",
+					Description: "The query to search the memory with.",
+				},
+			},
+			Required: []string{"query"},
+		},
+	}
+}
+
+// Run implements toolinternal.FunctionTool. It returns the memories matching
+// the query, each with its author, time and text.
+func (t *memoryTool) Run(ctx tool.Context, args any) (map[string]any, error) {
+	m, ok := args.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("unexpected args type, got: %T", args)
+	}
+	query, ok := m["query"].(string)
+	if !ok {
+		return nil, fmt.Errorf("query must be a string, got: %T", m["query"])
+	}
+	resp, err := ctx.SearchMemory(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search memory: %w", err)
+	}
+	memories := make([]any, 0, len(resp.Memories))
+	for _, entry := range resp.Memories {
+		memory := map[string]any{
+			"author": entry.Author,
+			"text":   imemory.Text(entry.Content),
+		}
+		if !entry.Timestamp.IsZero() {
+			memory["timestamp"] = entry.Timestamp.Format(time.RFC3339)
+		}
+		memories = append(memories, memory)
+	}
+	return map[string]any{"memories": memories}, nil
+}
+
+// ProcessRequest implements toolinternal.RequestProcessor. It declares the
+// tool and instructs the model to use it.
+func (t *memoryTool) ProcessRequest(ctx tool.Context, req *model.LLMRequest) error {
+	if err := toolutils.PackTool(req, t); err != nil {
+		return err
+	}
+	utils.AppendInstructions(req, instructions)
+	return nil
+}
@@ -0,0 +1,99 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package loadmemorytool_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/genai"
+
+	icontext "google.golang.org/adk/internal/context"
+	imemory "google.golang.org/adk/internal/memory"
+	"google.golang.org/adk/internal/toolinternal"
+	"google.golang.org/adk/memory"
+	"google.golang.org/adk/model"
+	"google.golang.org/adk/session"
+	"google.golang.org/adk/tool"
+	"google.golang.org/adk/tool/loadmemorytool"
+)
+
+func createToolContext(t *testing.T, mem memory.Service) tool.Context {
+	t.Helper()
+	params := icontext.InvocationContextParams{}
+	if mem != nil {
+		params.Memory = &imemory.Memory{Service: mem, AppName: "app", UserID: "user", SessionID: "current"}
+	}
+	return toolinternal.NewToolContext(icontext.NewInvocationContext(t.Context(), params), "", nil)
+}
+
+func TestLoadMemoryTool_Run(t *testing.T) {
+	mem := memory.InMemoryService()
+	sessions := session.InMemoryService()
+	created, err := sessions.Create(t.Context(), &session.CreateRequest{AppName: "app", UserID: "user"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	event := session.NewEvent("inv")
+	event.Author = "user"
+	event.Timestamp = time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	event.LLMResponse = model.LLMResponse{Content: genai.NewContentFromText("My favorite color is blue", genai.RoleUser)}
+	if err := sessions.AppendEvent(t.Context(), created.Session, event); err != nil {
+		t.Fatal(err)
+	}
+	if err := mem.AddSession(t.Context(), created.Session); err != nil {
+		t.Fatal(err)
+	}
+
+	loadMemory := loadmemorytool.New().(toolinternal.FunctionTool)
+	got, err := loadMemory.Run(createToolContext(t, mem), map[string]any{"query": "color"})
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	want := map[string]any{"memories": []any{map[string]any{
+		"author":    "user",
+		"text":      "My favorite color is blue",
+		"timestamp": "2025-01-02T03:04:05Z",
+	}}}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Run() mismatch (-want +got):\n%s", diff)
+	}
+
+	if _, err := loadMemory.Run(createToolContext(t, nil), map[string]any{"query": "color"}); err == nil {
+		t.Error("Run() without a memory service succeeded, want error")
+	}
+	if _, err := loadMemory.Run(createToolContext(t, mem), map[string]any{}); err == nil {
+		t.Error("Run() without a query succeeded, want error")
+	}
+}
+
+func TestLoadMemoryTool_ProcessRequest(t *testing.T) {
+	loadMemory := loadmemorytool.New()
+	req := &model.LLMRequest{}
+	if err := loadMemory.(toolinternal.RequestProcessor).ProcessRequest(createToolContext(t, nil), req); err != nil {
+		t.Fatalf("ProcessRequest() error = %v", err)
+	}
+	if req.Tools["load_memory"] != loadMemory {
+		t.Errorf("ProcessRequest() did not register the tool, got tools %v", req.Tools)
+	}
+	if len(req.Config.Tools) != 1 || req.Config.Tools[0].FunctionDeclarations[0].Name != "load_memory" {
+		t.Errorf("ProcessRequest() did not declare the tool, got %v", req.Config.Tools)
+	}
+	if instruction := req.Config.SystemInstruction.Parts[0].Text; !strings.Contains(instruction, "`load_memory`") {
+		t.Errorf("ProcessRequest() instruction = %q, want a mention of the tool", instruction)
+	}
+}
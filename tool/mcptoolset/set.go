@@ -17,7 +17,9 @@ package mcptoolset
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
 	"sync"
 
 	"github.com/modelcontextprotocol/go-sdk/mcp"
@@ -49,16 +51,44 @@ import (
 //			}),
 //		},
 //	})
+//
+// The tool list of the server is cached and refreshed when the server sends a
+// tool list changed notification, so tools added or removed at runtime are
+// picked up by the next LLM request. A request that is already being built
+// keeps the tools it listed; the refresh only applies to later requests. If
+// the model calls a tool that was removed in the meantime, the call fails
+// with an error explaining that the tool is no longer available.
+//
+// The text content blocks of a tool result are returned to the model under
+// "output", and its image and audio blocks are saved as artifacts, whose
+// names are returned under "artifacts". A result marked as an error, or a
+// failed call, is returned as a Go error.
+//
+// Notifications can only be observed on the default client. If a custom
+// Client is provided, the tool list is fetched from the server on every
+// request instead.
+//
+// The returned toolset implements io.Closer: Close ends the MCP session, e.g.
+// stopping the server started by a CommandTransport, once the agents using
+// the toolset are done. If the session ends otherwise, e.g. the server exits
+// or the connection is lost, the next request connects again, which requires
+// a transport able to connect several times, such as an SSE or streamable
+// transport; a CommandTransport only runs its command once.
 func New(cfg Config) (tool.Toolset, error) {
-	client := cfg.Client
-	if client == nil {
-		client = mcp.NewClient(&mcp.Implementation{Name: "adk-mcp-client", Version: version.Version}, nil)
-	}
-	return &set{
-		client:     client,
+	s := &set{
+		client:     cfg.Client,
 		transport:  cfg.Transport,
 		toolFilter: cfg.ToolFilter,
-	}, nil
+	}
+	if s.client == nil {
+		s.client = mcp.NewClient(&mcp.Implementation{Name: "adk-mcp-client", Version: version.Version}, &mcp.ClientOptions{
+			ToolListChangedHandler: func(context.Context, *mcp.ToolListChangedRequest) {
+				s.invalidateTools()
+			},
+		})
+		s.cacheTools = true
+	}
+	return s, nil
 }
 
 // Config provides initial configuration for the MCP ToolSet.
@@ -80,6 +110,16 @@ type set struct {
 
 	mu      sync.Mutex
 	session *mcp.ClientSession
+
+	// cacheTools reports whether the tool list can be cached, i.e. the set
+	// receives tool list changed notifications.
+	cacheTools bool
+
+	toolsMu sync.Mutex
+	// tools is the cached tool list of the server, nil if it must be fetched.
+	tools []*mcp.Tool
+	// toolsGeneration is incremented on every tool list changed notification.
+	toolsGeneration uint64
 }
 
 func (*set) Name() string {
@@ -96,42 +136,91 @@ func (*set) IsLongRunning() bool {
 
 // Tools fetch MCP tools from the server, convert to adk tool.Tool and filter by name.
 func (s *set) Tools(ctx agent.ReadonlyContext) ([]tool.Tool, error) {
-	session, err := s.getSession(ctx)
+	mcpTools, err := s.listTools(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get MCP session: %w", err)
+		return nil, err
 	}
 
 	var adkTools []tool.Tool
-
-	cursor := ""
-	for {
-		resp, err := session.ListTools(ctx, &mcp.ListToolsParams{
-			Cursor: cursor,
-		})
+	for _, mcpTool := range mcpTools {
+		t, err := convertTool(mcpTool, s)
 		if err != nil {
-			return nil, fmt.Errorf("failed to list MCP tools: %w", err)
+			return nil, fmt.Errorf("failed to convert MCP tool %q to adk tool: %w", mcpTool.Name, err)
 		}
 
-		for _, mcpTool := range resp.Tools {
-			t, err := convertTool(mcpTool, s.getSession)
+		if s.toolFilter != nil && !s.toolFilter(ctx, t) {
+			continue
+		}
+
+		adkTools = append(adkTools, t)
+	}
+
+	return adkTools, nil
+}
+
+// listTools returns the tools of the server, from the cache if it is current.
+func (s *set) listTools(ctx context.Context) ([]*mcp.Tool, error) {
+	s.toolsMu.Lock()
+	if s.tools != nil {
+		defer s.toolsMu.Unlock()
+		return s.tools, nil
+	}
+	generation := s.toolsGeneration
+	s.toolsMu.Unlock()
+
+	var mcpTools []*mcp.Tool
+	err := s.withSession(ctx, func(session *mcp.ClientSession) error {
+		mcpTools = []*mcp.Tool{}
+		cursor := ""
+		for {
+			resp, err := session.ListTools(ctx, &mcp.ListToolsParams{
+				Cursor: cursor,
+			})
 			if err != nil {
-				return nil, fmt.Errorf("failed to convert MCP tool %q to adk tool: %w", mcpTool.Name, err)
+				return fmt.Errorf("failed to list MCP tools: %w", err)
 			}
+			mcpTools = append(mcpTools, resp.Tools...)
 
-			if s.toolFilter != nil && !s.toolFilter(ctx, t) {
-				continue
+			if resp.NextCursor == "" {
+				return nil
 			}
-
-			adkTools = append(adkTools, t)
+			cursor = resp.NextCursor
 		}
+	})
+	if err != nil {
+		return nil, err
+	}
 
-		if resp.NextCursor == "" {
-			break
-		}
-		cursor = resp.NextCursor
+	s.toolsMu.Lock()
+	defer s.toolsMu.Unlock()
+	// Don't cache the list if it changed while it was being fetched.
+	if s.cacheTools && generation == s.toolsGeneration {
+		s.tools = mcpTools
 	}
+	return mcpTools, nil
+}
 
-	return adkTools, nil
+// invalidateTools drops the cached tool list after a tool list changed
+// notification.
+func (s *set) invalidateTools() {
+	s.toolsMu.Lock()
+	defer s.toolsMu.Unlock()
+	s.tools = nil
+	s.toolsGeneration++
+}
+
+// availableTools returns the names of the tools currently offered by the
+// server.
+func (s *set) availableTools(ctx context.Context) ([]string, error) {
+	mcpTools, err := s.listTools(ctx)
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, len(mcpTools))
+	for i, t := range mcpTools {
+		names[i] = t.Name
+	}
+	return names, nil
 }
 
 func (s *set) getSession(ctx context.Context) (*mcp.ClientSession, error) {
@@ -148,5 +237,62 @@ func (s *set) getSession(ctx context.Context) (*mcp.ClientSession, error) {
 	}
 
 	s.session = session
+	go func() {
+		// The session ends when the connection is closed, by either side.
+		_ = session.Wait()
+		s.dropSession(session)
+	}()
 	return s.session, nil
 }
+
+// withSession calls f with the MCP session, connecting if needed. If the
+// connection turns out to be closed, f is called once more with a new
+// session.
+func (s *set) withSession(ctx context.Context, f func(*mcp.ClientSession) error) error {
+	for attempt := 0; ; attempt++ {
+		session, err := s.getSession(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to get MCP session: %w", err)
+		}
+		err = f(session)
+		if attempt > 0 || !connectionLost(err) {
+			return err
+		}
+		s.dropSession(session)
+	}
+}
+
+// connectionLost reports whether err tells that the connection of the session
+// is closed, possibly before the session noticed it.
+func connectionLost(err error) bool {
+	return errors.Is(err, mcp.ErrConnectionClosed) || errors.Is(err, io.ErrClosedPipe) || errors.Is(err, io.EOF)
+}
+
+// dropSession forgets the session once it ended, so that the next request
+// connects again. The tools of the server may have changed in the meantime.
+func (s *set) dropSession(session *mcp.ClientSession) {
+	s.mu.Lock()
+	dropped := s.session == session
+	if dropped {
+		s.session = nil
+	}
+	s.mu.Unlock()
+	if dropped {
+		s.invalidateTools()
+	}
+}
+
+// Close closes the MCP session, if any. A later request connects again.
+func (s *set) Close() error {
+	s.mu.Lock()
+	session := s.session
+	s.session = nil
+	s.mu.Unlock()
+	if session == nil {
+		return nil
+	}
+	s.invalidateTools()
+	return session.Close()
+}
+
+var _ io.Closer = (*set)(nil)
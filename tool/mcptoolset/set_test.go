@@ -17,12 +17,15 @@ package mcptoolset_test
 import (
 	"context"
 	"fmt"
+	"io"
 	"iter"
 	"log"
 	"net/http"
 	"path/filepath"
 	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
@@ -31,9 +34,12 @@ import (
 
 	"google.golang.org/adk/agent"
 	"google.golang.org/adk/agent/llmagent"
+	"google.golang.org/adk/artifact"
+	artifactinternal "google.golang.org/adk/internal/artifact"
 	icontext "google.golang.org/adk/internal/context"
 	"google.golang.org/adk/internal/httprr"
 	"google.golang.org/adk/internal/testutil"
+	"google.golang.org/adk/internal/toolinternal"
 	"google.golang.org/adk/model"
 	"google.golang.org/adk/model/gemini"
 	"google.golang.org/adk/runner"
@@ -158,9 +164,9 @@ func TestMCPToolSet(t *testing.T) {
 	}
 
 	if diff := cmp.Diff(wantEvents, gotEvents,
-		cmpopts.IgnoreFields(session.Event{}, "ID", "Timestamp", "InvocationID"),
+		cmpopts.IgnoreFields(session.Event{}, "ID", "Timestamp", "InvocationID", "CorrelationID", "Sequence", "TurnID"),
 		cmpopts.IgnoreFields(session.EventActions{}, "StateDelta"),
-		cmpopts.IgnoreFields(model.LLMResponse{}, "UsageMetadata", "AvgLogprobs", "FinishReason"),
+		cmpopts.IgnoreFields(model.LLMResponse{}, "UsageMetadata", "CustomMetadata", "AvgLogprobs", "FinishReason"),
 		cmpopts.IgnoreFields(genai.FunctionCall{}, "ID"),
 		cmpopts.IgnoreFields(genai.FunctionResponse{}, "ID"),
 		cmpopts.IgnoreFields(genai.Part{}, "ThoughtSignature")); diff != "" {
@@ -307,3 +313,209 @@ func TestToolFilter(t *testing.T) {
 		t.Errorf("tools mismatch (-want +got):\n%s", diff)
 	}
 }
+
+func TestToolListChanged(t *testing.T) {
+	const toolDescription = "returns weather in the given city"
+
+	clientTransport, serverTransport := mcp.NewInMemoryTransports()
+
+	server := mcp.NewServer(&mcp.Implementation{Name: "weather_server", Version: "v1.0.0"}, nil)
+	mcp.AddTool(server, &mcp.Tool{Name: "get_weather", Description: toolDescription}, weatherFunc)
+	mcp.AddTool(server, &mcp.Tool{Name: "get_forecast", Description: toolDescription}, weatherFunc)
+	_, err := server.Connect(t.Context(), serverTransport, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ts, err := mcptoolset.New(mcptoolset.Config{
+		Transport: clientTransport,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create MCP tool set: %v", err)
+	}
+	ctx := icontext.NewReadonlyContext(icontext.NewInvocationContext(t.Context(), icontext.InvocationContextParams{}))
+
+	toolNames := func() []string {
+		tools, err := ts.Tools(ctx)
+		if err != nil {
+			t.Fatalf("Failed to get tools: %v", err)
+		}
+		names := make([]string, len(tools))
+		for i, tool := range tools {
+			names[i] = tool.Name()
+		}
+		return names
+	}
+	waitForTools := func(want []string) {
+		t.Helper()
+		deadline := time.Now().Add(5 * time.Second)
+		for {
+			got := toolNames()
+			if cmp.Equal(want, got) {
+				return
+			}
+			if time.Now().After(deadline) {
+				t.Fatalf("tools = %v, want %v", got, want)
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+	}
+
+	tools, err := ts.Tools(ctx)
+	if err != nil {
+		t.Fatalf("Failed to get tools: %v", err)
+	}
+	waitForTools([]string{"get_forecast", "get_weather"})
+
+	server.RemoveTools("get_forecast")
+	mcp.AddTool(server, &mcp.Tool{Name: "get_time", Description: "returns the time in the given city"}, weatherFunc)
+	waitForTools([]string{"get_time", "get_weather"})
+
+	// The model may still call a tool it was offered before the change.
+	var removed toolinternal.FunctionTool
+	for _, tool := range tools {
+		if tool.Name() == "get_forecast" {
+			removed = tool.(toolinternal.FunctionTool)
+		}
+	}
+	_, err = removed.Run(toolinternal.NewToolContext(icontext.NewInvocationContext(t.Context(), icontext.InvocationContextParams{}), "", nil), map[string]any{"city": "london"})
+	wantErr := `MCP tool "get_forecast" is no longer available on the server, use one of the available tools instead: get_time, get_weather`
+	if err == nil || err.Error() != wantErr {
+		t.Errorf("Run() of removed tool error = %v, want %q", err, wantErr)
+	}
+}
+
+func TestMultipleContentBlocks(t *testing.T) {
+	clientTransport, serverTransport := mcp.NewInMemoryTransports()
+
+	server := mcp.NewServer(&mcp.Implementation{Name: "screenshot_server", Version: "v1.0.0"}, nil)
+	mcp.AddTool(server, &mcp.Tool{Name: "screenshot", Description: "takes a screenshot"}, func(ctx context.Context, req *mcp.CallToolRequest, input Input) (*mcp.CallToolResult, any, error) {
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{
+				&mcp.TextContent{Text: "Screenshot of " + input.City},
+				&mcp.ImageContent{Data: []byte("\x89PNG"), MIMEType: "image/png"},
+			},
+		}, nil, nil
+	})
+	_, err := server.Connect(t.Context(), serverTransport, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ts, err := mcptoolset.New(mcptoolset.Config{
+		Transport: clientTransport,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create MCP tool set: %v", err)
+	}
+	invocationCtx := icontext.NewInvocationContext(t.Context(), icontext.InvocationContextParams{
+		Artifacts: &artifactinternal.Artifacts{
+			Service:   artifact.InMemoryService(),
+			AppName:   "app",
+			UserID:    "user",
+			SessionID: "session",
+		},
+	})
+	tools, err := ts.Tools(icontext.NewReadonlyContext(invocationCtx))
+	if err != nil || len(tools) != 1 {
+		t.Fatalf("Tools() = %v, %v, want the screenshot tool", tools, err)
+	}
+
+	toolCtx := toolinternal.NewToolContext(invocationCtx, "call1", nil)
+	got, err := tools[0].(toolinternal.FunctionTool).Run(toolCtx, map[string]any{"city": "london"})
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	want := map[string]any{
+		"output":    "Screenshot of london",
+		"artifacts": []string{"mcp_screenshot_call1_1.png"},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Run() mismatch (-want +got):\n%s", diff)
+	}
+	saved, err := toolCtx.Artifacts().Load(t.Context(), "mcp_screenshot_call1_1.png")
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if saved.Part.InlineData == nil || string(saved.Part.InlineData.Data) != "\x89PNG" || saved.Part.InlineData.MIMEType != "image/png" {
+		t.Errorf("saved image = %v, want the image content", saved.Part)
+	}
+}
+
+// reconnectingTransport connects to the server over a new in-memory
+// connection each time, as a network transport does.
+type reconnectingTransport struct {
+	server *mcp.Server
+	mu     sync.Mutex
+	// sessions are the server sessions of the connections.
+	sessions []*mcp.ServerSession
+}
+
+func (tr *reconnectingTransport) Connect(ctx context.Context) (mcp.Connection, error) {
+	clientTransport, serverTransport := mcp.NewInMemoryTransports()
+	ss, err := tr.server.Connect(ctx, serverTransport, nil)
+	if err != nil {
+		return nil, err
+	}
+	tr.mu.Lock()
+	tr.sessions = append(tr.sessions, ss)
+	tr.mu.Unlock()
+	return clientTransport.Connect(ctx)
+}
+
+func (tr *reconnectingTransport) lastSession() (*mcp.ServerSession, int) {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+	return tr.sessions[len(tr.sessions)-1], len(tr.sessions)
+}
+
+func TestLifecycle(t *testing.T) {
+	server := mcp.NewServer(&mcp.Implementation{Name: "weather_server", Version: "v1.0.0"}, nil)
+	mcp.AddTool(server, &mcp.Tool{Name: "get_weather", Description: "returns weather in the given city"}, weatherFunc)
+	transport := &reconnectingTransport{server: server}
+
+	ts, err := mcptoolset.New(mcptoolset.Config{Transport: transport})
+	if err != nil {
+		t.Fatalf("Failed to create MCP tool set: %v", err)
+	}
+	invCtx := icontext.NewInvocationContext(t.Context(), icontext.InvocationContextParams{})
+	tools, err := ts.Tools(icontext.NewReadonlyContext(invCtx))
+	if err != nil {
+		t.Fatalf("Failed to get tools: %v", err)
+	}
+	weather := tools[0].(toolinternal.FunctionTool)
+	call := func() {
+		t.Helper()
+		if _, err := weather.Run(toolinternal.NewToolContext(invCtx, "", nil), map[string]any{"city": "london"}); err != nil {
+			t.Fatalf("Run() error = %v", err)
+		}
+	}
+	call()
+
+	// The server ends the session: the next call connects again.
+	first, _ := transport.lastSession()
+	if err := first.Close(); err != nil {
+		t.Fatal(err)
+	}
+	call()
+	second, connections := transport.lastSession()
+	if connections != 2 {
+		t.Fatalf("got %d connections, want 2", connections)
+	}
+
+	// Close ends the session of the client.
+	if err := ts.(io.Closer).Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	done := make(chan error, 1)
+	go func() { done <- second.Wait() }()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("the server session did not end after Close()")
+	}
+	call()
+	if _, connections := transport.lastSession(); connections != 3 {
+		t.Errorf("got %d connections after Close(), want 3", connections)
+	}
+}
@@ -18,6 +18,8 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"mime"
+	"slices"
 	"strings"
 
 	"github.com/modelcontextprotocol/go-sdk/mcp"
@@ -29,9 +31,7 @@ import (
 	"google.golang.org/adk/tool"
 )
 
-type getSessionFunc func(ctx context.Context) (*mcp.ClientSession, error)
-
-func convertTool(t *mcp.Tool, getSessionFunc getSessionFunc) (tool.Tool, error) {
+func convertTool(t *mcp.Tool, s *set) (tool.Tool, error) {
 	mcp := &mcpTool{
 		name:        t.Name,
 		description: t.Description,
@@ -39,7 +39,7 @@ func convertTool(t *mcp.Tool, getSessionFunc getSessionFunc) (tool.Tool, error)
 			Name:        t.Name,
 			Description: t.Description,
 		},
-		getSessionFunc: getSessionFunc,
+		set: s,
 	}
 
 	// Since t.InputSchema and t.OutputSchema are pointers (*jsonschema.Schema) and the destination ResponseJsonSchema
@@ -61,7 +61,7 @@ type mcpTool struct {
 	description     string
 	funcDeclaration *genai.FunctionDeclaration
 
-	getSessionFunc getSessionFunc
+	set *set
 }
 
 // Name implements the tool.Tool.
@@ -88,17 +88,26 @@ func (t *mcpTool) Declaration() *genai.FunctionDeclaration {
 }
 
 func (t *mcpTool) Run(ctx tool.Context, args any) (map[string]any, error) {
-	session, err := t.getSessionFunc(ctx)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get session: %w", err)
-	}
+	return toolinternal.TraceRun(ctx, t.name, args, func(ctx tool.Context) (map[string]any, error) {
+		return t.run(ctx, args)
+	})
+}
 
+func (t *mcpTool) run(ctx tool.Context, args any) (map[string]any, error) {
 	// TODO: add auth
-	res, err := session.CallTool(ctx, &mcp.CallToolParams{
-		Name:      t.name,
-		Arguments: args,
+	var res *mcp.CallToolResult
+	err := t.set.withSession(ctx, func(session *mcp.ClientSession) error {
+		var err error
+		res, err = session.CallTool(ctx, &mcp.CallToolParams{
+			Name:      t.name,
+			Arguments: args,
+		})
+		return err
 	})
 	if err != nil {
+		if removedErr := t.checkAvailable(ctx); removedErr != nil {
+			return nil, removedErr
+		}
 		return nil, fmt.Errorf("failed to call MCP tool %q with err: %w", t.name, err)
 	}
 
@@ -129,25 +138,72 @@ func (t *mcpTool) Run(ctx tool.Context, args any) (map[string]any, error) {
 	}
 
 	textResponse := strings.Builder{}
-
-	for _, c := range res.Content {
-		textContent, ok := c.(*mcp.TextContent)
-		if !ok {
+	var artifacts []string
+	for i, c := range res.Content {
+		var data []byte
+		var mimeType string
+		switch c := c.(type) {
+		case *mcp.TextContent:
+			if _, err := textResponse.WriteString(c.Text); err != nil {
+				return nil, fmt.Errorf("failed to write text response: %w", err)
+			}
+			continue
+		case *mcp.ImageContent:
+			data, mimeType = c.Data, c.MIMEType
+		case *mcp.AudioContent:
+			data, mimeType = c.Data, c.MIMEType
+		default:
 			continue
 		}
-
-		if _, err := textResponse.WriteString(textContent.Text); err != nil {
-			return nil, fmt.Errorf("failed to write text response: %w", err)
+		// Binary content is saved as artifacts, which the model loads if it
+		// needs them, rather than inlined in the response.
+		name := contentArtifactName(t.name, ctx.FunctionCallID(), i, mimeType)
+		if _, err := ctx.Artifacts().Save(ctx, name, genai.NewPartFromBytes(data, mimeType)); err != nil {
+			return nil, fmt.Errorf("failed to save %s content of MCP tool %q: %w", mimeType, t.name, err)
 		}
+		artifacts = append(artifacts, name)
 	}
 
-	if textResponse.Len() == 0 {
+	if textResponse.Len() == 0 && len(artifacts) == 0 {
 		return nil, errors.New("no text content in tool response")
 	}
 
-	return map[string]any{
-		"output": textResponse.String(),
-	}, nil
+	result := map[string]any{}
+	if textResponse.Len() > 0 {
+		result["output"] = textResponse.String()
+	}
+	if len(artifacts) > 0 {
+		result["artifacts"] = artifacts
+	}
+	return result, nil
+}
+
+// contentArtifactName returns the name of the artifact holding the i-th
+// content block of the result of a call, e.g. "mcp_screenshot_call1_0.png".
+func contentArtifactName(toolName, callID string, i int, mimeType string) string {
+	name := fmt.Sprintf("mcp_%s_%s_%d", toolName, callID, i)
+	if callID == "" {
+		name = fmt.Sprintf("mcp_%s_%d", toolName, i)
+	}
+	if exts, err := mime.ExtensionsByType(mimeType); err == nil && len(exts) > 0 {
+		name += exts[0]
+	}
+	return name
+}
+
+// checkAvailable returns an error if the tool was removed from the server
+// after it was offered to the model.
+func (t *mcpTool) checkAvailable(ctx context.Context) error {
+	// The tool list changed notification may not have arrived yet.
+	t.set.invalidateTools()
+	available, err := t.set.availableTools(ctx)
+	if err != nil || slices.Contains(available, t.name) {
+		return nil
+	}
+	if len(available) == 0 {
+		return fmt.Errorf("MCP tool %q is no longer available on the server, which currently offers no tools", t.name)
+	}
+	return fmt.Errorf("MCP tool %q is no longer available on the server, use one of the available tools instead: %s", t.name, strings.Join(available, ", "))
 }
 
 var (
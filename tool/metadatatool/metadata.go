@@ -0,0 +1,45 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package metadatatool exposes a function tool under another name or
+// description, e.g. a generic search tool described differently to the
+// agents of distinct domains, without wrapping its handler again.
+package metadatatool
+
+import (
+	"google.golang.org/adk/internal/toolinternal"
+	"google.golang.org/adk/tool"
+)
+
+// WithMetadata returns a tool behaving like the function tool t, except that
+// its name and description, and thus its declaration to the model, are name
+// and description. An empty name or description keeps the one of t. The
+// model calls the tool by its new name, and the calls are run by t, with the
+// same schemas.
+//
+// The tools which are not declared to the model as functions, such as its
+// built-in tools whose names are fixed, are returned as is.
+func WithMetadata(t tool.Tool, name, description string) tool.Tool {
+	funcTool, ok := t.(toolinternal.FunctionTool)
+	if !ok || funcTool.Declaration() == nil {
+		return t
+	}
+	if name == "" {
+		name = t.Name()
+	}
+	if description == "" {
+		description = t.Description()
+	}
+	return toolinternal.Rename(funcTool, name, description)
+}
@@ -0,0 +1,153 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metadatatool_test
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/agent/llmagent"
+	"google.golang.org/adk/internal/testutil"
+	"google.golang.org/adk/internal/toolinternal"
+	"google.golang.org/adk/model"
+	"google.golang.org/adk/tool"
+	"google.golang.org/adk/tool/functiontool"
+	"google.golang.org/adk/tool/geminitool"
+	"google.golang.org/adk/tool/metadatatool"
+)
+
+type searchArgs struct {
+	Query string `json:"query"`
+}
+
+// newSearchTool returns a search tool recording the queries it runs.
+func newSearchTool(t *testing.T, queries *[]string) tool.Tool {
+	t.Helper()
+	searchTool, err := functiontool.New(functiontool.Config{
+		Name:        "search",
+		Description: "Searches the web.",
+	}, func(_ tool.Context, args searchArgs) (map[string]any, error) {
+		*queries = append(*queries, args.Query)
+		return map[string]any{"hits": 1}, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return searchTool
+}
+
+func TestWithMetadata(t *testing.T) {
+	var queries []string
+	search := newSearchTool(t, &queries)
+	recipes := metadatatool.WithMetadata(search, "search_recipes", "Searches cooking recipes.")
+
+	if got := recipes.Name(); got != "search_recipes" {
+		t.Errorf("Name() = %q, want %q", got, "search_recipes")
+	}
+	if got := recipes.Description(); got != "Searches cooking recipes." {
+		t.Errorf("Description() = %q, want %q", got, "Searches cooking recipes.")
+	}
+	decl := recipes.(toolinternal.FunctionTool).Declaration()
+	wantDecl := search.(toolinternal.FunctionTool).Declaration()
+	wantDecl.Name = "search_recipes"
+	wantDecl.Description = "Searches cooking recipes."
+	if diff := cmp.Diff(wantDecl, decl); diff != "" {
+		t.Errorf("Declaration() mismatch (-want +got):\n%s", diff)
+	}
+
+	// An empty name or description keeps the one of the wrapped tool.
+	described := metadatatool.WithMetadata(search, "", "Searches news.")
+	if described.Name() != "search" || described.Description() != "Searches news." {
+		t.Errorf("WithMetadata() with no name = %q, %q, want %q, %q", described.Name(), described.Description(), "search", "Searches news.")
+	}
+
+	// The built-in tools are returned as is.
+	builtin := geminitool.GoogleSearch{}
+	if got := metadatatool.WithMetadata(builtin, "web", ""); got != tool.Tool(builtin) {
+		t.Errorf("WithMetadata() of a built-in tool = %v, want the tool", got)
+	}
+}
+
+func TestWithMetadata_ProcessRequest(t *testing.T) {
+	var queries []string
+	search := newSearchTool(t, &queries)
+	recipes := metadatatool.WithMetadata(search, "search_recipes", "Searches cooking recipes.")
+
+	// The wrapped tool and the wrapper are declared side by side.
+	req := &model.LLMRequest{}
+	for _, tl := range []tool.Tool{search, recipes} {
+		if err := tl.(toolinternal.RequestProcessor).ProcessRequest(nil, req); err != nil {
+			t.Fatalf("ProcessRequest(%q) error = %v", tl.Name(), err)
+		}
+	}
+	if req.Tools["search"] != search || req.Tools["search_recipes"] != recipes {
+		t.Errorf("Tools = %v, want the tool and its wrapper under their names", req.Tools)
+	}
+	var got [][2]string
+	for _, decl := range req.Config.Tools[0].FunctionDeclarations {
+		got = append(got, [2]string{decl.Name, decl.Description})
+	}
+	want := [][2]string{{"search", "Searches the web."}, {"search_recipes", "Searches cooking recipes."}}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("declarations mismatch (-want +got):\n%s", diff)
+	}
+
+	// The name of the wrapper is unique.
+	sameName := metadatatool.WithMetadata(search, "search_recipes", "")
+	if err := sameName.(toolinternal.RequestProcessor).ProcessRequest(nil, req); err == nil {
+		t.Error("ProcessRequest() of a duplicate name succeeded, want error")
+	}
+}
+
+func TestWithMetadata_Agent(t *testing.T) {
+	var queries []string
+	search := newSearchTool(t, &queries)
+	mockModel := &testutil.MockModel{
+		Responses: []*genai.Content{
+			genai.NewContentFromFunctionCall("search_recipes", map[string]any{"query": "ratatouille"}, genai.RoleModel),
+			genai.NewContentFromText("Found a recipe.", genai.RoleModel),
+		},
+	}
+	a, err := llmagent.New(llmagent.Config{
+		Name:  "cook",
+		Model: mockModel,
+		Tools: []tool.Tool{metadatatool.WithMetadata(search, "search_recipes", "Searches cooking recipes.")},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	runner := testutil.NewTestAgentRunner(t, a)
+	if _, err := testutil.CollectEvents(runner.Run(t, "session", "find a recipe")); err != nil {
+		t.Fatal(err)
+	}
+
+	if diff := cmp.Diff([]string{"ratatouille"}, queries); diff != "" {
+		t.Errorf("queries mismatch (-want +got):\n%s", diff)
+	}
+	if len(mockModel.Requests) != 2 {
+		t.Fatalf("got %d model requests, want 2", len(mockModel.Requests))
+	}
+	decls := mockModel.Requests[0].Config.Tools[0].FunctionDeclarations
+	if len(decls) != 1 || decls[0].Name != "search_recipes" {
+		t.Errorf("declarations = %v, want only search_recipes", decls)
+	}
+	contents := mockModel.Requests[1].Contents
+	resp := contents[len(contents)-1].Parts[0].FunctionResponse
+	if resp == nil || resp.Name != "search_recipes" {
+		t.Errorf("last request content = %v, want the response of search_recipes", contents[len(contents)-1])
+	}
+}
@@ -0,0 +1,199 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package notifytool
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/mail"
+	"net/smtp"
+	"strings"
+	"sync"
+)
+
+// Notification is a message delivered through a [Channel].
+type Notification struct {
+	Recipient string `json:"recipient"`
+	Subject   string `json:"subject"`
+	Body      string `json:"body"`
+}
+
+// Channel delivers notifications.
+type Channel interface {
+	// Name of the channel, reported to the model in the delivery status.
+	Name() string
+	// ValidateRecipient reports whether the recipient is valid for the
+	// channel. The tool rejects notifications to invalid recipients without
+	// calling Send.
+	ValidateRecipient(recipient string) error
+	// Send delivers the notification. A returned error is reported to the
+	// model as a failed delivery.
+	Send(ctx context.Context, n *Notification) error
+}
+
+// SMTPConfig configures an email channel.
+type SMTPConfig struct {
+	// Addr is the address of the SMTP server, e.g. "smtp.example.com:587".
+	Addr string
+	// Auth is used to authenticate with the server. Optional.
+	Auth smtp.Auth
+	// From is the sender address.
+	From string
+	// AllowedDomains restricts the recipients to the given email domains.
+	// If empty, any valid email address is accepted.
+	AllowedDomains []string
+}
+
+// NewSMTPChannel creates a channel sending notifications as plain text
+// emails.
+func NewSMTPChannel(cfg SMTPConfig) Channel {
+	return &smtpChannel{cfg: cfg, sendMail: smtp.SendMail}
+}
+
+type smtpChannel struct {
+	cfg      SMTPConfig
+	sendMail func(addr string, a smtp.Auth, from string, to []string, msg []byte) error
+}
+
+func (c *smtpChannel) Name() string {
+	return "email"
+}
+
+func (c *smtpChannel) ValidateRecipient(recipient string) error {
+	addr, err := mail.ParseAddress(recipient)
+	if err != nil {
+		return fmt.Errorf("invalid email address %q: %w", recipient, err)
+	}
+	if len(c.cfg.AllowedDomains) == 0 {
+		return nil
+	}
+	_, domain, _ := strings.Cut(addr.Address, "@")
+	for _, allowed := range c.cfg.AllowedDomains {
+		if strings.EqualFold(domain, allowed) {
+			return nil
+		}
+	}
+	return fmt.Errorf("email domain %q is not allowed", domain)
+}
+
+func (c *smtpChannel) Send(ctx context.Context, n *Notification) error {
+	addr, err := mail.ParseAddress(n.Recipient)
+	if err != nil {
+		return err
+	}
+	// Header values must not contain line breaks.
+	subject := strings.Join(strings.Fields(n.Subject), " ")
+	msg := fmt.Sprintf("From: %s\r\nTo: %s\r\nSubject: %s\r\nContent-Type: text/plain; charset=UTF-8\r\n\r\n%s\r\n",
+		c.cfg.From, addr.Address, subject, n.Body)
+	return c.sendMail(c.cfg.Addr, c.cfg.Auth, c.cfg.From, []string{addr.Address}, []byte(msg))
+}
+
+// WebhookConfig configures a webhook channel.
+type WebhookConfig struct {
+	// URL receiving the notifications.
+	URL string
+	// Header is added to every request, e.g. for authentication.
+	Header http.Header
+	// Client is the HTTP client used to call the webhook.
+	// Defaults to http.DefaultClient.
+	Client *http.Client
+	// ValidateRecipient is an optional recipient validation. By default any
+	// non-empty recipient is accepted.
+	ValidateRecipient func(recipient string) error
+}
+
+// NewWebhookChannel creates a channel posting notifications as JSON to a
+// webhook. The request body is the JSON encoding of [Notification]. Any non
+// 2xx response status is a failed delivery.
+func NewWebhookChannel(cfg WebhookConfig) Channel {
+	if cfg.Client == nil {
+		cfg.Client = http.DefaultClient
+	}
+	return &webhookChannel{cfg: cfg}
+}
+
+type webhookChannel struct {
+	cfg WebhookConfig
+}
+
+func (c *webhookChannel) Name() string {
+	return "webhook"
+}
+
+func (c *webhookChannel) ValidateRecipient(recipient string) error {
+	if c.cfg.ValidateRecipient != nil {
+		return c.cfg.ValidateRecipient(recipient)
+	}
+	return nil
+}
+
+func (c *webhookChannel) Send(ctx context.Context, n *Notification) error {
+	body, err := json.Marshal(n)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.cfg.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	for k, v := range c.cfg.Header {
+		req.Header[k] = v
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := c.cfg.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook responded with status %s", resp.Status)
+	}
+	return nil
+}
+
+// NoopChannel is a channel that records notifications instead of delivering
+// them. It is meant for development and tests.
+type NoopChannel struct {
+	mu   sync.Mutex
+	sent []*Notification
+}
+
+// Name implements Channel.
+func (c *NoopChannel) Name() string {
+	return "noop"
+}
+
+// ValidateRecipient implements Channel.
+func (c *NoopChannel) ValidateRecipient(recipient string) error {
+	return nil
+}
+
+// Send implements Channel.
+func (c *NoopChannel) Send(ctx context.Context, n *Notification) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.sent = append(c.sent, n)
+	return nil
+}
+
+// Sent returns the notifications sent through the channel.
+func (c *NoopChannel) Sent() []*Notification {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]*Notification(nil), c.sent...)
+}
@@ -0,0 +1,47 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package notifytool
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestNotifier_SweepsIdleSessions(t *testing.T) {
+	now := time.Date(2025, 3, 6, 15, 30, 0, 0, time.UTC)
+	n := &notifier{
+		cfg:   Config{MaxPerWindow: 1, RateLimitWindow: time.Minute},
+		now:   func() time.Time { return now },
+		sends: make(map[string][]time.Time),
+	}
+	if !n.allow("session-0") || n.allow("session-0") {
+		t.Error("allow() twice = true, true, want true, false")
+	}
+	for i := 1; i < minSweepSize; i++ {
+		if !n.allow(fmt.Sprintf("session-%d", i)) {
+			t.Fatalf("allow(session-%d) = false, want true", i)
+		}
+	}
+
+	// Once their window elapsed, the sessions are dropped.
+	now = now.Add(2 * time.Minute)
+	if !n.allow("new") {
+		t.Error("allow(new) = false, want true")
+	}
+	if len(n.sends) != 1 {
+		t.Errorf("got %d sessions tracked, want 1", len(n.sends))
+	}
+}
@@ -0,0 +1,274 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package notifytool provides a tool that lets the model send notifications,
+// e.g. emails or webhook calls, through a pluggable [Channel].
+//
+// The model supplies the recipient, subject and body. The tool validates the
+// recipient with the channel, applies a per-session rate limit and reports
+// the delivery status in the [Result]. Rejected and failed deliveries are
+// reported to the model in the result rather than as tool errors, so it can
+// inform the user or correct the recipient.
+//
+// Sending a notification is a side effect. Use Config.RequireConfirmation to
+// have the user confirm each notification, see package toolconfirmation, and
+// Config.BeforeSend to audit or rewrite notifications before they are
+// delivered.
+package notifytool
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/adk/tool"
+	"google.golang.org/adk/tool/functiontool"
+	"google.golang.org/adk/util/instructionutil"
+)
+
+// Delivery statuses reported in Result.Status.
+const (
+	// StatusSent means the channel accepted the notification.
+	StatusSent = "sent"
+	// StatusRejected means the notification was not sent because it was
+	// invalid, rate limited or rejected by Config.BeforeSend.
+	StatusRejected = "rejected"
+	// StatusFailed means the channel failed to deliver the notification.
+	StatusFailed = "failed"
+)
+
+const (
+	defaultName            = "send_notification"
+	defaultMaxPerWindow    = 5
+	defaultRateLimitWindow = time.Hour
+)
+
+// ErrRateLimited is reported when the session exceeded its notification
+// rate limit.
+var ErrRateLimited = errors.New("notification rate limit exceeded")
+
+// Config is the configuration of the notification tool.
+type Config struct {
+	// Name of the tool as seen by the model. Defaults to "send_notification".
+	Name string
+	// Description of the tool. Should tell the model who can be notified.
+	Description string
+	// Channel delivering the notifications. Required.
+	Channel Channel
+	// MaxPerWindow is the maximum number of notifications a session can send
+	// within RateLimitWindow. Defaults to 5. Negative disables rate limiting.
+	MaxPerWindow int
+	// RateLimitWindow defaults to one hour.
+	RateLimitWindow time.Duration
+	// ExpandTemplates renders the subject and body supplied by the model as
+	// text/template against the session state, see
+	// instructionutil.InstructionTemplate. This exposes the session state to
+	// the notification content, only enable it if that is acceptable.
+	ExpandTemplates bool
+	// BeforeSend is called before a valid notification is delivered. It may
+	// modify the notification, whose recipient is then validated again. If it
+	// returns an error, the notification is rejected and the error is
+	// reported to the model.
+	BeforeSend func(ctx tool.Context, n *Notification) error
+	// RequireConfirmation makes each notification wait for the confirmation
+	// of the user, shown the recipient and the subject, before it is
+	// validated and delivered. See functiontool.Config.RequireConfirmation.
+	RequireConfirmation bool
+}
+
+// Args are the arguments the model provides when sending a notification.
+type Args struct {
+	Recipient string `json:"recipient" jsonschema:"the recipient of the notification"`
+	Subject   string `json:"subject" jsonschema:"the subject or title of the notification"`
+	Body      string `json:"body" jsonschema:"the content of the notification"`
+}
+
+// Result reports the delivery status of a notification.
+type Result struct {
+	// Status is one of StatusSent, StatusRejected or StatusFailed.
+	Status string `json:"status"`
+	// Channel is the name of the channel used for the delivery.
+	Channel   string `json:"channel"`
+	Recipient string `json:"recipient"`
+	// Error explains why the notification was rejected or failed.
+	Error string `json:"error,omitempty"`
+}
+
+// New creates a notification tool.
+func New(cfg Config) (tool.Tool, error) {
+	if cfg.Channel == nil {
+		return nil, errors.New("notification channel is required")
+	}
+	if cfg.Name == "" {
+		cfg.Name = defaultName
+	}
+	if cfg.Description == "" {
+		cfg.Description = fmt.Sprintf("Sends a notification to a recipient via %s.", cfg.Channel.Name())
+	}
+	if cfg.MaxPerWindow == 0 {
+		cfg.MaxPerWindow = defaultMaxPerWindow
+	}
+	if cfg.RateLimitWindow <= 0 {
+		cfg.RateLimitWindow = defaultRateLimitWindow
+	}
+
+	n := &notifier{
+		cfg:   cfg,
+		now:   time.Now,
+		sends: make(map[string][]time.Time),
+	}
+	notifyTool, err := functiontool.New(functiontool.Config{
+		Name:                cfg.Name,
+		Description:         cfg.Description,
+		RequireConfirmation: cfg.RequireConfirmation,
+		ConfirmationPrompt:  confirmationPrompt,
+	}, n.send)
+	if err != nil {
+		return nil, fmt.Errorf("error creating notification tool: %w", err)
+	}
+	return notifyTool, nil
+}
+
+type notifier struct {
+	cfg Config
+	now func() time.Time
+
+	mu sync.Mutex
+	// sends holds the time of recent notifications per session.
+	sends map[string][]time.Time
+	// sweepSize is the number of sessions in sends after the last sweep.
+	sweepSize int
+}
+
+// minSweepSize is the number of sessions in notifier.sends below which they
+// are not swept.
+const minSweepSize = 64
+
+// confirmationPrompt is the hint shown to the user to confirm a
+// notification.
+func confirmationPrompt(args map[string]any) string {
+	return fmt.Sprintf("Send the notification %q to %v?", args["subject"], args["recipient"])
+}
+
+func (n *notifier) send(ctx tool.Context, args Args) (Result, error) {
+	notification := &Notification{
+		Recipient: strings.TrimSpace(args.Recipient),
+		Subject:   args.Subject,
+		Body:      args.Body,
+	}
+	result := Result{
+		Channel:   n.cfg.Channel.Name(),
+		Recipient: notification.Recipient,
+	}
+	reject := func(err error) (Result, error) {
+		result.Status = StatusRejected
+		result.Error = err.Error()
+		return result, nil
+	}
+
+	validate := func() error {
+		if notification.Recipient == "" {
+			return errors.New("recipient must not be empty")
+		}
+		return n.cfg.Channel.ValidateRecipient(notification.Recipient)
+	}
+	if err := validate(); err != nil {
+		return reject(err)
+	}
+	if n.cfg.ExpandTemplates {
+		if err := expandTemplates(ctx, notification); err != nil {
+			return reject(err)
+		}
+	}
+	if n.cfg.BeforeSend != nil {
+		err := n.cfg.BeforeSend(ctx, notification)
+		result.Recipient = notification.Recipient
+		if err != nil {
+			return reject(err)
+		}
+		// The recipient may have been rewritten.
+		if err := validate(); err != nil {
+			return reject(err)
+		}
+	}
+	if !n.allow(ctx.SessionID()) {
+		return reject(ErrRateLimited)
+	}
+
+	if err := n.cfg.Channel.Send(ctx, notification); err != nil {
+		result.Status = StatusFailed
+		result.Error = err.Error()
+		return result, nil
+	}
+	result.Status = StatusSent
+	return result, nil
+}
+
+// allow records a notification for the session and reports whether it is
+// within the rate limit.
+func (n *notifier) allow(sessionID string) bool {
+	if n.cfg.MaxPerWindow < 0 {
+		return true
+	}
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	now := n.now()
+	cutoff := now.Add(-n.cfg.RateLimitWindow)
+	n.sweep(cutoff)
+	var recent []time.Time
+	for _, t := range n.sends[sessionID] {
+		if t.After(cutoff) {
+			recent = append(recent, t)
+		}
+	}
+	if len(recent) >= n.cfg.MaxPerWindow {
+		n.sends[sessionID] = recent
+		return false
+	}
+	n.sends[sessionID] = append(recent, now)
+	return true
+}
+
+// sweep drops the sessions with no notification after cutoff, equivalent to
+// missing ones, when the number of sessions doubled since the last sweep.
+func (n *notifier) sweep(cutoff time.Time) {
+	if len(n.sends) < max(minSweepSize, 2*n.sweepSize) {
+		return
+	}
+	for sessionID, times := range n.sends {
+		if len(times) == 0 || !times[len(times)-1].After(cutoff) {
+			delete(n.sends, sessionID)
+		}
+	}
+	n.sweepSize = len(n.sends)
+}
+
+func expandTemplates(ctx tool.Context, notification *Notification) error {
+	for _, field := range []*string{&notification.Subject, &notification.Body} {
+		if !strings.Contains(*field, "{{") {
+			continue
+		}
+		tmpl, err := instructionutil.NewInstructionTemplate("notification", *field)
+		if err != nil {
+			return err
+		}
+		if *field, err = tmpl.Render(ctx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,269 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package notifytool_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"google.golang.org/adk/agent"
+	icontext "google.golang.org/adk/internal/context"
+	"google.golang.org/adk/internal/sessioninternal"
+	"google.golang.org/adk/internal/toolinternal"
+	"google.golang.org/adk/session"
+	"google.golang.org/adk/tool"
+	"google.golang.org/adk/tool/notifytool"
+	"google.golang.org/adk/tool/toolconfirmation"
+)
+
+func newToolContext(t *testing.T, state map[string]any) tool.Context {
+	t.Helper()
+	sessionService := session.InMemoryService()
+	resp, err := sessionService.Create(t.Context(), &session.CreateRequest{
+		AppName: "app",
+		UserID:  "user",
+		State:   state,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	a, err := agent.New(agent.Config{Name: "notifier"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := icontext.NewInvocationContext(t.Context(), icontext.InvocationContextParams{
+		Agent:   a,
+		Session: sessioninternal.NewMutableSession(sessionService, resp.Session),
+	})
+	return toolinternal.NewToolContext(ctx, "", nil)
+}
+
+func run(t *testing.T, notifyTool tool.Tool, ctx tool.Context, args map[string]any) map[string]any {
+	t.Helper()
+	got, err := notifyTool.(toolinternal.FunctionTool).Run(ctx, args)
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	return got
+}
+
+func TestNotifyTool(t *testing.T) {
+	channel := &notifytool.NoopChannel{}
+	notifyTool, err := notifytool.New(notifytool.Config{
+		Channel:         channel,
+		MaxPerWindow:    2,
+		ExpandTemplates: true,
+		BeforeSend: func(ctx tool.Context, n *notifytool.Notification) error {
+			if n.Recipient == "ceo" {
+				return errors.New("notifying the ceo requires approval")
+			}
+			return nil
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := newToolContext(t, map[string]any{"order_id": "A-42"})
+
+	tests := []struct {
+		name string
+		args map[string]any
+		want map[string]any
+	}{
+		{
+			name: "sent with template",
+			args: map[string]any{"recipient": "ada", "subject": "Order {{.State.order_id}}", "body": "Shipped."},
+			want: map[string]any{"status": "sent", "channel": "noop", "recipient": "ada"},
+		},
+		{
+			name: "empty recipient",
+			args: map[string]any{"recipient": " ", "subject": "s", "body": "b"},
+			want: map[string]any{"status": "rejected", "channel": "noop", "recipient": "", "error": "recipient must not be empty"},
+		},
+		{
+			name: "invalid template",
+			args: map[string]any{"recipient": "ada", "subject": "{{.State.missing}}", "body": "b"},
+			want: map[string]any{"status": "rejected", "channel": "noop", "recipient": "ada", "error": `failed to render instruction template "notification": template: notification:1:8: executing "notification" at <.State.missing>: map has no entry for key "missing"`},
+		},
+		{
+			name: "rejected before send",
+			args: map[string]any{"recipient": "ceo", "subject": "s", "body": "b"},
+			want: map[string]any{"status": "rejected", "channel": "noop", "recipient": "ceo", "error": "notifying the ceo requires approval"},
+		},
+		{
+			name: "second notification",
+			args: map[string]any{"recipient": "bob", "subject": "s", "body": "b"},
+			want: map[string]any{"status": "sent", "channel": "noop", "recipient": "bob"},
+		},
+		{
+			name: "rate limited",
+			args: map[string]any{"recipient": "bob", "subject": "s", "body": "b"},
+			want: map[string]any{"status": "rejected", "channel": "noop", "recipient": "bob", "error": notifytool.ErrRateLimited.Error()},
+		},
+	}
+	for _, tt := range tests {
+		if diff := cmp.Diff(tt.want, run(t, notifyTool, ctx, tt.args)); diff != "" {
+			t.Errorf("%s: Run() mismatch (-want +got):\n%s", tt.name, diff)
+		}
+	}
+
+	wantSent := []*notifytool.Notification{
+		{Recipient: "ada", Subject: "Order A-42", Body: "Shipped."},
+		{Recipient: "bob", Subject: "s", Body: "b"},
+	}
+	if diff := cmp.Diff(wantSent, channel.Sent()); diff != "" {
+		t.Errorf("sent notifications mismatch (-want +got):\n%s", diff)
+	}
+}
+
+// teamChannel only accepts the recipients of the team.
+type teamChannel struct {
+	notifytool.NoopChannel
+}
+
+func (c *teamChannel) ValidateRecipient(recipient string) error {
+	if !strings.HasPrefix(recipient, "team-") {
+		return fmt.Errorf("%q is not a member of the team", recipient)
+	}
+	return nil
+}
+
+func TestNotifyTool_BeforeSendRewrite(t *testing.T) {
+	channel := &teamChannel{}
+	notifyTool, err := notifytool.New(notifytool.Config{
+		Channel: channel,
+		BeforeSend: func(ctx tool.Context, n *notifytool.Notification) error {
+			n.Recipient = strings.TrimSuffix(n.Recipient, "-oncall")
+			return nil
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := newToolContext(t, nil)
+
+	got := run(t, notifyTool, ctx, map[string]any{"recipient": "team-ada-oncall", "subject": "s", "body": "b"})
+	want := map[string]any{"status": "sent", "channel": "noop", "recipient": "team-ada"}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Run() mismatch (-want +got):\n%s", diff)
+	}
+	// The rewritten recipient is validated.
+	got = run(t, notifyTool, ctx, map[string]any{"recipient": "team-oncall", "subject": "s", "body": "b"})
+	want = map[string]any{"status": "rejected", "channel": "noop", "recipient": "team", "error": `"team" is not a member of the team`}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Run() mismatch (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff([]*notifytool.Notification{{Recipient: "team-ada", Subject: "s", Body: "b"}}, channel.Sent()); diff != "" {
+		t.Errorf("sent notifications mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestNotifyTool_RequireConfirmation(t *testing.T) {
+	channel := &notifytool.NoopChannel{}
+	notifyTool, err := notifytool.New(notifytool.Config{Channel: channel, RequireConfirmation: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	args := map[string]any{"recipient": "ada", "subject": "Deploy", "body": "Done."}
+
+	ctx := newToolContext(t, nil)
+	run(t, notifyTool, ctx, args)
+	var confirmation *toolconfirmation.ToolConfirmation
+	for _, c := range ctx.Actions().RequestedToolConfirmations {
+		confirmation = c
+	}
+	if confirmation == nil || confirmation.Hint != `Send the notification "Deploy" to ada?` {
+		t.Errorf("requested confirmation = %+v, want one naming the subject and the recipient", confirmation)
+	}
+	if len(channel.Sent()) != 0 {
+		t.Errorf("sent notifications = %v before the confirmation, want none", channel.Sent())
+	}
+
+	ctx = toolinternal.WithToolConfirmation(newToolContext(t, nil), &toolconfirmation.ToolConfirmation{Confirmed: true})
+	got := run(t, notifyTool, ctx, args)
+	if diff := cmp.Diff(map[string]any{"status": "sent", "channel": "noop", "recipient": "ada"}, got); diff != "" {
+		t.Errorf("Run() after the confirmation mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestWebhookChannel(t *testing.T) {
+	var received []notifytool.Notification
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		var n notifytool.Notification
+		if err := json.NewDecoder(r.Body).Decode(&n); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		received = append(received, n)
+	}))
+	defer server.Close()
+
+	for _, tt := range []struct {
+		name   string
+		header http.Header
+		want   map[string]any
+	}{
+		{
+			name:   "delivered",
+			header: http.Header{"Authorization": {"Bearer secret"}},
+			want:   map[string]any{"status": "sent", "channel": "webhook", "recipient": "#ops"},
+		},
+		{
+			name: "failed",
+			want: map[string]any{"status": "failed", "channel": "webhook", "recipient": "#ops", "error": "webhook responded with status 401 Unauthorized"},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			notifyTool, err := notifytool.New(notifytool.Config{
+				Channel: notifytool.NewWebhookChannel(notifytool.WebhookConfig{URL: server.URL, Header: tt.header}),
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := run(t, notifyTool, newToolContext(t, nil), map[string]any{"recipient": "#ops", "subject": "Deploy", "body": "Done."})
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("Run() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+	if diff := cmp.Diff([]notifytool.Notification{{Recipient: "#ops", Subject: "Deploy", Body: "Done."}}, received); diff != "" {
+		t.Errorf("received notifications mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestSMTPChannel_ValidateRecipient(t *testing.T) {
+	channel := notifytool.NewSMTPChannel(notifytool.SMTPConfig{AllowedDomains: []string{"example.com"}})
+	for recipient, wantErr := range map[string]bool{
+		"ada@example.com":       false,
+		"Ada <ada@Example.com>": false,
+		"ada@other.com":         true,
+		"not an address":        true,
+	} {
+		if err := channel.ValidateRecipient(recipient); (err != nil) != wantErr {
+			t.Errorf("ValidateRecipient(%q) error = %v, wantErr %v", recipient, err, wantErr)
+		}
+	}
+}
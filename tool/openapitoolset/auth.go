@@ -0,0 +1,121 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openapitoolset
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/clientcredentials"
+
+	"google.golang.org/adk/auth"
+	"google.golang.org/adk/tool"
+)
+
+// authenticate sets the credential of the scheme on the request. It returns
+// false if the credential was requested from the user, see package auth.
+func (s *set) authenticate(ctx tool.Context, scheme *auth.AuthScheme, req *http.Request) (bool, error) {
+	if scheme == nil {
+		return true, nil
+	}
+	cred := s.cfg.AuthCredential
+	switch scheme.Type {
+	case auth.APIKey:
+		if cred == nil || cred.APIKey == "" {
+			return false, errors.New("the API key scheme requires an API key credential")
+		}
+		switch scheme.In {
+		case "header":
+			req.Header.Set(scheme.Name, cred.APIKey)
+		case "query":
+			q := req.URL.Query()
+			q.Set(scheme.Name, cred.APIKey)
+			req.URL.RawQuery = q.Encode()
+		case "cookie":
+			req.AddCookie(&http.Cookie{Name: scheme.Name, Value: cred.APIKey})
+		default:
+			return false, fmt.Errorf("unsupported API key location %q", scheme.In)
+		}
+		return true, nil
+
+	case auth.HTTP:
+		if cred == nil || cred.HTTP == nil {
+			return false, fmt.Errorf("the HTTP %s scheme requires an HTTP credential", scheme.Scheme)
+		}
+		switch strings.ToLower(scheme.Scheme) {
+		case "bearer":
+			req.Header.Set("Authorization", "Bearer "+cred.HTTP.Token)
+		case "basic":
+			req.SetBasicAuth(cred.HTTP.Username, cred.HTTP.Password)
+		default:
+			return false, fmt.Errorf("unsupported HTTP scheme %q", scheme.Scheme)
+		}
+		return true, nil
+
+	case auth.OAuth2, auth.OpenIDConnect:
+		var oauth *auth.OAuth2Auth
+		if cred != nil {
+			oauth = cred.OAuth2
+		}
+		switch {
+		case oauth != nil && oauth.AccessToken != "":
+			req.Header.Set("Authorization", "Bearer "+oauth.AccessToken)
+		case oauth != nil && oauth.ClientSecret != "" && scheme.TokenURL != "" && scheme.AuthorizationURL == "":
+			token, err := s.tokenSource(scheme, oauth).Token()
+			if err != nil {
+				return false, fmt.Errorf("failed to get an OAuth2 token: %w", err)
+			}
+			token.SetAuthHeader(req)
+		default:
+			cfg := &auth.AuthConfig{AuthScheme: scheme, RawAuthCredential: cred}
+			exchanged := ctx.GetAuthResponse(cfg)
+			if exchanged == nil || exchanged.OAuth2 == nil || exchanged.OAuth2.AccessToken == "" {
+				ctx.RequestCredential(cfg)
+				return false, nil
+			}
+			req.Header.Set("Authorization", "Bearer "+exchanged.OAuth2.AccessToken)
+		}
+		return true, nil
+	}
+	return false, fmt.Errorf("unsupported auth scheme type %q", scheme.Type)
+}
+
+// tokenSource returns the token source of the client credentials flow of the
+// scheme, shared by the tools so that the tokens are reused until they
+// expire.
+func (s *set) tokenSource(scheme *auth.AuthScheme, oauth *auth.OAuth2Auth) oauth2.TokenSource {
+	key := scheme.TokenURL + " " + strings.Join(scheme.Scopes, " ")
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if ts, ok := s.tokenSources[key]; ok {
+		return ts
+	}
+	cfg := &clientcredentials.Config{
+		ClientID:     oauth.ClientID,
+		ClientSecret: oauth.ClientSecret,
+		TokenURL:     scheme.TokenURL,
+		Scopes:       scheme.Scopes,
+	}
+	// The token source outlives the calls: its context only carries the
+	// client.
+	ctx := context.WithValue(context.Background(), oauth2.HTTPClient, s.cfg.Client)
+	ts := cfg.TokenSource(ctx)
+	s.tokenSources[key] = ts
+	return ts
+}
@@ -0,0 +1,106 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openapitoolset
+
+// keywords are the keywords kept in the JSON schemas of the parameters. The
+// other keywords of the OpenAPI schemas, e.g. "example" or "xml", are not JSON
+// schema keywords and are dropped.
+var keywords = map[string]bool{
+	"type": true, "format": true, "title": true, "description": true,
+	"enum": true, "const": true, "default": true,
+	"minimum": true, "maximum": true, "multipleOf": true,
+	"minLength": true, "maxLength": true, "pattern": true,
+	"minItems": true, "maxItems": true, "uniqueItems": true,
+	"minProperties": true, "maxProperties": true, "required": true,
+}
+
+// jsonSchema returns the JSON schema of an OpenAPI schema: the OpenAPI 3.0
+// extensions are translated, e.g. a nullable type to a type list with
+// "null", and the keywords that are not JSON schema keywords are dropped.
+// A missing schema accepts any value.
+func jsonSchema(s map[string]any) map[string]any {
+	out := map[string]any{}
+	for k, v := range s {
+		switch k {
+		case "properties":
+			props, ok := v.(map[string]any)
+			if !ok {
+				continue
+			}
+			outProps := make(map[string]any, len(props))
+			for name, p := range props {
+				prop, _ := p.(map[string]any)
+				outProps[name] = jsonSchema(prop)
+			}
+			out[k] = outProps
+		case "items":
+			items, _ := v.(map[string]any)
+			out[k] = jsonSchema(items)
+		case "additionalProperties":
+			if additional, ok := v.(map[string]any); ok {
+				out[k] = jsonSchema(additional)
+			} else if b, ok := v.(bool); ok {
+				out[k] = b
+			}
+		case "anyOf", "oneOf", "allOf":
+			list, ok := v.([]any)
+			if !ok {
+				continue
+			}
+			outList := make([]any, len(list))
+			for i, e := range list {
+				schema, _ := e.(map[string]any)
+				outList[i] = jsonSchema(schema)
+			}
+			out[k] = outList
+		case "exclusiveMinimum", "exclusiveMaximum":
+			// Booleans in OpenAPI 3.0, modifying minimum and maximum;
+			// numbers in OpenAPI 3.1 as in JSON schema.
+			if _, ok := v.(float64); ok {
+				out[k] = v
+			}
+		default:
+			if keywords[k] {
+				out[k] = v
+			}
+		}
+	}
+	for _, k := range []string{"exclusiveMinimum", "exclusiveMaximum"} {
+		if s[k] == true {
+			bound := "minimum"
+			if k == "exclusiveMaximum" {
+				bound = "maximum"
+			}
+			if v, ok := out[bound]; ok {
+				out[k] = v
+				delete(out, bound)
+			}
+		}
+	}
+	if s["nullable"] == true {
+		if t, ok := out["type"].(string); ok {
+			out["type"] = []any{t, "null"}
+		}
+	}
+	return out
+}
+
+// withDescription returns the schema with the description, if it has none.
+func withDescription(schema map[string]any, description string) map[string]any {
+	if _, ok := schema["description"]; !ok && description != "" {
+		schema["description"] = description
+	}
+	return schema
+}
@@ -0,0 +1,416 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package openapitoolset provides a toolset calling a REST API described by
+// an OpenAPI 3 document, with one tool per operation of the document.
+//
+// Example:
+//
+//	spec, err := os.ReadFile("petstore.json")
+//	...
+//	pets, err := openapitoolset.New(openapitoolset.Config{
+//		Spec:           spec,
+//		AuthCredential: &auth.AuthCredential{AuthType: auth.APIKey, APIKey: key},
+//	})
+//	...
+//	llmagent.New(llmagent.Config{
+//		...
+//		Toolsets: []tool.Toolset{pets},
+//	})
+//
+// The parameters of an operation, in its path, query, headers or cookies, are
+// the parameters of its tool, under their names, and its JSON request body is
+// the "body" parameter. A tool returns the JSON object of the response as is,
+// and any other response body under "result". A response with an error
+// status is returned as a tool.ToolError whose code is "http_<status>", e.g.
+// "http_404", and whose message is the body of the response.
+package openapitoolset
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+
+	"golang.org/x/oauth2"
+
+	"google.golang.org/adk/agent"
+	"google.golang.org/adk/auth"
+	"google.golang.org/adk/tool"
+)
+
+// Config is the configuration of an OpenAPI toolset.
+type Config struct {
+	// Name of the toolset. Defaults to "openapi".
+	Name string
+	// Spec is the OpenAPI 3 document of the API, in JSON. A YAML document
+	// must be converted to JSON first, e.g. with sigs.k8s.io/yaml. Its
+	// references must be local, e.g. "#/components/schemas/Pet". Required.
+	Spec []byte
+	// BaseURL is the URL the paths of the operations are relative to.
+	// Defaults to the URL of the first server of the document.
+	BaseURL string
+	// Client sends the requests to the API. Defaults to http.DefaultClient.
+	Client *http.Client
+
+	// AuthScheme is how the requests authenticate. Defaults to the security
+	// scheme required by each operation in the document, if any: the first
+	// scheme of its first security requirement.
+	AuthScheme *auth.AuthScheme
+	// AuthCredential is the credential of the scheme:
+	//   - for an APIKey scheme, the API key,
+	//   - for an HTTP scheme, the token of the bearer scheme, or the username
+	//     and password of the basic scheme,
+	//   - for an OAuth2 or OpenIDConnect scheme, an access token, used as is,
+	//     or client credentials. With a token URL and no authorization URL,
+	//     as for the client credentials flow of the document, the tokens are
+	//     obtained with the client credentials flow. Otherwise, the token is
+	//     requested from the user, see package auth, and kept in the session
+	//     state.
+	AuthCredential *auth.AuthCredential
+
+	// ToolFilter selects tools for which tool.Predicate returns true.
+	// If ToolFilter is nil, then all tools are returned.
+	ToolFilter tool.Predicate
+}
+
+// New returns a toolset with a tool per operation of the OpenAPI document.
+// The tools are named after the IDs of the operations, in snake case, e.g.
+// "list_pets" for "listPets", or after their methods and paths if they have
+// no ID, e.g. "get_pets_pet_id" for "GET /pets/{petId}". It returns an error
+// if the document is invalid, or two operations have the same tool name.
+func New(cfg Config) (tool.Toolset, error) {
+	if cfg.Name == "" {
+		cfg.Name = "openapi"
+	}
+	if cfg.Client == nil {
+		cfg.Client = http.DefaultClient
+	}
+	doc, err := parseDocument(cfg.Spec)
+	if err != nil {
+		return nil, err
+	}
+	baseURL := cfg.BaseURL
+	if baseURL == "" && len(doc.Servers) > 0 {
+		baseURL = doc.Servers[0].URL
+	}
+	if baseURL == "" {
+		return nil, errors.New("the document has no server: Config.BaseURL is required")
+	}
+
+	s := &set{
+		cfg:          cfg,
+		baseURL:      strings.TrimSuffix(baseURL, "/"),
+		tokenSources: make(map[string]oauth2.TokenSource),
+	}
+	ops, err := doc.operations()
+	if err != nil {
+		return nil, err
+	}
+	for _, op := range ops {
+		t, err := newAPITool(s, doc, op)
+		if err != nil {
+			return nil, err
+		}
+		s.tools = append(s.tools, t)
+	}
+	if dups := tool.DuplicateNames(s.tools); len(dups) > 0 {
+		return nil, fmt.Errorf("operations with the same tool names: %s", strings.Join(dups, ", "))
+	}
+	return s, nil
+}
+
+type set struct {
+	cfg     Config
+	baseURL string
+	tools   []tool.Tool
+
+	mu sync.Mutex
+	// tokenSources are the token sources of the client credentials flows, by
+	// token URL and scopes.
+	tokenSources map[string]oauth2.TokenSource
+}
+
+// Name implements tool.Toolset.
+func (s *set) Name() string {
+	return s.cfg.Name
+}
+
+// Tools implements tool.Toolset.
+func (s *set) Tools(ctx agent.ReadonlyContext) ([]tool.Tool, error) {
+	if s.cfg.ToolFilter == nil {
+		return slices.Clone(s.tools), nil
+	}
+	var tools []tool.Tool
+	for _, t := range s.tools {
+		if s.cfg.ToolFilter(ctx, t) {
+			tools = append(tools, t)
+		}
+	}
+	return tools, nil
+}
+
+// methods are the methods of the operations of a path, in the order of their
+// tools.
+var methods = []string{"get", "put", "post", "delete", "options", "head", "patch", "trace"}
+
+// document is the part of an OpenAPI document the toolset uses, with its
+// references resolved.
+type document struct {
+	OpenAPI string `json:"openapi"`
+	Servers []struct {
+		URL string `json:"url"`
+	} `json:"servers"`
+	Paths      map[string]map[string]json.RawMessage `json:"paths"`
+	Components struct {
+		SecuritySchemes map[string]*securityScheme `json:"securitySchemes"`
+	} `json:"components"`
+	Security []map[string][]string `json:"security"`
+}
+
+type operation struct {
+	Method      string
+	Path        string
+	OperationID string       `json:"operationId"`
+	Summary     string       `json:"summary"`
+	Description string       `json:"description"`
+	Parameters  []*parameter `json:"parameters"`
+	RequestBody *requestBody `json:"requestBody"`
+	// Security overrides the security requirements of the document if set,
+	// an empty list meaning no authentication.
+	Security *[]map[string][]string `json:"security"`
+}
+
+type parameter struct {
+	Name        string         `json:"name"`
+	In          string         `json:"in"`
+	Description string         `json:"description"`
+	Required    bool           `json:"required"`
+	Schema      map[string]any `json:"schema"`
+}
+
+type requestBody struct {
+	Description string `json:"description"`
+	Required    bool   `json:"required"`
+	Content     map[string]struct {
+		Schema map[string]any `json:"schema"`
+	} `json:"content"`
+}
+
+type securityScheme struct {
+	Type        string `json:"type"`
+	Description string `json:"description"`
+	Name        string `json:"name"`
+	In          string `json:"in"`
+	Scheme      string `json:"scheme"`
+	Flows       struct {
+		ClientCredentials *oauthFlow `json:"clientCredentials"`
+		AuthorizationCode *oauthFlow `json:"authorizationCode"`
+	} `json:"flows"`
+}
+
+type oauthFlow struct {
+	AuthorizationURL string            `json:"authorizationUrl"`
+	TokenURL         string            `json:"tokenUrl"`
+	Scopes           map[string]string `json:"scopes"`
+}
+
+// parseDocument parses the JSON OpenAPI document and resolves its
+// references.
+func parseDocument(spec []byte) (*document, error) {
+	if len(spec) == 0 {
+		return nil, errors.New("Config.Spec is required")
+	}
+	var root any
+	if err := json.Unmarshal(spec, &root); err != nil {
+		return nil, fmt.Errorf("invalid OpenAPI document, expected JSON: %w", err)
+	}
+	resolved, err := resolveRefs(root, root, nil)
+	if err != nil {
+		return nil, fmt.Errorf("invalid OpenAPI document: %w", err)
+	}
+	encoded, err := json.Marshal(resolved)
+	if err != nil {
+		return nil, err
+	}
+	var doc document
+	if err := json.Unmarshal(encoded, &doc); err != nil {
+		return nil, fmt.Errorf("invalid OpenAPI document: %w", err)
+	}
+	if !strings.HasPrefix(doc.OpenAPI, "3.") {
+		return nil, fmt.Errorf("unsupported OpenAPI version %q, want 3.x", doc.OpenAPI)
+	}
+	return &doc, nil
+}
+
+// operations returns the operations of the document, by path and method.
+func (d *document) operations() ([]*operation, error) {
+	paths := make([]string, 0, len(d.Paths))
+	for path := range d.Paths {
+		paths = append(paths, path)
+	}
+	slices.Sort(paths)
+
+	var ops []*operation
+	for _, path := range paths {
+		item := d.Paths[path]
+		var common []*parameter
+		if raw, ok := item["parameters"]; ok {
+			if err := json.Unmarshal(raw, &common); err != nil {
+				return nil, fmt.Errorf("invalid parameters of path %q: %w", path, err)
+			}
+		}
+		for _, method := range methods {
+			raw, ok := item[method]
+			if !ok {
+				continue
+			}
+			op := &operation{Method: strings.ToUpper(method), Path: path}
+			if err := json.Unmarshal(raw, op); err != nil {
+				return nil, fmt.Errorf("invalid operation %s %s: %w", op.Method, path, err)
+			}
+			// The parameters of the operation override those of the path.
+			for _, p := range common {
+				if !slices.ContainsFunc(op.Parameters, func(q *parameter) bool { return q.Name == p.Name && q.In == p.In }) {
+					op.Parameters = append(op.Parameters, p)
+				}
+			}
+			ops = append(ops, op)
+		}
+	}
+	return ops, nil
+}
+
+// authScheme returns the security scheme of the operation, or nil if it
+// requires none.
+func (d *document) authScheme(op *operation) (*auth.AuthScheme, error) {
+	reqs := d.Security
+	if op.Security != nil {
+		reqs = *op.Security
+	}
+	for _, req := range reqs {
+		names := make([]string, 0, len(req))
+		for name := range req {
+			names = append(names, name)
+		}
+		if len(names) == 0 {
+			// An empty requirement makes the authentication optional.
+			continue
+		}
+		slices.Sort(names)
+		name := names[0]
+		s, ok := d.Components.SecuritySchemes[name]
+		if !ok || s == nil {
+			return nil, fmt.Errorf("unknown security scheme %q", name)
+		}
+		return s.authScheme(req[name])
+	}
+	return nil, nil
+}
+
+// authScheme returns the scheme, requiring the scopes for the OAuth2 schemes,
+// or all the scopes of its flow if there are none.
+func (s *securityScheme) authScheme(scopes []string) (*auth.AuthScheme, error) {
+	scheme := &auth.AuthScheme{Type: auth.CredentialType(s.Type), Description: s.Description}
+	switch scheme.Type {
+	case auth.APIKey:
+		scheme.Name, scheme.In = s.Name, s.In
+	case auth.HTTP:
+		scheme.Scheme = strings.ToLower(s.Scheme)
+	case auth.OAuth2:
+		// The client credentials flow needs no user.
+		flow := s.Flows.ClientCredentials
+		if flow == nil {
+			flow = s.Flows.AuthorizationCode
+		}
+		if flow == nil {
+			return nil, errors.New("OAuth2 security scheme without a client credentials or authorization code flow")
+		}
+		scheme.AuthorizationURL, scheme.TokenURL = flow.AuthorizationURL, flow.TokenURL
+		scheme.Scopes = scopes
+		if len(scopes) == 0 {
+			for scope := range flow.Scopes {
+				scheme.Scopes = append(scheme.Scopes, scope)
+			}
+			slices.Sort(scheme.Scopes)
+		}
+	case auth.OpenIDConnect:
+		scheme.Scopes = scopes
+	default:
+		return nil, fmt.Errorf("unsupported security scheme type %q", s.Type)
+	}
+	return scheme, nil
+}
+
+// resolveRefs returns the node with its references to the root replaced with
+// their targets. The references of recursive schemas are replaced with empty
+// schemas, accepting any value.
+func resolveRefs(root, node any, visiting []string) (any, error) {
+	switch v := node.(type) {
+	case map[string]any:
+		if ref, ok := v["$ref"].(string); ok {
+			if slices.Contains(visiting, ref) {
+				return map[string]any{}, nil
+			}
+			target, err := lookup(root, ref)
+			if err != nil {
+				return nil, err
+			}
+			return resolveRefs(root, target, append(visiting, ref))
+		}
+		resolved := make(map[string]any, len(v))
+		for k, e := range v {
+			r, err := resolveRefs(root, e, visiting)
+			if err != nil {
+				return nil, err
+			}
+			resolved[k] = r
+		}
+		return resolved, nil
+	case []any:
+		resolved := make([]any, len(v))
+		for i, e := range v {
+			r, err := resolveRefs(root, e, visiting)
+			if err != nil {
+				return nil, err
+			}
+			resolved[i] = r
+		}
+		return resolved, nil
+	}
+	return node, nil
+}
+
+// lookup returns the target of a local reference, a JSON pointer.
+func lookup(root any, ref string) (any, error) {
+	pointer, ok := strings.CutPrefix(ref, "#")
+	if !ok {
+		return nil, fmt.Errorf("unsupported reference %q: only local references are supported", ref)
+	}
+	node := root
+	for _, token := range strings.Split(pointer, "/")[1:] {
+		token = strings.NewReplacer("~1", "/", "~0", "~").Replace(token)
+		m, ok := node.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("invalid reference %q", ref)
+		}
+		if node, ok = m[token]; !ok {
+			return nil, fmt.Errorf("invalid reference %q", ref)
+		}
+	}
+	return node, nil
+}
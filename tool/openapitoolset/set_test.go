@@ -0,0 +1,344 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openapitoolset_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"google.golang.org/adk/auth"
+	icontext "google.golang.org/adk/internal/context"
+	"google.golang.org/adk/internal/sessioninternal"
+	"google.golang.org/adk/internal/toolinternal"
+	"google.golang.org/adk/session"
+	"google.golang.org/adk/tool"
+	"google.golang.org/adk/tool/openapitoolset"
+)
+
+func readSpec(t *testing.T) []byte {
+	t.Helper()
+	spec, err := os.ReadFile("testdata/petstore.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return spec
+}
+
+// tools returns the tools of the set, by name.
+func tools(t *testing.T, set tool.Toolset) map[string]toolinternal.FunctionTool {
+	t.Helper()
+	list, err := set.Tools(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	byName := map[string]toolinternal.FunctionTool{}
+	for _, tl := range list {
+		byName[tl.Name()] = tl.(toolinternal.FunctionTool)
+	}
+	return byName
+}
+
+// newToolContext returns the context of a call in a new session.
+func newToolContext(t *testing.T, actions *session.EventActions) tool.Context {
+	t.Helper()
+	service := session.InMemoryService()
+	resp, err := service.Create(t.Context(), &session.CreateRequest{AppName: "app", UserID: "user"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := icontext.NewInvocationContext(t.Context(), icontext.InvocationContextParams{
+		Session: sessioninternal.NewMutableSession(service, resp.Session),
+	})
+	return toolinternal.NewToolContext(ctx, "call", actions)
+}
+
+func TestNew_Declarations(t *testing.T) {
+	set, err := openapitoolset.New(openapitoolset.Config{Spec: readSpec(t)})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	byName := tools(t, set)
+	var names []string
+	list, _ := set.Tools(nil)
+	for _, tl := range list {
+		names = append(names, tl.Name())
+	}
+	if diff := cmp.Diff([]string{"list_pets", "create_pet", "get_pets_pet_id", "delete_pet"}, names); diff != "" {
+		t.Errorf("tool names mismatch (-want +got):\n%s", diff)
+	}
+
+	schema := func(name string) map[string]any {
+		t.Helper()
+		encoded, err := json.Marshal(byName[name].Declaration().ParametersJsonSchema)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var m map[string]any
+		if err := json.Unmarshal(encoded, &m); err != nil {
+			t.Fatal(err)
+		}
+		return m
+	}
+	for _, tc := range []struct {
+		tool string
+		want map[string]any
+	}{
+		{
+			tool: "list_pets",
+			want: map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					// The exclusive maximum of OpenAPI 3.0 is translated, and
+					// the example dropped.
+					"limit": map[string]any{"type": "integer", "exclusiveMaximum": 100.0, "description": "The maximum number of pets."},
+					"tags":  map[string]any{"type": "array", "items": map[string]any{"type": "string"}},
+				},
+			},
+		},
+		{
+			tool: "create_pet",
+			want: map[string]any{
+				"type":     "object",
+				"required": []any{"body"},
+				"properties": map[string]any{
+					"body": map[string]any{
+						"type":     "object",
+						"required": []any{"name"},
+						"properties": map[string]any{
+							"name": map[string]any{"type": "string"},
+							"tag":  map[string]any{"type": []any{"string", "null"}},
+							// The recursive reference accepts anything.
+							"parent": true,
+						},
+					},
+				},
+			},
+		},
+		{
+			tool: "get_pets_pet_id",
+			want: map[string]any{
+				"type":     "object",
+				"required": []any{"petId"},
+				"properties": map[string]any{
+					"petId":        map[string]any{"type": "integer"},
+					"X-Request-Id": map[string]any{"type": "string"},
+				},
+			},
+		},
+	} {
+		if diff := cmp.Diff(tc.want, schema(tc.tool)); diff != "" {
+			t.Errorf("parameters of %s mismatch (-want +got):\n%s", tc.tool, diff)
+		}
+	}
+	if got, want := byName["get_pets_pet_id"].Description(), "Returns a pet.\n\nFails if the pet does not exist."; got != want {
+		t.Errorf("Description() = %q, want %q", got, want)
+	}
+	if got, want := byName["delete_pet"].Description(), "DELETE /pets/{petId}"; got != want {
+		t.Errorf("Description() without summary = %q, want %q", got, want)
+	}
+
+	filtered, err := openapitoolset.New(openapitoolset.Config{Spec: readSpec(t), ToolFilter: tool.StringPredicate([]string{"list_pets"})})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := tools(t, filtered); len(got) != 1 || got["list_pets"] == nil {
+		t.Errorf("Tools() with filter = %v, want list_pets only", got)
+	}
+}
+
+func TestNew_Run(t *testing.T) {
+	type request struct {
+		Method, URL, APIKey, RequestID, Body string
+	}
+	var got []request
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		got = append(got, request{r.Method, r.URL.String(), r.Header.Get("X-API-Key"), r.Header.Get("X-Request-Id"), string(body)})
+		switch {
+		case r.URL.Path == "/v1/pets/404":
+			http.Error(w, "no such pet", http.StatusNotFound)
+		case r.Method == http.MethodDelete:
+			w.WriteHeader(http.StatusNoContent)
+		case r.URL.Path == "/v1/pets" && r.Method == http.MethodGet:
+			w.Header().Set("Content-Type", "application/json")
+			fmt.Fprint(w, `[{"name":"Rex"}]`)
+		default:
+			w.Header().Set("Content-Type", "application/json")
+			fmt.Fprint(w, `{"name":"Rex"}`)
+		}
+	}))
+	defer server.Close()
+
+	set, err := openapitoolset.New(openapitoolset.Config{
+		Spec:           readSpec(t),
+		BaseURL:        server.URL + "/v1/",
+		AuthCredential: &auth.AuthCredential{AuthType: auth.APIKey, APIKey: "secret"},
+	})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	byName := tools(t, set)
+	run := func(name string, args map[string]any) (map[string]any, error) {
+		t.Helper()
+		return byName[name].Run(newToolContext(t, &session.EventActions{}), args)
+	}
+
+	for _, tc := range []struct {
+		tool string
+		args map[string]any
+		want map[string]any
+	}{
+		{tool: "list_pets", args: map[string]any{"limit": 10.0, "tags": []any{"dog", "cat"}}, want: map[string]any{"result": []any{map[string]any{"name": "Rex"}}}},
+		{tool: "create_pet", args: map[string]any{"body": map[string]any{"name": "Rex"}}, want: map[string]any{"name": "Rex"}},
+		{tool: "get_pets_pet_id", args: map[string]any{"petId": 7.0, "X-Request-Id": "r1"}, want: map[string]any{"name": "Rex"}},
+		{tool: "delete_pet", args: map[string]any{"petId": 7.0}, want: map[string]any{"status_code": 204.0}},
+	} {
+		result, err := run(tc.tool, tc.args)
+		if err != nil {
+			t.Fatalf("Run(%s) error = %v", tc.tool, err)
+		}
+		// Compare the JSON forms.
+		encoded, _ := json.Marshal(result)
+		var gotResult map[string]any
+		_ = json.Unmarshal(encoded, &gotResult)
+		if diff := cmp.Diff(tc.want, gotResult); diff != "" {
+			t.Errorf("Run(%s) mismatch (-want +got):\n%s", tc.tool, diff)
+		}
+	}
+	want := []request{
+		{Method: "GET", URL: "/v1/pets?limit=10&tags=dog&tags=cat", APIKey: "secret"},
+		{Method: "POST", URL: "/v1/pets", APIKey: "secret", Body: `{"name":"Rex"}`},
+		{Method: "GET", URL: "/v1/pets/7", APIKey: "secret", RequestID: "r1"},
+		// The operation requires no authentication.
+		{Method: "DELETE", URL: "/v1/pets/7"},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("requests mismatch (-want +got):\n%s", diff)
+	}
+
+	_, err = run("get_pets_pet_id", map[string]any{"petId": 404.0})
+	var toolErr *tool.ToolError
+	if !errors.As(err, &toolErr) || toolErr.Code != "http_404" || toolErr.Message != "no such pet" {
+		t.Errorf("Run() of a missing pet error = %v, want an http_404 tool error", err)
+	}
+	if _, err := run("create_pet", map[string]any{}); err == nil {
+		t.Error("Run() without the required body succeeded, want error")
+	}
+}
+
+func TestNew_OAuth2(t *testing.T) {
+	var tokenRequests atomic.Int32
+	var gotAuth []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/token" {
+			tokenRequests.Add(1)
+			if id, secret, _ := r.BasicAuth(); id != "client" || secret != "secret" {
+				http.Error(w, "invalid client", http.StatusUnauthorized)
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			fmt.Fprint(w, `{"access_token":"token1","token_type":"bearer","expires_in":3600}`)
+			return
+		}
+		gotAuth = append(gotAuth, r.Header.Get("Authorization"))
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{}`)
+	}))
+	defer server.Close()
+
+	spec := strings.NewReplacer(
+		`"security": [{"api_key": []}]`, `"security": [{"oauth": ["read"]}]`,
+		`"api_key": {"type": "apiKey", "name": "X-API-Key", "in": "header"}`,
+		`"oauth": {"type": "oauth2", "flows": {"clientCredentials": {"tokenUrl": "`+server.URL+`/token", "scopes": {"read": "", "write": ""}}}}`,
+	).Replace(string(readSpec(t)))
+	set, err := openapitoolset.New(openapitoolset.Config{
+		Spec:           []byte(spec),
+		BaseURL:        server.URL,
+		AuthCredential: &auth.AuthCredential{AuthType: auth.OAuth2, OAuth2: &auth.OAuth2Auth{ClientID: "client", ClientSecret: "secret"}},
+	})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	byName := tools(t, set)
+	for _, name := range []string{"list_pets", "get_pets_pet_id"} {
+		if _, err := byName[name].Run(newToolContext(t, &session.EventActions{}), map[string]any{"petId": 1.0}); err != nil {
+			t.Fatalf("Run(%s) error = %v", name, err)
+		}
+	}
+	if diff := cmp.Diff([]string{"Bearer token1", "Bearer token1"}, gotAuth); diff != "" {
+		t.Errorf("Authorization headers mismatch (-want +got):\n%s", diff)
+	}
+	if got := tokenRequests.Load(); got != 1 {
+		t.Errorf("token requests = %d, want 1: the token is reused", got)
+	}
+
+	// Without client credentials, the token is requested from the user.
+	set, err = openapitoolset.New(openapitoolset.Config{
+		Spec:    []byte(spec),
+		BaseURL: server.URL,
+		AuthScheme: &auth.AuthScheme{
+			Type:             auth.OAuth2,
+			AuthorizationURL: server.URL + "/authorize",
+			TokenURL:         server.URL + "/token",
+		},
+		AuthCredential: &auth.AuthCredential{AuthType: auth.OAuth2, OAuth2: &auth.OAuth2Auth{ClientID: "client"}},
+	})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	actions := &session.EventActions{}
+	result, err := tools(t, set)["list_pets"].Run(newToolContext(t, actions), map[string]any{})
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if len(actions.RequestedAuthConfigs) != 1 {
+		t.Errorf("Run() = %v with requested credentials %v, want a credential request", result, actions.RequestedAuthConfigs)
+	}
+	if len(gotAuth) != 2 {
+		t.Errorf("Run() sent a request before the user authenticated")
+	}
+}
+
+func TestNew_InvalidSpec(t *testing.T) {
+	spec := string(readSpec(t))
+	for _, tc := range []struct {
+		name string
+		cfg  openapitoolset.Config
+	}{
+		{name: "no spec", cfg: openapitoolset.Config{}},
+		{name: "YAML", cfg: openapitoolset.Config{Spec: []byte("openapi: 3.0.0\npaths: {}\n")}},
+		{name: "Swagger 2", cfg: openapitoolset.Config{Spec: []byte(`{"swagger": "2.0", "paths": {}}`)}},
+		{name: "no server", cfg: openapitoolset.Config{Spec: []byte(`{"openapi": "3.1.0", "paths": {}}`)}},
+		{name: "external reference", cfg: openapitoolset.Config{Spec: []byte(strings.Replace(spec, "#/components/schemas/Pet", "pet.json", 1))}},
+		{name: "duplicate names", cfg: openapitoolset.Config{Spec: []byte(strings.Replace(spec, `"deletePet"`, `"createPet"`, 1))}},
+		{name: "unknown security scheme", cfg: openapitoolset.Config{Spec: []byte(strings.Replace(spec, `"security": [{"api_key": []}]`, `"security": [{"oauth": []}]`, 1))}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := openapitoolset.New(tc.cfg); err == nil {
+				t.Error("New() succeeded, want error")
+			}
+		})
+	}
+}
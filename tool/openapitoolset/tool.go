@@ -0,0 +1,341 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openapitoolset
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/auth"
+	"google.golang.org/adk/internal/toolinternal"
+	"google.golang.org/adk/internal/toolinternal/toolutils"
+	"google.golang.org/adk/model"
+	"google.golang.org/adk/tool"
+)
+
+// bodyParam is the name of the parameter of the request body.
+const bodyParam = "body"
+
+// maxNameLength is the maximum length of the names of the functions of the
+// models.
+const maxNameLength = 64
+
+// apiTool is the tool of an operation.
+type apiTool struct {
+	set         *set
+	op          *operation
+	name        string
+	description string
+	declaration *genai.FunctionDeclaration
+	schema      *jsonschema.Resolved
+	authScheme  *auth.AuthScheme
+	// bodyType is the content type of the request body, or empty if the
+	// operation has none.
+	bodyType string
+}
+
+func newAPITool(s *set, doc *document, op *operation) (*apiTool, error) {
+	t := &apiTool{set: s, op: op, name: toolName(op)}
+	t.description = strings.TrimSpace(op.Summary + "\n\n" + op.Description)
+	if t.description == "" {
+		t.description = op.Method + " " + op.Path
+	}
+
+	params := map[string]any{}
+	var required []string
+	for _, p := range op.Parameters {
+		if ignored(p) {
+			continue
+		}
+		if _, ok := params[p.Name]; ok {
+			return nil, fmt.Errorf("operation %s %s has several parameters named %q", op.Method, op.Path, p.Name)
+		}
+		params[p.Name] = withDescription(jsonSchema(p.Schema), p.Description)
+		if p.Required || p.In == "path" {
+			required = append(required, p.Name)
+		}
+	}
+	if body := op.RequestBody; body != nil && len(body.Content) > 0 {
+		if _, ok := params[bodyParam]; ok {
+			return nil, fmt.Errorf("operation %s %s has a parameter named %q, as its request body", op.Method, op.Path, bodyParam)
+		}
+		t.bodyType = bodyType(body)
+		schema := jsonSchema(body.Content[t.bodyType].Schema)
+		if !isJSON(t.bodyType) {
+			// Other bodies are sent as is.
+			schema = map[string]any{"type": "string"}
+		}
+		params[bodyParam] = withDescription(schema, body.Description)
+		if body.Required {
+			required = append(required, bodyParam)
+		}
+	}
+	slices.Sort(required)
+	encoded, err := json.Marshal(map[string]any{"type": "object", "properties": params, "required": required})
+	if err != nil {
+		return nil, err
+	}
+	var schema jsonschema.Schema
+	if err := json.Unmarshal(encoded, &schema); err != nil {
+		return nil, fmt.Errorf("invalid parameters of operation %s %s: %w", op.Method, op.Path, err)
+	}
+	if t.schema, err = schema.Resolve(nil); err != nil {
+		return nil, fmt.Errorf("invalid parameters of operation %s %s: %w", op.Method, op.Path, err)
+	}
+	t.declaration = &genai.FunctionDeclaration{
+		Name:                 t.name,
+		Description:          t.description,
+		ParametersJsonSchema: &schema,
+	}
+
+	t.authScheme = s.cfg.AuthScheme
+	if t.authScheme == nil {
+		if t.authScheme, err = doc.authScheme(op); err != nil {
+			return nil, fmt.Errorf("invalid security of operation %s %s: %w", op.Method, op.Path, err)
+		}
+	}
+	return t, nil
+}
+
+// ignored reports whether the parameter is ignored: the Accept,
+// Content-Type and Authorization headers are set from the document, see the
+// specification of the parameter object.
+func ignored(p *parameter) bool {
+	return p.In == "header" && slices.Contains([]string{"accept", "content-type", "authorization"}, strings.ToLower(p.Name))
+}
+
+var (
+	camelBoundary = regexp.MustCompile(`([a-z0-9])([A-Z])`)
+	invalidChars  = regexp.MustCompile(`[^a-z0-9]+`)
+)
+
+// toolName returns the name of the tool of the operation.
+func toolName(op *operation) string {
+	name := op.OperationID
+	if name == "" {
+		name = op.Method + "_" + op.Path
+	}
+	name = strings.ToLower(camelBoundary.ReplaceAllString(name, "${1}_${2}"))
+	name = strings.Trim(invalidChars.ReplaceAllString(name, "_"), "_")
+	if len(name) > maxNameLength {
+		name = name[:maxNameLength]
+	}
+	return name
+}
+
+// bodyType returns the content type of the request body sent by the tool:
+// JSON if the operation accepts it, or its first content type otherwise.
+func bodyType(body *requestBody) string {
+	types := make([]string, 0, len(body.Content))
+	for contentType := range body.Content {
+		types = append(types, contentType)
+	}
+	slices.Sort(types)
+	if i := slices.IndexFunc(types, isJSON); i >= 0 {
+		return types[i]
+	}
+	return types[0]
+}
+
+// isJSON reports whether the content type is JSON.
+func isJSON(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	return mediaType == "application/json" || strings.HasSuffix(mediaType, "+json")
+}
+
+// Name implements tool.Tool.
+func (t *apiTool) Name() string {
+	return t.name
+}
+
+// Description implements tool.Tool.
+func (t *apiTool) Description() string {
+	return t.description
+}
+
+// IsLongRunning implements tool.Tool.
+func (t *apiTool) IsLongRunning() bool {
+	return false
+}
+
+// ProcessRequest implements toolinternal.RequestProcessor.
+func (t *apiTool) ProcessRequest(ctx tool.Context, req *model.LLMRequest) error {
+	return toolutils.PackTool(req, t)
+}
+
+// Declaration implements toolinternal.FunctionTool.
+func (t *apiTool) Declaration() *genai.FunctionDeclaration {
+	return t.declaration
+}
+
+// Run implements toolinternal.FunctionTool.
+func (t *apiTool) Run(ctx tool.Context, args any) (map[string]any, error) {
+	return toolinternal.TraceRun(ctx, t.name, args, func(ctx tool.Context) (map[string]any, error) {
+		return t.run(ctx, args)
+	})
+}
+
+func (t *apiTool) run(ctx tool.Context, args any) (map[string]any, error) {
+	m, ok := args.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("unexpected args type, got: %T", args)
+	}
+	if err := t.schema.Validate(m); err != nil {
+		return nil, fmt.Errorf("invalid arguments: %w", err)
+	}
+	req, err := t.request(ctx, m)
+	if err != nil {
+		return nil, err
+	}
+	authenticated, err := t.set.authenticate(ctx, t.authScheme, req)
+	if err != nil {
+		return nil, err
+	}
+	if !authenticated {
+		return map[string]any{"status": "waiting for the user to authenticate"}, nil
+	}
+
+	resp, err := t.set.cfg.Client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call %s %s: %w", t.op.Method, t.op.Path, err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the response of %s %s: %w", t.op.Method, t.op.Path, err)
+	}
+	if resp.StatusCode >= 400 {
+		message := strings.TrimSpace(string(body))
+		if message == "" {
+			message = resp.Status
+		}
+		return nil, &tool.ToolError{Code: fmt.Sprintf("http_%d", resp.StatusCode), Message: message}
+	}
+	if len(bytes.TrimSpace(body)) == 0 {
+		return map[string]any{"status_code": resp.StatusCode}, nil
+	}
+	if isJSON(resp.Header.Get("Content-Type")) {
+		var result any
+		if err := json.Unmarshal(body, &result); err == nil {
+			if obj, ok := result.(map[string]any); ok {
+				return obj, nil
+			}
+			return map[string]any{"result": result}, nil
+		}
+	}
+	return map[string]any{"result": string(body)}, nil
+}
+
+// request returns the HTTP request of a call.
+func (t *apiTool) request(ctx tool.Context, args map[string]any) (*http.Request, error) {
+	path := t.op.Path
+	query := url.Values{}
+	header := http.Header{}
+	var cookies []*http.Cookie
+	for _, p := range t.op.Parameters {
+		v, ok := args[p.Name]
+		if !ok || v == nil || ignored(p) {
+			continue
+		}
+		switch p.In {
+		case "path":
+			path = strings.ReplaceAll(path, "{"+p.Name+"}", url.PathEscape(formatValue(v)))
+		case "query":
+			if values, ok := v.([]any); ok {
+				for _, e := range values {
+					query.Add(p.Name, formatValue(e))
+				}
+			} else {
+				query.Set(p.Name, formatValue(v))
+			}
+		case "header":
+			header.Set(p.Name, formatValue(v))
+		case "cookie":
+			cookies = append(cookies, &http.Cookie{Name: p.Name, Value: formatValue(v)})
+		}
+	}
+
+	var body io.Reader
+	if v, ok := args[bodyParam]; ok && t.bodyType != "" {
+		var encoded []byte
+		if s, ok := v.(string); ok && !isJSON(t.bodyType) {
+			encoded = []byte(s)
+		} else {
+			var err error
+			if encoded, err = json.Marshal(v); err != nil {
+				return nil, fmt.Errorf("failed to encode the request body: %w", err)
+			}
+		}
+		body = bytes.NewReader(encoded)
+		header.Set("Content-Type", t.bodyType)
+	}
+
+	u := t.set.baseURL + path
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+	req, err := http.NewRequestWithContext(ctx, t.op.Method, u, body)
+	if err != nil {
+		return nil, fmt.Errorf("invalid request of %s %s: %w", t.op.Method, t.op.Path, err)
+	}
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	req.Header.Set("Accept", "application/json")
+	for _, c := range cookies {
+		req.AddCookie(c)
+	}
+	return req, nil
+}
+
+// formatValue returns the string form of the value of a parameter: strings
+// as is, integers without exponent, arrays as comma-separated values, and
+// objects in JSON.
+func formatValue(v any) string {
+	switch v := v.(type) {
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	case []any:
+		values := make([]string, len(v))
+		for i, e := range v {
+			values[i] = formatValue(e)
+		}
+		return strings.Join(values, ",")
+	}
+	encoded, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(encoded)
+}
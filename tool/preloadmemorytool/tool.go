@@ -0,0 +1,96 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package preloadmemorytool defines the preload_memory tool, which adds the
+// memories relevant to the user message to the system instruction of each
+// model request, see the memory package and runner.Config.MemoryService.
+//
+// The tool is not called by the model: the memory is searched with the text
+// of the user message of the invocation, and the memories found are added as
+// past conversations. See the loadmemorytool package to let the model search
+// the memory itself.
+package preloadmemorytool
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	imemory "google.golang.org/adk/internal/memory"
+	"google.golang.org/adk/internal/toolinternal"
+	"google.golang.org/adk/internal/utils"
+	"google.golang.org/adk/model"
+	"google.golang.org/adk/tool"
+)
+
+var _ toolinternal.RequestProcessor = (*preloadTool)(nil)
+
+// preloadTool is a tool that adds the relevant memories to the requests.
+type preloadTool struct{}
+
+// New creates a preload_memory tool.
+func New() tool.Tool {
+	return &preloadTool{}
+}
+
+// Name implements tool.Tool.
+func (t *preloadTool) Name() string {
+	return "preload_memory"
+}
+
+// Description implements tool.Tool.
+func (t *preloadTool) Description() string {
+	return "Preloads the memory for the current user."
+}
+
+// IsLongRunning implements tool.Tool.
+func (t *preloadTool) IsLongRunning() bool {
+	return false
+}
+
+// ProcessRequest implements toolinternal.RequestProcessor. It searches the
+// memory with the text of the user message and appends the memories found
+// to the system instruction.
+func (t *preloadTool) ProcessRequest(ctx tool.Context, req *model.LLMRequest) error {
+	query := imemory.Text(ctx.UserContent())
+	if strings.TrimSpace(query) == "" {
+		return nil
+	}
+	resp, err := ctx.SearchMemory(ctx, query)
+	if err != nil {
+		return fmt.Errorf("failed to preload memory: %w", err)
+	}
+
+	var lines []string
+	for _, entry := range resp.Memories {
+		text := imemory.Text(entry.Content)
+		if text == "" {
+			continue
+		}
+		if !entry.Timestamp.IsZero() {
+			lines = append(lines, "Time: "+entry.Timestamp.Format(time.RFC3339))
+		}
+		if entry.Author != "" {
+			text = entry.Author + ": " + text
+		}
+		lines = append(lines, text)
+	}
+	if len(lines) == 0 {
+		return nil
+	}
+	utils.AppendInstructions(req, "The following content is from your previous conversations with the user."+
+		" They may be useful for answering the user's current query.\n"+
+		"<PAST_CONVERSATIONS>\n"+strings.Join(lines, "\n")+"\n</PAST_CONVERSATIONS>")
+	return nil
+}
@@ -0,0 +1,109 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package preloadmemorytool_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"google.golang.org/genai"
+
+	icontext "google.golang.org/adk/internal/context"
+	imemory "google.golang.org/adk/internal/memory"
+	"google.golang.org/adk/internal/toolinternal"
+	"google.golang.org/adk/memory"
+	"google.golang.org/adk/model"
+	"google.golang.org/adk/session"
+	"google.golang.org/adk/tool/preloadmemorytool"
+)
+
+// fakeMemory returns its memories for any query.
+type fakeMemory struct {
+	memories []memory.Entry
+	queries  []string
+}
+
+func (m *fakeMemory) AddSession(context.Context, session.Session) error {
+	return nil
+}
+
+func (m *fakeMemory) Search(_ context.Context, req *memory.SearchRequest) (*memory.SearchResponse, error) {
+	m.queries = append(m.queries, req.Query)
+	return &memory.SearchResponse{Memories: m.memories}, nil
+}
+
+func TestPreloadMemoryTool_ProcessRequest(t *testing.T) {
+	ts := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	tests := []struct {
+		name            string
+		userContent     *genai.Content
+		memories        []memory.Entry
+		wantQueries     []string
+		wantInstruction string
+	}{
+		{
+			name:        "memories found",
+			userContent: genai.NewContentFromText("What is my favorite color?", genai.RoleUser),
+			memories: []memory.Entry{
+				{Content: genai.NewContentFromText("My favorite color is blue", genai.RoleUser), Author: "user", Timestamp: ts},
+				{Content: genai.NewContentFromText("Noted.", genai.RoleModel), Author: "assistant"},
+			},
+			wantQueries: []string{"What is my favorite color?"},
+			wantInstruction: "The following content is from your previous conversations with the user." +
+				" They may be useful for answering the user's current query.\n" +
+				"<PAST_CONVERSATIONS>\n" +
+				"Time: 2025-01-02T03:04:05Z\n" +
+				"user: My favorite color is blue\n" +
+				"assistant: Noted.\n" +
+				"</PAST_CONVERSATIONS>",
+		},
+		{
+			name:        "no memories",
+			userContent: genai.NewContentFromText("Hello", genai.RoleUser),
+			wantQueries: []string{"Hello"},
+		},
+		{
+			name: "no user text",
+			userContent: &genai.Content{Role: genai.RoleUser, Parts: []*genai.Part{
+				genai.NewPartFromBytes([]byte("png"), "image/png"),
+			}},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mem := &fakeMemory{memories: tc.memories}
+			ctx := icontext.NewInvocationContext(t.Context(), icontext.InvocationContextParams{
+				Memory:      &imemory.Memory{Service: mem, AppName: "app", UserID: "user"},
+				UserContent: tc.userContent,
+			})
+			req := &model.LLMRequest{}
+			err := preloadmemorytool.New().(toolinternal.RequestProcessor).ProcessRequest(toolinternal.NewToolContext(ctx, "", nil), req)
+			if err != nil {
+				t.Fatalf("ProcessRequest() error = %v", err)
+			}
+			if len(mem.queries) != len(tc.wantQueries) || (len(mem.queries) > 0 && mem.queries[0] != tc.wantQueries[0]) {
+				t.Errorf("ProcessRequest() searched %q, want %q", mem.queries, tc.wantQueries)
+			}
+			var instruction string
+			if req.Config != nil && req.Config.SystemInstruction != nil {
+				instruction = req.Config.SystemInstruction.Parts[0].Text
+			}
+			if instruction != tc.wantInstruction {
+				t.Errorf("ProcessRequest() instruction = %q, want %q", instruction, tc.wantInstruction)
+			}
+		})
+	}
+}
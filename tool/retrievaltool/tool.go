@@ -0,0 +1,248 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package retrievaltool defines a retrieval tool, which lets the model
+// search a corpus of documents, e.g. for retrieval augmented generation.
+//
+// The documents are found by a [Retriever]: [NewVertexAISearch] returns one
+// searching a Vertex AI Search data store, and other stores, e.g. vector
+// databases, plug in by implementing the interface, or with a
+// [RetrieverFunc].
+//
+// The model calls the tool with a query, and gets the documents found,
+// numbered so that it cites them, e.g. [1], with their title and URI. With
+// Config.AttachContext, the documents relevant to the user message are also
+// added to the system instruction of each model request, without a call, for
+// grounded answers.
+package retrievaltool
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"google.golang.org/genai"
+
+	imemory "google.golang.org/adk/internal/memory"
+	"google.golang.org/adk/internal/toolinternal"
+	"google.golang.org/adk/internal/toolinternal/toolutils"
+	"google.golang.org/adk/internal/utils"
+	"google.golang.org/adk/model"
+	"google.golang.org/adk/tool"
+)
+
+const (
+	defaultName        = "retrieve"
+	defaultDescription = "Retrieves the documents relevant to the query from the knowledge base."
+	defaultMaxResults  = 5
+)
+
+// Document is a document, or a chunk of a document, found by a Retriever.
+type Document struct {
+	// ID identifies the document, or the chunk, in its store.
+	ID string `json:"id,omitempty"`
+	// Content is the text of the document, or of the chunk.
+	Content string `json:"content"`
+	// Title is the title of the source document.
+	Title string `json:"title,omitempty"`
+	// URI is the location of the source document.
+	URI string `json:"uri,omitempty"`
+	// Score is the relevance of the document to the query, if known.
+	Score float64 `json:"score,omitempty"`
+	// Metadata optionally holds other data about the document.
+	Metadata map[string]any `json:"metadata,omitempty"`
+}
+
+// Retriever finds the documents relevant to a query.
+type Retriever interface {
+	// Retrieve returns at most limit documents relevant to the query, the
+	// most relevant first.
+	Retrieve(ctx context.Context, query string, limit int) ([]Document, error)
+}
+
+// RetrieverFunc is a function implementing Retriever.
+type RetrieverFunc func(ctx context.Context, query string, limit int) ([]Document, error)
+
+// Retrieve implements Retriever.
+func (f RetrieverFunc) Retrieve(ctx context.Context, query string, limit int) ([]Document, error) {
+	return f(ctx, query, limit)
+}
+
+// Config is the configuration of the retrieval tool.
+type Config struct {
+	// Name of the tool. Defaults to "retrieve".
+	Name string
+	// Description of the tool for the model, e.g. the content of the
+	// corpus. Defaults to a generic description.
+	Description string
+	// Retriever finds the documents. Required.
+	Retriever Retriever
+	// MaxResults is the maximum number of documents returned for a query.
+	// Defaults to 5.
+	MaxResults int
+	// AttachContext adds the documents relevant to the text of the user
+	// message of the invocation to the system instruction of each model
+	// request, asking the model to ground its answer on them and to cite
+	// them. The model can still call the tool for other queries.
+	AttachContext bool
+}
+
+// New creates a retrieval tool.
+func New(cfg Config) (tool.Tool, error) {
+	if cfg.Retriever == nil {
+		return nil, errors.New("retriever is required")
+	}
+	if cfg.Name == "" {
+		cfg.Name = defaultName
+	}
+	if cfg.Description == "" {
+		cfg.Description = defaultDescription
+	}
+	if cfg.MaxResults <= 0 {
+		cfg.MaxResults = defaultMaxResults
+	}
+	return &retrievalTool{cfg: cfg}, nil
+}
+
+var (
+	_ toolinternal.FunctionTool     = (*retrievalTool)(nil)
+	_ toolinternal.RequestProcessor = (*retrievalTool)(nil)
+)
+
+type retrievalTool struct {
+	cfg Config
+}
+
+// Name implements tool.Tool.
+func (t *retrievalTool) Name() string {
+	return t.cfg.Name
+}
+
+// Description implements tool.Tool.
+func (t *retrievalTool) Description() string {
+	return t.cfg.Description
+}
+
+// IsLongRunning implements tool.Tool.
+func (t *retrievalTool) IsLongRunning() bool {
+	return false
+}
+
+// Declaration implements toolinternal.FunctionTool.
+func (t *retrievalTool) Declaration() *genai.FunctionDeclaration {
+	return &genai.FunctionDeclaration{
+		Name:        t.cfg.Name,
+		Description: t.cfg.Description,
+		Parameters: &genai.Schema{
+			Type: "OBJECT",
+			Properties: map[string]*genai.Schema{
+				"query": {
+					Type:        "STRING",
+					Description: "The query to retrieve the documents with.",
+				},
+			},
+			Required: []string{"query"},
+		},
+	}
+}
+
+// Run implements toolinternal.FunctionTool. It returns the documents found
+// for the query, each with its citation number.
+func (t *retrievalTool) Run(ctx tool.Context, args any) (map[string]any, error) {
+	m, ok := args.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("unexpected args type, got: %T", args)
+	}
+	query, ok := m["query"].(string)
+	if !ok {
+		return nil, fmt.Errorf("query must be a string, got: %T", m["query"])
+	}
+	docs, err := t.retrieve(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	documents := make([]any, 0, len(docs))
+	for i, doc := range docs {
+		document := map[string]any{
+			"citation": i + 1,
+			"content":  doc.Content,
+		}
+		if doc.Title != "" {
+			document["title"] = doc.Title
+		}
+		if doc.URI != "" {
+			document["uri"] = doc.URI
+		}
+		documents = append(documents, document)
+	}
+	return map[string]any{"documents": documents}, nil
+}
+
+// ProcessRequest implements toolinternal.RequestProcessor. It declares the
+// tool and, with Config.AttachContext, appends the documents relevant to
+// the user message to the system instruction.
+func (t *retrievalTool) ProcessRequest(ctx tool.Context, req *model.LLMRequest) error {
+	if err := toolutils.PackTool(req, t); err != nil {
+		return err
+	}
+	if !t.cfg.AttachContext {
+		return nil
+	}
+	query := imemory.Text(ctx.UserContent())
+	if strings.TrimSpace(query) == "" {
+		return nil
+	}
+	docs, err := t.retrieve(ctx, query)
+	if err != nil {
+		return err
+	}
+	if len(docs) == 0 {
+		return nil
+	}
+	utils.AppendInstructions(req, "The following documents were retrieved for the user's query."+
+		" Ground your answer on them, and cite the documents you use with their number in square brackets, e.g. [1]."+
+		" If they don't answer the query, say so rather than guessing.\n"+
+		"<DOCUMENTS>\n"+formatDocuments(docs)+"</DOCUMENTS>")
+	return nil
+}
+
+func (t *retrievalTool) retrieve(ctx context.Context, query string) ([]Document, error) {
+	docs, err := t.cfg.Retriever.Retrieve(ctx, query, t.cfg.MaxResults)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve documents: %w", err)
+	}
+	if len(docs) > t.cfg.MaxResults {
+		docs = docs[:t.cfg.MaxResults]
+	}
+	return docs, nil
+}
+
+// formatDocuments returns the documents as numbered text, with their
+// sources.
+func formatDocuments(docs []Document) string {
+	var b strings.Builder
+	for i, doc := range docs {
+		b.WriteString("[" + strconv.Itoa(i+1) + "]")
+		if doc.Title != "" {
+			b.WriteString(" " + doc.Title)
+		}
+		if doc.URI != "" {
+			b.WriteString(" (" + doc.URI + ")")
+		}
+		b.WriteString("\n" + strings.TrimSpace(doc.Content) + "\n")
+	}
+	return b.String()
+}
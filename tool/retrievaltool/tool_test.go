@@ -0,0 +1,212 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package retrievaltool_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/api/option"
+	"google.golang.org/genai"
+
+	icontext "google.golang.org/adk/internal/context"
+	"google.golang.org/adk/internal/toolinternal"
+	"google.golang.org/adk/model"
+	"google.golang.org/adk/tool"
+	"google.golang.org/adk/tool/retrievaltool"
+)
+
+var docs = []retrievaltool.Document{
+	{ID: "c1", Content: "Refunds are issued within 14 days.", Title: "Refund policy", URI: "https://example.com/refunds"},
+	{ID: "c2", Content: "Shipping is free above 50 EUR.", Title: "Shipping"},
+	{ID: "c3", Content: "unused"},
+}
+
+// fakeRetriever returns its documents for any query, ignoring the limit.
+type fakeRetriever struct {
+	queries []string
+}
+
+func (r *fakeRetriever) Retrieve(_ context.Context, query string, limit int) ([]retrievaltool.Document, error) {
+	r.queries = append(r.queries, query)
+	return docs, nil
+}
+
+func toolContext(t *testing.T, userText string) tool.Context {
+	t.Helper()
+	ctx := icontext.NewInvocationContext(t.Context(), icontext.InvocationContextParams{
+		UserContent: genai.NewContentFromText(userText, genai.RoleUser),
+	})
+	return toolinternal.NewToolContext(ctx, "", nil)
+}
+
+func TestRetrievalTool_Run(t *testing.T) {
+	retriever := &fakeRetriever{}
+	rt, err := retrievaltool.New(retrievaltool.Config{Retriever: retriever, MaxResults: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rt.Name() != "retrieve" {
+		t.Errorf("Name() = %q, want retrieve", rt.Name())
+	}
+	got, err := rt.(toolinternal.FunctionTool).Run(toolContext(t, ""), map[string]any{"query": "refunds"})
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	want := map[string]any{"documents": []any{
+		map[string]any{"citation": 1, "content": "Refunds are issued within 14 days.", "title": "Refund policy", "uri": "https://example.com/refunds"},
+		map[string]any{"citation": 2, "content": "Shipping is free above 50 EUR.", "title": "Shipping"},
+	}}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Run() mismatch (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff([]string{"refunds"}, retriever.queries); diff != "" {
+		t.Errorf("queries mismatch (-want +got):\n%s", diff)
+	}
+
+	if _, err := retrievaltool.New(retrievaltool.Config{}); err == nil {
+		t.Error("New() without retriever succeeded, want error")
+	}
+}
+
+func TestRetrievalTool_ProcessRequest(t *testing.T) {
+	for _, tc := range []struct {
+		name            string
+		attach          bool
+		userText        string
+		wantQueries     []string
+		wantInstruction string
+	}{
+		{
+			name:     "declared only",
+			userText: "How long do refunds take?",
+		},
+		{
+			name:        "attached context",
+			attach:      true,
+			userText:    "How long do refunds take?",
+			wantQueries: []string{"How long do refunds take?"},
+			wantInstruction: "The following documents were retrieved for the user's query." +
+				" Ground your answer on them, and cite the documents you use with their number in square brackets, e.g. [1]." +
+				" If they don't answer the query, say so rather than guessing.\n" +
+				"<DOCUMENTS>\n" +
+				"[1] Refund policy (https://example.com/refunds)\nRefunds are issued within 14 days.\n" +
+				"[2] Shipping\nShipping is free above 50 EUR.\n" +
+				"</DOCUMENTS>",
+		},
+		{
+			name:   "no user text",
+			attach: true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			retriever := &fakeRetriever{}
+			rt, err := retrievaltool.New(retrievaltool.Config{Name: "search_docs", Retriever: retriever, MaxResults: 2, AttachContext: tc.attach})
+			if err != nil {
+				t.Fatal(err)
+			}
+			req := &model.LLMRequest{}
+			if err := rt.(toolinternal.RequestProcessor).ProcessRequest(toolContext(t, tc.userText), req); err != nil {
+				t.Fatalf("ProcessRequest() error = %v", err)
+			}
+			if req.Tools["search_docs"] != rt {
+				t.Errorf("ProcessRequest() did not declare the tool: %v", req.Tools)
+			}
+			if diff := cmp.Diff(tc.wantQueries, retriever.queries); diff != "" {
+				t.Errorf("queries mismatch (-want +got):\n%s", diff)
+			}
+			var instruction string
+			if req.Config.SystemInstruction != nil {
+				instruction = req.Config.SystemInstruction.Parts[0].Text
+			}
+			if instruction != tc.wantInstruction {
+				t.Errorf("ProcessRequest() instruction = %q, want %q", instruction, tc.wantInstruction)
+			}
+		})
+	}
+}
+
+func TestVertexAISearch(t *testing.T) {
+	const dataStore = "projects/p/locations/global/collections/default_collection/dataStores/kb"
+	var gotPath string
+	var gotReq map[string]any
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		if err := json.NewDecoder(r.Body).Decode(&gotReq); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]any{"results": []any{
+			map[string]any{"chunk": map[string]any{
+				"id":               "c1",
+				"content":          "Refunds are issued within 14 days.",
+				"relevanceScore":   0.9,
+				"documentMetadata": map[string]any{"title": "Refund policy", "uri": "gs://kb/refunds.pdf"},
+				"pageSpan":         map[string]any{"pageStart": 2, "pageEnd": 3},
+			}},
+			map[string]any{"document": map[string]any{
+				"id": "d2",
+				"derivedStructData": map[string]any{
+					"title":               "Shipping",
+					"link":                "gs://kb/shipping.pdf",
+					"extractive_segments": []any{map[string]any{"content": "Shipping is free above 50 EUR."}},
+				},
+			}},
+			map[string]any{"document": map[string]any{"id": "d3"}},
+		}})
+	}))
+	defer srv.Close()
+
+	retriever, err := retrievaltool.NewVertexAISearch(t.Context(), retrievaltool.VertexAISearchConfig{
+		DataStore:     dataStore,
+		Filter:        `category: ANY("faq")`,
+		ClientOptions: []option.ClientOption{option.WithEndpoint(srv.URL + "/"), option.WithoutAuthentication()},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := retriever.Retrieve(t.Context(), "refunds", 3)
+	if err != nil {
+		t.Fatalf("Retrieve() error = %v", err)
+	}
+	want := []retrievaltool.Document{
+		{ID: "c1", Content: "Refunds are issued within 14 days.", Title: "Refund policy", URI: "gs://kb/refunds.pdf", Score: 0.9, Metadata: map[string]any{"page_start": int64(2), "page_end": int64(3)}},
+		{ID: "d2", Content: "Shipping is free above 50 EUR.", Title: "Shipping", URI: "gs://kb/shipping.pdf"},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Retrieve() mismatch (-want +got):\n%s", diff)
+	}
+	if want := "/v1/" + dataStore + "/servingConfigs/default_search:search"; gotPath != want {
+		t.Errorf("request path = %q, want %q", gotPath, want)
+	}
+	wantReq := map[string]any{
+		"query":             "refunds",
+		"pageSize":          float64(3),
+		"filter":            `category: ANY("faq")`,
+		"contentSearchSpec": map[string]any{"searchResultMode": "CHUNKS"},
+	}
+	if diff := cmp.Diff(wantReq, gotReq); diff != "" {
+		t.Errorf("request mismatch (-want +got):\n%s", diff)
+	}
+
+	if _, err := retrievaltool.NewVertexAISearch(t.Context(), retrievaltool.VertexAISearchConfig{}); err == nil {
+		t.Error("NewVertexAISearch() without data store succeeded, want error")
+	}
+}
@@ -0,0 +1,180 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package retrievaltool
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"google.golang.org/api/discoveryengine/v1"
+	"google.golang.org/api/option"
+)
+
+// VertexAISearchConfig is the configuration of a Vertex AI Search retriever.
+type VertexAISearchConfig struct {
+	// ServingConfig is the resource name of the serving config searched,
+	// e.g.
+	//
+	//	projects/<project>/locations/global/collections/default_collection/engines/<engine>/servingConfigs/default_search
+	//
+	// Either ServingConfig or DataStore is required.
+	ServingConfig string
+	// DataStore is the resource name of the data store searched, with its
+	// default serving config, e.g.
+	//
+	//	projects/<project>/locations/global/collections/default_collection/dataStores/<data store>
+	DataStore string
+	// Filter optionally restricts the documents searched, in the syntax of
+	// Vertex AI Search, e.g. `category: ANY("faq")`.
+	Filter string
+	// DocumentMode searches whole documents, returning their extractive
+	// segments, in place of chunks, for the data stores without chunking.
+	DocumentMode bool
+	// ClientOptions configure the client, e.g. its credentials. Defaults to
+	// the Application Default Credentials, and to the endpoint of the
+	// location of the serving config.
+	ClientOptions []option.ClientOption
+}
+
+// NewVertexAISearch returns a retriever searching a Vertex AI Search data
+// store or engine.
+func NewVertexAISearch(ctx context.Context, cfg VertexAISearchConfig) (Retriever, error) {
+	servingConfig := cfg.ServingConfig
+	if servingConfig == "" {
+		if cfg.DataStore == "" {
+			return nil, errors.New("serving config or data store is required")
+		}
+		servingConfig = strings.TrimSuffix(cfg.DataStore, "/") + "/servingConfigs/default_search"
+	}
+	var opts []option.ClientOption
+	if location := resourceLocation(servingConfig); location != "" && location != "global" {
+		opts = append(opts, option.WithEndpoint("https://"+location+"-discoveryengine.googleapis.com/"))
+	}
+	svc, err := discoveryengine.NewService(ctx, append(opts, cfg.ClientOptions...)...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Vertex AI Search client: %w", err)
+	}
+	return &vertexAISearch{svc: svc, servingConfig: servingConfig, cfg: cfg}, nil
+}
+
+// resourceLocation returns the location of the resource name, e.g. "eu" for
+// projects/p/locations/eu/..., or "".
+func resourceLocation(name string) string {
+	_, rest, ok := strings.Cut(name, "/locations/")
+	if !ok {
+		return ""
+	}
+	location, _, _ := strings.Cut(rest, "/")
+	return location
+}
+
+type vertexAISearch struct {
+	svc           *discoveryengine.Service
+	servingConfig string
+	cfg           VertexAISearchConfig
+}
+
+// Retrieve implements Retriever.
+func (s *vertexAISearch) Retrieve(ctx context.Context, query string, limit int) ([]Document, error) {
+	req := &discoveryengine.GoogleCloudDiscoveryengineV1SearchRequest{
+		Query:    query,
+		PageSize: int64(limit),
+		Filter:   s.cfg.Filter,
+		ContentSearchSpec: &discoveryengine.GoogleCloudDiscoveryengineV1SearchRequestContentSearchSpec{
+			SearchResultMode: "CHUNKS",
+		},
+	}
+	if s.cfg.DocumentMode {
+		req.ContentSearchSpec = &discoveryengine.GoogleCloudDiscoveryengineV1SearchRequestContentSearchSpec{
+			SearchResultMode: "DOCUMENTS",
+			ExtractiveContentSpec: &discoveryengine.GoogleCloudDiscoveryengineV1SearchRequestContentSearchSpecExtractiveContentSpec{
+				MaxExtractiveSegmentCount: 1,
+			},
+		}
+	}
+	resp, err := s.svc.Projects.Locations.Collections.DataStores.ServingConfigs.Search(s.servingConfig, req).Context(ctx).Do()
+	if err != nil {
+		return nil, fmt.Errorf("failed to search Vertex AI Search: %w", err)
+	}
+	var docs []Document
+	for _, result := range resp.Results {
+		switch {
+		case result.Chunk != nil:
+			docs = append(docs, chunkDocument(result.Chunk))
+		case result.Document != nil:
+			if doc, ok := documentDocument(result.Document); ok {
+				docs = append(docs, doc)
+			}
+		}
+	}
+	return docs, nil
+}
+
+func chunkDocument(chunk *discoveryengine.GoogleCloudDiscoveryengineV1Chunk) Document {
+	doc := Document{
+		ID:      chunk.Id,
+		Content: chunk.Content,
+		Score:   chunk.RelevanceScore,
+	}
+	if meta := chunk.DocumentMetadata; meta != nil {
+		doc.Title, doc.URI = meta.Title, meta.Uri
+	}
+	if chunk.PageSpan != nil {
+		doc.Metadata = map[string]any{"page_start": chunk.PageSpan.PageStart, "page_end": chunk.PageSpan.PageEnd}
+	}
+	return doc
+}
+
+// documentDocument returns the document of a search in DOCUMENTS mode, whose
+// content is its extractive segments, or its snippets. It reports false if
+// the document has no content.
+func documentDocument(d *discoveryengine.GoogleCloudDiscoveryengineV1Document) (Document, bool) {
+	var data struct {
+		Title              string `json:"title"`
+		Link               string `json:"link"`
+		ExtractiveSegments []struct {
+			Content string `json:"content"`
+		} `json:"extractive_segments"`
+		Snippets []struct {
+			Snippet string `json:"snippet"`
+		} `json:"snippets"`
+	}
+	if len(d.DerivedStructData) > 0 {
+		if err := json.Unmarshal(d.DerivedStructData, &data); err != nil {
+			return Document{}, false
+		}
+	}
+	var contents []string
+	for _, seg := range data.ExtractiveSegments {
+		contents = append(contents, seg.Content)
+	}
+	if len(contents) == 0 {
+		for _, snippet := range data.Snippets {
+			contents = append(contents, snippet.Snippet)
+		}
+	}
+	if len(contents) == 0 {
+		return Document{}, false
+	}
+	return Document{
+		ID:      d.Id,
+		Content: strings.Join(contents, "\n"),
+		Title:   data.Title,
+		URI:     data.Link,
+	}, true
+}
@@ -0,0 +1,162 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package retrytool
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"google.golang.org/adk/internal/toolinternal"
+	"google.golang.org/adk/model"
+	"google.golang.org/adk/tool"
+)
+
+const (
+	defaultFailureThreshold = 5
+	defaultOpenDuration     = 30 * time.Second
+)
+
+// ErrCodeUnavailable is the code of the tool.ToolError of the calls rejected
+// by an open circuit breaker.
+const ErrCodeUnavailable = "unavailable"
+
+// BreakerPolicy configures a circuit breaker.
+type BreakerPolicy struct {
+	// FailureThreshold is the number of consecutive failed calls opening the
+	// circuit. Defaults to 5.
+	FailureThreshold int
+	// OpenDuration is how long the circuit stays open, rejecting the calls,
+	// before a trial call is let through. Defaults to 30s.
+	OpenDuration time.Duration
+	// IsFailure reports whether the error of a call is a failure of the
+	// service. If it is nil, all the errors are failures except the
+	// tool.ToolError errors, which report the errors of the requests, e.g. an
+	// unknown ID, and the cancellations of the invocations.
+	IsFailure func(error) bool
+}
+
+// WithCircuitBreaker returns a tool behaving like the function tool t, except
+// that once policy.FailureThreshold calls failed in a row, its calls fail
+// immediately with a tool.ToolError whose code is ErrCodeUnavailable for
+// policy.OpenDuration, without running t, rather than waiting on a service
+// which is down. The model is told that the tool is unavailable, and can do
+// without it. Then, one call is run as a trial: the circuit closes if it
+// succeeds, and opens again otherwise.
+//
+// The state of the breaker is shared by all the calls of the returned tool,
+// across sessions. Wrap a tool returned by WithRetry to count the calls whose
+// retries all failed as one failure.
+//
+// The tools which are not function tools, or are long-running, are returned
+// as is.
+func WithCircuitBreaker(t tool.Tool, policy BreakerPolicy) tool.Tool {
+	funcTool, ok := t.(toolinternal.FunctionTool)
+	if !ok || t.IsLongRunning() {
+		return t
+	}
+	if policy.FailureThreshold <= 0 {
+		policy.FailureThreshold = defaultFailureThreshold
+	}
+	if policy.OpenDuration <= 0 {
+		policy.OpenDuration = defaultOpenDuration
+	}
+	return &breakerTool{FunctionTool: funcTool, policy: policy}
+}
+
+type breakerTool struct {
+	toolinternal.FunctionTool
+	policy BreakerPolicy
+
+	mu sync.Mutex
+	// failures is the number of consecutive failed calls.
+	failures int
+	// openUntil is the end of the open period, zero if the circuit is closed.
+	openUntil time.Time
+	// trial reports whether a trial call is running, once the open period
+	// ended.
+	trial bool
+}
+
+// Unwrap returns the wrapped tool.
+func (t *breakerTool) Unwrap() toolinternal.FunctionTool {
+	return t.FunctionTool
+}
+
+// ProcessRequest lets the wrapped tool process the request, and registers the
+// wrapper in its place, see toolinternal.RegisterWrapper.
+func (t *breakerTool) ProcessRequest(ctx tool.Context, req *model.LLMRequest) error {
+	return toolinternal.RegisterWrapper(ctx, req, t.FunctionTool, t)
+}
+
+// Run runs the wrapped tool unless the circuit is open.
+func (t *breakerTool) Run(ctx tool.Context, args any) (map[string]any, error) {
+	trial, err := t.acquire()
+	if err != nil {
+		return nil, err
+	}
+	result, err := t.FunctionTool.Run(ctx, args)
+	t.release(trial, err != nil && t.isFailure(err))
+	return result, err
+}
+
+// acquire reports whether the call may run, and whether it is the trial call
+// closing the circuit.
+func (t *breakerTool) acquire() (trial bool, err error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.openUntil.IsZero() {
+		return false, nil
+	}
+	if remaining := time.Until(t.openUntil); remaining > 0 || t.trial {
+		if remaining < 0 {
+			remaining = 0
+		}
+		return false, &tool.ToolError{
+			Code:    ErrCodeUnavailable,
+			Message: fmt.Sprintf("the tool %q is temporarily unavailable after repeated failures, retry in %v", t.Name(), remaining.Round(time.Second)),
+		}
+	}
+	t.trial = true
+	return true, nil
+}
+
+// release records the outcome of a call.
+func (t *breakerTool) release(trial, failed bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if trial {
+		t.trial = false
+	}
+	if !failed {
+		t.failures = 0
+		t.openUntil = time.Time{}
+		return
+	}
+	t.failures++
+	if trial || t.failures >= t.policy.FailureThreshold {
+		t.openUntil = time.Now().Add(t.policy.OpenDuration)
+	}
+}
+
+func (t *breakerTool) isFailure(err error) bool {
+	if t.policy.IsFailure != nil {
+		return t.policy.IsFailure(err)
+	}
+	var toolErr *tool.ToolError
+	return !errors.As(err, &toolErr) && !errors.Is(err, context.Canceled)
+}
@@ -0,0 +1,140 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package retrytool wraps function tools calling external APIs to retry their
+// transient failures.
+//
+// The attempts are spaced by a jittered exponential backoff. Each attempt is
+// a run of the wrapped tool, so that a function tool with a
+// functiontool.Config.Timeout gets a new deadline for each attempt. The state
+// changes of a failed attempt are discarded before the next one.
+//
+// WithCircuitBreaker stops calling a tool whose service keeps failing for a
+// while, reporting it as unavailable to the model instead.
+package retrytool
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"time"
+
+	"google.golang.org/adk/internal/toolinternal"
+	"google.golang.org/adk/model"
+	"google.golang.org/adk/tool"
+)
+
+const (
+	defaultMaxAttempts = 3
+	defaultBaseBackoff = 100 * time.Millisecond
+	defaultMaxBackoff  = 10 * time.Second
+)
+
+// RetryPolicy configures the retries of the failed calls.
+type RetryPolicy struct {
+	// MaxAttempts is the maximum number of runs of the tool for a call,
+	// including the first one. Defaults to 3.
+	MaxAttempts int
+	// BaseBackoff is the delay before the first retry, doubled for each next
+	// retry. Defaults to 100ms.
+	BaseBackoff time.Duration
+	// MaxBackoff caps the delay between two attempts. Defaults to 10s.
+	MaxBackoff time.Duration
+	// Retryable reports whether the call is retried after the error. If it is
+	// nil, all the errors are retried.
+	Retryable func(error) bool
+}
+
+// WithRetry returns a tool behaving like the function tool t, except that its
+// calls failing with a retryable error are run again, up to
+// policy.MaxAttempts times. The last error is returned if all the attempts
+// fail. The retries stop once the context of the call is done.
+//
+// The tools which are not function tools, or are long-running, are returned
+// as is.
+func WithRetry(t tool.Tool, policy RetryPolicy) tool.Tool {
+	funcTool, ok := t.(toolinternal.FunctionTool)
+	if !ok || t.IsLongRunning() {
+		return t
+	}
+	if policy.MaxAttempts <= 0 {
+		policy.MaxAttempts = defaultMaxAttempts
+	}
+	if policy.BaseBackoff <= 0 {
+		policy.BaseBackoff = defaultBaseBackoff
+	}
+	if policy.MaxBackoff <= 0 {
+		policy.MaxBackoff = defaultMaxBackoff
+	}
+	return &retryingTool{FunctionTool: funcTool, policy: policy}
+}
+
+type retryingTool struct {
+	toolinternal.FunctionTool
+	policy RetryPolicy
+}
+
+// Unwrap returns the wrapped tool.
+func (t *retryingTool) Unwrap() toolinternal.FunctionTool {
+	return t.FunctionTool
+}
+
+// ProcessRequest lets the wrapped tool process the request, and registers the
+// wrapper in its place, see toolinternal.RegisterWrapper.
+func (t *retryingTool) ProcessRequest(ctx tool.Context, req *model.LLMRequest) error {
+	return toolinternal.RegisterWrapper(ctx, req, t.FunctionTool, t)
+}
+
+// Run runs the wrapped tool until it succeeds, fails with an error which is
+// not retryable, or the attempts are exhausted.
+func (t *retryingTool) Run(ctx tool.Context, args any) (map[string]any, error) {
+	parent := context.Context(context.Background())
+	if ctx != nil {
+		parent = ctx
+	}
+	for attempt := 1; ; attempt++ {
+		result, err := t.FunctionTool.Run(ctx, args)
+		if err == nil || attempt >= t.policy.MaxAttempts || !t.retryable(err) || parent.Err() != nil {
+			return result, err
+		}
+		if ctx != nil {
+			toolinternal.DiscardState(ctx)
+		}
+
+		timer := time.NewTimer(t.backoff(attempt))
+		select {
+		case <-timer.C:
+		case <-parent.Done():
+			timer.Stop()
+			return nil, fmt.Errorf("retries of tool %q stopped: %w", t.Name(), errors.Join(parent.Err(), err))
+		}
+	}
+}
+
+func (t *retryingTool) retryable(err error) bool {
+	return t.policy.Retryable == nil || t.policy.Retryable(err)
+}
+
+// backoff returns the delay before the retry following the given attempt:
+// BaseBackoff doubled for each previous retry, capped by MaxBackoff, and
+// jittered between half and all of it.
+func (t *retryingTool) backoff(attempt int) time.Duration {
+	d := t.policy.BaseBackoff
+	for i := 1; i < attempt && d < t.policy.MaxBackoff; i++ {
+		d *= 2
+	}
+	d = min(d, t.policy.MaxBackoff)
+	return d/2 + rand.N(d/2+1)
+}
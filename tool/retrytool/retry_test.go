@@ -0,0 +1,248 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package retrytool_test
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	icontext "google.golang.org/adk/internal/context"
+	"google.golang.org/adk/internal/toolinternal"
+	"google.golang.org/adk/session"
+	"google.golang.org/adk/tool"
+	"google.golang.org/adk/tool/functiontool"
+	"google.golang.org/adk/tool/retrytool"
+)
+
+var (
+	errUnavailable = errors.New("service unavailable")
+	errBadRequest  = errors.New("bad request")
+)
+
+type quoteArgs struct {
+	Symbol string `json:"symbol"`
+}
+
+// newQuoteTool returns a tool failing with the given errors before
+// succeeding, counting its runs.
+func newQuoteTool(t *testing.T, cfg functiontool.Config, runs *atomic.Int32, failures ...error) tool.Tool {
+	t.Helper()
+	cfg.Name = "quote"
+	cfg.Description = "Returns the quote of a stock."
+	quoteTool, err := functiontool.New(cfg, func(ctx tool.Context, args quoteArgs) (map[string]any, error) {
+		n := int(runs.Add(1))
+		if n > len(failures) {
+			return map[string]any{"symbol": args.Symbol, "price": 42.0}, nil
+		}
+		if failures[n-1] == context.DeadlineExceeded {
+			// Blocks until the attempt times out.
+			<-ctx.Done()
+			return nil, nil
+		}
+		ctx.State().Set("attempt", n)
+		return nil, failures[n-1]
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return quoteTool
+}
+
+func newToolContext(t *testing.T, ctx context.Context) tool.Context {
+	t.Helper()
+	resp, err := session.InMemoryService().Create(ctx, &session.CreateRequest{AppName: "app", UserID: "user"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	inv := icontext.NewInvocationContext(ctx, icontext.InvocationContextParams{Session: resp.Session})
+	return toolinternal.NewToolContext(inv, "call1", nil)
+}
+
+func TestWithRetry_SucceedsOnThirdTry(t *testing.T) {
+	var runs atomic.Int32
+	wrapped := retrytool.WithRetry(newQuoteTool(t, functiontool.Config{}, &runs, errUnavailable, errUnavailable),
+		retrytool.RetryPolicy{BaseBackoff: time.Millisecond})
+	ctx := newToolContext(t, t.Context())
+
+	got, err := wrapped.(toolinternal.FunctionTool).Run(ctx, map[string]any{"symbol": "GOOG"})
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if diff := cmp.Diff(map[string]any{"symbol": "GOOG", "price": 42.0}, got); diff != "" {
+		t.Errorf("Run() mismatch (-want +got):\n%s", diff)
+	}
+	if got := runs.Load(); got != 3 {
+		t.Errorf("got %d runs, want 3", got)
+	}
+	if delta := ctx.Actions().StateDelta; len(delta) != 0 {
+		t.Errorf("StateDelta = %v, want the changes of the failed attempts discarded", delta)
+	}
+}
+
+func TestWithRetry_NonRetryableError(t *testing.T) {
+	var runs atomic.Int32
+	wrapped := retrytool.WithRetry(newQuoteTool(t, functiontool.Config{}, &runs, errBadRequest),
+		retrytool.RetryPolicy{
+			BaseBackoff: time.Millisecond,
+			Retryable:   func(err error) bool { return errors.Is(err, errUnavailable) },
+		})
+
+	_, err := wrapped.(toolinternal.FunctionTool).Run(newToolContext(t, t.Context()), map[string]any{"symbol": "GOOG"})
+	if !errors.Is(err, errBadRequest) {
+		t.Errorf("Run() error = %v, want %v", err, errBadRequest)
+	}
+	if got := runs.Load(); got != 1 {
+		t.Errorf("got %d runs, want 1", got)
+	}
+}
+
+func TestWithRetry_AttemptsExhausted(t *testing.T) {
+	var runs atomic.Int32
+	wrapped := retrytool.WithRetry(newQuoteTool(t, functiontool.Config{}, &runs, errUnavailable, errUnavailable, errBadRequest),
+		retrytool.RetryPolicy{MaxAttempts: 3, BaseBackoff: time.Millisecond})
+
+	_, err := wrapped.(toolinternal.FunctionTool).Run(newToolContext(t, t.Context()), map[string]any{"symbol": "GOOG"})
+	if !errors.Is(err, errBadRequest) {
+		t.Errorf("Run() error = %v, want the last error %v", err, errBadRequest)
+	}
+	if got := runs.Load(); got != 3 {
+		t.Errorf("got %d runs, want 3", got)
+	}
+}
+
+func TestWithRetry_TimeoutPerAttempt(t *testing.T) {
+	var runs atomic.Int32
+	wrapped := retrytool.WithRetry(
+		newQuoteTool(t, functiontool.Config{Timeout: 10 * time.Millisecond}, &runs, context.DeadlineExceeded),
+		retrytool.RetryPolicy{BaseBackoff: time.Millisecond})
+
+	if _, err := wrapped.(toolinternal.FunctionTool).Run(newToolContext(t, t.Context()), map[string]any{"symbol": "GOOG"}); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if got := runs.Load(); got != 2 {
+		t.Errorf("got %d runs, want 2", got)
+	}
+}
+
+func TestWithRetry_Canceled(t *testing.T) {
+	var runs atomic.Int32
+	wrapped := retrytool.WithRetry(newQuoteTool(t, functiontool.Config{}, &runs, errUnavailable, errUnavailable),
+		retrytool.RetryPolicy{BaseBackoff: time.Hour})
+	ctx, cancel := context.WithTimeout(t.Context(), 10*time.Millisecond)
+	defer cancel()
+
+	_, err := wrapped.(toolinternal.FunctionTool).Run(newToolContext(t, ctx), map[string]any{"symbol": "GOOG"})
+	if !errors.Is(err, context.DeadlineExceeded) || !errors.Is(err, errUnavailable) {
+		t.Errorf("Run() error = %v, want the context error and the last error", err)
+	}
+	if got := runs.Load(); got != 1 {
+		t.Errorf("got %d runs, want 1", got)
+	}
+}
+
+func TestWithRetry_Hooks(t *testing.T) {
+	var runs, afterRuns atomic.Int32
+	cfg := functiontool.Config{
+		AfterRun: func(_ tool.Context, result any, err error) (any, error) {
+			afterRuns.Add(1)
+			if err != nil {
+				return nil, err
+			}
+			m := result.(map[string]any)
+			delete(m, "price")
+			return m, nil
+		},
+	}
+	wrapped := retrytool.WithRetry(newQuoteTool(t, cfg, &runs, errUnavailable),
+		retrytool.RetryPolicy{BaseBackoff: time.Millisecond})
+
+	got, err := wrapped.(toolinternal.FunctionTool).Run(newToolContext(t, t.Context()), map[string]any{"symbol": "GOOG"})
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if diff := cmp.Diff(map[string]any{"symbol": "GOOG"}, got); diff != "" {
+		t.Errorf("Run() mismatch (-want +got):\n%s", diff)
+	}
+	// The hook runs for each attempt, and sees the retryable error of the
+	// first one.
+	if got := afterRuns.Load(); got != 2 {
+		t.Errorf("got %d AfterRun calls, want 2", got)
+	}
+}
+
+func TestWithCircuitBreaker(t *testing.T) {
+	var runs atomic.Int32
+	wrapped := retrytool.WithCircuitBreaker(
+		newQuoteTool(t, functiontool.Config{}, &runs, errUnavailable, errUnavailable, &tool.ToolError{Code: "unknown_symbol", Message: "no such symbol"}, errUnavailable, errUnavailable),
+		retrytool.BreakerPolicy{FailureThreshold: 2, OpenDuration: 50 * time.Millisecond},
+	).(toolinternal.FunctionTool)
+	run := func() error {
+		t.Helper()
+		_, err := wrapped.Run(newToolContext(t, t.Context()), map[string]any{"symbol": "GOOG"})
+		return err
+	}
+	unavailable := func(err error) bool {
+		var toolErr *tool.ToolError
+		return errors.As(err, &toolErr) && toolErr.Code == retrytool.ErrCodeUnavailable
+	}
+
+	// Two failures open the circuit.
+	for range 2 {
+		if err := run(); !errors.Is(err, errUnavailable) {
+			t.Fatalf("Run() error = %v, want %v", err, errUnavailable)
+		}
+	}
+	if err := run(); !unavailable(err) {
+		t.Fatalf("Run() with an open circuit error = %v, want unavailable", err)
+	}
+	if got := runs.Load(); got != 2 {
+		t.Errorf("got %d runs, want 2: the open circuit does not run the tool", got)
+	}
+
+	// The trial call fails with a request error, which is not a failure of
+	// the service: the circuit closes.
+	time.Sleep(60 * time.Millisecond)
+	var toolErr *tool.ToolError
+	if err := run(); !errors.As(err, &toolErr) || toolErr.Code != "unknown_symbol" {
+		t.Fatalf("Run() of the trial error = %v, want the tool error", err)
+	}
+	if err := run(); !errors.Is(err, errUnavailable) {
+		t.Fatalf("Run() with a closed circuit error = %v, want %v", err, errUnavailable)
+	}
+
+	// Two more failures open the circuit again, and a successful trial call
+	// closes it.
+	if err := run(); !errors.Is(err, errUnavailable) {
+		t.Fatalf("Run() error = %v, want %v", err, errUnavailable)
+	}
+	if err := run(); !unavailable(err) {
+		t.Fatalf("Run() with an open circuit error = %v, want unavailable", err)
+	}
+	time.Sleep(60 * time.Millisecond)
+	if err := run(); err != nil {
+		t.Fatalf("Run() of the trial error = %v, want success", err)
+	}
+	if err := run(); err != nil {
+		t.Errorf("Run() with a closed circuit error = %v, want success", err)
+	}
+	if got := runs.Load(); got != 7 {
+		t.Errorf("got %d runs, want 7", got)
+	}
+}
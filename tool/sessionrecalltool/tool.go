@@ -0,0 +1,335 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package sessionrecalltool provides a tool that lets the model recall the
+// content of another session of the same user, by session ID.
+//
+// Unlike a semantic memory search, the tool retrieves a known conversation
+// explicitly. Called without a session ID, it lists the recent sessions of
+// the user so the model can pick one.
+//
+// # Authorization
+//
+// Sessions are always looked up with the app name and user ID of the current
+// invocation, so the model can only recall sessions owned by the current
+// user; the session ID alone grants no access. Sessions of other users are
+// reported as not found, without revealing whether they exist.
+//
+// # Large histories
+//
+// The session is rendered as a text transcript (see session.Export), without
+// function responses, which are usually verbose. If the transcript is longer
+// than Config.MaxTranscriptLength, it is summarized:
+//
+//   - with Config.Model set, the transcript is split into chunks of at most
+//     MaxTranscriptLength bytes on line boundaries, each chunk is summarized
+//     by the model, and the chunk summaries are merged by a final model call
+//     if there are several;
+//   - otherwise the transcript is truncated: the beginning and the end of the
+//     conversation are kept, and the lines in between are replaced by a
+//     marker.
+package sessionrecalltool
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/model"
+	"google.golang.org/adk/session"
+	"google.golang.org/adk/tool"
+	"google.golang.org/adk/tool/functiontool"
+)
+
+const (
+	defaultName                = "recall_session"
+	defaultMaxTranscriptLength = 8000
+	defaultMaxListedSessions   = 10
+
+	// MethodFull means the whole transcript is returned.
+	MethodFull = "full"
+	// MethodSummarized means the transcript was summarized by the model.
+	MethodSummarized = "summarized"
+	// MethodTruncated means the middle of the transcript was omitted.
+	MethodTruncated = "truncated"
+
+	summaryInstruction = "You summarize past conversations between a user and an assistant. " +
+		"List the key points: the user's goals, the facts and preferences they shared, " +
+		"the decisions made and the open questions. Be concise and factual."
+)
+
+// Config is the configuration of the session recall tool.
+type Config struct {
+	// Name of the tool as seen by the model. Defaults to "recall_session".
+	Name string
+	// SessionService holding the sessions. Required.
+	SessionService session.Service
+	// Model summarizing long sessions. Optional; long sessions are truncated
+	// without it.
+	Model model.LLM
+	// MaxTranscriptLength is the length in bytes above which a transcript is
+	// summarized. Defaults to 8000.
+	MaxTranscriptLength int
+	// MaxListedSessions is the number of recent sessions listed when no
+	// session ID is given. Defaults to 10.
+	MaxListedSessions int
+}
+
+// Args are the arguments the model provides.
+type Args struct {
+	SessionID string `json:"session_id,omitempty" jsonschema:"the ID of the session to recall; if empty the recent sessions are listed"`
+}
+
+// SessionInfo describes a session in a list.
+type SessionInfo struct {
+	SessionID      string `json:"session_id"`
+	LastUpdateTime string `json:"last_update_time"`
+}
+
+// Result is the response of the tool.
+type Result struct {
+	SessionID      string `json:"session_id,omitempty"`
+	LastUpdateTime string `json:"last_update_time,omitempty"`
+	// Method is MethodFull, MethodSummarized or MethodTruncated.
+	Method string `json:"method,omitempty"`
+	// Content is the transcript, or its summary.
+	Content string `json:"content,omitempty"`
+	// Sessions are the recent sessions, when no session ID was given.
+	Sessions []SessionInfo `json:"sessions,omitempty"`
+}
+
+// New creates a session recall tool.
+func New(cfg Config) (tool.Tool, error) {
+	if cfg.SessionService == nil {
+		return nil, errors.New("session service is required")
+	}
+	if cfg.Name == "" {
+		cfg.Name = defaultName
+	}
+	if cfg.MaxTranscriptLength <= 0 {
+		cfg.MaxTranscriptLength = defaultMaxTranscriptLength
+	}
+	if cfg.MaxListedSessions <= 0 {
+		cfg.MaxListedSessions = defaultMaxListedSessions
+	}
+
+	r := &recaller{cfg: cfg}
+	recallTool, err := functiontool.New(functiontool.Config{
+		Name: cfg.Name,
+		Description: "Recalls the key points of a previous conversation with the user, by session ID.\n" +
+			"Call it without a session ID to list the user's recent conversations.",
+	}, r.recall)
+	if err != nil {
+		return nil, fmt.Errorf("error creating session recall tool: %w", err)
+	}
+	return recallTool, nil
+}
+
+type recaller struct {
+	cfg Config
+}
+
+func (r *recaller) recall(ctx tool.Context, args Args) (Result, error) {
+	sessionID := strings.TrimSpace(args.SessionID)
+	if sessionID == "" {
+		return r.list(ctx)
+	}
+	if sessionID == ctx.SessionID() {
+		return Result{}, errors.New("this is the current session, its history is already in the conversation")
+	}
+
+	// The session is looked up for the current user only.
+	resp, err := r.cfg.SessionService.Get(ctx, &session.GetRequest{
+		AppName:   ctx.AppName(),
+		UserID:    ctx.UserID(),
+		SessionID: sessionID,
+	})
+	if err != nil || resp == nil || resp.Session == nil {
+		return Result{}, fmt.Errorf("session %q not found", sessionID)
+	}
+
+	transcript, err := session.Export(resp.Session, session.ExportFormatText, &session.ExportOptions{
+		Redact: func(_ *session.Event, part *genai.Part) *genai.Part {
+			if part.FunctionResponse != nil {
+				return nil
+			}
+			return part
+		},
+	})
+	if err != nil {
+		return Result{}, fmt.Errorf("failed to render session %q: %w", sessionID, err)
+	}
+
+	result := Result{
+		SessionID:      sessionID,
+		LastUpdateTime: resp.Session.LastUpdateTime().Format(time.RFC3339),
+		Method:         MethodFull,
+		Content:        string(transcript),
+	}
+	if len(transcript) <= r.cfg.MaxTranscriptLength {
+		return result, nil
+	}
+	if r.cfg.Model == nil {
+		result.Method = MethodTruncated
+		result.Content = truncate(string(transcript), r.cfg.MaxTranscriptLength)
+		return result, nil
+	}
+	summary, err := r.summarize(ctx, string(transcript))
+	if err != nil {
+		return Result{}, fmt.Errorf("failed to summarize session %q: %w", sessionID, err)
+	}
+	result.Method = MethodSummarized
+	result.Content = summary
+	return result, nil
+}
+
+func (r *recaller) list(ctx tool.Context) (Result, error) {
+	resp, err := r.cfg.SessionService.List(ctx, &session.ListRequest{
+		AppName: ctx.AppName(),
+		UserID:  ctx.UserID(),
+	})
+	if err != nil {
+		return Result{}, fmt.Errorf("failed to list sessions: %w", err)
+	}
+	sessions := slices.DeleteFunc(slices.Clone(resp.Sessions), func(s session.Session) bool {
+		return s.ID() == ctx.SessionID()
+	})
+	slices.SortFunc(sessions, func(a, b session.Session) int {
+		return b.LastUpdateTime().Compare(a.LastUpdateTime())
+	})
+	infos := make([]SessionInfo, 0, min(len(sessions), r.cfg.MaxListedSessions))
+	for _, s := range sessions[:min(len(sessions), r.cfg.MaxListedSessions)] {
+		infos = append(infos, SessionInfo{
+			SessionID:      s.ID(),
+			LastUpdateTime: s.LastUpdateTime().Format(time.RFC3339),
+		})
+	}
+	return Result{Sessions: infos}, nil
+}
+
+// summarize summarizes the transcript chunk by chunk, and merges the chunk
+// summaries.
+func (r *recaller) summarize(ctx tool.Context, transcript string) (string, error) {
+	chunks := splitLines(transcript, r.cfg.MaxTranscriptLength)
+	summaries := make([]string, 0, len(chunks))
+	for i, chunk := range chunks {
+		prompt := "Summarize this conversation:\n\n" + chunk
+		if len(chunks) > 1 {
+			prompt = fmt.Sprintf("Summarize part %d of %d of this conversation:\n\n%s", i+1, len(chunks), chunk)
+		}
+		summary, err := r.generate(ctx, prompt)
+		if err != nil {
+			return "", err
+		}
+		summaries = append(summaries, summary)
+	}
+	if len(summaries) == 1 {
+		return summaries[0], nil
+	}
+	return r.generate(ctx, "Merge these summaries of consecutive parts of a conversation into a single summary:\n\n"+
+		strings.Join(summaries, "\n\n"))
+}
+
+func (r *recaller) generate(ctx tool.Context, prompt string) (string, error) {
+	req := &model.LLMRequest{
+		Model:    r.cfg.Model.Name(),
+		Contents: []*genai.Content{genai.NewContentFromText(prompt, genai.RoleUser)},
+		Config: &genai.GenerateContentConfig{
+			SystemInstruction: genai.NewContentFromText(summaryInstruction, genai.RoleUser),
+		},
+	}
+	var b strings.Builder
+	for resp, err := range r.cfg.Model.GenerateContent(ctx, req, false) {
+		if err != nil {
+			return "", err
+		}
+		if resp.Content == nil {
+			continue
+		}
+		for _, p := range resp.Content.Parts {
+			if !p.Thought {
+				b.WriteString(p.Text)
+			}
+		}
+	}
+	if b.Len() == 0 {
+		return "", errors.New("the model returned an empty summary")
+	}
+	return strings.TrimSpace(b.String()), nil
+}
+
+// splitLines splits s into chunks of at most size bytes on line boundaries.
+// Lines longer than size are split on rune boundaries.
+func splitLines(s string, size int) []string {
+	var chunks []string
+	var b strings.Builder
+	for line := range strings.Lines(s) {
+		if b.Len()+len(line) > size && b.Len() > 0 {
+			chunks = append(chunks, b.String())
+			b.Reset()
+		}
+		for len(line) > size {
+			cut := size
+			for cut > 0 && !utf8.RuneStart(line[cut]) {
+				cut--
+			}
+			if cut == 0 {
+				_, cut = utf8.DecodeRuneInString(line)
+			}
+			chunks = append(chunks, line[:cut])
+			line = line[cut:]
+		}
+		b.WriteString(line)
+	}
+	if b.Len() > 0 {
+		chunks = append(chunks, b.String())
+	}
+	return chunks
+}
+
+// truncate keeps the first quarter and the last three quarters of the
+// budget of lines, replacing the lines in between by a marker.
+func truncate(s string, size int) string {
+	lines := slices.Collect(strings.Lines(s))
+	headBudget := size / 4
+	tailBudget := size - headBudget
+
+	var head, tail []string
+	n := 0
+	for _, line := range lines {
+		if n+len(line) > headBudget {
+			break
+		}
+		head = append(head, line)
+		n += len(line)
+	}
+	n = 0
+	for i := len(lines) - 1; i >= len(head); i-- {
+		if n+len(lines[i]) > tailBudget {
+			break
+		}
+		tail = append(tail, lines[i])
+		n += len(lines[i])
+	}
+	slices.Reverse(tail)
+	omitted := len(lines) - len(head) - len(tail)
+	return strings.Join(head, "") +
+		fmt.Sprintf("[... %d lines omitted ...]\n", omitted) +
+		strings.Join(tail, "")
+}
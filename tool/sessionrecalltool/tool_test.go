@@ -0,0 +1,203 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sessionrecalltool_test
+
+import (
+	"context"
+	"fmt"
+	"iter"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/agent"
+	icontext "google.golang.org/adk/internal/context"
+	"google.golang.org/adk/internal/sessioninternal"
+	"google.golang.org/adk/internal/toolinternal"
+	"google.golang.org/adk/model"
+	"google.golang.org/adk/session"
+	"google.golang.org/adk/tool"
+	"google.golang.org/adk/tool/sessionrecalltool"
+)
+
+type fakeLLM struct {
+	prompts []string
+}
+
+func (m *fakeLLM) Name() string { return "fake" }
+
+func (m *fakeLLM) GenerateContent(ctx context.Context, req *model.LLMRequest, stream bool) iter.Seq2[*model.LLMResponse, error] {
+	return func(yield func(*model.LLMResponse, error) bool) {
+		m.prompts = append(m.prompts, req.Contents[0].Parts[0].Text)
+		yield(&model.LLMResponse{Content: genai.NewContentFromText(fmt.Sprintf("summary %d", len(m.prompts)), genai.RoleModel)}, nil)
+	}
+}
+
+// createSession creates a session of the user with n exchanges.
+func createSession(t *testing.T, service session.Service, userID, sessionID string, n int) session.Session {
+	t.Helper()
+	resp, err := service.Create(t.Context(), &session.CreateRequest{AppName: "app", UserID: userID, SessionID: sessionID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := range n {
+		for _, ev := range []*session.Event{
+			{Author: "user", LLMResponse: model.LLMResponse{Content: genai.NewContentFromText(fmt.Sprintf("question %d", i), genai.RoleUser)}},
+			{Author: "assistant", LLMResponse: model.LLMResponse{Content: genai.NewContentFromText(fmt.Sprintf("answer %d", i), genai.RoleModel)}},
+		} {
+			ev.ID = fmt.Sprintf("%s-%d-%s", sessionID, i, ev.Author)
+			if err := service.AppendEvent(t.Context(), resp.Session, ev); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	return resp.Session
+}
+
+func newToolContext(t *testing.T, service session.Service, s session.Session) tool.Context {
+	t.Helper()
+	a, err := agent.New(agent.Config{Name: "assistant"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := icontext.NewInvocationContext(t.Context(), icontext.InvocationContextParams{
+		Agent:   a,
+		Session: sessioninternal.NewMutableSession(service, s),
+	})
+	return toolinternal.NewToolContext(ctx, "", nil)
+}
+
+func TestRecallSession(t *testing.T) {
+	service := session.InMemoryService()
+	current := createSession(t, service, "alice", "current", 0)
+	createSession(t, service, "alice", "short", 2)
+	createSession(t, service, "alice", "long", 100)
+	createSession(t, service, "bob", "secret", 2)
+	ctx := newToolContext(t, service, current)
+
+	llm := &fakeLLM{}
+	recallTool, err := sessionrecalltool.New(sessionrecalltool.Config{SessionService: service, MaxTranscriptLength: 500})
+	if err != nil {
+		t.Fatal(err)
+	}
+	summarizingTool, err := sessionrecalltool.New(sessionrecalltool.Config{SessionService: service, Model: llm, MaxTranscriptLength: 500})
+	if err != nil {
+		t.Fatal(err)
+	}
+	run := func(tl tool.Tool, args map[string]any) (map[string]any, error) {
+		return tl.(toolinternal.FunctionTool).Run(ctx, args)
+	}
+
+	t.Run("full", func(t *testing.T) {
+		got, err := run(recallTool, map[string]any{"session_id": "short"})
+		if err != nil {
+			t.Fatalf("Run() error = %v", err)
+		}
+		want := "[user] question 0\n[assistant] answer 0\n[user] question 1\n[assistant] answer 1"
+		if got["method"] != sessionrecalltool.MethodFull || !strings.Contains(got["content"].(string), want) {
+			t.Errorf("Run() = %v, want the full transcript %q", got, want)
+		}
+	})
+
+	t.Run("other user", func(t *testing.T) {
+		if got, err := run(recallTool, map[string]any{"session_id": "secret"}); err == nil {
+			t.Errorf("Run() for a session of another user = %v, want error", got)
+		}
+	})
+
+	t.Run("current", func(t *testing.T) {
+		if _, err := run(recallTool, map[string]any{"session_id": "current"}); err == nil {
+			t.Error("Run() for the current session succeeded, want error")
+		}
+	})
+
+	t.Run("list", func(t *testing.T) {
+		got, err := run(recallTool, map[string]any{})
+		if err != nil {
+			t.Fatalf("Run() error = %v", err)
+		}
+		var ids []string
+		for _, s := range got["sessions"].([]any) {
+			ids = append(ids, s.(map[string]any)["session_id"].(string))
+		}
+		if len(ids) != 2 || strings.Contains(strings.Join(ids, ","), "current") || strings.Contains(strings.Join(ids, ","), "secret") {
+			t.Errorf("listed sessions = %v, want short and long", ids)
+		}
+	})
+
+	t.Run("truncated", func(t *testing.T) {
+		got, err := run(recallTool, map[string]any{"session_id": "long"})
+		if err != nil {
+			t.Fatalf("Run() error = %v", err)
+		}
+		content := got["content"].(string)
+		if got["method"] != sessionrecalltool.MethodTruncated || len(content) > 550 {
+			t.Errorf("Run() = %v (%d bytes), want a truncated transcript", got["method"], len(content))
+		}
+		for _, want := range []string{"question 0", "lines omitted", "answer 99"} {
+			if !strings.Contains(content, want) {
+				t.Errorf("truncated transcript %q does not contain %q", content, want)
+			}
+		}
+	})
+
+	t.Run("summarized", func(t *testing.T) {
+		got, err := run(summarizingTool, map[string]any{"session_id": "long"})
+		if err != nil {
+			t.Fatalf("Run() error = %v", err)
+		}
+		if got["method"] != sessionrecalltool.MethodSummarized {
+			t.Errorf("method = %v, want summarized", got["method"])
+		}
+		// The chunks are summarized, then the summaries merged.
+		if len(llm.prompts) < 3 || !strings.HasPrefix(llm.prompts[len(llm.prompts)-1], "Merge these summaries") {
+			t.Errorf("prompts = %d, want chunk summaries followed by a merge", len(llm.prompts))
+		}
+		if want := fmt.Sprintf("summary %d", len(llm.prompts)); got["content"] != want {
+			t.Errorf("content = %v, want %q", got["content"], want)
+		}
+	})
+
+	t.Run("summarized non-ASCII", func(t *testing.T) {
+		resp, err := service.Create(t.Context(), &session.CreateRequest{AppName: "app", UserID: "alice", SessionID: "accents"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		// A single line of 2-byte runes longer than the chunk size.
+		text := strings.Repeat("é", 401)
+		ev := &session.Event{ID: "accents-0", Author: "user", LLMResponse: model.LLMResponse{Content: genai.NewContentFromText(text, genai.RoleUser)}}
+		if err := service.AppendEvent(t.Context(), resp.Session, ev); err != nil {
+			t.Fatal(err)
+		}
+		llm.prompts = nil
+		if _, err := run(summarizingTool, map[string]any{"session_id": "accents"}); err != nil {
+			t.Fatalf("Run() error = %v", err)
+		}
+		var chunks string
+		for _, prompt := range llm.prompts {
+			if !utf8.ValidString(prompt) {
+				t.Errorf("prompt %q is not valid UTF-8", prompt)
+			}
+			if _, chunk, ok := strings.Cut(prompt, ":\n\n"); ok && !strings.HasPrefix(prompt, "Merge") {
+				chunks += chunk
+			}
+		}
+		if n := strings.Count(chunks, "é"); n != 401 {
+			t.Errorf("chunks have %d runes of the text, want 401", n)
+		}
+	})
+}
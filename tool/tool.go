@@ -20,9 +20,13 @@ package tool
 import (
 	"context"
 
+	"google.golang.org/genai"
+
 	"google.golang.org/adk/agent"
+	"google.golang.org/adk/auth"
 	"google.golang.org/adk/memory"
 	"google.golang.org/adk/session"
+	"google.golang.org/adk/tool/toolconfirmation"
 )
 
 // Tool defines the interface for a callable tool.
@@ -44,6 +48,20 @@ type Context interface {
 	// FunctionCallID returns the unique identifier of the function call
 	// that triggered this tool execution.
 	FunctionCallID() string
+	// FunctionCall returns the function call that triggered this tool
+	// execution, with its ID and its arguments as sent by the model, before
+	// their conversion, or nil if the tool was not called by the model. It
+	// must not be modified.
+	FunctionCall() *genai.FunctionCall
+	// CorrelationID returns the correlation ID of the current invocation.
+	// See agent.WithCorrelationID.
+	CorrelationID() string
+	// State returns the session state. The changes made with Set are staged
+	// in Actions().StateDelta, and read back by Get and All: they are
+	// committed to the session with the event of the function response, and
+	// visible to the next tools of the invocation, if the call succeeds, and
+	// discarded if it fails.
+	State() session.State
 
 	// Actions returns the EventActions for the current event. This can be
 	// used by the tool to modify the agent's state, transfer to another
@@ -51,6 +69,27 @@ type Context interface {
 	Actions() *session.EventActions
 	// SearchMemory performs a semantic search on the agent's memory.
 	SearchMemory(context.Context, string) (*memory.SearchResponse, error)
+
+	// RequestCredential requests the credential from the user. Once the call
+	// returned, the agent asks the client for the credential and the
+	// invocation ends; the function call is run again when the client sends
+	// the credential. See package auth.
+	RequestCredential(*auth.AuthConfig)
+	// GetAuthResponse returns the credential obtained by the client for the
+	// config, or nil if there is none yet. Credentials are kept in the
+	// session state, under AuthConfig.Key, so that they are available to the
+	// next calls.
+	GetAuthResponse(*auth.AuthConfig) *auth.AuthCredential
+
+	// RequestConfirmation requests the confirmation of the call from the
+	// user, with a hint explaining what is confirmed and an optional payload.
+	// Once the call returned, the agent asks the client for the confirmation
+	// and the invocation ends; the function call is run again when the
+	// client sends it. See package toolconfirmation.
+	RequestConfirmation(hint string, payload any)
+	// ToolConfirmation returns the confirmation sent by the client for the
+	// call, or nil if the call was not confirmed nor declined.
+	ToolConfirmation() *toolconfirmation.ToolConfirmation
 }
 
 // Toolset is an interface for a collection of tools. It allows grouping
@@ -64,7 +103,26 @@ type Toolset interface {
 	Tools(ctx agent.ReadonlyContext) ([]Tool, error)
 }
 
+// ConflictPolicy is how an agent handles its tools sharing a name, e.g. the
+// tools named "search" of two toolsets. See llmagent.Config.ToolConflicts.
+type ConflictPolicy int
+
+const (
+	// ConflictError fails the requests to the model when tools share a name.
+	// It is the default.
+	ConflictError ConflictPolicy = iota
+	// ConflictRename renames the function tools whose name is taken by a
+	// previous tool: a tool of a toolset to "<toolset name>_<name>", if free,
+	// and to "<name>_<n>" otherwise, with n the first free number from 2.
+	ConflictRename
+	// ConflictPreferFirst drops the tools whose name is taken by a previous
+	// tool.
+	ConflictPreferFirst
+)
+
 // Predicate is a function which decides whether a tool should be exposed to LLM.
+// It is evaluated on each LLM request, e.g. by toolset.Filter, so the tools
+// can depend on the user or the session state.
 type Predicate func(ctx agent.ReadonlyContext, tool Tool) bool
 
 // StringPredicate is a helper that creates a Predicate from a string slice.
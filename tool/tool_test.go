@@ -15,8 +15,11 @@
 package tool_test
 
 import (
+	"encoding/json"
 	"testing"
 
+	"github.com/google/go-cmp/cmp"
+
 	"google.golang.org/adk/internal/toolinternal"
 	"google.golang.org/adk/tool"
 	"google.golang.org/adk/tool/agenttool"
@@ -103,3 +106,109 @@ func TestTypes(t *testing.T) {
 		})
 	}
 }
+
+func TestDeclarations(t *testing.T) {
+	type args struct {
+		City string `json:"city"`
+	}
+	newTool := func(name string) tool.Tool {
+		ft, err := functiontool.New(functiontool.Config{Name: name, Description: name + " tool"}, func(tool.Context, args) (map[string]any, error) {
+			return nil, nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return ft
+	}
+	tools := []tool.Tool{newTool("weather"), geminitool.GoogleSearch{}, newTool("time"), newTool("weather")}
+
+	var names []string
+	for _, decl := range tool.Declarations(tools) {
+		names = append(names, decl.Name)
+		if decl.ParametersJsonSchema == nil {
+			t.Errorf("Declarations() %q has no parameters schema", decl.Name)
+		}
+	}
+	if diff := cmp.Diff([]string{"weather", "time", "weather"}, names); diff != "" {
+		t.Errorf("Declarations() names mismatch (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff([]string{"weather"}, tool.DuplicateNames(tools)); diff != "" {
+		t.Errorf("DuplicateNames() mismatch (-want +got):\n%s", diff)
+	}
+	if got := tool.DuplicateNames(tools[:3]); len(got) != 0 {
+		t.Errorf("DuplicateNames() = %v, want none", got)
+	}
+}
+
+func TestCanonicalJSON(t *testing.T) {
+	type point struct {
+		Y float64 `json:"y"`
+		X int     `json:"x"`
+	}
+	tests := []struct {
+		name  string
+		input any
+		want  string
+	}{
+		{
+			name:  "sorted keys",
+			input: map[string]any{"b": 1, "a": map[string]any{"d": true, "c": nil}},
+			want:  `{"a":{"c":null,"d":true},"b":1}`,
+		},
+		{
+			name:  "struct fields sorted",
+			input: point{Y: 2, X: 1},
+			want:  `{"x":1,"y":2}`,
+		},
+		{
+			name:  "numbers",
+			input: []any{3.0, int64(3), json.Number("3.50"), json.Number("1e2"), 0.1, 1e21, json.Number("-0.0"), json.Number("12345678901234567890")},
+			want:  `[3,3,3.5,100,0.1,1e+21,0,12345678901234567890]`,
+		},
+		{
+			name:  "no HTML escaping",
+			input: map[string]any{"html": "<b>&</b>"},
+			want:  `{"html":"<b>&</b>"}`,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := tool.CanonicalJSON(tc.input)
+			if err != nil {
+				t.Fatalf("CanonicalJSON() error = %v", err)
+			}
+			if string(got) != tc.want {
+				t.Errorf("CanonicalJSON() = %s, want %s", got, tc.want)
+			}
+		})
+	}
+
+	if _, err := tool.CanonicalJSON(map[string]any{"f": func() {}}); err == nil {
+		t.Error("CanonicalJSON() of a function succeeded, want error")
+	}
+}
+
+func TestChain(t *testing.T) {
+	var calls []string
+	trace := func(name string) tool.Interceptor {
+		return func(next tool.Handler) tool.Handler {
+			return func(ctx tool.Context, tl tool.Tool, args map[string]any) (map[string]any, error) {
+				calls = append(calls, name+" before")
+				result, err := next(ctx, tl, args)
+				calls = append(calls, name+" after")
+				return result, err
+			}
+		}
+	}
+	handler := tool.Chain(trace("outer"), trace("inner"))(func(tool.Context, tool.Tool, map[string]any) (map[string]any, error) {
+		calls = append(calls, "run")
+		return map[string]any{"ok": true}, nil
+	})
+	if _, err := handler(nil, nil, nil); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"outer before", "inner before", "run", "inner after", "outer after"}
+	if diff := cmp.Diff(want, calls); diff != "" {
+		t.Errorf("calls mismatch (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,64 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package toolconfirmation defines the confirmations of the tool calls by the
+// user, e.g. before sending an email or deleting a file.
+//
+// A tool requests a confirmation with tool.Context.RequestConfirmation, or
+// with functiontool.Config.RequireConfirmation for all its calls; any tool
+// can be wrapped with confirmtool.WithConfirmation to confirm the calls chosen
+// by a policy. Once the
+// call returned, the agent emits an event with a long-running function call
+// named FunctionCallName, whose arguments are a Request carrying the original
+// function call, so that the client can show its tool name and arguments.
+// The invocation ends there. The client asks the user, and sends a new
+// message with the function response of that call: a ToolConfirmation with
+// Confirmed set, and optionally a Payload. The agent then runs the original
+// function call again, in which the tool gets the answer with
+// tool.Context.ToolConfirmation. The function tools requiring a confirmation
+// run their handler if the call was confirmed, and fail with ErrDeclined
+// otherwise.
+package toolconfirmation
+
+import (
+	"errors"
+
+	"google.golang.org/genai"
+)
+
+// FunctionCallName is the name of the function call of the events requesting
+// a confirmation.
+const FunctionCallName = "adk_request_confirmation"
+
+// ErrDeclined is returned by the calls of the function tools requiring a
+// confirmation that the user declined.
+var ErrDeclined = errors.New("the user declined the tool call")
+
+// ToolConfirmation is the confirmation of a tool call.
+type ToolConfirmation struct {
+	// Hint explains to the user what is confirmed.
+	Hint string `json:"hint,omitempty"`
+	// Confirmed is set by the client if the user approved the call.
+	Confirmed bool `json:"confirmed"`
+	// Payload is optional data of the confirmation, e.g. requested by the
+	// tool and filled by the user.
+	Payload any `json:"payload,omitempty"`
+}
+
+// Request is the arguments of the function calls requesting a confirmation.
+type Request struct {
+	// OriginalFunctionCall is the function call to confirm.
+	OriginalFunctionCall *genai.FunctionCall `json:"original_function_call"`
+	ToolConfirmation     *ToolConfirmation   `json:"tool_confirmation"`
+}
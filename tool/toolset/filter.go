@@ -0,0 +1,85 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package toolset
+
+import (
+	"google.golang.org/adk/agent"
+	"google.golang.org/adk/tool"
+)
+
+// Filter returns a toolset with the tools of ts for which the predicate
+// returns true. The predicate is evaluated on each LLM request, with the
+// context of the invocation, so the tools can depend on the user, the
+// session state or the agent, e.g.
+//
+//	admin := func(ctx agent.ReadonlyContext, t tool.Tool) bool {
+//		isAdmin, _ := ctx.ReadonlyState().Get("user:is_admin")
+//		return isAdmin == true || !strings.HasPrefix(t.Name(), "admin_")
+//	}
+//	llmagent.New(llmagent.Config{
+//		...
+//		Toolsets: []tool.Toolset{toolset.Filter(toolset.New("ops", tools...), admin)},
+//	})
+//
+// A nil predicate keeps all the tools.
+func Filter(ts tool.Toolset, predicate tool.Predicate) tool.Toolset {
+	return &filtered{Toolset: ts, predicate: predicate}
+}
+
+// All returns a predicate true for the tools for which all the predicates
+// are true.
+func All(predicates ...tool.Predicate) tool.Predicate {
+	return func(ctx agent.ReadonlyContext, t tool.Tool) bool {
+		for _, p := range predicates {
+			if !p(ctx, t) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// Any returns a predicate true for the tools for which any of the predicates
+// is true.
+func Any(predicates ...tool.Predicate) tool.Predicate {
+	return func(ctx agent.ReadonlyContext, t tool.Tool) bool {
+		for _, p := range predicates {
+			if p(ctx, t) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+type filtered struct {
+	tool.Toolset
+	predicate tool.Predicate
+}
+
+// Tools implements tool.Toolset.
+func (f *filtered) Tools(ctx agent.ReadonlyContext) ([]tool.Tool, error) {
+	tools, err := f.Toolset.Tools(ctx)
+	if err != nil || f.predicate == nil {
+		return tools, err
+	}
+	var kept []tool.Tool
+	for _, t := range tools {
+		if f.predicate(ctx, t) {
+			kept = append(kept, t)
+		}
+	}
+	return kept, nil
+}
@@ -0,0 +1,76 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package toolset
+
+import (
+	"errors"
+	"fmt"
+
+	"google.golang.org/adk/agent"
+	"google.golang.org/adk/internal/toolinternal"
+	"google.golang.org/adk/tool"
+)
+
+// Prefix returns a toolset with the tools of ts namespaced with the prefix:
+// each function tool named "<name>" is named "<prefix>_<name>", so that
+// toolsets with tools of the same name can be given to an agent, e.g.
+//
+//	llmagent.New(llmagent.Config{
+//		...
+//		Toolsets: []tool.Toolset{
+//			toolset.Prefix(webTools, "web"),   // web_search
+//			toolset.Prefix(docsTools, "docs"), // docs_search
+//		},
+//	})
+//
+// The tools which are not function tools, e.g. the built-in tools of the
+// model, keep their name. See also llmagent.Config.ToolConflicts.
+func Prefix(ts tool.Toolset, prefix string) tool.Toolset {
+	return &prefixed{Toolset: ts, prefix: prefix}
+}
+
+// Rename returns the function tool t under the name: the model calls it with
+// the name. It fails if t is not a function tool.
+func Rename(t tool.Tool, name string) (tool.Tool, error) {
+	if name == "" {
+		return nil, errors.New("tool name is required")
+	}
+	funcTool, ok := t.(toolinternal.FunctionTool)
+	if !ok {
+		return nil, fmt.Errorf("tool %q is not a function tool", t.Name())
+	}
+	return toolinternal.Rename(funcTool, name, ""), nil
+}
+
+type prefixed struct {
+	tool.Toolset
+	prefix string
+}
+
+// Tools implements tool.Toolset.
+func (p *prefixed) Tools(ctx agent.ReadonlyContext) ([]tool.Tool, error) {
+	tools, err := p.Toolset.Tools(ctx)
+	if err != nil || p.prefix == "" {
+		return tools, err
+	}
+	prefixed := make([]tool.Tool, 0, len(tools))
+	for _, t := range tools {
+		if funcTool, ok := t.(toolinternal.FunctionTool); ok {
+			t = toolinternal.Rename(funcTool, p.prefix+"_"+t.Name(), "")
+		}
+		prefixed = append(prefixed, t)
+	}
+	return prefixed, nil
+}
@@ -0,0 +1,134 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package toolset provides a curated set of tools, passed to an agent as one
+// tool.Toolset.
+//
+// Example:
+//
+//	tools := toolset.New("billing", invoiceTool, refundTool)
+//	tools.Add(exportTool)
+//	llmagent.New(llmagent.Config{
+//		...
+//		Toolsets: []tool.Toolset{tools},
+//	})
+//
+// Prefix and Rename namespace the tools, so that toolsets with tools of the
+// same name, e.g. "search", can be given to one agent.
+package toolset
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+
+	"google.golang.org/adk/agent"
+	"google.golang.org/adk/internal/toolinternal"
+	"google.golang.org/adk/model"
+	"google.golang.org/adk/tool"
+)
+
+// Set is a mutable, ordered set of tools. It is safe for concurrent use.
+type Set struct {
+	name string
+
+	mu    sync.Mutex
+	tools []tool.Tool
+}
+
+// New returns a set with the name and the tools, in order.
+func New(name string, tools ...tool.Tool) *Set {
+	s := &Set{name: name}
+	s.Add(tools...)
+	return s
+}
+
+// Name implements tool.Toolset.
+func (s *Set) Name() string {
+	return s.name
+}
+
+// Add appends the tools to the set. Tools with the same name as a tool of
+// the set are added too: the collision is reported when the tools are
+// registered, see ProcessRequest.
+func (s *Set) Add(tools ...tool.Tool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.tools = append(s.tools, tools...)
+}
+
+// Remove removes the tools with the name from the set. It reports whether a
+// tool was removed.
+func (s *Set) Remove(name string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	n := len(s.tools)
+	s.tools = slices.DeleteFunc(s.tools, func(t tool.Tool) bool { return t.Name() == name })
+	return len(s.tools) != n
+}
+
+// All returns the tools of the set, in order.
+func (s *Set) All() []tool.Tool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return slices.Clone(s.tools)
+}
+
+// Tools implements tool.Toolset. It returns the tools of the set, in order.
+func (s *Set) Tools(agent.ReadonlyContext) ([]tool.Tool, error) {
+	return s.All(), nil
+}
+
+// ProcessRequest registers the tools of the set in the request, in order,
+// as the agents do with their tools.
+//
+// The names of the tools are checked before the request is modified: if
+// tools of the set share a name, or have the name of a tool already in
+// req.Tools, it returns an error listing all the collisions and leaves the
+// request unchanged.
+func (s *Set) ProcessRequest(ctx tool.Context, req *model.LLMRequest) error {
+	tools := s.All()
+
+	var errs []error
+	seen := make(map[string]bool, len(tools))
+	for _, t := range tools {
+		name := t.Name()
+		switch {
+		case seen[name]:
+			errs = append(errs, fmt.Errorf("tool %q is in the set %q more than once", name, s.name))
+		case req.Tools[name] != nil:
+			errs = append(errs, fmt.Errorf("tool %q of the set %q is already registered", name, s.name))
+		}
+		seen[name] = true
+		if _, ok := t.(toolinternal.RequestProcessor); !ok {
+			errs = append(errs, fmt.Errorf("tool %q does not implement RequestProcessor() method", name))
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("failed to register the tools of the set %q: %w", s.name, errors.Join(errs...))
+	}
+
+	for _, t := range tools {
+		if err := t.(toolinternal.RequestProcessor).ProcessRequest(ctx, req); err != nil {
+			return fmt.Errorf("failed to register tool %q of the set %q: %w", t.Name(), s.name, err)
+		}
+	}
+	return nil
+}
+
+var (
+	_ tool.Toolset                  = (*Set)(nil)
+	_ toolinternal.RequestProcessor = (*Set)(nil)
+)
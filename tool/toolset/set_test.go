@@ -0,0 +1,196 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package toolset_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"google.golang.org/adk/agent"
+	icontext "google.golang.org/adk/internal/context"
+	"google.golang.org/adk/internal/toolinternal"
+	"google.golang.org/adk/model"
+	"google.golang.org/adk/session"
+	"google.golang.org/adk/tool"
+	"google.golang.org/adk/tool/functiontool"
+	"google.golang.org/adk/tool/toolset"
+)
+
+func newTool(t *testing.T, name string) tool.Tool {
+	t.Helper()
+	type Args struct{}
+	tl, err := functiontool.New(functiontool.Config{Name: name, Description: name}, func(tool.Context, Args) (string, error) {
+		return name, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tl
+}
+
+func names(tools []tool.Tool) []string {
+	var names []string
+	for _, t := range tools {
+		names = append(names, t.Name())
+	}
+	return names
+}
+
+func TestSet(t *testing.T) {
+	set := toolset.New("billing", newTool(t, "invoice"), newTool(t, "refund"))
+	set.Add(newTool(t, "export"))
+	if !set.Remove("refund") {
+		t.Error("Remove(refund) = false, want true")
+	}
+	if set.Remove("missing") {
+		t.Error("Remove(missing) = true, want false")
+	}
+	tools, err := set.Tools(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff([]string{"invoice", "export"}, names(tools)); diff != "" {
+		t.Errorf("Tools() mismatch (-want +got):\n%s", diff)
+	}
+
+	req := &model.LLMRequest{}
+	if err := set.ProcessRequest(nil, req); err != nil {
+		t.Fatalf("ProcessRequest() error = %v", err)
+	}
+	var declared []string
+	for _, decl := range req.Config.Tools[0].FunctionDeclarations {
+		declared = append(declared, decl.Name)
+	}
+	if diff := cmp.Diff([]string{"invoice", "export"}, declared); diff != "" {
+		t.Errorf("declarations mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestSet_ProcessRequest_Collisions(t *testing.T) {
+	set := toolset.New("billing", newTool(t, "invoice"), newTool(t, "refund"), newTool(t, "invoice"), newTool(t, "search"))
+	req := &model.LLMRequest{Tools: map[string]any{"search": newTool(t, "search")}}
+
+	err := set.ProcessRequest(nil, req)
+	if err == nil {
+		t.Fatal("ProcessRequest() succeeded, want error")
+	}
+	for _, want := range []string{`tool "invoice" is in the set "billing" more than once`, `tool "search" of the set "billing" is already registered`} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("ProcessRequest() error = %v, want it to report %q", err, want)
+		}
+	}
+	// The request is left unchanged.
+	if len(req.Tools) != 1 || req.Config != nil {
+		t.Errorf("ProcessRequest() modified the request: tools %v, config %v", req.Tools, req.Config)
+	}
+}
+
+func TestFilter(t *testing.T) {
+	service := session.InMemoryService()
+	readonlyContext := func(state map[string]any) agent.ReadonlyContext {
+		t.Helper()
+		resp, err := service.Create(t.Context(), &session.CreateRequest{AppName: "app", UserID: "user", State: state})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return icontext.NewReadonlyContext(icontext.NewInvocationContext(t.Context(), icontext.InvocationContextParams{Session: resp.Session}))
+	}
+	admin := func(ctx agent.ReadonlyContext, t tool.Tool) bool {
+		isAdmin, _ := ctx.ReadonlyState().Get("is_admin")
+		return isAdmin == true || !strings.HasPrefix(t.Name(), "admin_")
+	}
+	set := toolset.New("ops", newTool(t, "search"), newTool(t, "admin_reset"), newTool(t, "admin_export"))
+
+	for _, tc := range []struct {
+		name      string
+		predicate tool.Predicate
+		state     map[string]any
+		want      []string
+	}{
+		{name: "user", predicate: admin, state: map[string]any{}, want: []string{"search"}},
+		{name: "admin", predicate: admin, state: map[string]any{"is_admin": true}, want: []string{"search", "admin_reset", "admin_export"}},
+		{name: "all", predicate: toolset.All(admin, tool.StringPredicate([]string{"admin_reset", "admin_export"})), state: map[string]any{"is_admin": true}, want: []string{"admin_reset", "admin_export"}},
+		{name: "any", predicate: toolset.Any(admin, tool.StringPredicate([]string{"admin_export"})), state: map[string]any{}, want: []string{"search", "admin_export"}},
+		{name: "nil predicate", state: map[string]any{}, want: []string{"search", "admin_reset", "admin_export"}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			filtered := toolset.Filter(set, tc.predicate)
+			if filtered.Name() != "ops" {
+				t.Errorf("Name() = %q, want the name of the toolset", filtered.Name())
+			}
+			tools, err := filtered.Tools(readonlyContext(tc.state))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tc.want, names(tools)); diff != "" {
+				t.Errorf("Tools() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestPrefix(t *testing.T) {
+	search := newTool(t, "search")
+	renamed, err := toolset.Rename(newTool(t, "lookup"), "docs_lookup")
+	if err != nil {
+		t.Fatal(err)
+	}
+	prefixed := toolset.Prefix(toolset.New("docs", search, renamed), "kb")
+	if prefixed.Name() != "docs" {
+		t.Errorf("Name() = %q, want the name of the toolset", prefixed.Name())
+	}
+	tools, err := prefixed.Tools(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff([]string{"kb_search", "kb_docs_lookup"}, names(tools)); diff != "" {
+		t.Errorf("Tools() mismatch (-want +got):\n%s", diff)
+	}
+
+	// The renamed tools register under their name, next to a tool with
+	// their original name.
+	req := &model.LLMRequest{}
+	for _, tl := range append([]tool.Tool{search}, tools...) {
+		if err := tl.(toolinternal.RequestProcessor).ProcessRequest(nil, req); err != nil {
+			t.Fatalf("ProcessRequest(%q) error = %v", tl.Name(), err)
+		}
+	}
+	var declared []string
+	for _, decl := range req.Config.Tools[0].FunctionDeclarations {
+		declared = append(declared, decl.Name)
+	}
+	if diff := cmp.Diff([]string{"search", "kb_search", "kb_docs_lookup"}, declared); diff != "" {
+		t.Errorf("declarations mismatch (-want +got):\n%s", diff)
+	}
+	if req.Tools["kb_search"] != tools[0] || req.Tools["search"] != search {
+		t.Errorf("registered tools = %v, want the renamed tools under their name", req.Tools)
+	}
+	if search.(toolinternal.FunctionTool).Declaration().Name != "search" {
+		t.Error("the declaration of the wrapped tool was renamed")
+	}
+	result, err := tools[1].(toolinternal.FunctionTool).Run(nil, map[string]any{})
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if diff := cmp.Diff(map[string]any{"result": "lookup"}, result); diff != "" {
+		t.Errorf("Run() mismatch (-want +got):\n%s", diff)
+	}
+
+	if _, err := toolset.Rename(newTool(t, "search"), ""); err == nil {
+		t.Error("Rename() with an empty name succeeded, want error")
+	}
+}
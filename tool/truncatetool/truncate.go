@@ -0,0 +1,162 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package truncatetool wraps function tools to truncate their oversized
+// results, keeping the full results available to the model as artifacts.
+//
+// # Truncated results
+//
+// The size of a result is the number of characters of its JSON encoding.
+// When it exceeds Config.MaxChars, the full JSON encoding is saved as a text
+// artifact, and the model receives instead:
+//
+//	{
+//	  "result": "<the first MaxChars characters of the JSON encoding>",
+//	  "remaining_chars": 1200,
+//	  "artifact": "tool_result_search_3f2a.json",
+//	  "note": "truncated; 1200 more chars available; call load_artifacts with artifact_names [\"tool_result_search_3f2a.json\"]"
+//	}
+//
+// The agent needs the load_artifacts tool, see package loadartifactstool, to
+// load the full result. If the artifact can't be saved, e.g. because the
+// runner has no artifact service, the artifact field is omitted and the note
+// reads "truncated; 1200 more chars not available".
+//
+// # Artifact names
+//
+// The artifact of a truncated result is named after the tool and the
+// function call, see [ArtifactName], so that the results of distinct calls
+// don't overwrite each other.
+package truncatetool
+
+import (
+	"encoding/json"
+	"fmt"
+	"unicode/utf8"
+
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/internal/toolinternal"
+	"google.golang.org/adk/model"
+	"google.golang.org/adk/tool"
+)
+
+const (
+	defaultMaxChars     = 4000
+	defaultLoadToolName = "load_artifacts"
+)
+
+// Config is the configuration of the truncation.
+type Config struct {
+	// MaxChars is the maximum number of characters of the JSON encoding of
+	// a result. Defaults to 4000.
+	MaxChars int
+	// LoadToolName is the name of the tool loading artifacts mentioned in
+	// the note. Defaults to "load_artifacts".
+	LoadToolName string
+}
+
+// Wrap returns a tool behaving like the function tool t, except that its
+// results larger than cfg.MaxChars are truncated. Errors are not truncated.
+func Wrap(t tool.Tool, cfg Config) (tool.Tool, error) {
+	funcTool, ok := t.(toolinternal.FunctionTool)
+	if !ok {
+		return nil, fmt.Errorf("tool %q is not a function tool", t.Name())
+	}
+	if cfg.MaxChars <= 0 {
+		cfg.MaxChars = defaultMaxChars
+	}
+	if cfg.LoadToolName == "" {
+		cfg.LoadToolName = defaultLoadToolName
+	}
+	return &truncatingTool{FunctionTool: funcTool, cfg: cfg}, nil
+}
+
+// ArtifactName returns the name of the artifact holding the full result of
+// the function call of the tool: "tool_result_<tool name>_<call ID>.json", or
+// "tool_result_<tool name>.json" without call ID.
+func ArtifactName(toolName, functionCallID string) string {
+	if functionCallID == "" {
+		return "tool_result_" + toolName + ".json"
+	}
+	return "tool_result_" + toolName + "_" + functionCallID + ".json"
+}
+
+type truncatingTool struct {
+	toolinternal.FunctionTool
+	cfg Config
+}
+
+// Unwrap returns the wrapped tool.
+func (t *truncatingTool) Unwrap() toolinternal.FunctionTool {
+	return t.FunctionTool
+}
+
+// ProcessRequest lets the wrapped tool process the request, and registers the
+// wrapper in its place, see toolinternal.RegisterWrapper.
+func (t *truncatingTool) ProcessRequest(ctx tool.Context, req *model.LLMRequest) error {
+	return toolinternal.RegisterWrapper(ctx, req, t.FunctionTool, t)
+}
+
+// Run runs the wrapped tool and truncates its result.
+func (t *truncatingTool) Run(ctx tool.Context, args any) (map[string]any, error) {
+	result, err := t.FunctionTool.Run(ctx, args)
+	if err != nil {
+		return result, err
+	}
+	encoded, err := encode(result)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode the result: %w", err)
+	}
+	if utf8.RuneCountInString(encoded) <= t.cfg.MaxChars {
+		return result, nil
+	}
+
+	remaining := utf8.RuneCountInString(encoded) - t.cfg.MaxChars
+	truncated := map[string]any{
+		"result":          prefix(encoded, t.cfg.MaxChars),
+		"remaining_chars": remaining,
+		"note":            fmt.Sprintf("truncated; %d more chars not available", remaining),
+	}
+	if ctx == nil {
+		return truncated, nil
+	}
+	name := ArtifactName(t.Name(), ctx.FunctionCallID())
+	if _, err := ctx.Artifacts().Save(ctx, name, genai.NewPartFromText(encoded)); err != nil {
+		return truncated, nil
+	}
+	names, _ := json.Marshal([]string{name})
+	truncated["artifact"] = name
+	truncated["note"] = fmt.Sprintf("truncated; %d more chars available; call %s with artifact_names %s", remaining, t.cfg.LoadToolName, names)
+	return truncated, nil
+}
+
+// encode returns the canonical JSON encoding of the result, see
+// tool.CanonicalJSON, whose HTML characters are not escaped so that the sizes
+// match the text the model sees.
+func encode(result map[string]any) (string, error) {
+	encoded, err := tool.CanonicalJSON(result)
+	return string(encoded), err
+}
+
+// prefix returns the first n characters of s.
+func prefix(s string, n int) string {
+	for i := range s {
+		if n == 0 {
+			return s[:i]
+		}
+		n--
+	}
+	return s
+}
@@ -0,0 +1,147 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package truncatetool_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"google.golang.org/adk/agent"
+	"google.golang.org/adk/artifact"
+	artifactinternal "google.golang.org/adk/internal/artifact"
+	icontext "google.golang.org/adk/internal/context"
+	"google.golang.org/adk/internal/toolinternal"
+	"google.golang.org/adk/model"
+	"google.golang.org/adk/tool"
+	"google.golang.org/adk/tool/functiontool"
+	"google.golang.org/adk/tool/truncatetool"
+)
+
+type searchArgs struct {
+	Query string `json:"query"`
+}
+
+func newSearchTool(t *testing.T) tool.Tool {
+	t.Helper()
+	searchTool, err := functiontool.New(functiontool.Config{
+		Name:        "search",
+		Description: "Searches the documents.",
+	}, func(_ tool.Context, args searchArgs) (map[string]any, error) {
+		return map[string]any{"text": strings.Repeat("é", len(args.Query))}, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return searchTool
+}
+
+func newToolContext(t *testing.T, artifacts agent.Artifacts) tool.Context {
+	t.Helper()
+	ctx := icontext.NewInvocationContext(t.Context(), icontext.InvocationContextParams{Artifacts: artifacts})
+	return toolinternal.NewToolContext(ctx, "call1", nil)
+}
+
+func TestWrap(t *testing.T) {
+	wrapped, err := truncatetool.Wrap(newSearchTool(t), truncatetool.Config{MaxChars: 20})
+	if err != nil {
+		t.Fatal(err)
+	}
+	artifacts := &artifactinternal.Artifacts{
+		Service:   artifact.InMemoryService(),
+		AppName:   "app",
+		UserID:    "user",
+		SessionID: "session",
+	}
+
+	tests := []struct {
+		name      string
+		ctx       tool.Context
+		query     string
+		want      map[string]any
+		wantSaved string
+	}{
+		{
+			name:  "small result",
+			ctx:   newToolContext(t, artifacts),
+			query: "go",
+			want:  map[string]any{"text": "éé"},
+		},
+		{
+			name:  "large result",
+			ctx:   newToolContext(t, artifacts),
+			query: "go tools and agents",
+			want: map[string]any{
+				"result":          `{"text":"ééééééééééé`,
+				"remaining_chars": 10,
+				"artifact":        "tool_result_search_call1.json",
+				"note":            `truncated; 10 more chars available; call load_artifacts with artifact_names ["tool_result_search_call1.json"]`,
+			},
+			wantSaved: `{"text":"` + strings.Repeat("é", 19) + `"}`,
+		},
+		{
+			name:  "no artifact service",
+			ctx:   newToolContext(t, nil),
+			query: "go tools and agents",
+			want: map[string]any{
+				"result":          `{"text":"ééééééééééé`,
+				"remaining_chars": 10,
+				"note":            "truncated; 10 more chars not available",
+			},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := wrapped.(toolinternal.FunctionTool).Run(tc.ctx, map[string]any{"query": tc.query})
+			if err != nil {
+				t.Fatalf("Run() error = %v", err)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("Run() mismatch (-want +got):\n%s", diff)
+			}
+			if tc.wantSaved == "" {
+				return
+			}
+			saved, err := tc.ctx.Artifacts().Load(t.Context(), truncatetool.ArtifactName("search", "call1"))
+			if err != nil {
+				t.Fatalf("Load() error = %v", err)
+			}
+			if saved.Part.Text != tc.wantSaved {
+				t.Errorf("saved artifact = %q, want %q", saved.Part.Text, tc.wantSaved)
+			}
+			if got := tc.ctx.Actions().ArtifactDelta; got["tool_result_search_call1.json"] == 0 {
+				t.Errorf("ArtifactDelta = %v, want the saved artifact", got)
+			}
+		})
+	}
+}
+
+func TestWrap_ProcessRequest(t *testing.T) {
+	wrapped, err := truncatetool.Wrap(newSearchTool(t), truncatetool.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	req := &model.LLMRequest{}
+	if err := wrapped.(toolinternal.RequestProcessor).ProcessRequest(nil, req); err != nil {
+		t.Fatalf("ProcessRequest() error = %v", err)
+	}
+	if req.Tools["search"] != wrapped {
+		t.Errorf("Tools[%q] = %v, want the wrapper", "search", req.Tools["search"])
+	}
+	if got := len(req.Config.Tools[0].FunctionDeclarations); got != 1 {
+		t.Errorf("got %d function declarations, want 1", got)
+	}
+}
@@ -0,0 +1,185 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package workflowtool provides a tool that guides the model through a
+// multi-turn state machine, such as an onboarding or troubleshooting flow.
+//
+// The workflow is described by a [Definition]: a set of named states, each
+// with the actions the model may take from it and the state every action
+// leads to. The current state is kept in the session state, so the flow
+// survives across turns and invocations.
+//
+// The model calls the tool with the action it wants to take. The tool
+// validates the transition against the current state, runs the entry action
+// of the new state, stores it and returns the new state together with the
+// actions allowed next. Calling the tool without an action reports the
+// current state. An invalid transition does not change the state; it is
+// reported in the [Result] with the actions that are allowed instead, so the
+// model can correct itself.
+package workflowtool
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+
+	"google.golang.org/adk/session"
+	"google.golang.org/adk/tool"
+	"google.golang.org/adk/tool/functiontool"
+)
+
+// Definition describes a workflow.
+type Definition struct {
+	// Name of the tool as seen by the model.
+	Name string
+	// Description of the workflow, used as the tool description.
+	Description string
+	// InitialState is the state the workflow starts in.
+	InitialState string
+	// States of the workflow, keyed by state name.
+	States map[string]State
+	// StateKey is the session state key holding the current state.
+	// Defaults to "workflow:" + Name.
+	StateKey string
+}
+
+// State is a state of a workflow.
+type State struct {
+	// Description explains the state to the model, e.g. what information
+	// has to be collected before moving on.
+	Description string
+	// Transitions maps the actions allowed in this state to the state they
+	// lead to. A state without transitions is final.
+	Transitions map[string]string
+	// OnEnter is an optional action run when the workflow enters the state.
+	// If it returns an error, the transition is aborted, the workflow stays
+	// in its current state and the error is reported to the model.
+	OnEnter func(ctx tool.Context) error
+}
+
+// Args are the arguments the model provides when calling the tool.
+type Args struct {
+	// Action to take from the current state.
+	Action string `json:"action,omitempty" jsonschema:"the action to take from the current state; omit to get the current state"`
+}
+
+// Result is the response of the tool.
+type Result struct {
+	// State is the current state of the workflow after the call.
+	State string `json:"state"`
+	// Description of the current state.
+	Description string `json:"description,omitempty"`
+	// AllowedActions are the actions that can be taken from the current state.
+	AllowedActions []string `json:"allowed_actions"`
+	// Final reports whether the workflow reached a final state.
+	Final bool `json:"final,omitempty"`
+	// Error describes why the requested action was rejected.
+	Error string `json:"error,omitempty"`
+}
+
+// New creates a tool driving the given workflow.
+func New(def Definition) (tool.Tool, error) {
+	if def.Name == "" {
+		return nil, errors.New("workflow name must not be empty")
+	}
+	if _, ok := def.States[def.InitialState]; !ok {
+		return nil, fmt.Errorf("initial state %q is not defined", def.InitialState)
+	}
+	for name, state := range def.States {
+		for action, target := range state.Transitions {
+			if _, ok := def.States[target]; !ok {
+				return nil, fmt.Errorf("action %q of state %q leads to undefined state %q", action, name, target)
+			}
+		}
+	}
+	if def.StateKey == "" {
+		def.StateKey = "workflow:" + def.Name
+	}
+
+	w := &workflow{def: def}
+	workflowTool, err := functiontool.New(functiontool.Config{
+		Name: def.Name,
+		Description: def.Description + "\n" +
+			"Call this tool with one of the allowed actions to move the workflow forward, " +
+			"or without an action to get the current state. Only the returned allowed_actions can be taken.",
+	}, w.run)
+	if err != nil {
+		return nil, fmt.Errorf("error creating workflow tool: %w", err)
+	}
+	return workflowTool, nil
+}
+
+type workflow struct {
+	def Definition
+}
+
+func (w *workflow) run(ctx tool.Context, args Args) (Result, error) {
+	current, err := w.currentState(ctx)
+	if err != nil {
+		return Result{}, err
+	}
+	if args.Action == "" {
+		return w.result(current, ""), nil
+	}
+
+	target, ok := w.def.States[current].Transitions[args.Action]
+	if !ok {
+		return w.result(current, fmt.Sprintf("action %q is not allowed in state %q", args.Action, current)), nil
+	}
+	if onEnter := w.def.States[target].OnEnter; onEnter != nil {
+		if err := onEnter(ctx); err != nil {
+			return w.result(current, fmt.Sprintf("failed to enter state %q: %v", target, err)), nil
+		}
+	}
+	if err := ctx.State().Set(w.def.StateKey, target); err != nil {
+		return Result{}, fmt.Errorf("failed to store workflow state: %w", err)
+	}
+	return w.result(target, ""), nil
+}
+
+// currentState returns the current state stored in the session, or the
+// initial state if the workflow has not started yet.
+func (w *workflow) currentState(ctx tool.Context) (string, error) {
+	val, err := ctx.State().Get(w.def.StateKey)
+	if errors.Is(err, session.ErrStateKeyNotExist) {
+		return w.def.InitialState, nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to read workflow state: %w", err)
+	}
+	current, ok := val.(string)
+	if !ok {
+		return "", fmt.Errorf("unexpected workflow state type %T", val)
+	}
+	if _, ok := w.def.States[current]; !ok {
+		return "", fmt.Errorf("unknown workflow state %q", current)
+	}
+	return current, nil
+}
+
+func (w *workflow) result(stateName, errMsg string) Result {
+	state := w.def.States[stateName]
+	allowed := slices.Sorted(maps.Keys(state.Transitions))
+	if allowed == nil {
+		allowed = []string{}
+	}
+	return Result{
+		State:          stateName,
+		Description:    state.Description,
+		AllowedActions: allowed,
+		Final:          len(state.Transitions) == 0,
+		Error:          errMsg,
+	}
+}
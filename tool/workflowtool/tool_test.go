@@ -0,0 +1,206 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package workflowtool_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	icontext "google.golang.org/adk/internal/context"
+	"google.golang.org/adk/internal/sessioninternal"
+	"google.golang.org/adk/internal/toolinternal"
+	"google.golang.org/adk/session"
+	"google.golang.org/adk/tool"
+	"google.golang.org/adk/tool/workflowtool"
+)
+
+func TestWorkflowTool(t *testing.T) {
+	var entered []string
+	verified := false
+	def := workflowtool.Definition{
+		Name:         "onboarding",
+		Description:  "Guides the user through account onboarding.",
+		InitialState: "start",
+		States: map[string]workflowtool.State{
+			"start": {
+				Description: "Collect the user's email.",
+				Transitions: map[string]string{"submit_email": "verify", "cancel": "cancelled"},
+			},
+			"verify": {
+				Description: "Ask the user for the verification code.",
+				Transitions: map[string]string{"submit_code": "done", "cancel": "cancelled"},
+				OnEnter: func(ctx tool.Context) error {
+					entered = append(entered, "verify")
+					return nil
+				},
+			},
+			"done": {
+				OnEnter: func(ctx tool.Context) error {
+					if !verified {
+						return errors.New("code not verified")
+					}
+					entered = append(entered, "done")
+					return nil
+				},
+			},
+			"cancelled": {},
+		},
+	}
+	workflowTool, err := workflowtool.New(def)
+	if err != nil {
+		t.Fatal(err)
+	}
+	toolImpl, ok := workflowTool.(toolinternal.FunctionTool)
+	if !ok {
+		t.Fatal("workflow tool does not implement FunctionTool")
+	}
+
+	sessionService := session.InMemoryService()
+	createResponse, err := sessionService.Create(t.Context(), &session.CreateRequest{
+		AppName: "testApp",
+		UserID:  "testUser",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := icontext.NewInvocationContext(t.Context(), icontext.InvocationContextParams{
+		Session: sessioninternal.NewMutableSession(sessionService, createResponse.Session),
+	})
+
+	steps := []struct {
+		name   string
+		args   map[string]any
+		before func()
+		want   map[string]any
+	}{
+		{
+			name: "initial state",
+			args: map[string]any{},
+			want: map[string]any{
+				"state":           "start",
+				"description":     "Collect the user's email.",
+				"allowed_actions": []any{"cancel", "submit_email"},
+			},
+		},
+		{
+			name: "invalid transition",
+			args: map[string]any{"action": "submit_code"},
+			want: map[string]any{
+				"state":           "start",
+				"description":     "Collect the user's email.",
+				"allowed_actions": []any{"cancel", "submit_email"},
+				"error":           `action "submit_code" is not allowed in state "start"`,
+			},
+		},
+		{
+			name: "valid transition",
+			args: map[string]any{"action": "submit_email"},
+			want: map[string]any{
+				"state":           "verify",
+				"description":     "Ask the user for the verification code.",
+				"allowed_actions": []any{"cancel", "submit_code"},
+			},
+		},
+		{
+			name: "failed entry action",
+			args: map[string]any{"action": "submit_code"},
+			want: map[string]any{
+				"state":           "verify",
+				"description":     "Ask the user for the verification code.",
+				"allowed_actions": []any{"cancel", "submit_code"},
+				"error":           `failed to enter state "done": code not verified`,
+			},
+		},
+		{
+			name:   "final state",
+			args:   map[string]any{"action": "submit_code"},
+			before: func() { verified = true },
+			want: map[string]any{
+				"state":           "done",
+				"allowed_actions": []any{},
+				"final":           true,
+			},
+		},
+	}
+	for _, step := range steps {
+		if step.before != nil {
+			step.before()
+		}
+		toolCtx := toolinternal.NewToolContext(ctx, "", nil)
+		got, err := toolImpl.Run(toolCtx, step.args)
+		if err != nil {
+			t.Fatalf("%s: Run() error = %v", step.name, err)
+		}
+		// Commit the state changes, as the flow does after a call.
+		if err := toolinternal.CommitState(toolCtx); err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff(step.want, got); diff != "" {
+			t.Errorf("%s: Run() mismatch (-want +got):\n%s", step.name, diff)
+		}
+	}
+
+	if diff := cmp.Diff([]string{"verify", "done"}, entered); diff != "" {
+		t.Errorf("entry actions mismatch (-want +got):\n%s", diff)
+	}
+	state, err := ctx.Session().State().Get("workflow:onboarding")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if state != "done" {
+		t.Errorf("stored state = %v, want %q", state, "done")
+	}
+}
+
+func TestNew_InvalidDefinition(t *testing.T) {
+	tests := []struct {
+		name string
+		def  workflowtool.Definition
+	}{
+		{
+			name: "missing name",
+			def: workflowtool.Definition{
+				InitialState: "start",
+				States:       map[string]workflowtool.State{"start": {}},
+			},
+		},
+		{
+			name: "undefined initial state",
+			def: workflowtool.Definition{
+				Name:         "flow",
+				InitialState: "start",
+			},
+		},
+		{
+			name: "undefined target state",
+			def: workflowtool.Definition{
+				Name:         "flow",
+				InitialState: "start",
+				States: map[string]workflowtool.State{
+					"start": {Transitions: map[string]string{"next": "missing"}},
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := workflowtool.New(tt.def); err == nil {
+				t.Error("New() succeeded, want error")
+			}
+		})
+	}
+}
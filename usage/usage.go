@@ -0,0 +1,344 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package usage aggregates the token usage of model calls, and attributes
+// the prompt tokens to the tools that caused them.
+//
+// # Attribution methodology
+//
+// Models report the number of prompt tokens of a call, but not which parts
+// of the prompt they come from. The LLM flow therefore estimates, for every
+// model call, the share of the prompt of each tool:
+//
+//   - The declaration tokens of a tool are the tokens of its function
+//     declaration (name, description and parameter schema), which is sent
+//     with every call the tool is available to.
+//   - The result tokens of a tool are the tokens of all its function
+//     responses present in the contents of the call. Since the conversation
+//     history is sent again with every call, a result is counted once for
+//     each subsequent model call, which reflects its actual cost.
+//
+// The sizes are estimated from the JSON encoding of the parts with
+// [EstimateTokens], and then scaled so that the estimated size of the whole
+// prompt (system instruction, contents and declarations) matches the prompt
+// token count reported by the model.
+//
+// The attribution is stored in the custom metadata of the final model
+// response event of each call under [MetadataKey]. It is only recorded for
+// calls whose response reports usage metadata. An [Aggregator] sums the
+// usage of events into a [Report], for the whole session and for each
+// invocation.
+//
+// The token budget of a run, agent.RunConfig.MaxTokens, is counted as the
+// TotalTokens of the report of its invocation.
+package usage
+
+import (
+	"encoding/json"
+	"math"
+
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/model"
+	"google.golang.org/adk/session"
+)
+
+// MetadataKey is the model.LLMResponse.CustomMetadata key holding the
+// per-tool token attribution of a model call. The value maps tool names to
+// maps with the "declaration_tokens" and "result_tokens" keys.
+const MetadataKey = "adk_tool_token_usage"
+
+const (
+	declarationTokensKey = "declaration_tokens"
+	resultTokensKey      = "result_tokens"
+
+	// bytesPerToken is the average number of bytes of JSON encoded text per
+	// token, a common approximation for current tokenizers.
+	bytesPerToken = 4
+)
+
+// ToolTokens is the token usage attributed to a tool.
+type ToolTokens struct {
+	// DeclarationTokens are the prompt tokens of the tool declaration.
+	DeclarationTokens int64
+	// ResultTokens are the prompt tokens of the tool results.
+	ResultTokens int64
+}
+
+// Total returns the total tokens attributed to the tool.
+func (t ToolTokens) Total() int64 {
+	return t.DeclarationTokens + t.ResultTokens
+}
+
+// EstimateTokens returns the estimated number of tokens of the JSON encoding
+// of v.
+func EstimateTokens(v any) int64 {
+	if v == nil {
+		return 0
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return 0
+	}
+	return int64(math.Ceil(float64(len(b)) / bytesPerToken))
+}
+
+// AttributeTools returns the tokens of the request attributed to each tool,
+// keyed by tool name, following the methodology described in the package
+// documentation. promptTokens is the prompt token count reported by the
+// model; if it is 0, the estimates are returned unscaled.
+func AttributeTools(req *model.LLMRequest, promptTokens int32) map[string]ToolTokens {
+	if req == nil {
+		return nil
+	}
+	attribution := make(map[string]ToolTokens)
+	var total int64
+
+	for _, c := range req.Contents {
+		if c == nil {
+			continue
+		}
+		for _, p := range c.Parts {
+			n := EstimateTokens(p)
+			total += n
+			if p != nil && p.FunctionResponse != nil && p.FunctionResponse.Name != "" {
+				t := attribution[p.FunctionResponse.Name]
+				t.ResultTokens += n
+				attribution[p.FunctionResponse.Name] = t
+			}
+		}
+	}
+	if req.Config != nil {
+		total += EstimateTokens(req.Config.SystemInstruction)
+		for _, tool := range req.Config.Tools {
+			if tool == nil {
+				continue
+			}
+			for _, decl := range tool.FunctionDeclarations {
+				if decl == nil {
+					continue
+				}
+				n := EstimateTokens(decl)
+				total += n
+				t := attribution[decl.Name]
+				t.DeclarationTokens += n
+				attribution[decl.Name] = t
+			}
+		}
+	}
+	if len(attribution) == 0 {
+		return nil
+	}
+
+	if promptTokens > 0 && total > 0 {
+		ratio := float64(promptTokens) / float64(total)
+		for name, t := range attribution {
+			attribution[name] = ToolTokens{
+				DeclarationTokens: int64(math.Round(float64(t.DeclarationTokens) * ratio)),
+				ResultTokens:      int64(math.Round(float64(t.ResultTokens) * ratio)),
+			}
+		}
+	}
+	return attribution
+}
+
+// Metadata returns the value stored under [MetadataKey] for the attribution.
+func Metadata(attribution map[string]ToolTokens) map[string]any {
+	m := make(map[string]any, len(attribution))
+	for name, t := range attribution {
+		m[name] = map[string]any{
+			declarationTokensKey: t.DeclarationTokens,
+			resultTokensKey:      t.ResultTokens,
+		}
+	}
+	return m
+}
+
+// FromMetadata returns the attribution stored in the custom metadata of a
+// model response. It accepts the numeric types produced when the metadata is
+// decoded from JSON, e.g. after being persisted by a session service.
+func FromMetadata(customMetadata map[string]any) map[string]ToolTokens {
+	m, ok := customMetadata[MetadataKey].(map[string]any)
+	if !ok {
+		return nil
+	}
+	attribution := make(map[string]ToolTokens, len(m))
+	for name, v := range m {
+		fields, ok := v.(map[string]any)
+		if !ok {
+			continue
+		}
+		attribution[name] = ToolTokens{
+			DeclarationTokens: toInt64(fields[declarationTokensKey]),
+			ResultTokens:      toInt64(fields[resultTokensKey]),
+		}
+	}
+	return attribution
+}
+
+func toInt64(v any) int64 {
+	switch n := v.(type) {
+	case int:
+		return int64(n)
+	case int32:
+		return int64(n)
+	case int64:
+		return n
+	case float64:
+		return int64(n)
+	case json.Number:
+		i, _ := n.Int64()
+		return i
+	}
+	return 0
+}
+
+// ToolReport is the usage of a tool in a [Report].
+type ToolReport struct {
+	ToolTokens
+	// Calls is the number of calls of the tool requested by the model.
+	Calls int
+	// ModelCalls is the number of model calls the tool contributed tokens to.
+	ModelCalls int
+}
+
+// Report is the aggregated token usage of a series of model calls.
+type Report struct {
+	// ModelCalls is the number of model calls.
+	ModelCalls int
+	// PromptTokens, CandidatesTokens, CachedTokens, ThoughtsTokens and
+	// TotalTokens are the sums of the token counts reported by the model.
+	// The cached tokens are part of the prompt tokens.
+	PromptTokens     int64
+	CandidatesTokens int64
+	CachedTokens     int64
+	ThoughtsTokens   int64
+	TotalTokens      int64
+	// Tools is the usage attributed to each tool, keyed by tool name.
+	Tools map[string]*ToolReport
+}
+
+// Aggregator sums the token usage of events into a [Report], and into a
+// report per invocation. It is not safe for concurrent use.
+type Aggregator struct {
+	report      Report
+	invocations map[string]*Report
+}
+
+// NewAggregator returns an empty aggregator.
+func NewAggregator() *Aggregator {
+	return &Aggregator{
+		report:      Report{Tools: make(map[string]*ToolReport)},
+		invocations: make(map[string]*Report),
+	}
+}
+
+// Add adds the usage of an event. Partial events are ignored, as their
+// usage is included in the final event of the model call.
+func (a *Aggregator) Add(ev *session.Event) {
+	if ev == nil || ev.Partial {
+		return
+	}
+	a.report.add(ev)
+	if ev.InvocationID == "" {
+		return
+	}
+	r, ok := a.invocations[ev.InvocationID]
+	if !ok {
+		r = &Report{Tools: make(map[string]*ToolReport)}
+		a.invocations[ev.InvocationID] = r
+	}
+	r.add(ev)
+}
+
+func (r *Report) add(ev *session.Event) {
+	for _, fc := range functionCalls(ev.Content) {
+		r.tool(fc.Name).Calls++
+	}
+	u := ev.UsageMetadata
+	attribution := FromMetadata(ev.CustomMetadata)
+	if u == nil && attribution == nil {
+		return
+	}
+	r.ModelCalls++
+	if u != nil {
+		r.PromptTokens += int64(u.PromptTokenCount)
+		r.CandidatesTokens += int64(u.CandidatesTokenCount)
+		r.CachedTokens += int64(u.CachedContentTokenCount)
+		r.ThoughtsTokens += int64(u.ThoughtsTokenCount)
+		r.TotalTokens += int64(u.TotalTokenCount)
+	}
+	for name, t := range attribution {
+		tr := r.tool(name)
+		tr.DeclarationTokens += t.DeclarationTokens
+		tr.ResultTokens += t.ResultTokens
+		if t.Total() > 0 {
+			tr.ModelCalls++
+		}
+	}
+}
+
+// AddSession adds the usage of all events of the session.
+func (a *Aggregator) AddSession(s session.Session) {
+	for ev := range s.Events().All() {
+		a.Add(ev)
+	}
+}
+
+// Report returns a copy of the aggregated report.
+func (a *Aggregator) Report() Report {
+	return a.report.clone()
+}
+
+// InvocationReport returns a copy of the aggregated report of the events of
+// the invocation, which is empty if there are none.
+func (a *Aggregator) InvocationReport(invocationID string) Report {
+	r, ok := a.invocations[invocationID]
+	if !ok {
+		return Report{Tools: make(map[string]*ToolReport)}
+	}
+	return r.clone()
+}
+
+func (r *Report) clone() Report {
+	c := *r
+	c.Tools = make(map[string]*ToolReport, len(r.Tools))
+	for name, t := range r.Tools {
+		copied := *t
+		c.Tools[name] = &copied
+	}
+	return c
+}
+
+func (r *Report) tool(name string) *ToolReport {
+	t, ok := r.Tools[name]
+	if !ok {
+		t = &ToolReport{}
+		r.Tools[name] = t
+	}
+	return t
+}
+
+func functionCalls(c *genai.Content) []*genai.FunctionCall {
+	if c == nil {
+		return nil
+	}
+	var calls []*genai.FunctionCall
+	for _, p := range c.Parts {
+		if p != nil && p.FunctionCall != nil {
+			calls = append(calls, p.FunctionCall)
+		}
+	}
+	return calls
+}
@@ -0,0 +1,185 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package usage
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/model"
+	"google.golang.org/adk/session"
+)
+
+func testRequest() *model.LLMRequest {
+	return &model.LLMRequest{
+		Contents: []*genai.Content{
+			genai.NewContentFromText("what is the weather?", genai.RoleUser),
+			genai.NewContentFromFunctionCall("weather", map[string]any{"city": "Paris"}, genai.RoleModel),
+			genai.NewContentFromFunctionResponse("weather", map[string]any{"report": strings.Repeat("sunny ", 100)}, genai.RoleUser),
+		},
+		Config: &genai.GenerateContentConfig{
+			Tools: []*genai.Tool{{
+				FunctionDeclarations: []*genai.FunctionDeclaration{
+					{Name: "weather", Description: "Returns the weather of a city."},
+					{Name: "time", Description: "Returns the time of a city."},
+				},
+			}},
+		},
+	}
+}
+
+func TestAttributeTools(t *testing.T) {
+	req := testRequest()
+
+	estimated := AttributeTools(req, 0)
+	if got, want := estimated["weather"].ResultTokens, EstimateTokens(req.Contents[2].Parts[0]); got != want {
+		t.Errorf("weather result tokens = %d, want %d", got, want)
+	}
+	if got, want := estimated["time"].DeclarationTokens, EstimateTokens(req.Config.Tools[0].FunctionDeclarations[1]); got != want {
+		t.Errorf("time declaration tokens = %d, want %d", got, want)
+	}
+	if estimated["time"].ResultTokens != 0 {
+		t.Errorf("time result tokens = %d, want 0", estimated["time"].ResultTokens)
+	}
+
+	// Scaling to twice the estimated prompt doubles the attribution.
+	var total int64
+	for _, c := range req.Contents {
+		for _, p := range c.Parts {
+			total += EstimateTokens(p)
+		}
+	}
+	for _, d := range req.Config.Tools[0].FunctionDeclarations {
+		total += EstimateTokens(d)
+	}
+	scaled := AttributeTools(req, int32(2*total))
+	for name, want := range estimated {
+		got := scaled[name]
+		if diff := got.DeclarationTokens - 2*want.DeclarationTokens; diff < -1 || diff > 1 {
+			t.Errorf("%s declaration tokens = %d, want about %d", name, got.DeclarationTokens, 2*want.DeclarationTokens)
+		}
+		if diff := got.ResultTokens - 2*want.ResultTokens; diff < -1 || diff > 1 {
+			t.Errorf("%s result tokens = %d, want about %d", name, got.ResultTokens, 2*want.ResultTokens)
+		}
+	}
+
+	if got := AttributeTools(&model.LLMRequest{Contents: req.Contents[:1]}, 10); got != nil {
+		t.Errorf("AttributeTools() without tools = %v, want nil", got)
+	}
+}
+
+func TestAggregator(t *testing.T) {
+	newEvent := func(content *genai.Content, promptTokens int32, attribution map[string]ToolTokens) *session.Event {
+		ev := session.NewEvent("inv")
+		ev.Content = content
+		ev.UsageMetadata = &genai.GenerateContentResponseUsageMetadata{
+			PromptTokenCount:     promptTokens,
+			CandidatesTokenCount: 5,
+			TotalTokenCount:      promptTokens + 5,
+		}
+		ev.CustomMetadata = map[string]any{MetadataKey: Metadata(attribution)}
+		return ev
+	}
+
+	first := newEvent(genai.NewContentFromFunctionCall("weather", nil, genai.RoleModel), 100, map[string]ToolTokens{
+		"weather": {DeclarationTokens: 20},
+		"time":    {DeclarationTokens: 15},
+	})
+	response := session.NewEvent("inv")
+	response.Content = genai.NewContentFromFunctionResponse("weather", map[string]any{"report": "sunny"}, genai.RoleUser)
+	partial := newEvent(genai.NewContentFromText("It is", genai.RoleModel), 150, nil)
+	partial.Partial = true
+	second := newEvent(genai.NewContentFromText("It is sunny.", genai.RoleModel), 150, map[string]ToolTokens{
+		"weather": {DeclarationTokens: 20, ResultTokens: 40},
+		"time":    {DeclarationTokens: 15},
+	})
+
+	// Round trip the metadata through JSON, as done by persistent session
+	// services.
+	b, err := json.Marshal(second.CustomMetadata)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second.CustomMetadata = nil
+	if err := json.Unmarshal(b, &second.CustomMetadata); err != nil {
+		t.Fatal(err)
+	}
+
+	a := NewAggregator()
+	for _, ev := range []*session.Event{first, response, partial, second} {
+		a.Add(ev)
+	}
+
+	want := Report{
+		ModelCalls:       2,
+		PromptTokens:     250,
+		CandidatesTokens: 10,
+		TotalTokens:      260,
+		Tools: map[string]*ToolReport{
+			"weather": {ToolTokens: ToolTokens{DeclarationTokens: 40, ResultTokens: 40}, Calls: 1, ModelCalls: 2},
+			"time":    {ToolTokens: ToolTokens{DeclarationTokens: 30}, ModelCalls: 2},
+		},
+	}
+	if diff := cmp.Diff(want, a.Report()); diff != "" {
+		t.Errorf("Report() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestAggregator_InvocationReport(t *testing.T) {
+	newEvent := func(invocationID string, prompt, cached, thoughts int32) *session.Event {
+		ev := session.NewEvent(invocationID)
+		ev.Content = genai.NewContentFromText("answer", genai.RoleModel)
+		ev.UsageMetadata = &genai.GenerateContentResponseUsageMetadata{
+			PromptTokenCount:        prompt,
+			CachedContentTokenCount: cached,
+			CandidatesTokenCount:    5,
+			ThoughtsTokenCount:      thoughts,
+			TotalTokenCount:         prompt + 5 + thoughts,
+		}
+		return ev
+	}
+
+	a := NewAggregator()
+	for _, ev := range []*session.Event{
+		newEvent("inv1", 100, 0, 10),
+		newEvent("inv2", 200, 80, 0),
+		newEvent("inv2", 300, 200, 20),
+	} {
+		a.Add(ev)
+	}
+
+	want := Report{
+		ModelCalls:       2,
+		PromptTokens:     500,
+		CandidatesTokens: 10,
+		CachedTokens:     280,
+		ThoughtsTokens:   20,
+		TotalTokens:      530,
+		Tools:            map[string]*ToolReport{},
+	}
+	if diff := cmp.Diff(want, a.InvocationReport("inv2")); diff != "" {
+		t.Errorf("InvocationReport(inv2) mismatch (-want +got):\n%s", diff)
+	}
+	if got := a.Report().TotalTokens; got != 645 {
+		t.Errorf("Report().TotalTokens = %d, want 645", got)
+	}
+	if got := a.InvocationReport("unknown"); got.ModelCalls != 0 || got.TotalTokens != 0 {
+		t.Errorf("InvocationReport(unknown) = %+v, want an empty report", got)
+	}
+}
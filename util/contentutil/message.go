@@ -0,0 +1,172 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package contentutil builds multimodal messages: text with images, PDFs,
+// audio or video, inline or referenced by URI.
+//
+//	msg, err := contentutil.NewUserMessage().
+//		WithText("What is in this picture?").
+//		WithImage("photo.jpg").
+//		Build()
+//
+// The inline data is limited by the models, e.g. to 20MB per request for the
+// Gemini API: UploadLarge moves the larger files to the Files API.
+package contentutil
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"google.golang.org/genai"
+)
+
+// Message is a builder of the content of a message. Its methods add parts to
+// the message, in order; the first error, e.g. a file that cannot be read,
+// is returned by Build.
+type Message struct {
+	role  genai.Role
+	parts []*genai.Part
+	err   error
+}
+
+// NewUserMessage returns a builder of a message of the user.
+func NewUserMessage() *Message {
+	return &Message{role: genai.RoleUser}
+}
+
+// WithText adds a text part.
+func (m *Message) WithText(text string) *Message {
+	m.parts = append(m.parts, genai.NewPartFromText(text))
+	return m
+}
+
+// WithBytes adds inline data of a MIME type.
+func (m *Message) WithBytes(data []byte, mimeType string) *Message {
+	m.parts = append(m.parts, genai.NewPartFromBytes(data, mimeType))
+	return m
+}
+
+// WithFile adds the content of a local file as inline data. Its MIME type is
+// the one of its extension, e.g. application/pdf for ".pdf", or else the one
+// detected from its content.
+func (m *Message) WithFile(path string) *Message {
+	m.withFile(path, "")
+	return m
+}
+
+// WithImage adds a local image file as inline data, as WithFile does. It
+// fails if the file is not an image.
+func (m *Message) WithImage(path string) *Message {
+	m.withFile(path, "image/")
+	return m
+}
+
+func (m *Message) withFile(path, mimePrefix string) {
+	if m.err != nil {
+		return
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		m.err = fmt.Errorf("failed to read file: %w", err)
+		return
+	}
+	mimeType := MIMEType(path, data)
+	if !strings.HasPrefix(mimeType, mimePrefix) {
+		m.err = fmt.Errorf("file %s of MIME type %s is not an image", path, mimeType)
+		return
+	}
+	m.parts = append(m.parts, genai.NewPartFromBytes(data, mimeType))
+}
+
+// WithURI adds a file referenced by its URI, e.g. a Cloud Storage URI
+// "gs://bucket/report.pdf" for Vertex AI, or the URI of a file of the Files
+// API of the Gemini API.
+func (m *Message) WithURI(uri, mimeType string) *Message {
+	m.parts = append(m.parts, genai.NewPartFromURI(uri, mimeType))
+	return m
+}
+
+// Build returns the content of the message, or the first error of the
+// builder.
+func (m *Message) Build() (*genai.Content, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	if len(m.parts) == 0 {
+		return nil, errors.New("message has no parts")
+	}
+	return genai.NewContentFromParts(m.parts, m.role), nil
+}
+
+// MIMEType returns the MIME type of a file: the one of its extension, if
+// known, or else the one detected from its data.
+func MIMEType(path string, data []byte) string {
+	if mimeType := mime.TypeByExtension(filepath.Ext(path)); mimeType != "" {
+		mimeType, _, _ = strings.Cut(mimeType, ";")
+		return mimeType
+	}
+	mimeType, _, _ := strings.Cut(http.DetectContentType(data), ";")
+	return mimeType
+}
+
+// DefaultMaxInlineSize is the default size of the inline data above which
+// UploadLarge uploads it.
+const DefaultMaxInlineSize = 20 << 20
+
+// FileUploader uploads files, e.g. the Files of a genai.Client of the
+// Gemini API.
+type FileUploader interface {
+	Upload(ctx context.Context, r io.Reader, config *genai.UploadFileConfig) (*genai.File, error)
+}
+
+// UploadLarge uploads the inline data of the parts of the content larger
+// than maxInlineSize bytes, DefaultMaxInlineSize if zero, and replaces them
+// with references to the uploaded files:
+//
+//	err := contentutil.UploadLarge(ctx, client.Files, msg, 0)
+//
+// The files of the Files API expire after 48 hours, and the videos must be
+// processed, see genai.FileStateActive, before the model can use them.
+func UploadLarge(ctx context.Context, uploader FileUploader, content *genai.Content, maxInlineSize int) error {
+	if maxInlineSize == 0 {
+		maxInlineSize = DefaultMaxInlineSize
+	}
+	if content == nil {
+		return nil
+	}
+	for i, part := range content.Parts {
+		if part == nil || part.InlineData == nil || len(part.InlineData.Data) <= maxInlineSize {
+			continue
+		}
+		blob := part.InlineData
+		file, err := uploader.Upload(ctx, bytes.NewReader(blob.Data), &genai.UploadFileConfig{MIMEType: blob.MIMEType})
+		if err != nil {
+			return fmt.Errorf("failed to upload inline data: %w", err)
+		}
+		mimeType := file.MIMEType
+		if mimeType == "" {
+			mimeType = blob.MIMEType
+		}
+		content.Parts[i] = genai.NewPartFromURI(file.URI, mimeType)
+	}
+	return nil
+}
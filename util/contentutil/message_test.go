@@ -0,0 +1,121 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package contentutil_test
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/util/contentutil"
+)
+
+func writeFile(t *testing.T, name string, data []byte) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestMessage(t *testing.T) {
+	png := []byte("\x89PNG\r\n\x1a\n0000")
+	pdf := []byte("%PDF-1.7")
+
+	got, err := contentutil.NewUserMessage().
+		WithText("Compare them.").
+		WithImage(writeFile(t, "chart.png", png)).
+		WithFile(writeFile(t, "report.pdf", pdf)).
+		WithImage(writeFile(t, "photo", png)).
+		WithURI("gs://bucket/talk.mp3", "audio/mpeg").
+		WithBytes([]byte("a,b"), "text/csv").
+		Build()
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+	want := genai.NewContentFromParts([]*genai.Part{
+		genai.NewPartFromText("Compare them."),
+		genai.NewPartFromBytes(png, "image/png"),
+		genai.NewPartFromBytes(pdf, "application/pdf"),
+		genai.NewPartFromBytes(png, "image/png"),
+		genai.NewPartFromURI("gs://bucket/talk.mp3", "audio/mpeg"),
+		genai.NewPartFromBytes([]byte("a,b"), "text/csv"),
+	}, genai.RoleUser)
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Build() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestMessage_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		msg  *contentutil.Message
+	}{
+		{"no parts", contentutil.NewUserMessage()},
+		{"missing file", contentutil.NewUserMessage().WithText("hi").WithFile(filepath.Join(t.TempDir(), "missing.pdf"))},
+		{"not an image", contentutil.NewUserMessage().WithImage(writeFile(t, "report.pdf", []byte("%PDF-1.7")))},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := tc.msg.Build(); err == nil {
+				t.Error("Build() error = nil, want an error")
+			}
+		})
+	}
+}
+
+type fakeUploader struct {
+	uploaded [][]byte
+}
+
+func (u *fakeUploader) Upload(_ context.Context, r io.Reader, cfg *genai.UploadFileConfig) (*genai.File, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	u.uploaded = append(u.uploaded, data)
+	return &genai.File{URI: "https://files.example.com/1", MIMEType: cfg.MIMEType}, nil
+}
+
+func TestUploadLarge(t *testing.T) {
+	large := make([]byte, 100)
+	content := genai.NewContentFromParts([]*genai.Part{
+		genai.NewPartFromText("Summarize it."),
+		genai.NewPartFromBytes([]byte("small"), "text/plain"),
+		genai.NewPartFromBytes(large, "video/mp4"),
+	}, genai.RoleUser)
+
+	uploader := &fakeUploader{}
+	if err := contentutil.UploadLarge(t.Context(), uploader, content, 10); err != nil {
+		t.Fatalf("UploadLarge() error = %v", err)
+	}
+	want := genai.NewContentFromParts([]*genai.Part{
+		genai.NewPartFromText("Summarize it."),
+		genai.NewPartFromBytes([]byte("small"), "text/plain"),
+		genai.NewPartFromURI("https://files.example.com/1", "video/mp4"),
+	}, genai.RoleUser)
+	if diff := cmp.Diff(want, content); diff != "" {
+		t.Errorf("UploadLarge() content mismatch (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff([][]byte{large}, uploader.uploaded); diff != "" {
+		t.Errorf("uploaded files mismatch (-want +got):\n%s", diff)
+	}
+}
@@ -30,7 +30,8 @@ import (
 //   - key_name must match "^[a-zA-Z_][a-zA-Z0-9_]*$", otherwise it will be
 //     treated as a literal.
 //   - {artifact.key_name} can be used to insert the text content of the
-//     artifact named key_name.
+//     artifact named key_name: its text, or its data if it has a textual MIME
+//     type such as text/markdown or application/json.
 //
 // If the state variable or artifact does not exist, the agent will raise an
 // error. If you want to ignore the error, you can append a ? to the
@@ -0,0 +1,130 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package instructionutil
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"text/template"
+
+	"google.golang.org/adk/agent"
+)
+
+// InstructionTemplate is an instruction rendered with text/template against
+// the current session state on every model request.
+//
+// The template data has the following fields:
+//   - .State: map of the session state, e.g. {{.State.user_name}}.
+//   - .AgentName, .AppName, .UserID, .SessionID of the current invocation.
+//
+// Referencing a missing state key with {{.State.key}}, including in {{if}}
+// and {{with}}, is a render error. Use {{index .State "key"}} for optional
+// keys, e.g. {{with index .State "key"}}...{{end}} or
+// {{index .State "key" | default "none"}}.
+//
+// Only a restricted set of functions is available: the text/template builtins
+// except "call", plus join, upper, lower, trim, contains, default and json.
+//
+// Use the Render method as an InstructionProvider:
+//
+//	tmpl, err := instructionutil.NewInstructionTemplate("root", `You help {{.State.user_name}}.
+//	{{with index .State "items"}}Open items:{{range .}}
+//	- {{.}}{{end}}{{end}}`)
+//	...
+//	llmagent.New(llmagent.Config{InstructionProvider: tmpl.Render, ...})
+//
+// If rendering fails, Render returns an error and the agent run fails with it,
+// the same way as for a missing placeholder in a plain Instruction.
+type InstructionTemplate struct {
+	tmpl *template.Template
+}
+
+// NewInstructionTemplate parses the template text. It reports syntax errors
+// and references to functions outside of the allowed set.
+func NewInstructionTemplate(name, text string) (*InstructionTemplate, error) {
+	tmpl, err := template.New(name).Option("missingkey=error").Funcs(templateFuncs).Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse instruction template %q: %w", name, err)
+	}
+	return &InstructionTemplate{tmpl: tmpl}, nil
+}
+
+// Render renders the template against the state of the given context.
+func (t *InstructionTemplate) Render(ctx agent.ReadonlyContext) (string, error) {
+	state := make(map[string]any)
+	for k, v := range ctx.ReadonlyState().All() {
+		state[k] = v
+	}
+	data := templateData{
+		State:     state,
+		AgentName: ctx.AgentName(),
+		AppName:   ctx.AppName(),
+		UserID:    ctx.UserID(),
+		SessionID: ctx.SessionID(),
+	}
+	var sb strings.Builder
+	if err := t.tmpl.Execute(&sb, data); err != nil {
+		return "", fmt.Errorf("failed to render instruction template %q: %w", t.tmpl.Name(), err)
+	}
+	return sb.String(), nil
+}
+
+type templateData struct {
+	State     map[string]any
+	AgentName string
+	AppName   string
+	UserID    string
+	SessionID string
+}
+
+var errCallNotAllowed = errors.New("call is not allowed in instruction templates")
+
+// templateFuncs is the function set available in instruction templates.
+// Functions registered here take precedence over the text/template builtins,
+// which is used to disable "call".
+var templateFuncs = template.FuncMap{
+	"call": func(any, ...any) (any, error) {
+		return nil, errCallNotAllowed
+	},
+	"join": func(sep string, items any) (string, error) {
+		v := reflect.ValueOf(items)
+		if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+			return "", fmt.Errorf("join: unsupported type %T", items)
+		}
+		strs := make([]string, v.Len())
+		for i := range v.Len() {
+			strs[i] = fmt.Sprint(v.Index(i).Interface())
+		}
+		return strings.Join(strs, sep), nil
+	},
+	"upper":    strings.ToUpper,
+	"lower":    strings.ToLower,
+	"trim":     strings.TrimSpace,
+	"contains": strings.Contains,
+	// default returns val, or def if val is empty.
+	"default": func(def, val any) any {
+		if val == nil || val == "" {
+			return def
+		}
+		return val
+	},
+	"json": func(v any) (string, error) {
+		b, err := json.Marshal(v)
+		return string(b), err
+	},
+}
@@ -0,0 +1,116 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package instructionutil_test
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"google.golang.org/adk/agent"
+	icontext "google.golang.org/adk/internal/context"
+	"google.golang.org/adk/session"
+	"google.golang.org/adk/util/instructionutil"
+)
+
+func TestInstructionTemplate(t *testing.T) {
+	resp, err := session.InMemoryService().Create(t.Context(), &session.CreateRequest{
+		AppName: "app",
+		UserID:  "user",
+		State: map[string]any{
+			"user_name": "Ada",
+			"items":     []string{"milk", "eggs"},
+			"premium":   true,
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	a, err := agent.New(agent.Config{Name: "helper"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := icontext.NewReadonlyContext(icontext.NewInvocationContext(t.Context(), icontext.InvocationContextParams{
+		Agent:   a,
+		Session: resp.Session,
+	}))
+
+	tests := []struct {
+		name         string
+		template     string
+		want         string
+		wantParseErr bool
+		wantErr      bool
+	}{
+		{
+			name:     "substitution and functions",
+			template: `{{.AgentName}}: Hello {{upper .State.user_name}} ({{.UserID}}).`,
+			want:     "helper: Hello ADA (user).",
+		},
+		{
+			name:     "conditionals and loops",
+			template: `{{if .State.premium}}Premium user.{{end}}{{range .State.items}} [{{.}}]{{end}} {{join ", " .State.items}}`,
+			want:     "Premium user. [milk] [eggs] milk, eggs",
+		},
+		{
+			name:     "optional key",
+			template: `{{index .State "missing" | default "none"}}`,
+			want:     "none",
+		},
+		{
+			name:     "optional block",
+			template: `{{with index .State "coupons"}}Coupons:{{range .}} {{.}}{{end}}{{else}}No coupons.{{end}}`,
+			want:     "No coupons.",
+		},
+		{
+			name:     "missing key is an error",
+			template: `{{.State.missing}}`,
+			wantErr:  true,
+		},
+		{
+			name:     "missing key in with is an error",
+			template: `{{with .State.missing}}{{.}}{{end}}`,
+			wantErr:  true,
+		},
+		{
+			name:     "call is disabled",
+			template: `{{call .State.user_name}}`,
+			wantErr:  true,
+		},
+		{
+			name:         "unknown function",
+			template:     `{{exec "rm"}}`,
+			wantParseErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpl, err := instructionutil.NewInstructionTemplate("test", tt.template)
+			if (err != nil) != tt.wantParseErr {
+				t.Fatalf("NewInstructionTemplate() error = %v, wantParseErr %v", err, tt.wantParseErr)
+			}
+			if err != nil {
+				return
+			}
+			got, err := tmpl.Render(ctx)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Render() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("Render() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}